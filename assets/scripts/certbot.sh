@@ -32,29 +32,11 @@ echo ""
 
 # Update package list
 echo "Updating package list..."
-case "$OS" in
-    ubuntu|debian)
-        apt-get update -qq
-        ;;
-    centos|rhel|fedora)
-        yum update -y -q
-        ;;
-esac
+pkg_update
 
 # Install Certbot
 echo "Installing Certbot..."
-case "$OS" in
-    ubuntu|debian)
-        apt-get install -y certbot python3-certbot-nginx
-        ;;
-    centos|rhel|fedora)
-        yum install -y certbot python3-certbot-nginx
-        ;;
-    *)
-        echo "Error: Unsupported distribution"
-        exit 1
-        ;;
-esac
+pkg_install certbot certbot-nginx
 
 # Verify installation
 if command -v certbot &> /dev/null; then
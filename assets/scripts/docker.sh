@@ -0,0 +1,114 @@
+#!/bin/bash
+#
+# Docker Installation Script for Ravact
+# Installs Docker Engine and the Compose plugin from Docker's repositories
+#
+
+set -e  # Exit on error
+
+echo "=========================================="
+echo "  Docker Installation"
+echo "=========================================="
+echo ""
+
+# Check if running as root
+if [ "$EUID" -ne 0 ]; then
+    echo "Error: This script must be run as root"
+    exit 1
+fi
+
+# Detect distribution
+if [ -f /etc/os-release ]; then
+    . /etc/os-release
+    OS=$ID
+    VERSION=$VERSION_ID
+else
+    echo "Error: Cannot detect OS distribution"
+    exit 1
+fi
+
+echo "Detected OS: $OS $VERSION"
+echo ""
+
+# Users added to the docker group (comma or space separated). Defaults to
+# the user who ran ravact through sudo.
+DOCKER_USERS="${DOCKER_USERS:-$SUDO_USER}"
+
+# Install Docker Engine and the Compose plugin
+echo "Installing Docker Engine..."
+case "$OS" in
+    ubuntu|debian)
+        pkg_update
+        pkg_install ca-certificates curl gnupg
+        install -m 0755 -d /etc/apt/keyrings
+        curl -fsSL "https://download.docker.com/linux/$OS/gpg" | gpg --dearmor --yes -o /etc/apt/keyrings/docker.gpg
+        chmod a+r /etc/apt/keyrings/docker.gpg
+
+        echo "deb [arch=$(dpkg --print-architecture) signed-by=/etc/apt/keyrings/docker.gpg] https://download.docker.com/linux/$OS $VERSION_CODENAME stable" \
+            > /etc/apt/sources.list.d/docker.list
+
+        apt-get update -qq
+        apt-get install -y docker-ce docker-ce-cli containerd.io docker-buildx-plugin docker-compose-plugin
+        ;;
+    centos|rhel|rocky|almalinux|fedora)
+        REPO_OS="$OS"
+        case "$OS" in
+            rocky|almalinux) REPO_OS="centos" ;;
+        esac
+        dnf -y install dnf-plugins-core
+        dnf config-manager --add-repo "https://download.docker.com/linux/$REPO_OS/docker-ce.repo" 2>/dev/null || \
+            dnf config-manager addrepo --from-repofile="https://download.docker.com/linux/$REPO_OS/docker-ce.repo"
+        dnf -y install docker-ce docker-ce-cli containerd.io docker-buildx-plugin docker-compose-plugin
+        ;;
+    arch|manjaro)
+        pacman -Sy --noconfirm --needed docker docker-compose docker-buildx
+        ;;
+    *)
+        echo "Error: Unsupported distribution: $OS"
+        exit 1
+        ;;
+esac
+
+echo "✓ Docker Engine installed"
+
+# Enable and start Docker
+echo ""
+echo "Starting Docker service..."
+systemctl enable --now docker
+systemctl enable containerd 2>/dev/null || true
+
+# Add users to the docker group
+for user in ${DOCKER_USERS//,/ }; do
+    if id -u "$user" > /dev/null 2>&1; then
+        usermod -aG docker "$user"
+        echo "✓ Added $user to the docker group (takes effect at next login)"
+    else
+        echo "⚠ User $user does not exist, skipping"
+    fi
+done
+
+if systemctl is-active --quiet docker; then
+    echo ""
+    echo "✓ Docker installed and running successfully!"
+    echo "✓ $(docker --version)"
+    echo "✓ $(docker compose version)"
+
+    echo ""
+    echo "=========================================="
+    echo "  Installation Complete!"
+    echo "=========================================="
+    echo ""
+    echo "Next steps:"
+    echo "  • Open Configurations → Docker in Ravact to manage containers"
+    echo "  • Members of the docker group have root-equivalent access;"
+    echo "    add only trusted users"
+    echo "  • Published ports bypass UFW; bind sidecars to 127.0.0.1"
+    echo ""
+else
+    echo ""
+    echo "✗ Error: Docker service failed to start"
+    echo "Check logs: journalctl -u docker -n 50"
+    exit 1
+fi
+
+exit 0
@@ -87,8 +87,7 @@ if [ "$install_method" = "1" ]; then
     # Download binary
     DRAGONFLY_URL="https://github.com/dragonflydb/dragonfly/releases/download/${DRAGONFLY_VERSION}/dragonfly-${ARCH_NAME}.tar.gz"
     
-    echo "Downloading from: $DRAGONFLY_URL"
-    wget -q "$DRAGONFLY_URL" -O /tmp/dragonfly.tar.gz
+    verified_download "$DRAGONFLY_URL" /tmp/dragonfly.tar.gz dragonflydb/dragonfly "${DRAGONFLY_SHA256:-}"
     
     # Extract binary
     tar -xzf /tmp/dragonfly.tar.gz -C /tmp/
@@ -0,0 +1,332 @@
+#!/bin/bash
+#
+# Prometheus Exporters Setup Script for Ravact
+# Installs node_exporter, nginx-prometheus-exporter, mysqld_exporter, and
+# php-fpm_exporter as systemd services, reachable only from the scrape source
+#
+# Optional environment:
+#   EXPORTERS      Space-separated exporters to run: node nginx mysqld phpfpm
+#                  (default: node). Exporters left out are stopped and disabled.
+#   SCRAPE_SOURCE  IP address or network allowed to scrape (default: 127.0.0.1).
+#                  A loopback source keeps the exporters on 127.0.0.1.
+#
+
+set -e  # Exit on error
+
+EXPORTERS="${EXPORTERS:-node}"
+SCRAPE_SOURCE="${SCRAPE_SOURCE:-127.0.0.1}"
+STATE_FILE=/etc/ravact/exporters.conf
+CONF_DIR=/etc/ravact-exporters
+FIREWALL_COMMENT=ravact-exporter
+
+NODE_EXPORTER_VERSION=1.8.2
+NGINX_EXPORTER_VERSION=1.3.0
+MYSQLD_EXPORTER_VERSION=0.15.1
+PHPFPM_EXPORTER_VERSION=2.2.0
+
+echo "=========================================="
+echo "  Prometheus Exporters Setup"
+echo "=========================================="
+echo ""
+
+# Check if running as root
+if [ "$EUID" -ne 0 ]; then
+    echo "Error: This script must be run as root"
+    exit 1
+fi
+
+# Detect architecture
+case "$(uname -m)" in
+    x86_64) ARCH_NAME="amd64" ;;
+    aarch64|arm64) ARCH_NAME="arm64" ;;
+    *)
+        echo "Error: Unsupported architecture: $(uname -m)"
+        exit 1
+        ;;
+esac
+
+# port returns the port an exporter listens on
+port() {
+    case "$1" in
+        node) echo 9100 ;;
+        nginx) echo 9113 ;;
+        mysqld) echo 9104 ;;
+        phpfpm) echo 9253 ;;
+    esac
+}
+
+# unit returns an exporter's systemd unit
+unit() {
+    case "$1" in
+        node) echo node_exporter ;;
+        nginx) echo nginx-prometheus-exporter ;;
+        mysqld) echo mysqld_exporter ;;
+        phpfpm) echo php-fpm_exporter ;;
+    esac
+}
+
+for exporter in $EXPORTERS; do
+    if [ -z "$(port "$exporter")" ]; then
+        echo "Error: unknown exporter '$exporter' (expected node, nginx, mysqld, or phpfpm)"
+        exit 1
+    fi
+done
+
+case "$SCRAPE_SOURCE" in
+    127.*|::1|localhost) LOCAL_ONLY=1; LISTEN_HOST=127.0.0.1 ;;
+    *) LOCAL_ONLY=0; LISTEN_HOST=0.0.0.0 ;;
+esac
+
+echo "Exporters:     $EXPORTERS"
+echo "Scrape source: $SCRAPE_SOURCE"
+echo "Architecture:  $ARCH_NAME"
+echo ""
+
+# Only a firewall keeps the exporters private when they listen on every
+# address, so refuse to go on without one
+FIREWALL=""
+if command -v ufw >/dev/null 2>&1 && ufw status | grep -q '^Status: active'; then
+    FIREWALL=ufw
+elif command -v firewall-cmd >/dev/null 2>&1 && firewall-cmd --state >/dev/null 2>&1; then
+    FIREWALL=firewalld
+fi
+if [ "$LOCAL_ONLY" -eq 0 ] && [ -z "$FIREWALL" ]; then
+    echo "Error: no active firewall (ufw or firewalld); refusing to expose the exporters"
+    echo "Set up the firewall first, or scrape from 127.0.0.1 through a tunnel"
+    exit 1
+fi
+
+# rich_rule returns the firewalld rule admitting a source to a port
+rich_rule() {
+    local family=ipv4
+    case "$1" in *:*) family=ipv6 ;; esac
+    echo "rule family=\"$family\" source address=\"$1\" port port=\"$2\" protocol=\"tcp\" accept"
+}
+
+# allow_source lets a source reach a port
+allow_source() {
+    case "$FIREWALL" in
+        ufw) ufw allow from "$1" to any port "$2" proto tcp comment "$FIREWALL_COMMENT" > /dev/null ;;
+        firewalld) firewall-cmd --permanent --add-rich-rule="$(rich_rule "$1" "$2")" > /dev/null ;;
+    esac
+}
+
+# deny_source removes a rule added by allow_source
+deny_source() {
+    case "$FIREWALL" in
+        ufw) ufw delete allow from "$1" to any port "$2" proto tcp > /dev/null 2>&1 || true ;;
+        firewalld) firewall-cmd --permanent --remove-rich-rule="$(rich_rule "$1" "$2")" > /dev/null 2>&1 || true ;;
+    esac
+}
+
+# Drop the rules of the previous run; they are added again below for the
+# exporters that stay
+if [ -f "$STATE_FILE" ]; then
+    PREVIOUS_SOURCE=$(sed -n 's/^SCRAPE_SOURCE=//p' "$STATE_FILE")
+    case "$PREVIOUS_SOURCE" in
+        ""|127.*|::1|localhost) ;;
+        *)
+            for exporter in node nginx mysqld phpfpm; do
+                deny_source "$PREVIOUS_SOURCE" "$(port "$exporter")"
+            done
+            ;;
+    esac
+fi
+
+# Stop the exporters that were left out
+for exporter in node nginx mysqld phpfpm; do
+    case " $EXPORTERS " in *" $exporter "*) continue ;; esac
+    if [ -f "/etc/systemd/system/$(unit "$exporter").service" ]; then
+        echo "Stopping $(unit "$exporter")..."
+        systemctl disable --now "$(unit "$exporter")" > /dev/null 2>&1 || true
+    fi
+done
+
+if ! command -v curl >/dev/null 2>&1; then
+    apt-get update && apt-get install -y curl
+fi
+
+if ! id exporter >/dev/null 2>&1; then
+    useradd --system --no-create-home --shell /usr/sbin/nologin exporter
+fi
+mkdir -p "$CONF_DIR"
+
+# install_binary downloads a release archive and installs one binary from it
+install_binary() {
+    local url="$1" binary="$2" tmp
+    tmp=$(mktemp -d)
+    echo "Downloading $url"
+    curl -fsSL "$url" -o "$tmp/release.tar.gz"
+    tar -xzf "$tmp/release.tar.gz" -C "$tmp"
+    install -m 0755 "$(find "$tmp" -type f -name "$binary" | head -n 1)" "/usr/local/bin/$binary"
+    rm -rf "$tmp"
+}
+
+# write_unit writes an exporter's systemd unit and (re)starts it
+write_unit() {
+    local name="$1" description="$2" exec="$3" extra="$4"
+    cat > "/etc/systemd/system/$name.service" <<EOF
+# Generated by ravact
+[Unit]
+Description=$description
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+User=exporter
+Group=exporter
+$extra
+ExecStart=$exec
+Restart=on-failure
+NoNewPrivileges=true
+ProtectSystem=strict
+ProtectHome=true
+
+[Install]
+WantedBy=multi-user.target
+EOF
+    systemctl daemon-reload
+    systemctl enable "$name" > /dev/null 2>&1
+    systemctl restart "$name"
+}
+
+for exporter in $EXPORTERS; do
+    PORT=$(port "$exporter")
+    LISTEN="$LISTEN_HOST:$PORT"
+    echo ""
+    echo "==> $(unit "$exporter") on $LISTEN"
+
+    case "$exporter" in
+        node)
+            install_binary "https://github.com/prometheus/node_exporter/releases/download/v${NODE_EXPORTER_VERSION}/node_exporter-${NODE_EXPORTER_VERSION}.linux-${ARCH_NAME}.tar.gz" node_exporter
+            write_unit node_exporter "Prometheus node_exporter" \
+                "/usr/local/bin/node_exporter --web.listen-address=$LISTEN --collector.systemd"
+            ;;
+
+        nginx)
+            if ! command -v nginx >/dev/null 2>&1; then
+                echo "Error: nginx is not installed"
+                exit 1
+            fi
+            # Serve stub_status on loopback only for the exporter to read
+            cat > /etc/nginx/conf.d/ravact-stub-status.conf <<'EOF'
+# Generated by ravact for nginx-prometheus-exporter
+server {
+    listen 127.0.0.1:9180;
+    server_name localhost;
+    access_log off;
+    location = /stub_status {
+        stub_status;
+    }
+}
+EOF
+            nginx -t
+            systemctl reload nginx
+            install_binary "https://github.com/nginx/nginx-prometheus-exporter/releases/download/v${NGINX_EXPORTER_VERSION}/nginx-prometheus-exporter_${NGINX_EXPORTER_VERSION}_linux_${ARCH_NAME}.tar.gz" nginx-prometheus-exporter
+            write_unit nginx-prometheus-exporter "Prometheus nginx exporter" \
+                "/usr/local/bin/nginx-prometheus-exporter --web.listen-address=$LISTEN --nginx.scrape-uri=http://127.0.0.1:9180/stub_status"
+            ;;
+
+        mysqld)
+            if ! command -v mysql >/dev/null 2>&1; then
+                echo "Error: MySQL is not installed"
+                exit 1
+            fi
+            # Root over the socket, or the maintenance account on Debian
+            MYSQL="mysql -u root"
+            if ! $MYSQL -e 'SELECT 1' >/dev/null 2>&1 && [ -f /etc/mysql/debian.cnf ]; then
+                MYSQL="mysql --defaults-file=/etc/mysql/debian.cnf"
+            fi
+            # A fresh password each run; the exporter only needs to read status
+            PASSWORD=$(head -c 32 /dev/urandom | base64 | tr -dc 'A-Za-z0-9' | head -c 24)
+            $MYSQL <<EOF
+CREATE USER IF NOT EXISTS 'exporter'@'localhost' IDENTIFIED BY '$PASSWORD' WITH MAX_USER_CONNECTIONS 3;
+ALTER USER 'exporter'@'localhost' IDENTIFIED BY '$PASSWORD';
+GRANT PROCESS, REPLICATION CLIENT, SELECT ON *.* TO 'exporter'@'localhost';
+FLUSH PRIVILEGES;
+EOF
+            install -m 0640 -o root -g exporter /dev/null "$CONF_DIR/mysqld_exporter.cnf"
+            cat > "$CONF_DIR/mysqld_exporter.cnf" <<EOF
+[client]
+user=exporter
+password=$PASSWORD
+socket=/var/run/mysqld/mysqld.sock
+EOF
+            install_binary "https://github.com/prometheus/mysqld_exporter/releases/download/v${MYSQLD_EXPORTER_VERSION}/mysqld_exporter-${MYSQLD_EXPORTER_VERSION}.linux-${ARCH_NAME}.tar.gz" mysqld_exporter
+            write_unit mysqld_exporter "Prometheus MySQL exporter" \
+                "/usr/local/bin/mysqld_exporter --web.listen-address=$LISTEN --config.my-cnf=$CONF_DIR/mysqld_exporter.cnf"
+            ;;
+
+        phpfpm)
+            # Turn on the status page of every pool and scrape each listener
+            URIS=""
+            for pool in /etc/php/*/fpm/pool.d/*.conf; do
+                [ -f "$pool" ] || continue
+                if ! grep -qE '^[[:space:]]*pm\.status_path' "$pool"; then
+                    echo "pm.status_path = /status" >> "$pool"
+                    echo "Enabled the status page in $pool"
+                fi
+                LISTEN_ON=$(sed -n 's/^[[:space:]]*listen[[:space:]]*=[[:space:]]*//p' "$pool" | head -n 1)
+                case "$LISTEN_ON" in
+                    "") continue ;;
+                    /*) URI="unix://$LISTEN_ON;/status" ;;
+                    *:*) URI="tcp://$LISTEN_ON/status" ;;
+                    *) URI="tcp://127.0.0.1:$LISTEN_ON/status" ;;
+                esac
+                URIS="${URIS:+$URIS,}$URI"
+            done
+            if [ -z "$URIS" ]; then
+                echo "Error: no PHP-FPM pools found in /etc/php/*/fpm/pool.d"
+                exit 1
+            fi
+            for fpm in /lib/systemd/system/php*-fpm.service; do
+                [ -f "$fpm" ] && systemctl reload "$(basename "$fpm" .service)" || true
+            done
+            # Pool sockets are usually group www-data
+            usermod -aG www-data exporter
+            install_binary "https://github.com/hipages/php-fpm_exporter/releases/download/v${PHPFPM_EXPORTER_VERSION}/php-fpm_exporter_${PHPFPM_EXPORTER_VERSION}_linux_${ARCH_NAME}.tar.gz" php-fpm_exporter
+            write_unit php-fpm_exporter "Prometheus PHP-FPM exporter" \
+                "/usr/local/bin/php-fpm_exporter server --web.listen-address=$LISTEN" \
+                "Environment=PHP_FPM_SCRAPE_URI=$URIS"
+            ;;
+    esac
+
+    if [ "$LOCAL_ONLY" -eq 0 ]; then
+        allow_source "$SCRAPE_SOURCE" "$PORT"
+        echo "✓ Port $PORT open to $SCRAPE_SOURCE ($FIREWALL)"
+    fi
+done
+
+if [ "$FIREWALL" = "firewalld" ]; then
+    firewall-cmd --reload > /dev/null
+fi
+
+mkdir -p "$(dirname "$STATE_FILE")"
+cat > "$STATE_FILE" <<EOF
+# Generated by ravact
+EXPORTERS=$EXPORTERS
+SCRAPE_SOURCE=$SCRAPE_SOURCE
+EOF
+
+# Check that each exporter answers
+sleep 2
+echo ""
+FAILED=0
+for exporter in $EXPORTERS; do
+    if curl -fsS -o /dev/null "http://127.0.0.1:$(port "$exporter")/metrics" 2>/dev/null; then
+        echo "✓ $(unit "$exporter") serves metrics on port $(port "$exporter")"
+    else
+        echo "✗ $(unit "$exporter") does not answer; see journalctl -u $(unit "$exporter")"
+        FAILED=1
+    fi
+done
+
+echo ""
+echo "=========================================="
+echo "  Exporters configured"
+echo "=========================================="
+echo "Add these targets to Prometheus, scraping from $SCRAPE_SOURCE:"
+for exporter in $EXPORTERS; do
+    echo "  $(unit "$exporter"): <this server>:$(port "$exporter")"
+done
+exit $FAILED
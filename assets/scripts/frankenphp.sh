@@ -67,8 +67,9 @@ echo "Downloading FrankenPHP..."
 FRANKENPHP_VERSION="${FRANKENPHP_VERSION:-1.1.0}"
 DOWNLOAD_URL="https://github.com/dunglas/frankenphp/releases/download/v${FRANKENPHP_VERSION}/frankenphp-linux-${ARCH_NAME}"
 
-wget -q "$DOWNLOAD_URL" -O /usr/local/bin/frankenphp
-chmod +x /usr/local/bin/frankenphp
+verified_download "$DOWNLOAD_URL" /tmp/frankenphp dunglas/frankenphp "${FRANKENPHP_SHA256:-}"
+install -m 0755 /tmp/frankenphp /usr/local/bin/frankenphp
+rm -f /tmp/frankenphp
 
 echo "✓ FrankenPHP binary installed"
 
@@ -32,29 +32,11 @@ echo ""
 
 # Update package list
 echo "Updating package list..."
-case "$OS" in
-    ubuntu|debian)
-        apt-get update -qq
-        ;;
-    centos|rhel|fedora)
-        yum update -y -q
-        ;;
-esac
+pkg_update
 
 # Install Git
 echo "Installing Git..."
-case "$OS" in
-    ubuntu|debian)
-        apt-get install -y git
-        ;;
-    centos|rhel|fedora)
-        yum install -y git
-        ;;
-    *)
-        echo "Error: Unsupported distribution"
-        exit 1
-        ;;
-esac
+pkg_install git
 
 # Verify installation
 if command -v git &> /dev/null; then
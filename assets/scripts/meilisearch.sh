@@ -0,0 +1,178 @@
+#!/bin/bash
+#
+# Meilisearch Installation Script for Ravact
+# Installs Meilisearch search engine for Laravel Scout
+#
+
+set -e  # Exit on error
+
+echo "=========================================="
+echo "  Meilisearch Installation"
+echo "=========================================="
+echo ""
+
+# Check if running as root
+if [ "$EUID" -ne 0 ]; then
+    echo "Error: This script must be run as root"
+    exit 1
+fi
+
+# Detect distribution
+if [ -f /etc/os-release ]; then
+    . /etc/os-release
+    OS=$ID
+    VERSION=$VERSION_ID
+else
+    echo "Error: Cannot detect OS distribution"
+    exit 1
+fi
+
+echo "Detected OS: $OS $VERSION"
+echo ""
+
+# Detect architecture
+ARCH=$(uname -m)
+case "$ARCH" in
+    x86_64)
+        ARCH_NAME="amd64"
+        ;;
+    aarch64|arm64)
+        ARCH_NAME="aarch64"
+        ;;
+    *)
+        echo "Error: Unsupported architecture: $ARCH"
+        echo "Meilisearch supports x86_64 and aarch64 only"
+        exit 1
+        ;;
+esac
+
+echo "Architecture: $ARCH_NAME"
+echo ""
+
+# Set Meilisearch version (latest unless overridden via environment)
+MEILISEARCH_VERSION="${MEILISEARCH_VERSION:-latest}"
+MEILISEARCH_ADDR="${MEILISEARCH_ADDR:-127.0.0.1:7700}"
+CONF_FILE="/etc/meilisearch.toml"
+DATA_DIR="/var/lib/meilisearch"
+
+# Install dependencies
+echo "Installing dependencies..."
+pkg_update
+pkg_install curl openssl
+
+# Download binary
+echo ""
+echo "Installing Meilisearch binary..."
+if [ "$MEILISEARCH_VERSION" = "latest" ]; then
+    MEILISEARCH_URL="https://github.com/meilisearch/meilisearch/releases/latest/download/meilisearch-linux-${ARCH_NAME}"
+else
+    MEILISEARCH_URL="https://github.com/meilisearch/meilisearch/releases/download/${MEILISEARCH_VERSION}/meilisearch-linux-${ARCH_NAME}"
+fi
+
+verified_download "$MEILISEARCH_URL" /tmp/meilisearch meilisearch/meilisearch "${MEILISEARCH_SHA256:-}"
+install -m 0755 /tmp/meilisearch /usr/local/bin/meilisearch
+rm -f /tmp/meilisearch
+
+echo "✓ Binary installed to /usr/local/bin/meilisearch"
+
+# Create meilisearch user
+if ! id -u meilisearch > /dev/null 2>&1; then
+    useradd -r -s /bin/false -d "$DATA_DIR" meilisearch
+    echo "✓ Created meilisearch user"
+fi
+
+# Create directories
+mkdir -p "$DATA_DIR/data" "$DATA_DIR/dumps" "$DATA_DIR/snapshots"
+chown -R meilisearch:meilisearch "$DATA_DIR"
+
+# Create configuration file, keeping an existing master key
+if [ -f "$CONF_FILE" ]; then
+    echo "✓ Keeping existing configuration: $CONF_FILE"
+else
+    MASTER_KEY=$(openssl rand -hex 32)
+    cat > "$CONF_FILE" << EOF
+# Meilisearch Configuration
+# Generated by Ravact
+
+env = "production"
+master_key = "$MASTER_KEY"
+http_addr = "$MEILISEARCH_ADDR"
+db_path = "$DATA_DIR/data"
+dump_dir = "$DATA_DIR/dumps"
+snapshot_dir = "$DATA_DIR/snapshots"
+EOF
+    echo "✓ Created configuration file with a new master key: $CONF_FILE"
+fi
+chown root:meilisearch "$CONF_FILE"
+chmod 640 "$CONF_FILE"
+
+# Create systemd service
+cat > /etc/systemd/system/meilisearch.service << EOF
+[Unit]
+Description=Meilisearch
+After=network.target
+
+[Service]
+Type=simple
+User=meilisearch
+Group=meilisearch
+WorkingDirectory=$DATA_DIR
+ExecStart=/usr/local/bin/meilisearch --config-file-path $CONF_FILE
+Restart=always
+RestartSec=3
+LimitNOFILE=65536
+
+[Install]
+WantedBy=multi-user.target
+EOF
+
+echo "✓ Created systemd service"
+
+systemctl daemon-reload
+
+# Enable and start Meilisearch
+echo ""
+echo "Starting Meilisearch service..."
+systemctl enable meilisearch
+systemctl restart meilisearch
+
+# Wait for Meilisearch to be ready
+echo "Waiting for Meilisearch to be ready..."
+sleep 3
+
+if systemctl is-active --quiet meilisearch; then
+    echo ""
+    echo "✓ Meilisearch installed and running successfully!"
+
+    MEILISEARCH_VERSION_FULL=$(meilisearch --version 2>&1 | head -1)
+    echo "✓ Meilisearch version: $MEILISEARCH_VERSION_FULL"
+
+    if curl --fail --silent "http://$MEILISEARCH_ADDR/health" | grep -q available; then
+        echo "✓ Meilisearch is responding to requests"
+    fi
+
+    echo ""
+    echo "=========================================="
+    echo "  Installation Complete!"
+    echo "=========================================="
+    echo ""
+    echo "Meilisearch is running on:"
+    echo "  http://$MEILISEARCH_ADDR"
+    echo ""
+    echo "Configuration:"
+    echo "  Config: $CONF_FILE (holds the master key)"
+    echo "  Data: $DATA_DIR"
+    echo ""
+    echo "Next steps:"
+    echo "  • Open Configurations → Meilisearch in Ravact"
+    echo "  • Write the Scout settings to your site's .env"
+    echo "  • composer require laravel/scout meilisearch/meilisearch-php"
+    echo ""
+else
+    echo ""
+    echo "✗ Error: Meilisearch service failed to start"
+    echo "Check logs: journalctl -u meilisearch -n 50"
+    exit 1
+fi
+
+exit 0
@@ -0,0 +1,111 @@
+#!/bin/bash
+#
+# Netdata Installation Script for Ravact
+# Installs Netdata for real-time graphs without a Prometheus stack. The
+# dashboard listens on 127.0.0.1 only; reach it through an SSH tunnel or the
+# basic-auth reverse proxy from Configurations → Netdata.
+#
+# Optional environment:
+#   NETDATA_CHANNEL  Release channel: stable or nightly (default: stable)
+#
+
+set -e  # Exit on error
+
+NETDATA_CHANNEL="${NETDATA_CHANNEL:-stable}"
+CONF_FILE=/etc/netdata/netdata.conf
+KICKSTART=/tmp/netdata-kickstart.sh
+
+echo "=========================================="
+echo "  Netdata Installation"
+echo "=========================================="
+echo ""
+
+# Check if running as root
+if [ "$EUID" -ne 0 ]; then
+    echo "Error: This script must be run as root"
+    exit 1
+fi
+
+case "$NETDATA_CHANNEL" in
+    stable|nightly) ;;
+    *)
+        echo "Error: NETDATA_CHANNEL must be stable or nightly"
+        exit 1
+        ;;
+esac
+
+# Install dependencies
+echo "Installing dependencies..."
+pkg_update
+pkg_install curl
+
+# Install Netdata with the official kickstart script, which uses the native
+# packages of the distribution when there are some
+echo ""
+if command -v netdata > /dev/null 2>&1; then
+    echo "✓ Netdata is already installed, keeping it"
+else
+    echo "Installing Netdata ($NETDATA_CHANNEL channel)..."
+    curl -fsSL https://get.netdata.cloud/kickstart.sh -o "$KICKSTART"
+    sh "$KICKSTART" --non-interactive --"$NETDATA_CHANNEL"-channel --disable-telemetry
+    rm -f "$KICKSTART"
+    echo "✓ Netdata installed"
+fi
+
+# Listen on loopback unless a bind address was already chosen
+mkdir -p "$(dirname "$CONF_FILE")"
+touch "$CONF_FILE"
+if grep -Eq '^[[:space:]]*bind to[[:space:]]*=' "$CONF_FILE"; then
+    BIND=$(grep -E '^[[:space:]]*bind to[[:space:]]*=' "$CONF_FILE" | head -1 | cut -d= -f2- | xargs)
+    echo "✓ Keeping the existing bind address: $BIND"
+elif grep -q '^\[web\]' "$CONF_FILE"; then
+    sed -i '/^\[web\]/a\    bind to = 127.0.0.1' "$CONF_FILE"
+    BIND=127.0.0.1
+    echo "✓ Dashboard bound to 127.0.0.1"
+else
+    printf '\n[web]\n    bind to = 127.0.0.1\n' >> "$CONF_FILE"
+    BIND=127.0.0.1
+    echo "✓ Dashboard bound to 127.0.0.1"
+fi
+
+# Enable and start Netdata
+echo ""
+echo "Starting Netdata service..."
+systemctl enable netdata
+systemctl restart netdata
+
+# Wait for Netdata to be ready
+echo "Waiting for Netdata to be ready..."
+sleep 3
+
+if systemctl is-active --quiet netdata; then
+    echo ""
+    echo "✓ Netdata installed and running successfully!"
+
+    if curl --fail --silent http://127.0.0.1:19999/api/v1/info > /dev/null; then
+        echo "✓ Netdata is responding to requests"
+    fi
+
+    echo ""
+    echo "=========================================="
+    echo "  Installation Complete!"
+    echo "=========================================="
+    echo ""
+    echo "Dashboard (listening on $BIND):"
+    echo "  http://127.0.0.1:19999"
+    echo ""
+    echo "From your machine, open an SSH tunnel first:"
+    echo "  ssh -L 19999:127.0.0.1:19999 user@this-server"
+    echo ""
+    echo "Next steps:"
+    echo "  • Open Configurations → Netdata in Ravact"
+    echo "  • Add a reverse proxy with basic auth to reach it from a domain"
+    echo ""
+else
+    echo ""
+    echo "✗ Error: Netdata service failed to start"
+    echo "Check logs: journalctl -u netdata -n 50"
+    exit 1
+fi
+
+exit 0
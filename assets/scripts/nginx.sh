@@ -32,32 +32,11 @@ echo ""
 
 # Update package list
 echo "Updating package list..."
-case "$OS" in
-    ubuntu|debian)
-        apt-get update -qq
-        ;;
-    centos|rhel|fedora)
-        yum update -y -q
-        ;;
-    *)
-        echo "Warning: Unsupported distribution. Attempting to continue..."
-        ;;
-esac
+pkg_update
 
 # Install Nginx
 echo "Installing Nginx..."
-case "$OS" in
-    ubuntu|debian)
-        apt-get install -y nginx
-        ;;
-    centos|rhel|fedora)
-        yum install -y nginx
-        ;;
-    *)
-        echo "Error: Cannot install Nginx on this distribution"
-        exit 1
-        ;;
-esac
+pkg_install nginx
 
 # Enable and start Nginx
 echo "Enabling and starting Nginx service..."
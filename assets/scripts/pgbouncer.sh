@@ -0,0 +1,94 @@
+#!/bin/bash
+#
+# pgBouncer Installation Script for Ravact
+# Installs pgBouncer connection pooler for PostgreSQL
+#
+
+set -e  # Exit on error
+
+echo "=========================================="
+echo "  pgBouncer Installation"
+echo "=========================================="
+echo ""
+
+# Check if running as root
+if [ "$EUID" -ne 0 ]; then
+    echo "Error: This script must be run as root"
+    exit 1
+fi
+
+# Detect distribution
+if [ -f /etc/os-release ]; then
+    . /etc/os-release
+    OS=$ID
+    VERSION=$VERSION_ID
+else
+    echo "Error: Cannot detect OS distribution"
+    exit 1
+fi
+
+echo "Detected OS: $OS $VERSION"
+echo ""
+
+if ! command -v psql >/dev/null 2>&1; then
+    echo "Warning: PostgreSQL client not found; install PostgreSQL before configuring pooling"
+    echo ""
+fi
+
+# Update package list
+echo "Updating package list..."
+pkg_update
+
+# Install pgBouncer
+echo "Installing pgBouncer..."
+pkg_install pgbouncer
+SERVICE=$(svc_name pgbouncer)
+
+CONF_DIR="/etc/pgbouncer"
+mkdir -p "$CONF_DIR"
+
+# The packaged service refuses to start without an auth file
+if [ ! -f "$CONF_DIR/userlist.txt" ]; then
+    touch "$CONF_DIR/userlist.txt"
+    chmod 640 "$CONF_DIR/userlist.txt"
+    chown "$(stat -c '%U:%G' "$CONF_DIR")" "$CONF_DIR/userlist.txt"
+fi
+
+# Enable and start pgBouncer
+echo "Enabling and starting pgBouncer service..."
+systemctl enable "$SERVICE"
+systemctl restart "$SERVICE"
+
+# Wait for pgBouncer to be ready
+sleep 2
+
+if systemctl is-active --quiet "$SERVICE"; then
+    echo ""
+    echo "✓ pgBouncer installed and running successfully!"
+
+    PGBOUNCER_VERSION=$(pgbouncer --version | head -1)
+    echo "✓ pgBouncer version: $PGBOUNCER_VERSION"
+
+    echo ""
+    echo "=========================================="
+    echo "  Installation Complete!"
+    echo "=========================================="
+    echo ""
+    echo "Configuration:"
+    echo "  Config file: $CONF_DIR/pgbouncer.ini"
+    echo "  Auth file:   $CONF_DIR/userlist.txt"
+    echo "  Default port: 6432"
+    echo ""
+    echo "Next steps:"
+    echo "  • Open Configurations → pgBouncer in Ravact"
+    echo "  • Generate pgbouncer.ini and sync users from PostgreSQL"
+    echo "  • Point DB_PORT in your .env at 6432"
+    echo ""
+else
+    echo ""
+    echo "✗ Error: pgBouncer installation failed or service not running"
+    echo "  Check: journalctl -u $SERVICE"
+    exit 1
+fi
+
+exit 0
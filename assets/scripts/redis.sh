@@ -32,41 +32,24 @@ echo ""
 
 # Update package list
 echo "Updating package list..."
-case "$OS" in
-    ubuntu|debian)
-        apt-get update -qq
-        ;;
-    centos|rhel|fedora)
-        yum update -y -q
-        ;;
-esac
+pkg_update
 
 # Install Redis
 echo "Installing Redis Server..."
-case "$OS" in
-    ubuntu|debian)
-        apt-get install -y redis-server
-        ;;
-    centos|rhel|fedora)
-        yum install -y redis
-        ;;
-    *)
-        echo "Error: Unsupported distribution"
-        exit 1
-        ;;
-esac
+pkg_install redis
+SERVICE=$(svc_name redis)
 
 # Enable and start Redis
 echo "Enabling and starting Redis service..."
-systemctl enable redis-server 2>/dev/null || systemctl enable redis 2>/dev/null || true
-systemctl start redis-server 2>/dev/null || systemctl start redis 2>/dev/null || true
+systemctl enable "$SERVICE"
+systemctl start "$SERVICE"
 
 # Wait for Redis to be ready
 echo "Waiting for Redis to be ready..."
 sleep 2
 
 # Check if Redis is running
-if systemctl is-active --quiet redis-server || systemctl is-active --quiet redis; then
+if systemctl is-active --quiet "$SERVICE"; then
     echo ""
     echo "✓ Redis installed and running successfully!"
     
@@ -104,7 +87,7 @@ if systemctl is-active --quiet redis-server || systemctl is-active --quiet redis
         fi
         
         # Restart to apply changes
-        systemctl restart redis-server 2>/dev/null || systemctl restart redis 2>/dev/null || true
+        systemctl restart "$SERVICE"
         sleep 1
     fi
     
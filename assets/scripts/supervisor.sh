@@ -32,41 +32,24 @@ echo ""
 
 # Update package list
 echo "Updating package list..."
-case "$OS" in
-    ubuntu|debian)
-        apt-get update -qq
-        ;;
-    centos|rhel|fedora)
-        yum update -y -q
-        ;;
-esac
+pkg_update
 
 # Install Supervisor
 echo "Installing Supervisor..."
-case "$OS" in
-    ubuntu|debian)
-        apt-get install -y supervisor
-        ;;
-    centos|rhel|fedora)
-        yum install -y supervisor
-        ;;
-    *)
-        echo "Error: Unsupported distribution"
-        exit 1
-        ;;
-esac
+pkg_install supervisor
+SERVICE=$(svc_name supervisor)
 
 # Enable and start Supervisor
 echo "Enabling and starting Supervisor service..."
-systemctl enable supervisor 2>/dev/null || systemctl enable supervisord 2>/dev/null || true
-systemctl start supervisor 2>/dev/null || systemctl start supervisord 2>/dev/null || true
+systemctl enable "$SERVICE"
+systemctl start "$SERVICE"
 
 # Wait for Supervisor to be ready
 echo "Waiting for Supervisor to be ready..."
 sleep 2
 
 # Check if Supervisor is running
-if systemctl is-active --quiet supervisor || systemctl is-active --quiet supervisord; then
+if systemctl is-active --quiet "$SERVICE"; then
     echo ""
     echo "✓ Supervisor installed and running successfully!"
     
@@ -0,0 +1,113 @@
+#!/bin/bash
+#
+# Swap File Setup Script for Ravact
+# Creates or resizes /swapfile and tunes swappiness and vfs_cache_pressure
+#
+# Optional environment:
+#   SWAP_SIZE_MB         Swap file size in megabytes (default: based on RAM)
+#   SWAP_SWAPPINESS      vm.swappiness (default: 10)
+#   SWAP_CACHE_PRESSURE  vm.vfs_cache_pressure (default: 50)
+#
+
+set -e  # Exit on error
+
+SWAP_FILE=/swapfile
+SYSCTL_FILE=/etc/sysctl.d/99-ravact-swap.conf
+
+echo "=========================================="
+echo "  Swap File Setup"
+echo "=========================================="
+echo ""
+
+# Check if running as root
+if [ "$EUID" -ne 0 ]; then
+    echo "Error: This script must be run as root"
+    exit 1
+fi
+
+# Default size: twice the RAM up to 2 GB, the RAM size up to 8 GB, 4 GB above
+MEM_MB=$(awk '/^MemTotal:/ { print int($2 / 1024) }' /proc/meminfo)
+if [ -z "$SWAP_SIZE_MB" ]; then
+    if [ "$MEM_MB" -le 2048 ]; then
+        SWAP_SIZE_MB=$((MEM_MB * 2))
+        [ "$SWAP_SIZE_MB" -lt 1024 ] && SWAP_SIZE_MB=1024
+    elif [ "$MEM_MB" -le 8192 ]; then
+        SWAP_SIZE_MB=$MEM_MB
+    else
+        SWAP_SIZE_MB=4096
+    fi
+fi
+SWAP_SWAPPINESS="${SWAP_SWAPPINESS:-10}"
+SWAP_CACHE_PRESSURE="${SWAP_CACHE_PRESSURE:-50}"
+
+echo "Memory:         ${MEM_MB} MB"
+echo "Swap file:      $SWAP_FILE (${SWAP_SIZE_MB} MB)"
+echo "Swappiness:     $SWAP_SWAPPINESS"
+echo "Cache pressure: $SWAP_CACHE_PRESSURE"
+echo ""
+
+# Replace an existing swap file; the file is rebuilt at the new size
+CURRENT_MB=0
+if [ -f "$SWAP_FILE" ]; then
+    CURRENT_MB=$(( $(stat -c %s "$SWAP_FILE") / 1024 / 1024 ))
+fi
+if [ "$CURRENT_MB" -eq "$SWAP_SIZE_MB" ] && grep -q "^$SWAP_FILE " /proc/swaps; then
+    echo "✓ $SWAP_FILE is already ${SWAP_SIZE_MB} MB and active"
+else
+    if grep -q "^$SWAP_FILE " /proc/swaps; then
+        echo "Turning off the current swap file (${CURRENT_MB} MB)..."
+        swapoff "$SWAP_FILE"
+    fi
+    rm -f "$SWAP_FILE"
+
+    # Leave 1 GB free on the root filesystem
+    AVAIL_MB=$(df -BM --output=avail / | tail -n 1 | tr -dc '0-9')
+    if [ "$SWAP_SIZE_MB" -gt $((AVAIL_MB - 1024)) ]; then
+        echo "Error: only ${AVAIL_MB} MB free on /; ${SWAP_SIZE_MB} MB of swap would leave less than 1 GB"
+        exit 1
+    fi
+
+    echo "Creating ${SWAP_SIZE_MB} MB swap file..."
+    # fallocate is instant but leaves holes on some filesystems (btrfs, older
+    # XFS); swapon rejects those, so fall back to writing zeroes
+    if ! fallocate -l "${SWAP_SIZE_MB}M" "$SWAP_FILE" 2>/dev/null; then
+        dd if=/dev/zero of="$SWAP_FILE" bs=1M count="$SWAP_SIZE_MB" status=none
+    fi
+    chmod 600 "$SWAP_FILE"
+    mkswap "$SWAP_FILE" > /dev/null
+    if ! swapon "$SWAP_FILE" 2>/dev/null; then
+        echo "fallocate produced a file swapon rejects; writing zeroes instead..."
+        rm -f "$SWAP_FILE"
+        dd if=/dev/zero of="$SWAP_FILE" bs=1M count="$SWAP_SIZE_MB" status=none
+        chmod 600 "$SWAP_FILE"
+        mkswap "$SWAP_FILE" > /dev/null
+        swapon "$SWAP_FILE"
+    fi
+    echo "✓ Swap file active"
+fi
+
+# Persist the swap file across reboots
+if ! grep -qE "^$SWAP_FILE[[:space:]]" /etc/fstab; then
+    cp /etc/fstab /etc/fstab.bak
+    echo "$SWAP_FILE none swap sw 0 0" >> /etc/fstab
+    echo "✓ Added $SWAP_FILE to /etc/fstab (previous copy in /etc/fstab.bak)"
+else
+    echo "✓ $SWAP_FILE already in /etc/fstab"
+fi
+
+# Tune the kernel and persist the settings
+cat > "$SYSCTL_FILE" <<EOF
+# Generated by ravact
+vm.swappiness = $SWAP_SWAPPINESS
+vm.vfs_cache_pressure = $SWAP_CACHE_PRESSURE
+EOF
+sysctl -p "$SYSCTL_FILE" > /dev/null
+echo "✓ Wrote $SYSCTL_FILE"
+
+echo ""
+echo "=========================================="
+echo "  Swap configured"
+echo "=========================================="
+swapon --show
+echo ""
+free -h
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/iperamuna/ravact/internal/manifest"
+)
+
+// runExport handles `ravact export [--output FILE]`
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := fs.String("output", "", "write the manifest to FILE instead of stdout")
+	fs.String("server", "", "export a server from ~/.ravact/servers.yaml")
+	fs.Usage = func() {
+		fmt.Println("Usage: ravact export [--output FILE]")
+		fmt.Println()
+		fmt.Println("Writes a YAML manifest of what ravact manages on this server: installed")
+		fmt.Println("services, nginx sites, FrankenPHP services, supervisor programs, and firewall")
+		fmt.Println("rules. `ravact apply` replays it on another server.")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	m, err := manifest.Collect()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	data, err := m.YAML()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	if *output == "" {
+		os.Stdout.Write(data)
+	} else if err := os.WriteFile(*output, data, 0600); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	for _, w := range m.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	return 0
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/iperamuna/ravact/internal/importer"
+)
+
+// runImport handles `ravact import [flags] [FILE]`
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	site := fs.String("site", "", "domain of the site a plain deploy script belongs to")
+	user := fs.String("user", "", "user that replaces forge or ploi for sites and scheduled jobs")
+	root := fs.String("root", "", "directory that replaces /home/forge or /home/ploi, e.g. /var/www")
+	dryRun := fs.Bool("dry-run", false, "print the plan and what would be written without writing it")
+	yes := fs.Bool("yes", false, "apply the plan without asking for approval")
+	fs.String("server", "", "import onto a server from ~/.ravact/servers.yaml")
+	fs.Usage = func() {
+		fmt.Println("Usage: ravact import [--site DOMAIN] [--user USER] [--root DIR] [--dry-run] [--yes] [FILE]")
+		fmt.Println()
+		fmt.Println("Imports a Forge or Ploi export: the JSON returned by their API for sites and")
+		fmt.Println("scheduled jobs, or a site's deployment script. Reads FILE, or the pasted")
+		fmt.Println("export from stdin when FILE is omitted or -. A plan of what would be created,")
+		fmt.Println("updated, or deleted is shown and must be approved before anything is written.")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var data []byte
+	var err error
+	if file := fs.Arg(0); file != "" && file != "-" {
+		data, err = os.ReadFile(file)
+	} else {
+		if info, statErr := os.Stdin.Stat(); statErr == nil && info.Mode()&os.ModeCharDevice != 0 {
+			fmt.Println("Paste the export, then press Ctrl+D:")
+		}
+		data, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	result, err := importer.Parse(data, importer.Options{Site: *site, User: *user, Root: *root})
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Imported from %s:\n", result.Source)
+	for _, s := range result.Sites {
+		b := s.Blueprint
+		fmt.Printf("  site %s: %s template, root %s", b.Domain, b.Template, b.RootDir())
+		if b.PHPVersion != "" {
+			fmt.Printf(", PHP %s", b.PHPVersion)
+		}
+		if s.DeployHook != "" {
+			fmt.Print(", deploy hook")
+		}
+		fmt.Println()
+	}
+	for _, job := range result.Schedules {
+		fmt.Printf("  job %s as %s: %s\n", job.Cron, job.User, job.Command)
+	}
+	if len(result.Unconverted) > 0 {
+		fmt.Println("\nNot converted (needs manual attention):")
+		for _, item := range result.Unconverted {
+			fmt.Printf("  - %s\n", item)
+		}
+	}
+
+	plan, err := result.Plan()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("\nPlan:\n%s", plan)
+
+	if *dryRun {
+		for _, s := range result.Sites {
+			if s.DeployHook != "" {
+				fmt.Printf("\n# %s deploy hook\n%s", s.Blueprint.Domain, s.DeployHook)
+			}
+		}
+		if len(result.Schedules) > 0 {
+			path, content := result.CronFile()
+			fmt.Printf("\n# %s\n%s", path, content)
+		}
+		return 0
+	}
+
+	if !plan.HasChanges() {
+		fmt.Println("Nothing to change.")
+		return 0
+	}
+	if !*yes && !approvePlan() {
+		fmt.Println("Import cancelled; nothing was written.")
+		return 1
+	}
+
+	written, err := result.Save()
+	fmt.Println()
+	for _, path := range written {
+		fmt.Printf("Wrote %s\n", path)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	if len(result.Sites) > 0 {
+		fmt.Println("\nCreate each site from Nginx Configuration > Add Site using its blueprint.")
+	}
+	return 0
+}
+
+// approvePlan asks on the terminal whether to apply the plan. Stdin may hold
+// the export itself, so the answer is read from /dev/tty; without a terminal
+// the plan must be approved with --yes.
+func approvePlan() bool {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		fmt.Println("\nNo terminal to approve the plan on; run again with --yes to apply it.")
+		return false
+	}
+	defer tty.Close()
+
+	fmt.Print("\nApply this plan? Only 'yes' will be accepted: ")
+	answer, _ := bufio.NewReader(tty).ReadString('\n')
+	return strings.TrimSpace(answer) == "yes"
+}
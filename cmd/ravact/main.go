@@ -4,11 +4,22 @@ import (
 	"embed"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/iperamuna/ravact/internal/config"
+	"github.com/iperamuna/ravact/internal/debuglog"
 	"github.com/iperamuna/ravact/internal/models"
+	"github.com/iperamuna/ravact/internal/notify"
+	"github.com/iperamuna/ravact/internal/settings"
+	"github.com/iperamuna/ravact/internal/stubs"
 	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/screens"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+	"github.com/iperamuna/ravact/internal/vault"
 )
 
 var Version = "0.4.1"
@@ -19,7 +30,8 @@ var embeddedAssets embed.FS
 // Model represents the root application model
 type Model struct {
 	currentScreen          screens.ScreenType
-	previousScreen         screens.ScreenType
+	history                screens.NavStack // Screens behind the current one, for Esc and the breadcrumb
+	theme                  *theme.Theme
 	splash                 screens.SplashModel
 	mainMenu               screens.MainMenuModel
 	setupMenu              screens.SetupMenuModel
@@ -58,6 +70,59 @@ type Model struct {
 	phpInstall             screens.PHPInstallModel
 	phpExtensions          screens.PHPExtensionsModel
 	laravelQueue           screens.LaravelQueueModel
+	envEditor              screens.EnvEditorModel
+	secretsVault           screens.SecretsVaultModel
+	servers                screens.ServersModel
+	siteNotes              screens.SiteNotesModel
+	dashboard              screens.DashboardModel
+	settingsTransfer       screens.SettingsTransferModel
+	settings               screens.SettingsModel
+	namingPolicy           screens.NamingPolicyModel
+	phpIni                 screens.PHPIniModel
+	marketplace            screens.MarketplaceModel
+	nodeManagement         screens.NodeManagementModel
+	nodeApps               screens.NodeAppsModel
+	wordPressSite          screens.WordPressSiteModel
+	composerAudit          screens.ComposerAuditModel
+	artisan                screens.ArtisanModel
+	siteBackup             screens.SiteBackupModel
+	backupTargets          screens.BackupTargetsModel
+	configDrift            screens.ConfigDriftModel
+	sudoManagement         screens.SudoManagementModel
+	userImport             screens.UserImportModel
+	sftpUser               screens.SFTPUserModel
+	gitHistory             screens.GitHistoryModel
+	supervisorProgramLogs  screens.SupervisorProgramLogsModel
+	supervisorGroups       screens.SupervisorGroupsModel
+	pgBouncer              screens.PgBouncerModel
+	meilisearch            screens.MeilisearchModel
+	mail                   screens.MailModel
+	docker                 screens.DockerModel
+	offboardUser           screens.OffboardUserModel
+	logViewer              screens.LogViewerModel
+	systemdServices        screens.SystemdServicesModel
+	configHistory          screens.ConfigHistoryModel
+	siteStack              screens.SiteStackModel
+	siteHardening          screens.SiteHardeningModel
+	dbAccess               screens.DBAccessModel
+	siteProtocols          screens.SiteProtocolsModel
+	siteAccess             screens.SiteAccessModel
+	siteHeaders            screens.SiteHeadersModel
+	siteRateLimits         screens.SiteRateLimitsModel
+	siteAnalytics          screens.SiteAnalyticsModel
+	diskUsage              screens.DiskUsageModel
+	logrotate              screens.LogrotateModel
+	swap                   screens.SwapModel
+	setupBatch             screens.SetupBatchModel
+	tasks                  screens.TasksModel
+	xdebug                 screens.XdebugModel
+	profiler               screens.ProfilerModel
+	exporters              screens.ExportersModel
+	netdata                screens.NetdataModel
+	sshdHardening          screens.SSHDHardeningModel
+	autoUpdates            screens.AutoUpdatesModel
+	laravelLint            screens.LaravelLintModel
+	updates                screens.UpdatesModel
 	frankenphpClassic      screens.FrankenPHPClassicModel
 	frankenphpServices     screens.FrankenPHPServicesModel
 	quickCommands          screens.QuickCommandsModel
@@ -72,6 +137,184 @@ type Model struct {
 	scriptsDir             string
 	configsDir             string
 	copyMode               bool // When true, mouse is released for text selection
+
+	// Idle lock
+	prefs            settings.Preferences
+	lastActivity     time.Time
+	locked           bool
+	lock             screens.LockModel
+	pendingExecution *screens.ExecutionStartMsg // Held until unlock
+
+	// Command palette (Ctrl+P), drawn over the current screen
+	paletteOpen bool
+	palette     screens.PaletteModel
+
+	// Application settings from config.yaml
+	appSettings config.Settings
+
+	// A task waiting for confirmation or for the sudo password
+	pendingRun *executionRequest
+	confirming bool // Asking because ConfirmBeforeExecute is set
+	confirm    screens.Confirmation
+	elevating  bool // Asking for the sudo password
+	elevation  screens.ElevationModel
+}
+
+// executionRequest is a command waiting to run on the execution screen
+type executionRequest struct {
+	command      string
+	description  string
+	returnScreen screens.ScreenType
+	unprivileged bool
+	scripts      []models.SetupScript // Setup scripts to install as a batch instead of command
+	removes      string               // Setup script to stop tracking once command succeeds
+	confirmed    bool
+}
+
+// asciiFlag is set by --ascii and wins over the theme setting
+var asciiFlag bool
+
+// applySettings makes the application settings take effect. Screens opened
+// afterwards pick up the theme; the ASCII filter applies at once.
+func applySettings(s config.Settings) {
+	screens.AppSettings = s
+	system.WebGroup = s.WebGroup
+	theme.ForceASCII = asciiFlag || s.Theme == config.ThemeASCII
+}
+
+// startExecution switches to the execution screen and runs the request,
+// first asking for confirmation when the settings require it and for the
+// sudo password when the task needs root and sudo has none cached
+func (m Model) startExecution(req executionRequest) (Model, tea.Cmd) {
+	if m.appSettings.ConfirmBeforeExecute && !req.confirmed {
+		m.pendingRun = &req
+		m.confirming = true
+		preview := executionPreview(req.command)
+		if len(req.scripts) > 0 {
+			names := make([]string, len(req.scripts))
+			for i, script := range req.scripts {
+				names[i] = "  " + m.theme.Symbols.Bullet + " " + script.Name
+			}
+			preview = strings.Join(names, "\n")
+		}
+		m.confirm = screens.NewConfirmation("run", "Run "+req.description+"?", preview, screens.ConfirmNormal)
+		return m, nil
+	}
+	if !req.unprivileged && system.PrivilegePromptNeeded() {
+		m.pendingRun = &req
+		m.elevating = true
+		m.elevation = screens.NewElevationModel(req.description)
+		return m, nil
+	}
+	m.pendingRun = nil
+
+	if len(req.scripts) > 0 {
+		debuglog.Screen(m.currentScreen.Title(), screens.SetupBatchScreen.Title())
+		m.history = m.history.Navigate(m.currentScreen, screens.SetupBatchScreen)
+		m.currentScreen = screens.SetupBatchScreen
+		m.setupBatch = screens.NewSetupBatchModel(req.scripts)
+		initCmd := m.setupBatch.Init()
+		if m.width > 0 && m.height > 0 {
+			sizeMsg := m.screenSize()
+			return m, tea.Batch(initCmd, func() tea.Msg { return sizeMsg })
+		}
+		return m, initCmd
+	}
+
+	m.currentScreen = screens.ExecutionScreen
+	m.execution = screens.NewExecutionModel(req.command, req.description, req.returnScreen)
+	if req.unprivileged {
+		m.execution = m.execution.WithoutPrivileges()
+	}
+	if req.removes != "" {
+		m.execution = m.execution.Removing(req.removes)
+	}
+	initCmd := m.execution.Init()
+
+	// Send window size
+	if m.width > 0 && m.height > 0 {
+		sizeMsg := m.screenSize()
+		return m, tea.Batch(initCmd, func() tea.Msg { return sizeMsg })
+	}
+	return m, initCmd
+}
+
+// attachTask shows the output of a task running detached on the host,
+// returning to the current screen afterwards
+func (m Model) attachTask(task system.Task) (Model, tea.Cmd) {
+	debuglog.Screen(m.currentScreen.Title(), screens.ExecutionScreen.Title())
+	m.execution = screens.AttachExecutionModel(task, m.currentScreen)
+	m.currentScreen = screens.ExecutionScreen
+	initCmd := m.execution.Init()
+	if m.width > 0 && m.height > 0 {
+		sizeMsg := m.screenSize()
+		return m, tea.Batch(initCmd, func() tea.Msg { return sizeMsg })
+	}
+	return m, initCmd
+}
+
+// executionPreview shows the start of a command for the confirmation
+func executionPreview(command string) string {
+	const maxLines = 12
+	lines := strings.Split(strings.TrimSpace(command), "\n")
+	if len(lines) > maxLines {
+		lines = append(lines[:maxLines], fmt.Sprintf("... (%d more lines)", len(lines)-maxLines))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// executionReturnScreens are the screens whose tasks finish somewhere other
+// than the screen that started them
+var executionReturnScreens = map[screens.ScreenType]screens.ScreenType{
+	screens.SetupActionScreen:   screens.SetupMenuScreen,
+	screens.NodeVersionScreen:   screens.SiteCommandsScreen,
+	screens.PHPVersionScreen:    screens.SiteCommandsScreen,
+	screens.PHPIniScreen:        screens.PHPFPMManagementScreen,
+	screens.ProfilerScreen:      screens.DeveloperToolkitScreen,
+	screens.WordPressSiteScreen: screens.NginxConfigScreen,
+}
+
+// showsBreadcrumb reports whether the current screen is drawn below the
+// breadcrumb; the splash and the main menu have nothing to trail
+func (m Model) showsBreadcrumb() bool {
+	return m.currentScreen != screens.SplashScreen && m.currentScreen != screens.MainMenuScreen
+}
+
+// screenSize is the window size the current screen draws in
+func (m Model) screenSize() tea.WindowSizeMsg {
+	if m.showsBreadcrumb() {
+		return tea.WindowSizeMsg{Width: m.width, Height: m.height - screens.BreadcrumbHeight}
+	}
+	return tea.WindowSizeMsg{Width: m.width, Height: m.height}
+}
+
+// backFrom turns a screen leaving on Esc for a parent it names itself into
+// a step back, so Esc returns to the screen it was actually opened from.
+// Parents still on the stack are kept: naming them unwinds to them and
+// reloads them.
+func (m Model) backFrom(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil || m.currentScreen == screens.ExecutionScreen {
+		return cmd
+	}
+	from, history := m.currentScreen, m.history
+	return func() tea.Msg {
+		msg := cmd()
+		nav, ok := msg.(screens.NavigateMsg)
+		if !ok || nav.Data != nil || nav.Screen == from || nav.Screen == history.Previous() || slices.Contains(history, nav.Screen) {
+			return msg
+		}
+		return screens.BackMsg{}
+	}
+}
+
+// idleCheckInterval is how often the idle lock timeout is checked
+const idleCheckInterval = 15 * time.Second
+
+// idleTickMsg triggers an idle lock check
+type idleTickMsg struct{}
+
+func idleTick() tea.Cmd {
+	return tea.Tick(idleCheckInterval, func(time.Time) tea.Msg { return idleTickMsg{} })
 }
 
 // NewModel creates a new application model
@@ -79,6 +322,16 @@ func NewModel() Model {
 	// No need to extract - we'll read directly from embedded FS
 	// Removed info message - silent operation
 
+	prefs, err := settings.LoadPreferences()
+	if err != nil {
+		debuglog.ParseFailure("preferences", err)
+	}
+	appSettings, err := config.Load()
+	if err != nil {
+		debuglog.ParseFailure("settings", err)
+	}
+	applySettings(appSettings)
+
 	return Model{
 		currentScreen:  screens.SplashScreen,
 		splash:         screens.NewSplashModel(Version),
@@ -90,6 +343,10 @@ func NewModel() Model {
 		quickCommands:  screens.NewQuickCommandsModel(),
 		scriptsDir:     "assets/scripts",
 		configsDir:     "assets/configs",
+		theme:          theme.DefaultTheme(),
+		appSettings:    appSettings,
+		prefs:          prefs,
+		lastActivity:   time.Now(),
 	}
 }
 
@@ -102,7 +359,53 @@ func GetEmbeddedFS() embed.FS {
 
 // Init initializes the application
 func (m Model) Init() tea.Cmd {
-	return nil
+	return idleTick()
+}
+
+// lockScreen locks ravact and forgets cached credentials so nothing
+// privileged can run until the user unlocks it
+func (m Model) lockScreen() Model {
+	m.locked = true
+	m.lock = screens.NewLockModel(m.prefs)
+	vault.Lock()
+	system.DropSudoCredentials()
+	return m
+}
+
+// updateLocked handles messages while the screen is locked. Key presses go
+// to the lock screen and new tasks are held; anything else is passed on so
+// running screens keep working.
+func (m Model) updateLocked(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit, true
+		}
+		var cmd tea.Cmd
+		m.lock, cmd = m.lock.Update(msg)
+		return m, cmd, true
+
+	case tea.MouseMsg:
+		return m, nil, true
+
+	case screens.UnlockResultMsg:
+		m.lock, _ = m.lock.Update(msg)
+		if msg.Err != nil {
+			return m, nil, true
+		}
+		m.locked = false
+		m.lastActivity = time.Now()
+		if pending := m.pendingExecution; pending != nil {
+			m.pendingExecution = nil
+			return m, func() tea.Msg { return *pending }, true
+		}
+		return m, nil, true
+
+	case screens.ExecutionStartMsg:
+		m.pendingExecution = &msg
+		return m, nil, true
+	}
+	return m, nil, false
 }
 
 // updateCurrentScreen delegates the message to the current screen
@@ -217,6 +520,22 @@ func (m Model) updateCurrentScreen(msg tea.Msg) (Model, tea.Cmd) {
 		var model tea.Model
 		model, cmd = m.firewallManagement.Update(msg)
 		m.firewallManagement = model.(screens.FirewallManagementModel)
+	case screens.SSHDHardeningScreen:
+		var model tea.Model
+		model, cmd = m.sshdHardening.Update(msg)
+		m.sshdHardening = model.(screens.SSHDHardeningModel)
+	case screens.AutoUpdatesScreen:
+		var model tea.Model
+		model, cmd = m.autoUpdates.Update(msg)
+		m.autoUpdates = model.(screens.AutoUpdatesModel)
+	case screens.LaravelLintScreen:
+		var model tea.Model
+		model, cmd = m.laravelLint.Update(msg)
+		m.laravelLint = model.(screens.LaravelLintModel)
+	case screens.UpdatesScreen:
+		var model tea.Model
+		model, cmd = m.updates.Update(msg)
+		m.updates = model.(screens.UpdatesModel)
 	case screens.DragonflyInstallScreen:
 		var model tea.Model
 		model, cmd = m.dragonflyInstall.Update(msg)
@@ -253,6 +572,202 @@ func (m Model) updateCurrentScreen(msg tea.Msg) (Model, tea.Cmd) {
 		var model tea.Model
 		model, cmd = m.laravelQueue.Update(msg)
 		m.laravelQueue = model.(screens.LaravelQueueModel)
+	case screens.EnvEditorScreen:
+		var model tea.Model
+		model, cmd = m.envEditor.Update(msg)
+		m.envEditor = model.(screens.EnvEditorModel)
+	case screens.SecretsVaultScreen:
+		var model tea.Model
+		model, cmd = m.secretsVault.Update(msg)
+		m.secretsVault = model.(screens.SecretsVaultModel)
+	case screens.ServersScreen:
+		var model tea.Model
+		model, cmd = m.servers.Update(msg)
+		m.servers = model.(screens.ServersModel)
+	case screens.SiteNotesScreen:
+		var model tea.Model
+		model, cmd = m.siteNotes.Update(msg)
+		m.siteNotes = model.(screens.SiteNotesModel)
+	case screens.SiteStackScreen:
+		var model tea.Model
+		model, cmd = m.siteStack.Update(msg)
+		m.siteStack = model.(screens.SiteStackModel)
+	case screens.SiteHardeningScreen:
+		var model tea.Model
+		model, cmd = m.siteHardening.Update(msg)
+		m.siteHardening = model.(screens.SiteHardeningModel)
+	case screens.DBAccessScreen:
+		var model tea.Model
+		model, cmd = m.dbAccess.Update(msg)
+		m.dbAccess = model.(screens.DBAccessModel)
+	case screens.SiteProtocolsScreen:
+		var model tea.Model
+		model, cmd = m.siteProtocols.Update(msg)
+		m.siteProtocols = model.(screens.SiteProtocolsModel)
+	case screens.SiteAccessScreen:
+		var model tea.Model
+		model, cmd = m.siteAccess.Update(msg)
+		m.siteAccess = model.(screens.SiteAccessModel)
+	case screens.SiteHeadersScreen:
+		var model tea.Model
+		model, cmd = m.siteHeaders.Update(msg)
+		m.siteHeaders = model.(screens.SiteHeadersModel)
+	case screens.SiteRateLimitsScreen:
+		var model tea.Model
+		model, cmd = m.siteRateLimits.Update(msg)
+		m.siteRateLimits = model.(screens.SiteRateLimitsModel)
+	case screens.SiteAnalyticsScreen:
+		var model tea.Model
+		model, cmd = m.siteAnalytics.Update(msg)
+		m.siteAnalytics = model.(screens.SiteAnalyticsModel)
+	case screens.DiskUsageScreen:
+		var model tea.Model
+		model, cmd = m.diskUsage.Update(msg)
+		m.diskUsage = model.(screens.DiskUsageModel)
+	case screens.LogrotateScreen:
+		var model tea.Model
+		model, cmd = m.logrotate.Update(msg)
+		m.logrotate = model.(screens.LogrotateModel)
+	case screens.SwapScreen:
+		var model tea.Model
+		model, cmd = m.swap.Update(msg)
+		m.swap = model.(screens.SwapModel)
+	case screens.SetupBatchScreen:
+		var model tea.Model
+		model, cmd = m.setupBatch.Update(msg)
+		m.setupBatch = model.(screens.SetupBatchModel)
+	case screens.TasksScreen:
+		var model tea.Model
+		model, cmd = m.tasks.Update(msg)
+		m.tasks = model.(screens.TasksModel)
+	case screens.XdebugScreen:
+		var model tea.Model
+		model, cmd = m.xdebug.Update(msg)
+		m.xdebug = model.(screens.XdebugModel)
+	case screens.ProfilerScreen:
+		var model tea.Model
+		model, cmd = m.profiler.Update(msg)
+		m.profiler = model.(screens.ProfilerModel)
+	case screens.ExportersScreen:
+		var model tea.Model
+		model, cmd = m.exporters.Update(msg)
+		m.exporters = model.(screens.ExportersModel)
+	case screens.NetdataScreen:
+		var model tea.Model
+		model, cmd = m.netdata.Update(msg)
+		m.netdata = model.(screens.NetdataModel)
+	case screens.DashboardScreen:
+		var model tea.Model
+		model, cmd = m.dashboard.Update(msg)
+		m.dashboard = model.(screens.DashboardModel)
+	case screens.SettingsTransferScreen:
+		var model tea.Model
+		model, cmd = m.settingsTransfer.Update(msg)
+		m.settingsTransfer = model.(screens.SettingsTransferModel)
+	case screens.SettingsScreen:
+		var model tea.Model
+		model, cmd = m.settings.Update(msg)
+		m.settings = model.(screens.SettingsModel)
+	case screens.NamingPolicyScreen:
+		var model tea.Model
+		model, cmd = m.namingPolicy.Update(msg)
+		m.namingPolicy = model.(screens.NamingPolicyModel)
+	case screens.PHPIniScreen:
+		var model tea.Model
+		model, cmd = m.phpIni.Update(msg)
+		m.phpIni = model.(screens.PHPIniModel)
+	case screens.MarketplaceScreen:
+		var model tea.Model
+		model, cmd = m.marketplace.Update(msg)
+		m.marketplace = model.(screens.MarketplaceModel)
+	case screens.NodeManagementScreen:
+		var model tea.Model
+		model, cmd = m.nodeManagement.Update(msg)
+		m.nodeManagement = model.(screens.NodeManagementModel)
+	case screens.NodeAppsScreen:
+		var model tea.Model
+		model, cmd = m.nodeApps.Update(msg)
+		m.nodeApps = model.(screens.NodeAppsModel)
+	case screens.WordPressSiteScreen:
+		var model tea.Model
+		model, cmd = m.wordPressSite.Update(msg)
+		m.wordPressSite = model.(screens.WordPressSiteModel)
+	case screens.ComposerAuditScreen:
+		var model tea.Model
+		model, cmd = m.composerAudit.Update(msg)
+		m.composerAudit = model.(screens.ComposerAuditModel)
+	case screens.ArtisanScreen:
+		var model tea.Model
+		model, cmd = m.artisan.Update(msg)
+		m.artisan = model.(screens.ArtisanModel)
+	case screens.SiteBackupScreen:
+		var model tea.Model
+		model, cmd = m.siteBackup.Update(msg)
+		m.siteBackup = model.(screens.SiteBackupModel)
+	case screens.BackupTargetsScreen:
+		var model tea.Model
+		model, cmd = m.backupTargets.Update(msg)
+		m.backupTargets = model.(screens.BackupTargetsModel)
+	case screens.ConfigDriftScreen:
+		var model tea.Model
+		model, cmd = m.configDrift.Update(msg)
+		m.configDrift = model.(screens.ConfigDriftModel)
+	case screens.SudoManagementScreen:
+		var model tea.Model
+		model, cmd = m.sudoManagement.Update(msg)
+		m.sudoManagement = model.(screens.SudoManagementModel)
+	case screens.UserImportScreen:
+		var model tea.Model
+		model, cmd = m.userImport.Update(msg)
+		m.userImport = model.(screens.UserImportModel)
+	case screens.SFTPUserScreen:
+		var model tea.Model
+		model, cmd = m.sftpUser.Update(msg)
+		m.sftpUser = model.(screens.SFTPUserModel)
+	case screens.GitHistoryScreen:
+		var model tea.Model
+		model, cmd = m.gitHistory.Update(msg)
+		m.gitHistory = model.(screens.GitHistoryModel)
+	case screens.SupervisorProgramLogsScreen:
+		var model tea.Model
+		model, cmd = m.supervisorProgramLogs.Update(msg)
+		m.supervisorProgramLogs = model.(screens.SupervisorProgramLogsModel)
+	case screens.SupervisorGroupsScreen:
+		var model tea.Model
+		model, cmd = m.supervisorGroups.Update(msg)
+		m.supervisorGroups = model.(screens.SupervisorGroupsModel)
+	case screens.PgBouncerScreen:
+		var model tea.Model
+		model, cmd = m.pgBouncer.Update(msg)
+		m.pgBouncer = model.(screens.PgBouncerModel)
+	case screens.MeilisearchScreen:
+		var model tea.Model
+		model, cmd = m.meilisearch.Update(msg)
+		m.meilisearch = model.(screens.MeilisearchModel)
+	case screens.MailScreen:
+		var model tea.Model
+		model, cmd = m.mail.Update(msg)
+		m.mail = model.(screens.MailModel)
+	case screens.DockerScreen:
+		var model tea.Model
+		model, cmd = m.docker.Update(msg)
+		m.docker = model.(screens.DockerModel)
+	case screens.OffboardUserScreen:
+		var model tea.Model
+		model, cmd = m.offboardUser.Update(msg)
+		m.offboardUser = model.(screens.OffboardUserModel)
+	case screens.LogViewerScreen:
+		var model tea.Model
+		model, cmd = m.logViewer.Update(msg)
+		m.logViewer = model.(screens.LogViewerModel)
+	case screens.SystemdServicesScreen:
+		var model tea.Model
+		model, cmd = m.systemdServices.Update(msg)
+		m.systemdServices = model.(screens.SystemdServicesModel)
+	case screens.ConfigHistoryScreen:
+		var model tea.Model
+		model, cmd = m.configHistory.Update(msg)
+		m.configHistory = model.(screens.ConfigHistoryModel)
 	case screens.FrankenPHPClassicScreen:
 		var model tea.Model
 		model, cmd = m.frankenphpClassic.Update(msg)
@@ -292,20 +807,100 @@ func (m Model) updateCurrentScreen(msg tea.Msg) (Model, tea.Cmd) {
 // Update handles all application messages
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
+
+	if _, ok := msg.(idleTickMsg); ok {
+		timeout := time.Duration(m.prefs.IdleLockMinutes) * time.Minute
+		if !m.locked && timeout > 0 && time.Since(m.lastActivity) >= timeout {
+			m = m.lockScreen()
+		}
+		return m, idleTick()
+	}
+	if m.locked {
+		var handled bool
+		if m, cmd, handled = m.updateLocked(msg); handled {
+			return m, cmd
+		}
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		// Propagate size to all screens
 		m.splash.SetSize(msg.Width, msg.Height)
-		// No need to return here, let it propagate to current screen
+		// The current screen gets the space below the breadcrumb
+		return m.updateCurrentScreen(m.screenSize())
+
+	case tea.MouseMsg:
+		m.lastActivity = time.Now()
+
+	case screens.ElevationResultMsg:
+		if !m.elevating {
+			return m, nil
+		}
+		if msg.Cancelled {
+			m.elevating = false
+			m.pendingRun = nil
+			return m, nil
+		}
+		m.elevation, _ = m.elevation.Update(msg)
+		if msg.Err != nil {
+			return m, nil
+		}
+		m.elevating = false
+		return m.startExecution(*m.pendingRun)
+
+	case screens.PreferencesChangedMsg:
+		m.prefs = msg.Preferences
+		applySettings(msg.Settings)
+		m.appSettings = msg.Settings
+		m.theme = theme.DefaultTheme()
+		m.lastActivity = time.Now()
 
 	case tea.KeyMsg:
+		m.lastActivity = time.Now()
+
 		// Global quit keys
 		if msg.String() == "ctrl+c" {
 			return m, tea.Quit
 		}
 
+		// A task waiting for confirmation or the sudo password takes every key
+		if m.confirming {
+			var result screens.ConfirmResult
+			m.confirm, result = m.confirm.Update(msg)
+			switch result {
+			case screens.ConfirmAccepted:
+				req := *m.pendingRun
+				req.confirmed = true
+				m.confirming = false
+				return m.startExecution(req)
+			case screens.ConfirmCancelled:
+				m.confirming = false
+				m.pendingRun = nil
+			}
+			return m, nil
+		}
+		if m.elevating {
+			m.elevation, cmd = m.elevation.Update(msg)
+			return m, cmd
+		}
+
+		// The command palette takes every key while it is open
+		if m.paletteOpen {
+			var done bool
+			m.palette, cmd, done = m.palette.Update(msg)
+			if done {
+				m.paletteOpen = false
+			}
+			return m, cmd
+		}
+		if msg.String() == "ctrl+p" && m.currentScreen != screens.SplashScreen {
+			m.palette = screens.NewPaletteModel(m.mainMenu.Items())
+			m.paletteOpen = true
+			return m, nil
+		}
+
 		// Toggle copy mode with Ctrl+Y
 		if msg.String() == "ctrl+y" {
 			m.copyMode = !m.copyMode
@@ -318,21 +913,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case screens.BackMsg:
-		// Go back to previous screen
-		if m.previousScreen != 0 {
-			m.currentScreen = m.previousScreen
-			m.previousScreen = screens.MainMenuScreen // Reset to main menu as fallback
-		} else {
-			m.currentScreen = screens.MainMenuScreen
+		// Return to the previous screen as it was left; its model is kept,
+		// so only the window size may be out of date
+		from := m.currentScreen
+		m.currentScreen, m.history = m.history.Back()
+		debuglog.Screen(from.Title(), m.currentScreen.Title())
+		if m.width > 0 && m.height > 0 {
+			return m.updateCurrentScreen(m.screenSize())
 		}
 		return m, nil
 
 	case screens.NavigateMsg:
-		// Don't set previousScreen if coming from ExecutionScreen
-		// (we want to go back to where we were before execution, not to execution)
-		if m.currentScreen != screens.ExecutionScreen {
-			m.previousScreen = m.currentScreen
-		}
+		debuglog.Screen(m.currentScreen.Title(), msg.Screen.Title())
+		m.history = m.history.Navigate(m.currentScreen, msg.Screen)
 		m.currentScreen = msg.Screen
 
 		// Handle screen-specific initialization with data
@@ -343,7 +936,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if s, ok := data["status"].(models.ServiceStatus); ok {
 						status = s
 					}
-					m.setupAction = screens.NewSetupActionModel(script, status)
+					provision, _ := data["provision"].(system.ProvisionState)
+					m.setupAction = screens.NewSetupActionModel(script, status, provision)
 				}
 			}
 		}
@@ -351,6 +945,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Initialize screen-specific models that need async loading or data
 		var initCmd tea.Cmd
 		switch msg.Screen {
+		case screens.SetupMenuScreen:
+			// Refresh statuses and provisioning state, e.g. after an install
+			initCmd = m.setupMenu.Init()
+
+		case screens.InstalledAppsScreen:
+			// Statuses come from the cache unless they expired
+			initCmd = m.installedApps.Init()
+
 		case screens.UserManagementScreen:
 			// Reinitialize user management on navigation
 			m.userManagement = screens.NewUserManagementModel()
@@ -375,6 +977,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Initialize config menu screen
 			m.configMenu = screens.NewConfigMenuModel()
 
+		case screens.QuickCommandsScreen:
+			// Pick up commands added to commands.yaml since the last visit
+			m.quickCommands = screens.NewQuickCommandsModel()
+
 		case screens.NginxConfigScreen:
 			// Initialize Nginx config screen
 			m.nginxConfig = screens.NewNginxConfigModel()
@@ -416,6 +1022,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			}
+			initCmd = m.editorSelection.Init()
 
 		case screens.RedisConfigScreen:
 			// Initialize Redis config screen
@@ -532,6 +1139,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Initialize Firewall management screen
 			m.firewallManagement = screens.NewFirewallManagementModel()
 
+		case screens.SSHDHardeningScreen:
+			m.sshdHardening = screens.NewSSHDHardeningModel()
+			initCmd = m.sshdHardening.Init()
+
+		case screens.AutoUpdatesScreen:
+			m.autoUpdates = screens.NewAutoUpdatesModel()
+			initCmd = m.autoUpdates.Init()
+
+		case screens.LaravelLintScreen:
+			m.laravelLint = screens.NewLaravelLintModel()
+			initCmd = m.laravelLint.Init()
+
+		case screens.UpdatesScreen:
+			m.updates = screens.NewUpdatesModel()
+			initCmd = m.updates.Init()
+
 		case screens.DragonflyInstallScreen:
 			// Initialize Dragonfly installation options screen
 			m.dragonflyInstall = screens.NewDragonflyInstallModel()
@@ -592,6 +1215,325 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.laravelQueue = screens.NewLaravelQueueModel(projectPath, systemUser)
 			initCmd = m.laravelQueue.Init()
 
+		case screens.EnvEditorScreen:
+			envPath := ""
+			if msg.Data != nil {
+				if data, ok := msg.Data.(map[string]interface{}); ok {
+					envPath, _ = data["path"].(string)
+				}
+			}
+			m.envEditor = screens.NewEnvEditorModel(envPath)
+			initCmd = m.envEditor.Init()
+
+		case screens.SecretsVaultScreen:
+			m.secretsVault = screens.NewSecretsVaultModel()
+			initCmd = m.secretsVault.Init()
+
+		case screens.ServersScreen:
+			m.servers = screens.NewServersModel()
+			initCmd = m.servers.Init()
+
+		case screens.SiteNotesScreen:
+			if msg.Data != nil {
+				if data, ok := msg.Data.(map[string]interface{}); ok {
+					if site, ok := data["site"].(system.NginxSite); ok {
+						m.siteNotes = screens.NewSiteNotesModel(site)
+						initCmd = m.siteNotes.Init()
+					}
+				}
+			}
+
+		case screens.SiteStackScreen:
+			// Returning from a stack restart keeps the graph and resumes refreshing
+			initCmd = m.siteStack.Init()
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				if site, ok := data["site"].(system.NginxSite); ok {
+					if restart, _ := data["restart"].(bool); restart {
+						m.siteStack = screens.NewSiteStackRestartModel(site)
+					} else if drain, _ := data["drain"].(bool); drain {
+						m.siteStack = screens.NewSiteStackDrainModel(site)
+					} else {
+						m.siteStack = screens.NewSiteStackModel(site)
+					}
+					initCmd = m.siteStack.Init()
+				}
+			}
+
+		case screens.SiteHardeningScreen:
+			// Returning from the script keeps the before checklist and re-inspects
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				if site, ok := data["site"].(system.NginxSite); ok {
+					m.siteHardening = screens.NewSiteHardeningModel(site)
+				}
+			}
+			initCmd = m.siteHardening.Init()
+
+		case screens.DBAccessScreen:
+			// Returning from the script keeps the model and refreshes the grant
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				if engine, ok := data["engine"].(string); ok {
+					m.dbAccess = screens.NewDBAccessModel(engine)
+				}
+			}
+			initCmd = m.dbAccess.Init()
+
+		case screens.SiteProtocolsScreen:
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				if site, ok := data["site"].(system.NginxSite); ok {
+					m.siteProtocols = screens.NewSiteProtocolsModel(site)
+					initCmd = m.siteProtocols.Init()
+				}
+			}
+
+		case screens.SiteAccessScreen:
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				if site, ok := data["site"].(system.NginxSite); ok {
+					m.siteAccess = screens.NewSiteAccessModel(site)
+					initCmd = m.siteAccess.Init()
+				}
+			}
+
+		case screens.SiteHeadersScreen:
+			// Returning from the script keeps the model and re-reads the headers
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				if site, ok := data["site"].(system.NginxSite); ok {
+					m.siteHeaders = screens.NewSiteHeadersModel(site)
+				}
+			}
+			initCmd = m.siteHeaders.Init()
+
+		case screens.SiteRateLimitsScreen:
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				if site, ok := data["site"].(system.NginxSite); ok {
+					m.siteRateLimits = screens.NewSiteRateLimitsModel(site)
+					initCmd = m.siteRateLimits.Init()
+				}
+			}
+
+		case screens.SiteAnalyticsScreen:
+			// Returning from the install or report keeps the model and re-runs GoAccess
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				if site, ok := data["site"].(system.NginxSite); ok {
+					m.siteAnalytics = screens.NewSiteAnalyticsModel(site)
+				}
+			}
+			initCmd = m.siteAnalytics.Init()
+
+		case screens.DiskUsageScreen:
+			// Returning from a cleanup keeps the model so it can show the space freed
+			if !m.diskUsage.Cleaning() {
+				m.diskUsage = screens.NewDiskUsageModel()
+			}
+			initCmd = m.diskUsage.Init()
+
+		case screens.LogrotateScreen:
+			// Returning from a simulation keeps the model and its preview
+			if !m.logrotate.Waiting() {
+				m.logrotate = screens.NewLogrotateModel()
+			}
+			initCmd = m.logrotate.Init()
+
+		case screens.SwapScreen:
+			m.swap = screens.NewSwapModel()
+			initCmd = m.swap.Init()
+
+		case screens.TasksScreen:
+			m.tasks = screens.NewTasksModel()
+			initCmd = m.tasks.Init()
+
+		case screens.XdebugScreen:
+			m.xdebug = screens.NewXdebugModel()
+			initCmd = m.xdebug.Init()
+
+		case screens.ProfilerScreen:
+			m.profiler = screens.NewProfilerModel()
+			initCmd = m.profiler.Init()
+
+		case screens.ExportersScreen:
+			m.exporters = screens.NewExportersModel()
+			initCmd = m.exporters.Init()
+
+		case screens.NetdataScreen:
+			m.netdata = screens.NewNetdataModel()
+			initCmd = m.netdata.Init()
+
+		case screens.DashboardScreen:
+			m.dashboard = screens.NewDashboardModel()
+			initCmd = m.dashboard.Init()
+
+		case screens.SettingsTransferScreen:
+			m.settingsTransfer = screens.NewSettingsTransferModel(Version)
+			initCmd = m.settingsTransfer.Init()
+
+		case screens.SettingsScreen:
+			m.settings = screens.NewSettingsModel()
+			initCmd = m.settings.Init()
+
+		case screens.NamingPolicyScreen:
+			m.namingPolicy = screens.NewNamingPolicyModel()
+			initCmd = m.namingPolicy.Init()
+
+		case screens.PHPIniScreen:
+			m.phpIni = screens.NewPHPIniModel()
+			initCmd = m.phpIni.Init()
+
+		case screens.MarketplaceScreen:
+			m.marketplace = screens.NewMarketplaceModel()
+			initCmd = m.marketplace.Init()
+
+		case screens.NodeManagementScreen:
+			// Opened from a site's details to pin its version in .nvmrc
+			m.nodeManagement = screens.NewNodeManagementModel()
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				if site, ok := data["site"].(system.NginxSite); ok {
+					m.nodeManagement = m.nodeManagement.WithSite(site)
+				}
+			}
+			initCmd = m.nodeManagement.Init()
+
+		case screens.NodeAppsScreen:
+			// Opened from a site's details to show only its apps
+			m.nodeApps = screens.NewNodeAppsModel()
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				if site, ok := data["site"].(system.NginxSite); ok {
+					m.nodeApps = m.nodeApps.WithSite(site)
+				}
+			}
+			initCmd = m.nodeApps.Init()
+
+		case screens.WordPressSiteScreen:
+			m.wordPressSite = screens.NewWordPressSiteModel()
+			initCmd = m.wordPressSite.Init()
+
+		case screens.ComposerAuditScreen:
+			var dir, user string
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				dir, _ = data["dir"].(string)
+				user, _ = data["user"].(string)
+			}
+			m.composerAudit = screens.NewComposerAuditModel(dir, user)
+			initCmd = m.composerAudit.Init()
+
+		case screens.ArtisanScreen:
+			// Coming back from a run keeps the loaded list and search
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				dir, _ := data["dir"].(string)
+				user, _ := data["user"].(string)
+				m.artisan = screens.NewArtisanModel(dir, user)
+				initCmd = m.artisan.Init()
+			}
+
+		case screens.SiteBackupScreen:
+			// Returning from a backup or restore keeps the model and reloads the list
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				if site, ok := data["site"].(system.NginxSite); ok {
+					m.siteBackup = screens.NewSiteBackupModel(site)
+				}
+			}
+			initCmd = m.siteBackup.Init()
+
+		case screens.BackupTargetsScreen:
+			// Returning from a connection test keeps the cursor
+			if msg.Data != nil {
+				m.backupTargets = screens.NewBackupTargetsModel()
+			}
+			initCmd = m.backupTargets.Init()
+
+		case screens.ConfigDriftScreen:
+			m.configDrift = screens.NewConfigDriftModel()
+			initCmd = m.configDrift.Init()
+
+		case screens.SudoManagementScreen:
+			if user, ok := msg.Data.(system.User); ok {
+				m.sudoManagement = screens.NewSudoManagementModel(user)
+			}
+
+		case screens.UserImportScreen:
+			m.userImport = screens.NewUserImportModel()
+			initCmd = m.userImport.Init()
+
+		case screens.SFTPUserScreen:
+			m.sftpUser = screens.NewSFTPUserModel()
+			initCmd = m.sftpUser.Init()
+
+		case screens.GitHistoryScreen:
+			m.gitHistory = screens.NewGitHistoryModel()
+
+		case screens.SupervisorProgramLogsScreen:
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				manager, _ := data["manager"].(*system.SupervisorManager)
+				program, _ := data["program"].(string)
+				if manager != nil {
+					m.supervisorProgramLogs = screens.NewSupervisorProgramLogsModel(manager, program)
+				}
+			}
+
+		case screens.SupervisorGroupsScreen:
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				if manager, ok := data["manager"].(*system.SupervisorManager); ok {
+					m.supervisorGroups = screens.NewSupervisorGroupsModel(manager)
+				}
+			}
+
+		case screens.PgBouncerScreen:
+			m.pgBouncer = screens.NewPgBouncerModel()
+			initCmd = m.pgBouncer.Init()
+
+		case screens.MeilisearchScreen:
+			m.meilisearch = screens.NewMeilisearchModel()
+			initCmd = m.meilisearch.Init()
+		case screens.MailScreen:
+			m.mail = screens.NewMailModel()
+			initCmd = m.mail.Init()
+		case screens.DockerScreen:
+			m.docker = screens.NewDockerModel()
+			initCmd = m.docker.Init()
+
+		case screens.OffboardUserScreen:
+			if user, ok := msg.Data.(system.User); ok {
+				m.offboardUser = screens.NewOffboardUserModel(user)
+				initCmd = m.offboardUser.Init()
+			}
+
+		case screens.LogViewerScreen:
+			// Callers can open a single unit's journal, a container, or a log file directly
+			data, _ := msg.Data.(map[string]interface{})
+			unit, _ := data["unit"].(string)
+			path, _ := data["path"].(string)
+			container, _ := data["container"].(string)
+			compose, _ := data["compose"].(string)
+			switch {
+			case unit != "":
+				m.logViewer = screens.NewLogViewerModelForSource(system.JournalSource(unit))
+			case container != "":
+				m.logViewer = screens.NewLogViewerModelForSource(system.DockerSource(container))
+			case compose != "":
+				m.logViewer = screens.NewLogViewerModelForSource(system.ComposeSource(compose))
+			case path != "":
+				group, _ := data["group"].(string)
+				m.logViewer = screens.NewLogViewerModelForSource(system.FileSource(group, path))
+			default:
+				m.logViewer = screens.NewLogViewerModel()
+			}
+			initCmd = m.logViewer.Init()
+
+		case screens.SystemdServicesScreen:
+			m.systemdServices = screens.NewSystemdServicesModel()
+			initCmd = m.systemdServices.Init()
+
+		case screens.ConfigHistoryScreen:
+			m.configHistory = screens.NewConfigHistoryModel()
+			initCmd = m.configHistory.Init()
+
+		case screens.MainMenuScreen:
+			// Reload host details after switching servers
+			if data, ok := msg.Data.(map[string]interface{}); ok {
+				if changed, _ := data["serverChanged"].(bool); changed {
+					m.mainMenu.RefreshSystemInfo()
+				}
+			}
+			initCmd = m.mainMenu.Init()
+
 		case screens.FrankenPHPClassicScreen:
 			// Initialize FrankenPHP Classic Mode screen
 			if msg.Data != nil {
@@ -601,6 +1543,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					} else {
 						m.frankenphpClassic = screens.NewFrankenPHPClassicModel()
 					}
+					if worker, _ := data["worker"].(bool); worker {
+						m.frankenphpClassic = m.frankenphpClassic.WithWorkerMode()
+					}
 				} else {
 					m.frankenphpClassic = screens.NewFrankenPHPClassicModel()
 				}
@@ -710,103 +1655,37 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Send window size to the new screen immediately after navigation
 		if m.width > 0 && m.height > 0 {
-			sizeMsg := tea.WindowSizeMsg{Width: m.width, Height: m.height}
+			sizeMsg := m.screenSize()
 			// Combine init cmd and size message
 			return m, tea.Batch(initCmd, func() tea.Msg { return sizeMsg })
 		}
 		return m, initCmd
 
 	case screens.ExecutionStartMsg:
-		// Determine return screen based on current screen (before changing it)
-		returnScreen := screens.MainMenuScreen
-		switch m.currentScreen {
-		// Setup screens
-		case screens.SetupActionScreen:
-			returnScreen = screens.SetupMenuScreen
-		case screens.SetupMenuScreen:
-			returnScreen = screens.SetupMenuScreen
-
-		// Quick commands
-		case screens.QuickCommandsScreen:
-			returnScreen = screens.QuickCommandsScreen
-
-		// FrankenPHP screens
-		case screens.FrankenPHPServicesScreen:
-			returnScreen = screens.FrankenPHPServicesScreen
-		case screens.FrankenPHPClassicScreen:
-			returnScreen = screens.FrankenPHPClassicScreen
-
-		// Site commands and related
-		case screens.SiteCommandsScreen:
-			returnScreen = screens.SiteCommandsScreen
-		case screens.GitManagementScreen:
-			returnScreen = screens.GitManagementScreen
-		case screens.LaravelPermissionsScreen:
-			returnScreen = screens.LaravelPermissionsScreen
-		case screens.NodeVersionScreen:
-			returnScreen = screens.SiteCommandsScreen
-		case screens.LaravelQueueScreen:
-			returnScreen = screens.LaravelQueueScreen
-		case screens.PHPVersionScreen:
-			returnScreen = screens.SiteCommandsScreen
-
-		// Config menu screens
-		case screens.ConfigMenuScreen:
-			returnScreen = screens.ConfigMenuScreen
-		case screens.NginxConfigScreen:
-			returnScreen = screens.NginxConfigScreen
-		case screens.RedisConfigScreen:
-			returnScreen = screens.RedisConfigScreen
-		case screens.MySQLManagementScreen:
-			returnScreen = screens.MySQLManagementScreen
-		case screens.PostgreSQLManagementScreen:
-			returnScreen = screens.PostgreSQLManagementScreen
-		case screens.PHPFPMManagementScreen:
-			returnScreen = screens.PHPFPMManagementScreen
-		case screens.SupervisorManagementScreen:
-			returnScreen = screens.SupervisorManagementScreen
-		case screens.FirewallManagementScreen:
-			returnScreen = screens.FirewallManagementScreen
-
-		// PHP screens
-		case screens.PHPInstallScreen:
-			returnScreen = screens.PHPInstallScreen
-		case screens.PHPExtensionsScreen:
-			returnScreen = screens.PHPExtensionsScreen
-
-		// SSL screens
-		case screens.SSLOptionsScreen:
-			returnScreen = screens.SSLOptionsScreen
-
-		// Dragonfly
-		case screens.DragonflyInstallScreen:
-			returnScreen = screens.DragonflyInstallScreen
+		// Return to the screen that started the task once it finishes
+		returnScreen := m.currentScreen
+		if target, ok := executionReturnScreens[returnScreen]; ok {
+			returnScreen = target
 		}
+		return m.startExecution(executionRequest{
+			command:      msg.Command,
+			description:  msg.Description,
+			returnScreen: returnScreen,
+			unprivileged: msg.Unprivileged,
+			scripts:      msg.Scripts,
+			removes:      msg.Removes,
+		})
 
-		// Switch to execution screen and start execution
-		m.currentScreen = screens.ExecutionScreen
-		m.execution = screens.NewExecutionModel(msg.Command, msg.Description, returnScreen)
-		initCmd := m.execution.Init()
-
-		// Send window size
-		if m.width > 0 && m.height > 0 {
-			sizeMsg := tea.WindowSizeMsg{Width: m.width, Height: m.height}
-			return m, tea.Batch(initCmd, func() tea.Msg { return sizeMsg })
-		}
-		return m, initCmd
+	case screens.AttachTaskMsg:
+		return m.attachTask(msg.Task)
 
 	case screens.ExecuteToolkitCommandMsg:
 		// Execute a command from the Developer Toolkit
-		m.currentScreen = screens.ExecutionScreen
-		m.execution = screens.NewExecutionModel(msg.Command, msg.Description, screens.DeveloperToolkitScreen)
-		initCmd := m.execution.Init()
-
-		// Send window size
-		if m.width > 0 && m.height > 0 {
-			sizeMsg := tea.WindowSizeMsg{Width: m.width, Height: m.height}
-			return m, tea.Batch(initCmd, func() tea.Msg { return sizeMsg })
-		}
-		return m, initCmd
+		return m.startExecution(executionRequest{
+			command:      msg.Command,
+			description:  msg.Description,
+			returnScreen: screens.DeveloperToolkitScreen,
+		})
 
 	case screens.EditorCompleteMsg:
 		// Delegate to current screen FIRST so it gets the message (crucial for picking up temp files)
@@ -820,12 +1699,41 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 	}
 
+	// Esc and Backspace may leave the screen
+	if key, ok := msg.(tea.KeyMsg); ok && (key.String() == "esc" || key.String() == "backspace") {
+		m, cmd = m.updateCurrentScreen(msg)
+		return m, m.backFrom(cmd)
+	}
+
 	// Delegate to current screen
 	return m.updateCurrentScreen(msg)
 }
 
-// View renders the current screen
+// View renders the current screen, in plain ASCII when the terminal cannot
+// draw anything else
 func (m Model) View() string {
+	view := m.render()
+	if m.theme.Caps.ASCII() {
+		return theme.ToASCII(view)
+	}
+	return view
+}
+
+// render draws the current screen or the overlay above it
+func (m Model) render() string {
+	if m.locked {
+		return m.lock.View(m.width, m.height)
+	}
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+	if m.elevating {
+		return m.elevation.View(m.width, m.height)
+	}
+	if m.paletteOpen {
+		return m.palette.View(m.width, m.height)
+	}
+
 	var view string
 	switch m.currentScreen {
 	case screens.SplashScreen:
@@ -892,6 +1800,14 @@ func (m Model) View() string {
 		view = m.supervisorAddProgram.View()
 	case screens.FirewallManagementScreen:
 		view = m.firewallManagement.View()
+	case screens.SSHDHardeningScreen:
+		view = m.sshdHardening.View()
+	case screens.AutoUpdatesScreen:
+		view = m.autoUpdates.View()
+	case screens.LaravelLintScreen:
+		view = m.laravelLint.View()
+	case screens.UpdatesScreen:
+		view = m.updates.View()
 	case screens.DragonflyInstallScreen:
 		view = m.dragonflyInstall.View()
 	case screens.SiteCommandsScreen:
@@ -910,6 +1826,104 @@ func (m Model) View() string {
 		view = m.phpExtensions.View()
 	case screens.LaravelQueueScreen:
 		view = m.laravelQueue.View()
+	case screens.EnvEditorScreen:
+		view = m.envEditor.View()
+	case screens.SecretsVaultScreen:
+		view = m.secretsVault.View()
+	case screens.ServersScreen:
+		view = m.servers.View()
+	case screens.SiteNotesScreen:
+		view = m.siteNotes.View()
+	case screens.SiteStackScreen:
+		view = m.siteStack.View()
+	case screens.SiteHardeningScreen:
+		view = m.siteHardening.View()
+	case screens.DBAccessScreen:
+		view = m.dbAccess.View()
+	case screens.SiteProtocolsScreen:
+		view = m.siteProtocols.View()
+	case screens.SiteAccessScreen:
+		view = m.siteAccess.View()
+	case screens.SiteHeadersScreen:
+		view = m.siteHeaders.View()
+	case screens.SiteRateLimitsScreen:
+		view = m.siteRateLimits.View()
+	case screens.SiteAnalyticsScreen:
+		view = m.siteAnalytics.View()
+	case screens.DiskUsageScreen:
+		view = m.diskUsage.View()
+	case screens.LogrotateScreen:
+		view = m.logrotate.View()
+	case screens.SwapScreen:
+		view = m.swap.View()
+	case screens.SetupBatchScreen:
+		view = m.setupBatch.View()
+	case screens.TasksScreen:
+		view = m.tasks.View()
+	case screens.XdebugScreen:
+		view = m.xdebug.View()
+	case screens.ProfilerScreen:
+		view = m.profiler.View()
+	case screens.ExportersScreen:
+		view = m.exporters.View()
+	case screens.NetdataScreen:
+		view = m.netdata.View()
+	case screens.DashboardScreen:
+		view = m.dashboard.View()
+	case screens.SettingsTransferScreen:
+		view = m.settingsTransfer.View()
+	case screens.SettingsScreen:
+		view = m.settings.View()
+	case screens.NamingPolicyScreen:
+		view = m.namingPolicy.View()
+	case screens.PHPIniScreen:
+		view = m.phpIni.View()
+	case screens.MarketplaceScreen:
+		view = m.marketplace.View()
+	case screens.NodeManagementScreen:
+		view = m.nodeManagement.View()
+	case screens.NodeAppsScreen:
+		view = m.nodeApps.View()
+	case screens.WordPressSiteScreen:
+		view = m.wordPressSite.View()
+	case screens.ComposerAuditScreen:
+		view = m.composerAudit.View()
+	case screens.ArtisanScreen:
+		view = m.artisan.View()
+	case screens.SiteBackupScreen:
+		view = m.siteBackup.View()
+	case screens.BackupTargetsScreen:
+		view = m.backupTargets.View()
+	case screens.ConfigDriftScreen:
+		view = m.configDrift.View()
+	case screens.SudoManagementScreen:
+		view = m.sudoManagement.View()
+	case screens.UserImportScreen:
+		view = m.userImport.View()
+	case screens.SFTPUserScreen:
+		view = m.sftpUser.View()
+	case screens.GitHistoryScreen:
+		view = m.gitHistory.View()
+	case screens.SupervisorProgramLogsScreen:
+		view = m.supervisorProgramLogs.View()
+	case screens.SupervisorGroupsScreen:
+		view = m.supervisorGroups.View()
+	case screens.PgBouncerScreen:
+		view = m.pgBouncer.View()
+	case screens.MeilisearchScreen:
+		view = m.meilisearch.View()
+	case screens.MailScreen:
+		view = m.mail.View()
+	case screens.DockerScreen:
+		view = m.docker.View()
+	case screens.OffboardUserScreen:
+		view = m.offboardUser.View()
+	case screens.LogViewerScreen:
+		view = m.logViewer.View()
+	case screens.SystemdServicesScreen:
+		view = m.systemdServices.View()
+	case screens.ConfigHistoryScreen:
+		view = m.configHistory.View()
 	case screens.FrankenPHPClassicScreen:
 		view = m.frankenphpClassic.View()
 
@@ -930,6 +1944,9 @@ func (m Model) View() string {
 	default:
 		view = "Unknown screen"
 	}
+	if m.showsBreadcrumb() {
+		view = screens.RenderBreadcrumb(m.theme, m.history, m.currentScreen, m.width) + "\n" + view
+	}
 	return m.wrapWithCopyModeIndicator(view)
 }
 
@@ -944,6 +1961,23 @@ func (m Model) wrapWithCopyModeIndicator(view string) string {
 }
 
 func main() {
+	// Plain ASCII output for serial consoles and minimal SSH clients, and
+	// diagnostics in ~/.ravact/debug.log
+	debug := false
+	args := os.Args[:1]
+	for _, arg := range os.Args[1:] {
+		switch arg {
+		case "--ascii":
+			asciiFlag = true
+			continue
+		case "--debug":
+			debug = true
+			continue
+		}
+		args = append(args, arg)
+	}
+	os.Args = args
+
 	// Check for version flag
 	if len(os.Args) > 1 && (os.Args[1] == "-v" || os.Args[1] == "--version") {
 		fmt.Printf("Ravact version %s\n", Version)
@@ -953,6 +1987,73 @@ func main() {
 	// Set embedded FS for screens to use
 	screens.EmbeddedFS = embeddedAssets
 
+	// Application settings live in ~/.config/ravact/config.yaml
+	config.Path = config.DefaultPath()
+
+	// Keep unfinished form input, the secrets vault, and other user settings under ~/.ravact
+	if home, err := os.UserHomeDir(); err == nil {
+		settings.UserDir = filepath.Join(home, ".ravact")
+		screens.FormStateDir = filepath.Join(settings.UserDir, "forms")
+		vault.DefaultPath = filepath.Join(settings.UserDir, "vault.json")
+		notify.ConfigPath = filepath.Join(settings.UserDir, "notify.yaml")
+	}
+
+	if debug {
+		if settings.UserDir == "" {
+			fmt.Println("Error: --debug needs a home directory for debug.log")
+			os.Exit(1)
+		}
+		logFile, err := debuglog.Enable(filepath.Join(settings.UserDir, "debug.log"))
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer logFile.Close()
+		debuglog.Debug("ravact started", "version", Version, "args", os.Args[1:])
+	}
+
+	// Manage a server from ~/.ravact/servers.yaml with --server <name>
+	for i, arg := range os.Args[1:] {
+		if arg != "--server" {
+			continue
+		}
+		if i+2 >= len(os.Args) {
+			fmt.Println("Error: --server requires a server name")
+			os.Exit(1)
+		}
+		name := os.Args[i+2]
+		inv, err := system.LoadServerInventory(system.DefaultServerInventoryPath())
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		server, ok := inv.Find(name)
+		if !ok {
+			fmt.Printf("Error: server %q not found in %s\n", name, system.DefaultServerInventoryPath())
+			os.Exit(1)
+		}
+		system.UseTransport(system.NewSSHTransport(server))
+	}
+	// Read user stub overrides from the managed host
+	stubs.ReadFile, stubs.ReadDir = system.ReadFile, system.ReadDir
+
+	// Non-interactive subcommands
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		os.Exit(runReport(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "monitor" {
+		os.Exit(runMonitor(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		os.Exit(runExport(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "webhook" {
+		os.Exit(runWebhook(os.Args[2:]))
+	}
+
 	// Create and run the program
 	p := tea.NewProgram(
 		NewModel(),
@@ -961,7 +2062,9 @@ func main() {
 	)
 
 	if _, err := p.Run(); err != nil {
+		debuglog.Debug("ravact exited", "error", err)
 		fmt.Printf("Error running application: %v\n", err)
 		os.Exit(1)
 	}
+	debuglog.Debug("ravact exited")
 }
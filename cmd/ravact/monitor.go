@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/report"
+	"github.com/iperamuna/ravact/internal/system"
+)
+
+// monitorServiceUnit is the systemd unit that keeps the monitor running
+const monitorServiceUnit = "ravact-monitor"
+
+// runMonitor handles `ravact monitor [flags]`: it checks the configured site
+// health checks and service states every interval, writes the results to
+// the status file the dashboard reads, and alerts when something changes
+func runMonitor(args []string) int {
+	fs := flag.NewFlagSet("monitor", flag.ContinueOnError)
+	interval := fs.Duration("interval", time.Minute, "time between checks")
+	once := fs.Bool("once", false, "run one round and exit (non-zero if anything is down)")
+	email := fs.String("email", "", "email alerts to ADDR through the local sendmail")
+	webhook := fs.String("webhook", "", "post alerts to a Slack/Mattermost/Discord-compatible webhook URL")
+	installService := fs.Bool("install-service", false, "install a systemd service that runs the monitor with these flags")
+	fs.String("server", "", "monitor a server from ~/.ravact/servers.yaml")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *interval < 10*time.Second {
+		fmt.Println("Error: --interval must be at least 10s")
+		return 2
+	}
+
+	if *installService {
+		if err := installMonitorService(args); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Installed and started %s.service (every %s)\n", monitorServiceUnit, *interval)
+		return 0
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start from the last saved round so a restart does not re-send alerts
+	prev, err := system.LoadMonitorStatus()
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	for {
+		status, err := system.RunMonitorRound(*interval)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			if *once {
+				return 1
+			}
+		} else {
+			if err := system.SaveMonitorStatus(status); err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+			sites, services := status.Failing()
+			fmt.Printf("%s checked %d site(s) and %d service(s): %d down\n",
+				status.UpdatedAt.Format(time.RFC3339), len(status.Sites), len(status.Services), len(sites)+len(services))
+			if alerts := system.MonitorAlerts(prev, status); len(alerts) > 0 {
+				for _, alert := range alerts {
+					fmt.Println("  " + alert)
+				}
+				sendMonitorAlerts(alerts, *email, *webhook)
+			}
+			prev = status
+			if *once {
+				if len(sites)+len(services) > 0 {
+					return 1
+				}
+				return 0
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-time.After(*interval):
+		}
+	}
+}
+
+// sendMonitorAlerts delivers alerts to the configured channels; delivery
+// failures are logged rather than stopping the monitor
+func sendMonitorAlerts(alerts []string, email, webhook string) {
+	host, _ := os.Hostname()
+	if t := system.CurrentTransport(); t.IsRemote() {
+		host = t.Name()
+	}
+	subject := fmt.Sprintf("[ravact] %s: %s", host, alerts[0])
+	if len(alerts) > 1 {
+		subject = fmt.Sprintf("[ravact] %s: %d monitor alerts", host, len(alerts))
+	}
+	body := "ravact monitor on " + host + "\n\n- " + strings.Join(alerts, "\n- ") + "\n"
+
+	if email != "" {
+		if err := report.Email(email, subject, body, false); err != nil {
+			fmt.Printf("Error: alert email: %v\n", err)
+		}
+	}
+	if webhook != "" {
+		if err := report.Post(webhook, body); err != nil {
+			fmt.Printf("Error: alert webhook: %v\n", err)
+		}
+	}
+}
+
+// installMonitorService writes and starts a systemd service on the local
+// host that runs the monitor with the same flags
+func installMonitorService(args []string) error {
+	if system.CurrentTransport().IsRemote() {
+		return fmt.Errorf("--install-service installs on the local host; run it on the server instead of with --server")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the ravact binary: %w", err)
+	}
+
+	command := []string{systemdQuote(exe), "monitor"}
+	for _, arg := range args {
+		if arg == "--install-service" || arg == "-install-service" || arg == "--once" || arg == "-once" {
+			continue
+		}
+		command = append(command, systemdQuote(arg))
+	}
+
+	service := fmt.Sprintf(`[Unit]
+Description=Ravact uptime monitor
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=simple
+ExecStart=%s
+Restart=on-failure
+RestartSec=30
+
+[Install]
+WantedBy=multi-user.target
+`, strings.Join(command, " "))
+
+	path := system.SystemdLocalUnitDir + "/" + monitorServiceUnit + ".service"
+	if err := os.WriteFile(path, []byte(service), 0644); err != nil {
+		return fmt.Errorf("failed to write service (are you root?): %w", err)
+	}
+	if output, err := exec.Command("systemctl", "daemon-reload").CombinedOutput(); err != nil {
+		return fmt.Errorf("daemon-reload failed: %s", strings.TrimSpace(string(output)))
+	}
+	if output, err := exec.Command("systemctl", "enable", "--now", monitorServiceUnit+".service").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to enable service: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/iperamuna/ravact/internal/report"
+	"github.com/iperamuna/ravact/internal/system"
+)
+
+// reportTimerUnit is the systemd unit name that schedules the weekly report
+const reportTimerUnit = "ravact-report"
+
+// runReport handles `ravact report weekly [flags]` and `ravact report handbook [flags]`
+func runReport(args []string) int {
+	if len(args) > 0 && args[0] == "handbook" {
+		return runHandbook(args[1:])
+	}
+	if len(args) == 0 || args[0] != "weekly" {
+		fmt.Println("Usage: ravact report weekly [--html] [--output FILE] [--email ADDR] [--webhook URL] [--install-timer]")
+		fmt.Println("       ravact report handbook [--output FILE]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("report weekly", flag.ContinueOnError)
+	asHTML := fs.Bool("html", false, "render HTML instead of Markdown")
+	output := fs.String("output", "", "write the report to FILE instead of stdout")
+	email := fs.String("email", "", "email the report to ADDR through the local sendmail")
+	webhook := fs.String("webhook", "", "post the report to a Slack/Mattermost/Discord-compatible webhook URL")
+	installTimer := fs.Bool("install-timer", false, "install a systemd timer that sends this report every Monday")
+	fs.String("server", "", "generate the report for a server from ~/.ravact/servers.yaml")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	if *installTimer {
+		if err := installReportTimer(args[1:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Installed %s.timer (weekly, Monday 07:00)\n", reportTimerUnit)
+		return 0
+	}
+
+	digest, err := report.Weekly()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	body := digest.Markdown()
+	if *asHTML {
+		if body, err = digest.HTML(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+	}
+
+	status := 0
+	switch {
+	case *output != "":
+		if err := os.WriteFile(*output, []byte(body), 0644); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			status = 1
+		}
+	case *email == "" && *webhook == "":
+		fmt.Print(body)
+	}
+	if *email != "" {
+		if err := report.Email(*email, digest.Subject(), body, *asHTML); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			status = 1
+		}
+	}
+	if *webhook != "" {
+		if err := report.Post(*webhook, digest.Markdown()); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			status = 1
+		}
+	}
+
+	// Only advance the disk baseline once the report went out
+	if status == 0 {
+		if err := digest.SaveState(); err != nil {
+			fmt.Printf("Warning: could not save report state: %v\n", err)
+		}
+	}
+	return status
+}
+
+// runHandbook generates the server handbook. By default it is written to
+// the managed host and recorded in the configuration history.
+func runHandbook(args []string) int {
+	fs := flag.NewFlagSet("report handbook", flag.ContinueOnError)
+	output := fs.String("output", "", "write the handbook to FILE (or - for stdout) instead of "+report.HandbookPath)
+	fs.String("server", "", "generate the handbook for a server from ~/.ravact/servers.yaml")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	h, err := report.CollectHandbook()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	switch *output {
+	case "":
+		changed, err := h.Save()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+		if changed {
+			fmt.Printf("Wrote %s and recorded it in the configuration history\n", report.HandbookPath)
+		} else {
+			fmt.Printf("Wrote %s (no changes since the last version)\n", report.HandbookPath)
+		}
+	case "-":
+		fmt.Print(h.Markdown())
+	default:
+		if err := os.WriteFile(*output, []byte(h.Markdown()), 0644); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// installReportTimer writes a systemd service and timer on the local host
+// that run the report with the same delivery flags
+func installReportTimer(args []string) error {
+	if system.CurrentTransport().IsRemote() {
+		return fmt.Errorf("--install-timer installs on the local host; run it on the server instead of with --server")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the ravact binary: %w", err)
+	}
+
+	command := []string{systemdQuote(exe), "report", "weekly"}
+	for _, arg := range args {
+		if arg == "--install-timer" || arg == "-install-timer" {
+			continue
+		}
+		command = append(command, systemdQuote(arg))
+	}
+
+	service := fmt.Sprintf(`[Unit]
+Description=Ravact weekly report
+After=network-online.target
+
+[Service]
+Type=oneshot
+ExecStart=%s
+`, strings.Join(command, " "))
+
+	timer := `[Unit]
+Description=Send the ravact weekly report
+
+[Timer]
+OnCalendar=Mon *-*-* 07:00:00
+Persistent=true
+RandomizedDelaySec=15m
+
+[Install]
+WantedBy=timers.target
+`
+
+	dir := system.SystemdLocalUnitDir
+	if err := os.WriteFile(dir+"/"+reportTimerUnit+".service", []byte(service), 0644); err != nil {
+		return fmt.Errorf("failed to write service (are you root?): %w", err)
+	}
+	if err := os.WriteFile(dir+"/"+reportTimerUnit+".timer", []byte(timer), 0644); err != nil {
+		return fmt.Errorf("failed to write timer: %w", err)
+	}
+	if output, err := exec.Command("systemctl", "daemon-reload").CombinedOutput(); err != nil {
+		return fmt.Errorf("daemon-reload failed: %s", strings.TrimSpace(string(output)))
+	}
+	if output, err := exec.Command("systemctl", "enable", "--now", reportTimerUnit+".timer").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to enable timer: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// systemdQuote quotes an ExecStart argument, escaping systemd specifiers
+// and environment variable expansion
+func systemdQuote(arg string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%", "$", "$$")
+	return `"` + r.Replace(arg) + `"`
+}
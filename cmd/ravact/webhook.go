@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/system"
+)
+
+// webhookServiceUnit is the systemd unit that keeps the webhook listener running
+const webhookServiceUnit = "ravact-webhook"
+
+// webhookMaxBody caps the size of a webhook request
+const webhookMaxBody = 5 << 20
+
+// runWebhook handles `ravact webhook serve|add|log`
+func runWebhook(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "serve":
+			return runWebhookServe(args[1:])
+		case "add":
+			return runWebhookAdd(args[1:])
+		case "log":
+			return runWebhookLog(args[1:])
+		}
+	}
+	fmt.Println("Usage: ravact webhook serve [--listen ADDR] [--install-service]")
+	fmt.Println("       ravact webhook add SITE --branch BRANCH --dir DIR [--repo OWNER/NAME] [--user USER]")
+	fmt.Println("       ravact webhook log [--site SITE] [--limit N]")
+	return 2
+}
+
+// runWebhookServe listens for GitHub/GitLab push webhooks at /hooks/SITE
+// and deploys the site when the push matches its webhook config
+func runWebhookServe(args []string) int {
+	fs := flag.NewFlagSet("webhook serve", flag.ContinueOnError)
+	listen := fs.String("listen", "127.0.0.1:9000", "address to listen on; put nginx in front for HTTPS")
+	installService := fs.Bool("install-service", false, "install a systemd service that runs the listener with these flags")
+	fs.String("server", "", "deploy on a server from ~/.ravact/servers.yaml")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *installService {
+		if err := installWebhookService(args); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Installed and started %s.service (listening on %s)\n", webhookServiceUnit, *listen)
+		return 0
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           newWebhookHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+
+	fmt.Printf("Listening for webhooks on http://%s/hooks/SITE\n", *listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// webhookHandler accepts push webhooks and runs one deploy per site at a
+// time; a push arriving mid-deploy queues a single follow-up run
+type webhookHandler struct {
+	mu      sync.Mutex
+	running map[string]bool
+	pending map[string]*system.WebhookPush
+}
+
+func newWebhookHandler() *webhookHandler {
+	return &webhookHandler{running: map[string]bool{}, pending: map[string]*system.WebhookPush{}}
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	site, ok := strings.CutPrefix(r.URL.Path, "/hooks/")
+	if !ok || r.Method != http.MethodPost {
+		http.NotFound(w, r)
+		return
+	}
+	config, err := system.LoadSiteWebhook(site)
+	if err != nil || config == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if err := config.Validate(); err != nil {
+		fmt.Printf("%s %s: webhook config is invalid: %v\n", time.Now().Format(time.RFC3339), site, err)
+		http.Error(w, "webhook is not configured", http.StatusServiceUnavailable)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, webhookMaxBody))
+	if err != nil {
+		http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	push, err := system.ParseWebhookPush(r.Header, body, config.Secret)
+	switch {
+	case errors.Is(err, system.ErrWebhookSignature):
+		fmt.Printf("%s %s: rejected request from %s: %v\n", time.Now().Format(time.RFC3339), site, r.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case errors.Is(err, system.ErrWebhookIgnored):
+		fmt.Fprintln(w, "ignored: not a push event")
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !config.Matches(*push) {
+		fmt.Fprintf(w, "ignored: %s %s does not match %s\n", push.Repository, push.Ref, config.Branch)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintln(w, "deploy queued")
+	h.deploy(site, *config, *push)
+}
+
+// deploy starts a deploy unless one is running for the site, in which case
+// the latest push runs when it finishes
+func (h *webhookHandler) deploy(site string, config system.SiteWebhook, push system.WebhookPush) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.running[site] {
+		h.pending[site] = &push
+		return
+	}
+	h.running[site] = true
+	go func() {
+		for {
+			rec := system.RunWebhookDeploy(site, config, push)
+			status := "succeeded"
+			if !rec.Success {
+				status = "failed: " + rec.Error
+			}
+			fmt.Printf("%s %s %s in %s\n", rec.StartedAt.Format(time.RFC3339), rec.Description, status, rec.Duration.Round(time.Second))
+
+			h.mu.Lock()
+			next := h.pending[site]
+			delete(h.pending, site)
+			if next == nil {
+				h.running[site] = false
+				h.mu.Unlock()
+				return
+			}
+			h.mu.Unlock()
+			push = *next
+		}
+	}()
+}
+
+// runWebhookAdd configures a site's webhook with a new secret and prints
+// what to enter on GitHub or GitLab
+func runWebhookAdd(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Println("Usage: ravact webhook add SITE --branch BRANCH --dir DIR [--repo OWNER/NAME] [--user USER]")
+		return 2
+	}
+	site := args[0]
+	fs := flag.NewFlagSet("webhook add", flag.ContinueOnError)
+	branch := fs.String("branch", "", "branch whose pushes deploy the site")
+	dir := fs.String("dir", "", "git checkout to deploy")
+	repo := fs.String("repo", "", "only accept pushes from this repository (owner/name)")
+	user := fs.String("user", "", "user that runs the deploy (default: the checkout's meta.systemuser)")
+	fs.String("server", "", "configure a server from ~/.ravact/servers.yaml")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	secret, err := system.GenerateWebhookSecret()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	config := system.SiteWebhook{Secret: secret, Repository: *repo, Branch: *branch, Directory: *dir, User: *user}
+	if existing, err := system.LoadSiteWebhook(site); err == nil && existing != nil {
+		fmt.Println("Replacing the existing webhook; update the secret on GitHub/GitLab too.")
+	}
+	if err := system.SaveSiteWebhook(site, config); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	path, _ := system.SiteWebhookPath(site)
+	fmt.Printf("Saved %s\n\n", path)
+	fmt.Printf("Payload URL:  https://<host>/hooks/%s (proxied to `ravact webhook serve`)\n", site)
+	fmt.Println("Content type: application/json")
+	fmt.Printf("Secret:       %s\n", secret)
+	fmt.Println("Events:       push (GitHub) / Push events (GitLab)")
+	return 0
+}
+
+// runWebhookLog prints the webhook deploys from the execution history
+func runWebhookLog(args []string) int {
+	fs := flag.NewFlagSet("webhook log", flag.ContinueOnError)
+	site := fs.String("site", "", "only show deploys of SITE")
+	limit := fs.Int("limit", 20, "number of deploys to show")
+	verbose := fs.Bool("v", false, "include the end of each deploy's output")
+	fs.String("server", "", "read the history of a server from ~/.ravact/servers.yaml")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	records, err := system.LoadExecutions()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	shown := 0
+	for _, rec := range records {
+		if rec.Source != "webhook" || (*site != "" && rec.Site != *site) {
+			continue
+		}
+		if shown == *limit {
+			break
+		}
+		shown++
+		status := "ok"
+		if !rec.Success {
+			status = "FAILED: " + rec.Error
+		}
+		fmt.Printf("%s  %s  %s (%s)\n", rec.StartedAt.Format("2006-01-02 15:04:05"), rec.Description, status, rec.Duration.Round(time.Second))
+		if *verbose && rec.Output != "" {
+			fmt.Println("    " + strings.ReplaceAll(strings.TrimRight(rec.Output, "\n"), "\n", "\n    "))
+		}
+	}
+	if shown == 0 {
+		fmt.Println("No webhook deploys recorded yet.")
+	}
+	return 0
+}
+
+// installWebhookService writes and starts a systemd service on the local
+// host that runs the listener with the same flags
+func installWebhookService(args []string) error {
+	if system.CurrentTransport().IsRemote() {
+		return fmt.Errorf("--install-service installs on the local host; run it on the server instead of with --server")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the ravact binary: %w", err)
+	}
+
+	command := []string{systemdQuote(exe), "webhook", "serve"}
+	for _, arg := range args {
+		if arg == "--install-service" || arg == "-install-service" {
+			continue
+		}
+		command = append(command, systemdQuote(arg))
+	}
+
+	service := fmt.Sprintf(`[Unit]
+Description=Ravact git webhook listener
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=simple
+ExecStart=%s
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=multi-user.target
+`, strings.Join(command, " "))
+
+	path := system.SystemdLocalUnitDir + "/" + webhookServiceUnit + ".service"
+	if err := os.WriteFile(path, []byte(service), 0644); err != nil {
+		return fmt.Errorf("failed to write service (are you root?): %w", err)
+	}
+	if output, err := exec.Command("systemctl", "daemon-reload").CombinedOutput(); err != nil {
+		return fmt.Errorf("daemon-reload failed: %s", strings.TrimSpace(string(output)))
+	}
+	if output, err := exec.Command("systemctl", "enable", "--now", webhookServiceUnit+".service").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to enable service: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// QuickCommand is a command the user added to the Quick Commands screen
+type QuickCommand struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	// Script is run with bash and may span several lines
+	Script string `yaml:"script"`
+	// RunAs is the user the script runs as; empty runs it as root
+	RunAs string `yaml:"run_as,omitempty"`
+	// WorkDir is the directory the script starts in
+	WorkDir string `yaml:"working_dir,omitempty"`
+}
+
+// quickCommandsFile is the layout of commands.yaml
+type quickCommandsFile struct {
+	Commands []QuickCommand `yaml:"commands"`
+}
+
+// CommandsPath returns commands.yaml next to the settings file
+func CommandsPath() string {
+	if Path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(Path), "commands.yaml")
+}
+
+// Validate checks a command before it is saved
+func (c QuickCommand) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(c.Script) == "" {
+		return fmt.Errorf("script is required")
+	}
+	// User names follow the same rules as group names
+	if c.RunAs != "" && !groupNamePattern.MatchString(c.RunAs) {
+		return fmt.Errorf("%q is not a valid user name", c.RunAs)
+	}
+	if c.WorkDir != "" && !filepath.IsAbs(c.WorkDir) {
+		return fmt.Errorf("working directory must be an absolute path")
+	}
+	return nil
+}
+
+// LoadQuickCommands reads the user's quick commands
+func LoadQuickCommands() ([]QuickCommand, error) {
+	path := CommandsPath()
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quick commands: %w", err)
+	}
+	var file quickCommandsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return file.Commands, nil
+}
+
+// SaveQuickCommands writes the user's quick commands
+func SaveQuickCommands(commands []QuickCommand) error {
+	path := CommandsPath()
+	if path == "" {
+		return fmt.Errorf("no settings directory")
+	}
+	for _, c := range commands {
+		if err := c.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", c.Name, err)
+		}
+	}
+	data, err := yaml.Marshal(quickCommandsFile{Commands: commands})
+	if err != nil {
+		return fmt.Errorf("failed to encode quick commands: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write quick commands: %w", err)
+	}
+	return nil
+}
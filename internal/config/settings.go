@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Path is the application settings file, normally
+// ~/.config/ravact/config.yaml; set by the main package
+var Path string
+
+// Themes the settings can select
+const (
+	ThemeAuto  = "auto"  // Detect colours and glyphs from the terminal
+	ThemeASCII = "ascii" // Plain ASCII, as with --ascii
+)
+
+// DefaultWebGroup is the group web servers and PHP-FPM run as on Debian
+// and Ubuntu
+const DefaultWebGroup = "www-data"
+
+var groupNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_-]*$`)
+
+// Settings are application preferences changed from the Settings screen
+type Settings struct {
+	// Editor opens files directly instead of asking for nano or vi
+	Editor string `yaml:"editor,omitempty"`
+	// Theme is ThemeAuto or ThemeASCII
+	Theme string `yaml:"theme,omitempty"`
+	// ShowHiddenFiles lists dotfiles in the file browser from the start
+	ShowHiddenFiles bool `yaml:"show_hidden_files"`
+	// WebGroup owns site files alongside the site user
+	WebGroup string `yaml:"web_group,omitempty"`
+	// ScriptsDir holds setup scripts that replace the bundled scripts of
+	// the same name
+	ScriptsDir string `yaml:"scripts_dir,omitempty"`
+	// ConfirmBeforeExecute asks before any command or script runs
+	ConfirmBeforeExecute bool `yaml:"confirm_before_execute"`
+}
+
+// DefaultPath returns ~/.config/ravact/config.yaml, or the platform's
+// equivalent
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "ravact", "config.yaml")
+}
+
+// Load reads the settings, returning defaults if none are saved
+func Load() (Settings, error) {
+	s := Settings{Theme: ThemeAuto, WebGroup: DefaultWebGroup}
+	if Path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(Path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to read settings: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("failed to parse %s: %w", Path, err)
+	}
+	if s.Theme == "" {
+		s.Theme = ThemeAuto
+	}
+	if s.WebGroup == "" {
+		s.WebGroup = DefaultWebGroup
+	}
+	return s, nil
+}
+
+// Validate checks the settings before they are saved
+func (s Settings) Validate() error {
+	if strings.ContainsAny(s.Editor, " \t'\"") {
+		return fmt.Errorf("editor must be a single command such as vim or micro")
+	}
+	switch s.Theme {
+	case "", ThemeAuto, ThemeASCII:
+	default:
+		return fmt.Errorf("unknown theme %q", s.Theme)
+	}
+	if s.WebGroup != "" && !groupNamePattern.MatchString(s.WebGroup) {
+		return fmt.Errorf("%q is not a valid group name", s.WebGroup)
+	}
+	if s.ScriptsDir != "" && !filepath.IsAbs(s.ScriptsDir) {
+		return fmt.Errorf("scripts directory must be an absolute path")
+	}
+	return nil
+}
+
+// Save writes the settings
+func (s Settings) Save() error {
+	if Path == "" {
+		return fmt.Errorf("no settings file")
+	}
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(Path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(Path), err)
+	}
+	if err := os.WriteFile(Path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write settings: %w", err)
+	}
+	return nil
+}
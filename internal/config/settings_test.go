@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func useSettingsPath(t *testing.T) {
+	t.Helper()
+	orig := Path
+	t.Cleanup(func() { Path = orig })
+	Path = filepath.Join(t.TempDir(), "ravact", "config.yaml")
+}
+
+func TestSettingsRoundTrip(t *testing.T) {
+	useSettingsPath(t)
+
+	s, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if s.Theme != ThemeAuto || s.WebGroup != DefaultWebGroup || s.ConfirmBeforeExecute {
+		t.Fatalf("unexpected defaults: %+v", s)
+	}
+
+	s.Editor = "vim"
+	s.Theme = ThemeASCII
+	s.ShowHiddenFiles = true
+	s.WebGroup = "nginx"
+	s.ScriptsDir = "/opt/ravact/scripts"
+	s.ConfirmBeforeExecute = true
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if info, err := os.Stat(Path); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("expected a 0600 settings file, got %v %v", info, err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded != s {
+		t.Errorf("loaded %+v, want %+v", loaded, s)
+	}
+}
+
+func TestSettingsValidate(t *testing.T) {
+	valid := Settings{Editor: "micro", Theme: ThemeAuto, WebGroup: "www-data", ScriptsDir: "/srv/scripts"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected valid settings, got %v", err)
+	}
+
+	invalid := map[string]Settings{
+		"editor with arguments": {Editor: "vim -u NONE"},
+		"unknown theme":         {Theme: "solarized"},
+		"bad group":             {WebGroup: "www data"},
+		"relative scripts dir":  {ScriptsDir: "scripts"},
+	}
+	for name, s := range invalid {
+		if err := s.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadWithoutPath(t *testing.T) {
+	orig := Path
+	defer func() { Path = orig }()
+	Path = ""
+
+	if s, err := Load(); err != nil || s.WebGroup != DefaultWebGroup {
+		t.Errorf("expected defaults without a settings file, got %+v %v", s, err)
+	}
+	if err := (Settings{}).Save(); err == nil {
+		t.Error("expected Save to fail without a settings file")
+	}
+}
+
+func TestQuickCommandsRoundTrip(t *testing.T) {
+	useSettingsPath(t)
+
+	if commands, err := LoadQuickCommands(); err != nil || len(commands) != 0 {
+		t.Fatalf("expected no commands before any are saved, got %v %v", commands, err)
+	}
+
+	commands := []QuickCommand{
+		{Name: "Clear cache", Script: "php artisan cache:clear", RunAs: "deploy", WorkDir: "/var/www/app"},
+		{Name: "Disk", Description: "Free space", Script: "df -h"},
+	}
+	if err := SaveQuickCommands(commands); err != nil {
+		t.Fatalf("SaveQuickCommands: %v", err)
+	}
+	if filepath.Dir(CommandsPath()) != filepath.Dir(Path) {
+		t.Errorf("expected commands.yaml next to config.yaml, got %s", CommandsPath())
+	}
+	loaded, err := LoadQuickCommands()
+	if err != nil {
+		t.Fatalf("LoadQuickCommands: %v", err)
+	}
+	if len(loaded) != 2 || loaded[0] != commands[0] || loaded[1] != commands[1] {
+		t.Errorf("loaded %+v, want %+v", loaded, commands)
+	}
+
+	if err := SaveQuickCommands([]QuickCommand{{Name: "Bad", Script: "ls", WorkDir: "relative"}}); err == nil {
+		t.Error("expected a relative working directory to be rejected")
+	}
+}
+
+func TestQuickCommandValidate(t *testing.T) {
+	invalid := map[string]QuickCommand{
+		"no name":      {Script: "ls"},
+		"no script":    {Name: "List"},
+		"bad user":     {Name: "List", Script: "ls", RunAs: "bad user"},
+		"relative dir": {Name: "List", Script: "ls", WorkDir: "www"},
+	}
+	for name, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Package debuglog writes structured diagnostics when ravact runs with
+// --debug. Logging is off by default, so call sites cost next to nothing
+// and nothing reaches the terminal the TUI is drawing on.
+package debuglog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// maxCommandLength keeps multi-line scripts passed with bash -c readable
+const maxCommandLength = 500
+
+var logger = slog.New(slog.DiscardHandler)
+
+// Enable writes debug logs to path as JSON lines, appending to any earlier
+// session. The returned closer flushes the file.
+func Enable(path string) (io.Closer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	logger = slog.New(slog.NewJSONHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return f, nil
+}
+
+// Enabled reports whether debug logging is on
+func Enabled() bool {
+	return logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// Debug logs a message with key/value attributes
+func Debug(msg string, args ...any) {
+	logger.Debug(msg, args...)
+}
+
+// Screen logs a move from one screen to another
+func Screen(from, to string) {
+	logger.Debug("screen", "from", from, "to", to)
+}
+
+// Command logs a command about to run on host, with passwords and tokens
+// masked
+func Command(host, name string, args []string) {
+	if !Enabled() {
+		return
+	}
+	line := Redact(strings.Join(append([]string{name}, args...), " "))
+	if len(line) > maxCommandLength {
+		line = line[:maxCommandLength] + "..."
+	}
+	logger.Debug("command", "host", host, "command", line)
+}
+
+// ParseFailure logs input that could not be read, such as a settings file
+// or a saved form value
+func ParseFailure(what string, err error, args ...any) {
+	logger.Warn("parse failure", append([]any{"what", what, "error", err}, args...)...)
+}
+
+var (
+	secretPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|identified by)(\s*[=:]?\s*)('[^']*'|"[^"]*"|[^\s'"]+)`)
+	// mysql and mysqldump take the password attached to -p
+	attachedPasswordPattern = regexp.MustCompile(`(\s-p)([^\s'"]+|'[^']*'|"[^"]*")`)
+)
+
+// Redact masks the values that follow password and token keywords
+func Redact(s string) string {
+	s = secretPattern.ReplaceAllString(s, "$1$2***")
+	return attachedPasswordPattern.ReplaceAllString(s, "$1***")
+}
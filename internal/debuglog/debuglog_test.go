@@ -0,0 +1,63 @@
+package debuglog
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	cases := map[string]string{
+		`mysql -u root -pS3cret -e "SELECT 1"`:          `mysql -u root -p*** -e "SELECT 1"`,
+		`ALTER USER 'app'@'%' IDENTIFIED BY 'hunter2';`: `ALTER USER 'app'@'%' IDENTIFIED BY ***;`,
+		`redis-cli CONFIG SET requirepass abc`:          `redis-cli CONFIG SET requirepass abc`,
+		`curl --token=abc123 https://example.com`:       `curl --token=*** https://example.com`,
+		`env DB_PASSWORD="x y" bash -s`:                 `env DB_PASSWORD=*** bash -s`,
+		`mkdir -p /var/www/site`:                        `mkdir -p /var/www/site`,
+	}
+	for in, want := range cases {
+		if got := Redact(in); got != want {
+			t.Errorf("Redact(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestEnable(t *testing.T) {
+	orig := logger
+	defer func() { logger = orig }()
+
+	if Enabled() {
+		t.Fatal("expected logging to be off by default")
+	}
+
+	path := filepath.Join(t.TempDir(), "ravact", "debug.log")
+	closer, err := Enable(path)
+	if err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	Screen("Main Menu", "Settings")
+	Command("local", "bash", []string{"-c", "echo password=abc"})
+	closer.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two log lines, got %q", data)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("expected JSON lines: %v", err)
+	}
+	if entry["msg"] != "command" || entry["level"] != slog.LevelDebug.String() || entry["command"] != "bash -c echo password=***" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected a 0600 log file, got %v %v", info, err)
+	}
+}
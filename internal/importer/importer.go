@@ -0,0 +1,492 @@
+// Package importer converts site definitions, deployment scripts, and
+// scheduled jobs exported from Laravel Forge or Ploi into ravact site
+// blueprints, deploy hooks, and cron entries
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/iperamuna/ravact/internal/system"
+	"gopkg.in/yaml.v3"
+)
+
+// Source is the control panel an export came from
+type Source string
+
+const (
+	SourceForge Source = "forge"
+	SourcePloi  Source = "ploi"
+)
+
+// CronDir is where imported scheduled jobs are installed
+var CronDir = "/etc/cron.d"
+
+// BlueprintFile and DeployHookFile are written to the site's data directory
+const (
+	BlueprintFile  = "blueprint.yaml"
+	DeployHookFile = system.SiteDeployHookFile
+)
+
+// SiteBlueprint describes a site well enough to recreate it with ravact
+type SiteBlueprint struct {
+	Source     Source   `yaml:"source"`
+	Domain     string   `yaml:"domain"`
+	Aliases    []string `yaml:"aliases,omitempty"`
+	Directory  string   `yaml:"directory"`         // Project directory
+	WebDir     string   `yaml:"web_dir,omitempty"` // Document root inside Directory
+	Template   string   `yaml:"template"`          // Nginx template ID
+	PHPVersion string   `yaml:"php_version,omitempty"`
+	Repository string   `yaml:"repository,omitempty"`
+	Branch     string   `yaml:"branch,omitempty"`
+	User       string   `yaml:"user,omitempty"`
+}
+
+// RootDir returns the document root
+func (b SiteBlueprint) RootDir() string {
+	return path.Join(b.Directory, b.WebDir)
+}
+
+// Site is one imported site
+type Site struct {
+	Blueprint  SiteBlueprint
+	DeployHook string // Converted deploy script; empty if none was exported
+}
+
+// ScheduleEntry is a scheduled job as a cron.d line
+type ScheduleEntry struct {
+	Cron    string
+	User    string
+	Command string
+}
+
+// Options adjust the conversion
+type Options struct {
+	Site string // Domain for a plain deploy script
+	User string // Replaces the panel's default user (forge, ploi)
+	Root string // Replaces /home/forge or /home/ploi in paths
+}
+
+// Result is everything converted from one export
+type Result struct {
+	Source      Source
+	Sites       []Site
+	Schedules   []ScheduleEntry
+	Unconverted []string // Items that need manual attention
+}
+
+// panelUsers are the default users each panel provisions
+var panelUsers = map[Source]string{SourceForge: "forge", SourcePloi: "ploi"}
+
+// Parse reads an export: a JSON document from the Forge or Ploi API (sites,
+// scheduled jobs, and optionally a deployment_script), or a plain deploy
+// script
+func Parse(data []byte, opts Options) (*Result, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" {
+		return nil, fmt.Errorf("the export is empty")
+	}
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return parseJSON([]byte(trimmed), opts)
+	}
+	return parseScript(trimmed, opts)
+}
+
+// exportSite covers the site fields of both APIs
+type exportSite struct {
+	// Forge
+	Name             string   `json:"name"`
+	Aliases          []string `json:"aliases"`
+	Directory        string   `json:"directory"`
+	RepositoryBranch string   `json:"repository_branch"`
+	Username         string   `json:"username"`
+	// Ploi
+	Domain       string `json:"domain"`
+	Root         string `json:"root"`
+	WebDirectory string `json:"web_directory"`
+	Branch       string `json:"branch"`
+	SystemUser   string `json:"system_user"`
+	// Both
+	ProjectType      string          `json:"project_type"`
+	PHPVersion       json.RawMessage `json:"php_version"`
+	Repository       string          `json:"repository"`
+	DeploymentScript string          `json:"deployment_script"`
+}
+
+// exportJob covers Forge scheduled jobs and Ploi crons
+type exportJob struct {
+	Command   string `json:"command"`
+	User      string `json:"user"`
+	Frequency string `json:"frequency"`
+	Cron      string `json:"cron"`
+}
+
+// export is the union of the documents the importer understands
+type export struct {
+	Site             *exportSite     `json:"site"`
+	Sites            []exportSite    `json:"sites"`
+	Data             json.RawMessage `json:"data"` // Ploi wraps everything in data
+	Jobs             []exportJob     `json:"jobs"`
+	Crons            []exportJob     `json:"crons"`
+	DeploymentScript string          `json:"deployment_script"`
+}
+
+// parseJSON converts a Forge or Ploi API document
+func parseJSON(data []byte, opts Options) (*Result, error) {
+	var doc export
+	if strings.HasPrefix(string(data), "[") {
+		doc.Data = data
+	} else if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse the export: %w", err)
+	}
+
+	sites := doc.Sites
+	if doc.Site != nil {
+		sites = append(sites, *doc.Site)
+	}
+	jobs := append(doc.Jobs, doc.Crons...)
+
+	// Ploi's data is a list (or one object) of sites or of crons
+	if len(doc.Data) > 0 {
+		var items []json.RawMessage
+		if err := json.Unmarshal(doc.Data, &items); err != nil {
+			items = []json.RawMessage{doc.Data}
+		}
+		for _, item := range items {
+			var probe map[string]json.RawMessage
+			if json.Unmarshal(item, &probe) != nil {
+				continue
+			}
+			if _, ok := probe["command"]; ok {
+				var job exportJob
+				json.Unmarshal(item, &job)
+				jobs = append(jobs, job)
+				continue
+			}
+			var site exportSite
+			if err := json.Unmarshal(item, &site); err == nil {
+				sites = append(sites, site)
+			}
+		}
+	}
+	if len(sites) == 0 && len(jobs) == 0 {
+		return nil, fmt.Errorf("no sites or scheduled jobs found in the export")
+	}
+
+	// Only Ploi wraps responses in data
+	r := &Result{Source: SourceForge}
+	if len(doc.Data) > 0 {
+		r.Source = SourcePloi
+	}
+	if len(sites) == 1 && sites[0].DeploymentScript == "" {
+		sites[0].DeploymentScript = doc.DeploymentScript
+	} else if doc.DeploymentScript != "" {
+		r.Unconverted = append(r.Unconverted, "deployment_script: the export has several sites; add it to a site's entry instead")
+	}
+
+	for _, s := range sites {
+		r.Sites = append(r.Sites, r.convertSite(s, opts))
+	}
+	for _, job := range jobs {
+		r.convertJob(job, opts)
+	}
+	return r, nil
+}
+
+// convertSite turns an API site into a blueprint and deploy hook
+func (r *Result) convertSite(s exportSite, opts Options) Site {
+	b := SiteBlueprint{Source: r.Source, Aliases: s.Aliases, Repository: s.Repository}
+	if r.Source == SourcePloi {
+		b.Domain, b.Directory, b.WebDir = s.Domain, s.Root, s.WebDirectory
+		b.Branch, b.User = s.Branch, s.SystemUser
+		if b.Directory == "" {
+			b.Directory = "/home/ploi/" + b.Domain
+		}
+	} else {
+		// Forge's directory is the web directory inside /home/forge/<name>
+		b.Domain, b.WebDir = s.Name, s.Directory
+		b.Directory = "/home/forge/" + s.Name
+		b.Branch, b.User = s.RepositoryBranch, s.Username
+	}
+	if b.User == "" {
+		b.User = panelUsers[r.Source]
+	}
+	b.PHPVersion = phpVersion(s.PHPVersion)
+	b.Template = r.template(b.Domain, s.ProjectType, s.DeploymentScript)
+	r.mapPaths(&b, opts)
+
+	site := Site{Blueprint: b}
+	if s.DeploymentScript != "" {
+		site.DeployHook = r.convertScript(b, s.DeploymentScript)
+	}
+	return site
+}
+
+// mapPaths applies --root and --user and reports what still points at the
+// panel's layout
+func (r *Result) mapPaths(b *SiteBlueprint, opts Options) {
+	panelHome := "/home/" + panelUsers[r.Source]
+	if opts.Root != "" && strings.HasPrefix(b.Directory, panelHome+"/") {
+		b.Directory = path.Join(opts.Root, strings.TrimPrefix(b.Directory, panelHome+"/"))
+	}
+	if b.User == panelUsers[r.Source] {
+		if opts.User != "" {
+			b.User = opts.User
+		} else {
+			r.Unconverted = append(r.Unconverted, fmt.Sprintf("%s: runs as %s; pass --user to map it to a user on this server", b.Domain, b.User))
+		}
+	}
+}
+
+// phpVersionPattern finds a version such as 8.3 or php83
+var phpVersionPattern = regexp.MustCompile(`(\d)\.?(\d)`)
+
+// phpVersion normalises "php83", "8.3", and 8.3 to "8.3"
+func phpVersion(raw json.RawMessage) string {
+	m := phpVersionPattern.FindStringSubmatch(string(raw))
+	if m == nil {
+		return ""
+	}
+	return m[1] + "." + m[2]
+}
+
+// projectTemplates maps panel project types to nginx template IDs
+var projectTemplates = map[string]string{
+	"laravel":   "laravel",
+	"statamic":  "laravel",
+	"php":       "php",
+	"craft-cms": "php",
+	"symfony":   "symfony",
+	"wordpress": "wordpress",
+	"html":      "static",
+	"static":    "static",
+	"nodejs":    "nodejs",
+	"spa":       "spa",
+}
+
+// template picks the nginx template. Forge reports Laravel sites as
+// "php", so a deploy script that runs artisan marks the site as Laravel.
+func (r *Result) template(domain, projectType, script string) string {
+	projectType = strings.ToLower(projectType)
+	if strings.HasPrefix(projectType, "symfony") {
+		projectType = "symfony"
+	}
+	if (projectType == "php" || projectType == "") && strings.Contains(script, "artisan") {
+		return "laravel"
+	}
+	if t, ok := projectTemplates[projectType]; ok {
+		return t
+	}
+	if projectType != "" {
+		r.Unconverted = append(r.Unconverted, fmt.Sprintf("%s: unknown project type %q; using the php template", domain, projectType))
+	}
+	return "php"
+}
+
+// forgeFrequencies maps Forge's named frequencies to cron schedules
+var forgeFrequencies = map[string]string{
+	"minutely": "* * * * *",
+	"hourly":   "0 * * * *",
+	"nightly":  "0 0 * * *",
+	"daily":    "0 0 * * *",
+	"weekly":   "0 0 * * 0",
+	"monthly":  "0 0 1 * *",
+	"reboot":   "@reboot",
+}
+
+// convertJob turns a scheduled job into a cron.d entry
+func (r *Result) convertJob(job exportJob, opts Options) {
+	schedule := job.Cron
+	if f, ok := forgeFrequencies[strings.ToLower(job.Frequency)]; ok && schedule == "" {
+		schedule = f
+	} else if len(strings.Fields(job.Frequency)) == 5 && schedule == "" {
+		schedule = job.Frequency
+	}
+	if schedule == "" || strings.TrimSpace(job.Command) == "" {
+		r.Unconverted = append(r.Unconverted, fmt.Sprintf("scheduled job %q: unrecognised frequency %q", job.Command, job.Frequency))
+		return
+	}
+
+	user := job.User
+	switch {
+	case (user == "" || user == panelUsers[r.Source]) && opts.User != "":
+		user = opts.User
+	case user == "":
+		user = "root"
+	case user == panelUsers[r.Source]:
+		r.Unconverted = append(r.Unconverted, fmt.Sprintf("scheduled job %q: runs as %s; pass --user to map it to a user on this server", job.Command, user))
+	}
+	command := job.Command
+	if opts.Root != "" {
+		command = strings.ReplaceAll(command, "/home/"+panelUsers[r.Source]+"/", strings.TrimSuffix(opts.Root, "/")+"/")
+	}
+	if strings.Contains(command, "%") {
+		// cron.d treats % as a newline
+		command = strings.ReplaceAll(command, "%", `\%`)
+	}
+	r.Schedules = append(r.Schedules, ScheduleEntry{Cron: schedule, User: user, Command: command})
+}
+
+// scriptVariables map panel deploy variables to the hook's variables
+var scriptVariables = map[Source]map[string]string{
+	SourceForge: {
+		"$FORGE_SITE_PATH":   "$RAVACT_SITE_PATH",
+		"$FORGE_SITE_BRANCH": "$RAVACT_BRANCH",
+		"$FORGE_SITE_USER":   "$RAVACT_USER",
+		"$FORGE_PHP_FPM":     "$RAVACT_PHP_FPM",
+		"$FORGE_PHP":         "$RAVACT_PHP",
+		"$FORGE_COMPOSER":    "composer",
+	},
+	SourcePloi: {
+		"{SITE_DIRECTORY}": "$RAVACT_SITE_PATH",
+		"{BRANCH}":         "$RAVACT_BRANCH",
+		"{SITE_USER}":      "$RAVACT_USER",
+		"{SITE_PHP}":       "$RAVACT_PHP",
+		"{RELOAD_PHP_FPM}": "sudo systemctl reload $RAVACT_PHP_FPM",
+		"{SITE_COMPOSER}":  "composer",
+	},
+}
+
+// unknownVariable matches panel variables left after conversion
+var unknownVariable = map[Source]*regexp.Regexp{
+	SourceForge: regexp.MustCompile(`\$\{?FORGE_[A-Z_]+`),
+	SourcePloi:  regexp.MustCompile(`\{[A-Z_]+\}`),
+}
+
+// convertScript rewrites a deploy script to run from ravact's hook
+func (r *Result) convertScript(b SiteBlueprint, script string) string {
+	vars := scriptVariables[r.Source]
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	// Longest first so $FORGE_PHP_FPM is not rewritten as $FORGE_PHP
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+	pairs := make([]string, 0, 2*len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k, vars[k])
+		// ${VAR} spelling for Forge
+		if strings.HasPrefix(k, "$") {
+			pairs = append(pairs, "${"+k[1:]+"}", vars[k])
+		}
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	var body []string
+	for i, line := range strings.Split(strings.TrimSpace(script), "\n") {
+		line = replacer.Replace(strings.TrimRight(line, "\r"))
+		panelHome := "/home/" + panelUsers[r.Source] + "/"
+		if strings.Contains(line, panelHome) {
+			line = strings.ReplaceAll(line, panelHome+b.Domain, "$RAVACT_SITE_PATH")
+		}
+		if unknown := unknownVariable[r.Source].FindAllString(line, -1); len(unknown) > 0 {
+			r.Unconverted = append(r.Unconverted, fmt.Sprintf("%s: deploy script line %d uses %s", b.Domain, i+1, strings.Join(unknown, ", ")))
+		}
+		body = append(body, line)
+	}
+
+	php := "php"
+	fpm := "php-fpm"
+	if b.PHPVersion != "" {
+		php = "php" + b.PHPVersion
+		fpm = "php" + b.PHPVersion + "-fpm"
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "#!/bin/bash\n# Deploy hook imported from %s by ravact\nset -e\n\n", r.Source)
+	fmt.Fprintf(&out, "RAVACT_SITE_PATH=%s\n", system.ShellQuote(b.Directory))
+	fmt.Fprintf(&out, "RAVACT_BRANCH=%s\n", system.ShellQuote(b.Branch))
+	fmt.Fprintf(&out, "RAVACT_USER=%s\n", system.ShellQuote(b.User))
+	fmt.Fprintf(&out, "RAVACT_PHP=%s\n", php)
+	fmt.Fprintf(&out, "RAVACT_PHP_FPM=%s\n\n", fpm)
+	out.WriteString(strings.Join(body, "\n"))
+	out.WriteString("\n")
+	return out.String()
+}
+
+// scriptSource guesses the panel a plain deploy script came from
+func scriptSource(script string) Source {
+	if strings.Contains(script, "{SITE_DIRECTORY}") || strings.Contains(script, "{RELOAD_PHP_FPM}") || strings.Contains(script, "/home/ploi/") {
+		return SourcePloi
+	}
+	return SourceForge
+}
+
+// siteFromCd finds the domain in the script's `cd /home/<panel>/<domain>`
+var siteFromCd = regexp.MustCompile(`(?m)^\s*cd\s+/home/(?:forge|ploi)/([^/\s]+)`)
+
+// parseScript converts a pasted deploy script for one site
+func parseScript(script string, opts Options) (*Result, error) {
+	r := &Result{Source: scriptSource(script)}
+	domain := opts.Site
+	if domain == "" {
+		if m := siteFromCd.FindStringSubmatch(script); m != nil {
+			domain = m[1]
+		}
+	}
+	if domain == "" {
+		return nil, fmt.Errorf("cannot tell which site the deploy script is for; pass --site DOMAIN")
+	}
+	site := exportSite{Name: domain, DeploymentScript: script}
+	if r.Source == SourcePloi {
+		site.Domain = domain
+	}
+	r.Sites = append(r.Sites, r.convertSite(site, opts))
+	r.Unconverted = append(r.Unconverted, domain+": only the deploy script was imported; check the blueprint's PHP version and repository")
+	return r, nil
+}
+
+// CronFile returns the cron.d file for the imported jobs and its content
+func (r *Result) CronFile() (string, string) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Scheduled jobs imported from %s by ravact\nSHELL=/bin/bash\n\n", r.Source)
+	for _, s := range r.Schedules {
+		fmt.Fprintf(&b, "%s %s %s\n", s.Cron, s.User, s.Command)
+	}
+	return filepath.Join(CronDir, "ravact-"+string(r.Source)+"-import"), b.String()
+}
+
+// Save writes each site's blueprint and deploy hook to its ravact data
+// directory and the scheduled jobs to cron.d, returning the written paths
+func (r *Result) Save() ([]string, error) {
+	var written []string
+	for _, site := range r.Sites {
+		dir, err := system.SiteDir(site.Blueprint.Domain)
+		if err != nil {
+			return written, err
+		}
+		if err := system.MkdirAll(dir, 0755); err != nil {
+			return written, fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		data, err := yaml.Marshal(site.Blueprint)
+		if err != nil {
+			return written, fmt.Errorf("failed to encode blueprint: %w", err)
+		}
+		blueprint := filepath.Join(dir, BlueprintFile)
+		if err := system.WriteFile(blueprint, data, 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", blueprint, err)
+		}
+		written = append(written, blueprint)
+
+		if site.DeployHook != "" {
+			hook := filepath.Join(dir, DeployHookFile)
+			if err := system.WriteFile(hook, []byte(site.DeployHook), 0755); err != nil {
+				return written, fmt.Errorf("failed to write %s: %w", hook, err)
+			}
+			written = append(written, hook)
+		}
+	}
+
+	if len(r.Schedules) > 0 {
+		path, content := r.CronFile()
+		if err := system.WriteFile(path, []byte(content), 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
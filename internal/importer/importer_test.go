@@ -0,0 +1,207 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iperamuna/ravact/internal/system"
+)
+
+const forgeExport = `{
+  "site": {
+    "name": "example.com",
+    "aliases": ["www.example.com"],
+    "directory": "/public",
+    "repository": "acme/example",
+    "repository_branch": "main",
+    "project_type": "php",
+    "php_version": "php83",
+    "username": "forge"
+  },
+  "deployment_script": "cd /home/forge/example.com\ngit pull origin $FORGE_SITE_BRANCH\n$FORGE_COMPOSER install --no-dev\n( flock -w 10 9 || exit 1\n    sudo -S service $FORGE_PHP_FPM reload ) 9>/tmp/fpmlock\n$FORGE_PHP artisan migrate --force\necho $FORGE_DEPLOY_COMMIT",
+  "jobs": [
+    {"command": "php /home/forge/example.com/artisan schedule:run", "user": "forge", "frequency": "minutely"},
+    {"command": "date +%F >> /tmp/x", "user": "root", "frequency": "custom", "cron": "30 2 * * *"},
+    {"command": "backup", "user": "root", "frequency": "fortnightly"}
+  ]
+}`
+
+func TestParseForge(t *testing.T) {
+	r, err := Parse([]byte(forgeExport), Options{User: "deploy", Root: "/var/www"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Source != SourceForge || len(r.Sites) != 1 {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+	b := r.Sites[0].Blueprint
+	want := SiteBlueprint{Source: SourceForge, Domain: "example.com", Aliases: []string{"www.example.com"}, Directory: "/var/www/example.com",
+		WebDir: "/public", Template: "laravel", PHPVersion: "8.3", Repository: "acme/example", Branch: "main", User: "deploy"}
+	if b.Domain != want.Domain || b.Directory != want.Directory || b.Template != want.Template || b.PHPVersion != want.PHPVersion || b.User != want.User || b.RootDir() != "/var/www/example.com/public" {
+		t.Errorf("got %+v, want %+v", b, want)
+	}
+
+	hook := r.Sites[0].DeployHook
+	for _, line := range []string{
+		"RAVACT_SITE_PATH=/var/www/example.com",
+		"RAVACT_PHP_FPM=php8.3-fpm",
+		"cd $RAVACT_SITE_PATH",
+		"git pull origin $RAVACT_BRANCH",
+		"composer install --no-dev",
+		"sudo -S service $RAVACT_PHP_FPM reload",
+		"$RAVACT_PHP artisan migrate --force",
+	} {
+		if !strings.Contains(hook, line) {
+			t.Errorf("deploy hook missing %q:\n%s", line, hook)
+		}
+	}
+
+	if len(r.Schedules) != 2 {
+		t.Fatalf("expected 2 schedules, got %+v", r.Schedules)
+	}
+	if got := r.Schedules[0]; got != (ScheduleEntry{"* * * * *", "deploy", "php /var/www/example.com/artisan schedule:run"}) {
+		t.Errorf("unexpected schedule: %+v", got)
+	}
+	if got := r.Schedules[1].Command; got != `date +\%F >> /tmp/x` {
+		t.Errorf("expected %% to be escaped for cron.d, got %q", got)
+	}
+
+	unconverted := strings.Join(r.Unconverted, "\n")
+	for _, want := range []string{"$FORGE_DEPLOY_COMMIT", `"fortnightly"`} {
+		if !strings.Contains(unconverted, want) {
+			t.Errorf("expected %s to be reported, got:\n%s", want, unconverted)
+		}
+	}
+}
+
+func TestParsePloi(t *testing.T) {
+	sites := `{"data": [{"domain": "shop.test", "root": "/home/ploi/shop.test", "web_directory": "/public", "project_type": "wordpress", "php_version": 8.2, "system_user": "ploi"}]}`
+	r, err := Parse([]byte(sites), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := r.Sites[0].Blueprint
+	if r.Source != SourcePloi || b.Template != "wordpress" || b.PHPVersion != "8.2" || b.Directory != "/home/ploi/shop.test" {
+		t.Errorf("unexpected blueprint: %+v", b)
+	}
+	if len(r.Unconverted) != 1 || !strings.Contains(r.Unconverted[0], "--user") {
+		t.Errorf("expected the ploi user to be reported, got %v", r.Unconverted)
+	}
+
+	crons := `{"data": [{"command": "php artisan queue:prune", "user": "ploi", "frequency": "0 3 * * *"}]}`
+	r, err = Parse([]byte(crons), Options{User: "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Schedules) != 1 || r.Schedules[0].Cron != "0 3 * * *" || r.Schedules[0].User != "web" {
+		t.Errorf("unexpected schedules: %+v", r.Schedules)
+	}
+}
+
+func TestParseScript(t *testing.T) {
+	script := "cd {SITE_DIRECTORY}\ngit pull origin {BRANCH}\n{SITE_COMPOSER} install\n{RELOAD_PHP_FPM}\necho {RELEASE}"
+	if _, err := Parse([]byte(script), Options{}); err == nil {
+		t.Fatal("expected an error without --site")
+	}
+	r, err := Parse([]byte(script), Options{Site: "blog.test", User: "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook := r.Sites[0].DeployHook
+	if r.Source != SourcePloi || !strings.Contains(hook, "sudo systemctl reload $RAVACT_PHP_FPM") || !strings.Contains(hook, "git pull origin $RAVACT_BRANCH") {
+		t.Errorf("unexpected hook:\n%s", hook)
+	}
+	if !strings.Contains(strings.Join(r.Unconverted, "\n"), "{RELEASE}") {
+		t.Errorf("expected {RELEASE} to be reported, got %v", r.Unconverted)
+	}
+
+	r, err = Parse([]byte("cd /home/forge/api.example.com\ngit pull"), Options{User: "web"})
+	if err != nil || r.Sites[0].Blueprint.Domain != "api.example.com" {
+		t.Errorf("expected the domain from the cd line, got %+v, %v", r, err)
+	}
+}
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	oldSites, oldCron := system.SiteDataDir, CronDir
+	system.SiteDataDir, CronDir = filepath.Join(dir, "sites"), filepath.Join(dir, "cron.d")
+	t.Cleanup(func() { system.SiteDataDir, CronDir = oldSites, oldCron })
+	os.MkdirAll(CronDir, 0755)
+
+	r, err := Parse([]byte(forgeExport), Options{User: "deploy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, err := r.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != 3 {
+		t.Fatalf("expected blueprint, hook, and cron file, got %v", written)
+	}
+	info, err := os.Stat(filepath.Join(dir, "sites", "example.com", DeployHookFile))
+	if err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("expected an executable deploy hook: %v", err)
+	}
+	blueprint, _ := os.ReadFile(filepath.Join(dir, "sites", "example.com", BlueprintFile))
+	if !strings.Contains(string(blueprint), "template: laravel") {
+		t.Errorf("unexpected blueprint:\n%s", blueprint)
+	}
+	cron, _ := os.ReadFile(filepath.Join(dir, "cron.d", "ravact-forge-import"))
+	if !strings.Contains(string(cron), "* * * * * deploy php /home/forge/example.com/artisan schedule:run") {
+		t.Errorf("unexpected cron file:\n%s", cron)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	dir := t.TempDir()
+	oldSites, oldCron, oldNginx := system.SiteDataDir, CronDir, NginxSitesDir
+	system.SiteDataDir, CronDir, NginxSitesDir = filepath.Join(dir, "sites"), filepath.Join(dir, "cron.d"), filepath.Join(dir, "nginx")
+	t.Cleanup(func() { system.SiteDataDir, CronDir, NginxSitesDir = oldSites, oldCron, oldNginx })
+	os.MkdirAll(CronDir, 0755)
+
+	r, err := Parse([]byte(forgeExport), Options{User: "deploy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := r.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if create, update, remove := plan.Counts(); create != 4 || update != 0 || remove != 0 {
+		t.Fatalf("expected blueprint, hook, and two jobs to be created, got %+v", plan.Changes)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sites")); !os.IsNotExist(err) {
+		t.Error("planning should not write anything")
+	}
+
+	if _, err := r.Save(); err != nil {
+		t.Fatal(err)
+	}
+	plan, _ = r.Plan()
+	if plan.HasChanges() {
+		t.Errorf("expected nothing to change after applying, got:\n%s", plan)
+	}
+
+	// A changed job is replaced, and a live site is flagged
+	os.MkdirAll(NginxSitesDir, 0755)
+	os.WriteFile(filepath.Join(NginxSitesDir, "example.com"), []byte("server {}"), 0644)
+	r.Schedules[1].Cron = "45 3 * * *"
+	r.Sites[0].Blueprint.Branch = "release"
+	plan, _ = r.Plan()
+	out := plan.String()
+	for _, want := range []string{
+		"~ site example.com blueprint",
+		"! site is live",
+		"+ job 45 3 * * * root date",
+		"! runs as root",
+		"- job 30 2 * * * root date",
+		"Plan: 1 to create, 1 to update, 1 to delete.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in plan:\n%s", want, out)
+		}
+	}
+}
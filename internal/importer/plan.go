@@ -0,0 +1,182 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/iperamuna/ravact/internal/system"
+	"gopkg.in/yaml.v3"
+)
+
+// NginxSitesDir is checked to tell whether an imported site is already live
+var NginxSitesDir = "/etc/nginx/sites-available"
+
+// Action is what applying an import does to one resource
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionDelete    Action = "delete"
+	ActionUnchanged Action = "unchanged"
+)
+
+// symbols are the terraform-style markers for each action
+var symbols = map[Action]string{ActionCreate: "+", ActionUpdate: "~", ActionDelete: "-", ActionUnchanged: " "}
+
+// PlannedChange is one resource in a plan
+type PlannedChange struct {
+	Action   Action
+	Resource string   // e.g. "site example.com blueprint"
+	Path     string   // File the change is written to
+	Risks    []string // Restarts, downtime, or other side effects
+}
+
+// Plan lists what applying an import would change, compared with what is
+// already on disk
+type Plan struct {
+	Changes []PlannedChange
+}
+
+// Counts returns how many resources are created, updated, and deleted
+func (p *Plan) Counts() (create, update, remove int) {
+	for _, c := range p.Changes {
+		switch c.Action {
+		case ActionCreate:
+			create++
+		case ActionUpdate:
+			update++
+		case ActionDelete:
+			remove++
+		}
+	}
+	return create, update, remove
+}
+
+// HasChanges reports whether applying the plan would change anything
+func (p *Plan) HasChanges() bool {
+	create, update, remove := p.Counts()
+	return create+update+remove > 0
+}
+
+// String renders the plan like `terraform plan`
+func (p *Plan) String() string {
+	var b strings.Builder
+	for _, c := range p.Changes {
+		if c.Action == ActionUnchanged {
+			continue
+		}
+		fmt.Fprintf(&b, "  %s %s (%s)\n", symbols[c.Action], c.Resource, c.Path)
+		for _, risk := range c.Risks {
+			fmt.Fprintf(&b, "      ! %s\n", risk)
+		}
+	}
+	create, update, remove := p.Counts()
+	fmt.Fprintf(&b, "\nPlan: %d to create, %d to update, %d to delete.\n", create, update, remove)
+	return b.String()
+}
+
+// Plan compares the import with the files on disk. It reads but never
+// writes, so it is safe to run before asking for approval.
+func (r *Result) Plan() (*Plan, error) {
+	plan := &Plan{}
+	for _, site := range r.Sites {
+		b := site.Blueprint
+		dir, err := system.SiteDir(b.Domain)
+		if err != nil {
+			return nil, err
+		}
+		data, err := yaml.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode blueprint: %w", err)
+		}
+
+		change := fileChange("site "+b.Domain+" blueprint", filepath.Join(dir, BlueprintFile), data)
+		if _, err := system.Stat(filepath.Join(NginxSitesDir, b.Domain)); err == nil && change.Action != ActionUnchanged {
+			change.Risks = append(change.Risks, "site is live: recreating it from this blueprint reloads nginx and may interrupt requests")
+		}
+		if b.PHPVersion != "" {
+			if _, err := system.Stat(filepath.Join(system.PHPConfigDir, b.PHPVersion, "fpm")); err != nil {
+				change.Risks = append(change.Risks, fmt.Sprintf("PHP %s is not installed: installing it starts a new PHP-FPM service", b.PHPVersion))
+			}
+		}
+		plan.Changes = append(plan.Changes, change)
+
+		if site.DeployHook != "" {
+			change := fileChange("site "+b.Domain+" deploy hook", filepath.Join(dir, DeployHookFile), []byte(site.DeployHook))
+			if change.Action != ActionUnchanged && strings.Contains(site.DeployHook, "reload") {
+				change.Risks = append(change.Risks, "reloads PHP-FPM on every deploy")
+			}
+			plan.Changes = append(plan.Changes, change)
+		}
+	}
+
+	if len(r.Schedules) > 0 {
+		path, content := r.CronFile()
+		plan.Changes = append(plan.Changes, jobChanges(path, content)...)
+	}
+	return plan, nil
+}
+
+// fileChange compares new content with a file on disk
+func fileChange(resource, path string, content []byte) PlannedChange {
+	change := PlannedChange{Action: ActionCreate, Resource: resource, Path: path}
+	if existing, err := system.ReadFile(path); err == nil {
+		change.Action = ActionUpdate
+		if bytes.Equal(existing, content) {
+			change.Action = ActionUnchanged
+		}
+	}
+	return change
+}
+
+// jobChanges compares the jobs of a cron.d file with the one on disk, line by
+// line, since writing the file replaces every job previously imported into it
+func jobChanges(path, content string) []PlannedChange {
+	var current []string
+	if data, err := system.ReadFile(path); err == nil {
+		current = cronJobLines(string(data))
+	}
+	existing := map[string]bool{}
+	for _, line := range current {
+		existing[line] = true
+	}
+
+	var changes []PlannedChange
+	for _, line := range cronJobLines(content) {
+		change := PlannedChange{Action: ActionCreate, Resource: "job " + line, Path: path}
+		if existing[line] {
+			change.Action = ActionUnchanged
+			delete(existing, line)
+		} else if fields := strings.Fields(line); len(fields) > 5 && fields[5] == "root" {
+			change.Risks = append(change.Risks, "runs as root")
+		}
+		changes = append(changes, change)
+	}
+	for _, line := range current {
+		if existing[line] {
+			changes = append(changes, PlannedChange{Action: ActionDelete, Resource: "job " + line, Path: path,
+				Risks: []string{"no longer scheduled once the file is replaced"}})
+		}
+	}
+	return changes
+}
+
+// cronJobLines returns the job lines of a cron.d file, skipping comments and
+// variable assignments
+func cronJobLines(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) > 0 && strings.Contains(fields[0], "=") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
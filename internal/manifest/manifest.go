@@ -0,0 +1,337 @@
+// Package manifest exports what ravact manages on a server as a YAML
+// manifest that `ravact apply` can replay on another server
+package manifest
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/models"
+	"github.com/iperamuna/ravact/internal/system"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the manifest format this build writes and reads
+const Version = 1
+
+// SystemdDir holds the FrankenPHP service units
+var SystemdDir = "/etc/systemd/system"
+
+// SetupServices maps each setup script to the service it installs, for
+// software installed before ravact recorded its provisioning
+var SetupServices = map[string]string{
+	"nginx":       "nginx",
+	"mysql":       "mysql",
+	"postgresql":  "postgresql",
+	"pgbouncer":   "pgbouncer",
+	"redis":       "redis-server",
+	"dragonfly":   "dragonfly",
+	"meilisearch": "meilisearch",
+	"netdata":     "netdata",
+	"docker":      "docker",
+	"supervisor":  "supervisor",
+	"certbot":     "certbot",
+	"git":         "git",
+	"firewall":    "ufw",
+}
+
+// Manifest describes a server well enough to rebuild it
+type Manifest struct {
+	Version    int                 `yaml:"version"`
+	Host       string              `yaml:"host,omitempty"`
+	Exported   time.Time           `yaml:"exported"`
+	Services   []Service           `yaml:"services,omitempty"`
+	PHP        []string            `yaml:"php,omitempty"` // Installed PHP versions
+	Sites      []Site              `yaml:"sites,omitempty"`
+	FrankenPHP []FrankenPHPService `yaml:"frankenphp,omitempty"`
+	Workers    []Worker            `yaml:"workers,omitempty"`
+	Firewall   *Firewall           `yaml:"firewall,omitempty"`
+	// Warnings lists sections that could not be read completely
+	Warnings []string `yaml:"warnings,omitempty"`
+}
+
+// Service is software installed by one of the setup scripts
+type Service struct {
+	Script      string `yaml:"script"`
+	Environment string `yaml:"environment,omitempty"` // Options the script ran with
+	Managed     bool   `yaml:"managed"`               // Installed through ravact
+}
+
+// Site is an nginx site. Managed sites carry the template and options they
+// were created with; others only what their config shows.
+type Site struct {
+	Name     string   `yaml:"name"`
+	Domains  []string `yaml:"domains"`
+	Root     string   `yaml:"root"`
+	Template string   `yaml:"template,omitempty"`
+	Upstream string   `yaml:"upstream,omitempty"`
+	SSL      bool     `yaml:"ssl"`
+	Certbot  bool     `yaml:"certbot,omitempty"`
+	Enabled  bool     `yaml:"enabled"`
+	Managed  bool     `yaml:"managed"`
+}
+
+// FrankenPHPService is a FrankenPHP site run by systemd; its unit and
+// Caddyfile are carried whole
+type FrankenPHPService struct {
+	SiteKey   string `yaml:"site_key"`
+	User      string `yaml:"user,omitempty"`
+	Group     string `yaml:"group,omitempty"`
+	Root      string `yaml:"root,omitempty"`
+	Enabled   bool   `yaml:"enabled"`
+	Unit      string `yaml:"unit"`
+	Caddyfile string `yaml:"caddyfile,omitempty"`
+}
+
+// Worker is a supervisor program
+type Worker struct {
+	Name      string `yaml:"name"`
+	Command   string `yaml:"command"`
+	Directory string `yaml:"directory,omitempty"`
+	User      string `yaml:"user,omitempty"`
+	AutoStart bool   `yaml:"autostart"`
+}
+
+// Firewall is the firewall and its rules
+type Firewall struct {
+	Type    string         `yaml:"type"`
+	Enabled bool           `yaml:"enabled"`
+	Rules   []FirewallRule `yaml:"rules,omitempty"`
+}
+
+// FirewallRule is one rule; From and To are empty for anywhere
+type FirewallRule struct {
+	Port      string `yaml:"port"`
+	Protocol  string `yaml:"protocol,omitempty"`
+	Action    string `yaml:"action"`
+	Direction string `yaml:"direction,omitempty"` // Only set for outgoing rules
+	From      string `yaml:"from,omitempty"`
+	To        string `yaml:"to,omitempty"`
+	Comment   string `yaml:"comment,omitempty"`
+}
+
+// Collect inspects the active host. Sections that cannot be read are noted
+// in Warnings rather than failing the export.
+func Collect() (*Manifest, error) {
+	if system.HostOS() != "linux" {
+		return nil, fmt.Errorf("export is only available on Linux (current OS: %s)", system.HostOS())
+	}
+	m := &Manifest{Version: Version, Exported: time.Now().UTC()}
+	if info, err := system.NewDetector().GetSystemInfo(); err == nil {
+		m.Host = info.Hostname
+	}
+
+	records, err := system.LoadProvisionRecords()
+	if err != nil {
+		m.warn("services", err)
+	}
+	detector := system.NewDetector()
+	m.Services = services(records, func(serviceID string) models.ServiceStatus {
+		status, _ := detector.GetServiceStatus(serviceID)
+		return status
+	})
+	m.PHP = phpVersions()
+
+	generated, err := system.LoadGeneratedConfigs()
+	if err != nil {
+		m.warn("sites", err)
+	}
+	if sites, err := system.NewNginxManager().GetAllSites(); err == nil {
+		m.Sites = nginxSites(sites, generated)
+	} else {
+		m.warn("sites", err)
+	}
+
+	m.FrankenPHP = m.frankenPHPServices()
+
+	if sm := system.NewSupervisorManager(); sm.IsInstalled() {
+		if programs, err := sm.GetAllPrograms(); err == nil {
+			m.Workers = workers(programs)
+		} else {
+			m.warn("workers", err)
+		}
+	}
+
+	fm := system.NewFirewallManager()
+	if kind := fm.GetFirewallType(); kind != system.FirewallNone {
+		status, _ := fm.GetStatus()
+		fw := &Firewall{Type: string(kind), Enabled: status == "active"}
+		if rules, err := fm.GetRules(); err == nil {
+			fw.Rules = firewallRules(rules)
+		} else {
+			m.warn("firewall", err)
+		}
+		m.Firewall = fw
+	}
+	return m, nil
+}
+
+// warn records a section that could not be read
+func (m *Manifest) warn(section string, err error) {
+	m.Warnings = append(m.Warnings, section+": "+err.Error())
+}
+
+// services lists the setup scripts whose software is installed, and those
+// ravact recorded installing
+func services(records map[string]system.ProvisionRecord, status func(serviceID string) models.ServiceStatus) []Service {
+	var list []Service
+	for script, rec := range records {
+		list = append(list, Service{Script: script, Environment: rec.Environment, Managed: true})
+	}
+	for script, serviceID := range SetupServices {
+		if _, ok := records[script]; ok {
+			continue
+		}
+		if s := status(serviceID); s != models.StatusNotInstalled && s != models.StatusUnknown {
+			list = append(list, Service{Script: script})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Script < list[j].Script })
+	return list
+}
+
+// phpVersions returns the PHP versions under /etc/php
+func phpVersions() []string {
+	entries, err := system.ReadDir("/etc/php")
+	if err != nil {
+		return nil
+	}
+	var versions []string
+	for _, e := range entries {
+		if e.IsDir() {
+			versions = append(versions, e.Name())
+		}
+	}
+	return versions
+}
+
+// nginxSites describes each site, using the options recorded when ravact
+// created it where there are any
+func nginxSites(sites []system.NginxSite, generated []system.GeneratedConfig) []Site {
+	byPath := map[string]system.GeneratedConfig{}
+	for _, g := range generated {
+		if g.Generator == system.GeneratorNginxSite {
+			byPath[g.Path] = g
+		}
+	}
+	var list []Site
+	for _, s := range sites {
+		site := Site{Name: s.Name, Domains: s.Domains, Root: s.RootDir, SSL: s.HasSSL, Enabled: s.IsEnabled}
+		if g, ok := byPath[s.ConfigPath]; ok {
+			site.Managed = true
+			site.Domains = strings.Fields(g.Params["domains"])
+			site.Root = g.Params["root"]
+			site.Template = g.Params["template"]
+			site.Upstream = g.Params["upstream"]
+			site.SSL, _ = strconv.ParseBool(g.Params["ssl"])
+			site.Certbot, _ = strconv.ParseBool(g.Params["certbot"])
+		}
+		list = append(list, site)
+	}
+	return list
+}
+
+// frankenPHPServices reads each frankenphp-<site>.service unit and its Caddyfile
+func (m *Manifest) frankenPHPServices() []FrankenPHPService {
+	entries, err := system.ReadDir(SystemdDir)
+	if err != nil {
+		return nil
+	}
+	var list []FrankenPHPService
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, "frankenphp-") || !strings.HasSuffix(name, ".service") {
+			continue
+		}
+		unit, err := system.ReadFile(path.Join(SystemdDir, name))
+		if err != nil {
+			m.warn("frankenphp", err)
+			continue
+		}
+		svc := parseFrankenPHPUnit(name, string(unit))
+		if caddyfile, err := system.ReadFile(path.Join("/etc/frankenphp", svc.SiteKey, "Caddyfile")); err == nil {
+			svc.Caddyfile = string(caddyfile)
+		}
+		out, _ := system.Command("systemctl", "is-enabled", strings.TrimSuffix(name, ".service")).Output()
+		svc.Enabled = strings.TrimSpace(string(out)) == "enabled"
+		list = append(list, svc)
+	}
+	return list
+}
+
+// parseFrankenPHPUnit reads the site key, user, group, and root of a unit
+func parseFrankenPHPUnit(name, unit string) FrankenPHPService {
+	svc := FrankenPHPService{
+		SiteKey: strings.TrimSuffix(strings.TrimPrefix(name, "frankenphp-"), ".service"),
+		Unit:    unit,
+	}
+	for _, line := range strings.Split(unit, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "User":
+			svc.User = value
+		case "Group":
+			svc.Group = value
+		case "WorkingDirectory":
+			svc.Root = value
+		}
+	}
+	return svc
+}
+
+// workers converts supervisor programs
+func workers(programs []system.SupervisorProgram) []Worker {
+	var list []Worker
+	for _, p := range programs {
+		list = append(list, Worker{Name: p.Name, Command: p.Command, Directory: p.Directory, User: p.User, AutoStart: p.AutoStart})
+	}
+	return list
+}
+
+// firewallRules converts rules, dropping the IPv6 copies ufw lists
+// alongside each IPv4 rule
+func firewallRules(rules []system.FirewallRule) []FirewallRule {
+	var list []FirewallRule
+	for _, r := range rules {
+		if r.V6 {
+			continue
+		}
+		rule := FirewallRule{Port: r.Port, Protocol: r.Protocol, Action: strings.ToLower(r.Action), To: r.To, Comment: r.Comment}
+		if strings.EqualFold(r.Direction, "OUT") {
+			rule.Direction = "out"
+		}
+		if r.From != "" && !strings.EqualFold(r.From, "Anywhere") {
+			rule.From = r.From
+		}
+		list = append(list, rule)
+	}
+	return list
+}
+
+// YAML renders the manifest
+func (m *Manifest) YAML() ([]byte, error) {
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return append([]byte("# ravact server manifest; replay with `ravact apply`\n"), data...), nil
+}
+
+// Parse reads a manifest, refusing formats newer than this build
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if m.Version < 1 || m.Version > Version {
+		return nil, fmt.Errorf("manifest version %d is not supported (this build reads version %d)", m.Version, Version)
+	}
+	return &m, nil
+}
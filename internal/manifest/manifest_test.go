@@ -0,0 +1,115 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iperamuna/ravact/internal/models"
+	"github.com/iperamuna/ravact/internal/system"
+)
+
+func TestServices(t *testing.T) {
+	records := map[string]system.ProvisionRecord{
+		"dragonfly": {Script: "dragonfly", Environment: "DRAGONFLY_METHOD=apt"},
+	}
+	statuses := map[string]models.ServiceStatus{"nginx": models.StatusRunning, "mysql": models.StatusNotInstalled}
+	list := services(records, func(id string) models.ServiceStatus {
+		if s, ok := statuses[id]; ok {
+			return s
+		}
+		return models.StatusNotInstalled
+	})
+	if len(list) != 2 {
+		t.Fatalf("expected dragonfly and nginx, got %+v", list)
+	}
+	if list[0] != (Service{Script: "dragonfly", Environment: "DRAGONFLY_METHOD=apt", Managed: true}) {
+		t.Errorf("unexpected recorded service %+v", list[0])
+	}
+	if list[1] != (Service{Script: "nginx"}) {
+		t.Errorf("unexpected detected service %+v", list[1])
+	}
+}
+
+func TestNginxSites(t *testing.T) {
+	sites := []system.NginxSite{
+		{Name: "shop", Domains: []string{"shop.test"}, RootDir: "/var/www/shop/public", ConfigPath: "/etc/nginx/sites-available/shop", IsEnabled: true},
+		{Name: "legacy", Domains: []string{"old.test"}, RootDir: "/srv/old", ConfigPath: "/etc/nginx/sites-available/legacy", HasSSL: true},
+	}
+	generated := []system.GeneratedConfig{{
+		Path:      "/etc/nginx/sites-available/shop",
+		Generator: system.GeneratorNginxSite,
+		Params: map[string]string{
+			"domains": "shop.test www.shop.test", "root": "/var/www/shop/public",
+			"template": "laravel", "ssl": "true", "certbot": "true",
+		},
+	}}
+	list := nginxSites(sites, generated)
+	shop := list[0]
+	if !shop.Managed || shop.Template != "laravel" || !shop.Certbot || len(shop.Domains) != 2 || !shop.Enabled {
+		t.Errorf("expected the recorded options, got %+v", shop)
+	}
+	if legacy := list[1]; legacy.Managed || legacy.Template != "" || !legacy.SSL {
+		t.Errorf("expected only what the config shows, got %+v", legacy)
+	}
+}
+
+func TestParseFrankenPHPUnit(t *testing.T) {
+	unit := "[Service]\nUser=deploy\nGroup=www-data\nWorkingDirectory=/var/www/api\n"
+	svc := parseFrankenPHPUnit("frankenphp-api.service", unit)
+	if svc.SiteKey != "api" || svc.User != "deploy" || svc.Group != "www-data" || svc.Root != "/var/www/api" || svc.Unit != unit {
+		t.Errorf("unexpected service %+v", svc)
+	}
+}
+
+func TestFirewallRules(t *testing.T) {
+	rules := firewallRules([]system.FirewallRule{
+		{Port: "22", Protocol: "tcp", Action: "ALLOW", Direction: "IN", From: "Anywhere"},
+		{Port: "22", Protocol: "tcp", Action: "ALLOW", Direction: "IN", From: "Anywhere (v6)", V6: true},
+		{Port: "3306", Action: "ALLOW", Direction: "IN", From: "10.0.0.5"},
+		{Port: "25", Action: "DENY", Direction: "OUT", From: "Anywhere"},
+	})
+	want := []FirewallRule{
+		{Port: "22", Protocol: "tcp", Action: "allow"},
+		{Port: "3306", Action: "allow", From: "10.0.0.5"},
+		{Port: "25", Action: "deny", Direction: "out"},
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("got %+v", rules)
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, rules[i], want[i])
+		}
+	}
+}
+
+func TestManifestRoundTrip(t *testing.T) {
+	m := &Manifest{
+		Version:  Version,
+		Host:     "web1",
+		Services: []Service{{Script: "nginx", Managed: true}},
+		Sites:    []Site{{Name: "shop", Domains: []string{"shop.test"}, Root: "/var/www/shop", Template: "static"}},
+		Firewall: &Firewall{Type: "ufw", Enabled: true, Rules: []FirewallRule{{Port: "80", Action: "allow"}}},
+	}
+	data, err := m.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "# ravact server manifest") {
+		t.Errorf("expected a header comment, got %q", data)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Host != "web1" || parsed.Sites[0].Template != "static" || parsed.Firewall.Rules[0].Port != "80" {
+		t.Errorf("round trip lost data: %+v", parsed)
+	}
+
+	if _, err := Parse([]byte("version: 99\n")); err == nil {
+		t.Error("expected a newer manifest version to be refused")
+	}
+	if _, err := Parse([]byte("sites: []\n")); err == nil {
+		t.Error("expected a manifest without a version to be refused")
+	}
+}
@@ -0,0 +1,322 @@
+// Package marketplace browses an optional index of community blueprints and
+// scripts and vendors them onto the server for review before use.
+//
+// The index is JSON served over HTTPS next to a detached ed25519 signature
+// (<index>.sig, base64). Every file an entry lists carries its SHA-256, so
+// the signature covers the file contents as well as the listing.
+package marketplace
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/system"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigPath holds the index URL and the key it must be signed with
+var ConfigPath = "/etc/ravact/marketplace.yaml"
+
+// VendorDir is where installed entries are copied for review
+var VendorDir = "/var/lib/ravact/marketplace"
+
+// MetadataFile records which index entry a vendored directory came from
+const MetadataFile = "ravact-marketplace.json"
+
+// Entry kinds
+const (
+	KindBlueprint = "blueprint"
+	KindScript    = "script"
+)
+
+const (
+	fetchTimeout = 30 * time.Second
+	maxIndexSize = 1 << 20
+	maxFileSize  = 10 << 20
+)
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// httpClient fetches the index and files; tests swap it for a TLS test client
+var httpClient = &http.Client{Timeout: fetchTimeout}
+
+// Config points ravact at an index. The marketplace is off until both are set.
+type Config struct {
+	URL       string `yaml:"url,omitempty"`
+	PublicKey string `yaml:"public_key,omitempty"` // base64 ed25519 public key
+}
+
+// Enabled reports whether an index is configured
+func (c Config) Enabled() bool {
+	return c.URL != "" && c.PublicKey != ""
+}
+
+// Validate checks the URL is HTTPS and the key decodes
+func (c Config) Validate() error {
+	if c.URL == "" && c.PublicKey == "" {
+		return nil
+	}
+	if err := checkHTTPS(c.URL); err != nil {
+		return fmt.Errorf("index %w", err)
+	}
+	if _, err := c.key(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c Config) key() (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(c.PublicKey))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key must be a base64 ed25519 key")
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// LoadConfig reads the marketplace settings; without them the marketplace
+// is disabled
+func LoadConfig() (Config, error) {
+	var c Config
+	data, err := system.ReadFile(ConfigPath)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to read marketplace config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("failed to parse %s: %w", ConfigPath, err)
+	}
+	return c, nil
+}
+
+// Save writes the marketplace settings
+func (c Config) Save() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode marketplace config: %w", err)
+	}
+	if err := system.MkdirAll(filepath.Dir(ConfigPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(ConfigPath), err)
+	}
+	if err := system.WriteFile(ConfigPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ConfigPath, err)
+	}
+	return nil
+}
+
+// File is one file of an entry
+type File struct {
+	Path   string `json:"path"` // Relative to the entry's directory
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+// Entry is a community blueprint or script
+type Entry struct {
+	Name        string   `json:"name"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Kind        string   `json:"kind"`
+	Author      string   `json:"author"`
+	Version     string   `json:"version"`
+	Tags        []string `json:"tags,omitempty"`
+	Files       []File   `json:"files"`
+}
+
+// Index is the marketplace listing
+type Index struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Validate rejects entries that could escape their vendor directory or be
+// fetched without TLS
+func (e Entry) Validate() error {
+	if !namePattern.MatchString(e.Name) {
+		return fmt.Errorf("invalid entry name %q", e.Name)
+	}
+	if e.Kind != KindBlueprint && e.Kind != KindScript {
+		return fmt.Errorf("%s: unknown kind %q", e.Name, e.Kind)
+	}
+	if len(e.Files) == 0 {
+		return fmt.Errorf("%s: no files", e.Name)
+	}
+	for _, f := range e.Files {
+		if f.Path == "" || path.IsAbs(f.Path) || path.Clean(f.Path) != f.Path || strings.HasPrefix(f.Path, "..") || f.Path == MetadataFile {
+			return fmt.Errorf("%s: invalid file path %q", e.Name, f.Path)
+		}
+		if err := checkHTTPS(f.URL); err != nil {
+			return fmt.Errorf("%s: %s %w", e.Name, f.Path, err)
+		}
+		if sum, err := hex.DecodeString(f.SHA256); err != nil || len(sum) != sha256.Size {
+			return fmt.Errorf("%s: %s has an invalid sha256", e.Name, f.Path)
+		}
+	}
+	return nil
+}
+
+// checkHTTPS requires an https:// URL
+func checkHTTPS(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("URL must use https: %q", raw)
+	}
+	return nil
+}
+
+// Verify checks the index signature and parses it
+func Verify(data, signature []byte, key ed25519.PublicKey) (*Index, error) {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || !ed25519.Verify(key, data, sig) {
+		return nil, fmt.Errorf("index signature does not match the configured public key")
+	}
+	var index Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse index: %w", err)
+	}
+	seen := map[string]bool{}
+	for _, e := range index.Entries {
+		if err := e.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[e.Name] {
+			return nil, fmt.Errorf("duplicate entry %q", e.Name)
+		}
+		seen[e.Name] = true
+	}
+	sort.Slice(index.Entries, func(i, j int) bool { return index.Entries[i].Name < index.Entries[j].Name })
+	return &index, nil
+}
+
+// Fetch downloads the index and its signature and verifies them
+func (c Config) Fetch() (*Index, error) {
+	if !c.Enabled() {
+		return nil, fmt.Errorf("no marketplace configured in %s", ConfigPath)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	key, _ := c.key()
+	data, err := download(c.URL, maxIndexSize)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := download(c.URL+".sig", 4096)
+	if err != nil {
+		return nil, err
+	}
+	return Verify(data, sig, key)
+}
+
+// download fetches an HTTPS URL, refusing bodies over limit bytes
+func download(raw string, limit int64) ([]byte, error) {
+	if err := checkHTTPS(raw); err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Get(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", raw, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", raw, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", raw, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s is larger than %d bytes", raw, limit)
+	}
+	return data, nil
+}
+
+// Installed describes a vendored entry
+type Installed struct {
+	Entry
+	Index       string    `json:"index"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// Dir returns the vendor directory of an entry
+func Dir(name string) string {
+	return filepath.Join(VendorDir, name)
+}
+
+// Install downloads an entry's files, checks them against the signed index,
+// and copies them to its vendor directory. Nothing is executed: files are
+// written without the execute bit so they are reviewed before use. A
+// failed download leaves a previous install untouched.
+func (c Config) Install(e Entry) (string, error) {
+	if err := e.Validate(); err != nil {
+		return "", err
+	}
+	contents := make(map[string][]byte, len(e.Files))
+	for _, f := range e.Files {
+		data, err := download(f.URL, maxFileSize)
+		if err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != strings.ToLower(f.SHA256) {
+			return "", fmt.Errorf("%s: checksum of %s does not match the index", e.Name, f.Path)
+		}
+		contents[f.Path] = data
+	}
+
+	dir := Dir(e.Name)
+	for _, f := range e.Files {
+		target := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err := system.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+		}
+		if err := system.WriteFile(target, contents[f.Path], 0644); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", target, err)
+		}
+	}
+	meta, err := json.MarshalIndent(Installed{Entry: e, Index: c.URL, InstalledAt: time.Now().UTC()}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := system.WriteFile(filepath.Join(dir, MetadataFile), meta, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", MetadataFile, err)
+	}
+	return dir, nil
+}
+
+// ListInstalled returns the vendored entries by name
+func ListInstalled() map[string]Installed {
+	installed := map[string]Installed{}
+	entries, err := system.ReadDir(VendorDir)
+	if err != nil {
+		return installed
+	}
+	for _, d := range entries {
+		data, err := system.ReadFile(filepath.Join(VendorDir, d.Name(), MetadataFile))
+		if err != nil {
+			continue
+		}
+		var inst Installed
+		if json.Unmarshal(data, &inst) == nil && inst.Name == d.Name() {
+			installed[inst.Name] = inst
+		}
+	}
+	return installed
+}
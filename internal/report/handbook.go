@@ -0,0 +1,424 @@
+package report
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/system"
+)
+
+// HandbookPath is where the handbook is written on the managed host. It is
+// one of the tracked configuration paths, so every regeneration is kept in
+// the configuration history.
+var HandbookPath = "/etc/ravact/handbook.md"
+
+// Software is an installed component and its version
+type Software struct {
+	Name    string
+	Version string
+}
+
+// HandbookSite is a site and the stack serving it
+type HandbookSite struct {
+	Site  system.NginxSite
+	Stack []system.StackNode
+}
+
+// HandbookDatabase is a database server and what it holds
+type HandbookDatabase struct {
+	Engine    string
+	Port      string
+	Bind      string
+	Databases []string
+}
+
+// ScheduledJob is a recurring task, such as a backup
+type ScheduledJob struct {
+	Source   string // Timer unit or crontab path
+	Schedule string
+	Command  string
+}
+
+// Handbook is a description of everything ravact manages on a server
+type Handbook struct {
+	Hostname    string
+	Generated   time.Time
+	System      string
+	Software    []Software
+	Sites       []HandbookSite
+	Services    []system.SystemdUnit
+	Workers     []system.SupervisorProgram
+	Databases   []HandbookDatabase
+	Backups     []ScheduledJob
+	Firewall    system.FirewallType
+	FirewallOn  string
+	Rules       []system.FirewallRule
+	SSHPort     string
+	ConfigPaths []string
+
+	// Sections that could not be collected, by section name
+	Errors map[string]string
+}
+
+// CollectHandbook gathers the handbook for the active host
+func CollectHandbook() (*Handbook, error) {
+	if system.HostOS() != "linux" {
+		return nil, fmt.Errorf("the handbook is only available on Linux (current OS: %s)", system.HostOS())
+	}
+
+	h := &Handbook{Generated: time.Now(), Errors: map[string]string{}, ConfigPaths: system.TrackedConfigPaths}
+	if info, err := system.NewDetector().GetSystemInfo(); err == nil {
+		h.Hostname = info.Hostname
+		h.System = strings.TrimSpace(fmt.Sprintf("%s %s (%s, kernel %s)", info.Distribution, info.Version, info.Arch, info.Kernel))
+	}
+	h.Software = installedSoftware()
+
+	nm := system.NewNginxManager()
+	if sites, err := nm.GetAllSites(); err == nil {
+		for _, site := range sites {
+			h.Sites = append(h.Sites, HandbookSite{Site: site, Stack: system.DiscoverSiteStack(site).Nodes})
+		}
+	} else {
+		h.Errors["sites"] = err.Error()
+	}
+
+	if patterns, err := system.LoadSystemdPatterns(); err == nil {
+		if h.Services, err = system.ListSystemdUnits(patterns); err != nil {
+			h.Errors["services"] = err.Error()
+		}
+	} else {
+		h.Errors["services"] = err.Error()
+	}
+	if sm := system.NewSupervisorManager(); sm.IsInstalled() {
+		if programs, err := sm.GetAllPrograms(); err == nil {
+			h.Workers = programs
+		} else {
+			h.Errors["workers"] = err.Error()
+		}
+	}
+
+	h.Databases = databases()
+	h.Backups = backupJobs()
+
+	fm := system.NewFirewallManager()
+	h.Firewall = fm.GetFirewallType()
+	h.FirewallOn, _ = fm.GetStatus()
+	if h.Firewall != system.FirewallNone {
+		if rules, err := fm.GetRules(); err == nil {
+			h.Rules = rules
+		} else {
+			h.Errors["firewall"] = err.Error()
+		}
+	}
+	h.SSHPort = system.SSHPort()
+	return h, nil
+}
+
+// versionCommands report the version of common components
+var versionCommands = []struct {
+	name string
+	args []string
+}{
+	{"MySQL", []string{"mysql", "--version"}},
+	{"PostgreSQL", []string{"psql", "--version"}},
+	{"Redis", []string{"redis-server", "--version"}},
+	{"Node.js", []string{"node", "--version"}},
+	{"Composer", []string{"composer", "--version", "--no-ansi"}},
+	{"Supervisor", []string{"supervisord", "--version"}},
+	{"FrankenPHP", []string{"frankenphp", "version"}},
+}
+
+// installedSoftware returns the versions of the components found on the host
+func installedSoftware() []Software {
+	var software []Software
+	if build, err := system.NewNginxManager().DetectNginxBuild(); err == nil && build.Version != "" {
+		software = append(software, Software{Name: "nginx", Version: build.Version})
+	}
+	if entries, err := system.ReadDir("/etc/php"); err == nil {
+		var versions []string
+		for _, e := range entries {
+			if e.IsDir() {
+				versions = append(versions, e.Name())
+			}
+		}
+		if len(versions) > 0 {
+			software = append(software, Software{Name: "PHP", Version: strings.Join(versions, ", ")})
+		}
+	}
+	for _, vc := range versionCommands {
+		output, err := system.Command(vc.args[0], vc.args[1:]...).Output()
+		if err != nil {
+			continue
+		}
+		if line := strings.TrimSpace(strings.SplitN(string(output), "\n", 2)[0]); line != "" {
+			software = append(software, Software{Name: vc.name, Version: line})
+		}
+	}
+	return software
+}
+
+// databases describes the database servers installed on the host
+func databases() []HandbookDatabase {
+	var dbs []HandbookDatabase
+	if mm := system.NewMySQLManager(); mm.IsInstalled() {
+		db := HandbookDatabase{Engine: "MySQL"}
+		if config, err := mm.GetConfig(); err == nil {
+			db.Port, db.Bind = fmt.Sprint(config.Port), config.BindAddress
+		}
+		db.Databases, _ = mm.ListDatabases()
+		dbs = append(dbs, db)
+	}
+	if pm := system.NewPostgreSQLManager(); pm.IsInstalled() {
+		db := HandbookDatabase{Engine: "PostgreSQL"}
+		if config, err := pm.GetConfig(); err == nil {
+			db.Port = fmt.Sprint(config.Port)
+		}
+		db.Databases, _ = pm.ListDatabases()
+		dbs = append(dbs, db)
+	}
+	if _, err := system.Command("which", "redis-server").Output(); err == nil {
+		db := HandbookDatabase{Engine: "Redis"}
+		if config, err := system.NewRedisManager().GetConfig(); err == nil {
+			db.Port = config.Port
+		}
+		dbs = append(dbs, db)
+	}
+	return dbs
+}
+
+// backupJobs returns the timers and cron entries that look like backups
+func backupJobs() []ScheduledJob {
+	var jobs []ScheduledJob
+	if output, err := system.Command("systemctl", "list-timers", "--all", "--no-legend", "--plain", "--no-pager", "*backup*").Output(); err == nil {
+		for _, timer := range parseTimerUnits(string(output)) {
+			show, _ := system.Command("systemctl", "show", timer, "-p", "TimersCalendar", "-p", "Unit", "--no-pager").Output()
+			job := parseTimerShow(string(show))
+			job.Source = timer
+			jobs = append(jobs, job)
+		}
+	}
+
+	crontabs := system.Crontabs()
+	paths := make([]string, 0, len(crontabs))
+	for file := range crontabs {
+		paths = append(paths, file)
+	}
+	sort.Strings(paths)
+	for _, file := range paths {
+		for _, job := range parseBackupCron(crontabs[file]) {
+			job.Source = file
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// parseTimerUnits returns the timer units listed by `systemctl list-timers`
+func parseTimerUnits(output string) []string {
+	var timers []string
+	for _, line := range strings.Split(output, "\n") {
+		for _, field := range strings.Fields(line) {
+			if strings.HasSuffix(field, ".timer") {
+				timers = append(timers, field)
+				break
+			}
+		}
+	}
+	return timers
+}
+
+var onCalendarPattern = regexp.MustCompile(`OnCalendar=([^;]+?)\s*;`)
+
+// parseTimerShow reads the schedule and service from `systemctl show`
+func parseTimerShow(output string) ScheduledJob {
+	var job ScheduledJob
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Unit":
+			job.Command = value
+		case "TimersCalendar":
+			if m := onCalendarPattern.FindStringSubmatch(value); m != nil {
+				job.Schedule = m[1]
+			}
+		}
+	}
+	return job
+}
+
+// parseBackupCron returns the crontab entries that mention backups
+func parseBackupCron(content string) []ScheduledJob {
+	var jobs []ScheduledJob
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || !strings.Contains(strings.ToLower(line), "backup") {
+			continue
+		}
+		fields := strings.Fields(line)
+		switch {
+		case strings.HasPrefix(fields[0], "@") && len(fields) > 1:
+			jobs = append(jobs, ScheduledJob{Schedule: fields[0], Command: strings.Join(fields[1:], " ")})
+		case len(fields) >= 6 && !strings.Contains(fields[0], "="):
+			jobs = append(jobs, ScheduledJob{Schedule: strings.Join(fields[:5], " "), Command: strings.Join(fields[5:], " ")})
+		}
+	}
+	return jobs
+}
+
+// Markdown renders the handbook
+func (h *Handbook) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Server handbook: %s\n\n", h.Hostname)
+	fmt.Fprintf(&b, "Generated by ravact on %s. Regenerate with `ravact report handbook`; earlier versions are kept in the configuration history.\n\n", h.Generated.Format("2006-01-02"))
+
+	b.WriteString("## Overview\n\n")
+	if h.System != "" {
+		fmt.Fprintf(&b, "- System: %s\n", h.System)
+	}
+	fmt.Fprintf(&b, "- Sites: %d\n", len(h.Sites))
+	fmt.Fprintf(&b, "- SSH port: %s\n", h.SSHPort)
+	for _, s := range h.Software {
+		fmt.Fprintf(&b, "- %s: %s\n", s.Name, s.Version)
+	}
+
+	b.WriteString("\n## Sites\n\n")
+	if len(h.Sites) == 0 {
+		b.WriteString("No nginx sites.\n")
+	}
+	for _, hs := range h.Sites {
+		site := hs.Site
+		state := "enabled"
+		if !site.IsEnabled {
+			state = "disabled"
+		}
+		tls := "HTTP only"
+		if site.HasSSL {
+			tls = "HTTPS"
+		}
+		fmt.Fprintf(&b, "### %s\n\n", site.Name)
+		fmt.Fprintf(&b, "- Domains: %s\n", strings.Join(site.Domains, ", "))
+		fmt.Fprintf(&b, "- Status: %s, %s\n", state, tls)
+		if site.RootDir != "" {
+			fmt.Fprintf(&b, "- Root: `%s`\n", site.RootDir)
+		}
+		fmt.Fprintf(&b, "- Config: `%s`\n", site.ConfigPath)
+		for _, layer := range system.StackLayers {
+			var names []string
+			for _, n := range hs.Stack {
+				if n.Layer != layer || layer == system.StackLayerWeb {
+					continue
+				}
+				name := n.Name
+				if n.Detail != "" && n.Detail != "local" {
+					name += " (" + n.Detail + ")"
+				}
+				names = append(names, name)
+			}
+			if len(names) > 0 {
+				fmt.Fprintf(&b, "- %s: %s\n", layer, strings.Join(names, ", "))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Services\n\n")
+	if len(h.Services) == 0 {
+		b.WriteString("No managed services found.\n")
+	} else {
+		b.WriteString("| Unit | State | Boot | Description |\n|---|---|---|---|\n")
+		for _, u := range h.Services {
+			boot := "manual"
+			if u.Enabled() {
+				boot = "enabled"
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", u.Name, u.Active, boot, u.Description)
+		}
+	}
+	if len(h.Workers) > 0 {
+		b.WriteString("\n### Supervisor programs\n\n| Program | State | User | Command |\n|---|---|---|---|\n")
+		for _, p := range h.Workers {
+			fmt.Fprintf(&b, "| `%s` | %s | %s | `%s` |\n", p.Name, p.State, p.User, p.Command)
+		}
+	}
+
+	b.WriteString("\n## Databases\n\n")
+	if len(h.Databases) == 0 {
+		b.WriteString("No database servers installed.\n")
+	}
+	for _, db := range h.Databases {
+		line := "- " + db.Engine
+		if db.Port != "" {
+			line += " on port " + db.Port
+		}
+		if db.Bind != "" {
+			line += ", bound to " + db.Bind
+		}
+		if len(db.Databases) > 0 {
+			line += ": " + strings.Join(db.Databases, ", ")
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n## Backups\n\n")
+	if len(h.Backups) == 0 {
+		b.WriteString("No backup timers or cron jobs found.\n")
+	}
+	for _, job := range h.Backups {
+		fmt.Fprintf(&b, "- `%s` from `%s`: %s\n", job.Schedule, job.Source, job.Command)
+	}
+
+	b.WriteString("\n## Firewall\n\n")
+	if h.Firewall == system.FirewallNone {
+		b.WriteString("No firewall installed.\n")
+	} else {
+		fmt.Fprintf(&b, "%s is %s.\n", h.Firewall, h.FirewallOn)
+		if len(h.Rules) > 0 {
+			b.WriteString("\n| Port | Protocol | Action | From |\n|---|---|---|---|\n")
+			for _, r := range h.Rules {
+				from := r.From
+				if r.V6 {
+					from += " (v6)"
+				}
+				fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", r.Port, r.Protocol, r.Action, from)
+			}
+		}
+	}
+
+	b.WriteString("\n## Configuration history\n\nThese paths are snapshotted after every change made through ravact:\n\n")
+	for _, p := range h.ConfigPaths {
+		fmt.Fprintf(&b, "- `%s`\n", p)
+	}
+
+	if len(h.Errors) > 0 {
+		b.WriteString("\n## Not collected\n\n")
+		for _, section := range []string{"sites", "services", "workers", "firewall"} {
+			if msg, ok := h.Errors[section]; ok {
+				fmt.Fprintf(&b, "- %s: %s\n", section, msg)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Save writes the handbook to HandbookPath and records it in the
+// configuration history. It reports whether the handbook changed.
+func (h *Handbook) Save() (bool, error) {
+	if err := system.MkdirAll(path.Dir(HandbookPath), 0755); err != nil {
+		return false, fmt.Errorf("failed to create %s: %w", path.Dir(HandbookPath), err)
+	}
+	if err := system.WriteFile(HandbookPath, []byte(h.Markdown()), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", HandbookPath, err)
+	}
+	changed, err := system.SnapshotConfig("Regenerate server handbook")
+	if err != nil {
+		return false, fmt.Errorf("wrote %s but could not record it in the configuration history: %w", HandbookPath, err)
+	}
+	return changed, nil
+}
@@ -0,0 +1,86 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/system"
+)
+
+func TestParseTimerUnits(t *testing.T) {
+	output := `Tue 2024-05-07 02:00:00 UTC 5h left Mon 2024-05-06 02:00:00 UTC 18h ago db-backup.timer db-backup.service
+n/a n/a n/a n/a site-backup.timer site-backup.service
+`
+	timers := parseTimerUnits(output)
+	if len(timers) != 2 || timers[0] != "db-backup.timer" || timers[1] != "site-backup.timer" {
+		t.Errorf("unexpected timers: %v", timers)
+	}
+}
+
+func TestParseTimerShow(t *testing.T) {
+	job := parseTimerShow("Unit=db-backup.service\nTimersCalendar={ OnCalendar=*-*-* 02:00:00 ; next_elapse=Tue 2024-05-07 02:00:00 UTC }\n")
+	if job.Command != "db-backup.service" || job.Schedule != "*-*-* 02:00:00" {
+		t.Errorf("unexpected job: %+v", job)
+	}
+}
+
+func TestParseBackupCron(t *testing.T) {
+	content := `SHELL=/bin/sh
+# 0 1 * * * root /usr/local/bin/backup-old
+30 3 * * * root /usr/local/bin/db-backup.sh --all
+@daily /usr/local/bin/Backup-files
+* * * * * www-data php /var/www/app/artisan schedule:run
+`
+	jobs := parseBackupCron(content)
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %+v", jobs)
+	}
+	if jobs[0].Schedule != "30 3 * * *" || jobs[0].Command != "root /usr/local/bin/db-backup.sh --all" {
+		t.Errorf("unexpected first job: %+v", jobs[0])
+	}
+	if jobs[1].Schedule != "@daily" {
+		t.Errorf("unexpected second job: %+v", jobs[1])
+	}
+}
+
+func TestHandbookMarkdown(t *testing.T) {
+	h := &Handbook{
+		Hostname:  "web-1",
+		Generated: time.Date(2024, 5, 8, 9, 0, 0, 0, time.UTC),
+		SSHPort:   "22",
+		Software:  []Software{{Name: "nginx", Version: "1.24.0"}},
+		Sites: []HandbookSite{{
+			Site: system.NginxSite{Name: "shop", Domains: []string{"shop.example.com", "www.shop.example.com"}, IsEnabled: true, HasSSL: true, RootDir: "/var/www/shop/public"},
+			Stack: []system.StackNode{
+				{Layer: system.StackLayerWeb, Name: "nginx"},
+				{Layer: system.StackLayerApp, Name: "php8.3-fpm", Detail: "PHP-FPM"},
+				{Layer: system.StackLayerData, Name: "mysql", Detail: "local"},
+			},
+		}},
+		Databases:  []HandbookDatabase{{Engine: "MySQL", Port: "3306", Databases: []string{"shop"}}},
+		Firewall:   system.FirewallUFW,
+		FirewallOn: "active",
+		Rules:      []system.FirewallRule{{Port: "443", Protocol: "tcp", Action: "allow", From: "Anywhere"}},
+		Errors:     map[string]string{},
+	}
+	md := h.Markdown()
+	for _, want := range []string{
+		"# Server handbook: web-1",
+		"### shop",
+		"- Domains: shop.example.com, www.shop.example.com",
+		"- Status: enabled, HTTPS",
+		"php8.3-fpm (PHP-FPM)",
+		"- MySQL on port 3306: shop",
+		"No backup timers or cron jobs found.",
+		"ufw is active.",
+		"| 443 | tcp | allow | Anywhere |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("handbook missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "Not collected") {
+		t.Error("unexpected Not collected section")
+	}
+}
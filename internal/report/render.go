@@ -0,0 +1,154 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/system"
+)
+
+// Subject returns the email subject for the digest
+func (d *Digest) Subject() string {
+	return fmt.Sprintf("[ravact] Weekly report for %s (%s - %s)", d.Hostname, d.From.Format("Jan 2"), d.To.Format("Jan 2"))
+}
+
+// diskGrowth describes the change in disk usage since the previous report
+func (d *Digest) diskGrowth() string {
+	if d.DiskPrevAt.IsZero() {
+		return "no earlier report to compare"
+	}
+	since := d.DiskPrevAt.Format("2006-01-02")
+	if d.DiskUsed >= d.DiskPrevUsed {
+		return fmt.Sprintf("+%s since %s", system.FormatBytes(d.DiskUsed-d.DiskPrevUsed), since)
+	}
+	return fmt.Sprintf("-%s since %s", system.FormatBytes(d.DiskPrevUsed-d.DiskUsed), since)
+}
+
+// diskPercent returns the used share of the root filesystem
+func (d *Digest) diskPercent() float64 {
+	if d.DiskTotal == 0 {
+		return 0
+	}
+	return float64(d.DiskUsed) / float64(d.DiskTotal) * 100
+}
+
+// formatUptime renders an uptime as days and hours
+func formatUptime(u time.Duration) string {
+	days := int(u.Hours()) / 24
+	hours := int(u.Hours()) % 24
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+	return fmt.Sprintf("%dh %dm", hours, int(u.Minutes())%60)
+}
+
+// daysLeft renders the time until a certificate expires
+func daysLeft(c Certificate, now time.Time) string {
+	days := int(c.NotAfter.Sub(now).Hours() / 24)
+	if days < 0 {
+		return "expired"
+	}
+	return fmt.Sprintf("%d days left", days)
+}
+
+// Markdown renders the digest as Markdown
+func (d *Digest) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Weekly report: %s\n\n", d.Hostname)
+	fmt.Fprintf(&b, "%s to %s\n\n", d.From.Format("2006-01-02"), d.To.Format("2006-01-02"))
+
+	b.WriteString("## Summary\n\n")
+	fmt.Fprintf(&b, "- Uptime: %s\n", formatUptime(d.Uptime))
+	fmt.Fprintf(&b, "- Incidents: %d\n", len(d.Incidents))
+	fmt.Fprintf(&b, "- Deploys: %d\n", len(d.Deploys))
+	fmt.Fprintf(&b, "- Certificates renewed: %d\n", len(d.Renewed))
+	if d.DiskTotal > 0 {
+		fmt.Fprintf(&b, "- Disk: %s of %s (%.0f%%), %s\n",
+			system.FormatBytes(d.DiskUsed), system.FormatBytes(d.DiskTotal), d.diskPercent(), d.diskGrowth())
+	}
+	fmt.Fprintf(&b, "- Pending updates: %d (%d security)\n", d.Updates, d.SecurityUpdates)
+
+	b.WriteString("\n## Incidents\n\n")
+	if len(d.Incidents) == 0 {
+		b.WriteString("No service failures.\n")
+	}
+	for _, i := range d.Incidents {
+		fmt.Fprintf(&b, "- %s `%s`: %s\n", i.Time.Local().Format("Mon 15:04"), i.Unit, i.Message)
+	}
+
+	b.WriteString("\n## Deploys\n\n")
+	if len(d.Deploys) == 0 {
+		b.WriteString("No configuration changes.\n")
+	}
+	for _, dep := range d.Deploys {
+		fmt.Fprintf(&b, "- %s %s\n", dep.Time.Local().Format("Mon 15:04"), dep.Description)
+	}
+
+	b.WriteString("\n## Certificates\n\n")
+	if len(d.Renewed) == 0 && len(d.Expiring) == 0 {
+		b.WriteString("Nothing renewed or expiring.\n")
+	}
+	for _, c := range d.Renewed {
+		fmt.Fprintf(&b, "- Renewed `%s`, valid until %s\n", c.Name, c.NotAfter.Format("2006-01-02"))
+	}
+	for _, c := range d.Expiring {
+		fmt.Fprintf(&b, "- **Expiring** `%s` on %s (%s)\n", c.Name, c.NotAfter.Format("2006-01-02"), daysLeft(c, d.To))
+	}
+
+	if len(d.Errors) > 0 {
+		b.WriteString("\n## Not collected\n\n")
+		for _, section := range []string{"health", "incidents", "deploys"} {
+			if msg, ok := d.Errors[section]; ok {
+				fmt.Fprintf(&b, "- %s: %s\n", section, msg)
+			}
+		}
+	}
+	return b.String()
+}
+
+// htmlTemplate renders the digest for email clients
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"bytes":  system.FormatBytes,
+	"uptime": formatUptime,
+	"when":   func(t time.Time) string { return t.Local().Format("Mon 15:04") },
+	"date":   func(t time.Time) string { return t.Format("2006-01-02") },
+}).Parse(`<!DOCTYPE html>
+<html><body style="font-family: sans-serif; max-width: 640px">
+<h1>Weekly report: {{.Hostname}}</h1>
+<p>{{date .From}} to {{date .To}}</p>
+<h2>Summary</h2>
+<ul>
+<li>Uptime: {{uptime .Uptime}}</li>
+<li>Incidents: {{len .Incidents}}</li>
+<li>Deploys: {{len .Deploys}}</li>
+<li>Certificates renewed: {{len .Renewed}}</li>
+{{if .DiskTotal}}<li>Disk: {{bytes .DiskUsed}} of {{bytes .DiskTotal}}, {{.Growth}}</li>{{end}}
+<li>Pending updates: {{.Updates}} ({{.SecurityUpdates}} security)</li>
+</ul>
+<h2>Incidents</h2>
+{{if .Incidents}}<ul>{{range .Incidents}}<li>{{when .Time}} <code>{{.Unit}}</code>: {{.Message}}</li>{{end}}</ul>{{else}}<p>No service failures.</p>{{end}}
+<h2>Deploys</h2>
+{{if .Deploys}}<ul>{{range .Deploys}}<li>{{when .Time}} {{.Description}}</li>{{end}}</ul>{{else}}<p>No configuration changes.</p>{{end}}
+<h2>Certificates</h2>
+{{if or .Renewed .Expiring}}<ul>
+{{range .Renewed}}<li>Renewed <code>{{.Name}}</code>, valid until {{date .NotAfter}}</li>{{end}}
+{{range .Expiring}}<li><strong>Expiring</strong> <code>{{.Name}}</code> on {{date .NotAfter}}</li>{{end}}
+</ul>{{else}}<p>Nothing renewed or expiring.</p>{{end}}
+</body></html>
+`))
+
+// HTML renders the digest as an HTML document
+func (d *Digest) HTML() (string, error) {
+	var buf bytes.Buffer
+	data := struct {
+		*Digest
+		Growth string
+	}{d, d.diskGrowth()}
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
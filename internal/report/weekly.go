@@ -0,0 +1,231 @@
+// Package report compiles periodic digests of what happened on a server
+package report
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/system"
+)
+
+// StatePath keeps the disk usage recorded by the previous report, so the
+// next one can show growth
+var StatePath = "/var/lib/ravact/report-state.json"
+
+// LetsEncryptLiveDir holds the certificates issued by certbot
+var LetsEncryptLiveDir = "/etc/letsencrypt/live"
+
+// certExpiryWarning is how far ahead expiring certificates are reported
+const certExpiryWarning = 21 * 24 * time.Hour
+
+// Incident is a service failure recorded by systemd
+type Incident struct {
+	Time    time.Time
+	Unit    string
+	Message string
+}
+
+// Deploy is a task that changed the configuration
+type Deploy struct {
+	Time        time.Time
+	Description string
+}
+
+// Certificate is a TLS certificate managed by certbot
+type Certificate struct {
+	Name      string
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// Digest is the compiled report for a period
+type Digest struct {
+	Hostname string
+	From     time.Time
+	To       time.Time
+
+	Uptime    time.Duration
+	Incidents []Incident
+	Deploys   []Deploy
+	Renewed   []Certificate // Issued during the period
+	Expiring  []Certificate // Expiring within three weeks
+
+	DiskTotal    uint64
+	DiskUsed     uint64
+	DiskPrevUsed uint64
+	DiskPrevAt   time.Time // Zero when there is no earlier report
+
+	Updates         int
+	SecurityUpdates int
+
+	// Sections that could not be collected, by section name
+	Errors map[string]string
+}
+
+// state is persisted between reports
+type state struct {
+	DiskUsed uint64    `json:"disk_used"`
+	At       time.Time `json:"at"`
+}
+
+// Weekly compiles the digest for the last seven days
+func Weekly() (*Digest, error) {
+	to := time.Now()
+	return Collect(to.Add(-7*24*time.Hour), to)
+}
+
+// Collect compiles the digest for a period on the active host
+func Collect(from, to time.Time) (*Digest, error) {
+	if system.HostOS() != "linux" {
+		return nil, fmt.Errorf("reports are only available on Linux (current OS: %s)", system.HostOS())
+	}
+
+	d := &Digest{From: from, To: to, Errors: map[string]string{}}
+	d.Hostname, _ = system.HostName()
+
+	if snap, err := system.NewHealthMonitor().Collect(); err == nil {
+		d.Uptime = snap.Uptime
+		d.DiskTotal, d.DiskUsed = snap.DiskTotal, snap.DiskUsed
+	} else {
+		d.Errors["health"] = err.Error()
+	}
+
+	if output, err := system.Command("journalctl", "--since", "@"+fmt.Sprint(from.Unix()),
+		"--no-pager", "-o", "short-iso", "_PID=1").Output(); err == nil {
+		d.Incidents = parseIncidents(string(output))
+	} else {
+		d.Errors["incidents"] = "journalctl is not available"
+	}
+
+	if commits, err := system.ConfigHistory(500); err == nil {
+		for _, c := range commits {
+			if c.Date.After(from) && !c.Date.After(to) {
+				d.Deploys = append(d.Deploys, Deploy{Time: c.Date, Description: c.Subject})
+			}
+		}
+	} else {
+		d.Errors["deploys"] = err.Error()
+	}
+
+	d.Renewed, d.Expiring = certificates(from, to)
+
+	d.Updates, d.SecurityUpdates, _ = pendingUpdates()
+
+	if prev, err := loadState(); err == nil {
+		d.DiskPrevUsed, d.DiskPrevAt = prev.DiskUsed, prev.At
+	}
+	return d, nil
+}
+
+// SaveState records the current disk usage for the next report
+func (d *Digest) SaveState() error {
+	data, err := json.Marshal(state{DiskUsed: d.DiskUsed, At: d.To})
+	if err != nil {
+		return err
+	}
+	if err := system.MkdirAll(filepath.Dir(StatePath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(StatePath), err)
+	}
+	return system.WriteFile(StatePath, data, 0644)
+}
+
+// loadState reads the state saved by the previous report
+func loadState() (state, error) {
+	var s state
+	data, err := system.ReadFile(StatePath)
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+// incidentPattern matches systemd failure messages such as
+// "2024-05-01T10:00:00+0000 web-1 systemd[1]: nginx.service: Failed with result 'exit-code'."
+var incidentPattern = regexp.MustCompile(`^(\S+) \S+ systemd\[1\]: (\S+?\.service): (.*(?:Failed with result|Main process exited, code=(?:killed|dumped)|Start request repeated too quickly).*)$`)
+
+// parseIncidents extracts service failures from journal output
+func parseIncidents(output string) []Incident {
+	var incidents []Incident
+	for _, line := range strings.Split(output, "\n") {
+		match := incidentPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		t, _ := time.Parse("2006-01-02T15:04:05-0700", match[1])
+		incidents = append(incidents, Incident{
+			Time:    t,
+			Unit:    strings.TrimSuffix(match[2], ".service"),
+			Message: strings.TrimSuffix(match[3], "."),
+		})
+	}
+	return incidents
+}
+
+// certificates returns the certbot certificates issued during the period and
+// those expiring soon
+func certificates(from, to time.Time) (renewed, expiring []Certificate) {
+	entries, err := system.ReadDir(LetsEncryptLiveDir)
+	if err != nil {
+		return nil, nil
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := system.ReadFile(filepath.Join(LetsEncryptLiveDir, entry.Name(), "cert.pem"))
+		if err != nil {
+			continue
+		}
+		cert, err := parseCertificate(entry.Name(), data)
+		if err != nil {
+			continue
+		}
+		if cert.NotBefore.After(from) && !cert.NotBefore.After(to) {
+			renewed = append(renewed, cert)
+		}
+		if cert.NotAfter.Sub(to) < certExpiryWarning {
+			expiring = append(expiring, cert)
+		}
+	}
+	sort.Slice(expiring, func(i, j int) bool { return expiring[i].NotAfter.Before(expiring[j].NotAfter) })
+	return renewed, expiring
+}
+
+// parseCertificate reads the validity of a PEM certificate
+func parseCertificate(name string, data []byte) (Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Certificate{}, fmt.Errorf("no PEM data")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return Certificate{}, err
+	}
+	return Certificate{Name: name, NotBefore: cert.NotBefore, NotAfter: cert.NotAfter}, nil
+}
+
+// pendingUpdates counts the packages with available upgrades
+func pendingUpdates() (total, security int, err error) {
+	updater, err := system.DetectPackageUpdater()
+	if err != nil {
+		return 0, 0, err
+	}
+	updates, err := updater.PendingUpdates()
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, u := range updates {
+		if u.Security {
+			security++
+		}
+	}
+	return len(updates), security, nil
+}
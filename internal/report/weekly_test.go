@@ -0,0 +1,78 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseIncidents(t *testing.T) {
+	output := `2024-05-01T10:00:00+0000 web-1 systemd[1]: Started nginx.service - A high performance web server.
+2024-05-01T10:05:12+0000 web-1 systemd[1]: php8.3-fpm.service: Failed with result 'exit-code'.
+2024-05-02T03:00:00+0000 web-1 systemd[1]: queue-worker.service: Main process exited, code=killed, status=9/KILL
+2024-05-02T03:00:00+0000 web-1 systemd[1]: queue-worker.service: Scheduled restart job, restart counter is at 1.
+2024-05-02T03:00:01+0000 web-1 sshd[812]: nginx.service: Failed with result 'exit-code'.
+`
+	incidents := parseIncidents(output)
+	if len(incidents) != 2 {
+		t.Fatalf("expected 2 incidents, got %d: %+v", len(incidents), incidents)
+	}
+	if incidents[0].Unit != "php8.3-fpm" || incidents[0].Message != "Failed with result 'exit-code'" {
+		t.Errorf("unexpected first incident: %+v", incidents[0])
+	}
+	if incidents[0].Time.Day() != 1 || incidents[0].Time.Minute() != 5 {
+		t.Errorf("unexpected time: %v", incidents[0].Time)
+	}
+	if incidents[1].Unit != "queue-worker" {
+		t.Errorf("unexpected second unit: %s", incidents[1].Unit)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	to := time.Date(2024, 5, 8, 9, 0, 0, 0, time.UTC)
+	d := &Digest{
+		Hostname:     "web-1",
+		From:         to.Add(-7 * 24 * time.Hour),
+		To:           to,
+		Uptime:       50 * time.Hour,
+		Incidents:    []Incident{{Time: to.Add(-time.Hour), Unit: "nginx", Message: "Failed with result 'exit-code'"}},
+		Deploys:      []Deploy{{Time: to.Add(-2 * time.Hour), Description: "Deploy example.com"}},
+		Expiring:     []Certificate{{Name: "example.com", NotAfter: to.Add(10 * 24 * time.Hour)}},
+		DiskTotal:    100 << 30,
+		DiskUsed:     40 << 30,
+		DiskPrevUsed: 38 << 30,
+		DiskPrevAt:   to.Add(-7 * 24 * time.Hour),
+		Updates:      4,
+	}
+
+	md := d.Markdown()
+	for _, want := range []string{
+		"# Weekly report: web-1",
+		"- Uptime: 2d 2h",
+		"- Incidents: 1",
+		"`nginx`: Failed with result 'exit-code'",
+		"Deploy example.com",
+		"**Expiring** `example.com` on 2024-05-18 (10 days left)",
+		"(40%), +2.0 GB since 2024-05-01",
+		"- Pending updates: 4 (0 security)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	html, err := d.HTML()
+	if err != nil {
+		t.Fatalf("HTML: %v", err)
+	}
+	if !strings.Contains(html, "<code>nginx</code>: Failed with result &#39;exit-code&#39;") {
+		t.Errorf("unexpected HTML:\n%s", html)
+	}
+}
+
+func TestDiskGrowthWithoutPreviousReport(t *testing.T) {
+	d := &Digest{DiskUsed: 10}
+	if got := d.diskGrowth(); got != "no earlier report to compare" {
+		t.Errorf("unexpected growth: %s", got)
+	}
+}
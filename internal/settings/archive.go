@@ -0,0 +1,290 @@
+package settings
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/vault"
+)
+
+// FormatVersion is the archive layout written by this build. Bump it when the
+// layout or the location of a settings file changes, and register a migration
+// from the previous version so older archives still import.
+const FormatVersion = 1
+
+// ArchiveExtension is the file extension used for exported settings
+const ArchiveExtension = ".ravact"
+
+var (
+	// UserDir holds per-user settings (servers, vault, ...), normally ~/.ravact
+	UserDir string
+	// SystemDir holds server-wide metadata (site notes, tags, ...)
+	SystemDir = "/etc/ravact"
+)
+
+// userExcludes are entries under UserDir that are not worth carrying over
+var userExcludes = map[string]bool{
+	"forms": true, // Unfinished form input
+}
+
+// Scope identifies which settings directory a file belongs to
+type Scope string
+
+const (
+	ScopeUser   Scope = "user"
+	ScopeSystem Scope = "system"
+	ScopeState  Scope = "state" // The metadata store in system.StateDir
+)
+
+// File is one settings file in an archive
+type File struct {
+	Scope Scope       `json:"scope"`
+	Path  string      `json:"path"` // Relative to the scope's directory
+	Mode  fs.FileMode `json:"mode"`
+	Data  []byte      `json:"data"`
+}
+
+// Archive is the decrypted content of a settings export
+type Archive struct {
+	FormatVersion int       `json:"format_version"`
+	RavactVersion string    `json:"ravact_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Hostname      string    `json:"hostname"`
+	Files         []File    `json:"files"`
+}
+
+// migrations upgrade an archive from the keyed format version to the next one
+var migrations = map[int]func(*Archive) error{}
+
+// Collect gathers ravact's settings from UserDir and SystemDir
+func Collect(ravactVersion string) (*Archive, error) {
+	a := &Archive{
+		FormatVersion: FormatVersion,
+		RavactVersion: ravactVersion,
+		CreatedAt:     time.Now().UTC(),
+	}
+	a.Hostname, _ = system.HostName()
+
+	if UserDir != "" {
+		if err := collectDir(a, ScopeUser, UserDir, "", os.ReadDir, os.ReadFile); err != nil {
+			return nil, err
+		}
+	}
+	// Server-wide metadata lives on the managed host
+	if err := collectDir(a, ScopeSystem, SystemDir, "", system.ReadDir, system.ReadFile); err != nil {
+		return nil, err
+	}
+	// Only the store itself; StateDir also holds vendored downloads
+	if data, err := system.ReadFile(system.StorePath()); err == nil {
+		a.Files = append(a.Files, File{Scope: ScopeState, Path: system.StoreFile, Mode: 0640, Data: data})
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", system.StorePath(), err)
+	}
+
+	sort.Slice(a.Files, func(i, j int) bool {
+		if a.Files[i].Scope != a.Files[j].Scope {
+			return a.Files[i].Scope < a.Files[j].Scope
+		}
+		return a.Files[i].Path < a.Files[j].Path
+	})
+	return a, nil
+}
+
+// collectDir adds the regular files below root/rel to the archive
+func collectDir(a *Archive, scope Scope, root, rel string,
+	readDir func(string) ([]os.DirEntry, error), readFile func(string) ([]byte, error)) error {
+	entries, err := readDir(filepath.Join(root, rel))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Join(root, rel), err)
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(rel, entry.Name())
+		if scope == ScopeUser && rel == "" && userExcludes[entry.Name()] {
+			continue
+		}
+		if entry.IsDir() {
+			if err := collectDir(a, scope, root, path, readDir, readFile); err != nil {
+				return err
+			}
+			continue
+		}
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		data, err := readFile(filepath.Join(root, path))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", filepath.Join(root, path), err)
+		}
+		mode := fs.FileMode(0644)
+		if info, err := entry.Info(); err == nil {
+			mode = info.Mode().Perm()
+		}
+		a.Files = append(a.Files, File{Scope: scope, Path: filepath.ToSlash(path), Mode: mode, Data: data})
+	}
+	return nil
+}
+
+// Encrypt compresses and encrypts the archive with a passphrase
+func (a *Archive) Encrypt(passphrase string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(a); err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress settings: %w", err)
+	}
+	return vault.Seal(passphrase, buf.Bytes())
+}
+
+// Export collects the current settings and writes an encrypted archive to path
+func Export(path, passphrase, ravactVersion string) (*Archive, error) {
+	a, err := Collect(ravactVersion)
+	if err != nil {
+		return nil, err
+	}
+	data, err := a.Encrypt(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return a, nil
+}
+
+// Open decrypts an exported archive and migrates it to the current format
+func Open(path, passphrase string) (*Archive, error) {
+	sealed, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	compressed, err := vault.Unseal(passphrase, sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress settings: %w", err)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress settings: %w", err)
+	}
+
+	a := &Archive{}
+	if err := json.Unmarshal(raw, a); err != nil {
+		return nil, fmt.Errorf("failed to parse settings: %w", err)
+	}
+	if err := a.migrate(); err != nil {
+		return nil, err
+	}
+	for _, f := range a.Files {
+		if f.Scope != ScopeUser && f.Scope != ScopeSystem {
+			return nil, fmt.Errorf("unknown scope %q for %s", f.Scope, f.Path)
+		}
+		if !filepath.IsLocal(filepath.FromSlash(f.Path)) {
+			return nil, fmt.Errorf("refusing to import unsafe path %q", f.Path)
+		}
+	}
+	return a, nil
+}
+
+// migrate upgrades an archive written by an older ravact
+func (a *Archive) migrate() error {
+	if a.FormatVersion > FormatVersion {
+		return fmt.Errorf("archive format %d was created by a newer ravact (%s); this build supports up to %d",
+			a.FormatVersion, a.RavactVersion, FormatVersion)
+	}
+	for a.FormatVersion < FormatVersion {
+		migration, ok := migrations[a.FormatVersion]
+		if !ok {
+			return fmt.Errorf("no migration from archive format %d", a.FormatVersion)
+		}
+		if err := migration(a); err != nil {
+			return fmt.Errorf("failed to migrate archive from format %d: %w", a.FormatVersion, err)
+		}
+		a.FormatVersion++
+	}
+	return nil
+}
+
+// Count returns the number of files in each scope
+func (a *Archive) Count(scope Scope) int {
+	n := 0
+	for _, f := range a.Files {
+		if f.Scope == scope {
+			n++
+		}
+	}
+	return n
+}
+
+// Apply writes the archive's files into UserDir, SystemDir, and the
+// metadata store, replacing existing files with the same name. It returns
+// the paths written.
+func (a *Archive) Apply() ([]string, error) {
+	var written []string
+	legacyTags := false
+	for _, f := range a.Files {
+		mkdirAll, writeFile := os.MkdirAll, os.WriteFile
+		root := UserDir
+		switch f.Scope {
+		case ScopeSystem:
+			mkdirAll, writeFile = system.MkdirAll, system.WriteFile
+			root = SystemDir
+			legacyTags = legacyTags || f.Path == filepath.Base(system.TagStorePath)
+		case ScopeState:
+			mkdirAll, writeFile = system.MkdirAll, system.WriteFile
+			root = system.StateDir
+		}
+		if root == "" {
+			continue
+		}
+
+		dest := filepath.Join(root, filepath.FromSlash(f.Path))
+		if err := mkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return written, fmt.Errorf("failed to create %s: %w", filepath.Dir(dest), err)
+		}
+		mode := f.Mode
+		if mode == 0 {
+			mode = 0644
+		}
+		if err := writeFile(dest, f.Data, mode); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", dest, err)
+		}
+		written = append(written, dest)
+	}
+	// Archives from before the metadata store carry tags.yaml
+	if legacyTags {
+		if err := system.ImportLegacyTags(); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// DefaultExportPath returns a timestamped archive name in the user's home directory
+func DefaultExportPath() string {
+	name := "ravact-settings-" + time.Now().Format("20060102-150405") + ArchiveExtension
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return name
+	}
+	return filepath.Join(home, name)
+}
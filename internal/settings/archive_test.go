@@ -0,0 +1,111 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/vault"
+)
+
+// useDirs points the settings directories at temporary locations
+func useDirs(t *testing.T) (userDir, systemDir string) {
+	t.Helper()
+	origUser, origSystem := UserDir, SystemDir
+	origState, origTags := system.StateDir, system.TagStorePath
+	t.Cleanup(func() {
+		UserDir, SystemDir = origUser, origSystem
+		system.StateDir, system.TagStorePath = origState, origTags
+	})
+
+	UserDir = filepath.Join(t.TempDir(), ".ravact")
+	SystemDir = filepath.Join(t.TempDir(), "etc-ravact")
+	system.StateDir = filepath.Join(t.TempDir(), "var-lib-ravact")
+	system.TagStorePath = filepath.Join(SystemDir, "tags.yaml")
+	return UserDir, SystemDir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	userDir, systemDir := useDirs(t)
+	writeFile(t, filepath.Join(userDir, "servers.yaml"), "servers: []\n")
+	writeFile(t, filepath.Join(userDir, "forms", "add_site.json"), "{}")
+	writeFile(t, filepath.Join(systemDir, "sites", "shop", "notes.md"), "# Shop\n")
+	writeFile(t, filepath.Join(systemDir, "tags.yaml"), "tags:\n  sites:\n    shop: [production]\n")
+
+	archivePath := filepath.Join(t.TempDir(), "export"+ArchiveExtension)
+	exported, err := Export(archivePath, "correct horse", "1.0.0")
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if exported.Count(ScopeUser) != 1 || exported.Count(ScopeSystem) != 2 {
+		t.Fatalf("unexpected file counts: user=%d system=%d", exported.Count(ScopeUser), exported.Count(ScopeSystem))
+	}
+
+	if _, err := Open(archivePath, "wrong"); err != vault.ErrWrongPassphrase {
+		t.Errorf("expected ErrWrongPassphrase, got %v", err)
+	}
+
+	// Import onto a fresh machine
+	newUser, newSystem := useDirs(t)
+	a, err := Open(archivePath, "correct horse")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	written, err := a.Apply()
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(written) != 3 {
+		t.Errorf("expected 3 files written, got %v", written)
+	}
+
+	data, err := os.ReadFile(filepath.Join(newSystem, "sites", "shop", "notes.md"))
+	if err != nil || string(data) != "# Shop\n" {
+		t.Errorf("notes not restored: %q (%v)", data, err)
+	}
+	// tags.yaml from before the metadata store is imported into it
+	tags, err := system.LoadTagStore()
+	if err != nil || !tags.Has(system.TagKindSite, "shop", "production") {
+		t.Errorf("legacy tags not imported into the store: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(newUser, "servers.yaml"))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("servers.yaml not restored with its mode: %v (%v)", info, err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	a := &Archive{FormatVersion: FormatVersion + 1, RavactVersion: "9.9.9"}
+	if err := a.migrate(); err == nil || !strings.Contains(err.Error(), "newer ravact") {
+		t.Errorf("expected newer format to be rejected, got %v", err)
+	}
+
+	// An archive one version behind is upgraded by the registered migration
+	migrations[FormatVersion-1] = func(a *Archive) error {
+		for i := range a.Files {
+			a.Files[i].Path = strings.Replace(a.Files[i].Path, "old/", "new/", 1)
+		}
+		return nil
+	}
+	defer delete(migrations, FormatVersion-1)
+
+	a = &Archive{FormatVersion: FormatVersion - 1, Files: []File{{Scope: ScopeSystem, Path: "old/tags.yaml"}}}
+	if err := a.migrate(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if a.FormatVersion != FormatVersion || a.Files[0].Path != "new/tags.yaml" {
+		t.Errorf("archive not migrated: %+v", a)
+	}
+}
@@ -0,0 +1,134 @@
+package settings
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PreferencesFile is the name of the preferences file inside UserDir
+const PreferencesFile = "preferences.yaml"
+
+const (
+	pinIter       = 200000
+	pinSaltLength = 16
+	pinKeyLength  = 32
+	minPINLength  = 4
+)
+
+// Preferences are per-user options changed from the Settings screen
+type Preferences struct {
+	// IdleLockMinutes locks the screen after this many minutes without
+	// input; 0 disables the lock
+	IdleLockMinutes int `yaml:"idle_lock_minutes"`
+	// PINHash unlocks the screen instead of the user's password when set
+	PINHash string `yaml:"pin_hash,omitempty"`
+}
+
+// PreferencesPath returns the location of the preferences file
+func PreferencesPath() string {
+	return filepath.Join(UserDir, PreferencesFile)
+}
+
+// LoadPreferences reads the preferences, returning defaults if none are saved
+func LoadPreferences() (Preferences, error) {
+	var p Preferences
+	if UserDir == "" {
+		return p, nil
+	}
+	data, err := os.ReadFile(PreferencesPath())
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return p, fmt.Errorf("failed to read preferences: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("failed to parse %s: %w", PreferencesPath(), err)
+	}
+	return p, nil
+}
+
+// Save writes the preferences; the file holds the PIN hash so it is
+// readable only by the user
+func (p Preferences) Save() error {
+	if UserDir == "" {
+		return fmt.Errorf("no settings directory")
+	}
+	if p.IdleLockMinutes < 0 {
+		return fmt.Errorf("idle lock minutes cannot be negative")
+	}
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode preferences: %w", err)
+	}
+	if err := os.MkdirAll(UserDir, 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", UserDir, err)
+	}
+	if err := os.WriteFile(PreferencesPath(), data, 0600); err != nil {
+		return fmt.Errorf("failed to write preferences: %w", err)
+	}
+	return nil
+}
+
+// HasPIN reports whether an unlock PIN is configured
+func (p Preferences) HasPIN() bool {
+	return p.PINHash != ""
+}
+
+// SetPIN stores a salted hash of the PIN; an empty PIN removes it
+func (p *Preferences) SetPIN(pin string) error {
+	if pin == "" {
+		p.PINHash = ""
+		return nil
+	}
+	if len(pin) < minPINLength {
+		return fmt.Errorf("PIN must be at least %d characters", minPINLength)
+	}
+	salt := make([]byte, pinSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key, err := pbkdf2.Key(sha256.New, pin, salt, pinIter, pinKeyLength)
+	if err != nil {
+		return fmt.Errorf("failed to hash PIN: %w", err)
+	}
+	enc := base64.RawStdEncoding
+	p.PINHash = fmt.Sprintf("pbkdf2-sha256$%d$%s$%s", pinIter, enc.EncodeToString(salt), enc.EncodeToString(key))
+	return nil
+}
+
+// CheckPIN reports whether pin matches the stored hash
+func (p Preferences) CheckPIN(pin string) bool {
+	parts := strings.Split(p.PINHash, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return false
+	}
+	iter, err := strconv.Atoi(parts[1])
+	if err != nil || iter <= 0 {
+		return false
+	}
+	enc := base64.RawStdEncoding
+	salt, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	want, err := enc.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+	got, err := pbkdf2.Key(sha256.New, pin, salt, iter, len(want))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
@@ -0,0 +1,70 @@
+package settings
+
+import (
+	"os"
+	"testing"
+)
+
+func TestPreferencesRoundTrip(t *testing.T) {
+	useDirs(t)
+
+	p, err := LoadPreferences()
+	if err != nil {
+		t.Fatalf("LoadPreferences: %v", err)
+	}
+	if p.IdleLockMinutes != 0 || p.HasPIN() {
+		t.Fatalf("expected empty defaults, got %+v", p)
+	}
+
+	p.IdleLockMinutes = 15
+	if err := p.SetPIN("4821"); err != nil {
+		t.Fatalf("SetPIN: %v", err)
+	}
+	if err := p.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	info, err := os.Stat(PreferencesPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("preferences mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	loaded, err := LoadPreferences()
+	if err != nil {
+		t.Fatalf("LoadPreferences: %v", err)
+	}
+	if loaded.IdleLockMinutes != 15 {
+		t.Errorf("IdleLockMinutes = %d, want 15", loaded.IdleLockMinutes)
+	}
+	if !loaded.CheckPIN("4821") {
+		t.Error("CheckPIN rejected the correct PIN")
+	}
+	if loaded.CheckPIN("4822") {
+		t.Error("CheckPIN accepted a wrong PIN")
+	}
+}
+
+func TestSetPIN(t *testing.T) {
+	var p Preferences
+	if err := p.SetPIN("12"); err == nil {
+		t.Error("expected an error for a short PIN")
+	}
+	if err := p.SetPIN("1234"); err != nil {
+		t.Fatal(err)
+	}
+	first := p.PINHash
+	if err := p.SetPIN("1234"); err != nil {
+		t.Fatal(err)
+	}
+	if p.PINHash == first {
+		t.Error("expected a fresh salt for each PIN")
+	}
+	if err := p.SetPIN(""); err != nil || p.HasPIN() {
+		t.Errorf("expected an empty PIN to remove it, got %q, %v", p.PINHash, err)
+	}
+	if p.CheckPIN("") {
+		t.Error("CheckPIN accepted a PIN with none set")
+	}
+}
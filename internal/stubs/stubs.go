@@ -2,23 +2,85 @@ package stubs
 
 import (
 	"embed"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 )
 
 //go:embed templates/*.stub
 var templatesFS embed.FS
 
-// LoadAndReplace loads a stub from the embedded filesystem and replaces placeholders
-func LoadAndReplace(name string, replacements map[string]string) (string, error) {
+// OverrideDir holds user stubs. A file here replaces the built-in stub of
+// the same name, and new names add to the built-in ones.
+var OverrideDir = "/etc/ravact/stubs"
+
+// ReadFile and ReadDir read OverrideDir. main points them at the active
+// host so overrides on a managed server apply.
+var (
+	ReadFile = os.ReadFile
+	ReadDir  = os.ReadDir
+)
+
+// Load returns a stub, preferring a user override
+func Load(name string) (string, error) {
+	if content, err := ReadFile(OverridePath(name)); err == nil {
+		return string(content), nil
+	}
 	content, err := templatesFS.ReadFile("templates/" + name + ".stub")
 	if err != nil {
 		return "", err
 	}
+	return string(content), nil
+}
+
+// LoadAndReplace loads a stub, preferring a user override, and replaces
+// {{KEY}} placeholders
+func LoadAndReplace(name string, replacements map[string]string) (string, error) {
+	result, err := Load(name)
+	if err != nil {
+		return "", err
+	}
 
-	result := string(content)
 	for key, value := range replacements {
 		result = strings.ReplaceAll(result, "{{"+key+"}}", value)
 	}
 
 	return result, nil
 }
+
+// OverridePath returns where a user override of a stub lives
+func OverridePath(name string) string {
+	return filepath.Join(OverrideDir, name+".stub")
+}
+
+// IsOverridden reports whether a user stub replaces or adds name
+func IsOverridden(name string) bool {
+	_, err := ReadFile(OverridePath(name))
+	return err == nil
+}
+
+// IsBuiltIn reports whether ravact ships a stub called name
+func IsBuiltIn(name string) bool {
+	_, err := templatesFS.ReadFile("templates/" + name + ".stub")
+	return err == nil
+}
+
+// Names returns the built-in and user stub names that start with prefix
+func Names(prefix string) []string {
+	seen := map[string]bool{}
+	entries, _ := templatesFS.ReadDir("templates")
+	if user, err := ReadDir(OverrideDir); err == nil {
+		entries = append(entries, user...)
+	}
+	var names []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".stub")
+		if ok && strings.HasPrefix(name, prefix) && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,57 @@
+package stubs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func withOverrideDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	old := OverrideDir
+	OverrideDir = dir
+	t.Cleanup(func() { OverrideDir = old })
+	return dir
+}
+
+func TestLoadPrefersOverride(t *testing.T) {
+	dir := withOverrideDir(t)
+
+	builtIn, err := Load("nginx-site-laravel")
+	if err != nil || !strings.Contains(builtIn, "Laravel Configuration") {
+		t.Fatalf("expected the built-in stub, got %q, %v", builtIn, err)
+	}
+	if IsOverridden("nginx-site-laravel") {
+		t.Error("expected no override yet")
+	}
+
+	os.WriteFile(filepath.Join(dir, "nginx-site-laravel.stub"), []byte("    # ours {{DOMAIN}}\n"), 0644)
+	got, err := LoadAndReplace("nginx-site-laravel", map[string]string{"DOMAIN": "example.com"})
+	if err != nil || got != "    # ours example.com\n" {
+		t.Errorf("expected the override, got %q, %v", got, err)
+	}
+	if !IsOverridden("nginx-site-laravel") || !IsBuiltIn("nginx-site-laravel") {
+		t.Error("expected an overridden built-in stub")
+	}
+}
+
+func TestNames(t *testing.T) {
+	dir := withOverrideDir(t)
+	os.WriteFile(filepath.Join(dir, "nginx-site-statamic.stub"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(dir, "nginx-site-php.stub"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0644)
+
+	want := []string{
+		"nginx-site-laravel", "nginx-site-nodejs", "nginx-site-php", "nginx-site-proxy",
+		"nginx-site-spa", "nginx-site-statamic", "nginx-site-static", "nginx-site-symfony", "nginx-site-wordpress",
+	}
+	if got := Names("nginx-site-"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if IsBuiltIn("nginx-site-statamic") {
+		t.Error("a user stub is not built in")
+	}
+}
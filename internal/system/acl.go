@@ -0,0 +1,130 @@
+package system
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ACLEntry is one POSIX ACL entry as printed by getfacl
+type ACLEntry struct {
+	Default   bool   // Inherited by new files and directories
+	Tag       string // "user", "group", "mask", or "other"
+	Qualifier string // User or group name; empty for the owner entries
+	Perms     string // e.g. "rwx" or "r-x"
+}
+
+// String formats the entry the way setfacl accepts it
+func (e ACLEntry) String() string {
+	s := e.Tag + ":" + e.Qualifier + ":" + e.Perms
+	if e.Default {
+		s = "default:" + s
+	}
+	return s
+}
+
+// Extended reports whether the entry goes beyond the owner/group/other mode
+// bits, meaning it was added with setfacl
+func (e ACLEntry) Extended() bool {
+	return e.Qualifier != "" || e.Tag == "mask" || e.Default
+}
+
+// aclFilesystems support POSIX ACLs unless mounted with noacl
+var aclFilesystems = map[string]bool{
+	"ext2": true, "ext3": true, "ext4": true, "xfs": true, "btrfs": true,
+	"tmpfs": true, "f2fs": true, "jfs": true, "reiserfs": true,
+}
+
+// aclMountSupport decides from findmnt's filesystem type and mount options
+// whether ACLs can be set, with the reason when they cannot
+func aclMountSupport(fstype, options string) (bool, string) {
+	opts := map[string]bool{}
+	for _, o := range strings.Split(options, ",") {
+		opts[o] = true
+	}
+	switch {
+	case opts["noacl"]:
+		return false, fstype + " is mounted with noacl"
+	case fstype == "zfs":
+		if opts["posixacl"] {
+			return true, ""
+		}
+		return false, "zfs needs acltype=posixacl for POSIX ACLs"
+	case aclFilesystems[fstype]:
+		return true, ""
+	}
+	return false, fstype + " does not support POSIX ACLs"
+}
+
+// ACLSupport reports whether POSIX ACLs can be used on dir: setfacl must be
+// installed and the filesystem must support them. The reason explains a
+// false result.
+func ACLSupport(dir string) (bool, string) {
+	if _, err := Command("which", "setfacl").Output(); err != nil {
+		return false, "setfacl is not installed (apt install acl)"
+	}
+	output, err := Command("findmnt", "-n", "-o", "FSTYPE,OPTIONS", "-T", dir).Output()
+	if err != nil {
+		return false, fmt.Sprintf("failed to find the filesystem of %s", dir)
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) < 2 {
+		return false, fmt.Sprintf("failed to find the filesystem of %s", dir)
+	}
+	return aclMountSupport(fields[0], fields[1])
+}
+
+// ParseGetfacl reads the entries from getfacl output, skipping the
+// comment header and any effective-permission notes
+func ParseGetfacl(output string) []ACLEntry {
+	var entries []ACLEntry
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			line = line[:i] // Drops "#effective:r-x"
+		}
+		var e ACLEntry
+		if rest, ok := strings.CutPrefix(line, "default:"); ok {
+			e.Default = true
+			line = rest
+		}
+		parts := strings.Split(line, ":")
+		if len(parts) != 3 {
+			continue
+		}
+		e.Tag, e.Qualifier, e.Perms = parts[0], parts[1], parts[2]
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// GetACL returns the ACL entries of a file or directory
+func GetACL(path string) ([]ACLEntry, error) {
+	output, err := Command("getfacl", "--absolute-names", "--", path).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("getfacl failed: %v - %s", err, strings.TrimSpace(string(output)))
+	}
+	return ParseGetfacl(string(output)), nil
+}
+
+// WritableACLCommand returns the setfacl commands granting users rwX on
+// dirs, both on what exists now and as default entries inherited by
+// anything created later. Unlike chgrp and chmod, the owner and group of
+// the files are left alone.
+func WritableACLCommand(users []string, dirs ...string) string {
+	var grants []string
+	for _, u := range users {
+		if u != "" {
+			grants = append(grants, "u:"+u+":rwX")
+		}
+	}
+	spec := ShellQuote(strings.Join(grants, ","))
+	quoted := make([]string, len(dirs))
+	for i, d := range dirs {
+		quoted[i] = ShellQuote(d)
+	}
+	targets := strings.Join(quoted, " ")
+	return fmt.Sprintf("setfacl -R -m %s %s && find %s -type d -exec setfacl -d -m %s {} +", spec, targets, targets, spec)
+}
@@ -0,0 +1,72 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseGetfacl(t *testing.T) {
+	output := `# file: /var/www/app/storage
+# owner: deploy
+# group: deploy
+# flags: -s-
+user::rwx
+user:www-data:rwx	#effective:r-x
+group::r-x
+mask::r-x
+other::r-x
+default:user::rwx
+default:user:www-data:rwx
+default:group::r-x
+default:mask::rwx
+default:other::r-x
+`
+	entries := ParseGetfacl(output)
+	if len(entries) != 10 {
+		t.Fatalf("expected 10 entries, got %d: %+v", len(entries), entries)
+	}
+	if want := (ACLEntry{Tag: "user", Qualifier: "www-data", Perms: "rwx"}); entries[1] != want {
+		t.Errorf("got %+v, want %+v", entries[1], want)
+	}
+	if got := entries[6].String(); got != "default:user:www-data:rwx" {
+		t.Errorf("unexpected default entry %q", got)
+	}
+
+	var extended []string
+	for _, e := range entries {
+		if e.Extended() {
+			extended = append(extended, e.String())
+		}
+	}
+	want := []string{"user:www-data:rwx", "mask::r-x", "default:user::rwx", "default:user:www-data:rwx", "default:group::r-x", "default:mask::rwx", "default:other::r-x"}
+	if !reflect.DeepEqual(extended, want) {
+		t.Errorf("extended entries = %v, want %v", extended, want)
+	}
+}
+
+func TestACLMountSupport(t *testing.T) {
+	cases := []struct {
+		fstype, options string
+		ok              bool
+	}{
+		{"ext4", "rw,relatime", true},
+		{"ext4", "rw,noacl", false},
+		{"xfs", "rw,noatime,attr2,inode64", true},
+		{"zfs", "rw,xattr,noacl", false},
+		{"zfs", "rw,xattr,posixacl", true},
+		{"vfat", "rw,relatime,fmask=0022", false},
+	}
+	for _, c := range cases {
+		if ok, reason := aclMountSupport(c.fstype, c.options); ok != c.ok || (!ok && reason == "") {
+			t.Errorf("aclMountSupport(%q, %q) = %v, %q", c.fstype, c.options, ok, reason)
+		}
+	}
+}
+
+func TestWritableACLCommand(t *testing.T) {
+	got := WritableACLCommand([]string{"www-data", "", "deploy"}, "storage", "bootstrap/cache")
+	want := "setfacl -R -m u:www-data:rwX,u:deploy:rwX storage bootstrap/cache && find storage bootstrap/cache -type d -exec setfacl -d -m u:www-data:rwX,u:deploy:rwX {} +"
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
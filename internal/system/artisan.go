@@ -0,0 +1,311 @@
+package system
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maintenanceSecretPattern keeps the bypass secret usable as a URL path
+var maintenanceSecretPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ArtisanArgument is a positional argument of an artisan command
+type ArtisanArgument struct {
+	Name        string
+	Required    bool
+	IsArray     bool // Takes several space-separated values
+	Description string
+	Default     string
+}
+
+// ArtisanOption is a --option of an artisan command
+type ArtisanOption struct {
+	Name          string // Without the leading --
+	Shortcut      string
+	AcceptValue   bool // false for flags such as --force
+	ValueRequired bool
+	IsMultiple    bool // May be given several times
+	Description   string
+	Default       string
+}
+
+// ArtisanCommand is one command from `php artisan list --format=json`
+type ArtisanCommand struct {
+	Name        string
+	Description string
+	Usage       string
+	Arguments   []ArtisanArgument
+	Options     []ArtisanOption
+}
+
+// artisanGlobalOptions are the Symfony console options every command
+// repeats; they are not worth a form field
+var artisanGlobalOptions = map[string]bool{
+	"help": true, "quiet": true, "verbose": true, "version": true,
+	"ansi": true, "no-ansi": true, "no-interaction": true, "env": true,
+}
+
+// ParseArtisanList parses `php artisan list --format=json`, dropping hidden
+// commands and the global console options
+func ParseArtisanList(data []byte) ([]ArtisanCommand, error) {
+	start := bytes.IndexByte(data, '{')
+	if start < 0 {
+		return nil, fmt.Errorf("unexpected artisan output: %s", strings.TrimSpace(string(data)))
+	}
+	var raw struct {
+		Commands []struct {
+			Name        string   `json:"name"`
+			Hidden      bool     `json:"hidden"`
+			Usage       []string `json:"usage"`
+			Description string   `json:"description"`
+			Definition  struct {
+				Arguments json.RawMessage `json:"arguments"`
+				Options   json.RawMessage `json:"options"`
+			} `json:"definition"`
+		} `json:"commands"`
+	}
+	if err := json.NewDecoder(bytes.NewReader(data[start:])).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse artisan output: %w", err)
+	}
+
+	var commands []ArtisanCommand
+	for _, c := range raw.Commands {
+		if c.Hidden {
+			continue
+		}
+		cmd := ArtisanCommand{Name: c.Name, Description: c.Description}
+		if len(c.Usage) > 0 {
+			cmd.Usage = c.Usage[0]
+		}
+
+		// PHP encodes an empty definition as [] rather than {}
+		var args map[string]struct {
+			Name        string          `json:"name"`
+			IsRequired  bool            `json:"is_required"`
+			IsArray     bool            `json:"is_array"`
+			Description string          `json:"description"`
+			Default     json.RawMessage `json:"default"`
+		}
+		if isJSONObject(c.Definition.Arguments) {
+			if err := json.Unmarshal(c.Definition.Arguments, &args); err != nil {
+				return nil, fmt.Errorf("failed to parse arguments of %s: %w", c.Name, err)
+			}
+		}
+		var opts map[string]struct {
+			Name            string          `json:"name"`
+			Shortcut        string          `json:"shortcut"`
+			AcceptValue     bool            `json:"accept_value"`
+			IsValueRequired bool            `json:"is_value_required"`
+			IsMultiple      bool            `json:"is_multiple"`
+			Description     string          `json:"description"`
+			Default         json.RawMessage `json:"default"`
+		}
+		if isJSONObject(c.Definition.Options) {
+			if err := json.Unmarshal(c.Definition.Options, &opts); err != nil {
+				return nil, fmt.Errorf("failed to parse options of %s: %w", c.Name, err)
+			}
+		}
+
+		// Arguments are positional, and JSON objects lose their order, so
+		// take it from the usage line
+		for _, a := range args {
+			cmd.Arguments = append(cmd.Arguments, ArtisanArgument{
+				Name:        a.Name,
+				Required:    a.IsRequired,
+				IsArray:     a.IsArray,
+				Description: a.Description,
+				Default:     jsonDefault(a.Default),
+			})
+		}
+		sort.SliceStable(cmd.Arguments, func(i, j int) bool {
+			return usageIndex(cmd.Usage, cmd.Arguments[i].Name) < usageIndex(cmd.Usage, cmd.Arguments[j].Name)
+		})
+		for key, o := range opts {
+			name := strings.TrimPrefix(o.Name, "--")
+			if name == "" {
+				name = key
+			}
+			if artisanGlobalOptions[name] {
+				continue
+			}
+			cmd.Options = append(cmd.Options, ArtisanOption{
+				Name:          name,
+				Shortcut:      o.Shortcut,
+				AcceptValue:   o.AcceptValue,
+				ValueRequired: o.IsValueRequired,
+				IsMultiple:    o.IsMultiple,
+				Description:   o.Description,
+				Default:       jsonDefault(o.Default),
+			})
+		}
+		sort.Slice(cmd.Options, func(i, j int) bool { return cmd.Options[i].Name < cmd.Options[j].Name })
+		commands = append(commands, cmd)
+	}
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name < commands[j].Name })
+	return commands, nil
+}
+
+// usageIndex is where <name> appears in a usage line, or after everything
+func usageIndex(usage, name string) int {
+	if i := strings.Index(usage, "<"+name+">"); i >= 0 {
+		return i
+	}
+	return len(usage)
+}
+
+// jsonDefault renders a default value for display and prefilling; null,
+// false, and empty arrays have none
+func jsonDefault(raw json.RawMessage) string {
+	var v interface{}
+	if json.Unmarshal(raw, &v) != nil {
+		return ""
+	}
+	switch d := v.(type) {
+	case string:
+		return d
+	case float64:
+		return fmt.Sprint(d)
+	case bool:
+		if d {
+			return "true"
+		}
+	case []interface{}:
+		parts := make([]string, len(d))
+		for i, p := range d {
+			parts[i] = fmt.Sprint(p)
+		}
+		return strings.Join(parts, ",")
+	}
+	return ""
+}
+
+// ArtisanArgs builds the artisan command line from form values keyed by
+// argument name and by "--"+option name. Array arguments split on spaces,
+// multiple-value options on commas, and flags are set by "true".
+func ArtisanArgs(cmd ArtisanCommand, values map[string]string) ([]string, error) {
+	args := []string{cmd.Name}
+	for _, a := range cmd.Arguments {
+		value := strings.TrimSpace(values[a.Name])
+		if value == "" {
+			if a.Required {
+				return nil, fmt.Errorf("argument %s is required", a.Name)
+			}
+			continue
+		}
+		if a.IsArray {
+			args = append(args, strings.Fields(value)...)
+		} else {
+			args = append(args, value)
+		}
+	}
+	for _, o := range cmd.Options {
+		value := strings.TrimSpace(values["--"+o.Name])
+		if !o.AcceptValue {
+			if value == "true" {
+				args = append(args, "--"+o.Name)
+			}
+			continue
+		}
+		if value == "" {
+			continue
+		}
+		if o.IsMultiple {
+			for _, v := range strings.Split(value, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					args = append(args, "--"+o.Name+"="+v)
+				}
+			}
+		} else {
+			args = append(args, "--"+o.Name+"="+value)
+		}
+	}
+	return args, nil
+}
+
+// artisanShell is the shell command that runs artisan in dir with php
+func artisanShell(dir, php string, args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = ShellQuote(arg)
+	}
+	return fmt.Sprintf("cd %s && %s artisan %s", ShellQuote(dir), ShellQuote(php), strings.Join(quoted, " "))
+}
+
+// ListArtisanCommands lists the commands of the Laravel project in dir,
+// running php as user when one is given
+func ListArtisanCommands(dir, user, php string) ([]ArtisanCommand, error) {
+	script := artisanShell(dir, php, "list", "--format=json", "--no-ansi")
+	cmd := Command("bash", "-c", script)
+	if user != "" {
+		cmd = Command("sudo", "-u", user, "-H", "bash", "-c", script)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("php artisan list failed: %s", strings.TrimSpace(stderr.String()+" "+err.Error()))
+	}
+	return ParseArtisanList(out)
+}
+
+// ArtisanScript returns the shell command that runs artisan args in dir as
+// the site's user, who must not be root
+func ArtisanScript(dir, user, php string, args []string) (string, error) {
+	if !usernamePattern.MatchString(user) || user == "root" {
+		return "", fmt.Errorf("choose the site's system user to run artisan as (got %q)", user)
+	}
+	return AsUser(user, artisanShell(dir, php, append(args, "--no-interaction")...)), nil
+}
+
+// LaravelMaintenance is a Laravel app's maintenance mode as recorded in
+// storage/framework/down by `php artisan down`
+type LaravelMaintenance struct {
+	Down   bool
+	Secret string // Bypass path segment, if one was set
+	Status int    // HTTP status served while down
+}
+
+// IsLaravelApp reports whether dir holds a Laravel project
+func IsLaravelApp(dir string) bool {
+	_, err := Stat(filepath.Join(dir, "artisan"))
+	return err == nil
+}
+
+// LaravelMaintenanceState reads the maintenance state of the app in dir
+func LaravelMaintenanceState(dir string) LaravelMaintenance {
+	data, err := ReadFile(filepath.Join(dir, "storage", "framework", "down"))
+	if err != nil {
+		return LaravelMaintenance{}
+	}
+	state := LaravelMaintenance{Down: true}
+	var payload struct {
+		Secret *string `json:"secret"`
+		Status int     `json:"status"`
+	}
+	if json.Unmarshal(data, &payload) == nil {
+		if payload.Secret != nil {
+			state.Secret = *payload.Secret
+		}
+		state.Status = payload.Status
+	}
+	return state
+}
+
+// MaintenanceArgs returns the artisan arguments that take an app down, with
+// an optional bypass secret, or bring it back up
+func MaintenanceArgs(down bool, secret string) ([]string, error) {
+	if !down {
+		return []string{"up"}, nil
+	}
+	if secret == "" {
+		return []string{"down"}, nil
+	}
+	if !maintenanceSecretPattern.MatchString(secret) {
+		return nil, fmt.Errorf("secret may only contain letters, digits, - and _")
+	}
+	return []string{"down", "--secret=" + secret}, nil
+}
@@ -0,0 +1,128 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const artisanListJSON = `{
+    "application": {"name": "Laravel Framework", "version": "11.9.0"},
+    "commands": [
+        {
+            "name": "migrate",
+            "hidden": false,
+            "usage": ["migrate [--database [DATABASE]] [--force] [--path [PATH]]"],
+            "description": "Run the database migrations",
+            "definition": {
+                "arguments": [],
+                "options": {
+                    "help": {"name": "--help", "shortcut": "-h", "accept_value": false, "is_value_required": false, "is_multiple": false, "description": "Display help", "default": false},
+                    "database": {"name": "--database", "shortcut": "", "accept_value": true, "is_value_required": false, "is_multiple": false, "description": "The database connection to use", "default": null},
+                    "force": {"name": "--force", "shortcut": "", "accept_value": false, "is_value_required": false, "is_multiple": false, "description": "Force the operation to run when in production", "default": false},
+                    "path": {"name": "--path", "shortcut": "", "accept_value": true, "is_value_required": false, "is_multiple": true, "description": "The path(s) to the migrations files", "default": []}
+                }
+            }
+        },
+        {
+            "name": "make:model",
+            "hidden": false,
+            "usage": ["make:model <name> <extra>"],
+            "description": "Create a new Eloquent model class",
+            "definition": {
+                "arguments": {
+                    "extra": {"name": "extra", "is_required": false, "is_array": true, "description": "Extra", "default": []},
+                    "name": {"name": "name", "is_required": true, "is_array": false, "description": "The name of the model", "default": null}
+                },
+                "options": []
+            }
+        },
+        {"name": "_complete", "hidden": true, "usage": [], "description": "", "definition": {"arguments": [], "options": []}}
+    ],
+    "namespaces": []
+}`
+
+func TestParseArtisanList(t *testing.T) {
+	commands, err := ParseArtisanList([]byte("Deprecated: noise\n" + artisanListJSON))
+	if err != nil {
+		t.Fatalf("ParseArtisanList: %v", err)
+	}
+	if len(commands) != 2 || commands[0].Name != "make:model" || commands[1].Name != "migrate" {
+		t.Fatalf("expected make:model and migrate, got %+v", commands)
+	}
+
+	model := commands[0]
+	if len(model.Arguments) != 2 || model.Arguments[0].Name != "name" || !model.Arguments[0].Required {
+		t.Errorf("expected arguments in usage order, got %+v", model.Arguments)
+	}
+
+	migrate := commands[1]
+	if len(migrate.Options) != 3 {
+		t.Fatalf("expected global options dropped, got %+v", migrate.Options)
+	}
+	if o := migrate.Options[1]; o.Name != "force" || o.AcceptValue {
+		t.Errorf("expected --force flag, got %+v", o)
+	}
+}
+
+func TestArtisanArgs(t *testing.T) {
+	commands, _ := ParseArtisanList([]byte(artisanListJSON))
+	model, migrate := commands[0], commands[1]
+
+	if _, err := ArtisanArgs(model, map[string]string{}); err == nil {
+		t.Error("expected missing required argument to fail")
+	}
+	args, err := ArtisanArgs(model, map[string]string{"name": "Post", "extra": "a b"})
+	if err != nil || strings.Join(args, " ") != "make:model Post a b" {
+		t.Errorf("unexpected args %v (%v)", args, err)
+	}
+
+	args, _ = ArtisanArgs(migrate, map[string]string{"--force": "true", "--path": "db/a, db/b", "--database": ""})
+	if got := strings.Join(args, " "); got != "migrate --force --path=db/a --path=db/b" {
+		t.Errorf("unexpected args %q", got)
+	}
+}
+
+func TestArtisanScript(t *testing.T) {
+	if _, err := ArtisanScript("/var/www/app", "root", "php", []string{"migrate"}); err == nil {
+		t.Error("expected root to be refused")
+	}
+	script, err := ArtisanScript("/var/www/app", "deploy", "php8.3", []string{"db:seed", "--class=Some Seeder"})
+	if err != nil {
+		t.Fatalf("ArtisanScript: %v", err)
+	}
+	if !strings.HasPrefix(script, "sudo -u deploy -i bash -c ") || !strings.Contains(script, "php8.3 artisan db:seed") {
+		t.Errorf("unexpected script %q", script)
+	}
+}
+
+func TestLaravelMaintenanceState(t *testing.T) {
+	dir := t.TempDir()
+	if LaravelMaintenanceState(dir).Down {
+		t.Error("expected an app without a down file to be live")
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "storage", "framework"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	down := `{"except":[],"redirect":null,"retry":null,"refresh":null,"secret":"let-me-in","status":503,"template":null}`
+	if err := os.WriteFile(filepath.Join(dir, "storage", "framework", "down"), []byte(down), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state := LaravelMaintenanceState(dir)
+	if !state.Down || state.Secret != "let-me-in" || state.Status != 503 {
+		t.Errorf("unexpected state %+v", state)
+	}
+}
+
+func TestMaintenanceArgs(t *testing.T) {
+	if args, _ := MaintenanceArgs(false, "ignored"); strings.Join(args, " ") != "up" {
+		t.Errorf("unexpected up args %v", args)
+	}
+	if args, _ := MaintenanceArgs(true, "abc_123"); strings.Join(args, " ") != "down --secret=abc_123" {
+		t.Errorf("unexpected down args %v", args)
+	}
+	if _, err := MaintenanceArgs(true, "a/b"); err == nil {
+		t.Error("expected a secret with a slash to be refused")
+	}
+}
@@ -0,0 +1,268 @@
+package system
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AutoUpdateBackend is the tool that installs updates unattended
+type AutoUpdateBackend string
+
+const (
+	AutoUpdateApt AutoUpdateBackend = "unattended-upgrades"
+	AutoUpdateDnf AutoUpdateBackend = "dnf-automatic"
+)
+
+// Paths of the files generated for each backend
+const (
+	unattendedUpgradesPath = "/etc/apt/apt.conf.d/50unattended-upgrades"
+	autoUpgradesPath       = "/etc/apt/apt.conf.d/20auto-upgrades"
+	dnfAutomaticPath       = "/etc/dnf/automatic.conf"
+	dnfAutomaticTimerPath  = "/etc/systemd/system/dnf-automatic.timer.d/ravact.conf"
+)
+
+// ConfigFile is a generated configuration file
+type ConfigFile struct {
+	Path    string
+	Content string
+}
+
+// AutoUpdateSettings configure unattended updates
+type AutoUpdateSettings struct {
+	Categories string // "security" or "all"
+	Reboot     string // "never" or "when-needed"
+	Time       string // HH:MM to run dnf-automatic and to reboot
+	Email      string // Report recipient; empty disables email
+}
+
+// DefaultAutoUpdateSettings installs security updates only and never reboots
+func DefaultAutoUpdateSettings() AutoUpdateSettings {
+	return AutoUpdateSettings{Categories: "security", Reboot: "never", Time: "03:00"}
+}
+
+// DetectAutoUpdateBackend returns the backend for the host's package manager
+func DetectAutoUpdateBackend() (AutoUpdateBackend, error) {
+	if HostOS() != "linux" {
+		return "", fmt.Errorf("automatic updates are only available on Linux (current OS: %s)", HostOS())
+	}
+	if _, err := Command("which", "apt-get").Output(); err == nil {
+		return AutoUpdateApt, nil
+	}
+	if _, err := Command("which", "dnf").Output(); err == nil {
+		return AutoUpdateDnf, nil
+	}
+	return "", fmt.Errorf("no supported package manager found (apt or dnf)")
+}
+
+var updateTimePattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// Validate checks the settings before files are generated
+func (s AutoUpdateSettings) Validate() error {
+	if s.Categories != "security" && s.Categories != "all" {
+		return fmt.Errorf("categories must be security or all")
+	}
+	if s.Reboot != "never" && s.Reboot != "when-needed" {
+		return fmt.Errorf("reboot policy must be never or when-needed")
+	}
+	if !updateTimePattern.MatchString(s.Time) {
+		return fmt.Errorf("time must be HH:MM, e.g. 03:00")
+	}
+	if s.Email != "" && (!strings.Contains(s.Email, "@") || strings.ContainsAny(s.Email, " \"';\n")) {
+		return fmt.Errorf("invalid email address %q", s.Email)
+	}
+	return nil
+}
+
+// Files returns the configuration files for a backend
+func (s AutoUpdateSettings) Files(backend AutoUpdateBackend) []ConfigFile {
+	if backend == AutoUpdateDnf {
+		return s.dnfFiles()
+	}
+	return s.aptFiles()
+}
+
+// aptFiles generates the unattended-upgrades configuration
+func (s AutoUpdateSettings) aptFiles() []ConfigFile {
+	var b strings.Builder
+	b.WriteString("// Generated by ravact\n")
+	b.WriteString("Unattended-Upgrade::Allowed-Origins {\n")
+	b.WriteString("\t\"${distro_id}:${distro_codename}-security\";\n")
+	b.WriteString("\t\"${distro_id}ESMApps:${distro_codename}-apps-security\";\n")
+	b.WriteString("\t\"${distro_id}ESM:${distro_codename}-infra-security\";\n")
+	if s.Categories == "all" {
+		b.WriteString("\t\"${distro_id}:${distro_codename}\";\n")
+		b.WriteString("\t\"${distro_id}:${distro_codename}-updates\";\n")
+	}
+	b.WriteString("};\n\n")
+	b.WriteString("Unattended-Upgrade::Remove-Unused-Kernel-Packages \"true\";\n")
+	b.WriteString("Unattended-Upgrade::Remove-Unused-Dependencies \"true\";\n")
+	if s.Reboot == "when-needed" {
+		b.WriteString("Unattended-Upgrade::Automatic-Reboot \"true\";\n")
+		b.WriteString("Unattended-Upgrade::Automatic-Reboot-WithUsers \"true\";\n")
+		fmt.Fprintf(&b, "Unattended-Upgrade::Automatic-Reboot-Time \"%s\";\n", s.Time)
+	} else {
+		b.WriteString("Unattended-Upgrade::Automatic-Reboot \"false\";\n")
+	}
+	if s.Email != "" {
+		fmt.Fprintf(&b, "Unattended-Upgrade::Mail \"%s\";\n", s.Email)
+		b.WriteString("Unattended-Upgrade::MailReport \"on-change\";\n")
+	}
+
+	periodic := "// Generated by ravact\n" +
+		"APT::Periodic::Update-Package-Lists \"1\";\n" +
+		"APT::Periodic::Unattended-Upgrade \"1\";\n" +
+		"APT::Periodic::AutocleanInterval \"7\";\n"
+
+	return []ConfigFile{
+		{Path: unattendedUpgradesPath, Content: b.String()},
+		{Path: autoUpgradesPath, Content: periodic},
+	}
+}
+
+// dnfFiles generates the dnf-automatic configuration and a timer override
+// that runs it at the chosen time
+func (s AutoUpdateSettings) dnfFiles() []ConfigFile {
+	upgradeType := "security"
+	if s.Categories == "all" {
+		upgradeType = "default"
+	}
+	emit := "stdio"
+	if s.Email != "" {
+		emit = "email"
+	}
+
+	var b strings.Builder
+	b.WriteString("# Generated by ravact\n")
+	b.WriteString("[commands]\n")
+	fmt.Fprintf(&b, "upgrade_type = %s\n", upgradeType)
+	b.WriteString("random_sleep = 0\n")
+	b.WriteString("download_updates = yes\n")
+	b.WriteString("apply_updates = yes\n")
+	fmt.Fprintf(&b, "reboot = %s\n", s.Reboot)
+	b.WriteString("\n[emitters]\n")
+	fmt.Fprintf(&b, "emit_via = %s\n", emit)
+	if s.Email != "" {
+		b.WriteString("\n[email]\n")
+		b.WriteString("email_from = root\n")
+		fmt.Fprintf(&b, "email_to = %s\n", s.Email)
+		b.WriteString("email_host = localhost\n")
+	}
+	b.WriteString("\n[base]\n")
+	b.WriteString("debuglevel = 1\n")
+
+	timer := "# Generated by ravact\n" +
+		"[Timer]\n" +
+		"OnCalendar=\n" +
+		fmt.Sprintf("OnCalendar=*-*-* %s\n", s.Time) +
+		"RandomizedDelaySec=0\n"
+
+	return []ConfigFile{
+		{Path: dnfAutomaticPath, Content: b.String()},
+		{Path: dnfAutomaticTimerPath, Content: timer},
+	}
+}
+
+// AutoUpdateScript installs the backend, writes the files (keeping a
+// .bak of any existing file), enables the service, and shows a dry run
+func AutoUpdateScript(backend AutoUpdateBackend, files []ConfigFile) string {
+	var script strings.Builder
+	script.WriteString("set -e\n")
+	if backend == AutoUpdateDnf {
+		script.WriteString("sudo dnf install -y dnf-automatic\n")
+	} else {
+		script.WriteString("sudo DEBIAN_FRONTEND=noninteractive apt-get install -y unattended-upgrades\n")
+	}
+	for _, file := range files {
+		path := ShellQuote(file.Path)
+		fmt.Fprintf(&script, "sudo mkdir -p \"$(dirname %s)\"\n", path)
+		fmt.Fprintf(&script, "if [ -f %s ]; then sudo cp %s %s.bak; fi\n", path, path, path)
+		fmt.Fprintf(&script, "sudo tee %s > /dev/null <<'EOF'\n%sEOF\n", path, file.Content)
+		fmt.Fprintf(&script, "echo '✓ Wrote %s'\n", file.Path)
+	}
+	if backend == AutoUpdateDnf {
+		script.WriteString("sudo systemctl daemon-reload\n")
+		script.WriteString("sudo systemctl enable --now dnf-automatic.timer\n")
+		script.WriteString("systemctl list-timers dnf-automatic.timer --no-pager\n")
+	} else {
+		script.WriteString("sudo systemctl enable --now unattended-upgrades\n")
+		script.WriteString("echo; echo 'Dry run:'\n")
+		script.WriteString("sudo unattended-upgrade --dry-run 2>&1 | tail -n 20\n")
+	}
+	return script.String()
+}
+
+// AutoUpdateEnabled reports whether unattended updates are switched on
+func AutoUpdateEnabled(backend AutoUpdateBackend) bool {
+	if backend == AutoUpdateDnf {
+		output, _ := Command("systemctl", "is-enabled", "dnf-automatic.timer").Output()
+		return strings.TrimSpace(string(output)) == "enabled"
+	}
+	data, err := ReadFile(autoUpgradesPath)
+	return err == nil && regexp.MustCompile(`APT::Periodic::Unattended-Upgrade\s+"1"`).Match(data)
+}
+
+// CurrentAutoUpdateSettings reads the settings from the backend's files,
+// falling back to the defaults for anything not set
+func CurrentAutoUpdateSettings(backend AutoUpdateBackend) AutoUpdateSettings {
+	s := DefaultAutoUpdateSettings()
+	if backend == AutoUpdateDnf {
+		data, _ := ReadFile(dnfAutomaticPath)
+		timer, _ := ReadFile(dnfAutomaticTimerPath)
+		return parseDnfAutomatic(string(data), string(timer), s)
+	}
+	data, _ := ReadFile(unattendedUpgradesPath)
+	return parseUnattendedUpgrades(string(data), s)
+}
+
+var (
+	aptRebootPattern     = regexp.MustCompile(`(?m)^\s*Unattended-Upgrade::Automatic-Reboot\s+"(true|false)"`)
+	aptRebootTimePattern = regexp.MustCompile(`(?m)^\s*Unattended-Upgrade::Automatic-Reboot-Time\s+"([0-9:]+)"`)
+	aptMailPattern       = regexp.MustCompile(`(?m)^\s*Unattended-Upgrade::Mail\s+"([^"]*)"`)
+	aptUpdatesPattern    = regexp.MustCompile(`(?m)^\s*"[^"]*:\$\{distro_codename\}-updates"`)
+	dnfTimerPattern      = regexp.MustCompile(`(?m)^OnCalendar=\*-\*-\* (\d\d:\d\d)`)
+)
+
+// parseUnattendedUpgrades reads settings from 50unattended-upgrades
+func parseUnattendedUpgrades(content string, s AutoUpdateSettings) AutoUpdateSettings {
+	if aptUpdatesPattern.MatchString(content) {
+		s.Categories = "all"
+	}
+	if m := aptRebootPattern.FindStringSubmatch(content); m != nil && m[1] == "true" {
+		s.Reboot = "when-needed"
+	}
+	if m := aptRebootTimePattern.FindStringSubmatch(content); m != nil && updateTimePattern.MatchString(m[1]) {
+		s.Time = m[1]
+	}
+	if m := aptMailPattern.FindStringSubmatch(content); m != nil {
+		s.Email = m[1]
+	}
+	return s
+}
+
+// parseDnfAutomatic reads settings from automatic.conf and the timer override
+func parseDnfAutomatic(content, timer string, s AutoUpdateSettings) AutoUpdateSettings {
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "upgrade_type":
+			if value == "default" {
+				s.Categories = "all"
+			}
+		case "reboot":
+			if value == "when-needed" || value == "when-changed" {
+				s.Reboot = "when-needed"
+			}
+		case "email_to":
+			s.Email = value
+		}
+	}
+	if m := dnfTimerPattern.FindStringSubmatch(timer); m != nil {
+		s.Time = m[1]
+	}
+	return s
+}
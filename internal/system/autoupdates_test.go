@@ -0,0 +1,81 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAutoUpdateSettingsValidate(t *testing.T) {
+	if err := DefaultAutoUpdateSettings().Validate(); err != nil {
+		t.Errorf("defaults should be valid: %v", err)
+	}
+	for _, s := range []AutoUpdateSettings{
+		{Categories: "everything", Reboot: "never", Time: "03:00"},
+		{Categories: "security", Reboot: "always", Time: "03:00"},
+		{Categories: "security", Reboot: "never", Time: "25:00"},
+		{Categories: "security", Reboot: "never", Time: "03:00", Email: "ops\"@example.com"},
+	} {
+		if err := s.Validate(); err == nil {
+			t.Errorf("expected an error for %+v", s)
+		}
+	}
+}
+
+func TestAptFilesRoundTrip(t *testing.T) {
+	s := AutoUpdateSettings{Categories: "all", Reboot: "when-needed", Time: "04:30", Email: "ops@example.com"}
+	files := s.Files(AutoUpdateApt)
+	if len(files) != 2 || files[0].Path != unattendedUpgradesPath || files[1].Path != autoUpgradesPath {
+		t.Fatalf("unexpected files: %+v", files)
+	}
+	for _, want := range []string{
+		`"${distro_id}:${distro_codename}-security";`,
+		`"${distro_id}:${distro_codename}-updates";`,
+		`Unattended-Upgrade::Automatic-Reboot-Time "04:30";`,
+		`Unattended-Upgrade::Mail "ops@example.com";`,
+	} {
+		if !strings.Contains(files[0].Content, want) {
+			t.Errorf("missing %q in:\n%s", want, files[0].Content)
+		}
+	}
+	if got := parseUnattendedUpgrades(files[0].Content, DefaultAutoUpdateSettings()); got != s {
+		t.Errorf("round trip: got %+v, want %+v", got, s)
+	}
+
+	security := DefaultAutoUpdateSettings().Files(AutoUpdateApt)[0].Content
+	if strings.Contains(security, "-updates") || !strings.Contains(security, `Automatic-Reboot "false"`) {
+		t.Errorf("unexpected security-only config:\n%s", security)
+	}
+}
+
+func TestDnfFilesRoundTrip(t *testing.T) {
+	s := AutoUpdateSettings{Categories: "security", Reboot: "when-needed", Time: "02:15", Email: "ops@example.com"}
+	files := s.Files(AutoUpdateDnf)
+	if len(files) != 2 || files[0].Path != dnfAutomaticPath {
+		t.Fatalf("unexpected files: %+v", files)
+	}
+	for _, want := range []string{"upgrade_type = security", "reboot = when-needed", "emit_via = email", "email_to = ops@example.com"} {
+		if !strings.Contains(files[0].Content, want) {
+			t.Errorf("missing %q in:\n%s", want, files[0].Content)
+		}
+	}
+	if !strings.Contains(files[1].Content, "OnCalendar=*-*-* 02:15") {
+		t.Errorf("unexpected timer override:\n%s", files[1].Content)
+	}
+	if got := parseDnfAutomatic(files[0].Content, files[1].Content, DefaultAutoUpdateSettings()); got != s {
+		t.Errorf("round trip: got %+v, want %+v", got, s)
+	}
+}
+
+func TestAutoUpdateScript(t *testing.T) {
+	script := AutoUpdateScript(AutoUpdateApt, DefaultAutoUpdateSettings().Files(AutoUpdateApt))
+	for _, want := range []string{
+		"apt-get install -y unattended-upgrades",
+		"sudo cp /etc/apt/apt.conf.d/20auto-upgrades /etc/apt/apt.conf.d/20auto-upgrades.bak",
+		"sudo tee /etc/apt/apt.conf.d/50unattended-upgrades > /dev/null <<'EOF'\n// Generated by ravact\n",
+		"systemctl enable --now unattended-upgrades",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+}
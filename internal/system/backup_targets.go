@@ -0,0 +1,321 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Backup target types
+const (
+	BackupTargetS3     = "s3"     // S3-compatible storage through the aws CLI
+	BackupTargetSFTP   = "sftp"   // Any SSH server through sftp
+	BackupTargetRclone = "rclone" // Any remote configured in rclone
+)
+
+// BackupCredentialsDir holds the S3 keys of each target, readable only by
+// root, so they never appear in scripts or the execution screen
+var BackupCredentialsDir = "/etc/ravact/backup-targets"
+
+// backupTargetNamePattern keeps target names safe for file names
+var backupTargetNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// rcloneRemotePattern matches "remote:" with an optional path
+var rcloneRemotePattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_ .-]*:`)
+
+// BackupTarget is an off-site destination that archives and database dumps
+// are uploaded to after each backup
+type BackupTarget struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+
+	// S3: bucket, optional endpoint for non-AWS providers, and region
+	Bucket   string `yaml:"bucket,omitempty"`
+	Endpoint string `yaml:"endpoint,omitempty"`
+	Region   string `yaml:"region,omitempty"`
+	// S3 keys live in BackupCredentialsDir rather than backup.yaml
+	AccessKey string `yaml:"-"`
+	SecretKey string `yaml:"-"`
+
+	// SFTP
+	Host    string `yaml:"host,omitempty"`
+	Port    int    `yaml:"port,omitempty"`
+	User    string `yaml:"user,omitempty"`
+	KeyFile string `yaml:"key_file,omitempty"`
+
+	// rclone: a configured remote such as "b2:" or "gdrive:"
+	Remote string `yaml:"remote,omitempty"`
+
+	// Path is the directory inside the bucket, server, or remote
+	Path string `yaml:"path,omitempty"`
+}
+
+// Validate checks the target has what its type needs
+func (t BackupTarget) Validate() error {
+	if !backupTargetNamePattern.MatchString(t.Name) {
+		return fmt.Errorf("target name may only contain letters, numbers, - and _")
+	}
+	if strings.Contains(t.Path, "..") {
+		return fmt.Errorf("path cannot contain ..")
+	}
+	switch t.Type {
+	case BackupTargetS3:
+		if t.Bucket == "" || strings.ContainsAny(t.Bucket, "/ ") {
+			return fmt.Errorf("enter a bucket name")
+		}
+		if t.Endpoint != "" && !strings.HasPrefix(t.Endpoint, "https://") && !strings.HasPrefix(t.Endpoint, "http://") {
+			return fmt.Errorf("endpoint must be an http:// or https:// URL")
+		}
+		if t.AccessKey == "" || t.SecretKey == "" {
+			return fmt.Errorf("S3 targets need an access key and secret key")
+		}
+		if strings.ContainsAny(t.AccessKey+t.SecretKey+t.Region, "\n\r'\"") {
+			return fmt.Errorf("keys and region cannot contain quotes or newlines")
+		}
+	case BackupTargetSFTP:
+		if t.Host == "" || t.User == "" || strings.ContainsAny(t.Host+t.User, " @:/") {
+			return fmt.Errorf("enter a host and user")
+		}
+		if t.Port < 0 || t.Port > 65535 {
+			return fmt.Errorf("port must be between 1 and 65535")
+		}
+		if t.KeyFile != "" && !filepath.IsAbs(t.KeyFile) {
+			return fmt.Errorf("key file must be an absolute path")
+		}
+	case BackupTargetRclone:
+		if !rcloneRemotePattern.MatchString(t.Remote) {
+			return fmt.Errorf("enter an rclone remote such as b2: (see rclone listremotes)")
+		}
+	default:
+		return fmt.Errorf("unknown target type %q", t.Type)
+	}
+	return nil
+}
+
+// Describe is a short description of where the target uploads
+func (t BackupTarget) Describe() string {
+	switch t.Type {
+	case BackupTargetS3:
+		where := "s3://" + path.Join(t.Bucket, t.Path)
+		if t.Endpoint != "" {
+			where += " at " + t.Endpoint
+		}
+		return where
+	case BackupTargetSFTP:
+		return fmt.Sprintf("sftp://%s@%s:%d/%s", t.User, t.Host, t.port(), strings.TrimPrefix(t.Path, "/"))
+	default:
+		return t.remotePath("")
+	}
+}
+
+func (t BackupTarget) port() int {
+	if t.Port == 0 {
+		return 22
+	}
+	return t.Port
+}
+
+// CredentialsPath returns the file holding an S3 target's keys
+func (t BackupTarget) CredentialsPath() string {
+	return filepath.Join(BackupCredentialsDir, t.Name+".env")
+}
+
+// saveCredentials writes an S3 target's keys for its scripts to source
+func (t BackupTarget) saveCredentials() error {
+	if t.Type != BackupTargetS3 {
+		return nil
+	}
+	content := fmt.Sprintf("AWS_ACCESS_KEY_ID='%s'\nAWS_SECRET_ACCESS_KEY='%s'\n", t.AccessKey, t.SecretKey)
+	if t.Region != "" {
+		content += fmt.Sprintf("AWS_DEFAULT_REGION='%s'\n", t.Region)
+	}
+	if err := MkdirAll(BackupCredentialsDir, 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", BackupCredentialsDir, err)
+	}
+	if err := WriteFile(t.CredentialsPath(), []byte(content), 0600); err != nil {
+		return fmt.Errorf("failed to write credentials for %s: %w", t.Name, err)
+	}
+	return nil
+}
+
+// loadCredentials reads an S3 target's keys back for editing
+func (t *BackupTarget) loadCredentials() {
+	if t.Type != BackupTargetS3 {
+		return
+	}
+	data, err := ReadFile(t.CredentialsPath())
+	if err != nil {
+		return
+	}
+	env := ParseEnv(string(data))
+	t.AccessKey, _ = env.Get("AWS_ACCESS_KEY_ID")
+	t.SecretKey, _ = env.Get("AWS_SECRET_ACCESS_KEY")
+}
+
+// remotePath joins the target's path and dir into an rclone or SFTP path
+func (t BackupTarget) remotePath(dir string) string {
+	p := path.Join(t.Path, dir)
+	if t.Type != BackupTargetRclone {
+		return p
+	}
+	p = strings.TrimPrefix(p, "/")
+	if p == "" || strings.HasSuffix(t.Remote, ":") {
+		return t.Remote + p
+	}
+	return strings.TrimSuffix(t.Remote, "/") + "/" + p
+}
+
+// s3Command runs an aws s3 subcommand with the target's keys and endpoint
+func (t BackupTarget) s3Command(args ...string) string {
+	cmd := "aws s3 " + strings.Join(args, " ")
+	if t.Endpoint != "" {
+		cmd += " --endpoint-url " + ShellQuote(t.Endpoint)
+	}
+	return fmt.Sprintf("(set -a; . %s; set +a; %s)", ShellQuote(t.CredentialsPath()), cmd)
+}
+
+// sftpCommand runs a batch of sftp commands; lines starting with - may fail
+func (t BackupTarget) sftpCommand(batch []string) string {
+	args := []string{"sftp", "-b", "-", "-P", strconv.Itoa(t.port()), "-o", "BatchMode=yes", "-o", "StrictHostKeyChecking=accept-new"}
+	if t.KeyFile != "" {
+		args = append(args, "-i", ShellQuote(t.KeyFile))
+	}
+	args = append(args, ShellQuote(t.User+"@"+t.Host))
+	return fmt.Sprintf("printf '%%s\\n' %s | %s", quoteAll(batch), strings.Join(args, " "))
+}
+
+// quoteAll shell-quotes each word
+func quoteAll(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = ShellQuote(w)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// sftpQuote quotes a path for an sftp batch file
+func sftpQuote(p string) string {
+	return `"` + strings.ReplaceAll(p, `"`, `\"`) + `"`
+}
+
+// requiredTool returns the command a target type uploads with
+func (t BackupTarget) requiredTool() string {
+	switch t.Type {
+	case BackupTargetS3:
+		return "aws"
+	case BackupTargetSFTP:
+		return "sftp"
+	default:
+		return "rclone"
+	}
+}
+
+// toolCheck fails the script early with a clear message when the upload
+// tool is missing
+func (t BackupTarget) toolCheck() string {
+	tool := t.requiredTool()
+	return fmt.Sprintf("command -v %s > /dev/null || { echo %s; exit 1; }", tool,
+		ShellQuote(fmt.Sprintf("%s is not installed; target %s needs it", tool, t.Name)))
+}
+
+// UploadScript returns a script that uploads files into dir under the
+// target's path, creating it as needed
+func (t BackupTarget) UploadScript(dir string, files []string) string {
+	var b strings.Builder
+	b.WriteString(t.toolCheck() + "\n")
+	fmt.Fprintf(&b, "echo %s\n", ShellQuote(fmt.Sprintf("Uploading to %s (%s)...", t.Name, t.Describe())))
+	switch t.Type {
+	case BackupTargetS3:
+		for _, f := range files {
+			dest := "s3://" + path.Join(t.Bucket, t.Path, dir, filepath.Base(f))
+			b.WriteString(t.s3Command("cp", "--only-show-errors", ShellQuote(f), ShellQuote(dest)) + "\n")
+		}
+	case BackupTargetSFTP:
+		var batch []string
+		// Create each level of the path; existing directories are not errors
+		parts := strings.Split(t.remotePath(dir), "/")
+		for i := range parts {
+			if parts[i] != "" {
+				batch = append(batch, "-mkdir "+sftpQuote(strings.Join(parts[:i+1], "/")))
+			}
+		}
+		for _, f := range files {
+			batch = append(batch, "put "+sftpQuote(f)+" "+sftpQuote(path.Join(t.remotePath(dir), filepath.Base(f))))
+		}
+		b.WriteString(t.sftpCommand(batch) + "\n")
+	default:
+		for _, f := range files {
+			b.WriteString("rclone copy " + ShellQuote(f) + " " + ShellQuote(t.remotePath(dir)) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// TestScript returns a script that checks the target is reachable and the
+// credentials work, without uploading anything
+func (t BackupTarget) TestScript() string {
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	b.WriteString(t.toolCheck() + "\n")
+	fmt.Fprintf(&b, "echo %s\n", ShellQuote(fmt.Sprintf("Connecting to %s (%s)...", t.Name, t.Describe())))
+	switch t.Type {
+	case BackupTargetS3:
+		prefix := "s3://" + t.Bucket + "/"
+		if p := strings.Trim(t.Path, "/"); p != "" {
+			prefix += p + "/"
+		}
+		b.WriteString(t.s3Command("ls", ShellQuote(prefix)) + "\n")
+	case BackupTargetSFTP:
+		dir := t.remotePath("")
+		if dir == "" {
+			dir = "."
+		}
+		b.WriteString(t.sftpCommand([]string{"-ls " + sftpQuote(dir)}) + "\n")
+	default:
+		b.WriteString("rclone lsf --max-depth 1 " + ShellQuote(t.remotePath("")) + " || rclone about " + ShellQuote(t.Remote) + "\n")
+	}
+	fmt.Fprintf(&b, "echo %s\n", ShellQuote("Connection to "+t.Name+" works"))
+	return b.String()
+}
+
+// Target returns the target with a name
+func (c BackupConfig) Target(name string) (BackupTarget, bool) {
+	for _, t := range c.Targets {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return BackupTarget{}, false
+}
+
+// SetTarget adds a target or replaces the one with the same name
+func (c *BackupConfig) SetTarget(target BackupTarget) {
+	for i, t := range c.Targets {
+		if t.Name == target.Name {
+			c.Targets[i] = target
+			return
+		}
+	}
+	c.Targets = append(c.Targets, target)
+}
+
+// RemoveTarget deletes a target and its credentials
+func (c *BackupConfig) RemoveTarget(name string) error {
+	for i, t := range c.Targets {
+		if t.Name != name {
+			continue
+		}
+		c.Targets = append(c.Targets[:i], c.Targets[i+1:]...)
+		if t.Type == BackupTargetS3 {
+			if err := Remove(t.CredentialsPath()); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove credentials for %s: %w", name, err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("no backup target named %s", name)
+}
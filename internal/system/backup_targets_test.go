@@ -0,0 +1,165 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackupTargetValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		target BackupTarget
+		ok     bool
+	}{
+		{"s3", BackupTarget{Name: "offsite", Type: BackupTargetS3, Bucket: "backups", AccessKey: "AK", SecretKey: "SK"}, true},
+		{"s3 without keys", BackupTarget{Name: "offsite", Type: BackupTargetS3, Bucket: "backups"}, false},
+		{"s3 bad endpoint", BackupTarget{Name: "offsite", Type: BackupTargetS3, Bucket: "backups", Endpoint: "minio:9000", AccessKey: "AK", SecretKey: "SK"}, false},
+		{"sftp", BackupTarget{Name: "nas", Type: BackupTargetSFTP, Host: "nas.local", User: "backup", KeyFile: "/root/.ssh/id_ed25519"}, true},
+		{"sftp relative key", BackupTarget{Name: "nas", Type: BackupTargetSFTP, Host: "nas.local", User: "backup", KeyFile: "id_ed25519"}, false},
+		{"rclone", BackupTarget{Name: "b2", Type: BackupTargetRclone, Remote: "b2:ravact"}, true},
+		{"rclone without colon", BackupTarget{Name: "b2", Type: BackupTargetRclone, Remote: "b2"}, false},
+		{"bad name", BackupTarget{Name: "../x", Type: BackupTargetRclone, Remote: "b2:"}, false},
+		{"path escapes", BackupTarget{Name: "b2", Type: BackupTargetRclone, Remote: "b2:", Path: "../etc"}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.target.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate() = %v", tt.name, err)
+		}
+	}
+}
+
+func TestBackupTargetUploadScript(t *testing.T) {
+	files := []string{"/var/backups/ravact/shop/shop-20260103-020000.tar.gz", "/var/backups/ravact/shop/shop-20260103-020000.sql.gz"}
+	tests := []struct {
+		name   string
+		target BackupTarget
+		want   []string
+	}{
+		{
+			"s3",
+			BackupTarget{Name: "wasabi", Type: BackupTargetS3, Bucket: "backups", Path: "web1", Endpoint: "https://s3.wasabisys.com"},
+			[]string{
+				". /etc/ravact/backup-targets/wasabi.env",
+				"aws s3 cp --only-show-errors " + files[0] + " s3://backups/web1/shop/shop-20260103-020000.tar.gz --endpoint-url https://s3.wasabisys.com",
+				"s3://backups/web1/shop/shop-20260103-020000.sql.gz",
+			},
+		},
+		{
+			"sftp",
+			BackupTarget{Name: "nas", Type: BackupTargetSFTP, Host: "nas.local", User: "backup", Port: 2222, Path: "/srv/backups"},
+			[]string{"'-mkdir \"/srv\"' '-mkdir \"/srv/backups\"' '-mkdir \"/srv/backups/shop\"'", "-P 2222", "backup@nas.local", `put "` + files[0] + `" "/srv/backups/shop/shop-20260103-020000.tar.gz"`},
+		},
+		{
+			"rclone",
+			BackupTarget{Name: "b2", Type: BackupTargetRclone, Remote: "b2:", Path: "ravact"},
+			[]string{"rclone copy " + files[0] + " b2:ravact/shop", "rclone copy " + files[1] + " b2:ravact/shop"},
+		},
+		{
+			"rclone remote with path",
+			BackupTarget{Name: "gd", Type: BackupTargetRclone, Remote: "gdrive:servers"},
+			[]string{"rclone copy " + files[0] + " gdrive:servers/shop"},
+		},
+	}
+	for _, tt := range tests {
+		script := tt.target.UploadScript("shop", files)
+		if !strings.Contains(script, "command -v "+tt.target.requiredTool()) {
+			t.Errorf("%s: script does not check for %s", tt.name, tt.target.requiredTool())
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(script, want) {
+				t.Errorf("%s: script is missing %q:\n%s", tt.name, want, script)
+			}
+		}
+	}
+}
+
+func TestBackupConfigTargets(t *testing.T) {
+	tmp := t.TempDir()
+	BackupConfigPath = filepath.Join(tmp, "backup.yaml")
+	BackupCredentialsDir = filepath.Join(tmp, "backup-targets")
+	defer func() {
+		BackupConfigPath = "/etc/ravact/backup.yaml"
+		BackupCredentialsDir = "/etc/ravact/backup-targets"
+	}()
+
+	cfg, err := LoadBackupConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.SetTarget(BackupTarget{Name: "wasabi", Type: BackupTargetS3, Bucket: "backups", Region: "eu-central-1", AccessKey: "AK", SecretKey: "S3CR3T"})
+	cfg.SetTarget(BackupTarget{Name: "b2", Type: BackupTargetRclone, Remote: "b2:"})
+	cfg.SetTarget(BackupTarget{Name: "b2", Type: BackupTargetRclone, Remote: "b2:ravact"})
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(BackupConfigPath)
+	if strings.Contains(string(data), "S3CR3T") {
+		t.Errorf("backup.yaml contains the secret key:\n%s", data)
+	}
+	info, err := os.Stat(filepath.Join(BackupCredentialsDir, "wasabi.env"))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("credentials file = %v, %v", info, err)
+	}
+
+	loaded, err := LoadBackupConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Targets) != 2 {
+		t.Fatalf("loaded %d targets, want 2", len(loaded.Targets))
+	}
+	if s3, _ := loaded.Target("wasabi"); s3.SecretKey != "S3CR3T" || s3.AccessKey != "AK" {
+		t.Errorf("credentials were not loaded: %+v", s3)
+	}
+	if b2, _ := loaded.Target("b2"); b2.Remote != "b2:ravact" {
+		t.Errorf("SetTarget() did not replace b2: %+v", b2)
+	}
+
+	if err := loaded.RemoveTarget("wasabi"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(BackupCredentialsDir, "wasabi.env")); !os.IsNotExist(err) {
+		t.Error("RemoveTarget() left the credentials behind")
+	}
+	if err := loaded.RemoveTarget("missing"); err == nil {
+		t.Error("RemoveTarget() of an unknown target succeeded")
+	}
+}
+
+func TestSiteBackupScriptDumpsAndUploads(t *testing.T) {
+	tmp := t.TempDir()
+	project := filepath.Join(tmp, "shop")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	env := "APP_NAME=Shop\nDB_CONNECTION=pgsql\nDB_DATABASE=shop_prod\n"
+	if err := os.WriteFile(filepath.Join(project, ".env"), []byte(env), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := BackupConfig{
+		Root:          "/var/backups/ravact",
+		DatabaseDumps: true,
+		Targets:       []BackupTarget{{Name: "b2", Type: BackupTargetRclone, Remote: "b2:"}},
+	}
+	script, archive, err := cfg.SiteBackupScript(NginxSite{Name: "shop", RootDir: filepath.Join(project, "public")}, nil, time.Date(2026, 1, 3, 2, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump := strings.TrimSuffix(archive, ".tar.gz") + ".sql.gz"
+	for _, want := range []string{"sudo -u postgres pg_dump shop_prod | gzip", "rclone copy " + archive + " b2:shop", "rclone copy " + dump + " b2:shop"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script is missing %q:\n%s", want, script)
+		}
+	}
+
+	cfg.DatabaseDumps = false
+	script, _, _ = cfg.SiteBackupScript(NginxSite{Name: "shop", RootDir: project}, nil, time.Date(2026, 1, 3, 2, 0, 0, 0, time.Local))
+	if strings.Contains(script, "pg_dump") {
+		t.Error("script dumps the database with DatabaseDumps off")
+	}
+}
@@ -0,0 +1,191 @@
+package system
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ComposerAdvisory is one security advisory affecting an installed package
+type ComposerAdvisory struct {
+	Package          string
+	Version          string // Installed version from composer.lock, if known
+	AdvisoryID       string
+	Title            string
+	CVE              string
+	Link             string
+	Severity         string // critical, high, medium, low, or "" when unrated
+	AffectedVersions string
+	ReportedAt       string
+}
+
+// ComposerAudit is the parsed result of `composer audit --format=json`
+type ComposerAudit struct {
+	Advisories []ComposerAdvisory
+	Abandoned  map[string]string // Package -> suggested replacement ("" if none)
+}
+
+// Sort orders for ComposerAudit.Sort
+const (
+	AuditSortSeverity = "severity"
+	AuditSortPackage  = "package"
+	AuditSortReported = "reported"
+)
+
+// AuditSortOrders lists the sort orders in the order the UI cycles them
+var AuditSortOrders = []string{AuditSortSeverity, AuditSortPackage, AuditSortReported}
+
+// SeverityRank orders severities from critical (4) down to unrated (0)
+func SeverityRank(severity string) int {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return 4
+	case "high":
+		return 3
+	case "medium", "moderate":
+		return 2
+	case "low":
+		return 1
+	}
+	return 0
+}
+
+// ParseComposerAudit parses `composer audit --format=json`. Composer prints
+// an empty JSON array instead of an object when there is nothing to report.
+func ParseComposerAudit(data []byte) (*ComposerAudit, error) {
+	start := bytes.IndexByte(data, '{')
+	if start < 0 {
+		return nil, fmt.Errorf("unexpected composer audit output: %s", strings.TrimSpace(string(data)))
+	}
+	var raw struct {
+		Advisories json.RawMessage `json:"advisories"`
+		Abandoned  json.RawMessage `json:"abandoned"`
+	}
+	if err := json.NewDecoder(bytes.NewReader(data[start:])).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse composer audit output: %w", err)
+	}
+
+	audit := &ComposerAudit{Abandoned: map[string]string{}}
+	var advisories map[string][]struct {
+		AdvisoryID       string `json:"advisoryId"`
+		PackageName      string `json:"packageName"`
+		AffectedVersions string `json:"affectedVersions"`
+		Title            string `json:"title"`
+		CVE              string `json:"cve"`
+		Link             string `json:"link"`
+		ReportedAt       string `json:"reportedAt"`
+		Severity         string `json:"severity"`
+	}
+	if isJSONObject(raw.Advisories) {
+		if err := json.Unmarshal(raw.Advisories, &advisories); err != nil {
+			return nil, fmt.Errorf("failed to parse advisories: %w", err)
+		}
+	}
+	for pkg, list := range advisories {
+		for _, a := range list {
+			audit.Advisories = append(audit.Advisories, ComposerAdvisory{
+				Package:          pkg,
+				AdvisoryID:       a.AdvisoryID,
+				Title:            a.Title,
+				CVE:              a.CVE,
+				Link:             a.Link,
+				Severity:         strings.ToLower(a.Severity),
+				AffectedVersions: a.AffectedVersions,
+				ReportedAt:       a.ReportedAt,
+			})
+		}
+	}
+
+	var abandoned map[string]*string
+	if isJSONObject(raw.Abandoned) {
+		if err := json.Unmarshal(raw.Abandoned, &abandoned); err != nil {
+			return nil, fmt.Errorf("failed to parse abandoned packages: %w", err)
+		}
+	}
+	for pkg, replacement := range abandoned {
+		audit.Abandoned[pkg] = ""
+		if replacement != nil {
+			audit.Abandoned[pkg] = *replacement
+		}
+	}
+
+	audit.Sort(AuditSortSeverity)
+	return audit, nil
+}
+
+// isJSONObject reports whether raw holds an object rather than [] or null
+func isJSONObject(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// Sort orders the advisories by one of the AuditSort orders, most severe or
+// most recent first; ties fall back to package name
+func (a *ComposerAudit) Sort(order string) {
+	sort.SliceStable(a.Advisories, func(i, j int) bool {
+		x, y := a.Advisories[i], a.Advisories[j]
+		switch order {
+		case AuditSortSeverity:
+			if rx, ry := SeverityRank(x.Severity), SeverityRank(y.Severity); rx != ry {
+				return rx > ry
+			}
+		case AuditSortReported:
+			if x.ReportedAt != y.ReportedAt {
+				return x.ReportedAt > y.ReportedAt
+			}
+		}
+		if x.Package != y.Package {
+			return x.Package < y.Package
+		}
+		return x.AdvisoryID < y.AdvisoryID
+	})
+}
+
+// lockedVersions returns package versions from a project's composer.lock
+func lockedVersions(dir string) map[string]string {
+	data, err := ReadFile(filepath.Join(dir, "composer.lock"))
+	if err != nil {
+		return nil
+	}
+	var lock struct {
+		Packages    []struct{ Name, Version string } `json:"packages"`
+		PackagesDev []struct{ Name, Version string } `json:"packages-dev"`
+	}
+	if json.Unmarshal(data, &lock) != nil {
+		return nil
+	}
+	versions := map[string]string{}
+	for _, p := range append(lock.Packages, lock.PackagesDev...) {
+		versions[p.Name] = p.Version
+	}
+	return versions
+}
+
+// RunComposerAudit audits the project in dir, as user when one is given.
+// composer exits non-zero when it finds advisories, so the output is parsed
+// whatever the exit status.
+func RunComposerAudit(dir, user string) (*ComposerAudit, error) {
+	script := "cd " + ShellQuote(dir) + " && composer audit --format=json --no-interaction"
+	cmd := Command("bash", "-c", script)
+	if user != "" {
+		cmd = Command("sudo", "-u", user, "-H", "bash", "-c", script)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, runErr := cmd.Output()
+	audit, err := ParseComposerAudit(out)
+	if err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("composer audit failed: %s", strings.TrimSpace(stderr.String()+" "+runErr.Error()))
+		}
+		return nil, err
+	}
+	versions := lockedVersions(dir)
+	for i := range audit.Advisories {
+		audit.Advisories[i].Version = versions[audit.Advisories[i].Package]
+	}
+	return audit, nil
+}
@@ -0,0 +1,65 @@
+package system
+
+import (
+	"testing"
+)
+
+func TestParseComposerAudit(t *testing.T) {
+	out := `{
+    "advisories": {
+        "guzzlehttp/psr7": [
+            {"advisoryId": "PKSA-1", "packageName": "guzzlehttp/psr7", "affectedVersions": "<1.9.1", "title": "Header injection", "cve": "CVE-2023-29197", "link": "https://example.com/1", "reportedAt": "2023-04-17T16:00:00+00:00", "severity": "medium"}
+        ],
+        "laravel/framework": [
+            {"advisoryId": "PKSA-2", "packageName": "laravel/framework", "affectedVersions": "<10.48.23", "title": "Environment manipulation", "cve": "CVE-2024-52301", "link": "https://example.com/2", "reportedAt": "2024-11-12T00:00:00+00:00", "severity": "high"},
+            {"advisoryId": "PKSA-3", "packageName": "laravel/framework", "affectedVersions": "<8.0", "title": "Unrated issue", "cve": null, "link": "https://example.com/3", "reportedAt": "2020-01-01T00:00:00+00:00", "severity": null}
+        ]
+    },
+    "abandoned": {"swiftmailer/swiftmailer": "symfony/mailer", "old/pkg": null}
+}`
+	audit, err := ParseComposerAudit([]byte(out))
+	if err != nil {
+		t.Fatalf("ParseComposerAudit: %v", err)
+	}
+	if len(audit.Advisories) != 3 {
+		t.Fatalf("expected 3 advisories, got %+v", audit.Advisories)
+	}
+	if got := audit.Advisories[0].AdvisoryID + audit.Advisories[1].AdvisoryID + audit.Advisories[2].AdvisoryID; got != "PKSA-2PKSA-1PKSA-3" {
+		t.Errorf("expected severity order, got %s", got)
+	}
+	if audit.Abandoned["swiftmailer/swiftmailer"] != "symfony/mailer" {
+		t.Errorf("unexpected abandoned packages %v", audit.Abandoned)
+	}
+	if r, ok := audit.Abandoned["old/pkg"]; !ok || r != "" {
+		t.Errorf("expected old/pkg abandoned without replacement, got %v", audit.Abandoned)
+	}
+
+	audit.Sort(AuditSortReported)
+	if audit.Advisories[0].AdvisoryID != "PKSA-2" || audit.Advisories[2].AdvisoryID != "PKSA-3" {
+		t.Errorf("expected newest first, got %+v", audit.Advisories)
+	}
+	audit.Sort(AuditSortPackage)
+	if audit.Advisories[0].Package != "guzzlehttp/psr7" {
+		t.Errorf("expected package order, got %+v", audit.Advisories)
+	}
+}
+
+func TestParseComposerAuditClean(t *testing.T) {
+	audit, err := ParseComposerAudit([]byte(`{"advisories": [], "abandoned": []}`))
+	if err != nil {
+		t.Fatalf("ParseComposerAudit: %v", err)
+	}
+	if len(audit.Advisories) != 0 || len(audit.Abandoned) != 0 {
+		t.Errorf("expected a clean audit, got %+v", audit)
+	}
+	if _, err := ParseComposerAudit([]byte("composer: command not found")); err == nil {
+		t.Error("expected an error without JSON")
+	}
+}
+
+func TestSeverityRank(t *testing.T) {
+	if !(SeverityRank("Critical") > SeverityRank("high") && SeverityRank("high") > SeverityRank("medium") &&
+		SeverityRank("medium") > SeverityRank("low") && SeverityRank("low") > SeverityRank("")) {
+		t.Error("severities out of order")
+	}
+}
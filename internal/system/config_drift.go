@@ -0,0 +1,216 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/stubs"
+)
+
+// Config generators, which say how a GeneratedConfig is rendered again
+const (
+	GeneratorStub      = "stub"       // Params are the stub's replacements
+	GeneratorNginxSite = "nginx-site" // Params are PlanCreateSite's arguments
+)
+
+// Drift states of a generated config
+const (
+	DriftNone     = "in sync"      // On disk matches a fresh render
+	DriftEdited   = "edited"       // Changed outside ravact since ravact wrote it
+	DriftUpdated  = "updated"      // Changed by ravact since it was generated
+	DriftTemplate = "stub changed" // Unchanged on disk, but the stub now renders differently
+	DriftMissing  = "missing"      // The file is gone
+)
+
+// GeneratedConfig records how ravact generated a config file, so it can be
+// rendered again and compared with the file on disk
+type GeneratedConfig struct {
+	Path          string            `json:"path"`
+	Owner         string            `json:"owner"` // The site or service the file belongs to
+	Generator     string            `json:"generator"`
+	Stub          string            `json:"stub,omitempty"`
+	Params        map[string]string `json:"params"`
+	GeneratedHash string            `json:"generated_hash"` // The content as generated
+	WrittenHash   string            `json:"written_hash"`   // What ravact last wrote, after any later edits of its own
+	GeneratedAt   time.Time         `json:"generated_at"`
+}
+
+// ConfigDrift compares a generated config with the file on disk
+type ConfigDrift struct {
+	Config   GeneratedConfig
+	State    string
+	Expected string // A fresh render from the stub and stored parameters
+	Actual   string
+	Err      error // The config could not be rendered
+}
+
+// Diff shows what differs on disk from a fresh render
+func (d ConfigDrift) Diff() string {
+	return UnifiedDiff(d.Config.Path+" (generated)", d.Config.Path, d.Expected, d.Actual)
+}
+
+// Render generates the config again from its stub and parameters
+func (g GeneratedConfig) Render() (string, error) {
+	switch g.Generator {
+	case GeneratorStub:
+		return stubs.LoadAndReplace(g.Stub, g.Params)
+	case GeneratorNginxSite:
+		nm := NewNginxManager()
+		domains := strings.Fields(g.Params["domains"])
+		if len(domains) == 0 {
+			return "", fmt.Errorf("no domains recorded for %s", g.Path)
+		}
+		ssl, _ := strconv.ParseBool(g.Params["ssl"])
+		certbot, _ := strconv.ParseBool(g.Params["certbot"])
+		directives, err := nm.getTemplateDirectives(g.Params["template"], PrimaryDomain(domains), g.Params["root"], siteOptionsFromParams(g.Params))
+		if err != nil {
+			return "", err
+		}
+		return nm.generateConfig(domains, g.Params["root"], directives, ssl, certbot), nil
+	}
+	return "", fmt.Errorf("unknown config generator %q", g.Generator)
+}
+
+// configHash hashes content ignoring trailing whitespace, which scripts
+// writing through heredocs add
+func configHash(content string) string {
+	return ScriptHash([]byte(strings.TrimRight(content, " \t\r\n")))
+}
+
+// sameConfig compares two configs ignoring trailing whitespace
+func sameConfig(a, b string) bool {
+	return strings.TrimRight(a, " \t\r\n") == strings.TrimRight(b, " \t\r\n")
+}
+
+// RecordGeneratedConfig stores how a config was generated and the content
+// ravact wrote for it, which differs from the render when it was edited
+// before being written
+func RecordGeneratedConfig(g GeneratedConfig, content string) error {
+	if g.Path == "" {
+		return fmt.Errorf("no config path to record")
+	}
+	rendered, err := g.Render()
+	if err != nil {
+		return err
+	}
+	g.GeneratedHash = configHash(rendered)
+	g.WrittenHash = configHash(content)
+	if g.GeneratedAt.IsZero() {
+		g.GeneratedAt = time.Now()
+	}
+	return UpdateStore(func(tx *StoreTx) error {
+		return tx.Put(BucketGeneratedConfigs, g.Path, g)
+	})
+}
+
+// ForgetGeneratedConfig stops checking a config for drift
+func ForgetGeneratedConfig(path string) error {
+	return UpdateStore(func(tx *StoreTx) error {
+		return tx.Delete(BucketGeneratedConfigs, path)
+	})
+}
+
+// LoadGeneratedConfigs returns every recorded config, sorted by path
+func LoadGeneratedConfigs() ([]GeneratedConfig, error) {
+	var configs []GeneratedConfig
+	err := ViewStore(func(tx *StoreTx) error {
+		for _, key := range tx.Keys(BucketGeneratedConfigs) {
+			var g GeneratedConfig
+			if _, err := tx.Get(BucketGeneratedConfigs, key, &g); err != nil {
+				return err
+			}
+			configs = append(configs, g)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read generated configs: %w", err)
+	}
+	return configs, nil
+}
+
+// noteConfigWrite keeps a generated config's written hash current when
+// ravact edits it later (SSL, hardening, ...), so only edits made outside
+// ravact count as drift. Nothing is created for configs without a record.
+func noteConfigWrite(path string, data []byte) {
+	if _, err := Stat(StorePath()); err != nil {
+		return
+	}
+	_ = UpdateStore(func(tx *StoreTx) error {
+		var g GeneratedConfig
+		if ok, err := tx.Get(BucketGeneratedConfigs, path, &g); !ok || err != nil {
+			return err
+		}
+		g.WrittenHash = configHash(string(data))
+		return tx.Put(BucketGeneratedConfigs, path, g)
+	})
+}
+
+// CheckConfigDrift renders a config again and compares it with the file
+// on disk and with what ravact last wrote
+func CheckConfigDrift(g GeneratedConfig) ConfigDrift {
+	d := ConfigDrift{Config: g}
+	data, err := ReadFile(g.Path)
+	if os.IsNotExist(err) {
+		d.State = DriftMissing
+		return d
+	}
+	if err != nil {
+		d.Err = err
+		return d
+	}
+	d.Actual = string(data)
+	d.Expected, d.Err = g.Render()
+
+	switch {
+	case configHash(d.Actual) != g.WrittenHash:
+		d.State = DriftEdited
+	case d.Err != nil:
+		// Unchanged since ravact wrote it, even if the stub is gone
+		d.State = DriftNone
+	case sameConfig(d.Expected, d.Actual):
+		d.State = DriftNone
+	case g.WrittenHash != g.GeneratedHash:
+		d.State = DriftUpdated
+	default:
+		d.State = DriftTemplate
+	}
+	return d
+}
+
+// DetectConfigDrift checks every recorded config
+func DetectConfigDrift() ([]ConfigDrift, error) {
+	configs, err := LoadGeneratedConfigs()
+	if err != nil {
+		return nil, err
+	}
+	drifts := make([]ConfigDrift, 0, len(configs))
+	for _, g := range configs {
+		drifts = append(drifts, CheckConfigDrift(g))
+	}
+	return drifts, nil
+}
+
+// AcceptConfigDrift takes the file on disk as the new baseline, so a
+// deliberate manual edit stops being reported
+func AcceptConfigDrift(path string) error {
+	data, err := ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return UpdateStore(func(tx *StoreTx) error {
+		var g GeneratedConfig
+		ok, err := tx.Get(BucketGeneratedConfigs, path, &g)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%s is not a generated config", path)
+		}
+		g.WrittenHash = configHash(string(data))
+		return tx.Put(BucketGeneratedConfigs, path, g)
+	})
+}
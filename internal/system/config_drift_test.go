@@ -0,0 +1,107 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigDrift(t *testing.T) {
+	origState, origPaths := StateDir, TrackedConfigPaths
+	StateDir = filepath.Join(t.TempDir(), "state")
+	dir := t.TempDir()
+	TrackedConfigPaths = []string{dir}
+	origHistory := ConfigHistoryDir
+	ConfigHistoryDir = filepath.Join(t.TempDir(), "history")
+	defer func() { StateDir, TrackedConfigPaths, ConfigHistoryDir = origState, origPaths, origHistory }()
+
+	nm := NewNginxManager()
+	nm.sitesAvailable = dir
+	change, err := nm.PlanCreateSite("shop", []string{"shop.test", "www.shop.test"}, "/var/www/shop/public", "laravel", "", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := nm.ApplyChange(change); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(want string) ConfigDrift {
+		t.Helper()
+		drifts, err := DetectConfigDrift()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(drifts) != 1 || drifts[0].State != want {
+			t.Fatalf("expected one config %q, got %+v", want, drifts)
+		}
+		return drifts[0]
+	}
+	check(DriftNone)
+
+	// Edits made by ravact move the baseline
+	updated := strings.Replace(change.New, "index index.html", "index index.php", 1)
+	if err := WriteFile(change.Path, []byte(updated), 0644); err != nil {
+		t.Fatal(err)
+	}
+	check(DriftUpdated)
+
+	// Edits made by hand are flagged with a diff against a fresh render
+	edited := updated + "# tuned by hand\n"
+	if err := os.WriteFile(change.Path, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d := check(DriftEdited)
+	if !strings.Contains(d.Diff(), "+# tuned by hand") {
+		t.Errorf("diff does not show the manual edit:\n%s", d.Diff())
+	}
+
+	if err := AcceptConfigDrift(change.Path); err != nil {
+		t.Fatal(err)
+	}
+	check(DriftUpdated)
+
+	if err := nm.DeleteSite("shop"); err != nil {
+		t.Fatal(err)
+	}
+	if configs, _ := LoadGeneratedConfigs(); len(configs) != 0 {
+		t.Errorf("expected deleting the site to forget its config, got %+v", configs)
+	}
+}
+
+func TestConfigDriftStates(t *testing.T) {
+	dir := t.TempDir()
+	g := GeneratedConfig{
+		Path:      filepath.Join(dir, "fpcli"),
+		Generator: GeneratorStub,
+		Stub:      "fpcli",
+		Params:    map[string]string{"BINARY": "/usr/local/bin/frankenphp"},
+	}
+	if d := CheckConfigDrift(g); d.State != DriftMissing {
+		t.Errorf("expected a missing file, got %q", d.State)
+	}
+
+	rendered, err := g.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Heredocs add a trailing newline, which is not drift
+	if err := os.WriteFile(g.Path, []byte(rendered+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	g.GeneratedHash = configHash(rendered)
+	g.WrittenHash = g.GeneratedHash
+	if d := CheckConfigDrift(g); d.State != DriftNone {
+		t.Errorf("expected in sync, got %q (%v)", d.State, d.Err)
+	}
+
+	// The file matches what was written, but the parameters now render differently
+	g.Params = map[string]string{"BINARY": "/opt/frankenphp"}
+	if d := CheckConfigDrift(g); d.State != DriftTemplate {
+		t.Errorf("expected a changed stub, got %q", d.State)
+	}
+
+	if _, err := (GeneratedConfig{Generator: "unknown"}).Render(); err == nil {
+		t.Error("expected an unknown generator to fail")
+	}
+}
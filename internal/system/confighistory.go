@@ -0,0 +1,238 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// ConfigHistoryDir is the git repository holding a shadow copy of the
+// configuration ravact manages. Files keep their absolute path inside it,
+// so /etc/nginx/nginx.conf is stored as etc/nginx/nginx.conf.
+var ConfigHistoryDir = "/var/lib/ravact/config-history"
+
+// TrackedConfigPaths are the files and directories copied into the history.
+// Shell globs are expanded on the managed host.
+var TrackedConfigPaths = []string{
+	"/etc/nginx/nginx.conf",
+	"/etc/nginx/sites-available",
+	"/etc/nginx/sites-enabled",
+	"/etc/nginx/conf.d",
+	"/etc/php/*/fpm/pool.d",
+	"/etc/php/*/fpm/php.ini",
+	"/etc/php/*/cli/php.ini",
+	"/etc/supervisor/conf.d",
+	"/etc/systemd/system/*.service",
+	"/etc/frankenphp",
+	"/etc/caddy",
+	"/etc/redis/redis.conf",
+	"/etc/mysql/mysql.conf.d",
+	"/etc/mysql/conf.d",
+	"/etc/postgresql/*/main/postgresql.conf",
+	"/etc/postgresql/*/main/pg_hba.conf",
+	"/etc/ssh/sshd_config",
+	"/etc/ufw/user.rules",
+	"/etc/ufw/user6.rules",
+	"/etc/ravact/handbook.md",
+	"/etc/ravact/naming.yaml",
+	"/etc/ravact/marketplace.yaml",
+	"/etc/ravact/backup.yaml",
+}
+
+// IsTrackedConfigPath reports whether a file is inside the history, either
+// listed itself or under a tracked directory
+func IsTrackedConfigPath(hostPath string) bool {
+	if !path.IsAbs(hostPath) {
+		return false
+	}
+	for p := path.Clean(hostPath); p != "/"; p = path.Dir(p) {
+		for _, pattern := range TrackedConfigPaths {
+			if ok, _ := path.Match(pattern, p); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// trackConfigChange records a change ravact makes to a tracked file. The
+// current state is snapshotted first so edits made outside ravact keep
+// their own entry instead of being folded into this one. The history is
+// best effort: hosts without git still get the change.
+func trackConfigChange(hostPath, action string, change func() error) error {
+	if !IsTrackedConfigPath(hostPath) {
+		return change()
+	}
+	_, _ = SnapshotConfig("Changes made outside ravact")
+	if err := change(); err != nil {
+		return err
+	}
+	_, _ = SnapshotConfig(action + " " + hostPath)
+	return nil
+}
+
+// ConfigCommit is one snapshot in the configuration history
+type ConfigCommit struct {
+	Hash    string
+	Date    time.Time
+	Subject string
+}
+
+// ShortHash returns the abbreviated commit hash
+func (c ConfigCommit) ShortHash() string {
+	if len(c.Hash) > 8 {
+		return c.Hash[:8]
+	}
+	return c.Hash
+}
+
+// ConfigChange is a file changed by a snapshot
+type ConfigChange struct {
+	Status string // A, M, D
+	Path   string // Absolute path on the host
+}
+
+// configGit runs a git command in the history repository on the active host
+func configGit(args ...string) (string, error) {
+	output, err := Command("git", append([]string{"-C", ConfigHistoryDir}, args...)...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
+
+// snapshotScript refreshes the shadow copy and commits it if anything changed
+func snapshotScript(message string) string {
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	fmt.Fprintf(&b, "mkdir -p %s && cd %s\n", ShellQuote(ConfigHistoryDir), ShellQuote(ConfigHistoryDir))
+	b.WriteString("if [ ! -d .git ]; then git init -q && git config user.name ravact && git config user.email ravact@localhost; fi\n")
+	// Start from an empty tree so deleted files show up as deletions
+	b.WriteString("find . -mindepth 1 -maxdepth 1 ! -name .git -exec rm -rf {} +\n")
+	b.WriteString("for p in")
+	for _, p := range TrackedConfigPaths {
+		b.WriteString(" " + p) // Unquoted so globs expand
+	}
+	b.WriteString("; do if [ -e \"$p\" ]; then cp -a --parents \"$p\" .; fi; done\n")
+	b.WriteString("git add -A\n")
+	fmt.Fprintf(&b, "if ! git diff --cached --quiet; then git commit -q -m %s; echo committed; fi\n", ShellQuote(message))
+	return b.String()
+}
+
+// SnapshotConfig copies the tracked configuration into the history and
+// commits it with message. It reports whether anything had changed.
+func SnapshotConfig(message string) (bool, error) {
+	if _, err := Command("git", "--version").Output(); err != nil {
+		return false, fmt.Errorf("git is not installed")
+	}
+	if strings.TrimSpace(message) == "" {
+		message = "Configuration changed"
+	}
+	output, err := Command("bash", "-c", snapshotScript(message)).CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("snapshot failed: %s", strings.TrimSpace(string(output)))
+	}
+	return strings.Contains(string(output), "committed"), nil
+}
+
+// ConfigHistory returns the most recent snapshots, newest first
+func ConfigHistory(limit int) ([]ConfigCommit, error) {
+	if _, err := Stat(path.Join(ConfigHistoryDir, ".git")); err != nil {
+		return nil, nil
+	}
+	output, err := configGit("log", fmt.Sprintf("-n%d", limit), "--format=%H%x1f%aI%x1f%s")
+	if err != nil {
+		return nil, err
+	}
+	return parseConfigLog(output), nil
+}
+
+// parseConfigLog parses `git log --format=%H%x1f%aI%x1f%s`
+func parseConfigLog(output string) []ConfigCommit {
+	var commits []ConfigCommit
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, "\x1f")
+		if len(parts) != 3 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, parts[1])
+		commits = append(commits, ConfigCommit{Hash: parts[0], Date: date, Subject: parts[2]})
+	}
+	return commits
+}
+
+// ConfigCommitChanges lists the files changed by a snapshot
+func ConfigCommitChanges(hash string) ([]ConfigChange, error) {
+	output, err := configGit("show", "--root", "--format=", "--name-status", hash)
+	if err != nil {
+		return nil, err
+	}
+	return parseNameStatus(output), nil
+}
+
+// parseNameStatus parses `git show --name-status` into host paths
+func parseNameStatus(output string) []ConfigChange {
+	var changes []ConfigChange
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\t", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		changes = append(changes, ConfigChange{Status: fields[0][:1], Path: "/" + fields[1]})
+	}
+	return changes
+}
+
+// ConfigFileDiff returns the change a snapshot made to one file
+func ConfigFileDiff(hash, hostPath string) (string, error) {
+	return configGit("show", "--root", "--format=", hash, "--", strings.TrimPrefix(hostPath, "/"))
+}
+
+// RestoreConfigFile writes a file back to its content at revision rev (a
+// snapshot hash, or hash^ for the version before it), then records the
+// restore as a new snapshot
+func RestoreConfigFile(rev, hostPath string) error {
+	if !path.IsAbs(hostPath) || path.Clean(hostPath) != hostPath {
+		return fmt.Errorf("invalid path %q", hostPath)
+	}
+	repoPath := strings.TrimPrefix(hostPath, "/")
+	short := ConfigCommit{Hash: strings.TrimSuffix(rev, "^")}.ShortHash()
+	if strings.HasSuffix(rev, "^") {
+		short += "^"
+	}
+
+	tree, err := configGit("ls-tree", rev, "--", repoPath)
+	if err != nil || strings.TrimSpace(tree) == "" {
+		return fmt.Errorf("%s does not exist in snapshot %s", hostPath, short)
+	}
+	content, err := Command("git", "-C", ConfigHistoryDir, "show", rev+":"+repoPath).Output()
+	if err != nil {
+		return fmt.Errorf("failed to read %s from snapshot %s", hostPath, short)
+	}
+
+	if err := MkdirAll(path.Dir(hostPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", path.Dir(hostPath), err)
+	}
+	if strings.HasPrefix(tree, "120000") {
+		// Symlinks such as sites-enabled entries are stored as their target
+		// Write through the transport so the restore is recorded once, below
+		t := CurrentTransport()
+		_ = t.Remove(hostPath)
+		if err := t.Symlink(string(content), hostPath); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", hostPath, err)
+		}
+	} else {
+		mode := os.FileMode(0644)
+		if info, err := Stat(hostPath); err == nil {
+			mode = info.Mode().Perm()
+		}
+		if err := CurrentTransport().WriteFile(hostPath, content, mode); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", hostPath, err)
+		}
+	}
+
+	_, err = SnapshotConfig(fmt.Sprintf("Restore %s from %s", hostPath, short))
+	return err
+}
@@ -0,0 +1,131 @@
+package system
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useConfigHistory points the history at a temporary repository tracking dir
+func useConfigHistory(t *testing.T) (tracked string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	origDir, origPaths := ConfigHistoryDir, TrackedConfigPaths
+	t.Cleanup(func() { ConfigHistoryDir, TrackedConfigPaths = origDir, origPaths })
+
+	tracked = filepath.Join(t.TempDir(), "nginx")
+	if err := os.MkdirAll(tracked, 0755); err != nil {
+		t.Fatal(err)
+	}
+	ConfigHistoryDir = filepath.Join(t.TempDir(), "history")
+	TrackedConfigPaths = []string{tracked, filepath.Join(t.TempDir(), "missing.conf")}
+	return tracked
+}
+
+func TestConfigHistorySnapshotAndRestore(t *testing.T) {
+	tracked := useConfigHistory(t)
+	site := filepath.Join(tracked, "shop.conf")
+	if err := os.WriteFile(site, []byte("listen 80;\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if changed, err := SnapshotConfig("Add site shop"); err != nil || !changed {
+		t.Fatalf("first snapshot: changed=%v err=%v", changed, err)
+	}
+	if changed, err := SnapshotConfig("Nothing to see"); err != nil || changed {
+		t.Fatalf("unchanged snapshot: changed=%v err=%v", changed, err)
+	}
+
+	if err := os.WriteFile(site, []byte("listen 443 ssl;\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SnapshotConfig("Enable SSL for shop"); err != nil {
+		t.Fatal(err)
+	}
+
+	commits, err := ConfigHistory(10)
+	if err != nil || len(commits) != 2 {
+		t.Fatalf("expected 2 snapshots, got %+v (%v)", commits, err)
+	}
+	if commits[0].Subject != "Enable SSL for shop" || commits[0].Date.IsZero() {
+		t.Errorf("unexpected latest snapshot %+v", commits[0])
+	}
+
+	changes, err := ConfigCommitChanges(commits[0].Hash)
+	if err != nil || len(changes) != 1 || changes[0].Status != "M" || changes[0].Path != site {
+		t.Fatalf("unexpected changes %+v (%v)", changes, err)
+	}
+	diff, err := ConfigFileDiff(commits[0].Hash, site)
+	if err != nil || !strings.Contains(diff, "+listen 443 ssl;") {
+		t.Errorf("unexpected diff %q (%v)", diff, err)
+	}
+
+	// Roll back to the version before the SSL change
+	if err := RestoreConfigFile(commits[0].Hash+"^", site); err != nil {
+		t.Fatalf("RestoreConfigFile: %v", err)
+	}
+	if data, _ := os.ReadFile(site); string(data) != "listen 80;\n" {
+		t.Errorf("file not restored: %q", data)
+	}
+	if commits, _ := ConfigHistory(10); len(commits) != 3 || !strings.HasPrefix(commits[0].Subject, "Restore ") {
+		t.Errorf("restore not recorded: %+v", commits)
+	}
+}
+
+func TestWriteFileRecordsConfigHistory(t *testing.T) {
+	tracked := useConfigHistory(t)
+	site := filepath.Join(tracked, "shop.conf")
+	if err := WriteFile(site, []byte("listen 80;\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// An edit made outside ravact gets its own entry before the next change
+	if err := os.WriteFile(site, []byte("listen 8080;\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(site, []byte("listen 443 ssl;\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(site); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(filepath.Join(t.TempDir(), "untracked.conf"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	commits, err := ConfigHistory(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var subjects []string
+	for _, c := range commits {
+		subjects = append(subjects, c.Subject)
+	}
+	want := []string{"Remove " + site, "Update " + site, "Changes made outside ravact", "Update " + site}
+	if strings.Join(subjects, "|") != strings.Join(want, "|") {
+		t.Errorf("history = %q, want %q", subjects, want)
+	}
+}
+
+func TestIsTrackedConfigPath(t *testing.T) {
+	tests := map[string]bool{
+		"/etc/nginx/sites-available/shop":  true,
+		"/etc/php/8.3/fpm/pool.d/www.conf": true,
+		"/etc/php/8.3/fpm/php.ini":         true,
+		"/etc/systemd/system/app.service":  true,
+		"/etc/systemd/system/app.timer":    false,
+		"/etc/caddy/Caddyfile":             true,
+		"/etc/nginx/mime.types":            false,
+		"/var/lib/ravact/store.json":       false,
+		"etc/nginx/nginx.conf":             false,
+	}
+	for p, want := range tests {
+		if got := IsTrackedConfigPath(p); got != want {
+			t.Errorf("IsTrackedConfigPath(%q) = %v, want %v", p, got, want)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package system
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DBAccessDir holds the revert script of each open remote database grant
+const DBAccessDir = "/etc/ravact/db-access"
+
+// Limits on how long a remote database grant may stay open
+const (
+	MinDBAccessDuration = 5 * time.Minute
+	MaxDBAccessDuration = 24 * time.Hour
+)
+
+// dbAccessMarker tags the firewall rules and pg_hba.conf lines of a grant
+const dbAccessMarker = "ravact-db-access"
+
+// DBAccess opens a database port to a single client IP for a limited time.
+// The database is made to listen on all addresses, the firewall lets only
+// the client through, and a systemd timer reverts both when it expires.
+type DBAccess struct {
+	Engine     string // "mysql" or "postgresql"
+	Port       int
+	ConfigPath string // my.cnf or postgresql.conf
+	HBAPath    string // pg_hba.conf (PostgreSQL only)
+	ClientIP   string
+	Duration   time.Duration
+}
+
+// DBAccessGrant is an open grant read back from its revert script
+type DBAccessGrant struct {
+	Engine   string
+	ClientIP string
+	Port     int
+	Expires  time.Time
+}
+
+// Validate checks the client IP, port, and duration
+func (a DBAccess) Validate() error {
+	if a.Engine != "mysql" && a.Engine != "postgresql" {
+		return fmt.Errorf("unsupported database engine: %s", a.Engine)
+	}
+	if a.Port < 1 || a.Port > 65535 {
+		return fmt.Errorf("invalid port: %d", a.Port)
+	}
+	ip := net.ParseIP(a.ClientIP)
+	if ip == nil {
+		return fmt.Errorf("invalid client IP: %q", a.ClientIP)
+	}
+	if ip.IsUnspecified() {
+		return fmt.Errorf("client IP must be a single address, not %s", a.ClientIP)
+	}
+	if a.Duration < MinDBAccessDuration || a.Duration > MaxDBAccessDuration {
+		return fmt.Errorf("duration must be between %s and %s", MinDBAccessDuration, MaxDBAccessDuration)
+	}
+	if a.Engine == "postgresql" && a.HBAPath == "" {
+		return fmt.Errorf("pg_hba.conf path is unknown")
+	}
+	return nil
+}
+
+// DBAccessUnit returns the transient systemd unit that expires a grant
+func DBAccessUnit(engine string) string {
+	return dbAccessMarker + "-" + engine
+}
+
+// DBAccessRevertPath returns where the revert script of a grant lives
+func DBAccessRevertPath(engine string) string {
+	return filepath.Join(DBAccessDir, engine+"-revert.sh")
+}
+
+// clientCIDR returns the client as a single-host network
+func (a DBAccess) clientCIDR() string {
+	if strings.Contains(a.ClientIP, ":") {
+		return a.ClientIP + "/128"
+	}
+	return a.ClientIP + "/32"
+}
+
+// mysqlDropInDir returns a directory mysqld reads after the main config, so
+// a drop-in there can override bind-address without editing that file
+func (a DBAccess) mysqlDropInDir() string {
+	if dir := filepath.Dir(a.ConfigPath); strings.HasSuffix(dir, ".d") {
+		return dir
+	}
+	for _, dir := range []string{"/etc/mysql/mysql.conf.d", "/etc/mysql/mariadb.conf.d", "/etc/my.cnf.d"} {
+		if _, err := Stat(dir); err == nil {
+			return dir
+		}
+	}
+	return "/etc/mysql/conf.d"
+}
+
+// firewallOpen returns shell that lets only the client reach the port. It
+// refuses to continue without a firewall, since the database is about to
+// listen on every address.
+func (a DBAccess) firewallOpen() string {
+	port := strconv.Itoa(a.Port)
+	iptables := "iptables"
+	if strings.Contains(a.ClientIP, ":") {
+		iptables = "ip6tables"
+	}
+	var b strings.Builder
+	b.WriteString("if command -v ufw >/dev/null 2>&1 && ufw status | grep -q '^Status: active'; then\n")
+	fmt.Fprintf(&b, "    ufw allow from %s to any port %s proto tcp comment %s\n", a.ClientIP, port, dbAccessMarker)
+	b.WriteString("elif command -v firewall-cmd >/dev/null 2>&1 && firewall-cmd --state >/dev/null 2>&1; then\n")
+	fmt.Fprintf(&b, "    firewall-cmd --add-rich-rule=%s\n", ShellQuote(a.richRule()))
+	fmt.Fprintf(&b, "elif command -v %s >/dev/null 2>&1; then\n", iptables)
+	fmt.Fprintf(&b, "    %s -I INPUT -p tcp --dport %s -m comment --comment %s -j DROP\n", iptables, port, dbAccessMarker)
+	fmt.Fprintf(&b, "    %s -I INPUT -p tcp -s %s --dport %s -m comment --comment %s -j ACCEPT\n", iptables, a.ClientIP, port, dbAccessMarker)
+	b.WriteString("else\n")
+	b.WriteString("    echo 'No firewall found; refusing to expose the database port' >&2\n")
+	b.WriteString("    exit 1\n")
+	b.WriteString("fi\n")
+	return b.String()
+}
+
+// firewallClose returns shell that removes the rules added by firewallOpen
+func (a DBAccess) firewallClose() string {
+	port := strconv.Itoa(a.Port)
+	iptables := "iptables"
+	if strings.Contains(a.ClientIP, ":") {
+		iptables = "ip6tables"
+	}
+	var b strings.Builder
+	b.WriteString("if command -v ufw >/dev/null 2>&1; then\n")
+	fmt.Fprintf(&b, "    ufw delete allow from %s to any port %s proto tcp\n", a.ClientIP, port)
+	b.WriteString("fi\n")
+	b.WriteString("if command -v firewall-cmd >/dev/null 2>&1; then\n")
+	fmt.Fprintf(&b, "    firewall-cmd --remove-rich-rule=%s\n", ShellQuote(a.richRule()))
+	b.WriteString("fi\n")
+	fmt.Fprintf(&b, "if command -v %s >/dev/null 2>&1; then\n", iptables)
+	fmt.Fprintf(&b, "    %s -D INPUT -p tcp -s %s --dport %s -m comment --comment %s -j ACCEPT\n", iptables, a.ClientIP, port, dbAccessMarker)
+	fmt.Fprintf(&b, "    %s -D INPUT -p tcp --dport %s -m comment --comment %s -j DROP\n", iptables, port, dbAccessMarker)
+	b.WriteString("fi\n")
+	return b.String()
+}
+
+// richRule returns the firewalld rule admitting the client
+func (a DBAccess) richRule() string {
+	family := "ipv4"
+	if strings.Contains(a.ClientIP, ":") {
+		family = "ipv6"
+	}
+	return fmt.Sprintf(`rule family="%s" source address="%s" port port="%d" protocol="tcp" accept`, family, a.ClientIP, a.Port)
+}
+
+// databaseOpen returns shell that makes the database listen remotely and
+// accept the client
+func (a DBAccess) databaseOpen() string {
+	var b strings.Builder
+	if a.Engine == "mysql" {
+		dropIn := filepath.Join(a.mysqlDropInDir(), "zz-"+dbAccessMarker+".cnf")
+		fmt.Fprintf(&b, "printf '[mysqld]\\nbind-address = 0.0.0.0\\n' > %s\n", ShellQuote(dropIn))
+		b.WriteString("systemctl restart mysql 2>/dev/null || systemctl restart mysqld 2>/dev/null || systemctl restart mariadb\n")
+		return b.String()
+	}
+	line := fmt.Sprintf("host all all %s scram-sha-256 # %s", a.clientCIDR(), dbAccessMarker)
+	fmt.Fprintf(&b, "echo %s >> %s\n", ShellQuote(line), ShellQuote(a.HBAPath))
+	b.WriteString("sudo -u postgres psql -c \"ALTER SYSTEM SET listen_addresses = '*'\"\n")
+	b.WriteString("systemctl restart postgresql\n")
+	return b.String()
+}
+
+// databaseClose returns shell that undoes databaseOpen
+func (a DBAccess) databaseClose() string {
+	var b strings.Builder
+	if a.Engine == "mysql" {
+		dropIn := filepath.Join(a.mysqlDropInDir(), "zz-"+dbAccessMarker+".cnf")
+		fmt.Fprintf(&b, "rm -f %s\n", ShellQuote(dropIn))
+		b.WriteString("systemctl restart mysql 2>/dev/null || systemctl restart mysqld 2>/dev/null || systemctl restart mariadb\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "sed -i '/# %s$/d' %s\n", dbAccessMarker, ShellQuote(a.HBAPath))
+	b.WriteString("sudo -u postgres psql -c 'ALTER SYSTEM RESET listen_addresses'\n")
+	b.WriteString("systemctl restart postgresql\n")
+	return b.String()
+}
+
+// RevertScript returns the script that closes the grant. Its header records
+// the grant so ActiveDBAccess can report it.
+func (a DBAccess) RevertScript(now time.Time) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/bash\n")
+	fmt.Fprintf(&b, "# ravact remote %s access\n", a.Engine)
+	fmt.Fprintf(&b, "# client: %s\n", a.ClientIP)
+	fmt.Fprintf(&b, "# port: %d\n", a.Port)
+	fmt.Fprintf(&b, "# expires: %s\n", now.Add(a.Duration).UTC().Format(time.RFC3339))
+	b.WriteString(a.firewallClose())
+	b.WriteString(a.databaseClose())
+	fmt.Fprintf(&b, "rm -f %s\n", ShellQuote(DBAccessRevertPath(a.Engine)))
+	return b.String()
+}
+
+// Script returns the root script that opens the grant. The expiry timer is
+// armed before anything is opened, so a failure part way through is still
+// reverted. An earlier grant for the same engine is closed first.
+func (a DBAccess) Script(now time.Time) string {
+	revert := DBAccessRevertPath(a.Engine)
+	unit := DBAccessUnit(a.Engine)
+
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	fmt.Fprintf(&b, "systemctl stop %s.timer %s.service 2>/dev/null || true\n", unit, unit)
+	fmt.Fprintf(&b, "systemctl reset-failed %s.timer %s.service 2>/dev/null || true\n", unit, unit)
+	fmt.Fprintf(&b, "if [ -f %s ]; then bash %s; fi\n", ShellQuote(revert), ShellQuote(revert))
+	fmt.Fprintf(&b, "mkdir -p %s\n", ShellQuote(DBAccessDir))
+	fmt.Fprintf(&b, "cat > %s <<'RAVACT_EOF'\n%sRAVACT_EOF\n", ShellQuote(revert), a.RevertScript(now))
+	fmt.Fprintf(&b, "chmod 700 %s\n", ShellQuote(revert))
+	fmt.Fprintf(&b, "systemd-run --unit=%s --on-active=%d /bin/bash %s\n", unit, int(a.Duration.Seconds()), ShellQuote(revert))
+	b.WriteString(a.firewallOpen())
+	b.WriteString(a.databaseOpen())
+	fmt.Fprintf(&b, "echo 'Port %d is open to %s until %s'\n", a.Port, a.ClientIP, now.Add(a.Duration).Local().Format("15:04 MST"))
+	return b.String()
+}
+
+// DBAccessCloseScript returns a root script that closes a grant now
+func DBAccessCloseScript(engine string) string {
+	revert := DBAccessRevertPath(engine)
+	unit := DBAccessUnit(engine)
+	return fmt.Sprintf("systemctl stop %s.timer 2>/dev/null || true\nif [ -f %s ]; then bash %s; fi\necho 'Remote access closed'\n",
+		unit, ShellQuote(revert), ShellQuote(revert))
+}
+
+// ActiveDBAccess returns the open grant for an engine, if any
+func ActiveDBAccess(engine string) (DBAccessGrant, bool) {
+	data, err := ReadFile(DBAccessRevertPath(engine))
+	if err != nil {
+		return DBAccessGrant{}, false
+	}
+	return parseDBAccessGrant(engine, string(data))
+}
+
+// parseDBAccessGrant reads the header of a revert script
+func parseDBAccessGrant(engine, script string) (DBAccessGrant, bool) {
+	grant := DBAccessGrant{Engine: engine}
+	for _, line := range strings.Split(script, "\n") {
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "# "), ": ")
+		if !ok || !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		switch key {
+		case "client":
+			grant.ClientIP = value
+		case "port":
+			grant.Port, _ = strconv.Atoi(value)
+		case "expires":
+			grant.Expires, _ = time.Parse(time.RFC3339, value)
+		}
+	}
+	return grant, grant.ClientIP != "" && !grant.Expires.IsZero()
+}
+
+// DBTunnelCommand returns the ssh command a developer runs on their own
+// machine to reach a database through the server. Nothing is opened on
+// the server; the remote `sleep` ends the tunnel after the duration once
+// no client is connected through it.
+func DBTunnelCommand(server Server, remotePort, localPort int, duration time.Duration) string {
+	args := []string{"ssh", "-o", "ExitOnForwardFailure=yes"}
+	if server.Port != 0 && server.Port != 22 {
+		args = append(args, "-p", strconv.Itoa(server.Port))
+	}
+	if server.IdentityFile != "" {
+		args = append(args, "-i", ShellQuote(server.IdentityFile))
+	}
+	args = append(args,
+		"-L", fmt.Sprintf("%d:127.0.0.1:%d", localPort, remotePort),
+		server.Destination(),
+		"sleep", strconv.Itoa(int(duration.Seconds())))
+	return strings.Join(args, " ")
+}
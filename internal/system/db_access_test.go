@@ -0,0 +1,95 @@
+package system
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDBAccessValidate(t *testing.T) {
+	valid := DBAccess{Engine: "mysql", Port: 3306, ClientIP: "203.0.113.7", Duration: time.Hour}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*DBAccess)
+	}{
+		{"bad IP", func(a *DBAccess) { a.ClientIP = "203.0.113" }},
+		{"unspecified IP", func(a *DBAccess) { a.ClientIP = "0.0.0.0" }},
+		{"too short", func(a *DBAccess) { a.Duration = time.Minute }},
+		{"too long", func(a *DBAccess) { a.Duration = 48 * time.Hour }},
+		{"bad engine", func(a *DBAccess) { a.Engine = "redis" }},
+		{"postgres without pg_hba", func(a *DBAccess) { a.Engine = "postgresql" }},
+	}
+	for _, tt := range tests {
+		a := valid
+		tt.modify(&a)
+		if err := a.Validate(); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestDBAccessScript(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	a := DBAccess{
+		Engine:   "postgresql",
+		Port:     5432,
+		HBAPath:  "/etc/postgresql/16/main/pg_hba.conf",
+		ClientIP: "203.0.113.7",
+		Duration: 2 * time.Hour,
+	}
+	script := a.Script(now)
+
+	timer := strings.Index(script, "systemd-run --unit=ravact-db-access-postgresql --on-active=7200")
+	open := strings.Index(script, "ufw allow from 203.0.113.7 to any port 5432 proto tcp")
+	if timer < 0 || open < 0 || timer > open {
+		t.Errorf("expected the expiry timer to be armed before the port is opened:\n%s", script)
+	}
+	for _, want := range []string{
+		"host all all 203.0.113.7/32 scram-sha-256 # ravact-db-access",
+		"ALTER SYSTEM SET listen_addresses = '*'",
+		"ALTER SYSTEM RESET listen_addresses",
+		"sed -i '/# ravact-db-access$/d' /etc/postgresql/16/main/pg_hba.conf",
+		"ufw delete allow from 203.0.113.7 to any port 5432 proto tcp",
+		"refusing to expose the database port",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+
+	grant, ok := parseDBAccessGrant("postgresql", a.RevertScript(now))
+	if !ok || grant.ClientIP != "203.0.113.7" || grant.Port != 5432 || !grant.Expires.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("unexpected grant: %+v, %v", grant, ok)
+	}
+	if _, ok := parseDBAccessGrant("mysql", "#!/bin/bash\n"); ok {
+		t.Error("expected no grant without a header")
+	}
+}
+
+func TestDBAccessScript_MySQLIPv6(t *testing.T) {
+	a := DBAccess{Engine: "mysql", Port: 3306, ConfigPath: "/etc/mysql/mysql.conf.d/mysqld.cnf", ClientIP: "2001:db8::7", Duration: time.Hour}
+	script := a.Script(time.Now())
+	for _, want := range []string{
+		"> /etc/mysql/mysql.conf.d/zz-ravact-db-access.cnf",
+		"bind-address = 0.0.0.0",
+		"ip6tables -I INPUT -p tcp -s 2001:db8::7 --dport 3306",
+		`rule family="ipv6" source address="2001:db8::7"`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+}
+
+func TestDBTunnelCommand(t *testing.T) {
+	server := Server{Host: "db.example.com", User: "deploy", Port: 2222}
+	got := DBTunnelCommand(server, 3306, 13306, time.Hour)
+	want := "ssh -o ExitOnForwardFailure=yes -p 2222 -L 13306:127.0.0.1:3306 deploy@db.example.com sleep 3600"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,36 @@
+package system
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"
+	new := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n"
+	want := `--- old
++++ new
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -11,3 +11,4 @@
+ k
+ l
+ m
++n
+`
+	if got := UnifiedDiff("old", "new", old, new); got != want {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+	if got := UnifiedDiff("old", "new", old, old); got != "" {
+		t.Errorf("expected no diff for equal content, got:\n%s", got)
+	}
+}
+
+func TestUnifiedDiff_NewFile(t *testing.T) {
+	want := "--- /dev/null\n+++ site\n@@ -0,0 +1,2 @@\n+server {\n+}\n"
+	if got := UnifiedDiff("/dev/null", "site", "", "server {\n}\n"); got != want {
+		t.Errorf("unexpected diff:\n%q\nwant:\n%q", got, want)
+	}
+}
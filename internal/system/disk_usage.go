@@ -0,0 +1,314 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DiskCategory is a kind of space hog found on the host, with the
+// cleanup that is safe to run for it
+type DiskCategory struct {
+	ID      string
+	Title   string
+	Size    int64
+	Items   []DiskItem // Largest first
+	Cleanup *DiskCleanup
+	Note    string // What the cleanup keeps, or why there is none
+}
+
+// DiskItem is a file or directory counted in a category
+type DiskItem struct {
+	Path string
+	Size int64
+}
+
+// DiskCleanup is a one-key cleanup, run as root
+type DiskCleanup struct {
+	Title  string
+	Script string
+}
+
+// diskProjectRoots are searched for project directories
+var diskProjectRoots = []string{"/var/www", "/home", "/srv"}
+
+// journalKeep is the journal size left by the vacuum
+const journalKeep = "200M"
+
+// releasesKeep is the number of releases kept besides the current one
+const releasesKeep = 4
+
+// ScanDiskUsage measures the usual space hogs, largest category first
+func ScanDiskUsage() []DiskCategory {
+	categories := []DiskCategory{
+		scanPackageCache(),
+		scanOldKernels(),
+		scanJournal(),
+		scanRotatedLogs(),
+	}
+	categories = append(categories, scanProjects()...)
+	sort.SliceStable(categories, func(i, j int) bool { return categories[i].Size > categories[j].Size })
+	return categories
+}
+
+// newDiskCategory totals and sorts the items of a category
+func newDiskCategory(id, title string, items []DiskItem) DiskCategory {
+	c := DiskCategory{ID: id, Title: title, Items: items}
+	sort.SliceStable(c.Items, func(i, j int) bool { return c.Items[i].Size > c.Items[j].Size })
+	for _, item := range items {
+		c.Size += item.Size
+	}
+	return c
+}
+
+// duSizes returns the apparent size of each path that exists
+func duSizes(paths []string) []DiskItem {
+	if len(paths) == 0 {
+		return nil
+	}
+	// du exits non-zero when any path is unreadable but still reports the rest
+	output, _ := Command("du", append([]string{"-sb", "--"}, paths...)...).Output()
+	return parseDu(string(output))
+}
+
+// parseDu reads "size<TAB>path" lines from du -sb
+func parseDu(output string) []DiskItem {
+	var items []DiskItem
+	for _, line := range strings.Split(output, "\n") {
+		size, path, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(size), 10, 64)
+		if err != nil {
+			continue
+		}
+		items = append(items, DiskItem{Path: path, Size: n})
+	}
+	return items
+}
+
+// existing returns the paths that exist on the active host
+func existing(paths ...string) []string {
+	var found []string
+	for _, path := range paths {
+		if _, err := Stat(path); err == nil {
+			found = append(found, path)
+		}
+	}
+	return found
+}
+
+// scanPackageCache measures the downloaded package files
+func scanPackageCache() DiskCategory {
+	caches := []struct{ path, clean string }{
+		{"/var/cache/apt/archives", "apt-get clean"},
+		{"/var/cache/dnf", "dnf clean all"},
+		{"/var/cache/yum", "yum clean all"},
+		{"/var/cache/pacman/pkg", "pacman -Sc --noconfirm"},
+	}
+	var paths, scripts []string
+	for _, cache := range caches {
+		if len(existing(cache.path)) > 0 {
+			paths = append(paths, cache.path)
+			scripts = append(scripts, cache.clean)
+		}
+	}
+	c := newDiskCategory("package_cache", "Package cache", duSizes(paths))
+	if len(scripts) > 0 {
+		c.Cleanup = &DiskCleanup{Title: "Clean package cache", Script: strings.Join(scripts, "\n")}
+		c.Note = "Downloaded packages; they are fetched again when needed"
+	}
+	return c
+}
+
+// oldKernels returns the installed kernels older than the running one.
+// Newer kernels are kept since they are waiting for a reboot.
+func oldKernels(installed []string, running string) []string {
+	dotted := func(v string) string { return strings.ReplaceAll(v, "-", ".") }
+	var old []string
+	for _, k := range installed {
+		if k != running && versionLess(dotted(k), dotted(running)) {
+			old = append(old, k)
+		}
+	}
+	return old
+}
+
+// scanOldKernels measures the modules of kernels older than the running one
+func scanOldKernels() DiskCategory {
+	var installed []string
+	if entries, err := ReadDir("/lib/modules"); err == nil {
+		for _, e := range entries {
+			if e.IsDir() {
+				installed = append(installed, e.Name())
+			}
+		}
+	}
+	output, _ := Command("uname", "-r").Output()
+	var paths []string
+	for _, k := range oldKernels(installed, strings.TrimSpace(string(output))) {
+		paths = append(paths, "/lib/modules/"+k)
+	}
+	c := newDiskCategory("kernels", "Old kernels", duSizes(paths))
+	switch {
+	case len(paths) == 0:
+	case len(existing("/var/lib/dpkg")) > 0:
+		c.Cleanup = &DiskCleanup{Title: "apt autoremove", Script: "DEBIAN_FRONTEND=noninteractive apt-get autoremove --purge -y"}
+		c.Note = "Removes kernels and packages nothing depends on any more"
+	case Command("which", "dnf").Run() == nil:
+		c.Cleanup = &DiskCleanup{Title: "Remove old kernels", Script: "dnf remove --oldinstallonly -y"}
+		c.Note = "dnf keeps the running kernel"
+	}
+	return c
+}
+
+// scanJournal measures the systemd journal
+func scanJournal() DiskCategory {
+	c := newDiskCategory("journal", "Journal logs", duSizes(existing("/var/log/journal", "/run/log/journal")))
+	if len(c.Items) > 0 {
+		c.Cleanup = &DiskCleanup{Title: "Vacuum journal", Script: "journalctl --vacuum-size=" + journalKeep}
+		c.Note = "Keeps the newest " + journalKeep
+	}
+	return c
+}
+
+// rotatedLogFind matches logrotate's old copies under /var/log
+const rotatedLogFind = `find /var/log -type f \( -name '*.gz' -o -name '*.[0-9]' -o -name '*.old' \)`
+
+// scanRotatedLogs measures the logs logrotate has already rotated
+func scanRotatedLogs() DiskCategory {
+	output, _ := Command("bash", "-c", rotatedLogFind+` -printf '%s\t%p\n'`).Output()
+	c := newDiskCategory("rotated_logs", "Rotated logs", parseDu(string(output)))
+	if len(c.Items) > 0 {
+		c.Cleanup = &DiskCleanup{
+			Title:  "Delete rotated logs",
+			Script: rotatedLogFind + " -delete",
+		}
+		c.Note = "Current logs are kept"
+	}
+	return c
+}
+
+// projectFind lists node_modules, Laravel log, and releases directories
+// under the roots without descending into node_modules or .git
+func projectFind(roots []string) string {
+	quoted := make([]string, len(roots))
+	for i, root := range roots {
+		quoted[i] = ShellQuote(root)
+	}
+	return "find " + strings.Join(quoted, " ") + ` -maxdepth 6 -type d \( -name node_modules -prune -print -o -name .git -prune -o -path '*/storage/logs' -print -o -name releases -prune -print \)`
+}
+
+// scanProjects measures node_modules, Laravel logs, and old releases in
+// the project directories
+func scanProjects() []DiskCategory {
+	roots := existing(diskProjectRoots...)
+	if len(roots) == 0 {
+		return nil
+	}
+	output, _ := Command("bash", "-c", projectFind(roots)).Output()
+	var modules, logs, releases []string
+	for _, path := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		switch filepath.Base(path) {
+		case "node_modules":
+			modules = append(modules, path)
+		case "logs":
+			logs = append(logs, path)
+		case "releases":
+			releases = append(releases, path)
+		}
+	}
+
+	nodeModules := newDiskCategory("node_modules", "node_modules", duSizes(modules))
+	nodeModules.Note = "Rebuilt by npm ci; remove by hand for projects that no longer build"
+
+	laravelLogs := newDiskCategory("laravel_logs", "Laravel logs", duSizes(logs))
+	if len(logs) > 0 {
+		quoted := make([]string, len(logs))
+		for i, path := range logs {
+			quoted[i] = ShellQuote(path)
+		}
+		laravelLogs.Cleanup = &DiskCleanup{
+			Title:  "Truncate Laravel logs",
+			Script: "find " + strings.Join(quoted, " ") + " -maxdepth 1 -type f -name '*.log' -exec truncate -s 0 {} +",
+		}
+		laravelLogs.Note = "Empties the files so open handles keep working"
+	}
+
+	var old []string
+	for _, dir := range releases {
+		old = append(old, scanOldReleases(dir)...)
+	}
+	oldReleases := newDiskCategory("releases", "Old releases", duSizes(old))
+	if len(old) > 0 {
+		quoted := make([]string, len(old))
+		for i, path := range old {
+			quoted[i] = ShellQuote(path)
+		}
+		oldReleases.Cleanup = &DiskCleanup{Title: "Delete old releases", Script: "rm -rf -- " + strings.Join(quoted, " ")}
+		oldReleases.Note = fmt.Sprintf("Keeps the current release and the %d newest", releasesKeep)
+	}
+	return []DiskCategory{nodeModules, laravelLogs, oldReleases}
+}
+
+// scanOldReleases returns the release directories of a zero-downtime
+// layout (releases/ next to a current symlink) that can be removed
+func scanOldReleases(dir string) []string {
+	output, err := Command("readlink", "-f", filepath.Join(filepath.Dir(dir), "current")).Output()
+	if err != nil {
+		return nil
+	}
+	entries, err := ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	var paths []string
+	for _, name := range oldReleases(names, filepath.Base(strings.TrimSpace(string(output))), releasesKeep) {
+		paths = append(paths, filepath.Join(dir, name))
+	}
+	return paths
+}
+
+// oldReleases returns the releases to remove: all but the current one
+// and the newest keep. Release names sort by age (timestamps or numbers).
+func oldReleases(names []string, current string, keep int) []string {
+	if !slices.Contains(names, current) {
+		// Not a release layout, or the current release is elsewhere
+		return nil
+	}
+	sorted := append([]string(nil), names...)
+	// Newest first; names that compare equal as numbers fall back to text
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if versionLess(a, b) != versionLess(b, a) {
+			return versionLess(b, a)
+		}
+		return a > b
+	})
+	var old []string
+	for i, name := range sorted {
+		if i >= keep && name != current {
+			old = append(old, name)
+		}
+	}
+	return old
+}
+
+// RootDiskUsage returns the size and use of the root filesystem
+func RootDiskUsage() (total, used uint64, err error) {
+	output, err := Command("df", "-B1", "--output=size,used", "/").Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to run df: %w", err)
+	}
+	return parseDfUsage(string(output))
+}
@@ -0,0 +1,54 @@
+package system
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDu(t *testing.T) {
+	output := "1048576\t/var/cache/apt/archives\nnot a size\t/x\n20\t/var/log/syslog.1\n\n"
+	want := []DiskItem{{"/var/cache/apt/archives", 1048576}, {"/var/log/syslog.1", 20}}
+	if got := parseDu(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDu() = %v, want %v", got, want)
+	}
+	c := newDiskCategory("x", "X", parseDu(output))
+	if c.Size != 1048596 || c.Items[0].Size != 1048576 {
+		t.Errorf("unexpected category: %+v", c)
+	}
+}
+
+func TestOldKernels(t *testing.T) {
+	installed := []string{"6.8.0-45-generic", "6.8.0-47-generic", "6.8.0-49-generic", "6.5.0-9-generic"}
+	got := oldKernels(installed, "6.8.0-47-generic")
+	want := []string{"6.8.0-45-generic", "6.5.0-9-generic"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("oldKernels() = %v, want %v", got, want)
+	}
+	if got := oldKernels([]string{"5.14.0-427.13.1.el9_4.x86_64", "5.14.0-503.11.1.el9_5.x86_64"}, "5.14.0-503.11.1.el9_5.x86_64"); len(got) != 1 || !strings.HasPrefix(got[0], "5.14.0-427") {
+		t.Errorf("unexpected RHEL kernels: %v", got)
+	}
+}
+
+func TestOldReleases(t *testing.T) {
+	names := []string{"1", "2", "3", "10", "11", "12", "9"}
+	got := oldReleases(names, "11", 4)
+	want := []string{"3", "2", "1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("oldReleases() = %v, want %v", got, want)
+	}
+	// A rolled back current release is never removed
+	if got := oldReleases(names, "2", 4); !reflect.DeepEqual(got, []string{"3", "1"}) {
+		t.Errorf("oldReleases() after a rollback = %v", got)
+	}
+	if got := oldReleases([]string{"v1", "v2"}, "", 0); got != nil {
+		t.Errorf("expected no releases without a current one, got %v", got)
+	}
+}
+
+func TestProjectFind(t *testing.T) {
+	got := projectFind([]string{"/var/www", "/home/my site"})
+	if !strings.HasPrefix(got, "find /var/www '/home/my site' -maxdepth 6 -type d") {
+		t.Errorf("unexpected find: %s", got)
+	}
+}
@@ -0,0 +1,164 @@
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// DockerGroup is the group whose members can use the Docker socket
+const DockerGroup = "docker"
+
+// composeProjectLabel is set by Compose on every container it creates
+const composeProjectLabel = "com.docker.compose.project"
+
+// DockerContainer is a container as listed by `docker ps -a`
+type DockerContainer struct {
+	ID      string
+	Name    string
+	Image   string
+	State   string // running, exited, restarting, ...
+	Status  string // e.g. "Up 3 hours"
+	Ports   string
+	Project string // Compose project, empty for standalone containers
+}
+
+// ComposeProject is a project as listed by `docker compose ls -a`
+type ComposeProject struct {
+	Name        string
+	Status      string // e.g. "running(2)" or "exited(1), running(1)"
+	ConfigFiles string
+}
+
+// parseDockerContainers parses `docker ps -a --format '{{json .}}'`, one
+// object per line
+func parseDockerContainers(data []byte) ([]DockerContainer, error) {
+	var containers []DockerContainer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var raw struct {
+			ID     string `json:"ID"`
+			Names  string `json:"Names"`
+			Image  string `json:"Image"`
+			State  string `json:"State"`
+			Status string `json:"Status"`
+			Ports  string `json:"Ports"`
+			Labels string `json:"Labels"`
+		}
+		if err := json.Unmarshal(line, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse docker ps output: %w", err)
+		}
+		c := DockerContainer{
+			ID:     raw.ID,
+			Name:   raw.Names,
+			Image:  raw.Image,
+			State:  raw.State,
+			Status: raw.Status,
+			Ports:  raw.Ports,
+		}
+		for _, label := range strings.Split(raw.Labels, ",") {
+			if value, ok := strings.CutPrefix(label, composeProjectLabel+"="); ok {
+				c.Project = value
+			}
+		}
+		containers = append(containers, c)
+	}
+	sort.Slice(containers, func(i, j int) bool {
+		if containers[i].Project != containers[j].Project {
+			return containers[i].Project < containers[j].Project
+		}
+		return containers[i].Name < containers[j].Name
+	})
+	return containers, nil
+}
+
+// parseComposeProjects parses `docker compose ls -a --format json`
+func parseComposeProjects(data []byte) ([]ComposeProject, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var projects []ComposeProject
+	if err := json.Unmarshal(data, &projects); err != nil {
+		return nil, fmt.Errorf("failed to parse docker compose ls output: %w", err)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects, nil
+}
+
+// DockerInstalled reports whether the docker CLI is on the active host
+func DockerInstalled() bool {
+	return Command("which", "docker").Run() == nil
+}
+
+// DockerContainers lists all containers, running or not
+func DockerContainers() ([]DockerContainer, error) {
+	output, err := Command("docker", "ps", "-a", "--no-trunc", "--format", "{{json .}}").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %s", strings.TrimSpace(string(output)))
+	}
+	return parseDockerContainers(output)
+}
+
+// ComposeProjects lists the Compose projects with containers on the host
+func ComposeProjects() ([]ComposeProject, error) {
+	output, err := Command("docker", "compose", "ls", "-a", "--format", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list compose projects: %s", strings.TrimSpace(string(output)))
+	}
+	return parseComposeProjects(output)
+}
+
+// DockerCommand returns the shell command and description for a start,
+// stop, or restart of a container, or of a whole Compose project when
+// project is true, as run from the execution screen
+func DockerCommand(action, name string, project bool) (command, description string) {
+	verb := map[string]string{"start": "Starting", "stop": "Stopping", "restart": "Restarting"}[action]
+	if project {
+		return fmt.Sprintf("sudo docker compose -p %s %s && sudo docker compose -p %s ps", ShellQuote(name), action, ShellQuote(name)),
+			fmt.Sprintf("%s compose project %s", verb, name)
+	}
+	return fmt.Sprintf("sudo docker %s %s && sudo docker inspect -f '{{.Name}}: {{.State.Status}}' %s", action, ShellQuote(name), ShellQuote(name)),
+		fmt.Sprintf("%s container %s", verb, name)
+}
+
+// parseDockerPort picks the host address from `docker port NAME PORT`,
+// which prints one binding per line such as 0.0.0.0:8080 and [::]:8080
+func parseDockerPort(output string) (string, error) {
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		host, port, err := net.SplitHostPort(strings.TrimSpace(line))
+		if err != nil {
+			continue
+		}
+		// A wildcard binding is reached on loopback from the host
+		if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+			host = "127.0.0.1"
+		}
+		return net.JoinHostPort(host, port), nil
+	}
+	return "", fmt.Errorf("no published port")
+}
+
+// ContainerUpstream returns the proxy_pass URL of a container port
+// published on the host. nginx on the host cannot resolve container names,
+// and container IPs change when they are recreated.
+func ContainerUpstream(name, port string) (string, error) {
+	output, err := Command("docker", "port", name, port).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("container %s does not publish port %s; publish it with -p 127.0.0.1:HOSTPORT:%s", name, port, port)
+	}
+	addr, err := parseDockerPort(string(output))
+	if err != nil {
+		return "", fmt.Errorf("container %s does not publish port %s", name, port)
+	}
+	return "http://" + addr, nil
+}
@@ -0,0 +1,68 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDockerContainers(t *testing.T) {
+	output := `{"ID":"a1","Names":"shop-redis-1","Image":"redis:7","State":"running","Status":"Up 2 hours","Ports":"127.0.0.1:6380->6379/tcp","Labels":"com.docker.compose.project=shop,com.docker.compose.service=redis"}
+{"ID":"b2","Names":"mailpit","Image":"axllent/mailpit","State":"exited","Status":"Exited (0) 3 days ago","Ports":"","Labels":""}
+`
+	containers, err := parseDockerContainers([]byte(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) != 2 {
+		t.Fatalf("got %d containers, want 2", len(containers))
+	}
+	// Standalone containers sort before project containers
+	if containers[0].Name != "mailpit" || containers[0].Project != "" || containers[0].State != "exited" {
+		t.Errorf("unexpected standalone container %+v", containers[0])
+	}
+	if containers[1].Project != "shop" || containers[1].Ports != "127.0.0.1:6380->6379/tcp" {
+		t.Errorf("unexpected compose container %+v", containers[1])
+	}
+
+	if _, err := parseDockerContainers([]byte("Cannot connect to the Docker daemon")); err == nil {
+		t.Error("expected an error for non-JSON output")
+	}
+}
+
+func TestParseComposeProjects(t *testing.T) {
+	projects, err := parseComposeProjects([]byte(`[{"Name":"shop","Status":"running(2)","ConfigFiles":"/var/www/shop/compose.yaml"},{"Name":"blog","Status":"exited(1)","ConfigFiles":"/srv/blog/docker-compose.yml"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 2 || projects[0].Name != "blog" || projects[1].ConfigFiles != "/var/www/shop/compose.yaml" {
+		t.Errorf("unexpected projects %+v", projects)
+	}
+	if projects, err := parseComposeProjects(nil); err != nil || projects != nil {
+		t.Errorf("empty output = %v, %v", projects, err)
+	}
+}
+
+func TestParseDockerPort(t *testing.T) {
+	for output, want := range map[string]string{
+		"0.0.0.0:8080\n[::]:8080\n": "127.0.0.1:8080",
+		"127.0.0.1:3001\n":          "127.0.0.1:3001",
+	} {
+		if got, err := parseDockerPort(output); err != nil || got != want {
+			t.Errorf("parseDockerPort(%q) = %q, %v; want %q", output, got, err, want)
+		}
+	}
+	if _, err := parseDockerPort(""); err == nil {
+		t.Error("expected an error when nothing is published")
+	}
+}
+
+func TestDockerCommand(t *testing.T) {
+	command, description := DockerCommand("stop", "shop", true)
+	if !strings.HasPrefix(command, "sudo docker compose -p shop stop") || description != "Stopping compose project shop" {
+		t.Errorf("project command = %q, %q", command, description)
+	}
+	command, _ = DockerCommand("restart", "mailpit", false)
+	if !strings.HasPrefix(command, "sudo docker restart mailpit") {
+		t.Errorf("container command = %q", command)
+	}
+}
@@ -0,0 +1,258 @@
+package system
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseDomains splits a comma- or space-separated list of domain names,
+// converting internationalized names to their ASCII (punycode) form and
+// validating each one. A wildcard is allowed only as the whole leftmost
+// label, as in *.example.com. Duplicates are dropped, keeping the order.
+func ParseDomains(s string) ([]string, error) {
+	var domains []string
+	seen := map[string]bool{}
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		domain, err := normalizeDomain(field)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[domain] {
+			seen[domain] = true
+			domains = append(domains, domain)
+		}
+	}
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("at least one domain is required")
+	}
+	return domains, nil
+}
+
+// normalizeDomain validates one domain and returns its ASCII form
+func normalizeDomain(domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	labels := strings.Split(domain, ".")
+
+	wildcard := labels[0] == "*"
+	if wildcard {
+		labels = labels[1:]
+		if len(labels) < 2 {
+			return "", fmt.Errorf("invalid domain %q: a wildcard needs a parent domain such as *.example.com", domain)
+		}
+	}
+	if len(labels) < 2 {
+		return "", fmt.Errorf("invalid domain %q: use a full name such as example.com", domain)
+	}
+
+	for i, label := range labels {
+		if strings.Contains(label, "*") {
+			return "", fmt.Errorf("invalid domain %q: * may only be the whole first label", domain)
+		}
+		ascii, err := labelToASCII(label)
+		if err != nil {
+			return "", fmt.Errorf("invalid domain %q: %w", domain, err)
+		}
+		labels[i] = ascii
+	}
+	if last := labels[len(labels)-1]; strings.Trim(last, "0123456789") == "" {
+		return "", fmt.Errorf("invalid domain %q: the top-level domain cannot be numeric", domain)
+	}
+
+	ascii := strings.Join(labels, ".")
+	if wildcard {
+		ascii = "*." + ascii
+	}
+	if len(ascii) > 253 {
+		return "", fmt.Errorf("invalid domain %q: longer than 253 characters", domain)
+	}
+	return ascii, nil
+}
+
+// labelToASCII validates a label, punycode-encoding it if it is not ASCII
+func labelToASCII(label string) (string, error) {
+	if label == "" {
+		return "", fmt.Errorf("empty label")
+	}
+	if !isASCII(label) {
+		encoded, err := punycodeEncode(label)
+		if err != nil {
+			return "", err
+		}
+		label = "xn--" + encoded
+	}
+	if len(label) > 63 {
+		return "", fmt.Errorf("label %q is longer than 63 characters", label)
+	}
+	if label[0] == '-' || label[len(label)-1] == '-' {
+		return "", fmt.Errorf("label %q cannot start or end with a hyphen", label)
+	}
+	for _, r := range label {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return "", fmt.Errorf("label %q contains %q", label, r)
+		}
+	}
+	return label, nil
+}
+
+// isASCII reports whether s has only ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// punycodeEncode encodes a label as in RFC 3492, without the xn-- prefix
+func punycodeEncode(label string) (string, error) {
+	const (
+		base        = 36
+		tMin        = 1
+		tMax        = 26
+		skew        = 38
+		damp        = 700
+		initialBias = 72
+		initialN    = 128
+	)
+	digit := func(d int) byte {
+		if d < 26 {
+			return byte('a' + d)
+		}
+		return byte('0' + d - 26)
+	}
+	adapt := func(delta, numPoints int, first bool) int {
+		if first {
+			delta /= damp
+		} else {
+			delta /= 2
+		}
+		delta += delta / numPoints
+		k := 0
+		for delta > ((base-tMin)*tMax)/2 {
+			delta /= base - tMin
+			k += base
+		}
+		return k + (base-tMin+1)*delta/(delta+skew)
+	}
+
+	runes := []rune(label)
+	var out []byte
+	for _, r := range runes {
+		if r < initialN {
+			out = append(out, byte(r))
+		}
+	}
+	basic := len(out)
+	handled := basic
+	if basic > 0 {
+		out = append(out, '-')
+	}
+
+	n, delta, bias := initialN, 0, initialBias
+	for handled < len(runes) {
+		m := int(^uint(0) >> 1)
+		for _, r := range runes {
+			if int(r) >= n && int(r) < m {
+				m = int(r)
+			}
+		}
+		delta += (m - n) * (handled + 1)
+		n = m
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+			}
+			if int(r) != n {
+				continue
+			}
+			q := delta
+			for k := base; ; k += base {
+				t := k - bias
+				if t < tMin {
+					t = tMin
+				} else if t > tMax {
+					t = tMax
+				}
+				if q < t {
+					break
+				}
+				out = append(out, digit(t+(q-t)%(base-t)))
+				q = (q - t) / (base - t)
+			}
+			out = append(out, digit(q))
+			bias = adapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+		delta++
+		n++
+	}
+	if len(out) == 0 {
+		return "", fmt.Errorf("cannot encode %q", label)
+	}
+	return string(out), nil
+}
+
+// secondLevelSuffixes are common second-level registries under country
+// code TLDs, so that example.co.uk is treated as an apex domain
+var secondLevelSuffixes = map[string]bool{
+	"co": true, "com": true, "net": true, "org": true, "ac": true, "gov": true, "edu": true,
+}
+
+// IsApexDomain reports whether a domain is a registrable name rather than
+// a subdomain. Without a public suffix list this recognizes example.com and
+// the common example.co.uk form.
+func IsApexDomain(domain string) bool {
+	labels := strings.Split(domain, ".")
+	switch len(labels) {
+	case 2:
+		return labels[0] != "*"
+	case 3:
+		return labels[0] != "*" && len(labels[2]) == 2 && secondLevelSuffixes[labels[1]]
+	}
+	return false
+}
+
+// ExpandDomains adds the www form of each apex domain and the apex of each
+// www domain, so both always reach the site. Other subdomains and
+// wildcards are kept as they are.
+func ExpandDomains(domains []string) []string {
+	var expanded []string
+	seen := map[string]bool{}
+	add := func(d string) {
+		if !seen[d] {
+			seen[d] = true
+			expanded = append(expanded, d)
+		}
+	}
+	for _, d := range domains {
+		add(d)
+		if IsApexDomain(d) {
+			add("www." + d)
+		} else if apex, ok := strings.CutPrefix(d, "www."); ok && IsApexDomain(apex) {
+			add(apex)
+		}
+	}
+	return expanded
+}
+
+// PrimaryDomain returns the name used for log files and the certificate,
+// which is the first domain without any wildcard prefix
+func PrimaryDomain(domains []string) string {
+	if len(domains) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(domains[0], "*.")
+}
+
+// HasWildcard reports whether any domain is a wildcard
+func HasWildcard(domains []string) bool {
+	for _, d := range domains {
+		if strings.HasPrefix(d, "*.") {
+			return true
+		}
+	}
+	return false
+}
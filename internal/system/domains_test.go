@@ -0,0 +1,56 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDomains(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{"example.com", []string{"example.com"}, false},
+		{"Example.COM, api.example.com  example.com", []string{"example.com", "api.example.com"}, false},
+		{"*.example.com", []string{"*.example.com"}, false},
+		{"münchen.de", []string{"xn--mnchen-3ya.de"}, false},
+		{"bücher.example.com", []string{"xn--bcher-kva.example.com"}, false},
+		{"例え.jp", []string{"xn--r8jz45g.jp"}, false},
+		{"example.com.", []string{"example.com"}, false},
+		{"", nil, true},
+		{"localhost", nil, true},
+		{"*.com", nil, true},
+		{"a.*.example.com", nil, true},
+		{"foo*.example.com", nil, true},
+		{"-bad.example.com", nil, true},
+		{"bad_name.example.com", nil, true},
+		{"example..com", nil, true},
+		{"10.0.0.1", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDomains(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDomains(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseDomains(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestExpandDomains(t *testing.T) {
+	got := ExpandDomains([]string{"example.com", "www.shop.co.uk", "api.example.com", "*.example.com", "www.example.com"})
+	want := []string{"example.com", "www.example.com", "www.shop.co.uk", "shop.co.uk", "api.example.com", "*.example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if PrimaryDomain([]string{"*.example.com", "example.com"}) != "example.com" {
+		t.Error("expected the wildcard prefix to be stripped")
+	}
+	if !HasWildcard(want) || HasWildcard([]string{"example.com"}) {
+		t.Error("unexpected HasWildcard result")
+	}
+}
@@ -0,0 +1,96 @@
+package system
+
+import "strings"
+
+// DownloadHelpers is bash defining verified_download, which install scripts
+// use to fetch release binaries:
+//
+//	verified_download URL DEST [GITHUB_REPO] [SHA256]
+//
+// The expected SHA-256 is, in order: the SHA256 argument, a published
+// <URL>.sha256 file, or the digest GitHub records for the release asset
+// when GITHUB_REPO (owner/name) is given. The download is deleted and the
+// function fails if no checksum is published or it does not match. When
+// cosign is installed and the release publishes <URL>.sig and <URL>.pem,
+// the keyless signature is verified as well.
+const DownloadHelpers = `# Download verification added by ravact
+verified_download() {
+  local url="$1" dest="$2" repo="${3:-}" expected="${4:-}" source="" actual asset tag api
+  echo "Downloading $url"
+  if ! curl --fail --location --silent --show-error --output "$dest" "$url"; then
+    echo "✗ Download failed: $url" >&2
+    return 1
+  fi
+
+  if [ -n "$expected" ]; then
+    source="the pinned checksum"
+  fi
+  if [ -z "$expected" ]; then
+    expected=$(curl --fail --location --silent "$url.sha256" 2>/dev/null | awk '{print $1; exit}')
+    [ -n "$expected" ] && source="$url.sha256"
+  fi
+  if [ -z "$expected" ] && [ -n "$repo" ]; then
+    # GitHub records a sha256 digest for every release asset
+    asset="${url##*/}"
+    case "$url" in
+      */releases/latest/download/*) api="https://api.github.com/repos/$repo/releases/latest" ;;
+      */releases/download/*) tag="${url%/*}"; tag="${tag##*/}"; api="https://api.github.com/repos/$repo/releases/tags/$tag" ;;
+    esac
+    if [ -n "$api" ]; then
+      expected=$(curl --fail --location --silent -H 'Accept: application/vnd.github+json' "$api" 2>/dev/null |
+        tr ',' '\n' | tr -d ' {}[]' |
+        awk -v name="\"name\":\"$asset\"" '
+          index($0, "\"name\":") == 1 { found = ($0 == name) }
+          found && index($0, "\"digest\":\"sha256:") == 1 { sub(/^"digest":"sha256:/, ""); sub(/".*/, ""); print; exit }')
+      [ -n "$expected" ] && source="the GitHub release digest"
+    fi
+  fi
+  if [ -z "$expected" ]; then
+    rm -f "$dest"
+    echo "✗ No published SHA-256 checksum found for $url" >&2
+    echo "  Refusing to install an unverified binary. Download it manually and" >&2
+    echo "  compare its checksum against the project's release page." >&2
+    return 1
+  fi
+
+  expected=$(echo "$expected" | tr 'A-F' 'a-f')
+  actual=$(sha256sum "$dest" | awk '{print $1}')
+  if [ "$actual" != "$expected" ]; then
+    rm -f "$dest"
+    echo "✗ Checksum mismatch for $url" >&2
+    echo "  expected $expected ($source)" >&2
+    echo "  got      $actual" >&2
+    echo "  The download was removed. It may be corrupt or tampered with." >&2
+    return 1
+  fi
+  echo "✓ SHA-256 verified against $source"
+
+  if command -v cosign >/dev/null 2>&1 && [ -n "$repo" ] &&
+    curl --fail --location --silent --output "$dest.sig" "$url.sig" 2>/dev/null &&
+    curl --fail --location --silent --output "$dest.pem" "$url.pem" 2>/dev/null; then
+    if ! cosign verify-blob --signature "$dest.sig" --certificate "$dest.pem" \
+      --certificate-identity-regexp "^https://github.com/$repo/" \
+      --certificate-oidc-issuer https://token.actions.githubusercontent.com "$dest" >/dev/null 2>&1; then
+      rm -f "$dest" "$dest.sig" "$dest.pem"
+      echo "✗ cosign signature verification failed for $url" >&2
+      return 1
+    fi
+    rm -f "$dest.sig" "$dest.pem"
+    echo "✓ cosign signature verified"
+  fi
+}
+`
+
+// GitHubReleaseRepo returns the owner/name of a GitHub release download URL,
+// or "" for other URLs
+func GitHubReleaseRepo(url string) string {
+	rest, ok := strings.CutPrefix(url, "https://github.com/")
+	if !ok {
+		return ""
+	}
+	parts := strings.SplitN(rest, "/", 4)
+	if len(parts) < 4 || parts[0] == "" || parts[1] == "" || parts[2] != "releases" {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
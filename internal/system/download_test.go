@@ -0,0 +1,67 @@
+package system
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifiedDownload(t *testing.T) {
+	for _, tool := range []string{"bash", "curl", "sha256sum"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not available", tool)
+		}
+	}
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "frankenphp-linux-x86_64")
+	os.WriteFile(binary, []byte("binary"), 0644)
+	sum := sha256.Sum256([]byte("binary"))
+	good := hex.EncodeToString(sum[:])
+	url := "file://" + binary
+	dest := filepath.Join(dir, "out")
+
+	run := func(args string) (string, error) {
+		out, err := exec.Command("bash", "-c", DownloadHelpers+"verified_download "+args).CombinedOutput()
+		return string(out), err
+	}
+
+	if out, err := run(url + " " + dest + " '' " + strings.ToUpper(good)); err != nil {
+		t.Fatalf("pinned checksum should verify: %v\n%s", err, out)
+	}
+
+	// No published checksum: refused
+	if out, err := run(url + " " + dest); err == nil || !strings.Contains(out, "No published SHA-256") {
+		t.Errorf("expected an unverified download to fail, got %v\n%s", err, out)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Error("unverified download should be removed")
+	}
+
+	// A <url>.sha256 file is used, and a mismatch fails
+	os.WriteFile(binary+".sha256", []byte(strings.Repeat("0", 64)+"  frankenphp-linux-x86_64\n"), 0644)
+	if out, err := run(url + " " + dest); err == nil || !strings.Contains(out, "Checksum mismatch") {
+		t.Errorf("expected a checksum mismatch, got %v\n%s", err, out)
+	}
+	os.WriteFile(binary+".sha256", []byte(good+"  frankenphp-linux-x86_64\n"), 0644)
+	if out, err := run(url + " " + dest); err != nil || !strings.Contains(out, ".sha256") {
+		t.Errorf("expected the .sha256 file to verify, got %v\n%s", err, out)
+	}
+}
+
+func TestGitHubReleaseRepo(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/dunglas/frankenphp/releases/download/v1.1.0/frankenphp-linux-x86_64":  "dunglas/frankenphp",
+		"https://github.com/dunglas/frankenphp/releases/latest/download/frankenphp-linux-aarch64": "dunglas/frankenphp",
+		"https://github.com/dunglas/frankenphp":                                                   "",
+		"https://mirror.example.com/frankenphp":                                                   "",
+	}
+	for url, want := range tests {
+		if got := GitHubReleaseRepo(url); got != want {
+			t.Errorf("GitHubReleaseRepo(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EnvEntry represents a single KEY=VALUE line in a .env file
+type EnvEntry struct {
+	Key   string
+	Value string
+	Line  int // Index into EnvFile.Lines
+}
+
+// EnvFile represents a parsed .env file. Comments and blank lines are
+// kept in Lines so the file can be written back without reformatting.
+type EnvFile struct {
+	Path    string
+	Lines   []string
+	Entries []EnvEntry
+}
+
+// EnvBackupDir holds the copies of .env files taken before they are saved.
+// They are kept here rather than next to the file, where a web server
+// serving the project directory could hand them out.
+func EnvBackupDir() string {
+	return filepath.Join(StateDir, "env-backups")
+}
+
+// envKeyPattern matches valid environment variable names
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// secretKeyMarkers are substrings of keys whose values should be masked
+var secretKeyMarkers = []string{"PASSWORD", "SECRET", "TOKEN", "KEY", "DSN", "PRIVATE", "CREDENTIAL"}
+
+// ParseEnvFile reads and parses a .env file
+func ParseEnvFile(path string) (*EnvFile, error) {
+	data, err := ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	env := ParseEnv(string(data))
+	env.Path = path
+	return env, nil
+}
+
+// ParseEnv parses .env content
+func ParseEnv(content string) *EnvFile {
+	env := &EnvFile{
+		Lines: strings.Split(strings.TrimSuffix(content, "\n"), "\n"),
+	}
+
+	for i, line := range env.Lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		trimmed = strings.TrimPrefix(trimmed, "export ")
+		key, value, found := strings.Cut(trimmed, "=")
+		if !found {
+			continue
+		}
+
+		env.Entries = append(env.Entries, EnvEntry{
+			Key:   strings.TrimSpace(key),
+			Value: unquoteEnvValue(strings.TrimSpace(value)),
+			Line:  i,
+		})
+	}
+
+	return env
+}
+
+// unquoteEnvValue strips matching surrounding quotes from a value and, in
+// double quotes, the backslashes escaping \ and "
+func unquoteEnvValue(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+	first, last := value[0], value[len(value)-1]
+	if first == '\'' && last == '\'' {
+		return value[1 : len(value)-1]
+	}
+	if first != '"' || last != '"' {
+		return value
+	}
+	inner := value[1 : len(value)-1]
+	var b strings.Builder
+	for i := 0; i < len(inner); i++ {
+		if inner[i] == '\\' && i+1 < len(inner) && (inner[i+1] == '\\' || inner[i+1] == '"') {
+			i++
+		}
+		b.WriteByte(inner[i])
+	}
+	return b.String()
+}
+
+// quoteEnvValue quotes a value if it contains characters that need it
+func quoteEnvValue(value string) string {
+	if value == "" || !strings.ContainsAny(value, " \t#\"'$") {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+// Get returns the value of the last occurrence of a key
+func (e *EnvFile) Get(key string) (string, bool) {
+	value, found := "", false
+	for _, entry := range e.Entries {
+		if entry.Key == key {
+			value, found = entry.Value, true
+		}
+	}
+	return value, found
+}
+
+// Set updates every occurrence of a key, or appends it if missing
+func (e *EnvFile) Set(key, value string) {
+	updated := false
+	for i, entry := range e.Entries {
+		if entry.Key == key {
+			e.Entries[i].Value = value
+			e.Lines[entry.Line] = key + "=" + quoteEnvValue(value)
+			updated = true
+		}
+	}
+
+	if !updated {
+		e.Lines = append(e.Lines, key+"="+quoteEnvValue(value))
+		e.Entries = append(e.Entries, EnvEntry{Key: key, Value: value, Line: len(e.Lines) - 1})
+	}
+}
+
+// Duplicates returns keys that are defined more than once
+func (e *EnvFile) Duplicates() []string {
+	counts := make(map[string]int)
+	for _, entry := range e.Entries {
+		counts[entry.Key]++
+	}
+
+	var dups []string
+	for key, count := range counts {
+		if count > 1 {
+			dups = append(dups, key)
+		}
+	}
+	sort.Strings(dups)
+	return dups
+}
+
+// Validate returns human-readable problems found in the file
+func (e *EnvFile) Validate() []string {
+	var problems []string
+	for _, key := range e.Duplicates() {
+		problems = append(problems, fmt.Sprintf("%s is defined more than once (the last value wins)", key))
+	}
+	for _, entry := range e.Entries {
+		if !envKeyPattern.MatchString(entry.Key) {
+			problems = append(problems, fmt.Sprintf("line %d: invalid key name %q", entry.Line+1, entry.Key))
+		}
+	}
+	return problems
+}
+
+// Content returns the file content as it will be written
+func (e *EnvFile) Content() string {
+	return strings.Join(e.Lines, "\n") + "\n"
+}
+
+// Save backs up the original file to EnvBackupDir and writes the current
+// content
+func (e *EnvFile) Save() (string, error) {
+	backupPath := ""
+	if data, err := ReadFile(e.Path); err == nil {
+		name := strings.ReplaceAll(strings.Trim(filepath.Clean(e.Path), "/"), "/", "_")
+		backupPath = filepath.Join(EnvBackupDir(), fmt.Sprintf("%s.backup-%s", name, time.Now().Format("20060102-150405")))
+		if err := MkdirAll(EnvBackupDir(), 0700); err != nil {
+			return "", fmt.Errorf("failed to create %s: %w", EnvBackupDir(), err)
+		}
+		if err := WriteFile(backupPath, data, 0600); err != nil {
+			return "", fmt.Errorf("failed to create backup: %w", err)
+		}
+	}
+
+	mode := os.FileMode(0600)
+	if info, err := Stat(e.Path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	if err := WriteFile(e.Path, []byte(e.Content()), mode); err != nil {
+		return backupPath, fmt.Errorf("failed to write %s: %w", e.Path, err)
+	}
+	return backupPath, nil
+}
+
+// EnvKeyPrefix returns the group prefix of a key (APP_NAME -> APP)
+func EnvKeyPrefix(key string) string {
+	if idx := strings.Index(key, "_"); idx > 0 {
+		return key[:idx]
+	}
+	return key
+}
+
+// IsSecretEnvKey reports whether a key's value should be masked
+func IsSecretEnvKey(key string) bool {
+	upper := strings.ToUpper(key)
+	for _, marker := range secretKeyMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseEnv(t *testing.T) {
+	content := `# App settings
+APP_NAME="My App"
+APP_ENV=production
+
+export DB_PASSWORD='s3cret'
+APP_ENV=local
+`
+	env := ParseEnv(content)
+
+	if len(env.Entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(env.Entries))
+	}
+	if v, _ := env.Get("APP_NAME"); v != "My App" {
+		t.Errorf("expected APP_NAME 'My App', got '%s'", v)
+	}
+	if v, _ := env.Get("DB_PASSWORD"); v != "s3cret" {
+		t.Errorf("expected DB_PASSWORD 's3cret', got '%s'", v)
+	}
+	if v, _ := env.Get("APP_ENV"); v != "local" {
+		t.Errorf("expected last APP_ENV value 'local', got '%s'", v)
+	}
+
+	dups := env.Duplicates()
+	if len(dups) != 1 || dups[0] != "APP_ENV" {
+		t.Errorf("expected APP_ENV to be reported as duplicate, got %v", dups)
+	}
+	if problems := env.Validate(); len(problems) != 1 {
+		t.Errorf("expected 1 validation problem, got %v", problems)
+	}
+}
+
+func TestEnvFileSetKeepsComments(t *testing.T) {
+	env := ParseEnv("# comment\nAPP_NAME=old\n")
+	env.Set("APP_NAME", "New Name")
+	env.Set("APP_DEBUG", "false")
+
+	expected := "# comment\nAPP_NAME=\"New Name\"\nAPP_DEBUG=false\n"
+	if env.Content() != expected {
+		t.Errorf("unexpected content:\n%s", env.Content())
+	}
+}
+
+func TestEnvFileSetRoundTrip(t *testing.T) {
+	values := []string{
+		`pass word\`,
+		`say \"hi\"`,
+		`C:\dir with space`,
+		`quote " and $dollar`,
+		`plain\value`,
+	}
+	env := ParseEnv("")
+	for i, v := range values {
+		env.Set(fmt.Sprintf("KEY_%d", i), v)
+	}
+	if !strings.Contains(env.Content(), `KEY_0="pass word\\"`) {
+		t.Errorf("expected a trailing backslash to be escaped:\n%s", env.Content())
+	}
+
+	reparsed := ParseEnv(env.Content())
+	for i, want := range values {
+		if got, _ := reparsed.Get(fmt.Sprintf("KEY_%d", i)); got != want {
+			t.Errorf("KEY_%d round-tripped to %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestEnvFileSaveCreatesBackup(t *testing.T) {
+	originalState := StateDir
+	StateDir = t.TempDir()
+	defer func() { StateDir = originalState }()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("APP_KEY=abc\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := ParseEnvFile(path)
+	if err != nil {
+		t.Fatalf("ParseEnvFile failed: %v", err)
+	}
+	env.Set("APP_KEY", "xyz")
+
+	backup, err := env.Save()
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if filepath.Dir(backup) != EnvBackupDir() {
+		t.Errorf("expected the backup in %s, got %s", EnvBackupDir(), backup)
+	}
+	original, err := os.ReadFile(backup)
+	if err != nil || string(original) != "APP_KEY=abc\n" {
+		t.Errorf("expected backup to hold the original content, got %q (%v)", original, err)
+	}
+
+	updated, _ := os.ReadFile(path)
+	if !strings.Contains(string(updated), "APP_KEY=xyz") {
+		t.Errorf("expected updated value to be written, got %q", updated)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0640 {
+		t.Errorf("expected file mode to be preserved, got %v", info.Mode().Perm())
+	}
+}
+
+func TestIsSecretEnvKey(t *testing.T) {
+	for _, key := range []string{"DB_PASSWORD", "APP_KEY", "AWS_SECRET_ACCESS_KEY", "SENTRY_LARAVEL_DSN"} {
+		if !IsSecretEnvKey(key) {
+			t.Errorf("expected %s to be treated as secret", key)
+		}
+	}
+	if IsSecretEnvKey("APP_NAME") {
+		t.Error("expected APP_NAME not to be treated as secret")
+	}
+}
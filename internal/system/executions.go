@@ -0,0 +1,67 @@
+package system
+
+import (
+	"sort"
+	"time"
+)
+
+// executionHistoryLimit is how many runs the execution history keeps
+const executionHistoryLimit = 200
+
+// executionKeyFormat sorts lexically in time order
+const executionKeyFormat = "20060102T150405.000000000"
+
+// ExecutionRecord is one finished run of a command ravact started
+type ExecutionRecord struct {
+	Description string        `json:"description"`
+	Source      string        `json:"source"`         // What started it, e.g. "webhook"
+	Site        string        `json:"site,omitempty"` // Site the run belongs to, if any
+	StartedAt   time.Time     `json:"started_at"`
+	Duration    time.Duration `json:"duration"`
+	Success     bool          `json:"success"`
+	Error       string        `json:"error,omitempty"`
+	Output      string        `json:"output,omitempty"` // The end of the output
+}
+
+// executionOutputLimit is how much of the end of the output is kept
+const executionOutputLimit = 8 << 10
+
+// RecordExecution adds a run to the execution history, dropping the oldest
+// runs beyond executionHistoryLimit
+func RecordExecution(rec ExecutionRecord) error {
+	if len(rec.Output) > executionOutputLimit {
+		rec.Output = rec.Output[len(rec.Output)-executionOutputLimit:]
+	}
+	return UpdateStore(func(tx *StoreTx) error {
+		if err := tx.Put(BucketExecutions, rec.StartedAt.UTC().Format(executionKeyFormat), rec); err != nil {
+			return err
+		}
+		keys := tx.Keys(BucketExecutions)
+		for len(keys) > executionHistoryLimit {
+			if err := tx.Delete(BucketExecutions, keys[0]); err != nil {
+				return err
+			}
+			keys = keys[1:]
+		}
+		return nil
+	})
+}
+
+// LoadExecutions returns the execution history, newest first
+func LoadExecutions() ([]ExecutionRecord, error) {
+	var records []ExecutionRecord
+	err := ViewStore(func(tx *StoreTx) error {
+		keys := tx.Keys(BucketExecutions)
+		sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+		for _, key := range keys {
+			var rec ExecutionRecord
+			if ok, err := tx.Get(BucketExecutions, key, &rec); err != nil {
+				return err
+			} else if ok {
+				records = append(records, rec)
+			}
+		}
+		return nil
+	})
+	return records, err
+}
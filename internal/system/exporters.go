@@ -0,0 +1,123 @@
+package system
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ExporterStatePath is where the exporters setup script records what it
+// installed and which source may scrape
+var ExporterStatePath = "/etc/ravact/exporters.conf"
+
+// Exporter is a Prometheus exporter the exporters setup script installs
+type Exporter struct {
+	ID          string // Name in the script's EXPORTERS list
+	Name        string
+	Description string
+	Unit        string // systemd unit
+	Port        int
+}
+
+// Exporters lists the exporters the setup script knows
+var Exporters = []Exporter{
+	{ID: "node", Name: "node_exporter", Description: "CPU, memory, disk, network, and systemd units", Unit: "node_exporter", Port: 9100},
+	{ID: "nginx", Name: "nginx-prometheus-exporter", Description: "Connections and requests from nginx stub_status", Unit: "nginx-prometheus-exporter", Port: 9113},
+	{ID: "mysqld", Name: "mysqld_exporter", Description: "MySQL status, connections, and replication", Unit: "mysqld_exporter", Port: 9104},
+	{ID: "phpfpm", Name: "php-fpm_exporter", Description: "Process and queue stats of every PHP-FPM pool", Unit: "php-fpm_exporter", Port: 9253},
+}
+
+// FindExporter returns the exporter with an ID
+func FindExporter(id string) (Exporter, bool) {
+	for _, e := range Exporters {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Exporter{}, false
+}
+
+// ExporterSettings is which exporters run and who may scrape them
+type ExporterSettings struct {
+	Exporters    []string // Exporter IDs
+	ScrapeSource string   // IP or CIDR; loopback keeps the exporters local
+}
+
+// DefaultExporterSettings runs node_exporter for a local Prometheus or tunnel
+func DefaultExporterSettings() ExporterSettings {
+	return ExporterSettings{Exporters: []string{"node"}, ScrapeSource: "127.0.0.1"}
+}
+
+// LocalOnly reports whether the exporters only listen on loopback
+func (s ExporterSettings) LocalOnly() bool {
+	ip := net.ParseIP(s.ScrapeSource)
+	if ip == nil {
+		ip, _, _ = net.ParseCIDR(s.ScrapeSource)
+	}
+	return ip != nil && ip.IsLoopback()
+}
+
+// Validate checks the exporters and the scrape source
+func (s ExporterSettings) Validate() error {
+	if len(s.Exporters) == 0 {
+		return fmt.Errorf("choose at least one exporter")
+	}
+	for _, id := range s.Exporters {
+		if _, ok := FindExporter(id); !ok {
+			return fmt.Errorf("unknown exporter %q", id)
+		}
+	}
+	if ip := net.ParseIP(s.ScrapeSource); ip != nil {
+		if ip.IsUnspecified() {
+			return fmt.Errorf("scrape source must be the Prometheus server, not every address")
+		}
+		return nil
+	}
+	_, network, err := net.ParseCIDR(s.ScrapeSource)
+	if err != nil {
+		return fmt.Errorf("scrape source must be an IP address or network, such as 10.0.0.5 or 10.0.0.0/24")
+	}
+	if ones, _ := network.Mask.Size(); ones == 0 {
+		return fmt.Errorf("scrape source must be the Prometheus server, not every address")
+	}
+	return nil
+}
+
+// Command runs the setup script with the settings
+func (s ExporterSettings) Command() string {
+	return fmt.Sprintf("EXPORTERS=%s SCRAPE_SOURCE=%s assets/scripts/exporters.sh",
+		ShellQuote(strings.Join(s.Exporters, " ")), ShellQuote(s.ScrapeSource))
+}
+
+// parseExporterSettings reads the state file the setup script writes
+func parseExporterSettings(content string) ExporterSettings {
+	s := DefaultExporterSettings()
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "EXPORTERS":
+			s.Exporters = strings.Fields(value)
+		case "SCRAPE_SOURCE":
+			s.ScrapeSource = strings.TrimSpace(value)
+		}
+	}
+	return s
+}
+
+// ReadExporterSettings returns the settings of the last setup run, or the
+// defaults when the exporters were never set up
+func ReadExporterSettings() (ExporterSettings, bool) {
+	data, err := ReadFile(ExporterStatePath)
+	if err != nil {
+		return DefaultExporterSettings(), false
+	}
+	return parseExporterSettings(string(data)), true
+}
+
+// ExporterRunning reports whether an exporter's unit is active
+func ExporterRunning(e Exporter) bool {
+	return Command("systemctl", "is-active", "--quiet", e.Unit).Run() == nil
+}
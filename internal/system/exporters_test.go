@@ -0,0 +1,49 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExporterSettingsValidate(t *testing.T) {
+	for _, s := range []ExporterSettings{
+		{Exporters: []string{"node"}, ScrapeSource: "127.0.0.1"},
+		{Exporters: []string{"node", "nginx", "mysqld", "phpfpm"}, ScrapeSource: "203.0.113.7"},
+		{Exporters: []string{"phpfpm"}, ScrapeSource: "10.0.0.0/24"},
+		{Exporters: []string{"node"}, ScrapeSource: "2001:db8::5"},
+	} {
+		if err := s.Validate(); err != nil {
+			t.Errorf("expected %+v to be valid: %v", s, err)
+		}
+	}
+	for _, s := range []ExporterSettings{
+		{ScrapeSource: "127.0.0.1"},
+		{Exporters: []string{"redis"}, ScrapeSource: "127.0.0.1"},
+		{Exporters: []string{"node"}, ScrapeSource: "0.0.0.0"},
+		{Exporters: []string{"node"}, ScrapeSource: "0.0.0.0/0"},
+		{Exporters: []string{"node"}, ScrapeSource: "prometheus; reboot"},
+	} {
+		if err := s.Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", s)
+		}
+	}
+}
+
+func TestExporterSettingsCommand(t *testing.T) {
+	s := ExporterSettings{Exporters: []string{"node", "nginx"}, ScrapeSource: "10.0.0.0/24"}
+	want := "EXPORTERS='node nginx' SCRAPE_SOURCE=10.0.0.0/24 assets/scripts/exporters.sh"
+	if got := s.Command(); got != want {
+		t.Errorf("Command() = %q, want %q", got, want)
+	}
+	if s.LocalOnly() || !DefaultExporterSettings().LocalOnly() {
+		t.Error("expected only a loopback source to keep the exporters local")
+	}
+}
+
+func TestParseExporterSettings(t *testing.T) {
+	s := parseExporterSettings("# Generated by ravact\nEXPORTERS=node mysqld\nSCRAPE_SOURCE=203.0.113.7\n")
+	want := ExporterSettings{Exporters: []string{"node", "mysqld"}, ScrapeSource: "203.0.113.7"}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("got %+v, want %+v", s, want)
+	}
+}
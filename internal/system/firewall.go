@@ -1,10 +1,7 @@
 package system
 
 import (
-	"bufio"
-	"bytes"
 	"fmt"
-	"os/exec"
 	"strings"
 )
 
@@ -19,11 +16,15 @@ const (
 
 // FirewallRule represents a firewall rule
 type FirewallRule struct {
-	Port     string
-	Protocol string
-	Action   string // allow, deny
-	From     string // IP or "Anywhere"
-	Comment  string
+	Number    int // Position in `ufw status numbered`; 0 for firewalld
+	Port      string // Empty when the rule covers every port
+	Protocol  string
+	Action    string // allow, deny, reject, limit
+	Direction string // IN, OUT
+	From      string // IP or "Anywhere"
+	To        string // Destination IP or CIDR; empty for any
+	Comment   string
+	V6        bool
 }
 
 // FirewallManager handles firewall operations
@@ -40,10 +41,10 @@ func NewFirewallManager() *FirewallManager {
 
 // detectFirewallType detects which firewall is installed
 func detectFirewallType() FirewallType {
-	if cmd := exec.Command("which", "ufw"); cmd.Run() == nil {
+	if cmd := Command("which", "ufw"); cmd.Run() == nil {
 		return FirewallUFW
 	}
-	if cmd := exec.Command("which", "firewall-cmd"); cmd.Run() == nil {
+	if cmd := Command("which", "firewall-cmd"); cmd.Run() == nil {
 		return FirewallFirewalld
 	}
 	return FirewallNone
@@ -58,7 +59,7 @@ func (m *FirewallManager) GetFirewallType() FirewallType {
 func (m *FirewallManager) GetStatus() (string, error) {
 	switch m.firewallType {
 	case FirewallUFW:
-		cmd := exec.Command("ufw", "status")
+		cmd := Command("ufw", "status")
 		output, err := cmd.CombinedOutput()
 		if err != nil {
 			return "unknown", err
@@ -69,7 +70,7 @@ func (m *FirewallManager) GetStatus() (string, error) {
 		return "inactive", nil
 
 	case FirewallFirewalld:
-		cmd := exec.Command("systemctl", "is-active", "firewalld")
+		cmd := Command("systemctl", "is-active", "firewalld")
 		output, _ := cmd.Output()
 		return strings.TrimSpace(string(output)), nil
 
@@ -84,46 +85,17 @@ func (m *FirewallManager) GetRules() ([]FirewallRule, error) {
 
 	switch m.firewallType {
 	case FirewallUFW:
-		cmd := exec.Command("ufw", "status", "numbered")
+		cmd := Command("ufw", "status", "numbered")
 		output, err := cmd.CombinedOutput()
 		if err != nil {
 			return nil, err
 		}
 
-		scanner := bufio.NewScanner(bytes.NewReader(output))
-		for scanner.Scan() {
-			line := scanner.Text()
-			// Parse UFW rules like: [ 1] 22/tcp                     ALLOW IN    Anywhere
-			if strings.HasPrefix(line, "[") && strings.Contains(line, "]") {
-				parts := strings.Fields(line)
-				if len(parts) >= 4 {
-					portProto := parts[1]
-					action := parts[2]
-					from := "Anywhere"
-					if len(parts) > 4 {
-						from = parts[4]
-					}
-
-					portParts := strings.Split(portProto, "/")
-					port := portParts[0]
-					protocol := "tcp"
-					if len(portParts) > 1 {
-						protocol = portParts[1]
-					}
-
-					rules = append(rules, FirewallRule{
-						Port:     port,
-						Protocol: protocol,
-						Action:   strings.ToLower(action),
-						From:     from,
-					})
-				}
-			}
-		}
+		rules = parseUFWNumbered(string(output))
 
 	case FirewallFirewalld:
 		// Get open ports
-		cmd := exec.Command("firewall-cmd", "--list-ports")
+		cmd := Command("firewall-cmd", "--list-ports")
 		output, err := cmd.Output()
 		if err == nil {
 			ports := strings.Fields(string(output))
@@ -141,7 +113,7 @@ func (m *FirewallManager) GetRules() ([]FirewallRule, error) {
 		}
 
 		// Get open services
-		cmd = exec.Command("firewall-cmd", "--list-services")
+		cmd = Command("firewall-cmd", "--list-services")
 		output, err = cmd.Output()
 		if err == nil {
 			services := strings.Fields(string(output))
@@ -163,16 +135,16 @@ func (m *FirewallManager) GetRules() ([]FirewallRule, error) {
 func (m *FirewallManager) AllowPort(port, protocol string) error {
 	switch m.firewallType {
 	case FirewallUFW:
-		cmd := exec.Command("ufw", "allow", fmt.Sprintf("%s/%s", port, protocol))
+		cmd := Command("ufw", "allow", fmt.Sprintf("%s/%s", port, protocol))
 		return cmd.Run()
 
 	case FirewallFirewalld:
-		cmd := exec.Command("firewall-cmd", "--permanent", fmt.Sprintf("--add-port=%s/%s", port, protocol))
+		cmd := Command("firewall-cmd", "--permanent", fmt.Sprintf("--add-port=%s/%s", port, protocol))
 		if err := cmd.Run(); err != nil {
 			return err
 		}
 		// Reload to apply
-		return exec.Command("firewall-cmd", "--reload").Run()
+		return Command("firewall-cmd", "--reload").Run()
 
 	default:
 		return fmt.Errorf("no firewall installed")
@@ -183,15 +155,15 @@ func (m *FirewallManager) AllowPort(port, protocol string) error {
 func (m *FirewallManager) DenyPort(port, protocol string) error {
 	switch m.firewallType {
 	case FirewallUFW:
-		cmd := exec.Command("ufw", "deny", fmt.Sprintf("%s/%s", port, protocol))
+		cmd := Command("ufw", "deny", fmt.Sprintf("%s/%s", port, protocol))
 		return cmd.Run()
 
 	case FirewallFirewalld:
-		cmd := exec.Command("firewall-cmd", "--permanent", fmt.Sprintf("--remove-port=%s/%s", port, protocol))
+		cmd := Command("firewall-cmd", "--permanent", fmt.Sprintf("--remove-port=%s/%s", port, protocol))
 		if err := cmd.Run(); err != nil {
 			return err
 		}
-		return exec.Command("firewall-cmd", "--reload").Run()
+		return Command("firewall-cmd", "--reload").Run()
 
 	default:
 		return fmt.Errorf("no firewall installed")
@@ -202,15 +174,15 @@ func (m *FirewallManager) DenyPort(port, protocol string) error {
 func (m *FirewallManager) DeleteRule(port, protocol string) error {
 	switch m.firewallType {
 	case FirewallUFW:
-		cmd := exec.Command("ufw", "delete", "allow", fmt.Sprintf("%s/%s", port, protocol))
+		cmd := Command("ufw", "delete", "allow", fmt.Sprintf("%s/%s", port, protocol))
 		return cmd.Run()
 
 	case FirewallFirewalld:
-		cmd := exec.Command("firewall-cmd", "--permanent", fmt.Sprintf("--remove-port=%s/%s", port, protocol))
+		cmd := Command("firewall-cmd", "--permanent", fmt.Sprintf("--remove-port=%s/%s", port, protocol))
 		if err := cmd.Run(); err != nil {
 			return err
 		}
-		return exec.Command("firewall-cmd", "--reload").Run()
+		return Command("firewall-cmd", "--reload").Run()
 
 	default:
 		return fmt.Errorf("no firewall installed")
@@ -221,14 +193,14 @@ func (m *FirewallManager) DeleteRule(port, protocol string) error {
 func (m *FirewallManager) EnableFirewall() error {
 	switch m.firewallType {
 	case FirewallUFW:
-		cmd := exec.Command("ufw", "--force", "enable")
+		cmd := Command("ufw", "--force", "enable")
 		return cmd.Run()
 
 	case FirewallFirewalld:
-		if err := exec.Command("systemctl", "enable", "firewalld").Run(); err != nil {
+		if err := Command("systemctl", "enable", "firewalld").Run(); err != nil {
 			return err
 		}
-		return exec.Command("systemctl", "start", "firewalld").Run()
+		return Command("systemctl", "start", "firewalld").Run()
 
 	default:
 		return fmt.Errorf("no firewall installed")
@@ -239,11 +211,11 @@ func (m *FirewallManager) EnableFirewall() error {
 func (m *FirewallManager) DisableFirewall() error {
 	switch m.firewallType {
 	case FirewallUFW:
-		cmd := exec.Command("ufw", "disable")
+		cmd := Command("ufw", "disable")
 		return cmd.Run()
 
 	case FirewallFirewalld:
-		return exec.Command("systemctl", "stop", "firewalld").Run()
+		return Command("systemctl", "stop", "firewalld").Run()
 
 	default:
 		return fmt.Errorf("no firewall installed")
@@ -254,13 +226,13 @@ func (m *FirewallManager) DisableFirewall() error {
 func (m *FirewallManager) ReloadFirewall() error {
 	switch m.firewallType {
 	case FirewallUFW:
-		if err := exec.Command("ufw", "disable").Run(); err != nil {
+		if err := Command("ufw", "disable").Run(); err != nil {
 			return err
 		}
-		return exec.Command("ufw", "--force", "enable").Run()
+		return Command("ufw", "--force", "enable").Run()
 
 	case FirewallFirewalld:
-		return exec.Command("firewall-cmd", "--reload").Run()
+		return Command("firewall-cmd", "--reload").Run()
 
 	default:
 		return fmt.Errorf("no firewall installed")
@@ -273,9 +245,9 @@ func (m *FirewallManager) AllowService(service string) error {
 		return fmt.Errorf("service-based rules only supported on firewalld")
 	}
 
-	cmd := exec.Command("firewall-cmd", "--permanent", fmt.Sprintf("--add-service=%s", service))
+	cmd := Command("firewall-cmd", "--permanent", fmt.Sprintf("--add-service=%s", service))
 	if err := cmd.Run(); err != nil {
 		return err
 	}
-	return exec.Command("firewall-cmd", "--reload").Run()
+	return Command("firewall-cmd", "--reload").Run()
 }
@@ -0,0 +1,404 @@
+package system
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ufwNumberedRule matches a line of `ufw status numbered`, e.g.
+// "[ 2] 443/tcp (v6)               ALLOW IN    Anywhere (v6)      # HTTPS"
+var ufwNumberedRule = regexp.MustCompile(`^\[\s*(\d+)\]\s+(.+?)\s+(ALLOW|DENY|REJECT|LIMIT)\s+(?:(IN|OUT|FWD)\s+)?(.*?)\s*(?:#\s*(.*))?$`)
+
+// parseUFWNumbered parses `ufw status numbered` output
+func parseUFWNumbered(output string) []FirewallRule {
+	var rules []FirewallRule
+	for _, line := range strings.Split(output, "\n") {
+		match := ufwNumberedRule.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		number, _ := strconv.Atoi(match[1])
+		to, from := match[2], match[5]
+		v6 := strings.HasSuffix(to, "(v6)") || strings.HasSuffix(from, "(v6)")
+		to = strings.TrimSpace(strings.TrimSuffix(to, "(v6)"))
+		from = strings.TrimSpace(strings.TrimSuffix(from, "(v6)"))
+		if from == "" {
+			from = "Anywhere"
+		}
+
+		address, port, protocol := splitUFWTo(to)
+		direction := match[4]
+		if direction == "" {
+			direction = "IN"
+		}
+
+		rules = append(rules, FirewallRule{
+			Number:    number,
+			Port:      port,
+			Protocol:  protocol,
+			Action:    strings.ToLower(match[3]),
+			Direction: direction,
+			From:      from,
+			To:        address,
+			Comment:   strings.TrimSpace(match[6]),
+			V6:        v6,
+		})
+	}
+	return rules
+}
+
+// isAddress reports whether s is an IP address or CIDR
+func isAddress(s string) bool {
+	if net.ParseIP(s) != nil {
+		return true
+	}
+	_, _, err := net.ParseCIDR(s)
+	return err == nil
+}
+
+// splitUFWTo splits the To column of `ufw status numbered`, such as
+// "22/tcp", "Nginx Full", "203.0.113.5 22/tcp", or "Anywhere", into the
+// destination address ("" for any), the port ("" for every port), and the
+// protocol
+func splitUFWTo(to string) (address, port, protocol string) {
+	protocol = "any"
+	if first, rest, _ := strings.Cut(to, " "); isAddress(first) || first == "Anywhere" {
+		if first != "Anywhere" {
+			address = first
+		}
+		to = strings.TrimSpace(rest)
+	}
+	if to == "" {
+		return address, "", protocol
+	}
+	port = to
+	if i := strings.LastIndex(to, "/"); i > 0 {
+		port, protocol = to[:i], to[i+1:]
+	}
+	return address, port, protocol
+}
+
+// UFWRuleSpec describes a rule to add with `ufw`
+type UFWRuleSpec struct {
+	Action   string // allow, deny, reject, limit
+	Port     string // Port, range (8000:8100), or application profile name; empty for every port
+	Protocol string // tcp, udp, any
+	From     string // IP, CIDR, or "any"
+	To       string // Destination IP or CIDR; empty or "any" for any
+	Comment  string
+}
+
+// SpecFromRule converts a listed rule back into a spec
+func SpecFromRule(r FirewallRule) UFWRuleSpec {
+	from := r.From
+	if strings.EqualFold(from, "Anywhere") {
+		from = "any"
+	}
+	return UFWRuleSpec{Action: r.Action, Port: r.Port, Protocol: r.Protocol, From: from, To: r.To, Comment: r.Comment}
+}
+
+// isPort reports whether p is a port number or range rather than an app profile
+func isPort(p string) bool {
+	for _, part := range strings.Split(p, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 1 || n > 65535 {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks the spec before it is passed to ufw
+func (s UFWRuleSpec) Validate() error {
+	switch s.Action {
+	case "allow", "deny", "reject", "limit":
+	default:
+		return fmt.Errorf("action must be allow, deny, reject, or limit")
+	}
+	if !isPort(s.Port) {
+		return fmt.Errorf("invalid port %q (use 1-65535 or a range like 8000:8100)", s.Port)
+	}
+	if strings.Contains(s.Port, ":") && s.Protocol != "tcp" && s.Protocol != "udp" {
+		return fmt.Errorf("port ranges need tcp or udp")
+	}
+	switch s.Protocol {
+	case "tcp", "udp", "any", "":
+	default:
+		return fmt.Errorf("protocol must be tcp, udp, or any")
+	}
+	if s.From != "" && s.From != "any" && !isAddress(s.From) {
+		return fmt.Errorf("invalid source %q (use an IP, a CIDR, or any)", s.From)
+	}
+	if s.To != "" && s.To != "any" && !isAddress(s.To) {
+		return fmt.Errorf("invalid destination %q (use an IP, a CIDR, or any)", s.To)
+	}
+	if strings.ContainsAny(s.Comment, "'\"\n") {
+		return fmt.Errorf("comment cannot contain quotes or newlines")
+	}
+	return nil
+}
+
+// Args returns the ufw arguments that add the rule
+func (s UFWRuleSpec) Args() []string {
+	if s.Port != "" && !isPort(s.Port) {
+		// Application profiles such as "Nginx Full"
+		return []string{s.Action, s.Port}
+	}
+	from, to := s.From, s.To
+	if from == "" {
+		from = "any"
+	}
+	if to == "" {
+		to = "any"
+	}
+	args := []string{s.Action}
+	if s.Protocol != "" && s.Protocol != "any" {
+		args = append(args, "proto", s.Protocol)
+	}
+	args = append(args, "from", from, "to", to)
+	if s.Port != "" {
+		args = append(args, "port", s.Port)
+	}
+	if s.Comment != "" {
+		args = append(args, "comment", s.Comment)
+	}
+	return args
+}
+
+// Command returns the ufw command line for the rule
+func (s UFWRuleSpec) Command() string {
+	return shellJoin(append([]string{"ufw"}, s.Args()...))
+}
+
+// DeleteCommand returns the ufw command line that removes the rule,
+// including its IPv6 twin
+func (s UFWRuleSpec) DeleteCommand() string {
+	s.Comment = ""
+	return shellJoin(append([]string{"ufw", "delete"}, s.Args()...))
+}
+
+// key identifies rules that ufw treats as the same
+func (s UFWRuleSpec) key() string {
+	proto, from, to := s.Protocol, s.From, s.To
+	if proto == "" {
+		proto = "any"
+	}
+	if from == "" || strings.EqualFold(from, "Anywhere") {
+		from = "any"
+	}
+	if to == "" {
+		to = "any"
+	}
+	return strings.Join([]string{s.Action, s.Port, proto, from, to}, "|")
+}
+
+// String renders the rule for previews
+func (s UFWRuleSpec) String() string {
+	from := s.From
+	if from == "" || from == "any" {
+		from = "anywhere"
+	}
+	proto := ""
+	if s.Protocol != "" && s.Protocol != "any" {
+		proto = "/" + s.Protocol
+	}
+	port := s.Port
+	if port == "" {
+		port = "all ports"
+	}
+	text := fmt.Sprintf("%s %s%s from %s", strings.ToUpper(s.Action), port, proto, from)
+	if s.To != "" && s.To != "any" {
+		text += " to " + s.To
+	}
+	if s.Comment != "" {
+		text += "  # " + s.Comment
+	}
+	return text
+}
+
+// shellJoin quotes arguments for bash
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = ShellQuote(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// AddRule adds a UFW rule
+func (m *FirewallManager) AddRule(spec UFWRuleSpec) error {
+	if m.firewallType != FirewallUFW {
+		return fmt.Errorf("rule editing requires UFW")
+	}
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	if output, err := Command("ufw", spec.Args()...).CombinedOutput(); err != nil {
+		return fmt.Errorf("ufw: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// DeleteRuleNumber deletes a UFW rule by its number in `ufw status numbered`
+func (m *FirewallManager) DeleteRuleNumber(number int) error {
+	if m.firewallType != FirewallUFW {
+		return fmt.Errorf("rule editing requires UFW")
+	}
+	if output, err := Command("ufw", "--force", "delete", strconv.Itoa(number)).CombinedOutput(); err != nil {
+		return fmt.Errorf("ufw: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// FirewallProfile is a predefined set of rules applied in one action
+type FirewallProfile struct {
+	ID          string
+	Name        string
+	Description string
+	Rules       []UFWRuleSpec
+}
+
+// privateNetworks are the RFC 1918 ranges allowed to reach private services
+var privateNetworks = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}
+
+// FirewallProfiles returns the predefined profiles. sshPort is always
+// allowed so applying a profile cannot lock out the current session.
+func FirewallProfiles(sshPort string) []FirewallProfile {
+	ssh := UFWRuleSpec{Action: "limit", Port: sshPort, Protocol: "tcp", From: "any", Comment: "SSH"}
+
+	database := []UFWRuleSpec{ssh}
+	for _, svc := range []struct{ port, name string }{{"3306", "MySQL"}, {"5432", "PostgreSQL"}, {"6379", "Redis"}} {
+		for _, network := range privateNetworks {
+			database = append(database, UFWRuleSpec{Action: "allow", Port: svc.port, Protocol: "tcp", From: network, Comment: svc.name + " private"})
+		}
+	}
+
+	return []FirewallProfile{
+		{
+			ID:          "web",
+			Name:        "Web Server",
+			Description: "SSH plus HTTP and HTTPS (including HTTP/3 over UDP)",
+			Rules: []UFWRuleSpec{
+				ssh,
+				{Action: "allow", Port: "80", Protocol: "tcp", From: "any", Comment: "HTTP"},
+				{Action: "allow", Port: "443", Protocol: "tcp", From: "any", Comment: "HTTPS"},
+				{Action: "allow", Port: "443", Protocol: "udp", From: "any", Comment: "HTTP/3"},
+			},
+		},
+		{
+			ID:          "database-private",
+			Name:        "Database (private network)",
+			Description: "SSH plus MySQL, PostgreSQL, and Redis from private networks only",
+			Rules:       database,
+		},
+		{
+			ID:          "ssh-only",
+			Name:        "SSH Only",
+			Description: "Only SSH is reachable; everything else is denied",
+			Rules:       []UFWRuleSpec{ssh},
+		},
+	}
+}
+
+// FirewallPlan is the dry-run result of applying a profile
+type FirewallPlan struct {
+	Profile FirewallProfile
+	Add     []UFWRuleSpec // Rules the profile adds
+	Keep    []UFWRuleSpec // Existing rules the profile also contains
+	Remove  []UFWRuleSpec // Existing rules not in the profile
+}
+
+// PlanProfile works out how to move from the current rules to the profile.
+// Existing rules for the SSH port are never removed.
+func PlanProfile(current []FirewallRule, profile FirewallProfile, sshPort string) FirewallPlan {
+	plan := FirewallPlan{Profile: profile}
+
+	wanted := map[string]bool{}
+	for _, spec := range profile.Rules {
+		wanted[spec.key()] = true
+	}
+
+	existing := map[string]bool{}
+	for _, rule := range current {
+		if rule.Direction != "" && rule.Direction != "IN" {
+			continue
+		}
+		spec := SpecFromRule(rule)
+		key := spec.key()
+		if existing[key] {
+			continue // IPv6 twin
+		}
+		existing[key] = true
+		switch {
+		case wanted[key]:
+			plan.Keep = append(plan.Keep, spec)
+		case spec.Port == sshPort:
+			plan.Keep = append(plan.Keep, spec)
+		default:
+			plan.Remove = append(plan.Remove, spec)
+		}
+	}
+
+	for _, spec := range profile.Rules {
+		if !existing[spec.key()] {
+			plan.Add = append(plan.Add, spec)
+		}
+	}
+	return plan
+}
+
+// Commands returns the shell commands that apply the plan
+func (p FirewallPlan) Commands() []string {
+	commands := []string{
+		"ufw default deny incoming",
+		"ufw default allow outgoing",
+	}
+	// Add before removing so SSH stays reachable throughout
+	for _, spec := range p.Add {
+		commands = append(commands, spec.Command())
+	}
+	for _, spec := range p.Remove {
+		commands = append(commands, spec.DeleteCommand())
+	}
+	return append(commands, "ufw --force enable", "ufw status numbered")
+}
+
+// Script returns the plan as a single shell command
+func (p FirewallPlan) Script() string {
+	return strings.Join(p.Commands(), " && ")
+}
+
+// Result returns the rules in place after applying the plan, sorted by port
+func (p FirewallPlan) Result() []UFWRuleSpec {
+	result := append(append([]UFWRuleSpec(nil), p.Keep...), p.Add...)
+	sort.SliceStable(result, func(i, j int) bool {
+		a, _ := strconv.Atoi(strings.Split(result[i].Port, ":")[0])
+		b, _ := strconv.Atoi(strings.Split(result[j].Port, ":")[0])
+		return a < b
+	})
+	return result
+}
+
+// SSHPort returns the port sshd listens on, from sshd_config
+func SSHPort() string {
+	content, err := ReadFile("/etc/ssh/sshd_config")
+	if err != nil {
+		return "22"
+	}
+	return parseSSHPort(string(content))
+}
+
+// parseSSHPort returns the first Port directive, defaulting to 22
+func parseSSHPort(config string) string {
+	for _, line := range strings.Split(config, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && strings.EqualFold(fields[0], "Port") && isPort(fields[1]) {
+			return fields[1]
+		}
+	}
+	return "22"
+}
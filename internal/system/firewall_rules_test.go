@@ -0,0 +1,143 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+const ufwNumberedOutput = `Status: active
+
+     To                         Action      From
+     --                         ------      ----
+[ 1] 22/tcp                     ALLOW IN    Anywhere
+[ 2] 80/tcp                     ALLOW IN    Anywhere                   # HTTP
+[ 3] 3306/tcp                   ALLOW IN    203.0.113.5
+[ 4] Nginx Full                 ALLOW IN    Anywhere
+[ 5] 22/tcp (v6)                ALLOW IN    Anywhere (v6)
+[ 6] 80/tcp (v6)                ALLOW IN    Anywhere (v6)              # HTTP
+`
+
+func TestParseUFWNumbered(t *testing.T) {
+	rules := parseUFWNumbered(ufwNumberedOutput)
+	if len(rules) != 6 {
+		t.Fatalf("expected 6 rules, got %d: %+v", len(rules), rules)
+	}
+	if r := rules[1]; r.Number != 2 || r.Port != "80" || r.Protocol != "tcp" || r.Comment != "HTTP" || r.From != "Anywhere" {
+		t.Errorf("unexpected rule 2: %+v", r)
+	}
+	if r := rules[2]; r.From != "203.0.113.5" || r.Action != "allow" || r.Direction != "IN" {
+		t.Errorf("unexpected rule 3: %+v", r)
+	}
+	if r := rules[3]; r.Port != "Nginx Full" || r.Protocol != "any" {
+		t.Errorf("unexpected app rule: %+v", r)
+	}
+	if r := rules[4]; !r.V6 || r.Port != "22" || r.From != "Anywhere" {
+		t.Errorf("unexpected v6 rule: %+v", r)
+	}
+}
+
+func TestParseUFWNumberedDestination(t *testing.T) {
+	rules := parseUFWNumbered(`[ 1] 198.51.100.7 22/tcp          ALLOW IN    203.0.113.5
+[ 2] 10.0.0.0/8 5432            ALLOW IN    Anywhere
+[ 3] Anywhere                   DENY IN     192.0.2.9
+`)
+	if len(rules) != 3 {
+		t.Fatalf("expected 3 rules, got %d: %+v", len(rules), rules)
+	}
+	if r := rules[0]; r.To != "198.51.100.7" || r.Port != "22" || r.Protocol != "tcp" || r.From != "203.0.113.5" {
+		t.Errorf("unexpected rule 1: %+v", r)
+	}
+	if r := rules[1]; r.To != "10.0.0.0/8" || r.Port != "5432" || r.Protocol != "any" {
+		t.Errorf("unexpected rule 2: %+v", r)
+	}
+	if r := rules[2]; r.To != "" || r.Port != "" || r.From != "192.0.2.9" {
+		t.Errorf("unexpected rule 3: %+v", r)
+	}
+
+	if got := SpecFromRule(rules[0]).DeleteCommand(); got != "ufw delete allow proto tcp from 203.0.113.5 to 198.51.100.7 port 22" {
+		t.Errorf("unexpected delete command %q", got)
+	}
+	if got := SpecFromRule(rules[2]).DeleteCommand(); got != "ufw delete deny from 192.0.2.9 to any" {
+		t.Errorf("unexpected delete command %q", got)
+	}
+}
+
+func TestUFWRuleSpec(t *testing.T) {
+	spec := UFWRuleSpec{Action: "allow", Port: "5432", Protocol: "tcp", From: "10.0.0.0/8", Comment: "PostgreSQL private"}
+	if err := spec.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if got := spec.Command(); got != "ufw allow proto tcp from 10.0.0.0/8 to any port 5432 comment 'PostgreSQL private'" {
+		t.Errorf("unexpected command %q", got)
+	}
+	if got := spec.DeleteCommand(); got != "ufw delete allow proto tcp from 10.0.0.0/8 to any port 5432" {
+		t.Errorf("unexpected delete command %q", got)
+	}
+
+	for _, bad := range []UFWRuleSpec{
+		{Action: "open", Port: "80"},
+		{Action: "allow", Port: "70000"},
+		{Action: "allow", Port: "8000:8100", Protocol: "any"},
+		{Action: "allow", Port: "80", From: "not-an-ip"},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestPlanProfile(t *testing.T) {
+	current := parseUFWNumbered(ufwNumberedOutput)
+	var web FirewallProfile
+	for _, p := range FirewallProfiles("22") {
+		if p.ID == "web" {
+			web = p
+		}
+	}
+
+	plan := PlanProfile(current, web, "22")
+
+	// 80/tcp is already open; SSH is switched to limit but the allow rule is kept
+	var added []string
+	for _, spec := range plan.Add {
+		added = append(added, spec.String())
+	}
+	if len(plan.Add) != 3 || !strings.Contains(strings.Join(added, ","), "LIMIT 22/tcp") {
+		t.Errorf("unexpected additions %v", added)
+	}
+	if len(plan.Remove) != 2 {
+		t.Errorf("expected MySQL and Nginx Full to be removed, got %+v", plan.Remove)
+	}
+	for _, spec := range plan.Remove {
+		if spec.Port == "22" {
+			t.Error("SSH rule must never be removed")
+		}
+	}
+	if len(plan.Result()) != len(plan.Keep)+len(plan.Add) {
+		t.Error("result must contain kept and added rules")
+	}
+
+	// A rule for one destination address is not the profile's rule for any
+	destination := parseUFWNumbered("[ 1] 198.51.100.7 80/tcp          ALLOW IN    Anywhere\n")
+	plan = PlanProfile(destination, web, "22")
+	if len(plan.Remove) != 1 || plan.Remove[0].To != "198.51.100.7" || plan.Remove[0].Port != "80" {
+		t.Errorf("expected the destination rule to be removed, got %+v", plan.Remove)
+	}
+	if len(plan.Add) != len(web.Rules) {
+		t.Errorf("expected every profile rule to be added, got %+v", plan.Add)
+	}
+
+	commands := plan.Commands()
+	if commands[0] != "ufw default deny incoming" || commands[len(commands)-2] != "ufw --force enable" {
+		t.Errorf("unexpected commands %v", commands)
+	}
+}
+
+func TestParseSSHPort(t *testing.T) {
+	if got := parseSSHPort("# Port 22\nPort 2222\n"); got != "2222" {
+		t.Errorf("got %q, want 2222", got)
+	}
+	if got := parseSSHPort("PermitRootLogin no\n"); got != "22" {
+		t.Errorf("got %q, want default 22", got)
+	}
+}
@@ -0,0 +1,192 @@
+package system
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// GitTokenService is the secrets vault service name for git HTTPS tokens.
+// Each secret's account is the host and its note the username.
+const GitTokenService = "Git HTTPS token"
+
+// GitCredentialsFile is git's credential store inside a user's home
+const GitCredentialsFile = ".git-credentials"
+
+// gitTempCredentialsFile holds a vault token for a single clone or pull
+const gitTempCredentialsFile = ".ravact-git-credentials"
+
+// GitCredential is a personal access token for an HTTPS git host
+type GitCredential struct {
+	Host     string // e.g. github.com
+	Username string // x-access-token for GitHub, oauth2 for GitLab
+	Token    string
+}
+
+// DefaultGitTokenUser returns the username a host expects with a token
+func DefaultGitTokenUser(host string) string {
+	if strings.Contains(host, "gitlab") {
+		return "oauth2"
+	}
+	return "x-access-token"
+}
+
+// Validate checks a credential before it is stored
+func (c GitCredential) Validate() error {
+	if c.Host == "" || strings.ContainsAny(c.Host, "/@: ") {
+		return fmt.Errorf("enter a host name such as github.com")
+	}
+	if c.Username == "" {
+		return fmt.Errorf("username is required")
+	}
+	if c.Token == "" || strings.ContainsAny(c.Token, " \t\r\n") {
+		return fmt.Errorf("token is empty or contains whitespace")
+	}
+	return nil
+}
+
+// Line returns the credential as a git credential store line
+func (c GitCredential) Line() string {
+	u := url.URL{Scheme: "https", User: url.UserPassword(c.Username, c.Token), Host: c.Host}
+	return u.String()
+}
+
+// HTTPSRemoteHost returns the host of an https:// remote URL
+func HTTPSRemoteHost(remote string) (string, bool) {
+	u, err := url.Parse(remote)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return "", false
+	}
+	return u.Host, true
+}
+
+// credentialHost returns the host of a credential store line
+func credentialHost(line string) string {
+	u, err := url.Parse(strings.TrimSpace(line))
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// mergeGitCredentials replaces any credential for the same host in a
+// credential store file with c
+func mergeGitCredentials(content string, c GitCredential) string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" || credentialHost(line) == c.Host {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if c.Token != "" {
+		lines = append(lines, c.Line())
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// gitCredentialsPath returns a user's credential store and owner
+func (um *UserManager) gitCredentialsPath(username string) (string, string, error) {
+	user, err := um.GetUser(username)
+	if err != nil {
+		return "", "", err
+	}
+	return path.Join(user.HomeDir, GitCredentialsFile), fmt.Sprintf("%s:%d", username, user.GID), nil
+}
+
+// SaveGitCredential stores a token in a user's git credential store and
+// turns the store on for them, so clones, pulls, and webhook deploys of
+// HTTPS remotes on that host authenticate without SSH
+func (um *UserManager) SaveGitCredential(username string, c GitCredential) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	file, owner, err := um.gitCredentialsPath(username)
+	if err != nil {
+		return err
+	}
+	content, err := ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if err := WriteFile(file, []byte(mergeGitCredentials(string(content), c)), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	Command("chown", owner, file).Run()
+	if output, err := Command("sudo", "-H", "-u", username, "git", "config", "--global", "credential.helper", "store").CombinedOutput(); err != nil {
+		return fmt.Errorf("token saved but enabling the credential store failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// RemoveGitCredential deletes a host's token from a user's credential store
+func (um *UserManager) RemoveGitCredential(username, host string) error {
+	file, _, err := um.gitCredentialsPath(username)
+	if err != nil {
+		return err
+	}
+	content, err := ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return WriteFile(file, []byte(mergeGitCredentials(string(content), GitCredential{Host: host})), 0600)
+}
+
+// GitCredentialHosts lists the hosts a user has stored tokens for
+func (um *UserManager) GitCredentialHosts(username string) []string {
+	file, _, err := um.gitCredentialsPath(username)
+	if err != nil {
+		return nil
+	}
+	content, err := ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	var hosts []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if host := credentialHost(line); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// WriteTempGitCredential writes a token kept in the secrets vault to a
+// private file in the user's home and returns shell lines that make git use
+// only that file and delete it when the script exits. The token never
+// appears in the command itself.
+func (um *UserManager) WriteTempGitCredential(username string, c GitCredential) (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+	user, err := um.GetUser(username)
+	if err != nil {
+		return "", err
+	}
+	file := path.Join(user.HomeDir, gitTempCredentialsFile)
+	if err := WriteFile(file, []byte(c.Line()+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to write temporary credentials: %w", err)
+	}
+	Command("chown", fmt.Sprintf("%s:%d", username, user.GID), file).Run()
+	return GitTempCredentialEnv(file), nil
+}
+
+// GitTempCredentialEnv returns the shell lines that point git at a single
+// credential file, through environment config so submodules use it too
+func GitTempCredentialEnv(file string) string {
+	q := ShellQuote(file)
+	return fmt.Sprintf("trap 'rm -f %s' EXIT\n"+
+		"export GIT_CONFIG_COUNT=2 GIT_CONFIG_KEY_0=credential.helper GIT_CONFIG_VALUE_0= "+
+		"GIT_CONFIG_KEY_1=credential.helper GIT_CONFIG_VALUE_1=%s GIT_TERMINAL_PROMPT=0\n",
+		strings.ReplaceAll(q, "'", `'\''`), ShellQuote("store --file="+file))
+}
@@ -0,0 +1,153 @@
+package system
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gitLogFormat separates the fields of each commit after the graph
+const gitLogFormat = "--format=%x1f%H%x1f%an%x1f%aI%x1f%D%x1f%s"
+
+// GitLogEntry is one line of `git log --graph`. Lines that only continue
+// the graph have no Hash.
+type GitLogEntry struct {
+	Graph   string
+	Hash    string
+	Author  string
+	Date    time.Time
+	Refs    string // e.g. HEAD -> main, origin/main
+	Subject string
+}
+
+// ShortHash returns the abbreviated commit hash
+func (e GitLogEntry) ShortHash() string {
+	if len(e.Hash) > 7 {
+		return e.Hash[:7]
+	}
+	return e.Hash
+}
+
+// GitComparison is how the local branch differs from its upstream
+type GitComparison struct {
+	Branch   string
+	Upstream string        // e.g. origin/main
+	Ahead    []GitLogEntry // Local commits not on the upstream
+	Behind   []GitLogEntry // Upstream commits not pulled yet
+}
+
+// repoGit runs a read-only git command in a working copy. The directory is
+// marked safe so history can be read when root does not own the checkout.
+func repoGit(dir string, args ...string) (string, error) {
+	output, err := Command("git", append([]string{"-c", "safe.directory=" + dir, "-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
+
+// GitLog returns up to limit commits reachable from rev (all branches when
+// empty) with the commit graph, newest first
+func GitLog(dir, rev string, limit int) ([]GitLogEntry, error) {
+	args := []string{"log", "--graph", fmt.Sprintf("-n%d", limit), gitLogFormat}
+	if rev == "" {
+		args = append(args, "--all")
+	} else {
+		args = append(args, rev, "--")
+	}
+	output, err := repoGit(dir, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseGitLog(output), nil
+}
+
+// parseGitLog parses `git log --graph` output in gitLogFormat
+func parseGitLog(output string) []GitLogEntry {
+	var entries []GitLogEntry
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.Split(line, "\x1f")
+		if len(parts) != 6 {
+			entries = append(entries, GitLogEntry{Graph: strings.TrimRight(line, " ")})
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, parts[3])
+		entries = append(entries, GitLogEntry{
+			Graph:   strings.TrimRight(parts[0], " "),
+			Hash:    parts[1],
+			Author:  parts[2],
+			Date:    date,
+			Refs:    parts[4],
+			Subject: parts[5],
+		})
+	}
+	return entries
+}
+
+// GitCommitDetail returns a commit's header, file stats, and patch
+func GitCommitDetail(dir, hash string) (string, error) {
+	return repoGit(dir, "show", "--stat", "--patch", "--format=fuller", "--no-color", hash, "--")
+}
+
+// GitCompareUpstream compares HEAD with its upstream branch as of the last
+// fetch
+func GitCompareUpstream(dir string) (*GitComparison, error) {
+	branch, err := repoGit(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	c := &GitComparison{Branch: strings.TrimSpace(branch)}
+	if c.Branch == "HEAD" {
+		return nil, fmt.Errorf("HEAD is detached (a tag or commit is checked out), so there is no upstream to compare with")
+	}
+	upstream, err := repoGit(dir, "rev-parse", "--abbrev-ref", "@{upstream}")
+	if err != nil {
+		return nil, fmt.Errorf("branch %s has no upstream; set one with git branch -u origin/%s", c.Branch, c.Branch)
+	}
+	c.Upstream = strings.TrimSpace(upstream)
+
+	for _, side := range []struct {
+		rev  string
+		list *[]GitLogEntry
+	}{
+		{"@{upstream}..HEAD", &c.Ahead},
+		{"HEAD..@{upstream}", &c.Behind},
+	} {
+		output, err := repoGit(dir, "log", gitLogFormat, side.rev, "--")
+		if err != nil {
+			return nil, err
+		}
+		*side.list = parseGitLog(output)
+	}
+	return c, nil
+}
+
+// GitDiffStat returns the file stats between two revisions
+func GitDiffStat(dir, from, to string) (string, error) {
+	return repoGit(dir, "diff", "--stat", "--no-color", from+"..."+to, "--")
+}
+
+// gitCount formats a commit count
+func gitCount(n int) string {
+	if n == 1 {
+		return "1 commit"
+	}
+	return strconv.Itoa(n) + " commits"
+}
+
+// Summary describes the comparison in one line
+func (c GitComparison) Summary() string {
+	switch {
+	case len(c.Ahead) == 0 && len(c.Behind) == 0:
+		return fmt.Sprintf("%s is up to date with %s", c.Branch, c.Upstream)
+	case len(c.Behind) == 0:
+		return fmt.Sprintf("%s is %s ahead of %s", c.Branch, gitCount(len(c.Ahead)), c.Upstream)
+	case len(c.Ahead) == 0:
+		return fmt.Sprintf("%s is %s behind %s", c.Branch, gitCount(len(c.Behind)), c.Upstream)
+	}
+	return fmt.Sprintf("%s and %s have diverged: %s ahead, %s behind", c.Branch, c.Upstream, gitCount(len(c.Ahead)), gitCount(len(c.Behind)))
+}
@@ -0,0 +1,89 @@
+package system
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseGitLog(t *testing.T) {
+	output := "* \x1faaaaaaaaaa\x1fJo\x1f2026-03-01T10:00:00+00:00\x1fHEAD -> main, origin/main\x1fMerge feature\n" +
+		"|\\  \n" +
+		"| * \x1fbbbbbbbbbb\x1fSam\x1f2026-02-28T09:00:00+00:00\x1f\x1fAdd feature\n" +
+		"|/  \n"
+	entries := parseGitLog(output)
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d: %+v", len(entries), entries)
+	}
+	if e := entries[0]; e.Graph != "*" || e.ShortHash() != "aaaaaaa" || e.Refs != "HEAD -> main, origin/main" || e.Subject != "Merge feature" || e.Date.Day() != 1 {
+		t.Errorf("unexpected first entry %+v", e)
+	}
+	if e := entries[1]; e.Graph != "|\\" || e.Hash != "" {
+		t.Errorf("expected a graph-only line, got %+v", e)
+	}
+	if e := entries[2]; e.Graph != "| *" || e.Author != "Sam" || e.Refs != "" {
+		t.Errorf("unexpected branch entry %+v", e)
+	}
+}
+
+func TestGitCompareUpstream(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	origin, clone := filepath.Join(root, "origin"), filepath.Join(root, "clone")
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	commit := func(dir, file string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(file), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run(dir, "add", file)
+		run(dir, "commit", "-q", "-m", "Add "+file)
+	}
+
+	run(root, "init", "-q", "-b", "main", origin)
+	commit(origin, "a.txt")
+	run(root, "clone", "-q", origin, clone)
+	commit(origin, "b.txt")
+	commit(clone, "c.txt")
+	run(clone, "fetch", "-q")
+
+	c, err := GitCompareUpstream(clone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Branch != "main" || c.Upstream != "origin/main" || len(c.Ahead) != 1 || len(c.Behind) != 1 {
+		t.Fatalf("unexpected comparison %+v", c)
+	}
+	if c.Behind[0].Subject != "Add b.txt" || !strings.Contains(c.Summary(), "diverged") {
+		t.Errorf("got %q, %q", c.Behind[0].Subject, c.Summary())
+	}
+
+	entries, err := GitLog(clone, "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var subjects []string
+	for _, e := range entries {
+		if e.Hash != "" {
+			subjects = append(subjects, e.Subject)
+		}
+	}
+	if len(subjects) != 3 {
+		t.Errorf("expected all 3 commits across branches, got %v", subjects)
+	}
+
+	detail, err := GitCommitDetail(clone, c.Ahead[0].Hash)
+	if err != nil || !strings.Contains(detail, "c.txt | 1 +") || !strings.Contains(detail, "+c.txt") {
+		t.Errorf("unexpected detail %q, %v", detail, err)
+	}
+}
@@ -0,0 +1,231 @@
+package system
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GitRef is a branch or tag published by a remote repository
+type GitRef struct {
+	Name   string // Short name, e.g. "main" or "v1.2.0"
+	Tag    bool
+	Commit string
+}
+
+// FullName returns the ref as stored in meta.deployref, e.g. refs/tags/v1.2.0
+func (r GitRef) FullName() string {
+	if r.Tag {
+		return "refs/tags/" + r.Name
+	}
+	return "refs/heads/" + r.Name
+}
+
+// ParseDeployRef splits a meta.deployref value into a GitRef. Bare names
+// are taken to be branches.
+func ParseDeployRef(ref string) GitRef {
+	if name, ok := strings.CutPrefix(ref, "refs/tags/"); ok {
+		return GitRef{Name: name, Tag: true}
+	}
+	return GitRef{Name: strings.TrimPrefix(ref, "refs/heads/")}
+}
+
+// ParseLsRemote reads `git ls-remote --symref --heads --tags` output into
+// branches then tags, each sorted by name, and the default branch from the
+// HEAD symref when present. Peeled tag lines (^{}) replace the tag object
+// with the commit it points at.
+func ParseLsRemote(output string) ([]GitRef, string) {
+	var head string
+	branches := map[string]GitRef{}
+	tags := map[string]GitRef{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if fields[0] == "ref:" {
+			if len(fields) == 3 && fields[2] == "HEAD" {
+				head = strings.TrimPrefix(fields[1], "refs/heads/")
+			}
+			continue
+		}
+		commit, ref := fields[0], fields[1]
+		if name, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+			branches[name] = GitRef{Name: name, Commit: commit}
+		} else if name, ok := strings.CutPrefix(ref, "refs/tags/"); ok {
+			name, peeled := strings.CutSuffix(name, "^{}")
+			if _, seen := tags[name]; !seen || peeled {
+				tags[name] = GitRef{Name: name, Tag: true, Commit: commit}
+			}
+		}
+	}
+
+	refs := make([]GitRef, 0, len(branches)+len(tags))
+	for _, group := range []map[string]GitRef{branches, tags} {
+		names := make([]string, 0, len(group))
+		for name := range group {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			refs = append(refs, group[name])
+		}
+	}
+	return refs, head
+}
+
+// ListRemoteRefs lists a repository's branches and tags as user, with the
+// SSH keys in their ~/.ssh loaded the same way the clone does. Prompts for
+// host keys or passwords fail instead of hanging.
+func ListRemoteRefs(user, url string) ([]GitRef, string, error) {
+	script := `eval $(ssh-agent -s) > /dev/null 2>&1
+for key in ~/.ssh/id_*; do
+    if [[ -f "$key" && "$key" != *.pub ]] && head -n 1 "$key" | grep -q "PRIVATE KEY"; then
+        ssh-add "$key" > /dev/null 2>&1
+    fi
+done
+GIT_TERMINAL_PROMPT=0 GIT_SSH_COMMAND="ssh -o BatchMode=yes -o StrictHostKeyChecking=accept-new" \
+    git ls-remote --symref --heads --tags -- "$1" HEAD 'refs/heads/*' 'refs/tags/*'
+status=$?
+ssh-agent -k > /dev/null 2>&1
+exit $status`
+	output, err := Command("sudo", "-H", "-u", user, "bash", "-c", script, "ls-remote", url).CombinedOutput()
+	if err != nil {
+		return nil, "", fmt.Errorf("git ls-remote failed: %s", strings.TrimSpace(string(output)))
+	}
+	refs, head := ParseLsRemote(string(output))
+	return refs, head, nil
+}
+
+// GitCloneOptions are the choices made when cloning a repository
+type GitCloneOptions struct {
+	Ref        GitRef // Empty name clones the default branch
+	Depth      int    // 0 clones the full history
+	Submodules bool
+}
+
+// ParseCloneDepth reads the shallow clone depth, empty meaning full history
+func ParseCloneDepth(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	depth, err := strconv.Atoi(s)
+	if err != nil || depth < 0 {
+		return 0, fmt.Errorf("depth must be a positive number, or empty for full history")
+	}
+	return depth, nil
+}
+
+// CloneArgs returns the git clone flags for the options
+func (o GitCloneOptions) CloneArgs() []string {
+	var args []string
+	if o.Ref.Name != "" {
+		args = append(args, "--branch", o.Ref.Name)
+	}
+	if o.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(o.Depth))
+	}
+	if o.Submodules {
+		args = append(args, "--recurse-submodules")
+		if o.Depth > 0 {
+			args = append(args, "--shallow-submodules")
+		}
+	}
+	return args
+}
+
+// GitDeployCommand returns the commands that bring a checkout up to date
+// with its meta.deployref: branches are pulled fast-forward only and tags
+// are fetched and checked out. Without a ref it is a plain pull. Submodules
+// are updated to match when the repository has them.
+func GitDeployCommand(deployRef string) string {
+	var cmd string
+	switch ref := ParseDeployRef(deployRef); {
+	case deployRef == "":
+		cmd = "git pull"
+	case ref.Tag:
+		tag := ShellQuote(ref.Name)
+		cmd = fmt.Sprintf("git fetch --force origin tag %s && git checkout %s", tag, tag)
+	default:
+		cmd = "git pull --ff-only origin " + ShellQuote(ref.Name)
+	}
+	return cmd + " && if [ -f .gitmodules ]; then git submodule update --init --recursive; fi"
+}
+
+// precompressPatterns are the text assets worth serving pre-compressed
+var precompressPatterns = []string{"*.html", "*.css", "*.js", "*.mjs", "*.json", "*.svg", "*.xml", "*.txt", "*.wasm"}
+
+// BuildStep runs after each pull, for sites such as SPAs that deploy a
+// build output. It is kept in the repository's meta.buildcommand and
+// meta.precompress config, alongside meta.deployref.
+type BuildStep struct {
+	Command     string // e.g. npm ci && npm run build
+	Precompress string // Build output directory, relative to the repository, whose assets get .gz and .br copies
+}
+
+// Validate checks the precompress directory stays inside the repository
+func (b BuildStep) Validate() error {
+	dir := strings.TrimSpace(b.Precompress)
+	if dir == "" {
+		return nil
+	}
+	if strings.HasPrefix(dir, "/") || slices.Contains(strings.Split(dir, "/"), "..") {
+		return fmt.Errorf("precompress directory must be inside the repository, e.g. dist")
+	}
+	return nil
+}
+
+// Script returns the commands that run the build and then compress the
+// assets in the output directory. brotli copies are made when the brotli
+// tool is installed.
+func (b BuildStep) Script() string {
+	var steps []string
+	if cmd := strings.TrimSpace(b.Command); cmd != "" {
+		steps = append(steps, "( "+cmd+" )")
+	}
+	if dir := strings.TrimSpace(b.Precompress); dir != "" {
+		var names []string
+		for _, pattern := range precompressPatterns {
+			names = append(names, "-name '"+pattern+"'")
+		}
+		find := fmt.Sprintf("find %s -type f \\( %s \\) -size +1k", ShellQuote(dir), strings.Join(names, " -o "))
+		steps = append(steps, find+" -exec gzip -kf9 {} +",
+			"{ ! command -v brotli >/dev/null 2>&1 || "+find+" -exec brotli -kf {} +; }")
+	}
+	return strings.Join(steps, " && ")
+}
+
+// ReadBuildStep returns the build step recorded in the checkout at dir
+func ReadBuildStep(dir string) BuildStep {
+	get := func(key string) string {
+		output, _ := Command("git", "-C", dir, "config", "--get", key).Output()
+		return strings.TrimSpace(string(output))
+	}
+	return BuildStep{Command: get("meta.buildcommand"), Precompress: get("meta.precompress")}
+}
+
+// Save records the build step in the checkout at dir, removing empty
+// settings
+func (b BuildStep) Save(dir string) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+	for key, value := range map[string]string{"meta.buildcommand": b.Command, "meta.precompress": b.Precompress} {
+		args := []string{"-C", dir, "config", key, strings.TrimSpace(value)}
+		if strings.TrimSpace(value) == "" {
+			args = []string{"-C", dir, "config", "--unset", key}
+		}
+		// Exit status 5 is unsetting a key that is not set
+		if output, err := Command("git", args...).CombinedOutput(); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 5 {
+				continue
+			}
+			return fmt.Errorf("failed to set %s: %s", key, strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package system
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseLsRemote(t *testing.T) {
+	output := "ref: refs/heads/main\tHEAD\n" +
+		"1111111111111111111111111111111111111111\tHEAD\n" +
+		"2222222222222222222222222222222222222222\trefs/heads/release\n" +
+		"1111111111111111111111111111111111111111\trefs/heads/main\n" +
+		"3333333333333333333333333333333333333333\trefs/tags/v1.0.0\n" +
+		"4444444444444444444444444444444444444444\trefs/tags/v1.1.0\n" +
+		"5555555555555555555555555555555555555555\trefs/tags/v1.1.0^{}\n"
+
+	refs, head := ParseLsRemote(output)
+	if head != "main" {
+		t.Errorf("expected default branch main, got %q", head)
+	}
+	want := []GitRef{
+		{Name: "main", Commit: "1111111111111111111111111111111111111111"},
+		{Name: "release", Commit: "2222222222222222222222222222222222222222"},
+		{Name: "v1.0.0", Tag: true, Commit: "3333333333333333333333333333333333333333"},
+		{Name: "v1.1.0", Tag: true, Commit: "5555555555555555555555555555555555555555"},
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("got %+v\nwant %+v", refs, want)
+	}
+}
+
+func TestDeployRefRoundTrip(t *testing.T) {
+	for _, ref := range []GitRef{{Name: "main"}, {Name: "feature/login"}, {Name: "v2.0.0", Tag: true}} {
+		if got := ParseDeployRef(ref.FullName()); got != ref {
+			t.Errorf("ParseDeployRef(%q) = %+v, want %+v", ref.FullName(), got, ref)
+		}
+	}
+	if got := ParseDeployRef("develop"); got != (GitRef{Name: "develop"}) {
+		t.Errorf("expected a bare name to be a branch, got %+v", got)
+	}
+}
+
+func TestCloneArgs(t *testing.T) {
+	cases := []struct {
+		opts GitCloneOptions
+		want []string
+	}{
+		{GitCloneOptions{}, nil},
+		{GitCloneOptions{Ref: GitRef{Name: "v1.0.0", Tag: true}, Depth: 1}, []string{"--branch", "v1.0.0", "--depth", "1"}},
+		{GitCloneOptions{Submodules: true}, []string{"--recurse-submodules"}},
+		{GitCloneOptions{Depth: 5, Submodules: true}, []string{"--depth", "5", "--recurse-submodules", "--shallow-submodules"}},
+	}
+	for _, c := range cases {
+		if got := c.opts.CloneArgs(); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("CloneArgs(%+v) = %v, want %v", c.opts, got, c.want)
+		}
+	}
+
+	if _, err := ParseCloneDepth("-1"); err == nil {
+		t.Error("expected a negative depth to be rejected")
+	}
+	if depth, err := ParseCloneDepth(" "); err != nil || depth != 0 {
+		t.Errorf("expected empty depth to mean full history, got %d, %v", depth, err)
+	}
+}
+
+func TestGitDeployCommand(t *testing.T) {
+	const submodules = " && if [ -f .gitmodules ]; then git submodule update --init --recursive; fi"
+	cases := map[string]string{
+		"":                 "git pull" + submodules,
+		"refs/heads/main":  "git pull --ff-only origin main" + submodules,
+		"refs/tags/v1.2.0": "git fetch --force origin tag v1.2.0 && git checkout v1.2.0" + submodules,
+	}
+	for ref, want := range cases {
+		if got := GitDeployCommand(ref); got != want {
+			t.Errorf("GitDeployCommand(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
+
+func TestBuildStep(t *testing.T) {
+	if (BuildStep{}).Script() != "" {
+		t.Error("an empty build step should run nothing")
+	}
+	for _, dir := range []string{"/var/www/dist", "../dist", "dist/../../x"} {
+		if (BuildStep{Precompress: dir}).Validate() == nil {
+			t.Errorf("expected %q to be rejected", dir)
+		}
+	}
+
+	repo := t.TempDir()
+	step := BuildStep{
+		Command:     "mkdir -p dist/assets && printf '%2048s' x > dist/assets/app.js && echo small > dist/robots.txt",
+		Precompress: "dist",
+	}
+	if err := step.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("bash", "-c", step.Script())
+	cmd.Dir = repo
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("build step failed: %v\n%s", err, output)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "dist/assets/app.js.gz")); err != nil {
+		t.Errorf("expected a gzip copy: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "dist/robots.txt.gz")); err == nil {
+		t.Error("files under 1k should not be compressed")
+	}
+}
+
+func TestBuildStep_SaveAndRead(t *testing.T) {
+	repo := t.TempDir()
+	if output, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Skipf("git unavailable: %v\n%s", err, output)
+	}
+
+	step := BuildStep{Command: "npm ci && npm run build", Precompress: "dist"}
+	if err := step.Save(repo); err != nil {
+		t.Fatal(err)
+	}
+	if got := ReadBuildStep(repo); got != step {
+		t.Errorf("ReadBuildStep() = %+v, want %+v", got, step)
+	}
+
+	// Clearing a setting twice must not fail on the missing key
+	for i := 0; i < 2; i++ {
+		if err := (BuildStep{Command: "npm run build"}).Save(repo); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := ReadBuildStep(repo); got.Precompress != "" {
+		t.Errorf("precompress should be cleared, got %q", got.Precompress)
+	}
+	if err := (BuildStep{Precompress: "../public"}).Save(repo); err == nil {
+		t.Error("expected an error for a directory outside the repository")
+	}
+}
@@ -0,0 +1,243 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// AnalyticsPath is the URL path the HTML report is published under. It is
+// served from the site's document root behind basic auth.
+const AnalyticsPath = "/ravact-analytics"
+
+// analyticsReportFile is the report's file name; PHP sites only index
+// index.php, so the report is linked by name
+const analyticsReportFile = "report.html"
+
+// GoAccessPanel is one of the report panels shown in the viewer
+type GoAccessPanel struct {
+	Key   string // GoAccess panel key in the JSON output
+	Title string
+	Items []GoAccessItem
+}
+
+// GoAccessItem is a row of a panel, sorted by hits
+type GoAccessItem struct {
+	Label    string
+	Hits     int64
+	Visitors int64
+	Bytes    int64
+}
+
+// GoAccessReport is the parsed JSON output of GoAccess
+type GoAccessReport struct {
+	StartDate      string
+	EndDate        string
+	TotalRequests  int64
+	ValidRequests  int64
+	FailedRequests int64
+	UniqueVisitors int64
+	Bandwidth      int64
+	Panels         []GoAccessPanel
+}
+
+// goaccessPanels are the panels that answer what is hitting a site, in
+// the order the viewer shows them
+var goaccessPanels = []struct{ key, title string }{
+	{"requests", "Requests"},
+	{"hosts", "Visitor IPs"},
+	{"status_codes", "Status Codes"},
+	{"not_found", "Not Found"},
+	{"browsers", "Browsers"},
+	{"referring_sites", "Referring Sites"},
+}
+
+// goaccessCount reads counts written as a number by older GoAccess
+// versions and as {"count": n, "percent": p} by newer ones
+type goaccessCount int64
+
+// UnmarshalJSON accepts either form of a count
+func (c *goaccessCount) UnmarshalJSON(data []byte) error {
+	var n int64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*c = goaccessCount(n)
+		return nil
+	}
+	var obj struct {
+		Count int64 `json:"count"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*c = goaccessCount(obj.Count)
+	return nil
+}
+
+// parseGoAccessJSON reads the general totals and the viewer's panels
+func parseGoAccessJSON(data []byte) (GoAccessReport, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return GoAccessReport{}, fmt.Errorf("failed to parse GoAccess output: %w", err)
+	}
+
+	var general struct {
+		StartDate      string        `json:"start_date"`
+		EndDate        string        `json:"end_date"`
+		TotalRequests  goaccessCount `json:"total_requests"`
+		ValidRequests  goaccessCount `json:"valid_requests"`
+		FailedRequests goaccessCount `json:"failed_requests"`
+		UniqueVisitors goaccessCount `json:"unique_visitors"`
+		Bandwidth      goaccessCount `json:"bandwidth"`
+	}
+	if g, ok := raw["general"]; ok {
+		if err := json.Unmarshal(g, &general); err != nil {
+			return GoAccessReport{}, fmt.Errorf("failed to parse GoAccess totals: %w", err)
+		}
+	}
+	report := GoAccessReport{
+		StartDate:      general.StartDate,
+		EndDate:        general.EndDate,
+		TotalRequests:  int64(general.TotalRequests),
+		ValidRequests:  int64(general.ValidRequests),
+		FailedRequests: int64(general.FailedRequests),
+		UniqueVisitors: int64(general.UniqueVisitors),
+		Bandwidth:      int64(general.Bandwidth),
+	}
+
+	for _, p := range goaccessPanels {
+		data, ok := raw[p.key]
+		if !ok {
+			continue
+		}
+		var panel struct {
+			Data []struct {
+				Data     string        `json:"data"`
+				Method   string        `json:"method"`
+				Hits     goaccessCount `json:"hits"`
+				Visitors goaccessCount `json:"visitors"`
+				Bytes    goaccessCount `json:"bytes"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(data, &panel); err != nil {
+			return GoAccessReport{}, fmt.Errorf("failed to parse GoAccess %s panel: %w", p.key, err)
+		}
+		out := GoAccessPanel{Key: p.key, Title: p.title}
+		for _, d := range panel.Data {
+			label := d.Data
+			if d.Method != "" {
+				label = d.Method + " " + label
+			}
+			out.Items = append(out.Items, GoAccessItem{
+				Label:    label,
+				Hits:     int64(d.Hits),
+				Visitors: int64(d.Visitors),
+				Bytes:    int64(d.Bytes),
+			})
+		}
+		report.Panels = append(report.Panels, out)
+	}
+	return report, nil
+}
+
+// GoAccessInstalled reports whether goaccess is on the active host
+func GoAccessInstalled() bool {
+	return Command("which", "goaccess").Run() == nil
+}
+
+// accessLogPath returns the first access log a site config writes to,
+// skipping syslog targets and access_log off
+func accessLogPath(config string) string {
+	for _, line := range strings.Split(config, "\n") {
+		fields := strings.Fields(strings.TrimSuffix(strings.TrimSpace(line), ";"))
+		if len(fields) < 2 || fields[0] != "access_log" {
+			continue
+		}
+		if path := fields[1]; path != "off" && !strings.HasPrefix(path, "syslog:") && !strings.Contains(path, "$") {
+			return path
+		}
+	}
+	return ""
+}
+
+// SiteAccessLog returns the access log of a site: the one its config
+// names, else the per-site log ravact creates, else nginx's shared log
+func SiteAccessLog(site NginxSite) string {
+	if data, err := ReadFile(site.ConfigPath); err == nil {
+		if path := accessLogPath(string(data)); path != "" {
+			return path
+		}
+	}
+	path := "/var/log/nginx/" + site.Name + "-access.log"
+	if _, err := Stat(path); err == nil {
+		return path
+	}
+	return "/var/log/nginx/access.log"
+}
+
+// goaccessLogs returns the log and its first rotation, which logrotate
+// leaves uncompressed, so the report covers more than today
+func goaccessLogs(logPath string) []string {
+	logs := []string{logPath}
+	if _, err := Stat(logPath + ".1"); err == nil {
+		logs = append(logs, logPath+".1")
+	}
+	return logs
+}
+
+// goaccessArgs are the arguments shared by the JSON and HTML reports.
+// nginx's default log format is GoAccess's COMBINED.
+func goaccessArgs(logs []string, output string) []string {
+	return append(logs, "--log-format=COMBINED", "--no-progress", "-o", output)
+}
+
+// RunGoAccess analyses an access log and returns the report
+func RunGoAccess(logPath string) (GoAccessReport, error) {
+	output, err := Command("goaccess", goaccessArgs(goaccessLogs(logPath), "json")...).Output()
+	if err != nil {
+		return GoAccessReport{}, fmt.Errorf("goaccess failed on %s: %w", logPath, err)
+	}
+	return parseGoAccessJSON(output)
+}
+
+// AnalyticsReportPath is where a site's HTML report is written
+func AnalyticsReportPath(site NginxSite) string {
+	return filepath.Join(site.RootDir, strings.TrimPrefix(AnalyticsPath, "/"), analyticsReportFile)
+}
+
+// AnalyticsReportURL is where a site's HTML report is served
+func AnalyticsReportURL(site NginxSite) string {
+	scheme := "http"
+	if site.HasSSL {
+		scheme = "https"
+	}
+	return scheme + "://" + site.Domain + AnalyticsPath + "/" + analyticsReportFile
+}
+
+// AnalyticsProtected reports whether an access rule guards the report.
+// Rules are prefix locations, so any prefix of the path covers it.
+func AnalyticsProtected(rules []SiteAccessRule) bool {
+	for _, r := range rules {
+		if strings.HasPrefix(AnalyticsPath, r.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// AnalyticsReportScript returns the bash script that writes the HTML
+// report into the site's document root
+func AnalyticsReportScript(site NginxSite, logPath string) string {
+	report := AnalyticsReportPath(site)
+	var args []string
+	for _, arg := range goaccessArgs(goaccessLogs(logPath), report) {
+		args = append(args, ShellQuote(arg))
+	}
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	fmt.Fprintf(&b, "mkdir -p %s\n", ShellQuote(filepath.Dir(report)))
+	fmt.Fprintf(&b, "goaccess %s\n", strings.Join(args, " "))
+	fmt.Fprintf(&b, "chmod 644 %s\n", ShellQuote(report))
+	fmt.Fprintf(&b, "echo; echo %s\n", ShellQuote("==> Report written to "+AnalyticsReportURL(site)))
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGoAccessJSON(t *testing.T) {
+	// Newer versions write counts as objects, older ones as numbers
+	data := `{
+  "general": {"start_date": "01/Oct/2026", "end_date": "16/Oct/2026", "total_requests": 1200,
+    "valid_requests": 1180, "failed_requests": 20, "unique_visitors": 85, "bandwidth": 5242880},
+  "requests": {"metadata": {}, "data": [
+    {"hits": {"count": 700, "percent": 58.3}, "visitors": {"count": 40, "percent": 47.1},
+     "bytes": {"count": 1048576, "percent": 20.0}, "data": "/wp-login.php", "method": "POST", "protocol": "HTTP/1.1"}
+  ]},
+  "hosts": {"data": [{"hits": 500, "visitors": 1, "bytes": 2048, "data": "203.0.113.9"}]},
+  "visitors": {"data": [{"hits": 1, "data": "20261016"}]}
+}`
+	report, err := parseGoAccessJSON([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalRequests != 1200 || report.FailedRequests != 20 || report.UniqueVisitors != 85 || report.Bandwidth != 5242880 || report.EndDate != "16/Oct/2026" {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+	if len(report.Panels) != 2 || report.Panels[0].Key != "requests" || report.Panels[1].Title != "Visitor IPs" {
+		t.Fatalf("expected the requests and hosts panels in order, got %+v", report.Panels)
+	}
+	if got := report.Panels[0].Items[0]; got != (GoAccessItem{Label: "POST /wp-login.php", Hits: 700, Visitors: 40, Bytes: 1048576}) {
+		t.Errorf("unexpected request item: %+v", got)
+	}
+	if got := report.Panels[1].Items[0]; got.Hits != 500 || got.Label != "203.0.113.9" {
+		t.Errorf("unexpected host item: %+v", got)
+	}
+	if _, err := parseGoAccessJSON([]byte("Parsing... [0]")); err == nil {
+		t.Error("expected an error for output that is not JSON")
+	}
+}
+
+func TestAccessLogPath(t *testing.T) {
+	config := "server {\n    access_log off;\n    access_log syslog:server=unix:/dev/log;\n    access_log /var/log/nginx/shop-access.log combined buffer=32k;\n}\n"
+	if got := accessLogPath(config); got != "/var/log/nginx/shop-access.log" {
+		t.Errorf("accessLogPath() = %q", got)
+	}
+	if got := accessLogPath("server {\n}\n"); got != "" {
+		t.Errorf("expected no log, got %q", got)
+	}
+}
+
+func TestAnalyticsReport(t *testing.T) {
+	site := NginxSite{Name: "shop.com", Domain: "shop.com", RootDir: "/var/www/shop/public", HasSSL: true}
+	if got := AnalyticsReportURL(site); got != "https://shop.com/ravact-analytics/report.html" {
+		t.Errorf("AnalyticsReportURL() = %q", got)
+	}
+	script := AnalyticsReportScript(site, "/var/log/nginx/shop-access.log")
+	if !strings.Contains(script, "goaccess /var/log/nginx/shop-access.log --log-format=COMBINED --no-progress -o /var/www/shop/public/ravact-analytics/report.html\n") {
+		t.Errorf("unexpected script:\n%s", script)
+	}
+
+	for _, tc := range []struct {
+		rules []SiteAccessRule
+		want  bool
+	}{
+		{nil, false},
+		{[]SiteAccessRule{{Path: "/admin", BasicAuth: true}}, false},
+		{[]SiteAccessRule{{Path: "/", AllowIPs: []string{"203.0.113.9"}}}, true},
+		{[]SiteAccessRule{{Path: "/ravact", BasicAuth: true}}, true},
+		{[]SiteAccessRule{{Path: AnalyticsPath, BasicAuth: true}}, true},
+	} {
+		if got := AnalyticsProtected(tc.rules); got != tc.want {
+			t.Errorf("AnalyticsProtected(%+v) = %v, want %v", tc.rules, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package system
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DashboardServiceUnits are the systemd units shown on the health dashboard.
+// Patterns are expanded by systemctl; units that are not installed are skipped.
+var DashboardServiceUnits = []string{
+	"nginx.service",
+	"php*-fpm.service",
+	"mysql.service",
+	"mariadb.service",
+	"postgresql.service",
+	"redis-server.service",
+	"redis.service",
+	"supervisor.service",
+	"supervisord.service",
+	"frankenphp*.service",
+}
+
+// ServiceHealth is the state of one systemd unit
+type ServiceHealth struct {
+	Unit   string
+	Active string // active, inactive, failed, activating, ...
+	Sub    string // running, dead, exited, ...
+}
+
+// Healthy reports whether the unit is up
+func (s ServiceHealth) Healthy() bool {
+	return s.Active == "active"
+}
+
+// HealthSnapshot is a point-in-time view of server health
+type HealthSnapshot struct {
+	CPUPercent float64 // Usage since the previous sample; 0 on the first one
+	CPUCount   int
+	MemTotal   uint64
+	MemUsed    uint64
+	SwapTotal  uint64
+	SwapUsed   uint64
+	DiskTotal  uint64
+	DiskUsed   uint64
+	Load1      float64
+	Load5      float64
+	Load15     float64
+	Uptime     time.Duration
+	Services   []ServiceHealth
+	Collected  time.Time
+}
+
+// cpuSample holds the aggregate counters from /proc/stat
+type cpuSample struct {
+	idle  uint64
+	total uint64
+}
+
+// HealthMonitor collects health snapshots. It remembers the previous CPU
+// sample so usage reflects the interval between calls to Collect.
+type HealthMonitor struct {
+	mu      sync.Mutex
+	prevCPU *cpuSample
+}
+
+// NewHealthMonitor creates a new health monitor
+func NewHealthMonitor() *HealthMonitor {
+	return &HealthMonitor{}
+}
+
+// Collect gathers a health snapshot from the current host
+func (h *HealthMonitor) Collect() (*HealthSnapshot, error) {
+	if HostOS() != "linux" {
+		return nil, fmt.Errorf("live health metrics are only available on Linux (current OS: %s)", HostOS())
+	}
+
+	snap := &HealthSnapshot{Collected: time.Now()}
+
+	h.mu.Lock()
+	first := h.prevCPU == nil
+	h.mu.Unlock()
+	if first {
+		// Take a short baseline so the first snapshot has a CPU reading
+		if data, err := ReadFile("/proc/stat"); err == nil {
+			if sample, _, err := parseCPUStat(string(data)); err == nil {
+				h.mu.Lock()
+				h.prevCPU = &sample
+				h.mu.Unlock()
+				time.Sleep(500 * time.Millisecond)
+			}
+		}
+	}
+
+	if data, err := ReadFile("/proc/stat"); err == nil {
+		if sample, cpus, err := parseCPUStat(string(data)); err == nil {
+			snap.CPUCount = cpus
+			h.mu.Lock()
+			if h.prevCPU != nil {
+				snap.CPUPercent = cpuUsage(*h.prevCPU, sample)
+			}
+			h.prevCPU = &sample
+			h.mu.Unlock()
+		}
+	}
+
+	data, err := ReadFile("/proc/meminfo")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memory info: %w", err)
+	}
+	snap.MemTotal, snap.MemUsed, snap.SwapTotal, snap.SwapUsed = parseMemInfo(string(data))
+
+	if data, err := ReadFile("/proc/loadavg"); err == nil {
+		snap.Load1, snap.Load5, snap.Load15, _ = parseLoadAvg(string(data))
+	}
+
+	if data, err := ReadFile("/proc/uptime"); err == nil {
+		if fields := strings.Fields(string(data)); len(fields) > 0 {
+			if secs, err := strconv.ParseFloat(fields[0], 64); err == nil {
+				snap.Uptime = time.Duration(secs) * time.Second
+			}
+		}
+	}
+
+	if output, err := Command("df", "-B1", "--output=size,used", "/").Output(); err == nil {
+		snap.DiskTotal, snap.DiskUsed, _ = parseDfUsage(string(output))
+	}
+
+	snap.Services, _ = ServiceStates()
+
+	return snap, nil
+}
+
+// ServiceStates returns the state of the installed DashboardServiceUnits
+func ServiceStates() ([]ServiceHealth, error) {
+	args := append([]string{"list-units", "--type=service", "--all", "--no-legend", "--plain", "--no-pager"}, DashboardServiceUnits...)
+	output, err := Command("systemctl", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	return parseServiceUnits(string(output)), nil
+}
+
+// parseCPUStat reads the aggregate cpu line and counts cores in /proc/stat
+func parseCPUStat(data string) (cpuSample, int, error) {
+	var sample cpuSample
+	found := false
+	cpus := 0
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+		if fields[0] != "cpu" {
+			cpus++
+			continue
+		}
+		for i, field := range fields[1:] {
+			v, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return sample, 0, fmt.Errorf("unexpected /proc/stat value %q", field)
+			}
+			// guest and guest_nice are already included in user and nice
+			if i >= 8 {
+				break
+			}
+			sample.total += v
+			// idle and iowait
+			if i == 3 || i == 4 {
+				sample.idle += v
+			}
+		}
+		found = true
+	}
+	if !found {
+		return sample, 0, fmt.Errorf("cpu line not found in /proc/stat")
+	}
+	return sample, cpus, nil
+}
+
+// cpuUsage returns the busy percentage between two samples
+func cpuUsage(prev, cur cpuSample) float64 {
+	if cur.total <= prev.total {
+		return 0
+	}
+	total := float64(cur.total - prev.total)
+	idle := float64(cur.idle - prev.idle)
+	return (total - idle) / total * 100
+}
+
+// parseMemInfo returns total and used memory and swap in bytes
+func parseMemInfo(data string) (memTotal, memUsed, swapTotal, swapUsed uint64) {
+	values := map[string]uint64{}
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if kb, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[strings.TrimSuffix(fields[0], ":")] = kb * 1024
+		}
+	}
+
+	memTotal = values["MemTotal"]
+	available, ok := values["MemAvailable"]
+	if !ok {
+		available = values["MemFree"] + values["Buffers"] + values["Cached"]
+	}
+	if available < memTotal {
+		memUsed = memTotal - available
+	}
+
+	swapTotal = values["SwapTotal"]
+	if values["SwapFree"] < swapTotal {
+		swapUsed = swapTotal - values["SwapFree"]
+	}
+	return memTotal, memUsed, swapTotal, swapUsed
+}
+
+// parseLoadAvg parses the first three fields of /proc/loadavg
+func parseLoadAvg(data string) (load1, load5, load15 float64, err error) {
+	fields := strings.Fields(data)
+	if len(fields) < 3 {
+		return 0, 0, 0, fmt.Errorf("unexpected /proc/loadavg format")
+	}
+	loads := make([]float64, 3)
+	for i := range loads {
+		if loads[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	return loads[0], loads[1], loads[2], nil
+}
+
+// parseDfUsage parses `df -B1 --output=size,used` output
+func parseDfUsage(output string) (total, used uint64, err error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return 0, 0, fmt.Errorf("unexpected df output")
+	}
+	fields := strings.Fields(lines[1])
+	if len(fields) < 2 {
+		return 0, 0, fmt.Errorf("unexpected df output format")
+	}
+	if total, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+		return 0, 0, err
+	}
+	if used, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+		return 0, 0, err
+	}
+	return total, used, nil
+}
+
+// parseServiceUnits parses `systemctl list-units --plain --no-legend` output,
+// skipping units that are not installed
+func parseServiceUnits(output string) []ServiceHealth {
+	var services []ServiceHealth
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[1] == "not-found" {
+			continue
+		}
+		services = append(services, ServiceHealth{
+			Unit:   strings.TrimSuffix(fields[0], ".service"),
+			Active: fields[2],
+			Sub:    fields[3],
+		})
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Unit < services[j].Unit })
+	return services
+}
@@ -0,0 +1,81 @@
+package system
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseCPUStat(t *testing.T) {
+	data := `cpu  100 0 100 700 100 0 0 0 0 0
+cpu0 50 0 50 350 50 0 0 0 0 0
+cpu1 50 0 50 350 50 0 0 0 0 0
+intr 12345
+`
+	sample, cpus, err := parseCPUStat(data)
+	if err != nil {
+		t.Fatalf("parseCPUStat: %v", err)
+	}
+	if cpus != 2 {
+		t.Errorf("expected 2 cpus, got %d", cpus)
+	}
+	if sample.total != 1000 || sample.idle != 800 {
+		t.Errorf("unexpected sample %+v", sample)
+	}
+
+	next := cpuSample{total: 2000, idle: 1300}
+	if got := cpuUsage(sample, next); math.Abs(got-50) > 0.01 {
+		t.Errorf("expected 50%% usage, got %.2f", got)
+	}
+	if got := cpuUsage(next, sample); got != 0 {
+		t.Errorf("expected 0 for a counter reset, got %.2f", got)
+	}
+}
+
+func TestParseMemInfo(t *testing.T) {
+	data := `MemTotal:        2000 kB
+MemFree:          200 kB
+MemAvailable:     500 kB
+SwapTotal:       1000 kB
+SwapFree:         750 kB
+`
+	total, used, swapTotal, swapUsed := parseMemInfo(data)
+	if total != 2000*1024 || used != 1500*1024 {
+		t.Errorf("unexpected memory %d/%d", used, total)
+	}
+	if swapTotal != 1000*1024 || swapUsed != 250*1024 {
+		t.Errorf("unexpected swap %d/%d", swapUsed, swapTotal)
+	}
+}
+
+func TestParseLoadAvgAndDf(t *testing.T) {
+	l1, l5, l15, err := parseLoadAvg("0.52 0.58 0.59 1/467 12345\n")
+	if err != nil || l1 != 0.52 || l5 != 0.58 || l15 != 0.59 {
+		t.Errorf("unexpected load %v %v %v (%v)", l1, l5, l15, err)
+	}
+
+	total, used, err := parseDfUsage("     1B-blocks        Used\n 105089261568 42035704627\n")
+	if err != nil || total != 105089261568 || used != 42035704627 {
+		t.Errorf("unexpected df usage %d/%d (%v)", used, total, err)
+	}
+}
+
+func TestParseServiceUnits(t *testing.T) {
+	output := `nginx.service              loaded    active   running A high performance web server
+php8.3-fpm.service         loaded    failed   failed  The PHP 8.3 FastCGI Process Manager
+mysql.service              not-found inactive dead    mysql.service
+frankenphp-shop.service    loaded    inactive dead    FrankenPHP shop
+`
+	services := parseServiceUnits(output)
+	if len(services) != 3 {
+		t.Fatalf("expected 3 services, got %d: %+v", len(services), services)
+	}
+	if services[0].Unit != "frankenphp-shop" || services[0].Healthy() {
+		t.Errorf("unexpected first service %+v", services[0])
+	}
+	if services[1].Unit != "nginx" || !services[1].Healthy() {
+		t.Errorf("unexpected nginx entry %+v", services[1])
+	}
+	if services[2].Active != "failed" {
+		t.Errorf("expected php-fpm to be failed, got %+v", services[2])
+	}
+}
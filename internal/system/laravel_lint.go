@@ -0,0 +1,205 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// LaravelCheck is one production-readiness check for a Laravel app
+type LaravelCheck struct {
+	ID       string
+	Title    string
+	Severity string // "error" or "warning"
+	Passed   bool
+	Detail   string
+
+	// Fix, when set, is a .env change and/or artisan commands that make
+	// the check pass. Checks without one need a manual change.
+	EnvKey   string
+	EnvValue string
+	Artisan  []string
+}
+
+// Fixable reports whether the check failed and ravact can fix it
+func (c LaravelCheck) Fixable() bool {
+	return !c.Passed && (c.EnvKey != "" || len(c.Artisan) > 0)
+}
+
+// LaravelCaches reports which framework caches are built
+type LaravelCaches struct {
+	Config bool
+	Routes bool
+	Views  bool
+
+	// ViewsKnown is false when the view cache could not be checked;
+	// compiled views on disk do not mean view:cache was run
+	ViewsKnown bool
+}
+
+// DetectLaravelCaches asks `artisan about` which caches are built and
+// falls back to looking for the cache files when artisan cannot run
+func DetectLaravelCaches(projectDir string) LaravelCaches {
+	output, err := Command("php", filepath.Join(projectDir, "artisan"), "about", "--only=cache", "--json").Output()
+	if err == nil {
+		if caches, ok := parseArtisanAboutCaches(output); ok {
+			return caches
+		}
+	}
+
+	exists := func(name string) bool {
+		_, err := Stat(filepath.Join(projectDir, "bootstrap", "cache", name))
+		return err == nil
+	}
+	return LaravelCaches{
+		Config: exists("config.php"),
+		Routes: exists("routes-v7.php") || exists("routes.php"),
+	}
+}
+
+// parseArtisanAboutCaches reads the cache section of `artisan about --json`
+func parseArtisanAboutCaches(output []byte) (LaravelCaches, bool) {
+	var about map[string]map[string]interface{}
+	if err := json.Unmarshal(output, &about); err != nil {
+		return LaravelCaches{}, false
+	}
+	section, ok := about["cache"]
+	if !ok {
+		return LaravelCaches{}, false
+	}
+	cached := func(key string) bool {
+		value, _ := section[key].(bool)
+		return value
+	}
+	_, viewsKnown := section["views"].(bool)
+	return LaravelCaches{
+		Config:     cached("config"),
+		Routes:     cached("routes"),
+		Views:      cached("views"),
+		ViewsKnown: viewsKnown,
+	}, true
+}
+
+// LintLaravel checks a Laravel app's .env and caches for production
+// anti-patterns. When the config is cached, .env fixes rebuild it so
+// they take effect.
+func LintLaravel(env *EnvFile, caches LaravelCaches) []LaravelCheck {
+	var rebuild []string
+	if caches.Config {
+		rebuild = []string{"config:cache"}
+	}
+
+	appEnv, _ := env.Get("APP_ENV")
+	appDebug, _ := env.Get("APP_DEBUG")
+	appKey, _ := env.Get("APP_KEY")
+	queue, ok := env.Get("QUEUE_CONNECTION")
+	if !ok {
+		queue, _ = env.Get("QUEUE_DRIVER")
+	}
+
+	checks := []LaravelCheck{
+		{
+			ID:       "app_env",
+			Title:    "APP_ENV is production",
+			Severity: "warning",
+			Passed:   appEnv == "production",
+			Detail:   fmt.Sprintf("APP_ENV=%s", appEnv),
+			EnvKey:   "APP_ENV",
+			EnvValue: "production",
+			Artisan:  rebuild,
+		},
+		{
+			ID:       "app_debug",
+			Title:    "APP_DEBUG is off",
+			Severity: "error",
+			Passed:   !isTruthy(appDebug),
+			Detail:   "Debug mode shows stack traces and environment values to visitors",
+			EnvKey:   "APP_DEBUG",
+			EnvValue: "false",
+			Artisan:  rebuild,
+		},
+		{
+			ID:       "app_key",
+			Title:    "APP_KEY is set",
+			Severity: "error",
+			Passed:   appKey != "",
+			Detail:   "Sessions and encrypted values need an application key",
+			Artisan:  append([]string{"key:generate --force"}, rebuild...),
+		},
+		{
+			ID:       "queue",
+			Title:    "Queue is not sync",
+			Severity: "warning",
+			Passed:   queue != "sync",
+			Detail:   "The sync driver runs jobs inside the web request; use redis or database with queue workers",
+		},
+		{
+			ID:       "config_cache",
+			Title:    "Config is cached",
+			Severity: "warning",
+			Passed:   caches.Config,
+			Detail:   "php artisan config:cache",
+			Artisan:  []string{"config:cache"},
+		},
+		{
+			ID:       "route_cache",
+			Title:    "Routes are cached",
+			Severity: "warning",
+			Passed:   caches.Routes,
+			Detail:   "php artisan route:cache",
+			Artisan:  []string{"route:cache"},
+		},
+	}
+	if caches.ViewsKnown {
+		checks = append(checks, LaravelCheck{
+			ID:       "view_cache",
+			Title:    "Views are cached",
+			Severity: "warning",
+			Passed:   caches.Views,
+			Detail:   "php artisan view:cache",
+			Artisan:  []string{"view:cache"},
+		})
+	}
+	return checks
+}
+
+// isTruthy reports whether a .env value enables a boolean setting
+func isTruthy(value string) bool {
+	switch strings.ToLower(strings.Trim(value, "()")) {
+	case "true", "1", "on", "yes":
+		return true
+	}
+	return false
+}
+
+// LaravelFixScript returns the artisan commands that fix the given checks,
+// in order and without repeats, as one line of shell
+func LaravelFixScript(checks []LaravelCheck) string {
+	seen := map[string]bool{}
+	var commands []string
+	add := func(command string) {
+		if !seen[command] {
+			seen[command] = true
+			commands = append(commands, "php artisan "+command)
+		}
+	}
+	var rebuildConfig bool
+	for _, c := range checks {
+		if !c.Fixable() {
+			continue
+		}
+		for _, command := range c.Artisan {
+			// Rebuild the config cache once, after every other fix
+			if command == "config:cache" {
+				rebuildConfig = true
+				continue
+			}
+			add(command)
+		}
+	}
+	if rebuildConfig {
+		add("config:cache")
+	}
+	return strings.Join(commands, " && ")
+}
@@ -0,0 +1,57 @@
+package system
+
+import "testing"
+
+func TestLintLaravel(t *testing.T) {
+	env := ParseEnv("APP_ENV=local\nAPP_DEBUG=true\nAPP_KEY=\nQUEUE_CONNECTION=sync\n")
+	checks := LintLaravel(env, LaravelCaches{})
+
+	failed := map[string]LaravelCheck{}
+	for _, c := range checks {
+		if !c.Passed {
+			failed[c.ID] = c
+		}
+	}
+	for _, id := range []string{"app_env", "app_debug", "app_key", "queue", "config_cache", "route_cache"} {
+		if _, ok := failed[id]; !ok {
+			t.Errorf("expected %s to fail", id)
+		}
+	}
+	if _, ok := failed["view_cache"]; ok {
+		t.Error("the view cache should not be checked when it is unknown")
+	}
+	if failed["queue"].Fixable() {
+		t.Error("the queue driver should need a manual fix")
+	}
+	if c := failed["app_debug"]; c.EnvKey != "APP_DEBUG" || c.EnvValue != "false" || len(c.Artisan) != 0 {
+		t.Errorf("unexpected APP_DEBUG fix: %+v", c)
+	}
+}
+
+func TestLintLaravelProductionReady(t *testing.T) {
+	env := ParseEnv("APP_ENV=production\nAPP_DEBUG=false\nAPP_KEY=base64:abc\nQUEUE_CONNECTION=redis\n")
+	for _, c := range LintLaravel(env, LaravelCaches{Config: true, Routes: true, Views: true, ViewsKnown: true}) {
+		if !c.Passed {
+			t.Errorf("%s failed: %s", c.ID, c.Detail)
+		}
+	}
+}
+
+func TestLaravelFixScript(t *testing.T) {
+	env := ParseEnv("APP_ENV=production\nAPP_DEBUG=true\nQUEUE_CONNECTION=sync\n")
+	checks := LintLaravel(env, LaravelCaches{Config: true})
+	want := "php artisan key:generate --force && php artisan route:cache && php artisan config:cache"
+	if got := LaravelFixScript(checks); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseArtisanAboutCaches(t *testing.T) {
+	caches, ok := parseArtisanAboutCaches([]byte(`{"cache":{"config":true,"events":false,"routes":false,"views":true}}`))
+	if !ok || caches != (LaravelCaches{Config: true, Views: true, ViewsKnown: true}) {
+		t.Errorf("unexpected caches: %+v (ok=%v)", caches, ok)
+	}
+	if _, ok := parseArtisanAboutCaches([]byte("Laravel Framework 8.0")); ok {
+		t.Error("expected non-JSON output to be rejected")
+	}
+}
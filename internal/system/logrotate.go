@@ -0,0 +1,461 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LogrotateDir holds the logrotate configs, ravact's included
+var LogrotateDir = "/etc/logrotate.d"
+
+// logrotateHeader marks the configs ravact writes
+const logrotateHeader = "# Generated by ravact"
+
+// nginxReopen makes nginx reopen its logs after they are moved
+const nginxReopen = `[ -s /run/nginx.pid ] && kill -USR1 "$(cat /run/nginx.pid)" || true`
+
+// logrotateOwnerPattern matches the "user group" of an su directive
+var logrotateOwnerPattern = regexp.MustCompile(`^[a-z_][a-z0-9_-]*\$? [a-z_][a-z0-9_-]*\$?$`)
+
+// LogrotatePeriods are the rotation periods offered in the form
+var LogrotatePeriods = []string{"daily", "weekly", "monthly"}
+
+// LogrotateSettings are how often a target's logs rotate and how many
+// rotations are kept
+type LogrotateSettings struct {
+	Period   string // daily, weekly, or monthly
+	Rotate   int    // Rotated logs kept
+	Compress bool   // gzip rotated logs, one rotation late
+}
+
+// Validate checks the settings before a config is generated
+func (s LogrotateSettings) Validate() error {
+	valid := false
+	for _, p := range LogrotatePeriods {
+		valid = valid || s.Period == p
+	}
+	if !valid {
+		return fmt.Errorf("period must be daily, weekly, or monthly")
+	}
+	if s.Rotate < 1 || s.Rotate > 366 {
+		return fmt.Errorf("keep between 1 and 366 rotated logs")
+	}
+	return nil
+}
+
+// Describe summarizes the settings for the target list
+func (s LogrotateSettings) Describe() string {
+	text := fmt.Sprintf("%s, keep %d", s.Period, s.Rotate)
+	if s.Compress {
+		text += ", compressed"
+	}
+	return text
+}
+
+// LogrotateTarget is a set of logs rotated by one config under LogrotateDir
+type LogrotateTarget struct {
+	Name  string // Config file name
+	Title string
+	Kind  string // "site", "laravel", or "supervisor"
+	Paths []string
+	Owner string // "user group" logrotate switches to, for logs in directories the web user can write
+
+	Installed bool // A ravact config exists for the target
+	Settings  LogrotateSettings
+	CoveredBy map[string]string // Path -> another config already rotating it
+	Note      string
+}
+
+// ConfigPath is where the target's config is installed
+func (t LogrotateTarget) ConfigPath() string {
+	return filepath.Join(LogrotateDir, t.Name)
+}
+
+// Uncovered returns the paths no other config rotates. logrotate stops
+// with an error when two configs name the same log.
+func (t LogrotateTarget) Uncovered() []string {
+	var paths []string
+	for _, path := range t.Paths {
+		if _, ok := t.CoveredBy[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// defaultLogrotateSettings are the settings offered for a new target
+func defaultLogrotateSettings(kind string) LogrotateSettings {
+	switch kind {
+	case "laravel":
+		return LogrotateSettings{Period: "daily", Rotate: 7, Compress: true}
+	case "supervisor":
+		return LogrotateSettings{Period: "weekly", Rotate: 4, Compress: true}
+	}
+	return LogrotateSettings{Period: "daily", Rotate: 14, Compress: true}
+}
+
+// logrotateName turns a site or program name into a config file name.
+// logrotate skips files with some extensions, so only safe characters are kept.
+func logrotateName(kind, name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	return "ravact-" + kind + "-" + b.String()
+}
+
+// logrotatePath quotes a path for a logrotate config when it has spaces
+func logrotatePath(path string) string {
+	if strings.ContainsAny(path, " \t") {
+		return `"` + path + `"`
+	}
+	return path
+}
+
+// LogrotateConfig generates the config for a target's uncovered paths.
+// nginx reopens its logs after rotation; Laravel and supervisor logs are
+// copied and truncated since the writers keep them open.
+func LogrotateConfig(t LogrotateTarget, s LogrotateSettings) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s\n", logrotateHeader, t.Title)
+	for _, path := range t.Uncovered() {
+		b.WriteString(logrotatePath(path) + "\n")
+	}
+	b.WriteString("{\n")
+	fmt.Fprintf(&b, "\t%s\n", s.Period)
+	fmt.Fprintf(&b, "\trotate %d\n", s.Rotate)
+	b.WriteString("\tmissingok\n")
+	b.WriteString("\tnotifempty\n")
+	if s.Compress {
+		b.WriteString("\tcompress\n")
+		b.WriteString("\tdelaycompress\n")
+	}
+	if t.Owner != "" {
+		fmt.Fprintf(&b, "\tsu %s\n", t.Owner)
+	}
+	if t.Kind == "site" {
+		b.WriteString("\tsharedscripts\n")
+		b.WriteString("\tpostrotate\n")
+		fmt.Fprintf(&b, "\t\t%s\n", nginxReopen)
+		b.WriteString("\tendscript\n")
+	} else {
+		b.WriteString("\tcopytruncate\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// parseLogrotateSettings reads the settings back from a ravact config
+func parseLogrotateSettings(content string, s LogrotateSettings) LogrotateSettings {
+	s.Compress = false
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "daily", "weekly", "monthly":
+			s.Period = fields[0]
+		case "rotate":
+			if len(fields) > 1 {
+				if n, err := strconv.Atoi(fields[1]); err == nil {
+					s.Rotate = n
+				}
+			}
+		case "compress":
+			s.Compress = true
+		}
+	}
+	return s
+}
+
+// logrotateScripts open the script sections of a config, which end at
+// endscript and may contain braces
+var logrotateScripts = map[string]bool{
+	"prerotate": true, "postrotate": true, "firstaction": true,
+	"lastaction": true, "preremove": true,
+}
+
+// logrotatePatterns returns the log paths and globs a config names
+func logrotatePatterns(content string) []string {
+	var patterns []string
+	depth := 0
+	inScript := false
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if inScript {
+			inScript = fields[0] != "endscript"
+			continue
+		}
+		if depth > 0 {
+			if logrotateScripts[fields[0]] {
+				inScript = true
+			}
+			depth -= strings.Count(line, "}")
+			continue
+		}
+		tokens := logrotateTokens(line)
+		if tokens[0] != "{" && !strings.HasPrefix(tokens[0], "/") {
+			// A global directive such as include /etc/logrotate.d
+			continue
+		}
+		for _, token := range tokens {
+			switch {
+			case token == "{":
+				depth++
+			case token == "}":
+				depth--
+			case strings.HasPrefix(token, "/"):
+				patterns = append(patterns, token)
+			}
+		}
+	}
+	return patterns
+}
+
+// logrotateTokens splits a line into paths, honouring double quotes, and
+// braces
+func logrotateTokens(line string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+			current.WriteRune(r)
+		case r == ' ' || r == '\t':
+			flush()
+		case r == '{' || r == '}':
+			flush()
+			tokens = append(tokens, string(r))
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// logrotateCoverage returns the patterns of every config but the target's,
+// keyed by pattern with the config's name as the value
+func logrotateCoverage(configs map[string]string, skip string) map[string]string {
+	coverage := map[string]string{}
+	for name, content := range configs {
+		if name == skip {
+			continue
+		}
+		for _, pattern := range logrotatePatterns(content) {
+			coverage[pattern] = name
+		}
+	}
+	return coverage
+}
+
+// coveredBy returns the config whose patterns include path. Globs in path
+// (Laravel's *.log) are matched literally, which a broader glob also matches.
+func coveredBy(path string, coverage map[string]string) string {
+	for pattern, name := range coverage {
+		if pattern == path {
+			return name
+		}
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// siteLogPaths returns the access and error logs a site config writes to,
+// skipping syslog, stderr, and paths built from variables
+func siteLogPaths(config string) []string {
+	var paths []string
+	seen := map[string]bool{}
+	for _, line := range strings.Split(config, "\n") {
+		fields := strings.Fields(strings.TrimSuffix(strings.TrimSpace(line), ";"))
+		if len(fields) < 2 || (fields[0] != "access_log" && fields[0] != "error_log") {
+			continue
+		}
+		path := fields[1]
+		if !strings.HasPrefix(path, "/") || strings.Contains(path, "$") || seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// logrotateConfigs reads every config in LogrotateDir and logrotate.conf
+func logrotateConfigs() map[string]string {
+	configs := map[string]string{}
+	if data, err := ReadFile("/etc/logrotate.conf"); err == nil {
+		configs["logrotate.conf"] = string(data)
+	}
+	entries, err := ReadDir(LogrotateDir)
+	if err != nil {
+		return configs
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if data, err := ReadFile(filepath.Join(LogrotateDir, e.Name())); err == nil {
+			configs[e.Name()] = string(data)
+		}
+	}
+	return configs
+}
+
+// DiscoverLogrotateTargets lists the site, Laravel, and supervisor program
+// logs on the active host with the rotation each has
+func DiscoverLogrotateTargets() []LogrotateTarget {
+	var targets []LogrotateTarget
+
+	sites, _ := NewNginxManager().GetAllSites()
+	seenProjects := map[string]bool{}
+	for _, site := range sites {
+		if data, err := ReadFile(site.ConfigPath); err == nil {
+			if paths := siteLogPaths(string(data)); len(paths) > 0 {
+				targets = append(targets, LogrotateTarget{
+					Name:  logrotateName("site", site.Name),
+					Title: "site " + site.Name,
+					Kind:  "site",
+					Paths: paths,
+				})
+			}
+		}
+
+		// Laravel projects served from <project>/public
+		if site.RootDir == "" || filepath.Base(site.RootDir) != "public" {
+			continue
+		}
+		project := filepath.Dir(site.RootDir)
+		logs := filepath.Join(project, "storage", "logs")
+		if seenProjects[project] {
+			continue
+		}
+		seenProjects[project] = true
+		if _, err := Stat(logs); err != nil {
+			continue
+		}
+		t := LogrotateTarget{
+			Name:  logrotateName("laravel", site.Name),
+			Title: "Laravel " + project,
+			Kind:  "laravel",
+			Paths: []string{filepath.Join(logs, "*.log")},
+		}
+		// logrotate refuses directories writable by other users unless it
+		// switches to the owner
+		if output, err := Command("stat", "-c", "%U %G", logs).Output(); err == nil {
+			if owner := strings.TrimSpace(string(output)); owner != "root root" && logrotateOwnerPattern.MatchString(owner) {
+				t.Owner = owner
+			}
+		}
+		t.Note = "Laravel's daily channel rotates by itself; this covers the single channel"
+		targets = append(targets, t)
+	}
+
+	sm := NewSupervisorManager()
+	if programs, err := sm.GetAllPrograms(); err == nil {
+		for _, p := range programs {
+			c, err := sm.ProgramLogConfig(p.Name)
+			if err != nil {
+				continue
+			}
+			var paths []string
+			for _, file := range []string{c.StdoutLogfile, c.StderrLogfile} {
+				file = strings.ReplaceAll(file, "%(program_name)s", p.Name)
+				if strings.HasPrefix(file, "/") && !strings.Contains(file, "%(") && !strings.HasPrefix(file, "/dev/") && !slices.Contains(paths, file) {
+					paths = append(paths, file)
+				}
+			}
+			if len(paths) == 0 {
+				continue
+			}
+			t := LogrotateTarget{
+				Name:  logrotateName("supervisor", p.Name),
+				Title: "supervisor " + p.Name,
+				Kind:  "supervisor",
+				Paths: paths,
+			}
+			if c.MaxBytes != "0" {
+				t.Note = fmt.Sprintf("supervisor also rotates these at %s; set its log size to 0 to leave rotation to logrotate", c.MaxBytes)
+			}
+			targets = append(targets, t)
+		}
+	}
+
+	configs := logrotateConfigs()
+	for i := range targets {
+		t := &targets[i]
+		t.Settings = defaultLogrotateSettings(t.Kind)
+		if content, ok := configs[t.Name]; ok && strings.HasPrefix(content, logrotateHeader) {
+			t.Installed = true
+			t.Settings = parseLogrotateSettings(content, t.Settings)
+		}
+		coverage := logrotateCoverage(configs, t.Name)
+		for _, path := range t.Paths {
+			if name := coveredBy(path, coverage); name != "" {
+				if t.CoveredBy == nil {
+					t.CoveredBy = map[string]string{}
+				}
+				t.CoveredBy[path] = name
+			}
+		}
+	}
+	sort.SliceStable(targets, func(i, j int) bool { return targets[i].Kind < targets[j].Kind })
+	return targets
+}
+
+// LogrotateSimulateScript dry-runs a generated config from a temporary
+// file. logrotate -d changes nothing, not even its state file.
+func LogrotateSimulateScript(content string) string {
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	b.WriteString("tmp=$(mktemp)\n")
+	b.WriteString("trap 'rm -f \"$tmp\"' EXIT\n")
+	fmt.Fprintf(&b, "cat > \"$tmp\" <<'EOF'\n%sEOF\n", content)
+	b.WriteString("logrotate -d \"$tmp\" 2>&1\n")
+	return b.String()
+}
+
+// LogrotateInstallScript writes a target's config and dry-runs it
+func LogrotateInstallScript(t LogrotateTarget, content string) string {
+	path := ShellQuote(t.ConfigPath())
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	fmt.Fprintf(&b, "tee %s > /dev/null <<'EOF'\n%sEOF\n", path, content)
+	fmt.Fprintf(&b, "chmod 644 %s\n", path)
+	fmt.Fprintf(&b, "echo %s\n", ShellQuote("✓ Wrote "+t.ConfigPath()))
+	b.WriteString("echo; echo 'Dry run:'\n")
+	fmt.Fprintf(&b, "logrotate -d %s 2>&1\n", path)
+	return b.String()
+}
+
+// LogrotateRemoveScript removes a target's config
+func LogrotateRemoveScript(t LogrotateTarget) string {
+	return fmt.Sprintf("rm -f %s\necho %s\n", ShellQuote(t.ConfigPath()), ShellQuote("✓ Removed "+t.ConfigPath()))
+}
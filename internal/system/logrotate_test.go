@@ -0,0 +1,101 @@
+package system
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLogrotateConfig(t *testing.T) {
+	site := LogrotateTarget{
+		Name:      "ravact-site-shop.com",
+		Title:     "site shop.com",
+		Kind:      "site",
+		Paths:     []string{"/var/www/shop/logs/access.log", "/var/log/nginx/shop-error.log"},
+		CoveredBy: map[string]string{"/var/log/nginx/shop-error.log": "nginx"},
+	}
+	s := LogrotateSettings{Period: "weekly", Rotate: 8, Compress: true}
+	config := LogrotateConfig(site, s)
+	for _, want := range []string{"/var/www/shop/logs/access.log\n{\n", "\tweekly\n", "\trotate 8\n", "\tdelaycompress\n", "\tsharedscripts\n\tpostrotate\n"} {
+		if !strings.Contains(config, want) {
+			t.Errorf("expected %q in:\n%s", want, config)
+		}
+	}
+	if strings.Contains(config, "shop-error.log") {
+		t.Errorf("a log another config rotates must be left out:\n%s", config)
+	}
+	if got := parseLogrotateSettings(config, defaultLogrotateSettings("site")); got != s {
+		t.Errorf("parseLogrotateSettings() = %+v, want %+v", got, s)
+	}
+
+	laravel := LogrotateTarget{Kind: "laravel", Paths: []string{"/var/www/my app/storage/logs/*.log"}, Owner: "www-data www-data"}
+	config = LogrotateConfig(laravel, LogrotateSettings{Period: "daily", Rotate: 7})
+	for _, want := range []string{"\"/var/www/my app/storage/logs/*.log\"\n", "\tsu www-data www-data\n", "\tcopytruncate\n"} {
+		if !strings.Contains(config, want) {
+			t.Errorf("expected %q in:\n%s", want, config)
+		}
+	}
+	if strings.Contains(config, "compress") || strings.Contains(config, "postrotate") {
+		t.Errorf("unexpected directives:\n%s", config)
+	}
+}
+
+func TestLogrotateSettingsValidate(t *testing.T) {
+	if err := (LogrotateSettings{Period: "daily", Rotate: 14}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (LogrotateSettings{Period: "hourly", Rotate: 14}).Validate(); err == nil {
+		t.Error("expected an error for an unsupported period")
+	}
+	if err := (LogrotateSettings{Period: "daily", Rotate: 0}).Validate(); err == nil {
+		t.Error("expected an error for keeping no logs")
+	}
+}
+
+func TestLogrotatePatterns(t *testing.T) {
+	config := `# global options
+weekly
+include /etc/logrotate.d
+
+/var/log/nginx/*.log
+"/srv/my app/app.log" {
+	daily
+	postrotate
+		if [ -d /run/systemd/system ]; then { invoke-rc.d nginx rotate; } fi
+	endscript
+}
+/var/log/wtmp { monthly }
+`
+	want := []string{"/var/log/nginx/*.log", "/srv/my app/app.log", "/var/log/wtmp"}
+	if got := logrotatePatterns(config); !reflect.DeepEqual(got, want) {
+		t.Errorf("logrotatePatterns() = %q, want %q", got, want)
+	}
+
+	coverage := logrotateCoverage(map[string]string{"nginx": config, "ravact-site-shop": "/srv/shop.log {\n}\n"}, "ravact-site-shop")
+	if got := coveredBy("/var/log/nginx/shop-access.log", coverage); got != "nginx" {
+		t.Errorf("coveredBy() = %q, want nginx", got)
+	}
+	if got := coveredBy("/srv/shop.log", coverage); got != "" {
+		t.Errorf("the target's own config must not count, got %q", got)
+	}
+}
+
+func TestSiteLogPaths(t *testing.T) {
+	config := `server {
+    access_log /var/www/shop/logs/access.log combined;
+    access_log off;
+    error_log /var/www/shop/logs/error.log warn;
+    error_log stderr;
+    access_log /var/log/nginx/$host.log;
+    location / {
+        access_log /var/www/shop/logs/access.log;
+    }
+}`
+	want := []string{"/var/www/shop/logs/access.log", "/var/www/shop/logs/error.log"}
+	if got := siteLogPaths(config); !reflect.DeepEqual(got, want) {
+		t.Errorf("siteLogPaths() = %q, want %q", got, want)
+	}
+	if got := logrotateName("site", "shop.com/v2"); got != "ravact-site-shop.com-v2" {
+		t.Errorf("logrotateName() = %q", got)
+	}
+}
@@ -0,0 +1,238 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LogBacklog is the number of existing lines shown before following a log
+const LogBacklog = 200
+
+// LogSourceKind identifies how a log is read
+type LogSourceKind string
+
+const (
+	LogSourceFile    LogSourceKind = "file"
+	LogSourceJournal LogSourceKind = "journal"
+	LogSourceDocker  LogSourceKind = "docker"
+	LogSourceCompose LogSourceKind = "compose"
+)
+
+// LogSource is a log that can be tailed
+type LogSource struct {
+	Name  string
+	Kind  LogSourceKind
+	Path  string // For file sources
+	Unit  string // For journal sources, or the container or compose project
+	Group string // nginx, php-fpm, laravel, systemd, docker
+}
+
+// JournalSource returns a log source for a systemd unit
+func JournalSource(unit string) LogSource {
+	return LogSource{Name: unit, Kind: LogSourceJournal, Unit: unit, Group: "systemd"}
+}
+
+// FileSource returns a log source for a file
+func FileSource(group, path string) LogSource {
+	return LogSource{Name: path, Kind: LogSourceFile, Path: path, Group: group}
+}
+
+// DockerSource returns a log source for a container
+func DockerSource(container string) LogSource {
+	return LogSource{Name: container, Kind: LogSourceDocker, Unit: container, Group: "docker"}
+}
+
+// ComposeSource returns a log source for every service of a compose project
+func ComposeSource(project string) LogSource {
+	return LogSource{Name: project, Kind: LogSourceCompose, Unit: project, Group: "docker"}
+}
+
+// LaravelLogSource returns the log source for a Laravel project directory
+func LaravelLogSource(projectDir string) LogSource {
+	return FileSource("laravel", filepath.Join(projectDir, "storage", "logs", "laravel.log"))
+}
+
+// TailArgs returns the command that prints the last backlog lines of the
+// source and keeps following it
+func (s LogSource) TailArgs(backlog int) []string {
+	n := fmt.Sprintf("%d", backlog)
+	switch s.Kind {
+	case LogSourceJournal:
+		return []string{"journalctl", "-u", s.Unit, "-n", n, "-f", "--no-pager", "-o", "short-iso"}
+	case LogSourceDocker:
+		return []string{"docker", "logs", "-n", n, "-f", "-t", s.Unit}
+	case LogSourceCompose:
+		return []string{"docker", "compose", "-p", s.Unit, "logs", "-n", n, "-f", "-t"}
+	}
+	// -F keeps following across logrotate
+	return []string{"tail", "-n", n, "-F", s.Path}
+}
+
+// DiscoverLogSources lists the nginx, PHP-FPM and Laravel logs on the active
+// host, plus the journals of the services shown on the dashboard
+func DiscoverLogSources() []LogSource {
+	var sources []LogSource
+
+	for _, path := range logFiles("/var/log/nginx", func(name string) bool {
+		return strings.HasSuffix(name, ".log")
+	}) {
+		sources = append(sources, FileSource("nginx", path))
+	}
+	for _, path := range logFiles("/var/log", func(name string) bool {
+		return strings.HasPrefix(name, "php") && strings.Contains(name, "fpm") && strings.HasSuffix(name, ".log")
+	}) {
+		sources = append(sources, FileSource("php-fpm", path))
+	}
+
+	// Laravel projects served by nginx sites (root is <project>/public)
+	if sites, err := NewNginxManager().GetAllSites(); err == nil {
+		seen := map[string]bool{}
+		for _, site := range sites {
+			if site.RootDir == "" || filepath.Base(site.RootDir) != "public" {
+				continue
+			}
+			source := LaravelLogSource(filepath.Dir(site.RootDir))
+			if seen[source.Path] {
+				continue
+			}
+			seen[source.Path] = true
+			if _, err := Stat(source.Path); err == nil {
+				sources = append(sources, source)
+			}
+		}
+	}
+
+	for _, unit := range activeServiceUnits() {
+		sources = append(sources, JournalSource(unit))
+	}
+	return sources
+}
+
+// logFiles returns the files in dir accepted by match, sorted by name
+func logFiles(dir string, match func(string) bool) []string {
+	entries, err := ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() || !match(entry.Name()) {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// activeServiceUnits returns the installed dashboard services
+func activeServiceUnits() []string {
+	args := append([]string{"list-units", "--type=service", "--all", "--no-legend", "--plain", "--no-pager"}, DashboardServiceUnits...)
+	output, err := Command("systemctl", args...).Output()
+	if err != nil {
+		return nil
+	}
+	var units []string
+	for _, svc := range parseServiceUnits(string(output)) {
+		units = append(units, svc.Unit)
+	}
+	return units
+}
+
+// LogLevel is the severity detected in a log line
+type LogLevel int
+
+const (
+	LogLevelNone LogLevel = iota
+	LogLevelDebug
+	LogLevelInfo
+	LogLevelWarning
+	LogLevelError
+)
+
+var (
+	// Laravel "local.ERROR:", nginx "[error]", PHP-FPM "WARNING:" and journal text
+	logErrorPattern   = regexp.MustCompile(`(?i)\.(emergency|alert|critical|error):|\[(emerg|alert|crit|error)\]|\b(fatal|emergency|critical|error|alert):|\bpanic\b|\bfailed\b`)
+	logWarningPattern = regexp.MustCompile(`(?i)\.(warning|notice):|\[(warn|notice)\]|\b(warning|warn|notice):`)
+	logInfoPattern    = regexp.MustCompile(`(?i)\.info:|\[info\]|\binfo:`)
+	logDebugPattern   = regexp.MustCompile(`(?i)\.debug:|\[debug\]|\bdebug:`)
+	// nginx access log status code after the quoted request
+	accessStatusPattern = regexp.MustCompile(`" ([1-5])\d\d \d+`)
+)
+
+// DetectLogLevel guesses the severity of a log line
+func DetectLogLevel(line string) LogLevel {
+	if m := accessStatusPattern.FindStringSubmatch(line); m != nil {
+		switch m[1] {
+		case "5":
+			return LogLevelError
+		case "4":
+			return LogLevelWarning
+		}
+		return LogLevelInfo
+	}
+	switch {
+	case logErrorPattern.MatchString(line):
+		return LogLevelError
+	case logWarningPattern.MatchString(line):
+		return LogLevelWarning
+	case logInfoPattern.MatchString(line):
+		return LogLevelInfo
+	case logDebugPattern.MatchString(line):
+		return LogLevelDebug
+	}
+	return LogLevelNone
+}
+
+// LogTail follows a log source in the background
+type LogTail struct {
+	Lines  <-chan string // Closed when the tail process exits
+	cancel context.CancelFunc
+}
+
+// StartLogTail starts following a log source on the active host
+func StartLogTail(source LogSource, backlog int) (*LogTail, error) {
+	args := source.TailArgs(backlog)
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := CommandContext(ctx, args[0], args[1:]...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to open log output: %w", err)
+	}
+	// Errors such as a missing file are shown inline with the log
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start %s: %w", args[0], err)
+	}
+
+	lines := make(chan string, 1000)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+			}
+		}
+		_ = cmd.Wait()
+	}()
+
+	return &LogTail{Lines: lines, cancel: cancel}, nil
+}
+
+// Stop terminates the tail process
+func (t *LogTail) Stop() {
+	if t != nil && t.cancel != nil {
+		t.cancel()
+	}
+}
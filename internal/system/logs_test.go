@@ -0,0 +1,53 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectLogLevel(t *testing.T) {
+	tests := []struct {
+		line string
+		want LogLevel
+	}{
+		{"[2024-05-01 10:00:00] production.ERROR: Undefined variable $user", LogLevelError},
+		{"[2024-05-01 10:00:00] local.WARNING: Slow query", LogLevelWarning},
+		{"[2024-05-01 10:00:00] local.INFO: User logged in", LogLevelInfo},
+		{"[2024-05-01 10:00:00] local.DEBUG: Cache hit", LogLevelDebug},
+		{"2024/05/01 10:00:00 [error] 123#123: *1 open() failed", LogLevelError},
+		{"2024/05/01 10:00:00 [warn] 123#123: conflicting server name", LogLevelWarning},
+		{"[01-May-2024 10:00:00] WARNING: [pool www] server reached pm.max_children", LogLevelWarning},
+		{`1.2.3.4 - - [01/May/2024:10:00:00 +0000] "GET / HTTP/1.1" 200 612 "-" "curl"`, LogLevelInfo},
+		{`1.2.3.4 - - [01/May/2024:10:00:00 +0000] "GET /x HTTP/1.1" 404 153 "-" "curl"`, LogLevelWarning},
+		{`1.2.3.4 - - [01/May/2024:10:00:00 +0000] "POST /api HTTP/1.1" 502 157 "-" "curl"`, LogLevelError},
+		{"2024-05-01T10:00:00+0000 host systemd[1]: Started nginx.service.", LogLevelNone},
+	}
+	for _, tt := range tests {
+		if got := DetectLogLevel(tt.line); got != tt.want {
+			t.Errorf("DetectLogLevel(%q) = %d, want %d", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestTailArgs(t *testing.T) {
+	got := JournalSource("nginx.service").TailArgs(50)
+	want := []string{"journalctl", "-u", "nginx.service", "-n", "50", "-f", "--no-pager", "-o", "short-iso"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("journal args = %v, want %v", got, want)
+	}
+
+	source := LaravelLogSource("/var/www/shop")
+	if source.Path != "/var/www/shop/storage/logs/laravel.log" {
+		t.Errorf("unexpected laravel log path %q", source.Path)
+	}
+	if got := source.TailArgs(10); !reflect.DeepEqual(got, []string{"tail", "-n", "10", "-F", source.Path}) {
+		t.Errorf("file args = %v", got)
+	}
+
+	if got := DockerSource("meili").TailArgs(20); !reflect.DeepEqual(got, []string{"docker", "logs", "-n", "20", "-f", "-t", "meili"}) {
+		t.Errorf("docker args = %v", got)
+	}
+	if got := ComposeSource("shop").TailArgs(20); !reflect.DeepEqual(got, []string{"docker", "compose", "-p", "shop", "logs", "-n", "20", "-f", "-t"}) {
+		t.Errorf("compose args = %v", got)
+	}
+}
@@ -0,0 +1,368 @@
+package system
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Outbound mail config files. They hold the relay password, so ravact
+// writes them itself rather than passing the password through a script.
+var (
+	MsmtpConfigPath       = "/etc/msmtprc"
+	PostfixSASLPasswdPath = "/etc/postfix/sasl_passwd"
+)
+
+// Mail backends that hand local mail to a relay
+const (
+	MailBackendPostfix = "postfix" // Postfix as a satellite system
+	MailBackendMsmtp   = "msmtp"   // msmtp as the system sendmail
+)
+
+// MailRelay is the SMTP server outbound mail is handed to, such as SES,
+// Mailgun, or Postmark
+type MailRelay struct {
+	Backend  string
+	Host     string
+	Port     int
+	TLS      string // "starttls", "tls" (implicit, usually port 465), or "none"
+	Username string
+	Password string
+	From     string // Envelope sender the relay accepts
+}
+
+// Validate checks the relay before it is written
+func (r MailRelay) Validate() error {
+	if r.Backend != MailBackendPostfix && r.Backend != MailBackendMsmtp {
+		return fmt.Errorf("unsupported mail backend %q", r.Backend)
+	}
+	if r.Host == "" || strings.ContainsAny(r.Host, " []:\t") {
+		return fmt.Errorf("enter the relay host name")
+	}
+	if r.Port < 1 || r.Port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+	switch r.TLS {
+	case "starttls", "tls", "none":
+	default:
+		return fmt.Errorf("unsupported TLS mode %q", r.TLS)
+	}
+	if r.Username != "" && r.Password == "" {
+		return fmt.Errorf("a password is required with a username")
+	}
+	if strings.ContainsAny(r.Username+r.Password, "\n\r") {
+		return fmt.Errorf("credentials cannot contain line breaks")
+	}
+	if r.From != "" {
+		if _, err := mail.ParseAddress(r.From); err != nil {
+			return fmt.Errorf("invalid from address: %s", r.From)
+		}
+	}
+	return nil
+}
+
+// MsmtpConfig returns /etc/msmtprc for the relay. Delivery is logged to
+// syslog because the invoking user (often www-data) cannot write a log file.
+func (r MailRelay) MsmtpConfig() string {
+	onOff := func(b bool) string {
+		if b {
+			return "on"
+		}
+		return "off"
+	}
+	var b strings.Builder
+	b.WriteString("# Managed by ravact\n")
+	b.WriteString("defaults\n")
+	fmt.Fprintf(&b, "auth           %s\n", onOff(r.Username != ""))
+	fmt.Fprintf(&b, "tls            %s\n", onOff(r.TLS != "none"))
+	fmt.Fprintf(&b, "tls_starttls   %s\n", onOff(r.TLS == "starttls"))
+	b.WriteString("syslog         LOG_MAIL\n\n")
+	b.WriteString("account        default\n")
+	fmt.Fprintf(&b, "host           %s\n", r.Host)
+	fmt.Fprintf(&b, "port           %d\n", r.Port)
+	if r.From != "" {
+		fmt.Fprintf(&b, "from           %s\n", r.From)
+	}
+	if r.Username != "" {
+		fmt.Fprintf(&b, "user           %s\n", r.Username)
+		fmt.Fprintf(&b, "password       %s\n", msmtpQuote(r.Password))
+	}
+	return b.String()
+}
+
+// msmtpQuote quotes an msmtprc argument so spaces and quotes survive
+func msmtpQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// msmtpUnquote reverses msmtpQuote; unquoted values are returned as is
+func msmtpUnquote(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+	return strings.NewReplacer(`\\`, `\`, `\"`, `"`).Replace(s[1 : len(s)-1])
+}
+
+// parseMsmtpConfig reads the default account of an msmtprc
+func parseMsmtpConfig(content string) MailRelay {
+	r := MailRelay{Backend: MailBackendMsmtp, Port: 25, TLS: "none"}
+	tls, starttls := false, true
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		key := fields[0]
+		value := msmtpUnquote(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), key)))
+		switch key {
+		case "host":
+			r.Host = value
+		case "port":
+			r.Port, _ = strconv.Atoi(value)
+		case "from":
+			r.From = value
+		case "user":
+			r.Username = value
+		case "password":
+			r.Password = value
+		case "tls":
+			tls = value == "on"
+		case "tls_starttls":
+			starttls = value == "on"
+		}
+	}
+	switch {
+	case tls && starttls:
+		r.TLS = "starttls"
+	case tls:
+		r.TLS = "tls"
+	}
+	return r
+}
+
+// relayhost returns the Postfix relayhost; brackets skip the MX lookup
+func (r MailRelay) relayhost() string {
+	return fmt.Sprintf("[%s]:%d", r.Host, r.Port)
+}
+
+// PostfixSASLPasswd returns the sasl_passwd map entry for the relay
+func (r MailRelay) PostfixSASLPasswd() string {
+	if r.Username == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s %s:%s\n", r.relayhost(), r.Username, r.Password)
+}
+
+// PostfixSettings returns the main.cf settings, as postconf -e arguments,
+// that make Postfix a satellite relaying through r
+func (r MailRelay) PostfixSettings() []string {
+	level, wrapper := "encrypt", "no"
+	switch r.TLS {
+	case "tls":
+		wrapper = "yes"
+	case "none":
+		level = "may"
+	}
+	auth := "no"
+	if r.Username != "" {
+		auth = "yes"
+	}
+	settings := []string{
+		"relayhost = " + r.relayhost(),
+		"inet_interfaces = loopback-only",
+		"smtp_tls_security_level = " + level,
+		"smtp_tls_wrappermode = " + wrapper,
+		"smtp_sasl_auth_enable = " + auth,
+		"smtp_sasl_password_maps = hash:" + PostfixSASLPasswdPath,
+		"smtp_sasl_security_options = noanonymous",
+	}
+	// Relays only accept verified senders; rewrite the envelope of
+	// cron and system mail while leaving the app's From header alone
+	if r.From != "" {
+		settings = append(settings,
+			"sender_canonical_maps = static:"+r.From,
+			"sender_canonical_classes = envelope_sender")
+	} else {
+		settings = append(settings, "sender_canonical_maps =")
+	}
+	return settings
+}
+
+// parsePostfixRelay rebuilds a relay from postconf values and sasl_passwd
+func parsePostfixRelay(relayhost, level, wrapper, canonical, saslPasswd string) MailRelay {
+	r := MailRelay{Backend: MailBackendPostfix, Port: 25, TLS: "starttls"}
+	hostPort := strings.NewReplacer("[", "", "]", "").Replace(relayhost)
+	if host, port, err := net.SplitHostPort(hostPort); err == nil {
+		r.Host = host
+		r.Port, _ = strconv.Atoi(port)
+	} else {
+		r.Host = hostPort
+	}
+	switch {
+	case wrapper == "yes":
+		r.TLS = "tls"
+	case level != "encrypt" && level != "verify" && level != "secure":
+		r.TLS = "none"
+	}
+	r.From = strings.TrimPrefix(canonical, "static:")
+	if r.From == canonical {
+		r.From = ""
+	}
+	for _, line := range strings.Split(saslPasswd, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if ok && key == relayhost {
+			r.Username, r.Password, _ = strings.Cut(strings.TrimSpace(value), ":")
+		}
+	}
+	return r
+}
+
+// MailBackendPackages returns the generic packages a backend needs. Postfix
+// needs the SASL PLAIN module to log in; msmtp-mta provides sendmail.
+func MailBackendPackages(backend string) []string {
+	if backend == MailBackendMsmtp {
+		return []string{"msmtp", "msmtp-mta"}
+	}
+	return []string{"postfix", "sasl-plain"}
+}
+
+// MailSetupScript returns the script that installs the backend with
+// installCommand when missing and applies the relay. The credentials are
+// already on disk, so the script carries no secrets.
+func (r MailRelay) MailSetupScript(installCommand string) string {
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	if r.Backend == MailBackendMsmtp {
+		fmt.Fprintf(&b, "if ! command -v msmtp >/dev/null 2>&1; then\n  %s\nfi\n", installCommand)
+		// The config holds the password, so only the msmtp group may read
+		// it; msmtp runs setgid to read it for any user that sends mail
+		b.WriteString("getent group msmtp >/dev/null || groupadd -r msmtp\n")
+		fmt.Fprintf(&b, "chown root:msmtp %s\nchmod 640 %s\n", MsmtpConfigPath, MsmtpConfigPath)
+		b.WriteString("MSMTP=$(command -v msmtp)\nchgrp msmtp \"$MSMTP\"\nchmod 2755 \"$MSMTP\"\n")
+		b.WriteString("[ -e /usr/sbin/sendmail ] || ln -s \"$MSMTP\" /usr/sbin/sendmail\n")
+		b.WriteString("echo \"✓ msmtp configured as the system sendmail\"\n")
+		return b.String()
+	}
+
+	b.WriteString("if ! command -v postconf >/dev/null 2>&1; then\n")
+	b.WriteString("  if command -v debconf-set-selections >/dev/null 2>&1; then\n")
+	b.WriteString("    echo \"postfix postfix/main_mailer_type select Satellite system\" | debconf-set-selections\n")
+	b.WriteString("    echo \"postfix postfix/mailname string $(hostname -f)\" | debconf-set-selections\n")
+	b.WriteString("  fi\n")
+	fmt.Fprintf(&b, "  %s\nfi\n", installCommand)
+	fmt.Fprintf(&b, "touch %s\nchmod 600 %s\npostmap %s\n", PostfixSASLPasswdPath, PostfixSASLPasswdPath, PostfixSASLPasswdPath)
+	quoted := make([]string, len(r.PostfixSettings()))
+	for i, s := range r.PostfixSettings() {
+		quoted[i] = ShellQuote(s)
+	}
+	fmt.Fprintf(&b, "postconf -e %s\n", strings.Join(quoted, " "))
+	b.WriteString("systemctl enable postfix\nsystemctl restart postfix\n")
+	fmt.Fprintf(&b, "echo \"✓ Postfix relaying through %s\"\n", r.relayhost())
+	return b.String()
+}
+
+// DetectMailBackend returns the configured backend, or "" when neither
+// Postfix nor msmtp is installed
+func DetectMailBackend() string {
+	if Command("which", "msmtp").Run() == nil {
+		if _, err := Stat(MsmtpConfigPath); err == nil {
+			return MailBackendMsmtp
+		}
+	}
+	if Command("which", "postconf").Run() == nil {
+		return MailBackendPostfix
+	}
+	if Command("which", "msmtp").Run() == nil {
+		return MailBackendMsmtp
+	}
+	return ""
+}
+
+// ReadMailRelay returns the relay the installed backend sends through
+func ReadMailRelay() (MailRelay, error) {
+	switch DetectMailBackend() {
+	case MailBackendMsmtp:
+		data, err := ReadFile(MsmtpConfigPath)
+		if err != nil {
+			return MailRelay{Backend: MailBackendMsmtp}, fmt.Errorf("failed to read %s: %w", MsmtpConfigPath, err)
+		}
+		return parseMsmtpConfig(string(data)), nil
+	case MailBackendPostfix:
+		postconf := func(key string) string {
+			output, _ := Command("postconf", "-h", key).Output()
+			return strings.TrimSpace(string(output))
+		}
+		sasl, _ := ReadFile(PostfixSASLPasswdPath)
+		return parsePostfixRelay(postconf("relayhost"), postconf("smtp_tls_security_level"),
+			postconf("smtp_tls_wrappermode"), postconf("sender_canonical_maps"), string(sasl)), nil
+	}
+	return MailRelay{}, fmt.Errorf("no mail backend installed")
+}
+
+// WriteMailCredentials writes the backend's credentials file before the
+// setup script runs
+func WriteMailCredentials(r MailRelay) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if r.Backend == MailBackendMsmtp {
+		if err := WriteFile(MsmtpConfigPath, []byte(r.MsmtpConfig()), 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", MsmtpConfigPath, err)
+		}
+		return nil
+	}
+	if err := MkdirAll("/etc/postfix", 0755); err != nil {
+		return fmt.Errorf("failed to create /etc/postfix: %w", err)
+	}
+	if err := WriteFile(PostfixSASLPasswdPath, []byte(r.PostfixSASLPasswd()), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", PostfixSASLPasswdPath, err)
+	}
+	return nil
+}
+
+// MailTestMessage returns a test email for sendmail -t
+func MailTestMessage(from, to, host string, now time.Time) string {
+	var b strings.Builder
+	if from != "" {
+		fmt.Fprintf(&b, "From: %s\n", from)
+	}
+	fmt.Fprintf(&b, "To: %s\n", to)
+	fmt.Fprintf(&b, "Subject: Test email from %s\n", host)
+	fmt.Fprintf(&b, "Date: %s\n\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "This is a test email sent by ravact from %s.\n", host)
+	b.WriteString("If it arrived, password resets and notifications can be delivered too.\n")
+	return b.String()
+}
+
+// SendTestMail hands a test email to the system sendmail. msmtp reports
+// relay errors here; Postfix queues the mail, so check the mail log.
+func SendTestMail(from, to string) error {
+	if _, err := mail.ParseAddress(to); err != nil {
+		return fmt.Errorf("invalid recipient: %s", to)
+	}
+	host, _ := HostName()
+	args := []string{"-t", "-i"}
+	if from != "" {
+		args = append(args, "-f", from)
+	}
+	cmd := Command("sendmail", args...)
+	cmd.Stdin = strings.NewReader(MailTestMessage(from, to, host, time.Now()))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("sendmail failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// MailLogPath returns the syslog mail log, or "" when the host only logs
+// to the journal
+func MailLogPath() string {
+	for _, path := range []string{"/var/log/mail.log", "/var/log/maillog"} {
+		if _, err := Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
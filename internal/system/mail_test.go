@@ -0,0 +1,97 @@
+package system
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMsmtpConfigRoundTrip(t *testing.T) {
+	r := MailRelay{
+		Backend:  MailBackendMsmtp,
+		Host:     "smtp.mailgun.org",
+		Port:     587,
+		TLS:      "starttls",
+		Username: "postmaster@mg.example.com",
+		Password: `pa ss"word\`,
+		From:     "no-reply@example.com",
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	content := r.MsmtpConfig()
+	if !strings.Contains(content, "auth           on\n") || !strings.Contains(content, "tls_starttls   on\n") {
+		t.Errorf("unexpected msmtprc:\n%s", content)
+	}
+	if got := parseMsmtpConfig(content); got != r {
+		t.Errorf("round trip = %+v, want %+v", got, r)
+	}
+
+	r.TLS = "tls"
+	r.Port = 465
+	if got := parseMsmtpConfig(r.MsmtpConfig()); got.TLS != "tls" {
+		t.Errorf("implicit TLS parsed as %q", got.TLS)
+	}
+}
+
+func TestPostfixRelay(t *testing.T) {
+	r := MailRelay{
+		Backend:  MailBackendPostfix,
+		Host:     "email-smtp.eu-west-1.amazonaws.com",
+		Port:     465,
+		TLS:      "tls",
+		Username: "AKIA123",
+		Password: "secret with space",
+		From:     "no-reply@example.com",
+	}
+	settings := strings.Join(r.PostfixSettings(), "\n")
+	for _, want := range []string{
+		"relayhost = [email-smtp.eu-west-1.amazonaws.com]:465",
+		"smtp_tls_wrappermode = yes",
+		"smtp_sasl_auth_enable = yes",
+		"sender_canonical_maps = static:no-reply@example.com",
+	} {
+		if !strings.Contains(settings, want) {
+			t.Errorf("settings missing %q:\n%s", want, settings)
+		}
+	}
+
+	got := parsePostfixRelay("[email-smtp.eu-west-1.amazonaws.com]:465", "encrypt", "yes",
+		"static:no-reply@example.com", r.PostfixSASLPasswd())
+	if got != r {
+		t.Errorf("parsed %+v, want %+v", got, r)
+	}
+
+	script := r.MailSetupScript("apt-get install -y postfix")
+	if strings.Contains(script, r.Password) {
+		t.Error("setup script must not contain the password")
+	}
+	if !strings.Contains(script, "postmap /etc/postfix/sasl_passwd") {
+		t.Errorf("setup script does not build the SASL map:\n%s", script)
+	}
+}
+
+func TestMailRelayValidate(t *testing.T) {
+	base := MailRelay{Backend: MailBackendMsmtp, Host: "smtp.example.com", Port: 587, TLS: "starttls"}
+	for name, change := range map[string]func(*MailRelay){
+		"backend":  func(r *MailRelay) { r.Backend = "sendmail" },
+		"host":     func(r *MailRelay) { r.Host = "smtp example.com" },
+		"port":     func(r *MailRelay) { r.Port = 0 },
+		"tls":      func(r *MailRelay) { r.TLS = "ssl" },
+		"password": func(r *MailRelay) { r.Username = "user" },
+		"from":     func(r *MailRelay) { r.From = "not an address" },
+	} {
+		r := base
+		change(&r)
+		if r.Validate() == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMailTestMessage(t *testing.T) {
+	msg := MailTestMessage("app@example.com", "me@example.com", "web1", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	if !strings.HasPrefix(msg, "From: app@example.com\nTo: me@example.com\nSubject: Test email from web1\nDate: Fri, 02 Jan 2026 03:04:05 +0000\n\n") {
+		t.Errorf("unexpected message:\n%s", msg)
+	}
+}
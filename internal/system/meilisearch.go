@@ -0,0 +1,224 @@
+package system
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// MeilisearchConfigPath is the config file the setup script writes
+var MeilisearchConfigPath = "/etc/meilisearch.toml"
+
+// MeilisearchService is the systemd unit the setup script creates
+const MeilisearchService = "meilisearch"
+
+// MeilisearchConfig is the part of meilisearch.toml ravact manages
+type MeilisearchConfig struct {
+	Env       string // production requires a master key
+	HTTPAddr  string // host:port the API listens on
+	MasterKey string
+	DBPath    string
+}
+
+// ParseMeilisearchConfig reads the top-level key = "value" settings of
+// meilisearch.toml
+func ParseMeilisearchConfig(content string) MeilisearchConfig {
+	c := MeilisearchConfig{Env: "development", HTTPAddr: "127.0.0.1:7700"}
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		switch strings.TrimSpace(key) {
+		case "env":
+			c.Env = value
+		case "http_addr":
+			c.HTTPAddr = value
+		case "master_key":
+			c.MasterKey = value
+		case "db_path":
+			c.DBPath = value
+		}
+	}
+	return c
+}
+
+// setMeilisearchOption sets a string option in meilisearch.toml, replacing
+// the existing line or appending one
+func setMeilisearchOption(content, key, value string) string {
+	line := key + " = " + strconv.Quote(value)
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	for i, l := range lines {
+		if k, _, ok := strings.Cut(strings.TrimSpace(l), "="); ok && strings.TrimSpace(k) == key {
+			lines[i] = line
+			return strings.Join(lines, "\n") + "\n"
+		}
+	}
+	return strings.Join(append(lines, line), "\n") + "\n"
+}
+
+// ValidateMeilisearchAddr checks a host:port listen address
+func ValidateMeilisearchAddr(addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) == nil {
+		return fmt.Errorf("enter an IP and port such as 127.0.0.1:7700")
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+	return nil
+}
+
+// GenerateMeilisearchKey returns a random master key; Meilisearch requires
+// at least 16 bytes
+func GenerateMeilisearchKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// URL returns the address applications connect to. A wildcard listen
+// address is reached on loopback from the same server.
+func (c MeilisearchConfig) URL() string {
+	host, port, err := net.SplitHostPort(c.HTTPAddr)
+	if err != nil {
+		return "http://" + c.HTTPAddr
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// ScoutEnv returns the .env settings for Laravel Scout with the given key
+func (c MeilisearchConfig) ScoutEnv(key string) [][2]string {
+	return [][2]string{
+		{"SCOUT_DRIVER", "meilisearch"},
+		{"MEILISEARCH_HOST", c.URL()},
+		{"MEILISEARCH_KEY", key},
+	}
+}
+
+// MeilisearchManager configures a Meilisearch installed by the setup script
+type MeilisearchManager struct {
+	configPath string
+}
+
+// NewMeilisearchManager creates a Meilisearch manager
+func NewMeilisearchManager() *MeilisearchManager {
+	return &MeilisearchManager{configPath: MeilisearchConfigPath}
+}
+
+// IsInstalled checks if Meilisearch is installed
+func (mm *MeilisearchManager) IsInstalled() bool {
+	return Command("which", "meilisearch").Run() == nil
+}
+
+// GetConfig reads meilisearch.toml
+func (mm *MeilisearchManager) GetConfig() (MeilisearchConfig, error) {
+	data, err := ReadFile(mm.configPath)
+	if err != nil {
+		return MeilisearchConfig{}, fmt.Errorf("failed to read %s: %w", mm.configPath, err)
+	}
+	return ParseMeilisearchConfig(string(data)), nil
+}
+
+// setOption updates one option in meilisearch.toml, keeping its mode
+func (mm *MeilisearchManager) setOption(key, value string) error {
+	data, err := ReadFile(mm.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", mm.configPath, err)
+	}
+	mode := os.FileMode(0640)
+	if info, err := Stat(mm.configPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := WriteFile(mm.configPath, []byte(setMeilisearchOption(string(data), key, value)), mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", mm.configPath, err)
+	}
+	return nil
+}
+
+// SetListenAddress changes the address the API listens on
+func (mm *MeilisearchManager) SetListenAddress(addr string) error {
+	if err := ValidateMeilisearchAddr(addr); err != nil {
+		return err
+	}
+	return mm.setOption("http_addr", addr)
+}
+
+// RotateMasterKey replaces the master key and returns the new one. The API
+// keys derived from the old master key stop working.
+func (mm *MeilisearchManager) RotateMasterKey() (string, error) {
+	key, err := GenerateMeilisearchKey()
+	if err != nil {
+		return "", err
+	}
+	if err := mm.setOption("master_key", key); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// meilisearchKeys is the response of GET /keys
+type meilisearchKeys struct {
+	Results []struct {
+		Name string `json:"name"`
+		Key  string `json:"key"`
+	} `json:"results"`
+}
+
+// AdminAPIKey returns the Default Admin API Key, which applications should
+// use instead of the master key
+func (mm *MeilisearchManager) AdminAPIKey(c MeilisearchConfig) (string, error) {
+	// The header is read from stdin so the master key stays out of ps
+	cmd := Command("curl", "--fail", "--silent", "--show-error", "-H", "@-", c.URL()+"/keys")
+	cmd.Stdin = strings.NewReader("Authorization: Bearer " + c.MasterKey + "\n")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to list API keys: %s", strings.TrimSpace(string(output)))
+	}
+	return parseMeilisearchAdminKey(output)
+}
+
+// parseMeilisearchAdminKey finds the default admin key in a GET /keys response
+func parseMeilisearchAdminKey(data []byte) (string, error) {
+	var keys meilisearchKeys
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return "", fmt.Errorf("unexpected /keys response: %w", err)
+	}
+	for _, k := range keys.Results {
+		if k.Name == "Default Admin API Key" {
+			return k.Key, nil
+		}
+	}
+	return "", fmt.Errorf("no Default Admin API Key found")
+}
+
+// Restart restarts Meilisearch to apply config changes
+func (mm *MeilisearchManager) Restart() error {
+	if output, err := Command("systemctl", "restart", MeilisearchService).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to restart Meilisearch: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Status returns the state of the Meilisearch unit, e.g. active or failed
+func (mm *MeilisearchManager) Status() string {
+	output, _ := Command("systemctl", "is-active", MeilisearchService).Output()
+	if status := strings.TrimSpace(string(output)); status != "" {
+		return status
+	}
+	return "unknown"
+}
@@ -0,0 +1,56 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMeilisearchConfig(t *testing.T) {
+	content := `# Meilisearch configuration
+env = "production"
+master_key = "old-key"
+http_addr = "0.0.0.0:7700"
+db_path = "/var/lib/meilisearch/data"
+`
+	c := ParseMeilisearchConfig(content)
+	if c.Env != "production" || c.MasterKey != "old-key" || c.DBPath != "/var/lib/meilisearch/data" {
+		t.Fatalf("unexpected config %+v", c)
+	}
+	if got := c.URL(); got != "http://127.0.0.1:7700" {
+		t.Errorf("URL for a wildcard address = %q", got)
+	}
+
+	updated := setMeilisearchOption(content, "master_key", "new-key")
+	updated = setMeilisearchOption(updated, "max_indexing_memory", "1 GiB")
+	c = ParseMeilisearchConfig(updated)
+	if c.MasterKey != "new-key" || c.HTTPAddr != "0.0.0.0:7700" {
+		t.Errorf("unexpected config after update %+v", c)
+	}
+	if strings.Count(updated, "master_key") != 1 || !strings.HasSuffix(updated, "max_indexing_memory = \"1 GiB\"\n") {
+		t.Errorf("expected master_key replaced in place and the new option appended:\n%s", updated)
+	}
+}
+
+func TestValidateMeilisearchAddr(t *testing.T) {
+	for addr, ok := range map[string]bool{
+		"127.0.0.1:7700": true,
+		"[::1]:7700":     true,
+		"localhost:7700": false,
+		"127.0.0.1":      false,
+		"127.0.0.1:0":    false,
+	} {
+		if err := ValidateMeilisearchAddr(addr); (err == nil) != ok {
+			t.Errorf("ValidateMeilisearchAddr(%q) = %v", addr, err)
+		}
+	}
+}
+
+func TestParseMeilisearchAdminKey(t *testing.T) {
+	data := []byte(`{"results":[{"name":"Default Search API Key","key":"search"},{"name":"Default Admin API Key","key":"admin"}],"offset":0,"limit":20,"total":2}`)
+	if key, err := parseMeilisearchAdminKey(data); err != nil || key != "admin" {
+		t.Errorf("admin key = %q, %v", key, err)
+	}
+	if _, err := parseMeilisearchAdminKey([]byte(`{"results":[]}`)); err == nil {
+		t.Error("expected an error without an admin key")
+	}
+}
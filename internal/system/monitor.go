@@ -0,0 +1,171 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// MonitorStatusFile is where `ravact monitor` writes its latest results,
+// inside StateDir
+const MonitorStatusFile = "monitor.json"
+
+// MonitorSite is the latest health check of one site
+type MonitorSite struct {
+	Name   string           `json:"name"`
+	URL    string           `json:"url"`
+	Result SiteHealthResult `json:"result"`
+}
+
+// MonitorStatus is one round of `ravact monitor`
+type MonitorStatus struct {
+	UpdatedAt time.Time       `json:"updated_at"`
+	Interval  time.Duration   `json:"interval"`
+	Sites     []MonitorSite   `json:"sites"`
+	Services  []ServiceHealth `json:"services"`
+}
+
+// Stale reports whether the monitor has missed several rounds, meaning it
+// has probably stopped
+func (s *MonitorStatus) Stale(now time.Time) bool {
+	return s.Interval > 0 && now.Sub(s.UpdatedAt) > 3*s.Interval
+}
+
+// Failing returns the sites and services that are currently down
+func (s *MonitorStatus) Failing() (sites []MonitorSite, services []ServiceHealth) {
+	for _, site := range s.Sites {
+		if !site.Result.OK {
+			sites = append(sites, site)
+		}
+	}
+	for _, svc := range s.Services {
+		if svc.Active == "failed" {
+			services = append(services, svc)
+		}
+	}
+	return sites, services
+}
+
+// MonitorStatusPath returns the location of the status file
+func MonitorStatusPath() string {
+	return filepath.Join(StateDir, MonitorStatusFile)
+}
+
+// HealthCheckedSites returns the sites that have a health check configured
+func HealthCheckedSites() ([]string, error) {
+	entries, err := ReadDir(SiteDataDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", SiteDataDir, err)
+	}
+	var sites []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, err := Stat(filepath.Join(SiteDataDir, e.Name(), SiteHealthCheckFile)); err == nil {
+			sites = append(sites, e.Name())
+		}
+	}
+	sort.Strings(sites)
+	return sites, nil
+}
+
+// RunMonitorRound checks every configured site and the service states,
+// recording each site's result for the TUI
+func RunMonitorRound(interval time.Duration) (*MonitorStatus, error) {
+	status := &MonitorStatus{Interval: interval}
+	sites, err := HealthCheckedSites()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range sites {
+		check, err := LoadSiteHealthCheck(name)
+		if err != nil || check == nil {
+			continue
+		}
+		result := check.Run()
+		if err := RecordSiteHealth(name, result); err != nil {
+			return nil, err
+		}
+		status.Sites = append(status.Sites, MonitorSite{Name: name, URL: check.URL, Result: result})
+	}
+	// Services are optional; hosts without systemd still get site checks
+	status.Services, _ = ServiceStates()
+	status.UpdatedAt = time.Now()
+	return status, nil
+}
+
+// SaveMonitorStatus writes the status file
+func SaveMonitorStatus(status *MonitorStatus) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode monitor status: %w", err)
+	}
+	if err := MkdirAll(StateDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", StateDir, err)
+	}
+	if err := WriteFile(MonitorStatusPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write monitor status: %w", err)
+	}
+	return nil
+}
+
+// LoadMonitorStatus reads the status file, or returns nil if the monitor
+// has never run
+func LoadMonitorStatus() (*MonitorStatus, error) {
+	data, err := ReadFile(MonitorStatusPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read monitor status: %w", err)
+	}
+	var status MonitorStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", MonitorStatusPath(), err)
+	}
+	return &status, nil
+}
+
+// MonitorAlerts compares two rounds and describes what went down or came
+// back, so alerts fire once per change rather than every round. With no
+// previous round, everything already down is reported.
+func MonitorAlerts(prev, cur *MonitorStatus) []string {
+	wasOK := map[string]bool{}
+	wasActive := map[string]string{}
+	if prev != nil {
+		for _, site := range prev.Sites {
+			wasOK[site.Name] = site.Result.OK
+		}
+		for _, svc := range prev.Services {
+			wasActive[svc.Unit] = svc.Active
+		}
+	}
+
+	var alerts []string
+	for _, site := range cur.Sites {
+		before, seen := wasOK[site.Name]
+		switch {
+		case !site.Result.OK && (!seen || before):
+			alerts = append(alerts, fmt.Sprintf("DOWN site %s (%s): %s", site.Name, site.URL, site.Result.Error))
+		case site.Result.OK && seen && !before:
+			alerts = append(alerts, fmt.Sprintf("RECOVERED site %s (%s): %s", site.Name, site.URL, site.Result.Summary()))
+		}
+	}
+	for _, svc := range cur.Services {
+		before, seen := wasActive[svc.Unit]
+		switch {
+		case svc.Active == "failed" && before != "failed":
+			alerts = append(alerts, fmt.Sprintf("FAILED service %s (%s)", svc.Unit, svc.Sub))
+		case svc.Active == "active" && seen && before == "failed":
+			alerts = append(alerts, fmt.Sprintf("RECOVERED service %s", svc.Unit))
+		}
+	}
+	return alerts
+}
@@ -0,0 +1,80 @@
+package system
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMonitorAlerts(t *testing.T) {
+	up := SiteHealthResult{OK: true, Status: 200}
+	down := SiteHealthResult{Error: "got HTTP 502, expected 200"}
+	round := func(shop SiteHealthResult, nginx string) *MonitorStatus {
+		return &MonitorStatus{
+			Sites:    []MonitorSite{{Name: "shop", URL: "https://shop.test/", Result: shop}},
+			Services: []ServiceHealth{{Unit: "nginx.service", Active: nginx, Sub: "dead"}},
+		}
+	}
+
+	if alerts := MonitorAlerts(nil, round(up, "active")); len(alerts) != 0 {
+		t.Errorf("expected no alerts for a healthy first round, got %v", alerts)
+	}
+	if alerts := MonitorAlerts(nil, round(down, "failed")); len(alerts) != 2 {
+		t.Errorf("expected a first round to report what is already down, got %v", alerts)
+	}
+
+	alerts := MonitorAlerts(round(up, "active"), round(down, "failed"))
+	if len(alerts) != 2 || !strings.HasPrefix(alerts[0], "DOWN site shop") || !strings.HasPrefix(alerts[1], "FAILED service nginx.service") {
+		t.Errorf("unexpected alerts %v", alerts)
+	}
+	if alerts := MonitorAlerts(round(down, "failed"), round(down, "failed")); len(alerts) != 0 {
+		t.Errorf("expected no repeated alerts, got %v", alerts)
+	}
+	alerts = MonitorAlerts(round(down, "failed"), round(up, "active"))
+	if len(alerts) != 2 || !strings.HasPrefix(alerts[0], "RECOVERED site shop") || !strings.HasPrefix(alerts[1], "RECOVERED service") {
+		t.Errorf("unexpected recovery alerts %v", alerts)
+	}
+}
+
+func TestMonitorRound(t *testing.T) {
+	originalData, originalState := SiteDataDir, StateDir
+	SiteDataDir = t.TempDir()
+	StateDir = filepath.Join(t.TempDir(), "state")
+	defer func() { SiteDataDir, StateDir = originalData, originalState }()
+
+	if status, err := LoadMonitorStatus(); err != nil || status != nil {
+		t.Fatalf("expected no status before the first round, got %+v (%v)", status, err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	if err := SaveSiteHealthCheck("shop", SiteHealthCheck{URL: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+	// A site directory without a health check is skipped
+	if err := os.MkdirAll(filepath.Join(SiteDataDir, "blog"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := RunMonitorRound(time.Minute)
+	if err != nil {
+		t.Fatalf("RunMonitorRound: %v", err)
+	}
+	if len(status.Sites) != 1 || status.Sites[0].Name != "shop" || !status.Sites[0].Result.OK {
+		t.Fatalf("unexpected sites %+v", status.Sites)
+	}
+	if err := SaveMonitorStatus(status); err != nil {
+		t.Fatalf("SaveMonitorStatus: %v", err)
+	}
+	loaded, err := LoadMonitorStatus()
+	if err != nil || loaded == nil || loaded.Interval != time.Minute || len(loaded.Sites) != 1 {
+		t.Fatalf("unexpected loaded status %+v (%v)", loaded, err)
+	}
+	if loaded.Stale(loaded.UpdatedAt.Add(2*time.Minute)) || !loaded.Stale(loaded.UpdatedAt.Add(4*time.Minute)) {
+		t.Error("expected the status to go stale after three missed rounds")
+	}
+}
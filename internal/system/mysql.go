@@ -2,12 +2,13 @@ package system
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
+	"regexp"
 	"strings"
 )
 
+// databaseNamePattern matches database names that are safe to use in SQL identifiers
+var databaseNamePattern = regexp.MustCompile(`^[A-Za-z0-9_$-]+$`)
+
 // MySQLConfig represents MySQL configuration
 type MySQLConfig struct {
 	Port         int
@@ -41,7 +42,7 @@ func (m *MySQLManager) GetConfig() (*MySQLConfig, error) {
 	}
 
 	// Check if config file exists
-	if _, err := os.Stat(m.configPath); err != nil {
+	if _, err := Stat(m.configPath); err != nil {
 		// Try alternative paths
 		altPaths := []string{
 			"/etc/mysql/my.cnf",
@@ -51,7 +52,7 @@ func (m *MySQLManager) GetConfig() (*MySQLConfig, error) {
 		
 		found := false
 		for _, path := range altPaths {
-			if _, err := os.Stat(path); err == nil {
+			if _, err := Stat(path); err == nil {
 				m.configPath = path
 				config.ConfigPath = path
 				found = true
@@ -65,7 +66,7 @@ func (m *MySQLManager) GetConfig() (*MySQLConfig, error) {
 	}
 
 	// Read and parse config file
-	data, err := os.ReadFile(m.configPath)
+	data, err := ReadFile(m.configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read MySQL config: %w", err)
 	}
@@ -110,14 +111,14 @@ func (m *MySQLManager) ChangePort(newPort int) error {
 	}
 
 	// Read current config
-	data, err := os.ReadFile(m.configPath)
+	data, err := ReadFile(m.configPath)
 	if err != nil {
 		return fmt.Errorf("failed to read MySQL config: %w", err)
 	}
 
 	// Backup original config
 	backupPath := m.configPath + ".bak"
-	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+	if err := WriteFile(backupPath, data, 0644); err != nil {
 		return fmt.Errorf("failed to backup config: %w", err)
 	}
 
@@ -161,9 +162,9 @@ func (m *MySQLManager) ChangePort(newPort int) error {
 
 	// Write updated config
 	newData := strings.Join(lines, "\n")
-	if err := os.WriteFile(m.configPath, []byte(newData), 0644); err != nil {
+	if err := WriteFile(m.configPath, []byte(newData), 0644); err != nil {
 		// Restore backup on failure
-		os.WriteFile(m.configPath, data, 0644)
+		WriteFile(m.configPath, data, 0644)
 		return fmt.Errorf("failed to write MySQL config: %w", err)
 	}
 
@@ -177,7 +178,7 @@ func (m *MySQLManager) ChangeRootPassword(newPassword string) error {
 	}
 
 	// Check if MySQL is running
-	cmd := exec.Command("systemctl", "is-active", "mysql")
+	cmd := Command("systemctl", "is-active", "mysql")
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("MySQL service is not running")
 	}
@@ -186,12 +187,12 @@ func (m *MySQLManager) ChangeRootPassword(newPassword string) error {
 	sqlCmd := fmt.Sprintf("ALTER USER 'root'@'localhost' IDENTIFIED BY '%s';", 
 		strings.ReplaceAll(newPassword, "'", "\\'"))
 	
-	cmd = exec.Command("mysql", "-u", "root", "-e", sqlCmd)
+	cmd = Command("mysql", "-u", "root", "-e", sqlCmd)
 	
 	// Try with existing password from debian-sys-maint
 	debianCnfPath := "/etc/mysql/debian.cnf"
-	if _, err := os.Stat(debianCnfPath); err == nil {
-		cmd = exec.Command("mysql", "--defaults-file="+debianCnfPath, "-e", sqlCmd)
+	if _, err := Stat(debianCnfPath); err == nil {
+		cmd = Command("mysql", "--defaults-file="+debianCnfPath, "-e", sqlCmd)
 	}
 
 	output, err := cmd.CombinedOutput()
@@ -200,7 +201,7 @@ func (m *MySQLManager) ChangeRootPassword(newPassword string) error {
 	}
 
 	// Flush privileges
-	cmd = exec.Command("mysql", "-u", "root", "-p"+newPassword, "-e", "FLUSH PRIVILEGES;")
+	cmd = Command("mysql", "-u", "root", "-p"+newPassword, "-e", "FLUSH PRIVILEGES;")
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to flush privileges: %w", err)
 	}
@@ -210,7 +211,7 @@ func (m *MySQLManager) ChangeRootPassword(newPassword string) error {
 
 // RestartService restarts the MySQL service
 func (m *MySQLManager) RestartService() error {
-	cmd := exec.Command("systemctl", "restart", "mysql")
+	cmd := Command("systemctl", "restart", "mysql")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to restart MySQL: %s", string(output))
@@ -220,7 +221,7 @@ func (m *MySQLManager) RestartService() error {
 
 // GetStatus returns the MySQL service status
 func (m *MySQLManager) GetStatus() (string, error) {
-	cmd := exec.Command("systemctl", "status", "mysql")
+	cmd := Command("systemctl", "status", "mysql")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// Status command returns non-zero if service is not running
@@ -231,13 +232,13 @@ func (m *MySQLManager) GetStatus() (string, error) {
 
 // IsInstalled checks if MySQL is installed
 func (m *MySQLManager) IsInstalled() bool {
-	cmd := exec.Command("which", "mysql")
+	cmd := Command("which", "mysql")
 	return cmd.Run() == nil
 }
 
 // GetVersion returns the MySQL version
 func (m *MySQLManager) GetVersion() (string, error) {
-	cmd := exec.Command("mysql", "--version")
+	cmd := Command("mysql", "--version")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -247,9 +248,13 @@ func (m *MySQLManager) GetVersion() (string, error) {
 
 // CreateDatabase creates a new database
 func (m *MySQLManager) CreateDatabase(dbName, username, password string) error {
+	if err := CheckName(NamingDatabase, dbName); err != nil {
+		return err
+	}
+
 	// Create database
 	createDBCmd := fmt.Sprintf("CREATE DATABASE IF NOT EXISTS `%s`;", dbName)
-	cmd := exec.Command("mysql", "-u", "root", "-e", createDBCmd)
+	cmd := Command("mysql", "-u", "root", "-e", createDBCmd)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to create database: %w", err)
 	}
@@ -260,7 +265,7 @@ func (m *MySQLManager) CreateDatabase(dbName, username, password string) error {
 			"CREATE USER IF NOT EXISTS '%s'@'localhost' IDENTIFIED BY '%s';",
 			username, strings.ReplaceAll(password, "'", "\\'"),
 		)
-		cmd = exec.Command("mysql", "-u", "root", "-e", createUserCmd)
+		cmd = Command("mysql", "-u", "root", "-e", createUserCmd)
 		if err := cmd.Run(); err != nil {
 			return fmt.Errorf("failed to create user: %w", err)
 		}
@@ -269,7 +274,7 @@ func (m *MySQLManager) CreateDatabase(dbName, username, password string) error {
 			"GRANT ALL PRIVILEGES ON `%s`.* TO '%s'@'localhost'; FLUSH PRIVILEGES;",
 			dbName, username,
 		)
-		cmd = exec.Command("mysql", "-u", "root", "-e", grantCmd)
+		cmd = Command("mysql", "-u", "root", "-e", grantCmd)
 		if err := cmd.Run(); err != nil {
 			return fmt.Errorf("failed to grant privileges: %w", err)
 		}
@@ -280,7 +285,7 @@ func (m *MySQLManager) CreateDatabase(dbName, username, password string) error {
 
 // ListDatabases returns a list of all databases
 func (m *MySQLManager) ListDatabases() ([]string, error) {
-	cmd := exec.Command("mysql", "-u", "root", "-e", "SHOW DATABASES;")
+	cmd := Command("mysql", "-u", "root", "-e", "SHOW DATABASES;")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list databases: %w", err)
@@ -304,28 +309,21 @@ func (m *MySQLManager) ListDatabases() ([]string, error) {
 	return databases, nil
 }
 
-// ExportDatabase exports a database to SQL file
-func (m *MySQLManager) ExportDatabase(dbName, outputPath string) error {
-	// Ensure output directory exists
-	dir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+// DropDatabase permanently deletes a database
+func (m *MySQLManager) DropDatabase(dbName string) error {
+	if !databaseNamePattern.MatchString(dbName) {
+		return fmt.Errorf("invalid database name: %s", dbName)
 	}
 
-	// Create output file
-	outFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+	dropCmd := fmt.Sprintf("DROP DATABASE `%s`;", dbName)
+	cmd := Command("mysql", "-u", "root", "-e", dropCmd)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to drop database: %s", strings.TrimSpace(string(output)))
 	}
-	defer outFile.Close()
-
-	// Run mysqldump
-	cmd := exec.Command("mysqldump", "-u", "root", dbName)
-	cmd.Stdout = outFile
-	
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to export database: %w", err)
-	}
-
 	return nil
 }
+
+// ExportDatabase exports a database to an SQL file on the managed host
+func (m *MySQLManager) ExportDatabase(dbName, outputPath string) error {
+	return exportDatabase("mysql", dbName, outputPath)
+}
@@ -0,0 +1,179 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NamingPolicyPath is where the team's naming rules are kept
+var NamingPolicyPath = "/etc/ravact/naming.yaml"
+
+// Resource kinds a naming policy covers
+const (
+	NamingSite     = "site"
+	NamingDatabase = "database"
+	NamingUser     = "user"
+)
+
+// NamingRule is a pattern names must match, with a hint shown when they don't
+type NamingRule struct {
+	Pattern string `yaml:"pattern,omitempty"`
+	Hint    string `yaml:"hint,omitempty"` // e.g. "use <client>-<env>, such as acme-prod"
+}
+
+// Check reports why a name breaks the rule; an empty rule allows any name
+func (r NamingRule) Check(kind, name string) error {
+	if r.Pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid %s naming pattern: %w", kind, err)
+	}
+	if re.MatchString(name) {
+		return nil
+	}
+	if r.Hint != "" {
+		return fmt.Errorf("%s name %q breaks the naming policy: %s", kind, name, r.Hint)
+	}
+	return fmt.Errorf("%s name %q breaks the naming policy: must match %s", kind, name, r.Pattern)
+}
+
+// NamingPolicy holds the rules for each kind of resource ravact creates
+type NamingPolicy struct {
+	Sites     NamingRule `yaml:"sites,omitempty"`
+	Databases NamingRule `yaml:"databases,omitempty"`
+	Users     NamingRule `yaml:"users,omitempty"`
+}
+
+// LoadNamingPolicy reads the policy from the current host; without one every
+// name is allowed
+func LoadNamingPolicy() (NamingPolicy, error) {
+	var policy NamingPolicy
+	data, err := ReadFile(NamingPolicyPath)
+	if os.IsNotExist(err) {
+		return policy, nil
+	}
+	if err != nil {
+		return policy, fmt.Errorf("failed to read naming policy: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return policy, fmt.Errorf("failed to parse %s: %w", NamingPolicyPath, err)
+	}
+	return policy, nil
+}
+
+// Rule returns the rule for a resource kind
+func (p NamingPolicy) Rule(kind string) NamingRule {
+	switch kind {
+	case NamingSite:
+		return p.Sites
+	case NamingDatabase:
+		return p.Databases
+	case NamingUser:
+		return p.Users
+	}
+	return NamingRule{}
+}
+
+// Check reports why a name breaks the policy for its kind
+func (p NamingPolicy) Check(kind, name string) error {
+	return p.Rule(kind).Check(kind, name)
+}
+
+// Validate checks that every pattern compiles
+func (p NamingPolicy) Validate() error {
+	for _, kind := range []string{NamingSite, NamingDatabase, NamingUser} {
+		if pattern := p.Rule(kind).Pattern; pattern != "" {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid %s naming pattern: %w", kind, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Save writes the policy
+func (p NamingPolicy) Save() error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode naming policy: %w", err)
+	}
+	if err := MkdirAll(filepath.Dir(NamingPolicyPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(NamingPolicyPath), err)
+	}
+	if err := WriteFile(NamingPolicyPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", NamingPolicyPath, err)
+	}
+	return nil
+}
+
+// CheckName loads the policy and checks a name against it, so creation paths
+// can enforce it with one call
+func CheckName(kind, name string) error {
+	policy, err := LoadNamingPolicy()
+	if err != nil {
+		return err
+	}
+	return policy.Check(kind, name)
+}
+
+// NamingViolation is an existing resource whose name breaks the policy
+type NamingViolation struct {
+	Kind string
+	Name string
+	Err  error
+}
+
+// AuditNames checks existing resources against the policy. Resources that
+// cannot be listed, such as databases when no server is installed, are
+// skipped.
+func (p NamingPolicy) AuditNames() []NamingViolation {
+	names := map[string][]string{}
+	if p.Sites.Pattern != "" {
+		if sites, err := NewNginxManager().GetAllSites(); err == nil {
+			for _, site := range sites {
+				names[NamingSite] = append(names[NamingSite], site.Name)
+			}
+		}
+	}
+	if p.Databases.Pattern != "" {
+		if dbs, err := NewMySQLManager().ListDatabases(); err == nil {
+			names[NamingDatabase] = append(names[NamingDatabase], dbs...)
+		}
+		if dbs, err := NewPostgreSQLManager().ListDatabases(); err == nil {
+			names[NamingDatabase] = append(names[NamingDatabase], dbs...)
+		}
+	}
+	if p.Users.Pattern != "" {
+		if users, err := NewUserManager().GetAllUsers(); err == nil {
+			for _, user := range users {
+				// Only people ravact could have created, not system accounts
+				if user.UID >= 1000 && user.UID < 65534 {
+					names[NamingUser] = append(names[NamingUser], user.Username)
+				}
+			}
+		}
+	}
+	return p.audit(names)
+}
+
+// audit checks names grouped by kind
+func (p NamingPolicy) audit(names map[string][]string) []NamingViolation {
+	var violations []NamingViolation
+	for _, kind := range []string{NamingSite, NamingDatabase, NamingUser} {
+		for _, name := range names[kind] {
+			if err := p.Check(kind, name); err != nil {
+				violations = append(violations, NamingViolation{Kind: kind, Name: name, Err: err})
+			}
+		}
+	}
+	return violations
+}
@@ -0,0 +1,57 @@
+package system
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNamingPolicyCheck(t *testing.T) {
+	policy := NamingPolicy{
+		Sites: NamingRule{Pattern: `^[a-z]+-(prod|staging)$`, Hint: "use <client>-<env>, such as acme-prod"},
+		Users: NamingRule{Pattern: `^u-[a-z]+$`},
+	}
+
+	if err := policy.Check(NamingSite, "acme-prod"); err != nil {
+		t.Errorf("expected acme-prod to match: %v", err)
+	}
+	if err := policy.Check(NamingSite, "acme"); err == nil || !strings.Contains(err.Error(), "acme-prod") {
+		t.Errorf("expected the hint in the error, got %v", err)
+	}
+	if err := policy.Check(NamingUser, "alice"); err == nil || !strings.Contains(err.Error(), "^u-[a-z]+$") {
+		t.Errorf("expected the pattern in the error, got %v", err)
+	}
+	if err := policy.Check(NamingDatabase, "Anything Goes"); err != nil {
+		t.Errorf("an empty rule should allow any name: %v", err)
+	}
+
+	violations := policy.audit(map[string][]string{
+		NamingSite:     {"acme-prod", "legacy"},
+		NamingDatabase: {"whatever"},
+		NamingUser:     {"u-deploy", "bob"},
+	})
+	if len(violations) != 2 || violations[0].Name != "legacy" || violations[1].Name != "bob" {
+		t.Errorf("unexpected violations: %+v", violations)
+	}
+}
+
+func TestNamingPolicySave(t *testing.T) {
+	old := NamingPolicyPath
+	NamingPolicyPath = filepath.Join(t.TempDir(), "naming.yaml")
+	t.Cleanup(func() { NamingPolicyPath = old })
+
+	if policy, err := LoadNamingPolicy(); err != nil || policy.Check(NamingSite, "x") != nil {
+		t.Fatalf("a missing policy should allow everything: %v", err)
+	}
+	if err := (NamingPolicy{Databases: NamingRule{Pattern: "("}}).Save(); err == nil {
+		t.Error("expected an invalid pattern to be rejected")
+	}
+
+	want := NamingPolicy{Databases: NamingRule{Pattern: `^app_[a-z]+$`, Hint: "prefix with app_"}}
+	if err := want.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckName(NamingDatabase, "shop"); err == nil || !strings.Contains(err.Error(), "prefix with app_") {
+		t.Errorf("expected the saved rule to apply, got %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package system
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Netdata defaults
+const (
+	NetdataService  = "netdata"
+	NetdataPort     = 19999
+	NetdataSiteName = "netdata" // nginx site of the reverse proxy
+)
+
+// NetdataConfigPath is Netdata's main config file
+var NetdataConfigPath = "/etc/netdata/netdata.conf"
+
+// netdataBindPattern matches the bind option, commented out or not
+var netdataBindPattern = regexp.MustCompile(`^\s*#?\s*bind to\s*=`)
+
+// NetdataUpstream is the dashboard as the reverse proxy reaches it
+var NetdataUpstream = fmt.Sprintf("http://127.0.0.1:%d", NetdataPort)
+
+// parseNetdataBind returns the bind option of the [web] section. Netdata
+// listens on every address when it is unset.
+func parseNetdataBind(content string) string {
+	section := ""
+	bind := "*"
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			section = trimmed
+			continue
+		}
+		if section != "[web]" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(trimmed, "="); ok && strings.TrimSpace(key) == "bind to" {
+			bind = strings.TrimSpace(value)
+		}
+	}
+	return bind
+}
+
+// setNetdataBind sets the bind option of the [web] section, adding the
+// section when the file has none
+func setNetdataBind(content, bind string) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+	option := "    bind to = " + bind
+	inWeb, header := false, -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			inWeb = trimmed == "[web]"
+			if inWeb {
+				header = i
+			}
+			continue
+		}
+		if inWeb && netdataBindPattern.MatchString(line) {
+			lines[i] = option
+			return strings.Join(lines, "\n") + "\n"
+		}
+	}
+	if header < 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		return strings.Join(append(lines, "[web]", option), "\n") + "\n"
+	}
+	lines = append(lines[:header+1], append([]string{option}, lines[header+1:]...)...)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// NetdataLocalBind reports whether a bind option keeps the dashboard on
+// loopback. Entries may carry a port and an =acl suffix; unix sockets are
+// local by nature.
+func NetdataLocalBind(bind string) bool {
+	fields := strings.Fields(bind)
+	for _, addr := range fields {
+		addr, _, _ = strings.Cut(addr, "=")
+		if strings.HasPrefix(addr, "unix:") {
+			continue
+		}
+		host := addr
+		if h, _, err := net.SplitHostPort(addr); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]")
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return false
+		}
+	}
+	return len(fields) > 0
+}
+
+// NetdataStatus is Netdata's state and how the dashboard is reached
+type NetdataStatus struct {
+	Installed bool
+	Service   string // systemctl is-active output
+	Bind      string
+	Proxy     *NginxSite // The reverse proxy site, when there is one
+}
+
+// DashboardURL returns where to open the dashboard: the proxy's domain, or
+// the port directly (through an SSH tunnel when bound to loopback)
+func (s NetdataStatus) DashboardURL(hostIP string) string {
+	if s.Proxy != nil {
+		scheme := "http"
+		if s.Proxy.HasSSL {
+			scheme = "https"
+		}
+		return scheme + "://" + s.Proxy.Domain + "/"
+	}
+	if NetdataLocalBind(s.Bind) || hostIP == "" {
+		return NetdataUpstream + "/"
+	}
+	return fmt.Sprintf("http://%s:%d/", hostIP, NetdataPort)
+}
+
+// NetdataManager configures a Netdata installed by the setup script
+type NetdataManager struct {
+	configPath string
+}
+
+// NewNetdataManager creates a Netdata manager
+func NewNetdataManager() *NetdataManager {
+	return &NetdataManager{configPath: NetdataConfigPath}
+}
+
+// IsInstalled checks if Netdata is installed
+func (dm *NetdataManager) IsInstalled() bool {
+	return Command("which", "netdata").Run() == nil
+}
+
+// Status reads Netdata's state and finds its reverse proxy site
+func (dm *NetdataManager) Status(nm *NginxManager) NetdataStatus {
+	st := NetdataStatus{Installed: dm.IsInstalled(), Service: "unknown", Bind: "*"}
+	if output, _ := Command("systemctl", "is-active", NetdataService).Output(); len(output) > 0 {
+		st.Service = strings.TrimSpace(string(output))
+	}
+	if data, err := ReadFile(dm.configPath); err == nil {
+		st.Bind = parseNetdataBind(string(data))
+	}
+	if sites, err := nm.GetAllSites(); err == nil {
+		for i := range sites {
+			if sites[i].Name == NetdataSiteName {
+				st.Proxy = &sites[i]
+			}
+		}
+	}
+	return st
+}
+
+// SetBind changes the addresses the dashboard listens on and restarts
+// Netdata. A missing config file is created with only this option.
+func (dm *NetdataManager) SetBind(bind string) error {
+	data, err := ReadFile(dm.configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", dm.configPath, err)
+	}
+	mode := os.FileMode(0644)
+	if info, err := Stat(dm.configPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := WriteFile(dm.configPath, []byte(setNetdataBind(string(data), bind)), mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dm.configPath, err)
+	}
+	if output, err := Command("systemctl", "restart", NetdataService).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to restart Netdata: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// PlanNetdataProxy returns the nginx site that proxies domains to the
+// dashboard behind basic auth from the site's htpasswd file
+func PlanNetdataProxy(nm *NginxManager, domains []string, useSSL, useCertbot bool) (NginxChange, error) {
+	opts := DefaultSiteOptions(NetdataUpstream)
+	opts.Proxy.WebSocket = true
+	change, err := nm.PlanCreateSiteWithOptions(NetdataSiteName, domains, "/var/www/html", "proxy", opts, useSSL, useCertbot)
+	if err != nil {
+		return NginxChange{}, err
+	}
+	change.New, err = ApplySiteAccess(change.New, []SiteAccessRule{{Path: "/", BasicAuth: true}}, nm.HtpasswdPath(NetdataSiteName))
+	if err != nil {
+		return NginxChange{}, err
+	}
+	return change, nil
+}
@@ -0,0 +1,76 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNetdataBind(t *testing.T) {
+	if bind := parseNetdataBind(""); bind != "*" || NetdataLocalBind(bind) {
+		t.Errorf("expected an unset bind to listen everywhere, got %q", bind)
+	}
+
+	conf := "[global]\n    run as user = netdata\n\n[web]\n    # bind to = *\n    enable gzip compression = yes\n"
+	updated := setNetdataBind(conf, "127.0.0.1")
+	if !strings.Contains(updated, "[web]\n    bind to = 127.0.0.1\n    enable gzip") || strings.Count(updated, "bind to") != 1 {
+		t.Errorf("expected the commented option to be replaced:\n%s", updated)
+	}
+	if bind := parseNetdataBind(updated); bind != "127.0.0.1" || !NetdataLocalBind(bind) {
+		t.Errorf("expected a loopback bind, got %q", bind)
+	}
+
+	added := setNetdataBind("[global]\n    history = 3600\n", "*")
+	if !strings.HasSuffix(added, "\n\n[web]\n    bind to = *\n") {
+		t.Errorf("expected a [web] section to be added:\n%s", added)
+	}
+	if created := setNetdataBind("", "localhost"); created != "[web]\n    bind to = localhost\n" {
+		t.Errorf("unexpected new config: %q", created)
+	}
+
+	for bind, local := range map[string]bool{
+		"localhost":                        true,
+		"127.0.0.1:19999 [::1]:19999":      true,
+		"127.0.0.1=dashboard unix:/x.sock": true,
+		"0.0.0.0":                          false,
+		"127.0.0.1 10.0.0.5":               false,
+	} {
+		if NetdataLocalBind(bind) != local {
+			t.Errorf("NetdataLocalBind(%q) = %v, want %v", bind, !local, local)
+		}
+	}
+}
+
+func TestNetdataDashboardURL(t *testing.T) {
+	st := NetdataStatus{Bind: "127.0.0.1"}
+	if url := st.DashboardURL("203.0.113.7"); url != "http://127.0.0.1:19999/" {
+		t.Errorf("expected the loopback URL, got %s", url)
+	}
+	st.Bind = "*"
+	if url := st.DashboardURL("203.0.113.7"); url != "http://203.0.113.7:19999/" {
+		t.Errorf("expected the host URL, got %s", url)
+	}
+	st.Proxy = &NginxSite{Domain: "stats.example.com", HasSSL: true}
+	if url := st.DashboardURL("203.0.113.7"); url != "https://stats.example.com/" {
+		t.Errorf("expected the proxy URL, got %s", url)
+	}
+}
+
+func TestPlanNetdataProxy(t *testing.T) {
+	dir := t.TempDir()
+	nm := NewNginxManager()
+	nm.sitesAvailable = filepath.Join(dir, "sites-available")
+	nm.mainConfig = filepath.Join(dir, "nginx.conf")
+	os.MkdirAll(nm.sitesAvailable, 0755)
+
+	change, err := PlanNetdataProxy(nm, []string{"stats.example.com"}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"server_name stats.example.com", "proxy_pass http://127.0.0.1:19999", "auth_basic_user_file " + nm.HtpasswdPath(NetdataSiteName)} {
+		if !strings.Contains(change.New, want) {
+			t.Errorf("config is missing %q:\n%s", want, change.New)
+		}
+	}
+}
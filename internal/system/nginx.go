@@ -4,16 +4,21 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+
+	"github.com/iperamuna/ravact/internal/stubs"
 )
 
 // NginxSite represents an Nginx site configuration
 type NginxSite struct {
 	Name       string
 	Domain     string
+	Domains    []string // Every server_name, Domain first
 	RootDir    string
 	IsEnabled  bool
 	HasSSL     bool
@@ -32,6 +37,230 @@ type NginxTemplate struct {
 	PublicDir      string   `json:"public_dir,omitempty"`
 	RecommendedFor []string `json:"recommended_for,omitempty"`
 	Notes          string   `json:"notes,omitempty"`
+	Upstream       string   `json:"upstream,omitempty"` // Default proxy target for proxy templates
+	Overridden     bool     `json:"-"`                  // A user stub replaces or adds this template
+}
+
+// nginxSiteStubPrefix names the stubs that hold each template's directives
+const nginxSiteStubPrefix = "nginx-site-"
+
+// defaultPHPSocket is the PHP-FPM socket PHP templates pass requests to
+const defaultPHPSocket = "unix:/var/run/php/php-fpm.sock"
+
+// defaultUpstream is the proxy target of user stubs that need one
+const defaultUpstream = "http://127.0.0.1:3000"
+
+// StubName returns the stub that holds the template's directives
+func (t NginxTemplate) StubName() string {
+	return nginxSiteStubPrefix + t.ID
+}
+
+// ProxyOptions are the settings of proxy templates
+type ProxyOptions struct {
+	Upstream       string // URL passed to proxy_pass
+	WebSocket      bool   // Pass Upgrade and Connection through for WebSocket clients
+	ConnectTimeout int    // Seconds; nginx caps this at 75
+	ReadTimeout    int    // Seconds
+}
+
+// DefaultProxyOptions returns the options proxy sites get unless chosen
+// otherwise
+func DefaultProxyOptions(upstream string) ProxyOptions {
+	return ProxyOptions{Upstream: upstream, ConnectTimeout: 75, ReadTimeout: 300}
+}
+
+// Validate checks the timeouts
+func (p ProxyOptions) Validate() error {
+	if p.ConnectTimeout < 1 || p.ConnectTimeout > 75 {
+		return fmt.Errorf("connect timeout must be between 1 and 75 seconds")
+	}
+	if p.ReadTimeout < 1 || p.ReadTimeout > 86400 {
+		return fmt.Errorf("read timeout must be between 1 and 86400 seconds")
+	}
+	return nil
+}
+
+// replacements returns the stub placeholders for the options
+func (p ProxyOptions) replacements() map[string]string {
+	websocket := ""
+	if p.WebSocket {
+		// $http_connection is "upgrade" for WebSocket handshakes and
+		// keep-alive otherwise, so plain requests are unaffected
+		websocket = "\n        proxy_set_header Upgrade $http_upgrade;\n        proxy_set_header Connection $http_connection;"
+	}
+	return map[string]string{
+		"UPSTREAM":              p.Upstream,
+		"PROXY_WEBSOCKET":       websocket,
+		"PROXY_CONNECT_TIMEOUT": strconv.Itoa(p.ConnectTimeout),
+		"PROXY_READ_TIMEOUT":    strconv.Itoa(p.ReadTimeout),
+	}
+}
+
+// proxyOptionsFromParams reads the options recorded with a generated
+// site, falling back to the defaults for sites recorded before they existed
+func proxyOptionsFromParams(params map[string]string) ProxyOptions {
+	p := DefaultProxyOptions(params["upstream"])
+	p.WebSocket, _ = strconv.ParseBool(params["websocket"])
+	if n, err := strconv.Atoi(params["connect_timeout"]); err == nil {
+		p.ConnectTimeout = n
+	}
+	if n, err := strconv.Atoi(params["read_timeout"]); err == nil {
+		p.ReadTimeout = n
+	}
+	return p
+}
+
+// StaticOptions are the caching and compression settings of static site
+// templates
+type StaticOptions struct {
+	AssetCacheDays int  // How long browsers cache assets; 0 makes them revalidate
+	Immutable      bool // Asset names carry content hashes, so they never change
+	HTMLNoCache    bool // Browsers revalidate pages so a deploy shows up at once
+	GzipStatic     bool // Serve the .gz copies made at build time
+	BrotliStatic   bool // Serve the .br copies; needs the nginx brotli module
+}
+
+// DefaultStaticOptions returns the options static sites get unless chosen
+// otherwise
+func DefaultStaticOptions() StaticOptions {
+	return StaticOptions{AssetCacheDays: 30}
+}
+
+// Validate checks the cache lifetime
+func (s StaticOptions) Validate() error {
+	if s.AssetCacheDays < 0 || s.AssetCacheDays > 365 {
+		return fmt.Errorf("asset cache must be between 0 and 365 days")
+	}
+	return nil
+}
+
+// replacements returns the stub placeholders for the options
+func (s StaticOptions) replacements() map[string]string {
+	var precompressed string
+	if s.GzipStatic {
+		precompressed += "    gzip_static on;\n"
+	}
+	if s.BrotliStatic {
+		precompressed += "    brotli_static on;\n"
+	}
+	if precompressed != "" {
+		precompressed = "    # Serve compressed copies made at build time\n" + precompressed + "\n"
+	}
+
+	htmlCache := ""
+	if s.HTMLNoCache {
+		htmlCache = "\n        add_header Cache-Control \"no-cache\";"
+	}
+
+	// expires sets Cache-Control itself, so immutable replaces it
+	assetCache := fmt.Sprintf("expires %dd;", s.AssetCacheDays)
+	switch {
+	case s.AssetCacheDays == 0:
+		assetCache = "add_header Cache-Control \"no-cache\";"
+	case s.Immutable:
+		assetCache = fmt.Sprintf("add_header Cache-Control \"public, max-age=%d, immutable\";", s.AssetCacheDays*86400)
+	}
+
+	return map[string]string{
+		"STATIC_PRECOMPRESSED": precompressed,
+		"STATIC_HTML_CACHE":    htmlCache,
+		"STATIC_ASSET_CACHE":   assetCache,
+	}
+}
+
+// SiteOptions are the template settings beyond the domains and root
+type SiteOptions struct {
+	Proxy  ProxyOptions
+	Static StaticOptions
+}
+
+// DefaultSiteOptions returns the defaults, proxying to upstream
+func DefaultSiteOptions(upstream string) SiteOptions {
+	return SiteOptions{Proxy: DefaultProxyOptions(upstream), Static: DefaultStaticOptions()}
+}
+
+// Validate checks the proxy and static options
+func (o SiteOptions) Validate() error {
+	if err := o.Proxy.Validate(); err != nil {
+		return err
+	}
+	return o.Static.Validate()
+}
+
+// params records the options with a generated site. Only proxy sites and
+// SPA sites record theirs, so other sites stay as they were.
+func (o SiteOptions) params(template string, params map[string]string) {
+	if o.Proxy.Upstream != "" {
+		params["websocket"] = strconv.FormatBool(o.Proxy.WebSocket)
+		params["connect_timeout"] = strconv.Itoa(o.Proxy.ConnectTimeout)
+		params["read_timeout"] = strconv.Itoa(o.Proxy.ReadTimeout)
+	}
+	if template == "spa" {
+		params["asset_cache_days"] = strconv.Itoa(o.Static.AssetCacheDays)
+		params["immutable"] = strconv.FormatBool(o.Static.Immutable)
+		params["html_no_cache"] = strconv.FormatBool(o.Static.HTMLNoCache)
+		params["gzip_static"] = strconv.FormatBool(o.Static.GzipStatic)
+		params["brotli_static"] = strconv.FormatBool(o.Static.BrotliStatic)
+	}
+}
+
+// siteOptionsFromParams reads the options recorded with a generated site,
+// falling back to the defaults for sites recorded before they existed
+func siteOptionsFromParams(params map[string]string) SiteOptions {
+	o := SiteOptions{Proxy: proxyOptionsFromParams(params), Static: DefaultStaticOptions()}
+	if n, err := strconv.Atoi(params["asset_cache_days"]); err == nil {
+		o.Static.AssetCacheDays = n
+	}
+	o.Static.Immutable, _ = strconv.ParseBool(params["immutable"])
+	o.Static.HTMLNoCache, _ = strconv.ParseBool(params["html_no_cache"])
+	o.Static.GzipStatic, _ = strconv.ParseBool(params["gzip_static"])
+	o.Static.BrotliStatic, _ = strconv.ParseBool(params["brotli_static"])
+	return o
+}
+
+// NginxHasBrotli reports whether nginx has the brotli module built in or
+// loaded, which brotli_static needs
+func NginxHasBrotli() bool {
+	output, _ := Command("sh", "-c", "nginx -V 2>&1; cat /etc/nginx/modules-enabled/*.conf 2>/dev/null").Output()
+	return strings.Contains(string(output), "brotli")
+}
+
+// NormalizeUpstream turns what a user types for an upstream into a
+// proxy_pass URL: a port (3000), host:port, a unix socket path, a URL, or
+// docker:NAME:PORT for the host port a container publishes
+func NormalizeUpstream(input string) (string, error) {
+	s := strings.TrimSpace(input)
+	if strings.ContainsAny(s, " \t;{}'\"") {
+		return "", fmt.Errorf("invalid upstream %q", s)
+	}
+	switch {
+	case s == "":
+		return "", nil
+	case strings.HasPrefix(s, "http://"), strings.HasPrefix(s, "https://"):
+		return s, nil
+	case strings.HasPrefix(s, "unix:"), strings.HasPrefix(s, "/"):
+		path := strings.TrimSuffix(strings.TrimPrefix(s, "unix:"), ":")
+		if !strings.HasPrefix(path, "/") {
+			return "", fmt.Errorf("socket path must be absolute: %s", path)
+		}
+		return "http://unix:" + path + ":", nil
+	case strings.HasPrefix(s, "docker:"):
+		name, port, ok := strings.Cut(strings.TrimPrefix(s, "docker:"), ":")
+		if !ok || name == "" {
+			return "", fmt.Errorf("use docker:NAME:PORT with the container port, e.g. docker:grafana:3000")
+		}
+		return ContainerUpstream(name, port)
+	}
+	if port, err := strconv.Atoi(s); err == nil {
+		if port < 1 || port > 65535 {
+			return "", fmt.Errorf("port must be between 1 and 65535")
+		}
+		return "http://127.0.0.1:" + s, nil
+	}
+	if _, port, err := net.SplitHostPort(s); err == nil && port != "" {
+		return "http://" + s, nil
+	}
+	return "", fmt.Errorf("enter a port, host:port, socket path, URL, or docker:NAME:PORT")
 }
 
 // NginxTemplatesConfig holds all templates
@@ -43,6 +272,7 @@ type NginxTemplatesConfig struct {
 type NginxManager struct {
 	sitesAvailable string
 	sitesEnabled   string
+	mainConfig     string
 	embeddedFS     *embed.FS
 	templates      []NginxTemplate
 }
@@ -52,6 +282,7 @@ func NewNginxManager() *NginxManager {
 	return &NginxManager{
 		sitesAvailable: "/etc/nginx/sites-available",
 		sitesEnabled:   "/etc/nginx/sites-enabled",
+		mainConfig:     "/etc/nginx/nginx.conf",
 		embeddedFS:     nil,
 		templates:      []NginxTemplate{},
 	}
@@ -83,14 +314,42 @@ func (nm *NginxManager) loadTemplates() {
 	nm.templates = config.Templates
 }
 
-// GetTemplates returns available nginx templates
+// GetTemplates returns available nginx templates: the built-in ones,
+// marked when a user stub overrides them, followed by templates added as
+// user stubs
 func (nm *NginxManager) GetTemplates() []NginxTemplate {
-	return nm.templates
+	templates := make([]NginxTemplate, 0, len(nm.templates))
+	known := map[string]bool{}
+	for _, t := range nm.templates {
+		t.Overridden = stubs.IsOverridden(t.StubName())
+		templates = append(templates, t)
+		known[t.ID] = true
+	}
+	for _, name := range stubs.Names(nginxSiteStubPrefix) {
+		id := strings.TrimPrefix(name, nginxSiteStubPrefix)
+		if known[id] || stubs.IsBuiltIn(name) {
+			continue
+		}
+		t := NginxTemplate{
+			ID:          id,
+			Name:        "Custom: " + id,
+			Description: "User stub " + stubs.OverridePath(name),
+			Overridden:  true,
+		}
+		if content, err := stubs.Load(name); err == nil {
+			t.RequiresPHP = strings.Contains(content, "{{PHP_SOCKET}}")
+			if strings.Contains(content, "{{UPSTREAM}}") {
+				t.Upstream = defaultUpstream
+			}
+		}
+		templates = append(templates, t)
+	}
+	return templates
 }
 
 // GetAllSites returns all available sites
 func (nm *NginxManager) GetAllSites() ([]NginxSite, error) {
-	entries, err := os.ReadDir(nm.sitesAvailable)
+	entries, err := ReadDir(nm.sitesAvailable)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return []NginxSite{}, nil
@@ -115,16 +374,17 @@ func (nm *NginxManager) GetAllSites() ([]NginxSite, error) {
 		// Check if enabled (symlink exists)
 		isEnabled := false
 		enabledPath := filepath.Join(nm.sitesEnabled, name)
-		if _, err := os.Lstat(enabledPath); err == nil {
+		if _, err := Lstat(enabledPath); err == nil {
 			isEnabled = true
 		}
 
 		// Parse config to get details
-		domain, rootDir, hasSSL, hasPHP := nm.parseConfig(configPath)
+		domains, rootDir, hasSSL, hasPHP := nm.parseConfig(configPath)
 
 		site := NginxSite{
 			Name:       name,
-			Domain:     domain,
+			Domain:     PrimaryDomain(domains),
+			Domains:    domains,
 			RootDir:    rootDir,
 			IsEnabled:  isEnabled,
 			HasSSL:     hasSSL,
@@ -139,10 +399,10 @@ func (nm *NginxManager) GetAllSites() ([]NginxSite, error) {
 }
 
 // parseConfig extracts basic info from nginx config
-func (nm *NginxManager) parseConfig(configPath string) (domain, rootDir string, hasSSL, hasPHP bool) {
-	data, err := os.ReadFile(configPath)
+func (nm *NginxManager) parseConfig(configPath string) (domains []string, rootDir string, hasSSL, hasPHP bool) {
+	data, err := ReadFile(configPath)
 	if err != nil {
-		return "", "", false, false
+		return nil, "", false, false
 	}
 
 	content := string(data)
@@ -151,11 +411,13 @@ func (nm *NginxManager) parseConfig(configPath string) (domain, rootDir string,
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 
-		// Extract server_name
+		// Extract server_name, collecting names across server blocks
 		if strings.HasPrefix(line, "server_name") {
-			parts := strings.Fields(line)
-			if len(parts) >= 2 {
-				domain = strings.TrimSuffix(parts[1], ";")
+			for _, name := range strings.Fields(strings.TrimSuffix(line, ";"))[1:] {
+				name = strings.TrimSuffix(name, ";")
+				if name != "" && name != "_" && !slices.Contains(domains, name) {
+					domains = append(domains, name)
+				}
 			}
 		}
 
@@ -173,7 +435,7 @@ func (nm *NginxManager) parseConfig(configPath string) (domain, rootDir string,
 		}
 	}
 
-	return domain, rootDir, hasSSL, hasPHP
+	return domains, rootDir, hasSSL, hasPHP
 }
 
 // EnableSite enables a site by creating symlink
@@ -182,12 +444,12 @@ func (nm *NginxManager) EnableSite(siteName string) error {
 	enabledPath := filepath.Join(nm.sitesEnabled, siteName)
 
 	// Check if site exists
-	if _, err := os.Stat(availablePath); os.IsNotExist(err) {
+	if _, err := Stat(availablePath); os.IsNotExist(err) {
 		return fmt.Errorf("site not found: %s", siteName)
 	}
 
 	// Create symlink
-	if err := os.Symlink(availablePath, enabledPath); err != nil {
+	if err := Symlink(availablePath, enabledPath); err != nil {
 		return fmt.Errorf("failed to enable site: %w", err)
 	}
 
@@ -199,7 +461,7 @@ func (nm *NginxManager) DisableSite(siteName string) error {
 	enabledPath := filepath.Join(nm.sitesEnabled, siteName)
 
 	// Remove symlink
-	if err := os.Remove(enabledPath); err != nil {
+	if err := Remove(enabledPath); err != nil {
 		return fmt.Errorf("failed to disable site: %w", err)
 	}
 
@@ -208,7 +470,7 @@ func (nm *NginxManager) DisableSite(siteName string) error {
 
 // TestConfig tests nginx configuration
 func (nm *NginxManager) TestConfig() error {
-	cmd := exec.Command("nginx", "-t")
+	cmd := Command("nginx", "-t")
 	output, err := cmd.CombinedOutput()
 
 	if err != nil {
@@ -220,7 +482,7 @@ func (nm *NginxManager) TestConfig() error {
 
 // ReloadNginx reloads nginx configuration
 func (nm *NginxManager) ReloadNginx() error {
-	cmd := exec.Command("systemctl", "reload", "nginx")
+	cmd := Command("systemctl", "reload", "nginx")
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to reload nginx: %w", err)
 	}
@@ -229,28 +491,73 @@ func (nm *NginxManager) ReloadNginx() error {
 }
 
 // CreateSite creates a new site configuration
-func (nm *NginxManager) CreateSite(siteName, domain, rootDir, template string, useSSL, useCertbot bool) error {
+func (nm *NginxManager) CreateSite(siteName string, domains []string, rootDir, template, upstream string, useSSL, useCertbot bool) error {
+	change, err := nm.PlanCreateSite(siteName, domains, rootDir, template, upstream, useSSL, useCertbot)
+	if err != nil {
+		return err
+	}
+	return nm.ApplyChange(change)
+}
+
+// PlanCreateSite returns the config CreateSite would write. domains are the
+// server_names, already validated by ParseDomains; the first one names the
+// logs and certificate. upstream is the proxy target of proxy templates and
+// ignored by the others.
+func (nm *NginxManager) PlanCreateSite(siteName string, domains []string, rootDir, template, upstream string, useSSL, useCertbot bool) (NginxChange, error) {
+	return nm.PlanCreateSiteWithOptions(siteName, domains, rootDir, template, DefaultSiteOptions(upstream), useSSL, useCertbot)
+}
+
+// PlanCreateSiteWithOptions is PlanCreateSite with the settings of proxy
+// and static templates
+func (nm *NginxManager) PlanCreateSiteWithOptions(siteName string, domains []string, rootDir, template string, opts SiteOptions, useSSL, useCertbot bool) (NginxChange, error) {
 	configPath := filepath.Join(nm.sitesAvailable, siteName)
 
+	if len(domains) == 0 {
+		return NginxChange{}, fmt.Errorf("at least one domain is required")
+	}
+	if useCertbot && HasWildcard(domains) {
+		return NginxChange{}, fmt.Errorf("certbot --nginx cannot issue wildcard certificates; use a manual certificate from a DNS challenge")
+	}
+
 	// Check if site already exists
-	if _, err := os.Stat(configPath); err == nil {
-		return fmt.Errorf("site already exists: %s", siteName)
+	if _, err := Stat(configPath); err == nil {
+		return NginxChange{}, fmt.Errorf("site already exists: %s", siteName)
 	}
 
 	// Generate config based on template and options
-	config := nm.generateConfig(domain, rootDir, template, useSSL, useCertbot)
-
-	// Write config file
-	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
+	if err := opts.Validate(); err != nil {
+		return NginxChange{}, err
 	}
-
-	return nil
+	directives, err := nm.getTemplateDirectives(template, PrimaryDomain(domains), rootDir, opts)
+	if err != nil {
+		return NginxChange{}, err
+	}
+	config := nm.generateConfig(domains, rootDir, directives, useSSL, useCertbot)
+
+	generated := &GeneratedConfig{
+		Path:      configPath,
+		Owner:     siteName,
+		Generator: GeneratorNginxSite,
+		Params: map[string]string{
+			"domains":  strings.Join(domains, " "),
+			"root":     rootDir,
+			"template": template,
+			"upstream": opts.Proxy.Upstream,
+			"ssl":      strconv.FormatBool(useSSL),
+			"certbot":  strconv.FormatBool(useCertbot),
+		},
+	}
+	opts.params(template, generated.Params)
+	return NginxChange{SiteName: siteName, Path: configPath, New: config, IsNew: true, Generated: generated}, nil
 }
 
-// generateConfig generates nginx configuration based on parameters
-func (nm *NginxManager) generateConfig(domain, rootDir, template string, useSSL, useCertbot bool) string {
+// generateConfig generates nginx configuration based on parameters.
+// Redirects use $host rather than $server_name, which is only the first
+// name (and may be a wildcard).
+func (nm *NginxManager) generateConfig(domains []string, rootDir, directives string, useSSL, useCertbot bool) string {
 	var config strings.Builder
+	serverName := strings.Join(domains, " ")
+	domain := PrimaryDomain(domains)
 
 	if !useSSL {
 		// HTTP only
@@ -266,10 +573,10 @@ func (nm *NginxManager) generateConfig(domain, rootDir, template string, useSSL,
     access_log /var/log/nginx/%s-access.log;
     error_log /var/log/nginx/%s-error.log;
 
-`, domain, rootDir, domain, domain))
+`, serverName, rootDir, domain, domain))
 
 		// Add template-specific directives
-		config.WriteString(nm.getTemplateDirectives(template))
+		config.WriteString(directives)
 
 		config.WriteString("}\n")
 	} else if useCertbot {
@@ -286,7 +593,7 @@ func (nm *NginxManager) generateConfig(domain, rootDir, template string, useSSL,
 
     # Redirect all other HTTP traffic to HTTPS
     location / {
-        return 301 https://$server_name$request_uri;
+        return 301 https://$host$request_uri;
     }
 }
 
@@ -311,10 +618,10 @@ server {
     access_log /var/log/nginx/%s-access.log;
     error_log /var/log/nginx/%s-error.log;
 
-`, domain, rootDir, domain, rootDir, domain, domain, domain, domain))
+`, serverName, rootDir, serverName, rootDir, domain, domain, domain, domain))
 
 		// Add template-specific directives
-		config.WriteString(nm.getTemplateDirectives(template))
+		config.WriteString(directives)
 
 		config.WriteString("}\n")
 	} else {
@@ -325,7 +632,7 @@ server {
     server_name %s;
 
     # Redirect to HTTPS
-    return 301 https://$server_name$request_uri;
+    return 301 https://$host$request_uri;
 }
 
 server {
@@ -352,10 +659,10 @@ server {
     access_log /var/log/nginx/%s-access.log;
     error_log /var/log/nginx/%s-error.log;
 
-`, domain, domain, rootDir, domain, domain))
+`, serverName, serverName, rootDir, domain, domain))
 
 		// Add template-specific directives
-		config.WriteString(nm.getTemplateDirectives(template))
+		config.WriteString(directives)
 
 		config.WriteString("}\n")
 	}
@@ -363,78 +670,32 @@ server {
 	return config.String()
 }
 
-// getTemplateDirectives returns nginx directives for specific templates
-func (nm *NginxManager) getTemplateDirectives(template string) string {
-	switch template {
-	case "php":
-		return `    # PHP Configuration
-    location ~ \.php$ {
-        include snippets/fastcgi-php.conf;
-        fastcgi_pass unix:/var/run/php/php-fpm.sock;
-    }
-
-    location ~ /\.ht {
-        deny all;
-    }
-
-`
-	case "laravel":
-		return `    # Laravel Configuration
-    location / {
-        try_files $uri $uri/ /index.php?$query_string;
-    }
-
-    location ~ \.php$ {
-        include snippets/fastcgi-php.conf;
-        fastcgi_pass unix:/var/run/php/php-fpm.sock;
-        fastcgi_param SCRIPT_FILENAME $realpath_root$fastcgi_script_name;
-    }
-
-    location ~ /\.(?!well-known).* {
-        deny all;
-    }
-
-`
-	case "wordpress":
-		return `    # WordPress Configuration
-    location / {
-        try_files $uri $uri/ /index.php?$args;
-    }
-
-    location ~ \.php$ {
-        include snippets/fastcgi-php.conf;
-        fastcgi_pass unix:/var/run/php/php-fpm.sock;
-    }
-
-    location ~ /\.ht {
-        deny all;
-    }
-
-    location = /favicon.ico {
-        log_not_found off;
-        access_log off;
-    }
-
-    location = /robots.txt {
-        allow all;
-        log_not_found off;
-        access_log off;
-    }
-
-    location ~* \.(js|css|png|jpg|jpeg|gif|ico)$ {
-        expires max;
-        log_not_found off;
-    }
-
-`
-	default:
-		return `    # Static file configuration
-    location / {
-        try_files $uri $uri/ =404;
-    }
-
-`
+// getTemplateDirectives renders the template's stub, preferring a user
+// override from the stubs directory
+func (nm *NginxManager) getTemplateDirectives(template, domain, rootDir string, opts SiteOptions) (string, error) {
+	t := NginxTemplate{ID: template}
+	if template == "" {
+		t.ID = "static"
 	}
+	stub, err := stubs.Load(t.StubName())
+	if err != nil {
+		return "", fmt.Errorf("no stub for template %q (%s)", template, stubs.OverridePath(t.StubName()))
+	}
+	if opts.Proxy.Upstream == "" && strings.Contains(stub, "{{UPSTREAM}}") {
+		return "", fmt.Errorf("template %q needs an upstream such as %s", template, defaultUpstream)
+	}
+	replacements := opts.Proxy.replacements()
+	for key, value := range opts.Static.replacements() {
+		replacements[key] = value
+	}
+	replacements["DOMAIN"] = domain
+	replacements["ROOT"] = rootDir
+	replacements["PHP_SOCKET"] = defaultPHPSocket
+	content, err := stubs.LoadAndReplace(t.StubName(), replacements)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(content, "\n") + "\n\n", nil
 }
 
 // DeleteSite deletes a site configuration
@@ -444,16 +705,24 @@ func (nm *NginxManager) DeleteSite(siteName string) error {
 
 	// Delete config file
 	configPath := filepath.Join(nm.sitesAvailable, siteName)
-	if err := os.Remove(configPath); err != nil {
+	if err := Remove(configPath); err != nil {
 		return fmt.Errorf("failed to delete site: %w", err)
 	}
+	_ = ForgetGeneratedConfig(configPath)
 
 	return nil
 }
 
-// ObtainSSLCertificate obtains SSL certificate using certbot
-func (nm *NginxManager) ObtainSSLCertificate(domain string) error {
-	cmd := exec.Command("certbot", "--nginx", "-d", domain, "--non-interactive", "--agree-tos", "--email", "admin@"+domain)
+// ObtainSSLCertificate obtains one SSL certificate covering every domain
+// using certbot, named after the first so it matches the config paths
+func (nm *NginxManager) ObtainSSLCertificate(domains []string) error {
+	if len(domains) == 0 {
+		return fmt.Errorf("at least one domain is required")
+	}
+	if HasWildcard(domains) {
+		return fmt.Errorf("certbot --nginx cannot issue wildcard certificates; use a manual certificate from a DNS challenge")
+	}
+	cmd := Command("certbot", CertbotArgs(domains)...)
 	output, err := cmd.CombinedOutput()
 
 	if err != nil {
@@ -463,21 +732,41 @@ func (nm *NginxManager) ObtainSSLCertificate(domain string) error {
 	return nil
 }
 
+// CertbotArgs returns the certbot --nginx arguments for a certificate
+// covering domains
+func CertbotArgs(domains []string) []string {
+	domain := PrimaryDomain(domains)
+	args := []string{"--nginx", "--cert-name", domain}
+	for _, d := range domains {
+		args = append(args, "-d", d)
+	}
+	return append(args, "--non-interactive", "--agree-tos", "--email", "admin@"+domain)
+}
+
 // AddSSLManual adds manual SSL certificates to a site
 func (nm *NginxManager) AddSSLManual(siteName, certPath, keyPath, chainPath string) error {
+	change, err := nm.PlanAddSSLManual(siteName, certPath, keyPath, chainPath)
+	if err != nil {
+		return err
+	}
+	return nm.ApplyChange(change)
+}
+
+// PlanAddSSLManual returns the config AddSSLManual would write
+func (nm *NginxManager) PlanAddSSLManual(siteName, certPath, keyPath, chainPath string) (NginxChange, error) {
 	configPath := filepath.Join(nm.sitesAvailable, siteName)
 
 	// Read existing config
-	content, err := os.ReadFile(configPath)
+	content, err := ReadFile(configPath)
 	if err != nil {
-		return fmt.Errorf("failed to read site config: %w", err)
+		return NginxChange{}, fmt.Errorf("failed to read site config: %w", err)
 	}
 
 	config := string(content)
 
 	// Check if already has SSL
 	if strings.Contains(config, "ssl_certificate") {
-		return fmt.Errorf("site already has SSL configured")
+		return NginxChange{}, fmt.Errorf("site already has SSL configured")
 	}
 
 	// Find server block and add SSL directives
@@ -503,23 +792,26 @@ func (nm *NginxManager) AddSSLManual(siteName, certPath, keyPath, chainPath stri
 	}
 	config = strings.Join(newLines, "\n")
 
-	// Write updated config
-	err = os.WriteFile(configPath, []byte(config), 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
-	}
-
-	return nil
+	return NginxChange{SiteName: siteName, Path: configPath, Old: string(content), New: config}, nil
 }
 
 // RemoveSSL removes SSL configuration from a site
 func (nm *NginxManager) RemoveSSL(siteName string) error {
+	change, err := nm.PlanRemoveSSL(siteName)
+	if err != nil {
+		return err
+	}
+	return nm.ApplyChange(change)
+}
+
+// PlanRemoveSSL returns the config RemoveSSL would write
+func (nm *NginxManager) PlanRemoveSSL(siteName string) (NginxChange, error) {
 	configPath := filepath.Join(nm.sitesAvailable, siteName)
 
 	// Read existing config
-	content, err := os.ReadFile(configPath)
+	content, err := ReadFile(configPath)
 	if err != nil {
-		return fmt.Errorf("failed to read site config: %w", err)
+		return NginxChange{}, fmt.Errorf("failed to read site config: %w", err)
 	}
 
 	config := string(content)
@@ -567,11 +859,5 @@ func (nm *NginxManager) RemoveSSL(siteName string) error {
 	// Clean up extra blank lines
 	config = strings.ReplaceAll(config, "\n\n\n", "\n\n")
 
-	// Write updated config
-	err = os.WriteFile(configPath, []byte(config), 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
-	}
-
-	return nil
+	return NginxChange{SiteName: siteName, Path: configPath, Old: string(content), New: config}, nil
 }
@@ -0,0 +1,385 @@
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SiteAccessRule protects a whole site or one path of it with HTTP basic
+// auth, an IP allowlist, or both
+type SiteAccessRule struct {
+	Path       string   // "/" for the whole site, else a prefix such as /admin
+	BasicAuth  bool     // Ask for a user from the site's htpasswd file
+	AllowIPs   []string // Addresses or CIDR ranges; everyone else is denied
+	SatisfyAny bool     // A listed IP or a password gets in, rather than both
+}
+
+// Markers around an access rule ravact manages in a site
+const (
+	accessStart = "# ravact: access "
+	accessEnd   = "# ravact: end access"
+)
+
+// accessRealm is the prompt browsers show for basic auth
+const accessRealm = "Restricted"
+
+// Validate checks the path and allowlist of a rule
+func (r SiteAccessRule) Validate() error {
+	if !strings.HasPrefix(r.Path, "/") || strings.ContainsAny(r.Path, " \t{};\"'#$\\") {
+		return fmt.Errorf("path must start with / and contain no spaces or quotes, e.g. /admin")
+	}
+	if !r.BasicAuth && len(r.AllowIPs) == 0 {
+		return fmt.Errorf("choose basic auth, allowed IPs, or both")
+	}
+	for _, ip := range r.AllowIPs {
+		if net.ParseIP(ip) == nil {
+			if _, _, err := net.ParseCIDR(ip); err != nil {
+				return fmt.Errorf("%q is not an IP address or CIDR range", ip)
+			}
+		}
+	}
+	if r.SatisfyAny && (!r.BasicAuth || len(r.AllowIPs) == 0) {
+		return fmt.Errorf("\"IP or password\" needs both basic auth and allowed IPs")
+	}
+	return nil
+}
+
+// ParseAllowIPs splits a comma or space separated list of addresses
+func ParseAllowIPs(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' || r == '\n' })
+}
+
+// Describe summarizes the protection of a rule
+func (r SiteAccessRule) Describe() string {
+	var parts []string
+	if r.BasicAuth {
+		parts = append(parts, "password")
+	}
+	if len(r.AllowIPs) > 0 {
+		parts = append(parts, strings.Join(r.AllowIPs, ", "))
+	}
+	joiner := " and "
+	if r.SatisfyAny {
+		joiner = " or "
+	}
+	return strings.Join(parts, joiner)
+}
+
+// directives returns the auth and allowlist directives of the rule
+func (r SiteAccessRule) directives(indent, userFile string) []string {
+	var lines []string
+	if r.BasicAuth {
+		lines = append(lines,
+			indent+"auth_basic \""+accessRealm+"\";",
+			indent+"auth_basic_user_file "+userFile+";")
+	}
+	for _, ip := range r.AllowIPs {
+		lines = append(lines, indent+"allow "+ip+";")
+	}
+	if len(r.AllowIPs) > 0 {
+		lines = append(lines, indent+"deny all;")
+	}
+	if r.SatisfyAny {
+		lines = append(lines, indent+"satisfy any;")
+	}
+	return lines
+}
+
+// ParseSiteAccess reads the access rules ravact manages in a site config.
+// Each rule is written to every server block, so the first copy is used.
+func ParseSiteAccess(config string) []SiteAccessRule {
+	var rules []SiteAccessRule
+	var current *SiteAccessRule
+	depth := 0
+	for _, line := range strings.Split(config, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if path, ok := strings.CutPrefix(trimmed, accessStart); ok {
+			current = &SiteAccessRule{Path: path}
+			depth = 0
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if trimmed == accessEnd {
+			if !slices.ContainsFunc(rules, func(r SiteAccessRule) bool { return r.Path == current.Path }) {
+				rules = append(rules, *current)
+			}
+			current = nil
+			continue
+		}
+		code, _, _ := strings.Cut(trimmed, "#")
+		// Only the directives of the rule itself: the protected location
+		// for a path, or the server level for the whole site
+		level := 0
+		if current.Path != "/" {
+			level = 1
+		}
+		if depth == level {
+			fields := strings.Fields(strings.TrimSuffix(code, ";"))
+			switch {
+			case len(fields) == 2 && fields[0] == "auth_basic" && fields[1] != "off":
+				current.BasicAuth = true
+			case len(fields) == 2 && fields[0] == "allow" && fields[1] != "all":
+				current.AllowIPs = append(current.AllowIPs, fields[1])
+			case len(fields) == 2 && fields[0] == "satisfy":
+				current.SatisfyAny = fields[1] == "any"
+			}
+		}
+		depth += strings.Count(code, "{") - strings.Count(code, "}")
+	}
+	return rules
+}
+
+// stripMarked removes the managed blocks between start and end markers,
+// and the blank line written before each
+func stripMarked(lines []string, start, end string) []string {
+	var out []string
+	inBlock := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, start):
+			inBlock = true
+			if len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+				out = out[:len(out)-1]
+			}
+		case inBlock:
+			inBlock = trimmed != end
+		default:
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// blockEnd returns the line closing the block opened on line start
+func blockEnd(lines []string, start int) int {
+	depth := 0
+	for i := start; i < len(lines); i++ {
+		code, _, _ := strings.Cut(lines[i], "#")
+		depth += strings.Count(code, "{") - strings.Count(code, "}")
+		if depth == 0 {
+			return i
+		}
+	}
+	return len(lines) - 1
+}
+
+// ApplySiteAccess rewrites a site config for the given rules. The whole
+// site rule goes at the server level; path rules become ^~ locations that
+// repeat the site's location / and PHP handling so the path still works.
+// Blocks without a document root only redirect and are left alone.
+func ApplySiteAccess(config string, rules []SiteAccessRule, userFile string) (string, error) {
+	seen := map[string]bool{}
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			return "", fmt.Errorf("%s: %w", r.Path, err)
+		}
+		if seen[r.Path] {
+			return "", fmt.Errorf("%s has more than one rule", r.Path)
+		}
+		seen[r.Path] = true
+	}
+
+	lines := stripMarked(strings.Split(config, "\n"), accessStart, accessEnd)
+	blocks := findServerBlocks(lines)
+	// Rewrite from the last block so earlier line numbers stay valid
+	for bi := len(blocks) - 1; bi >= 0; bi-- {
+		lines = applyAccessToBlock(lines, blocks[bi], rules, userFile)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// applyAccessToBlock writes the rules into one server block
+func applyAccessToBlock(lines []string, b serverBlock, rules []SiteAccessRule, userFile string) []string {
+	indent := "    "
+	rootLine := -1
+	var rootLocation, phpLocation []string
+	for _, i := range blockDirectives(lines, b) {
+		trimmed := strings.TrimSpace(lines[i])
+		switch directiveName(trimmed) {
+		case "root":
+			rootLine = i
+			indent = lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " \t"))]
+		case "location":
+			fields := strings.Fields(trimmed)
+			end := blockEnd(lines, i)
+			switch {
+			case len(fields) >= 2 && fields[1] == "/":
+				rootLocation = lines[i+1 : end]
+			case strings.Contains(trimmed, `\.php`):
+				phpLocation = lines[i : end+1]
+			}
+		}
+	}
+	if rootLine < 0 {
+		return lines
+	}
+
+	var site, paths []string
+	for _, r := range rules {
+		if r.Path == "/" {
+			site = append(site, indent+accessStart+r.Path)
+			site = append(site, r.directives(indent, userFile)...)
+			site = append(site,
+				"",
+				indent+"# Certificate renewals must reach the challenge files",
+				indent+"location ^~ /.well-known/acme-challenge/ {",
+				indent+"    auth_basic off;",
+				indent+"    allow all;",
+				indent+"}",
+				indent+accessEnd)
+			continue
+		}
+		paths = append(paths, "", indent+accessStart+r.Path, indent+"location ^~ "+r.Path+" {")
+		paths = append(paths, r.directives(indent+"    ", userFile)...)
+		if len(rootLocation) > 0 {
+			paths = append(paths, "")
+			paths = append(paths, rootLocation...)
+		}
+		if len(phpLocation) > 0 {
+			paths = append(paths, "")
+			for _, line := range phpLocation {
+				paths = append(paths, "    "+line)
+			}
+		}
+		paths = append(paths, indent+"}", indent+accessEnd)
+	}
+
+	var out []string
+	out = append(out, lines[:rootLine+1]...)
+	if len(site) > 0 {
+		out = append(out, "")
+		out = append(out, site...)
+	}
+	body := lines[rootLine+1 : b.end]
+	if len(paths) > 0 {
+		// Drop blank lines before the closing brace so the block ends tidily
+		for len(body) > 0 && strings.TrimSpace(body[len(body)-1]) == "" {
+			body = body[:len(body)-1]
+		}
+	}
+	out = append(out, body...)
+	out = append(out, paths...)
+	return append(out, lines[b.end:]...)
+}
+
+// HtpasswdPath is the basic auth user file of a site
+func (nm *NginxManager) HtpasswdPath(siteName string) string {
+	return filepath.Join(filepath.Dir(nm.mainConfig), "htpasswd", siteName)
+}
+
+// SiteAccess reads the access rules of a site
+func (nm *NginxManager) SiteAccess(siteName string) ([]SiteAccessRule, error) {
+	content, err := ReadFile(filepath.Join(nm.sitesAvailable, siteName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read site config: %w", err)
+	}
+	return ParseSiteAccess(string(content)), nil
+}
+
+// PlanSiteAccess returns the config change for a site's access rules
+func (nm *NginxManager) PlanSiteAccess(siteName string, rules []SiteAccessRule) (NginxChange, error) {
+	configPath := filepath.Join(nm.sitesAvailable, siteName)
+	content, err := ReadFile(configPath)
+	if err != nil {
+		return NginxChange{}, fmt.Errorf("failed to read site config: %w", err)
+	}
+	config, err := ApplySiteAccess(string(content), rules, nm.HtpasswdPath(siteName))
+	if err != nil {
+		return NginxChange{}, err
+	}
+	return NginxChange{SiteName: siteName, Path: configPath, Old: string(content), New: config}, nil
+}
+
+// Htpasswd is a basic auth user file of user:hash lines
+type Htpasswd struct {
+	Path    string
+	entries [][2]string
+}
+
+// LoadHtpasswd reads a user file; a missing file has no users
+func LoadHtpasswd(path string) (*Htpasswd, error) {
+	h := &Htpasswd{Path: path}
+	data, err := ReadFile(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		if user, hash, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":"); ok && user != "" {
+			h.entries = append(h.entries, [2]string{user, hash})
+		}
+	}
+	return h, nil
+}
+
+// Users returns the user names in file order
+func (h *Htpasswd) Users() []string {
+	var users []string
+	for _, e := range h.entries {
+		users = append(users, e[0])
+	}
+	return users
+}
+
+// Set adds a user or changes their password. The password is hashed with
+// openssl's apr1, which every nginx build accepts.
+func (h *Htpasswd) Set(user, password string) error {
+	if user == "" || strings.ContainsAny(user, ": \t") {
+		return fmt.Errorf("user name must not be empty or contain spaces or colons")
+	}
+	if len(password) < 8 {
+		return fmt.Errorf("password must be at least 8 characters")
+	}
+	cmd := Command("openssl", "passwd", "-apr1", "-stdin")
+	cmd.Stdin = strings.NewReader(password + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to hash password with openssl: %w", err)
+	}
+	hash := strings.TrimSpace(string(output))
+	for i, e := range h.entries {
+		if e[0] == user {
+			h.entries[i][1] = hash
+			return nil
+		}
+	}
+	h.entries = append(h.entries, [2]string{user, hash})
+	return nil
+}
+
+// Remove deletes a user
+func (h *Htpasswd) Remove(user string) {
+	h.entries = slices.DeleteFunc(h.entries, func(e [2]string) bool { return e[0] == user })
+}
+
+// Save writes the file readable by root and group, which should be the
+// user nginx workers run as
+func (h *Htpasswd) Save(group string) error {
+	if err := MkdirAll(filepath.Dir(h.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(h.Path), err)
+	}
+	var b strings.Builder
+	for _, e := range h.entries {
+		b.WriteString(e[0] + ":" + e[1] + "\n")
+	}
+	if err := WriteFile(h.Path, []byte(b.String()), 0640); err != nil {
+		return fmt.Errorf("failed to write %s: %w", h.Path, err)
+	}
+	if group != "" {
+		if output, err := Command("chgrp", group, h.Path).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to set the group of %s: %s", h.Path, strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
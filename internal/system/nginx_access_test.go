@@ -0,0 +1,125 @@
+package system
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const accessTestConfig = `server {
+    listen 80;
+    server_name shop.com;
+
+    # Redirect to HTTPS
+    return 301 https://$host$request_uri;
+}
+
+server {
+    listen 443 ssl;
+    server_name shop.com;
+
+    root /var/www/shop/public;
+    index index.php;
+
+    location / {
+        try_files $uri $uri/ /index.php?$query_string;
+    }
+
+    location ~ \.php$ {
+        include snippets/fastcgi-php.conf;
+        fastcgi_pass unix:/run/php/php8.3-fpm.sock;
+    }
+}
+`
+
+func TestApplySiteAccess(t *testing.T) {
+	rules := []SiteAccessRule{
+		{Path: "/", AllowIPs: []string{"203.0.113.0/24"}},
+		{Path: "/admin", BasicAuth: true, AllowIPs: []string{"198.51.100.7"}, SatisfyAny: true},
+	}
+	config, err := ApplySiteAccess(accessTestConfig, rules, "/etc/nginx/htpasswd/shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"    root /var/www/shop/public;\n\n    # ravact: access /\n    allow 203.0.113.0/24;\n    deny all;\n",
+		"    location ^~ /admin {\n        auth_basic \"Restricted\";\n        auth_basic_user_file /etc/nginx/htpasswd/shop;\n        allow 198.51.100.7;\n        deny all;\n        satisfy any;\n",
+		"        try_files $uri $uri/ /index.php?$query_string;\n\n        location ~ \\.php$ {\n            include snippets/fastcgi-php.conf;",
+		"    }\n    # ravact: end access\n}\n",
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("config missing %q:\n%s", want, config)
+		}
+	}
+	if strings.Count(config, "# ravact: access /admin") != 1 {
+		t.Errorf("the redirect-only block should not be protected:\n%s", config)
+	}
+	if got := ParseSiteAccess(config); !reflect.DeepEqual(got, rules) {
+		t.Errorf("ParseSiteAccess() = %+v, want %+v", got, rules)
+	}
+
+	// Applying again is stable, and no rules restores the original
+	again, _ := ApplySiteAccess(config, rules, "/etc/nginx/htpasswd/shop")
+	if again != config {
+		t.Errorf("expected applying twice to be stable:\n%s", UnifiedDiff("a", "b", config, again))
+	}
+	if off, _ := ApplySiteAccess(config, nil, "/etc/nginx/htpasswd/shop"); off != accessTestConfig {
+		t.Errorf("expected removing all rules to restore the config:\n%s", UnifiedDiff("a", "b", accessTestConfig, off))
+	}
+}
+
+func TestSiteAccessRule_Validate(t *testing.T) {
+	for _, r := range []SiteAccessRule{
+		{Path: "admin", BasicAuth: true},
+		{Path: "/ad min", BasicAuth: true},
+		{Path: "/admin"},
+		{Path: "/admin", AllowIPs: []string{"not-an-ip"}},
+		{Path: "/admin", BasicAuth: true, SatisfyAny: true},
+	} {
+		if r.Validate() == nil {
+			t.Errorf("expected %+v to be rejected", r)
+		}
+	}
+	if _, err := ApplySiteAccess(accessTestConfig, []SiteAccessRule{{Path: "/a", BasicAuth: true}, {Path: "/a", BasicAuth: true}}, "x"); err == nil {
+		t.Error("expected an error for two rules on one path")
+	}
+}
+
+func TestHtpasswd(t *testing.T) {
+	if _, err := exec.LookPath("openssl"); err != nil {
+		t.Skip("openssl not installed")
+	}
+	path := filepath.Join(t.TempDir(), "htpasswd", "shop")
+	h, err := LoadHtpasswd(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Set("bad:user", "password123"); err == nil {
+		t.Error("expected an error for a colon in the user name")
+	}
+	if err := h.Set("alice", "short"); err == nil {
+		t.Error("expected an error for a short password")
+	}
+	for _, user := range []string{"alice", "bob", "alice"} {
+		if err := h.Set(user, "correct horse"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.Remove("bob")
+	if err := h.Save(""); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadHtpasswd(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Users(); !reflect.DeepEqual(got, []string{"alice"}) {
+		t.Errorf("Users() = %v, want [alice]", got)
+	}
+	if !strings.HasPrefix(loaded.entries[0][1], "$apr1$") {
+		t.Errorf("expected an apr1 hash, got %q", loaded.entries[0][1])
+	}
+}
@@ -0,0 +1,369 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// NginxBuild describes what the installed nginx binary supports
+type NginxBuild struct {
+	Version string
+	HTTP2   bool
+	HTTP3   bool
+	Gzip    bool
+	Brotli  bool // ngx_brotli, built in or loaded as a dynamic module
+}
+
+// NginxProtocols are the per-site protocol and compression settings
+type NginxProtocols struct {
+	HTTP2  bool
+	HTTP3  bool
+	Gzip   bool
+	Brotli bool
+}
+
+// Markers around the compression directives ravact manages in a site
+const (
+	compressionStart = "# ravact: compression"
+	compressionEnd   = "# ravact: end compression"
+)
+
+// compressionTypes are the MIME types worth compressing; text/html is
+// always compressed by nginx
+const compressionTypes = "text/plain text/css text/xml text/javascript application/javascript application/json application/xml application/rss+xml image/svg+xml"
+
+var (
+	nginxVersionPattern = regexp.MustCompile(`nginx/(\d+\.\d+\.\d+)`)
+	serverBlockPattern  = regexp.MustCompile(`^\s*server\s*\{`)
+	gzipOnPattern       = regexp.MustCompile(`(?m)^\s*gzip\s+on;`)
+)
+
+// DetectNginxBuild inspects `nginx -V` and the loaded dynamic modules
+func (nm *NginxManager) DetectNginxBuild() (NginxBuild, error) {
+	output, err := Command("nginx", "-V").CombinedOutput()
+	if err != nil {
+		return NginxBuild{}, fmt.Errorf("failed to run nginx -V: %w", err)
+	}
+	build := parseNginxBuild(string(output))
+	if !build.Brotli {
+		// Debian's libnginx-mod-http-brotli packages load through modules-enabled
+		dir := filepath.Join(filepath.Dir(nm.mainConfig), "modules-enabled")
+		entries, _ := ReadDir(dir)
+		for _, entry := range entries {
+			if strings.Contains(entry.Name(), "brotli") {
+				build.Brotli = true
+			}
+		}
+	}
+	return build, nil
+}
+
+// parseNginxBuild reads the version and configure arguments of nginx -V
+func parseNginxBuild(output string) NginxBuild {
+	build := NginxBuild{Gzip: !strings.Contains(output, "--without-http_gzip_module")}
+	if m := nginxVersionPattern.FindStringSubmatch(output); m != nil {
+		build.Version = m[1]
+	}
+	build.HTTP2 = strings.Contains(output, "--with-http_v2_module")
+	build.HTTP3 = strings.Contains(output, "--with-http_v3_module") && nginxVersionAtLeast(build.Version, 1, 25, 0)
+	build.Brotli = strings.Contains(output, "brotli")
+	return build
+}
+
+// nginxVersionAtLeast compares a dotted version with major.minor.patch
+func nginxVersionAtLeast(version string, major, minor, patch int) bool {
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	want := []int{major, minor, patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return false
+		}
+		if n != want[i] {
+			return n > want[i]
+		}
+	}
+	return true
+}
+
+// http2Directive reports whether the build uses `http2 on;` (1.25.1+)
+// rather than the http2 parameter of listen
+func (b NginxBuild) http2Directive() bool {
+	return nginxVersionAtLeast(b.Version, 1, 25, 1)
+}
+
+// serverBlock is the line range of a top-level server { } block
+type serverBlock struct {
+	start, end int // Lines of "server {" and its closing brace
+}
+
+// findServerBlocks locates the server blocks of a site config
+func findServerBlocks(lines []string) []serverBlock {
+	var blocks []serverBlock
+	depth, start := 0, -1
+	for i, line := range lines {
+		code, _, _ := strings.Cut(line, "#")
+		if depth == 0 && serverBlockPattern.MatchString(code) {
+			start = i
+		}
+		depth += strings.Count(code, "{") - strings.Count(code, "}")
+		if depth == 0 && start >= 0 {
+			blocks = append(blocks, serverBlock{start, i})
+			start = -1
+		}
+	}
+	return blocks
+}
+
+// blockDirectives returns the indexes of the lines directly inside a block
+func blockDirectives(lines []string, b serverBlock) []int {
+	var direct []int
+	depth := 0
+	for i := b.start + 1; i < b.end; i++ {
+		if depth == 0 {
+			direct = append(direct, i)
+		}
+		code, _, _ := strings.Cut(lines[i], "#")
+		depth += strings.Count(code, "{") - strings.Count(code, "}")
+	}
+	return direct
+}
+
+// directiveName returns the first word of a directive line
+func directiveName(line string) string {
+	fields := strings.Fields(strings.TrimSuffix(strings.TrimSpace(line), ";"))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// isSSLListen reports whether a listen line accepts TLS over TCP
+func isSSLListen(line string) bool {
+	fields := strings.Fields(strings.TrimSuffix(strings.TrimSpace(line), ";"))
+	return len(fields) > 1 && fields[0] == "listen" && strings.Contains(" "+strings.Join(fields[2:], " ")+" ", " ssl ")
+}
+
+// ParseSiteProtocols reads the protocol and compression settings of a site.
+// Gzip falls back to the http-level setting when the site does not set it.
+func ParseSiteProtocols(config, mainConfig string) NginxProtocols {
+	var p NginxProtocols
+	gzipSet := false
+	lines := strings.Split(config, "\n")
+	for _, b := range findServerBlocks(lines) {
+		for _, i := range blockDirectives(lines, b) {
+			line := strings.TrimSpace(lines[i])
+			fields := strings.Fields(strings.TrimSuffix(line, ";"))
+			switch directiveName(line) {
+			case "listen":
+				for _, f := range fields[1:] {
+					if f == "http2" {
+						p.HTTP2 = true
+					}
+					if f == "quic" {
+						p.HTTP3 = true
+					}
+				}
+			case "http2":
+				p.HTTP2 = p.HTTP2 || line == "http2 on;"
+			case "gzip":
+				gzipSet = true
+				p.Gzip = line == "gzip on;"
+			case "brotli":
+				p.Brotli = line == "brotli on;"
+			}
+		}
+	}
+	if !gzipSet {
+		p.Gzip = gzipOnPattern.MatchString(mainConfig)
+	}
+	return p
+}
+
+// ApplySiteProtocols rewrites a site config for the given settings: the
+// HTTP/2 and QUIC listen directives of each TLS server block, and a
+// managed compression block in the blocks that serve content
+func ApplySiteProtocols(config string, p NginxProtocols, build NginxBuild) (string, error) {
+	switch {
+	case p.HTTP2 && !build.HTTP2:
+		return "", fmt.Errorf("this nginx build does not include the HTTP/2 module")
+	case p.HTTP3 && !build.HTTP3:
+		return "", fmt.Errorf("this nginx build does not support HTTP/3 (needs 1.25+ with the http_v3 module)")
+	case p.Brotli && !build.Brotli:
+		return "", fmt.Errorf("the brotli module is not installed (e.g. libnginx-mod-http-brotli)")
+	case p.Gzip && !build.Gzip:
+		return "", fmt.Errorf("this nginx build was compiled without gzip")
+	}
+
+	lines := strings.Split(config, "\n")
+	blocks := findServerBlocks(lines)
+	hasSSL := false
+	for _, b := range blocks {
+		for _, i := range blockDirectives(lines, b) {
+			hasSSL = hasSSL || isSSLListen(lines[i])
+		}
+	}
+	if (p.HTTP2 || p.HTTP3) && !hasSSL {
+		return "", fmt.Errorf("HTTP/2 and HTTP/3 need HTTPS; add an SSL certificate first")
+	}
+
+	// Rewrite from the last block so earlier line numbers stay valid
+	for bi := len(blocks) - 1; bi >= 0; bi-- {
+		lines = rewriteServerBlock(lines, blocks[bi], p, build)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// rewriteServerBlock applies the settings to one server block
+func rewriteServerBlock(lines []string, b serverBlock, p NginxProtocols, build NginxBuild) []string {
+	direct := blockDirectives(lines, b)
+	isDirect := map[int]bool{}
+	for _, i := range direct {
+		isDirect[i] = true
+	}
+
+	indent := "    "
+	var sslListens []string
+	// Blocks with a document root serve the site; the others only redirect
+	// or answer ACME challenges and get no compression block
+	lastListen, servesContent := -1, false
+	for _, i := range direct {
+		line := strings.TrimSpace(lines[i])
+		switch directiveName(line) {
+		case "listen":
+			if strings.Contains(line, " quic") {
+				continue // Replaced below
+			}
+			if isSSLListen(line) {
+				sslListens = append(sslListens, line)
+			}
+			lastListen = i
+			indent = lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " \t"))]
+		case "root":
+			servesContent = true
+		}
+	}
+
+	var out []string
+	out = append(out, lines[:b.start+1]...)
+	inCompression := false
+	for i := b.start + 1; i < b.end; i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if isDirect[i] || inCompression {
+			if trimmed == compressionStart {
+				inCompression = true
+				continue
+			}
+			if inCompression {
+				if trimmed == compressionEnd {
+					inCompression = false
+				}
+				continue
+			}
+			switch directiveName(trimmed) {
+			case "http2", "http3", "quic_retry":
+				continue
+			case "add_header":
+				if strings.Contains(trimmed, "Alt-Svc") {
+					continue
+				}
+			case "listen":
+				fields := strings.Fields(strings.TrimSuffix(trimmed, ";"))
+				if slices.Contains(fields, "quic") {
+					continue
+				}
+				if isSSLListen(trimmed) {
+					fields = slices.DeleteFunc(fields, func(f string) bool { return f == "http2" })
+					if p.HTTP2 && !build.http2Directive() {
+						fields = append(fields, "http2")
+					}
+					line = indent + strings.Join(fields, " ") + ";"
+				}
+			}
+		}
+		out = append(out, line)
+
+		if i == lastListen && len(sslListens) > 0 {
+			if p.HTTP3 {
+				for _, l := range sslListens {
+					fields := strings.Fields(strings.TrimSuffix(l, ";"))
+					out = append(out, indent+"listen "+fields[1]+" quic;")
+				}
+			}
+			if p.HTTP2 && build.http2Directive() {
+				out = append(out, indent+"http2 on;")
+			}
+			if p.HTTP3 {
+				out = append(out, indent+"http3 on;", indent+`add_header Alt-Svc 'h3=":443"; ma=86400' always;`)
+			}
+		}
+	}
+
+	if servesContent {
+		// Drop a blank line left before the closing brace by the old block
+		for len(out) > b.start+1 && strings.TrimSpace(out[len(out)-1]) == "" {
+			out = out[:len(out)-1]
+		}
+		out = append(out, "")
+		out = append(out, compressionBlock(indent, p)...)
+	}
+	out = append(out, lines[b.end:]...)
+	return out
+}
+
+// compressionBlock returns the managed gzip and brotli directives
+func compressionBlock(indent string, p NginxProtocols) []string {
+	block := []string{indent + compressionStart}
+	if p.Gzip {
+		block = append(block,
+			indent+"gzip on;",
+			indent+"gzip_vary on;",
+			indent+"gzip_proxied any;",
+			indent+"gzip_comp_level 5;",
+			indent+"gzip_min_length 256;",
+			indent+"gzip_types "+compressionTypes+";")
+	} else {
+		block = append(block, indent+"gzip off;")
+	}
+	if p.Brotli {
+		block = append(block,
+			indent+"brotli on;",
+			indent+"brotli_comp_level 5;",
+			indent+"brotli_min_length 256;",
+			indent+"brotli_types "+compressionTypes+";")
+	}
+	return append(block, indent+compressionEnd)
+}
+
+// PlanSiteProtocols returns the config change for a site's protocol and
+// compression settings
+func (nm *NginxManager) PlanSiteProtocols(siteName string, p NginxProtocols, build NginxBuild) (NginxChange, error) {
+	configPath := filepath.Join(nm.sitesAvailable, siteName)
+	content, err := ReadFile(configPath)
+	if err != nil {
+		return NginxChange{}, fmt.Errorf("failed to read site config: %w", err)
+	}
+	config, err := ApplySiteProtocols(string(content), p, build)
+	if err != nil {
+		return NginxChange{}, err
+	}
+	return NginxChange{SiteName: siteName, Path: configPath, Old: string(content), New: config}, nil
+}
+
+// SiteProtocols reads the current settings of a site
+func (nm *NginxManager) SiteProtocols(siteName string) (NginxProtocols, error) {
+	content, err := ReadFile(filepath.Join(nm.sitesAvailable, siteName))
+	if err != nil {
+		return NginxProtocols{}, fmt.Errorf("failed to read site config: %w", err)
+	}
+	main, _ := ReadFile(nm.mainConfig)
+	return ParseSiteProtocols(string(content), string(main)), nil
+}
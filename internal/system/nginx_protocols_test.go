@@ -0,0 +1,113 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+const protocolsTestConfig = `server {
+    listen 80;
+    listen [::]:80;
+    server_name shop.com;
+
+    # Redirect to HTTPS
+    return 301 https://$host$request_uri;
+}
+
+server {
+    listen 443 ssl http2;
+    listen [::]:443 ssl http2;
+    server_name shop.com;
+
+    root /var/www/shop;
+
+    location / {
+        try_files $uri $uri/ =404;
+    }
+}
+`
+
+func TestParseNginxBuild(t *testing.T) {
+	output := "nginx version: nginx/1.26.2\nbuilt with OpenSSL 3.0.13\nconfigure arguments: --with-http_ssl_module --with-http_v2_module --with-http_v3_module --add-dynamic-module=/build/ngx_brotli"
+	build := parseNginxBuild(output)
+	if build.Version != "1.26.2" || !build.HTTP2 || !build.HTTP3 || !build.Gzip || !build.Brotli {
+		t.Errorf("unexpected build: %+v", build)
+	}
+	if !build.http2Directive() {
+		t.Error("expected 1.26 to use the http2 directive")
+	}
+
+	old := parseNginxBuild("nginx version: nginx/1.18.0\nconfigure arguments: --with-http_v2_module --with-http_v3_module --without-http_gzip_module")
+	if old.HTTP3 || old.Gzip || old.Brotli || old.http2Directive() {
+		t.Errorf("unexpected build: %+v", old)
+	}
+}
+
+func TestApplySiteProtocols(t *testing.T) {
+	build := NginxBuild{Version: "1.26.2", HTTP2: true, HTTP3: true, Gzip: true, Brotli: true}
+	p := NginxProtocols{HTTP2: true, HTTP3: true, Gzip: true, Brotli: true}
+
+	config, err := ApplySiteProtocols(protocolsTestConfig, p, build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"    listen 443 ssl;\n    listen [::]:443 ssl;\n    listen 443 quic;\n    listen [::]:443 quic;\n    http2 on;\n    http3 on;\n",
+		"add_header Alt-Svc",
+		"    # ravact: compression\n    gzip on;",
+		"    brotli on;",
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("config missing %q:\n%s", want, config)
+		}
+	}
+	if strings.Count(config, compressionStart) != 1 {
+		t.Errorf("expected compression only in the block with a root:\n%s", config)
+	}
+	if got := ParseSiteProtocols(config, ""); got != p {
+		t.Errorf("got %+v, want %+v", got, p)
+	}
+
+	// Applying again is a no-op, and turning everything off removes it all
+	again, _ := ApplySiteProtocols(config, p, build)
+	if again != config {
+		t.Errorf("expected applying twice to be stable:\n%s", UnifiedDiff("a", "b", config, again))
+	}
+	off, _ := ApplySiteProtocols(config, NginxProtocols{}, build)
+	for _, gone := range []string{"quic", "http2", "http3", "Alt-Svc", "brotli", "gzip on"} {
+		if strings.Contains(off, gone) {
+			t.Errorf("expected %q to be removed:\n%s", gone, off)
+		}
+	}
+	if !strings.Contains(off, "gzip off;") {
+		t.Error("expected gzip to be turned off explicitly")
+	}
+}
+
+func TestApplySiteProtocols_OldNginx(t *testing.T) {
+	build := NginxBuild{Version: "1.18.0", HTTP2: true, Gzip: true}
+	config, err := ApplySiteProtocols(protocolsTestConfig, NginxProtocols{HTTP2: true, Gzip: true}, build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(config, "listen 443 ssl http2;") || strings.Contains(config, "http2 on;") {
+		t.Errorf("expected http2 on the listen line for nginx < 1.25.1:\n%s", config)
+	}
+
+	for _, p := range []NginxProtocols{{HTTP3: true}, {Brotli: true}} {
+		if _, err := ApplySiteProtocols(protocolsTestConfig, p, build); err == nil {
+			t.Errorf("expected %+v to be refused by this build", p)
+		}
+	}
+	httpOnly := "server {\n    listen 80;\n    root /var/www;\n}\n"
+	if _, err := ApplySiteProtocols(httpOnly, NginxProtocols{HTTP2: true}, build); err == nil {
+		t.Error("expected HTTP/2 to require HTTPS")
+	}
+}
+
+func TestParseSiteProtocols_InheritsGzip(t *testing.T) {
+	main := "http {\n    gzip on;\n}\n"
+	if p := ParseSiteProtocols(protocolsTestConfig, main); !p.Gzip || !p.HTTP2 || p.HTTP3 {
+		t.Errorf("unexpected protocols: %+v", p)
+	}
+}
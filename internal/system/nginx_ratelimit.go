@@ -0,0 +1,356 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RateLimitRule limits how often one client address may request a path
+type RateLimitRule struct {
+	Path    string // "/" for the whole site, else a prefix such as /login
+	Rate    int    // Requests allowed per Per
+	Per     string // "s" or "m"
+	Burst   int    // Requests over the rate that are queued instead of refused
+	NoDelay bool   // Serve the burst at once rather than spacing it out
+}
+
+// SiteRateLimits are the request limits and bot blocking of a site
+type SiteRateLimits struct {
+	Rules     []RateLimitRule
+	BlockBots bool // Include the shared user-agent blocklist
+}
+
+// Markers around the rate limits ravact manages in a site. The zones are
+// defined at the top of the file, which nginx reads in the http context.
+const (
+	rateZonesStart = "# ravact: rate limit zones"
+	rateZonesEnd   = "# ravact: end rate limit zones"
+	rateLimitStart = "# ravact: rate limits"
+	rateLimitEnd   = "# ravact: end rate limits"
+)
+
+// rateLimitPath restricts paths to characters that need no quoting in
+// nginx and only the dot escaping in a regex
+var rateLimitPath = regexp.MustCompile(`^/[A-Za-z0-9/_.-]*$`)
+
+// Validate checks the path and numbers of a rule
+func (r RateLimitRule) Validate() error {
+	if !rateLimitPath.MatchString(r.Path) {
+		return fmt.Errorf("path must start with / and use only letters, digits, and / _ . -")
+	}
+	if r.Rate < 1 || r.Rate > 100000 {
+		return fmt.Errorf("rate must be between 1 and 100000 requests")
+	}
+	if r.Per != "s" && r.Per != "m" {
+		return fmt.Errorf("rate must be per second (s) or per minute (m)")
+	}
+	if r.Burst < 0 || r.Burst > 100000 {
+		return fmt.Errorf("burst must be between 0 and 100000 requests")
+	}
+	return nil
+}
+
+// Describe summarizes the limit of a rule
+func (r RateLimitRule) Describe() string {
+	unit := "second"
+	if r.Per == "m" {
+		unit = "minute"
+	}
+	s := fmt.Sprintf("%d per %s, burst %d", r.Rate, unit, r.Burst)
+	if r.NoDelay {
+		s += ", no delay"
+	}
+	return s
+}
+
+// rateLimitNames returns the zone and map variable names of a rule. The
+// names include the site because zones are shared by every server.
+func rateLimitNames(siteName, path string) (zone, variable string) {
+	slug := "site"
+	if path != "/" {
+		slug = strings.Trim(strings.NewReplacer("/", "_", ".", "_", "-", "_").Replace(path), "_")
+	}
+	base := strings.NewReplacer(".", "_", "-", "_").Replace(siteName) + "_" + strings.ToLower(slug)
+	return "ravact_" + base, "$ravact_limit_" + base
+}
+
+// ParseSiteRateLimits reads the rate limits ravact manages in a site config.
+// The server-level block is written to every server, so the first is used.
+func ParseSiteRateLimits(config string) SiteRateLimits {
+	var limits SiteRateLimits
+	paths := map[string]string{} // Map variable to path
+	zones := map[string]int{}    // Zone name to rule index
+	inZones, inLimits, seenLimits := false, false, false
+	variable := ""
+	for _, line := range strings.Split(config, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch trimmed {
+		case rateZonesStart:
+			inZones = true
+			continue
+		case rateZonesEnd:
+			inZones = false
+			continue
+		case rateLimitStart:
+			inLimits = !seenLimits
+			continue
+		case rateLimitEnd:
+			if inLimits {
+				seenLimits = true
+			}
+			inLimits = false
+			continue
+		}
+		fields := strings.Fields(strings.TrimSuffix(trimmed, ";"))
+		switch {
+		case inZones && len(fields) == 4 && fields[0] == "map":
+			variable = fields[2]
+		case inZones && len(fields) == 2 && strings.HasPrefix(fields[0], "~^"):
+			paths[variable] = strings.ReplaceAll(strings.TrimPrefix(fields[0], "~^"), `\`, "")
+		case inZones && len(fields) == 4 && fields[0] == "limit_req_zone":
+			rule := RateLimitRule{Path: paths[fields[1]]}
+			if fields[1] == "$binary_remote_addr" {
+				rule.Path = "/"
+			}
+			name, _, _ := strings.Cut(strings.TrimPrefix(fields[2], "zone="), ":")
+			rate, per, _ := strings.Cut(strings.TrimPrefix(fields[3], "rate="), "r/")
+			rule.Rate, _ = strconv.Atoi(rate)
+			rule.Per = per
+			zones[name] = len(limits.Rules)
+			limits.Rules = append(limits.Rules, rule)
+		case inLimits && len(fields) >= 2 && fields[0] == "limit_req":
+			i, ok := zones[strings.TrimPrefix(fields[1], "zone=")]
+			if !ok {
+				continue
+			}
+			for _, f := range fields[2:] {
+				if burst, ok := strings.CutPrefix(f, "burst="); ok {
+					limits.Rules[i].Burst, _ = strconv.Atoi(burst)
+				}
+				if f == "nodelay" {
+					limits.Rules[i].NoDelay = true
+				}
+			}
+		case inLimits && len(fields) == 2 && fields[0] == "include" && fields[1] == BotBlocklistPath:
+			limits.BlockBots = true
+		}
+	}
+	return limits
+}
+
+// stripRateZones removes the zones section and the blank line after it
+func stripRateZones(lines []string) []string {
+	var out []string
+	inZones, after := false, false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == rateZonesStart:
+			inZones = true
+		case inZones:
+			inZones = trimmed != rateZonesEnd
+			after = !inZones
+		case after && trimmed == "":
+			after = false
+		default:
+			after = false
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// ApplySiteRateLimits rewrites a site config for the given limits. Path
+// rules key their zone on a map that is empty for other URIs, and nginx
+// does not count requests with an empty key, so every limit can sit at
+// the server level without repeating the site's locations.
+func ApplySiteRateLimits(config, siteName string, limits SiteRateLimits) (string, error) {
+	zoneNames := map[string]string{}
+	for _, r := range limits.Rules {
+		if err := r.Validate(); err != nil {
+			return "", fmt.Errorf("%s: %w", r.Path, err)
+		}
+		zone, _ := rateLimitNames(siteName, r.Path)
+		if other, ok := zoneNames[zone]; ok {
+			return "", fmt.Errorf("%s and %s have the same zone name; use one rule", other, r.Path)
+		}
+		zoneNames[zone] = r.Path
+	}
+
+	lines := stripRateZones(strings.Split(config, "\n"))
+	lines = stripMarked(lines, rateLimitStart, rateLimitEnd)
+
+	// The hardening snippet already sets the status, and nginx refuses a
+	// second limit_req_status in the same block
+	hardened := strings.Contains(config, (SiteHardening{Site: NginxSite{Name: siteName}}).SnippetPath())
+	blocks := findServerBlocks(lines)
+	for bi := len(blocks) - 1; bi >= 0; bi-- {
+		lines = applyRateLimitsToBlock(lines, blocks[bi], siteName, limits, hardened)
+	}
+
+	if len(limits.Rules) > 0 && len(blocks) > 0 {
+		zones := []string{rateZonesStart}
+		for _, r := range limits.Rules {
+			zone, variable := rateLimitNames(siteName, r.Path)
+			key := "$binary_remote_addr"
+			if r.Path != "/" {
+				key = variable
+				zones = append(zones,
+					"map $uri "+variable+" {",
+					"    default \"\";",
+					"    ~^"+regexp.QuoteMeta(r.Path)+" $binary_remote_addr;",
+					"}")
+			}
+			zones = append(zones, fmt.Sprintf("limit_req_zone %s zone=%s:10m rate=%dr/%s;", key, zone, r.Rate, r.Per))
+		}
+		zones = append(zones, rateZonesEnd, "")
+		first := blocks[0].start
+		lines = append(lines[:first], append(zones, lines[first:]...)...)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// applyRateLimitsToBlock writes the limits after the root of a server
+// block. Blocks without a document root only redirect and are left alone.
+func applyRateLimitsToBlock(lines []string, b serverBlock, siteName string, limits SiteRateLimits, hardened bool) []string {
+	if len(limits.Rules) == 0 && !limits.BlockBots {
+		return lines
+	}
+	for _, i := range blockDirectives(lines, b) {
+		if directiveName(lines[i]) != "root" {
+			continue
+		}
+		indent := lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " \t"))]
+		block := []string{"", indent + rateLimitStart}
+		for _, r := range limits.Rules {
+			zone, _ := rateLimitNames(siteName, r.Path)
+			line := fmt.Sprintf("%slimit_req zone=%s burst=%d", indent, zone, r.Burst)
+			if r.NoDelay {
+				line += " nodelay"
+			}
+			block = append(block, line+";")
+		}
+		if len(limits.Rules) > 0 && !hardened {
+			block = append(block, indent+"limit_req_status 429;")
+		}
+		if limits.BlockBots {
+			block = append(block, indent+"include "+BotBlocklistPath+";")
+		}
+		block = append(block, indent+rateLimitEnd)
+		return append(lines[:i+1], append(block, lines[i+1:]...)...)
+	}
+	return lines
+}
+
+// SiteRateLimits reads the rate limits of a site
+func (nm *NginxManager) SiteRateLimits(siteName string) (SiteRateLimits, error) {
+	content, err := ReadFile(filepath.Join(nm.sitesAvailable, siteName))
+	if err != nil {
+		return SiteRateLimits{}, fmt.Errorf("failed to read site config: %w", err)
+	}
+	return ParseSiteRateLimits(string(content)), nil
+}
+
+// PlanSiteRateLimits returns the config change for a site's rate limits
+func (nm *NginxManager) PlanSiteRateLimits(siteName string, limits SiteRateLimits) (NginxChange, error) {
+	configPath := filepath.Join(nm.sitesAvailable, siteName)
+	content, err := ReadFile(configPath)
+	if err != nil {
+		return NginxChange{}, fmt.Errorf("failed to read site config: %w", err)
+	}
+	config, err := ApplySiteRateLimits(string(content), siteName, limits)
+	if err != nil {
+		return NginxChange{}, err
+	}
+	return NginxChange{SiteName: siteName, Path: configPath, Old: string(content), New: config}, nil
+}
+
+// BotBlocklistPath is the shared server-level include that refuses the
+// blocked user agents
+const BotBlocklistPath = "/etc/nginx/snippets/ravact-bot-blocklist.conf"
+
+// DefaultBlockedAgents are vulnerability scanners and crawlers that ignore
+// crawl delays
+var DefaultBlockedAgents = []string{
+	"sqlmap", "nikto", "masscan", "zgrab", "Nuclei", "WPScan",
+	"MJ12bot", "DotBot", "BLEXBot", "PetalBot", "SemrushBot", "AhrefsBot",
+}
+
+// blockedAgent restricts agents to characters that are safe in the quoted
+// regex without escaping beyond the dot
+var blockedAgent = regexp.MustCompile(`^[A-Za-z0-9 ._/-]+$`)
+
+// ValidateBlockedAgents checks user agents can be written to the blocklist
+func ValidateBlockedAgents(agents []string) error {
+	for _, agent := range agents {
+		if !blockedAgent.MatchString(agent) {
+			return fmt.Errorf("%q may only contain letters, digits, spaces, and . _ / -", agent)
+		}
+	}
+	return nil
+}
+
+// BotBlocklist returns the include refusing the agents, matched anywhere
+// in the User-Agent header without regard to case
+func BotBlocklist(agents []string) string {
+	var b strings.Builder
+	b.WriteString("# User-agent blocklist managed by ravact\n")
+	if len(agents) > 0 {
+		quoted := make([]string, len(agents))
+		for i, agent := range agents {
+			quoted[i] = regexp.QuoteMeta(agent)
+		}
+		fmt.Fprintf(&b, "if ($http_user_agent ~* \"(%s)\") {\n    return 403;\n}\n", strings.Join(quoted, "|"))
+	}
+	return b.String()
+}
+
+// LoadBotBlocklist reads the blocked agents, or the defaults when the
+// blocklist has not been written yet
+func LoadBotBlocklist() ([]string, error) {
+	data, err := ReadFile(BotBlocklistPath)
+	if os.IsNotExist(err) {
+		return DefaultBlockedAgents, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", BotBlocklistPath, err)
+	}
+	return parseBotBlocklist(string(data)), nil
+}
+
+// parseBotBlocklist reads the agents back from a blocklist include
+func parseBotBlocklist(content string) []string {
+	var agents []string
+	for _, line := range strings.Split(content, "\n") {
+		_, pattern, ok := strings.Cut(line, `~* "(`)
+		if !ok {
+			continue
+		}
+		pattern, _, _ = strings.Cut(pattern, `)"`)
+		for _, agent := range strings.Split(pattern, "|") {
+			if agent = strings.ReplaceAll(agent, `\`, ""); agent != "" {
+				agents = append(agents, agent)
+			}
+		}
+	}
+	return agents
+}
+
+// SaveBotBlocklist writes the blocklist include. Sites that include it
+// pick up changes on the next nginx reload.
+func SaveBotBlocklist(agents []string) error {
+	if err := ValidateBlockedAgents(agents); err != nil {
+		return err
+	}
+	if err := MkdirAll(filepath.Dir(BotBlocklistPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(BotBlocklistPath), err)
+	}
+	if err := WriteFile(BotBlocklistPath, []byte(BotBlocklist(agents)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", BotBlocklistPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package system
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApplySiteRateLimits(t *testing.T) {
+	limits := SiteRateLimits{
+		Rules: []RateLimitRule{
+			{Path: "/", Rate: 20, Per: "s", Burst: 40, NoDelay: true},
+			{Path: "/wp-login.php", Rate: 5, Per: "m", Burst: 3},
+		},
+		BlockBots: true,
+	}
+	config, err := ApplySiteRateLimits(accessTestConfig, "shop.com", limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# ravact: rate limit zones\nlimit_req_zone $binary_remote_addr zone=ravact_shop_com_site:10m rate=20r/s;\n",
+		"map $uri $ravact_limit_shop_com_wp_login_php {\n    default \"\";\n    ~^/wp-login\\.php $binary_remote_addr;\n}\n",
+		"limit_req_zone $ravact_limit_shop_com_wp_login_php zone=ravact_shop_com_wp_login_php:10m rate=5r/m;\n# ravact: end rate limit zones\n\nserver {\n    listen 80;",
+		"    root /var/www/shop/public;\n\n    # ravact: rate limits\n    limit_req zone=ravact_shop_com_site burst=40 nodelay;\n    limit_req zone=ravact_shop_com_wp_login_php burst=3;\n    limit_req_status 429;\n    include /etc/nginx/snippets/ravact-bot-blocklist.conf;\n    # ravact: end rate limits\n    index index.php;\n",
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("config missing %q:\n%s", want, config)
+		}
+	}
+	if strings.Count(config, rateLimitStart) != 1 {
+		t.Errorf("the redirect-only block should not be limited:\n%s", config)
+	}
+	if got := ParseSiteRateLimits(config); !reflect.DeepEqual(got, limits) {
+		t.Errorf("ParseSiteRateLimits() = %+v, want %+v", got, limits)
+	}
+
+	// Applying again is stable, and no limits restores the original
+	again, _ := ApplySiteRateLimits(config, "shop.com", limits)
+	if again != config {
+		t.Errorf("expected applying twice to be stable:\n%s", UnifiedDiff("a", "b", config, again))
+	}
+	if off, _ := ApplySiteRateLimits(config, "shop.com", SiteRateLimits{}); off != accessTestConfig {
+		t.Errorf("expected removing all limits to restore the config:\n%s", UnifiedDiff("a", "b", accessTestConfig, off))
+	}
+
+	// Hardening and the rate limits set the status only once between them
+	hardened := strings.Replace(accessTestConfig, "    index index.php;\n", "    index index.php;\n    include /etc/nginx/snippets/ravact-hardening-shop.com.conf;\n", 1)
+	if config, _ := ApplySiteRateLimits(hardened, "shop.com", limits); strings.Contains(config, "limit_req_status") {
+		t.Errorf("expected the hardening snippet to set the status:\n%s", config)
+	}
+	h := SiteHardening{Site: NginxSite{Name: "shop.com"}, Config: config}
+	if strings.Contains(h.Snippet(), "limit_req_status") {
+		t.Errorf("expected the rate limits to set the status:\n%s", h.Snippet())
+	}
+}
+
+func TestRateLimitRule_Validate(t *testing.T) {
+	for _, r := range []RateLimitRule{
+		{Path: "login", Rate: 5, Per: "m"},
+		{Path: "/log in", Rate: 5, Per: "m"},
+		{Path: "/login(.*)", Rate: 5, Per: "m"},
+		{Path: "/login", Rate: 0, Per: "m"},
+		{Path: "/login", Rate: 5, Per: "h"},
+		{Path: "/login", Rate: 5, Per: "m", Burst: -1},
+	} {
+		if r.Validate() == nil {
+			t.Errorf("expected %+v to be rejected", r)
+		}
+	}
+	limits := SiteRateLimits{Rules: []RateLimitRule{{Path: "/a-b", Rate: 1, Per: "s"}, {Path: "/a.b", Rate: 1, Per: "s"}}}
+	if _, err := ApplySiteRateLimits(accessTestConfig, "shop.com", limits); err == nil {
+		t.Error("expected an error for two paths with the same zone name")
+	}
+}
+
+func TestBotBlocklist(t *testing.T) {
+	agents := []string{"sqlmap", "MJ12bot", "Go-http-client/1.1"}
+	content := BotBlocklist(agents)
+	if !strings.Contains(content, "if ($http_user_agent ~* \"(sqlmap|MJ12bot|Go-http-client/1\\.1)\") {\n    return 403;\n}\n") {
+		t.Errorf("unexpected blocklist:\n%s", content)
+	}
+	if got := parseBotBlocklist(content); !reflect.DeepEqual(got, agents) {
+		t.Errorf("parseBotBlocklist() = %v, want %v", got, agents)
+	}
+	if got := parseBotBlocklist(BotBlocklist(nil)); got != nil {
+		t.Errorf("expected no agents, got %v", got)
+	}
+	if ValidateBlockedAgents([]string{`bad"agent`}) == nil || ValidateBlockedAgents([]string{"a|b"}) == nil {
+		t.Error("expected quotes and alternation to be rejected")
+	}
+}
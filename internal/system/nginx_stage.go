@@ -0,0 +1,91 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// NginxChange is a site config write that has not been applied yet, so it
+// can be tested and reviewed first
+type NginxChange struct {
+	SiteName string
+	Path     string
+	Old      string // Current content; empty for a new site
+	New      string
+	IsNew    bool
+	// Generated records how a new config was generated, for drift detection
+	Generated *GeneratedConfig
+}
+
+// Diff returns the change as a unified diff
+func (c NginxChange) Diff() string {
+	oldLabel := c.Path
+	if c.IsNew {
+		oldLabel = "/dev/null"
+	}
+	return UnifiedDiff(oldLabel, c.Path, c.Old, c.New)
+}
+
+// ApplyChange writes the staged config to its real path
+func (nm *NginxManager) ApplyChange(c NginxChange) error {
+	if err := WriteFile(c.Path, []byte(c.New), 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if c.Generated != nil {
+		// Best effort: the site works without its drift record
+		_ = RecordGeneratedConfig(*c.Generated, c.New)
+	}
+	return nil
+}
+
+// sitesEnabledInclude matches the include of sites-enabled in nginx.conf
+var sitesEnabledInclude = regexp.MustCompile(`(?m)^(\s*)include\s+\S*sites-enabled/\S*;`)
+
+// TestChange runs `nginx -t` with the change in place of the site's current
+// config, without touching the live files. A copy of nginx.conf is staged
+// next to the original (so relative includes still resolve) that includes
+// every enabled site, with the staged file standing in for this one.
+func (nm *NginxManager) TestChange(c NginxChange) error {
+	main, err := ReadFile(nm.mainConfig)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", nm.mainConfig, err)
+	}
+	loc := sitesEnabledInclude.FindStringSubmatchIndex(string(main))
+	if loc == nil {
+		return fmt.Errorf("%s does not include %s; cannot stage the change", nm.mainConfig, nm.sitesEnabled)
+	}
+	indent := string(main)[loc[2]:loc[3]]
+
+	dir := filepath.Dir(nm.mainConfig)
+	stagedSite := filepath.Join(dir, ".ravact-staged-"+c.SiteName+".conf")
+	stagedMain := filepath.Join(dir, ".ravact-staged-nginx.conf")
+	defer Remove(stagedSite)
+	defer Remove(stagedMain)
+
+	includes := []string{}
+	entries, _ := ReadDir(nm.sitesEnabled)
+	for _, entry := range entries {
+		if entry.Name() != c.SiteName {
+			includes = append(includes, indent+"include "+filepath.Join(nm.sitesEnabled, entry.Name())+";")
+		}
+	}
+	includes = append(includes, indent+"include "+stagedSite+";")
+
+	staged := string(main)[:loc[0]] + strings.Join(includes, "\n") + string(main)[loc[1]:]
+	if err := WriteFile(stagedSite, []byte(c.New), 0644); err != nil {
+		return fmt.Errorf("failed to stage config: %w", err)
+	}
+	if err := WriteFile(stagedMain, []byte(staged), 0644); err != nil {
+		return fmt.Errorf("failed to stage config: %w", err)
+	}
+
+	output, err := Command("nginx", "-t", "-c", stagedMain).CombinedOutput()
+	if err != nil {
+		// Point errors at the file the user is about to write
+		message := strings.ReplaceAll(strings.TrimSpace(string(output)), stagedSite, c.Path)
+		return fmt.Errorf("nginx config test failed: %s", message)
+	}
+	return nil
+}
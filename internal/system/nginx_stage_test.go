@@ -0,0 +1,102 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// stubNginx puts an nginx on PATH that fails when the staged site config
+// contains "broken" and records the main config it was given
+func stubNginx(t *testing.T) (captured string) {
+	t.Helper()
+	bin := t.TempDir()
+	captured = filepath.Join(bin, "main.conf")
+	script := `#!/bin/sh
+cp "$3" ` + captured + `
+if grep -h broken $(sed -n 's/^ *include \(.*staged.*\);/\1/p' "$3"); then
+  echo "nginx: [emerg] unknown directive \"broken\" in $(sed -n 's/^ *include \(.*staged.*\);/\1/p' "$3"):2"
+  exit 1
+fi
+`
+	if err := os.WriteFile(filepath.Join(bin, "nginx"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+":"+os.Getenv("PATH"))
+	return captured
+}
+
+func testStagingManager(t *testing.T) *NginxManager {
+	t.Helper()
+	dir := t.TempDir()
+	nm := &NginxManager{
+		sitesAvailable: filepath.Join(dir, "sites-available"),
+		sitesEnabled:   filepath.Join(dir, "sites-enabled"),
+		mainConfig:     filepath.Join(dir, "nginx.conf"),
+	}
+	os.MkdirAll(nm.sitesAvailable, 0755)
+	os.MkdirAll(nm.sitesEnabled, 0755)
+	main := "http {\n    include mime.types;\n    include " + nm.sitesEnabled + "/*;\n}\n"
+	os.WriteFile(nm.mainConfig, []byte(main), 0644)
+	for _, site := range []string{"other.com", "example.com"} {
+		os.WriteFile(filepath.Join(nm.sitesAvailable, site), []byte("server {\n}\n"), 0644)
+		os.Symlink(filepath.Join(nm.sitesAvailable, site), filepath.Join(nm.sitesEnabled, site))
+	}
+	return nm
+}
+
+func TestNginxManager_TestChange(t *testing.T) {
+	captured := stubNginx(t)
+	nm := testStagingManager(t)
+	dir := filepath.Dir(nm.mainConfig)
+
+	change := NginxChange{SiteName: "example.com", Path: filepath.Join(nm.sitesAvailable, "example.com"), Old: "server {\n}\n", New: "server {\n    listen 80;\n}\n"}
+	if err := nm.TestChange(change); err != nil {
+		t.Fatalf("expected the staged config to pass: %v", err)
+	}
+	main, _ := os.ReadFile(captured)
+	for _, want := range []string{"include mime.types;", "include " + filepath.Join(nm.sitesEnabled, "other.com") + ";", "include " + filepath.Join(dir, ".ravact-staged-example.com.conf") + ";"} {
+		if !strings.Contains(string(main), want) {
+			t.Errorf("staged nginx.conf missing %q:\n%s", want, main)
+		}
+	}
+	if strings.Contains(string(main), filepath.Join(nm.sitesEnabled, "example.com")) {
+		t.Errorf("staged nginx.conf should replace the live site config:\n%s", main)
+	}
+
+	change.New = "server {\n    broken;\n}\n"
+	err := nm.TestChange(change)
+	if err == nil || !strings.Contains(err.Error(), change.Path+":2") {
+		t.Errorf("expected a failure pointing at %s, got %v", change.Path, err)
+	}
+
+	// Staged files are cleaned up and the live config is untouched
+	if matches, _ := filepath.Glob(filepath.Join(dir, ".ravact-staged-*")); len(matches) != 0 {
+		t.Errorf("staged files left behind: %v", matches)
+	}
+	if live, _ := os.ReadFile(change.Path); string(live) != "server {\n}\n" {
+		t.Errorf("live config changed: %q", live)
+	}
+}
+
+func TestNginxManager_PlanRemoveSSL(t *testing.T) {
+	nm := testStagingManager(t)
+	path := filepath.Join(nm.sitesAvailable, "example.com")
+	config := "server {\n    listen 80;\n    listen 443 ssl;\n    server_name example.com;\n\n    # SSL Configuration\n    ssl_certificate /a.pem;\n    ssl_certificate_key /a.key;\n}\n"
+	os.WriteFile(path, []byte(config), 0644)
+
+	change, err := nm.PlanRemoveSSL("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.Old != config || strings.Contains(change.New, "ssl_certificate") {
+		t.Errorf("unexpected change: %+v", change)
+	}
+	if live, _ := os.ReadFile(path); string(live) != config {
+		t.Error("planning must not write the config")
+	}
+	if diff := change.Diff(); !strings.Contains(diff, "-    ssl_certificate /a.pem;") {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+}
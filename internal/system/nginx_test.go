@@ -3,6 +3,7 @@ package system
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -227,3 +228,170 @@ func TestNginxManager_DisableSite(t *testing.T) {
 		t.Error("link should be removed")
 	}
 }
+
+func TestNginxManager_TemplateStubs(t *testing.T) {
+	nm := testStagingManager(t)
+
+	change, err := nm.PlanCreateSite("app", []string{"app.test"}, "/var/www/app/public", "laravel", "", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(change.New, "fastcgi_pass unix:/var/run/php/php-fpm.sock;") || !strings.Contains(change.New, "server_name app.test;") {
+		t.Errorf("unexpected laravel config:\n%s", change.New)
+	}
+
+	if _, err := nm.PlanCreateSite("api", []string{"api.test"}, "/var/www/api", "proxy", "", false, false); err == nil {
+		t.Error("expected the proxy template to require an upstream")
+	}
+	change, err = nm.PlanCreateSite("api", []string{"api.test"}, "/var/www/api", "proxy", "http://127.0.0.1:8080", false, false)
+	if err != nil || !strings.Contains(change.New, "proxy_pass http://127.0.0.1:8080;") {
+		t.Errorf("unexpected proxy config: %v\n%s", err, change.New)
+	}
+
+	if _, err := nm.PlanCreateSite("x", []string{"x.test"}, "/var/www/x", "missing", "", false, false); err == nil {
+		t.Error("expected an error for a template without a stub")
+	}
+}
+
+func TestNginxManager_ProxyOptions(t *testing.T) {
+	nm := testStagingManager(t)
+
+	// The defaults render the proxy stub as it was before the options existed
+	change, err := nm.PlanCreateSite("api", []string{"api.test"}, "/var/www/api", "proxy", "http://127.0.0.1:8080", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(change.New, "proxy_http_version 1.1;\n        proxy_set_header Host $host;") ||
+		!strings.Contains(change.New, "proxy_read_timeout 300s;") || !strings.Contains(change.New, "proxy_connect_timeout 75s;") {
+		t.Errorf("unexpected default proxy config:\n%s", change.New)
+	}
+
+	proxy := ProxyOptions{Upstream: "http://unix:/run/app.sock:", WebSocket: true, ConnectTimeout: 10, ReadTimeout: 3600}
+	change, err = nm.PlanCreateSiteWithOptions("ws", []string{"ws.test"}, "/var/www/ws", "proxy", SiteOptions{Proxy: proxy, Static: DefaultStaticOptions()}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"proxy_pass http://unix:/run/app.sock:;",
+		"proxy_set_header Upgrade $http_upgrade;",
+		"proxy_set_header Connection $http_connection;",
+		"proxy_read_timeout 3600s;",
+		"proxy_connect_timeout 10s;",
+	} {
+		if !strings.Contains(change.New, want) {
+			t.Errorf("proxy config missing %q:\n%s", want, change.New)
+		}
+	}
+	if got := proxyOptionsFromParams(change.Generated.Params); got != proxy {
+		t.Errorf("recorded options = %+v, want %+v", got, proxy)
+	}
+	rendered, err := change.Generated.Render()
+	if err != nil || rendered != change.New {
+		t.Errorf("render from params differs: %v\n%s", err, rendered)
+	}
+
+	proxy.ConnectTimeout = 120
+	if _, err := nm.PlanCreateSiteWithOptions("ws", []string{"ws.test"}, "/var/www/ws", "proxy", SiteOptions{Proxy: proxy, Static: DefaultStaticOptions()}, false, false); err == nil {
+		t.Error("expected an error for a connect timeout above 75s")
+	}
+}
+
+func TestNginxManager_StaticOptions(t *testing.T) {
+	nm := testStagingManager(t)
+
+	// The defaults render the SPA stub as it was before the options existed
+	change, err := nm.PlanCreateSite("app", []string{"app.test"}, "/var/www/app/dist", "spa", "", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(change.New, "    # Single Page Application") || strings.Contains(change.New, "gzip_static") ||
+		!strings.Contains(change.New, "try_files $uri $uri/ /index.html;\n    }") || !strings.Contains(change.New, "expires 30d;") {
+		t.Errorf("unexpected default SPA config:\n%s", change.New)
+	}
+
+	opts := DefaultSiteOptions("")
+	opts.Static = StaticOptions{AssetCacheDays: 365, Immutable: true, HTMLNoCache: true, GzipStatic: true, BrotliStatic: true}
+	change, err = nm.PlanCreateSiteWithOptions("app", []string{"app.test"}, "/var/www/app/dist", "spa", opts, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"    gzip_static on;\n    brotli_static on;\n",
+		"try_files $uri $uri/ /index.html;\n        add_header Cache-Control \"no-cache\";",
+		"add_header Cache-Control \"public, max-age=31536000, immutable\";",
+	} {
+		if !strings.Contains(change.New, want) {
+			t.Errorf("SPA config missing %q:\n%s", want, change.New)
+		}
+	}
+	if strings.Contains(change.New, "expires") {
+		t.Errorf("immutable assets should not also set expires:\n%s", change.New)
+	}
+	if got := siteOptionsFromParams(change.Generated.Params); got.Static != opts.Static {
+		t.Errorf("recorded options = %+v, want %+v", got.Static, opts.Static)
+	}
+
+	opts.Static.AssetCacheDays = 400
+	if _, err := nm.PlanCreateSiteWithOptions("app", []string{"app.test"}, "/var/www/app/dist", "spa", opts, false, false); err == nil {
+		t.Error("expected an error for an asset cache over a year")
+	}
+}
+
+func TestNormalizeUpstream(t *testing.T) {
+	for input, want := range map[string]string{
+		"3000":                  "http://127.0.0.1:3000",
+		"10.0.0.5:8080":         "http://10.0.0.5:8080",
+		"[::1]:8080":            "http://[::1]:8080",
+		"/run/app/app.sock":     "http://unix:/run/app/app.sock:",
+		"unix:/run/app.sock":    "http://unix:/run/app.sock:",
+		"https://internal.test": "https://internal.test",
+		"":                      "",
+	} {
+		if got, err := NormalizeUpstream(input); err != nil || got != want {
+			t.Errorf("NormalizeUpstream(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"70000", "localhost", "unix:run.sock", "http://x; return 200", "docker:grafana"} {
+		if _, err := NormalizeUpstream(input); err == nil {
+			t.Errorf("NormalizeUpstream(%q): expected an error", input)
+		}
+	}
+}
+
+func TestNginxManager_MultipleDomains(t *testing.T) {
+	nm := testStagingManager(t)
+	domains := []string{"shop.com", "www.shop.com", "*.shop.com"}
+
+	change, err := nm.PlanCreateSite("shop", domains, "/var/www/shop", "static", "", true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"server_name shop.com www.shop.com *.shop.com;",
+		"return 301 https://$host$request_uri;",
+		"access_log /var/log/nginx/shop.com-access.log;",
+	} {
+		if !strings.Contains(change.New, want) {
+			t.Errorf("config missing %q:\n%s", want, change.New)
+		}
+	}
+
+	if _, err := nm.PlanCreateSite("shop", domains, "/var/www/shop", "static", "", true, true); err == nil {
+		t.Error("expected certbot to be refused for a wildcard")
+	}
+
+	// Sites read back list every server_name across the server blocks
+	os.WriteFile(change.Path, []byte(change.New), 0644)
+	parsed, _, _, _ := nm.parseConfig(change.Path)
+	if !reflect.DeepEqual(parsed, domains) {
+		t.Errorf("got %v, want %v", parsed, domains)
+	}
+}
+
+func TestCertbotArgs(t *testing.T) {
+	got := strings.Join(CertbotArgs([]string{"shop.com", "www.shop.com"}), " ")
+	want := "--nginx --cert-name shop.com -d shop.com -d www.shop.com --non-interactive --agree-tos --email admin@shop.com"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,280 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Node version managers ravact can install system-wide
+const (
+	NodeManagerFnm = "fnm"
+	NodeManagerNvm = "nvm"
+)
+
+// System-wide locations of the node version managers. Versions are
+// installed once by root and shared by every user through NodeProfilePath.
+var (
+	FnmDir          = "/opt/fnm"
+	NvmDir          = "/opt/nvm"
+	NodeProfilePath = "/etc/profile.d/ravact-node.sh"
+)
+
+// nvmVersion is the nvm release installed by NodeManagerInstallScript
+const nvmVersion = "v0.40.1"
+
+var nodeVersionPattern = regexp.MustCompile(`^(v?[0-9]+(\.[0-9]+){0,2}|lts/[a-z*]+|lts|node|latest)$`)
+
+// NodeManager is the system-wide version manager found on the host
+type NodeManager struct {
+	Kind string // NodeManagerFnm, NodeManagerNvm, or "" when neither is installed
+	Dir  string
+}
+
+// DetectNodeManager finds a system-wide fnm or nvm; fnm wins if both exist
+func DetectNodeManager() NodeManager {
+	if _, err := Stat(filepath.Join(FnmDir, "node-versions")); err == nil {
+		return NodeManager{Kind: NodeManagerFnm, Dir: FnmDir}
+	}
+	if _, err := Stat(filepath.Join(NvmDir, "nvm.sh")); err == nil {
+		return NodeManager{Kind: NodeManagerNvm, Dir: NvmDir}
+	}
+	return NodeManager{}
+}
+
+// Installed reports whether a manager was found
+func (n NodeManager) Installed() bool {
+	return n.Kind != ""
+}
+
+// versionsDir is where the manager keeps installed Node versions
+func (n NodeManager) versionsDir() string {
+	if n.Kind == NodeManagerFnm {
+		return filepath.Join(n.Dir, "node-versions")
+	}
+	return filepath.Join(n.Dir, "versions", "node")
+}
+
+// Versions returns the installed Node versions, newest first
+func (n NodeManager) Versions() ([]string, error) {
+	if !n.Installed() {
+		return nil, nil
+	}
+	entries, err := ReadDir(n.versionsDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Node versions: %w", err)
+	}
+	var versions []string
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), "v") {
+			versions = append(versions, e.Name())
+		}
+	}
+	sortNodeVersions(versions)
+	return versions, nil
+}
+
+// sortNodeVersions orders versions newest first, numerically
+func sortNodeVersions(versions []string) {
+	parse := func(v string) [3]int {
+		var n [3]int
+		for i, part := range strings.SplitN(strings.TrimPrefix(v, "v"), ".", 3) {
+			n[i], _ = strconv.Atoi(part)
+		}
+		return n
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		a, b := parse(versions[i]), parse(versions[j])
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] > b[k]
+			}
+		}
+		return false
+	})
+}
+
+// Default returns the version new shells use, as the alias names it (e.g.
+// "20" or "v20.11.1"), or "" if none is set
+func (n NodeManager) Default() string {
+	switch n.Kind {
+	case NodeManagerFnm:
+		// aliases/default links to the version's directory
+		out, err := Command("readlink", filepath.Join(n.Dir, "aliases", "default")).Output()
+		if err != nil {
+			return ""
+		}
+		return filepath.Base(strings.TrimSpace(string(out)))
+	case NodeManagerNvm:
+		data, err := ReadFile(filepath.Join(n.Dir, "alias", "default"))
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+	return ""
+}
+
+// ValidateNodeVersion accepts versions and aliases both managers understand,
+// such as 20, v20.11.1, or lts/iron
+func ValidateNodeVersion(version string) error {
+	if !nodeVersionPattern.MatchString(strings.TrimSpace(version)) {
+		return fmt.Errorf("use a version such as 20, v20.11.1, or lts/iron")
+	}
+	return nil
+}
+
+// Env returns bash that loads the manager into a shell, for commands run
+// outside a login shell
+func (n NodeManager) Env() string {
+	switch n.Kind {
+	case NodeManagerFnm:
+		return fmt.Sprintf("export FNM_DIR=%s; eval \"$(fnm env --shell bash)\"", ShellQuote(n.Dir))
+	case NodeManagerNvm:
+		return fmt.Sprintf("export NVM_DIR=%s; . \"$NVM_DIR/nvm.sh\"", ShellQuote(n.Dir))
+	}
+	return ""
+}
+
+// UseCommand returns bash that switches the current shell to a version, or
+// to the one in .nvmrc when version is empty
+func (n NodeManager) UseCommand(version string) string {
+	switch n.Kind {
+	case NodeManagerFnm:
+		if version == "" {
+			return n.Env() + " && fnm use --install-if-missing"
+		}
+		return n.Env() + " && fnm use --install-if-missing " + ShellQuote(version)
+	case NodeManagerNvm:
+		if version == "" {
+			return n.Env() + " && nvm use"
+		}
+		return n.Env() + " && nvm use " + ShellQuote(version)
+	}
+	return ""
+}
+
+// NodeManagerInstallScript installs fnm or nvm under /opt for every user and
+// loads it from /etc/profile.d. The fnm binary is checked with
+// verified_download.
+func NodeManagerInstallScript(kind string) (string, error) {
+	var b strings.Builder
+	b.WriteString("#!/bin/bash\nset -e\n")
+	switch kind {
+	case NodeManagerFnm:
+		b.WriteString(DownloadHelpers)
+		b.WriteString(`case "$(uname -m)" in
+  x86_64) FNM_ASSET="fnm-linux.zip" ;;
+  aarch64|arm64) FNM_ASSET="fnm-arm64.zip" ;;
+  *) echo "✗ fnm has no build for $(uname -m)"; exit 1 ;;
+esac
+command -v unzip >/dev/null 2>&1 || apt-get install -y unzip || yum install -y unzip
+verified_download "https://github.com/Schniz/fnm/releases/latest/download/$FNM_ASSET" /tmp/fnm.zip Schniz/fnm
+unzip -o -q /tmp/fnm.zip -d /tmp/fnm
+install -m 0755 /tmp/fnm/fnm /usr/local/bin/fnm
+rm -rf /tmp/fnm /tmp/fnm.zip
+`)
+		fmt.Fprintf(&b, "mkdir -p %s\nchmod 755 %s\n", ShellQuote(FnmDir), ShellQuote(FnmDir))
+		fmt.Fprintf(&b, "cat > %s <<'EOF'\n# Managed by ravact: system-wide fnm\nexport FNM_DIR=%s\nif command -v fnm >/dev/null 2>&1; then\n  eval \"$(fnm env --use-on-cd --shell bash)\"\nfi\nEOF\n",
+			ShellQuote(NodeProfilePath), FnmDir)
+		b.WriteString("echo \"✓ fnm $(/usr/local/bin/fnm --version | awk '{print $2}') installed\"\n")
+	case NodeManagerNvm:
+		b.WriteString("command -v git >/dev/null 2>&1 || { echo \"✗ git is required to install nvm\"; exit 1; }\n")
+		fmt.Fprintf(&b, "if [ ! -d %s/.git ]; then\n  git clone --quiet https://github.com/nvm-sh/nvm.git %s\nfi\n", ShellQuote(NvmDir), ShellQuote(NvmDir))
+		fmt.Fprintf(&b, "git -C %s fetch --quiet --tags\ngit -C %s -c advice.detachedHead=false checkout --quiet %s\n", ShellQuote(NvmDir), ShellQuote(NvmDir), nvmVersion)
+		fmt.Fprintf(&b, "chmod -R a+rX %s\n", ShellQuote(NvmDir))
+		fmt.Fprintf(&b, "cat > %s <<'EOF'\n# Managed by ravact: system-wide nvm\nexport NVM_DIR=%s\n[ -s \"$NVM_DIR/nvm.sh\" ] && . \"$NVM_DIR/nvm.sh\"\nEOF\n",
+			ShellQuote(NodeProfilePath), NvmDir)
+		fmt.Fprintf(&b, "echo \"✓ nvm %s installed in %s\"\n", nvmVersion, NvmDir)
+	default:
+		return "", fmt.Errorf("unknown Node version manager %q", kind)
+	}
+	fmt.Fprintf(&b, "chmod 644 %s\necho \"  New login shells load it from %s\"\n", ShellQuote(NodeProfilePath), NodeProfilePath)
+	return b.String(), nil
+}
+
+// nodeCommandScript runs a manager command as root and leaves the versions
+// readable by every user
+func (n NodeManager) nodeCommandScript(command string) string {
+	return fmt.Sprintf("#!/bin/bash\nset -e\n%s\n%s\nchmod -R a+rX %s\n", n.Env(), command, ShellQuote(n.Dir))
+}
+
+// InstallVersionScript installs a Node version
+func (n NodeManager) InstallVersionScript(version string) (string, error) {
+	if err := ValidateNodeVersion(version); err != nil {
+		return "", err
+	}
+	switch n.Kind {
+	case NodeManagerFnm:
+		return n.nodeCommandScript("fnm install " + ShellQuote(version)), nil
+	case NodeManagerNvm:
+		return n.nodeCommandScript("nvm install " + ShellQuote(version)), nil
+	}
+	return "", fmt.Errorf("no Node version manager installed")
+}
+
+// UninstallVersionScript removes an installed Node version
+func (n NodeManager) UninstallVersionScript(version string) (string, error) {
+	if err := ValidateNodeVersion(version); err != nil {
+		return "", err
+	}
+	switch n.Kind {
+	case NodeManagerFnm:
+		return n.nodeCommandScript("fnm uninstall " + ShellQuote(version)), nil
+	case NodeManagerNvm:
+		return n.nodeCommandScript("nvm deactivate >/dev/null 2>&1 || true\nnvm uninstall " + ShellQuote(version)), nil
+	}
+	return "", fmt.Errorf("no Node version manager installed")
+}
+
+// SetDefaultScript makes a version the default for new shells
+func (n NodeManager) SetDefaultScript(version string) (string, error) {
+	if err := ValidateNodeVersion(version); err != nil {
+		return "", err
+	}
+	switch n.Kind {
+	case NodeManagerFnm:
+		return n.nodeCommandScript("fnm default " + ShellQuote(version)), nil
+	case NodeManagerNvm:
+		return n.nodeCommandScript("nvm alias default " + ShellQuote(version)), nil
+	}
+	return "", fmt.Errorf("no Node version manager installed")
+}
+
+// ReadNvmrc returns the version pinned in a directory's .nvmrc, or "" if
+// it has none
+func ReadNvmrc(dir string) string {
+	data, err := ReadFile(filepath.Join(dir, ".nvmrc"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
+
+// WriteNvmrc pins a site's Node version, keeping the directory owner as the
+// file owner so deploys can update it
+func WriteNvmrc(dir, version string) error {
+	if err := ValidateNodeVersion(version); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, ".nvmrc")
+	if err := WriteFile(path, []byte(strings.TrimSpace(version)+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if out, err := Command("chown", "--reference="+dir, path).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to set owner of %s: %s", path, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNodeManager(t *testing.T) {
+	originalFnm, originalNvm := FnmDir, NvmDir
+	FnmDir = filepath.Join(t.TempDir(), "fnm")
+	NvmDir = filepath.Join(t.TempDir(), "nvm")
+	defer func() { FnmDir, NvmDir = originalFnm, originalNvm }()
+
+	if DetectNodeManager().Installed() {
+		t.Fatal("expected no manager")
+	}
+
+	for _, v := range []string{"v18.20.4", "v20.9.0", "v20.11.1", "v8.17.0"} {
+		os.MkdirAll(filepath.Join(NvmDir, "versions", "node", v), 0755)
+	}
+	os.WriteFile(filepath.Join(NvmDir, "nvm.sh"), nil, 0644)
+	os.MkdirAll(filepath.Join(NvmDir, "alias"), 0755)
+	os.WriteFile(filepath.Join(NvmDir, "alias", "default"), []byte("20\n"), 0644)
+
+	n := DetectNodeManager()
+	if n.Kind != NodeManagerNvm {
+		t.Fatalf("expected nvm, got %q", n.Kind)
+	}
+	versions, err := n.Versions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"v20.11.1", "v20.9.0", "v18.20.4", "v8.17.0"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("got %v, want %v", versions, want)
+	}
+	if got := n.Default(); got != "20" {
+		t.Errorf("expected default 20, got %q", got)
+	}
+
+	script, err := n.InstallVersionScript("lts/iron")
+	if err != nil || !strings.Contains(script, "nvm install lts/iron") || !strings.Contains(script, ". \"$NVM_DIR/nvm.sh\"") {
+		t.Errorf("unexpected install script (%v):\n%s", err, script)
+	}
+	if _, err := n.InstallVersionScript("20; rm -rf /"); err == nil {
+		t.Error("expected an invalid version to be rejected")
+	}
+	if use := n.UseCommand(""); !strings.HasSuffix(use, "nvm use") {
+		t.Errorf("expected .nvmrc to be used without a version, got %q", use)
+	}
+
+	// fnm wins once installed
+	os.MkdirAll(filepath.Join(FnmDir, "node-versions", "v22.1.0"), 0755)
+	if n := DetectNodeManager(); n.Kind != NodeManagerFnm {
+		t.Errorf("expected fnm, got %q", n.Kind)
+	}
+}
+
+func TestNvmrc(t *testing.T) {
+	dir := t.TempDir()
+	if v := ReadNvmrc(dir); v != "" {
+		t.Errorf("expected no .nvmrc, got %q", v)
+	}
+	os.WriteFile(filepath.Join(dir, ".nvmrc"), []byte("# pinned for the build\nlts/iron\n"), 0644)
+	if v := ReadNvmrc(dir); v != "lts/iron" {
+		t.Errorf("got %q", v)
+	}
+	if err := WriteNvmrc(dir, "bad version"); err == nil {
+		t.Error("expected an invalid version to be rejected")
+	}
+}
+
+func TestNodeManagerInstallScript(t *testing.T) {
+	fnm, err := NodeManagerInstallScript(NodeManagerFnm)
+	if err != nil || !strings.Contains(fnm, "verified_download") || !strings.Contains(fnm, "export FNM_DIR=") {
+		t.Errorf("unexpected fnm script (%v)", err)
+	}
+	nvm, err := NodeManagerInstallScript(NodeManagerNvm)
+	if err != nil || !strings.Contains(nvm, "checkout --quiet "+nvmVersion) {
+		t.Errorf("unexpected nvm script (%v)", err)
+	}
+	if _, err := NodeManagerInstallScript("volta"); err == nil {
+		t.Error("expected an unknown manager to be rejected")
+	}
+}
@@ -0,0 +1,323 @@
+package system
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NodeAppUnitPrefix names the systemd units ravact creates for Node apps
+const NodeAppUnitPrefix = "node-"
+
+// nodeAppMarker identifies unit files written by NodeApp.Unit
+const nodeAppMarker = "# Managed by ravact: Node app"
+
+var nodeAppNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,40}$`)
+
+// NodeApp is a Node process run as a systemd service
+type NodeApp struct {
+	Name    string // The unit is node-<Name>.service
+	Dir     string
+	User    string
+	Command string // Such as "npm start" or "node server.js"
+	Port    string // Exported as PORT when set
+	NodeEnv string // Exported as NODE_ENV when set
+	Active  string // active, inactive, failed, ... (set by ListNodeApps)
+	Enabled bool
+}
+
+// UnitName returns the app's systemd unit
+func (a NodeApp) UnitName() string {
+	return NodeAppUnitPrefix + a.Name + ".service"
+}
+
+// Validate checks the fields that end up in the unit file
+func (a NodeApp) Validate() error {
+	if !nodeAppNamePattern.MatchString(a.Name) {
+		return fmt.Errorf("name must be lowercase letters, digits, and dashes")
+	}
+	if !filepath.IsAbs(a.Dir) || strings.ContainsAny(a.Dir, "'\n") {
+		return fmt.Errorf("directory must be an absolute path")
+	}
+	if !usernamePattern.MatchString(a.User) {
+		return fmt.Errorf("invalid user %q", a.User)
+	}
+	if strings.TrimSpace(a.Command) == "" || strings.ContainsAny(a.Command, "'\n") {
+		return fmt.Errorf("command is required and cannot contain single quotes or newlines")
+	}
+	if a.Port != "" {
+		if port, err := strconv.Atoi(a.Port); err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("port must be between 1 and 65535")
+		}
+	}
+	if strings.ContainsAny(a.NodeEnv, " \"'\n") {
+		return fmt.Errorf("invalid NODE_ENV %q", a.NodeEnv)
+	}
+	return nil
+}
+
+// Unit returns the systemd unit for the app. The command runs in a login
+// shell so the system-wide Node manager from NodeProfilePath is loaded, and
+// a .nvmrc in the app directory selects the Node version.
+func (a NodeApp) Unit(manager NodeManager) string {
+	script := "cd " + ShellQuote(a.Dir)
+	if manager.Installed() && ReadNvmrc(a.Dir) != "" {
+		script += " && " + manager.UseCommand("")
+	}
+	script += " && exec " + strings.TrimSpace(a.Command)
+	// systemd expands specifiers and variables even inside quotes
+	script = strings.NewReplacer(`\`, `\\`, "$", "$$", "%", "%%").Replace(script)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n# Command: %s\n", nodeAppMarker, strings.TrimSpace(a.Command))
+	fmt.Fprintf(&b, "[Unit]\nDescription=Node app %s\nAfter=network.target\n\n", a.Name)
+	fmt.Fprintf(&b, "[Service]\nType=simple\nUser=%s\nWorkingDirectory=%s\n", a.User, a.Dir)
+	if a.NodeEnv != "" {
+		fmt.Fprintf(&b, "Environment=NODE_ENV=%s\n", a.NodeEnv)
+	}
+	if a.Port != "" {
+		fmt.Fprintf(&b, "Environment=PORT=%s\n", a.Port)
+	}
+	fmt.Fprintf(&b, "ExecStart=/bin/bash -lc '%s'\n", script)
+	b.WriteString(`Restart=always
+RestartSec=5s
+LimitNOFILE=65535
+
+NoNewPrivileges=true
+PrivateTmp=true
+
+StandardOutput=journal
+StandardError=journal
+
+[Install]
+WantedBy=multi-user.target
+`)
+	return b.String()
+}
+
+// parseNodeApp reads an app back from a unit written by Unit, reporting
+// false for units ravact did not create
+func parseNodeApp(unit, content string) (NodeApp, bool) {
+	if !strings.HasPrefix(content, nodeAppMarker) {
+		return NodeApp{}, false
+	}
+	app := NodeApp{Name: strings.TrimSuffix(strings.TrimPrefix(unit, NodeAppUnitPrefix), ".service")}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "# Command: "):
+			app.Command = strings.TrimPrefix(line, "# Command: ")
+		case strings.HasPrefix(line, "User="):
+			app.User = strings.TrimPrefix(line, "User=")
+		case strings.HasPrefix(line, "WorkingDirectory="):
+			app.Dir = strings.TrimPrefix(line, "WorkingDirectory=")
+		case strings.HasPrefix(line, "Environment=PORT="):
+			app.Port = strings.TrimPrefix(line, "Environment=PORT=")
+		case strings.HasPrefix(line, "Environment=NODE_ENV="):
+			app.NodeEnv = strings.TrimPrefix(line, "Environment=NODE_ENV=")
+		}
+	}
+	return app, true
+}
+
+// ListNodeApps returns the Node apps ravact runs under systemd, with their
+// current state
+func ListNodeApps() ([]NodeApp, error) {
+	entries, err := ReadDir(SystemdLocalUnitDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", SystemdLocalUnitDir, err)
+	}
+	var apps []NodeApp
+	var units []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, NodeAppUnitPrefix) || !strings.HasSuffix(name, ".service") {
+			continue
+		}
+		content, err := ReadFile(filepath.Join(SystemdLocalUnitDir, name))
+		if err != nil {
+			continue
+		}
+		if app, ok := parseNodeApp(name, string(content)); ok {
+			apps = append(apps, app)
+			units = append(units, name)
+		}
+	}
+	if len(apps) == 0 {
+		return nil, nil
+	}
+
+	// Both commands print one line per unit and exit non-zero if any unit
+	// is not active or enabled
+	active, _ := Command("systemctl", append([]string{"is-active"}, units...)...).Output()
+	enabled, _ := Command("systemctl", append([]string{"is-enabled"}, units...)...).Output()
+	activeLines := strings.Split(strings.TrimSpace(string(active)), "\n")
+	enabledLines := strings.Split(strings.TrimSpace(string(enabled)), "\n")
+	for i := range apps {
+		apps[i].Active = "unknown"
+		if i < len(activeLines) && activeLines[i] != "" {
+			apps[i].Active = activeLines[i]
+		}
+		apps[i].Enabled = i < len(enabledLines) && enabledLines[i] == "enabled"
+	}
+	return apps, nil
+}
+
+// NodeAppInstallScript writes the app's unit and starts it, restarting it
+// if it was already running
+func NodeAppInstallScript(app NodeApp, manager NodeManager) (string, error) {
+	if err := app.Validate(); err != nil {
+		return "", err
+	}
+	unit := app.UnitName()
+	path := filepath.Join(SystemdLocalUnitDir, unit)
+	var b strings.Builder
+	b.WriteString("#!/bin/bash\nset -e\n")
+	fmt.Fprintf(&b, "cat > %s <<'RAVACT_UNIT'\n%sRAVACT_UNIT\n", ShellQuote(path), app.Unit(manager))
+	fmt.Fprintf(&b, "systemctl daemon-reload\nsystemctl enable %s\nsystemctl restart %s\n", unit, unit)
+	fmt.Fprintf(&b, "sleep 2\nsystemctl status %s --no-pager -l || true\n", unit)
+	return b.String(), nil
+}
+
+// NodeAppRemoveScript stops an app and deletes its unit
+func NodeAppRemoveScript(app NodeApp) string {
+	unit := app.UnitName()
+	return fmt.Sprintf("systemctl disable --now %s || true\nrm -f %s\nsystemctl daemon-reload\necho '✓ %s removed'\n",
+		unit, ShellQuote(filepath.Join(SystemdLocalUnitDir, unit)), unit)
+}
+
+// PM2Process is one entry of `pm2 jlist`
+type PM2Process struct {
+	ID       int
+	Name     string
+	Status   string // online, stopped, errored, ...
+	Dir      string
+	Restarts int
+	Memory   int64 // Bytes
+	CPU      float64
+}
+
+// ParsePM2List parses `pm2 jlist`, skipping the lines PM2 prints before the
+// JSON (such as the "[PM2] Spawning PM2 daemon" banner)
+func ParsePM2List(data []byte) ([]PM2Process, error) {
+	data = bytes.TrimSpace(data)
+	start := bytes.LastIndex(data, []byte("\n[")) + 1
+	if len(data) == 0 || data[start] != '[' {
+		return nil, fmt.Errorf("unexpected pm2 output: %s", data)
+	}
+	var raw []struct {
+		ID    int    `json:"pm_id"`
+		Name  string `json:"name"`
+		Monit struct {
+			Memory int64   `json:"memory"`
+			CPU    float64 `json:"cpu"`
+		} `json:"monit"`
+		Env struct {
+			Status   string `json:"status"`
+			Cwd      string `json:"pm_cwd"`
+			Restarts int    `json:"restart_time"`
+		} `json:"pm2_env"`
+	}
+	if err := json.NewDecoder(bytes.NewReader(data[start:])).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse pm2 output: %w", err)
+	}
+	processes := make([]PM2Process, 0, len(raw))
+	for _, p := range raw {
+		processes = append(processes, PM2Process{
+			ID:       p.ID,
+			Name:     p.Name,
+			Status:   p.Env.Status,
+			Dir:      p.Env.Cwd,
+			Restarts: p.Env.Restarts,
+			Memory:   p.Monit.Memory,
+			CPU:      p.Monit.CPU,
+		})
+	}
+	return processes, nil
+}
+
+// AsUser wraps a bash script so it runs in a login shell of user, which
+// loads the system-wide Node manager
+func AsUser(user, script string) string {
+	return "sudo -u " + ShellQuote(user) + " -i bash -c " + ShellQuote(script)
+}
+
+// PM2Installed reports whether pm2 is on user's PATH
+func PM2Installed(user string) bool {
+	return Command("sudo", "-u", user, "-i", "bash", "-c", "command -v pm2").Run() == nil
+}
+
+// PM2Processes returns the processes in user's PM2 list
+func PM2Processes(user string) ([]PM2Process, error) {
+	out, err := Command("sudo", "-u", user, "-i", "bash", "-c", "pm2 jlist").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PM2 processes for %s: %w", user, err)
+	}
+	return ParsePM2List(out)
+}
+
+// PM2SetupScript installs pm2 globally and registers `pm2 startup` so the
+// user's saved process list is resurrected on boot
+func PM2SetupScript(user string, manager NodeManager) (string, error) {
+	if !usernamePattern.MatchString(user) {
+		return "", fmt.Errorf("invalid user %q", user)
+	}
+	var b strings.Builder
+	b.WriteString("#!/bin/bash\nset -e\n")
+	if manager.Installed() {
+		b.WriteString(manager.Env() + "\n")
+	}
+	b.WriteString("command -v npm >/dev/null 2>&1 || { echo \"✗ npm not found. Install Node.js first (Service Settings > Node.js Versions).\"; exit 1; }\n")
+	b.WriteString("command -v pm2 >/dev/null 2>&1 || npm install -g pm2\n")
+	if manager.Installed() {
+		fmt.Fprintf(&b, "chmod -R a+rX %s\n", ShellQuote(manager.Dir))
+	}
+	fmt.Fprintf(&b, "HOME_DIR=$(getent passwd %s | cut -d: -f6)\n", ShellQuote(user))
+	fmt.Fprintf(&b, "env PATH=\"$PATH\" pm2 startup systemd -u %s --hp \"$HOME_DIR\"\n", ShellQuote(user))
+	fmt.Fprintf(&b, "%s\n", AsUser(user, "pm2 save --force"))
+	fmt.Fprintf(&b, "echo \"✓ PM2 starts %s's saved processes on boot (pm2-%s.service)\"\n", user, user)
+	return b.String(), nil
+}
+
+// PM2StartScript adds an app to user's PM2 list and saves the list
+func PM2StartScript(app NodeApp) (string, error) {
+	if err := app.Validate(); err != nil {
+		return "", err
+	}
+	var env []string
+	if app.NodeEnv != "" {
+		env = append(env, "NODE_ENV="+app.NodeEnv)
+	}
+	if app.Port != "" {
+		env = append(env, "PORT="+app.Port)
+	}
+	script := "cd " + ShellQuote(app.Dir) + " && "
+	if len(env) > 0 {
+		script += strings.Join(env, " ") + " "
+	}
+	script += fmt.Sprintf("pm2 start %s --name %s && pm2 save", ShellQuote(strings.TrimSpace(app.Command)), ShellQuote(app.Name))
+	return AsUser(app.User, script), nil
+}
+
+// PM2Command returns the shell command for a PM2 action on a process.
+// Changes are saved so they survive a reboot.
+func PM2Command(user, action, name string) string {
+	switch action {
+	case "logs":
+		return AsUser(user, "pm2 logs "+ShellQuote(name)+" --lines 200 --nostream")
+	}
+	return AsUser(user, fmt.Sprintf("pm2 %s %s && pm2 save", action, ShellQuote(name)))
+}
+
+// PathOwner returns the user owning a path, or "" if it cannot be read
+func PathOwner(path string) string {
+	out, err := Command("stat", "-c", "%U", path).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,98 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNodeAppValidate(t *testing.T) {
+	valid := NodeApp{Name: "api", Dir: "/var/www/api", User: "deploy", Command: "npm start", Port: "3000"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected valid app, got %v", err)
+	}
+	for _, bad := range []NodeApp{
+		{Name: "API", Dir: "/var/www/api", User: "deploy", Command: "npm start"},
+		{Name: "api", Dir: "var/www/api", User: "deploy", Command: "npm start"},
+		{Name: "api", Dir: "/var/www/api", User: "root;", Command: "npm start"},
+		{Name: "api", Dir: "/var/www/api", User: "deploy", Command: "node -e 'x'"},
+		{Name: "api", Dir: "/var/www/api", User: "deploy", Command: "npm start", Port: "70000"},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestNodeAppUnitRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".nvmrc"), []byte("20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	app := NodeApp{Name: "web", Dir: dir, User: "deploy", Command: "node server.js --label=50%", Port: "3000", NodeEnv: "production"}
+	unit := app.Unit(NodeManager{Kind: NodeManagerFnm, Dir: "/opt/fnm"})
+
+	for _, want := range []string{
+		"User=deploy",
+		"Environment=PORT=3000",
+		"Environment=NODE_ENV=production",
+		`eval "$$(fnm env --shell bash)" && fnm use --install-if-missing && exec node server.js --label=50%%'`,
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit missing %q:\n%s", want, unit)
+		}
+	}
+
+	parsed, ok := parseNodeApp(app.UnitName(), unit)
+	if !ok {
+		t.Fatal("expected the unit to be recognised")
+	}
+	if parsed != app {
+		t.Errorf("got %+v, want %+v", parsed, app)
+	}
+	if _, ok := parseNodeApp("node-other.service", "[Unit]\nDescription=hand written\n"); ok {
+		t.Error("units without the marker should be ignored")
+	}
+}
+
+func TestNodeAppUnitWithoutNvmrc(t *testing.T) {
+	app := NodeApp{Name: "web", Dir: t.TempDir(), User: "deploy", Command: "npm start"}
+	unit := app.Unit(NodeManager{Kind: NodeManagerNvm, Dir: "/opt/nvm"})
+	if strings.Contains(unit, "nvm use") {
+		t.Errorf("nvm use without a .nvmrc would fail:\n%s", unit)
+	}
+	if !strings.Contains(unit, "&& exec npm start'") {
+		t.Errorf("unexpected ExecStart:\n%s", unit)
+	}
+}
+
+func TestParsePM2List(t *testing.T) {
+	out := `[PM2] Spawning PM2 daemon with pm2_home=/home/deploy/.pm2
+[PM2] PM2 Successfully daemonized
+[{"pm_id":0,"name":"api","monit":{"memory":52428800,"cpu":1.5},"pm2_env":{"status":"online","pm_cwd":"/var/www/api","restart_time":3}}]`
+	processes, err := ParsePM2List([]byte(out))
+	if err != nil {
+		t.Fatalf("ParsePM2List: %v", err)
+	}
+	want := PM2Process{ID: 0, Name: "api", Status: "online", Dir: "/var/www/api", Restarts: 3, Memory: 52428800, CPU: 1.5}
+	if len(processes) != 1 || processes[0] != want {
+		t.Errorf("got %+v, want %+v", processes, want)
+	}
+	if _, err := ParsePM2List([]byte("command not found")); err == nil {
+		t.Error("expected an error without JSON")
+	}
+}
+
+func TestPM2StartScript(t *testing.T) {
+	script, err := PM2StartScript(NodeApp{Name: "api", Dir: "/var/www/api", User: "deploy", Command: "npm run start", Port: "3000"})
+	if err != nil {
+		t.Fatalf("PM2StartScript: %v", err)
+	}
+	if !strings.HasPrefix(script, "sudo -u deploy -i bash -c ") {
+		t.Errorf("expected the script to run as deploy, got %s", script)
+	}
+	if !strings.Contains(script, `PORT=3000 pm2 start '"'"'npm run start'"'"' --name api && pm2 save`) {
+		t.Errorf("unexpected script %s", script)
+	}
+}
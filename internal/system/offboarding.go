@@ -0,0 +1,342 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// What to do with an offboarded user's home directory
+const (
+	OffboardHomeKeep     = "keep"
+	OffboardHomeArchive  = "archive"  // Tar it into OffboardArchiveDir and remove it
+	OffboardHomeReassign = "reassign" // Hand it to another user
+)
+
+// OffboardArchiveDir receives archived home directories
+var OffboardArchiveDir = "/var/backups/ravact/users"
+
+// UserReference is something on the server that still runs as, or acts as,
+// a user
+type UserReference struct {
+	Kind   string // "supervisor", "cron", "git"
+	Where  string // Program name, crontab path, or repository
+	Detail string
+}
+
+// OffboardOptions controls an offboarding
+type OffboardOptions struct {
+	Home       string // OffboardHomeKeep, OffboardHomeArchive, or OffboardHomeReassign
+	ReassignTo string // New owner for OffboardHomeReassign
+}
+
+// OffboardStep is the outcome of one offboarding step
+type OffboardStep struct {
+	Name   string
+	Detail string
+	Err    error
+}
+
+// sessionUsers returns the accounts ravact works through: the SSH login of
+// a remote server, or locally the user running ravact and who ran sudo.
+// Tests replace it.
+var sessionUsers = func() []string {
+	if t, ok := CurrentTransport().(*SSHTransport); ok && t.Server.User != "" {
+		return []string{t.Server.User}
+	}
+	// ssh logs in as the local user when the server names none
+	var users []string
+	if u, err := user.Current(); err == nil {
+		users = append(users, u.Username)
+	}
+	if u := os.Getenv("SUDO_USER"); u != "" {
+		users = append(users, u)
+	}
+	return users
+}
+
+// homeNeighbours returns the other accounts sharing a user's home directory
+// and the nginx site roots. Tests replace it.
+var homeNeighbours = func(u User) (sharers, siteRoots []string) {
+	if users, err := NewUserManager().GetAllUsers(); err == nil {
+		for _, other := range users {
+			if other.Username != u.Username && path.Clean(other.HomeDir) == path.Clean(u.HomeDir) {
+				sharers = append(sharers, other.Username)
+			}
+		}
+	}
+	if sites, err := NewNginxManager().GetAllSites(); err == nil {
+		for _, s := range sites {
+			if s.RootDir != "" {
+				siteRoots = append(siteRoots, s.RootDir)
+			}
+		}
+	}
+	return sharers, siteRoots
+}
+
+// Offboardable refuses root and the account ravact is working through,
+// which offboarding would lock out of the server
+func Offboardable(u User) error {
+	if u.UID == 0 || u.Username == "root" {
+		return fmt.Errorf("cannot offboard root")
+	}
+	for _, name := range sessionUsers() {
+		if name == u.Username {
+			return fmt.Errorf("cannot offboard %s: ravact is logged in as this user", u.Username)
+		}
+	}
+	return nil
+}
+
+// pathWithin reports whether p is dir or inside it
+func pathWithin(p, dir string) bool {
+	p, dir = path.Clean(p), path.Clean(dir)
+	return p == dir || strings.HasPrefix(p, strings.TrimSuffix(dir, "/")+"/")
+}
+
+// OffboardableHome refuses to archive or reassign a home directory that is
+// not the user's own directory under /home: the filesystem root, a shared
+// or web directory, or one holding or inside a site
+func OffboardableHome(u User) error {
+	home := path.Clean(u.HomeDir)
+	if u.HomeDir == "" || home == "/" {
+		return fmt.Errorf("%s has no home directory of its own; keep it", u.Username)
+	}
+	if path.Dir(home) != "/home" {
+		return fmt.Errorf("%s is not a directory under /home; keep it", home)
+	}
+	sharers, siteRoots := homeNeighbours(u)
+	if len(sharers) > 0 {
+		return fmt.Errorf("%s is also the home of %s; keep it", home, strings.Join(sharers, ", "))
+	}
+	for _, root := range siteRoots {
+		if pathWithin(root, home) || pathWithin(home, root) {
+			return fmt.Errorf("%s holds the site root %s; keep it", home, path.Clean(root))
+		}
+	}
+	return nil
+}
+
+// Validate checks the options against the user being offboarded
+func (o OffboardOptions) Validate(u User) error {
+	if err := Offboardable(u); err != nil {
+		return err
+	}
+	switch o.Home {
+	case OffboardHomeKeep:
+		return nil
+	case OffboardHomeArchive:
+	case OffboardHomeReassign:
+		if o.ReassignTo == "" {
+			return fmt.Errorf("choose the user to reassign the home directory to")
+		}
+		if o.ReassignTo == u.Username {
+			return fmt.Errorf("cannot reassign the home directory to %s", u.Username)
+		}
+	default:
+		return fmt.Errorf("unknown home directory action %q", o.Home)
+	}
+	return OffboardableHome(u)
+}
+
+// OffboardUser locks an account, expires its password and account, removes
+// its SSH keys, and deals with its home directory. Every step runs even if
+// an earlier one fails, so one failure does not leave the account open.
+func (um *UserManager) OffboardUser(username string, opts OffboardOptions) ([]OffboardStep, error) {
+	user, err := um.GetUser(username)
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.Validate(*user); err != nil {
+		return nil, err
+	}
+	if opts.Home == OffboardHomeReassign {
+		if _, err := um.GetUser(opts.ReassignTo); err != nil {
+			return nil, err
+		}
+	}
+
+	run := func(name string, args ...string) error {
+		if output, err := Command(name, args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("%s failed: %v - %s", name, err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+
+	var steps []OffboardStep
+	steps = append(steps, OffboardStep{Name: "Lock account", Err: run("usermod", "--lock", username)})
+	// Expiring the account also blocks SSH key logins, which a lock does not
+	steps = append(steps, OffboardStep{Name: "Expire password and account", Err: run("chage", "--lastday", "0", "--expiredate", "0", username)})
+
+	keys := OffboardStep{Name: "Remove SSH keys"}
+	for _, name := range []string{"authorized_keys", "authorized_keys.disabled"} {
+		file := path.Join(user.HomeDir, ".ssh", name)
+		if err := Remove(file); err == nil {
+			keys.Detail = strings.TrimSpace(keys.Detail + " " + file)
+		} else if !os.IsNotExist(err) {
+			keys.Err = err
+		}
+	}
+	if keys.Detail == "" && keys.Err == nil {
+		keys.Detail = "no authorized_keys"
+	}
+	if keys.Err == nil {
+		keys.Err = forgetKeySources(username)
+	}
+	steps = append(steps, keys)
+
+	switch opts.Home {
+	case OffboardHomeArchive:
+		archive := path.Join(OffboardArchiveDir, fmt.Sprintf("%s-%s.tar.gz", username, time.Now().Format("20060102-150405")))
+		step := OffboardStep{Name: "Archive home directory", Detail: archive}
+		if err := MkdirAll(OffboardArchiveDir, 0700); err != nil {
+			step.Err = err
+		} else if err := run("tar", "-czf", archive, "-C", path.Dir(user.HomeDir), path.Base(user.HomeDir)); err != nil {
+			step.Err = err
+		} else {
+			step.Err = run("rm", "-rf", "--one-file-system", user.HomeDir)
+		}
+		steps = append(steps, step)
+	case OffboardHomeReassign:
+		steps = append(steps, OffboardStep{
+			Name:   "Reassign home directory",
+			Detail: fmt.Sprintf("%s now owned by %s", user.HomeDir, opts.ReassignTo),
+			Err:    run("chown", "-R", opts.ReassignTo+":"+opts.ReassignTo, user.HomeDir),
+		})
+	}
+	return steps, nil
+}
+
+// forgetKeySources drops the GitHub/GitLab key sources recorded for a user
+func forgetKeySources(username string) error {
+	if _, err := Stat(StorePath()); err != nil {
+		return nil
+	}
+	return UpdateStore(func(tx *StoreTx) error {
+		for _, key := range tx.Keys(BucketKeySources) {
+			if strings.HasPrefix(key, username+"/") {
+				if err := tx.Delete(BucketKeySources, key); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// FindUserReferences lists the supervisor programs, cron entries, and git
+// meta.systemuser settings that still name a user
+func FindUserReferences(username string) []UserReference {
+	var refs []UserReference
+	if sm := NewSupervisorManager(); sm.IsInstalled() {
+		if programs, err := sm.GetAllPrograms(); err == nil {
+			for _, p := range programs {
+				if p.User == username {
+					refs = append(refs, UserReference{Kind: "supervisor", Where: p.Name, Detail: p.Command})
+				}
+			}
+		}
+	}
+
+	crontabs := Crontabs()
+	paths := make([]string, 0, len(crontabs))
+	for p := range crontabs {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		refs = append(refs, cronReferences(p, crontabs[p], username)...)
+	}
+
+	for _, repo := range gitRepoCandidates() {
+		config, err := ReadFile(path.Join(repo, ".git", "config"))
+		if err != nil {
+			continue
+		}
+		if gitConfigValue(string(config), "meta", "systemuser") == username {
+			refs = append(refs, UserReference{Kind: "git", Where: repo, Detail: "meta.systemuser = " + username})
+		}
+	}
+	return refs
+}
+
+// cronReferences returns the jobs in a crontab that run as username: every
+// job in the user's own crontab, and system crontab lines naming the user
+func cronReferences(file, content, username string) []UserReference {
+	own := (path.Dir(file) == "/var/spool/cron/crontabs" || path.Dir(file) == "/var/spool/cron") && path.Base(file) == username
+	var refs []UserReference
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if strings.Contains(fields[0], "=") && !strings.HasPrefix(fields[0], "@") {
+			continue // Environment setting
+		}
+		userField := 5
+		if strings.HasPrefix(fields[0], "@") {
+			userField = 1
+		}
+		if own || (len(fields) > userField+1 && fields[userField] == username) {
+			refs = append(refs, UserReference{Kind: "cron", Where: file, Detail: line})
+		}
+	}
+	return refs
+}
+
+// gitRepoCandidates returns the directories under /var/www and the nginx
+// site roots (and their parents, for roots like app/public) that hold a
+// git repository
+func gitRepoCandidates() []string {
+	dirs := map[string]bool{}
+	if entries, err := ReadDir("/var/www"); err == nil {
+		for _, e := range entries {
+			if e.IsDir() {
+				dirs[path.Join("/var/www", e.Name())] = true
+			}
+		}
+	}
+	if sites, err := NewNginxManager().GetAllSites(); err == nil {
+		for _, s := range sites {
+			if s.RootDir != "" {
+				dirs[filepath.Clean(s.RootDir)] = true
+				dirs[path.Dir(filepath.Clean(s.RootDir))] = true
+			}
+		}
+	}
+	var repos []string
+	for dir := range dirs {
+		if info, err := Stat(path.Join(dir, ".git")); err == nil && info.IsDir() {
+			repos = append(repos, dir)
+		}
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+// gitConfigValue reads a key from a git config file
+func gitConfigValue(config, section, key string) string {
+	current := ""
+	for _, line := range strings.Split(config, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = ""
+			if fields := strings.Fields(strings.Trim(line, "[]")); len(fields) > 0 {
+				current = strings.ToLower(fields[0])
+			}
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if ok && current == section && strings.EqualFold(strings.TrimSpace(name), key) {
+			return strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return ""
+}
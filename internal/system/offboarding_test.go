@@ -0,0 +1,115 @@
+package system
+
+import "testing"
+
+func TestCronReferences(t *testing.T) {
+	crontab := `SHELL=/bin/bash
+# m h dom mon dow user command
+* * * * * deploy cd /var/www/app && php artisan schedule:run
+0 3 * * * root /usr/local/bin/backup deploy
+@reboot deploy /home/deploy/start.sh
+`
+	refs := cronReferences("/etc/cron.d/app", crontab, "deploy")
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 system cron references, got %+v", refs)
+	}
+	if refs[1].Detail != "@reboot deploy /home/deploy/start.sh" {
+		t.Errorf("unexpected reference: %+v", refs[1])
+	}
+
+	own := "MAILTO=ops@example.com\n*/5 * * * * /home/deploy/sync.sh\n"
+	if refs := cronReferences("/var/spool/cron/crontabs/deploy", own, "deploy"); len(refs) != 1 {
+		t.Errorf("expected every job in the user's crontab, got %+v", refs)
+	}
+	if refs := cronReferences("/var/spool/cron/crontabs/other", own, "deploy"); len(refs) != 0 {
+		t.Errorf("another user's crontab should not match, got %+v", refs)
+	}
+}
+
+func TestGitConfigValue(t *testing.T) {
+	config := `[core]
+	repositoryformatversion = 0
+[remote "origin"]
+	url = git@github.com:acme/app.git
+[meta]
+	systemUser = "deploy"
+[]
+`
+	if got := gitConfigValue(config, "meta", "systemuser"); got != "deploy" {
+		t.Errorf("expected deploy, got %q", got)
+	}
+	if got := gitConfigValue(config, "core", "systemuser"); got != "" {
+		t.Errorf("expected no value outside the section, got %q", got)
+	}
+}
+
+// stubOffboardGuards replaces the session and neighbour lookups for a test
+func stubOffboardGuards(t *testing.T, session, sharers, siteRoots []string) {
+	prevSession, prevNeighbours := sessionUsers, homeNeighbours
+	sessionUsers = func() []string { return session }
+	homeNeighbours = func(User) ([]string, []string) { return sharers, siteRoots }
+	t.Cleanup(func() { sessionUsers, homeNeighbours = prevSession, prevNeighbours })
+}
+
+func TestOffboardOptionsValidate(t *testing.T) {
+	stubOffboardGuards(t, []string{"admin"}, nil, []string{"/var/www/example.com/public"})
+	deploy := User{Username: "deploy", UID: 1001, HomeDir: "/home/deploy"}
+
+	cases := []struct {
+		opts OffboardOptions
+		ok   bool
+	}{
+		{OffboardOptions{Home: OffboardHomeKeep}, true},
+		{OffboardOptions{Home: OffboardHomeArchive}, true},
+		{OffboardOptions{Home: OffboardHomeReassign, ReassignTo: "ops"}, true},
+		{OffboardOptions{Home: OffboardHomeReassign}, false},
+		{OffboardOptions{Home: OffboardHomeReassign, ReassignTo: "deploy"}, false},
+		{OffboardOptions{Home: "delete"}, false},
+	}
+	for _, c := range cases {
+		if err := c.opts.Validate(deploy); (err == nil) != c.ok {
+			t.Errorf("Validate(%+v) = %v, want ok=%v", c.opts, err, c.ok)
+		}
+	}
+}
+
+func TestOffboardRefusesRootAndSessionUser(t *testing.T) {
+	stubOffboardGuards(t, []string{"admin"}, nil, nil)
+	for _, u := range []User{
+		{Username: "root", UID: 0, HomeDir: "/root"},
+		{Username: "toor", UID: 0, HomeDir: "/home/toor"},
+		{Username: "admin", UID: 1000, HomeDir: "/home/admin"},
+	} {
+		if err := (OffboardOptions{Home: OffboardHomeKeep}).Validate(u); err == nil {
+			t.Errorf("expected offboarding %s to be refused", u.Username)
+		}
+	}
+}
+
+func TestOffboardRefusesSharedHomes(t *testing.T) {
+	cases := []struct {
+		name      string
+		user      User
+		sharers   []string
+		siteRoots []string
+	}{
+		{"filesystem root", User{Username: "svc", UID: 998, HomeDir: "/"}, nil, nil},
+		{"no home", User{Username: "svc", UID: 998}, nil, nil},
+		{"web tree", User{Username: "deploy", UID: 1001, HomeDir: "/var/www"}, nil, nil},
+		{"nested under home", User{Username: "deploy", UID: 1001, HomeDir: "/home/apps/deploy"}, nil, nil},
+		{"shared", User{Username: "deploy", UID: 1001, HomeDir: "/home/team"}, []string{"ops"}, nil},
+		{"holds a site", User{Username: "deploy", UID: 1001, HomeDir: "/home/deploy"}, nil, []string{"/home/deploy/example.com/public"}},
+		{"is a site", User{Username: "deploy", UID: 1001, HomeDir: "/home/deploy"}, nil, []string{"/home/deploy"}},
+	}
+	for _, c := range cases {
+		stubOffboardGuards(t, nil, c.sharers, c.siteRoots)
+		for _, opts := range []OffboardOptions{{Home: OffboardHomeArchive}, {Home: OffboardHomeReassign, ReassignTo: "ops"}} {
+			if err := opts.Validate(c.user); err == nil {
+				t.Errorf("%s: expected %s of %q to be refused", c.name, opts.Home, c.user.HomeDir)
+			}
+		}
+		if err := (OffboardOptions{Home: OffboardHomeKeep}).Validate(c.user); err != nil {
+			t.Errorf("%s: expected keeping the home to be allowed: %v", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package system
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PECLExtension is a PHP extension ravact compiles from PECL, for
+// extensions or versions the distribution does not package
+type PECLExtension struct {
+	Name        string // Extension and PECL package name
+	Description string
+	BuildDeps   []string // Debian packages the build needs besides phpX.Y-dev
+	Configure   string   // pecl -D options answering its configure prompts
+	Zend        bool     // Loaded with zend_extension, e.g. xdebug
+	Priority    int      // Load order in mods-available; extensions needing others load later
+}
+
+// PECLExtensions are the extensions offered for building with PECL
+var PECLExtensions = []PECLExtension{
+	{Name: "redis", Description: "Redis client (phpredis)"},
+	{Name: "imagick", Description: "ImageMagick image processing", BuildDeps: []string{"libmagickwand-dev"}},
+	{Name: "swoole", Description: "Coroutine-based async server", BuildDeps: []string{"libssl-dev", "libcurl4-openssl-dev", "libbrotli-dev"},
+		Configure: `enable-openssl="yes" enable-swoole-curl="yes" enable-brotli="yes"`, Priority: 30},
+	{Name: "excimer", Description: "Low-overhead sampling profiler"},
+	{Name: "apcu", Description: "APC User Cache for data caching"},
+	{Name: "igbinary", Description: "Binary serialization"},
+	{Name: "msgpack", Description: "MessagePack serialization"},
+	{Name: "memcached", Description: "Memcached client", BuildDeps: []string{"libmemcached-dev", "zlib1g-dev", "libssl-dev"}, Priority: 25},
+	{Name: "mongodb", Description: "MongoDB driver", BuildDeps: []string{"libssl-dev"}},
+	{Name: "yaml", Description: "YAML parsing and emitting", BuildDeps: []string{"libyaml-dev"}},
+	{Name: "uuid", Description: "UUID generation functions", BuildDeps: []string{"uuid-dev"}},
+	{Name: "ssh2", Description: "SSH2 protocol bindings", BuildDeps: []string{"libssh2-1-dev"}},
+	{Name: "xdebug", Description: "Debugging and profiling", Zend: true},
+	{Name: "pcov", Description: "Code coverage driver"},
+	{Name: "ds", Description: "Data Structures extension"},
+	{Name: "ast", Description: "Abstract Syntax Tree"},
+	{Name: "mailparse", Description: "Email message parsing", Priority: 30},
+	{Name: "event", Description: "libevent bindings", BuildDeps: []string{"libevent-dev", "libssl-dev"}, Priority: 30},
+	{Name: "grpc", Description: "gRPC client library (long build)", BuildDeps: []string{"zlib1g-dev"}},
+}
+
+// phpVersionPattern matches the PHP versions ravact installs, e.g. 8.3
+var phpVersionPattern = regexp.MustCompile(`^[0-9]+\.[0-9]+$`)
+
+// FindPECLExtension returns the catalog entry for an extension
+func FindPECLExtension(name string) (PECLExtension, bool) {
+	for _, ext := range PECLExtensions {
+		if ext.Name == name {
+			return ext, true
+		}
+	}
+	return PECLExtension{}, false
+}
+
+// IniContent is the mods-available file that loads the extension
+func (e PECLExtension) IniContent() string {
+	priority := e.Priority
+	if priority == 0 {
+		priority = 20
+	}
+	directive := "extension"
+	if e.Zend {
+		directive = "zend_extension"
+	}
+	return fmt.Sprintf("; configuration for php %s module, built by ravact with PECL\n; priority=%d\n%s=%s.so\n", e.Name, priority, directive, e.Name)
+}
+
+// PECLBuildDeps returns the Debian packages needed to build the extensions
+// for a PHP version
+func PECLBuildDeps(version string, exts []PECLExtension) []string {
+	seen := map[string]bool{}
+	deps := []string{"php" + version + "-dev", "php-pear", "build-essential", "pkg-config"}
+	var extra []string
+	for _, ext := range exts {
+		for _, dep := range ext.BuildDeps {
+			if !seen[dep] {
+				seen[dep] = true
+				extra = append(extra, dep)
+			}
+		}
+	}
+	sort.Strings(extra)
+	return append(deps, extra...)
+}
+
+// PECLInstallScript returns the script that builds extensions with PECL
+// for a PHP version, enables them for every SAPI, and restarts PHP-FPM.
+// Extensions the version already loads, e.g. from a distribution package,
+// are skipped.
+//
+// pecl remembers one build per extension, not one per PHP version, so the
+// record of earlier builds is dropped (without removing their modules)
+// before building for another version.
+func PECLInstallScript(version string, names []string) (string, error) {
+	if !phpVersionPattern.MatchString(version) {
+		return "", fmt.Errorf("invalid PHP version %q", version)
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("no extensions selected")
+	}
+	var exts []PECLExtension
+	for _, name := range names {
+		ext, ok := FindPECLExtension(name)
+		if !ok {
+			return "", fmt.Errorf("%s is not available from PECL", name)
+		}
+		exts = append(exts, ext)
+	}
+
+	php := "php" + version
+	var b strings.Builder
+	b.WriteString("set -e\nexport DEBIAN_FRONTEND=noninteractive\n")
+	b.WriteString("apt-get update\n")
+	fmt.Fprintf(&b, "apt-get install -y %s\n", strings.Join(PECLBuildDeps(version, exts), " "))
+	fmt.Fprintf(&b, "ext_dir=$(php-config%s --extension-dir)\n", version)
+	for _, ext := range exts {
+		fmt.Fprintf(&b, "\nif %s -m | grep -qix %s; then\n", php, ShellQuote(ext.Name))
+		fmt.Fprintf(&b, "  echo \"%s is already loaded for PHP %s, skipping\"\nelse\n", ext.Name, version)
+		fmt.Fprintf(&b, "  echo \"==> Building %s for PHP %s\"\n", ext.Name, version)
+		fmt.Fprintf(&b, "  pecl uninstall -r %s >/dev/null 2>&1 || true\n", ext.Name)
+		configure := ""
+		if ext.Configure != "" {
+			configure = "-D " + ShellQuote(ext.Configure) + " "
+		}
+		fmt.Fprintf(&b, "  yes '' | pecl -d php_suffix=%s install %s%s\n", version, configure, ext.Name)
+		fmt.Fprintf(&b, "  test -f \"$ext_dir/%s.so\"\n", ext.Name)
+		fmt.Fprintf(&b, "  printf '%%s' %s >/etc/php/%s/mods-available/%s.ini\n", ShellQuote(ext.IniContent()), version, ext.Name)
+		fmt.Fprintf(&b, "  phpenmod -v %s -s ALL %s\nfi\n", version, ext.Name)
+	}
+	fmt.Fprintf(&b, "\nif systemctl cat %s-fpm >/dev/null 2>&1; then\n  systemctl restart %s-fpm\nfi\n", php, php)
+	b.WriteString("echo \"Extensions installed successfully!\"\n")
+	pattern := make([]string, len(exts))
+	for i, ext := range exts {
+		pattern[i] = ext.Name
+	}
+	fmt.Fprintf(&b, "%s -m | grep -iE '^(%s)$'\n", php, strings.Join(pattern, "|"))
+	return b.String(), nil
+}
@@ -0,0 +1,40 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPECLInstallScript(t *testing.T) {
+	script, err := PECLInstallScript("8.3", []string{"imagick", "swoole", "xdebug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"apt-get install -y php8.3-dev php-pear build-essential pkg-config libbrotli-dev libcurl4-openssl-dev libmagickwand-dev libssl-dev\n",
+		"pecl uninstall -r imagick",
+		"yes '' | pecl -d php_suffix=8.3 install imagick\n",
+		`pecl -d php_suffix=8.3 install -D 'enable-openssl="yes" enable-swoole-curl="yes" enable-brotli="yes"' swoole`,
+		"zend_extension=xdebug.so",
+		"; priority=30\nextension=swoole.so",
+		">/etc/php/8.3/mods-available/imagick.ini",
+		"phpenmod -v 8.3 -s ALL imagick",
+		"systemctl restart php8.3-fpm",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script is missing %q:\n%s", want, script)
+		}
+	}
+}
+
+func TestPECLInstallScriptRejects(t *testing.T) {
+	if _, err := PECLInstallScript("8.3; rm -rf /", []string{"redis"}); err == nil {
+		t.Error("expected an invalid PHP version to be rejected")
+	}
+	if _, err := PECLInstallScript("8.3", []string{"not-an-extension"}); err == nil {
+		t.Error("expected an extension missing from the catalog to be rejected")
+	}
+	if _, err := PECLInstallScript("8.3", nil); err == nil {
+		t.Error("expected an empty selection to be rejected")
+	}
+}
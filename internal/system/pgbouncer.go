@@ -0,0 +1,251 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PgBouncer config locations, shared by the Debian and PGDG packages
+var (
+	PgBouncerConfigPath   = "/etc/pgbouncer/pgbouncer.ini"
+	PgBouncerUserlistPath = "/etc/pgbouncer/userlist.txt"
+)
+
+// PgBouncerService is the systemd unit of the pooler
+const PgBouncerService = "pgbouncer"
+
+// PgBouncerPoolModes are the ways server connections are shared, from
+// most to least compatible
+var PgBouncerPoolModes = []string{"session", "transaction", "statement"}
+
+// PgBouncerConfig is the pooling setup ravact writes to pgbouncer.ini
+type PgBouncerConfig struct {
+	ListenAddr      string
+	ListenPort      int
+	PoolMode        string // session, transaction, or statement
+	MaxClientConn   int    // Client connections accepted
+	DefaultPoolSize int    // Server connections per database and user
+	AuthType        string // scram-sha-256 or md5
+	ServerHost      string // PostgreSQL host the pooler connects to
+	ServerPort      int
+}
+
+// DefaultPgBouncerConfig returns a transaction-pooling setup on the
+// standard pgBouncer port in front of a local PostgreSQL
+func DefaultPgBouncerConfig() PgBouncerConfig {
+	return PgBouncerConfig{
+		ListenAddr:      "127.0.0.1",
+		ListenPort:      6432,
+		PoolMode:        "transaction",
+		MaxClientConn:   1000,
+		DefaultPoolSize: 20,
+		AuthType:        "scram-sha-256",
+		ServerHost:      "127.0.0.1",
+		ServerPort:      5432,
+	}
+}
+
+// Validate checks the pool mode, ports, and sizes
+func (c PgBouncerConfig) Validate() error {
+	valid := false
+	for _, mode := range PgBouncerPoolModes {
+		valid = valid || c.PoolMode == mode
+	}
+	if !valid {
+		return fmt.Errorf("unsupported pool mode %q", c.PoolMode)
+	}
+	if c.ListenPort < 1 || c.ListenPort > 65535 || c.ServerPort < 1 || c.ServerPort > 65535 {
+		return fmt.Errorf("ports must be between 1 and 65535")
+	}
+	if c.ListenPort == c.ServerPort && c.ListenAddr == c.ServerHost {
+		return fmt.Errorf("pgBouncer cannot listen on PostgreSQL's own port")
+	}
+	if c.MaxClientConn < 1 || c.DefaultPoolSize < 1 {
+		return fmt.Errorf("connection limits must be at least 1")
+	}
+	if c.AuthType != "scram-sha-256" && c.AuthType != "md5" {
+		return fmt.Errorf("unsupported auth type %q", c.AuthType)
+	}
+	return nil
+}
+
+// Render returns pgbouncer.ini. Every database is forwarded to the same
+// server, so databases created later need no pooler change.
+func (c PgBouncerConfig) Render() string {
+	return fmt.Sprintf(`;; Managed by ravact. Reconfiguring pooling rewrites this file.
+[databases]
+* = host=%s port=%d
+
+[pgbouncer]
+listen_addr = %s
+listen_port = %d
+auth_type = %s
+auth_file = %s
+admin_users = postgres
+pool_mode = %s
+max_client_conn = %d
+default_pool_size = %d
+ignore_startup_parameters = extra_float_digits
+`, c.ServerHost, c.ServerPort, c.ListenAddr, c.ListenPort, c.AuthType, PgBouncerUserlistPath,
+		c.PoolMode, c.MaxClientConn, c.DefaultPoolSize)
+}
+
+// ParsePgBouncerConfig reads pgbouncer.ini, keeping defaults for anything
+// it does not set
+func ParsePgBouncerConfig(content string) PgBouncerConfig {
+	c := DefaultPgBouncerConfig()
+	options := supervisorOptions(content, "pgbouncer")
+	if v, ok := options["listen_addr"]; ok {
+		c.ListenAddr = v
+	}
+	if v, ok := options["pool_mode"]; ok {
+		c.PoolMode = v
+	}
+	if v, ok := options["auth_type"]; ok {
+		c.AuthType = v
+	}
+	for key, target := range map[string]*int{
+		"listen_port":       &c.ListenPort,
+		"max_client_conn":   &c.MaxClientConn,
+		"default_pool_size": &c.DefaultPoolSize,
+	} {
+		if n, err := strconv.Atoi(options[key]); err == nil {
+			*target = n
+		}
+	}
+	// The wildcard entry names the server every database is forwarded to
+	for _, field := range strings.Fields(supervisorOptions(content, "databases")["*"]) {
+		key, value, _ := strings.Cut(field, "=")
+		switch key {
+		case "host":
+			c.ServerHost = value
+		case "port":
+			if n, err := strconv.Atoi(value); err == nil {
+				c.ServerPort = n
+			}
+		}
+	}
+	return c
+}
+
+// PgBouncerUserlist returns userlist.txt for the roles, one quoted name
+// and secret per line
+func PgBouncerUserlist(roles []PostgreSQLRole) string {
+	quote := func(s string) string { return `"` + strings.ReplaceAll(s, `"`, `""`) + `"` }
+	var b strings.Builder
+	for _, r := range roles {
+		fmt.Fprintf(&b, "%s %s\n", quote(r.Name), quote(r.Secret))
+	}
+	return b.String()
+}
+
+// PgBouncerAuthType returns scram-sha-256 when every role has a SCRAM
+// secret, and md5 otherwise; pgBouncer still uses SCRAM for SCRAM secrets
+// under md5
+func PgBouncerAuthType(roles []PostgreSQLRole) string {
+	for _, r := range roles {
+		if !strings.HasPrefix(r.Secret, "SCRAM-SHA-256$") {
+			return "md5"
+		}
+	}
+	return "scram-sha-256"
+}
+
+// PgBouncerManager configures the pgBouncer connection pooler
+type PgBouncerManager struct {
+	configPath   string
+	userlistPath string
+}
+
+// NewPgBouncerManager creates a pgBouncer manager
+func NewPgBouncerManager() *PgBouncerManager {
+	return &PgBouncerManager{
+		configPath:   PgBouncerConfigPath,
+		userlistPath: PgBouncerUserlistPath,
+	}
+}
+
+// IsInstalled checks if pgBouncer is installed
+func (pm *PgBouncerManager) IsInstalled() bool {
+	return Command("which", "pgbouncer").Run() == nil
+}
+
+// GetConfig reads the current pooling setup
+func (pm *PgBouncerManager) GetConfig() (PgBouncerConfig, error) {
+	data, err := ReadFile(pm.configPath)
+	if err != nil {
+		return PgBouncerConfig{}, fmt.Errorf("failed to read %s: %w", pm.configPath, err)
+	}
+	return ParsePgBouncerConfig(string(data)), nil
+}
+
+// owner returns the user:group pgBouncer's config directory belongs to,
+// postgres on Debian and pgbouncer with the PGDG packages
+func (pm *PgBouncerManager) owner() string {
+	output, err := Command("stat", "-c", "%U:%G", filepath.Dir(pm.configPath)).Output()
+	if owner := strings.TrimSpace(string(output)); err == nil && owner != "" && owner != "root:root" {
+		return owner
+	}
+	return "postgres:postgres"
+}
+
+// writeOwned writes a file readable only by pgBouncer's user
+func (pm *PgBouncerManager) writeOwned(path, content string) error {
+	if err := WriteFile(path, []byte(content), 0640); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if output, err := Command("chown", pm.owner(), path).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to set owner of %s: %s", path, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// SaveConfig writes pgbouncer.ini, keeping a .bak of the previous version
+func (pm *PgBouncerManager) SaveConfig(c PgBouncerConfig) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if existing, err := ReadFile(pm.configPath); err == nil {
+		if err := WriteFile(pm.configPath+".bak", existing, 0640); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", pm.configPath, err)
+		}
+	}
+	return pm.writeOwned(pm.configPath, c.Render())
+}
+
+// SyncUsers writes userlist.txt from PostgreSQL's login roles
+func (pm *PgBouncerManager) SyncUsers(roles []PostgreSQLRole) error {
+	if len(roles) == 0 {
+		return fmt.Errorf("no PostgreSQL roles with a password; set one first")
+	}
+	return pm.writeOwned(pm.userlistPath, PgBouncerUserlist(roles))
+}
+
+// Restart applies the configuration and enables pgBouncer at boot
+func (pm *PgBouncerManager) Restart() error {
+	for _, args := range [][]string{{"enable", PgBouncerService}, {"restart", PgBouncerService}} {
+		if output, err := Command("systemctl", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to %s pgBouncer: %s", args[0], strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
+
+// Reload rereads the config and userlist without dropping clients
+func (pm *PgBouncerManager) Reload() error {
+	if output, err := Command("systemctl", "reload", PgBouncerService).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to reload pgBouncer: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Status returns the state of the pgBouncer unit, e.g. active or failed
+func (pm *PgBouncerManager) Status() string {
+	output, _ := Command("systemctl", "is-active", PgBouncerService).Output()
+	if status := strings.TrimSpace(string(output)); status != "" {
+		return status
+	}
+	return "unknown"
+}
@@ -0,0 +1,57 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPgBouncerConfigRoundTrip(t *testing.T) {
+	c := DefaultPgBouncerConfig()
+	c.PoolMode = "session"
+	c.ListenPort = 6433
+	c.DefaultPoolSize = 40
+	c.ServerPort = 5433
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	content := c.Render()
+	if !strings.Contains(content, "* = host=127.0.0.1 port=5433") {
+		t.Errorf("missing wildcard database:\n%s", content)
+	}
+	if got := ParsePgBouncerConfig(content); got != c {
+		t.Errorf("round trip = %+v, want %+v", got, c)
+	}
+}
+
+func TestPgBouncerConfigValidate(t *testing.T) {
+	for name, change := range map[string]func(*PgBouncerConfig){
+		"pool mode": func(c *PgBouncerConfig) { c.PoolMode = "pipeline" },
+		"same port": func(c *PgBouncerConfig) { c.ListenPort = c.ServerPort },
+		"pool size": func(c *PgBouncerConfig) { c.DefaultPoolSize = 0 },
+		"auth type": func(c *PgBouncerConfig) { c.AuthType = "trust" },
+	} {
+		c := DefaultPgBouncerConfig()
+		change(&c)
+		if c.Validate() == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPgBouncerUserlist(t *testing.T) {
+	roles := parsePostgreSQLRoles("app\tSCRAM-SHA-256$4096:abc\nodd\"name\tmd5123\n\nbroken\n")
+	if len(roles) != 2 {
+		t.Fatalf("expected 2 roles, got %+v", roles)
+	}
+	want := "\"app\" \"SCRAM-SHA-256$4096:abc\"\n\"odd\"\"name\" \"md5123\"\n"
+	if got := PgBouncerUserlist(roles); got != want {
+		t.Errorf("userlist = %q, want %q", got, want)
+	}
+	if got := PgBouncerAuthType(roles); got != "md5" {
+		t.Errorf("auth type with an md5 secret = %q", got)
+	}
+	if got := PgBouncerAuthType(roles[:1]); got != "scram-sha-256" {
+		t.Errorf("auth type with only SCRAM secrets = %q", got)
+	}
+}
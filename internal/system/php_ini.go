@@ -0,0 +1,207 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PHPIniOverrideFile is the conf.d file ravact writes tuned settings to. It
+// sorts after 99-ravact-opcache.ini so tuning wins over hardening defaults.
+const PHPIniOverrideFile = "99-ravact-tuning.ini"
+
+var phpIniSizePattern = regexp.MustCompile(`^(-1|[0-9]+[KMG]?)$`)
+
+// PHPIniKnob is a php.ini setting exposed for tuning
+type PHPIniKnob struct {
+	Key     string
+	Title   string
+	Kind    string // "size", "int", "bool", or "path"
+	Default string // PHP's built-in default, shown when php.ini does not set it
+	Group   string
+}
+
+// PHPIniKnobs are the settings most often tuned per server
+var PHPIniKnobs = []PHPIniKnob{
+	{"memory_limit", "Memory Limit", "size", "128M", "Limits & Uploads"},
+	{"max_execution_time", "Max Execution Time (seconds)", "int", "30", "Limits & Uploads"},
+	{"max_input_vars", "Max Input Vars", "int", "1000", "Limits & Uploads"},
+	{"upload_max_filesize", "Upload Max Filesize", "size", "2M", "Limits & Uploads"},
+	{"post_max_size", "Post Max Size", "size", "8M", "Limits & Uploads"},
+	{"opcache.enable", "OPcache", "bool", "1", "OPcache"},
+	{"opcache.memory_consumption", "OPcache Memory (MB)", "int", "128", "OPcache"},
+	{"opcache.interned_strings_buffer", "OPcache Interned Strings (MB)", "int", "8", "OPcache"},
+	{"opcache.max_accelerated_files", "OPcache Max Files", "int", "10000", "OPcache"},
+	{"opcache.validate_timestamps", "OPcache Validate Timestamps", "bool", "1", "OPcache"},
+	{"opcache.revalidate_freq", "OPcache Revalidate Frequency (seconds)", "int", "2", "OPcache"},
+	{"display_errors", "Display Errors", "bool", "0", "Error Logging"},
+	{"log_errors", "Log Errors", "bool", "1", "Error Logging"},
+	{"error_log", "Error Log File", "path", "", "Error Logging"},
+}
+
+// Validate checks a value for the knob's kind; empty leaves php.ini's value
+func (k PHPIniKnob) Validate(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	switch k.Kind {
+	case "size":
+		if !phpIniSizePattern.MatchString(value) {
+			return fmt.Errorf("%s must be a size such as 256M, or -1 for no limit", k.Key)
+		}
+	case "int":
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%s must be a whole number", k.Key)
+		}
+	case "bool":
+		if _, ok := phpIniBool(value); !ok {
+			return fmt.Errorf("%s must be On or Off", k.Key)
+		}
+	case "path":
+		if !filepath.IsAbs(value) {
+			return fmt.Errorf("%s must be an absolute path", k.Key)
+		}
+	}
+	return nil
+}
+
+// phpIniBool normalises php.ini booleans to 1 or 0
+func phpIniBool(value string) (string, bool) {
+	switch strings.ToLower(strings.Trim(value, `"'`)) {
+	case "1", "on", "yes", "true":
+		return "1", true
+	case "0", "off", "no", "false", "":
+		return "0", true
+	}
+	return "", false
+}
+
+// PHPIniDir returns the configuration directory of a PHP version's SAPI
+// ("fpm" or "cli")
+func PHPIniDir(version, sapi string) string {
+	return filepath.Join(PHPConfigDir, version, sapi)
+}
+
+// ReadPHPIni returns the effective values of the tuning knobs for a SAPI:
+// php.ini, then each conf.d file in load order
+func ReadPHPIni(version, sapi string) (map[string]string, error) {
+	dir := PHPIniDir(version, sapi)
+	data, err := ReadFile(filepath.Join(dir, "php.ini"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s php.ini: %w", sapi, err)
+	}
+	values := map[string]string{}
+	parsePHPIni(string(data), values)
+
+	if entries, err := ReadDir(filepath.Join(dir, "conf.d")); err == nil {
+		var names []string
+		for _, e := range entries {
+			if strings.HasSuffix(e.Name(), ".ini") {
+				names = append(names, e.Name())
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if data, err := ReadFile(filepath.Join(dir, "conf.d", name)); err == nil {
+				parsePHPIni(string(data), values)
+			}
+		}
+	}
+	return values, nil
+}
+
+// parsePHPIni records the knob settings in an ini file, later ones winning
+func parsePHPIni(content string, values map[string]string) {
+	known := map[string]PHPIniKnob{}
+	for _, k := range PHPIniKnobs {
+		known[k.Key] = k
+	}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "[") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		knob, managed := known[key]
+		if !managed {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if i := strings.Index(value, " ;"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		value = strings.Trim(value, `"`)
+		if knob.Kind == "bool" {
+			if b, ok := phpIniBool(value); ok {
+				value = b
+			}
+		}
+		values[key] = value
+	}
+}
+
+// RenderPHPIniOverride returns the override file for the given values,
+// skipping knobs left empty
+func RenderPHPIniOverride(values map[string]string) string {
+	var b strings.Builder
+	b.WriteString("; Managed by ravact: PHP tuning\n")
+	for _, k := range PHPIniKnobs {
+		value := strings.TrimSpace(values[k.Key])
+		if value == "" {
+			continue
+		}
+		if k.Kind == "bool" {
+			value, _ = phpIniBool(value)
+		}
+		if k.Kind == "path" {
+			value = `"` + value + `"`
+		}
+		fmt.Fprintf(&b, "%s = %s\n", k.Key, value)
+	}
+	return b.String()
+}
+
+// PHPIniOverridePath returns the override file of a PHP version's SAPI
+func PHPIniOverridePath(version, sapi string) string {
+	return filepath.Join(PHPIniDir(version, sapi), "conf.d", PHPIniOverrideFile)
+}
+
+// PHPIniScript writes the override to the FPM conf.d, and the CLI's when
+// asked, then restarts PHP-FPM. The previous overrides are restored if
+// php-fpm rejects the new ones.
+func PHPIniScript(version string, values map[string]string, cli bool) string {
+	override := RenderPHPIniOverride(values)
+	sapis := []string{"fpm"}
+	if cli {
+		sapis = append(sapis, "cli")
+	}
+
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	for _, sapi := range sapis {
+		path := ShellQuote(PHPIniOverridePath(version, sapi))
+		fmt.Fprintf(&b, "echo %s\n", ShellQuote("==> Writing "+sapi+" overrides to "+PHPIniOverridePath(version, sapi)))
+		fmt.Fprintf(&b, "if [ -f %s ]; then cp %s %s.bak; else rm -f %s.bak; fi\n", path, path, path, path)
+		fmt.Fprintf(&b, "cat > %s <<'EOF'\n%sEOF\n", path, override)
+	}
+
+	fmt.Fprintf(&b, "if ! php-fpm%s -t; then\n", version)
+	for _, sapi := range sapis {
+		path := ShellQuote(PHPIniOverridePath(version, sapi))
+		fmt.Fprintf(&b, "    if [ -f %s.bak ]; then mv %s.bak %s; else rm -f %s; fi\n", path, path, path, path)
+	}
+	b.WriteString("    echo 'PHP-FPM rejected the settings; restored the previous overrides'\n    exit 1\nfi\n")
+	for _, sapi := range sapis {
+		fmt.Fprintf(&b, "rm -f %s.bak\n", ShellQuote(PHPIniOverridePath(version, sapi)))
+	}
+	fmt.Fprintf(&b, "echo %s\nsystemctl restart php%s-fpm\n", ShellQuote("==> Restarting php"+version+"-fpm"), version)
+	return b.String()
+}
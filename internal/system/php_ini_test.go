@@ -0,0 +1,77 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadPHPIni(t *testing.T) {
+	old := PHPConfigDir
+	PHPConfigDir = t.TempDir()
+	t.Cleanup(func() { PHPConfigDir = old })
+
+	dir := PHPIniDir("8.3", "fpm")
+	os.MkdirAll(filepath.Join(dir, "conf.d"), 0755)
+	os.WriteFile(filepath.Join(dir, "php.ini"), []byte("[PHP]\nmemory_limit = 128M\n;upload_max_filesize = 64M\nupload_max_filesize = 2M ; default\ndisplay_errors = Off\nerror_log = \"/var/log/php.log\"\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "conf.d", "10-opcache.ini"), []byte("opcache.enable=1\nmemory_limit=256M\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "conf.d", PHPIniOverrideFile), []byte("memory_limit = 512M\n"), 0644)
+
+	values, err := ReadPHPIni("8.3", "fpm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"memory_limit": "512M", "upload_max_filesize": "2M", "display_errors": "0", "opcache.enable": "1", "error_log": "/var/log/php.log"}
+	for key, value := range want {
+		if values[key] != value {
+			t.Errorf("%s = %q, want %q", key, values[key], value)
+		}
+	}
+	if _, err := ReadPHPIni("8.3", "cli"); err == nil {
+		t.Error("expected an error for a missing CLI php.ini")
+	}
+}
+
+func TestPHPIniKnobValidate(t *testing.T) {
+	knobs := map[string]PHPIniKnob{}
+	for _, k := range PHPIniKnobs {
+		knobs[k.Key] = k
+	}
+	valid := map[string]string{"memory_limit": "-1", "post_max_size": "64M", "max_input_vars": "3000", "opcache.enable": "On", "error_log": ""}
+	for key, value := range valid {
+		if err := knobs[key].Validate(value); err != nil {
+			t.Errorf("%s=%s: %v", key, value, err)
+		}
+	}
+	invalid := map[string]string{"memory_limit": "lots", "max_execution_time": "30s", "display_errors": "maybe", "error_log": "php.log"}
+	for key, value := range invalid {
+		if err := knobs[key].Validate(value); err == nil {
+			t.Errorf("%s=%s: expected an error", key, value)
+		}
+	}
+}
+
+func TestPHPIniScript(t *testing.T) {
+	values := map[string]string{"memory_limit": "256M", "display_errors": "Off", "error_log": "/var/log/php.log", "max_input_vars": ""}
+	override := RenderPHPIniOverride(values)
+	if override != "; Managed by ravact: PHP tuning\nmemory_limit = 256M\ndisplay_errors = 0\nerror_log = \"/var/log/php.log\"\n" {
+		t.Errorf("unexpected override:\n%s", override)
+	}
+
+	script := PHPIniScript("8.3", values, true)
+	for _, want := range []string{
+		"cat > /etc/php/8.3/fpm/conf.d/99-ravact-tuning.ini <<'EOF'",
+		"cat > /etc/php/8.3/cli/conf.d/99-ravact-tuning.ini <<'EOF'",
+		"if ! php-fpm8.3 -t; then",
+		"mv /etc/php/8.3/cli/conf.d/99-ravact-tuning.ini.bak",
+		"systemctl restart php8.3-fpm",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+	if strings.Contains(PHPIniScript("8.3", values, false), "/cli/") {
+		t.Error("CLI overrides should only be written when asked")
+	}
+}
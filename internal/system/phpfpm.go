@@ -2,8 +2,6 @@ package system
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -23,6 +21,8 @@ type PHPFPMPool struct {
 	PMMinSpareServers   int
 	PMMaxSpareServers   int
 	PMMaxRequests       int
+	Slowlog             string
+	RequestSlowlogTimeout string
 	ConfigPath          string
 	PHPVersion          string
 }
@@ -52,7 +52,7 @@ func (p *PHPFPMManager) DetectPHPVersion() (string, error) {
 	
 	for _, ver := range versions {
 		poolDir := fmt.Sprintf("/etc/php/%s/fpm/pool.d", ver)
-		if _, err := os.Stat(poolDir); err == nil {
+		if _, err := Stat(poolDir); err == nil {
 			p.phpVersion = ver
 			p.poolDir = poolDir
 			return ver, nil
@@ -64,18 +64,21 @@ func (p *PHPFPMManager) DetectPHPVersion() (string, error) {
 
 // ListPools returns all configured PHP-FPM pools
 func (p *PHPFPMManager) ListPools() ([]PHPFPMPool, error) {
-	if _, err := os.Stat(p.poolDir); err != nil {
+	if _, err := Stat(p.poolDir); err != nil {
 		return nil, fmt.Errorf("pool directory not found: %s", p.poolDir)
 	}
 
-	files, err := filepath.Glob(filepath.Join(p.poolDir, "*.conf"))
+	entries, err := ReadDir(p.poolDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pool files: %w", err)
 	}
 
 	pools := make([]PHPFPMPool, 0)
-	for _, file := range files {
-		pool, err := p.ReadPool(filepath.Base(file))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".conf") {
+			continue
+		}
+		pool, err := p.ReadPool(entry.Name())
 		if err != nil {
 			continue // Skip invalid pools
 		}
@@ -93,7 +96,7 @@ func (p *PHPFPMManager) ReadPool(poolName string) (*PHPFPMPool, error) {
 	}
 
 	configPath := filepath.Join(p.poolDir, poolName)
-	data, err := os.ReadFile(configPath)
+	data, err := ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read pool config: %w", err)
 	}
@@ -174,6 +177,10 @@ func (p *PHPFPMManager) ReadPool(poolName string) (*PHPFPMPool, error) {
 			fmt.Sscanf(value, "%d", &pool.PMMaxSpareServers)
 		case "pm.max_requests":
 			fmt.Sscanf(value, "%d", &pool.PMMaxRequests)
+		case "slowlog":
+			pool.Slowlog = value
+		case "request_slowlog_timeout":
+			pool.RequestSlowlogTimeout = value
 		}
 	}
 
@@ -228,7 +235,7 @@ func (p *PHPFPMManager) CreatePool(pool *PHPFPMPool) error {
 	pool.ConfigPath = configPath
 
 	// Check if pool already exists
-	if _, err := os.Stat(configPath); err == nil {
+	if _, err := Stat(configPath); err == nil {
 		return fmt.Errorf("pool '%s' already exists", pool.Name)
 	}
 
@@ -236,7 +243,7 @@ func (p *PHPFPMManager) CreatePool(pool *PHPFPMPool) error {
 	config := p.generatePoolConfig(pool)
 
 	// Write config file
-	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+	if err := WriteFile(configPath, []byte(config), 0644); err != nil {
 		return fmt.Errorf("failed to write pool config: %w", err)
 	}
 
@@ -253,23 +260,23 @@ func (p *PHPFPMManager) UpdatePool(pool *PHPFPMPool) error {
 	pool.ConfigPath = configPath
 
 	// Check if pool exists
-	if _, err := os.Stat(configPath); err != nil {
+	if _, err := Stat(configPath); err != nil {
 		return fmt.Errorf("pool '%s' not found", pool.Name)
 	}
 
 	// Backup existing config
 	backupPath := configPath + ".bak"
-	data, _ := os.ReadFile(configPath)
-	os.WriteFile(backupPath, data, 0644)
+	data, _ := ReadFile(configPath)
+	WriteFile(backupPath, data, 0644)
 
 	// Generate new pool configuration
 	config := p.generatePoolConfig(pool)
 
 	// Write config file
-	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+	if err := WriteFile(configPath, []byte(config), 0644); err != nil {
 		// Restore backup on failure
 		if data != nil {
-			os.WriteFile(configPath, data, 0644)
+			WriteFile(configPath, data, 0644)
 		}
 		return fmt.Errorf("failed to write pool config: %w", err)
 	}
@@ -291,12 +298,12 @@ func (p *PHPFPMManager) DeletePool(poolName string) error {
 	configPath := filepath.Join(p.poolDir, poolName+".conf")
 
 	// Check if pool exists
-	if _, err := os.Stat(configPath); err != nil {
+	if _, err := Stat(configPath); err != nil {
 		return fmt.Errorf("pool '%s' not found", poolName)
 	}
 
 	// Delete the config file
-	if err := os.Remove(configPath); err != nil {
+	if err := Remove(configPath); err != nil {
 		return fmt.Errorf("failed to delete pool: %w", err)
 	}
 
@@ -334,6 +341,15 @@ func (p *PHPFPMManager) generatePoolConfig(pool *PHPFPMPool) string {
 	}
 	
 	sb.WriteString(fmt.Sprintf("pm.max_requests = %d\n\n", pool.PMMaxRequests))
+
+	if pool.Slowlog != "" {
+		sb.WriteString("; Log the stack of requests slower than the timeout\n")
+		sb.WriteString(fmt.Sprintf("slowlog = %s\n", pool.Slowlog))
+		if pool.RequestSlowlogTimeout != "" {
+			sb.WriteString(fmt.Sprintf("request_slowlog_timeout = %s\n", pool.RequestSlowlogTimeout))
+		}
+		sb.WriteString("\n")
+	}
 	
 	sb.WriteString("; Additional settings\n")
 	sb.WriteString("pm.status_path = /status\n")
@@ -346,7 +362,7 @@ func (p *PHPFPMManager) generatePoolConfig(pool *PHPFPMPool) string {
 // RestartService restarts the PHP-FPM service
 func (p *PHPFPMManager) RestartService() error {
 	serviceName := fmt.Sprintf("php%s-fpm", p.phpVersion)
-	cmd := exec.Command("systemctl", "restart", serviceName)
+	cmd := Command("systemctl", "restart", serviceName)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to restart PHP-FPM: %s", string(output))
@@ -357,7 +373,7 @@ func (p *PHPFPMManager) RestartService() error {
 // ReloadService reloads the PHP-FPM service (graceful reload)
 func (p *PHPFPMManager) ReloadService() error {
 	serviceName := fmt.Sprintf("php%s-fpm", p.phpVersion)
-	cmd := exec.Command("systemctl", "reload", serviceName)
+	cmd := Command("systemctl", "reload", serviceName)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to reload PHP-FPM: %s", string(output))
@@ -368,7 +384,7 @@ func (p *PHPFPMManager) ReloadService() error {
 // GetStatus returns the PHP-FPM service status
 func (p *PHPFPMManager) GetStatus() (string, error) {
 	serviceName := fmt.Sprintf("php%s-fpm", p.phpVersion)
-	cmd := exec.Command("systemctl", "status", serviceName)
+	cmd := Command("systemctl", "status", serviceName)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return string(output), nil
@@ -379,14 +395,14 @@ func (p *PHPFPMManager) GetStatus() (string, error) {
 // IsInstalled checks if PHP-FPM is installed
 func (p *PHPFPMManager) IsInstalled() bool {
 	serviceName := fmt.Sprintf("php%s-fpm", p.phpVersion)
-	cmd := exec.Command("systemctl", "list-unit-files", serviceName+".service")
+	cmd := Command("systemctl", "list-unit-files", serviceName+".service")
 	output, err := cmd.Output()
 	return err == nil && strings.Contains(string(output), serviceName)
 }
 
 // GetVersion returns the PHP version
 func (p *PHPFPMManager) GetVersion() (string, error) {
-	cmd := exec.Command("php", "--version")
+	cmd := Command("php", "--version")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
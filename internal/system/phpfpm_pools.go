@@ -0,0 +1,324 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PHPConfigDir holds one directory per installed PHP version
+var PHPConfigDir = "/etc/php"
+
+var (
+	poolNamePattern       = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+	slowlogTimeoutPattern = regexp.MustCompile(`^[0-9]+[smh]?$`)
+	fastcgiPassSocket     = regexp.MustCompile(`(fastcgi_pass\s+)unix:[^;\s]+(\s*;)`)
+	socketVersionPattern  = regexp.MustCompile(`^php([0-9]+\.[0-9]+)`)
+)
+
+// PHPFPMVersions returns the PHP versions with an FPM pool directory
+func PHPFPMVersions() []string {
+	entries, err := ReadDir(PHPConfigDir)
+	if err != nil {
+		return nil
+	}
+	var versions []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, err := Stat(filepath.Join(PHPConfigDir, e.Name(), "fpm", "pool.d")); err == nil {
+			versions = append(versions, e.Name())
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versionLess(versions[j], versions[i]) })
+	return versions
+}
+
+// versionLess compares dotted version numbers such as 8.2 and 8.10
+func versionLess(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		var x, y int
+		fmt.Sscanf(as[i], "%d", &x)
+		fmt.Sscanf(bs[i], "%d", &y)
+		if x != y {
+			return x < y
+		}
+	}
+	return len(as) < len(bs)
+}
+
+// ListAllPools returns the pools of every installed PHP-FPM version, newest
+// version first
+func ListAllPools() []PHPFPMPool {
+	var pools []PHPFPMPool
+	for _, version := range PHPFPMVersions() {
+		versionPools, err := NewPHPFPMManager(version).ListPools()
+		if err == nil {
+			pools = append(pools, versionPools...)
+		}
+	}
+	return pools
+}
+
+// SitePool returns the suggested pool for a site: its own user, socket,
+// and slow log
+func SitePool(siteName, phpVersion, user string) PHPFPMPool {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, siteName)
+	if user == "" {
+		user = "www-data"
+	}
+	return PHPFPMPool{
+		Name:                  name,
+		PHPVersion:            phpVersion,
+		User:                  user,
+		Group:                 user,
+		Listen:                fmt.Sprintf("/run/php/php%s-%s-fpm.sock", phpVersion, name),
+		ListenOwner:           "www-data",
+		ListenGroup:           "www-data",
+		ListenMode:            "0660",
+		PM:                    "ondemand",
+		PMMaxChildren:         5,
+		PMStartServers:        2,
+		PMMinSpareServers:     1,
+		PMMaxSpareServers:     3,
+		PMMaxRequests:         500,
+		Slowlog:               fmt.Sprintf("/var/log/php%s-fpm-%s-slow.log", phpVersion, name),
+		RequestSlowlogTimeout: "5s",
+	}
+}
+
+// Validate checks a pool against the rules php-fpm enforces at startup
+func (pool PHPFPMPool) Validate() error {
+	if !poolNamePattern.MatchString(pool.Name) {
+		return fmt.Errorf("invalid pool name %q", pool.Name)
+	}
+	for label, name := range map[string]string{"user": pool.User, "group": pool.Group} {
+		if name != "" && !usernamePattern.MatchString(name) {
+			return fmt.Errorf("invalid %s %q", label, name)
+		}
+	}
+	if pool.PMMaxChildren < 1 {
+		return fmt.Errorf("pm.max_children must be at least 1")
+	}
+	if pool.PMMaxRequests < 0 {
+		return fmt.Errorf("pm.max_requests cannot be negative")
+	}
+	switch pool.PM {
+	case "static", "ondemand":
+	case "dynamic":
+		if pool.PMMinSpareServers < 1 || pool.PMMaxSpareServers < 1 {
+			return fmt.Errorf("spare servers must be at least 1")
+		}
+		if pool.PMMinSpareServers > pool.PMMaxSpareServers {
+			return fmt.Errorf("pm.min_spare_servers (%d) cannot be greater than pm.max_spare_servers (%d)", pool.PMMinSpareServers, pool.PMMaxSpareServers)
+		}
+		if pool.PMMaxSpareServers > pool.PMMaxChildren {
+			return fmt.Errorf("pm.max_spare_servers (%d) cannot be greater than pm.max_children (%d)", pool.PMMaxSpareServers, pool.PMMaxChildren)
+		}
+		if pool.PMStartServers < pool.PMMinSpareServers || pool.PMStartServers > pool.PMMaxSpareServers {
+			return fmt.Errorf("pm.start_servers must be between pm.min_spare_servers and pm.max_spare_servers")
+		}
+	default:
+		return fmt.Errorf("pm must be static, dynamic, or ondemand")
+	}
+	if pool.RequestSlowlogTimeout != "" && !slowlogTimeoutPattern.MatchString(pool.RequestSlowlogTimeout) {
+		return fmt.Errorf("invalid slow log timeout %q: use seconds such as 5s", pool.RequestSlowlogTimeout)
+	}
+	if pool.RequestSlowlogTimeout != "" && pool.RequestSlowlogTimeout != "0" && pool.Slowlog == "" {
+		return fmt.Errorf("a slow log path is required when the slow log timeout is set")
+	}
+	if pool.Slowlog != "" && !filepath.IsAbs(pool.Slowlog) {
+		return fmt.Errorf("slow log path must be absolute")
+	}
+	return nil
+}
+
+// poolDirectives returns the directives ravact manages in a pool file
+func (pool PHPFPMPool) poolDirectives() [][2]string {
+	directives := [][2]string{
+		{"user", pool.User},
+		{"group", pool.Group},
+		{"pm", pool.PM},
+		{"pm.max_children", fmt.Sprint(pool.PMMaxChildren)},
+	}
+	if pool.PM == "dynamic" {
+		directives = append(directives,
+			[2]string{"pm.start_servers", fmt.Sprint(pool.PMStartServers)},
+			[2]string{"pm.min_spare_servers", fmt.Sprint(pool.PMMinSpareServers)},
+			[2]string{"pm.max_spare_servers", fmt.Sprint(pool.PMMaxSpareServers)},
+		)
+	}
+	directives = append(directives,
+		[2]string{"pm.max_requests", fmt.Sprint(pool.PMMaxRequests)},
+		[2]string{"slowlog", pool.Slowlog},
+		[2]string{"request_slowlog_timeout", pool.RequestSlowlogTimeout},
+	)
+	return directives
+}
+
+// setPoolDirectives updates directives in a pool file in place, keeping
+// comments and any settings ravact does not manage. Directives with an
+// empty value are commented out; missing ones are appended to the pool
+// section.
+func setPoolDirectives(content string, directives [][2]string) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	done := map[string]bool{}
+	values := map[string]string{}
+	for _, d := range directives {
+		values[d[0]] = d[1]
+	}
+
+	inPool := false
+	insertAt := len(lines)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			if inPool {
+				insertAt = i
+				break
+			}
+			inPool = true
+			continue
+		}
+		if !inPool || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+		key, _, ok := strings.Cut(trimmed, "=")
+		key = strings.TrimSpace(key)
+		value, managed := values[key]
+		if !ok || !managed {
+			continue
+		}
+		if done[key] || value == "" {
+			lines[i] = ";" + line
+		} else {
+			lines[i] = key + " = " + value
+		}
+		done[key] = true
+	}
+
+	var missing []string
+	for _, d := range directives {
+		if !done[d[0]] && d[1] != "" {
+			missing = append(missing, d[0]+" = "+d[1])
+		}
+	}
+	// Keep appended settings above any blank lines that end the section
+	for insertAt > 0 && strings.TrimSpace(lines[insertAt-1]) == "" {
+		insertAt--
+	}
+	lines = append(lines[:insertAt], append(missing, lines[insertAt:]...)...)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// TestConfig runs php-fpm's configuration test for this version
+func (p *PHPFPMManager) TestConfig() error {
+	output, err := Command("php-fpm"+p.phpVersion, "-t").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("php-fpm%s config test failed: %s", p.phpVersion, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// SavePool creates or updates a pool, checks the configuration, and reloads
+// this PHP version only. A new pool that fails the test is removed again and
+// an edited one is restored.
+func (p *PHPFPMManager) SavePool(pool *PHPFPMPool, create bool) error {
+	if err := pool.Validate(); err != nil {
+		return err
+	}
+	var previous []byte
+	if create {
+		if err := p.CreatePool(pool); err != nil {
+			return err
+		}
+	} else {
+		// The file name can differ from the [section] name
+		if pool.ConfigPath == "" {
+			pool.ConfigPath = filepath.Join(p.poolDir, pool.Name+".conf")
+		}
+		var err error
+		if previous, err = ReadFile(pool.ConfigPath); err != nil {
+			return fmt.Errorf("pool '%s' not found", pool.Name)
+		}
+		if err := WriteFile(pool.ConfigPath, []byte(setPoolDirectives(string(previous), pool.poolDirectives())), 0644); err != nil {
+			return fmt.Errorf("failed to write pool config: %w", err)
+		}
+	}
+
+	if err := p.TestConfig(); err != nil {
+		if create {
+			_ = Remove(pool.ConfigPath)
+		} else {
+			_ = WriteFile(pool.ConfigPath, previous, 0644)
+		}
+		return err
+	}
+	return p.ReloadService()
+}
+
+// PlanSitePHPSocket points every fastcgi_pass of a site at a PHP-FPM socket
+func (nm *NginxManager) PlanSitePHPSocket(siteName, socket string) (NginxChange, error) {
+	configPath := filepath.Join(nm.sitesAvailable, siteName)
+	content, err := ReadFile(configPath)
+	if err != nil {
+		return NginxChange{}, fmt.Errorf("failed to read site config: %w", err)
+	}
+	if !fastcgiPassSocket.Match(content) {
+		return NginxChange{}, fmt.Errorf("%s does not pass requests to a PHP-FPM socket", siteName)
+	}
+	config := fastcgiPassSocket.ReplaceAllString(string(content), "${1}unix:"+socket+"${2}")
+	return NginxChange{SiteName: siteName, Path: configPath, Old: string(content), New: config}, nil
+}
+
+// SitePHPSocket returns the socket a site should use for a PHP version: the
+// site's own pool when one exists for that version, otherwise the version's
+// shared www pool
+func SitePHPSocket(siteName, phpVersion string) string {
+	name := SitePool(siteName, phpVersion, "").Name
+	if pools, err := NewPHPFPMManager(phpVersion).ListPools(); err == nil {
+		for _, pool := range pools {
+			if pool.Name == name && filepath.IsAbs(pool.Listen) {
+				return pool.Listen
+			}
+		}
+	}
+	return fmt.Sprintf("/run/php/php%s-fpm.sock", phpVersion)
+}
+
+// CurrentPHPSocket returns the first PHP-FPM socket a site passes requests to
+func (nm *NginxManager) CurrentPHPSocket(siteName string) (string, error) {
+	content, err := ReadFile(filepath.Join(nm.sitesAvailable, siteName))
+	if err != nil {
+		return "", fmt.Errorf("failed to read site config: %w", err)
+	}
+	return phpSocketOf(string(content)), nil
+}
+
+// phpSocketOf returns the socket of the first fastcgi_pass in a config
+func phpSocketOf(config string) string {
+	match := fastcgiPassSocket.FindString(config)
+	if match == "" {
+		return ""
+	}
+	_, socket, _ := strings.Cut(match, "unix:")
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(socket), ";"))
+}
+
+// SocketPHPVersion returns the PHP version in a socket path such as
+// /run/php/php8.3-fpm.sock, or "" if it has none
+func SocketPHPVersion(socket string) string {
+	if m := socketVersionPattern.FindStringSubmatch(filepath.Base(socket)); m != nil {
+		return m[1]
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPHPFPMPoolValidate(t *testing.T) {
+	valid := SitePool("shop.example.com", "8.3", "shop")
+	if valid.Name != "shop_example_com" {
+		t.Errorf("unexpected pool name %q", valid.Name)
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected the site pool to be valid: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*PHPFPMPool)
+	}{
+		{"bad name", func(p *PHPFPMPool) { p.Name = "a b" }},
+		{"bad user", func(p *PHPFPMPool) { p.User = "Root!" }},
+		{"no children", func(p *PHPFPMPool) { p.PMMaxChildren = 0 }},
+		{"unknown pm", func(p *PHPFPMPool) { p.PM = "adaptive" }},
+		{"spare above children", func(p *PHPFPMPool) { p.PM, p.PMMaxChildren = "dynamic", 2 }},
+		{"start outside spare", func(p *PHPFPMPool) { p.PM, p.PMStartServers = "dynamic", 4 }},
+		{"bad timeout", func(p *PHPFPMPool) { p.RequestSlowlogTimeout = "five" }},
+		{"timeout without log", func(p *PHPFPMPool) { p.Slowlog = "" }},
+		{"relative log", func(p *PHPFPMPool) { p.Slowlog = "slow.log" }},
+	}
+	for _, tt := range tests {
+		pool := valid
+		tt.modify(&pool)
+		if err := pool.Validate(); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestSetPoolDirectives(t *testing.T) {
+	content := `; Pool: shop
+[shop]
+user = www-data
+group = www-data
+listen = /run/php/php8.3-shop-fpm.sock
+pm = dynamic
+pm.max_children = 5
+pm.start_servers = 2
+php_admin_value[memory_limit] = 256M
+;slowlog = /var/log/old.log
+
+`
+	pool := PHPFPMPool{User: "shop", Group: "shop", PM: "static", PMMaxChildren: 10, PMMaxRequests: 200, Slowlog: "/var/log/shop-slow.log", RequestSlowlogTimeout: "3s"}
+	got := setPoolDirectives(content, pool.poolDirectives())
+
+	for _, want := range []string{
+		"user = shop\ngroup = shop\n",
+		"pm = static\npm.max_children = 10\npm.start_servers = 2\n",
+		"php_admin_value[memory_limit] = 256M\n;slowlog = /var/log/old.log\npm.max_requests = 200\nslowlog = /var/log/shop-slow.log\nrequest_slowlog_timeout = 3s\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+
+	// Clearing the slow log comments it out
+	pool.Slowlog, pool.RequestSlowlogTimeout = "", ""
+	got = setPoolDirectives(got, pool.poolDirectives())
+	if !strings.Contains(got, ";slowlog = /var/log/shop-slow.log") || strings.Contains(got, "\nslowlog") {
+		t.Errorf("expected slowlog to be commented out:\n%s", got)
+	}
+}
+
+func TestFastCGIPassSocket(t *testing.T) {
+	config := "location ~ \\.php$ {\n    fastcgi_pass unix:/var/run/php/php8.2-fpm.sock;\n}\nlocation /api { fastcgi_pass 127.0.0.1:9000; }\n"
+	got := fastcgiPassSocket.ReplaceAllString(config, "${1}unix:/run/php/php8.3-shop-fpm.sock${2}")
+	if !strings.Contains(got, "fastcgi_pass unix:/run/php/php8.3-shop-fpm.sock;") {
+		t.Errorf("socket not replaced:\n%s", got)
+	}
+	if !strings.Contains(got, "fastcgi_pass 127.0.0.1:9000;") {
+		t.Errorf("TCP upstream should be left alone:\n%s", got)
+	}
+}
+
+func TestVersionLess(t *testing.T) {
+	if !versionLess("8.2", "8.10") || versionLess("8.3", "7.4") || !versionLess("8", "8.1") {
+		t.Error("unexpected version ordering")
+	}
+}
+
+func TestPHPSocketOf(t *testing.T) {
+	config := "location /api { fastcgi_pass 127.0.0.1:9000; }\nlocation ~ \\.php$ {\n    fastcgi_pass unix:/run/php/php8.2-shop-fpm.sock ;\n}\n"
+	socket := phpSocketOf(config)
+	if socket != "/run/php/php8.2-shop-fpm.sock" {
+		t.Errorf("unexpected socket %q", socket)
+	}
+	if v := SocketPHPVersion(socket); v != "8.2" {
+		t.Errorf("unexpected version %q", v)
+	}
+	if v := SocketPHPVersion("/var/run/php/php-fpm.sock"); v != "" {
+		t.Errorf("expected no version, got %q", v)
+	}
+}
@@ -0,0 +1,327 @@
+// Package pkgmanager maps package, service, and config names to the
+// conventions of the host's Linux distribution
+package pkgmanager
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iperamuna/ravact/internal/system"
+)
+
+// Family is a group of distributions that share a package manager and
+// naming conventions
+type Family string
+
+const (
+	Debian  Family = "debian" // Debian, Ubuntu, and derivatives
+	RHEL    Family = "rhel"   // RHEL, CentOS, Rocky, Alma, Fedora
+	Arch    Family = "arch"   // Arch, Manjaro, EndeavourOS
+	Unknown Family = ""
+)
+
+// OSReleasePath is read to detect the distribution
+var OSReleasePath = "/etc/os-release"
+
+// Distro describes the host's distribution
+type Distro struct {
+	ID      string // e.g. ubuntu, rocky
+	IDLike  []string
+	Version string
+	Name    string
+	Family  Family
+}
+
+// Detect reads os-release on the active host
+func Detect() (Distro, error) {
+	data, err := system.ReadFile(OSReleasePath)
+	if err != nil {
+		return Distro{}, fmt.Errorf("cannot detect the distribution: %w", err)
+	}
+	d := ParseOSRelease(string(data))
+	if d.Family == Unknown {
+		return d, fmt.Errorf("unsupported distribution %q", d.ID)
+	}
+	return d, nil
+}
+
+// ParseOSRelease parses os-release content
+func ParseOSRelease(content string) Distro {
+	values := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		values[key] = strings.Trim(value, `"'`)
+	}
+	d := Distro{
+		ID:      strings.ToLower(values["ID"]),
+		IDLike:  strings.Fields(strings.ToLower(values["ID_LIKE"])),
+		Version: values["VERSION_ID"],
+		Name:    values["PRETTY_NAME"],
+	}
+	d.Family = familyOf(append([]string{d.ID}, d.IDLike...))
+	return d
+}
+
+// familyOf returns the family of the first ID it recognises
+func familyOf(ids []string) Family {
+	for _, id := range ids {
+		switch id {
+		case "debian", "ubuntu":
+			return Debian
+		case "rhel", "centos", "fedora", "rocky", "almalinux", "ol", "amzn":
+			return RHEL
+		case "arch", "archlinux", "manjaro", "endeavouros":
+			return Arch
+		}
+	}
+	return Unknown
+}
+
+// Manager returns the package manager command: apt-get, dnf, yum (on
+// RHEL and CentOS 7), or pacman
+func (d Distro) Manager() string {
+	switch d.Family {
+	case Debian:
+		return "apt-get"
+	case RHEL:
+		if major, err := strconv.Atoi(strings.Split(d.Version, ".")[0]); err == nil && major < 8 && d.ID != "fedora" && d.ID != "amzn" {
+			return "yum"
+		}
+		return "dnf"
+	case Arch:
+		return "pacman"
+	}
+	return ""
+}
+
+// UpdateCommand refreshes the package lists without upgrading anything
+func (d Distro) UpdateCommand() string {
+	switch d.Manager() {
+	case "apt-get":
+		return "apt-get update -qq"
+	case "dnf", "yum":
+		return d.Manager() + " makecache -q"
+	case "pacman":
+		return "pacman -Sy --noconfirm"
+	}
+	return ""
+}
+
+// UpgradeCommand upgrades every installed package
+func (d Distro) UpgradeCommand() string {
+	switch d.Manager() {
+	case "apt-get":
+		return "DEBIAN_FRONTEND=noninteractive apt-get upgrade -y"
+	case "dnf", "yum":
+		return d.Manager() + " upgrade -y"
+	case "pacman":
+		return "pacman -Syu --noconfirm"
+	}
+	return ""
+}
+
+// InstallCommand installs packages, mapping generic names with Package.
+// Packages that RHEL only ships in EPEL enable it first.
+func (d Distro) InstallCommand(packages ...string) string {
+	names := d.packages(packages)
+	switch d.Manager() {
+	case "apt-get":
+		return "DEBIAN_FRONTEND=noninteractive apt-get install -y " + names
+	case "dnf", "yum":
+		install := d.Manager() + " install -y " + names
+		if d.ID != "fedora" && needsEPEL(packages) {
+			install = d.Manager() + " install -y epel-release && " + install
+		}
+		return install
+	case "pacman":
+		return "pacman -S --needed --noconfirm " + names
+	}
+	return ""
+}
+
+// RemoveCommand removes packages, mapping generic names with Package
+func (d Distro) RemoveCommand(packages ...string) string {
+	names := d.packages(packages)
+	switch d.Manager() {
+	case "apt-get":
+		return "DEBIAN_FRONTEND=noninteractive apt-get remove -y " + names
+	case "dnf", "yum":
+		return d.Manager() + " remove -y " + names
+	case "pacman":
+		return "pacman -R --noconfirm " + names
+	}
+	return ""
+}
+
+// packages maps and quotes package names for a command line
+func (d Distro) packages(packages []string) string {
+	names := make([]string, len(packages))
+	for i, p := range packages {
+		names[i] = system.ShellQuote(d.Package(p))
+	}
+	return strings.Join(names, " ")
+}
+
+// names holds the per-family spelling of a generic name
+type names struct {
+	debian, rhel, arch string
+}
+
+// pick returns the name for a family, falling back to the Debian name
+func (n names) pick(f Family) string {
+	switch f {
+	case RHEL:
+		return n.rhel
+	case Arch:
+		return n.arch
+	}
+	return n.debian
+}
+
+// packageNames maps generic package names to distribution packages
+var packageNames = map[string]names{
+	"redis":         {"redis-server", "redis", "redis"},
+	"mysql":         {"mysql-server", "mysql-server", "mariadb"},
+	"postgresql":    {"postgresql", "postgresql-server", "postgresql"},
+	"php-fpm":       {"php-fpm", "php-fpm", "php-fpm"},
+	"certbot-nginx": {"python3-certbot-nginx", "python3-certbot-nginx", "certbot-nginx"},
+	"firewall":      {"ufw", "firewalld", "ufw"},
+	"cron":          {"cron", "cronie", "cronie"},
+	"ssh-server":    {"openssh-server", "openssh-server", "openssh"},
+	"sasl-plain":    {"libsasl2-modules", "cyrus-sasl-plain", "libsasl"},
+	"msmtp-mta":     {"msmtp-mta", "msmtp", "msmtp-mta"},
+}
+
+// epelPackages are only available from EPEL on RHEL and its rebuilds
+var epelPackages = map[string]bool{
+	"supervisor":    true,
+	"certbot":       true,
+	"certbot-nginx": true,
+	"msmtp":         true,
+	"msmtp-mta":     true,
+	"goaccess":      true,
+}
+
+// needsEPEL reports whether any generic package comes from EPEL
+func needsEPEL(packages []string) bool {
+	for _, p := range packages {
+		if epelPackages[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// Package returns the distribution's name for a generic package. Names
+// without a mapping are the same everywhere (nginx, git, supervisor).
+func (d Distro) Package(name string) string {
+	if n, ok := packageNames[name]; ok {
+		return n.pick(d.Family)
+	}
+	return name
+}
+
+// serviceNames maps generic service names to systemd units
+var serviceNames = map[string]names{
+	"redis":      {"redis-server", "redis", "redis"},
+	"mysql":      {"mysql", "mysqld", "mariadb"},
+	"supervisor": {"supervisor", "supervisord", "supervisord"},
+	"php-fpm":    {"php-fpm", "php-fpm", "php-fpm"},
+	"ssh":        {"ssh", "sshd", "sshd"},
+	"cron":       {"cron", "crond", "cronie"},
+	"firewall":   {"ufw", "firewalld", "ufw"},
+}
+
+// Service returns the distribution's systemd unit for a generic service.
+// Debian runs PHP-FPM per version (php8.3-fpm); php-fpm is the
+// unversioned name used elsewhere.
+func (d Distro) Service(name string) string {
+	if n, ok := serviceNames[name]; ok {
+		return n.pick(d.Family)
+	}
+	return name
+}
+
+// configPaths maps generic config names to files and directories
+var configPaths = map[string]names{
+	"nginx-sites":     {"/etc/nginx/sites-available", "/etc/nginx/conf.d", "/etc/nginx/conf.d"},
+	"nginx-enabled":   {"/etc/nginx/sites-enabled", "/etc/nginx/conf.d", "/etc/nginx/conf.d"},
+	"redis":           {"/etc/redis/redis.conf", "/etc/redis/redis.conf", "/etc/redis/redis.conf"},
+	"supervisor-conf": {"/etc/supervisor/conf.d", "/etc/supervisord.d", "/etc/supervisor.d"},
+	"php-fpm-pools":   {"/etc/php/*/fpm/pool.d", "/etc/php-fpm.d", "/etc/php/php-fpm.d"},
+	"mysql":           {"/etc/mysql/my.cnf", "/etc/my.cnf", "/etc/my.cnf"},
+	"postgresql-data": {"/var/lib/postgresql", "/var/lib/pgsql/data", "/var/lib/postgres/data"},
+}
+
+// ConfigPath returns the distribution's path for a generic config name,
+// or "" when the distribution has no equivalent
+func (d Distro) ConfigPath(name string) string {
+	if n, ok := configPaths[name]; ok {
+		return n.pick(d.Family)
+	}
+	return ""
+}
+
+// ScriptPreamble returns bash that setup scripts run first. It exports
+// RAVACT_OS_FAMILY and RAVACT_PKG_MANAGER and defines pkg_update,
+// pkg_install, pkg_remove, and svc_name, which take generic names.
+func (d Distro) ScriptPreamble() string {
+	var b strings.Builder
+	b.WriteString("# Distribution helpers added by ravact\n")
+	fmt.Fprintf(&b, "export RAVACT_OS_FAMILY=%s\n", system.ShellQuote(string(d.Family)))
+	fmt.Fprintf(&b, "export RAVACT_PKG_MANAGER=%s\n", system.ShellQuote(d.Manager()))
+
+	if d.Family == Unknown {
+		unsupported := system.ShellQuote(fmt.Sprintf("Error: Unsupported distribution %q", d.ID))
+		for _, fn := range []string{"pkg_update", "pkg_install", "pkg_remove"} {
+			fmt.Fprintf(&b, "%s() { echo %s >&2; return 1; }\n", fn, unsupported)
+		}
+		b.WriteString("svc_name() { echo \"$1\"; }\n")
+		return b.String()
+	}
+
+	// Map generic names in the shell so scripts can pass them directly
+	b.WriteString("pkg_name() {\n  case \"$1\" in\n")
+	for _, name := range sortedKeys(packageNames) {
+		fmt.Fprintf(&b, "    %s) echo %s ;;\n", name, system.ShellQuote(d.Package(name)))
+	}
+	b.WriteString("    *) echo \"$1\" ;;\n  esac\n}\n")
+
+	b.WriteString("svc_name() {\n  case \"$1\" in\n")
+	for _, name := range sortedKeys(serviceNames) {
+		fmt.Fprintf(&b, "    %s) echo %s ;;\n", name, system.ShellQuote(d.Service(name)))
+	}
+	b.WriteString("    *) echo \"$1\" ;;\n  esac\n}\n")
+
+	fmt.Fprintf(&b, "pkg_update() { %s; }\n", d.UpdateCommand())
+
+	install := strings.TrimSuffix(d.InstallCommand(), " ")
+	if d.Family == RHEL && d.ID != "fedora" {
+		// Enable EPEL when a package needs it
+		epel := sortedKeys(epelPackages)
+		fmt.Fprintf(&b, "pkg_install() {\n  local p names=()\n  for p in \"$@\"; do\n"+
+			"    case \"$p\" in %s) rpm -q epel-release >/dev/null 2>&1 || %s install -y epel-release ;; esac\n"+
+			"    names+=(\"$(pkg_name \"$p\")\")\n  done\n  %s \"${names[@]}\"\n}\n",
+			strings.Join(epel, "|"), d.Manager(), d.Manager()+" install -y")
+	} else {
+		fmt.Fprintf(&b, "pkg_install() {\n  local p names=()\n  for p in \"$@\"; do names+=(\"$(pkg_name \"$p\")\"); done\n  %s \"${names[@]}\"\n}\n", install)
+	}
+	remove := strings.TrimSuffix(d.RemoveCommand(), " ")
+	fmt.Fprintf(&b, "pkg_remove() {\n  local p names=()\n  for p in \"$@\"; do names+=(\"$(pkg_name \"$p\")\"); done\n  %s \"${names[@]}\"\n}\n", remove)
+	return b.String()
+}
+
+// sortedKeys returns a map's keys in order so generated scripts are stable
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
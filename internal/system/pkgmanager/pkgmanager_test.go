@@ -0,0 +1,84 @@
+package pkgmanager
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseOSRelease(t *testing.T) {
+	tests := []struct {
+		content string
+		family  Family
+		manager string
+	}{
+		{"ID=ubuntu\nID_LIKE=debian\nVERSION_ID=\"24.04\"\n", Debian, "apt-get"},
+		{"ID=\"rocky\"\nID_LIKE=\"rhel centos fedora\"\nVERSION_ID=\"9.3\"\n", RHEL, "dnf"},
+		{"ID=\"almalinux\"\nID_LIKE=\"rhel centos fedora\"\nVERSION_ID=\"8.9\"\n", RHEL, "dnf"},
+		{"ID=\"centos\"\nID_LIKE=\"rhel fedora\"\nVERSION_ID=\"7\"\n", RHEL, "yum"},
+		{"ID=fedora\nVERSION_ID=40\n", RHEL, "dnf"},
+		{"ID=arch\nBUILD_ID=rolling\n", Arch, "pacman"},
+		{"ID=manjaro\nID_LIKE=arch\n", Arch, "pacman"},
+		{"ID=alpine\n", Unknown, ""},
+	}
+	for _, tt := range tests {
+		d := ParseOSRelease(tt.content)
+		if d.Family != tt.family || d.Manager() != tt.manager {
+			t.Errorf("%q: got %s/%s, want %s/%s", tt.content, d.Family, d.Manager(), tt.family, tt.manager)
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	rocky := ParseOSRelease("ID=rocky\nID_LIKE=\"rhel centos fedora\"\nVERSION_ID=9.3\n")
+	ubuntu := ParseOSRelease("ID=ubuntu\nID_LIKE=debian\n")
+	arch := ParseOSRelease("ID=arch\n")
+
+	if rocky.Package("redis") != "redis" || ubuntu.Package("redis") != "redis-server" || arch.Package("mysql") != "mariadb" {
+		t.Error("unexpected package names")
+	}
+	if rocky.Service("supervisor") != "supervisord" || ubuntu.Service("ssh") != "ssh" || arch.Service("cron") != "cronie" {
+		t.Error("unexpected service names")
+	}
+	if rocky.ConfigPath("supervisor-conf") != "/etc/supervisord.d" || ubuntu.ConfigPath("nginx-sites") != "/etc/nginx/sites-available" {
+		t.Error("unexpected config paths")
+	}
+	if got := rocky.InstallCommand("supervisor"); got != "dnf install -y epel-release && dnf install -y supervisor" {
+		t.Errorf("unexpected install command: %q", got)
+	}
+	if got := arch.InstallCommand("redis", "git"); got != "pacman -S --needed --noconfirm redis git" {
+		t.Errorf("unexpected install command: %q", got)
+	}
+}
+
+func TestScriptPreamble(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available")
+	}
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "calls.log")
+	for _, name := range []string{"dnf", "rpm"} {
+		body := "#!/bin/sh\necho \"" + name + " $*\" >> " + logPath + "\n"
+		if name == "rpm" {
+			body += "exit 1\n"
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rocky := ParseOSRelease("ID=rocky\nID_LIKE=\"rhel centos fedora\"\nVERSION_ID=9.3\n")
+	script := rocky.ScriptPreamble() + "pkg_update\npkg_install redis supervisor\necho \"$(svc_name redis) $RAVACT_OS_FAMILY\" >> " + logPath + "\n"
+	cmd := exec.Command("bash", "-c", script)
+	cmd.Env = append(os.Environ(), "PATH="+dir+":"+os.Getenv("PATH"))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("preamble failed: %v\n%s", err, output)
+	}
+
+	calls, _ := os.ReadFile(logPath)
+	want := "dnf makecache -q\nrpm -q epel-release\ndnf install -y epel-release\ndnf install -y redis supervisor\nredis rhel\n"
+	if string(calls) != want {
+		t.Errorf("unexpected calls:\n%s", calls)
+	}
+}
@@ -2,8 +2,6 @@ package system
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -37,7 +35,7 @@ func NewPostgreSQLManager() *PostgreSQLManager {
 // detectConfigPath finds the actual PostgreSQL config path
 func (p *PostgreSQLManager) detectConfigPath() error {
 	// Try to find the actual config path
-	cmd := exec.Command("bash", "-c", "ls /etc/postgresql/*/main/postgresql.conf 2>/dev/null | head -1")
+	cmd := Command("bash", "-c", "ls /etc/postgresql/*/main/postgresql.conf 2>/dev/null | head -1")
 	output, err := cmd.Output()
 	if err != nil || len(output) == 0 {
 		return fmt.Errorf("PostgreSQL config file not found")
@@ -75,7 +73,7 @@ func (p *PostgreSQLManager) GetConfig() (*PostgreSQLConfig, error) {
 	}
 
 	// Read and parse config file
-	data, err := os.ReadFile(p.configPath)
+	data, err := ReadFile(p.configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read PostgreSQL config: %w", err)
 	}
@@ -131,14 +129,14 @@ func (p *PostgreSQLManager) ChangePort(newPort int) error {
 	}
 
 	// Read current config
-	data, err := os.ReadFile(p.configPath)
+	data, err := ReadFile(p.configPath)
 	if err != nil {
 		return fmt.Errorf("failed to read PostgreSQL config: %w", err)
 	}
 
 	// Backup original config
 	backupPath := p.configPath + ".bak"
-	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+	if err := WriteFile(backupPath, data, 0644); err != nil {
 		return fmt.Errorf("failed to backup config: %w", err)
 	}
 
@@ -176,9 +174,9 @@ func (p *PostgreSQLManager) ChangePort(newPort int) error {
 
 	// Write updated config
 	newData := strings.Join(lines, "\n")
-	if err := os.WriteFile(p.configPath, []byte(newData), 0644); err != nil {
+	if err := WriteFile(p.configPath, []byte(newData), 0644); err != nil {
 		// Restore backup on failure
-		os.WriteFile(p.configPath, data, 0644)
+		WriteFile(p.configPath, data, 0644)
 		return fmt.Errorf("failed to write PostgreSQL config: %w", err)
 	}
 
@@ -197,7 +195,7 @@ func (p *PostgreSQLManager) ChangeRootPassword(newPassword string) error {
 	// Change password using psql as postgres user
 	sqlCmd := fmt.Sprintf("ALTER USER postgres WITH PASSWORD '%s';", escapedPassword)
 	
-	cmd := exec.Command("sudo", "-u", "postgres", "psql", "-c", sqlCmd)
+	cmd := Command("sudo", "-u", "postgres", "psql", "-c", sqlCmd)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to change postgres password: %s", string(output))
@@ -208,7 +206,7 @@ func (p *PostgreSQLManager) ChangeRootPassword(newPassword string) error {
 
 // RestartService restarts the PostgreSQL service
 func (p *PostgreSQLManager) RestartService() error {
-	cmd := exec.Command("systemctl", "restart", "postgresql")
+	cmd := Command("systemctl", "restart", "postgresql")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to restart PostgreSQL: %s", string(output))
@@ -218,7 +216,7 @@ func (p *PostgreSQLManager) RestartService() error {
 
 // GetStatus returns the PostgreSQL service status
 func (p *PostgreSQLManager) GetStatus() (string, error) {
-	cmd := exec.Command("systemctl", "status", "postgresql")
+	cmd := Command("systemctl", "status", "postgresql")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// Status command returns non-zero if service is not running
@@ -229,13 +227,13 @@ func (p *PostgreSQLManager) GetStatus() (string, error) {
 
 // IsInstalled checks if PostgreSQL is installed
 func (p *PostgreSQLManager) IsInstalled() bool {
-	cmd := exec.Command("which", "psql")
+	cmd := Command("which", "psql")
 	return cmd.Run() == nil
 }
 
 // GetVersion returns the PostgreSQL version
 func (p *PostgreSQLManager) GetVersion() (string, error) {
-	cmd := exec.Command("psql", "--version")
+	cmd := Command("psql", "--version")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -245,9 +243,13 @@ func (p *PostgreSQLManager) GetVersion() (string, error) {
 
 // CreateDatabase creates a new PostgreSQL database
 func (p *PostgreSQLManager) CreateDatabase(dbName, username, password string) error {
+	if err := CheckName(NamingDatabase, dbName); err != nil {
+		return err
+	}
+
 	// Create database
 	createDBCmd := fmt.Sprintf("CREATE DATABASE \"%s\";", dbName)
-	cmd := exec.Command("sudo", "-u", "postgres", "psql", "-c", createDBCmd)
+	cmd := Command("sudo", "-u", "postgres", "psql", "-c", createDBCmd)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// Check if database already exists
@@ -265,7 +267,7 @@ func (p *PostgreSQLManager) CreateDatabase(dbName, username, password string) er
 			"CREATE USER \"%s\" WITH PASSWORD '%s';",
 			username, escapedPassword,
 		)
-		cmd = exec.Command("sudo", "-u", "postgres", "psql", "-c", createUserCmd)
+		cmd = Command("sudo", "-u", "postgres", "psql", "-c", createUserCmd)
 		output, err = cmd.CombinedOutput()
 		if err != nil {
 			if !strings.Contains(string(output), "already exists") {
@@ -278,7 +280,7 @@ func (p *PostgreSQLManager) CreateDatabase(dbName, username, password string) er
 			"GRANT ALL PRIVILEGES ON DATABASE \"%s\" TO \"%s\";",
 			dbName, username,
 		)
-		cmd = exec.Command("sudo", "-u", "postgres", "psql", "-c", grantCmd)
+		cmd = Command("sudo", "-u", "postgres", "psql", "-c", grantCmd)
 		if err := cmd.Run(); err != nil {
 			return fmt.Errorf("failed to grant privileges: %w", err)
 		}
@@ -289,7 +291,7 @@ func (p *PostgreSQLManager) CreateDatabase(dbName, username, password string) er
 
 // ListDatabases returns a list of all databases
 func (p *PostgreSQLManager) ListDatabases() ([]string, error) {
-	cmd := exec.Command("sudo", "-u", "postgres", "psql", "-t", "-c", "SELECT datname FROM pg_database WHERE datistemplate = false;")
+	cmd := Command("sudo", "-u", "postgres", "psql", "-t", "-c", "SELECT datname FROM pg_database WHERE datistemplate = false;")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list databases: %w", err)
@@ -308,32 +310,25 @@ func (p *PostgreSQLManager) ListDatabases() ([]string, error) {
 	return databases, nil
 }
 
-// ExportDatabase exports a database to SQL file
-func (p *PostgreSQLManager) ExportDatabase(dbName, outputPath string) error {
-	// Ensure output directory exists
-	dir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
-	}
-
-	// Create output file
-	outFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+// DropDatabase permanently deletes a database
+func (p *PostgreSQLManager) DropDatabase(dbName string) error {
+	if !databaseNamePattern.MatchString(dbName) {
+		return fmt.Errorf("invalid database name: %s", dbName)
 	}
-	defer outFile.Close()
 
-	// Run pg_dump
-	cmd := exec.Command("sudo", "-u", "postgres", "pg_dump", dbName)
-	cmd.Stdout = outFile
-	
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to export database: %w", err)
+	dropCmd := fmt.Sprintf("DROP DATABASE \"%s\";", dbName)
+	cmd := Command("sudo", "-u", "postgres", "psql", "-c", dropCmd)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to drop database: %s", strings.TrimSpace(string(output)))
 	}
-
 	return nil
 }
 
+// ExportDatabase exports a database to an SQL file on the managed host
+func (p *PostgreSQLManager) ExportDatabase(dbName, outputPath string) error {
+	return exportDatabase("pgsql", dbName, outputPath)
+}
+
 // UpdateMaxConnections updates the max_connections setting
 func (p *PostgreSQLManager) UpdateMaxConnections(maxConn int) error {
 	if maxConn < 10 || maxConn > 10000 {
@@ -359,14 +354,14 @@ func (p *PostgreSQLManager) UpdateSharedBuffers(sharedBuf string) error {
 // updateConfigValue is a helper to update a config value
 func (p *PostgreSQLManager) updateConfigValue(key, value string) error {
 	// Read current config
-	data, err := os.ReadFile(p.configPath)
+	data, err := ReadFile(p.configPath)
 	if err != nil {
 		return fmt.Errorf("failed to read config: %w", err)
 	}
 
 	// Backup
 	backupPath := p.configPath + ".bak"
-	os.WriteFile(backupPath, data, 0644)
+	WriteFile(backupPath, data, 0644)
 
 	// Modify config
 	lines := strings.Split(string(data), "\n")
@@ -398,10 +393,40 @@ func (p *PostgreSQLManager) updateConfigValue(key, value string) error {
 
 	// Write updated config
 	newData := strings.Join(lines, "\n")
-	if err := os.WriteFile(p.configPath, []byte(newData), 0644); err != nil {
-		os.WriteFile(p.configPath, data, 0644)
+	if err := WriteFile(p.configPath, []byte(newData), 0644); err != nil {
+		WriteFile(p.configPath, data, 0644)
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
 	return nil
 }
+
+// PostgreSQLRole is a login role and its stored password secret
+type PostgreSQLRole struct {
+	Name   string
+	Secret string // SCRAM-SHA-256$... or md5...
+}
+
+// LoginRoles returns the roles that can log in with a password, with the
+// secrets PostgreSQL stores for them
+func (p *PostgreSQLManager) LoginRoles() ([]PostgreSQLRole, error) {
+	query := "SELECT rolname, rolpassword FROM pg_authid WHERE rolcanlogin AND rolpassword IS NOT NULL ORDER BY rolname;"
+	output, err := Command("sudo", "-u", "postgres", "psql", "-tAX", "-F", "\t", "-c", query).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %s", strings.TrimSpace(string(output)))
+	}
+	return parsePostgreSQLRoles(string(output)), nil
+}
+
+// parsePostgreSQLRoles parses tab-separated rolname and rolpassword rows
+func parsePostgreSQLRoles(output string) []PostgreSQLRole {
+	var roles []PostgreSQLRole
+	for _, line := range strings.Split(output, "\n") {
+		name, secret, ok := strings.Cut(strings.TrimRight(line, "\r"), "\t")
+		if !ok || name == "" || secret == "" {
+			continue
+		}
+		roles = append(roles, PostgreSQLRole{Name: name, Secret: secret})
+	}
+	return roles
+}
@@ -0,0 +1,138 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PrivilegeMethod is how ravact runs commands that need root
+type PrivilegeMethod int
+
+const (
+	// PrivilegeRoot means ravact already runs as root
+	PrivilegeRoot PrivilegeMethod = iota
+	// PrivilegeSudo runs commands through sudo, asking for the password once
+	// and reusing sudo's cached credentials afterwards
+	PrivilegeSudo
+	// PrivilegePolkit runs commands through pkexec, which asks through the
+	// desktop's polkit agent
+	PrivilegePolkit
+	// PrivilegeUnavailable means changes cannot be made from this account
+	PrivilegeUnavailable
+)
+
+// String names the method for the UI
+func (p PrivilegeMethod) String() string {
+	switch p {
+	case PrivilegeRoot:
+		return "root"
+	case PrivilegeSudo:
+		return "sudo"
+	case PrivilegePolkit:
+		return "polkit"
+	}
+	return "unavailable"
+}
+
+// ErrPrivilegeUnavailable is returned when a change needs root and neither
+// sudo nor polkit can provide it
+var ErrPrivilegeUnavailable = errors.New("this needs root: run ravact as root or install sudo")
+
+var (
+	privilegeMu    sync.Mutex
+	privilegeCache = map[string]PrivilegeMethod{}
+)
+
+// DetectPrivilegeMethod returns how privileged commands run on the active
+// host. The result is remembered per host.
+//
+// sudo credentials are cached per terminal, which ssh sessions do not
+// share, so remote hosts only use sudo when it needs no password.
+func DetectPrivilegeMethod() PrivilegeMethod {
+	t := CurrentTransport()
+	privilegeMu.Lock()
+	defer privilegeMu.Unlock()
+	if method, ok := privilegeCache[t.Name()]; ok {
+		return method
+	}
+
+	method := PrivilegeUnavailable
+	switch {
+	case HostIsRoot():
+		method = PrivilegeRoot
+	case t.IsRemote():
+		if Command("sudo", "-n", "true").Run() == nil {
+			method = PrivilegeSudo
+		}
+	case hostHasCommand("sudo"):
+		method = PrivilegeSudo
+	case hostHasCommand("pkexec"):
+		method = PrivilegePolkit
+	}
+	privilegeCache[t.Name()] = method
+	return method
+}
+
+// hostHasCommand reports whether a program is on the active host's PATH
+func hostHasCommand(name string) bool {
+	return Command("sh", "-c", "command -v "+ShellQuote(name)).Run() == nil
+}
+
+// privilegedArgs returns the command line that runs name with root
+// privileges using method. sudo runs non-interactively since the password
+// is asked for up front; a missing or expired credential fails the command
+// instead of hanging on a prompt the TUI cannot show.
+func privilegedArgs(method PrivilegeMethod, name string, args ...string) (string, []string) {
+	switch method {
+	case PrivilegeSudo:
+		return "sudo", append([]string{"-n", "--", name}, args...)
+	case PrivilegePolkit:
+		return "pkexec", append([]string{name}, args...)
+	}
+	return name, args
+}
+
+// PrivilegedCommandContext prepares a command that runs as root on the
+// active host, through sudo or polkit when ravact is not root itself. A
+// whole task should run as one privileged command, such as bash with a
+// script, so the user is asked once rather than for every step.
+func PrivilegedCommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	name, args = privilegedArgs(DetectPrivilegeMethod(), name, args...)
+	return CommandContext(ctx, name, args...)
+}
+
+// PrivilegePromptNeeded reports whether the sudo password has to be asked
+// for before privileged commands can run. It is false when running as
+// root, with passwordless sudo, or while sudo still has cached credentials.
+func PrivilegePromptNeeded() bool {
+	if DetectPrivilegeMethod() != PrivilegeSudo {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return CommandContext(ctx, "sudo", "-n", "true").Run() != nil
+}
+
+// AuthenticateSudo validates password with sudo, caching the credentials
+// so that privileged commands run without asking again until sudo's
+// timeout or DropSudoCredentials
+func AuthenticateSudo(password string) error {
+	if password == "" {
+		return ErrWrongPassword
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	cmd := CommandContext(ctx, "sudo", "-S", "-v", "-p", "")
+	cmd.Stdin = strings.NewReader(password + "\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if strings.Contains(string(out), "not in the sudoers") || strings.Contains(string(out), "may not run sudo") {
+			return ErrPrivilegeUnavailable
+		}
+		return ErrWrongPassword
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrivilegedArgs(t *testing.T) {
+	cases := []struct {
+		method   PrivilegeMethod
+		wantName string
+		wantArgs []string
+	}{
+		{PrivilegeRoot, "bash", []string{"-c", "id"}},
+		{PrivilegeSudo, "sudo", []string{"-n", "--", "bash", "-c", "id"}},
+		{PrivilegePolkit, "pkexec", []string{"bash", "-c", "id"}},
+		{PrivilegeUnavailable, "bash", []string{"-c", "id"}},
+	}
+	for _, c := range cases {
+		name, args := privilegedArgs(c.method, "bash", "-c", "id")
+		if name != c.wantName || !reflect.DeepEqual(args, c.wantArgs) {
+			t.Errorf("%s: got %s %v, want %s %v", c.method, name, args, c.wantName, c.wantArgs)
+		}
+	}
+}
+
+func TestAuthenticateSudoEmptyPassword(t *testing.T) {
+	if err := AuthenticateSudo(""); err != ErrWrongPassword {
+		t.Errorf("expected an empty password to be rejected without running sudo, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Profiling agents ravact can set up
+const (
+	ProfilerBlackfire = "blackfire"
+	ProfilerSPX       = "spx"
+)
+
+// SPXVersion is the php-spx release built for SPX
+const SPXVersion = "v0.4.17"
+
+var (
+	blackfireIDPattern    = regexp.MustCompile(`^[A-Za-z0-9-]{8,64}$`)
+	blackfireTokenPattern = regexp.MustCompile(`^[A-Za-z0-9]{16,128}$`)
+	spxKeyPattern         = regexp.MustCompile(`^[A-Za-z0-9]{8,64}$`)
+	ipListPattern         = regexp.MustCompile(`^[0-9A-Fa-f.:]+(,[0-9A-Fa-f.:]+)*$`)
+)
+
+// BlackfireConfig holds the server credentials the Blackfire agent sends
+// profiles with, from the Blackfire account's credentials page
+type BlackfireConfig struct {
+	ServerID    string
+	ServerToken string
+}
+
+// Validate checks the credentials' format; the agent checks them with
+// Blackfire when it starts
+func (c BlackfireConfig) Validate() error {
+	if !blackfireIDPattern.MatchString(c.ServerID) {
+		return fmt.Errorf("server ID must be the ID from your Blackfire credentials")
+	}
+	if !blackfireTokenPattern.MatchString(c.ServerToken) {
+		return fmt.Errorf("server token must be the token from your Blackfire credentials")
+	}
+	return nil
+}
+
+// BlackfireSetupScript installs the Blackfire agent and PHP probe from
+// Blackfire's apt repository, configures the agent's credentials, enables
+// the probe for a PHP version, and checks that the agent stays up (it
+// exits when Blackfire rejects the credentials) and the probe is loaded
+func BlackfireSetupScript(version string, c BlackfireConfig) (string, error) {
+	if !phpVersionPattern.MatchString(version) {
+		return "", fmt.Errorf("invalid PHP version %q", version)
+	}
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+	ini := filepath.Join(PHPConfigDir, version, "mods-available", "blackfire.ini")
+
+	var b strings.Builder
+	b.WriteString("set -e\nexport DEBIAN_FRONTEND=noninteractive\n")
+	b.WriteString("echo '==> Adding the Blackfire repository'\n")
+	b.WriteString("apt-get update\napt-get install -y ca-certificates curl gnupg\n")
+	b.WriteString("install -m 0755 -d /etc/apt/keyrings\n")
+	b.WriteString("curl -fsSL https://packages.blackfire.io/gpg.key | gpg --dearmor --yes -o /etc/apt/keyrings/blackfire.gpg\n")
+	b.WriteString("echo \"deb [arch=$(dpkg --print-architecture) signed-by=/etc/apt/keyrings/blackfire.gpg] http://packages.blackfire.io/debian any main\" > /etc/apt/sources.list.d/blackfire.list\n")
+	b.WriteString("apt-get update\n")
+	b.WriteString("echo '==> Installing the Blackfire agent and PHP probe'\n")
+	b.WriteString("apt-get install -y blackfire blackfire-php\n")
+	fmt.Fprintf(&b, "if [ ! -f %s ]; then\n", ShellQuote(ini))
+	fmt.Fprintf(&b, "    echo 'The Blackfire probe does not support PHP %s'\n    exit 1\nfi\n", version)
+	fmt.Fprintf(&b, "phpenmod -v %s blackfire\n", version)
+	b.WriteString("echo '==> Configuring the agent'\n")
+	fmt.Fprintf(&b, "blackfire agent:config --server-id=%s --server-token=%s\n", ShellQuote(c.ServerID), ShellQuote(c.ServerToken))
+	b.WriteString("systemctl enable blackfire-agent\nsystemctl restart blackfire-agent\n")
+	fmt.Fprintf(&b, "systemctl restart php%s-fpm\n", version)
+	b.WriteString("echo '==> Checking the agent connection'\nsleep 3\n")
+	b.WriteString("if ! systemctl is-active --quiet blackfire-agent; then\n")
+	b.WriteString("    journalctl -u blackfire-agent -n 20 --no-pager || true\n")
+	b.WriteString("    echo 'The Blackfire agent stopped: check the server ID and token'\n    exit 1\nfi\n")
+	fmt.Fprintf(&b, "php%s --ri blackfire\n", version)
+	fmt.Fprintf(&b, "echo 'Blackfire is ready for PHP %s: profile with the browser extension or blackfire curl'\n", version)
+	return b.String(), nil
+}
+
+// SPXConfig controls access to SPX's web UI
+type SPXConfig struct {
+	Key        string // Secret passed as SPX_KEY to open the UI
+	AllowedIPs string // Comma-separated client IPs allowed to use the UI
+}
+
+// Validate checks the key and IP list
+func (c SPXConfig) Validate() error {
+	if !spxKeyPattern.MatchString(c.Key) {
+		return fmt.Errorf("key must be 8 to 64 letters and digits")
+	}
+	if !ipListPattern.MatchString(c.AllowedIPs) {
+		return fmt.Errorf("allowed IPs must be a comma-separated list of IP addresses")
+	}
+	return nil
+}
+
+// IniContent is the mods-available file that loads SPX with its web UI
+func (c SPXConfig) IniContent() string {
+	return fmt.Sprintf("; configuration for php spx module, built by ravact\n; priority=20\nextension=spx.so\nspx.http_enabled = 1\nspx.http_key = \"%s\"\nspx.http_ip_whitelist = \"%s\"\n", c.Key, c.AllowedIPs)
+}
+
+// SPXSetupScript builds php-spx for a PHP version, enables it with the web
+// UI restricted to the key and IPs, and checks that PHP loads it
+func SPXSetupScript(version string, c SPXConfig) (string, error) {
+	if !phpVersionPattern.MatchString(version) {
+		return "", fmt.Errorf("invalid PHP version %q", version)
+	}
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+	ini := filepath.Join(PHPConfigDir, version, "mods-available", "spx.ini")
+
+	var b strings.Builder
+	b.WriteString("set -e\nexport DEBIAN_FRONTEND=noninteractive\n")
+	b.WriteString("apt-get update\n")
+	fmt.Fprintf(&b, "apt-get install -y php%s-dev build-essential git zlib1g-dev\n", version)
+	b.WriteString("build=$(mktemp -d)\ntrap 'rm -rf \"$build\"' EXIT\n")
+	fmt.Fprintf(&b, "echo '==> Building php-spx %s for PHP %s'\n", SPXVersion, version)
+	fmt.Fprintf(&b, "git clone --depth 1 --branch %s https://github.com/NoiseByNorthwest/php-spx.git \"$build/php-spx\"\n", SPXVersion)
+	b.WriteString("cd \"$build/php-spx\"\n")
+	fmt.Fprintf(&b, "phpize%s\n./configure --with-php-config=php-config%s\nmake -j\"$(nproc)\"\nmake install\n", version, version)
+	fmt.Fprintf(&b, "printf '%%s' %s > %s\n", ShellQuote(c.IniContent()), ShellQuote(ini))
+	fmt.Fprintf(&b, "phpenmod -v %s spx\n", version)
+	fmt.Fprintf(&b, "systemctl restart php%s-fpm\n", version)
+	b.WriteString("echo '==> Checking the extension'\n")
+	fmt.Fprintf(&b, "php%s --ri spx\n", version)
+	fmt.Fprintf(&b, "echo 'SPX is ready for PHP %s: open any page with ?SPX_KEY=<key>&SPX_UI_URI=/ from an allowed IP'\n", version)
+	return b.String(), nil
+}
@@ -0,0 +1,55 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBlackfireSetupScript(t *testing.T) {
+	c := BlackfireConfig{ServerID: "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0", ServerToken: "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"}
+	script, err := BlackfireSetupScript("8.3", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"apt-get install -y blackfire blackfire-php",
+		"phpenmod -v 8.3 blackfire",
+		"blackfire agent:config --server-id=0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0 --server-token=a1b2c3d4",
+		"systemctl is-active --quiet blackfire-agent",
+		"php8.3 --ri blackfire",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script is missing %q:\n%s", want, script)
+		}
+	}
+
+	if _, err := BlackfireSetupScript("8.3", BlackfireConfig{ServerID: "id; reboot", ServerToken: c.ServerToken}); err == nil {
+		t.Error("expected a malformed server ID to be rejected")
+	}
+}
+
+func TestSPXSetupScript(t *testing.T) {
+	c := SPXConfig{Key: "s3cretkey123", AllowedIPs: "127.0.0.1,203.0.113.7"}
+	script, err := SPXSetupScript("8.2", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"git clone --depth 1 --branch " + SPXVersion,
+		"phpize8.2\n./configure --with-php-config=php-config8.2",
+		`spx.http_key = "s3cretkey123"`,
+		`spx.http_ip_whitelist = "127.0.0.1,203.0.113.7"`,
+		"phpenmod -v 8.2 spx",
+		"php8.2 --ri spx",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script is missing %q:\n%s", want, script)
+		}
+	}
+
+	for _, bad := range []SPXConfig{{Key: "short", AllowedIPs: "127.0.0.1"}, {Key: c.Key, AllowedIPs: "anywhere"}} {
+		if _, err := SPXSetupScript("8.2", bad); err == nil {
+			t.Errorf("expected %+v to be rejected", bad)
+		}
+	}
+}
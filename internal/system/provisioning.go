@@ -0,0 +1,109 @@
+package system
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/models"
+)
+
+// ProvisionRecord is what ravact applied with a setup script, recorded when
+// the script succeeds
+type ProvisionRecord struct {
+	Script      string    `json:"script"`
+	ScriptHash  string    `json:"script_hash"`
+	Environment string    `json:"environment,omitempty"` // Options the script ran with, e.g. DRAGONFLY_METHOD=apt
+	AppliedAt   time.Time `json:"applied_at"`
+}
+
+// ProvisionState compares a setup script's record with what is on the host
+type ProvisionState struct {
+	Record *ProvisionRecord
+	// Satisfied means the script already ran to completion with the same
+	// options, its software is still detected as installed, and the script
+	// has not changed since, so running it again would change nothing
+	Satisfied bool
+	// Drift lists the ways the host differs from the record
+	Drift []string
+}
+
+// ScriptHash identifies a version of a setup script
+func ScriptHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// RecordProvisioned stores a successful setup script run
+func RecordProvisioned(rec ProvisionRecord) error {
+	if rec.Script == "" {
+		return fmt.Errorf("no setup script to record")
+	}
+	if rec.AppliedAt.IsZero() {
+		rec.AppliedAt = time.Now()
+	}
+	return UpdateStore(func(tx *StoreTx) error {
+		return tx.Put(BucketProvisioned, rec.Script, rec)
+	})
+}
+
+// ForgetProvisioned drops a setup script's record, e.g. when its software is
+// removed through ravact
+func ForgetProvisioned(script string) error {
+	return UpdateStore(func(tx *StoreTx) error {
+		return tx.Delete(BucketProvisioned, script)
+	})
+}
+
+// LoadProvisionRecords returns the record of every setup script that has run
+func LoadProvisionRecords() (map[string]ProvisionRecord, error) {
+	records := map[string]ProvisionRecord{}
+	err := ViewStore(func(tx *StoreTx) error {
+		for _, key := range tx.Keys(BucketProvisioned) {
+			var rec ProvisionRecord
+			if _, err := tx.Get(BucketProvisioned, key, &rec); err != nil {
+				return err
+			}
+			records[key] = rec
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provisioning state: %w", err)
+	}
+	return records, nil
+}
+
+// CheckProvisioned compares a setup script's record, if any, with the
+// current script, the options it would run with, and the status of its
+// service. An unknown status never counts as installed.
+func CheckProvisioned(rec *ProvisionRecord, scriptHash, environment string, status models.ServiceStatus) ProvisionState {
+	state := ProvisionState{Record: rec}
+	installed := status != models.StatusNotInstalled
+	detected := status != models.StatusUnknown
+
+	if rec == nil {
+		if installed && detected {
+			state.Drift = append(state.Drift, "installed outside ravact")
+		}
+		return state
+	}
+
+	applied := rec.AppliedAt.Format("2006-01-02")
+	if !installed {
+		state.Drift = append(state.Drift, "applied on "+applied+" but no longer installed")
+	}
+	changed := scriptHash != "" && rec.ScriptHash != scriptHash
+	if changed {
+		state.Drift = append(state.Drift, "setup script changed since it was applied on "+applied)
+	}
+	switch status {
+	case models.StatusStopped:
+		state.Drift = append(state.Drift, "service is stopped")
+	case models.StatusFailed:
+		state.Drift = append(state.Drift, "service has failed")
+	}
+	state.Satisfied = installed && detected && !changed && rec.Environment == environment && status != models.StatusFailed
+	return state
+}
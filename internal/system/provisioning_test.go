@@ -0,0 +1,78 @@
+package system
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/models"
+)
+
+func TestCheckProvisioned(t *testing.T) {
+	hash := ScriptHash([]byte("apt-get install -y nginx"))
+	rec := &ProvisionRecord{Script: "nginx", ScriptHash: hash, AppliedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name      string
+		rec       *ProvisionRecord
+		hash      string
+		env       string
+		status    models.ServiceStatus
+		satisfied bool
+		drift     string
+	}{
+		{"never applied", nil, hash, "", models.StatusNotInstalled, false, ""},
+		{"installed by hand", nil, hash, "", models.StatusRunning, false, "installed outside ravact"},
+		{"applied and running", rec, hash, "", models.StatusRunning, true, ""},
+		{"stopped", rec, hash, "", models.StatusStopped, true, "service is stopped"},
+		{"failed", rec, hash, "", models.StatusFailed, false, "service has failed"},
+		{"removed", rec, hash, "", models.StatusNotInstalled, false, "no longer installed"},
+		{"script changed", rec, ScriptHash([]byte("apt-get install -y nginx-full")), "", models.StatusRunning, false, "changed since it was applied on 2026-03-01"},
+		{"status unknown", rec, hash, "", models.StatusUnknown, false, ""},
+		{"other options", rec, hash, "DRAGONFLY_METHOD=apt", models.StatusRunning, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := CheckProvisioned(tt.rec, tt.hash, tt.env, tt.status)
+			if state.Satisfied != tt.satisfied {
+				t.Errorf("Satisfied = %v, want %v", state.Satisfied, tt.satisfied)
+			}
+			drift := strings.Join(state.Drift, "; ")
+			if tt.drift == "" && drift != "" {
+				t.Errorf("expected no drift, got %q", drift)
+			}
+			if !strings.Contains(drift, tt.drift) {
+				t.Errorf("drift %q does not mention %q", drift, tt.drift)
+			}
+		})
+	}
+}
+
+func TestProvisionRecords(t *testing.T) {
+	original := StateDir
+	StateDir = filepath.Join(t.TempDir(), "state")
+	defer func() { StateDir = original }()
+
+	if err := RecordProvisioned(ProvisionRecord{}); err == nil {
+		t.Error("expected a record without a script to be refused")
+	}
+	if err := RecordProvisioned(ProvisionRecord{Script: "dragonfly", ScriptHash: "abc", Environment: "DRAGONFLY_METHOD=apt"}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := LoadProvisionRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, ok := records["dragonfly"]
+	if !ok || rec.Environment != "DRAGONFLY_METHOD=apt" || rec.AppliedAt.IsZero() {
+		t.Fatalf("unexpected records %+v", records)
+	}
+
+	if err := ForgetProvisioned("dragonfly"); err != nil {
+		t.Fatal(err)
+	}
+	if records, _ := LoadProvisionRecords(); len(records) != 0 {
+		t.Errorf("expected the record to be forgotten, got %+v", records)
+	}
+}
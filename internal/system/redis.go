@@ -2,8 +2,6 @@ package system
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"strings"
 )
 
@@ -32,7 +30,7 @@ func NewRedisManager() *RedisManager {
 	
 	configPath := "/etc/redis/redis.conf" // Default
 	for _, path := range configPaths {
-		if _, err := os.Stat(path); err == nil {
+		if _, err := Stat(path); err == nil {
 			configPath = path
 			break
 		}
@@ -45,7 +43,7 @@ func NewRedisManager() *RedisManager {
 
 // GetConfig reads current Redis configuration
 func (rm *RedisManager) GetConfig() (*RedisConfig, error) {
-	data, err := os.ReadFile(rm.configPath)
+	data, err := ReadFile(rm.configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
@@ -84,7 +82,7 @@ func (rm *RedisManager) GetConfig() (*RedisConfig, error) {
 
 // SetPassword sets Redis password (requirepass)
 func (rm *RedisManager) SetPassword(password string) error {
-	data, err := os.ReadFile(rm.configPath)
+	data, err := ReadFile(rm.configPath)
 	if err != nil {
 		return fmt.Errorf("failed to read config: %w", err)
 	}
@@ -119,7 +117,7 @@ func (rm *RedisManager) SetPassword(password string) error {
 	
 	// Write back
 	newConfig := strings.Join(lines, "\n")
-	if err := os.WriteFile(rm.configPath, []byte(newConfig), 0644); err != nil {
+	if err := WriteFile(rm.configPath, []byte(newConfig), 0644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 	
@@ -128,7 +126,7 @@ func (rm *RedisManager) SetPassword(password string) error {
 
 // SetPort changes Redis port
 func (rm *RedisManager) SetPort(port string) error {
-	data, err := os.ReadFile(rm.configPath)
+	data, err := ReadFile(rm.configPath)
 	if err != nil {
 		return fmt.Errorf("failed to read config: %w", err)
 	}
@@ -153,7 +151,7 @@ func (rm *RedisManager) SetPort(port string) error {
 	
 	// Write back
 	newConfig := strings.Join(lines, "\n")
-	if err := os.WriteFile(rm.configPath, []byte(newConfig), 0644); err != nil {
+	if err := WriteFile(rm.configPath, []byte(newConfig), 0644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 	
@@ -172,7 +170,7 @@ func (rm *RedisManager) TestConnection() error {
 		args = []string{"-p", config.Port, "-a", config.RequirePass, "ping"}
 	}
 	
-	cmd := exec.Command("redis-cli", args...)
+	cmd := Command("redis-cli", args...)
 	output, err := cmd.CombinedOutput()
 	
 	if err != nil {
@@ -188,12 +186,12 @@ func (rm *RedisManager) TestConnection() error {
 
 // RestartRedis restarts Redis service
 func (rm *RedisManager) RestartRedis() error {
-	cmd := exec.Command("systemctl", "restart", "redis-server")
+	cmd := Command("systemctl", "restart", "redis-server")
 	output, err := cmd.CombinedOutput()
 	
 	if err != nil {
 		// Try alternative service name
-		cmd = exec.Command("systemctl", "restart", "redis")
+		cmd = Command("systemctl", "restart", "redis")
 		output, err = cmd.CombinedOutput()
 		if err != nil {
 			return fmt.Errorf("failed to restart: %s", string(output))
@@ -205,12 +203,12 @@ func (rm *RedisManager) RestartRedis() error {
 
 // GetStatus gets Redis service status
 func (rm *RedisManager) GetStatus() (string, error) {
-	cmd := exec.Command("systemctl", "is-active", "redis-server")
+	cmd := Command("systemctl", "is-active", "redis-server")
 	output, err := cmd.CombinedOutput()
 	
 	if err != nil {
 		// Try alternative service name
-		cmd = exec.Command("systemctl", "is-active", "redis")
+		cmd = Command("systemctl", "is-active", "redis")
 		output, _ = cmd.CombinedOutput()
 	}
 	
@@ -0,0 +1,70 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrWrongPassword is returned when an unlock password is rejected
+var ErrWrongPassword = errors.New("incorrect password")
+
+// ErrPasswordUnverifiable is returned when sudo would succeed without a
+// password, so it cannot be used to check one
+var ErrPasswordUnverifiable = errors.New("sudo does not ask for a password here; set an unlock PIN in Settings")
+
+// passwordCheckArgs returns the sudo arguments that check the password of
+// the person at the terminal. When ravact itself runs under sudo, the check
+// is made as the invoking user rather than root.
+func passwordCheckArgs(euid int, sudoUser string, args ...string) ([]string, error) {
+	if euid != 0 {
+		return args, nil
+	}
+	if sudoUser == "" || sudoUser == "root" {
+		return nil, fmt.Errorf("running as root: set an unlock PIN in Settings")
+	}
+	return append([]string{"-u", sudoUser, "sudo"}, args...), nil
+}
+
+// VerifyLocalPassword checks the login password of the user running ravact
+// on this machine with sudo, ignoring any cached credentials. It always runs
+// locally, even when managing a remote server.
+func VerifyLocalPassword(password string) error {
+	euid, sudoUser := os.Geteuid(), os.Getenv("SUDO_USER")
+	local := LocalTransport{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// With passwordless sudo any password would be accepted
+	args, err := passwordCheckArgs(euid, sudoUser, "-n", "-k", "true")
+	if err != nil {
+		return err
+	}
+	if local.CommandContext(ctx, "sudo", args...).Run() == nil {
+		return ErrPasswordUnverifiable
+	}
+
+	if password == "" {
+		return ErrWrongPassword
+	}
+	args, _ = passwordCheckArgs(euid, sudoUser, "-S", "-k", "-p", "", "true")
+	cmd := local.CommandContext(ctx, "sudo", args...)
+	cmd.Stdin = strings.NewReader(password + "\n")
+	if err := cmd.Run(); err != nil {
+		return ErrWrongPassword
+	}
+	return nil
+}
+
+// DropSudoCredentials invalidates cached sudo credentials on the managed
+// host and on this machine, so privileged commands ask again
+func DropSudoCredentials() {
+	_ = LocalTransport{}.CommandContext(context.Background(), "sudo", "-n", "-k").Run()
+	if CurrentTransport().IsRemote() {
+		_ = Command("sudo", "-n", "-k").Run()
+	}
+}
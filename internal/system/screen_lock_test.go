@@ -0,0 +1,24 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPasswordCheckArgs(t *testing.T) {
+	args, err := passwordCheckArgs(1000, "", "-S", "true")
+	if err != nil || !reflect.DeepEqual(args, []string{"-S", "true"}) {
+		t.Errorf("non-root: got %v, %v", args, err)
+	}
+
+	args, err = passwordCheckArgs(0, "alice", "-S", "true")
+	if err != nil || !reflect.DeepEqual(args, []string{"-u", "alice", "sudo", "-S", "true"}) {
+		t.Errorf("under sudo: got %v, %v", args, err)
+	}
+
+	for _, user := range []string{"", "root"} {
+		if _, err := passwordCheckArgs(0, user, "-S", "true"); err == nil {
+			t.Errorf("expected root with SUDO_USER=%q to need a PIN", user)
+		}
+	}
+}
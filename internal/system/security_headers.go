@@ -0,0 +1,562 @@
+package system
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SecurityHeaders are the response headers written to a site's headers
+// include
+type SecurityHeaders struct {
+	HSTSMaxAge        int // Seconds; 0 leaves out Strict-Transport-Security
+	HSTSSubdomains    bool
+	HSTSPreload       bool
+	FrameOptions      string // DENY or SAMEORIGIN; empty leaves it out
+	NoSniff           bool
+	ReferrerPolicy    string // Empty leaves it out
+	PermissionsPolicy string // Empty leaves it out
+	CSP               ContentSecurityPolicy
+}
+
+// ContentSecurityPolicy is a policy built from the common fetch directives
+type ContentSecurityPolicy struct {
+	Enabled         bool
+	ReportOnly      bool // Browsers report violations without blocking
+	DefaultSrc      string
+	ScriptSrc       string
+	StyleSrc        string
+	ImgSrc          string
+	ConnectSrc      string
+	FontSrc         string
+	FrameAncestors  string
+	UpgradeInsecure bool
+	Extra           string // Other directives, kept as written
+}
+
+// managedHeaders are the headers the include sets; other copies of them
+// are dropped from the hardening snippet so they are not sent twice
+var managedHeaders = []string{
+	"Strict-Transport-Security",
+	"X-Frame-Options",
+	"X-Content-Type-Options",
+	"Referrer-Policy",
+	"Permissions-Policy",
+	"Content-Security-Policy",
+	"Content-Security-Policy-Report-Only",
+}
+
+// ReferrerPolicies are the values browsers accept, safest first
+var ReferrerPolicies = []string{
+	"no-referrer",
+	"same-origin",
+	"strict-origin",
+	"strict-origin-when-cross-origin",
+	"origin",
+	"origin-when-cross-origin",
+	"no-referrer-when-downgrade",
+	"unsafe-url",
+}
+
+// privateReferrerPolicies do not leak paths to other origins
+var privateReferrerPolicies = ReferrerPolicies[:4]
+
+// hstsMinAge is the six months Observatory expects at least
+const hstsMinAge = 15768000
+
+// DefaultSecurityHeaders returns the recommended headers. The policy is
+// prepared but off, since a CSP that does not match the site breaks it.
+func DefaultSecurityHeaders() SecurityHeaders {
+	return SecurityHeaders{
+		HSTSMaxAge:        31536000,
+		FrameOptions:      "SAMEORIGIN",
+		NoSniff:           true,
+		ReferrerPolicy:    "strict-origin-when-cross-origin",
+		PermissionsPolicy: "camera=(), microphone=(), geolocation=()",
+		CSP:               defaultCSP(),
+	}
+}
+
+// defaultCSP is a same-origin policy that still allows inline styles,
+// data: images and fonts, which most frameworks need
+func defaultCSP() ContentSecurityPolicy {
+	return ContentSecurityPolicy{
+		DefaultSrc:     "'self'",
+		ScriptSrc:      "'self'",
+		StyleSrc:       "'self' 'unsafe-inline'",
+		ImgSrc:         "'self' data:",
+		ConnectSrc:     "'self'",
+		FontSrc:        "'self' data:",
+		FrameAncestors: "'self'",
+		Extra:          "object-src 'none'; base-uri 'self'",
+	}
+}
+
+// fields returns the directive names and values of the policy in order
+func (c *ContentSecurityPolicy) fields() []struct {
+	name  string
+	value *string
+} {
+	return []struct {
+		name  string
+		value *string
+	}{
+		{"default-src", &c.DefaultSrc},
+		{"script-src", &c.ScriptSrc},
+		{"style-src", &c.StyleSrc},
+		{"img-src", &c.ImgSrc},
+		{"connect-src", &c.ConnectSrc},
+		{"font-src", &c.FontSrc},
+		{"frame-ancestors", &c.FrameAncestors},
+	}
+}
+
+// String renders the policy as a header value
+func (c ContentSecurityPolicy) String() string {
+	var parts []string
+	for _, f := range c.fields() {
+		if v := strings.TrimSpace(*f.value); v != "" {
+			parts = append(parts, f.name+" "+v)
+		}
+	}
+	if c.UpgradeInsecure {
+		parts = append(parts, "upgrade-insecure-requests")
+	}
+	for _, d := range strings.Split(c.Extra, ";") {
+		if d = strings.TrimSpace(d); d != "" {
+			parts = append(parts, d)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ParseCSP reads a policy header value
+func ParseCSP(value string) ContentSecurityPolicy {
+	c := ContentSecurityPolicy{Enabled: true}
+	var extra []string
+	for _, d := range strings.Split(value, ";") {
+		d = strings.TrimSpace(d)
+		name, sources, _ := strings.Cut(d, " ")
+		if name == "" {
+			continue
+		}
+		if name == "upgrade-insecure-requests" {
+			c.UpgradeInsecure = true
+			continue
+		}
+		known := false
+		for _, f := range c.fields() {
+			if f.name == name {
+				*f.value, known = strings.TrimSpace(sources), true
+			}
+		}
+		if !known {
+			extra = append(extra, d)
+		}
+	}
+	c.Extra = strings.Join(extra, "; ")
+	return c
+}
+
+// Validate checks the values can be written into nginx quoted strings
+// and are ones browsers understand
+func (h SecurityHeaders) Validate() error {
+	if h.HSTSMaxAge < 0 {
+		return fmt.Errorf("HSTS max-age cannot be negative")
+	}
+	if h.HSTSPreload && (h.HSTSMaxAge < 31536000 || !h.HSTSSubdomains) {
+		return fmt.Errorf("HSTS preload needs a max-age of a year or more and includeSubDomains")
+	}
+	if h.FrameOptions != "" && h.FrameOptions != "DENY" && h.FrameOptions != "SAMEORIGIN" {
+		return fmt.Errorf("X-Frame-Options must be DENY or SAMEORIGIN")
+	}
+	if h.ReferrerPolicy != "" && !slices.Contains(ReferrerPolicies, h.ReferrerPolicy) {
+		return fmt.Errorf("unknown Referrer-Policy %q", h.ReferrerPolicy)
+	}
+	for _, header := range h.Headers() {
+		if strings.ContainsAny(header[1], "\"\n\\") {
+			return fmt.Errorf("%s must not contain double quotes, backslashes, or line breaks", header[0])
+		}
+	}
+	if h.CSP.Enabled && h.CSP.String() == "" {
+		return fmt.Errorf("the Content-Security-Policy has no directives")
+	}
+	return nil
+}
+
+// Headers returns the header names and values in the order they are written
+func (h SecurityHeaders) Headers() [][2]string {
+	var headers [][2]string
+	if h.HSTSMaxAge > 0 {
+		value := "max-age=" + strconv.Itoa(h.HSTSMaxAge)
+		if h.HSTSSubdomains {
+			value += "; includeSubDomains"
+		}
+		if h.HSTSPreload {
+			value += "; preload"
+		}
+		headers = append(headers, [2]string{"Strict-Transport-Security", value})
+	}
+	if h.FrameOptions != "" {
+		headers = append(headers, [2]string{"X-Frame-Options", h.FrameOptions})
+	}
+	if h.NoSniff {
+		headers = append(headers, [2]string{"X-Content-Type-Options", "nosniff"})
+	}
+	if h.ReferrerPolicy != "" {
+		headers = append(headers, [2]string{"Referrer-Policy", h.ReferrerPolicy})
+	}
+	if h.PermissionsPolicy != "" {
+		headers = append(headers, [2]string{"Permissions-Policy", h.PermissionsPolicy})
+	}
+	if h.CSP.Enabled {
+		name := "Content-Security-Policy"
+		if h.CSP.ReportOnly {
+			name += "-Report-Only"
+		}
+		headers = append(headers, [2]string{name, h.CSP.String()})
+	}
+	return headers
+}
+
+// Include returns the nginx include with the headers. always sends them on
+// error pages and redirects too.
+func (h SecurityHeaders) Include() string {
+	var b strings.Builder
+	b.WriteString("# Security headers managed by ravact\n")
+	for _, header := range h.Headers() {
+		fmt.Fprintf(&b, "add_header %s \"%s\" always;\n", header[0], header[1])
+	}
+	return b.String()
+}
+
+// SecurityHeadersFromSent reads the headers a site sends, keyed by
+// lower-case name, back into editor settings
+func SecurityHeadersFromSent(sent map[string]string) SecurityHeaders {
+	var h SecurityHeaders
+	if v, ok := sent["strict-transport-security"]; ok {
+		for _, part := range strings.Split(v, ";") {
+			part = strings.TrimSpace(part)
+			switch {
+			case strings.HasPrefix(strings.ToLower(part), "max-age="):
+				h.HSTSMaxAge, _ = strconv.Atoi(part[len("max-age="):])
+			case strings.EqualFold(part, "includeSubDomains"):
+				h.HSTSSubdomains = true
+			case strings.EqualFold(part, "preload"):
+				h.HSTSPreload = true
+			}
+		}
+	}
+	h.FrameOptions = strings.ToUpper(sent["x-frame-options"])
+	h.NoSniff = strings.EqualFold(sent["x-content-type-options"], "nosniff")
+	h.ReferrerPolicy = strings.ToLower(sent["referrer-policy"])
+	h.PermissionsPolicy = sent["permissions-policy"]
+	h.CSP = defaultCSP()
+	if v, ok := sent["content-security-policy"]; ok {
+		h.CSP = ParseCSP(v)
+	} else if v, ok := sent["content-security-policy-report-only"]; ok {
+		h.CSP = ParseCSP(v)
+		h.CSP.ReportOnly = true
+	}
+	return h
+}
+
+// parseAddHeader reads the name and value of an add_header line
+func parseAddHeader(line string) (name, value string, ok bool) {
+	rest, found := strings.CutPrefix(strings.TrimSpace(line), "add_header ")
+	if !found {
+		return "", "", false
+	}
+	name, rest, _ = strings.Cut(strings.TrimSpace(rest), " ")
+	rest = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(rest), ";"))
+	rest = strings.TrimSpace(strings.TrimSuffix(rest, " always"))
+	if len(rest) >= 2 && (rest[0] == '"' || rest[0] == '\'') && rest[len(rest)-1] == rest[0] {
+		rest = rest[1 : len(rest)-1]
+	}
+	return name, rest, name != ""
+}
+
+// SecurityHeadersPath is the headers include of a site
+func SecurityHeadersPath(siteName string) string {
+	return "/etc/nginx/snippets/ravact-headers-" + siteName + ".conf"
+}
+
+// SiteHeaders is what the security headers editor knows about a site
+type SiteHeaders struct {
+	Site       NginxSite
+	Config     string
+	Sent       map[string]string // Headers of the site's server block, by lower-case name
+	Overridden []string          // Locations with their own add_header, where the server's are not sent
+	Managed    bool              // The site includes its ravact headers file
+}
+
+// InspectSiteHeaders reads the headers a site sends from its config and
+// the files its server block includes
+func InspectSiteHeaders(site NginxSite) SiteHeaders {
+	s := SiteHeaders{Site: site}
+	if data, err := ReadFile(site.ConfigPath); err == nil {
+		s.Config = string(data)
+	}
+	s.Managed = strings.Contains(s.Config, SecurityHeadersPath(site.Name))
+	s.Sent, s.Overridden = sentHeaders(s.Config, func(path string) string {
+		data, _ := ReadFile(path)
+		return string(data)
+	})
+	return s
+}
+
+// sentHeaders collects the server-level add_header directives of the
+// first server block with a document root, following its includes
+func sentHeaders(config string, read func(path string) string) (map[string]string, []string) {
+	sent := map[string]string{}
+	var overridden []string
+	lines := strings.Split(config, "\n")
+	for _, b := range findServerBlocks(lines) {
+		direct := blockDirectives(lines, b)
+		if !slices.ContainsFunc(direct, func(i int) bool { return directiveName(lines[i]) == "root" }) {
+			continue
+		}
+		for _, i := range direct {
+			trimmed := strings.TrimSpace(lines[i])
+			switch directiveName(trimmed) {
+			case "add_header":
+				if name, value, ok := parseAddHeader(trimmed); ok {
+					sent[strings.ToLower(name)] = value
+				}
+			case "include":
+				fields := strings.Fields(strings.TrimSuffix(trimmed, ";"))
+				if len(fields) < 2 || strings.Contains(fields[1], "*") {
+					continue
+				}
+				path := fields[1]
+				for _, line := range strings.Split(read(path), "\n") {
+					if name, value, ok := parseAddHeader(line); ok {
+						sent[strings.ToLower(name)] = value
+					}
+				}
+			case "location":
+				for j := i + 1; j < blockEnd(lines, i); j++ {
+					if directiveName(lines[j]) == "add_header" {
+						fields := strings.Fields(strings.TrimSuffix(trimmed, "{"))
+						overridden = append(overridden, strings.Join(fields[1:], " "))
+						break
+					}
+				}
+			}
+		}
+		break
+	}
+	return sent, overridden
+}
+
+// HasSSL reports whether the site serves HTTPS
+func (s SiteHeaders) HasSSL() bool {
+	return s.Site.HasSSL || strings.Contains(s.Config, "ssl_certificate")
+}
+
+// Current returns the editor settings for what the site sends now, or the
+// recommended headers when it sends none
+func (s SiteHeaders) Current() SecurityHeaders {
+	for _, name := range managedHeaders {
+		if _, ok := s.Sent[strings.ToLower(name)]; ok {
+			return SecurityHeadersFromSent(s.Sent)
+		}
+	}
+	return DefaultSecurityHeaders()
+}
+
+// Score grades what the site sends now
+func (s SiteHeaders) Score() HeadersScore {
+	return ScoreHeaders(s.Sent, s.HasSSL(), s.Overridden)
+}
+
+// ScoreWith grades the site as it would be with h applied
+func (s SiteHeaders) ScoreWith(h SecurityHeaders) HeadersScore {
+	sent := maps.Clone(s.Sent)
+	for _, name := range managedHeaders {
+		delete(sent, strings.ToLower(name))
+	}
+	for _, header := range h.Headers() {
+		sent[strings.ToLower(header[0])] = header[1]
+	}
+	return ScoreHeaders(sent, s.HasSSL(), s.Overridden)
+}
+
+// IncludedConfig returns the site config with the headers include after
+// the root of every server block that serves content
+func (s SiteHeaders) IncludedConfig() string {
+	path := SecurityHeadersPath(s.Site.Name)
+	if strings.Contains(s.Config, path) {
+		return s.Config
+	}
+	lines := strings.Split(s.Config, "\n")
+	blocks := findServerBlocks(lines)
+	for bi := len(blocks) - 1; bi >= 0; bi-- {
+		for _, i := range blockDirectives(lines, blocks[bi]) {
+			if directiveName(lines[i]) == "root" {
+				indent := lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " \t"))]
+				lines = slices.Insert(lines, i+1, indent+"include "+path+";")
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Script returns the bash script that writes the include, references it
+// from the site, and drops the same headers from the hardening snippet.
+// Everything is restored if nginx rejects the result.
+func (s SiteHeaders) Script(h SecurityHeaders) string {
+	include := ShellQuote(SecurityHeadersPath(s.Site.Name))
+	config := ShellQuote(s.Site.ConfigPath)
+	hardening := ShellQuote((SiteHardening{Site: s.Site}).SnippetPath())
+
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	b.WriteString("mkdir -p /etc/nginx/snippets\n")
+	fmt.Fprintf(&b, "cp %s %s.bak\n", config, config)
+	fmt.Fprintf(&b, "if [ -f %s ]; then cp %s %s.bak; else : > %s.bak; fi\n", include, include, include, include)
+	fmt.Fprintf(&b, "if [ -f %s ]; then cp %s %s.bak; fi\n", hardening, hardening, hardening)
+	fmt.Fprintf(&b, "cat > %s <<'EOF'\n%sEOF\n", include, h.Include())
+	fmt.Fprintf(&b, "cat > %s <<'EOF'\n%s\nEOF\n", config, strings.TrimRight(s.IncludedConfig(), "\n"))
+	fmt.Fprintf(&b, "if [ -f %s ]; then sed -i -E '/^add_header (%s) /d' %s; fi\n", hardening, strings.Join(managedHeaders, "|"), hardening)
+	fmt.Fprintf(&b, "if ! nginx -t; then mv %s.bak %s; mv %s.bak %s; [ -s %s ] || rm -f %s; [ ! -f %s.bak ] || mv %s.bak %s; echo 'nginx rejected the headers; restored the previous config'; exit 1; fi\n",
+		config, config, include, include, include, include, hardening, hardening, hardening)
+	fmt.Fprintf(&b, "rm -f %s.bak %s.bak %s.bak\n", config, include, hardening)
+	b.WriteString("systemctl reload nginx\n")
+	b.WriteString("echo; echo '==> Security headers applied'\n")
+	return b.String()
+}
+
+// HeaderCheck is one line of a headers score
+type HeaderCheck struct {
+	Title    string
+	Passed   bool
+	Modifier int // Points added to or taken from the base score of 100
+	Detail   string
+}
+
+// HeadersScore grades headers the way Mozilla Observatory does: a base of
+// 100 with points taken for missing or weak headers
+type HeadersScore struct {
+	Score  int
+	Grade  string
+	Checks []HeaderCheck
+}
+
+// headerGrades are the lowest scores of each grade
+var headerGrades = []struct {
+	min   int
+	grade string
+}{
+	{100, "A+"}, {90, "A"}, {85, "A-"}, {80, "B+"}, {70, "B"}, {65, "B-"},
+	{60, "C+"}, {50, "C"}, {45, "C-"}, {40, "D+"}, {30, "D"}, {25, "D-"},
+}
+
+// ScoreHeaders grades the headers a site sends, keyed by lower-case name
+func ScoreHeaders(sent map[string]string, hasSSL bool, overridden []string) HeadersScore {
+	var checks []HeaderCheck
+
+	csp := HeaderCheck{Title: "Content-Security-Policy"}
+	policy, hasPolicy := sent["content-security-policy"]
+	switch {
+	case !hasPolicy && sent["content-security-policy-report-only"] != "":
+		csp.Modifier, csp.Detail = -25, "Report-only policies are not enforced"
+	case !hasPolicy:
+		csp.Modifier, csp.Detail = -25, "Not set"
+	default:
+		parsed := ParseCSP(policy)
+		scripts := parsed.ScriptSrc
+		if scripts == "" {
+			scripts = parsed.DefaultSrc
+		}
+		switch {
+		case scripts == "" || strings.Contains(scripts, "*"):
+			csp.Modifier, csp.Detail = -20, "Scripts may load from anywhere"
+		case strings.Contains(scripts, "'unsafe-inline'"):
+			csp.Modifier, csp.Detail = -20, "Allows inline scripts ('unsafe-inline')"
+		case strings.Contains(scripts, "'unsafe-eval'"):
+			csp.Modifier, csp.Detail = -10, "Allows eval ('unsafe-eval')"
+		default:
+			csp.Passed, csp.Detail = true, "Scripts are restricted"
+		}
+	}
+	checks = append(checks, csp)
+
+	hsts := HeaderCheck{Title: "Strict-Transport-Security"}
+	value, hasHSTS := sent["strict-transport-security"]
+	maxAge := SecurityHeadersFromSent(map[string]string{"strict-transport-security": value}).HSTSMaxAge
+	switch {
+	case !hasSSL:
+		hsts.Modifier, hsts.Detail = -20, "The site has no HTTPS"
+	case !hasHSTS:
+		hsts.Modifier, hsts.Detail = -20, "Not set"
+	case maxAge < hstsMinAge:
+		hsts.Modifier, hsts.Detail = -10, fmt.Sprintf("max-age of %d days is under six months", maxAge/86400)
+	default:
+		hsts.Passed, hsts.Detail = true, fmt.Sprintf("max-age of %d days", maxAge/86400)
+		if strings.Contains(strings.ToLower(value), "preload") {
+			hsts.Modifier, hsts.Detail = 5, hsts.Detail+", preload"
+		}
+	}
+	checks = append(checks, hsts)
+
+	frame := HeaderCheck{Title: "X-Frame-Options"}
+	switch v := strings.ToUpper(sent["x-frame-options"]); {
+	case ParseCSP(policy).FrameAncestors != "" && hasPolicy:
+		frame.Passed, frame.Detail = true, "Framing restricted by CSP frame-ancestors"
+	case v == "DENY" || v == "SAMEORIGIN":
+		frame.Passed, frame.Detail = true, v
+	default:
+		frame.Modifier, frame.Detail = -20, "Not set; other sites can frame this one (clickjacking)"
+	}
+	checks = append(checks, frame)
+
+	nosniff := HeaderCheck{Title: "X-Content-Type-Options"}
+	if strings.EqualFold(sent["x-content-type-options"], "nosniff") {
+		nosniff.Passed, nosniff.Detail = true, "nosniff"
+	} else {
+		nosniff.Modifier, nosniff.Detail = -5, "Not set to nosniff"
+	}
+	checks = append(checks, nosniff)
+
+	referrer := HeaderCheck{Title: "Referrer-Policy"}
+	switch v := strings.ToLower(sent["referrer-policy"]); {
+	case slices.Contains(privateReferrerPolicies, v):
+		referrer.Passed, referrer.Modifier, referrer.Detail = true, 5, v
+	case v == "":
+		referrer.Detail = "Not set; browsers default to strict-origin-when-cross-origin"
+	default:
+		referrer.Modifier, referrer.Detail = -5, v+" leaks paths to other sites"
+	}
+	checks = append(checks, referrer)
+
+	permissions := HeaderCheck{Title: "Permissions-Policy", Detail: "Not set (not scored)"}
+	if v := sent["permissions-policy"]; v != "" {
+		permissions.Passed, permissions.Detail = true, v
+	}
+	checks = append(checks, permissions)
+
+	if len(overridden) > 0 {
+		checks = append(checks, HeaderCheck{
+			Title:  "Location headers",
+			Detail: "add_header in " + strings.Join(overridden, ", ") + " replaces these headers there (not scored)",
+		})
+	}
+
+	score := 100
+	for _, c := range checks {
+		score += c.Modifier
+	}
+	score = max(score, 0)
+	grade := "F"
+	for _, g := range headerGrades {
+		if score >= g.min {
+			grade = g.grade
+			break
+		}
+	}
+	return HeadersScore{Score: score, Grade: grade, Checks: checks}
+}
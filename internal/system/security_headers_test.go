@@ -0,0 +1,109 @@
+package system
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSecurityHeaders_Include(t *testing.T) {
+	h := DefaultSecurityHeaders()
+	h.HSTSSubdomains = true
+	h.CSP.Enabled = true
+	h.CSP.UpgradeInsecure = true
+	if err := h.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	include := h.Include()
+	for _, want := range []string{
+		"add_header Strict-Transport-Security \"max-age=31536000; includeSubDomains\" always;\n",
+		"add_header X-Frame-Options \"SAMEORIGIN\" always;\n",
+		"add_header Content-Security-Policy \"default-src 'self'; script-src 'self'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; connect-src 'self'; font-src 'self' data:; frame-ancestors 'self'; upgrade-insecure-requests; object-src 'none'; base-uri 'self'\" always;\n",
+	} {
+		if !strings.Contains(include, want) {
+			t.Errorf("include missing %q:\n%s", want, include)
+		}
+	}
+
+	// Reading the include back gives the same settings
+	sent := map[string]string{}
+	for _, line := range strings.Split(include, "\n") {
+		if name, value, ok := parseAddHeader(line); ok {
+			sent[strings.ToLower(name)] = value
+		}
+	}
+	if got := SecurityHeadersFromSent(sent); !reflect.DeepEqual(got, h) {
+		t.Errorf("round trip = %+v, want %+v", got, h)
+	}
+
+	for _, bad := range []SecurityHeaders{
+		{FrameOptions: "ALLOW-FROM x"},
+		{ReferrerPolicy: "sometimes"},
+		{HSTSMaxAge: 300, HSTSPreload: true},
+		{PermissionsPolicy: `camera="self"`},
+	} {
+		if bad.Validate() == nil {
+			t.Errorf("expected %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestScoreHeaders(t *testing.T) {
+	if s := ScoreHeaders(map[string]string{}, true, nil); s.Score != 30 || s.Grade != "D" {
+		t.Errorf("no headers: got %d %s, want 30 D", s.Score, s.Grade)
+	}
+
+	h := DefaultSecurityHeaders()
+	h.CSP.Enabled = true
+	site := SiteHeaders{Site: NginxSite{HasSSL: true}, Sent: map[string]string{"x-frame-options": "DENY"}}
+	if s := site.ScoreWith(h); s.Score != 105 || s.Grade != "A+" {
+		t.Errorf("recommended headers: got %d %s, want 105 A+", s.Score, s.Grade)
+	}
+
+	h.CSP.ScriptSrc = "'self' 'unsafe-inline'"
+	h.HSTSMaxAge = 86400
+	site.Site.HasSSL = false
+	s := site.ScoreWith(h)
+	if s.Score != 65 || s.Grade != "B-" {
+		t.Errorf("weak headers: got %d %s, want 65 B-", s.Score, s.Grade)
+	}
+}
+
+func TestSiteHeaders_IncludedConfig(t *testing.T) {
+	config := testHardeningConfig
+	config = strings.Replace(config, "    root /var/www/example/public;\n", "    root /var/www/example/public;\n    include /etc/nginx/snippets/extra.conf;\n\n    location /assets {\n        add_header Cache-Control \"public\";\n    }\n", 1)
+	site := NginxSite{Name: "example.com", ConfigPath: "/etc/nginx/sites-available/example.com"}
+	sent, overridden := sentHeaders(config, func(path string) string {
+		if path == "/etc/nginx/snippets/extra.conf" {
+			return "add_header X-Content-Type-Options nosniff always;\nadd_header Referrer-Policy 'no-referrer';\n"
+		}
+		return ""
+	})
+	if sent["x-content-type-options"] != "nosniff" || sent["referrer-policy"] != "no-referrer" {
+		t.Errorf("unexpected headers from the include: %v", sent)
+	}
+	if !reflect.DeepEqual(overridden, []string{"/assets"}) {
+		t.Errorf("overridden = %v, want [/assets]", overridden)
+	}
+
+	s := SiteHeaders{Site: site, Config: config}
+	included := s.IncludedConfig()
+	if strings.Count(included, SecurityHeadersPath(site.Name)) != 1 ||
+		!strings.Contains(included, "    root /var/www/example/public;\n    include /etc/nginx/snippets/ravact-headers-example.com.conf;\n") {
+		t.Errorf("expected the include once, after the root:\n%s", included)
+	}
+	s.Config = included
+	if s.IncludedConfig() != included {
+		t.Error("expected including twice to be stable")
+	}
+
+	// Production hardening leaves the headers to the include
+	h := testSiteHardening()
+	h.Config = included
+	if strings.Contains(h.Snippet(), "add_header") {
+		t.Errorf("hardening snippet should not set headers:\n%s", h.Snippet())
+	}
+	if !strings.Contains(s.Script(DefaultSecurityHeaders()), "nginx -t") {
+		t.Error("the script should test nginx before reloading")
+	}
+}
@@ -0,0 +1,121 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	sentryProjectPattern = regexp.MustCompile(`^[0-9]+$`)
+	sentryEnvPattern     = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+)
+
+// SentrySetup is how a site reports errors to Sentry
+type SentrySetup struct {
+	Laravel     bool   // Uses sentry/sentry-laravel and SENTRY_LARAVEL_DSN
+	DSN         string // Client key URL from the Sentry project's settings
+	Environment string // Optional; Laravel falls back to APP_ENV
+}
+
+// ValidateSentryDSN checks that a DSN looks like
+// https://<key>@<host>/<project id>
+func ValidateSentryDSN(dsn string) error {
+	u, err := url.Parse(dsn)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("DSN must be a URL like https://<key>@o0.ingest.sentry.io/<project>")
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return fmt.Errorf("DSN is missing the public key before the @")
+	}
+	if !sentryProjectPattern.MatchString(path.Base(u.Path)) {
+		return fmt.Errorf("DSN must end with the numeric project ID")
+	}
+	return nil
+}
+
+// Validate checks the DSN and environment
+func (s SentrySetup) Validate() error {
+	if err := ValidateSentryDSN(s.DSN); err != nil {
+		return err
+	}
+	if s.Environment != "" && !sentryEnvPattern.MatchString(s.Environment) {
+		return fmt.Errorf("environment must be letters, digits, dots, dashes, or underscores")
+	}
+	return nil
+}
+
+// DSNKey is the .env key the SDK reads the DSN from
+func (s SentrySetup) DSNKey() string {
+	if s.Laravel {
+		return "SENTRY_LARAVEL_DSN"
+	}
+	return "SENTRY_DSN"
+}
+
+// Package is the Composer package of the site's Sentry SDK
+func (s SentrySetup) Package() string {
+	if s.Laravel {
+		return "sentry/sentry-laravel"
+	}
+	return "sentry/sentry"
+}
+
+// Installed reports whether the project in dir already requires the SDK
+func (s SentrySetup) Installed(dir string) bool {
+	data, err := ReadFile(filepath.Join(dir, "composer.json"))
+	if err != nil {
+		return false
+	}
+	var composer struct {
+		Require map[string]string `json:"require"`
+	}
+	if json.Unmarshal(data, &composer) != nil {
+		return false
+	}
+	_, ok := composer.Require[s.Package()]
+	return ok
+}
+
+// Env returns the .env settings for the SDK
+func (s SentrySetup) Env() [][2]string {
+	env := [][2]string{{s.DSNKey(), s.DSN}}
+	if s.Environment != "" {
+		env = append(env, [2]string{"SENTRY_ENVIRONMENT", s.Environment})
+	}
+	return env
+}
+
+// Script returns the commands, run in dir as the site's user, that install
+// the SDK and send a test event. It reads the DSN from .env so the DSN
+// stays out of the command line and the task log. Laravel's config cache
+// is rebuilt first so the new .env values take effect.
+func (s SentrySetup) Script(dir, user string, install, test bool) (string, error) {
+	if !usernamePattern.MatchString(user) || user == "root" {
+		return "", fmt.Errorf("choose the site's system user to run Composer as (got %q)", user)
+	}
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	fmt.Fprintf(&b, "cd %s\n", ShellQuote(dir))
+	if install {
+		fmt.Fprintf(&b, "echo '==> Installing %s'\n", s.Package())
+		fmt.Fprintf(&b, "composer require %s --no-interaction\n", s.Package())
+	}
+	if s.Laravel {
+		b.WriteString("if [ -f bootstrap/cache/config.php ]; then php artisan config:cache; fi\n")
+	}
+	if test {
+		b.WriteString("echo '==> Sending a test event'\n")
+		if s.Laravel {
+			b.WriteString("php artisan sentry:test\n")
+		} else {
+			fmt.Fprintf(&b, "SENTRY_DSN=\"$(grep -E '^%s=' .env | tail -n 1 | cut -d= -f2- | tr -d '\"')\" php -r %s\n", s.DSNKey(),
+				ShellQuote(`require "vendor/autoload.php"; \Sentry\init(["dsn" => getenv("SENTRY_DSN")]); $id = \Sentry\captureMessage("ravact test event"); \Sentry\SentrySdk::getCurrentHub()->getClient()->flush(); echo $id ? "Sent test event $id\n" : "Sentry did not accept the event\n"; exit($id ? 0 : 1);`))
+		}
+	}
+	return AsUser(user, b.String()), nil
+}
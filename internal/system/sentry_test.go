@@ -0,0 +1,65 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSentryDSN(t *testing.T) {
+	for _, dsn := range []string{
+		"https://abc123@o4505.ingest.sentry.io/4505123",
+		"http://key@sentry.internal:9000/7",
+	} {
+		if err := ValidateSentryDSN(dsn); err != nil {
+			t.Errorf("expected %q to be valid: %v", dsn, err)
+		}
+	}
+	for _, dsn := range []string{
+		"",
+		"o4505.ingest.sentry.io/4505123",
+		"https://o4505.ingest.sentry.io/4505123",
+		"https://abc123@o4505.ingest.sentry.io/project",
+	} {
+		if err := ValidateSentryDSN(dsn); err == nil {
+			t.Errorf("expected %q to be rejected", dsn)
+		}
+	}
+}
+
+func TestSentrySetupEnv(t *testing.T) {
+	s := SentrySetup{Laravel: true, DSN: "https://abc@o1.ingest.sentry.io/2", Environment: "staging"}
+	env := s.Env()
+	if len(env) != 2 || env[0][0] != "SENTRY_LARAVEL_DSN" || env[1] != [2]string{"SENTRY_ENVIRONMENT", "staging"} {
+		t.Errorf("unexpected Laravel env: %v", env)
+	}
+	s.Laravel, s.Environment = false, ""
+	if env := s.Env(); len(env) != 1 || env[0][0] != "SENTRY_DSN" {
+		t.Errorf("unexpected env: %v", env)
+	}
+}
+
+func TestSentrySetupScript(t *testing.T) {
+	s := SentrySetup{Laravel: true, DSN: "https://abc@o1.ingest.sentry.io/2"}
+	script, err := s.Script("/var/www/app", "deploy", true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"sudo -u deploy", "composer require sentry/sentry-laravel", "php artisan sentry:test"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script is missing %q:\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, s.DSN) {
+		t.Error("the DSN should not appear in the script")
+	}
+
+	s.Laravel = false
+	script, _ = s.Script("/var/www/app", "deploy", false, true)
+	if strings.Contains(script, "composer require") || !strings.Contains(script, "captureMessage") {
+		t.Errorf("expected only the plain PHP test event:\n%s", script)
+	}
+
+	if _, err := s.Script("/var/www/app", "root", true, false); err == nil {
+		t.Error("expected root to be rejected")
+	}
+}
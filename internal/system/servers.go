@@ -0,0 +1,118 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Server is a remote host ravact can manage over SSH
+type Server struct {
+	Name         string   `yaml:"name"`
+	Host         string   `yaml:"host"`
+	User         string   `yaml:"user,omitempty"`
+	Port         int      `yaml:"port,omitempty"`
+	IdentityFile string   `yaml:"identity_file,omitempty"`
+	Tags         []string `yaml:"tags,omitempty"`
+}
+
+// Destination returns the ssh destination (user@host)
+func (s Server) Destination() string {
+	if s.User != "" {
+		return s.User + "@" + s.Host
+	}
+	return s.Host
+}
+
+// Address returns a display form of the destination including a non-default port
+func (s Server) Address() string {
+	if s.Port != 0 && s.Port != 22 {
+		return s.Destination() + ":" + strconv.Itoa(s.Port)
+	}
+	return s.Destination()
+}
+
+// ServerInventory is the list of servers stored in servers.yaml
+type ServerInventory struct {
+	Servers []Server `yaml:"servers"`
+}
+
+// DefaultServerInventoryPath returns ~/.ravact/servers.yaml
+func DefaultServerInventoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ravact", "servers.yaml")
+}
+
+// LoadServerInventory reads the inventory, returning an empty one if the file does not exist
+func LoadServerInventory(path string) (*ServerInventory, error) {
+	inv := &ServerInventory{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return inv, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server inventory: %w", err)
+	}
+	if err := yaml.Unmarshal(data, inv); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return inv, nil
+}
+
+// Save writes the inventory to path
+func (inv *ServerInventory) Save(path string) error {
+	data, err := yaml.Marshal(inv)
+	if err != nil {
+		return fmt.Errorf("failed to encode server inventory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write server inventory: %w", err)
+	}
+	return nil
+}
+
+// Find returns the server with the given name
+func (inv *ServerInventory) Find(name string) (Server, bool) {
+	for _, s := range inv.Servers {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Server{}, false
+}
+
+// Add appends a server, rejecting duplicate names
+func (inv *ServerInventory) Add(s Server) error {
+	if s.Name == "" || s.Host == "" {
+		return fmt.Errorf("server name and host are required")
+	}
+	// ssh would read a leading dash as an option, such as -oProxyCommand
+	if strings.HasPrefix(s.Host, "-") || strings.HasPrefix(s.User, "-") {
+		return fmt.Errorf("server host and user cannot start with '-'")
+	}
+	if _, exists := inv.Find(s.Name); exists {
+		return fmt.Errorf("server %q already exists", s.Name)
+	}
+	inv.Servers = append(inv.Servers, s)
+	return nil
+}
+
+// Remove deletes the server with the given name
+func (inv *ServerInventory) Remove(name string) {
+	for i, s := range inv.Servers {
+		if s.Name == name {
+			inv.Servers = append(inv.Servers[:i], inv.Servers[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package system
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestServerInventoryRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers.yaml")
+
+	inv, err := LoadServerInventory(path)
+	if err != nil {
+		t.Fatalf("loading missing inventory: %v", err)
+	}
+	if len(inv.Servers) != 0 {
+		t.Fatalf("expected empty inventory, got %d servers", len(inv.Servers))
+	}
+
+	if err := inv.Add(Server{Name: "web-1", Host: "203.0.113.10", User: "deploy", Port: 2222}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := inv.Add(Server{Name: "web-1", Host: "203.0.113.11"}); err == nil {
+		t.Error("expected duplicate name to be rejected")
+	}
+	if err := inv.Add(Server{Name: "no-host"}); err == nil {
+		t.Error("expected server without host to be rejected")
+	}
+	if err := inv.Add(Server{Name: "proxy", Host: "-oProxyCommand=touch /tmp/x"}); err == nil {
+		t.Error("expected a host starting with '-' to be rejected")
+	}
+	if err := inv.Add(Server{Name: "proxy", Host: "203.0.113.12", User: "-oProxyCommand=id"}); err == nil {
+		t.Error("expected a user starting with '-' to be rejected")
+	}
+	if err := inv.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := LoadServerInventory(path)
+	if err != nil {
+		t.Fatalf("LoadServerInventory: %v", err)
+	}
+	s, ok := loaded.Find("web-1")
+	if !ok {
+		t.Fatal("web-1 not found after reload")
+	}
+	if s.Address() != "deploy@203.0.113.10:2222" {
+		t.Errorf("unexpected address %q", s.Address())
+	}
+
+	loaded.Remove("web-1")
+	if _, ok := loaded.Find("web-1"); ok {
+		t.Error("web-1 still present after Remove")
+	}
+}
+
+func TestSSHArgsEndOptionsBeforeDestination(t *testing.T) {
+	args := NewSSHTransport(Server{Name: "web-1", Host: "-oProxyCommand=id", Port: 2222}).sshArgs()
+	if len(args) < 2 || args[len(args)-2] != "--" || args[len(args)-1] != "-oProxyCommand=id" {
+		t.Errorf("destination must follow --, got %q", args)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"/etc/nginx/nginx.conf": "/etc/nginx/nginx.conf",
+		"":                      "''",
+		"two words":             "'two words'",
+		"it's":                  `'it'"'"'s'`,
+		"$(reboot)":             "'$(reboot)'",
+	}
+	for in, want := range tests {
+		if got := ShellQuote(in); got != want {
+			t.Errorf("ShellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseRemoteStat(t *testing.T) {
+	info, err := parseRemoteStat("site.conf", "1024 81a4 1700000000")
+	if err != nil {
+		t.Fatalf("parseRemoteStat: %v", err)
+	}
+	if info.Name() != "site.conf" || info.Size() != 1024 {
+		t.Errorf("unexpected info: %s %d", info.Name(), info.Size())
+	}
+	if info.IsDir() || info.Mode().Perm() != 0644 {
+		t.Errorf("unexpected mode %v", info.Mode())
+	}
+
+	dir, err := parseRemoteStat("sites", "4096 41ed 1700000000")
+	if err != nil {
+		t.Fatalf("parseRemoteStat: %v", err)
+	}
+	if !dir.IsDir() {
+		t.Error("expected directory")
+	}
+
+	if _, err := parseRemoteStat("bad", "garbage"); err == nil {
+		t.Error("expected error for malformed output")
+	}
+}
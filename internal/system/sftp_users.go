@@ -0,0 +1,254 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SFTPGroup is the group whose members sshd restricts to chrooted SFTP
+const SFTPGroup = "sftponly"
+
+// SFTPChrootRoot holds a root-owned chroot for each SFTP-only user
+var SFTPChrootRoot = "/srv/sftp"
+
+// SFTPKeysDir holds SFTP-only users' public keys. sshd reads keys before
+// entering the chroot, and the users have no home directory outside it.
+var SFTPKeysDir = "/etc/ssh/sftp_keys"
+
+// FstabPath is the filesystem table that makes bind mounts permanent
+var FstabPath = "/etc/fstab"
+
+// SFTP site access levels
+const (
+	SFTPAccessWrite    = "write"    // Joins the site's group, which gets write access
+	SFTPAccessReadOnly = "readonly" // Read-only bind mount
+)
+
+// sftpMatchMarker starts the Match block ravact adds to sshd_config
+const sftpMatchMarker = "# Added by ravact: chrooted SFTP-only users"
+
+// SFTPUserOptions describes an SFTP-only user
+type SFTPUserOptions struct {
+	Username  string
+	SiteDir   string // Bind-mounted into the user's chroot
+	Access    string // SFTPAccessWrite or SFTPAccessReadOnly
+	PublicKey string // Optional; a password or a key is required
+	Password  string
+}
+
+// Chroot returns the user's chroot directory
+func (o SFTPUserOptions) Chroot() string {
+	return path.Join(SFTPChrootRoot, o.Username)
+}
+
+// MountPoint returns where the site appears inside the chroot
+func (o SFTPUserOptions) MountPoint() string {
+	return path.Join(o.Chroot(), path.Base(filepath.Clean(o.SiteDir)))
+}
+
+// Validate checks the options before anything is created
+func (o SFTPUserOptions) Validate() error {
+	if !usernamePattern.MatchString(o.Username) || len(o.Username) > 32 {
+		return fmt.Errorf("invalid username %q", o.Username)
+	}
+	if !path.IsAbs(o.SiteDir) || filepath.Clean(o.SiteDir) == "/" {
+		return fmt.Errorf("site directory must be an absolute path below /")
+	}
+	if o.Access != SFTPAccessWrite && o.Access != SFTPAccessReadOnly {
+		return fmt.Errorf("unknown access level %q", o.Access)
+	}
+	if o.PublicKey == "" && o.Password == "" {
+		return fmt.Errorf("set a password or a public key so the user can log in")
+	}
+	if o.PublicKey != "" {
+		if _, ok := ParseAuthorizedKey(o.PublicKey); !ok {
+			return fmt.Errorf("the public key is not valid")
+		}
+	}
+	return nil
+}
+
+// SFTPMatchBlock is the sshd_config block restricting SFTPGroup members to
+// SFTP inside their chroot
+func SFTPMatchBlock() string {
+	return sftpMatchMarker + "\n" +
+		"Match Group " + SFTPGroup + "\n" +
+		"    ChrootDirectory " + SFTPChrootRoot + "/%u\n" +
+		"    ForceCommand internal-sftp\n" +
+		"    AuthorizedKeysFile " + SFTPKeysDir + "/%u\n" +
+		"    AllowTcpForwarding no\n" +
+		"    AllowAgentForwarding no\n" +
+		"    X11Forwarding no\n" +
+		"    PermitTunnel no\n"
+}
+
+// EnsureSFTPMatchBlock appends the SFTP Match block to a config that lacks
+// one. Match blocks run to the end of the file, so it goes last.
+func EnsureSFTPMatchBlock(content string) (string, bool) {
+	for _, line := range strings.Split(content, "\n") {
+		keyword, value, ok := sshdDirective(line)
+		if ok && strings.EqualFold(keyword, "Match") && strings.EqualFold(value, "Group "+SFTPGroup) {
+			return content, false
+		}
+	}
+	return strings.TrimRight(content, "\n") + "\n\n" + SFTPMatchBlock(), true
+}
+
+// fstabEscaper writes the characters fstab separates fields with as the
+// octal escapes mount understands
+var fstabEscaper = strings.NewReplacer(`\`, `\134`, " ", `\040`, "\t", `\011`, "\n", `\012`)
+
+// sftpFstabLine is the fstab entry bind-mounting a site into a chroot
+func sftpFstabLine(o SFTPUserOptions) string {
+	options := "bind"
+	if o.Access == SFTPAccessReadOnly {
+		options = "bind,ro"
+	}
+	return fmt.Sprintf("%s %s none %s 0 0", fstabEscaper.Replace(filepath.Clean(o.SiteDir)), fstabEscaper.Replace(o.MountPoint()), options)
+}
+
+// hasFstabMount reports whether fstab already mounts something at target
+func hasFstabMount(fstab, target string) bool {
+	target = fstabEscaper.Replace(target)
+	for _, line := range strings.Split(fstab, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && !strings.HasPrefix(fields[0], "#") && fields[1] == target {
+			return true
+		}
+	}
+	return false
+}
+
+// sftpWriteGroup returns the group of a site directory that write access
+// joins the user to. Root and other system groups are refused, since they
+// reach far more than the site.
+func sftpWriteGroup(siteDir string) (string, error) {
+	output, err := Command("stat", "-c", "%G %g", siteDir).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the group of %s: %v", siteDir, err)
+	}
+	return checkSFTPWriteGroup(siteDir, strings.TrimSpace(string(output)))
+}
+
+// checkSFTPWriteGroup checks stat's "name gid" output for a site directory
+func checkSFTPWriteGroup(siteDir, stat string) (string, error) {
+	name, gidText, _ := strings.Cut(stat, " ")
+	gid, err := strconv.Atoi(gidText)
+	if err != nil || name == "" {
+		return "", fmt.Errorf("unexpected group of %s: %q", siteDir, stat)
+	}
+	if gid < 1000 {
+		return "", fmt.Errorf("%s belongs to the system group %s; give the site its own group before granting write access", siteDir, name)
+	}
+	return name, nil
+}
+
+// CreateSFTPUser creates a user restricted to SFTP inside a chroot holding
+// a bind mount of one site directory. sshd gets the Match block for
+// SFTPGroup the first time, checked with sshd -t and then reloaded.
+func (um *UserManager) CreateSFTPUser(o SFTPUserOptions) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	if info, err := Stat(o.SiteDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", o.SiteDir)
+	}
+	if _, err := um.GetUser(o.Username); err == nil {
+		return fmt.Errorf("user %s already exists", o.Username)
+	}
+	group := ""
+	if o.Access == SFTPAccessWrite {
+		var err error
+		if group, err = sftpWriteGroup(o.SiteDir); err != nil {
+			return err
+		}
+	}
+
+	run := func(name string, args ...string) error {
+		if output, err := Command(name, args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("%s failed: %v - %s", name, err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+
+	// The chroot and every directory above it must be owned by root and
+	// not writable by anyone else, or sshd refuses the login
+	if err := MkdirAll(o.MountPoint(), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", o.MountPoint(), err)
+	}
+	if err := run("chown", "root:root", SFTPChrootRoot, o.Chroot()); err != nil {
+		return err
+	}
+	if err := run("chmod", "755", SFTPChrootRoot, o.Chroot()); err != nil {
+		return err
+	}
+
+	if err := run("groupadd", "-f", SFTPGroup); err != nil {
+		return err
+	}
+	home := "/" + path.Base(o.MountPoint()) // Relative to the chroot
+	if err := run("useradd", "-M", "-d", home, "-s", "/usr/sbin/nologin", "-G", SFTPGroup, o.Username); err != nil {
+		return err
+	}
+	if o.Password != "" {
+		cmd := Command("chpasswd")
+		cmd.Stdin = strings.NewReader(o.Username + ":" + o.Password + "\n")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("user created but chpasswd failed: %v - %s", err, strings.TrimSpace(string(output)))
+		}
+	}
+	if o.PublicKey != "" {
+		if err := MkdirAll(SFTPKeysDir, 0755); err != nil {
+			return fmt.Errorf("user created but failed to create %s: %w", SFTPKeysDir, err)
+		}
+		key, _ := ParseAuthorizedKey(o.PublicKey)
+		if err := WriteFile(path.Join(SFTPKeysDir, o.Username), []byte(key.Line()+"\n"), 0644); err != nil {
+			return fmt.Errorf("user created but failed to save the public key: %w", err)
+		}
+	}
+
+	if o.Access == SFTPAccessWrite {
+		// Let the site's group write, with new files inheriting the group
+		if err := run("usermod", "-aG", group, o.Username); err != nil {
+			return fmt.Errorf("user created but %v", err)
+		}
+		if err := run("chmod", "-R", "g+rwX", o.SiteDir); err != nil {
+			return fmt.Errorf("user created but %v", err)
+		}
+		if err := run("find", o.SiteDir, "-type", "d", "-exec", "chmod", "g+s", "{}", "+"); err != nil {
+			return fmt.Errorf("user created but %v", err)
+		}
+	}
+
+	fstab, err := ReadFile(FstabPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("user created but failed to read %s: %w", FstabPath, err)
+	}
+	if !hasFstabMount(string(fstab), o.MountPoint()) {
+		entry := "# ravact: SFTP access for " + o.Username + "\n" + sftpFstabLine(o) + "\n"
+		if err := AppendFile(FstabPath, []byte(entry), 0644); err != nil {
+			return fmt.Errorf("user created but failed to update %s: %w", FstabPath, err)
+		}
+	}
+	if err := run("mount", o.MountPoint()); err != nil {
+		return fmt.Errorf("user created but %v", err)
+	}
+
+	content, err := ReadFile(SSHDConfigPath)
+	if err != nil {
+		return fmt.Errorf("user created but failed to read %s: %w", SSHDConfigPath, err)
+	}
+	if updated, changed := EnsureSFTPMatchBlock(string(content)); changed {
+		if _, err := SaveSSHDConfig(updated); err != nil {
+			return fmt.Errorf("user created but %v", err)
+		}
+		if err := run("systemctl", "reload", SSHDServiceName()); err != nil {
+			return fmt.Errorf("user created but %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnsureSFTPMatchBlock(t *testing.T) {
+	config := "Port 22\nPasswordAuthentication no\n\nMatch User backup\n    ForceCommand /usr/local/bin/backup\n"
+	updated, changed := EnsureSFTPMatchBlock(config)
+	if !changed {
+		t.Fatal("expected the Match block to be added")
+	}
+	if !strings.HasPrefix(updated, config) || !strings.HasSuffix(updated, SFTPMatchBlock()) {
+		t.Errorf("expected the block appended after the existing config, got:\n%s", updated)
+	}
+	if again, changed := EnsureSFTPMatchBlock(updated); changed || again != updated {
+		t.Error("expected an existing Match Group block to be left alone")
+	}
+}
+
+func TestSFTPFstabLine(t *testing.T) {
+	opts := SFTPUserOptions{Username: "agency", SiteDir: "/var/www/shop/", Access: SFTPAccessReadOnly}
+	if got, want := sftpFstabLine(opts), "/var/www/shop /srv/sftp/agency/shop none bind,ro 0 0"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	spaced := SFTPUserOptions{Username: "agency", SiteDir: "/var/www/my shop", Access: SFTPAccessWrite}
+	if got, want := sftpFstabLine(spaced), `/var/www/my\040shop /srv/sftp/agency/my\040shop none bind 0 0`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !hasFstabMount(sftpFstabLine(spaced)+"\n", spaced.MountPoint()) {
+		t.Error("expected the escaped bind mount to be found")
+	}
+
+	fstab := "UUID=abc / ext4 defaults 0 1\n# /var/www/shop /srv/sftp/old/shop none bind 0 0\n"
+	if hasFstabMount(fstab, "/srv/sftp/old/shop") {
+		t.Error("commented entries should not count")
+	}
+	if !hasFstabMount(fstab+sftpFstabLine(opts)+"\n", opts.MountPoint()) {
+		t.Error("expected the bind mount to be found")
+	}
+}
+
+func TestCheckSFTPWriteGroup(t *testing.T) {
+	if group, err := checkSFTPWriteGroup("/var/www/shop", "shop 1001"); err != nil || group != "shop" {
+		t.Errorf("got %q, %v", group, err)
+	}
+	for _, stat := range []string{"root 0", "www-data 33", "adm 4", "", "shop"} {
+		if _, err := checkSFTPWriteGroup("/var/www/shop", stat); err == nil {
+			t.Errorf("expected %q to be refused", stat)
+		}
+	}
+}
+
+func TestSFTPUserOptionsValidate(t *testing.T) {
+	base := SFTPUserOptions{Username: "agency", SiteDir: "/var/www/shop", Access: SFTPAccessWrite, Password: "secret"}
+	if err := base.Validate(); err != nil {
+		t.Fatalf("expected valid options, got %v", err)
+	}
+
+	cases := map[string]func(o *SFTPUserOptions){
+		"bad username": func(o *SFTPUserOptions) { o.Username = "Agency!" },
+		"relative dir": func(o *SFTPUserOptions) { o.SiteDir = "www/shop" },
+		"root dir":     func(o *SFTPUserOptions) { o.SiteDir = "/" },
+		"bad access":   func(o *SFTPUserOptions) { o.Access = "admin" },
+		"no login":     func(o *SFTPUserOptions) { o.Password = "" },
+		"bad key":      func(o *SFTPUserOptions) { o.PublicKey = "ssh-ed25519 not*base64" },
+	}
+	for name, change := range cases {
+		o := base
+		change(&o)
+		if err := o.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
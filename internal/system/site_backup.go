@@ -0,0 +1,338 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BackupConfigPath holds the backup root, retention, and exclusions
+var BackupConfigPath = "/etc/ravact/backup.yaml"
+
+// Backup defaults
+const (
+	DefaultBackupRoot = "/var/backups/ravact"
+	DefaultBackupKeep = 7
+)
+
+// DefaultBackupExcludes are left out of site backups; they are rebuilt by
+// npm and composer
+var DefaultBackupExcludes = []string{"node_modules", "vendor"}
+
+// backupTimeFormat is the timestamp in archive names; it sorts by time
+const backupTimeFormat = "20060102-150405"
+
+// BackupConfig controls where site backups go and how many are kept
+type BackupConfig struct {
+	Root     string   `yaml:"root"`
+	Keep     int      `yaml:"keep"`     // Archives kept per site; 0 keeps all
+	Excludes []string `yaml:"excludes"` // Directories relative to the project
+	// DatabaseDumps also dumps the database named in the site's .env
+	DatabaseDumps bool `yaml:"database_dumps"`
+	// Targets receive a copy of each archive and dump
+	Targets []BackupTarget `yaml:"targets,omitempty"`
+}
+
+// SiteBackup is one archive of a site
+type SiteBackup struct {
+	Path    string
+	Created time.Time
+	Size    int64
+	Dump    string // Database dump made with the archive, if any
+}
+
+// Name is the archive's file name
+func (b SiteBackup) Name() string {
+	return filepath.Base(b.Path)
+}
+
+// Validate checks the root and exclusions
+func (c BackupConfig) Validate() error {
+	if !filepath.IsAbs(c.Root) || filepath.Clean(c.Root) == "/" {
+		return fmt.Errorf("backup root must be an absolute path other than /")
+	}
+	if c.Keep < 0 {
+		return fmt.Errorf("retention cannot be negative")
+	}
+	for _, e := range c.Excludes {
+		if e == "" || filepath.IsAbs(e) || strings.Contains(e, "..") {
+			return fmt.Errorf("exclude %q must be a path inside the project", e)
+		}
+	}
+	names := map[string]bool{}
+	for _, t := range c.Targets {
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("target %s: %w", t.Name, err)
+		}
+		if names[t.Name] {
+			return fmt.Errorf("target %s is defined twice", t.Name)
+		}
+		names[t.Name] = true
+	}
+	return nil
+}
+
+// LoadBackupConfig reads the backup settings, returning defaults if none
+// are saved
+func LoadBackupConfig() (BackupConfig, error) {
+	c := BackupConfig{Root: DefaultBackupRoot, Keep: DefaultBackupKeep, Excludes: DefaultBackupExcludes, DatabaseDumps: true}
+	data, err := ReadFile(BackupConfigPath)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to read backup config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("failed to parse %s: %w", BackupConfigPath, err)
+	}
+	for i := range c.Targets {
+		c.Targets[i].loadCredentials()
+	}
+	return c, nil
+}
+
+// Save writes the backup settings and the targets' credentials
+func (c BackupConfig) Save() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode backup config: %w", err)
+	}
+	if err := MkdirAll(filepath.Dir(BackupConfigPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(BackupConfigPath), err)
+	}
+	if err := WriteFile(BackupConfigPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", BackupConfigPath, err)
+	}
+	for _, t := range c.Targets {
+		if err := t.saveCredentials(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SiteBackupDir returns the directory holding a site's archives
+func (c BackupConfig) SiteBackupDir(siteKey string) (string, error) {
+	if _, err := SiteDir(siteKey); err != nil {
+		return "", err
+	}
+	return filepath.Join(c.Root, siteKey), nil
+}
+
+// ListSiteBackups returns a site's archives, newest first
+func (c BackupConfig) ListSiteBackups(siteKey string) ([]SiteBackup, error) {
+	dir, err := c.SiteBackupDir(siteKey)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	files := map[string]bool{}
+	for _, e := range entries {
+		files[e.Name()] = true
+	}
+	var backups []SiteBackup
+	for _, e := range entries {
+		created, ok := parseBackupName(siteKey, e.Name())
+		if !ok || e.IsDir() {
+			continue
+		}
+		b := SiteBackup{Path: filepath.Join(dir, e.Name()), Created: created}
+		if info, err := e.Info(); err == nil {
+			b.Size = info.Size()
+		}
+		if dump := dumpPath(b.Path); files[filepath.Base(dump)] {
+			b.Dump = dump
+		}
+		backups = append(backups, b)
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Created.After(backups[j].Created) })
+	return backups, nil
+}
+
+// parseBackupName reads the timestamp from an archive name made by
+// SiteBackupScript, ignoring anything else in the directory
+func parseBackupName(siteKey, name string) (time.Time, bool) {
+	stamp, ok := strings.CutPrefix(name, siteKey+"-")
+	if !ok {
+		return time.Time{}, false
+	}
+	stamp, ok = strings.CutSuffix(stamp, ".tar.gz")
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+	return t, err == nil
+}
+
+// SiteDatabase returns the engine (mysql or pgsql) and name of the database
+// in a project's .env, or empty strings if it uses none that can be dumped
+func SiteDatabase(projectDir string) (engine, name string) {
+	data, err := ReadFile(filepath.Join(projectDir, ".env"))
+	if err != nil {
+		return "", ""
+	}
+	env := ParseEnv(string(data))
+	connection, _ := env.Get("DB_CONNECTION")
+	name, _ = env.Get("DB_DATABASE")
+	if !databaseNamePattern.MatchString(name) {
+		return "", ""
+	}
+	switch connection {
+	case "mysql", "mariadb":
+		return "mysql", name
+	case "pgsql":
+		return "pgsql", name
+	}
+	return "", ""
+}
+
+// databaseDumpCommand dumps a database to stdout as root
+func databaseDumpCommand(engine, name string) string {
+	if engine == "pgsql" {
+		return "sudo -u postgres pg_dump " + ShellQuote(name)
+	}
+	return "mysqldump --single-transaction --routines --triggers -u root " + ShellQuote(name)
+}
+
+// exportDatabase dumps a database to a file on the active host, so remote
+// exports land on the server next to the directory created for them
+func exportDatabase(engine, name, outputPath string) error {
+	if err := MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	partial := ShellQuote(outputPath + ".partial")
+	script := fmt.Sprintf("umask 077\n%s > %s && mv -- %s %s", databaseDumpCommand(engine, name), partial, partial, ShellQuote(outputPath))
+	if output, err := Command("bash", "-c", script).CombinedOutput(); err != nil {
+		Command("rm", "-f", "--", outputPath+".partial").Run()
+		return fmt.Errorf("failed to export database: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// dumpPath returns the database dump made alongside an archive
+func dumpPath(archive string) string {
+	return strings.TrimSuffix(archive, ".tar.gz") + ".sql.gz"
+}
+
+// PruneBackups returns the archives that fall outside the retention once a
+// new one is added; existing must be newest first
+func (c BackupConfig) PruneBackups(existing []SiteBackup) []SiteBackup {
+	if c.Keep <= 0 || len(existing) < c.Keep {
+		return nil
+	}
+	return existing[c.Keep-1:]
+}
+
+// backupPaths returns the paths a site's archive holds, relative to /
+func backupPaths(site NginxSite) []string {
+	var paths []string
+	add := func(path string) {
+		if path != "" {
+			paths = append(paths, strings.TrimPrefix(filepath.Clean(path), "/"))
+		}
+	}
+	add(SiteProjectDir(site.RootDir))
+	add(site.ConfigPath)
+	if site.IsEnabled {
+		add(filepath.Join(NewNginxManager().sitesEnabled, site.Name))
+	}
+	if dir, err := SiteDir(site.Name); err == nil {
+		add(dir)
+	}
+	return paths
+}
+
+// SiteBackupScript returns a script that archives the site's project
+// directory (with its .env), nginx config, and ravact data into a
+// timestamped archive, then prunes archives beyond the retention. It also
+// returns the archive's path.
+func (c BackupConfig) SiteBackupScript(site NginxSite, existing []SiteBackup, now time.Time) (string, string, error) {
+	if err := c.Validate(); err != nil {
+		return "", "", err
+	}
+	dir, err := c.SiteBackupDir(site.Name)
+	if err != nil {
+		return "", "", err
+	}
+	project := SiteProjectDir(site.RootDir)
+	if project == "" || project == "/" {
+		return "", "", fmt.Errorf("%s has no project directory to back up", site.Name)
+	}
+	archive := filepath.Join(dir, site.Name+"-"+now.Format(backupTimeFormat)+".tar.gz")
+
+	args := []string{"tar", "-czpf", ShellQuote(archive + ".partial"), "--ignore-failed-read"}
+	for _, e := range c.Excludes {
+		args = append(args, "--exclude="+ShellQuote(strings.TrimPrefix(filepath.Join(project, e), "/")))
+	}
+	args = append(args, "-C", "/")
+	for _, p := range backupPaths(site) {
+		args = append(args, ShellQuote(p))
+	}
+
+	var b strings.Builder
+	b.WriteString("set -e\nset -o pipefail\n")
+	fmt.Fprintf(&b, "install -d -m 700 %s\n", ShellQuote(dir))
+	fmt.Fprintf(&b, "echo %s\n", ShellQuote("Archiving "+project+"..."))
+	// Write to a temporary name so an interrupted backup is never listed.
+	// tar exits 1 when files change while it reads them, which a live site
+	// does; only worse errors fail the backup.
+	b.WriteString(strings.Join(args, " ") + " || [ $? -eq 1 ]\n")
+	fmt.Fprintf(&b, "chmod 600 %s\n", ShellQuote(archive+".partial"))
+	fmt.Fprintf(&b, "mv %s %s\n", ShellQuote(archive+".partial"), ShellQuote(archive))
+	fmt.Fprintf(&b, "echo %s \"($(du -h %s | cut -f1))\"\n", ShellQuote("Created "+archive), ShellQuote(archive))
+	uploads := []string{archive}
+
+	if engine, name := SiteDatabase(project); c.DatabaseDumps && engine != "" {
+		dump := dumpPath(archive)
+		fmt.Fprintf(&b, "echo %s\n", ShellQuote("Dumping database "+name+"..."))
+		fmt.Fprintf(&b, "%s | gzip > %s\n", databaseDumpCommand(engine, name), ShellQuote(dump+".partial"))
+		fmt.Fprintf(&b, "chmod 600 %s\n", ShellQuote(dump+".partial"))
+		fmt.Fprintf(&b, "mv %s %s\n", ShellQuote(dump+".partial"), ShellQuote(dump))
+		fmt.Fprintf(&b, "echo %s \"($(du -h %s | cut -f1))\"\n", ShellQuote("Created "+dump), ShellQuote(dump))
+		uploads = append(uploads, dump)
+	}
+
+	for _, old := range c.PruneBackups(existing) {
+		fmt.Fprintf(&b, "rm -f %s %s\necho %s\n", ShellQuote(old.Path), ShellQuote(dumpPath(old.Path)), ShellQuote("Pruned "+old.Name()))
+	}
+
+	// Uploads run last so a failing target still leaves the local backup
+	// in place; off-site copies are not pruned
+	for _, t := range c.Targets {
+		b.WriteString(t.UploadScript(site.Name, uploads))
+	}
+	return b.String(), archive, nil
+}
+
+// SiteRestoreScript returns a script that extracts an archive back over the
+// site and reloads nginx. Excluded directories such as vendor are left as
+// they are, and files added since the backup are not removed.
+func SiteRestoreScript(site NginxSite, backup SiteBackup) string {
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	fmt.Fprintf(&b, "echo %s\n", ShellQuote("Checking "+backup.Name()+"..."))
+	fmt.Fprintf(&b, "tar -tzf %s > /dev/null\n", ShellQuote(backup.Path))
+	fmt.Fprintf(&b, "echo %s\n", ShellQuote("Restoring "+site.Name+"..."))
+	fmt.Fprintf(&b, "tar -xzpf %s -C /\n", ShellQuote(backup.Path))
+	b.WriteString("if command -v nginx > /dev/null; then\n")
+	b.WriteString("  nginx -t\n")
+	b.WriteString("  systemctl reload nginx\n")
+	b.WriteString("fi\n")
+	fmt.Fprintf(&b, "echo %s\n", ShellQuote("Restored "+site.Name+" from "+backup.Created.Format("2006-01-02 15:04:05")))
+	return b.String()
+}
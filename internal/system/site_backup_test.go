@@ -0,0 +1,152 @@
+package system
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackupConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  BackupConfig
+		ok   bool
+	}{
+		{"defaults", BackupConfig{Root: DefaultBackupRoot, Keep: 7, Excludes: DefaultBackupExcludes}, true},
+		{"relative root", BackupConfig{Root: "backups"}, false},
+		{"root is /", BackupConfig{Root: "/"}, false},
+		{"negative keep", BackupConfig{Root: "/srv/backups", Keep: -1}, false},
+		{"exclude outside project", BackupConfig{Root: "/srv/backups", Excludes: []string{"../etc"}}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate() = %v", tt.name, err)
+		}
+	}
+}
+
+func TestListAndPruneSiteBackups(t *testing.T) {
+	cfg := BackupConfig{Root: t.TempDir(), Keep: 2}
+	dir := filepath.Join(cfg.Root, "shop")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{
+		"shop-20260101-020000.tar.gz",
+		"shop-20260103-020000.tar.gz",
+		"shop-20260102-020000.tar.gz",
+		"shop-20260104-020000.tar.gz.partial",
+		"other-20260101-020000.tar.gz",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, err := cfg.ListSiteBackups("shop")
+	if err != nil {
+		t.Fatalf("ListSiteBackups() error = %v", err)
+	}
+	var names []string
+	for _, b := range backups {
+		names = append(names, b.Name())
+	}
+	want := "shop-20260103-020000.tar.gz shop-20260102-020000.tar.gz shop-20260101-020000.tar.gz"
+	if strings.Join(names, " ") != want {
+		t.Errorf("ListSiteBackups() = %v, want %s", names, want)
+	}
+
+	// Keeping 2 including the new archive leaves room for one existing
+	pruned := cfg.PruneBackups(backups)
+	if len(pruned) != 2 || pruned[0].Name() != "shop-20260102-020000.tar.gz" {
+		t.Errorf("PruneBackups() = %v", pruned)
+	}
+	if got := (BackupConfig{Root: cfg.Root}).PruneBackups(backups); got != nil {
+		t.Errorf("PruneBackups() with Keep 0 = %v, want none", got)
+	}
+
+	if _, err := cfg.ListSiteBackups("../etc"); err == nil {
+		t.Error("ListSiteBackups() accepted an invalid site key")
+	}
+}
+
+func TestSiteBackupScript(t *testing.T) {
+	if _, err := exec.LookPath("tar"); err != nil {
+		t.Skip("tar not available")
+	}
+	tmp := t.TempDir()
+	SiteDataDir = filepath.Join(tmp, "sites")
+	defer func() { SiteDataDir = "/etc/ravact/sites" }()
+
+	project := filepath.Join(tmp, "www", "shop")
+	for path, content := range map[string]string{
+		"public/index.php":         "<?php",
+		".env":                     "APP_KEY=secret",
+		"vendor/autoload.php":      "<?php",
+		"node_modules/x/index.js":  "",
+		"public/vendor/app.js":     "",
+		"../../nginx/shop.conf":    "server {}",
+		"storage/logs/laravel.log": "",
+	} {
+		full := filepath.Join(project, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	site := NginxSite{Name: "shop", RootDir: filepath.Join(project, "public"), ConfigPath: filepath.Join(tmp, "nginx", "shop.conf")}
+	cfg := BackupConfig{Root: filepath.Join(tmp, "backups"), Keep: 2, Excludes: DefaultBackupExcludes}
+	old := []SiteBackup{
+		{Path: filepath.Join(cfg.Root, "shop", "shop-20260102-020000.tar.gz")},
+		{Path: filepath.Join(cfg.Root, "shop", "shop-20260101-020000.tar.gz")},
+	}
+
+	script, archive, err := cfg.SiteBackupScript(site, old, time.Date(2026, 1, 3, 2, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("SiteBackupScript() error = %v", err)
+	}
+	if filepath.Base(archive) != "shop-20260103-020000.tar.gz" {
+		t.Errorf("archive = %s", archive)
+	}
+	if !strings.Contains(script, "rm -f "+ShellQuote(old[1].Path)) || strings.Contains(script, "rm -f "+ShellQuote(old[0].Path)) {
+		t.Errorf("script prunes the wrong archives:\n%s", script)
+	}
+
+	if output, err := exec.Command("bash", "-c", script).CombinedOutput(); err != nil {
+		t.Fatalf("backup script failed: %v\n%s", err, output)
+	}
+	listing, err := exec.Command("tar", "-tzf", archive).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rel := strings.TrimPrefix(project, "/") + "/"
+	for _, want := range []string{rel + ".env", rel + "public/index.php", rel + "public/vendor/app.js", strings.TrimPrefix(site.ConfigPath, "/")} {
+		if !strings.Contains(string(listing), want+"\n") {
+			t.Errorf("archive is missing %s:\n%s", want, listing)
+		}
+	}
+	for _, excluded := range []string{rel + "vendor/", rel + "node_modules/"} {
+		if strings.Contains(string(listing), excluded) {
+			t.Errorf("archive includes %s", excluded)
+		}
+	}
+}
+
+func TestSiteRestoreScript(t *testing.T) {
+	backup := SiteBackup{Path: "/var/backups/ravact/shop/shop-20260103-020000.tar.gz", Created: time.Date(2026, 1, 3, 2, 0, 0, 0, time.Local)}
+	script := SiteRestoreScript(NginxSite{Name: "shop"}, backup)
+	for _, want := range []string{"tar -tzf " + ShellQuote(backup.Path), "tar -xzpf " + ShellQuote(backup.Path) + " -C /", "nginx -t"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("restore script is missing %q:\n%s", want, script)
+		}
+	}
+	// The archive is verified before anything is extracted
+	if strings.Index(script, "tar -tzf") > strings.Index(script, "tar -xzpf") {
+		t.Error("restore script extracts before checking the archive")
+	}
+}
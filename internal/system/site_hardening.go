@@ -0,0 +1,315 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HardeningCheck is one item of a site's production checklist
+type HardeningCheck struct {
+	ID      string
+	Title   string
+	Passed  bool
+	Skipped bool // Not applicable to this site
+	Manual  bool // Failing, but the hardening action cannot fix it
+	Detail  string
+}
+
+// Opcache settings written by the hardening action. Timestamps are still
+// validated (every 60s) so deploys that do not reload PHP-FPM go live.
+var hardeningOpcache = []struct{ key, value string }{
+	{"opcache.enable", "1"},
+	{"opcache.memory_consumption", "256"},
+	{"opcache.interned_strings_buffer", "16"},
+	{"opcache.max_accelerated_files", "20000"},
+	{"opcache.validate_timestamps", "1"},
+	{"opcache.revalidate_freq", "60"},
+	{"opcache.save_comments", "1"},
+}
+
+// hardeningHeaders are the security headers added to every server block
+var hardeningHeaders = []struct{ name, value string }{
+	{"X-Frame-Options", "SAMEORIGIN"},
+	{"X-Content-Type-Options", "nosniff"},
+	{"Referrer-Policy", "strict-origin-when-cross-origin"},
+	{"Permissions-Policy", "camera=(), microphone=(), geolocation=()"},
+}
+
+// hardeningRate is the per-IP request rate allowed by the site's limit
+const hardeningRate = "10r/s"
+
+// SiteHardening is what the production hardening action knows about a site
+type SiteHardening struct {
+	Site       NginxSite
+	Config     string
+	ProjectDir string
+	PHPVersion string // PHP-FPM version from the fastcgi_pass socket
+	Laravel    bool
+	WebUser    string
+	SystemUser string // Runs artisan; empty runs it as the current user
+
+	opcache map[string]string // PHP-FPM's effective opcache settings
+	caches  LaravelCaches
+	envMode int
+	storage int
+}
+
+// InspectSiteHardening collects the state the checklist is built from
+func InspectSiteHardening(site NginxSite, webUser, systemUser string) SiteHardening {
+	h := SiteHardening{
+		Site:       site,
+		ProjectDir: SiteProjectDir(site.RootDir),
+		WebUser:    webUser,
+		SystemUser: systemUser,
+		envMode:    -1,
+		storage:    -1,
+	}
+	if data, err := ReadFile(site.ConfigPath); err == nil {
+		h.Config = string(data)
+	}
+	if m := phpFPMSocket.FindStringSubmatch(h.Config); m != nil {
+		h.PHPVersion = m[1]
+		if output, err := Command("php-fpm"+h.PHPVersion, "-i").Output(); err == nil {
+			h.opcache = parsePHPInfo(string(output), "opcache.")
+		}
+	}
+	if h.ProjectDir != "" {
+		if _, err := Stat(filepath.Join(h.ProjectDir, "artisan")); err == nil {
+			h.Laravel = true
+			h.caches = DetectLaravelCaches(h.ProjectDir)
+			if info, err := Stat(filepath.Join(h.ProjectDir, ".env")); err == nil {
+				h.envMode = int(info.Mode().Perm())
+			}
+			if info, err := Stat(filepath.Join(h.ProjectDir, "storage")); err == nil {
+				h.storage = int(info.Mode().Perm())
+			}
+		}
+	}
+	return h
+}
+
+// parsePHPInfo reads "key => local => master" lines from `php -i`
+func parsePHPInfo(output, prefix string) map[string]string {
+	values := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, " => ")
+		if len(parts) >= 2 && strings.HasPrefix(parts[0], prefix) {
+			values[parts[0]] = strings.TrimSpace(parts[1])
+		}
+	}
+	return values
+}
+
+// httpsRedirect matches a redirect of plain HTTP requests to HTTPS
+var httpsRedirect = regexp.MustCompile(`return\s+30[178]\s+https://|rewrite\s+\S+\s+https://\S+\s+permanent`)
+
+// Checks returns the site's production checklist
+func (h SiteHardening) Checks() []HardeningCheck {
+	var checks []HardeningCheck
+
+	opcache := HardeningCheck{ID: "opcache", Title: "OPcache enabled and sized"}
+	switch {
+	case h.PHPVersion == "":
+		opcache.Skipped, opcache.Detail = true, "Site does not use a versioned PHP-FPM socket"
+	case h.opcache == nil:
+		opcache.Detail = "Could not read settings from php-fpm" + h.PHPVersion + " -i"
+	default:
+		memory, _ := strconv.Atoi(h.opcache["opcache.memory_consumption"])
+		files, _ := strconv.Atoi(h.opcache["opcache.max_accelerated_files"])
+		opcache.Passed = isTruthy(h.opcache["opcache.enable"]) && memory >= 128 && files >= 10000
+		opcache.Detail = fmt.Sprintf("enable=%s memory=%dMB files=%d", h.opcache["opcache.enable"], memory, files)
+	}
+	checks = append(checks, opcache)
+
+	caches := HardeningCheck{ID: "laravel_caches", Title: "Laravel config, routes, and views cached"}
+	if !h.Laravel {
+		caches.Skipped, caches.Detail = true, "Not a Laravel project"
+	} else {
+		caches.Passed = h.caches.Config && h.caches.Routes && (h.caches.Views || !h.caches.ViewsKnown)
+		caches.Detail = fmt.Sprintf("config=%v routes=%v views=%v", h.caches.Config, h.caches.Routes, h.caches.Views)
+	}
+	checks = append(checks, caches)
+
+	perms := HardeningCheck{ID: "permissions", Title: "Permissions on .env and storage"}
+	if !h.Laravel {
+		perms.Skipped, perms.Detail = true, "Not a Laravel project"
+	} else {
+		perms.Passed = h.envMode >= 0 && h.envMode&0007 == 0 && h.storage >= 0 && h.storage&0070 == 0070 && h.storage&0007 != 0007
+		perms.Detail = fmt.Sprintf(".env %s, storage %s (want no world access to .env, group-writable storage)", formatMode(h.envMode), formatMode(h.storage))
+	}
+	checks = append(checks, perms)
+
+	headers := HardeningCheck{ID: "headers", Title: "Security headers"}
+	var missing []string
+	for _, header := range hardeningHeaders {
+		if !strings.Contains(h.Config, header.name) && !strings.Contains(h.Config, h.SnippetPath()) && !h.customHeaders() {
+			missing = append(missing, header.name)
+		}
+	}
+	headers.Passed = len(missing) == 0
+	if !headers.Passed {
+		headers.Detail = "Missing " + strings.Join(missing, ", ")
+	}
+	checks = append(checks, headers)
+
+	checks = append(checks, HardeningCheck{
+		ID:     "rate_limit",
+		Title:  "Request rate limit",
+		Passed: strings.Contains(h.Config, "limit_req ") || strings.Contains(h.Config, h.SnippetPath()),
+		Detail: hardeningRate + " per IP with a burst of 20",
+	})
+
+	redirect := HardeningCheck{ID: "https", Title: "HTTPS with HTTP redirect"}
+	switch {
+	case !h.Site.HasSSL && !strings.Contains(h.Config, "ssl_certificate"):
+		redirect.Manual, redirect.Detail = true, "No certificate: add one from SSL Options first"
+	case !httpsRedirect.MatchString(h.Config):
+		redirect.Manual, redirect.Detail = true, "HTTP is served without redirecting to HTTPS"
+	default:
+		redirect.Passed = true
+	}
+	checks = append(checks, redirect)
+	return checks
+}
+
+// formatMode renders permission bits, or "missing" for a missing file
+func formatMode(mode int) string {
+	if mode < 0 {
+		return "missing"
+	}
+	return fmt.Sprintf("%04o", mode)
+}
+
+// SnippetPath is the nginx include with the site's headers and limits
+func (h SiteHardening) SnippetPath() string {
+	return "/etc/nginx/snippets/ravact-hardening-" + h.Site.Name + ".conf"
+}
+
+// rateZone is the name of the site's limit_req zone
+func (h SiteHardening) rateZone() string {
+	return "ravact_" + strings.NewReplacer(".", "_", "-", "_").Replace(h.Site.Name)
+}
+
+// customHeaders reports whether the site uses the security headers editor,
+// whose include then sets the headers instead of the snippet
+func (h SiteHardening) customHeaders() bool {
+	return strings.Contains(h.Config, SecurityHeadersPath(h.Site.Name))
+}
+
+// Snippet returns the nginx server-context include
+func (h SiteHardening) Snippet() string {
+	var b strings.Builder
+	b.WriteString("# Production hardening managed by ravact\n")
+	b.WriteString("server_tokens off;\n")
+	if !h.customHeaders() {
+		for _, header := range hardeningHeaders {
+			fmt.Fprintf(&b, "add_header %s \"%s\" always;\n", header.name, header.value)
+		}
+		if strings.Contains(h.Config, "ssl_certificate") {
+			b.WriteString("add_header Strict-Transport-Security \"max-age=31536000\" always;\n")
+		}
+	}
+	fmt.Fprintf(&b, "limit_req zone=%s burst=20 nodelay;\n", h.rateZone())
+	// The site's rate limits may already set the status, which nginx
+	// refuses twice in the same block
+	if !strings.Contains(h.Config, "limit_req_status") {
+		b.WriteString("limit_req_status 429;\n")
+	}
+	return b.String()
+}
+
+// RateZoneConfig returns the http-context file that defines the zone
+func (h SiteHardening) RateZoneConfig() (path, content string) {
+	path = "/etc/nginx/conf.d/ravact-ratelimit-" + h.Site.Name + ".conf"
+	content = fmt.Sprintf("# Managed by ravact\nlimit_req_zone $binary_remote_addr zone=%s:10m rate=%s;\n", h.rateZone(), hardeningRate)
+	return path, content
+}
+
+// serverBlockStart matches the opening line of a server block
+var serverBlockStart = regexp.MustCompile(`^(\s*)server\s*\{\s*$`)
+
+// HardenedConfig returns the site config with the snippet included at the
+// top of every server block
+func (h SiteHardening) HardenedConfig() string {
+	if strings.Contains(h.Config, h.SnippetPath()) {
+		return h.Config
+	}
+	lines := strings.Split(h.Config, "\n")
+	var out []string
+	for _, line := range lines {
+		out = append(out, line)
+		if m := serverBlockStart.FindStringSubmatch(line); m != nil {
+			out = append(out, m[1]+"    include "+h.SnippetPath()+";")
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// opcacheIniPath returns where the opcache overrides are written: the
+// FPM conf.d of the versioned install, or /etc/php.d elsewhere
+func (h SiteHardening) opcacheIniPath() string {
+	dir := "/etc/php/" + h.PHPVersion + "/fpm/conf.d"
+	if _, err := Stat(dir); err != nil {
+		dir = "/etc/php.d"
+	}
+	return dir + "/99-ravact-opcache.ini"
+}
+
+// Script returns the bash script that applies every fix. Each part is
+// validated before the service is reloaded and rolled back on failure.
+func (h SiteHardening) Script() string {
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	step := func(title string) {
+		fmt.Fprintf(&b, "echo; echo %s\n", ShellQuote("==> "+title))
+	}
+
+	if h.PHPVersion != "" {
+		step("Enable OPcache for PHP " + h.PHPVersion)
+		path := ShellQuote(h.opcacheIniPath())
+		var ini strings.Builder
+		ini.WriteString("; Managed by ravact\n")
+		for _, s := range hardeningOpcache {
+			fmt.Fprintf(&ini, "%s=%s\n", s.key, s.value)
+		}
+		fmt.Fprintf(&b, "cat > %s <<'EOF'\n%sEOF\n", path, ini.String())
+		fmt.Fprintf(&b, "if ! php-fpm%s -t; then rm -f %s; echo 'PHP-FPM rejected the OPcache settings; removed them'; exit 1; fi\n", h.PHPVersion, path)
+		fmt.Fprintf(&b, "systemctl reload php%s-fpm\n", h.PHPVersion)
+	}
+
+	if h.Laravel {
+		project := ShellQuote(h.ProjectDir)
+		step("Set permissions on .env, storage, and bootstrap/cache")
+		web := ShellQuote(h.WebUser)
+		fmt.Fprintf(&b, "cd %s\n", project)
+		fmt.Fprintf(&b, "chgrp %s .env && chmod 640 .env\n", web)
+		fmt.Fprintf(&b, "chgrp -R %s storage bootstrap/cache && chmod -R ug+rwX,o-w storage bootstrap/cache\n", web)
+
+		step("Cache Laravel config, routes, and views")
+		artisan := "php artisan config:cache && php artisan route:cache && php artisan view:cache"
+		if h.SystemUser != "" {
+			fmt.Fprintf(&b, "sudo -u %s bash -c %s\n", ShellQuote(h.SystemUser), ShellQuote("cd "+h.ProjectDir+" && "+artisan))
+		} else {
+			b.WriteString(artisan + "\n")
+		}
+	}
+
+	step("Add security headers and rate limit to nginx")
+	zonePath, zone := h.RateZoneConfig()
+	config := ShellQuote(h.Site.ConfigPath)
+	snippet := ShellQuote(h.SnippetPath())
+	b.WriteString("mkdir -p /etc/nginx/snippets\n")
+	fmt.Fprintf(&b, "cp %s %s.bak\n", config, config)
+	fmt.Fprintf(&b, "cat > %s <<'EOF'\n%sEOF\n", ShellQuote(zonePath), zone)
+	fmt.Fprintf(&b, "cat > %s <<'EOF'\n%sEOF\n", snippet, h.Snippet())
+	fmt.Fprintf(&b, "cat > %s <<'EOF'\n%s\nEOF\n", config, strings.TrimRight(h.HardenedConfig(), "\n"))
+	fmt.Fprintf(&b, "if ! nginx -t; then mv %s.bak %s; rm -f %s %s; echo 'nginx rejected the changes; restored %s'; exit 1; fi\n",
+		config, config, snippet, ShellQuote(zonePath), h.Site.ConfigPath)
+	b.WriteString("systemctl reload nginx\n")
+
+	b.WriteString("echo; echo '==> Hardening applied'\n")
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+const testHardeningConfig = `server {
+    listen 80;
+    server_name example.com;
+    return 301 https://$host$request_uri;
+}
+
+server {
+    listen 443 ssl;
+    server_name example.com;
+    ssl_certificate /etc/letsencrypt/live/example.com/fullchain.pem;
+    root /var/www/example/public;
+
+    location ~ \.php$ {
+        fastcgi_pass unix:/run/php/php8.3-fpm.sock;
+    }
+}
+`
+
+func testSiteHardening() SiteHardening {
+	return SiteHardening{
+		Site:       NginxSite{Name: "example.com", ConfigPath: "/etc/nginx/sites-available/example.com", HasSSL: true},
+		Config:     testHardeningConfig,
+		ProjectDir: "/var/www/example",
+		PHPVersion: "8.3",
+		Laravel:    true,
+		WebUser:    "www-data",
+		opcache:    map[string]string{"opcache.enable": "On", "opcache.memory_consumption": "128", "opcache.max_accelerated_files": "10000"},
+		caches:     LaravelCaches{Config: true, Routes: false},
+		envMode:    0644,
+		storage:    0775,
+	}
+}
+
+func TestHardeningChecks(t *testing.T) {
+	got := map[string]HardeningCheck{}
+	for _, c := range testSiteHardening().Checks() {
+		got[c.ID] = c
+	}
+	want := map[string]bool{"opcache": true, "laravel_caches": false, "permissions": false, "headers": false, "rate_limit": false, "https": true}
+	for id, passed := range want {
+		if got[id].Passed != passed {
+			t.Errorf("%s: passed=%v, want %v (%s)", id, got[id].Passed, passed, got[id].Detail)
+		}
+	}
+
+	h := testSiteHardening()
+	h.Config = strings.Replace(h.Config, "return 301 https://$host$request_uri;", "root /var/www/example/public;", 1)
+	for _, c := range h.Checks() {
+		if c.ID == "https" && (c.Passed || !c.Manual) {
+			t.Errorf("expected a manual HTTPS redirect check, got %+v", c)
+		}
+	}
+}
+
+func TestHardenedConfig(t *testing.T) {
+	h := testSiteHardening()
+	out := h.HardenedConfig()
+	if n := strings.Count(out, "include /etc/nginx/snippets/ravact-hardening-example.com.conf;"); n != 2 {
+		t.Fatalf("expected the snippet in both server blocks, got %d:\n%s", n, out)
+	}
+	h.Config = out
+	if h.HardenedConfig() != out {
+		t.Error("hardening should be idempotent")
+	}
+	for _, c := range h.Checks() {
+		if (c.ID == "headers" || c.ID == "rate_limit") && !c.Passed {
+			t.Errorf("%s should pass once the snippet is included", c.ID)
+		}
+	}
+}
+
+func TestHardeningSnippet(t *testing.T) {
+	h := testSiteHardening()
+	snippet := h.Snippet()
+	for _, want := range []string{"server_tokens off;", `add_header X-Frame-Options "SAMEORIGIN" always;`, "Strict-Transport-Security", "limit_req zone=ravact_example_com burst=20 nodelay;"} {
+		if !strings.Contains(snippet, want) {
+			t.Errorf("snippet missing %q:\n%s", want, snippet)
+		}
+	}
+	if _, zone := h.RateZoneConfig(); !strings.Contains(zone, "zone=ravact_example_com:10m rate=10r/s;") {
+		t.Errorf("unexpected zone config: %s", zone)
+	}
+}
+
+func TestParsePHPInfo(t *testing.T) {
+	values := parsePHPInfo("opcache.enable => On => On\nopcache.memory_consumption => 256 => 128\nmemory_limit => 128M => 128M\n", "opcache.")
+	if len(values) != 2 || values["opcache.memory_consumption"] != "256" {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
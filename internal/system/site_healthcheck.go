@@ -0,0 +1,227 @@
+package system
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SiteHealthCheckFile is the health check config inside a site's directory
+const SiteHealthCheckFile = "healthcheck.yaml"
+
+// Health check defaults
+const (
+	DefaultHealthCheckStatus  = 200
+	DefaultHealthCheckTimeout = 10 // Seconds
+)
+
+// healthCheckBodyLimit caps how much of the response is searched for the
+// expected string
+const healthCheckBodyLimit = 1 << 20
+
+// SiteHealthCheck describes how to tell whether a site is up
+type SiteHealthCheck struct {
+	URL            string `yaml:"url"`
+	ExpectStatus   int    `yaml:"expect_status,omitempty"`   // Defaults to 200
+	ExpectContains string `yaml:"expect_contains,omitempty"` // Must appear in the body when set
+	TimeoutSeconds int    `yaml:"timeout_seconds,omitempty"` // Defaults to 10
+	Insecure       bool   `yaml:"insecure,omitempty"`        // Skip TLS verification, e.g. before certbot
+}
+
+// SiteHealthResult is the outcome of one health check
+type SiteHealthResult struct {
+	CheckedAt time.Time `json:"checked_at"`
+	OK        bool      `json:"ok"`
+	Status    int       `json:"status,omitempty"`
+	LatencyMS int64     `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Summary is a short description of the result for lists
+func (r SiteHealthResult) Summary() string {
+	if r.OK {
+		return fmt.Sprintf("%d in %dms", r.Status, r.LatencyMS)
+	}
+	return r.Error
+}
+
+// Validate checks the URL and limits
+func (c SiteHealthCheck) Validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("health check URL must be an http:// or https:// URL")
+	}
+	if c.ExpectStatus != 0 && (c.ExpectStatus < 100 || c.ExpectStatus > 599) {
+		return fmt.Errorf("expected status must be between 100 and 599")
+	}
+	if c.TimeoutSeconds < 0 || c.TimeoutSeconds > 300 {
+		return fmt.Errorf("timeout must be between 1 and 300 seconds")
+	}
+	return nil
+}
+
+// Run performs the check
+func (c SiteHealthCheck) Run() SiteHealthResult {
+	expect := c.ExpectStatus
+	if expect == 0 {
+		expect = DefaultHealthCheckStatus
+	}
+	timeout := c.TimeoutSeconds
+	if timeout == 0 {
+		timeout = DefaultHealthCheckTimeout
+	}
+	client := &http.Client{
+		Timeout: time.Duration(timeout) * time.Second,
+		// Report redirects as they are, so a check expecting 200 notices
+		// a site that now bounces to a login page
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	if c.Insecure {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+
+	result := SiteHealthResult{CheckedAt: time.Now()}
+	req, err := http.NewRequest(http.MethodGet, c.URL, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	req.Header.Set("User-Agent", "ravact-healthcheck")
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		result.LatencyMS = time.Since(start).Milliseconds()
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, healthCheckBodyLimit))
+	result.LatencyMS = time.Since(start).Milliseconds()
+	result.Status = resp.StatusCode
+
+	switch {
+	case err != nil:
+		result.Error = fmt.Sprintf("failed to read response: %v", err)
+	case resp.StatusCode != expect:
+		result.Error = fmt.Sprintf("got HTTP %d, expected %d", resp.StatusCode, expect)
+	case c.ExpectContains != "" && !strings.Contains(string(body), c.ExpectContains):
+		result.Error = fmt.Sprintf("response does not contain %q", c.ExpectContains)
+	default:
+		result.OK = true
+	}
+	return result
+}
+
+// SiteHealthCheckPath returns the path of a site's health check config
+func SiteHealthCheckPath(siteKey string) (string, error) {
+	dir, err := SiteDir(siteKey)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, SiteHealthCheckFile), nil
+}
+
+// DefaultSiteHealthCheck proposes a check of the site's home page
+func DefaultSiteHealthCheck(site NginxSite) SiteHealthCheck {
+	scheme := "http://"
+	if site.HasSSL {
+		scheme = "https://"
+	}
+	host := site.Domain
+	if host == "" || host == "_" {
+		host = "localhost"
+	}
+	return SiteHealthCheck{URL: scheme + host + "/", ExpectStatus: DefaultHealthCheckStatus, TimeoutSeconds: DefaultHealthCheckTimeout}
+}
+
+// LoadSiteHealthCheck returns a site's health check, or nil if none is set
+func LoadSiteHealthCheck(siteKey string) (*SiteHealthCheck, error) {
+	path, err := SiteHealthCheckPath(siteKey)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read health check: %w", err)
+	}
+	var check SiteHealthCheck
+	if err := yaml.Unmarshal(data, &check); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &check, nil
+}
+
+// SaveSiteHealthCheck writes a site's health check, creating its directory
+func SaveSiteHealthCheck(siteKey string, check SiteHealthCheck) error {
+	if err := check.Validate(); err != nil {
+		return err
+	}
+	path, err := SiteHealthCheckPath(siteKey)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(check)
+	if err != nil {
+		return fmt.Errorf("failed to encode health check: %w", err)
+	}
+	if err := MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := WriteFile(path, data, 0640); err != nil {
+		return fmt.Errorf("failed to write health check: %w", err)
+	}
+	return nil
+}
+
+// RecordSiteHealth stores the latest result for a site
+func RecordSiteHealth(siteKey string, result SiteHealthResult) error {
+	return UpdateStore(func(tx *StoreTx) error {
+		return tx.Put(BucketSiteHealth, siteKey, result)
+	})
+}
+
+// LoadSiteHealthResults returns the last result of every checked site
+func LoadSiteHealthResults() (map[string]SiteHealthResult, error) {
+	results := map[string]SiteHealthResult{}
+	err := ViewStore(func(tx *StoreTx) error {
+		for _, key := range tx.Keys(BucketSiteHealth) {
+			var result SiteHealthResult
+			if _, err := tx.Get(BucketSiteHealth, key, &result); err != nil {
+				return err
+			}
+			results[key] = result
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read health results: %w", err)
+	}
+	return results, nil
+}
+
+// CheckSiteHealth runs a site's configured check and records the result
+func CheckSiteHealth(siteKey string) (SiteHealthResult, error) {
+	check, err := LoadSiteHealthCheck(siteKey)
+	if err != nil {
+		return SiteHealthResult{}, err
+	}
+	if check == nil {
+		return SiteHealthResult{}, fmt.Errorf("no health check is configured for %s", siteKey)
+	}
+	result := check.Run()
+	if err := RecordSiteHealth(siteKey, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
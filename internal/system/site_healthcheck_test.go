@@ -0,0 +1,73 @@
+package system
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSiteHealthCheckRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/up":
+			w.Write([]byte("status: ok"))
+		case "/login":
+			http.Redirect(w, r, "/", http.StatusFound)
+		default:
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name  string
+		check SiteHealthCheck
+		ok    bool
+		err   string
+	}{
+		{"up", SiteHealthCheck{URL: srv.URL + "/up", ExpectContains: "ok"}, true, ""},
+		{"missing string", SiteHealthCheck{URL: srv.URL + "/up", ExpectContains: "healthy"}, false, "does not contain"},
+		{"server error", SiteHealthCheck{URL: srv.URL + "/"}, false, "got HTTP 500"},
+		{"redirect not followed", SiteHealthCheck{URL: srv.URL + "/login"}, false, "got HTTP 302"},
+		{"expected redirect", SiteHealthCheck{URL: srv.URL + "/login", ExpectStatus: 302}, true, ""},
+	}
+	for _, tt := range tests {
+		result := tt.check.Run()
+		if result.OK != tt.ok || !strings.Contains(result.Error, tt.err) {
+			t.Errorf("%s: unexpected result %+v", tt.name, result)
+		}
+	}
+}
+
+func TestSiteHealthCheckStorage(t *testing.T) {
+	originalData, originalState := SiteDataDir, StateDir
+	SiteDataDir = t.TempDir()
+	StateDir = filepath.Join(t.TempDir(), "state")
+	defer func() { SiteDataDir, StateDir = originalData, originalState }()
+
+	if check, err := LoadSiteHealthCheck("shop"); err != nil || check != nil {
+		t.Fatalf("expected no check, got %+v (%v)", check, err)
+	}
+	if _, err := CheckSiteHealth("shop"); err == nil {
+		t.Error("expected checking without a config to fail")
+	}
+	if err := SaveSiteHealthCheck("shop", SiteHealthCheck{URL: "ftp://shop"}); err == nil {
+		t.Error("expected a non-HTTP URL to be refused")
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	if err := SaveSiteHealthCheck("shop", SiteHealthCheck{URL: srv.URL, TimeoutSeconds: 5}); err != nil {
+		t.Fatalf("SaveSiteHealthCheck: %v", err)
+	}
+	result, err := CheckSiteHealth("shop")
+	if err != nil || !result.OK {
+		t.Fatalf("CheckSiteHealth: %+v (%v)", result, err)
+	}
+	results, err := LoadSiteHealthResults()
+	if err != nil || !results["shop"].OK || results["shop"].Status != 200 {
+		t.Errorf("expected recorded result, got %+v (%v)", results, err)
+	}
+}
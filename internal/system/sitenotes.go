@@ -0,0 +1,106 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SiteDataDir is where ravact keeps per-site files such as notes and runbooks
+var SiteDataDir = "/etc/ravact/sites"
+
+// SiteNotesFile is the name of the notes file inside a site's directory
+const SiteNotesFile = "notes.md"
+
+// SiteAttachment is a file kept alongside a site's notes (e.g. a runbook)
+type SiteAttachment struct {
+	Name string
+	Path string
+	Size int64
+}
+
+// SiteDir returns the ravact data directory for a site
+func SiteDir(siteKey string) (string, error) {
+	if siteKey == "" || siteKey == "." || siteKey == ".." || strings.ContainsAny(siteKey, "/\\") {
+		return "", fmt.Errorf("invalid site key: %q", siteKey)
+	}
+	return filepath.Join(SiteDataDir, siteKey), nil
+}
+
+// SiteNotesPath returns the path of a site's notes file
+func SiteNotesPath(siteKey string) (string, error) {
+	dir, err := SiteDir(siteKey)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, SiteNotesFile), nil
+}
+
+// LoadSiteNotes returns a site's notes, or an empty string if none have been written
+func LoadSiteNotes(siteKey string) (string, error) {
+	path, err := SiteNotesPath(siteKey)
+	if err != nil {
+		return "", err
+	}
+	data, err := ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read notes: %w", err)
+	}
+	return string(data), nil
+}
+
+// SaveSiteNotes writes a site's notes, creating its directory if needed
+func SaveSiteNotes(siteKey, content string) error {
+	path, err := SiteNotesPath(siteKey)
+	if err != nil {
+		return err
+	}
+	if err := MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if err := WriteFile(path, []byte(content), 0640); err != nil {
+		return fmt.Errorf("failed to write notes: %w", err)
+	}
+	return nil
+}
+
+// ListSiteAttachments returns the files stored next to a site's notes
+func ListSiteAttachments(siteKey string) ([]SiteAttachment, error) {
+	dir, err := SiteDir(siteKey)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var attachments []SiteAttachment
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == SiteNotesFile {
+			continue
+		}
+		var size int64
+		if info, err := entry.Info(); err == nil {
+			size = info.Size()
+		}
+		attachments = append(attachments, SiteAttachment{
+			Name: entry.Name(),
+			Path: filepath.Join(dir, entry.Name()),
+			Size: size,
+		})
+	}
+	sort.Slice(attachments, func(i, j int) bool { return attachments[i].Name < attachments[j].Name })
+	return attachments, nil
+}
@@ -0,0 +1,268 @@
+package system
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// KeyProviders maps the code hosts ravact imports public keys from to the
+// base URL serving <account>.keys
+var KeyProviders = map[string]string{
+	"github": "https://github.com",
+	"gitlab": "https://gitlab.com",
+}
+
+// keyFetchTimeout bounds a request for an account's keys
+const keyFetchTimeout = 15 * time.Second
+
+// keyFetchLimit caps the size of a keys listing
+const keyFetchLimit = 256 << 10
+
+var keyAccountPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,254}$`)
+
+// authorizedKeyTypes are the key algorithms accepted from a keys listing
+var authorizedKeyTypes = map[string]bool{
+	"ssh-rsa":                            true,
+	"ssh-ed25519":                        true,
+	"ecdsa-sha2-nistp256":                true,
+	"ecdsa-sha2-nistp384":                true,
+	"ecdsa-sha2-nistp521":                true,
+	"sk-ssh-ed25519@openssh.com":         true,
+	"sk-ecdsa-sha2-nistp256@openssh.com": true,
+}
+
+// RemoteKey is a public key published by a code host account
+type RemoteKey struct {
+	Type        string
+	Blob        string // Base64 key data
+	Comment     string
+	Fingerprint string // SHA256:..., as ssh-keygen -l prints it
+}
+
+// Line returns the key as an authorized_keys entry
+func (k RemoteKey) Line() string {
+	line := k.Type + " " + k.Blob
+	if k.Comment != "" {
+		line += " " + k.Comment
+	}
+	return line
+}
+
+// KeySource records which keys were imported from an account, so a later
+// re-sync can drop keys the account no longer publishes
+type KeySource struct {
+	Username     string    `json:"username"` // The local user whose authorized_keys holds them
+	Provider     string    `json:"provider"`
+	Account      string    `json:"account"`
+	Fingerprints []string  `json:"fingerprints"`
+	SyncedAt     time.Time `json:"synced_at"`
+}
+
+// storeKey is the source's key in BucketKeySources
+func (s KeySource) storeKey() string {
+	return s.Username + "/" + s.Provider + "/" + s.Account
+}
+
+// ParseAuthorizedKey reads a public key line, skipping any authorized_keys
+// options in front of the key type
+func ParseAuthorizedKey(line string) (RemoteKey, bool) {
+	fields := strings.Fields(strings.TrimSpace(line))
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return RemoteKey{}, false
+	}
+	for i := 0; i+1 < len(fields); i++ {
+		if !authorizedKeyTypes[fields[i]] {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(fields[i+1])
+		if err != nil {
+			return RemoteKey{}, false
+		}
+		sum := sha256.Sum256(raw)
+		return RemoteKey{
+			Type:        fields[i],
+			Blob:        fields[i+1],
+			Comment:     strings.Join(fields[i+2:], " "),
+			Fingerprint: "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:]),
+		}, true
+	}
+	return RemoteKey{}, false
+}
+
+// FetchRemoteKeys downloads the public keys an account publishes, such as
+// https://github.com/<account>.keys
+func FetchRemoteKeys(provider, account string) ([]RemoteKey, error) {
+	base, ok := KeyProviders[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown key provider %q", provider)
+	}
+	if !keyAccountPattern.MatchString(account) {
+		return nil, fmt.Errorf("invalid %s username %q", provider, account)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keyFetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(base, "/")+"/"+account+".keys", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "ravact")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch keys from %s: %w", provider, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s user %q not found", provider, account)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch keys from %s: %s", provider, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, keyFetchLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keys from %s: %w", provider, err)
+	}
+
+	var keys []RemoteKey
+	for _, line := range strings.Split(string(body), "\n") {
+		if key, ok := ParseAuthorizedKey(line); ok {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s user %q has no public keys", provider, account)
+	}
+	return keys, nil
+}
+
+// mergeAuthorizedKeys removes the previously imported keys that are no
+// longer selected and appends selected keys not already present. Other
+// lines are kept as they are.
+func mergeAuthorizedKeys(content string, previous []string, selected []RemoteKey) (string, int, int) {
+	drop := map[string]bool{}
+	for _, fp := range previous {
+		drop[fp] = true
+	}
+	for _, k := range selected {
+		delete(drop, k.Fingerprint)
+	}
+
+	var lines []string
+	present := map[string]bool{}
+	removed := 0
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		if key, ok := ParseAuthorizedKey(line); ok {
+			if drop[key.Fingerprint] {
+				removed++
+				continue
+			}
+			present[key.Fingerprint] = true
+		}
+		if line != "" || len(lines) > 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	added := 0
+	for _, k := range selected {
+		if present[k.Fingerprint] {
+			continue
+		}
+		lines = append(lines, k.Line())
+		present[k.Fingerprint] = true
+		added++
+	}
+	if len(lines) == 0 {
+		return "", added, removed
+	}
+	return strings.Join(lines, "\n") + "\n", added, removed
+}
+
+// updateAuthorizedKeys merges keys into a user's authorized_keys, creating
+// it if needed, and removes the previous fingerprints no longer selected
+func (um *UserManager) updateAuthorizedKeys(username string, previous []string, selected []RemoteKey) (int, int, error) {
+	user, err := um.GetUser(username)
+	if err != nil {
+		return 0, 0, err
+	}
+	sshDir := user.HomeDir + "/.ssh"
+	authKeysPath := sshDir + "/authorized_keys"
+	content, err := ReadFile(authKeysPath)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, 0, fmt.Errorf("failed to read authorized_keys: %w", err)
+	}
+	merged, added, removed := mergeAuthorizedKeys(string(content), previous, selected)
+	if added == 0 && removed == 0 {
+		return 0, 0, nil
+	}
+	if err := MkdirAll(sshDir, 0700); err != nil {
+		return 0, 0, fmt.Errorf("failed to create %s: %w", sshDir, err)
+	}
+	if err := WriteFile(authKeysPath, []byte(merged), 0600); err != nil {
+		return 0, 0, fmt.Errorf("failed to write authorized_keys: %w", err)
+	}
+	owner := fmt.Sprintf("%s:%s", username, username)
+	Command("chown", owner, sshDir, authKeysPath).Run()
+	return added, removed, nil
+}
+
+// ImportRemoteKeys writes the selected keys from an account into a user's
+// authorized_keys and records the source. Keys imported from the same
+// account earlier but no longer selected are removed.
+func (um *UserManager) ImportRemoteKeys(username, provider, account string, selected []RemoteKey) (added, removed int, err error) {
+	src := KeySource{Username: username, Provider: provider, Account: account}
+	var previous KeySource
+	if err := ViewStore(func(tx *StoreTx) error {
+		_, err := tx.Get(BucketKeySources, src.storeKey(), &previous)
+		return err
+	}); err != nil {
+		return 0, 0, err
+	}
+
+	added, removed, err = um.updateAuthorizedKeys(username, previous.Fingerprints, selected)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = UpdateStore(func(tx *StoreTx) error {
+		if len(selected) == 0 {
+			return tx.Delete(BucketKeySources, src.storeKey())
+		}
+		for _, k := range selected {
+			src.Fingerprints = append(src.Fingerprints, k.Fingerprint)
+		}
+		src.SyncedAt = time.Now()
+		return tx.Put(BucketKeySources, src.storeKey(), src)
+	})
+	return added, removed, err
+}
+
+// LoadKeySources returns the accounts keys were imported from for a user
+func LoadKeySources(username string) ([]KeySource, error) {
+	var sources []KeySource
+	err := ViewStore(func(tx *StoreTx) error {
+		for _, key := range tx.Keys(BucketKeySources) {
+			if !strings.HasPrefix(key, username+"/") {
+				continue
+			}
+			var s KeySource
+			if _, err := tx.Get(BucketKeySources, key, &s); err != nil {
+				return err
+			}
+			sources = append(sources, s)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key sources: %w", err)
+	}
+	return sources, nil
+}
@@ -0,0 +1,102 @@
+package system
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testKeyA = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFh"
+	testKeyB = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGJiYmJiYmJiYmJiYmJiYmJiYmJiYmJiYmJiYmJiYmJi"
+)
+
+func TestParseAuthorizedKey(t *testing.T) {
+	key, ok := ParseAuthorizedKey(`from="10.0.0.1",no-pty ` + testKeyA + " deploy@laptop")
+	if !ok {
+		t.Fatal("expected key with options to parse")
+	}
+	if key.Type != "ssh-ed25519" || key.Comment != "deploy@laptop" {
+		t.Errorf("unexpected key: %+v", key)
+	}
+	// As printed by ssh-keygen -lf
+	if key.Fingerprint != "SHA256:TGIk2D1DFZBKmjWssB2+Qdiona/OUK1TSuWAAHMK+n0" {
+		t.Errorf("unexpected fingerprint %q", key.Fingerprint)
+	}
+	if key.Line() != testKeyA+" deploy@laptop" {
+		t.Errorf("Line() dropped or kept the wrong fields: %q", key.Line())
+	}
+
+	other, _ := ParseAuthorizedKey(testKeyB)
+	if other.Fingerprint == key.Fingerprint {
+		t.Error("different keys should have different fingerprints")
+	}
+
+	for _, line := range []string{"", "# comment", "ssh-dss AAAAB3NzaC1kc3M=", "ssh-ed25519 not-base64!"} {
+		if _, ok := ParseAuthorizedKey(line); ok {
+			t.Errorf("expected %q to be rejected", line)
+		}
+	}
+}
+
+func TestFetchRemoteKeys(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/octocat.keys" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(testKeyA + "\n" + testKeyB + "\n\n"))
+	}))
+	defer srv.Close()
+
+	orig := KeyProviders
+	KeyProviders = map[string]string{"github": srv.URL}
+	defer func() { KeyProviders = orig }()
+
+	keys, err := FetchRemoteKeys("github", "octocat")
+	if err != nil {
+		t.Fatalf("FetchRemoteKeys failed: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(keys))
+	}
+
+	if _, err := FetchRemoteKeys("github", "nobody"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if _, err := FetchRemoteKeys("github", "../etc"); err == nil {
+		t.Error("expected invalid username to be rejected")
+	}
+	if _, err := FetchRemoteKeys("bitbucket", "octocat"); err == nil {
+		t.Error("expected unknown provider to be rejected")
+	}
+}
+
+func TestMergeAuthorizedKeys(t *testing.T) {
+	a, _ := ParseAuthorizedKey(testKeyA)
+	b, _ := ParseAuthorizedKey(testKeyB)
+	existing := "# managed by hand\n" + testKeyA + " old-comment\n"
+
+	// Importing both keys skips the one already present
+	merged, added, removed := mergeAuthorizedKeys(existing, nil, []RemoteKey{a, b})
+	if added != 1 || removed != 0 {
+		t.Errorf("expected 1 added 0 removed, got %d %d", added, removed)
+	}
+	if !strings.Contains(merged, "# managed by hand\n") || !strings.Contains(merged, testKeyB) {
+		t.Errorf("unexpected merge:\n%s", merged)
+	}
+
+	// Re-syncing without b removes it but keeps other lines
+	merged, added, removed = mergeAuthorizedKeys(merged, []string{a.Fingerprint, b.Fingerprint}, []RemoteKey{a})
+	if added != 0 || removed != 1 {
+		t.Errorf("expected 0 added 1 removed, got %d %d", added, removed)
+	}
+	if strings.Contains(merged, testKeyB) || !strings.Contains(merged, "old-comment") {
+		t.Errorf("unexpected re-sync:\n%s", merged)
+	}
+
+	if merged, added, _ := mergeAuthorizedKeys("", nil, []RemoteKey{b}); added != 1 || merged != testKeyB+"\n" {
+		t.Errorf("expected new file with one key, got %q", merged)
+	}
+}
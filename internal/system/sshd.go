@@ -0,0 +1,274 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSHDConfigPath is the OpenSSH server configuration
+var SSHDConfigPath = "/etc/ssh/sshd_config"
+
+// SSHDSettings are the hardening options managed by ravact
+type SSHDSettings struct {
+	PermitRootLogin        string // yes, prohibit-password, no
+	PasswordAuthentication string // yes, no
+	Port                   string
+	AllowUsers             string // Space-separated; empty allows everyone
+	MaxAuthTries           string
+}
+
+// sshdDirectives maps sshd keywords to their settings and OpenSSH defaults
+var sshdDirectives = []struct {
+	keyword string
+	def     string
+	field   func(*SSHDSettings) *string
+}{
+	{"Port", "22", func(s *SSHDSettings) *string { return &s.Port }},
+	{"PermitRootLogin", "prohibit-password", func(s *SSHDSettings) *string { return &s.PermitRootLogin }},
+	{"PasswordAuthentication", "yes", func(s *SSHDSettings) *string { return &s.PasswordAuthentication }},
+	{"MaxAuthTries", "6", func(s *SSHDSettings) *string { return &s.MaxAuthTries }},
+	{"AllowUsers", "", func(s *SSHDSettings) *string { return &s.AllowUsers }},
+}
+
+// sshdDirective splits an active config line into keyword and value
+func sshdDirective(line string) (keyword, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return fields[0], "", true
+	}
+	return fields[0], strings.Join(fields[1:], " "), true
+}
+
+// ParseSSHDSettings returns the settings in effect for a config. As in sshd,
+// the first occurrence of a keyword wins and Match blocks are ignored.
+func ParseSSHDSettings(content string) SSHDSettings {
+	var s SSHDSettings
+	seen := map[string]bool{}
+	for _, line := range strings.Split(content, "\n") {
+		keyword, value, ok := sshdDirective(line)
+		if !ok {
+			continue
+		}
+		if strings.EqualFold(keyword, "Match") {
+			break
+		}
+		for _, d := range sshdDirectives {
+			if strings.EqualFold(keyword, d.keyword) && !seen[d.keyword] {
+				seen[d.keyword] = true
+				*d.field(&s) = value
+			}
+		}
+	}
+	for _, d := range sshdDirectives {
+		if !seen[d.keyword] {
+			*d.field(&s) = d.def
+		}
+	}
+	return s
+}
+
+// Validate checks the settings before they are written
+func (s SSHDSettings) Validate() error {
+	switch s.PermitRootLogin {
+	case "yes", "prohibit-password", "without-password", "forced-commands-only", "no":
+	default:
+		return fmt.Errorf("PermitRootLogin must be yes, prohibit-password, or no")
+	}
+	if s.PasswordAuthentication != "yes" && s.PasswordAuthentication != "no" {
+		return fmt.Errorf("PasswordAuthentication must be yes or no")
+	}
+	if !isPort(s.Port) || strings.Contains(s.Port, ":") {
+		return fmt.Errorf("invalid port %q", s.Port)
+	}
+	if n, err := strconv.Atoi(s.MaxAuthTries); err != nil || n < 1 || n > 100 {
+		return fmt.Errorf("MaxAuthTries must be a number between 1 and 100")
+	}
+	for _, user := range strings.Fields(s.AllowUsers) {
+		if strings.ContainsAny(user, "#\"'") {
+			return fmt.Errorf("invalid AllowUsers entry %q", user)
+		}
+	}
+	return nil
+}
+
+// ApplySSHDSettings rewrites a config with new settings. The first active
+// directive for each keyword is replaced in place and later duplicates
+// are commented out; missing directives are added before the first Match
+// block. An empty AllowUsers removes the restriction.
+func ApplySSHDSettings(content string, s SSHDSettings) string {
+	lines := strings.Split(content, "\n")
+	values := map[string]string{}
+	for _, d := range sshdDirectives {
+		values[strings.ToLower(d.keyword)] = *d.field(&s)
+	}
+
+	done := map[string]bool{}
+	matchAt := -1
+	for i, line := range lines {
+		keyword, _, ok := sshdDirective(line)
+		if !ok {
+			continue
+		}
+		if strings.EqualFold(keyword, "Match") {
+			matchAt = i
+			break
+		}
+		key := strings.ToLower(keyword)
+		value, managed := values[key]
+		if !managed {
+			continue
+		}
+		switch {
+		case done[key]:
+			lines[i] = "# " + strings.TrimSpace(line) + " # superseded by ravact"
+		case value == "":
+			lines[i] = "# " + strings.TrimSpace(line)
+		default:
+			lines[i] = canonicalKeyword(keyword) + " " + value
+		}
+		done[key] = true
+	}
+
+	var missing []string
+	for _, d := range sshdDirectives {
+		key := strings.ToLower(d.keyword)
+		if !done[key] && values[key] != "" {
+			missing = append(missing, d.keyword+" "+values[key])
+		}
+	}
+	if len(missing) == 0 {
+		return strings.Join(lines, "\n")
+	}
+
+	if matchAt < 0 {
+		text := strings.TrimRight(strings.Join(lines, "\n"), "\n")
+		return text + "\n\n# Added by ravact\n" + strings.Join(missing, "\n") + "\n"
+	}
+	block := append([]string{"# Added by ravact"}, missing...)
+	block = append(block, "")
+	result := append(append(append([]string{}, lines[:matchAt]...), block...), lines[matchAt:]...)
+	return strings.Join(result, "\n")
+}
+
+// canonicalKeyword returns the documented spelling of a managed keyword
+func canonicalKeyword(keyword string) string {
+	for _, d := range sshdDirectives {
+		if strings.EqualFold(keyword, d.keyword) {
+			return d.keyword
+		}
+	}
+	return keyword
+}
+
+// Warnings returns the risks of moving from the current settings
+func (s SSHDSettings) Warnings(current SSHDSettings) []string {
+	var warnings []string
+	if s.Port != current.Port {
+		warnings = append(warnings, fmt.Sprintf("The SSH port changes from %s to %s: allow %s/tcp in the firewall before restarting sshd", current.Port, s.Port, s.Port))
+	}
+	if s.PasswordAuthentication == "no" && current.PasswordAuthentication != "no" {
+		warnings = append(warnings, "Password logins will stop working: make sure your SSH key is in authorized_keys")
+	}
+	if s.PermitRootLogin == "no" && current.PermitRootLogin != "no" {
+		warnings = append(warnings, "root can no longer log in: make sure another user has sudo access")
+	}
+	if s.AllowUsers != "" && s.AllowUsers != current.AllowUsers {
+		warnings = append(warnings, "Only "+s.AllowUsers+" will be able to log in")
+	}
+	return warnings
+}
+
+// SSHDDropInOverrides returns the managed keywords set by files in
+// sshd_config.d, which sshd reads first when sshd_config includes them,
+// keyed by keyword with the file that sets it
+func SSHDDropInOverrides(content string) map[string]string {
+	overrides := map[string]string{}
+	includes := false
+	for _, line := range strings.Split(content, "\n") {
+		keyword, value, ok := sshdDirective(line)
+		if ok && strings.EqualFold(keyword, "Include") && strings.Contains(value, "sshd_config.d") {
+			includes = true
+		}
+	}
+	if !includes {
+		return overrides
+	}
+
+	dir := filepath.Join(filepath.Dir(SSHDConfigPath), "sshd_config.d")
+	entries, err := ReadDir(dir)
+	if err != nil {
+		return overrides
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".conf") {
+			continue
+		}
+		data, err := ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			keyword, _, ok := sshdDirective(line)
+			if !ok {
+				continue
+			}
+			if strings.EqualFold(keyword, "Match") {
+				break
+			}
+			for _, d := range sshdDirectives {
+				if strings.EqualFold(keyword, d.keyword) {
+					if _, exists := overrides[d.keyword]; !exists {
+						overrides[d.keyword] = filepath.Join(dir, entry.Name())
+					}
+				}
+			}
+		}
+	}
+	return overrides
+}
+
+// SaveSSHDConfig validates a new config with `sshd -t`, backs up the
+// current file, and installs the new one. It returns the backup path.
+// sshd is not restarted.
+func SaveSSHDConfig(content string) (string, error) {
+	tmp := SSHDConfigPath + ".ravact-new"
+	if err := WriteFile(tmp, []byte(content), 0600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if output, err := Command("sshd", "-t", "-f", tmp).CombinedOutput(); err != nil {
+		Remove(tmp)
+		return "", fmt.Errorf("sshd -t rejected the configuration: %s", strings.TrimSpace(string(output)))
+	}
+
+	original, err := ReadFile(SSHDConfigPath)
+	if err != nil {
+		Remove(tmp)
+		return "", fmt.Errorf("failed to read %s: %w", SSHDConfigPath, err)
+	}
+	backup := fmt.Sprintf("%s.bak.%s", SSHDConfigPath, time.Now().Format("20060102-150405"))
+	if err := WriteFile(backup, original, 0600); err != nil {
+		Remove(tmp)
+		return "", fmt.Errorf("failed to back up %s: %w", SSHDConfigPath, err)
+	}
+	if err := Rename(tmp, SSHDConfigPath); err != nil {
+		return "", fmt.Errorf("failed to install %s: %w", SSHDConfigPath, err)
+	}
+	return backup, nil
+}
+
+// SSHDServiceName returns the OpenSSH server unit: ssh on Debian and
+// Ubuntu, sshd elsewhere
+func SSHDServiceName() string {
+	output, _ := Command("systemctl", "list-unit-files", "ssh.service", "--no-legend").Output()
+	if strings.Contains(string(output), "ssh.service") {
+		return "ssh"
+	}
+	return "sshd"
+}
@@ -0,0 +1,97 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSSHDConfig = `Include /etc/ssh/sshd_config.d/*.conf
+
+#Port 22
+PermitRootLogin yes
+#PasswordAuthentication yes
+permitrootlogin no
+AllowUsers deploy admin
+
+Match User sftp
+    PasswordAuthentication yes
+`
+
+func TestParseSSHDSettings(t *testing.T) {
+	s := ParseSSHDSettings(testSSHDConfig)
+	want := SSHDSettings{
+		PermitRootLogin:        "yes",
+		PasswordAuthentication: "yes",
+		Port:                   "22",
+		AllowUsers:             "deploy admin",
+		MaxAuthTries:           "6",
+	}
+	if s != want {
+		t.Errorf("got %+v, want %+v", s, want)
+	}
+}
+
+func TestApplySSHDSettings(t *testing.T) {
+	s := SSHDSettings{
+		PermitRootLogin:        "no",
+		PasswordAuthentication: "no",
+		Port:                   "2222",
+		AllowUsers:             "",
+		MaxAuthTries:           "3",
+	}
+	out := ApplySSHDSettings(testSSHDConfig, s)
+
+	if got := ParseSSHDSettings(out); got != s {
+		t.Errorf("round trip: got %+v, want %+v", got, s)
+	}
+	for _, want := range []string{
+		"PermitRootLogin no\n",
+		"# permitrootlogin no # superseded by ravact",
+		"# AllowUsers deploy admin",
+		"# Added by ravact\nPort 2222\nPasswordAuthentication no\nMaxAuthTries 3\n\nMatch User sftp",
+		"    PasswordAuthentication yes",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestApplySSHDSettingsWithoutMatch(t *testing.T) {
+	out := ApplySSHDSettings("Port 22\n", SSHDSettings{
+		PermitRootLogin: "no", PasswordAuthentication: "no", Port: "22", MaxAuthTries: "6",
+	})
+	want := "Port 22\n\n# Added by ravact\nPermitRootLogin no\nPasswordAuthentication no\nMaxAuthTries 6\n"
+	if out != want {
+		t.Errorf("got:\n%q\nwant:\n%q", out, want)
+	}
+}
+
+func TestSSHDSettingsValidate(t *testing.T) {
+	valid := SSHDSettings{PermitRootLogin: "no", PasswordAuthentication: "no", Port: "22", MaxAuthTries: "3"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, s := range []SSHDSettings{
+		{PermitRootLogin: "maybe", PasswordAuthentication: "no", Port: "22", MaxAuthTries: "3"},
+		{PermitRootLogin: "no", PasswordAuthentication: "no", Port: "70000", MaxAuthTries: "3"},
+		{PermitRootLogin: "no", PasswordAuthentication: "no", Port: "22", MaxAuthTries: "0"},
+	} {
+		if err := s.Validate(); err == nil {
+			t.Errorf("expected an error for %+v", s)
+		}
+	}
+}
+
+func TestSSHDSettingsWarnings(t *testing.T) {
+	current := SSHDSettings{PermitRootLogin: "yes", PasswordAuthentication: "yes", Port: "22", MaxAuthTries: "6"}
+	next := current
+	next.Port = "2222"
+	next.PasswordAuthentication = "no"
+	if w := next.Warnings(current); len(w) != 2 || !strings.Contains(w[0], "2222/tcp") {
+		t.Errorf("unexpected warnings: %v", w)
+	}
+	if w := current.Warnings(current); len(w) != 0 {
+		t.Errorf("expected no warnings, got %v", w)
+	}
+}
@@ -0,0 +1,411 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// StackLayer is a tier of a site's request path, from the web server down
+// to scheduled jobs
+type StackLayer int
+
+const (
+	StackLayerWeb StackLayer = iota
+	StackLayerApp
+	StackLayerData
+	StackLayerWorkers
+	StackLayerCron
+)
+
+// StackLayers lists the layers in dependency order
+var StackLayers = []StackLayer{StackLayerWeb, StackLayerApp, StackLayerData, StackLayerWorkers, StackLayerCron}
+
+// String returns the layer's display name
+func (l StackLayer) String() string {
+	switch l {
+	case StackLayerWeb:
+		return "Web"
+	case StackLayerApp:
+		return "App"
+	case StackLayerData:
+		return "Data"
+	case StackLayerWorkers:
+		return "Workers"
+	case StackLayerCron:
+		return "Cron"
+	}
+	return "Unknown"
+}
+
+// StackNode is a component a site depends on
+type StackNode struct {
+	Layer  StackLayer
+	Name   string // Display name
+	Kind   string // "systemd", "supervisor", or "remote"
+	Unit   string // systemd unit or supervisor program
+	Detail string
+	Status string // active, inactive, failed, remote, unknown
+}
+
+// Up reports whether the node is running
+func (n StackNode) Up() bool {
+	return n.Status == "active" || n.Status == "remote"
+}
+
+// Down reports whether the node is known to be stopped or failed
+func (n StackNode) Down() bool {
+	return n.Status == "inactive" || n.Status == "failed"
+}
+
+// SiteStack is the dependency graph of a site
+type SiteStack struct {
+	Site       NginxSite
+	ProjectDir string
+	Nodes      []StackNode
+}
+
+// Layer returns the nodes in a layer
+func (s SiteStack) Layer(layer StackLayer) []StackNode {
+	var nodes []StackNode
+	for _, n := range s.Nodes {
+		if n.Layer == layer {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// BrokenLayer returns the topmost layer with a stopped or failed node
+func (s SiteStack) BrokenLayer() (StackLayer, bool) {
+	for _, layer := range StackLayers {
+		for _, n := range s.Layer(layer) {
+			if n.Down() {
+				return layer, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// DiscoverSiteStack works out which services a site depends on from its
+// nginx configuration, the project's .env, worker units, and crontabs
+func DiscoverSiteStack(site NginxSite) SiteStack {
+	stack := SiteStack{Site: site, ProjectDir: SiteProjectDir(site.RootDir)}
+	stack.Nodes = append(stack.Nodes, StackNode{Layer: StackLayerWeb, Name: "nginx", Kind: "systemd", Unit: "nginx", Detail: site.Domain})
+
+	if config, err := ReadFile(site.ConfigPath); err == nil {
+		stack.Nodes = append(stack.Nodes, appNodesFromNginx(string(config))...)
+	}
+
+	if stack.ProjectDir == "" {
+		return stack
+	}
+
+	// FrankenPHP services serving the project over a port
+	for _, unit := range unitFilesMentioning(stack.ProjectDir, "frankenphp-*.service") {
+		name := strings.TrimSuffix(unit, ".service")
+		if !stack.has(name) {
+			stack.Nodes = append(stack.Nodes, StackNode{Layer: StackLayerApp, Name: name, Kind: "systemd", Unit: name, Detail: "FrankenPHP"})
+		}
+	}
+
+	if env, err := ParseEnvFile(filepath.Join(stack.ProjectDir, ".env")); err == nil {
+		stack.Nodes = append(stack.Nodes, dataNodesFromEnv(env)...)
+	}
+
+	for _, unit := range unitFilesMentioning("WorkingDirectory="+stack.ProjectDir, "*.service") {
+		name := strings.TrimSuffix(unit, ".service")
+		if strings.HasPrefix(name, "frankenphp-") {
+			continue
+		}
+		stack.Nodes = append(stack.Nodes, StackNode{Layer: StackLayerWorkers, Name: name, Kind: "systemd", Unit: name, Detail: "systemd"})
+	}
+	if programs, err := NewSupervisorManager().GetAllPrograms(); err == nil {
+		for _, p := range programs {
+			if strings.TrimSuffix(p.Directory, "/") == stack.ProjectDir || strings.Contains(p.Command, stack.ProjectDir+"/") {
+				stack.Nodes = append(stack.Nodes, StackNode{Layer: StackLayerWorkers, Name: p.Name, Kind: "supervisor", Unit: p.ProcessName(), Detail: "supervisor"})
+			}
+		}
+	}
+
+	if jobs := projectCronJobs(stack.ProjectDir); len(jobs) > 0 {
+		detail := jobs[0]
+		if len(jobs) > 1 {
+			detail = fmt.Sprintf("%d jobs", len(jobs))
+		}
+		stack.Nodes = append(stack.Nodes, StackNode{Layer: StackLayerCron, Name: "cron", Kind: "systemd", Unit: cronUnit(), Detail: detail})
+	}
+	sort.SliceStable(stack.Nodes, func(i, j int) bool { return stack.Nodes[i].Layer < stack.Nodes[j].Layer })
+	return stack
+}
+
+// has reports whether the stack already contains a node
+func (s SiteStack) has(name string) bool {
+	for _, n := range s.Nodes {
+		if n.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RefreshStatus updates the status of every node
+func (s *SiteStack) RefreshStatus() {
+	var units []string
+	for _, n := range s.Nodes {
+		if n.Kind == "systemd" {
+			units = append(units, n.Unit)
+		}
+	}
+
+	// systemctl prints one state per unit, in order, and exits non-zero
+	// when any of them is not active
+	states := map[string]string{}
+	if len(units) > 0 {
+		output, _ := Command("systemctl", append([]string{"is-active"}, units...)...).Output()
+		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		for i, unit := range units {
+			if i < len(lines) {
+				states[unit] = strings.TrimSpace(lines[i])
+			}
+		}
+	}
+
+	sm := NewSupervisorManager()
+	for i := range s.Nodes {
+		n := &s.Nodes[i]
+		switch n.Kind {
+		case "remote":
+			n.Status = "remote"
+		case "supervisor":
+			n.Status = supervisorStatus(sm.getProgramState(n.Unit))
+		default:
+			n.Status = states[n.Unit]
+			if n.Status == "" {
+				n.Status = "unknown"
+			}
+		}
+	}
+}
+
+// supervisorStatus maps a supervisor state onto the systemd vocabulary
+func supervisorStatus(state string) string {
+	switch state {
+	case "RUNNING":
+		return "active"
+	case "STARTING":
+		return "activating"
+	case "STOPPED", "STOPPING", "EXITED":
+		return "inactive"
+	case "FATAL", "BACKOFF":
+		return "failed"
+	}
+	return "unknown"
+}
+
+// SiteProjectDir returns the project directory for a document root,
+// dropping a trailing public/ as used by Laravel and Symfony
+func SiteProjectDir(root string) string {
+	root = strings.TrimSuffix(root, "/")
+	if root == "" {
+		return ""
+	}
+	if filepath.Base(root) == "public" || filepath.Base(root) == "web" {
+		return filepath.Dir(root)
+	}
+	return root
+}
+
+var (
+	phpFPMSocket     = regexp.MustCompile(`fastcgi_pass\s+unix:\S*?php(\d+\.\d+)-fpm\.sock`)
+	frankenPHPSocket = regexp.MustCompile(`proxy_pass\s+\S*unix:/run/frankenphp/([A-Za-z0-9_.-]+)\.sock`)
+)
+
+// appNodesFromNginx returns the application servers a site proxies to
+func appNodesFromNginx(config string) []StackNode {
+	var nodes []StackNode
+	seen := map[string]bool{}
+	add := func(node StackNode) {
+		if !seen[node.Unit] {
+			seen[node.Unit] = true
+			nodes = append(nodes, node)
+		}
+	}
+	for _, match := range phpFPMSocket.FindAllStringSubmatch(config, -1) {
+		unit := "php" + match[1] + "-fpm"
+		add(StackNode{Layer: StackLayerApp, Name: unit, Kind: "systemd", Unit: unit, Detail: "PHP-FPM"})
+	}
+	for _, match := range frankenPHPSocket.FindAllStringSubmatch(config, -1) {
+		unit := "frankenphp-" + match[1]
+		add(StackNode{Layer: StackLayerApp, Name: unit, Kind: "systemd", Unit: unit, Detail: "FrankenPHP"})
+	}
+	if len(nodes) == 0 && strings.Contains(config, "fastcgi_pass") {
+		// Unversioned socket such as /run/php-fpm/www.sock (RHEL)
+		add(StackNode{Layer: StackLayerApp, Name: "php-fpm", Kind: "systemd", Unit: "php-fpm", Detail: "PHP-FPM"})
+	}
+	return nodes
+}
+
+// dataNodesFromEnv returns the databases and caches a Laravel .env uses
+func dataNodesFromEnv(env *EnvFile) []StackNode {
+	var nodes []StackNode
+	get := func(key string) string {
+		v, _ := env.Get(key)
+		return strings.TrimSpace(v)
+	}
+	local := func(host string) bool {
+		return host == "" || host == "127.0.0.1" || host == "localhost" || host == "::1"
+	}
+
+	dbHost := get("DB_HOST")
+	switch get("DB_CONNECTION") {
+	case "mysql", "mariadb":
+		nodes = append(nodes, dataNode("mysql", "mysql", dbHost, local(dbHost)))
+	case "pgsql":
+		nodes = append(nodes, dataNode("postgresql", "postgresql", dbHost, local(dbHost)))
+	}
+
+	usesRedis := false
+	for _, key := range []string{"CACHE_STORE", "CACHE_DRIVER", "QUEUE_CONNECTION", "SESSION_DRIVER", "BROADCAST_CONNECTION"} {
+		if get(key) == "redis" {
+			usesRedis = true
+		}
+	}
+	if usesRedis {
+		redisHost := get("REDIS_HOST")
+		nodes = append(nodes, dataNode("redis", "redis-server", redisHost, local(redisHost)))
+	}
+	return nodes
+}
+
+// dataNode builds a data-layer node, marking services on other hosts as
+// remote since their status cannot be checked here
+func dataNode(name, unit, host string, local bool) StackNode {
+	if !local {
+		return StackNode{Layer: StackLayerData, Name: name, Kind: "remote", Unit: unit, Detail: host}
+	}
+	return StackNode{Layer: StackLayerData, Name: name, Kind: "systemd", Unit: unit, Detail: "local"}
+}
+
+// unitFilesMentioning returns the administrator unit files matching
+// pattern whose content contains text
+func unitFilesMentioning(text, pattern string) []string {
+	entries, err := ReadDir(SystemdLocalUnitDir)
+	if err != nil {
+		return nil
+	}
+	var units []string
+	for _, entry := range entries {
+		if ok, _ := filepath.Match(pattern, entry.Name()); !ok || entry.IsDir() {
+			continue
+		}
+		content, err := ReadFile(filepath.Join(SystemdLocalUnitDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		if containsPath(string(content), text) {
+			units = append(units, entry.Name())
+		}
+	}
+	return units
+}
+
+// containsPath reports whether text appears in content as a whole path,
+// so /var/www/app does not match /var/www/app2
+func containsPath(content, text string) bool {
+	for {
+		i := strings.Index(content, text)
+		if i < 0 {
+			return false
+		}
+		rest := content[i+len(text):]
+		if rest == "" || strings.ContainsAny(rest[:1], "/ \t\n\"'") {
+			return true
+		}
+		content = rest
+	}
+}
+
+// cronSources are the crontabs searched for a project's scheduled jobs
+var cronSources = []string{"/etc/crontab", "/etc/cron.d", "/var/spool/cron/crontabs", "/var/spool/cron"}
+
+// projectCronJobs returns the cron entries that run inside a project
+func projectCronJobs(projectDir string) []string {
+	crontabs := Crontabs()
+	paths := make([]string, 0, len(crontabs))
+	for path := range crontabs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var jobs []string
+	for _, path := range paths {
+		jobs = append(jobs, parseCronJobs(crontabs[path], projectDir)...)
+	}
+	return jobs
+}
+
+// Crontabs returns the content of every system and user crontab, by path
+func Crontabs() map[string]string {
+	crontabs := map[string]string{}
+	for _, source := range cronSources {
+		info, err := Stat(source)
+		if err != nil {
+			continue
+		}
+		files := []string{source}
+		if info.IsDir() {
+			files = nil
+			entries, _ := ReadDir(source)
+			for _, e := range entries {
+				if !e.IsDir() {
+					files = append(files, filepath.Join(source, e.Name()))
+				}
+			}
+		}
+		for _, file := range files {
+			if content, err := ReadFile(file); err == nil {
+				crontabs[file] = string(content)
+			}
+		}
+	}
+	return crontabs
+}
+
+// parseCronJobs returns the schedule of the crontab lines that mention
+// projectDir, such as "* * * * * artisan schedule:run"
+func parseCronJobs(content, projectDir string) []string {
+	var jobs []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || !containsPath(line, projectDir) {
+			continue
+		}
+		fields := strings.Fields(line)
+		var job string
+		switch {
+		case strings.HasPrefix(fields[0], "@"):
+			job = fields[0]
+		case len(fields) >= 6:
+			job = strings.Join(fields[:5], " ")
+		default:
+			continue
+		}
+		if strings.Contains(line, "schedule:run") {
+			job += " schedule:run"
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+// cronUnit returns the cron daemon's unit name for the host's distro
+func cronUnit() string {
+	if _, err := Stat("/usr/lib/systemd/system/crond.service"); err == nil {
+		return "crond"
+	}
+	return "cron"
+}
@@ -0,0 +1,104 @@
+package system
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RestartStep is one stage of a full-stack restart
+type RestartStep struct {
+	Title    string
+	Commands []string
+}
+
+// workerCommands returns the commands that stop or start the stack's
+// queue workers
+func (s SiteStack) workerCommands(action string) []string {
+	var units, programs []string
+	for _, n := range s.Layer(StackLayerWorkers) {
+		switch n.Kind {
+		case "systemd":
+			units = append(units, ShellQuote(n.Unit))
+		case "supervisor":
+			programs = append(programs, ShellQuote(n.Unit))
+		}
+	}
+	var commands []string
+	if len(units) > 0 {
+		commands = append(commands, "systemctl "+action+" "+strings.Join(units, " "))
+	}
+	if len(programs) > 0 {
+		commands = append(commands, "supervisorctl "+action+" "+strings.Join(programs, " "))
+	}
+	return commands
+}
+
+// healthCheckCommand requests the site through the running nginx and
+// retries for up to ten seconds until it answers without a 5xx error
+func (s SiteStack) healthCheckCommand() string {
+	host := s.Site.Domain
+	if host == "" || host == "_" {
+		host = "localhost"
+	}
+	return fmt.Sprintf(`for i in $(seq 1 10); do `+
+		`code=$(curl -s -o /dev/null -m 5 -w '%%{http_code}' -H %s http://127.0.0.1/ || true); `+
+		`echo "HTTP $code"; `+
+		`if [ "$code" -ge 200 ] 2>/dev/null && [ "$code" -lt 500 ]; then break; fi; `+
+		`if [ "$i" = 10 ]; then echo "Health check failed for %s"; false; fi; `+
+		`sleep 1; done`, ShellQuote("Host: "+host), host)
+}
+
+// RestartSteps returns the stages of a full-stack restart in dependency
+// order: queue workers are drained first so no job is killed mid-flight
+// or runs against a restarting app, the app servers restart, the site is health-checked,
+// nginx reloads last, and the workers start again. Databases and caches
+// are left running.
+func (s SiteStack) RestartSteps() []RestartStep {
+	var steps []RestartStep
+
+	if drain := s.DrainCommands(DefaultDrainTimeout); len(drain) > 0 {
+		steps = append(steps, RestartStep{Title: "Drain queue workers", Commands: drain})
+	}
+
+	var app []string
+	for _, n := range s.Layer(StackLayerApp) {
+		app = append(app, "systemctl restart "+ShellQuote(n.Unit), "systemctl is-active "+ShellQuote(n.Unit))
+	}
+	if len(app) > 0 {
+		steps = append(steps, RestartStep{Title: "Restart app services", Commands: app})
+	}
+
+	steps = append(steps,
+		RestartStep{Title: "Health check " + s.Site.Domain, Commands: []string{s.healthCheckCommand()}},
+		RestartStep{Title: "Reload nginx", Commands: []string{"nginx -t", "systemctl reload nginx"}},
+	)
+
+	if start := s.workerCommands("start"); len(start) > 0 {
+		steps = append(steps, RestartStep{Title: "Start queue workers", Commands: start})
+	}
+	return steps
+}
+
+// RestartScript returns the restart as a single bash script that prints
+// each step and stops at the first failure. Workers drained earlier are
+// started again if a later step fails.
+func (s SiteStack) RestartScript() string {
+	steps := s.RestartSteps()
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	if start := s.workerCommands("start"); len(start) > 0 {
+		restore := "echo; echo 'Step failed; starting queue workers again'; " + strings.Join(start, "; ")
+		fmt.Fprintf(&b, "trap %s ERR\n", ShellQuote(restore))
+	}
+	for i, step := range steps {
+		fmt.Fprintf(&b, "echo; echo %s\n", ShellQuote(fmt.Sprintf("==> [%d/%d] %s", i+1, len(steps), step.Title)))
+		if i == len(steps)-1 && step.Title == "Start queue workers" {
+			b.WriteString("trap - ERR\n")
+		}
+		for _, command := range step.Commands {
+			fmt.Fprintf(&b, "echo %s\n%s\n", ShellQuote("$ "+command), command)
+		}
+	}
+	b.WriteString("echo; echo '==> Stack restarted'\n")
+	return b.String()
+}
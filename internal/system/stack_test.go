@@ -0,0 +1,213 @@
+package system
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAppNodesFromNginx(t *testing.T) {
+	config := `server {
+    server_name example.com;
+    root /var/www/example/public;
+    location ~ \.php$ {
+        fastcgi_pass unix:/run/php/php8.3-fpm.sock;
+    }
+    location /app {
+        proxy_pass http://unix:/run/frankenphp/example.sock;
+    }
+    location ~ \.phar$ {
+        fastcgi_pass unix:/run/php/php8.3-fpm.sock;
+    }
+}`
+	nodes := appNodesFromNginx(config)
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 app nodes, got %+v", nodes)
+	}
+	if nodes[0].Unit != "php8.3-fpm" || nodes[1].Unit != "frankenphp-example" {
+		t.Errorf("unexpected units: %s, %s", nodes[0].Unit, nodes[1].Unit)
+	}
+
+	nodes = appNodesFromNginx("fastcgi_pass unix:/run/php-fpm/www.sock;")
+	if len(nodes) != 1 || nodes[0].Unit != "php-fpm" {
+		t.Errorf("expected unversioned php-fpm, got %+v", nodes)
+	}
+}
+
+func TestDataNodesFromEnv(t *testing.T) {
+	env := ParseEnv("DB_CONNECTION=mysql\nDB_HOST=127.0.0.1\nQUEUE_CONNECTION=redis\nREDIS_HOST=10.0.0.5\n")
+	nodes := dataNodesFromEnv(env)
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 data nodes, got %+v", nodes)
+	}
+	if nodes[0].Name != "mysql" || nodes[0].Kind != "systemd" {
+		t.Errorf("unexpected database node: %+v", nodes[0])
+	}
+	if nodes[1].Name != "redis" || nodes[1].Kind != "remote" || nodes[1].Detail != "10.0.0.5" {
+		t.Errorf("unexpected redis node: %+v", nodes[1])
+	}
+
+	if nodes := dataNodesFromEnv(ParseEnv("DB_CONNECTION=sqlite\nCACHE_STORE=file\n")); len(nodes) != 0 {
+		t.Errorf("expected no data nodes for sqlite, got %+v", nodes)
+	}
+}
+
+func TestParseCronJobs(t *testing.T) {
+	content := `# m h dom mon dow command
+* * * * * cd /var/www/example && php artisan schedule:run >> /dev/null 2>&1
+0 3 * * * /var/www/example2/backup.sh
+@daily /var/www/example/bin/cleanup
+`
+	jobs := parseCronJobs(content, "/var/www/example")
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %v", jobs)
+	}
+	if jobs[0] != "* * * * * schedule:run" || jobs[1] != "@daily" {
+		t.Errorf("unexpected jobs: %v", jobs)
+	}
+}
+
+func TestSiteProjectDir(t *testing.T) {
+	tests := map[string]string{
+		"/var/www/app/public":  "/var/www/app",
+		"/var/www/app/public/": "/var/www/app",
+		"/var/www/html":        "/var/www/html",
+		"":                     "",
+	}
+	for root, want := range tests {
+		if got := SiteProjectDir(root); got != want {
+			t.Errorf("SiteProjectDir(%q) = %q, want %q", root, got, want)
+		}
+	}
+}
+
+func TestBrokenLayer(t *testing.T) {
+	stack := SiteStack{Nodes: []StackNode{
+		{Layer: StackLayerWeb, Name: "nginx", Status: "active"},
+		{Layer: StackLayerApp, Name: "php8.3-fpm", Status: "active"},
+		{Layer: StackLayerData, Name: "mysql", Status: "failed"},
+		{Layer: StackLayerWorkers, Name: "queue", Status: "inactive"},
+	}}
+	layer, broken := stack.BrokenLayer()
+	if !broken || layer != StackLayerData {
+		t.Errorf("expected the data layer to be broken, got %v %v", layer, broken)
+	}
+
+	stack.Nodes[2].Status = "remote"
+	stack.Nodes[3].Status = "active"
+	if _, broken := stack.BrokenLayer(); broken {
+		t.Error("expected no broken layer")
+	}
+}
+
+func testRestartStack() SiteStack {
+	return SiteStack{
+		Site: NginxSite{Name: "example", Domain: "example.com"},
+		Nodes: []StackNode{
+			{Layer: StackLayerWeb, Name: "nginx", Kind: "systemd", Unit: "nginx"},
+			{Layer: StackLayerApp, Name: "php8.3-fpm", Kind: "systemd", Unit: "php8.3-fpm"},
+			{Layer: StackLayerData, Name: "mysql", Kind: "systemd", Unit: "mysql"},
+			{Layer: StackLayerWorkers, Name: "example-queue", Kind: "systemd", Unit: "example-queue"},
+			{Layer: StackLayerWorkers, Name: "example-horizon", Kind: "supervisor", Unit: "example-horizon"},
+		},
+	}
+}
+
+func TestRestartSteps(t *testing.T) {
+	steps := testRestartStack().RestartSteps()
+	var titles []string
+	for _, s := range steps {
+		titles = append(titles, s.Title)
+	}
+	want := []string{"Drain queue workers", "Restart app services", "Health check example.com", "Reload nginx", "Start queue workers"}
+	if strings.Join(titles, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected steps: %v", titles)
+	}
+	if len(steps[0].Commands) != 2 ||
+		!strings.HasPrefix(steps[0].Commands[0], "systemctl stop --no-block example-queue;") ||
+		!strings.HasPrefix(steps[0].Commands[1], "if timeout 120 supervisorctl stop example-horizon;") {
+		t.Errorf("unexpected drain commands: %v", steps[0].Commands)
+	}
+	for _, step := range steps {
+		for _, c := range step.Commands {
+			if strings.Contains(c, "mysql") {
+				t.Errorf("databases must not be restarted: %s", c)
+			}
+		}
+	}
+}
+
+// runRestartScript runs the script with stub commands that log their
+// arguments, and returns the log
+func runRestartScript(t *testing.T, failing string) (string, error) {
+	t.Helper()
+	return runStubbedScript(t, testRestartStack().RestartScript(), failing, "inactive")
+}
+
+// runStubbedScript runs a script with stub commands that log their
+// arguments, and returns the log. failing names a command that exits
+// with an error and state is what `systemctl show` reports.
+func runStubbedScript(t *testing.T, script, failing, state string) (string, error) {
+	t.Helper()
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available")
+	}
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "calls.log")
+	for _, name := range []string{"systemctl", "supervisorctl", "nginx", "curl"} {
+		body := "#!/bin/sh\n"
+		if name == "systemctl" {
+			body += "if [ \"$1\" = show ]; then echo " + state + "; exit 0; fi\n"
+		}
+		body += "echo \"" + name + " $*\" >> " + logPath + "\n"
+		if name == "curl" {
+			body += "printf 200\n"
+		}
+		if name == failing {
+			body += "exit 1\n"
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command("bash", "-c", script)
+	cmd.Env = append(os.Environ(), "PATH="+dir+":"+os.Getenv("PATH"))
+	_, err := cmd.CombinedOutput()
+	calls, _ := os.ReadFile(logPath)
+	return string(calls), err
+}
+
+func TestRestartScript(t *testing.T) {
+	calls, err := runRestartScript(t, "")
+	if err != nil {
+		t.Fatalf("script failed: %v\n%s", err, calls)
+	}
+	want := `systemctl stop --no-block example-queue
+supervisorctl stop example-horizon
+systemctl restart php8.3-fpm
+systemctl is-active php8.3-fpm
+curl -s -o /dev/null -m 5 -w %{http_code} -H Host: example.com http://127.0.0.1/
+nginx -t
+systemctl reload nginx
+systemctl start example-queue
+supervisorctl start example-horizon
+`
+	if calls != want {
+		t.Errorf("unexpected calls:\n%s", calls)
+	}
+}
+
+func TestRestartScriptRestartsWorkersOnFailure(t *testing.T) {
+	calls, err := runRestartScript(t, "nginx")
+	if err == nil {
+		t.Fatal("expected the script to fail")
+	}
+	if strings.Contains(calls, "systemctl reload nginx") {
+		t.Error("nginx must not be reloaded after a failed config test")
+	}
+	if !strings.HasSuffix(calls, "systemctl start example-queue\nsupervisorctl start example-horizon\n") {
+		t.Errorf("expected workers to be started again:\n%s", calls)
+	}
+}
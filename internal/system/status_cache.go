@@ -0,0 +1,117 @@
+package system
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/models"
+)
+
+// StatusTTL is how long list screens reuse a detected service status
+// before asking systemctl again
+var StatusTTL = 30 * time.Second
+
+// StatusCache remembers slow lookups, such as systemctl calls, for
+// StatusTTL. It is safe for concurrent use.
+type StatusCache[V any] struct {
+	mu      sync.Mutex
+	entries map[string]statusEntry[V]
+	now     func() time.Time
+}
+
+// statusEntry is a cached value and when it was looked up
+type statusEntry[V any] struct {
+	value   V
+	fetched time.Time
+}
+
+// NewStatusCache creates an empty cache
+func NewStatusCache[V any]() *StatusCache[V] {
+	return &StatusCache[V]{entries: make(map[string]statusEntry[V]), now: time.Now}
+}
+
+// Get returns the value cached for key, calling load when there is none or
+// it is older than StatusTTL. load runs without the lock held, so lookups of
+// different keys run side by side.
+func (c *StatusCache[V]) Get(key string, load func() V) V {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Sub(entry.fetched) < StatusTTL {
+		return entry.value
+	}
+
+	value := load()
+	c.mu.Lock()
+	c.entries[key] = statusEntry[V]{value: value, fetched: c.now()}
+	c.mu.Unlock()
+	return value
+}
+
+// Invalidate drops the given keys, or everything when none are given
+func (c *StatusCache[V]) Invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(keys) == 0 {
+		clear(c.entries)
+		return
+	}
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+}
+
+// UnitState is whether a systemd unit runs and starts on boot
+type UnitState struct {
+	Active  string // systemctl is-active output, "unknown" when empty
+	Enabled bool
+}
+
+// GetUnitState asks systemctl for a unit's state
+func GetUnitState(unit string) UnitState {
+	state := UnitState{Active: "unknown"}
+	if output, _ := Command("systemctl", "is-active", unit).Output(); len(strings.TrimSpace(string(output))) > 0 {
+		state.Active = strings.TrimSpace(string(output))
+	}
+	output, _ := Command("systemctl", "is-enabled", unit).Output()
+	state.Enabled = strings.TrimSpace(string(output)) == "enabled"
+	return state
+}
+
+var (
+	serviceStatuses = NewStatusCache[models.ServiceStatus]()
+	unitStates      = NewStatusCache[UnitState]()
+)
+
+// statusKey scopes a cache key to the host being managed
+func statusKey(name string) string {
+	return CurrentTransport().Name() + "\x00" + name
+}
+
+// CachedServiceStatus is GetServiceStatus, reused for StatusTTL
+func (d *Detector) CachedServiceStatus(serviceName string) models.ServiceStatus {
+	return serviceStatuses.Get(statusKey(serviceName), func() models.ServiceStatus {
+		status, _ := d.GetServiceStatus(serviceName)
+		return status
+	})
+}
+
+// CachedUnitState is GetUnitState, reused for StatusTTL
+func CachedUnitState(unit string) UnitState {
+	return unitStates.Get(statusKey(unit), func() UnitState {
+		return GetUnitState(unit)
+	})
+}
+
+// InvalidateServiceStatus forgets the cached states of the given services,
+// or of every service when none are given, so the next lookup asks
+// systemctl again
+func InvalidateServiceStatus(names ...string) {
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = statusKey(name)
+	}
+	serviceStatuses.Invalidate(keys...)
+	unitStates.Invalidate(keys...)
+}
@@ -0,0 +1,43 @@
+package system
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatusCache(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewStatusCache[string]()
+	c.now = func() time.Time { return now }
+
+	loads := 0
+	load := func() string {
+		loads++
+		return "active"
+	}
+	c.Get("nginx", load)
+	now = now.Add(StatusTTL - time.Second)
+	if got := c.Get("nginx", load); got != "active" || loads != 1 {
+		t.Errorf("expected the cached value within the TTL, got %q after %d loads", got, loads)
+	}
+
+	now = now.Add(2 * time.Second)
+	c.Get("nginx", load)
+	if loads != 2 {
+		t.Errorf("expected an expired entry to load again, got %d loads", loads)
+	}
+
+	c.Get("mysql", load)
+	c.Invalidate("nginx")
+	c.Get("nginx", load)
+	c.Get("mysql", load)
+	if loads != 4 {
+		t.Errorf("expected only the invalidated key to load again, got %d loads", loads)
+	}
+
+	c.Invalidate()
+	c.Get("mysql", load)
+	if loads != 5 {
+		t.Errorf("expected invalidating everything to drop mysql, got %d loads", loads)
+	}
+}
@@ -0,0 +1,252 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// StateDir holds ravact's persistent state on the managed host
+var StateDir = "/var/lib/ravact"
+
+// StoreFile is the metadata store inside StateDir
+const StoreFile = "ravact.db"
+
+// storeLockTimeout is how long an update waits for another ravact process
+// before giving up; locks older than storeStaleLock are assumed abandoned
+const (
+	storeLockTimeout = 10 * time.Second
+	storeStaleLock   = 2 * time.Minute
+)
+
+// Store buckets. Keys within a bucket are free-form; values are JSON.
+const (
+	BucketTags             = "tags"              // "<kind>/<name>" -> []string
+	BucketSiteHealth       = "site-health"       // "<site>" -> SiteHealthResult
+	BucketProvisioned      = "provisioned"       // "<setup script>" -> ProvisionRecord
+	BucketGeneratedConfigs = "generated-configs" // "<path>" -> GeneratedConfig
+	BucketKeySources       = "key-sources"       // "<user>/<provider>/<account>" -> KeySource
+	BucketExecutions       = "executions"        // "<start time>" -> ExecutionRecord
+)
+
+// storeMigration upgrades the store from version-1 to version
+type storeMigration struct {
+	version int
+	name    string
+	up      func(tx *StoreTx) error
+}
+
+// storeMigrations run in order the first time a newer ravact opens the
+// store. Append new ones; never edit or reorder released migrations.
+var storeMigrations = []storeMigration{
+	{1, "import tags.yaml", migrateLegacyTags},
+}
+
+// storeMu serialises store access within this process; the lock file
+// covers other ravact processes on the host
+var storeMu sync.Mutex
+
+// storeData is the on-disk layout of the store
+type storeData struct {
+	Schema    int                                   `json:"schema"`
+	UpdatedAt time.Time                             `json:"updated_at"`
+	Buckets   map[string]map[string]json.RawMessage `json:"buckets"`
+}
+
+// StorePath returns the location of the metadata store
+func StorePath() string {
+	return filepath.Join(StateDir, StoreFile)
+}
+
+// StoreTx is a view of the store inside ViewStore or UpdateStore
+type StoreTx struct {
+	data     *storeData
+	writable bool
+	changed  bool
+}
+
+// Get decodes a value into v, reporting whether the key exists
+func (tx *StoreTx) Get(bucket, key string, v any) (bool, error) {
+	raw, ok := tx.data.Buckets[bucket][key]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("failed to decode %s/%s: %w", bucket, key, err)
+	}
+	return true, nil
+}
+
+// Put stores a value under a key
+func (tx *StoreTx) Put(bucket, key string, v any) error {
+	if !tx.writable {
+		return fmt.Errorf("store is read-only in this transaction")
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s/%s: %w", bucket, key, err)
+	}
+	if tx.data.Buckets[bucket] == nil {
+		tx.data.Buckets[bucket] = map[string]json.RawMessage{}
+	}
+	tx.data.Buckets[bucket][key] = raw
+	tx.changed = true
+	return nil
+}
+
+// Delete removes a key; deleting a missing key is not an error
+func (tx *StoreTx) Delete(bucket, key string) error {
+	if !tx.writable {
+		return fmt.Errorf("store is read-only in this transaction")
+	}
+	if _, ok := tx.data.Buckets[bucket][key]; ok {
+		delete(tx.data.Buckets[bucket], key)
+		tx.changed = true
+	}
+	return nil
+}
+
+// Keys returns the keys of a bucket, sorted
+func (tx *StoreTx) Keys(bucket string) []string {
+	keys := make([]string, 0, len(tx.data.Buckets[bucket]))
+	for k := range tx.data.Buckets[bucket] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ViewStore runs fn against a consistent snapshot of the store
+func ViewStore(fn func(tx *StoreTx) error) error {
+	storeMu.Lock()
+	data, err := loadStore()
+	storeMu.Unlock()
+	if err != nil {
+		return err
+	}
+	if data.Schema < len(storeMigrations) {
+		// Migrations write, so run them under the update lock first. Without
+		// write access (ravact not run as root) migrate a copy in memory.
+		if err := UpdateStore(func(tx *StoreTx) error {
+			data = tx.data
+			return nil
+		}); err != nil {
+			if data, err = loadStore(); err != nil {
+				return err
+			}
+			if err := migrateStore(&StoreTx{data: data, writable: true}); err != nil {
+				return err
+			}
+		}
+	}
+	return fn(&StoreTx{data: data})
+}
+
+// UpdateStore runs fn with the store locked against other ravact processes
+// and writes the result atomically if fn succeeds and changed anything
+func UpdateStore(fn func(tx *StoreTx) error) error {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	if err := MkdirAll(StateDir, 0750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", StateDir, err)
+	}
+	unlock, err := lockStore()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := loadStore()
+	if err != nil {
+		return err
+	}
+	tx := &StoreTx{data: data, writable: true}
+	if err := migrateStore(tx); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if !tx.changed {
+		return nil
+	}
+	return saveStore(data)
+}
+
+// loadStore reads the store, returning an empty one if none exists yet
+func loadStore() (*storeData, error) {
+	data := &storeData{}
+	raw, err := ReadFile(StorePath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read metadata store: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(raw, data); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", StorePath(), err)
+		}
+	}
+	if data.Schema > len(storeMigrations) {
+		return nil, fmt.Errorf("%s has schema %d from a newer ravact; this build supports up to %d",
+			StorePath(), data.Schema, len(storeMigrations))
+	}
+	if data.Buckets == nil {
+		data.Buckets = map[string]map[string]json.RawMessage{}
+	}
+	return data, nil
+}
+
+// saveStore writes the store to a temporary file and renames it into
+// place, so readers never see a partial write
+func saveStore(data *storeData) error {
+	data.UpdatedAt = time.Now().UTC()
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata store: %w", err)
+	}
+	tmp := StorePath() + ".tmp"
+	if err := WriteFile(tmp, raw, 0640); err != nil {
+		return fmt.Errorf("failed to write metadata store: %w", err)
+	}
+	if err := Rename(tmp, StorePath()); err != nil {
+		return fmt.Errorf("failed to replace metadata store: %w", err)
+	}
+	return nil
+}
+
+// migrateStore runs the migrations newer than the store's schema
+func migrateStore(tx *StoreTx) error {
+	for _, m := range storeMigrations[tx.data.Schema:] {
+		if err := m.up(tx); err != nil {
+			return fmt.Errorf("metadata store migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+		tx.data.Schema = m.version
+		tx.changed = true
+	}
+	return nil
+}
+
+// lockStore takes the store's lock file. Creating a symlink is atomic and
+// fails if it exists on every transport, local or over SSH.
+func lockStore() (func(), error) {
+	lock := StorePath() + ".lock"
+	deadline := time.Now().Add(storeLockTimeout)
+	for {
+		err := Symlink(strconv.Itoa(os.Getpid()), lock)
+		if err == nil {
+			return func() { Remove(lock) }, nil
+		}
+		if info, statErr := Lstat(lock); statErr == nil && time.Since(info.ModTime()) > storeStaleLock {
+			Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("metadata store is locked by another ravact process (%s)", lock)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
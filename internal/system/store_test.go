@@ -0,0 +1,65 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	original, originalTags := StateDir, TagStorePath
+	StateDir = filepath.Join(t.TempDir(), "state")
+	TagStorePath = filepath.Join(t.TempDir(), "tags.yaml")
+	defer func() { StateDir, TagStorePath = original, originalTags }()
+
+	// The first open imports tags.yaml
+	if err := os.WriteFile(TagStorePath, []byte("tags:\n  sites:\n    shop: [production]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var tags []string
+	if err := ViewStore(func(tx *StoreTx) error {
+		_, err := tx.Get(BucketTags, "sites/shop", &tags)
+		return err
+	}); err != nil {
+		t.Fatalf("ViewStore: %v", err)
+	}
+	if !reflect.DeepEqual(tags, []string{"production"}) {
+		t.Errorf("legacy tags not migrated, got %v", tags)
+	}
+
+	// Concurrent updates are serialised, none lost
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := UpdateStore(func(tx *StoreTx) error {
+				return tx.Put("test", string(rune('a'+i)), i)
+			}); err != nil {
+				t.Errorf("UpdateStore: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	ViewStore(func(tx *StoreTx) error {
+		if n := len(tx.Keys("test")); n != 20 {
+			t.Errorf("expected 20 keys, got %d", n)
+		}
+		if err := tx.Put("test", "x", 1); err == nil {
+			t.Error("expected writes to fail in a view")
+		}
+		return nil
+	})
+	if _, err := os.Lstat(StorePath() + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind: %v", err)
+	}
+
+	// A store written by a newer ravact is refused rather than downgraded
+	os.WriteFile(StorePath(), []byte(`{"schema": 999}`), 0640)
+	if err := ViewStore(func(*StoreTx) error { return nil }); err == nil || !strings.Contains(err.Error(), "newer ravact") {
+		t.Errorf("expected newer schema to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// SudoersDir holds the sudoers fragments ravact installs
+var SudoersDir = "/etc/sudoers.d"
+
+// sudoCommandsHeader marks the fragments ravact manages
+const sudoCommandsHeader = "# Managed by ravact: passwordless commands for %s\n"
+
+// SudoCommandSuggestions are common commands a deploy user runs with sudo
+var SudoCommandSuggestions = []string{
+	"/usr/bin/systemctl reload nginx",
+	"/usr/bin/systemctl restart nginx",
+	"/usr/bin/systemctl reload php8.3-fpm",
+	"/usr/bin/systemctl restart php8.3-fpm",
+	"/usr/bin/supervisorctl restart all",
+	"/usr/bin/supervisorctl reread",
+	"/usr/bin/supervisorctl update",
+}
+
+// SudoCommandsPath returns the fragment holding a user's passwordless
+// commands. sudo skips fragments with a dot in the name, so dots in the
+// username are replaced.
+func SudoCommandsPath(username string) string {
+	return path.Join(SudoersDir, "ravact-"+strings.ReplaceAll(username, ".", "_"))
+}
+
+// ValidateSudoCommand checks a command can go in a sudoers rule: an
+// absolute program path, optionally with arguments
+func ValidateSudoCommand(command string) error {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return fmt.Errorf("command cannot be empty")
+	}
+	if strings.ContainsAny(command, "\n\r") {
+		return fmt.Errorf("command must be on one line")
+	}
+	if !strings.HasPrefix(fields[0], "/") {
+		return fmt.Errorf("command must start with an absolute path, e.g. /usr/bin/systemctl")
+	}
+	if path.Base(fields[0]) == "sudoedit" {
+		return fmt.Errorf("sudoedit cannot be granted as a command")
+	}
+	return nil
+}
+
+// escapeSudoersCommand escapes the characters sudoers treats specially in
+// command arguments
+func escapeSudoersCommand(command string) string {
+	r := strings.NewReplacer(`\`, `\\`, `,`, `\,`, `:`, `\:`, `=`, `\=`)
+	return strings.Join(strings.Fields(r.Replace(command)), " ")
+}
+
+// unescapeSudoersCommand reverses escapeSudoersCommand
+func unescapeSudoersCommand(command string) string {
+	r := strings.NewReplacer(`\\`, `\`, `\,`, `,`, `\:`, `:`, `\=`, `=`)
+	return r.Replace(command)
+}
+
+// RenderSudoCommands renders a fragment letting a user run each command as
+// root without a password
+func RenderSudoCommands(username string, commands []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, sudoCommandsHeader, username)
+	for _, c := range commands {
+		fmt.Fprintf(&b, "%s ALL=(root) NOPASSWD: %s\n", username, escapeSudoersCommand(c))
+	}
+	return b.String()
+}
+
+// parseSudoCommands reads the commands back from a fragment ravact rendered
+func parseSudoCommands(username, content string) []string {
+	var commands []string
+	prefix := username + " ALL=(root) NOPASSWD: "
+	for _, line := range strings.Split(content, "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), prefix); ok {
+			commands = append(commands, unescapeSudoersCommand(rest))
+		}
+	}
+	return commands
+}
+
+// GetSudoCommands returns the commands a user may run without a password
+func (um *UserManager) GetSudoCommands(username string) ([]string, error) {
+	data, err := ReadFile(SudoCommandsPath(username))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sudoers fragment: %w", err)
+	}
+	return parseSudoCommands(username, string(data)), nil
+}
+
+// SetSudoCommands installs the fragment for a user's passwordless commands,
+// or removes it when there are none
+func (um *UserManager) SetSudoCommands(username string, commands []string) error {
+	for _, c := range commands {
+		if err := ValidateSudoCommand(c); err != nil {
+			return fmt.Errorf("%q: %w", c, err)
+		}
+	}
+	target := SudoCommandsPath(username)
+	if len(commands) == 0 {
+		if err := Remove(target); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove sudoers fragment: %w", err)
+		}
+		return nil
+	}
+	return installSudoersFragment(target, RenderSudoCommands(username, commands))
+}
+
+// HasSudoNoPassword reports whether a user has passwordless sudo for all
+// commands
+func (um *UserManager) HasSudoNoPassword(username string) bool {
+	_, err := Stat(path.Join(SudoersDir, username))
+	return err == nil
+}
+
+// installSudoersFragment checks a fragment with visudo before moving it
+// into place, so a broken rule never locks everyone out of sudo. The
+// temporary name has a dot, which sudo ignores while it is checked.
+func installSudoersFragment(target, content string) error {
+	tmp := path.Join(path.Dir(target), "."+path.Base(target)+".tmp")
+	if err := WriteFile(tmp, []byte(content), 0440); err != nil {
+		return fmt.Errorf("failed to write sudoers fragment: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	output, err := CommandContext(ctx, "visudo", "-c", "-f", tmp).CombinedOutput()
+	if err != nil {
+		Remove(tmp)
+		return fmt.Errorf("sudoers validation failed: %v - %s", err, strings.TrimSpace(string(output)))
+	}
+	if err := Rename(tmp, target); err != nil {
+		Remove(tmp)
+		return fmt.Errorf("failed to install sudoers fragment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSudoCommandsRoundTrip(t *testing.T) {
+	commands := []string{
+		"/usr/bin/systemctl reload nginx",
+		"/usr/bin/env FOO=bar,baz /usr/local/bin/deploy",
+	}
+	content := RenderSudoCommands("deploy", commands)
+	if !strings.HasPrefix(content, "# Managed by ravact") {
+		t.Errorf("fragment should start with the managed header:\n%s", content)
+	}
+	if !strings.Contains(content, `deploy ALL=(root) NOPASSWD: /usr/bin/env FOO\=bar\,baz /usr/local/bin/deploy`) {
+		t.Errorf("special characters should be escaped:\n%s", content)
+	}
+
+	got := parseSudoCommands("deploy", content)
+	if len(got) != 2 || got[0] != commands[0] || got[1] != commands[1] {
+		t.Errorf("round trip changed commands: %q", got)
+	}
+	if other := parseSudoCommands("someone", content); len(other) != 0 {
+		t.Errorf("rules for another user should be ignored, got %q", other)
+	}
+}
+
+func TestValidateSudoCommand(t *testing.T) {
+	valid := []string{"/usr/bin/systemctl reload nginx", "/usr/bin/supervisorctl"}
+	for _, c := range valid {
+		if err := ValidateSudoCommand(c); err != nil {
+			t.Errorf("expected %q to be valid: %v", c, err)
+		}
+	}
+	invalid := []string{"", "systemctl reload nginx", "ALL", "/usr/bin/sudoedit /etc/hosts", "/bin/true\n/bin/false"}
+	for _, c := range invalid {
+		if err := ValidateSudoCommand(c); err == nil {
+			t.Errorf("expected %q to be rejected", c)
+		}
+	}
+}
+
+func TestSudoCommandsPath(t *testing.T) {
+	if got := SudoCommandsPath("first.last"); got != "/etc/sudoers.d/ravact-first_last" {
+		t.Errorf("dots must not reach the fragment name, got %s", got)
+	}
+}
+
+func TestSetSudoCommandsRemovesEmptyFragment(t *testing.T) {
+	orig := SudoersDir
+	SudoersDir = t.TempDir()
+	defer func() { SudoersDir = orig }()
+
+	um := NewUserManager()
+	path := SudoCommandsPath("deploy")
+	if err := os.WriteFile(path, []byte(RenderSudoCommands("deploy", []string{"/usr/bin/true"})), 0440); err != nil {
+		t.Fatal(err)
+	}
+	commands, err := um.GetSudoCommands("deploy")
+	if err != nil || len(commands) != 1 {
+		t.Fatalf("expected one command, got %q (%v)", commands, err)
+	}
+
+	if err := um.SetSudoCommands("deploy", nil); err != nil {
+		t.Fatalf("SetSudoCommands failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("fragment should be removed when no commands remain")
+	}
+	if err := um.SetSudoCommands("deploy", []string{"relative"}); err == nil {
+		t.Error("invalid commands should be rejected before anything is written")
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 0 {
+		t.Errorf("nothing should be left behind, found %d entries", len(entries))
+	}
+}
@@ -3,7 +3,6 @@ package system
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -18,6 +17,7 @@ type SupervisorProgram struct {
 	Directory  string
 	User       string
 	AutoStart  bool
+	Group      string // Supervisor group the program belongs to, if any
 }
 
 // SupervisorXMLRPCConfig represents XML-RPC server configuration
@@ -45,7 +45,7 @@ func NewSupervisorManager() *SupervisorManager {
 	
 	configPath := "/etc/supervisor/supervisord.conf" // Default
 	for _, path := range configPaths {
-		if _, err := os.Stat(path); err == nil {
+		if _, err := Stat(path); err == nil {
 			configPath = path
 			break
 		}
@@ -59,7 +59,7 @@ func NewSupervisorManager() *SupervisorManager {
 
 // GetAllPrograms returns all Supervisor programs
 func (sm *SupervisorManager) GetAllPrograms() ([]SupervisorProgram, error) {
-	entries, err := os.ReadDir(sm.programsDir)
+	entries, err := ReadDir(sm.programsDir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return []SupervisorProgram{}, nil
@@ -67,6 +67,7 @@ func (sm *SupervisorManager) GetAllPrograms() ([]SupervisorProgram, error) {
 		return nil, err
 	}
 
+	groups := sm.programGroups()
 	var programs []SupervisorProgram
 	for _, entry := range entries {
 		if entry.IsDir() {
@@ -74,8 +75,8 @@ func (sm *SupervisorManager) GetAllPrograms() ([]SupervisorProgram, error) {
 		}
 
 		name := entry.Name()
-		// Only process .conf files
-		if !strings.HasSuffix(name, ".conf") {
+		// Only process .conf files; group files are listed by GetGroups
+		if !strings.HasSuffix(name, ".conf") || strings.HasSuffix(name, supervisorGroupSuffix) {
 			continue
 		}
 
@@ -85,20 +86,20 @@ func (sm *SupervisorManager) GetAllPrograms() ([]SupervisorProgram, error) {
 		// Parse config to get details
 		command, directory, user, autostart := sm.parseConfig(configPath)
 		
-		// Get state from supervisorctl
-		state := sm.getProgramState(programName)
-
 		program := SupervisorProgram{
 			Name:       programName,
 			ConfigPath: configPath,
 			IsEnabled:  true, // If file exists, it's enabled
-			State:      state,
 			Command:    command,
 			Directory:  directory,
 			User:       user,
 			AutoStart:  autostart,
+			Group:      groups[programName],
 		}
 
+		// Get state from supervisorctl
+		program.State = sm.getProgramState(program.ProcessName())
+
 		programs = append(programs, program)
 	}
 
@@ -107,7 +108,7 @@ func (sm *SupervisorManager) GetAllPrograms() ([]SupervisorProgram, error) {
 
 // parseConfig extracts basic info from supervisor config
 func (sm *SupervisorManager) parseConfig(configPath string) (command, directory, user string, autostart bool) {
-	data, err := os.ReadFile(configPath)
+	data, err := ReadFile(configPath)
 	if err != nil {
 		return "", "", "", false
 	}
@@ -132,7 +133,7 @@ func (sm *SupervisorManager) parseConfig(configPath string) (command, directory,
 
 // getProgramState gets the state of a program from supervisorctl
 func (sm *SupervisorManager) getProgramState(programName string) string {
-	cmd := exec.Command("supervisorctl", "status", programName)
+	cmd := Command("supervisorctl", "status", programName)
 	output, err := cmd.CombinedOutput()
 	
 	if err != nil {
@@ -150,7 +151,7 @@ func (sm *SupervisorManager) getProgramState(programName string) string {
 
 // StartProgram starts a supervisor program
 func (sm *SupervisorManager) StartProgram(programName string) error {
-	cmd := exec.Command("supervisorctl", "start", programName)
+	cmd := Command("supervisorctl", "start", sm.processName(programName))
 	output, err := cmd.CombinedOutput()
 	
 	if err != nil {
@@ -162,7 +163,7 @@ func (sm *SupervisorManager) StartProgram(programName string) error {
 
 // StopProgram stops a supervisor program
 func (sm *SupervisorManager) StopProgram(programName string) error {
-	cmd := exec.Command("supervisorctl", "stop", programName)
+	cmd := Command("supervisorctl", "stop", sm.processName(programName))
 	output, err := cmd.CombinedOutput()
 	
 	if err != nil {
@@ -174,7 +175,7 @@ func (sm *SupervisorManager) StopProgram(programName string) error {
 
 // RestartProgram restarts a supervisor program
 func (sm *SupervisorManager) RestartProgram(programName string) error {
-	cmd := exec.Command("supervisorctl", "restart", programName)
+	cmd := Command("supervisorctl", "restart", sm.processName(programName))
 	output, err := cmd.CombinedOutput()
 	
 	if err != nil {
@@ -191,9 +192,12 @@ func (sm *SupervisorManager) DeleteProgram(programName string) error {
 	
 	// Delete config file
 	configPath := filepath.Join(sm.programsDir, programName+".conf")
-	if err := os.Remove(configPath); err != nil {
+	if err := Remove(configPath); err != nil {
 		return fmt.Errorf("failed to delete: %w", err)
 	}
+	if err := sm.removeFromGroups(programName); err != nil {
+		return err
+	}
 	
 	// Reload supervisor
 	return sm.Reread()
@@ -204,7 +208,7 @@ func (sm *SupervisorManager) CreateProgram(name, command, directory, user string
 	configPath := filepath.Join(sm.programsDir, name+".conf")
 
 	// Check if already exists
-	if _, err := os.Stat(configPath); err == nil {
+	if _, err := Stat(configPath); err == nil {
 		return fmt.Errorf("program already exists: %s", name)
 	}
 
@@ -222,7 +226,7 @@ stdout_logfile_backups=10
 `, name, command, directory, user, autostart, name)
 
 	// Write config file
-	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+	if err := WriteFile(configPath, []byte(config), 0644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
@@ -232,7 +236,7 @@ stdout_logfile_backups=10
 
 // Reread tells supervisor to reload configuration
 func (sm *SupervisorManager) Reread() error {
-	cmd := exec.Command("supervisorctl", "reread")
+	cmd := Command("supervisorctl", "reread")
 	output, err := cmd.CombinedOutput()
 	
 	if err != nil {
@@ -240,7 +244,7 @@ func (sm *SupervisorManager) Reread() error {
 	}
 	
 	// Update
-	cmd = exec.Command("supervisorctl", "update")
+	cmd = Command("supervisorctl", "update")
 	output, err = cmd.CombinedOutput()
 	
 	if err != nil {
@@ -252,7 +256,7 @@ func (sm *SupervisorManager) Reread() error {
 
 // GetXMLRPCConfig gets the XML-RPC server configuration
 func (sm *SupervisorManager) GetXMLRPCConfig() (*SupervisorXMLRPCConfig, error) {
-	data, err := os.ReadFile(sm.configPath)
+	data, err := ReadFile(sm.configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
@@ -302,7 +306,7 @@ func (sm *SupervisorManager) GetXMLRPCConfig() (*SupervisorXMLRPCConfig, error)
 
 // SetXMLRPCConfig configures the XML-RPC server
 func (sm *SupervisorManager) SetXMLRPCConfig(ip, port, username, password string) error {
-	data, err := os.ReadFile(sm.configPath)
+	data, err := ReadFile(sm.configPath)
 	if err != nil {
 		return fmt.Errorf("failed to read config: %w", err)
 	}
@@ -359,7 +363,7 @@ func (sm *SupervisorManager) SetXMLRPCConfig(ip, port, username, password string
 
 	// Write back
 	newConfig := strings.Join(newLines, "\n")
-	if err := os.WriteFile(sm.configPath, []byte(newConfig), 0644); err != nil {
+	if err := WriteFile(sm.configPath, []byte(newConfig), 0644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
@@ -369,7 +373,7 @@ func (sm *SupervisorManager) SetXMLRPCConfig(ip, port, username, password string
 
 // RestartSupervisor restarts the supervisor service
 func (sm *SupervisorManager) RestartSupervisor() error {
-	cmd := exec.Command("systemctl", "restart", "supervisor")
+	cmd := Command("systemctl", "restart", "supervisor")
 	output, err := cmd.CombinedOutput()
 	
 	if err != nil {
@@ -384,15 +388,15 @@ func (sm *SupervisorManager) UpdateProgram(name, command, directory, user string
 	configPath := filepath.Join(sm.programsDir, name+".conf")
 
 	// Check if exists
-	if _, err := os.Stat(configPath); err != nil {
+	if _, err := Stat(configPath); err != nil {
 		return fmt.Errorf("program not found: %s", name)
 	}
 
 	// Backup old config
 	backupPath := configPath + ".bak"
-	oldData, _ := os.ReadFile(configPath)
+	oldData, _ := ReadFile(configPath)
 	if oldData != nil {
-		os.WriteFile(backupPath, oldData, 0644)
+		WriteFile(backupPath, oldData, 0644)
 	}
 
 	// Generate new config
@@ -408,11 +412,23 @@ stdout_logfile_maxbytes=10MB
 stdout_logfile_backups=10
 `, name, command, directory, user, autostart, name)
 
+	// Keep log rotation set from the program's log actions
+	if oldData != nil {
+		old := supervisorOptions(string(oldData), "program:"+name)
+		keep := map[string]string{}
+		for _, key := range []string{"stdout_logfile_maxbytes", "stdout_logfile_backups"} {
+			if value, ok := old[key]; ok {
+				keep[key] = value
+			}
+		}
+		config = setSupervisorOptions(config, "program:"+name, nil, keep)
+	}
+
 	// Write config file
-	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+	if err := WriteFile(configPath, []byte(config), 0644); err != nil {
 		// Restore backup on failure
 		if oldData != nil {
-			os.WriteFile(configPath, oldData, 0644)
+			WriteFile(configPath, oldData, 0644)
 		}
 		return fmt.Errorf("failed to write config: %w", err)
 	}
@@ -424,7 +440,7 @@ stdout_logfile_backups=10
 // GetProgramConfig reads a program's full configuration
 func (sm *SupervisorManager) GetProgramConfig(programName string) (string, error) {
 	configPath := filepath.Join(sm.programsDir, programName+".conf")
-	data, err := os.ReadFile(configPath)
+	data, err := ReadFile(configPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to read config: %w", err)
 	}
@@ -433,13 +449,13 @@ func (sm *SupervisorManager) GetProgramConfig(programName string) (string, error
 
 // IsInstalled checks if Supervisor is installed
 func (sm *SupervisorManager) IsInstalled() bool {
-	cmd := exec.Command("which", "supervisorctl")
+	cmd := Command("which", "supervisorctl")
 	return cmd.Run() == nil
 }
 
 // GetStatus returns the Supervisor service status
 func (sm *SupervisorManager) GetStatus() (string, error) {
-	cmd := exec.Command("systemctl", "status", "supervisor")
+	cmd := Command("systemctl", "status", "supervisor")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return string(output), nil
@@ -449,7 +465,7 @@ func (sm *SupervisorManager) GetStatus() (string, error) {
 
 // DisableXMLRPC disables the XML-RPC server
 func (sm *SupervisorManager) DisableXMLRPC() error {
-	data, err := os.ReadFile(sm.configPath)
+	data, err := ReadFile(sm.configPath)
 	if err != nil {
 		return fmt.Errorf("failed to read config: %w", err)
 	}
@@ -482,7 +498,7 @@ func (sm *SupervisorManager) DisableXMLRPC() error {
 
 	// Write back
 	newConfig := strings.Join(newLines, "\n")
-	if err := os.WriteFile(sm.configPath, []byte(newConfig), 0644); err != nil {
+	if err := WriteFile(sm.configPath, []byte(newConfig), 0644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
@@ -0,0 +1,207 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// supervisorGroupSuffix marks a conf.d file that defines a group rather
+// than a program
+const supervisorGroupSuffix = ".group.conf"
+
+// supervisorNamePattern matches names supervisor accepts for groups
+var supervisorNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// SupervisorGroup is a set of programs started, stopped, and restarted
+// together. supervisorctl addresses a grouped program as group:program.
+type SupervisorGroup struct {
+	Name       string
+	Programs   []string
+	ConfigPath string
+}
+
+// ProcessName returns the name supervisorctl uses for the program
+func (p SupervisorProgram) ProcessName() string {
+	if p.Group != "" {
+		return p.Group + ":" + p.Name
+	}
+	return p.Name
+}
+
+// groupConfig returns the conf.d file for a group
+func groupConfig(name string, programs []string) string {
+	return fmt.Sprintf("[group:%s]\nprograms=%s\n", name, strings.Join(programs, ","))
+}
+
+// parseGroupPrograms returns the programs listed in a group file
+func parseGroupPrograms(name, content string) []string {
+	var programs []string
+	for _, p := range strings.Split(supervisorOptions(content, "group:"+name)["programs"], ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			programs = append(programs, p)
+		}
+	}
+	return programs
+}
+
+// GetGroups returns the groups ravact manages, sorted by name
+func (sm *SupervisorManager) GetGroups() ([]SupervisorGroup, error) {
+	entries, err := ReadDir(sm.programsDir)
+	if err != nil {
+		return nil, nil
+	}
+	var groups []SupervisorGroup
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), supervisorGroupSuffix) {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), supervisorGroupSuffix)
+		configPath := filepath.Join(sm.programsDir, entry.Name())
+		data, err := ReadFile(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", configPath, err)
+		}
+		groups = append(groups, SupervisorGroup{Name: name, Programs: parseGroupPrograms(name, string(data)), ConfigPath: configPath})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, nil
+}
+
+// programGroups maps each grouped program to its group
+func (sm *SupervisorManager) programGroups() map[string]string {
+	membership := map[string]string{}
+	groups, _ := sm.GetGroups()
+	for _, g := range groups {
+		for _, p := range g.Programs {
+			membership[p] = g.Name
+		}
+	}
+	return membership
+}
+
+// processName returns the supervisorctl name of a program
+func (sm *SupervisorManager) processName(program string) string {
+	if group := sm.programGroups()[program]; group != "" {
+		return group + ":" + program
+	}
+	return program
+}
+
+// writeGroup writes a group's file, or removes it when it has no programs
+func (sm *SupervisorManager) writeGroup(name string, programs []string) error {
+	configPath := filepath.Join(sm.programsDir, name+supervisorGroupSuffix)
+	if len(programs) == 0 {
+		if err := Remove(configPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove group %s: %w", name, err)
+		}
+		return nil
+	}
+	if err := WriteFile(configPath, []byte(groupConfig(name, programs)), 0644); err != nil {
+		return fmt.Errorf("failed to write group %s: %w", name, err)
+	}
+	return nil
+}
+
+// SaveGroup creates or replaces a group. A program belongs to at most one
+// group, so programs are taken out of any other group first. Supervisor
+// restarts the programs whose group changed.
+func (sm *SupervisorManager) SaveGroup(name string, programs []string) error {
+	if !supervisorNamePattern.MatchString(name) {
+		return fmt.Errorf("group name may only contain letters, digits, '.', '_' and '-'")
+	}
+	if len(programs) == 0 {
+		return fmt.Errorf("select at least one program")
+	}
+	existing, err := sm.GetAllPrograms()
+	if err != nil {
+		return err
+	}
+	known := map[string]bool{}
+	for _, p := range existing {
+		known[p.Name] = true
+		if p.Name == name {
+			return fmt.Errorf("a program is already named %s", name)
+		}
+	}
+	selected := map[string]bool{}
+	for _, p := range programs {
+		if !known[p] {
+			return fmt.Errorf("program not found: %s", p)
+		}
+		selected[p] = true
+	}
+
+	groups, err := sm.GetGroups()
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		if g.Name == name {
+			continue
+		}
+		var kept []string
+		for _, p := range g.Programs {
+			if !selected[p] {
+				kept = append(kept, p)
+			}
+		}
+		if len(kept) != len(g.Programs) {
+			if err := sm.writeGroup(g.Name, kept); err != nil {
+				return err
+			}
+		}
+	}
+	if err := sm.writeGroup(name, programs); err != nil {
+		return err
+	}
+	return sm.Reread()
+}
+
+// DeleteGroup removes a group; its programs run on their own again
+func (sm *SupervisorManager) DeleteGroup(name string) error {
+	if err := sm.writeGroup(name, nil); err != nil {
+		return err
+	}
+	return sm.Reread()
+}
+
+// removeFromGroups takes a deleted program out of its group so supervisor
+// does not reject the group on the next reread
+func (sm *SupervisorManager) removeFromGroups(program string) error {
+	groups, err := sm.GetGroups()
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		var kept []string
+		for _, p := range g.Programs {
+			if p != program {
+				kept = append(kept, p)
+			}
+		}
+		if len(kept) != len(g.Programs) {
+			if err := sm.writeGroup(g.Name, kept); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GroupAction runs start, stop, or restart on every program in a group
+func (sm *SupervisorManager) GroupAction(action, name string) error {
+	switch action {
+	case "start", "stop", "restart":
+	default:
+		return fmt.Errorf("unsupported group action %q", action)
+	}
+	output, err := Command("supervisorctl", action, name+":*").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to %s %s: %s", action, name, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSupervisorGroupFiles(t *testing.T) {
+	sm := &SupervisorManager{programsDir: t.TempDir()}
+	for _, name := range []string{"shop-queue", "shop-horizon", "blog-queue"} {
+		if err := os.WriteFile(filepath.Join(sm.programsDir, name+".conf"), []byte("[program:"+name+"]\ncommand=true\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sm.writeGroup("shop", []string{"shop-queue", "shop-horizon"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.writeGroup("blog", []string{"blog-queue"}); err != nil {
+		t.Fatal(err)
+	}
+
+	groups, err := sm.GetGroups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 || groups[0].Name != "blog" || !reflect.DeepEqual(groups[1].Programs, []string{"shop-queue", "shop-horizon"}) {
+		t.Fatalf("unexpected groups %+v", groups)
+	}
+	if got := sm.processName("shop-horizon"); got != "shop:shop-horizon" {
+		t.Errorf("processName = %q", got)
+	}
+	if got := (SupervisorProgram{Name: "blog-queue"}).ProcessName(); got != "blog-queue" {
+		t.Errorf("ProcessName without a group = %q", got)
+	}
+
+	programs, err := sm.GetAllPrograms()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(programs) != 3 {
+		t.Errorf("expected group files to be skipped, got %d programs", len(programs))
+	}
+
+	// Removing the last program of a group removes the group
+	if err := sm.removeFromGroups("blog-queue"); err != nil {
+		t.Fatal(err)
+	}
+	if groups, _ := sm.GetGroups(); len(groups) != 1 || groups[0].Name != "shop" {
+		t.Errorf("expected only the shop group to remain, got %+v", groups)
+	}
+}
@@ -0,0 +1,233 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SupervisorChildLogDir is where supervisord puts AUTO program logs
+var SupervisorChildLogDir = "/var/log/supervisor"
+
+// Supervisor's defaults for program log rotation
+const (
+	supervisorDefaultMaxBytes = "50MB"
+	supervisorDefaultBackups  = 10
+)
+
+// supervisorBytesPattern matches supervisor byte sizes such as 10MB or 0
+var supervisorBytesPattern = regexp.MustCompile(`^[0-9]+(KB|MB|GB)?$`)
+
+// SupervisorLogConfig is where a program logs and how its logs rotate
+type SupervisorLogConfig struct {
+	StdoutLogfile  string // AUTO when not set
+	StderrLogfile  string // AUTO when not set
+	RedirectStderr bool   // stderr is written to the stdout log
+	MaxBytes       string // Size at which a log rotates; 0 never rotates
+	Backups        int    // Rotated logs kept
+}
+
+// ValidateSupervisorBytes checks a size such as 10MB for a supervisor option
+func ValidateSupervisorBytes(s string) error {
+	if !supervisorBytesPattern.MatchString(strings.ToUpper(strings.TrimSpace(s))) {
+		return fmt.Errorf("enter a size such as 10MB, 500KB, or 0 for no rotation")
+	}
+	return nil
+}
+
+// supervisorOptions returns the key=value options of an ini section
+func supervisorOptions(content, section string) map[string]string {
+	options := map[string]string{}
+	in := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			in = trimmed == "["+section+"]"
+			continue
+		}
+		if !in || trimmed == "" || strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(trimmed, "="); ok {
+			options[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return options
+}
+
+// setSupervisorOptions sets options in an ini section, replacing existing
+// values in place and adding new ones at the end of the section
+func setSupervisorOptions(content, section string, keys []string, values map[string]string) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	set := map[string]bool{}
+	var out []string
+	in := false
+	flush := func() {
+		for _, key := range keys {
+			if !set[key] {
+				out = append(out, key+"="+values[key])
+				set[key] = true
+			}
+		}
+	}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			if in {
+				// Keep blank lines between sections after the new options
+				blank := 0
+				for len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+					out = out[:len(out)-1]
+					blank++
+				}
+				flush()
+				for ; blank > 0; blank-- {
+					out = append(out, "")
+				}
+			}
+			in = trimmed == "["+section+"]"
+		} else if in {
+			if key, _, ok := strings.Cut(trimmed, "="); ok {
+				key = strings.TrimSpace(key)
+				if value, ok := values[key]; ok {
+					out = append(out, key+"="+value)
+					set[key] = true
+					continue
+				}
+			}
+		}
+		out = append(out, line)
+	}
+	if in {
+		flush()
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// parseSupervisorLogConfig reads a program's log options, filling in
+// supervisor's defaults
+func parseSupervisorLogConfig(name, content string) SupervisorLogConfig {
+	options := supervisorOptions(content, "program:"+name)
+	c := SupervisorLogConfig{
+		StdoutLogfile:  options["stdout_logfile"],
+		StderrLogfile:  options["stderr_logfile"],
+		RedirectStderr: strings.EqualFold(options["redirect_stderr"], "true"),
+		MaxBytes:       options["stdout_logfile_maxbytes"],
+		Backups:        supervisorDefaultBackups,
+	}
+	if c.StdoutLogfile == "" {
+		c.StdoutLogfile = "AUTO"
+	}
+	if c.StderrLogfile == "" {
+		c.StderrLogfile = "AUTO"
+	}
+	if c.MaxBytes == "" {
+		c.MaxBytes = supervisorDefaultMaxBytes
+	}
+	if backups, err := strconv.Atoi(options["stdout_logfile_backups"]); err == nil {
+		c.Backups = backups
+	}
+	return c
+}
+
+// ProgramLogConfig returns a program's log files and rotation settings
+func (sm *SupervisorManager) ProgramLogConfig(name string) (SupervisorLogConfig, error) {
+	content, err := sm.GetProgramConfig(name)
+	if err != nil {
+		return SupervisorLogConfig{}, err
+	}
+	return parseSupervisorLogConfig(name, content), nil
+}
+
+// ProgramLogFile returns the file a program writes stream ("stdout" or
+// "stderr") to, finding the newest AUTO log supervisord created
+func (sm *SupervisorManager) ProgramLogFile(name, stream string) (string, error) {
+	c, err := sm.ProgramLogConfig(name)
+	if err != nil {
+		return "", err
+	}
+	file := c.StdoutLogfile
+	if stream == "stderr" {
+		if c.RedirectStderr {
+			return "", fmt.Errorf("%s redirects stderr to its stdout log", name)
+		}
+		file = c.StderrLogfile
+	}
+	file = strings.ReplaceAll(file, "%(program_name)s", name)
+
+	switch {
+	case strings.EqualFold(file, "NONE"):
+		return "", fmt.Errorf("%s does not log %s", name, stream)
+	case strings.Contains(file, "%("):
+		return "", fmt.Errorf("%s log path %s depends on the process; open it from the log viewer", stream, file)
+	case !strings.EqualFold(file, "AUTO"):
+		return file, nil
+	}
+
+	entries, err := ReadDir(SupervisorChildLogDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to list %s: %w", SupervisorChildLogDir, err)
+	}
+	prefix := name + "-" + stream + "---supervisor-"
+	var newest string
+	var newestTime int64
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && (newest == "" || info.ModTime().UnixNano() > newestTime) {
+			newest, newestTime = entry.Name(), info.ModTime().UnixNano()
+		}
+	}
+	if newest == "" {
+		return "", fmt.Errorf("no %s log found for %s; has it started since supervisord last restarted?", stream, name)
+	}
+	return filepath.Join(SupervisorChildLogDir, newest), nil
+}
+
+// SetProgramLogRotation sets the size at which a program's logs rotate and
+// how many rotated logs are kept. Supervisor restarts the program to apply it.
+func (sm *SupervisorManager) SetProgramLogRotation(name, maxBytes string, backups int) error {
+	maxBytes = strings.ToUpper(strings.TrimSpace(maxBytes))
+	if err := ValidateSupervisorBytes(maxBytes); err != nil {
+		return err
+	}
+	if backups < 0 {
+		return fmt.Errorf("backups cannot be negative")
+	}
+	configPath := filepath.Join(sm.programsDir, name+".conf")
+	content, err := ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	c := parseSupervisorLogConfig(name, string(content))
+	keys := []string{"stdout_logfile_maxbytes", "stdout_logfile_backups"}
+	if !c.RedirectStderr {
+		keys = append(keys, "stderr_logfile_maxbytes", "stderr_logfile_backups")
+	}
+	values := map[string]string{}
+	for _, key := range keys {
+		if strings.HasSuffix(key, "_maxbytes") {
+			values[key] = maxBytes
+		} else {
+			values[key] = strconv.Itoa(backups)
+		}
+	}
+	updated := setSupervisorOptions(string(content), "program:"+name, keys, values)
+	if err := WriteFile(configPath, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return sm.Reread()
+}
+
+// ClearProgramLogs empties a program's stdout and stderr logs
+func (sm *SupervisorManager) ClearProgramLogs(name string) error {
+	output, err := Command("supervisorctl", "clear", sm.processName(name)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to clear logs: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testSupervisorProgram = `[program:queue]
+command=php artisan queue:work
+stdout_logfile=/var/log/supervisor/%(program_name)s.log
+stdout_logfile_maxbytes=10MB
+
+[group:site]
+programs=queue
+`
+
+func TestParseSupervisorLogConfig(t *testing.T) {
+	c := parseSupervisorLogConfig("queue", testSupervisorProgram)
+	want := SupervisorLogConfig{
+		StdoutLogfile: "/var/log/supervisor/%(program_name)s.log",
+		StderrLogfile: "AUTO",
+		MaxBytes:      "10MB",
+		Backups:       supervisorDefaultBackups,
+	}
+	if c != want {
+		t.Errorf("got %+v, want %+v", c, want)
+	}
+}
+
+func TestSetSupervisorOptions(t *testing.T) {
+	keys := []string{"stdout_logfile_maxbytes", "stdout_logfile_backups"}
+	got := setSupervisorOptions(testSupervisorProgram, "program:queue", keys,
+		map[string]string{"stdout_logfile_maxbytes": "5MB", "stdout_logfile_backups": "3"})
+	want := `[program:queue]
+command=php artisan queue:work
+stdout_logfile=/var/log/supervisor/%(program_name)s.log
+stdout_logfile_maxbytes=5MB
+stdout_logfile_backups=3
+
+[group:site]
+programs=queue
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Only existing options are replaced when no keys are required
+	got = setSupervisorOptions(testSupervisorProgram, "program:queue", nil, map[string]string{"stdout_logfile_backups": "3", "command": "true"})
+	if opts := supervisorOptions(got, "program:queue"); opts["command"] != "true" || opts["stdout_logfile_backups"] != "" {
+		t.Errorf("unexpected options %v", opts)
+	}
+}
+
+func TestValidateSupervisorBytes(t *testing.T) {
+	for _, s := range []string{"0", "10MB", "500kb", "1GB"} {
+		if err := ValidateSupervisorBytes(s); err != nil {
+			t.Errorf("expected %q to be valid: %v", s, err)
+		}
+	}
+	for _, s := range []string{"", "10 MB", "-1", "1TB", "ten"} {
+		if err := ValidateSupervisorBytes(s); err == nil {
+			t.Errorf("expected %q to be rejected", s)
+		}
+	}
+}
+
+func TestProgramLogFile(t *testing.T) {
+	sm := &SupervisorManager{programsDir: t.TempDir()}
+	origDir := SupervisorChildLogDir
+	SupervisorChildLogDir = t.TempDir()
+	defer func() { SupervisorChildLogDir = origDir }()
+
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(sm.programsDir, "queue.conf"), testSupervisorProgram)
+	write(filepath.Join(sm.programsDir, "auto.conf"), "[program:auto]\ncommand=sleep 1\n")
+
+	if got, err := sm.ProgramLogFile("queue", "stdout"); err != nil || got != "/var/log/supervisor/queue.log" {
+		t.Errorf("got %q, %v", got, err)
+	}
+
+	older := filepath.Join(SupervisorChildLogDir, "auto-stderr---supervisor-aaaa.log")
+	newer := filepath.Join(SupervisorChildLogDir, "auto-stderr---supervisor-bbbb.log")
+	write(older, "")
+	write(newer, "")
+	os.Chtimes(older, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour))
+	if got, err := sm.ProgramLogFile("auto", "stderr"); err != nil || got != newer {
+		t.Errorf("got %q, %v; want %q", got, err, newer)
+	}
+	if _, err := sm.ProgramLogFile("auto", "stdout"); err == nil {
+		t.Error("expected an error when no AUTO log exists yet")
+	}
+}
@@ -0,0 +1,206 @@
+package system
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SwapFilePath is the swap file the swap setup creates
+const SwapFilePath = "/swapfile"
+
+// SwapSysctlPath holds the swappiness and cache pressure the swap setup sets
+const SwapSysctlPath = "/etc/sysctl.d/99-ravact-swap.conf"
+
+// minSwapMB is the smallest swap file offered
+const minSwapMB = 256
+
+// swapDiskReserveMB is left free on the root filesystem after the swap file
+const swapDiskReserveMB = 1024
+
+// SwapSettings are the size of the swap file and the kernel's swap tuning
+type SwapSettings struct {
+	SizeMB        int
+	Swappiness    int // vm.swappiness, 0-100; low values swap only under pressure
+	CachePressure int // vm.vfs_cache_pressure; below 100 keeps directory caches longer
+}
+
+// SwapDevice is an active swap area from /proc/swaps
+type SwapDevice struct {
+	Path string
+	Type string // "file" or "partition"
+	Size uint64
+	Used uint64
+}
+
+// SwapState is the host's swap and memory as the swap screen shows it
+type SwapState struct {
+	Devices       []SwapDevice
+	MemTotal      uint64
+	Swappiness    int
+	CachePressure int
+	DiskFree      uint64 // Free space on the root filesystem
+	FileSize      uint64 // Size of SwapFilePath, 0 when it does not exist
+	Tuned         bool   // SwapSysctlPath exists
+}
+
+// Settings returns the settings in effect, with the defaults for anything
+// ravact has not set
+func (s SwapState) Settings() SwapSettings {
+	settings := DefaultSwapSettings(s.MemTotal)
+	if s.FileSize > 0 {
+		settings.SizeMB = int(s.FileSize / (1024 * 1024))
+	}
+	if s.Tuned {
+		settings.Swappiness = s.Swappiness
+		settings.CachePressure = s.CachePressure
+	}
+	return settings
+}
+
+// RecommendedSwapMB suggests a swap size for the memory: twice the RAM up
+// to 2 GB, the RAM size up to 8 GB, and 4 GB above that
+func RecommendedSwapMB(memTotal uint64) int {
+	mb := int(memTotal / (1024 * 1024))
+	switch {
+	case mb <= 2048:
+		return max(mb*2, 1024)
+	case mb <= 8192:
+		return mb
+	}
+	return 4096
+}
+
+// DefaultSwapSettings are offered when ravact has not configured swap yet.
+// A low swappiness suits servers: swap catches spikes such as composer
+// installs instead of paging out PHP-FPM workers.
+func DefaultSwapSettings(memTotal uint64) SwapSettings {
+	return SwapSettings{SizeMB: RecommendedSwapMB(memTotal), Swappiness: 10, CachePressure: 50}
+}
+
+// ParseSwapSize reads a size such as 2G, 512M, or 1536 (megabytes)
+func ParseSwapSize(s string) (int, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(s, "G"):
+		multiplier = 1024
+		s = strings.TrimSuffix(s, "G")
+	case strings.HasSuffix(s, "M"):
+		s = strings.TrimSuffix(s, "M")
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("enter a size such as 2G or 512M")
+	}
+	return n * multiplier, nil
+}
+
+// FormatSwapSize writes a size in megabytes the way ParseSwapSize reads it
+func FormatSwapSize(mb int) string {
+	if mb%1024 == 0 {
+		return fmt.Sprintf("%dG", mb/1024)
+	}
+	return fmt.Sprintf("%dM", mb)
+}
+
+// Validate checks the settings against the space the swap file can use
+func (s SwapSettings) Validate(state SwapState) error {
+	if s.SizeMB < minSwapMB {
+		return fmt.Errorf("swap must be at least %dM", minSwapMB)
+	}
+	// The existing file is replaced, so its space is available too
+	availableMB := int((state.DiskFree+state.FileSize)/(1024*1024)) - swapDiskReserveMB
+	if state.DiskFree > 0 && s.SizeMB > availableMB {
+		return fmt.Errorf("only %s fits while leaving 1G free on /", FormatSwapSize(max(availableMB, 0)))
+	}
+	if s.Swappiness < 0 || s.Swappiness > 100 {
+		return fmt.Errorf("swappiness must be between 0 and 100")
+	}
+	if s.CachePressure < 1 || s.CachePressure > 1000 {
+		return fmt.Errorf("cache pressure must be between 1 and 1000")
+	}
+	return nil
+}
+
+// Command returns the setup command running the swap script with the settings
+func (s SwapSettings) Command() string {
+	return fmt.Sprintf("SWAP_SIZE_MB=%d SWAP_SWAPPINESS=%d SWAP_CACHE_PRESSURE=%d assets/scripts/swap.sh",
+		s.SizeMB, s.Swappiness, s.CachePressure)
+}
+
+// parseProcSwaps reads /proc/swaps, whose sizes are in kilobytes
+func parseProcSwaps(data string) []SwapDevice {
+	var devices []SwapDevice
+	for i, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 4 {
+			continue
+		}
+		size, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		used, _ := strconv.ParseUint(fields[3], 10, 64)
+		devices = append(devices, SwapDevice{Path: fields[0], Type: fields[1], Size: size * 1024, Used: used * 1024})
+	}
+	return devices
+}
+
+// readSysctlInt reads an integer kernel setting under /proc/sys
+func readSysctlInt(path string) int {
+	data, err := ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return n
+}
+
+// SwapActive reports whether any swap is in use on the active host
+func SwapActive() bool {
+	data, err := ReadFile("/proc/swaps")
+	return err == nil && len(parseProcSwaps(string(data))) > 0
+}
+
+// CurrentSwap reads the active swap, memory, and swap tuning
+func CurrentSwap() (SwapState, error) {
+	data, err := ReadFile("/proc/swaps")
+	if err != nil {
+		return SwapState{}, fmt.Errorf("failed to read /proc/swaps: %w", err)
+	}
+	state := SwapState{
+		Devices:       parseProcSwaps(string(data)),
+		Swappiness:    readSysctlInt("/proc/sys/vm/swappiness"),
+		CachePressure: readSysctlInt("/proc/sys/vm/vfs_cache_pressure"),
+	}
+	if meminfo, err := ReadFile("/proc/meminfo"); err == nil {
+		state.MemTotal, _, _, _ = parseMemInfo(string(meminfo))
+	}
+	if output, err := Command("df", "-B1", "--output=size,used", "/").Output(); err == nil {
+		if total, used, err := parseDfUsage(string(output)); err == nil && used < total {
+			state.DiskFree = total - used
+		}
+	}
+	if info, err := Stat(SwapFilePath); err == nil {
+		state.FileSize = uint64(info.Size())
+	}
+	if _, err := Stat(SwapSysctlPath); err == nil {
+		state.Tuned = true
+	}
+	return state, nil
+}
+
+// SwapRemoveScript turns off and deletes the swap file, its fstab entry,
+// and the tuning file
+func SwapRemoveScript() string {
+	path := SwapFilePath
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	fmt.Fprintf(&b, "if grep -q '^%s ' /proc/swaps; then swapoff %s; fi\n", path, path)
+	fmt.Fprintf(&b, "sed -i '\\|^%s[[:space:]]|d' /etc/fstab\n", path)
+	fmt.Fprintf(&b, "rm -f %s %s\n", path, SwapSysctlPath)
+	fmt.Fprintf(&b, "echo '✓ Removed %s'\n", path)
+	b.WriteString("echo 'Swappiness and cache pressure return to the defaults at the next reboot'\n")
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSwapSize(t *testing.T) {
+	cases := map[string]int{"2G": 2048, "512m": 512, " 1536 ": 1536}
+	for input, want := range cases {
+		if got, err := ParseSwapSize(input); err != nil || got != want {
+			t.Errorf("ParseSwapSize(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "2T", "-1G", "0"} {
+		if _, err := ParseSwapSize(input); err == nil {
+			t.Errorf("ParseSwapSize(%q): expected an error", input)
+		}
+	}
+	if got := FormatSwapSize(2048); got != "2G" {
+		t.Errorf("FormatSwapSize(2048) = %q", got)
+	}
+	if got := FormatSwapSize(1536); got != "1536M" {
+		t.Errorf("FormatSwapSize(1536) = %q", got)
+	}
+}
+
+func TestRecommendedSwapMB(t *testing.T) {
+	const mb = 1024 * 1024
+	cases := map[uint64]int{
+		256 * mb:   1024,
+		1024 * mb:  2048,
+		4096 * mb:  4096,
+		16384 * mb: 4096,
+	}
+	for mem, want := range cases {
+		if got := RecommendedSwapMB(mem); got != want {
+			t.Errorf("RecommendedSwapMB(%d MB) = %d, want %d", mem/mb, got, want)
+		}
+	}
+}
+
+func TestSwapSettingsValidate(t *testing.T) {
+	const mb = 1024 * 1024
+	state := SwapState{DiskFree: 3072 * mb, FileSize: 1024 * mb}
+	if err := (SwapSettings{SizeMB: 3072, Swappiness: 10, CachePressure: 50}).Validate(state); err != nil {
+		t.Errorf("the replaced file's space should count as free: %v", err)
+	}
+	if err := (SwapSettings{SizeMB: 4096, Swappiness: 10, CachePressure: 50}).Validate(state); err == nil || !strings.Contains(err.Error(), "3G") {
+		t.Errorf("expected an error naming the 3G that fits, got %v", err)
+	}
+	if err := (SwapSettings{SizeMB: 128, Swappiness: 10, CachePressure: 50}).Validate(state); err == nil {
+		t.Error("expected an error for a tiny swap file")
+	}
+	if err := (SwapSettings{SizeMB: 1024, Swappiness: 101, CachePressure: 50}).Validate(state); err == nil {
+		t.Error("expected an error for swappiness above 100")
+	}
+}
+
+func TestParseProcSwaps(t *testing.T) {
+	data := "Filename\t\t\t\tType\t\tSize\t\tUsed\t\tPriority\n" +
+		"/swapfile                               file\t\t2097148\t\t524288\t\t-2\n" +
+		"/dev/zram0                              partition\t1015804\t\t0\t\t100\n"
+	devices := parseProcSwaps(data)
+	if len(devices) != 2 {
+		t.Fatalf("expected 2 devices, got %+v", devices)
+	}
+	if devices[0] != (SwapDevice{Path: "/swapfile", Type: "file", Size: 2097148 * 1024, Used: 524288 * 1024}) {
+		t.Errorf("unexpected device: %+v", devices[0])
+	}
+	if got := parseProcSwaps("Filename\tType\tSize\tUsed\tPriority\n"); len(got) != 0 {
+		t.Errorf("expected no devices, got %+v", got)
+	}
+}
@@ -5,15 +5,16 @@ import (
 	"bytes"
 	"fmt"
 	"net"
-	"os"
-	"os/exec"
-	"runtime"
 	"strconv"
 	"strings"
 
 	"github.com/iperamuna/ravact/internal/models"
 )
 
+// WebGroup is the group nginx and PHP-FPM run as. Site files are group-owned
+// by it so the web server can read them; set from the application settings.
+var WebGroup = "www-data"
+
 // Detector provides system detection capabilities
 type Detector struct{}
 
@@ -25,20 +26,21 @@ func NewDetector() *Detector {
 // GetSystemInfo retrieves comprehensive system information
 func (d *Detector) GetSystemInfo() (*models.SystemInfo, error) {
 	info := &models.SystemInfo{
-		OS:       runtime.GOOS,
-		Arch:     runtime.GOARCH,
-		CPUCount: runtime.NumCPU(),
+		OS:       HostOS(),
+		Arch:     HostArch(),
+		CPUCount: HostNumCPU(),
 		IsRoot:   d.IsRoot(),
 	}
+	info.Privilege = DetectPrivilegeMethod().String()
 
 	// Get hostname
-	hostname, err := os.Hostname()
+	hostname, err := HostName()
 	if err == nil {
 		info.Hostname = hostname
 	}
 
 	// Get distribution info (Linux only)
-	if runtime.GOOS == "linux" {
+	if HostOS() == "linux" {
 		if err := d.detectLinuxDistribution(info); err != nil {
 			// Non-fatal, continue
 		}
@@ -64,18 +66,18 @@ func (d *Detector) GetSystemInfo() (*models.SystemInfo, error) {
 
 // IsRoot checks if the current process is running as root
 func (d *Detector) IsRoot() bool {
-	return os.Geteuid() == 0
+	return HostIsRoot()
 }
 
 // detectLinuxDistribution detects the Linux distribution
 func (d *Detector) detectLinuxDistribution(info *models.SystemInfo) error {
 	// Try /etc/os-release first (modern standard)
-	if data, err := os.ReadFile("/etc/os-release"); err == nil {
+	if data, err := ReadFile("/etc/os-release"); err == nil {
 		return d.parseOSRelease(data, info)
 	}
 
 	// Try /etc/lsb-release
-	if data, err := os.ReadFile("/etc/lsb-release"); err == nil {
+	if data, err := ReadFile("/etc/lsb-release"); err == nil {
 		return d.parseLSBRelease(data, info)
 	}
 
@@ -88,9 +90,9 @@ func (d *Detector) detectLinuxDistribution(info *models.SystemInfo) error {
 	}
 
 	for file, distro := range distros {
-		if _, err := os.Stat(file); err == nil {
+		if _, err := Stat(file); err == nil {
 			info.Distribution = distro
-			if data, err := os.ReadFile(file); err == nil {
+			if data, err := ReadFile(file); err == nil {
 				info.Version = strings.TrimSpace(string(data))
 			}
 			return nil
@@ -130,7 +132,7 @@ func (d *Detector) parseLSBRelease(data []byte, info *models.SystemInfo) error {
 
 // getKernelVersion gets the kernel version
 func (d *Detector) getKernelVersion() (string, error) {
-	cmd := exec.Command("uname", "-r")
+	cmd := Command("uname", "-r")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -140,9 +142,9 @@ func (d *Detector) getKernelVersion() (string, error) {
 
 // getTotalRAM gets total system RAM in bytes
 func (d *Detector) getTotalRAM() (uint64, error) {
-	if runtime.GOOS == "linux" {
+	if HostOS() == "linux" {
 		return d.getTotalRAMLinux()
-	} else if runtime.GOOS == "darwin" {
+	} else if HostOS() == "darwin" {
 		return d.getTotalRAMMacOS()
 	}
 	return 0, fmt.Errorf("unsupported platform for RAM detection")
@@ -150,7 +152,7 @@ func (d *Detector) getTotalRAM() (uint64, error) {
 
 // getTotalRAMLinux gets RAM on Linux
 func (d *Detector) getTotalRAMLinux() (uint64, error) {
-	data, err := os.ReadFile("/proc/meminfo")
+	data, err := ReadFile("/proc/meminfo")
 	if err != nil {
 		return 0, err
 	}
@@ -174,7 +176,7 @@ func (d *Detector) getTotalRAMLinux() (uint64, error) {
 
 // getTotalRAMMacOS gets RAM on macOS
 func (d *Detector) getTotalRAMMacOS() (uint64, error) {
-	cmd := exec.Command("sysctl", "-n", "hw.memsize")
+	cmd := Command("sysctl", "-n", "hw.memsize")
 	output, err := cmd.Output()
 	if err != nil {
 		return 0, err
@@ -190,9 +192,9 @@ func (d *Detector) getTotalRAMMacOS() (uint64, error) {
 
 // getTotalDisk gets total disk space in bytes
 func (d *Detector) getTotalDisk() (uint64, error) {
-	if runtime.GOOS == "linux" {
+	if HostOS() == "linux" {
 		return d.getTotalDiskLinux()
-	} else if runtime.GOOS == "darwin" {
+	} else if HostOS() == "darwin" {
 		return d.getTotalDiskMacOS()
 	}
 	return 0, fmt.Errorf("unsupported platform for disk detection")
@@ -200,7 +202,7 @@ func (d *Detector) getTotalDisk() (uint64, error) {
 
 // getTotalDiskLinux gets disk space on Linux
 func (d *Detector) getTotalDiskLinux() (uint64, error) {
-	cmd := exec.Command("df", "-B1", "/")
+	cmd := Command("df", "-B1", "/")
 	output, err := cmd.Output()
 	if err != nil {
 		return 0, err
@@ -226,7 +228,7 @@ func (d *Detector) getTotalDiskLinux() (uint64, error) {
 
 // getTotalDiskMacOS gets disk space on macOS
 func (d *Detector) getTotalDiskMacOS() (uint64, error) {
-	cmd := exec.Command("df", "-k", "/")
+	cmd := Command("df", "-k", "/")
 	output, err := cmd.Output()
 	if err != nil {
 		return 0, err
@@ -254,6 +256,11 @@ func (d *Detector) getTotalDiskMacOS() (uint64, error) {
 
 // IsServiceInstalled checks if a service/package is installed
 func (d *Detector) IsServiceInstalled(serviceName string) (bool, error) {
+	// Swap counts as installed while any swap area is active
+	if serviceName == "swap" {
+		return SwapActive(), nil
+	}
+
 	// For tools that don't run as services, check binary directly
 	binaryOnlyTools := map[string][]string{
 		"certbot": {"certbot"},
@@ -264,7 +271,7 @@ func (d *Detector) IsServiceInstalled(serviceName string) (bool, error) {
 
 	if binaries, isBinaryOnly := binaryOnlyTools[serviceName]; isBinaryOnly {
 		for _, binary := range binaries {
-			cmd := exec.Command("which", binary)
+			cmd := Command("which", binary)
 			if err := cmd.Run(); err == nil {
 				return true, nil
 			}
@@ -273,14 +280,14 @@ func (d *Detector) IsServiceInstalled(serviceName string) (bool, error) {
 	}
 
 	// Try systemctl first for services
-	cmd := exec.Command("systemctl", "list-unit-files", serviceName+".service")
+	cmd := Command("systemctl", "list-unit-files", serviceName+".service")
 	output, err := cmd.Output()
 	if err == nil && strings.Contains(string(output), serviceName) {
 		return true, nil
 	}
 
 	// Try which command as fallback
-	cmd = exec.Command("which", serviceName)
+	cmd = Command("which", serviceName)
 	err = cmd.Run()
 	return err == nil, nil
 }
@@ -302,6 +309,7 @@ func (d *Detector) GetServiceStatus(serviceName string) (models.ServiceStatus, e
 		"git":     true,
 		"node":    true,
 		"ufw":     true,
+		"swap":    true,
 	}
 
 	if binaryOnlyTools[serviceName] {
@@ -309,7 +317,7 @@ func (d *Detector) GetServiceStatus(serviceName string) (models.ServiceStatus, e
 	}
 
 	// Check if running via systemctl
-	cmd := exec.Command("systemctl", "is-active", serviceName)
+	cmd := Command("systemctl", "is-active", serviceName)
 	output, err := cmd.Output()
 	status := strings.TrimSpace(string(output))
 
@@ -327,7 +335,7 @@ func (d *Detector) GetServiceStatus(serviceName string) (models.ServiceStatus, e
 
 // GetRecommendedWorkerProcesses returns recommended nginx worker processes
 func (d *Detector) GetRecommendedWorkerProcesses() int {
-	return runtime.NumCPU()
+	return HostNumCPU()
 }
 
 // GetRecommendedWorkerConnections returns recommended nginx worker connections based on RAM
@@ -360,7 +368,7 @@ func FormatBytes(bytes uint64) string {
 
 // GetHostInfo returns hostname with IP in format "hostname (ip)" or just "hostname"
 func GetHostInfo() string {
-	hostname, err := os.Hostname()
+	hostname, err := HostName()
 	if err != nil {
 		return ""
 	}
@@ -375,7 +383,7 @@ func GetHostInfo() string {
 // GetPrimaryIP returns the primary IP address of the system
 func GetPrimaryIP() string {
 	// Try to get IP from hostname command first (most reliable for primary IP)
-	cmd := exec.Command("hostname", "-I")
+	cmd := Command("hostname", "-I")
 	output, err := cmd.Output()
 	if err == nil {
 		ips := strings.Fields(strings.TrimSpace(string(output)))
@@ -385,7 +393,7 @@ func GetPrimaryIP() string {
 	}
 
 	// Fallback: try ip command on Linux
-	cmd = exec.Command("ip", "-4", "addr", "show", "scope", "global")
+	cmd = Command("ip", "-4", "addr", "show", "scope", "global")
 	output, err = cmd.Output()
 	if err == nil {
 		lines := strings.Split(string(output), "\n")
@@ -403,8 +411,8 @@ func GetPrimaryIP() string {
 	}
 
 	// Fallback for macOS: use ifconfig
-	if runtime.GOOS == "darwin" {
-		cmd = exec.Command("ipconfig", "getifaddr", "en0")
+	if HostOS() == "darwin" {
+		cmd = Command("ipconfig", "getifaddr", "en0")
 		output, err = cmd.Output()
 		if err == nil {
 			return strings.TrimSpace(string(output))
@@ -0,0 +1,223 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SystemdPatternsPath is where the unit patterns shown on the systemd
+// services screen are kept
+var SystemdPatternsPath = "/etc/ravact/systemd.yaml"
+
+// DefaultSystemdPatterns are the units managed when no patterns are saved
+var DefaultSystemdPatterns = []string{
+	"nginx.service",
+	"php*-fpm.service",
+	"mysql.service",
+	"mariadb.service",
+	"postgresql*.service",
+	"redis-server.service",
+	"supervisor.service",
+	"frankenphp-*.service",
+}
+
+// SystemdLocalUnitDir holds administrator unit files, which take
+// precedence over the vendor copies in /lib/systemd/system
+const SystemdLocalUnitDir = "/etc/systemd/system"
+
+// SystemdUnit is a service unit and its current state
+type SystemdUnit struct {
+	Name        string // Including the .service suffix
+	Description string
+	Active      string // active, inactive, failed, ...
+	Sub         string // running, dead, exited, ...
+	UnitState   string // enabled, disabled, static, masked, ...
+}
+
+// Enabled reports whether the unit starts on boot
+func (u SystemdUnit) Enabled() bool {
+	return u.UnitState == "enabled" || u.UnitState == "enabled-runtime"
+}
+
+// systemdPatternsFile is the on-disk format of SystemdPatternsPath
+type systemdPatternsFile struct {
+	Patterns []string `yaml:"patterns"`
+}
+
+// LoadSystemdPatterns returns the saved unit patterns, or the defaults
+func LoadSystemdPatterns() ([]string, error) {
+	data, err := ReadFile(SystemdPatternsPath)
+	if os.IsNotExist(err) {
+		return append([]string(nil), DefaultSystemdPatterns...), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read unit patterns: %w", err)
+	}
+	var file systemdPatternsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", SystemdPatternsPath, err)
+	}
+	if len(file.Patterns) == 0 {
+		return append([]string(nil), DefaultSystemdPatterns...), nil
+	}
+	return file.Patterns, nil
+}
+
+// SaveSystemdPatterns stores the unit patterns
+func SaveSystemdPatterns(patterns []string) error {
+	data, err := yaml.Marshal(systemdPatternsFile{Patterns: patterns})
+	if err != nil {
+		return fmt.Errorf("failed to encode unit patterns: %w", err)
+	}
+	if err := MkdirAll(filepath.Dir(SystemdPatternsPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(SystemdPatternsPath), err)
+	}
+	if err := WriteFile(SystemdPatternsPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", SystemdPatternsPath, err)
+	}
+	return nil
+}
+
+// ParseSystemdPatterns splits user input into unit patterns, adding the
+// .service suffix where it is missing
+func ParseSystemdPatterns(input string) []string {
+	var patterns []string
+	seen := map[string]bool{}
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n'
+	}) {
+		if !strings.HasSuffix(field, ".service") {
+			field += ".service"
+		}
+		if !seen[field] {
+			seen[field] = true
+			patterns = append(patterns, field)
+		}
+	}
+	return patterns
+}
+
+// ListSystemdUnits returns the installed service units matching patterns
+func ListSystemdUnits(patterns []string) ([]SystemdUnit, error) {
+	args := append([]string{"list-unit-files", "--type=service", "--no-legend", "--no-pager"}, patterns...)
+	files, err := Command("systemctl", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unit files: %w", err)
+	}
+	args = append([]string{"list-units", "--type=service", "--all", "--no-legend", "--plain", "--no-pager"}, patterns...)
+	// Units that are not loaded are simply reported inactive
+	states, _ := Command("systemctl", args...).Output()
+
+	return parseSystemdUnits(string(files), string(states)), nil
+}
+
+// parseSystemdUnits merges `systemctl list-unit-files` and `systemctl
+// list-units` output. Template units (name@.service) are skipped since
+// only their instances can be started.
+func parseSystemdUnits(unitFiles, unitStates string) []SystemdUnit {
+	units := map[string]*SystemdUnit{}
+	for _, line := range strings.Split(unitFiles, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasSuffix(fields[0], "@.service") {
+			continue
+		}
+		units[fields[0]] = &SystemdUnit{Name: fields[0], UnitState: fields[1], Active: "inactive", Sub: "dead"}
+	}
+
+	for _, line := range strings.Split(unitStates, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[1] == "not-found" {
+			continue
+		}
+		unit, ok := units[fields[0]]
+		if !ok {
+			// Running instances of template units
+			unit = &SystemdUnit{Name: fields[0], UnitState: "instance"}
+			units[fields[0]] = unit
+		}
+		unit.Active = fields[2]
+		unit.Sub = fields[3]
+		unit.Description = strings.Join(fields[4:], " ")
+	}
+
+	result := make([]SystemdUnit, 0, len(units))
+	for _, unit := range units {
+		result = append(result, *unit)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// SystemctlCommand returns the shell command and description for a
+// systemctl action on a unit, as run from the execution screen
+func SystemctlCommand(action, unit string) (command, description string) {
+	switch action {
+	case "start", "restart", "reload":
+		verb := map[string]string{"start": "Starting", "restart": "Restarting", "reload": "Reloading"}[action]
+		return fmt.Sprintf("sudo systemctl %s %s && sudo systemctl status %s --no-pager -l", action, unit, unit),
+			fmt.Sprintf("%s %s", verb, unit)
+	case "stop":
+		return fmt.Sprintf("sudo systemctl stop %s && echo '✓ Service stopped'", unit), fmt.Sprintf("Stopping %s", unit)
+	case "enable":
+		return fmt.Sprintf("sudo systemctl enable %s && echo '✓ Service enabled'", unit), fmt.Sprintf("Enabling %s", unit)
+	case "disable":
+		return fmt.Sprintf("sudo systemctl disable %s && echo '✓ Service disabled'", unit), fmt.Sprintf("Disabling %s", unit)
+	case "status":
+		return fmt.Sprintf("sudo systemctl status %s --no-pager -l", unit), fmt.Sprintf("Status of %s", unit)
+	}
+	return fmt.Sprintf("sudo systemctl %s %s", action, unit), fmt.Sprintf("systemctl %s %s", action, unit)
+}
+
+// SystemdUnitPath returns the file a unit was loaded from
+func SystemdUnitPath(unit string) (string, error) {
+	output, err := Command("systemctl", "show", "-p", "FragmentPath", unit).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate %s: %w", unit, err)
+	}
+	path := strings.TrimPrefix(strings.TrimSpace(string(output)), "FragmentPath=")
+	if path == "" {
+		return "", fmt.Errorf("%s has no unit file", unit)
+	}
+	return path, nil
+}
+
+// ReadSystemdUnit returns the unit file content and the path it should be
+// saved to. Vendor units are saved as a full copy in SystemdLocalUnitDir,
+// like `systemctl edit --full`, so package upgrades do not overwrite them.
+func ReadSystemdUnit(unit string) (content, savePath string, err error) {
+	path, err := SystemdUnitPath(unit)
+	if err != nil {
+		return "", "", err
+	}
+	data, err := ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	savePath = path
+	if filepath.Dir(path) != SystemdLocalUnitDir {
+		savePath = filepath.Join(SystemdLocalUnitDir, filepath.Base(path))
+	}
+	return string(data), savePath, nil
+}
+
+// SaveSystemdUnit writes a unit file, keeping a .bak of the previous
+// version, and reloads systemd
+func SaveSystemdUnit(path, content string) error {
+	if existing, err := ReadFile(path); err == nil {
+		if err := WriteFile(path+".bak", existing, 0644); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+	if err := WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if output, err := Command("systemctl", "daemon-reload").CombinedOutput(); err != nil {
+		return fmt.Errorf("daemon-reload failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package system
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSystemdUnits(t *testing.T) {
+	files := `nginx.service          enabled  enabled
+php8.3-fpm.service     disabled enabled
+laravel-queue@.service static   -
+`
+	states := `nginx.service loaded active running A high performance web server
+php8.3-fpm.service loaded failed failed The PHP 8.3 FastCGI Process Manager
+laravel-queue@1.service loaded active running Queue worker 1
+missing.service not-found inactive dead missing.service
+`
+	units := parseSystemdUnits(files, states)
+	if len(units) != 3 {
+		t.Fatalf("expected 3 units, got %+v", units)
+	}
+
+	nginx := units[1]
+	if nginx.Name != "nginx.service" || !nginx.Enabled() || nginx.Active != "active" || nginx.Description != "A high performance web server" {
+		t.Errorf("unexpected nginx unit %+v", nginx)
+	}
+	php := units[2]
+	if php.Enabled() || php.Active != "failed" {
+		t.Errorf("unexpected php unit %+v", php)
+	}
+	if units[0].Name != "laravel-queue@1.service" || units[0].UnitState != "instance" {
+		t.Errorf("expected template instance to be listed, got %+v", units[0])
+	}
+}
+
+func TestParseSystemdPatterns(t *testing.T) {
+	got := ParseSystemdPatterns("nginx, php*-fpm.service redis-server nginx")
+	want := []string{"nginx.service", "php*-fpm.service", "redis-server.service"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSystemdPatternsRoundTrip(t *testing.T) {
+	original := SystemdPatternsPath
+	SystemdPatternsPath = filepath.Join(t.TempDir(), "ravact", "systemd.yaml")
+	defer func() { SystemdPatternsPath = original }()
+
+	patterns, err := LoadSystemdPatterns()
+	if err != nil || !reflect.DeepEqual(patterns, DefaultSystemdPatterns) {
+		t.Fatalf("expected defaults, got %v (%v)", patterns, err)
+	}
+	if err := SaveSystemdPatterns([]string{"caddy.service"}); err != nil {
+		t.Fatalf("SaveSystemdPatterns: %v", err)
+	}
+	if patterns, err := LoadSystemdPatterns(); err != nil || !reflect.DeepEqual(patterns, []string{"caddy.service"}) {
+		t.Errorf("got %v (%v)", patterns, err)
+	}
+}
+
+func TestWorkerUnit(t *testing.T) {
+	if err := ValidateWorkerName("shop-queue"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	for _, name := range []string{"", "shop queue", "shop@1", "../etc"} {
+		if ValidateWorkerName(name) == nil {
+			t.Errorf("expected %q to be rejected", name)
+		}
+	}
+	if got := WorkerUnitPath("shop-queue"); got != "/etc/systemd/system/shop-queue@.service" {
+		t.Errorf("WorkerUnitPath = %q", got)
+	}
+	if unit := WorkerUnitTemplate("shop-queue"); !strings.Contains(unit, "Description=shop-queue worker (%i)") {
+		t.Errorf("template missing instance description:\n%s", unit)
+	}
+
+	script := WorkerInstancesScript("shop-queue", 3)
+	for _, want := range []string{
+		"list-units --all --plain --no-legend 'shop-queue@*.service'",
+		`if [ "$n" -gt 3 ]`,
+		"for i in $(seq 1 3); do",
+		"systemctl enable --now shop-queue@$i.service",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+}
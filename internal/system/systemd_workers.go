@@ -0,0 +1,70 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+)
+
+// workerNamePattern matches names usable as a systemd template unit prefix
+var workerNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// ValidateWorkerName checks a worker name for use as name@.service
+func ValidateWorkerName(name string) error {
+	if !workerNamePattern.MatchString(name) {
+		return fmt.Errorf("worker name may only contain letters, digits, '.', '_' and '-'")
+	}
+	return nil
+}
+
+// WorkerUnitPath returns the template unit file for a worker
+func WorkerUnitPath(name string) string {
+	return filepath.Join(SystemdLocalUnitDir, name+"@.service")
+}
+
+// WorkerUnitTemplate returns a starting template unit for a long-running
+// worker. Each instance (name@1, name@2, ...) is one process, the systemd
+// equivalent of a supervisor program's numprocs.
+func WorkerUnitTemplate(name string) string {
+	return fmt.Sprintf(`[Unit]
+Description=%s worker (%%i)
+After=network.target
+
+[Service]
+User=www-data
+Group=www-data
+WorkingDirectory=/path/to/working/directory
+ExecStart=/path/to/your/command
+
+Restart=always
+RestartSec=5s
+KillSignal=SIGTERM
+TimeoutStopSec=60
+
+StandardOutput=journal
+StandardError=journal
+SyslogIdentifier=%s
+
+[Install]
+WantedBy=multi-user.target
+`, name, name)
+}
+
+// WorkerInstancesScript returns a shell script that reloads systemd,
+// enables and starts instances 1..count of a worker template, and stops
+// and disables any higher-numbered instances left from a larger count
+func WorkerInstancesScript(name string, count int) string {
+	return fmt.Sprintf(`set -e
+systemctl daemon-reload
+for unit in $(systemctl list-units --all --plain --no-legend %s | awk '{print $1}'); do
+  n=${unit#%s@}
+  n=${n%%.service}
+  if [ "$n" -gt %d ] 2>/dev/null; then
+    systemctl disable --now "$unit"
+  fi
+done
+for i in $(seq 1 %d); do
+  systemctl enable --now %s@$i.service
+done
+`, ShellQuote(name+"@*.service"), name, count, count, name)
+}
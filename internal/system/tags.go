@@ -0,0 +1,193 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TagStorePath is where older ravact versions kept tags; they are imported
+// into the metadata store
+var TagStorePath = "/etc/ravact/tags.yaml"
+
+// TagKind is the type of resource a tag is attached to
+type TagKind string
+
+const (
+	TagKindSite    TagKind = "sites"
+	TagKindService TagKind = "services"
+	TagKindUser    TagKind = "users"
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._:-]*$`)
+
+// TagStore maps resource names to their tags, per kind
+type TagStore struct {
+	Tags  map[TagKind]map[string][]string `yaml:"tags"`
+	dirty map[string]bool                 // Store keys changed since loading
+}
+
+// tagKey is the metadata store key of a resource's tags
+func tagKey(kind TagKind, name string) string {
+	return string(kind) + "/" + name
+}
+
+// LoadTagStore reads the tags from the metadata store on the current host
+func LoadTagStore() (*TagStore, error) {
+	store := &TagStore{Tags: map[TagKind]map[string][]string{}}
+	err := ViewStore(func(tx *StoreTx) error {
+		for _, key := range tx.Keys(BucketTags) {
+			kind, name, ok := strings.Cut(key, "/")
+			if !ok {
+				continue
+			}
+			var tags []string
+			if _, err := tx.Get(BucketTags, key, &tags); err != nil {
+				return err
+			}
+			if store.Tags[TagKind(kind)] == nil {
+				store.Tags[TagKind(kind)] = map[string][]string{}
+			}
+			store.Tags[TagKind(kind)][name] = tags
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tags: %w", err)
+	}
+	return store, nil
+}
+
+// Save writes the resources changed since loading, leaving tags saved by
+// other ravact sessions in the meantime intact
+func (s *TagStore) Save() error {
+	err := UpdateStore(func(tx *StoreTx) error {
+		for key := range s.dirty {
+			kind, name, _ := strings.Cut(key, "/")
+			tags := s.Tags[TagKind(kind)][name]
+			var err error
+			if len(tags) == 0 {
+				err = tx.Delete(BucketTags, key)
+			} else {
+				err = tx.Put(BucketTags, key, tags)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write tags: %w", err)
+	}
+	s.dirty = nil
+	return nil
+}
+
+// migrateLegacyTags imports tags.yaml into the metadata store. The file is
+// left in place so older ravact builds on the host keep their tags.
+func migrateLegacyTags(tx *StoreTx) error {
+	data, err := ReadFile(TagStorePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var legacy TagStore
+	if err := yaml.Unmarshal(data, &legacy); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", TagStorePath, err)
+	}
+	for kind, resources := range legacy.Tags {
+		for name, tags := range resources {
+			if err := tx.Put(BucketTags, tagKey(kind, name), tags); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ImportLegacyTags merges a tags.yaml, such as one restored from an older
+// settings archive, into the metadata store and renames it to
+// tags.yaml.migrated
+func ImportLegacyTags() error {
+	if _, err := Stat(TagStorePath); os.IsNotExist(err) {
+		return nil
+	}
+	if err := UpdateStore(migrateLegacyTags); err != nil {
+		return fmt.Errorf("failed to import %s: %w", TagStorePath, err)
+	}
+	return Rename(TagStorePath, TagStorePath+".migrated")
+}
+
+// Get returns the tags on a resource
+func (s *TagStore) Get(kind TagKind, name string) []string {
+	return s.Tags[kind][name]
+}
+
+// Set replaces the tags on a resource; an empty list removes the entry
+func (s *TagStore) Set(kind TagKind, name string, tags []string) {
+	if s.dirty == nil {
+		s.dirty = map[string]bool{}
+	}
+	s.dirty[tagKey(kind, name)] = true
+	if len(tags) == 0 {
+		delete(s.Tags[kind], name)
+		return
+	}
+	if s.Tags[kind] == nil {
+		s.Tags[kind] = map[string][]string{}
+	}
+	s.Tags[kind][name] = tags
+}
+
+// Has reports whether a resource carries a tag
+func (s *TagStore) Has(kind TagKind, name, tag string) bool {
+	for _, t := range s.Tags[kind][name] {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// All returns every tag used for a kind of resource, sorted
+func (s *TagStore) All(kind TagKind) []string {
+	seen := map[string]bool{}
+	var all []string
+	for _, tags := range s.Tags[kind] {
+		for _, t := range tags {
+			if !seen[t] {
+				seen[t] = true
+				all = append(all, t)
+			}
+		}
+	}
+	sort.Strings(all)
+	return all
+}
+
+// ParseTags splits a comma or space separated list into normalized tags.
+// Tags are lowercased, a leading # is dropped, and duplicates are removed.
+func ParseTags(input string) ([]string, error) {
+	seen := map[string]bool{}
+	var tags []string
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		tag := strings.ToLower(strings.TrimPrefix(field, "#"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag %q: use letters, numbers, '.', '_', ':' or '-'", tag)
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
@@ -0,0 +1,76 @@
+package system
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tags, err := ParseTags("Client-Acme, #production  staging,production")
+	if err != nil {
+		t.Fatalf("ParseTags: %v", err)
+	}
+	want := []string{"client-acme", "production", "staging"}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("got %v, want %v", tags, want)
+	}
+
+	if tags, err := ParseTags(""); err != nil || len(tags) != 0 {
+		t.Errorf("expected no tags for empty input, got %v (%v)", tags, err)
+	}
+	if _, err := ParseTags("bad/tag"); err == nil {
+		t.Error("expected invalid tag to be rejected")
+	}
+}
+
+func TestTagStoreRoundTrip(t *testing.T) {
+	original, originalState := TagStorePath, StateDir
+	TagStorePath = filepath.Join(t.TempDir(), "ravact", "tags.yaml")
+	StateDir = filepath.Join(t.TempDir(), "state")
+	defer func() { TagStorePath, StateDir = original, originalState }()
+
+	store, err := LoadTagStore()
+	if err != nil {
+		t.Fatalf("LoadTagStore: %v", err)
+	}
+	store.Set(TagKindSite, "shop", []string{"client-acme", "production"})
+	store.Set(TagKindSite, "blog", []string{"client-acme"})
+	store.Set(TagKindService, "frankenphp-shop", []string{"client-acme"})
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := LoadTagStore()
+	if err != nil {
+		t.Fatalf("LoadTagStore: %v", err)
+	}
+	if !loaded.Has(TagKindSite, "shop", "production") {
+		t.Error("expected shop to be tagged production")
+	}
+	if loaded.Has(TagKindUser, "shop", "production") {
+		t.Error("tags must not leak across kinds")
+	}
+	if got := loaded.All(TagKindSite); !reflect.DeepEqual(got, []string{"client-acme", "production"}) {
+		t.Errorf("unexpected site tags %v", got)
+	}
+
+	// Saving only writes what changed, so concurrent sessions don't clobber
+	// each other's tags
+	other, _ := LoadTagStore()
+	loaded.Set(TagKindSite, "shop", nil)
+	other.Set(TagKindUser, "deploy", []string{"ops"})
+	if err := loaded.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := other.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	final, _ := LoadTagStore()
+	if tags := final.Get(TagKindSite, "shop"); len(tags) != 0 {
+		t.Errorf("expected tags to be cleared, got %v", tags)
+	}
+	if !final.Has(TagKindUser, "deploy", "ops") || !final.Has(TagKindSite, "blog", "client-acme") {
+		t.Errorf("concurrent saves lost tags: %+v", final.Tags)
+	}
+}
@@ -0,0 +1,334 @@
+package system
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TasksDir holds the tasks ravact runs detached on the managed host, one
+// directory per task with its script, output, and exit code. The tasks
+// keep running when ravact exits or its ssh connection drops, and ravact
+// re-attaches to them when it is opened again.
+var TasksDir = "/var/lib/ravact/tasks"
+
+// tasksKept is how many finished tasks are kept for review
+const tasksKept = 20
+
+// TaskState is where a detached task is in its life
+type TaskState int
+
+const (
+	TaskRunning TaskState = iota
+	TaskSucceeded
+	TaskFailed
+	// TaskLost means the task stopped without recording an exit code,
+	// e.g. because the server rebooted while it ran
+	TaskLost
+)
+
+// String names the state for the UI
+func (s TaskState) String() string {
+	switch s {
+	case TaskRunning:
+		return "Running"
+	case TaskSucceeded:
+		return "Succeeded"
+	case TaskFailed:
+		return "Failed"
+	}
+	return "Interrupted"
+}
+
+// Task is a command ravact started detached on the managed host
+type Task struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	Command     string    `json:"command"` // What the user asked for, e.g. a setup script path
+	Started     time.Time `json:"started"`
+	Unit        string    `json:"unit,omitempty"` // Transient systemd unit, when systemd-run started it
+}
+
+// TaskStatus is a task with how far it got
+type TaskStatus struct {
+	Task
+	State    TaskState
+	ExitCode int
+	Finished time.Time // When the exit code was recorded
+	Handled  bool      // ravact has recorded the result (history, notifications)
+}
+
+// Done reports whether the task stopped running
+func (s TaskStatus) Done() bool { return s.State != TaskRunning }
+
+// NewTaskID returns a task id that sorts by start time
+func NewTaskID(now time.Time) string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	return now.Format("20060102-150405") + "-" + hex.EncodeToString(b)
+}
+
+// validTaskID guards the ids used in paths and unit names
+func validTaskID(id string) bool {
+	return id != "" && strings.IndexFunc(id, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-')
+	}) == -1
+}
+
+// taskDir is the directory of a task on the managed host
+func taskDir(id string) string { return TasksDir + "/" + id }
+
+// taskUnit is the transient systemd unit a task runs in
+func taskUnit(id string) string { return "ravact-task-" + id + ".service" }
+
+// taskRunner is the wrapper that runs a task's script and records its exit
+// code. The exit code is written to a temporary file first so a reader
+// never sees a partial one.
+func taskRunner(dir string, env []string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/bash\n")
+	b.WriteString(": \"${HOME:=/root}\"\nexport HOME\ncd /\n")
+	b.WriteString("env")
+	for _, kv := range env {
+		b.WriteString(" " + ShellQuote(kv))
+	}
+	fmt.Fprintf(&b, " bash %s/script.sh </dev/null >>%s/output.log 2>&1\n", ShellQuote(dir), ShellQuote(dir))
+	fmt.Fprintf(&b, "echo $? >%s/exit_code.tmp && mv %s/exit_code.tmp %s/exit_code\n", ShellQuote(dir), ShellQuote(dir), ShellQuote(dir))
+	return b.String()
+}
+
+// taskLauncher is the script, run as root, that writes a task's files and
+// starts it detached: as a transient systemd unit when systemd runs the
+// host, otherwise in its own session so a hangup does not reach it. Old
+// finished tasks beyond tasksKept are removed.
+func taskLauncher(task Task, script []byte, env []string) (string, error) {
+	meta, err := json.Marshal(task)
+	if err != nil {
+		return "", err
+	}
+	dir := taskDir(task.ID)
+	qdir := ShellQuote(dir)
+	enc := base64.StdEncoding.EncodeToString
+
+	var b strings.Builder
+	b.WriteString("set -e\numask 077\n")
+	fmt.Fprintf(&b, "mkdir -p %s\n", qdir)
+	fmt.Fprintf(&b, "echo %s | base64 -d >%s/script.sh\n", enc(script), qdir)
+	fmt.Fprintf(&b, "echo %s | base64 -d >%s/run.sh\n", enc([]byte(taskRunner(dir, env))), qdir)
+	fmt.Fprintf(&b, "echo %s | base64 -d >%s/task.json\n", enc(meta), qdir)
+	fmt.Fprintf(&b, ": >%s/output.log\n", qdir)
+	fmt.Fprintf(&b, "cd %s\nls -1t | tail -n +%d | while read -r old; do if [ -f \"$old/exit_code\" ]; then rm -rf -- \"$old\"; fi; done; cd /\n",
+		ShellQuote(TasksDir), tasksKept+1)
+	if task.Unit != "" {
+		fmt.Fprintf(&b, "systemd-run --quiet --collect --unit=%s --description=%s bash %s/run.sh\n",
+			ShellQuote(task.Unit), ShellQuote("ravact: "+task.Description), qdir)
+	} else {
+		fmt.Fprintf(&b, "setsid nohup bash %s/run.sh >/dev/null 2>&1 </dev/null &\necho $! >%s/pid\n", qdir, qdir)
+	}
+	return b.String(), nil
+}
+
+// hostHasSystemd reports whether systemd manages the active host, so
+// systemd-run can start transient units
+func hostHasSystemd() bool {
+	return Command("sh", "-c", "[ -d /run/systemd/system ] && command -v systemd-run").Run() == nil
+}
+
+// StartTask starts script detached on the managed host as root, with env
+// as extra environment variables. The task outlives ravact; follow it with
+// TaskOutput and TaskStatusOf.
+func StartTask(description, command string, script []byte, env []string) (Task, error) {
+	task := Task{
+		ID:          NewTaskID(time.Now()),
+		Description: description,
+		Command:     command,
+		Started:     time.Now(),
+	}
+	if hostHasSystemd() {
+		task.Unit = taskUnit(task.ID)
+	}
+	launcher, err := taskLauncher(task, script, env)
+	if err != nil {
+		return task, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	cmd := PrivilegedCommandContext(ctx, "bash", "-s")
+	cmd.Stdin = strings.NewReader(launcher)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return task, fmt.Errorf("starting task: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return task, nil
+}
+
+// taskStatusScript prints one line per task directory given: the state
+// ("running", "lost", or "exit=N"), when it finished, whether ravact handled
+// the result, and the task's metadata
+const taskStatusScript = `for dir in "$@"; do
+  [ -f "$dir/task.json" ] || continue
+  finished=0
+  if [ -f "$dir/exit_code" ]; then state="exit=$(cat "$dir/exit_code")"; finished=$(stat -c %Y "$dir/exit_code")
+  elif [ -f "$dir/pid" ] && kill -0 "$(cat "$dir/pid")" 2>/dev/null; then state=running
+  elif [ ! -f "$dir/pid" ] && systemctl is-active --quiet "ravact-task-$(basename "$dir").service" 2>/dev/null; then state=running
+  else state=lost; fi
+  handled=0; [ -f "$dir/handled" ] && handled=1
+  printf '%s\t%s\t%s\t%s\n' "$state" "$finished" "$handled" "$(cat "$dir/task.json")"
+done`
+
+// parseTaskStatus parses a line printed by taskStatusScript
+func parseTaskStatus(line string) (TaskStatus, error) {
+	parts := strings.SplitN(line, "\t", 4)
+	if len(parts) != 4 {
+		return TaskStatus{}, fmt.Errorf("unexpected task status %q", line)
+	}
+	var s TaskStatus
+	if err := json.Unmarshal([]byte(parts[3]), &s.Task); err != nil {
+		return s, fmt.Errorf("reading task: %w", err)
+	}
+	if finished, err := strconv.ParseInt(parts[1], 10, 64); err == nil && finished > 0 {
+		s.Finished = time.Unix(finished, 0)
+	}
+	s.Handled = parts[2] == "1"
+	switch state := parts[0]; {
+	case state == "running":
+		s.State = TaskRunning
+	case strings.HasPrefix(state, "exit="):
+		code, err := strconv.Atoi(strings.TrimPrefix(state, "exit="))
+		if err != nil {
+			return s, fmt.Errorf("unexpected exit code %q", state)
+		}
+		s.ExitCode = code
+		s.State = TaskSucceeded
+		if code != 0 {
+			s.State = TaskFailed
+		}
+	default:
+		s.State = TaskLost
+		s.ExitCode = -1
+	}
+	return s, nil
+}
+
+// taskStatuses runs taskStatusScript as root over the given directories
+// (a glob is expanded by the host's shell)
+func taskStatuses(dirs string) ([]TaskStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	out, err := PrivilegedCommandContext(ctx, "bash", "-c", "set -- "+dirs+"\n"+taskStatusScript).Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading tasks: %w", err)
+	}
+	var statuses []TaskStatus
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		s, err := parseTaskStatus(line)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// ListTasks returns the tasks on the managed host, newest first
+func ListTasks() ([]TaskStatus, error) {
+	statuses, err := taskStatuses(ShellQuote(TasksDir) + "/*")
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ID > statuses[j].ID })
+	return statuses, nil
+}
+
+// RunningTasks returns the tasks still running on the managed host
+func RunningTasks() ([]TaskStatus, error) {
+	statuses, err := ListTasks()
+	if err != nil {
+		return nil, err
+	}
+	var running []TaskStatus
+	for _, s := range statuses {
+		if s.State == TaskRunning {
+			running = append(running, s)
+		}
+	}
+	return running, nil
+}
+
+// TaskStatusOf returns how far a task got
+func TaskStatusOf(id string) (TaskStatus, error) {
+	if !validTaskID(id) {
+		return TaskStatus{}, fmt.Errorf("invalid task id %q", id)
+	}
+	statuses, err := taskStatuses(ShellQuote(taskDir(id)))
+	if err != nil {
+		return TaskStatus{}, err
+	}
+	if len(statuses) == 0 {
+		return TaskStatus{}, fmt.Errorf("task %s not found", id)
+	}
+	return statuses[0], nil
+}
+
+// TaskOutput returns a task's output from byte offset on
+func TaskOutput(id string, offset int64) ([]byte, error) {
+	if !validTaskID(id) {
+		return nil, fmt.Errorf("invalid task id %q", id)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	out, err := PrivilegedCommandContext(ctx, "tail", "-c", "+"+strconv.FormatInt(offset+1, 10), taskDir(id)+"/output.log").Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading task output: %w", err)
+	}
+	return out, nil
+}
+
+// privilegedTaskCommand runs a short shell command as root
+func privilegedTaskCommand(script string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if out, err := PrivilegedCommandContext(ctx, "bash", "-c", script).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// MarkTaskHandled records that ravact has recorded a finished task's
+// result, so re-attaching does not record it again
+func MarkTaskHandled(id string) error {
+	if !validTaskID(id) {
+		return fmt.Errorf("invalid task id %q", id)
+	}
+	return privilegedTaskCommand("touch " + ShellQuote(taskDir(id)+"/handled"))
+}
+
+// StopTask stops a running task and everything it started
+func StopTask(t Task) error {
+	if !validTaskID(t.ID) {
+		return fmt.Errorf("invalid task id %q", t.ID)
+	}
+	if t.Unit != "" {
+		return privilegedTaskCommand("systemctl stop " + ShellQuote(t.Unit))
+	}
+	// setsid made the task a process group leader
+	pid := ShellQuote(taskDir(t.ID) + "/pid")
+	return privilegedTaskCommand("kill -TERM -- -\"$(cat " + pid + ")\"")
+}
+
+// RemoveTask deletes a finished task's files
+func RemoveTask(id string) error {
+	if !validTaskID(id) {
+		return fmt.Errorf("invalid task id %q", id)
+	}
+	return privilegedTaskCommand("rm -rf -- " + ShellQuote(taskDir(id)))
+}
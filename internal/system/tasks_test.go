@@ -0,0 +1,105 @@
+package system
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTaskStatus(t *testing.T) {
+	meta := `{"id":"20261016-120000-abcdef","description":"Install MySQL","command":"assets/scripts/mysql.sh","started":"2026-10-16T12:00:00Z"}`
+	tests := []struct {
+		line    string
+		state   TaskState
+		code    int
+		handled bool
+	}{
+		{"running\t0\t0\t" + meta, TaskRunning, 0, false},
+		{"exit=0\t1792152000\t1\t" + meta, TaskSucceeded, 0, true},
+		{"exit=100\t1792152000\t0\t" + meta, TaskFailed, 100, false},
+		{"lost\t0\t0\t" + meta, TaskLost, -1, false},
+	}
+	for _, tt := range tests {
+		s, err := parseTaskStatus(tt.line)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.line, err)
+		}
+		if s.State != tt.state || s.ExitCode != tt.code || s.Handled != tt.handled {
+			t.Errorf("%q: got %v/%d/%v, want %v/%d/%v", tt.line, s.State, s.ExitCode, s.Handled, tt.state, tt.code, tt.handled)
+		}
+		if done := !s.Finished.IsZero(); done != strings.HasPrefix(tt.line, "exit=") {
+			t.Errorf("%q: finished time %v", tt.line, s.Finished)
+		}
+		if s.ID != "20261016-120000-abcdef" || s.Description != "Install MySQL" {
+			t.Errorf("%q: task metadata not read: %+v", tt.line, s.Task)
+		}
+	}
+
+	if _, err := parseTaskStatus("garbage"); err == nil {
+		t.Error("expected an error for a malformed line")
+	}
+}
+
+func TestValidTaskID(t *testing.T) {
+	if id := NewTaskID(time.Now()); !validTaskID(id) {
+		t.Errorf("generated id %q is not valid", id)
+	}
+	for _, id := range []string{"", "../etc", "a b", "x;rm"} {
+		if validTaskID(id) {
+			t.Errorf("expected %q to be rejected", id)
+		}
+	}
+}
+
+// TestTaskLauncher runs a task through the launcher without systemd and
+// checks it records its output and exit code
+func TestTaskLauncher(t *testing.T) {
+	for _, tool := range []string{"bash", "setsid", "nohup", "base64"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not available", tool)
+		}
+	}
+	orig := TasksDir
+	defer func() { TasksDir = orig }()
+	TasksDir = t.TempDir()
+
+	task := Task{ID: "20261016-120000-abcdef", Description: "Say hello"}
+	launcher, err := taskLauncher(task, []byte("echo \"hello $NAME\"\nexit 3\n"), []string{"NAME=it's me"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("bash", "-c", launcher).CombinedOutput(); err != nil {
+		t.Fatalf("launcher failed: %v: %s", err, out)
+	}
+
+	dir := filepath.Join(TasksDir, task.ID)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "exit_code")); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("task did not finish")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	out, err := exec.Command("bash", "-c", taskStatusScript, "bash", dir).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := parseTaskStatus(strings.TrimSpace(string(out)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.State != TaskFailed || s.ExitCode != 3 {
+		t.Errorf("got %v with exit code %d, want Failed with 3", s.State, s.ExitCode)
+	}
+	output, _ := os.ReadFile(filepath.Join(dir, "output.log"))
+	if string(output) != "hello it's me\n" {
+		t.Errorf("unexpected output %q", output)
+	}
+}
@@ -0,0 +1,463 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iperamuna/ravact/internal/debuglog"
+)
+
+// Transport runs commands and file operations on the host being managed.
+// The default is the local machine; an SSHTransport targets a remote server.
+type Transport interface {
+	Name() string
+	IsRemote() bool
+	CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd
+	ReadFile(path string) ([]byte, error)
+	WriteFile(path string, data []byte, perm os.FileMode) error
+	Stat(path string) (fs.FileInfo, error)
+	Lstat(path string) (fs.FileInfo, error)
+	ReadDir(path string) ([]os.DirEntry, error)
+	MkdirAll(path string, perm os.FileMode) error
+	Remove(path string) error
+	Rename(oldPath, newPath string) error
+	Symlink(oldName, newName string) error
+	Chmod(path string, mode os.FileMode) error
+	OS() string
+}
+
+var (
+	transportMu sync.RWMutex
+	transport   Transport = LocalTransport{}
+)
+
+// UseTransport switches the host all managers operate on
+func UseTransport(t Transport) {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	if t == nil {
+		t = LocalTransport{}
+	}
+	transport = t
+}
+
+// CurrentTransport returns the transport managers are using
+func CurrentTransport() Transport {
+	transportMu.RLock()
+	defer transportMu.RUnlock()
+	return transport
+}
+
+// Command prepares a command on the active host
+func Command(name string, args ...string) *exec.Cmd {
+	return CommandContext(context.Background(), name, args...)
+}
+
+// CommandContext prepares a command on the active host, bound to ctx
+func CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	t := CurrentTransport()
+	debuglog.Command(t.Name(), name, args)
+	return t.CommandContext(ctx, name, args...)
+}
+
+// ReadFile reads a file on the active host
+func ReadFile(path string) ([]byte, error) { return CurrentTransport().ReadFile(path) }
+
+// WriteFile writes a file on the active host. Changes to tracked
+// configuration are recorded in the config history.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	err := trackConfigChange(path, "Update", func() error {
+		return CurrentTransport().WriteFile(path, data, perm)
+	})
+	if err == nil && IsTrackedConfigPath(path) {
+		noteConfigWrite(path, data)
+	}
+	return err
+}
+
+// AppendFile appends data to a file on the active host, creating it if needed
+func AppendFile(path string, data []byte, perm os.FileMode) error {
+	existing, err := ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return WriteFile(path, append(existing, data...), perm)
+}
+
+// Stat returns file info on the active host
+func Stat(path string) (fs.FileInfo, error) { return CurrentTransport().Stat(path) }
+
+// Lstat returns file info on the active host without following symlinks
+func Lstat(path string) (fs.FileInfo, error) { return CurrentTransport().Lstat(path) }
+
+// ReadDir lists a directory on the active host
+func ReadDir(path string) ([]os.DirEntry, error) { return CurrentTransport().ReadDir(path) }
+
+// MkdirAll creates a directory tree on the active host
+func MkdirAll(path string, perm os.FileMode) error {
+	return CurrentTransport().MkdirAll(path, perm)
+}
+
+// Remove deletes a file or empty directory on the active host
+func Remove(path string) error {
+	return trackConfigChange(path, "Remove", func() error {
+		return CurrentTransport().Remove(path)
+	})
+}
+
+// Rename moves a file on the active host
+func Rename(oldPath, newPath string) error {
+	tracked := newPath
+	if !IsTrackedConfigPath(newPath) {
+		// Moving a tracked file away still removes it from the history
+		tracked = oldPath
+	}
+	return trackConfigChange(tracked, "Update", func() error {
+		return CurrentTransport().Rename(oldPath, newPath)
+	})
+}
+
+// Symlink creates a symbolic link on the active host
+func Symlink(oldName, newName string) error {
+	return trackConfigChange(newName, "Link", func() error {
+		return CurrentTransport().Symlink(oldName, newName)
+	})
+}
+
+// Chmod changes file permissions on the active host
+func Chmod(path string, mode os.FileMode) error { return CurrentTransport().Chmod(path, mode) }
+
+// HostOS returns the operating system of the active host (linux, darwin, ...)
+func HostOS() string { return CurrentTransport().OS() }
+
+// HostName returns the hostname of the active host
+func HostName() (string, error) {
+	if !CurrentTransport().IsRemote() {
+		return os.Hostname()
+	}
+	out, err := Command("hostname").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// HostIsRoot reports whether commands on the active host run as root
+func HostIsRoot() bool {
+	if !CurrentTransport().IsRemote() {
+		return os.Geteuid() == 0
+	}
+	out, err := Command("id", "-u").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "0"
+}
+
+// HostArch returns the CPU architecture of the active host
+func HostArch() string {
+	if !CurrentTransport().IsRemote() {
+		return runtime.GOARCH
+	}
+	out, err := Command("uname", "-m").Output()
+	if err != nil {
+		return ""
+	}
+	switch arch := strings.TrimSpace(string(out)); arch {
+	case "x86_64":
+		return "amd64"
+	case "aarch64":
+		return "arm64"
+	default:
+		return arch
+	}
+}
+
+// HostNumCPU returns the number of CPUs on the active host
+func HostNumCPU() int {
+	if !CurrentTransport().IsRemote() {
+		return runtime.NumCPU()
+	}
+	out, err := Command("nproc").Output()
+	if err != nil {
+		return 1
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// LocalTransport operates on the machine ravact is running on
+type LocalTransport struct{}
+
+func (LocalTransport) Name() string   { return "localhost" }
+func (LocalTransport) IsRemote() bool { return false }
+func (LocalTransport) OS() string     { return runtime.GOOS }
+
+func (LocalTransport) CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, name, args...)
+}
+
+func (LocalTransport) ReadFile(path string) ([]byte, error) { return os.ReadFile(path) }
+func (LocalTransport) WriteFile(path string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(path, data, perm)
+}
+func (LocalTransport) Stat(path string) (fs.FileInfo, error)      { return os.Stat(path) }
+func (LocalTransport) Lstat(path string) (fs.FileInfo, error)     { return os.Lstat(path) }
+func (LocalTransport) ReadDir(path string) ([]os.DirEntry, error) { return os.ReadDir(path) }
+func (LocalTransport) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+func (LocalTransport) Remove(path string) error              { return os.Remove(path) }
+func (LocalTransport) Rename(oldPath, newPath string) error  { return os.Rename(oldPath, newPath) }
+func (LocalTransport) Symlink(oldName, newName string) error { return os.Symlink(oldName, newName) }
+func (LocalTransport) Chmod(path string, mode os.FileMode) error {
+	return os.Chmod(path, mode)
+}
+
+// SSHTransport operates on a remote server through the system ssh client,
+// so keys, agents, and ~/.ssh/config work as they do in a terminal
+type SSHTransport struct {
+	Server Server
+
+	osOnce sync.Once
+	osName string
+}
+
+// NewSSHTransport creates a transport for a server from the inventory
+func NewSSHTransport(server Server) *SSHTransport {
+	return &SSHTransport{Server: server}
+}
+
+func (t *SSHTransport) Name() string   { return t.Server.Name }
+func (t *SSHTransport) IsRemote() bool { return true }
+
+// sshArgs returns the ssh options and destination for the server. The
+// destination follows "--" so a host starting with "-" is never an option.
+func (t *SSHTransport) sshArgs() []string {
+	args := []string{"-o", "BatchMode=yes", "-o", "ConnectTimeout=10"}
+	if t.Server.Port != 0 && t.Server.Port != 22 {
+		args = append(args, "-p", strconv.Itoa(t.Server.Port))
+	}
+	if t.Server.IdentityFile != "" {
+		args = append(args, "-i", expandHome(t.Server.IdentityFile))
+	}
+	return append(args, "--", t.Server.Destination())
+}
+
+func (t *SSHTransport) CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	remote := make([]string, 0, len(args)+1)
+	remote = append(remote, ShellQuote(name))
+	for _, arg := range args {
+		remote = append(remote, ShellQuote(arg))
+	}
+	sshArgs := append(t.sshArgs(), strings.Join(remote, " "))
+	return exec.CommandContext(ctx, "ssh", sshArgs...)
+}
+
+// run executes a shell snippet remotely and returns stdout
+func (t *SSHTransport) run(stdin []byte, script string, args ...string) ([]byte, error) {
+	cmdArgs := append([]string{"-c", script, "ravact"}, args...)
+	cmd := t.CommandContext(context.Background(), "sh", cmdArgs...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if strings.Contains(msg, "No such file or directory") {
+			return out, os.ErrNotExist
+		}
+		if msg != "" {
+			return out, fmt.Errorf("%s: %w", msg, err)
+		}
+		return out, err
+	}
+	return out, nil
+}
+
+func (t *SSHTransport) pathError(op, path string, err error) error {
+	return &fs.PathError{Op: op, Path: path, Err: err}
+}
+
+func (t *SSHTransport) ReadFile(path string) ([]byte, error) {
+	out, err := t.run(nil, `cat -- "$1"`, path)
+	if err != nil {
+		return nil, t.pathError("open", path, err)
+	}
+	return out, nil
+}
+
+func (t *SSHTransport) WriteFile(path string, data []byte, perm os.FileMode) error {
+	_, err := t.run(data, `[ -e "$1" ] || { touch -- "$1" && chmod "$2" -- "$1"; } && cat > "$1"`, path, fmt.Sprintf("%o", perm.Perm()))
+	if err != nil {
+		return t.pathError("write", path, err)
+	}
+	return nil
+}
+
+func (t *SSHTransport) stat(path string, follow bool) (fs.FileInfo, error) {
+	flag := "-c"
+	if follow {
+		flag = "-Lc"
+	}
+	out, err := t.run(nil, `stat `+flag+` '%s %f %Y' -- "$1"`, path)
+	if err != nil {
+		return nil, t.pathError("stat", path, err)
+	}
+	return parseRemoteStat(filepath.Base(path), strings.TrimSpace(string(out)))
+}
+
+func (t *SSHTransport) Stat(path string) (fs.FileInfo, error)  { return t.stat(path, true) }
+func (t *SSHTransport) Lstat(path string) (fs.FileInfo, error) { return t.stat(path, false) }
+
+func (t *SSHTransport) ReadDir(path string) ([]os.DirEntry, error) {
+	out, err := t.run(nil, `cd -- "$1" && for f in * .[!.]*; do [ -e "$f" ] || [ -L "$f" ] || continue; stat -c '%s %f %Y %n' -- "$f"; done`, path)
+	if err != nil {
+		return nil, t.pathError("open", path, err)
+	}
+
+	var entries []os.DirEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, " ", 4)
+		if len(parts) < 4 {
+			continue
+		}
+		info, err := parseRemoteStat(parts[3], strings.Join(parts[:3], " "))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	return entries, nil
+}
+
+func (t *SSHTransport) MkdirAll(path string, perm os.FileMode) error {
+	_, err := t.run(nil, `mkdir -p -m "$2" -- "$1"`, path, fmt.Sprintf("%o", perm.Perm()))
+	return err
+}
+
+func (t *SSHTransport) Remove(path string) error {
+	_, err := t.run(nil, `if [ -d "$1" ] && [ ! -L "$1" ]; then rmdir -- "$1"; else rm -- "$1"; fi`, path)
+	if err != nil {
+		return t.pathError("remove", path, err)
+	}
+	return nil
+}
+
+func (t *SSHTransport) Rename(oldPath, newPath string) error {
+	_, err := t.run(nil, `mv -- "$1" "$2"`, oldPath, newPath)
+	return err
+}
+
+func (t *SSHTransport) Symlink(oldName, newName string) error {
+	_, err := t.run(nil, `ln -s -- "$1" "$2"`, oldName, newName)
+	return err
+}
+
+func (t *SSHTransport) Chmod(path string, mode os.FileMode) error {
+	_, err := t.run(nil, `chmod "$2" -- "$1"`, path, fmt.Sprintf("%o", mode.Perm()))
+	return err
+}
+
+// OS returns the remote kernel name, detected once per transport
+func (t *SSHTransport) OS() string {
+	t.osOnce.Do(func() {
+		t.osName = "linux"
+		if out, err := t.run(nil, "uname -s"); err == nil {
+			t.osName = strings.ToLower(strings.TrimSpace(string(out)))
+		}
+	})
+	return t.osName
+}
+
+// TestConnection checks that the server is reachable without prompting
+func (t *SSHTransport) TestConnection() (string, error) {
+	out, err := t.run(nil, "uname -snr")
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to %s: %w", t.Server.Destination(), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// remoteFileInfo implements fs.FileInfo for files on a remote host
+type remoteFileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (fi remoteFileInfo) Name() string       { return fi.name }
+func (fi remoteFileInfo) Size() int64        { return fi.size }
+func (fi remoteFileInfo) Mode() fs.FileMode  { return fi.mode }
+func (fi remoteFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi remoteFileInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi remoteFileInfo) Sys() any           { return nil }
+
+// parseRemoteStat parses "size rawmode(hex) mtime" as printed by stat -c '%s %f %Y'
+func parseRemoteStat(name, line string) (fs.FileInfo, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected stat output: %q", line)
+	}
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := strconv.ParseUint(fields[1], 16, 32)
+	if err != nil {
+		return nil, err
+	}
+	mtime, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := fs.FileMode(raw & 0o777)
+	switch raw & 0o170000 {
+	case 0o040000:
+		mode |= fs.ModeDir
+	case 0o120000:
+		mode |= fs.ModeSymlink
+	case 0o140000:
+		mode |= fs.ModeSocket
+	case 0o010000:
+		mode |= fs.ModeNamedPipe
+	}
+	return remoteFileInfo{name: name, size: size, mode: mode, modTime: time.Unix(mtime, 0)}, nil
+}
+
+// ShellQuote quotes a string for safe use as a single POSIX shell word
+func ShellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@%+=:,./_-", r))
+	}) == -1 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// expandHome replaces a leading ~ with the local user's home directory
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
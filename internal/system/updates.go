@@ -0,0 +1,162 @@
+package system
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PackageUpdate is a package with an available upgrade
+type PackageUpdate struct {
+	Name      string
+	Current   string
+	Available string
+	Source    string // Repository or suite the upgrade comes from
+	Security  bool
+}
+
+// PackageUpdater lists and applies OS package upgrades
+type PackageUpdater string
+
+const (
+	UpdaterApt PackageUpdater = "apt"
+	UpdaterDnf PackageUpdater = "dnf"
+)
+
+// DetectPackageUpdater returns the host's package manager
+func DetectPackageUpdater() (PackageUpdater, error) {
+	if _, err := Command("which", "apt-get").Output(); err == nil {
+		return UpdaterApt, nil
+	}
+	if _, err := Command("which", "dnf").Output(); err == nil {
+		return UpdaterDnf, nil
+	}
+	return "", fmt.Errorf("no supported package manager found (apt or dnf)")
+}
+
+// RefreshPackageLists downloads the latest package lists. dnf refreshes
+// its metadata when listing, so this only applies to apt.
+func (u PackageUpdater) RefreshPackageLists() error {
+	if u != UpdaterApt {
+		return nil
+	}
+	if output, err := Command("apt-get", "update", "-qq").CombinedOutput(); err != nil {
+		return fmt.Errorf("apt-get update failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// PendingUpdates lists the available upgrades, security updates first
+func (u PackageUpdater) PendingUpdates() ([]PackageUpdate, error) {
+	var updates []PackageUpdate
+	switch u {
+	case UpdaterApt:
+		output, err := Command("apt", "list", "--upgradable").Output()
+		if err != nil {
+			return nil, fmt.Errorf("apt list --upgradable failed: %w", err)
+		}
+		updates = parseAptUpgradable(string(output))
+	case UpdaterDnf:
+		// dnf exits 100 when updates are available
+		output, _ := Command("dnf", "-q", "check-update").Output()
+		security, _ := Command("dnf", "-q", "updateinfo", "list", "--security", "--updates").Output()
+		updates = parseDnfCheckUpdate(string(output), string(security))
+	default:
+		return nil, fmt.Errorf("unsupported package manager %q", u)
+	}
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].Security && !updates[j].Security
+	})
+	return updates, nil
+}
+
+// UpgradeScript returns the commands that upgrade the given packages, or
+// every package when none are given
+func (u PackageUpdater) UpgradeScript(packages []string) string {
+	quoted := make([]string, len(packages))
+	for i, p := range packages {
+		quoted[i] = ShellQuote(p)
+	}
+	if u == UpdaterDnf {
+		if len(packages) == 0 {
+			return "sudo dnf upgrade -y"
+		}
+		return "sudo dnf upgrade -y " + strings.Join(quoted, " ")
+	}
+	script := "sudo apt-get update\n"
+	if len(packages) == 0 {
+		return script + "sudo DEBIAN_FRONTEND=noninteractive apt-get upgrade -y"
+	}
+	return script + "sudo DEBIAN_FRONTEND=noninteractive apt-get install --only-upgrade -y " + strings.Join(quoted, " ")
+}
+
+// aptUpgradablePattern matches `apt list --upgradable` entries:
+// name/suites version arch [upgradable from: old]
+var aptUpgradablePattern = regexp.MustCompile(`^(\S+?)/(\S+) (\S+) \S+ \[upgradable from: ([^\]]+)\]`)
+
+// parseAptUpgradable parses `apt list --upgradable`
+func parseAptUpgradable(output string) []PackageUpdate {
+	var updates []PackageUpdate
+	for _, line := range strings.Split(output, "\n") {
+		m := aptUpgradablePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		updates = append(updates, PackageUpdate{
+			Name:      m[1],
+			Source:    m[2],
+			Available: m[3],
+			Current:   m[4],
+			Security:  strings.Contains(m[2], "-security"),
+		})
+	}
+	return updates
+}
+
+// parseDnfCheckUpdate parses `dnf check-update` and marks the packages
+// named in `dnf updateinfo list --security`. check-update does not show
+// installed versions.
+func parseDnfCheckUpdate(output, security string) []PackageUpdate {
+	secure := map[string]bool{}
+	for _, line := range strings.Split(security, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 {
+			secure[dnfPackageName(fields[2])] = true
+		}
+	}
+
+	var updates []PackageUpdate
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		// Obsoleting packages and notices follow a blank line
+		if len(fields) == 0 && len(updates) > 0 {
+			break
+		}
+		if len(fields) != 3 || !strings.Contains(fields[0], ".") {
+			continue
+		}
+		name := fields[0][:strings.LastIndex(fields[0], ".")]
+		updates = append(updates, PackageUpdate{
+			Name:      name,
+			Available: fields[1],
+			Source:    fields[2],
+			Security:  secure[name],
+		})
+	}
+	return updates
+}
+
+// dnfPackageName strips the version, release, and architecture from a
+// package NEVRA such as openssl-libs-1:3.0.7-27.el9.x86_64
+func dnfPackageName(nevra string) string {
+	if i := strings.LastIndex(nevra, "."); i > 0 {
+		nevra = nevra[:i]
+	}
+	for n := 0; n < 2; n++ {
+		if i := strings.LastIndex(nevra, "-"); i > 0 {
+			nevra = nevra[:i]
+		}
+	}
+	return nevra
+}
@@ -0,0 +1,50 @@
+package system
+
+import "testing"
+
+func TestParseAptUpgradable(t *testing.T) {
+	output := `Listing...
+curl/noble-updates 8.5.0-2ubuntu10.2 amd64 [upgradable from: 8.5.0-2ubuntu10.1]
+openssl/noble-security 3.0.13-0ubuntu3.2 amd64 [upgradable from: 3.0.13-0ubuntu3.1]
+libssl3t64/noble-updates,noble-security 3.0.13-0ubuntu3.2 amd64 [upgradable from: 3.0.13-0ubuntu3.1]
+`
+	updates := parseAptUpgradable(output)
+	if len(updates) != 3 {
+		t.Fatalf("expected 3 updates, got %d", len(updates))
+	}
+	want := PackageUpdate{Name: "openssl", Current: "3.0.13-0ubuntu3.1", Available: "3.0.13-0ubuntu3.2", Source: "noble-security", Security: true}
+	if updates[1] != want {
+		t.Errorf("got %+v, want %+v", updates[1], want)
+	}
+	if updates[0].Security || !updates[2].Security {
+		t.Errorf("unexpected security flags: %+v", updates)
+	}
+}
+
+func TestParseDnfCheckUpdate(t *testing.T) {
+	output := `
+openssl-libs.x86_64        1:3.0.7-27.el9       baseos
+vim-minimal.x86_64         2:8.2.2637-21.el9    baseos
+
+Obsoleting Packages
+grub2-tools.x86_64         1:2.06-80.el9        baseos
+`
+	security := "RHSA-2024:1234 Important/Sec. openssl-libs-1:3.0.7-27.el9.x86_64\n"
+	updates := parseDnfCheckUpdate(output, security)
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 updates, got %+v", updates)
+	}
+	if updates[0].Name != "openssl-libs" || !updates[0].Security || updates[1].Security {
+		t.Errorf("unexpected updates: %+v", updates)
+	}
+}
+
+func TestUpgradeScript(t *testing.T) {
+	if got := UpdaterDnf.UpgradeScript([]string{"openssl-libs"}); got != "sudo dnf upgrade -y openssl-libs" {
+		t.Errorf("unexpected dnf script: %q", got)
+	}
+	want := "sudo apt-get update\nsudo DEBIAN_FRONTEND=noninteractive apt-get install --only-upgrade -y curl openssl"
+	if got := UpdaterApt.UpgradeScript([]string{"curl", "openssl"}); got != want {
+		t.Errorf("unexpected apt script: %q", got)
+	}
+}
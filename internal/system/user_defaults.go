@@ -0,0 +1,258 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UserDefaultsPath is where ravact keeps the settings applied to new users
+var UserDefaultsPath = "/etc/ravact/user-defaults.yaml"
+
+// ShellsPath lists the login shells installed on the host
+var ShellsPath = "/etc/shells"
+
+// UmaskProfilePath is the profile snippet that sets per-user umasks at login
+var UmaskProfilePath = "/etc/profile.d/ravact-umask.sh"
+
+var umaskPattern = regexp.MustCompile(`^0?[0-7]{3}$`)
+
+// UserDefaults are applied to every user ravact creates, whether from the
+// Add User form or an import
+type UserDefaults struct {
+	Shell  string   `yaml:"shell"`
+	Umask  string   `yaml:"umask,omitempty"`
+	Groups []string `yaml:"groups,omitempty"`
+}
+
+// LoadUserDefaults reads the defaults from the current host, falling back to
+// bash with no umask or extra groups
+func LoadUserDefaults() (UserDefaults, error) {
+	defaults := UserDefaults{Shell: "/bin/bash"}
+	data, err := ReadFile(UserDefaultsPath)
+	if os.IsNotExist(err) {
+		return defaults, nil
+	}
+	if err != nil {
+		return defaults, fmt.Errorf("failed to read user defaults: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &defaults); err != nil {
+		return defaults, fmt.Errorf("failed to parse %s: %w", UserDefaultsPath, err)
+	}
+	if defaults.Shell == "" {
+		defaults.Shell = "/bin/bash"
+	}
+	return defaults, nil
+}
+
+// Save writes the defaults
+func (d UserDefaults) Save() error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("failed to encode user defaults: %w", err)
+	}
+	if err := MkdirAll(filepath.Dir(UserDefaultsPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(UserDefaultsPath), err)
+	}
+	if err := WriteFile(UserDefaultsPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write user defaults: %w", err)
+	}
+	return nil
+}
+
+// Validate checks the umask and group names
+func (d UserDefaults) Validate() error {
+	if err := ValidateUmask(d.Umask); err != nil {
+		return err
+	}
+	for _, group := range d.Groups {
+		if !usernamePattern.MatchString(group) {
+			return fmt.Errorf("invalid group name: %q", group)
+		}
+	}
+	return nil
+}
+
+// usernamePattern matches the user and group names useradd accepts by default
+var usernamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_-]*$`)
+
+// ValidateUmask checks a umask such as 022 or 0027; empty means unset
+func ValidateUmask(umask string) error {
+	if umask != "" && !umaskPattern.MatchString(umask) {
+		return fmt.Errorf("invalid umask %q: use three octal digits such as 022", umask)
+	}
+	return nil
+}
+
+// ParseGroupList splits a comma- or space-separated list of groups
+func ParseGroupList(s string) []string {
+	var groups []string
+	seen := map[string]bool{}
+	for _, group := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if !seen[group] {
+			seen[group] = true
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// InstalledShells returns the login shells in /etc/shells that exist on
+// the host, skipping nologin and false
+func InstalledShells() []string {
+	data, err := ReadFile(ShellsPath)
+	if err != nil {
+		return []string{"/bin/bash", "/bin/sh"}
+	}
+	return parseShells(string(data), func(path string) bool {
+		_, err := Stat(path)
+		return err == nil
+	})
+}
+
+// parseShells lists the usable shells in an /etc/shells file. /bin and
+// /usr/bin entries for the same shell are usually both listed (and the
+// same file on merged-/usr systems), so only the first is kept.
+func parseShells(content string, exists func(string) bool) []string {
+	var shells []string
+	seen := map[string]bool{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name := filepath.Base(line)
+		if name == "nologin" || name == "false" || seen[name] || !exists(line) {
+			continue
+		}
+		seen[name] = true
+		shells = append(shells, line)
+	}
+	return shells
+}
+
+// parseUmaskProfile reads the per-user umasks from the profile snippet
+func parseUmaskProfile(content string) map[string]string {
+	umasks := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		user, rest, ok := strings.Cut(strings.TrimSpace(line), ") umask ")
+		if !ok {
+			continue
+		}
+		umask := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(rest), ";;"))
+		if usernamePattern.MatchString(user) && umaskPattern.MatchString(umask) {
+			umasks[user] = umask
+		}
+	}
+	return umasks
+}
+
+// renderUmaskProfile writes the profile snippet for a set of umasks
+func renderUmaskProfile(umasks map[string]string) string {
+	users := make([]string, 0, len(umasks))
+	for user := range umasks {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	var b strings.Builder
+	b.WriteString("# Managed by ravact: per-user umask, set from User Management\n")
+	b.WriteString("case \"$(id -un)\" in\n")
+	for _, user := range users {
+		fmt.Fprintf(&b, "    %s) umask %s ;;\n", user, umasks[user])
+	}
+	b.WriteString("esac\n")
+	return b.String()
+}
+
+// GetUmask returns the umask ravact sets for a user at login, or "" if none
+func (um *UserManager) GetUmask(username string) string {
+	data, err := ReadFile(UmaskProfilePath)
+	if err != nil {
+		return ""
+	}
+	return parseUmaskProfile(string(data))[username]
+}
+
+// SetUmask sets the login umask of a user through the profile snippet; an
+// empty umask removes the user's entry
+func (um *UserManager) SetUmask(username, umask string) error {
+	if err := ValidateUmask(umask); err != nil {
+		return err
+	}
+	umasks := map[string]string{}
+	if data, err := ReadFile(UmaskProfilePath); err == nil {
+		umasks = parseUmaskProfile(string(data))
+	}
+	if umask == "" {
+		delete(umasks, username)
+	} else {
+		umasks[username] = umask
+	}
+	if len(umasks) == 0 {
+		if err := Remove(UmaskProfilePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", UmaskProfilePath, err)
+		}
+		return nil
+	}
+	if err := WriteFile(UmaskProfilePath, []byte(renderUmaskProfile(umasks)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", UmaskProfilePath, err)
+	}
+	return nil
+}
+
+// ChangeShell sets a user's login shell to one of the installed shells
+func (um *UserManager) ChangeShell(username, shell string) error {
+	found := false
+	for _, s := range InstalledShells() {
+		if s == shell {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s is not an installed shell", shell)
+	}
+	output, err := Command("usermod", "-s", shell, username).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("usermod failed: %v - %s", err, string(output))
+	}
+	return nil
+}
+
+// ApplyUserDefaults sets the umask and group memberships of a newly created
+// user; the shell is passed to useradd when the user is created
+func (um *UserManager) ApplyUserDefaults(username string, defaults UserDefaults) error {
+	if err := defaults.Validate(); err != nil {
+		return err
+	}
+	if defaults.Umask != "" {
+		if err := um.SetUmask(username, defaults.Umask); err != nil {
+			return err
+		}
+	}
+	if len(defaults.Groups) > 0 {
+		var missing []string
+		for _, group := range defaults.Groups {
+			if _, err := um.GetGroup(group); err != nil {
+				missing = append(missing, group)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("groups do not exist: %s", strings.Join(missing, ", "))
+		}
+		output, err := Command("usermod", "-aG", strings.Join(defaults.Groups, ","), username).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("usermod failed: %v - %s", err, string(output))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseShells(t *testing.T) {
+	content := "# /etc/shells: valid login shells\n/bin/sh\n/usr/bin/sh\n/bin/bash\n/usr/bin/bash\n/usr/sbin/nologin\n/bin/false\n/usr/bin/fish\n"
+	exists := func(path string) bool { return path != "/usr/bin/fish" }
+
+	got := parseShells(content, exists)
+	want := []string{"/bin/sh", "/bin/bash"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestUmaskProfileRoundTrip(t *testing.T) {
+	umasks := map[string]string{"deploy": "027", "alice": "0002"}
+	content := renderUmaskProfile(umasks)
+	if got := parseUmaskProfile(content); !reflect.DeepEqual(got, umasks) {
+		t.Errorf("got %v, want %v\n%s", got, umasks, content)
+	}
+}
+
+func TestSetUmask(t *testing.T) {
+	old := UmaskProfilePath
+	UmaskProfilePath = filepath.Join(t.TempDir(), "ravact-umask.sh")
+	defer func() { UmaskProfilePath = old }()
+
+	um := NewUserManager()
+	if err := um.SetUmask("deploy", "27"); err == nil {
+		t.Error("expected an invalid umask to be rejected")
+	}
+	if err := um.SetUmask("deploy", "027"); err != nil {
+		t.Fatal(err)
+	}
+	if err := um.SetUmask("alice", "002"); err != nil {
+		t.Fatal(err)
+	}
+	if got := um.GetUmask("deploy"); got != "027" {
+		t.Errorf("expected 027, got %q", got)
+	}
+
+	um.SetUmask("deploy", "")
+	um.SetUmask("alice", "")
+	if _, err := os.Stat(UmaskProfilePath); !os.IsNotExist(err) {
+		t.Error("expected the snippet to be removed once empty")
+	}
+}
+
+func TestUserDefaults(t *testing.T) {
+	old := UserDefaultsPath
+	UserDefaultsPath = filepath.Join(t.TempDir(), "user-defaults.yaml")
+	defer func() { UserDefaultsPath = old }()
+
+	defaults, err := LoadUserDefaults()
+	if err != nil || defaults.Shell != "/bin/bash" {
+		t.Fatalf("unexpected defaults: %+v, %v", defaults, err)
+	}
+
+	saved := UserDefaults{Shell: "/bin/zsh", Umask: "027", Groups: ParseGroupList("www-data, developers www-data")}
+	if err := saved.Save(); err != nil {
+		t.Fatal(err)
+	}
+	defaults, err = LoadUserDefaults()
+	if err != nil || !reflect.DeepEqual(defaults, saved) {
+		t.Errorf("got %+v, want %+v (%v)", defaults, saved, err)
+	}
+	if !reflect.DeepEqual(saved.Groups, []string{"www-data", "developers"}) {
+		t.Errorf("unexpected groups: %v", saved.Groups)
+	}
+
+	if err := (UserDefaults{Groups: []string{"Bad Group"}}).Save(); err == nil {
+		t.Error("expected an invalid group to be rejected")
+	}
+}
@@ -0,0 +1,190 @@
+package system
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UserImportEntry is one user in a bulk import file
+type UserImportEntry struct {
+	Username string   `yaml:"username"`
+	Shell    string   `yaml:"shell,omitempty"`  // Defaults to the saved user defaults
+	Groups   []string `yaml:"groups,omitempty"` // Added to the default groups
+	Keys     []string `yaml:"keys,omitempty"`   // Public keys for authorized_keys
+}
+
+// userImportFile is the YAML layout of an import file
+type userImportFile struct {
+	Users []UserImportEntry `yaml:"users"`
+}
+
+// UserImportResult is the outcome of importing one user
+type UserImportResult struct {
+	Username string
+	Err      error
+}
+
+// ParseUserImport reads users from a CSV or YAML file, chosen by extension.
+// CSV files need a header row naming the username, shell, groups, and keys
+// columns; only username is required. Groups are separated by commas or
+// spaces and keys by semicolons or newlines.
+func ParseUserImport(name string, data []byte) ([]UserImportEntry, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		var f userImportFile
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(name), err)
+		}
+		if len(f.Users) == 0 {
+			return nil, fmt.Errorf("%s has no users: list them under users:", filepath.Base(name))
+		}
+		return f.Users, nil
+	case ".csv":
+		return parseUserImportCSV(data)
+	}
+	return nil, fmt.Errorf("unsupported import file %s: use .csv, .yaml, or .yml", filepath.Base(name))
+}
+
+// parseUserImportCSV reads the CSV layout described on ParseUserImport
+func parseUserImportCSV(data []byte) ([]UserImportEntry, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+
+	columns := map[string]int{}
+	for i, h := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := columns["username"]; !ok {
+		return nil, fmt.Errorf("CSV header must include a username column")
+	}
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var entries []UserImportEntry
+	for _, record := range records[1:] {
+		username := field(record, "username")
+		if username == "" {
+			continue
+		}
+		entry := UserImportEntry{
+			Username: username,
+			Shell:    field(record, "shell"),
+			Groups:   ParseGroupList(field(record, "groups")),
+		}
+		for _, key := range strings.FieldsFunc(field(record, "keys"), func(r rune) bool { return r == ';' || r == '\n' }) {
+			if key = strings.TrimSpace(key); key != "" {
+				entry.Keys = append(entry.Keys, key)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("CSV file has no users")
+	}
+	return entries, nil
+}
+
+// Validate checks an entry before anything is created
+func (e UserImportEntry) Validate() error {
+	if !usernamePattern.MatchString(e.Username) || len(e.Username) > 32 {
+		return fmt.Errorf("invalid username %q", e.Username)
+	}
+	if e.Shell != "" && !strings.HasPrefix(e.Shell, "/") {
+		return fmt.Errorf("shell must be an absolute path, got %q", e.Shell)
+	}
+	for _, group := range e.Groups {
+		if !usernamePattern.MatchString(group) {
+			return fmt.Errorf("invalid group name %q", group)
+		}
+	}
+	for i, key := range e.Keys {
+		if _, ok := ParseAuthorizedKey(key); !ok {
+			return fmt.Errorf("key %d is not a valid public key", i+1)
+		}
+	}
+	return nil
+}
+
+// CheckUserImport validates every entry and flags usernames that already
+// exist or appear twice, returning nil for the entries that can be imported
+func (um *UserManager) CheckUserImport(entries []UserImportEntry) []error {
+	checks := make([]error, len(entries))
+	seen := map[string]bool{}
+	for i, e := range entries {
+		if seen[e.Username] {
+			checks[i] = fmt.Errorf("listed more than once")
+		} else if err := e.Validate(); err != nil {
+			checks[i] = err
+		} else if _, err := um.GetUser(e.Username); err == nil {
+			checks[i] = fmt.Errorf("user already exists")
+		}
+		seen[e.Username] = true
+	}
+	return checks
+}
+
+// ImportUser creates a passwordless user from an import entry, applying the
+// user defaults under the entry's shell and extra groups, and authorizes
+// its keys
+func (um *UserManager) ImportUser(e UserImportEntry, defaults UserDefaults) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	shell := e.Shell
+	if shell == "" {
+		shell = defaults.Shell
+	}
+	if err := um.CreateUserPasswordless(e.Username, shell); err != nil {
+		return fmt.Errorf("failed to create user: %v", err)
+	}
+
+	groups := append(append([]string{}, defaults.Groups...), e.Groups...)
+	applied := UserDefaults{Shell: shell, Umask: defaults.Umask, Groups: ParseGroupList(strings.Join(groups, " "))}
+	if err := um.ApplyUserDefaults(e.Username, applied); err != nil {
+		return fmt.Errorf("user created but failed to apply umask and groups: %v", err)
+	}
+
+	if len(e.Keys) > 0 {
+		var keys []RemoteKey
+		for _, line := range e.Keys {
+			key, _ := ParseAuthorizedKey(line)
+			keys = append(keys, key)
+		}
+		if _, _, err := um.updateAuthorizedKeys(e.Username, nil, keys); err != nil {
+			return fmt.Errorf("user created but failed to add keys: %v", err)
+		}
+	}
+	return nil
+}
+
+// ImportUsers imports each entry in turn, carrying on past failures
+func (um *UserManager) ImportUsers(entries []UserImportEntry) []UserImportResult {
+	defaults, err := LoadUserDefaults()
+	results := make([]UserImportResult, 0, len(entries))
+	for _, e := range entries {
+		result := UserImportResult{Username: e.Username, Err: err}
+		if err == nil {
+			result.Err = um.ImportUser(e, defaults)
+		}
+		results = append(results, result)
+	}
+	return results
+}
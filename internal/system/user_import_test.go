@@ -0,0 +1,99 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseUserImportCSV(t *testing.T) {
+	data := "# onboarding\n" +
+		"Username,Groups,Keys,Shell\n" +
+		`alice,"developers,www-data",` + testKeyA + ";" + testKeyB + ",/bin/zsh\n" +
+		"bob,,,\n" +
+		",ignored,,\n"
+	entries, err := ParseUserImport("team.csv", []byte(data))
+	if err != nil {
+		t.Fatalf("ParseUserImport failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(entries))
+	}
+	alice := entries[0]
+	if alice.Username != "alice" || alice.Shell != "/bin/zsh" {
+		t.Errorf("unexpected entry: %+v", alice)
+	}
+	if strings.Join(alice.Groups, " ") != "developers www-data" {
+		t.Errorf("unexpected groups: %q", alice.Groups)
+	}
+	if len(alice.Keys) != 2 || alice.Keys[1] != testKeyB {
+		t.Errorf("unexpected keys: %q", alice.Keys)
+	}
+	if entries[1].Username != "bob" || entries[1].Shell != "" || len(entries[1].Keys) != 0 {
+		t.Errorf("unexpected entry: %+v", entries[1])
+	}
+
+	if _, err := ParseUserImport("team.csv", []byte("name,shell\nalice,/bin/bash\n")); err == nil {
+		t.Error("expected an error without a username column")
+	}
+}
+
+func TestParseUserImportYAML(t *testing.T) {
+	data := `users:
+  - username: carol
+    groups: [developers]
+    keys:
+      - ` + testKeyA + ` carol@laptop
+  - username: dave
+    shell: /bin/sh
+`
+	entries, err := ParseUserImport("team.yml", []byte(data))
+	if err != nil {
+		t.Fatalf("ParseUserImport failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Keys[0] != testKeyA+" carol@laptop" || entries[1].Shell != "/bin/sh" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+
+	if _, err := ParseUserImport("team.yaml", []byte("people: []\n")); err == nil {
+		t.Error("expected an error for a file without users")
+	}
+	if _, err := ParseUserImport("team.json", []byte("{}")); err == nil {
+		t.Error("expected an error for an unsupported extension")
+	}
+}
+
+func TestUserImportEntryValidate(t *testing.T) {
+	valid := UserImportEntry{Username: "deploy", Shell: "/bin/bash", Groups: []string{"www-data"}, Keys: []string{testKeyA}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected entry to be valid: %v", err)
+	}
+	invalid := []UserImportEntry{
+		{Username: "Deploy"},
+		{Username: "deploy", Shell: "bash"},
+		{Username: "deploy", Groups: []string{"web team"}},
+		{Username: "deploy", Keys: []string{"not a key"}},
+	}
+	for _, e := range invalid {
+		if err := e.Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", e)
+		}
+	}
+}
+
+func TestCheckUserImportFlagsDuplicates(t *testing.T) {
+	um := NewUserManager()
+	checks := um.CheckUserImport([]UserImportEntry{
+		{Username: "ravact-import-test"},
+		{Username: "ravact-import-test"},
+		{Username: "Bad"},
+	})
+	if checks[0] != nil {
+		t.Errorf("first entry should be importable: %v", checks[0])
+	}
+	if checks[1] == nil || !strings.Contains(checks[1].Error(), "more than once") {
+		t.Errorf("duplicate should be flagged, got %v", checks[1])
+	}
+	if checks[2] == nil {
+		t.Error("invalid username should be flagged")
+	}
+}
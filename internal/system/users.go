@@ -2,11 +2,10 @@ package system
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
-	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -41,18 +40,17 @@ func NewUserManager() *UserManager {
 // GetAllUsers returns all system users (UID >= 1000 for regular users)
 func (um *UserManager) GetAllUsers() ([]User, error) {
 	// Check if running on unsupported OS
-	if runtime.GOOS == "darwin" {
+	if HostOS() == "darwin" {
 		return um.getDarwinUsers()
 	}
 
-	file, err := os.Open("/etc/passwd")
+	data, err := ReadFile("/etc/passwd")
 	if err != nil {
 		return nil, fmt.Errorf("failed to read /etc/passwd: %w", err)
 	}
-	defer file.Close()
 
 	var users []User
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -150,11 +148,11 @@ func (um *UserManager) getUserGroups(username string) []string {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "groups", username)
+	cmd := CommandContext(ctx, "groups", username)
 	output, err := cmd.Output()
 	if err != nil {
 		// On macOS or if command fails, try reading from /etc/group
-		if runtime.GOOS == "darwin" {
+		if HostOS() == "darwin" {
 			return um.getUserGroupsFromFile(username)
 		}
 		return []string{}
@@ -176,14 +174,13 @@ func (um *UserManager) getUserGroups(username string) []string {
 
 // getUserGroupsFromFile reads groups from /etc/group file (fallback method)
 func (um *UserManager) getUserGroupsFromFile(username string) []string {
-	file, err := os.Open("/etc/group")
+	data, err := ReadFile("/etc/group")
 	if err != nil {
 		return []string{}
 	}
-	defer file.Close()
 
 	var groups []string
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -219,7 +216,7 @@ func (um *UserManager) CreateUser(username, password, shell string) error {
 		username,
 	}
 
-	cmd := exec.CommandContext(ctx, "useradd", args...)
+	cmd := CommandContext(ctx, "useradd", args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("useradd failed: %v - %s", err, string(output))
@@ -229,7 +226,7 @@ func (um *UserManager) CreateUser(username, password, shell string) error {
 	ctx2, cancel2 := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel2()
 
-	passwdCmd := exec.CommandContext(ctx2, "chpasswd")
+	passwdCmd := CommandContext(ctx2, "chpasswd")
 	passwdCmd.Stdin = strings.NewReader(fmt.Sprintf("%s:%s\n", username, password))
 	output, err = passwdCmd.CombinedOutput()
 	if err != nil {
@@ -252,7 +249,7 @@ func (um *UserManager) CreateUserPasswordless(username, shell string) error {
 		username,
 	}
 
-	cmd := exec.CommandContext(ctx, "useradd", args...)
+	cmd := CommandContext(ctx, "useradd", args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("useradd failed: %v - %s", err, string(output))
@@ -263,7 +260,7 @@ func (um *UserManager) CreateUserPasswordless(username, shell string) error {
 	ctx2, cancel2 := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel2()
 
-	passwdCmd := exec.CommandContext(ctx2, "passwd", "-d", username)
+	passwdCmd := CommandContext(ctx2, "passwd", "-d", username)
 	output, err = passwdCmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("passwd -d failed: %v - %s", err, string(output))
@@ -278,7 +275,7 @@ func (um *UserManager) GrantSudo(username string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "usermod", "-aG", "sudo", username)
+	cmd := CommandContext(ctx, "usermod", "-aG", "sudo", username)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("usermod failed: %v - %s", err, string(output))
@@ -294,7 +291,7 @@ func (um *UserManager) GrantSudoNoPassword(username string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "usermod", "-aG", "sudo", username)
+	cmd := CommandContext(ctx, "usermod", "-aG", "sudo", username)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("usermod failed: %v - %s", err, string(output))
@@ -304,22 +301,11 @@ func (um *UserManager) GrantSudoNoPassword(username string) error {
 	sudoersFile := fmt.Sprintf("/etc/sudoers.d/%s", username)
 	sudoersContent := fmt.Sprintf("%s ALL=(ALL) NOPASSWD:ALL\n", username)
 
-	if err := os.WriteFile(sudoersFile, []byte(sudoersContent), 0440); err != nil {
+	// Validated with visudo before it is installed
+	if err := installSudoersFragment(sudoersFile, sudoersContent); err != nil {
 		return fmt.Errorf("failed to create sudoers file: %v", err)
 	}
 
-	// Validate the sudoers file
-	ctx2, cancel2 := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel2()
-
-	checkCmd := exec.CommandContext(ctx2, "visudo", "-c", "-f", sudoersFile)
-	output, err = checkCmd.CombinedOutput()
-	if err != nil {
-		// Remove invalid file
-		os.Remove(sudoersFile)
-		return fmt.Errorf("sudoers validation failed: %v - %s", err, string(output))
-	}
-
 	return nil
 }
 
@@ -336,7 +322,7 @@ func (um *UserManager) EnablePasswordlessSu(username string) error {
 // RevokeSudoNoPassword removes the NOPASSWD sudoers file for a user
 func (um *UserManager) RevokeSudoNoPassword(username string) error {
 	sudoersFile := fmt.Sprintf("/etc/sudoers.d/%s", username)
-	if err := os.Remove(sudoersFile); err != nil && !os.IsNotExist(err) {
+	if err := Remove(sudoersFile); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove sudoers file: %v", err)
 	}
 	return nil
@@ -348,7 +334,7 @@ func (um *UserManager) RevokeSudo(username string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "gpasswd", "-d", username, "sudo")
+	cmd := CommandContext(ctx, "gpasswd", "-d", username, "sudo")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("gpasswd failed: %v - %s", err, string(output))
@@ -377,7 +363,7 @@ func (um *UserManager) DeleteUser(username string, removeHome bool) error {
 		args = append([]string{"-r"}, args...) // -r removes home directory
 	}
 
-	cmd := exec.CommandContext(ctx, "userdel", args...)
+	cmd := CommandContext(ctx, "userdel", args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("userdel failed: %v - %s", err, string(output))
@@ -388,14 +374,13 @@ func (um *UserManager) DeleteUser(username string, removeHome bool) error {
 
 // GetAllGroups returns all system groups
 func (um *UserManager) GetAllGroups() ([]Group, error) {
-	file, err := os.Open("/etc/group")
+	data, err := ReadFile("/etc/group")
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
 	var groups []Group
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -444,7 +429,7 @@ func (um *UserManager) GetGroup(groupname string) (*Group, error) {
 
 // CreateGroup creates a new group
 func (um *UserManager) CreateGroup(groupname string) error {
-	cmd := exec.Command("groupadd", groupname)
+	cmd := Command("groupadd", groupname)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to create group: %w", err)
 	}
@@ -464,7 +449,7 @@ func (um *UserManager) DeleteGroup(groupname string) error {
 		return fmt.Errorf("group is not empty (has %d members)", len(group.Members))
 	}
 
-	cmd := exec.Command("groupdel", groupname)
+	cmd := Command("groupdel", groupname)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to delete group: %w", err)
 	}
@@ -474,7 +459,7 @@ func (um *UserManager) DeleteGroup(groupname string) error {
 
 // AddUserToGroup adds a user to a group
 func (um *UserManager) AddUserToGroup(username, groupname string) error {
-	cmd := exec.Command("usermod", "-a", "-G", groupname, username)
+	cmd := Command("usermod", "-a", "-G", groupname, username)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to add user to group: %w", err)
 	}
@@ -484,7 +469,7 @@ func (um *UserManager) AddUserToGroup(username, groupname string) error {
 
 // RemoveUserFromGroup removes a user from a group
 func (um *UserManager) RemoveUserFromGroup(username, groupname string) error {
-	cmd := exec.Command("gpasswd", "-d", username, groupname)
+	cmd := Command("gpasswd", "-d", username, groupname)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to remove user from group: %w", err)
 	}
@@ -494,7 +479,7 @@ func (um *UserManager) RemoveUserFromGroup(username, groupname string) error {
 
 // ChangePassword changes a user's password
 func (um *UserManager) ChangePassword(username, newPassword string) error {
-	cmd := exec.Command("chpasswd")
+	cmd := Command("chpasswd")
 	cmd.Stdin = strings.NewReader(fmt.Sprintf("%s:%s\n", username, newPassword))
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to change password: %w", err)
@@ -535,14 +520,14 @@ func (um *UserManager) GetUserSSHKeys(username string) ([]SSHKey, error) {
 	var keys []SSHKey
 
 	// Check if .ssh directory exists
-	if _, err := os.Stat(sshDir); os.IsNotExist(err) {
+	if _, err := Stat(sshDir); os.IsNotExist(err) {
 		return keys, nil
 	}
 
 	// Read authorized_keys to know which keys are for login
 	authorizedKeys := make(map[string]bool)
 	authKeysPath := fmt.Sprintf("%s/authorized_keys", sshDir)
-	if authContent, err := os.ReadFile(authKeysPath); err == nil {
+	if authContent, err := ReadFile(authKeysPath); err == nil {
 		lines := strings.Split(string(authContent), "\n")
 		for _, line := range lines {
 			line = strings.TrimSpace(line)
@@ -557,7 +542,7 @@ func (um *UserManager) GetUserSSHKeys(username string) ([]SSHKey, error) {
 	}
 
 	// Find all key files in .ssh directory
-	files, err := os.ReadDir(sshDir)
+	files, err := ReadDir(sshDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read .ssh directory: %w", err)
 	}
@@ -578,7 +563,7 @@ func (um *UserManager) GetUserSSHKeys(username string) ([]SSHKey, error) {
 			pubKeyPath := fmt.Sprintf("%s/%s", sshDir, name)
 			privKeyPath := strings.TrimSuffix(pubKeyPath, ".pub")
 
-			pubContent, err := os.ReadFile(pubKeyPath)
+			pubContent, err := ReadFile(pubKeyPath)
 			if err != nil {
 				continue
 			}
@@ -597,7 +582,7 @@ func (um *UserManager) GetUserSSHKeys(username string) ([]SSHKey, error) {
 			}
 
 			// Check if private key has passphrase
-			if _, err := os.Stat(privKeyPath); err == nil {
+			if _, err := Stat(privKeyPath); err == nil {
 				keyInfo.HasPassphrase = um.checkKeyHasPassphrase(privKeyPath)
 			}
 
@@ -650,7 +635,7 @@ func (um *UserManager) getKeyFingerprint(pubKey string) string {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "ssh-keygen", "-lf", "-")
+	cmd := CommandContext(ctx, "ssh-keygen", "-lf", "-")
 	cmd.Stdin = strings.NewReader(pubKey)
 	output, err := cmd.Output()
 	if err != nil {
@@ -671,7 +656,7 @@ func (um *UserManager) checkKeyHasPassphrase(privKeyPath string) bool {
 	defer cancel()
 
 	// Try to read the key with empty passphrase
-	cmd := exec.CommandContext(ctx, "ssh-keygen", "-y", "-P", "", "-f", privKeyPath)
+	cmd := CommandContext(ctx, "ssh-keygen", "-y", "-P", "", "-f", privKeyPath)
 	err := cmd.Run()
 	// If it fails, the key has a passphrase
 	return err != nil
@@ -687,14 +672,14 @@ func (um *UserManager) GenerateSSHKey(username string, keyType SSHKeyType, ident
 	sshDir := fmt.Sprintf("%s/.ssh", user.HomeDir)
 
 	// Create .ssh directory if it doesn't exist
-	if _, err := os.Stat(sshDir); os.IsNotExist(err) {
-		if err := os.MkdirAll(sshDir, 0700); err != nil {
+	if _, err := Stat(sshDir); os.IsNotExist(err) {
+		if err := MkdirAll(sshDir, 0700); err != nil {
 			return "", fmt.Errorf("failed to create .ssh directory: %w", err)
 		}
 		// Set ownership
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		cmd := exec.CommandContext(ctx, "chown", "-R", fmt.Sprintf("%s:%s", username, username), sshDir)
+		cmd := CommandContext(ctx, "chown", "-R", fmt.Sprintf("%s:%s", username, username), sshDir)
 		cmd.Run()
 	}
 
@@ -705,7 +690,7 @@ func (um *UserManager) GenerateSSHKey(username string, keyType SSHKeyType, ident
 	keyPath := fmt.Sprintf("%s/%s", sshDir, keyName)
 
 	// Check if key already exists
-	if _, err := os.Stat(keyPath); err == nil {
+	if _, err := Stat(keyPath); err == nil {
 		return "", fmt.Errorf("key with name %s already exists", keyName)
 	}
 
@@ -733,7 +718,7 @@ func (um *UserManager) GenerateSSHKey(username string, keyType SSHKeyType, ident
 		args = append(args, "-b", fmt.Sprintf("%d", bits))
 	}
 
-	cmd := exec.CommandContext(ctx, "ssh-keygen", args...)
+	cmd := CommandContext(ctx, "ssh-keygen", args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return "", fmt.Errorf("failed to generate SSH key: %v - %s", err, string(output))
@@ -742,12 +727,12 @@ func (um *UserManager) GenerateSSHKey(username string, keyType SSHKeyType, ident
 	// Set proper ownership
 	ctx2, cancel2 := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel2()
-	chownCmd := exec.CommandContext(ctx2, "chown", fmt.Sprintf("%s:%s", username, username), keyPath, keyPath+".pub")
+	chownCmd := CommandContext(ctx2, "chown", fmt.Sprintf("%s:%s", username, username), keyPath, keyPath+".pub")
 	chownCmd.Run()
 
 	// Set proper permissions
-	os.Chmod(keyPath, 0600)
-	os.Chmod(keyPath+".pub", 0644)
+	Chmod(keyPath, 0600)
+	Chmod(keyPath+".pub", 0644)
 
 	return keyPath, nil
 }
@@ -763,28 +748,21 @@ func (um *UserManager) AddKeyToAuthorizedKeys(username string, pubKeyPath string
 	authKeysPath := fmt.Sprintf("%s/authorized_keys", sshDir)
 
 	// Read the public key
-	pubKey, err := os.ReadFile(pubKeyPath)
+	pubKey, err := ReadFile(pubKeyPath)
 	if err != nil {
 		return fmt.Errorf("failed to read public key: %w", err)
 	}
 
-	// Create authorized_keys file if it doesn't exist
-	file, err := os.OpenFile(authKeysPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to open authorized_keys: %w", err)
-	}
-	defer file.Close()
-
-	// Add the key
+	// Add the key (creates authorized_keys if it doesn't exist)
 	keyContent := strings.TrimSpace(string(pubKey))
-	if _, err := file.WriteString(keyContent + "\n"); err != nil {
+	if err := AppendFile(authKeysPath, []byte(keyContent+"\n"), 0600); err != nil {
 		return fmt.Errorf("failed to write to authorized_keys: %w", err)
 	}
 
 	// Set proper ownership
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	cmd := exec.CommandContext(ctx, "chown", fmt.Sprintf("%s:%s", username, username), authKeysPath)
+	cmd := CommandContext(ctx, "chown", fmt.Sprintf("%s:%s", username, username), authKeysPath)
 	cmd.Run()
 
 	return nil
@@ -799,7 +777,7 @@ func (um *UserManager) RemoveKeyFromAuthorizedKeys(username string, fingerprint
 
 	authKeysPath := fmt.Sprintf("%s/.ssh/authorized_keys", user.HomeDir)
 
-	content, err := os.ReadFile(authKeysPath)
+	content, err := ReadFile(authKeysPath)
 	if err != nil {
 		return fmt.Errorf("failed to read authorized_keys: %w", err)
 	}
@@ -821,7 +799,7 @@ func (um *UserManager) RemoveKeyFromAuthorizedKeys(username string, fingerprint
 	}
 
 	// Write back
-	err = os.WriteFile(authKeysPath, []byte(strings.Join(newLines, "\n")+"\n"), 0600)
+	err = WriteFile(authKeysPath, []byte(strings.Join(newLines, "\n")+"\n"), 0600)
 	if err != nil {
 		return fmt.Errorf("failed to write authorized_keys: %w", err)
 	}
@@ -861,7 +839,7 @@ echo "SSH_AUTH_SOCK=$SSH_AUTH_SOCK"
 	defer cancel()
 
 	// Run as the target user
-	cmd := exec.CommandContext(ctx, "su", "-", username, "-c", script)
+	cmd := CommandContext(ctx, "su", "-", username, "-c", script)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to add key to ssh-agent: %v - %s", err, string(output))
@@ -911,7 +889,7 @@ func (um *UserManager) saveSSHAgentSocket(username string, output []byte) {
 
 	// Add to .bashrc if not already there
 	bashrcPath := fmt.Sprintf("%s/.bashrc", user.HomeDir)
-	content, err := os.ReadFile(bashrcPath)
+	content, err := ReadFile(bashrcPath)
 	if err != nil {
 		return
 	}
@@ -929,15 +907,10 @@ func (um *UserManager) saveSSHAgentSocket(username string, output []byte) {
 				newLines = append(newLines, line)
 			}
 		}
-		os.WriteFile(bashrcPath, []byte(strings.Join(newLines, "\n")), 0644)
+		WriteFile(bashrcPath, []byte(strings.Join(newLines, "\n")), 0644)
 	} else {
 		// Add new
-		f, err := os.OpenFile(bashrcPath, os.O_APPEND|os.O_WRONLY, 0644)
-		if err != nil {
-			return
-		}
-		defer f.Close()
-		f.WriteString(fmt.Sprintf("\n# SSH Agent (added by Ravact)\n%s\n", agentExport))
+		AppendFile(bashrcPath, []byte(fmt.Sprintf("\n# SSH Agent (added by Ravact)\n%s\n", agentExport)), 0644)
 	}
 }
 
@@ -949,7 +922,7 @@ func (um *UserManager) DeleteSSHKey(pubKeyPath string) error {
 	username := um.extractUsernameFromPath(pubKeyPath)
 
 	// Remove from authorized_keys first if present
-	pubContent, err := os.ReadFile(pubKeyPath)
+	pubContent, err := ReadFile(pubKeyPath)
 	if err == nil {
 		keyInfo := um.parseSSHPublicKey(string(pubContent))
 		if keyInfo.Fingerprint != "" && username != "" {
@@ -963,12 +936,12 @@ func (um *UserManager) DeleteSSHKey(pubKeyPath string) error {
 	}
 
 	// Delete private key
-	if err := os.Remove(privKeyPath); err != nil && !os.IsNotExist(err) {
+	if err := Remove(privKeyPath); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to delete private key: %w", err)
 	}
 
 	// Delete public key
-	if err := os.Remove(pubKeyPath); err != nil && !os.IsNotExist(err) {
+	if err := Remove(pubKeyPath); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to delete public key: %w", err)
 	}
 
@@ -978,7 +951,7 @@ func (um *UserManager) DeleteSSHKey(pubKeyPath string) error {
 // IsKeyInSSHAgent checks if a key is loaded in the SSH agent
 func (um *UserManager) IsKeyInSSHAgent(pubKeyPath string, username string) bool {
 	// Get the fingerprint of the key
-	pubContent, err := os.ReadFile(pubKeyPath)
+	pubContent, err := ReadFile(pubKeyPath)
 	if err != nil {
 		return false
 	}
@@ -996,7 +969,7 @@ func (um *UserManager) IsKeyInSSHAgent(pubKeyPath string, username string) bool
 	// Try to find the SSH_AUTH_SOCK from user's environment
 	// Check common locations and user's bashrc
 	bashrcPath := fmt.Sprintf("%s/.bashrc", user.HomeDir)
-	bashrcContent, err := os.ReadFile(bashrcPath)
+	bashrcContent, err := ReadFile(bashrcPath)
 	if err != nil {
 		return false
 	}
@@ -1021,7 +994,7 @@ func (um *UserManager) IsKeyInSSHAgent(pubKeyPath string, username string) bool
 	}
 
 	// Check if the socket file exists (agent is running)
-	if _, err := os.Stat(agentSocket); os.IsNotExist(err) {
+	if _, err := Stat(agentSocket); os.IsNotExist(err) {
 		return false
 	}
 
@@ -1034,7 +1007,7 @@ ssh-add -l 2>/dev/null || echo ""
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "su", "-", username, "-c", script)
+	cmd := CommandContext(ctx, "su", "-", username, "-c", script)
 	output, err := cmd.Output()
 	if err != nil {
 		return false
@@ -1057,7 +1030,7 @@ ssh-add -d "%s" 2>/dev/null || true
 	defer cancel()
 
 	// Run as the target user
-	cmd := exec.CommandContext(ctx, "su", "-", username, "-c", script)
+	cmd := CommandContext(ctx, "su", "-", username, "-c", script)
 	cmd.Run() // Ignore errors - key might not be in agent
 
 	return nil
@@ -1067,7 +1040,7 @@ ssh-add -d "%s" 2>/dev/null || true
 func (um *UserManager) DisablePasswordSSHLogin(username string) error {
 	sshdConfig := "/etc/ssh/sshd_config"
 
-	content, err := os.ReadFile(sshdConfig)
+	content, err := ReadFile(sshdConfig)
 	if err != nil {
 		return fmt.Errorf("failed to read sshd_config: %w", err)
 	}
@@ -1097,7 +1070,7 @@ func (um *UserManager) DisablePasswordSSHLogin(username string) error {
 	}
 
 	// Write back
-	if err := os.WriteFile(sshdConfig, []byte(strings.Join(newLines, "\n")), 0644); err != nil {
+	if err := WriteFile(sshdConfig, []byte(strings.Join(newLines, "\n")), 0644); err != nil {
 		return fmt.Errorf("failed to write sshd_config: %w", err)
 	}
 
@@ -1116,8 +1089,8 @@ func (um *UserManager) DisableSSHKeyLogin(username string) error {
 
 	// Rename authorized_keys to authorized_keys.disabled
 	disabledPath := authKeysPath + ".disabled"
-	if _, err := os.Stat(authKeysPath); err == nil {
-		if err := os.Rename(authKeysPath, disabledPath); err != nil {
+	if _, err := Stat(authKeysPath); err == nil {
+		if err := Rename(authKeysPath, disabledPath); err != nil {
 			return fmt.Errorf("failed to disable authorized_keys: %w", err)
 		}
 	}
@@ -1136,8 +1109,8 @@ func (um *UserManager) EnableSSHKeyLogin(username string) error {
 	disabledPath := authKeysPath + ".disabled"
 
 	// Rename authorized_keys.disabled back to authorized_keys
-	if _, err := os.Stat(disabledPath); err == nil {
-		if err := os.Rename(disabledPath, authKeysPath); err != nil {
+	if _, err := Stat(disabledPath); err == nil {
+		if err := Rename(disabledPath, authKeysPath); err != nil {
 			return fmt.Errorf("failed to enable authorized_keys: %w", err)
 		}
 	}
@@ -1153,13 +1126,13 @@ func (um *UserManager) IsSSHKeyLoginDisabled(username string) bool {
 	}
 
 	disabledPath := fmt.Sprintf("%s/.ssh/authorized_keys.disabled", user.HomeDir)
-	_, err = os.Stat(disabledPath)
+	_, err = Stat(disabledPath)
 	return err == nil
 }
 
 // IsPasswordSSHLoginDisabled checks if password SSH login is disabled globally
 func (um *UserManager) IsPasswordSSHLoginDisabled() bool {
-	content, err := os.ReadFile("/etc/ssh/sshd_config")
+	content, err := ReadFile("/etc/ssh/sshd_config")
 	if err != nil {
 		return false
 	}
@@ -1182,7 +1155,7 @@ func (um *UserManager) IsPasswordSSHLoginDisabled() bool {
 func (um *UserManager) EnablePasswordSSHLogin() error {
 	sshdConfig := "/etc/ssh/sshd_config"
 
-	content, err := os.ReadFile(sshdConfig)
+	content, err := ReadFile(sshdConfig)
 	if err != nil {
 		return fmt.Errorf("failed to read sshd_config: %w", err)
 	}
@@ -1212,7 +1185,7 @@ func (um *UserManager) EnablePasswordSSHLogin() error {
 	}
 
 	// Write back
-	if err := os.WriteFile(sshdConfig, []byte(strings.Join(newLines, "\n")), 0644); err != nil {
+	if err := WriteFile(sshdConfig, []byte(strings.Join(newLines, "\n")), 0644); err != nil {
 		return fmt.Errorf("failed to write sshd_config: %w", err)
 	}
 
@@ -1226,15 +1199,15 @@ func (um *UserManager) reloadSSHService() error {
 	defer cancel()
 
 	// Try systemctl first (modern systems)
-	cmd := exec.CommandContext(ctx, "systemctl", "reload", "sshd")
+	cmd := CommandContext(ctx, "systemctl", "reload", "sshd")
 	if err := cmd.Run(); err != nil {
 		// Try ssh instead of sshd
-		cmd = exec.CommandContext(ctx, "systemctl", "reload", "ssh")
+		cmd = CommandContext(ctx, "systemctl", "reload", "ssh")
 		if err := cmd.Run(); err != nil {
 			// Try service command (older systems)
-			cmd = exec.CommandContext(ctx, "service", "ssh", "reload")
+			cmd = CommandContext(ctx, "service", "ssh", "reload")
 			if err := cmd.Run(); err != nil {
-				cmd = exec.CommandContext(ctx, "service", "sshd", "reload")
+				cmd = CommandContext(ctx, "service", "sshd", "reload")
 				return cmd.Run()
 			}
 		}
@@ -0,0 +1,276 @@
+package system
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SiteWebhookFile is the webhook config inside a site's directory
+const SiteWebhookFile = "webhook.yaml"
+
+// SiteDeployHookFile is a site's deploy script inside its directory. When
+// present, webhook deploys run it instead of pulling.
+const SiteDeployHookFile = "deploy.sh"
+
+// webhookDeployTimeout bounds one webhook deploy
+const webhookDeployTimeout = 15 * time.Minute
+
+// Webhook request errors
+var (
+	ErrWebhookSignature = errors.New("invalid webhook signature or token")
+	ErrWebhookIgnored   = errors.New("not a push event")
+)
+
+// SiteWebhook configures deploys of a site on GitHub/GitLab push webhooks
+type SiteWebhook struct {
+	Secret     string `yaml:"secret"`
+	Repository string `yaml:"repository,omitempty"` // owner/name; any repository when empty
+	Branch     string `yaml:"branch"`               // Pushes to other branches are ignored
+	Directory  string `yaml:"directory"`            // The git checkout to deploy
+	User       string `yaml:"user,omitempty"`       // Runs the deploy; defaults to meta.systemuser
+}
+
+// WebhookPush is the part of a push event a deploy needs
+type WebhookPush struct {
+	Provider   string // "github" or "gitlab"
+	Repository string // owner/name
+	Ref        string // e.g. refs/heads/main
+	After      string // Commit pushed
+	Pusher     string
+}
+
+// Validate checks a webhook config
+func (w SiteWebhook) Validate() error {
+	if len(w.Secret) < 16 {
+		return fmt.Errorf("webhook secret must be at least 16 characters")
+	}
+	if w.Branch == "" {
+		return fmt.Errorf("webhook branch is required")
+	}
+	if !filepath.IsAbs(w.Directory) {
+		return fmt.Errorf("webhook directory must be an absolute path")
+	}
+	if w.User == "root" {
+		return fmt.Errorf("webhook deploys cannot run as root")
+	}
+	return nil
+}
+
+// Matches reports whether a push should deploy the site
+func (w SiteWebhook) Matches(p WebhookPush) bool {
+	if p.Ref != "refs/heads/"+w.Branch {
+		return false
+	}
+	return w.Repository == "" || strings.EqualFold(w.Repository, p.Repository)
+}
+
+// GenerateWebhookSecret returns a random secret for a new webhook
+func GenerateWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// SiteWebhookPath returns the path of a site's webhook config
+func SiteWebhookPath(siteKey string) (string, error) {
+	dir, err := SiteDir(siteKey)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, SiteWebhookFile), nil
+}
+
+// LoadSiteWebhook returns a site's webhook config, or nil if none is set
+func LoadSiteWebhook(siteKey string) (*SiteWebhook, error) {
+	path, err := SiteWebhookPath(siteKey)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook config: %w", err)
+	}
+	var w SiteWebhook
+	if err := yaml.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &w, nil
+}
+
+// SaveSiteWebhook writes a site's webhook config, readable by root only
+// since it holds the secret
+func SaveSiteWebhook(siteKey string, w SiteWebhook) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	path, err := SiteWebhookPath(siteKey)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(w)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook config: %w", err)
+	}
+	if err := MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write webhook config: %w", err)
+	}
+	return nil
+}
+
+// ParseWebhookPush authenticates a GitHub or GitLab webhook request and
+// reads its push event. GitHub requests carry an HMAC-SHA256 signature of
+// the body and GitLab requests the secret itself as a token. Other events,
+// such as GitHub's ping, return ErrWebhookIgnored once authenticated. An
+// empty secret authenticates nothing.
+func ParseWebhookPush(header http.Header, body []byte, secret string) (*WebhookPush, error) {
+	if secret == "" {
+		return nil, ErrWebhookSignature
+	}
+	switch {
+	case header.Get("X-GitHub-Event") != "":
+		sig, ok := strings.CutPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+		want, err := hex.DecodeString(sig)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		if !ok || err != nil || !hmac.Equal(want, mac.Sum(nil)) {
+			return nil, ErrWebhookSignature
+		}
+		if header.Get("X-GitHub-Event") != "push" {
+			return nil, ErrWebhookIgnored
+		}
+		var event struct {
+			Ref        string `json:"ref"`
+			After      string `json:"after"`
+			Repository struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+			Pusher struct {
+				Name string `json:"name"`
+			} `json:"pusher"`
+		}
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse push event: %w", err)
+		}
+		return &WebhookPush{Provider: "github", Repository: event.Repository.FullName, Ref: event.Ref, After: event.After, Pusher: event.Pusher.Name}, nil
+
+	case header.Get("X-Gitlab-Event") != "":
+		if subtle.ConstantTimeCompare([]byte(header.Get("X-Gitlab-Token")), []byte(secret)) != 1 {
+			return nil, ErrWebhookSignature
+		}
+		if header.Get("X-Gitlab-Event") != "Push Hook" {
+			return nil, ErrWebhookIgnored
+		}
+		var event struct {
+			Ref      string `json:"ref"`
+			After    string `json:"after"`
+			UserName string `json:"user_username"`
+			Project  struct {
+				PathWithNamespace string `json:"path_with_namespace"`
+			} `json:"project"`
+		}
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse push event: %w", err)
+		}
+		return &WebhookPush{Provider: "gitlab", Repository: event.Project.PathWithNamespace, Ref: event.Ref, After: event.After, Pusher: event.UserName}, nil
+	}
+	return nil, fmt.Errorf("not a GitHub or GitLab webhook")
+}
+
+// deployScript returns the site's deploy script, or the pull that follows
+// the checkout's meta.deployref and its build step when the site has none
+func (w SiteWebhook) deployScript(siteKey string) (string, error) {
+	dir, err := SiteDir(siteKey)
+	if err != nil {
+		return "", err
+	}
+	script := "set -e\ncd " + ShellQuote(w.Directory) + "\n"
+	if hook, err := ReadFile(filepath.Join(dir, SiteDeployHookFile)); err == nil {
+		return script + string(hook), nil
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read deploy hook: %w", err)
+	}
+	ref, _ := Command("git", "-C", w.Directory, "config", "--get", "meta.deployref").Output()
+	script += GitDeployCommand(strings.TrimSpace(string(ref))) + "\n"
+	if build := ReadBuildStep(w.Directory).Script(); build != "" {
+		script += build + "\n"
+	}
+	return script, nil
+}
+
+// deployUser returns who the deploy runs as: the configured user, else the
+// checkout's meta.systemuser. The listener runs as root, so a deploy with
+// neither, or with root, is refused rather than run as root.
+func (w SiteWebhook) deployUser() (string, error) {
+	user := w.User
+	if user == "" {
+		output, _ := Command("git", "-C", w.Directory, "config", "--get", "meta.systemuser").Output()
+		user = strings.TrimSpace(string(output))
+	}
+	switch user {
+	case "":
+		return "", fmt.Errorf("no deploy user: set the webhook's user or the checkout's meta.systemuser")
+	case "root":
+		return "", fmt.Errorf("refusing to deploy as root; set the webhook's user to the site's system user")
+	}
+	return user, nil
+}
+
+// RunWebhookDeploy deploys a site for a push and records the run in the
+// execution history
+func RunWebhookDeploy(siteKey string, w SiteWebhook, push WebhookPush) ExecutionRecord {
+	rec := ExecutionRecord{
+		Description: fmt.Sprintf("Deploy %s (%s push to %s by %s)", siteKey, push.Provider, strings.TrimPrefix(push.Ref, "refs/heads/"), push.Pusher),
+		Source:      "webhook",
+		Site:        siteKey,
+		StartedAt:   time.Now(),
+	}
+	if len(push.After) >= 7 {
+		rec.Description += " at " + push.After[:7]
+	}
+
+	user, err := w.deployUser()
+	var script string
+	if err == nil {
+		script, err = w.deployScript(siteKey)
+	}
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookDeployTimeout)
+		defer cancel()
+		cmd := CommandContext(ctx, "sudo", "-H", "-u", user, "bash", "-s")
+		cmd.Stdin = strings.NewReader(script)
+		var output []byte
+		output, err = cmd.CombinedOutput()
+		rec.Output = string(output)
+	}
+	rec.Duration = time.Since(rec.StartedAt)
+	rec.Success = err == nil
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	if err := RecordExecution(rec); err != nil {
+		rec.Error = strings.TrimSpace(rec.Error + "; failed to record: " + err.Error())
+	}
+	return rec
+}
@@ -0,0 +1,153 @@
+package system
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testWebhookSecret = "0123456789abcdef0123"
+
+func githubHeader(event string, body []byte, secret string) http.Header {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	h := http.Header{}
+	h.Set("X-GitHub-Event", event)
+	h.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return h
+}
+
+func TestParseWebhookPushGitHub(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/main","after":"abc1234def","repository":{"full_name":"acme/shop"},"pusher":{"name":"jo"}}`)
+
+	push, err := ParseWebhookPush(githubHeader("push", body, testWebhookSecret), body, testWebhookSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := WebhookPush{Provider: "github", Repository: "acme/shop", Ref: "refs/heads/main", After: "abc1234def", Pusher: "jo"}
+	if *push != want {
+		t.Errorf("got %+v, want %+v", *push, want)
+	}
+
+	if _, err := ParseWebhookPush(githubHeader("push", body, "wrong-secret-wrong"), body, testWebhookSecret); !errors.Is(err, ErrWebhookSignature) {
+		t.Errorf("expected a signature error, got %v", err)
+	}
+	if _, err := ParseWebhookPush(githubHeader("ping", body, testWebhookSecret), body, testWebhookSecret); !errors.Is(err, ErrWebhookIgnored) {
+		t.Errorf("expected ping to be ignored, got %v", err)
+	}
+}
+
+func TestParseWebhookPushGitLab(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/release","after":"9f8e7d6","user_username":"sam","project":{"path_with_namespace":"acme/api"}}`)
+	h := http.Header{}
+	h.Set("X-Gitlab-Event", "Push Hook")
+	h.Set("X-Gitlab-Token", testWebhookSecret)
+
+	push, err := ParseWebhookPush(h, body, testWebhookSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if push.Repository != "acme/api" || push.Ref != "refs/heads/release" || push.Pusher != "sam" {
+		t.Errorf("unexpected push %+v", *push)
+	}
+
+	h.Set("X-Gitlab-Token", "nope")
+	if _, err := ParseWebhookPush(h, body, testWebhookSecret); !errors.Is(err, ErrWebhookSignature) {
+		t.Errorf("expected a token error, got %v", err)
+	}
+}
+
+func TestParseWebhookPushEmptySecret(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/main"}`)
+	if _, err := ParseWebhookPush(githubHeader("push", body, ""), body, ""); !errors.Is(err, ErrWebhookSignature) {
+		t.Errorf("expected GitHub with an empty secret to be rejected, got %v", err)
+	}
+	h := http.Header{}
+	h.Set("X-Gitlab-Event", "Push Hook")
+	if _, err := ParseWebhookPush(h, body, ""); !errors.Is(err, ErrWebhookSignature) {
+		t.Errorf("expected GitLab with an empty secret to be rejected, got %v", err)
+	}
+}
+
+func TestSiteWebhookDeployUser(t *testing.T) {
+	if user, err := (SiteWebhook{User: "shop", Directory: t.TempDir()}).deployUser(); err != nil || user != "shop" {
+		t.Errorf("got %q, %v", user, err)
+	}
+	if _, err := (SiteWebhook{User: "root", Directory: t.TempDir()}).deployUser(); err == nil {
+		t.Error("expected root to be refused")
+	}
+	if _, err := (SiteWebhook{Directory: t.TempDir()}).deployUser(); err == nil {
+		t.Error("expected a checkout without meta.systemuser to be refused")
+	}
+}
+
+func TestSiteWebhookMatches(t *testing.T) {
+	w := SiteWebhook{Repository: "Acme/Shop", Branch: "main"}
+	cases := []struct {
+		push WebhookPush
+		want bool
+	}{
+		{WebhookPush{Repository: "acme/shop", Ref: "refs/heads/main"}, true},
+		{WebhookPush{Repository: "acme/shop", Ref: "refs/heads/dev"}, false},
+		{WebhookPush{Repository: "acme/shop", Ref: "refs/tags/main"}, false},
+		{WebhookPush{Repository: "other/shop", Ref: "refs/heads/main"}, false},
+	}
+	for _, c := range cases {
+		if got := w.Matches(c.push); got != c.want {
+			t.Errorf("Matches(%+v) = %v, want %v", c.push, got, c.want)
+		}
+	}
+	if !(SiteWebhook{Branch: "main"}).Matches(WebhookPush{Repository: "any/repo", Ref: "refs/heads/main"}) {
+		t.Error("expected any repository to match when none is configured")
+	}
+}
+
+func TestSiteWebhookRoundTrip(t *testing.T) {
+	originalData := SiteDataDir
+	SiteDataDir = t.TempDir()
+	defer func() { SiteDataDir = originalData }()
+
+	if w, err := LoadSiteWebhook("shop.example.com"); err != nil || w != nil {
+		t.Fatalf("expected no webhook, got %+v, %v", w, err)
+	}
+	if err := SaveSiteWebhook("shop.example.com", SiteWebhook{Secret: "short", Branch: "main", Directory: "/var/www/shop"}); err == nil {
+		t.Error("expected a short secret to be rejected")
+	}
+	w := SiteWebhook{Secret: testWebhookSecret, Branch: "main", Directory: "/var/www/shop"}
+	if err := SaveSiteWebhook("shop.example.com", w); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := LoadSiteWebhook("shop.example.com"); err != nil || *got != w {
+		t.Errorf("got %+v, %v", got, err)
+	}
+}
+
+func TestRecordExecution(t *testing.T) {
+	originalState := StateDir
+	StateDir = filepath.Join(t.TempDir(), "state")
+	defer func() { StateDir = originalState }()
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := 0; i < executionHistoryLimit+3; i++ {
+		rec := ExecutionRecord{Description: fmt.Sprintf("run %d", i), Source: "webhook", StartedAt: start.Add(time.Duration(i) * time.Second), Success: true}
+		if err := RecordExecution(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	records, err := LoadExecutions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != executionHistoryLimit {
+		t.Fatalf("expected %d records, got %d", executionHistoryLimit, len(records))
+	}
+	if records[0].Description != fmt.Sprintf("run %d", executionHistoryLimit+2) || records[len(records)-1].Description != "run 3" {
+		t.Errorf("expected newest first with the oldest dropped, got %q ... %q", records[0].Description, records[len(records)-1].Description)
+	}
+}
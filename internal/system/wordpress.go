@@ -0,0 +1,261 @@
+package system
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// WPCLIPath is where wp-cli is installed
+const WPCLIPath = "/usr/local/bin/wp"
+
+// wpCLIVersion is the wp-cli release WPCLIInstallScript installs
+const wpCLIVersion = "2.11.0"
+
+// wpSaltKeys are the secret keys and salts of wp-config.php
+var wpSaltKeys = []string{
+	"AUTH_KEY", "SECURE_AUTH_KEY", "LOGGED_IN_KEY", "NONCE_KEY",
+	"AUTH_SALT", "SECURE_AUTH_SALT", "LOGGED_IN_SALT", "NONCE_SALT",
+}
+
+// wpSaltChars are the characters of the keys WordPress' secret-key service
+// generates, without the quote and backslash that would need escaping
+const wpSaltChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*()-_ []{}<>~`+=,.;:/?|"
+
+const passwordChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+var (
+	wpLocalePattern = regexp.MustCompile(`^[a-z]{2,3}(_[A-Z]{2})?(_[a-z]+)?$`)
+	wpAdminPattern  = regexp.MustCompile(`^[A-Za-z0-9._@-]{1,60}$`)
+)
+
+// randomString returns n characters drawn uniformly from chars
+func randomString(n int, chars string) (string, error) {
+	b := make([]byte, n)
+	max := big.NewInt(int64(len(chars)))
+	for i := range b {
+		idx, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate random data: %w", err)
+		}
+		b[i] = chars[idx.Int64()]
+	}
+	return string(b), nil
+}
+
+// GeneratePassword returns a random alphanumeric password
+func GeneratePassword(length int) (string, error) {
+	return randomString(length, passwordChars)
+}
+
+// WordPressSite describes a WordPress install to provision
+type WordPressSite struct {
+	Dir           string // Document root; WordPress is installed here
+	Owner         string // Owns the files; PHP-FPM reads them through WebGroup
+	URL           string // Site URL, https:// when a certificate is requested
+	Title         string
+	Locale        string // Such as en_US or de_DE
+	AdminUser     string
+	AdminEmail    string
+	AdminPassword string
+	DBName        string
+	DBUser        string
+	DBPassword    string
+	DBHost        string // Defaults to localhost
+}
+
+// Validate checks the fields that end up in the provisioning script
+func (w WordPressSite) Validate() error {
+	if !filepath.IsAbs(w.Dir) || filepath.Clean(w.Dir) == "/" {
+		return fmt.Errorf("directory must be an absolute path below /")
+	}
+	if !usernamePattern.MatchString(w.Owner) {
+		return fmt.Errorf("invalid owner %q", w.Owner)
+	}
+	if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid site URL %q", w.URL)
+	}
+	if strings.TrimSpace(w.Title) == "" {
+		return fmt.Errorf("site title is required")
+	}
+	if w.Locale != "" && !wpLocalePattern.MatchString(w.Locale) {
+		return fmt.Errorf("invalid locale %q (use a code such as en_US)", w.Locale)
+	}
+	if !wpAdminPattern.MatchString(w.AdminUser) {
+		return fmt.Errorf("invalid admin username %q", w.AdminUser)
+	}
+	if !strings.Contains(w.AdminEmail, "@") {
+		return fmt.Errorf("invalid admin email %q", w.AdminEmail)
+	}
+	if w.AdminPassword == "" || w.DBPassword == "" {
+		return fmt.Errorf("passwords are required")
+	}
+	if err := CheckName(NamingDatabase, w.DBName); err != nil {
+		return err
+	}
+	if !databaseNamePattern.MatchString(w.DBUser) {
+		return fmt.Errorf("invalid database user %q", w.DBUser)
+	}
+	return nil
+}
+
+// phpString quotes s as a single-quoted PHP string
+func phpString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// WordPressConfig returns wp-config.php for the site with freshly
+// generated keys and salts, so install does not depend on the
+// api.wordpress.org secret-key service
+func (w WordPressSite) WordPressConfig() (string, error) {
+	host := w.DBHost
+	if host == "" {
+		host = "localhost"
+	}
+	var b strings.Builder
+	b.WriteString("<?php\n// Generated by ravact\n\n")
+	for _, kv := range [][2]string{
+		{"DB_NAME", w.DBName},
+		{"DB_USER", w.DBUser},
+		{"DB_PASSWORD", w.DBPassword},
+		{"DB_HOST", host},
+		{"DB_CHARSET", "utf8mb4"},
+		{"DB_COLLATE", ""},
+	} {
+		fmt.Fprintf(&b, "define( %s, %s );\n", phpString(kv[0]), phpString(kv[1]))
+	}
+	b.WriteString("\n")
+	for _, key := range wpSaltKeys {
+		salt, err := randomString(64, wpSaltChars)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "define( %s, %s );\n", phpString(key), phpString(salt))
+	}
+	b.WriteString(`
+$table_prefix = 'wp_';
+
+define( 'WP_DEBUG', false );
+define( 'DISALLOW_FILE_EDIT', true );
+define( 'FS_METHOD', 'direct' );
+
+if ( ! defined( 'ABSPATH' ) ) {
+	define( 'ABSPATH', __DIR__ . '/' );
+}
+
+require_once ABSPATH . 'wp-settings.php';
+`)
+	return b.String(), nil
+}
+
+// WPCLIInstallScript returns bash that installs wp-cli if it is missing,
+// checking the phar against the SHA-512 published with the release
+func WPCLIInstallScript() string {
+	base := fmt.Sprintf("https://github.com/wp-cli/wp-cli/releases/download/v%s/wp-cli-%s.phar", wpCLIVersion, wpCLIVersion)
+	return fmt.Sprintf(`if ! command -v wp >/dev/null 2>&1; then
+  echo "Installing wp-cli %s"
+  curl --fail --location --silent --show-error --output /tmp/wp-cli.phar %s
+  expected=$(curl --fail --location --silent %s.sha512 | awk '{print $1}')
+  actual=$(sha512sum /tmp/wp-cli.phar | awk '{print $1}')
+  if [ -z "$expected" ] || [ "$actual" != "$expected" ]; then
+    rm -f /tmp/wp-cli.phar
+    echo "✗ wp-cli checksum did not match the published SHA-512" >&2
+    exit 1
+  fi
+  install -m 0755 /tmp/wp-cli.phar %s
+  rm -f /tmp/wp-cli.phar
+  echo "✓ wp-cli installed (SHA-512 verified)"
+fi
+`, wpCLIVersion, base, base, WPCLIPath)
+}
+
+// WordPressPermissionsScript returns bash that gives the owner the files,
+// lets PHP-FPM read them, and lets it write only uploads and the cache
+func WordPressPermissionsScript(dir, owner string) string {
+	d := ShellQuote(dir)
+	return fmt.Sprintf(`chown -R %s:%s %s
+find %s -type d -exec chmod 755 {} +
+find %s -type f -exec chmod 644 {} +
+chmod 640 %s/wp-config.php
+mkdir -p %s/wp-content/uploads %s/wp-content/cache
+chown -R %s:%s %s/wp-content/uploads %s/wp-content/cache
+chmod -R g+w %s/wp-content/uploads %s/wp-content/cache
+find %s/wp-content/uploads %s/wp-content/cache -type d -exec chmod g+s {} +
+`, ShellQuote(owner), WebGroup, d, d, d, d, d, d, ShellQuote(owner), WebGroup, d, d, d, d, d, d)
+}
+
+// ProvisionScript returns bash that downloads WordPress core into Dir with
+// wp-cli, writes wp-config.php, installs the site, and sets permissions.
+// The database must already exist (see MySQLManager.CreateDatabase). The
+// passwords are not printed; the caller keeps them in the secrets vault.
+func (w WordPressSite) ProvisionScript() (string, error) {
+	if err := w.Validate(); err != nil {
+		return "", err
+	}
+	config, err := w.WordPressConfig()
+	if err != nil {
+		return "", err
+	}
+	locale := w.Locale
+	if locale == "" {
+		locale = "en_US"
+	}
+	dir := ShellQuote(w.Dir)
+	wp := fmt.Sprintf("sudo -u %s -H %s --path=%s", ShellQuote(w.Owner), WPCLIPath, dir)
+
+	var b strings.Builder
+	b.WriteString("#!/bin/bash\nset -e\n")
+	b.WriteString(WPCLIInstallScript())
+	fmt.Fprintf(&b, "mkdir -p %s\nchown %s %s\n", dir, ShellQuote(w.Owner), dir)
+	fmt.Fprintf(&b, "if [ ! -f %s/wp-load.php ]; then\n  %s core download --locale=%s\nfi\n", dir, wp, ShellQuote(locale))
+	fmt.Fprintf(&b, "if [ ! -f %s/wp-config.php ]; then\n  cat > %s/wp-config.php <<'RAVACT_WP_CONFIG'\n%sRAVACT_WP_CONFIG\nfi\n", dir, dir, config)
+	fmt.Fprintf(&b, "chown %s:%s %s/wp-config.php\n", ShellQuote(w.Owner), WebGroup, dir)
+	fmt.Fprintf(&b, "if ! %s core is-installed 2>/dev/null; then\n", wp)
+	fmt.Fprintf(&b, "  %s core install --url=%s --title=%s --admin_user=%s --admin_email=%s --admin_password=%s --skip-email\nfi\n",
+		wp, ShellQuote(w.URL), ShellQuote(w.Title), ShellQuote(w.AdminUser), ShellQuote(w.AdminEmail), ShellQuote(w.AdminPassword))
+	b.WriteString(WordPressPermissionsScript(w.Dir, w.Owner))
+	fmt.Fprintf(&b, "echo\necho %s\n", ShellQuote("✓ WordPress is installed at "+w.URL))
+	fmt.Fprintf(&b, "echo %s\n", ShellQuote(fmt.Sprintf("  Admin:    %s/wp-admin (user %s)", strings.TrimSuffix(w.URL, "/"), w.AdminUser)))
+	fmt.Fprintf(&b, "echo %s\n", ShellQuote(fmt.Sprintf("  Database: %s (user %s)", w.DBName, w.DBUser)))
+	return b.String(), nil
+}
+
+// IsWordPress reports whether dir holds a WordPress install
+func IsWordPress(dir string) bool {
+	_, err := Stat(filepath.Join(dir, "wp-includes", "version.php"))
+	return err == nil
+}
+
+// WPSearchReplaceArgs returns the wp-cli arguments that move a site from
+// one URL or domain to another. GUIDs are left alone as WordPress requires.
+func WPSearchReplaceArgs(from, to string, dryRun bool) []string {
+	args := []string{"search-replace", from, to, "--all-tables-with-prefix", "--skip-columns=guid", "--report-changed-only"}
+	if dryRun {
+		args = append(args, "--dry-run")
+	}
+	return args
+}
+
+// WPCLIScript returns a root bash script that installs wp-cli if needed and
+// runs each command against the WordPress install in dir as user
+func WPCLIScript(dir, user string, commands ...[]string) (string, error) {
+	if !usernamePattern.MatchString(user) || user == "root" {
+		return "", fmt.Errorf("choose the site's system user to run wp-cli as (got %q)", user)
+	}
+	var b strings.Builder
+	b.WriteString("#!/bin/bash\nset -e\n")
+	b.WriteString(WPCLIInstallScript())
+	for _, args := range commands {
+		quoted := make([]string, len(args))
+		for i, arg := range args {
+			quoted[i] = ShellQuote(arg)
+		}
+		line := fmt.Sprintf("sudo -u %s -H %s --path=%s %s", ShellQuote(user), WPCLIPath, ShellQuote(dir), strings.Join(quoted, " "))
+		fmt.Fprintf(&b, "echo %s\n%s\n", ShellQuote("$ wp "+strings.Join(quoted, " ")), line)
+	}
+	return b.String(), nil
+}
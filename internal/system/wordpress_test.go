@@ -0,0 +1,137 @@
+package system
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func testWordPressSite() WordPressSite {
+	return WordPressSite{
+		Dir:           "/var/www/blog",
+		Owner:         "deploy",
+		URL:           "https://blog.example.com",
+		Title:         "Jo's Blog",
+		AdminUser:     "admin",
+		AdminEmail:    "admin@example.com",
+		AdminPassword: "secret",
+		DBName:        "blog",
+		DBUser:        "blog",
+		DBPassword:    `pa'ss\word`,
+	}
+}
+
+func TestWordPressSiteValidate(t *testing.T) {
+	if err := testWordPressSite().Validate(); err != nil {
+		t.Fatalf("expected valid site, got %v", err)
+	}
+	for name, mutate := range map[string]func(*WordPressSite){
+		"root dir":  func(w *WordPressSite) { w.Dir = "/" },
+		"owner":     func(w *WordPressSite) { w.Owner = "Deploy User" },
+		"url":       func(w *WordPressSite) { w.URL = "blog.example.com" },
+		"locale":    func(w *WordPressSite) { w.Locale = "english" },
+		"db user":   func(w *WordPressSite) { w.DBUser = "blog;drop" },
+		"admin":     func(w *WordPressSite) { w.AdminUser = "ad min" },
+		"no passwd": func(w *WordPressSite) { w.AdminPassword = "" },
+	} {
+		w := testWordPressSite()
+		mutate(&w)
+		if err := w.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestWordPressConfig(t *testing.T) {
+	config, err := testWordPressSite().WordPressConfig()
+	if err != nil {
+		t.Fatalf("WordPressConfig: %v", err)
+	}
+	if !strings.Contains(config, `define( 'DB_PASSWORD', 'pa\'ss\\word' );`) {
+		t.Errorf("password not escaped for PHP:\n%s", config)
+	}
+	salts := regexp.MustCompile(`define\( '(\w+_(?:KEY|SALT))', '((?:[^'\\]|\\.)*)' \);`).FindAllStringSubmatch(config, -1)
+	if len(salts) != len(wpSaltKeys) {
+		t.Fatalf("expected %d keys and salts, got %d", len(wpSaltKeys), len(salts))
+	}
+	seen := map[string]bool{}
+	for _, s := range salts {
+		if len(s[2]) != 64 || seen[s[2]] {
+			t.Errorf("%s has a short or repeated value %q", s[1], s[2])
+		}
+		seen[s[2]] = true
+	}
+	if strings.Contains(config, "put your unique phrase here") {
+		t.Error("sample placeholders left in config")
+	}
+}
+
+func TestWordPressProvisionScript(t *testing.T) {
+	script, err := testWordPressSite().ProvisionScript()
+	if err != nil {
+		t.Fatalf("ProvisionScript: %v", err)
+	}
+	for _, want := range []string{
+		"sudo -u deploy -H /usr/local/bin/wp --path=/var/www/blog core download --locale=en_US",
+		"cat > /var/www/blog/wp-config.php <<'RAVACT_WP_CONFIG'",
+		`--title='Jo'"'"'s Blog'`,
+		"chown -R deploy:www-data /var/www/blog/wp-content/uploads",
+		"sha512sum /tmp/wp-cli.phar",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+
+	site := testWordPressSite()
+	if strings.Contains(script, "echo '  Password") || strings.Contains(script, "password "+site.DBPassword) {
+		t.Error("expected the passwords to stay out of the summary")
+	}
+
+	quoted := testWordPressSite()
+	quoted.URL = "https://example.com/a'b"
+	script, err = quoted.ProvisionScript()
+	if err != nil {
+		t.Fatalf("ProvisionScript: %v", err)
+	}
+	if !strings.Contains(script, `echo '✓ WordPress is installed at https://example.com/a'"'"'b'`) {
+		t.Error("expected a quote in the URL to be shell-quoted")
+	}
+
+	invalid := testWordPressSite()
+	invalid.Dir = "relative"
+	if _, err := invalid.ProvisionScript(); err == nil {
+		t.Error("expected an invalid site to be rejected")
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	a, err := GeneratePassword(24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GeneratePassword(24)
+	if len(a) != 24 || a == b || !regexp.MustCompile(`^[A-Za-z0-9]+$`).MatchString(a) {
+		t.Errorf("unexpected passwords %q and %q", a, b)
+	}
+}
+
+func TestWPCLIScript(t *testing.T) {
+	script, err := WPCLIScript("/var/www/blog", "deploy",
+		WPSearchReplaceArgs("https://old.example.com", "https://new.example.com", true),
+		[]string{"cache", "flush"})
+	if err != nil {
+		t.Fatalf("WPCLIScript: %v", err)
+	}
+	for _, want := range []string{
+		"sudo -u deploy -H /usr/local/bin/wp --path=/var/www/blog search-replace https://old.example.com https://new.example.com --all-tables-with-prefix --skip-columns=guid --report-changed-only --dry-run\n",
+		"sudo -u deploy -H /usr/local/bin/wp --path=/var/www/blog cache flush\n",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+	if _, err := WPCLIScript("/var/www/blog", "root", []string{"core", "update"}); err == nil {
+		t.Error("expected wp-cli as root to be refused")
+	}
+}
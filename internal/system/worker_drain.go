@@ -0,0 +1,92 @@
+package system
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultDrainTimeout is how long queue workers get to finish the job
+// they are running before they are killed
+const DefaultDrainTimeout = 120 * time.Second
+
+// drainSystemdCommand stops a systemd worker without blocking, waits for
+// it to exit after SIGTERM, and kills it once the timeout passes. The
+// unit's own TimeoutStopSec still applies, so systemd may kill it sooner.
+func drainSystemdCommand(unit string, timeout time.Duration) string {
+	u := ShellQuote(unit)
+	seconds := int(timeout.Seconds())
+	return fmt.Sprintf(`systemctl stop --no-block %s; `+
+		`for i in $(seq 1 %d); do `+
+		`state=$(systemctl show -p ActiveState --value %s); `+
+		`if [ "$state" = inactive ] || [ "$state" = failed ]; then break; fi; `+
+		`sleep 1; done; `+
+		`state=$(systemctl show -p ActiveState --value %s); `+
+		`if [ "$state" = inactive ] || [ "$state" = failed ]; then echo %s; `+
+		`else echo %s; systemctl kill --signal=SIGKILL %s; systemctl stop %s; fi`,
+		u, seconds, u, u,
+		ShellQuote(unit+" drained"),
+		ShellQuote(fmt.Sprintf("%s still running after %ds; killing it", unit, seconds)), u, u)
+}
+
+// drainSupervisorCommand stops a supervisor program, which sends its
+// stopsignal and waits for the job to finish, and kills it if the stop
+// takes longer than the timeout
+func drainSupervisorCommand(program string, timeout time.Duration) string {
+	p := ShellQuote(program)
+	seconds := int(timeout.Seconds())
+	return fmt.Sprintf(`if timeout %d supervisorctl stop %s; then echo %s; `+
+		`else echo %s; supervisorctl signal SIGKILL %s; supervisorctl stop %s; fi`,
+		seconds, p, ShellQuote(program+" drained"),
+		ShellQuote(fmt.Sprintf("%s still running after %ds; killing it", program, seconds)), p, p)
+}
+
+// DrainCommands returns the commands that drain the stack's queue
+// workers: each worker is asked to stop, finishes its current job, and
+// is killed only if it is still running after the timeout
+func (s SiteStack) DrainCommands(timeout time.Duration) []string {
+	var commands []string
+	for _, n := range s.Layer(StackLayerWorkers) {
+		switch n.Kind {
+		case "systemd":
+			commands = append(commands, drainSystemdCommand(n.Unit, timeout))
+		case "supervisor":
+			commands = append(commands, drainSupervisorCommand(n.Unit, timeout))
+		}
+	}
+	return commands
+}
+
+// HasWorkers reports whether the stack has queue workers ravact can stop
+func (s SiteStack) HasWorkers() bool {
+	for _, n := range s.Layer(StackLayerWorkers) {
+		if n.Kind == "systemd" || n.Kind == "supervisor" {
+			return true
+		}
+	}
+	return false
+}
+
+// DrainScript returns a bash script that drains the stack's queue
+// workers and leaves them stopped
+func (s SiteStack) DrainScript(timeout time.Duration) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "echo %s\n", ShellQuote(fmt.Sprintf("==> Draining queue workers for %s (timeout %ds)", s.Site.Name, int(timeout.Seconds()))))
+	for _, command := range s.DrainCommands(timeout) {
+		b.WriteString(command + "\n")
+	}
+	b.WriteString("echo; echo '==> Workers stopped. Start them again from the dependency graph or with Restart Stack.'\n")
+	return b.String()
+}
+
+// StartWorkersScript returns a bash script that starts the stack's
+// queue workers
+func (s SiteStack) StartWorkersScript() string {
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	for _, command := range s.workerCommands("start") {
+		fmt.Fprintf(&b, "echo %s\n%s\n", ShellQuote("$ "+command), command)
+	}
+	b.WriteString("echo; echo '==> Workers started'\n")
+	return b.String()
+}
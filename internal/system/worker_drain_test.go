@@ -0,0 +1,46 @@
+package system
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDrainScript(t *testing.T) {
+	calls, err := runStubbedScript(t, testRestartStack().DrainScript(DefaultDrainTimeout), "", "inactive")
+	if err != nil {
+		t.Fatalf("script failed: %v\n%s", err, calls)
+	}
+	want := "systemctl stop --no-block example-queue\nsupervisorctl stop example-horizon\n"
+	if calls != want {
+		t.Errorf("unexpected calls:\n%s", calls)
+	}
+}
+
+func TestDrainScriptKillsAfterTimeout(t *testing.T) {
+	calls, err := runStubbedScript(t, testRestartStack().DrainScript(time.Second), "supervisorctl", "active")
+	if err != nil {
+		t.Fatalf("script failed: %v\n%s", err, calls)
+	}
+	for _, want := range []string{
+		"systemctl kill --signal=SIGKILL example-queue\nsystemctl stop example-queue\n",
+		"supervisorctl signal SIGKILL example-horizon\n",
+	} {
+		if !strings.Contains(calls, want) {
+			t.Errorf("missing %q in:\n%s", want, calls)
+		}
+	}
+}
+
+func TestStartWorkersScript(t *testing.T) {
+	calls, err := runStubbedScript(t, testRestartStack().StartWorkersScript(), "", "inactive")
+	if err != nil {
+		t.Fatalf("script failed: %v\n%s", err, calls)
+	}
+	if calls != "systemctl start example-queue\nsupervisorctl start example-horizon\n" {
+		t.Errorf("unexpected calls:\n%s", calls)
+	}
+	if (SiteStack{}).HasWorkers() || !testRestartStack().HasWorkers() {
+		t.Error("HasWorkers reported the wrong result")
+	}
+}
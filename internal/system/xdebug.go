@@ -0,0 +1,196 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// XdebugSettingsFile is the conf.d file ravact writes Xdebug's settings to
+const XdebugSettingsFile = "99-ravact-xdebug.ini"
+
+// Xdebug modes offered by ravact
+const (
+	XdebugOff     = "off"
+	XdebugDebug   = "debug"
+	XdebugProfile = "profile"
+)
+
+// XdebugProfileDir is where profile mode writes cachegrind files
+const XdebugProfileDir = "/tmp/xdebug"
+
+var (
+	// xdebugEnabledPattern matches the conf.d link phpenmod creates
+	xdebugEnabledPattern = regexp.MustCompile(`^[0-9]+-xdebug\.ini$`)
+	xdebugHostPattern    = regexp.MustCompile(`^[A-Za-z0-9.:_-]+$`)
+)
+
+// XdebugSettings is how Xdebug runs for a PHP version
+type XdebugSettings struct {
+	Mode             string // XdebugOff, XdebugDebug, or XdebugProfile
+	ClientHost       string
+	ClientPort       int
+	StartWithRequest string // "trigger" or "yes"
+}
+
+// DefaultXdebugSettings step debug on request, with the IDE reached on this host
+func DefaultXdebugSettings() XdebugSettings {
+	return XdebugSettings{Mode: XdebugDebug, ClientHost: "127.0.0.1", ClientPort: 9003, StartWithRequest: "trigger"}
+}
+
+// Validate checks the settings before they are written
+func (s XdebugSettings) Validate() error {
+	switch s.Mode {
+	case XdebugOff, XdebugDebug, XdebugProfile:
+	default:
+		return fmt.Errorf("unknown Xdebug mode %q", s.Mode)
+	}
+	if !xdebugHostPattern.MatchString(s.ClientHost) {
+		return fmt.Errorf("client host must be a host name or IP address")
+	}
+	if s.ClientPort < 1 || s.ClientPort > 65535 {
+		return fmt.Errorf("client port must be between 1 and 65535")
+	}
+	if s.StartWithRequest != "trigger" && s.StartWithRequest != "yes" {
+		return fmt.Errorf("start with request must be trigger or yes")
+	}
+	return nil
+}
+
+// Render returns the settings file
+func (s XdebugSettings) Render() string {
+	var b strings.Builder
+	b.WriteString("; Managed by ravact: Xdebug\n")
+	fmt.Fprintf(&b, "xdebug.mode = %s\n", s.Mode)
+	fmt.Fprintf(&b, "xdebug.client_host = %s\n", s.ClientHost)
+	fmt.Fprintf(&b, "xdebug.client_port = %d\n", s.ClientPort)
+	fmt.Fprintf(&b, "xdebug.start_with_request = %s\n", s.StartWithRequest)
+	if s.Mode == XdebugProfile {
+		fmt.Fprintf(&b, "xdebug.output_dir = %s\n", XdebugProfileDir)
+	}
+	return b.String()
+}
+
+// parseXdebugSettings reads a settings file written by Render, keeping
+// defaults for anything missing
+func parseXdebugSettings(content string) XdebugSettings {
+	s := DefaultXdebugSettings()
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.HasPrefix(strings.TrimSpace(line), ";") {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch strings.TrimSpace(key) {
+		case "xdebug.mode":
+			s.Mode = value
+		case "xdebug.client_host":
+			s.ClientHost = value
+		case "xdebug.client_port":
+			if port, err := strconv.Atoi(value); err == nil {
+				s.ClientPort = port
+			}
+		case "xdebug.start_with_request":
+			s.StartWithRequest = value
+		}
+	}
+	return s
+}
+
+// XdebugStatus is Xdebug's state for a PHP version
+type XdebugStatus struct {
+	Version   string
+	Installed bool // The xdebug module is available
+	Enabled   bool // PHP-FPM loads the module
+	Settings  XdebugSettings
+}
+
+// Active reports whether PHP-FPM requests run with Xdebug, which slows
+// them down and can expose a debugger; it should be off in production
+func (s XdebugStatus) Active() bool {
+	return s.Enabled && s.Settings.Mode != XdebugOff
+}
+
+// Label describes the state for the UI
+func (s XdebugStatus) Label() string {
+	switch {
+	case !s.Installed:
+		return "not installed"
+	case s.Active():
+		return s.Settings.Mode
+	}
+	return "off"
+}
+
+// ReadXdebugStatus reads Xdebug's state for a PHP version
+func ReadXdebugStatus(version string) XdebugStatus {
+	st := XdebugStatus{Version: version, Settings: DefaultXdebugSettings()}
+	if _, err := Stat(filepath.Join(PHPConfigDir, version, "mods-available", "xdebug.ini")); err == nil {
+		st.Installed = true
+	}
+	confDir := filepath.Join(PHPIniDir(version, "fpm"), "conf.d")
+	if entries, err := ReadDir(confDir); err == nil {
+		for _, e := range entries {
+			if xdebugEnabledPattern.MatchString(e.Name()) {
+				st.Enabled = true
+			}
+		}
+	}
+	if data, err := ReadFile(filepath.Join(confDir, XdebugSettingsFile)); err == nil {
+		st.Settings = parseXdebugSettings(string(data))
+	} else if st.Enabled {
+		// Loaded without ravact's settings: Xdebug's own default is develop
+		st.Settings.Mode = "develop"
+	}
+	return st
+}
+
+// ActiveXdebug returns the PHP versions whose PHP-FPM runs with Xdebug on
+func ActiveXdebug() []XdebugStatus {
+	var active []XdebugStatus
+	for _, version := range PHPFPMVersions() {
+		if st := ReadXdebugStatus(version); st.Active() {
+			active = append(active, st)
+		}
+	}
+	return active
+}
+
+// XdebugScript installs Xdebug for a PHP version when it is missing,
+// writes the settings for PHP-FPM and the CLI, and restarts PHP-FPM. Mode
+// off also unloads the module so it costs nothing.
+func XdebugScript(version string, s XdebugSettings) (string, error) {
+	if !phpVersionPattern.MatchString(version) {
+		return "", fmt.Errorf("invalid PHP version %q", version)
+	}
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	fmt.Fprintf(&b, "if [ ! -f %s ]; then\n", ShellQuote(filepath.Join(PHPConfigDir, version, "mods-available", "xdebug.ini")))
+	fmt.Fprintf(&b, "    echo '==> Installing Xdebug for PHP %s'\n", version)
+	fmt.Fprintf(&b, "    apt-get update\n    DEBIAN_FRONTEND=noninteractive apt-get install -y php%s-xdebug\nfi\n", version)
+	for _, sapi := range []string{"fpm", "cli"} {
+		confDir := filepath.Join(PHPIniDir(version, sapi), "conf.d")
+		fmt.Fprintf(&b, "if [ -d %s ]; then\n", ShellQuote(confDir))
+		fmt.Fprintf(&b, "    cat > %s <<'EOF'\n%sEOF\nfi\n", ShellQuote(filepath.Join(confDir, XdebugSettingsFile)), s.Render())
+	}
+	if s.Mode == XdebugOff {
+		fmt.Fprintf(&b, "echo '==> Unloading Xdebug'\nphpdismod -v %s xdebug\n", version)
+	} else {
+		fmt.Fprintf(&b, "echo '==> Enabling Xdebug (%s)'\nphpenmod -v %s xdebug\n", s.Mode, version)
+		if s.Mode == XdebugProfile {
+			fmt.Fprintf(&b, "mkdir -p %s\nchmod 1777 %s\n", XdebugProfileDir, XdebugProfileDir)
+		}
+	}
+	fmt.Fprintf(&b, "php-fpm%s -t\n", version)
+	fmt.Fprintf(&b, "echo '==> Restarting php%s-fpm'\nsystemctl restart php%s-fpm\n", version, version)
+	if s.Mode != XdebugOff {
+		fmt.Fprintf(&b, "echo 'Xdebug is ON for PHP %s. Turn it off before this server takes production traffic.'\n", version)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,62 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadXdebugStatus(t *testing.T) {
+	old := PHPConfigDir
+	PHPConfigDir = t.TempDir()
+	t.Cleanup(func() { PHPConfigDir = old })
+
+	confDir := filepath.Join(PHPIniDir("8.3", "fpm"), "conf.d")
+	os.MkdirAll(confDir, 0755)
+	os.MkdirAll(filepath.Join(PHPConfigDir, "8.3", "mods-available"), 0755)
+
+	if st := ReadXdebugStatus("8.3"); st.Installed || st.Label() != "not installed" {
+		t.Errorf("expected Xdebug to be missing, got %+v", st)
+	}
+
+	os.WriteFile(filepath.Join(PHPConfigDir, "8.3", "mods-available", "xdebug.ini"), []byte("zend_extension=xdebug.so\n"), 0644)
+	settings := XdebugSettings{Mode: XdebugProfile, ClientHost: "10.0.0.5", ClientPort: 9000, StartWithRequest: "yes"}
+	os.WriteFile(filepath.Join(confDir, XdebugSettingsFile), []byte(settings.Render()), 0644)
+	if st := ReadXdebugStatus("8.3"); st.Active() || st.Label() != "off" || st.Settings != settings {
+		t.Errorf("expected an installed but unloaded Xdebug with saved settings, got %+v", st)
+	}
+
+	os.WriteFile(filepath.Join(confDir, "20-xdebug.ini"), []byte("zend_extension=xdebug.so\n"), 0644)
+	if st := ReadXdebugStatus("8.3"); !st.Active() || st.Label() != XdebugProfile {
+		t.Errorf("expected Xdebug to be active in profile mode, got %+v", st)
+	}
+}
+
+func TestXdebugScript(t *testing.T) {
+	script, err := XdebugScript("8.3", DefaultXdebugSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"apt-get install -y php8.3-xdebug", "xdebug.mode = debug\n", "xdebug.client_port = 9003\n", "phpenmod -v 8.3 xdebug", "systemctl restart php8.3-fpm"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script is missing %q:\n%s", want, script)
+		}
+	}
+
+	off := DefaultXdebugSettings()
+	off.Mode = XdebugOff
+	script, _ = XdebugScript("8.3", off)
+	if !strings.Contains(script, "phpdismod -v 8.3 xdebug") || strings.Contains(script, "phpenmod") {
+		t.Errorf("expected mode off to unload Xdebug:\n%s", script)
+	}
+
+	bad := DefaultXdebugSettings()
+	bad.ClientHost = "$(reboot)"
+	if _, err := XdebugScript("8.3", bad); err == nil {
+		t.Error("expected an invalid client host to be rejected")
+	}
+	if _, err := XdebugScript("8.3", XdebugSettings{Mode: "trace", ClientHost: "127.0.0.1", ClientPort: 9003, StartWithRequest: "trigger"}); err == nil {
+		t.Error("expected an unknown mode to be rejected")
+	}
+}
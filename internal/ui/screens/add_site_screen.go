@@ -3,11 +3,13 @@ package screens
 import (
 	"embed"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/huh"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/stubs"
 	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/theme"
 )
@@ -26,16 +28,38 @@ type AddSiteModel struct {
 	// Form fields
 	siteName         string
 	domain           string
+	domains          []string // Parsed and expanded from domain on submit
 	rootDir          string
 	selectedTemplate string
 	sslOption        string
 	email            string
+	upstream         string
+	websocket        bool   // Reverse proxy only
+	connectTimeout   string // Reverse proxy only, seconds
+	readTimeout      string // Reverse proxy only, seconds
+	assetCacheDays   string // SPA only
+	immutable        bool   // SPA only
+	htmlNoCache      bool   // SPA only
+	gzipStatic       bool   // SPA only
+	brotliStatic     bool   // SPA only
+	brotliAvailable  bool   // nginx has the brotli module
+
+	// Resume previous input
+	resumeState FormState
+	resuming    bool
+
+	// Staged config awaiting review
+	review    ConfigReview
+	reviewing bool
 
 	// State
 	err     error
 	success bool
 }
 
+// addSiteFormKey identifies the add site form in the form state store
+const addSiteFormKey = "add_site"
+
 // NewAddSiteModel creates a new add site model
 func NewAddSiteModel() AddSiteModel {
 	nginxManager := system.NewNginxManager()
@@ -58,25 +82,81 @@ func NewAddSiteModel() AddSiteModel {
 		selectedTemplate: "static",
 		sslOption:        "none",
 		email:            "",
+		connectTimeout:   "75",
+		readTimeout:      "300",
+		assetCacheDays:   "30",
+		htmlNoCache:      true,
+		brotliAvailable:  system.NginxHasBrotli(),
 		err:              nil,
 		success:          false,
 	}
 
+	if state, ok := LoadFormState(addSiteFormKey); ok {
+		m.resumeState = state
+		m.resuming = true
+	}
+
+	m.form = m.buildForm()
+
+	return m
+}
+
+// formBindings maps form field keys to the model fields they populate
+func (m *AddSiteModel) formBindings() map[string]interface{} {
+	return map[string]interface{}{
+		"siteName":       &m.siteName,
+		"domain":         &m.domain,
+		"rootDir":        &m.rootDir,
+		"template":       &m.selectedTemplate,
+		"ssl":            &m.sslOption,
+		"email":          &m.email,
+		"upstream":       &m.upstream,
+		"websocket":      &m.websocket,
+		"connectTimeout": &m.connectTimeout,
+		"readTimeout":    &m.readTimeout,
+		"assetCacheDays": &m.assetCacheDays,
+		"immutable":      &m.immutable,
+		"htmlNoCache":    &m.htmlNoCache,
+		"gzipStatic":     &m.gzipStatic,
+		"brotliStatic":   &m.brotliStatic,
+	}
+}
+
+// buildForm creates the huh form for the site configuration
+func (m *AddSiteModel) buildForm() *huh.Form {
+	naming, _ := system.LoadNamingPolicy()
+	siteDescription := "Unique identifier for the site configuration"
+	if hint := naming.Sites.Hint; hint != "" {
+		siteDescription += " (" + hint + ")"
+	}
+
 	// Build template options
 	templateOptions := []huh.Option[string]{}
-	for _, tpl := range templates {
-		templateOptions = append(templateOptions, huh.NewOption(tpl.Name, tpl.ID))
+	for _, tpl := range m.templates {
+		name := tpl.Name
+		if tpl.Overridden && !strings.HasPrefix(name, "Custom: ") {
+			name += " (user stub)"
+		}
+		templateOptions = append(templateOptions, huh.NewOption(name, tpl.ID))
 	}
 	if len(templateOptions) == 0 {
 		templateOptions = append(templateOptions, huh.NewOption("Static HTML", "static"))
 	}
 
-	// Create the huh form
-	m.form = huh.NewForm(
+	// brotli_static fails nginx -t without the module
+	brotliDescription := "Serve .br copies made by the build step"
+	if !m.brotliAvailable {
+		m.brotliStatic = false
+		brotliDescription = "Unavailable: nginx does not have the brotli module"
+	}
+
+	template := &m.selectedTemplate
+	return huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
+				Key("siteName").
 				Title("Site Name").
-				Description("Unique identifier for the site configuration").
+				Description(siteDescription).
 				Placeholder("mysite").
 				Validate(func(s string) error {
 					if s == "" {
@@ -85,23 +165,23 @@ func NewAddSiteModel() AddSiteModel {
 					if strings.Contains(s, " ") {
 						return fmt.Errorf("site name cannot contain spaces")
 					}
-					return nil
+					return naming.Check(system.NamingSite, s)
 				}).
 				Value(&m.siteName),
 
 			huh.NewInput().
-				Title("Domain").
-				Description("Domain name for the site (e.g., example.com)").
-				Placeholder("example.com").
+				Key("domain").
+				Title("Domains").
+				Description("Comma-separated; www is added to apex domains, *.example.com for wildcards").
+				Placeholder("example.com, api.example.com").
 				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("domain is required")
-					}
-					return nil
+					_, err := system.ParseDomains(s)
+					return err
 				}).
 				Value(&m.domain),
 
 			huh.NewInput().
+				Key("rootDir").
 				Title("Root Directory").
 				Description("Document root path for web files").
 				Placeholder("/var/www/html").
@@ -117,12 +197,14 @@ func NewAddSiteModel() AddSiteModel {
 				Value(&m.rootDir),
 
 			huh.NewSelect[string]().
+				Key("template").
 				Title("Template").
 				Description("Nginx configuration template").
 				Options(templateOptions...).
 				Value(&m.selectedTemplate),
 
 			huh.NewSelect[string]().
+				Key("ssl").
 				Title("SSL Certificate").
 				Description("SSL/HTTPS configuration").
 				Options(
@@ -132,20 +214,107 @@ func NewAddSiteModel() AddSiteModel {
 				Value(&m.sslOption),
 
 			huh.NewInput().
+				Key("email").
 				Title("Email (for Let's Encrypt)").
 				Description("Only required if using Let's Encrypt SSL").
 				Placeholder("admin@example.com").
 				Value(&m.email),
+
+			huh.NewInput().
+				Key("upstream").
+				Title("Upstream (for proxy templates)").
+				Description("A port, host:port, socket path, URL, or docker:NAME:PORT; blank uses the template default").
+				Placeholder("3000").
+				Validate(func(s string) error {
+					// Containers are looked up when the site is created
+					if strings.HasPrefix(strings.TrimSpace(s), "docker:") {
+						return nil
+					}
+					_, err := system.NormalizeUpstream(s)
+					return err
+				}).
+				Value(&m.upstream),
 		),
-	).WithTheme(t.HuhTheme).
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key("websocket").
+				Title("WebSocket Support").
+				Description("Pass Upgrade headers through for apps using WebSockets").
+				Value(&m.websocket),
+
+			huh.NewInput().
+				Key("connectTimeout").
+				Title("Connect Timeout (seconds)").
+				Description("How long to wait for the upstream to accept a connection; at most 75").
+				Validate(validateSeconds).
+				Value(&m.connectTimeout),
+
+			huh.NewInput().
+				Key("readTimeout").
+				Title("Read Timeout (seconds)").
+				Description("How long a response or an idle WebSocket may take").
+				Validate(validateSeconds).
+				Value(&m.readTimeout),
+		).WithHideFunc(func() bool {
+			return *template != "proxy"
+		}),
+		huh.NewGroup(
+			huh.NewInput().
+				Key("assetCacheDays").
+				Title("Asset Cache (days)").
+				Description("How long browsers keep JS, CSS, images, and fonts; 0 makes them check every time").
+				Validate(func(s string) error {
+					if n, err := strconv.Atoi(strings.TrimSpace(s)); err != nil || n < 0 || n > 365 {
+						return fmt.Errorf("enter 0 to 365 days")
+					}
+					return nil
+				}).
+				Value(&m.assetCacheDays),
+
+			huh.NewConfirm().
+				Key("immutable").
+				Title("Hashed Asset Names").
+				Description("Mark assets immutable; only when the build puts a content hash in file names (Vite, webpack)").
+				Value(&m.immutable),
+
+			huh.NewConfirm().
+				Key("htmlNoCache").
+				Title("Revalidate Pages").
+				Description("Send Cache-Control: no-cache for index.html so a new deploy shows up at once").
+				Value(&m.htmlNoCache),
+
+			huh.NewConfirm().
+				Key("gzipStatic").
+				Title("Serve Pre-compressed gzip").
+				Description("Serve .gz copies; set a precompress directory in the repo's Git build step to make them").
+				Value(&m.gzipStatic),
+
+			huh.NewConfirm().
+				Key("brotliStatic").
+				Title("Serve Pre-compressed Brotli").
+				Description(brotliDescription).
+				Value(&m.brotliStatic),
+		).WithHideFunc(func() bool {
+			return *template != "spa"
+		}),
+	).WithTheme(m.theme.HuhTheme).
 		WithShowHelp(true).
 		WithShowErrors(true)
+}
 
-	return m
+// validateSeconds checks a timeout in whole seconds
+func validateSeconds(s string) error {
+	if n, err := strconv.Atoi(strings.TrimSpace(s)); err != nil || n < 1 {
+		return fmt.Errorf("enter a number of seconds")
+	}
+	return nil
 }
 
 // Init initializes the add site screen
 func (m AddSiteModel) Init() tea.Cmd {
+	if m.resuming {
+		return nil
+	}
 	return m.form.Init()
 }
 
@@ -168,12 +337,50 @@ func (m AddSiteModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.reviewing {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.review, result = m.review.Update(msg, m.height)
+			switch result {
+			case ConfirmAccepted:
+				m.reviewing = false
+				return m.applySite()
+			case ConfirmCancelled:
+				// Back to the form with the entered values
+				m.reviewing = false
+				m.form = m.buildForm()
+				return m, m.form.Init()
+			}
+			return m, nil
+		}
+
+		// Offer to resume input saved from a previous visit
+		if m.resuming {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "y", "enter":
+				m.resumeState.Apply(m.formBindings())
+			case "n", "esc":
+				ClearFormState(addSiteFormKey)
+			default:
+				return m, nil
+			}
+			m.resuming = false
+			m.resumeState = nil
+			m.form = m.buildForm()
+			return m, m.form.Init()
+		}
+
 		// Global keys
 		switch msg.String() {
 		case "ctrl+c":
 			return m, tea.Quit
 		case "esc":
 			if m.form.State == huh.StateNormal {
+				SaveFormState(addSiteFormKey, CaptureFormState(m.form, formBindingKeys(m.formBindings())))
 				return m, func() tea.Msg {
 					return NavigateMsg{Screen: NginxConfigScreen}
 				}
@@ -195,8 +402,21 @@ func (m AddSiteModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-// createSite creates the nginx site configuration
+// template returns the selected template
+func (m AddSiteModel) template() (system.NginxTemplate, bool) {
+	for _, tpl := range m.templates {
+		if tpl.ID == m.selectedTemplate {
+			return tpl, true
+		}
+	}
+	return system.NginxTemplate{}, false
+}
+
+// createSite stages the nginx site configuration for review
 func (m AddSiteModel) createSite() (AddSiteModel, tea.Cmd) {
+	// Read form values explicitly (pointer bindings target the form's own copy)
+	CaptureFormState(m.form, formBindingKeys(m.formBindings())).Apply(m.formBindings())
+
 	// Validate email for Let's Encrypt
 	if m.sslOption == "letsencrypt" && m.email == "" {
 		m.err = fmt.Errorf("email is required for Let's Encrypt")
@@ -207,8 +427,51 @@ func (m AddSiteModel) createSite() (AddSiteModel, tea.Cmd) {
 	useSSL := m.sslOption != "none"
 	useCertbot := m.sslOption == "letsencrypt"
 
+	upstream, err := system.NormalizeUpstream(m.upstream)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if tpl, ok := m.template(); ok && upstream == "" {
+		upstream = tpl.Upstream
+	}
+	opts := system.DefaultSiteOptions(upstream)
+	switch m.selectedTemplate {
+	case "proxy":
+		opts.Proxy.WebSocket = m.websocket
+		opts.Proxy.ConnectTimeout, _ = strconv.Atoi(strings.TrimSpace(m.connectTimeout))
+		opts.Proxy.ReadTimeout, _ = strconv.Atoi(strings.TrimSpace(m.readTimeout))
+	case "spa":
+		opts.Static.AssetCacheDays, _ = strconv.Atoi(strings.TrimSpace(m.assetCacheDays))
+		opts.Static.Immutable = m.immutable
+		opts.Static.HTMLNoCache = m.htmlNoCache
+		opts.Static.GzipStatic = m.gzipStatic
+		opts.Static.BrotliStatic = m.brotliStatic && m.brotliAvailable
+	}
+
+	domains, err := system.ParseDomains(m.domain)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.domains = system.ExpandDomains(domains)
+
+	change, err := m.nginxManager.PlanCreateSiteWithOptions(m.siteName, m.domains, m.rootDir, m.selectedTemplate, opts, useSSL, useCertbot)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.review = NewConfigReview("create_site", m.nginxManager, change)
+	m.reviewing = true
+	return m, nil
+}
+
+// applySite writes the reviewed config, enables the site, and reloads nginx
+func (m AddSiteModel) applySite() (AddSiteModel, tea.Cmd) {
+	useCertbot := m.sslOption == "letsencrypt"
+
 	// Create the site
-	err := m.nginxManager.CreateSite(m.siteName, m.domain, m.rootDir, m.selectedTemplate, useSSL, useCertbot)
+	err := m.nginxManager.ApplyChange(m.review.Change)
 	if err != nil {
 		m.err = err
 		return m, nil
@@ -237,13 +500,14 @@ func (m AddSiteModel) createSite() (AddSiteModel, tea.Cmd) {
 
 	// If using certbot, obtain certificate
 	if useCertbot {
-		err = m.nginxManager.ObtainSSLCertificate(m.domain)
+		err = m.nginxManager.ObtainSSLCertificate(m.domains)
 		if err != nil {
 			m.err = fmt.Errorf("site created but certbot failed: %w", err)
 			return m, nil
 		}
 	}
 
+	ClearFormState(addSiteFormKey)
 	m.success = true
 	m.err = nil
 	return m, nil
@@ -255,6 +519,10 @@ func (m AddSiteModel) View() string {
 		return "Loading..."
 	}
 
+	if m.reviewing {
+		return m.review.View(m.theme, m.width, m.height)
+	}
+
 	// If success, show message
 	if m.success {
 		msg := m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " Site created successfully!")
@@ -276,6 +544,10 @@ func (m AddSiteModel) View() string {
 	// Header
 	header := m.theme.Title.Render("Add Nginx Site")
 
+	if m.resuming {
+		return m.viewResumePrompt(header)
+	}
+
 	// Render the huh form
 	formView := m.form.View()
 
@@ -284,13 +556,13 @@ func (m AddSiteModel) View() string {
 
 	// Template description
 	templateDesc := ""
-	for _, tpl := range m.templates {
-		if tpl.ID == m.selectedTemplate {
-			templateDesc = m.theme.DescriptionStyle.Render("Template: " + tpl.Description)
-			if len(tpl.RecommendedFor) > 0 {
-				templateDesc += "\n" + m.theme.DescriptionStyle.Render("Recommended for: " + strings.Join(tpl.RecommendedFor, ", "))
-			}
-			break
+	if tpl, ok := m.template(); ok {
+		templateDesc = m.theme.DescriptionStyle.Render("Template: " + tpl.Description)
+		if len(tpl.RecommendedFor) > 0 {
+			templateDesc += "\n" + m.theme.DescriptionStyle.Render("Recommended for: " + strings.Join(tpl.RecommendedFor, ", "))
+		}
+		if tpl.Overridden {
+			templateDesc += "\n" + m.theme.InfoStyle.Render(m.theme.Symbols.Info+" Using "+stubs.OverridePath(tpl.StubName()))
 		}
 	}
 
@@ -317,3 +589,26 @@ func (m AddSiteModel) View() string {
 		bordered,
 	)
 }
+
+// viewResumePrompt asks whether to restore input saved from a previous visit
+func (m AddSiteModel) viewResumePrompt(header string) string {
+	var summary []string
+	for _, key := range []string{"siteName", "domain", "rootDir", "template", "ssl", "email", "upstream"} {
+		if v := m.resumeState[key]; v != "" {
+			summary = append(summary, m.theme.Label.Render(key+": ")+m.theme.InfoStyle.Render(v))
+		}
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		"",
+		m.theme.Subtitle.Render("Resume previous input?"),
+		"",
+		lipgloss.JoinVertical(lipgloss.Left, summary...),
+		"",
+		m.theme.Help.Render("y/Enter: Resume "+m.theme.Symbols.Bullet+" n/Esc: Start fresh"),
+	)
+
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
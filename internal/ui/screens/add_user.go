@@ -3,6 +3,7 @@ package screens
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/huh"
@@ -25,8 +26,13 @@ type AddUserModel struct {
 	// Form fields
 	username       string
 	shell          string
+	umask          string
+	groups         string // Comma- or space-separated extra groups
 	grantSudo      bool
 	passwordlessSu bool // Allow passwordless su and sudo NOPASSWD
+	saveDefaults   bool // Remember shell, umask, and groups for new users
+
+	shells []string // Installed login shells
 
 	// UI state
 	err       error
@@ -39,67 +45,11 @@ func NewAddUserModel() AddUserModel {
 	t := theme.DefaultTheme()
 
 	m := AddUserModel{
-		theme:          t,
-		userManager:    system.NewUserManager(),
-		username:       "",
-		shell:          "/bin/bash",
-		grantSudo:      true,
-		passwordlessSu: true,
+		theme:       t,
+		userManager: system.NewUserManager(),
+		shells:      system.InstalledShells(),
 	}
-
-	// Create the huh form
-	m.form = huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().
-				Key("username").
-				Title("Username").
-				Description("Must be 3+ chars, start with letter, lowercase/numbers/_/-").
-				Placeholder("Enter username...").
-				Validate(func(s string) error {
-					if s == "" {
-						return fmt.Errorf("username cannot be empty")
-					}
-					if len(s) < 3 {
-						return fmt.Errorf("username must be at least 3 characters")
-					}
-					if matched, _ := regexp.MatchString(`^[a-z][a-z0-9_-]*$`, s); !matched {
-						return fmt.Errorf("must start with letter, use lowercase/numbers/_/-")
-					}
-					return nil
-				}).
-				Value(&m.username),
-
-			huh.NewSelect[string]().
-				Key("shell").
-				Title("Shell").
-				Description("Default shell for the user").
-				Options(
-					huh.NewOption("/bin/bash", "/bin/bash"),
-					huh.NewOption("/bin/sh", "/bin/sh"),
-					huh.NewOption("/bin/zsh", "/bin/zsh"),
-					huh.NewOption("/bin/fish", "/bin/fish"),
-				).
-				Value(&m.shell),
-
-			huh.NewConfirm().
-				Key("grantSudo").
-				Title("Grant Sudo Privileges").
-				Description("Add user to sudo group").
-				Affirmative("Yes").
-				Negative("No").
-				Value(&m.grantSudo),
-
-			huh.NewConfirm().
-				Key("passwordlessSu").
-				Title("Passwordless Access (NOPASSWD)").
-				Description("Allow su and sudo without password (SSH key-only auth)").
-				Affirmative("Yes").
-				Negative("No").
-				Value(&m.passwordlessSu),
-		),
-	).WithTheme(t.HuhTheme).
-		WithShowHelp(true).
-		WithShowErrors(true)
+	m.form = m.rebuildForm()
 
 	return m
 }
@@ -181,8 +131,12 @@ func (m AddUserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if v := m.form.GetString("shell"); v != "" {
 			m.shell = v
 		}
-		// Note: GetBool doesn't exist, grantSudo and passwordlessSu should be bound via Value()
-		
+		m.umask = strings.TrimSpace(m.form.GetString("umask"))
+		m.groups = m.form.GetString("groups")
+		m.grantSudo = m.form.GetBool("grantSudo")
+		m.passwordlessSu = m.form.GetBool("passwordlessSu")
+		m.saveDefaults = m.form.GetBool("saveDefaults")
+
 		if err := m.createUser(); err != nil {
 			m.err = err
 			// Reset form state to allow retry
@@ -196,20 +150,39 @@ func (m AddUserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-// rebuildForm creates a fresh form instance
+// rebuildForm creates a fresh form instance, filled from the saved user
+// defaults
 func (m *AddUserModel) rebuildForm() *huh.Form {
+	defaults, _ := system.LoadUserDefaults()
+	naming, _ := system.LoadNamingPolicy()
+	usernameDescription := "Must be 3+ chars, start with letter, lowercase/numbers/_/-"
+	if hint := naming.Users.Hint; hint != "" {
+		usernameDescription += "; " + hint
+	}
+
 	// Reset form field values
 	m.username = ""
-	m.shell = "/bin/bash"
+	m.shell = defaults.Shell
+	m.umask = defaults.Umask
+	m.groups = strings.Join(defaults.Groups, ", ")
 	m.grantSudo = true
 	m.passwordlessSu = true
+	m.saveDefaults = false
+
+	shellOptions := make([]huh.Option[string], 0, len(m.shells)+1)
+	for _, shell := range m.shells {
+		shellOptions = append(shellOptions, huh.NewOption(shell, shell))
+	}
+	if !slices.Contains(m.shells, m.shell) {
+		shellOptions = append(shellOptions, huh.NewOption(m.shell+" (not installed)", m.shell))
+	}
 
 	return huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
 				Key("username").
 				Title("Username").
-				Description("Must be 3+ chars, start with letter, lowercase/numbers/_/-").
+				Description(usernameDescription).
 				Placeholder("Enter username...").
 				Validate(func(s string) error {
 					if s == "" {
@@ -221,22 +194,34 @@ func (m *AddUserModel) rebuildForm() *huh.Form {
 					if matched, _ := regexp.MatchString(`^[a-z][a-z0-9_-]*$`, s); !matched {
 						return fmt.Errorf("must start with letter, use lowercase/numbers/_/-")
 					}
-					return nil
+					return naming.Check(system.NamingUser, s)
 				}).
 				Value(&m.username),
 
 			huh.NewSelect[string]().
 				Key("shell").
 				Title("Shell").
-				Description("Default shell for the user").
-				Options(
-					huh.NewOption("/bin/bash", "/bin/bash"),
-					huh.NewOption("/bin/sh", "/bin/sh"),
-					huh.NewOption("/bin/zsh", "/bin/zsh"),
-					huh.NewOption("/bin/fish", "/bin/fish"),
-				).
+				Description("Login shells installed on this server").
+				Options(shellOptions...).
 				Value(&m.shell),
 
+			huh.NewInput().
+				Key("umask").
+				Title("Umask").
+				Description("Set at login, e.g. 027 keeps new files private to the group (empty for the system default)").
+				Placeholder("022").
+				Validate(system.ValidateUmask).
+				Value(&m.umask),
+
+			huh.NewInput().
+				Key("groups").
+				Title("Groups").
+				Description("Extra groups, comma-separated (e.g. www-data, developers)").
+				Validate(func(s string) error {
+					return system.UserDefaults{Groups: system.ParseGroupList(s)}.Validate()
+				}).
+				Value(&m.groups),
+
 			huh.NewConfirm().
 				Key("grantSudo").
 				Title("Grant Sudo Privileges").
@@ -252,6 +237,14 @@ func (m *AddUserModel) rebuildForm() *huh.Form {
 				Affirmative("Yes").
 				Negative("No").
 				Value(&m.passwordlessSu),
+
+			huh.NewConfirm().
+				Key("saveDefaults").
+				Title("Save as Defaults").
+				Description("Use this shell, umask, and groups for future new users").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&m.saveDefaults),
 		),
 	).WithTheme(m.theme.HuhTheme).
 		WithShowHelp(true).
@@ -287,6 +280,16 @@ func (m *AddUserModel) createUser() error {
 		}
 	}
 
+	defaults := system.UserDefaults{Shell: m.shell, Umask: m.umask, Groups: system.ParseGroupList(m.groups)}
+	if err := m.userManager.ApplyUserDefaults(m.username, defaults); err != nil {
+		return fmt.Errorf("user created but failed to apply umask and groups: %v", err)
+	}
+	if m.saveDefaults {
+		if err := defaults.Save(); err != nil {
+			return fmt.Errorf("user created but failed to save defaults: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,377 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// artisanOptionsPerGroup keeps generated forms within one screen
+const artisanOptionsPerGroup = 6
+
+// artisanListMsg carries the result of php artisan list
+type artisanListMsg struct {
+	php      string
+	commands []system.ArtisanCommand
+	err      error
+}
+
+// ArtisanModel is a searchable palette of a Laravel project's artisan
+// commands that fills in arguments with a generated form and runs the
+// command as the site's user
+type ArtisanModel struct {
+	theme    *theme.Theme
+	width    int
+	height   int
+	dir      string
+	user     string
+	phps     []string // php and the installed phpX.Y binaries
+	php      int      // Index into phps
+	commands []system.ArtisanCommand
+	query    string
+	cursor   int
+	offset   int
+	loading  bool
+
+	// Form for the selected command
+	form    *huh.Form
+	command system.ArtisanCommand
+
+	err error
+}
+
+// NewArtisanModel creates the artisan palette for the project in dir
+func NewArtisanModel(dir, user string) ArtisanModel {
+	phps := []string{"php"}
+	for _, v := range detectAvailablePHPVersions() {
+		phps = append(phps, "php"+v)
+	}
+	return ArtisanModel{theme: theme.DefaultTheme(), dir: dir, user: user, phps: phps, loading: true}
+}
+
+// load lists the commands with the selected PHP binary in the background
+func (m ArtisanModel) load() tea.Cmd {
+	dir, user, php := m.dir, m.user, m.phps[m.php]
+	return func() tea.Msg {
+		commands, err := system.ListArtisanCommands(dir, user, php)
+		return artisanListMsg{php: php, commands: commands, err: err}
+	}
+}
+
+// Init loads the command list
+func (m ArtisanModel) Init() tea.Cmd {
+	return m.load()
+}
+
+// filtered returns the commands whose name or description match the query
+func (m ArtisanModel) filtered() []system.ArtisanCommand {
+	if m.query == "" {
+		return m.commands
+	}
+	query := strings.ToLower(m.query)
+	var matches []system.ArtisanCommand
+	for _, c := range m.commands {
+		if strings.Contains(c.Name, query) || strings.Contains(strings.ToLower(c.Description), query) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+// visibleRows is how many commands fit on screen
+func (m ArtisanModel) visibleRows() int {
+	if rows := m.height - 16; rows > 5 {
+		return rows
+	}
+	return 5
+}
+
+// Update handles messages for the artisan palette
+func (m ArtisanModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case artisanListMsg:
+		// Ignore a list from a PHP binary that is no longer selected
+		if msg.php != m.phps[m.php] {
+			return m, nil
+		}
+		m.loading = false
+		m.commands, m.err = msg.commands, msg.err
+		m.cursor, m.offset = 0, 0
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.form != nil {
+			if msg.String() == "esc" {
+				m.form = nil
+				return m, nil
+			}
+			break
+		}
+		return m.updateList(msg)
+	}
+
+	if m.form == nil {
+		return m, nil
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	switch m.form.State {
+	case huh.StateCompleted:
+		return m.run(m.formValues())
+	case huh.StateAborted:
+		m.form = nil
+	}
+	return m, cmd
+}
+
+// updateList handles typing a filter and choosing a command
+func (m ArtisanModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	matches := m.filtered()
+	switch msg.Type {
+	case tea.KeyEsc:
+		if m.query != "" {
+			m.query = ""
+			m.cursor, m.offset = 0, 0
+			return m, nil
+		}
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: SiteCommandsScreen}
+		}
+	case tea.KeyUp:
+		if m.cursor > 0 {
+			m.cursor--
+			if m.cursor < m.offset {
+				m.offset = m.cursor
+			}
+		}
+	case tea.KeyDown:
+		if m.cursor < len(matches)-1 {
+			m.cursor++
+			if m.cursor >= m.offset+m.visibleRows() {
+				m.offset = m.cursor - m.visibleRows() + 1
+			}
+		}
+	case tea.KeyTab:
+		// Switch PHP binary and list again, since commands can differ
+		if len(m.phps) > 1 {
+			m.php = (m.php + 1) % len(m.phps)
+			m.loading = true
+			m.err = nil
+			return m, m.load()
+		}
+	case tea.KeyEnter:
+		if m.loading || m.cursor >= len(matches) {
+			return m, nil
+		}
+		m.command = matches[m.cursor]
+		m.form = m.buildForm(m.command)
+		if m.form == nil {
+			return m.run(map[string]string{})
+		}
+		return m, m.form.Init()
+	case tea.KeyBackspace:
+		if len(m.query) > 0 {
+			runes := []rune(m.query)
+			m.query = string(runes[:len(runes)-1])
+			m.cursor, m.offset = 0, 0
+		}
+	case tea.KeySpace:
+		m.query += " "
+		m.cursor, m.offset = 0, 0
+	case tea.KeyRunes:
+		m.query += string(msg.Runes)
+		m.cursor, m.offset = 0, 0
+	}
+	return m, nil
+}
+
+// buildForm generates a form for a command's arguments and options, or
+// returns nil when it takes none
+func (m ArtisanModel) buildForm(cmd system.ArtisanCommand) *huh.Form {
+	var groups []*huh.Group
+
+	var args []huh.Field
+	for _, a := range cmd.Arguments {
+		a := a
+		value := a.Default
+		title := a.Name
+		if a.Required {
+			title += " (required)"
+		}
+		desc := a.Description
+		if a.IsArray {
+			desc = strings.TrimSpace(desc + " (space-separated)")
+		}
+		args = append(args, huh.NewInput().Key(a.Name).Title(title).Description(desc).
+			Validate(func(s string) error {
+				if a.Required && strings.TrimSpace(s) == "" {
+					return fmt.Errorf("%s is required", a.Name)
+				}
+				return nil
+			}).
+			Value(&value))
+	}
+	if len(args) > 0 {
+		groups = append(groups, huh.NewGroup(args...).Title("Arguments"))
+	}
+
+	var opts []huh.Field
+	for _, o := range cmd.Options {
+		title := "--" + o.Name
+		if o.Shortcut != "" {
+			title += " (" + o.Shortcut + ")"
+		}
+		if !o.AcceptValue {
+			flag := false
+			opts = append(opts, huh.NewConfirm().Key("--"+o.Name).Title(title).Description(o.Description).Value(&flag))
+			continue
+		}
+		value := o.Default
+		desc := o.Description
+		if o.IsMultiple {
+			desc = strings.TrimSpace(desc + " (comma-separated)")
+		}
+		opts = append(opts, huh.NewInput().Key("--"+o.Name).Title(title).Description(desc).Value(&value))
+	}
+	for start := 0; start < len(opts); start += artisanOptionsPerGroup {
+		end := start + artisanOptionsPerGroup
+		if end > len(opts) {
+			end = len(opts)
+		}
+		groups = append(groups, huh.NewGroup(opts[start:end]...).Title("Options"))
+	}
+
+	if len(groups) == 0 {
+		return nil
+	}
+	return huh.NewForm(groups...).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// formValues reads the completed form keyed as system.ArtisanArgs expects
+func (m ArtisanModel) formValues() map[string]string {
+	values := map[string]string{}
+	for _, a := range m.command.Arguments {
+		values[a.Name] = m.form.GetString(a.Name)
+	}
+	for _, o := range m.command.Options {
+		key := "--" + o.Name
+		if !o.AcceptValue {
+			if m.form.GetBool(key) {
+				values[key] = "true"
+			}
+			continue
+		}
+		values[key] = m.form.GetString(key)
+	}
+	return values
+}
+
+// run hands the command to the execution screen
+func (m ArtisanModel) run(values map[string]string) (ArtisanModel, tea.Cmd) {
+	m.form = nil
+	args, err := system.ArtisanArgs(m.command, values)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	php := m.phps[m.php]
+	script, err := system.ArtisanScript(m.dir, m.user, php, args)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{
+			Command:     script,
+			Description: fmt.Sprintf("%s artisan %s (as %s)", php, strings.Join(args, " "), m.user),
+		}
+	}
+}
+
+// View renders the artisan palette
+func (m ArtisanModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections := []string{m.theme.Title.Render("Artisan Commands")}
+	status := m.theme.Label.Render("PHP: ") + m.theme.InfoStyle.Render(m.phps[m.php])
+	if m.user != "" {
+		status += bullet + m.theme.Label.Render("Run as: ") + m.theme.InfoStyle.Render(m.user)
+	}
+	sections = append(sections, m.theme.DescriptionStyle.Render("Project: "+m.dir), status, "")
+
+	if m.form != nil {
+		sections = append(sections,
+			m.theme.Subtitle.Render("php artisan "+m.command.Name),
+			m.theme.DescriptionStyle.Render(m.command.Description),
+			"",
+			m.form.View(),
+			"",
+			m.theme.Help.Render("Tab/Shift+Tab: Navigate"+bullet+"Enter: Next/Run"+bullet+"Esc: Back to commands"))
+		return m.render(sections)
+	}
+
+	help := "Type to search" + bullet + "↑/↓: Navigate" + bullet + "Enter: Run" + bullet + "Esc: Back"
+	if len(m.phps) > 1 {
+		help = "Type to search" + bullet + "↑/↓: Navigate" + bullet + "Tab: PHP version" + bullet + "Enter: Run" + bullet + "Esc: Back"
+	}
+	if m.err != nil {
+		sections = append(sections, m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()), "")
+	}
+	sections = append(sections, m.theme.Label.Render("Search: ")+m.theme.SelectedItem.Render(m.query+"_"), "")
+
+	if m.loading {
+		sections = append(sections, m.theme.DescriptionStyle.Render("Loading artisan commands..."))
+	} else {
+		matches := m.filtered()
+		if len(matches) == 0 && m.err == nil {
+			sections = append(sections, m.theme.DescriptionStyle.Render("No commands match"))
+		}
+		end := m.offset + m.visibleRows()
+		if end > len(matches) {
+			end = len(matches)
+		}
+		for i := m.offset; i < end; i++ {
+			c := matches[i]
+			line := fmt.Sprintf("%-28s ", truncateRunes(c.Name, 28))
+			if i == m.cursor {
+				sections = append(sections, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+
+					m.theme.SelectedItem.Render(line)+m.theme.DescriptionStyle.Render(truncateRunes(c.Description, 60)))
+			} else {
+				sections = append(sections, "  "+m.theme.MenuItem.Render(line)+m.theme.DescriptionStyle.Render(truncateRunes(c.Description, 60)))
+			}
+		}
+		if len(matches) > m.visibleRows() {
+			sections = append(sections, m.theme.DescriptionStyle.Render(fmt.Sprintf("  %d-%d of %d", m.offset+1, end, len(matches))))
+		}
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+	return m.render(sections)
+}
+
+// render boxes and centres the sections
+func (m ArtisanModel) render(sections []string) string {
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
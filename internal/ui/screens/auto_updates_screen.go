@@ -0,0 +1,368 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// AutoUpdatesModel configures unattended-upgrades or dnf-automatic
+type AutoUpdatesModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	mode    string // "view", "form", "preview", "confirm"
+	backend system.AutoUpdateBackend
+	enabled bool
+	current system.AutoUpdateSettings
+	pending system.AutoUpdateSettings
+	files   []system.ConfigFile
+	form    *huh.Form
+	confirm Confirmation
+
+	fileIndex int
+	scroll    int
+
+	err error
+}
+
+// NewAutoUpdatesModel creates a new automatic updates model
+func NewAutoUpdatesModel() AutoUpdatesModel {
+	m := AutoUpdatesModel{
+		theme: theme.DefaultTheme(),
+		mode:  "view",
+	}
+	backend, err := system.DetectAutoUpdateBackend()
+	if err != nil {
+		m.err = err
+		return m
+	}
+	m.backend = backend
+	m.enabled = system.AutoUpdateEnabled(backend)
+	m.current = system.CurrentAutoUpdateSettings(backend)
+	return m
+}
+
+// Init initializes the automatic updates screen
+func (m AutoUpdatesModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the automatic updates screen
+func (m AutoUpdatesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	if m.mode == "form" {
+		return m.updateForm(msg)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if keyMsg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch m.mode {
+	case "confirm":
+		return m.updateConfirm(keyMsg)
+	case "preview":
+		return m.updatePreview(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: MainMenuScreen}
+		}
+	case "e", "enter":
+		if m.backend == "" {
+			return m, nil
+		}
+		return m.openForm()
+	}
+	return m, nil
+}
+
+// openForm shows the settings form filled with the current values
+func (m AutoUpdatesModel) openForm() (tea.Model, tea.Cmd) {
+	s := m.current
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("categories").
+				Title("Updates to install").
+				Options(
+					huh.NewOption("Security updates only (recommended)", "security"),
+					huh.NewOption("All updates", "all"),
+				).
+				Value(&s.Categories),
+			huh.NewSelect[string]().
+				Key("reboot").
+				Title("Reboot policy").
+				Options(
+					huh.NewOption("Never reboot automatically", "never"),
+					huh.NewOption("Reboot when an update requires it", "when-needed"),
+				).
+				Value(&s.Reboot),
+			huh.NewInput().
+				Key("time").
+				Title("Time (HH:MM)").
+				Description(m.timeDescription()).
+				Value(&s.Time).
+				Validate(func(v string) error {
+					t := system.DefaultAutoUpdateSettings()
+					t.Time = strings.TrimSpace(v)
+					return t.Validate()
+				}),
+			huh.NewInput().
+				Key("email").
+				Title("Email report to").
+				Description("Leave empty to disable email (requires a working MTA)").
+				Value(&s.Email).
+				Validate(func(v string) error {
+					t := system.DefaultAutoUpdateSettings()
+					t.Email = strings.TrimSpace(v)
+					return t.Validate()
+				}),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+	m.mode = "form"
+	m.err = nil
+	return m, m.form.Init()
+}
+
+// timeDescription explains what the time setting controls for the backend
+func (m AutoUpdatesModel) timeDescription() string {
+	if m.backend == system.AutoUpdateDnf {
+		return "When dnf-automatic runs and, if enabled, reboots"
+	}
+	return "When to reboot if automatic reboots are enabled"
+}
+
+// updateForm passes messages to the settings form
+func (m AutoUpdatesModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = "view"
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	switch m.form.State {
+	case huh.StateCompleted:
+		m.pending = system.AutoUpdateSettings{
+			Categories: m.form.GetString("categories"),
+			Reboot:     m.form.GetString("reboot"),
+			Time:       strings.TrimSpace(m.form.GetString("time")),
+			Email:      strings.TrimSpace(m.form.GetString("email")),
+		}
+		if err := m.pending.Validate(); err != nil {
+			m.err = err
+			m.mode = "view"
+			return m, nil
+		}
+		m.files = m.pending.Files(m.backend)
+		m.fileIndex = 0
+		m.scroll = 0
+		m.mode = "preview"
+		return m, nil
+	case huh.StateAborted:
+		m.mode = "view"
+		return m, nil
+	}
+	return m, cmd
+}
+
+// updatePreview handles keys while previewing the generated files
+func (m AutoUpdatesModel) updatePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = "view"
+	case "tab", "right", "l":
+		m.fileIndex = (m.fileIndex + 1) % len(m.files)
+		m.scroll = 0
+	case "shift+tab", "left", "h":
+		m.fileIndex = (m.fileIndex + len(m.files) - 1) % len(m.files)
+		m.scroll = 0
+	case "down", "j":
+		if m.scroll < len(m.previewLines())-1 {
+			m.scroll++
+		}
+	case "up", "k":
+		if m.scroll > 0 {
+			m.scroll--
+		}
+	case "enter", "w":
+		paths := make([]string, len(m.files))
+		for i, file := range m.files {
+			paths[i] = file.Path
+		}
+		m.confirm = NewConfirmation("write", "Enable Automatic Updates",
+			fmt.Sprintf("Install %s and write:\n\n%s\n\nExisting files are backed up to .bak first.", m.backend, strings.Join(paths, "\n")),
+			ConfirmNormal)
+		m.mode = "confirm"
+	}
+	return m, nil
+}
+
+// updateConfirm handles the write confirmation
+func (m AutoUpdatesModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var result ConfirmResult
+	m.confirm, result = m.confirm.Update(msg)
+	switch result {
+	case ConfirmAccepted:
+		m.mode = "view"
+		script := system.AutoUpdateScript(m.backend, m.files)
+		return m, func() tea.Msg {
+			return ExecutionStartMsg{Command: script, Description: "Configuring automatic updates (" + string(m.backend) + ")"}
+		}
+	case ConfirmCancelled:
+		m.mode = "preview"
+	}
+	return m, nil
+}
+
+// previewLines returns the lines of the file being previewed
+func (m AutoUpdatesModel) previewLines() []string {
+	return strings.Split(strings.TrimRight(m.files[m.fileIndex].Content, "\n"), "\n")
+}
+
+// View renders the automatic updates screen
+func (m AutoUpdatesModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{m.theme.Title.Render("Automatic Updates")}
+	if m.backend != "" {
+		sections = append(sections, m.theme.DescriptionStyle.Render(string(m.backend)))
+	}
+	sections = append(sections, "")
+
+	switch m.mode {
+	case "form":
+		sections = append(sections, m.form.View())
+	case "preview":
+		sections = append(sections, m.renderPreview()...)
+	default:
+		if m.backend != "" {
+			sections = append(sections, m.renderSettings()...)
+		}
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := "e: Configure" + bullet + "Esc: Back"
+	switch m.mode {
+	case "form":
+		help = "Enter: Next" + bullet + "Esc: Cancel"
+	case "preview":
+		help = "Tab: Next file" + bullet + "↑/↓: Scroll" + bullet + "Enter: Write" + bullet + "Esc: Cancel"
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// renderSettings shows the status and current settings
+func (m AutoUpdatesModel) renderSettings() []string {
+	status := m.theme.WarningStyle.Render("disabled")
+	if m.enabled {
+		status = m.theme.SuccessStyle.Render("enabled")
+	}
+	categories := "security updates only"
+	if m.current.Categories == "all" {
+		categories = "all updates"
+	}
+	reboot := "never"
+	if m.current.Reboot == "when-needed" {
+		reboot = "when required, at " + m.current.Time
+	}
+	email := m.current.Email
+	if email == "" {
+		email = "(none)"
+	}
+
+	rows := [][2]string{
+		{"Categories", categories},
+		{"Reboot", reboot},
+		{"Email", email},
+	}
+	if m.backend == system.AutoUpdateDnf {
+		rows = append(rows, [2]string{"Runs at", m.current.Time})
+	}
+
+	lines := []string{m.theme.Label.Render(fmt.Sprintf("%-14s", "Status")) + status}
+	for _, row := range rows {
+		lines = append(lines, m.theme.Label.Render(fmt.Sprintf("%-14s", row[0]))+m.theme.MenuItem.Render(row[1]))
+	}
+	return lines
+}
+
+// renderPreview shows the generated file being previewed
+func (m AutoUpdatesModel) renderPreview() []string {
+	var tabs []string
+	for i, file := range m.files {
+		name := file.Path[strings.LastIndex(file.Path, "/")+1:]
+		if i == m.fileIndex {
+			tabs = append(tabs, m.theme.SelectedItem.Render(name))
+		} else {
+			tabs = append(tabs, m.theme.MenuItem.Render(name))
+		}
+	}
+
+	height := m.height - 16
+	if height < 5 {
+		height = 5
+	}
+	lines := m.previewLines()
+	end := m.scroll + height
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	sections := []string{
+		strings.Join(tabs, "  "),
+		m.theme.DescriptionStyle.Render(m.files[m.fileIndex].Path),
+		"",
+	}
+	for _, line := range lines[m.scroll:end] {
+		sections = append(sections, m.theme.Value.Render(line))
+	}
+	if len(lines) > height {
+		sections = append(sections, m.theme.DescriptionStyle.Render(fmt.Sprintf("%d-%d of %d", m.scroll+1, end, len(lines))))
+	}
+	return sections
+}
@@ -0,0 +1,382 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// backupTargetsLoadedMsg carries the backup settings with their targets
+type backupTargetsLoadedMsg struct {
+	config system.BackupConfig
+	err    error
+}
+
+// BackupTargetsModel manages the off-site destinations backups are
+// uploaded to
+type BackupTargetsModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	config  system.BackupConfig
+	cursor  int
+	loading bool
+	err     error
+	success string
+
+	form    *huh.Form
+	editing string // Name of the target being edited; empty when adding
+	kind    *string
+
+	confirm    Confirmation
+	confirming bool
+}
+
+// NewBackupTargetsModel creates the backup targets screen
+func NewBackupTargetsModel() BackupTargetsModel {
+	return BackupTargetsModel{
+		theme:   theme.DefaultTheme(),
+		loading: true,
+	}
+}
+
+// Init loads the targets
+func (m BackupTargetsModel) Init() tea.Cmd {
+	return func() tea.Msg {
+		config, err := system.LoadBackupConfig()
+		return backupTargetsLoadedMsg{config: config, err: err}
+	}
+}
+
+// Update handles messages for the targets screen
+func (m BackupTargetsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case backupTargetsLoadedMsg:
+		m.loading = false
+		m.config = msg.config
+		m.err = msg.err
+		if m.cursor >= len(m.config.Targets) {
+			m.cursor = 0
+		}
+		return m, nil
+	}
+
+	if m.form != nil {
+		if key, ok := msg.(tea.KeyMsg); ok && key.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m.updateForm(msg)
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if m.confirming {
+		if key.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		var result ConfirmResult
+		m.confirm, result = m.confirm.Update(key)
+		switch result {
+		case ConfirmAccepted:
+			m.confirming = false
+			return m.remove()
+		case ConfirmCancelled:
+			m.confirming = false
+		}
+		return m, nil
+	}
+
+	switch key.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: SiteBackupScreen}
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.config.Targets)-1 {
+			m.cursor++
+		}
+	case "a":
+		if !m.loading {
+			return m.openForm(system.BackupTarget{Type: system.BackupTargetS3})
+		}
+	case "enter", "e":
+		if !m.loading && len(m.config.Targets) > 0 {
+			return m.openForm(m.config.Targets[m.cursor])
+		}
+	case "d":
+		if !m.loading && len(m.config.Targets) > 0 {
+			t := m.config.Targets[m.cursor]
+			m.confirm = NewConfirmation("remove_target", "Remove Target",
+				fmt.Sprintf("Remove backup target %s (%s)?\n\nArchives already uploaded there are not deleted.", t.Name, t.Describe()),
+				ConfirmWarning)
+			m.confirming = true
+		}
+	case "t":
+		if !m.loading && len(m.config.Targets) > 0 {
+			t := m.config.Targets[m.cursor]
+			script := t.TestScript()
+			return m, func() tea.Msg {
+				return ExecutionStartMsg{Command: "sudo bash -c " + system.ShellQuote(script), Description: "Testing backup target " + t.Name}
+			}
+		}
+	}
+	return m, nil
+}
+
+// openForm edits a target; a target without a name is added
+func (m BackupTargetsModel) openForm(t system.BackupTarget) (BackupTargetsModel, tea.Cmd) {
+	m.err = nil
+	m.success = ""
+	m.editing = t.Name
+	m.form = m.buildForm(t)
+	return m, m.form.Init()
+}
+
+// buildForm shows the fields of the chosen target type
+func (m *BackupTargetsModel) buildForm(t system.BackupTarget) *huh.Form {
+	name, kind, targetPath := t.Name, t.Type, t.Path
+	bucket, endpoint, region := t.Bucket, t.Endpoint, t.Region
+	accessKey, secretKey := t.AccessKey, t.SecretKey
+	host, user, keyFile := t.Host, t.User, t.KeyFile
+	port := ""
+	if t.Port != 0 {
+		port = strconv.Itoa(t.Port)
+	}
+	remote := t.Remote
+	m.kind = &kind
+
+	required := func(field string) func(string) error {
+		return func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return fmt.Errorf("%s is required", field)
+			}
+			return nil
+		}
+	}
+
+	general := []huh.Field{
+		huh.NewInput().Key("name").Title("Name").
+			Description("Used in logs and for the credentials file").
+			Validate(func(s string) error {
+				s = strings.TrimSpace(s)
+				if _, exists := m.config.Target(s); exists && s != m.editing {
+					return fmt.Errorf("a target named %s already exists", s)
+				}
+				return system.BackupTarget{Name: s, Type: system.BackupTargetRclone, Remote: "x:"}.Validate()
+			}).
+			Value(&name),
+		huh.NewSelect[string]().Key("type").Title("Type").
+			Options(
+				huh.NewOption("S3-compatible (AWS, Wasabi, MinIO, R2...)", system.BackupTargetS3),
+				huh.NewOption("SFTP", system.BackupTargetSFTP),
+				huh.NewOption("rclone remote", system.BackupTargetRclone),
+			).
+			Value(&kind),
+		huh.NewInput().Key("path").Title("Path").
+			Description("Directory archives go in; each site gets a subdirectory").
+			Value(&targetPath),
+	}
+	if m.editing != "" {
+		// Renaming would orphan the credentials file
+		general = general[1:]
+	}
+
+	hideUnless := func(want string) func() bool {
+		return func() bool { return kind != want }
+	}
+	return huh.NewForm(
+		huh.NewGroup(general...).Title("Backup Target"),
+		huh.NewGroup(
+			huh.NewInput().Key("bucket").Title("Bucket").Validate(required("bucket")).Value(&bucket),
+			huh.NewInput().Key("endpoint").Title("Endpoint").
+				Description("Leave blank for AWS; e.g. https://s3.eu-central-1.wasabisys.com").
+				Value(&endpoint),
+			huh.NewInput().Key("region").Title("Region").Placeholder("us-east-1").Value(&region),
+			huh.NewInput().Key("access_key").Title("Access Key").Validate(required("access key")).Value(&accessKey),
+			huh.NewInput().Key("secret_key").Title("Secret Key").
+				EchoMode(huh.EchoModePassword).
+				Validate(required("secret key")).
+				Value(&secretKey),
+		).Title("S3").WithHideFunc(hideUnless(system.BackupTargetS3)),
+		huh.NewGroup(
+			huh.NewInput().Key("host").Title("Host").Validate(required("host")).Value(&host),
+			huh.NewInput().Key("port").Title("Port").Placeholder("22").
+				Validate(func(s string) error {
+					if s = strings.TrimSpace(s); s == "" {
+						return nil
+					}
+					if n, err := strconv.Atoi(s); err != nil || n < 1 || n > 65535 {
+						return fmt.Errorf("port must be between 1 and 65535")
+					}
+					return nil
+				}).
+				Value(&port),
+			huh.NewInput().Key("user").Title("User").Validate(required("user")).Value(&user),
+			huh.NewInput().Key("key_file").Title("Private Key").
+				Description("Key root connects with; blank uses root's default keys").
+				Value(&keyFile),
+		).Title("SFTP").WithHideFunc(hideUnless(system.BackupTargetSFTP)),
+		huh.NewGroup(
+			huh.NewInput().Key("remote").Title("Remote").
+				Description("A remote from root's rclone config, e.g. b2: or gdrive:backups").
+				Validate(required("remote")).
+				Value(&remote),
+		).Title("rclone").WithHideFunc(hideUnless(system.BackupTargetRclone)),
+	).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// updateForm handles the target form and saves the target
+func (m BackupTargetsModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "esc" {
+		m.form = nil
+		return m, nil
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	name := m.editing
+	if name == "" {
+		name = strings.TrimSpace(m.form.GetString("name"))
+	}
+	port, _ := strconv.Atoi(strings.TrimSpace(m.form.GetString("port")))
+	target := system.BackupTarget{Name: name, Type: *m.kind, Path: strings.TrimSpace(m.form.GetString("path"))}
+	switch target.Type {
+	case system.BackupTargetS3:
+		target.Bucket = strings.TrimSpace(m.form.GetString("bucket"))
+		target.Endpoint = strings.TrimSpace(m.form.GetString("endpoint"))
+		target.Region = strings.TrimSpace(m.form.GetString("region"))
+		target.AccessKey = strings.TrimSpace(m.form.GetString("access_key"))
+		target.SecretKey = strings.TrimSpace(m.form.GetString("secret_key"))
+	case system.BackupTargetSFTP:
+		target.Host = strings.TrimSpace(m.form.GetString("host"))
+		target.Port = port
+		target.User = strings.TrimSpace(m.form.GetString("user"))
+		target.KeyFile = strings.TrimSpace(m.form.GetString("key_file"))
+	case system.BackupTargetRclone:
+		target.Remote = strings.TrimSpace(m.form.GetString("remote"))
+	}
+	m.form = nil
+
+	if err := target.Validate(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	config := m.config
+	config.Targets = append([]system.BackupTarget(nil), m.config.Targets...)
+	config.SetTarget(target)
+	if err := config.Save(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.config = config
+	for i, t := range m.config.Targets {
+		if t.Name == target.Name {
+			m.cursor = i
+		}
+	}
+	m.success = m.theme.Symbols.CheckMark + " Saved " + target.Name + "; press t to test the connection"
+	return m, nil
+}
+
+// remove deletes the selected target
+func (m BackupTargetsModel) remove() (BackupTargetsModel, tea.Cmd) {
+	config := m.config
+	config.Targets = append([]system.BackupTarget(nil), m.config.Targets...)
+	name := config.Targets[m.cursor].Name
+	if err := config.RemoveTarget(name); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := config.Save(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.config = config
+	if m.cursor >= len(m.config.Targets) && m.cursor > 0 {
+		m.cursor--
+	}
+	m.err = nil
+	m.success = m.theme.Symbols.CheckMark + " Removed " + name
+	return m, nil
+}
+
+// View renders the targets screen
+func (m BackupTargetsModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Off-site Backup Targets"),
+		m.theme.DescriptionStyle.Render("Every backup and database dump is uploaded to each target"),
+		"",
+	}
+
+	switch {
+	case m.form != nil:
+		sections = append(sections, m.form.View())
+	case m.loading:
+		sections = append(sections, m.theme.InfoStyle.Render("Loading targets..."))
+	case len(m.config.Targets) == 0:
+		sections = append(sections, m.theme.DescriptionStyle.Render("No targets yet. Press a to add S3, SFTP, or rclone."))
+	default:
+		for i, t := range m.config.Targets {
+			line := fmt.Sprintf("%-16s %-7s %s", truncateRunes(t.Name, 16), t.Type, t.Describe())
+			if i == m.cursor {
+				sections = append(sections, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+m.theme.SelectedItem.Render(line))
+			} else {
+				sections = append(sections, "  "+m.theme.MenuItem.Render(line))
+			}
+		}
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := "a: Add" + bullet + "Enter: Edit" + bullet + "t: Test connection" + bullet + "d: Remove" + bullet + "Esc: Back"
+	if m.form != nil {
+		help = "Tab/Shift+Tab: Navigate" + bullet + "Enter: Next/Save" + bullet + "Esc: Cancel"
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
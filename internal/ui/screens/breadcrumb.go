@@ -0,0 +1,175 @@
+package screens
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// screenTitles name the screens in the breadcrumb
+var screenTitles = map[ScreenType]string{
+	MainMenuScreen:               "Main Menu",
+	SetupMenuScreen:              "Install Software",
+	SetupActionScreen:            "Setup",
+	InstalledAppsScreen:          "Installed Applications",
+	UserManagementScreen:         "Users",
+	UserDetailsScreen:            "User",
+	AddUserScreen:                "Add User",
+	NginxConfigScreen:            "Nginx Sites",
+	ConfigMenuScreen:             "Service Settings",
+	QuickCommandsScreen:          "Quick Commands",
+	ConfigEditorScreen:           "Site",
+	SSLOptionsScreen:             "SSL",
+	SSLManualScreen:              "Manual Certificate",
+	EditorSelectionScreen:        "Editor",
+	RedisConfigScreen:            "Redis",
+	RedisPasswordScreen:          "Password",
+	RedisPortScreen:              "Port",
+	ExecutionScreen:              "Running",
+	MySQLManagementScreen:        "MySQL",
+	MySQLPasswordScreen:          "Password",
+	MySQLPortScreen:              "Port",
+	PostgreSQLManagementScreen:   "PostgreSQL",
+	PostgreSQLPasswordScreen:     "Password",
+	PostgreSQLPortScreen:         "Port",
+	PHPFPMManagementScreen:       "PHP-FPM",
+	SupervisorManagementScreen:   "Supervisor",
+	SupervisorXMLRPCConfigScreen: "XML-RPC",
+	SupervisorAddProgramScreen:   "Add Program",
+	FirewallManagementScreen:     "Firewall",
+	DragonflyInstallScreen:       "Dragonfly",
+	SiteCommandsScreen:           "Site Commands",
+	GitManagementScreen:          "Git",
+	LaravelPermissionsScreen:     "Laravel Permissions",
+	NodeVersionScreen:            "npm",
+	PHPVersionScreen:             "Composer",
+	PHPInstallScreen:             "Install PHP",
+	PHPExtensionsScreen:          "PHP Extensions",
+	PHPManagementScreen:          "PHP",
+	FrankenPHPClassicScreen:      "FrankenPHP",
+	FrankenPHPServicesScreen:     "FrankenPHP Services",
+	DeveloperToolkitScreen:       "Developer Toolkit",
+	FileBrowserScreen:            "File Browser",
+	SSHKeyManagementScreen:       "SSH Keys",
+	TextDisplayScreen:            "Details",
+	LaravelQueueScreen:           "Queue Workers",
+	EnvEditorScreen:              ".env",
+	SecretsVaultScreen:           "Secrets Vault",
+	ServersScreen:                "Servers",
+	SiteNotesScreen:              "Notes",
+	DashboardScreen:              "Dashboard",
+	SettingsTransferScreen:       "Export / Import",
+	LogViewerScreen:              "Logs",
+	SystemdServicesScreen:        "Systemd Services",
+	ConfigHistoryScreen:          "Configuration History",
+	SiteStackScreen:              "Stack",
+	SSHDHardeningScreen:          "SSH Hardening",
+	AutoUpdatesScreen:            "Automatic Updates",
+	LaravelLintScreen:            "Laravel Lint",
+	UpdatesScreen:                "System Updates",
+	SiteHardeningScreen:          "Hardening",
+	DBAccessScreen:               "Remote Access",
+	SiteProtocolsScreen:          "Protocols",
+	SettingsScreen:               "Settings",
+	NamingPolicyScreen:           "Naming Policy",
+	PHPIniScreen:                 "PHP Settings",
+	MarketplaceScreen:            "Marketplace",
+	NodeManagementScreen:         "Node.js",
+	NodeAppsScreen:               "Node.js Apps",
+	WordPressSiteScreen:          "WordPress",
+	ComposerAuditScreen:          "Composer Audit",
+	ArtisanScreen:                "Artisan",
+	SiteBackupScreen:             "Backups",
+	BackupTargetsScreen:          "Backup Targets",
+	ConfigDriftScreen:            "Drift",
+	SudoManagementScreen:         "Sudo",
+	UserImportScreen:             "Import Users",
+	OffboardUserScreen:           "Offboard",
+	SFTPUserScreen:               "SFTP User",
+	GitHistoryScreen:             "Git History",
+	SupervisorProgramLogsScreen:  "Program Logs",
+	SupervisorGroupsScreen:       "Groups",
+	PgBouncerScreen:              "PgBouncer",
+	MeilisearchScreen:            "Meilisearch",
+	MailScreen:                   "Mail",
+	DockerScreen:                 "Docker",
+	SiteAccessScreen:             "Access",
+	SiteHeadersScreen:            "Headers",
+	SiteRateLimitsScreen:         "Rate Limits",
+	SiteAnalyticsScreen:          "Analytics",
+	DiskUsageScreen:              "Disk Usage",
+	LogrotateScreen:              "Log Rotation",
+	SwapScreen:                   "Swap",
+	SetupBatchScreen:             "Batch Install",
+	TasksScreen:                  "Background Tasks",
+	XdebugScreen:                 "Xdebug",
+	ProfilerScreen:               "Profiling Agent",
+	ExportersScreen:              "Prometheus Exporters",
+	NetdataScreen:                "Netdata",
+}
+
+// Title returns the screen's name in the breadcrumb
+func (s ScreenType) Title() string {
+	if title, ok := screenTitles[s]; ok {
+		return title
+	}
+	return "Screen"
+}
+
+// NavStack holds the screens behind the current one, oldest first. The main
+// menu is always the bottom and is not stored.
+type NavStack []ScreenType
+
+// Navigate records moving from one screen to another. Going to a screen
+// already on the stack unwinds back to it, so a screen that returns to its
+// parent by name leaves the same trail as going back. Nothing is recorded
+// when leaving the splash or execution screens, which are never returned to.
+func (s NavStack) Navigate(from, to ScreenType) NavStack {
+	if to == MainMenuScreen {
+		return nil
+	}
+	for i, screen := range s {
+		if screen == to {
+			return s[:i]
+		}
+	}
+	switch from {
+	case to, MainMenuScreen, SplashScreen, ExecutionScreen:
+		return s
+	}
+	return append(s[:len(s):len(s)], from)
+}
+
+// Back returns the previous screen and the stack without it
+func (s NavStack) Back() (ScreenType, NavStack) {
+	if len(s) == 0 {
+		return MainMenuScreen, nil
+	}
+	return s[len(s)-1], s[:len(s)-1]
+}
+
+// Previous returns the screen Back would return to
+func (s NavStack) Previous() ScreenType {
+	previous, _ := s.Back()
+	return previous
+}
+
+// BreadcrumbHeight is the number of lines RenderBreadcrumb takes
+const BreadcrumbHeight = 1
+
+// RenderBreadcrumb renders the trail from the main menu to the current
+// screen, dropping the oldest entries when it is wider than width
+func RenderBreadcrumb(t *theme.Theme, stack NavStack, current ScreenType, width int) string {
+	parents := []string{MainMenuScreen.Title()}
+	for _, screen := range stack {
+		parents = append(parents, screen.Title())
+	}
+
+	separator := " " + t.Symbols.ArrowRight + " "
+	trail := strings.Join(parents, separator) + separator
+	for i := 1; width > 0 && lipgloss.Width(trail+current.Title()) > width-2 && i < len(parents); i++ {
+		trail = strings.Join(append([]string{"..."}, parents[i:]...), separator) + separator
+	}
+	return " " + t.DescriptionStyle.Render(trail) + t.Label.Render(current.Title())
+}
@@ -0,0 +1,73 @@
+package screens
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+func TestNavStackNavigate(t *testing.T) {
+	var s NavStack
+	s = s.Navigate(MainMenuScreen, ConfigMenuScreen)
+	s = s.Navigate(ConfigMenuScreen, MySQLManagementScreen)
+	s = s.Navigate(MySQLManagementScreen, MySQLPasswordScreen)
+	if want := (NavStack{ConfigMenuScreen, MySQLManagementScreen}); !slices.Equal(s, want) {
+		t.Fatalf("expected %v, got %v", want, s)
+	}
+
+	// A task runs from the password screen and finishes on its parent
+	s = s.Navigate(MySQLPasswordScreen, ExecutionScreen)
+	if want := (NavStack{ConfigMenuScreen, MySQLManagementScreen, MySQLPasswordScreen}); !slices.Equal(s, want) {
+		t.Fatalf("expected %v, got %v", want, s)
+	}
+	s = s.Navigate(ExecutionScreen, MySQLManagementScreen)
+	if want := (NavStack{ConfigMenuScreen}); !slices.Equal(s, want) {
+		t.Errorf("expected naming the parent to unwind to it, got %v", s)
+	}
+
+	if s.Navigate(MySQLManagementScreen, MainMenuScreen) != nil {
+		t.Error("expected the main menu to clear the stack")
+	}
+	if got := s.Navigate(MySQLManagementScreen, MySQLManagementScreen); !slices.Equal(got, s) {
+		t.Errorf("expected reopening the current screen to change nothing, got %v", got)
+	}
+}
+
+func TestNavStackBack(t *testing.T) {
+	s := NavStack{ConfigMenuScreen, RedisConfigScreen}
+	previous, s := s.Back()
+	if previous != RedisConfigScreen || len(s) != 1 {
+		t.Errorf("expected Redis and one screen left, got %v %v", previous, s)
+	}
+	_, s = s.Back()
+	previous, s = s.Back()
+	if previous != MainMenuScreen || s != nil {
+		t.Errorf("expected an empty stack to go back to the main menu, got %v %v", previous, s)
+	}
+}
+
+func TestRenderBreadcrumb(t *testing.T) {
+	th := theme.DefaultTheme()
+	stack := NavStack{ConfigMenuScreen, MySQLManagementScreen}
+	got := RenderBreadcrumb(th, stack, MySQLPasswordScreen, 200)
+	for _, title := range []string{"Main Menu", "Service Settings", "MySQL", "Password"} {
+		if !strings.Contains(got, title) {
+			t.Errorf("expected %q in the breadcrumb %q", title, got)
+		}
+	}
+
+	got = RenderBreadcrumb(th, stack, MySQLPasswordScreen, 30)
+	if strings.Contains(got, "Main Menu") || !strings.Contains(got, "...") || !strings.Contains(got, "Password") {
+		t.Errorf("expected a narrow breadcrumb to drop the oldest screens, got %q", got)
+	}
+}
+
+func TestScreenTitles(t *testing.T) {
+	for s := MainMenuScreen; s <= NetdataScreen; s++ {
+		if s.Title() == "Screen" {
+			t.Errorf("screen %d has no breadcrumb title", s)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package screens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// PaletteCommand is a place the command palette can jump to
+type PaletteCommand struct {
+	Title       string
+	Description string
+	Keywords    string // Extra words to match, such as the actions on the screen
+	Screen      ScreenType
+	Data        interface{}
+}
+
+// paletteCommands are screens below the main menu that open without a site
+// or user selected first. The main menu items are added in front of them.
+var paletteCommands = []PaletteCommand{
+	{Title: "Add Nginx Site", Description: "Create a new site with its nginx server block", Keywords: "new domain vhost server block", Screen: ConfigEditorScreen, Data: map[string]interface{}{"action": "add_nginx_site"}},
+	{Title: "Nginx Sites", Description: "Edit, enable, disable, and secure nginx sites", Keywords: "ssl certificate domains vhosts", Screen: NginxConfigScreen},
+	{Title: "Add WordPress Site", Description: "Create a WordPress site with its database", Keywords: "wp wordpress install", Screen: WordPressSiteScreen},
+	{Title: "Redis Configuration", Description: "Set the Redis password, port, and memory", Keywords: "redis password port auth requirepass", Screen: RedisConfigScreen},
+	{Title: "MySQL Management", Description: "Databases, users, root password, and remote access", Keywords: "mysql mariadb database password", Screen: MySQLManagementScreen},
+	{Title: "PostgreSQL Management", Description: "Databases, users, and passwords", Keywords: "postgres pgsql database password", Screen: PostgreSQLManagementScreen},
+	{Title: "PHP-FPM Pools", Description: "Restart PHP-FPM and tune pool workers", Keywords: "restart php-fpm reload pools workers", Screen: PHPFPMManagementScreen},
+	{Title: "Install PHP Version", Description: "Install another PHP version side by side", Keywords: "php install version", Screen: PHPInstallScreen},
+	{Title: "PHP Extensions", Description: "Install and remove PHP extensions", Keywords: "php modules extensions", Screen: PHPExtensionsScreen},
+	{Title: "PHP Settings", Description: "Edit php.ini limits such as memory and upload size", Keywords: "php.ini memory_limit upload_max_filesize", Screen: PHPIniScreen},
+	{Title: "Supervisor Programs", Description: "Start, stop, and restart supervisor programs", Keywords: "restart queue workers supervisorctl", Screen: SupervisorManagementScreen},
+	{Title: "Firewall", Description: "Allow and deny ports with UFW", Keywords: "ufw ports allow deny", Screen: FirewallManagementScreen},
+	{Title: "SSH Hardening", Description: "Disable root login and password authentication", Keywords: "sshd ssh root password keys", Screen: SSHDHardeningScreen},
+	{Title: "Node.js Versions", Description: "Install and switch Node.js versions", Keywords: "node nvm npm", Screen: NodeManagementScreen},
+	{Title: "Node.js Apps", Description: "Start, stop, and restart Node.js apps", Keywords: "node pm2 restart apps", Screen: NodeAppsScreen},
+	{Title: "FrankenPHP Services", Description: "Manage FrankenPHP site services", Keywords: "frankenphp octane restart", Screen: FrankenPHPServicesScreen},
+	{Title: "PgBouncer", Description: "Pool PostgreSQL connections", Keywords: "pgbouncer postgres pool", Screen: PgBouncerScreen},
+	{Title: "Meilisearch", Description: "Manage the Meilisearch service and keys", Keywords: "meilisearch search master key", Screen: MeilisearchScreen},
+	{Title: "Mail", Description: "Configure outgoing mail", Keywords: "mail smtp postfix relay", Screen: MailScreen},
+	{Title: "Docker", Description: "Containers, images, and compose projects", Keywords: "docker containers compose", Screen: DockerScreen},
+	{Title: "Git Repositories", Description: "Clone and update site repositories", Keywords: "git clone pull deploy key", Screen: GitManagementScreen},
+	{Title: "Laravel Permissions", Description: "Fix storage and cache permissions", Keywords: "laravel storage chmod chown", Screen: LaravelPermissionsScreen},
+	{Title: "Composer Install", Description: "Run composer with a chosen PHP version", Keywords: "composer install update php", Screen: PHPVersionScreen},
+	{Title: "npm Install", Description: "Run npm with a chosen Node.js version", Keywords: "npm install build node", Screen: NodeVersionScreen},
+	{Title: "Swap File", Description: "Create, resize, and tune the swap file", Keywords: "swap swappiness memory", Screen: SwapScreen},
+	{Title: "Prometheus Exporters", Description: "Install exporters and restrict scraping to one source", Keywords: "prometheus exporter node_exporter metrics monitoring", Screen: ExportersScreen},
+	{Title: "Netdata", Description: "Real-time graphs: bind to localhost or proxy with basic auth", Keywords: "netdata monitoring graphs dashboard metrics", Screen: NetdataScreen},
+	{Title: "Add User", Description: "Create a system user", Keywords: "user account create", Screen: AddUserScreen},
+	{Title: "Import Users", Description: "Create users from a file", Keywords: "users csv import bulk", Screen: UserImportScreen},
+	{Title: "SFTP User", Description: "Create a chrooted SFTP-only user", Keywords: "sftp chroot user", Screen: SFTPUserScreen},
+	{Title: "Git History", Description: "Browse commits in a repository", Keywords: "git log commits", Screen: GitHistoryScreen},
+	{Title: "Laravel Lint", Description: "Check a Laravel app's configuration", Keywords: "laravel lint check env", Screen: LaravelLintScreen},
+	{Title: "Backup Targets", Description: "Add and test backup destinations", Keywords: "backup s3 sftp targets", Screen: BackupTargetsScreen, Data: map[string]interface{}{}},
+}
+
+// paletteVisible is how many results the palette shows at once
+const paletteVisible = 10
+
+// PaletteModel is the Ctrl+P command palette. Like the lock screen it is
+// owned by the root model and drawn over the current screen.
+type PaletteModel struct {
+	theme    *theme.Theme
+	commands []PaletteCommand
+	query    string
+	results  []PaletteCommand
+	cursor   int
+}
+
+// NewPaletteModel indexes the main menu items and the screens below them
+func NewPaletteModel(items []MenuItem) PaletteModel {
+	var commands []PaletteCommand
+	for _, item := range items {
+		commands = append(commands, PaletteCommand{
+			Title:       item.Title,
+			Description: item.Description,
+			Keywords:    item.Category,
+			Screen:      item.Screen,
+		})
+	}
+	commands = append(commands, paletteCommands...)
+	return PaletteModel{
+		theme:    theme.DefaultTheme(),
+		commands: commands,
+		results:  commands,
+	}
+}
+
+// Update handles key presses. done reports that the palette should close;
+// the command navigates to the chosen screen, or is nil when cancelled.
+func (m PaletteModel) Update(msg tea.KeyMsg) (PaletteModel, tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "ctrl+p", "ctrl+k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil, false
+	case "down", "ctrl+n", "ctrl+j":
+		if m.cursor < len(m.results)-1 {
+			m.cursor++
+		}
+		return m, nil, false
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		return m, nil, true
+	case tea.KeyEnter:
+		if len(m.results) == 0 {
+			return m, nil, false
+		}
+		selected := m.results[m.cursor]
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: selected.Screen, Data: selected.Data}
+		}, true
+	case tea.KeyBackspace:
+		if len(m.query) > 0 {
+			runes := []rune(m.query)
+			m.query = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.query += " "
+	case tea.KeyRunes:
+		m.query += string(msg.Runes)
+	default:
+		return m, nil, false
+	}
+
+	m.results = filterPalette(m.commands, m.query)
+	m.cursor = 0
+	return m, nil, false
+}
+
+// filterPalette returns the commands matching every word of the query, best
+// matches first. Commands keep their index order on equal scores.
+func filterPalette(commands []PaletteCommand, query string) []PaletteCommand {
+	tokens := strings.Fields(strings.ToLower(query))
+	if len(tokens) == 0 {
+		return commands
+	}
+
+	type scored struct {
+		command PaletteCommand
+		score   int
+	}
+	var matches []scored
+	for _, c := range commands {
+		total := 0
+		for _, token := range tokens {
+			score := paletteScore(c, token)
+			if score == 0 {
+				total = 0
+				break
+			}
+			total += score
+		}
+		if total > 0 {
+			matches = append(matches, scored{c, total})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	results := make([]PaletteCommand, len(matches))
+	for i, s := range matches {
+		results[i] = s.command
+	}
+	return results
+}
+
+// paletteScore rates how well one query word matches a command: a title
+// prefix beats the start of a title word, which beats anywhere in the title,
+// then the keywords and description, then the title's letters in order.
+// Zero means no match.
+func paletteScore(c PaletteCommand, token string) int {
+	title := strings.ToLower(c.Title)
+	switch {
+	case strings.HasPrefix(title, token):
+		return 50
+	case paletteWordPrefix(title, token):
+		return 40
+	case strings.Contains(title, token):
+		return 30
+	case paletteWordPrefix(strings.ToLower(c.Keywords), token):
+		return 25
+	case strings.Contains(strings.ToLower(c.Keywords), token):
+		return 20
+	case strings.Contains(strings.ToLower(c.Description), token):
+		return 15
+	case paletteSubsequence(title, token):
+		return 5
+	}
+	return 0
+}
+
+// paletteWordPrefix reports whether any word of s starts with token
+func paletteWordPrefix(s, token string) bool {
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == '/' || r == '(' || r == ')'
+	}) {
+		if strings.HasPrefix(word, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// paletteSubsequence reports whether the letters of token appear in s in
+// order, so "phpfpm" finds "PHP-FPM Pools"
+func paletteSubsequence(s, token string) bool {
+	runes := []rune(token)
+	i := 0
+	for _, r := range s {
+		if i < len(runes) && r == runes[i] {
+			i++
+		}
+	}
+	return i == len(runes)
+}
+
+// View renders the palette
+func (m PaletteModel) View(width, height int) string {
+	if width == 0 {
+		return "Loading..."
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Go to"),
+		"",
+		m.theme.Label.Render("> ") + m.theme.SelectedItem.Render(m.query+"_"),
+		"",
+	}
+
+	if len(m.results) == 0 {
+		sections = append(sections, m.theme.DescriptionStyle.Render("No matching screens"))
+	}
+	start := 0
+	if m.cursor >= paletteVisible {
+		start = m.cursor - paletteVisible + 1
+	}
+	end := min(start+paletteVisible, len(m.results))
+	for i := start; i < end; i++ {
+		c := m.results[i]
+		cursor := "  "
+		title := m.theme.MenuItem.Render(c.Title)
+		if i == m.cursor {
+			cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+			title = m.theme.SelectedItem.Render(c.Title)
+		}
+		sections = append(sections, cursor+title+"  "+m.theme.DescriptionStyle.Render(c.Description))
+	}
+	if len(m.results) > paletteVisible {
+		sections = append(sections, "", m.theme.DescriptionStyle.Render(fmt.Sprintf("  %d/%d", m.cursor+1, len(m.results))))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections = append(sections, "", m.theme.Help.Render(
+		m.theme.Symbols.ArrowUp+"/"+m.theme.Symbols.ArrowDown+": Navigate"+bullet+"Enter: Go"+bullet+"Esc: Close"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -0,0 +1,48 @@
+package screens
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFilterPaletteRanking(t *testing.T) {
+	commands := NewPaletteModel(nil).commands
+	cases := map[string]string{
+		"restart php-fpm": "PHP-FPM Pools",
+		"add nginx site":  "Add Nginx Site",
+		"redis password":  "Redis Configuration",
+		"phpfpm":          "PHP-FPM Pools",
+		"swap":            "Swap File",
+	}
+	for query, want := range cases {
+		results := filterPalette(commands, query)
+		if len(results) == 0 || results[0].Title != want {
+			t.Errorf("filterPalette(%q): expected %q first, got %+v", query, want, results)
+		}
+	}
+	if results := filterPalette(commands, "redis zzzz"); len(results) != 0 {
+		t.Errorf("expected every word to have to match, got %+v", results)
+	}
+	if results := filterPalette(commands, "  "); len(results) != len(commands) {
+		t.Errorf("expected an empty query to list every command, got %d", len(results))
+	}
+}
+
+func TestPaletteUpdate(t *testing.T) {
+	m := NewPaletteModel([]MenuItem{{Title: "Disk Usage", Screen: DiskUsageScreen}})
+	for _, r := range "disk" {
+		m, _, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m, cmd, done := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !done || cmd == nil {
+		t.Fatal("expected Enter to close the palette and navigate")
+	}
+	if nav, ok := cmd().(NavigateMsg); !ok || nav.Screen != DiskUsageScreen {
+		t.Errorf("expected navigation to the disk usage screen, got %+v", cmd())
+	}
+
+	if _, cmd, done := m.Update(tea.KeyMsg{Type: tea.KeyEsc}); !done || cmd != nil {
+		t.Error("expected Esc to close the palette without navigating")
+	}
+}
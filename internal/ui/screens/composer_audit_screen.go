@@ -0,0 +1,279 @@
+package screens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// composerAuditMsg carries the result of composer audit
+type composerAuditMsg struct {
+	audit *system.ComposerAudit
+	err   error
+}
+
+// ComposerAuditModel shows `composer audit` as a sortable table
+type ComposerAuditModel struct {
+	theme     *theme.Theme
+	width     int
+	height    int
+	dir       string
+	user      string // Runs composer as this user when set
+	audit     *system.ComposerAudit
+	sortOrder int // Index into system.AuditSortOrders
+	cursor    int
+	offset    int
+	details   bool // Showing the selected advisory
+	loading   bool
+	err       error
+}
+
+// NewComposerAuditModel creates the audit screen for a project
+func NewComposerAuditModel(dir, user string) ComposerAuditModel {
+	return ComposerAuditModel{theme: theme.DefaultTheme(), dir: dir, user: user, loading: true}
+}
+
+// runAudit audits the project in the background
+func (m ComposerAuditModel) runAudit() tea.Cmd {
+	dir, user := m.dir, m.user
+	return func() tea.Msg {
+		audit, err := system.RunComposerAudit(dir, user)
+		return composerAuditMsg{audit: audit, err: err}
+	}
+}
+
+// Init starts the audit
+func (m ComposerAuditModel) Init() tea.Cmd {
+	return m.runAudit()
+}
+
+// visibleRows is how many advisories fit on screen
+func (m ComposerAuditModel) visibleRows() int {
+	if rows := m.height - 18; rows > 5 {
+		return rows
+	}
+	return 5
+}
+
+// Update handles messages for the audit screen
+func (m ComposerAuditModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case composerAuditMsg:
+		m.loading = false
+		m.audit, m.err = msg.audit, msg.err
+		if m.audit != nil {
+			m.audit.Sort(system.AuditSortOrders[m.sortOrder])
+		}
+		m.cursor, m.offset = 0, 0
+		return m, nil
+
+	case tea.KeyMsg:
+		count := 0
+		if m.audit != nil {
+			count = len(m.audit.Advisories)
+		}
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			if m.details {
+				m.details = false
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: SiteCommandsScreen}
+			}
+		case "up", "k":
+			if !m.details && m.cursor > 0 {
+				m.cursor--
+				if m.cursor < m.offset {
+					m.offset = m.cursor
+				}
+			}
+		case "down", "j":
+			if !m.details && m.cursor < count-1 {
+				m.cursor++
+				if m.cursor >= m.offset+m.visibleRows() {
+					m.offset = m.cursor - m.visibleRows() + 1
+				}
+			}
+		case "enter", " ":
+			if count > 0 {
+				m.details = !m.details
+			}
+		case "s":
+			if m.audit != nil && !m.details {
+				m.sortOrder = (m.sortOrder + 1) % len(system.AuditSortOrders)
+				m.audit.Sort(system.AuditSortOrders[m.sortOrder])
+				m.cursor, m.offset = 0, 0
+			}
+		case "r":
+			if !m.loading {
+				m.loading = true
+				m.details = false
+				m.err = nil
+				return m, m.runAudit()
+			}
+		}
+	}
+	return m, nil
+}
+
+// severityStyle colours a severity label
+func (m ComposerAuditModel) severityStyle(severity string) lipgloss.Style {
+	switch system.SeverityRank(severity) {
+	case 4, 3:
+		return m.theme.ErrorStyle
+	case 2:
+		return m.theme.WarningStyle
+	case 1:
+		return m.theme.InfoStyle
+	}
+	return m.theme.DescriptionStyle
+}
+
+// View renders the audit screen
+func (m ComposerAuditModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections := []string{
+		m.theme.Title.Render("Composer Audit"),
+		m.theme.DescriptionStyle.Render("Project: " + m.dir),
+		"",
+	}
+	help := "↑/↓: Navigate" + bullet + "Enter: Details" + bullet + "s: Sort" + bullet + "r: Re-run" + bullet + "Esc: Back"
+
+	switch {
+	case m.loading:
+		sections = append(sections, m.theme.DescriptionStyle.Render("Running composer audit..."))
+	case m.err != nil:
+		sections = append(sections, m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+		help = "r: Re-run" + bullet + "Esc: Back"
+	case m.details:
+		sections = append(sections, m.detailView(m.audit.Advisories[m.cursor])...)
+		help = "Enter/Esc: Back to list"
+	default:
+		sections = append(sections, m.tableView()...)
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// tableView renders the summary, the advisories, and abandoned packages
+func (m ComposerAuditModel) tableView() []string {
+	advisories := m.audit.Advisories
+	if len(advisories) == 0 {
+		lines := []string{m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " No security advisories affect the locked packages")}
+		return append(lines, m.abandonedView()...)
+	}
+
+	counts := map[string]int{}
+	for _, a := range advisories {
+		counts[a.Severity]++
+	}
+	var summary []string
+	for _, severity := range []string{"critical", "high", "medium", "low", ""} {
+		if counts[severity] == 0 {
+			continue
+		}
+		label := severity
+		if label == "" {
+			label = "unrated"
+		}
+		summary = append(summary, m.severityStyle(severity).Render(fmt.Sprintf("%d %s", counts[severity], label)))
+	}
+	lines := []string{
+		strings.Join(summary, "  ") + m.theme.DescriptionStyle.Render("   sorted by "+system.AuditSortOrders[m.sortOrder]),
+		"",
+		m.theme.Label.Render(fmt.Sprintf("  %-9s %-32s %-14s %-16s %s", "SEVERITY", "PACKAGE", "VERSION", "CVE", "TITLE")),
+	}
+
+	end := m.offset + m.visibleRows()
+	if end > len(advisories) {
+		end = len(advisories)
+	}
+	for i := m.offset; i < end; i++ {
+		a := advisories[i]
+		severity := a.Severity
+		if severity == "" {
+			severity = "unrated"
+		}
+		cursor := "  "
+		style := m.theme.MenuItem
+		if i == m.cursor {
+			cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+			style = m.theme.SelectedItem
+		}
+		row := style.Render(fmt.Sprintf(" %-32s %-14s %-16s %s", truncateRunes(a.Package, 32), truncateRunes(a.Version, 14), a.CVE, truncateRunes(a.Title, 48)))
+		lines = append(lines, cursor+m.severityStyle(a.Severity).Render(fmt.Sprintf("%-9s", severity))+row)
+	}
+	if len(advisories) > m.visibleRows() {
+		lines = append(lines, m.theme.DescriptionStyle.Render(fmt.Sprintf("  %d-%d of %d", m.offset+1, end, len(advisories))))
+	}
+	return append(lines, m.abandonedView()...)
+}
+
+// abandonedView lists abandoned packages and their suggested replacements
+func (m ComposerAuditModel) abandonedView() []string {
+	if len(m.audit.Abandoned) == 0 {
+		return nil
+	}
+	packages := make([]string, 0, len(m.audit.Abandoned))
+	for pkg := range m.audit.Abandoned {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+	lines := []string{"", m.theme.WarningStyle.Render("Abandoned packages")}
+	for _, pkg := range packages {
+		line := " " + m.theme.Symbols.Bullet + " " + pkg
+		if replacement := m.audit.Abandoned[pkg]; replacement != "" {
+			line += m.theme.DescriptionStyle.Render(" (use " + replacement + ")")
+		}
+		lines = append(lines, m.theme.MenuItem.Render(line))
+	}
+	return lines
+}
+
+// detailView shows everything composer reported for an advisory
+func (m ComposerAuditModel) detailView(a system.ComposerAdvisory) []string {
+	severity := a.Severity
+	if severity == "" {
+		severity = "unrated"
+	}
+	field := func(label, value string) string {
+		if value == "" {
+			value = "-"
+		}
+		return m.theme.Label.Render(fmt.Sprintf("%-10s ", label)) + m.theme.MenuItem.Render(value)
+	}
+	return []string{
+		m.theme.Subtitle.Render(a.Title),
+		"",
+		m.theme.Label.Render(fmt.Sprintf("%-10s ", "Severity:")) + m.severityStyle(a.Severity).Render(severity),
+		field("Package:", a.Package),
+		field("Installed:", a.Version),
+		field("Affected:", a.AffectedVersions),
+		field("CVE:", a.CVE),
+		field("Advisory:", a.AdvisoryID),
+		field("Reported:", a.ReportedAt),
+		field("Link:", a.Link),
+		"",
+		m.theme.DescriptionStyle.Render("Update with: composer update " + a.Package + " --with-dependencies"),
+	}
+}
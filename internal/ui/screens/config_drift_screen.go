@@ -0,0 +1,298 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// configDriftMsg carries the result of checking every generated config
+type configDriftMsg struct {
+	drifts []system.ConfigDrift
+	err    error
+}
+
+// ConfigDriftModel regenerates the configs ravact created and flags files
+// edited by hand, before a redeploy overwrites them
+type ConfigDriftModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	mode    string // "list", "diff", "confirm"
+	drifts  []system.ConfigDrift
+	cursor  int
+	loading bool
+
+	diff   []string
+	scroll int
+
+	confirm Confirmation
+	err     error
+	success string
+}
+
+// NewConfigDriftModel creates the drift detection screen
+func NewConfigDriftModel() ConfigDriftModel {
+	return ConfigDriftModel{
+		theme:   theme.DefaultTheme(),
+		mode:    "list",
+		loading: true,
+	}
+}
+
+// Init checks every generated config
+func (m ConfigDriftModel) Init() tea.Cmd {
+	return func() tea.Msg {
+		drifts, err := system.DetectConfigDrift()
+		return configDriftMsg{drifts: drifts, err: err}
+	}
+}
+
+// Update handles messages for the drift screen
+func (m ConfigDriftModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case configDriftMsg:
+		m.loading = false
+		m.drifts = msg.drifts
+		m.err = msg.err
+		if m.cursor >= len(m.drifts) {
+			m.cursor = 0
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case "diff":
+			return m.updateDiff(msg)
+		case "confirm":
+			return m.updateConfirm(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+// updateList handles keys on the list of generated configs
+func (m ConfigDriftModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: MainMenuScreen}
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.drifts)-1 {
+			m.cursor++
+		}
+	case "r":
+		if !m.loading {
+			m.loading = true
+			m.err = nil
+			m.success = ""
+			return m, m.Init()
+		}
+	case "enter", " ":
+		if m.loading || len(m.drifts) == 0 {
+			return m, nil
+		}
+		d := m.drifts[m.cursor]
+		switch {
+		case d.State == system.DriftMissing:
+			m.err = fmt.Errorf("%s no longer exists", d.Config.Path)
+		case d.Err != nil:
+			m.err = d.Err
+		case d.State == system.DriftNone:
+			m.success = m.theme.Symbols.Info + " " + d.Config.Path + " matches a fresh render"
+		default:
+			m.diff = strings.Split(strings.TrimRight(d.Diff(), "\n"), "\n")
+			m.scroll = 0
+			m.mode = "diff"
+		}
+	case "a":
+		// Accept a deliberate manual edit as the new baseline
+		if !m.loading && len(m.drifts) > 0 && m.drifts[m.cursor].State == system.DriftEdited {
+			path := m.drifts[m.cursor].Config.Path
+			if err := system.AcceptConfigDrift(path); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.success = m.theme.Symbols.CheckMark + " Accepted the edits to " + path
+			m.loading = true
+			return m, m.Init()
+		}
+	case "f":
+		if !m.loading && len(m.drifts) > 0 {
+			d := m.drifts[m.cursor]
+			m.confirm = NewConfirmation("forget", "Stop Checking",
+				fmt.Sprintf("Stop checking %s for drift?\nThe file itself is left as it is.", d.Config.Path), ConfirmWarning)
+			m.mode = "confirm"
+		}
+	}
+	return m, nil
+}
+
+// updateDiff handles scrolling a diff
+func (m ConfigDriftModel) updateDiff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.mode = "list"
+	case "up", "k":
+		if m.scroll > 0 {
+			m.scroll--
+		}
+	case "down", "j":
+		if m.scroll < len(m.diff)-m.visibleLines() {
+			m.scroll++
+		}
+	}
+	return m, nil
+}
+
+// updateConfirm handles the forget confirmation
+func (m ConfigDriftModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var result ConfirmResult
+	m.confirm, result = m.confirm.Update(msg)
+	switch result {
+	case ConfirmAccepted:
+		m.mode = "list"
+		path := m.drifts[m.cursor].Config.Path
+		if err := system.ForgetGeneratedConfig(path); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.success = m.theme.Symbols.CheckMark + " No longer checking " + path
+		m.loading = true
+		return m, m.Init()
+	case ConfirmCancelled:
+		m.mode = "list"
+	}
+	return m, nil
+}
+
+// visibleLines is the number of list or diff lines that fit on screen
+func (m ConfigDriftModel) visibleLines() int {
+	if m.height < 20 {
+		return 8
+	}
+	return m.height - 14
+}
+
+// stateStyle colours a drift state
+func (m ConfigDriftModel) stateStyle(d system.ConfigDrift) string {
+	label := fmt.Sprintf("%-12s", d.State)
+	switch {
+	case d.Err != nil && d.State == "":
+		return m.theme.ErrorStyle.Render(fmt.Sprintf("%-12s", "error"))
+	case d.State == system.DriftNone:
+		return m.theme.SuccessStyle.Render(label)
+	case d.State == system.DriftEdited, d.State == system.DriftMissing:
+		return m.theme.ErrorStyle.Render(label)
+	case d.State == system.DriftTemplate:
+		return m.theme.WarningStyle.Render(label)
+	}
+	return m.theme.InfoStyle.Render(label)
+}
+
+// View renders the drift screen
+func (m ConfigDriftModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	var sections []string
+	var help string
+	bullet := " " + m.theme.Symbols.Bullet + " "
+
+	if m.mode == "diff" {
+		d := m.drifts[m.cursor]
+		sections = append(sections,
+			m.theme.Title.Render(d.Config.Path),
+			m.theme.DescriptionStyle.Render("- generated from the stub and stored parameters, + on disk"),
+			"",
+		)
+		end := m.scroll + m.visibleLines()
+		if end > len(m.diff) {
+			end = len(m.diff)
+		}
+		for _, line := range m.diff[m.scroll:end] {
+			sections = append(sections, renderDiffLine(m.theme, line))
+		}
+		help = m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Scroll" + bullet + "Esc: Back"
+	} else {
+		sections = append(sections,
+			m.theme.Title.Render("Detect Drift"),
+			m.theme.DescriptionStyle.Render("Configs ravact generated, rendered again and compared with the files on disk"),
+			"",
+		)
+		edited := 0
+		for _, d := range m.drifts {
+			if d.State == system.DriftEdited {
+				edited++
+			}
+		}
+		switch {
+		case m.loading:
+			sections = append(sections, m.theme.InfoStyle.Render("Checking generated configs..."))
+		case len(m.drifts) == 0:
+			sections = append(sections, m.theme.DescriptionStyle.Render("No generated configs recorded yet. Sites and FrankenPHP services created from now on are tracked."))
+		default:
+			if edited > 0 {
+				sections = append(sections, m.theme.WarningStyle.Render(fmt.Sprintf("%s %d file(s) edited by hand; redeploying would overwrite them", m.theme.Symbols.Warning, edited)), "")
+			}
+			start := 0
+			if m.cursor >= m.visibleLines() {
+				start = m.cursor - m.visibleLines() + 1
+			}
+			end := start + m.visibleLines()
+			if end > len(m.drifts) {
+				end = len(m.drifts)
+			}
+			for i := start; i < end; i++ {
+				d := m.drifts[i]
+				line := truncateRunes(d.Config.Path+"  ("+d.Config.Owner+")", 70)
+				if i == m.cursor {
+					sections = append(sections, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+m.stateStyle(d)+" "+m.theme.SelectedItem.Render(line))
+				} else {
+					sections = append(sections, "  "+m.stateStyle(d)+" "+m.theme.MenuItem.Render(line))
+				}
+			}
+		}
+		help = m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate" + bullet + "Enter: Diff" + bullet +
+			"a: Accept edits" + bullet + "f: Stop checking" + bullet + "r: Recheck" + bullet + "Esc: Back"
+	}
+
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -0,0 +1,388 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/report"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// configHistoryLimit is the number of snapshots listed
+const configHistoryLimit = 200
+
+// handbookGeneratedMsg reports the result of regenerating the handbook
+type handbookGeneratedMsg struct {
+	changed bool
+	err     error
+}
+
+// ConfigHistoryModel browses the configuration history and restores files
+type ConfigHistoryModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	mode    string // "commits", "files", "diff", "confirm"
+	commits []system.ConfigCommit
+	cursor  int
+
+	changes    []system.ConfigChange
+	fileCursor int
+
+	diff   []string
+	scroll int
+
+	restoreRev string
+	confirm    Confirmation
+
+	generating bool
+	err        error
+	success    string
+}
+
+// NewConfigHistoryModel creates a new configuration history model
+func NewConfigHistoryModel() ConfigHistoryModel {
+	m := ConfigHistoryModel{
+		theme: theme.DefaultTheme(),
+		mode:  "commits",
+	}
+	m.commits, m.err = system.ConfigHistory(configHistoryLimit)
+	return m
+}
+
+// Init initializes the history screen
+func (m ConfigHistoryModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the history screen
+func (m ConfigHistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case handbookGeneratedMsg:
+		m.generating = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.success = m.theme.Symbols.CheckMark + " Handbook written to " + report.HandbookPath
+		if !msg.changed {
+			m.success += " (unchanged)"
+		}
+		m.commits, m.err = system.ConfigHistory(configHistoryLimit)
+		m.cursor = 0
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case "files":
+			return m.updateFiles(msg)
+		case "diff":
+			return m.updateDiff(msg)
+		case "confirm":
+			return m.updateConfirm(msg)
+		}
+		return m.updateCommits(msg)
+	}
+
+	return m, nil
+}
+
+// updateCommits handles keys on the snapshot list
+func (m ConfigHistoryModel) updateCommits(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: MainMenuScreen}
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.commits)-1 {
+			m.cursor++
+		}
+	case "s":
+		// Record changes made outside ravact
+		m.success = ""
+		changed, err := system.SnapshotConfig("Manual snapshot")
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.err = nil
+		if !changed {
+			m.success = m.theme.Symbols.Info + " No configuration changes since the last snapshot"
+		}
+		m.commits, m.err = system.ConfigHistory(configHistoryLimit)
+		m.cursor = 0
+	case "h":
+		if m.generating {
+			return m, nil
+		}
+		m.generating = true
+		m.err = nil
+		m.success = ""
+		return m, func() tea.Msg {
+			h, err := report.CollectHandbook()
+			if err != nil {
+				return handbookGeneratedMsg{err: err}
+			}
+			changed, err := h.Save()
+			return handbookGeneratedMsg{changed: changed, err: err}
+		}
+	case "enter", " ":
+		if len(m.commits) == 0 {
+			return m, nil
+		}
+		changes, err := system.ConfigCommitChanges(m.commits[m.cursor].Hash)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.changes = changes
+		m.fileCursor = 0
+		m.err = nil
+		m.success = ""
+		m.mode = "files"
+	}
+	return m, nil
+}
+
+// updateFiles handles keys on the files changed by a snapshot
+func (m ConfigHistoryModel) updateFiles(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.mode = "commits"
+	case "up", "k":
+		if m.fileCursor > 0 {
+			m.fileCursor--
+		}
+	case "down", "j":
+		if m.fileCursor < len(m.changes)-1 {
+			m.fileCursor++
+		}
+	case "enter", " ":
+		if len(m.changes) == 0 {
+			return m, nil
+		}
+		diff, err := system.ConfigFileDiff(m.commits[m.cursor].Hash, m.changes[m.fileCursor].Path)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.diff = strings.Split(strings.TrimRight(diff, "\n"), "\n")
+		m.scroll = 0
+		m.mode = "diff"
+	case "r", "u":
+		return m.askRestore(msg.String() == "u")
+	}
+	return m, nil
+}
+
+// updateDiff handles scrolling a diff
+func (m ConfigHistoryModel) updateDiff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.mode = "files"
+	case "up", "k":
+		if m.scroll > 0 {
+			m.scroll--
+		}
+	case "down", "j":
+		if m.scroll < len(m.diff)-m.visibleLines() {
+			m.scroll++
+		}
+	case "r", "u":
+		return m.askRestore(msg.String() == "u")
+	}
+	return m, nil
+}
+
+// askRestore confirms restoring the selected file. r restores the version
+// in the snapshot (or the deleted file); u reverts the snapshot's change.
+func (m ConfigHistoryModel) askRestore(revert bool) (tea.Model, tea.Cmd) {
+	if len(m.changes) == 0 {
+		return m, nil
+	}
+	commit := m.commits[m.cursor]
+	change := m.changes[m.fileCursor]
+
+	m.restoreRev = commit.Hash
+	what := "the version saved in"
+	switch {
+	case change.Status == "D" && !revert:
+		m.restoreRev = commit.Hash + "^"
+		what = "the version deleted by"
+	case revert && change.Status == "M":
+		m.restoreRev = commit.Hash + "^"
+		what = "the version before"
+	case revert:
+		m.err = fmt.Errorf("only modified files can be reverted; use r to restore")
+		return m, nil
+	}
+
+	m.err = nil
+	m.confirm = NewConfirmation("restore", "Restore File",
+		fmt.Sprintf("Overwrite %s with %s snapshot %s (%s)?\nReload the affected service afterwards.",
+			change.Path, what, commit.ShortHash(), commit.Subject), ConfirmWarning)
+	m.mode = "confirm"
+	return m, nil
+}
+
+// updateConfirm handles the restore confirmation
+func (m ConfigHistoryModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var result ConfirmResult
+	m.confirm, result = m.confirm.Update(msg)
+	switch result {
+	case ConfirmAccepted:
+		path := m.changes[m.fileCursor].Path
+		if err := system.RestoreConfigFile(m.restoreRev, path); err != nil {
+			m.err = err
+			m.mode = "files"
+			return m, nil
+		}
+		m.success = fmt.Sprintf("%s Restored %s", m.theme.Symbols.CheckMark, path)
+		m.commits, m.err = system.ConfigHistory(configHistoryLimit)
+		m.cursor = 0
+		m.mode = "commits"
+	case ConfirmCancelled:
+		m.mode = "files"
+	}
+	return m, nil
+}
+
+// visibleLines is the number of list or diff lines that fit on screen
+func (m ConfigHistoryModel) visibleLines() int {
+	if m.height < 20 {
+		return 8
+	}
+	return m.height - 14
+}
+
+// window returns the slice of n items around cursor that fits on screen
+func (m ConfigHistoryModel) window(n, cursor int) (int, int) {
+	height := m.visibleLines()
+	start := 0
+	if cursor >= height {
+		start = cursor - height + 1
+	}
+	end := start + height
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+// View renders the history screen
+func (m ConfigHistoryModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	var sections []string
+	var help string
+	bullet := " " + m.theme.Symbols.Bullet + " "
+
+	switch m.mode {
+	case "files":
+		commit := m.commits[m.cursor]
+		sections = append(sections,
+			m.theme.Title.Render("Snapshot "+commit.ShortHash()),
+			m.theme.DescriptionStyle.Render(commit.Date.Local().Format("2006-01-02 15:04")+"  "+commit.Subject),
+			"",
+		)
+		start, end := m.window(len(m.changes), m.fileCursor)
+		for i := start; i < end; i++ {
+			change := m.changes[i]
+			status := m.theme.InfoStyle.Render(change.Status)
+			switch change.Status {
+			case "A":
+				status = m.theme.SuccessStyle.Render(change.Status)
+			case "D":
+				status = m.theme.ErrorStyle.Render(change.Status)
+			}
+			if i == m.fileCursor {
+				sections = append(sections, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+status+" "+m.theme.SelectedItem.Render(change.Path))
+			} else {
+				sections = append(sections, "  "+status+" "+m.theme.MenuItem.Render(change.Path))
+			}
+		}
+		help = m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate" + bullet + "Enter: Diff" + bullet +
+			"r: Restore this version" + bullet + "u: Revert change" + bullet + "Esc: Back"
+
+	case "diff":
+		sections = append(sections,
+			m.theme.Title.Render(m.changes[m.fileCursor].Path),
+			m.theme.DescriptionStyle.Render("Snapshot "+m.commits[m.cursor].ShortHash()+": "+m.commits[m.cursor].Subject),
+			"",
+		)
+		end := m.scroll + m.visibleLines()
+		if end > len(m.diff) {
+			end = len(m.diff)
+		}
+		for _, line := range m.diff[m.scroll:end] {
+			sections = append(sections, renderDiffLine(m.theme, line))
+		}
+		help = m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Scroll" + bullet +
+			"r: Restore this version" + bullet + "u: Revert change" + bullet + "Esc: Back"
+
+	default:
+		sections = append(sections,
+			m.theme.Title.Render("Configuration History"),
+			m.theme.DescriptionStyle.Render("Every task records the configuration it changed in "+system.ConfigHistoryDir),
+			"",
+		)
+		if len(m.commits) == 0 && m.err == nil {
+			sections = append(sections, m.theme.DescriptionStyle.Render("No snapshots yet. Press s to record the current configuration."))
+		}
+		start, end := m.window(len(m.commits), m.cursor)
+		for i := start; i < end; i++ {
+			commit := m.commits[i]
+			line := fmt.Sprintf("%s  %s  %s", commit.ShortHash(), commit.Date.Local().Format("2006-01-02 15:04"), commit.Subject)
+			if i == m.cursor {
+				sections = append(sections, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+m.theme.SelectedItem.Render(line))
+			} else {
+				sections = append(sections, "  "+m.theme.MenuItem.Render(line))
+			}
+		}
+		help = m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate" + bullet + "Enter: Changed files" + bullet +
+			"s: Snapshot now" + bullet + "h: Regenerate handbook" + bullet + "Esc: Back"
+	}
+
+	if m.generating {
+		sections = append(sections, "", m.theme.InfoStyle.Render("Generating server handbook..."))
+	}
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
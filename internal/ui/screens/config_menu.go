@@ -60,7 +60,13 @@ func NewConfigMenuModel() ConfigMenuModel {
 	postgresqlInstalled := isServiceInstalled("postgresql")
 	phpfpmInstalled := isServiceInstalled("php8.3-fpm") || isServiceInstalled("php8.2-fpm") || isServiceInstalled("php8.1-fpm")
 	supervisorInstalled := isServiceInstalled("supervisor")
+	pgbouncerInstalled := isServiceInstalled("pgbouncer")
+	meilisearchInstalled := isServiceInstalled("meilisearch")
+	netdataInstalled := isServiceInstalled("netdata")
+	dockerInstalled := isServiceInstalled("docker")
 	firewallInstalled := isFirewallInstalled()
+	_, sshdErr := system.Stat(system.SSHDConfigPath)
+	sshdInstalled := sshdErr == nil
 	
 	items := []ConfigMenuItem{
 		{
@@ -91,6 +97,41 @@ func NewConfigMenuModel() ConfigMenuModel {
 			Available:   postgresqlInstalled,
 			Screen:      PostgreSQLManagementScreen,
 		},
+		{
+			ID:          "pgbouncer",
+			Name:        "pgBouncer Pooler",
+			Description: getDescription(pgbouncerInstalled, "Pool PostgreSQL connections: pool mode, sizes, and users"),
+			Available:   pgbouncerInstalled,
+			Screen:      PgBouncerScreen,
+		},
+		{
+			ID:          "meilisearch",
+			Name:        "Meilisearch",
+			Description: getDescription(meilisearchInstalled, "Laravel Scout search: listen address, master key, and .env keys"),
+			Available:   meilisearchInstalled,
+			Screen:      MeilisearchScreen,
+		},
+		{
+			ID:          "netdata",
+			Name:        "Netdata",
+			Description: getDescription(netdataInstalled, "Real-time graphs: localhost binding, basic-auth proxy, and dashboard URL"),
+			Available:   netdataInstalled,
+			Screen:      NetdataScreen,
+		},
+		{
+			ID:          "mail",
+			Name:        "Outbound Mail",
+			Description: "Relay mail through an SMTP provider with Postfix or msmtp",
+			Available:   true,
+			Screen:      MailScreen,
+		},
+		{
+			ID:          "docker",
+			Name:        "Docker",
+			Description: getDescription(dockerInstalled, "Containers and Compose projects: start, stop, logs, and docker group"),
+			Available:   dockerInstalled,
+			Screen:      DockerScreen,
+		},
 		{
 			ID:          "php",
 			Name:        "PHP-FPM Pools",
@@ -102,7 +143,7 @@ func NewConfigMenuModel() ConfigMenuModel {
 			ID:          "supervisor",
 			Name:        "Supervisor",
 			Description: getDescription(supervisorInstalled, "Manage supervisor programs and XML-RPC configuration"),
-			Available:   supervisorInstalled,
+			Available:   true, // Workers can run as systemd units without supervisord
 			Screen:      SupervisorManagementScreen,
 		},
 		{
@@ -119,6 +160,27 @@ func NewConfigMenuModel() ConfigMenuModel {
 			Available:   firewallInstalled,
 			Screen:      FirewallManagementScreen,
 		},
+		{
+			ID:          "sshd",
+			Name:        "SSH Server",
+			Description: getDescription(sshdInstalled, "Harden sshd: root login, password logins, port, and allowed users"),
+			Available:   sshdInstalled,
+			Screen:      SSHDHardeningScreen,
+		},
+		{
+			ID:          "node",
+			Name:        "Node.js Versions",
+			Description: "Install fnm or nvm system-wide and manage Node.js versions",
+			Available:   true, // Offers to install a version manager when none is found
+			Screen:      NodeManagementScreen,
+		},
+		{
+			ID:          "node_apps",
+			Name:        "Node Apps",
+			Description: "Run Node apps as systemd services or with PM2: start, stop, restart, logs",
+			Available:   true, // Lists nothing until an app is added
+			Screen:      NodeAppsScreen,
+		},
 	}
 
 	return ConfigMenuModel{
@@ -0,0 +1,117 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// ConfigReview shows a staged nginx change as a coloured diff with the
+// result of `nginx -t`, and asks before it is written. Screens keep one as
+// a field like Confirmation. A change that fails the test cannot be
+// accepted.
+type ConfigReview struct {
+	Action  string // Caller-defined identifier for the reviewed operation
+	Change  system.NginxChange
+	TestErr error
+
+	diff   []string
+	scroll int
+}
+
+// NewConfigReview tests a staged change and prepares its diff
+func NewConfigReview(action string, nm *system.NginxManager, change system.NginxChange) ConfigReview {
+	r := ConfigReview{Action: action, Change: change, TestErr: nm.TestChange(change)}
+	if diff := change.Diff(); diff != "" {
+		r.diff = strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	}
+	return r
+}
+
+// visibleLines is the number of diff lines that fit on screen
+func (r ConfigReview) visibleLines(height int) int {
+	if height < 24 {
+		return 8
+	}
+	return height - 16
+}
+
+// Update handles a key press
+func (r ConfigReview) Update(msg tea.KeyMsg, height int) (ConfigReview, ConfirmResult) {
+	switch msg.String() {
+	case "esc", "n", "N":
+		return r, ConfirmCancelled
+	case "y", "Y", "enter":
+		if r.TestErr == nil {
+			return r, ConfirmAccepted
+		}
+	case "up", "k":
+		if r.scroll > 0 {
+			r.scroll--
+		}
+	case "down", "j":
+		if r.scroll < len(r.diff)-r.visibleLines(height) {
+			r.scroll++
+		}
+	}
+	return r, ConfirmPending
+}
+
+// View renders the review as a full-screen dialog
+func (r ConfigReview) View(t *theme.Theme, width, height int) string {
+	sections := []string{
+		t.Title.Render("Review Nginx Changes"),
+		t.DescriptionStyle.Render(r.Change.Path),
+		"",
+	}
+
+	if len(r.diff) == 0 {
+		sections = append(sections, t.InfoStyle.Render(t.Symbols.Info+" No changes to the config"))
+	} else {
+		end := min(r.scroll+r.visibleLines(height), len(r.diff))
+		for _, line := range r.diff[r.scroll:end] {
+			sections = append(sections, renderDiffLine(t, line))
+		}
+		if len(r.diff) > r.visibleLines(height) {
+			sections = append(sections, t.DescriptionStyle.Render(fmt.Sprintf("Showing %d-%d of %d", r.scroll+1, end, len(r.diff))))
+		}
+	}
+
+	sections = append(sections, "")
+	bullet := " " + t.Symbols.Bullet + " "
+	var help string
+	if r.TestErr != nil {
+		sections = append(sections, t.ErrorStyle.Render(t.Symbols.CrossMark+" "+r.TestErr.Error()))
+		help = "↑/↓: Scroll" + bullet + "Esc: Back"
+	} else {
+		sections = append(sections, t.SuccessStyle.Render(t.Symbols.CheckMark+" nginx -t passed with the staged config"))
+		help = "↑/↓: Scroll" + bullet + "y/Enter: Apply" + bullet + "n/Esc: Cancel"
+	}
+	sections = append(sections, "", t.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := t.RenderBox(content)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// renderDiffLine colours a line of a unified diff
+func renderDiffLine(t *theme.Theme, line string) string {
+	if len([]rune(line)) > t.AppWidth {
+		line = string([]rune(line)[:t.AppWidth-1]) + "…"
+	}
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "):
+		return t.DescriptionStyle.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return t.SuccessStyle.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return t.ErrorStyle.Render(line)
+	case strings.HasPrefix(line, "@@"):
+		return t.InfoStyle.Render(line)
+	}
+	return t.MenuItem.Render(line)
+}
@@ -0,0 +1,25 @@
+package screens
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestConfigReviewBlocksFailingChange(t *testing.T) {
+	yes := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}
+
+	passing := ConfigReview{}
+	if _, result := passing.Update(yes, 40); result != ConfirmAccepted {
+		t.Errorf("expected y to apply a passing change, got %v", result)
+	}
+
+	failing := ConfigReview{TestErr: errors.New("nginx config test failed")}
+	if _, result := failing.Update(yes, 40); result != ConfirmPending {
+		t.Errorf("expected a failing change not to be applied, got %v", result)
+	}
+	if _, result := failing.Update(tea.KeyMsg{Type: tea.KeyEsc}, 40); result != ConfirmCancelled {
+		t.Errorf("expected Esc to cancel, got %v", result)
+	}
+}
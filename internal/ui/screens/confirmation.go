@@ -0,0 +1,143 @@
+package screens
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// ConfirmSeverity controls how much effort a confirmation asks for
+type ConfirmSeverity int
+
+const (
+	// ConfirmNormal accepts y or Enter
+	ConfirmNormal ConfirmSeverity = iota
+	// ConfirmWarning accepts y only, so a stray Enter does not confirm
+	ConfirmWarning
+	// ConfirmDanger requires typing the resource name
+	ConfirmDanger
+)
+
+// ConfirmResult is the outcome of a key press on a confirmation
+type ConfirmResult int
+
+const (
+	ConfirmPending ConfirmResult = iota
+	ConfirmAccepted
+	ConfirmCancelled
+)
+
+// Confirmation is a shared yes/no prompt. Screens keep one as a field,
+// forward key presses to Update while it is active, and act on the result.
+type Confirmation struct {
+	Action   string // Caller-defined identifier for the confirmed operation
+	Title    string
+	Message  string
+	Severity ConfirmSeverity
+	Phrase   string // Text that must be typed for ConfirmDanger
+
+	typed string
+}
+
+// NewConfirmation creates a y/n confirmation
+func NewConfirmation(action, title, message string, severity ConfirmSeverity) Confirmation {
+	return Confirmation{Action: action, Title: title, Message: message, Severity: severity}
+}
+
+// NewDangerConfirmation creates a confirmation that requires typing phrase,
+// normally the name of the resource being destroyed
+func NewDangerConfirmation(action, title, message, phrase string) Confirmation {
+	return Confirmation{Action: action, Title: title, Message: message, Severity: ConfirmDanger, Phrase: phrase}
+}
+
+// Update handles a key press
+func (c Confirmation) Update(msg tea.KeyMsg) (Confirmation, ConfirmResult) {
+	if msg.Type == tea.KeyEsc {
+		return c, ConfirmCancelled
+	}
+
+	if c.Severity != ConfirmDanger {
+		switch msg.String() {
+		case "y", "Y":
+			return c, ConfirmAccepted
+		case "enter":
+			if c.Severity == ConfirmNormal {
+				return c, ConfirmAccepted
+			}
+		case "n", "N":
+			return c, ConfirmCancelled
+		}
+		return c, ConfirmPending
+	}
+
+	switch msg.Type {
+	case tea.KeyEnter:
+		if c.typed == c.Phrase {
+			return c, ConfirmAccepted
+		}
+	case tea.KeyBackspace:
+		if len(c.typed) > 0 {
+			runes := []rune(c.typed)
+			c.typed = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		c.typed += " "
+	case tea.KeyRunes:
+		c.typed += string(msg.Runes)
+	}
+	return c, ConfirmPending
+}
+
+// Matches reports whether the typed phrase matches
+func (c Confirmation) Matches() bool {
+	return c.typed == c.Phrase
+}
+
+// View renders the confirmation as a full-screen dialog
+func (c Confirmation) View(t *theme.Theme, width, height int) string {
+	title := c.Title
+	if title == "" {
+		title = "Confirm Action"
+	}
+	header := t.Title.Render(title)
+
+	messageStyle := t.WarningStyle
+	if c.Severity == ConfirmDanger {
+		messageStyle = t.ErrorStyle
+	}
+	sections := []string{header, "", messageStyle.Render(c.Message), ""}
+
+	var help string
+	switch c.Severity {
+	case ConfirmDanger:
+		sections = append(sections,
+			t.ErrorStyle.Render(t.Symbols.Warning+" This cannot be undone."),
+			t.MenuItem.Render("Type ")+t.Label.Render(c.Phrase)+t.MenuItem.Render(" to confirm:"),
+		)
+		input := t.SelectedItem.Render(c.typed + "_")
+		if c.typed != "" && !c.Matches() && !strings.HasPrefix(c.Phrase, c.typed) {
+			input = t.ErrorStyle.Render(c.typed + "_")
+		}
+		sections = append(sections, "  "+input)
+		help = t.Help.Render("Enter: Confirm " + t.Symbols.Bullet + " Esc: Cancel")
+	case ConfirmWarning:
+		sections = append(sections,
+			t.MenuItem.Render("  Press 'y' to confirm"),
+			t.MenuItem.Render("  Press 'n' or Esc to cancel"),
+		)
+		help = t.Help.Render("y: Yes " + t.Symbols.Bullet + " n/Esc: No")
+	default:
+		sections = append(sections,
+			t.MenuItem.Render("  Press 'y' or Enter to confirm"),
+			t.MenuItem.Render("  Press 'n' or Esc to cancel"),
+		)
+		help = t.Help.Render("y/Enter: Yes " + t.Symbols.Bullet + " n/Esc: No")
+	}
+	sections = append(sections, "", help)
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := t.RenderBox(content)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -0,0 +1,56 @@
+package screens
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func typeConfirmation(c Confirmation, text string) Confirmation {
+	for _, r := range text {
+		c, _ = c.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return c
+}
+
+func TestConfirmationSeverity(t *testing.T) {
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	yes := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}
+
+	normal := NewConfirmation("stop", "", "Stop?", ConfirmNormal)
+	if _, result := normal.Update(enter); result != ConfirmAccepted {
+		t.Errorf("expected Enter to confirm a normal prompt, got %v", result)
+	}
+
+	warning := NewConfirmation("disable", "", "Disable?", ConfirmWarning)
+	if _, result := warning.Update(enter); result != ConfirmPending {
+		t.Errorf("expected Enter not to confirm a warning prompt, got %v", result)
+	}
+	if _, result := warning.Update(yes); result != ConfirmAccepted {
+		t.Errorf("expected y to confirm a warning prompt, got %v", result)
+	}
+	if _, result := warning.Update(tea.KeyMsg{Type: tea.KeyEsc}); result != ConfirmCancelled {
+		t.Errorf("expected Esc to cancel, got %v", result)
+	}
+}
+
+func TestDangerConfirmationRequiresPhrase(t *testing.T) {
+	c := NewDangerConfirmation("drop", "", "Drop database?", "shop")
+
+	if _, result := c.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}); result != ConfirmPending {
+		t.Errorf("expected y not to confirm a danger prompt, got %v", result)
+	}
+
+	c = typeConfirmation(c, "sho")
+	if _, result := c.Update(tea.KeyMsg{Type: tea.KeyEnter}); result != ConfirmPending {
+		t.Errorf("expected partial phrase not to confirm, got %v", result)
+	}
+
+	c = typeConfirmation(c, "p")
+	if !c.Matches() {
+		t.Fatal("expected typed phrase to match")
+	}
+	if _, result := c.Update(tea.KeyMsg{Type: tea.KeyEnter}); result != ConfirmAccepted {
+		t.Errorf("expected full phrase to confirm, got %v", result)
+	}
+}
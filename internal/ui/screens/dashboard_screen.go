@@ -0,0 +1,319 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// dashboardRefreshInterval is how often the dashboard collects new metrics
+const dashboardRefreshInterval = 2 * time.Second
+
+// dashboardGeneration tags ticks so a reopened dashboard does not pick up
+// the refresh loop of a previous one
+var dashboardGeneration int
+
+// dashboardTickMsg triggers a metrics refresh
+type dashboardTickMsg struct {
+	generation int
+}
+
+// dashboardDataMsg carries a collected snapshot
+type dashboardDataMsg struct {
+	generation int
+	snapshot   *system.HealthSnapshot
+	uptime     *system.MonitorStatus
+	err        error
+}
+
+// DashboardModel represents the server health dashboard
+type DashboardModel struct {
+	theme      *theme.Theme
+	width      int
+	height     int
+	monitor    *system.HealthMonitor
+	generation int
+
+	snapshot *system.HealthSnapshot
+	uptime   *system.MonitorStatus // Latest round of `ravact monitor`, if it runs
+	paused   bool
+	loading  bool
+	err      error
+}
+
+// NewDashboardModel creates a new dashboard model
+func NewDashboardModel() DashboardModel {
+	dashboardGeneration++
+	return DashboardModel{
+		theme:      theme.DefaultTheme(),
+		monitor:    system.NewHealthMonitor(),
+		generation: dashboardGeneration,
+		loading:    true,
+	}
+}
+
+// Init starts collecting metrics
+func (m DashboardModel) Init() tea.Cmd {
+	return m.collect()
+}
+
+// collect gathers a snapshot in the background
+func (m DashboardModel) collect() tea.Cmd {
+	monitor := m.monitor
+	generation := m.generation
+	return func() tea.Msg {
+		snapshot, err := monitor.Collect()
+		uptime, _ := system.LoadMonitorStatus()
+		return dashboardDataMsg{generation: generation, snapshot: snapshot, uptime: uptime, err: err}
+	}
+}
+
+// tick schedules the next refresh
+func (m DashboardModel) tick() tea.Cmd {
+	generation := m.generation
+	return tea.Tick(dashboardRefreshInterval, func(time.Time) tea.Msg {
+		return dashboardTickMsg{generation: generation}
+	})
+}
+
+// Update handles messages for the dashboard
+func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case dashboardDataMsg:
+		if msg.generation != m.generation {
+			return m, nil
+		}
+		m.loading = false
+		m.err = msg.err
+		if msg.snapshot != nil {
+			m.snapshot = msg.snapshot
+		}
+		m.uptime = msg.uptime
+		if m.paused {
+			return m, nil
+		}
+		return m, m.tick()
+
+	case dashboardTickMsg:
+		if msg.generation != m.generation || m.paused {
+			return m, nil
+		}
+		return m, m.collect()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+
+		case "esc", "backspace":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: MainMenuScreen}
+			}
+
+		case "r":
+			if !m.loading {
+				m.loading = true
+				return m, m.collect()
+			}
+
+		case "p":
+			m.paused = !m.paused
+			if !m.paused && !m.loading {
+				m.loading = true
+				return m, m.collect()
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// usageBar renders a fixed-width bar for a percentage
+func (m DashboardModel) usageBar(percent float64, width int) string {
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	filled := int(percent / 100 * float64(width))
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+
+	style := m.theme.SuccessStyle
+	switch {
+	case percent >= 90:
+		style = m.theme.ErrorStyle
+	case percent >= 75:
+		style = m.theme.WarningStyle
+	}
+	return style.Render(bar) + fmt.Sprintf(" %5.1f%%", percent)
+}
+
+// percentOf returns used as a percentage of total
+func percentOf(used, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(used) / float64(total) * 100
+}
+
+// formatUptime renders an uptime as days, hours and minutes
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
+
+// View renders the dashboard
+func (m DashboardModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	title := "Server Health"
+	if t := system.CurrentTransport(); t.IsRemote() {
+		title += ": " + t.Name()
+	}
+	header := m.theme.Title.Render(title)
+
+	sections := []string{header, ""}
+
+	if m.snapshot == nil {
+		if m.err != nil {
+			sections = append(sections, m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+		} else {
+			sections = append(sections, m.theme.DescriptionStyle.Render("Collecting metrics..."))
+		}
+	} else {
+		s := m.snapshot
+		const barWidth = 30
+
+		cpuLabel := "CPU"
+		if s.CPUCount > 0 {
+			cpuLabel = fmt.Sprintf("CPU (%d)", s.CPUCount)
+		}
+		metrics := []string{
+			m.theme.Label.Render(fmt.Sprintf("%-10s", cpuLabel)) + m.usageBar(s.CPUPercent, barWidth),
+			m.theme.Label.Render(fmt.Sprintf("%-10s", "Memory")) + m.usageBar(percentOf(s.MemUsed, s.MemTotal), barWidth) +
+				m.theme.DescriptionStyle.Render(fmt.Sprintf("  %s / %s", system.FormatBytes(s.MemUsed), system.FormatBytes(s.MemTotal))),
+		}
+		if s.SwapTotal > 0 {
+			metrics = append(metrics, m.theme.Label.Render(fmt.Sprintf("%-10s", "Swap"))+m.usageBar(percentOf(s.SwapUsed, s.SwapTotal), barWidth)+
+				m.theme.DescriptionStyle.Render(fmt.Sprintf("  %s / %s", system.FormatBytes(s.SwapUsed), system.FormatBytes(s.SwapTotal))))
+		}
+		if s.DiskTotal > 0 {
+			metrics = append(metrics, m.theme.Label.Render(fmt.Sprintf("%-10s", "Disk /"))+m.usageBar(percentOf(s.DiskUsed, s.DiskTotal), barWidth)+
+				m.theme.DescriptionStyle.Render(fmt.Sprintf("  %s / %s", system.FormatBytes(s.DiskUsed), system.FormatBytes(s.DiskTotal))))
+		}
+
+		loadStyle := m.theme.MenuItem
+		if s.CPUCount > 0 && s.Load1 > float64(s.CPUCount) {
+			loadStyle = m.theme.WarningStyle
+		}
+		metrics = append(metrics, "",
+			m.theme.Label.Render(fmt.Sprintf("%-10s", "Load"))+loadStyle.Render(fmt.Sprintf("%.2f  %.2f  %.2f", s.Load1, s.Load5, s.Load15))+
+				m.theme.DescriptionStyle.Render("  (1m 5m 15m)"),
+			m.theme.Label.Render(fmt.Sprintf("%-10s", "Uptime"))+m.theme.MenuItem.Render(formatUptime(s.Uptime)),
+		)
+		sections = append(sections, lipgloss.JoinVertical(lipgloss.Left, metrics...), "", m.theme.Subtitle.Render("Services:"), "")
+
+		if len(s.Services) == 0 {
+			sections = append(sections, m.theme.DescriptionStyle.Render("  No monitored services installed"))
+		} else {
+			var services []string
+			healthy := 0
+			for _, svc := range s.Services {
+				var status string
+				switch {
+				case svc.Healthy():
+					healthy++
+					status = m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " " + svc.Active)
+				case svc.Active == "failed":
+					status = m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark + " " + svc.Active)
+				default:
+					status = m.theme.WarningStyle.Render(m.theme.Symbols.Warning + " " + svc.Active)
+				}
+				services = append(services, "  "+m.theme.MenuItem.Render(fmt.Sprintf("%-28s", svc.Unit))+status+m.theme.DescriptionStyle.Render(" ("+svc.Sub+")"))
+			}
+			summary := fmt.Sprintf("  %d of %d services running", healthy, len(s.Services))
+			summaryStyle := m.theme.SuccessStyle
+			if healthy < len(s.Services) {
+				summaryStyle = m.theme.WarningStyle
+			}
+			sections = append(sections, lipgloss.JoinVertical(lipgloss.Left, services...), "", summaryStyle.Render(summary))
+		}
+
+		if m.uptime != nil {
+			sections = append(sections, "", m.theme.Subtitle.Render("Uptime Monitor:"), "")
+			sections = append(sections, m.uptimeView()...)
+		}
+
+		status := "Updated " + s.Collected.Format("15:04:05")
+		switch {
+		case m.paused:
+			status += " " + m.theme.Symbols.Bullet + " paused"
+		default:
+			status += fmt.Sprintf(" %s refreshing every %s", m.theme.Symbols.Bullet, dashboardRefreshInterval)
+		}
+		sections = append(sections, "", m.theme.DescriptionStyle.Render(status))
+		if m.err != nil {
+			sections = append(sections, m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+		}
+	}
+
+	pauseHelp := "p: Pause"
+	if m.paused {
+		pauseHelp = "p: Resume"
+	}
+	help := m.theme.Help.Render("r: Refresh " + m.theme.Symbols.Bullet + " " + pauseHelp + " " + m.theme.Symbols.Bullet + " Esc: Back " + m.theme.Symbols.Bullet + " q: Quit")
+	sections = append(sections, "", help)
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// uptimeView summarises the latest round of `ravact monitor`
+func (m DashboardModel) uptimeView() []string {
+	u := m.uptime
+	var lines []string
+	for _, site := range u.Sites {
+		status := m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " " + site.Result.Summary())
+		if !site.Result.OK {
+			status = m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark + " " + site.Result.Summary())
+		}
+		lines = append(lines, "  "+m.theme.MenuItem.Render(fmt.Sprintf("%-28s", site.Name))+status)
+	}
+	if len(u.Sites) == 0 {
+		lines = append(lines, m.theme.DescriptionStyle.Render("  No site health checks configured"))
+	}
+
+	sites, services := u.Failing()
+	summary := fmt.Sprintf("  Last round %s: %d of %d sites up", u.UpdatedAt.Format("15:04:05"), len(u.Sites)-len(sites), len(u.Sites))
+	if len(services) > 0 {
+		summary += fmt.Sprintf(", %d failed service(s)", len(services))
+	}
+	style := m.theme.SuccessStyle
+	if len(sites)+len(services) > 0 {
+		style = m.theme.ErrorStyle
+	}
+	lines = append(lines, "", style.Render(summary))
+	if u.Stale(time.Now()) {
+		lines = append(lines, m.theme.WarningStyle.Render(m.theme.Symbols.Warning+"  The monitor has not reported since "+u.UpdatedAt.Format("2006-01-02 15:04")+"; is ravact-monitor running?"))
+	}
+	return lines
+}
@@ -0,0 +1,405 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// dbAccessLoadedMsg carries the database config and any open grant
+type dbAccessLoadedMsg struct {
+	access system.DBAccess
+	grant  system.DBAccessGrant
+	open   bool
+	err    error
+}
+
+// dbAccessDurations are the offered lifetimes of a tunnel or grant
+var dbAccessDurations = []time.Duration{15 * time.Minute, time.Hour, 4 * time.Hour, 8 * time.Hour}
+
+// DBAccessModel lets a developer reach MySQL or PostgreSQL from a local
+// client, either through an SSH tunnel or by opening the port to their IP
+// until a timer closes it again
+type DBAccessModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	engine string // "mysql" or "postgresql"
+	access system.DBAccess
+	grant  system.DBAccessGrant
+	open   bool
+
+	mode      string // "view", "tunnel_form", "open_form"
+	form      *huh.Form
+	clientIP  string
+	localPort string
+	duration  string
+	tunnel    string // Generated ssh command
+
+	confirm    Confirmation
+	confirming bool
+
+	loading     bool
+	err         error
+	copied      bool
+	copiedTimer int
+}
+
+// NewDBAccessModel creates the remote access screen for an engine
+func NewDBAccessModel(engine string) DBAccessModel {
+	return DBAccessModel{
+		theme:    theme.DefaultTheme(),
+		engine:   engine,
+		mode:     "view",
+		clientIP: sshClientIP(),
+		duration: time.Hour.String(),
+		loading:  true,
+	}
+}
+
+// sshClientIP returns the address of the SSH session ravact runs in, which
+// is usually the developer's own IP
+func sshClientIP() string {
+	if fields := strings.Fields(os.Getenv("SSH_CLIENT")); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// Init reads the database config and the state of any open grant.
+// Returning from a script keeps the model, so this also refreshes it.
+func (m DBAccessModel) Init() tea.Cmd {
+	engine := m.engine
+	return func() tea.Msg {
+		msg := dbAccessLoadedMsg{access: system.DBAccess{Engine: engine}}
+		if engine == "mysql" {
+			config, err := system.NewMySQLManager().GetConfig()
+			if err != nil {
+				msg.err = err
+				return msg
+			}
+			msg.access.Port = config.Port
+			msg.access.ConfigPath = config.ConfigPath
+		} else {
+			config, err := system.NewPostgreSQLManager().GetConfig()
+			if err != nil {
+				msg.err = err
+				return msg
+			}
+			msg.access.Port = config.Port
+			msg.access.ConfigPath = config.ConfigPath
+			msg.access.HBAPath = config.HBAPath
+		}
+		msg.grant, msg.open = system.ActiveDBAccess(engine)
+		return msg
+	}
+}
+
+// engineName returns the display name of the engine
+func (m DBAccessModel) engineName() string {
+	if m.engine == "mysql" {
+		return "MySQL"
+	}
+	return "PostgreSQL"
+}
+
+// backScreen returns the management screen of the engine
+func (m DBAccessModel) backScreen() ScreenType {
+	if m.engine == "mysql" {
+		return MySQLManagementScreen
+	}
+	return PostgreSQLManagementScreen
+}
+
+// Update handles messages for the remote access screen
+func (m DBAccessModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case dbAccessLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.access = msg.access
+		m.grant, m.open = msg.grant, msg.open
+		if m.localPort == "" && m.access.Port != 0 {
+			m.localPort = strconv.Itoa(m.access.Port + 10000)
+		}
+		return m, nil
+
+	case CopyTimerTickMsg:
+		if m.copiedTimer > 0 {
+			m.copiedTimer--
+			if m.copiedTimer == 0 {
+				m.copied = false
+			} else {
+				return m, tea.Tick(time.Second, func(t time.Time) tea.Msg { return CopyTimerTickMsg{} })
+			}
+		}
+		return m, nil
+	}
+
+	if m.mode != "view" {
+		return m.updateForm(msg)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if m.confirming {
+		if keyMsg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		var result ConfirmResult
+		m.confirm, result = m.confirm.Update(keyMsg)
+		switch result {
+		case ConfirmAccepted:
+			m.confirming = false
+			m.loading = true
+			script, description := system.DBAccessCloseScript(m.engine), "Closing remote "+m.engineName()+" access"
+			if m.confirm.Action == "open" {
+				script = m.access.Script(time.Now())
+				description = fmt.Sprintf("Opening %s to %s", m.engineName(), m.access.ClientIP)
+			}
+			return m, func() tea.Msg {
+				return ExecutionStartMsg{Command: "sudo bash -c " + system.ShellQuote(script), Description: description}
+			}
+		case ConfirmCancelled:
+			m.confirming = false
+		}
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		screen := m.backScreen()
+		return m, func() tea.Msg { return NavigateMsg{Screen: screen} }
+	case "t":
+		if !m.loading && m.access.Port != 0 {
+			return m.openForm("tunnel_form")
+		}
+	case "o":
+		if !m.loading && m.access.Port != 0 {
+			return m.openForm("open_form")
+		}
+	case "x":
+		if m.open {
+			m.confirm = NewConfirmation("close", "Close Remote Access",
+				fmt.Sprintf("Close port %d to %s now?", m.grant.Port, m.grant.ClientIP), ConfirmNormal)
+			m.confirming = true
+		}
+	case "c":
+		if m.tunnel != "" {
+			clipboard.WriteAll(m.tunnel)
+			m.copied = true
+			m.copiedTimer = 3
+			return m, tea.Tick(time.Second, func(t time.Time) tea.Msg { return CopyTimerTickMsg{} })
+		}
+	}
+	return m, nil
+}
+
+// openForm shows the tunnel or open-port form
+func (m DBAccessModel) openForm(mode string) (tea.Model, tea.Cmd) {
+	durations := make([]huh.Option[string], 0, len(dbAccessDurations))
+	for _, d := range dbAccessDurations {
+		durations = append(durations, huh.NewOption(formatDBAccessDuration(d), d.String()))
+	}
+
+	// The form keeps pointers, so bind locals and read them back by key
+	localPort, clientIP, duration := m.localPort, m.clientIP, m.duration
+	var first huh.Field
+	if mode == "tunnel_form" {
+		first = huh.NewInput().
+			Key("value").
+			Title("Local Port").
+			Description(fmt.Sprintf("Port on your machine forwarded to %s port %d", m.engineName(), m.access.Port)).
+			Value(&localPort).
+			Validate(func(s string) error {
+				port, err := strconv.Atoi(strings.TrimSpace(s))
+				if err != nil || port < 1 || port > 65535 {
+					return fmt.Errorf("port must be between 1-65535")
+				}
+				return nil
+			})
+	} else {
+		first = huh.NewInput().
+			Key("value").
+			Title("Client IP").
+			Description("The public IP address of your machine").
+			Value(&clientIP).
+			Validate(func(s string) error {
+				a := m.access
+				a.ClientIP = strings.TrimSpace(s)
+				a.Duration = time.Hour
+				return a.Validate()
+			})
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			first,
+			huh.NewSelect[string]().
+				Key("duration").
+				Title("Close After").
+				Options(durations...).
+				Value(&duration),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+	m.mode = mode
+	m.err = nil
+	return m, m.form.Init()
+}
+
+// updateForm passes messages to the active form
+func (m DBAccessModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = "view"
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	mode := m.mode
+	m.mode = "view"
+	m.duration = m.form.GetString("duration")
+	duration, _ := time.ParseDuration(m.duration)
+	if mode == "tunnel_form" {
+		m.localPort = strings.TrimSpace(m.form.GetString("value"))
+		localPort, _ := strconv.Atoi(m.localPort)
+		m.tunnel = system.DBTunnelCommand(tunnelServer(), m.access.Port, localPort, duration)
+		return m, nil
+	}
+
+	m.clientIP = strings.TrimSpace(m.form.GetString("value"))
+	m.access.ClientIP = m.clientIP
+	m.access.Duration = duration
+	if err := m.access.Validate(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.confirm = NewConfirmation("open", "Open Remote Access",
+		fmt.Sprintf("Open %s port %d to %s for %s?\n\n"+
+			"%s will listen on all addresses and restart. The firewall only admits %s, "+
+			"and a timer closes the port again when the time is up.",
+			m.engineName(), m.access.Port, m.access.ClientIP, formatDBAccessDuration(duration),
+			m.engineName(), m.access.ClientIP), ConfirmWarning)
+	m.confirming = true
+	return m, nil
+}
+
+// tunnelServer returns the ssh destination of the managed server
+func tunnelServer() system.Server {
+	if t, ok := system.CurrentTransport().(*system.SSHTransport); ok {
+		return t.Server
+	}
+	host, _ := system.HostName()
+	user := os.Getenv("SUDO_USER")
+	if user == "" || user == "root" {
+		user = os.Getenv("USER")
+	}
+	return system.Server{Host: host, User: user}
+}
+
+// formatDBAccessDuration renders a duration as "15 minutes" or "4 hours"
+func formatDBAccessDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%d minutes", int(d.Minutes()))
+	case d == time.Hour:
+		return "1 hour"
+	default:
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	}
+}
+
+// View renders the remote access screen
+func (m DBAccessModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Remote " + m.engineName() + " Access"),
+		"",
+	}
+
+	if m.mode != "view" {
+		sections = append(sections, m.form.View())
+		content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(content))
+	}
+
+	switch {
+	case m.loading:
+		sections = append(sections, m.theme.InfoStyle.Render("Checking access..."))
+	case m.open:
+		remaining := time.Until(m.grant.Expires).Round(time.Minute)
+		sections = append(sections,
+			m.theme.WarningStyle.Render(fmt.Sprintf("%s Port %d is open to %s", m.theme.Symbols.Warning, m.grant.Port, m.grant.ClientIP)),
+			m.theme.DescriptionStyle.Render(fmt.Sprintf("  Closes at %s (in %s)", m.grant.Expires.Local().Format("15:04"), remaining)))
+	case m.access.Port != 0:
+		sections = append(sections,
+			m.theme.SuccessStyle.Render(fmt.Sprintf("%s Port %d is not open to remote clients", m.theme.Symbols.CheckMark, m.access.Port)))
+	}
+
+	if m.tunnel != "" {
+		sections = append(sections, "",
+			m.theme.Label.Render("Run on your machine:"),
+			m.theme.MenuItem.Render("  "+m.tunnel),
+			m.theme.DescriptionStyle.Render(fmt.Sprintf("  Then connect your client to 127.0.0.1:%s", strings.TrimSpace(m.localPort))))
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.copied {
+		sections = append(sections, "", m.theme.CopiedStyle.Render(m.theme.Symbols.Copy+" Copied to clipboard!"))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := "t: SSH tunnel" + bullet + "o: Open to my IP"
+	if m.open {
+		help += bullet + "x: Close now"
+	}
+	if m.tunnel != "" {
+		help += bullet + "c: Copy"
+	}
+	sections = append(sections, "", m.theme.Help.Render(help+bullet+"Esc: Back"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
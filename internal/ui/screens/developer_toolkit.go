@@ -29,7 +29,8 @@ type ToolkitCommand struct {
 	Description string
 	Command     string
 	Category    ToolkitCategory
-	NeedsPath   bool // If true, command needs a project path
+	NeedsPath   bool       // If true, command needs a project path
+	Screen      ScreenType // If set, Enter opens this screen instead of running Command
 }
 
 // DeveloperToolkitModel represents the developer toolkit screen
@@ -79,7 +80,7 @@ func NewDeveloperToolkitModel() DeveloperToolkitModel {
 		{
 			Name:        "Fix Storage Permissions",
 			Description: "Set correct permissions for storage & bootstrap/cache",
-			Command:     "chmod -R 775 storage bootstrap/cache && chown -R www-data:www-data storage bootstrap/cache",
+			Command:     "chmod -R 775 storage bootstrap/cache && chown -R " + system.WebGroup + ":" + system.WebGroup + " storage bootstrap/cache",
 			Category:    LaravelCategory,
 			NeedsPath:   true,
 		},
@@ -123,7 +124,7 @@ func NewDeveloperToolkitModel() DeveloperToolkitModel {
 		{
 			Name:        "Fix wp-content Permissions",
 			Description: "Set correct permissions for wp-content directory",
-			Command:     "find wp-content -type d -exec chmod 755 {} \\; && find wp-content -type f -exec chmod 644 {} \\; && chown -R www-data:www-data wp-content",
+			Command:     "find wp-content -type d -exec chmod 755 {} \\; && find wp-content -type f -exec chmod 644 {} \\; && chown -R " + system.WebGroup + ":" + system.WebGroup + " wp-content",
 			Category:    WordPressCategory,
 			NeedsPath:   true,
 		},
@@ -234,6 +235,12 @@ func NewDeveloperToolkitModel() DeveloperToolkitModel {
 			Category:    PHPCategory,
 			NeedsPath:   true,
 		},
+		{
+			Name:        "Set Up Profiling Agent",
+			Description: "Install Blackfire or SPX for a PHP version and check it works",
+			Category:    PHPCategory,
+			Screen:      ProfilerScreen,
+		},
 		{
 			Name:        "List PHP-FPM Pools",
 			Description: "Show PHP-FPM pool configurations",
@@ -415,7 +422,7 @@ func (m DeveloperToolkitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "c":
 			// Copy command to clipboard
-			if len(m.filteredCmds) > 0 && m.cursor < len(m.filteredCmds) {
+			if len(m.filteredCmds) > 0 && m.cursor < len(m.filteredCmds) && m.filteredCmds[m.cursor].Command != "" {
 				cmd := m.filteredCmds[m.cursor]
 				err := clipboard.WriteAll(cmd.Command)
 				m.copied = true
@@ -434,6 +441,11 @@ func (m DeveloperToolkitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Execute command (navigate to execution screen)
 			if len(m.filteredCmds) > 0 && m.cursor < len(m.filteredCmds) {
 				cmd := m.filteredCmds[m.cursor]
+				if cmd.Screen != 0 {
+					return m, func() tea.Msg {
+						return NavigateMsg{Screen: cmd.Screen}
+					}
+				}
 				// Pass system user for commands that need it
 				return m, func() tea.Msg {
 					return ExecuteToolkitCommandMsg{
@@ -518,6 +530,10 @@ func (m DeveloperToolkitModel) View() string {
 			cmdStyle = m.theme.Help.Render("    $ " + truncateCommand(cmd.Command, m.width-20))
 		}
 
+		if cmd.Screen != 0 {
+			cmdStyle = m.theme.Help.Render("    " + m.theme.Symbols.ArrowRight + " Opens a setup form")
+		}
+
 		cmdItems = append(cmdItems, nameStyle, descStyle, cmdStyle, "")
 	}
 
@@ -0,0 +1,233 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// diskUsageItemsShown is the number of items listed under an expanded
+// category
+const diskUsageItemsShown = 8
+
+// diskUsageScannedMsg carries a finished scan
+type diskUsageScannedMsg struct {
+	categories []system.DiskCategory
+	total      uint64
+	used       uint64
+}
+
+// DiskUsageModel shows the usual space hogs as a tree and runs their
+// cleanups
+type DiskUsageModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	categories []system.DiskCategory
+	expanded   map[string]bool
+	total      uint64
+	used       uint64
+	usedBefore uint64 // Root filesystem use before a running cleanup
+	freed      uint64
+	loading    bool
+	cursor     int
+
+	confirm    Confirmation
+	confirming bool
+}
+
+// NewDiskUsageModel creates the disk usage screen
+func NewDiskUsageModel() DiskUsageModel {
+	return DiskUsageModel{
+		theme:    theme.DefaultTheme(),
+		expanded: map[string]bool{},
+		loading:  true,
+	}
+}
+
+// Init scans the disk
+func (m DiskUsageModel) Init() tea.Cmd {
+	return func() tea.Msg {
+		total, used, _ := system.RootDiskUsage()
+		return diskUsageScannedMsg{categories: system.ScanDiskUsage(), total: total, used: used}
+	}
+}
+
+// Cleaning reports whether a cleanup was started from the screen. The
+// model is kept across the cleanup so the rescan can show what it freed.
+func (m DiskUsageModel) Cleaning() bool {
+	return m.usedBefore > 0
+}
+
+// Update handles messages for the disk usage screen
+func (m DiskUsageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case diskUsageScannedMsg:
+		m.loading = false
+		m.categories = msg.categories
+		m.total, m.used = msg.total, msg.used
+		m.freed = 0
+		if m.usedBefore > m.used {
+			m.freed = m.usedBefore - m.used
+		}
+		m.usedBefore = 0
+		if m.cursor >= len(m.categories) {
+			m.cursor = 0
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.confirming {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.confirm, result = m.confirm.Update(msg)
+			switch result {
+			case ConfirmAccepted:
+				m.confirming = false
+				cleanup := m.categories[m.cursor].Cleanup
+				m.usedBefore = m.used
+				m.loading = true
+				return m, func() tea.Msg {
+					return ExecutionStartMsg{Command: "sudo bash -c " + system.ShellQuote(cleanup.Script), Description: cleanup.Title}
+				}
+			case ConfirmCancelled:
+				m.confirming = false
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: MainMenuScreen}
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.categories)-1 {
+				m.cursor++
+			}
+		case "enter", " ", "right", "left":
+			if len(m.categories) > 0 {
+				id := m.categories[m.cursor].ID
+				m.expanded[id] = !m.expanded[id]
+			}
+		case "r":
+			if !m.loading {
+				m.loading = true
+				return m, m.Init()
+			}
+		case "c":
+			if m.loading || len(m.categories) == 0 {
+				break
+			}
+			c := m.categories[m.cursor]
+			if c.Cleanup == nil || c.Size == 0 {
+				break
+			}
+			m.confirm = NewConfirmation("cleanup", c.Cleanup.Title,
+				fmt.Sprintf("Free up to %s from %s?\n\n%s\n\n%s",
+					system.FormatBytes(uint64(c.Size)), strings.ToLower(c.Title), c.Note, c.Cleanup.Script), ConfirmWarning)
+			m.confirming = true
+		}
+	}
+	return m, nil
+}
+
+// View renders the disk usage screen
+func (m DiskUsageModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{m.theme.Title.Render("Disk Usage")}
+	if m.total > 0 {
+		usage := fmt.Sprintf("/ %s of %s used (%d%%)", system.FormatBytes(m.used), system.FormatBytes(m.total), m.used*100/m.total)
+		sections = append(sections, m.theme.DescriptionStyle.Render(usage))
+	}
+	if m.freed > 0 && !m.loading {
+		sections = append(sections, m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark+" Freed "+system.FormatBytes(m.freed)))
+	}
+	sections = append(sections, "")
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := "r: Rescan" + bullet + "Esc: Back"
+	if m.loading {
+		sections = append(sections, m.theme.InfoStyle.Render("Scanning caches, logs, kernels, and projects..."))
+	} else {
+		for i, c := range m.categories {
+			sections = append(sections, m.renderCategory(i, c)...)
+		}
+		help = "Enter: Expand" + bullet + "c: Clean up" + bullet + help
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// renderCategory renders a category row and, when expanded, its largest
+// items as a tree
+func (m DiskUsageModel) renderCategory(i int, c system.DiskCategory) []string {
+	marker := "+"
+	if m.expanded[c.ID] {
+		marker = "-"
+	}
+	cursor := "  "
+	style := m.theme.MenuItem
+	if i == m.cursor {
+		cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+		style = m.theme.SelectedItem
+	}
+	line := fmt.Sprintf("%s %-16s %10s", marker, c.Title, system.FormatBytes(uint64(c.Size)))
+	if c.Cleanup != nil && c.Size > 0 {
+		line += "  [" + c.Cleanup.Title + "]"
+	}
+	lines := []string{cursor + style.Render(line)}
+	if !m.expanded[c.ID] {
+		return lines
+	}
+
+	if c.Note != "" {
+		lines = append(lines, "    "+m.theme.DescriptionStyle.Render(c.Note))
+	}
+	shown := c.Items
+	if len(shown) > diskUsageItemsShown {
+		shown = shown[:diskUsageItemsShown]
+	}
+	pathWidth := max(m.width-30, 20)
+	for j, item := range shown {
+		branch := "├─"
+		if j == len(shown)-1 && len(c.Items) == len(shown) {
+			branch = "└─"
+		}
+		lines = append(lines, fmt.Sprintf("    %s %10s  %s", branch, system.FormatBytes(uint64(item.Size)), truncateRunes(item.Path, pathWidth)))
+	}
+	if more := len(c.Items) - len(shown); more > 0 {
+		lines = append(lines, m.theme.DescriptionStyle.Render(fmt.Sprintf("    └─ %d more", more)))
+	}
+	if len(c.Items) == 0 {
+		lines = append(lines, m.theme.DescriptionStyle.Render("    └─ nothing found"))
+	}
+	return lines
+}
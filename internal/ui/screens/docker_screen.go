@@ -0,0 +1,435 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// dockerActions are the actions for a container or Compose project
+var dockerActions = []struct {
+	label  string
+	action string
+}{
+	{"Start", "start"},
+	{"Stop", "stop"},
+	{"Restart", "restart"},
+	{"View Logs", "logs"},
+	{"← Back to List", "back"},
+}
+
+// dockerItem is a row of the Docker list: a Compose project or a container
+type dockerItem struct {
+	project   *system.ComposeProject
+	container *system.DockerContainer
+}
+
+// name returns the project or container name
+func (i dockerItem) name() string {
+	if i.project != nil {
+		return i.project.Name
+	}
+	return i.container.Name
+}
+
+// DockerModel lists the Compose projects and containers on the host and
+// starts, stops, and tails them
+type DockerModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	installed bool
+	items     []dockerItem
+	members   []string // Members of the docker group
+	cursor    int
+
+	mode         string // "list", "actions", "confirm", "users"
+	actionCursor int
+	confirm      Confirmation
+	form         *huh.Form
+
+	err     error
+	success string
+}
+
+// NewDockerModel creates the Docker screen
+func NewDockerModel() DockerModel {
+	m := DockerModel{
+		theme:     theme.DefaultTheme(),
+		installed: system.DockerInstalled(),
+		mode:      "list",
+	}
+	if m.installed {
+		m.reload()
+	}
+	return m
+}
+
+// reload lists the projects, containers, and docker group members
+func (m *DockerModel) reload() {
+	m.items = nil
+	projects, err := system.ComposeProjects()
+	if err != nil {
+		m.err = err
+	}
+	for i := range projects {
+		m.items = append(m.items, dockerItem{project: &projects[i]})
+	}
+	containers, err := system.DockerContainers()
+	if err != nil {
+		m.err = err
+	}
+	for i := range containers {
+		m.items = append(m.items, dockerItem{container: &containers[i]})
+	}
+	if m.cursor >= len(m.items) {
+		m.cursor = 0
+	}
+
+	m.members = nil
+	if group, err := system.NewUserManager().GetGroup(system.DockerGroup); err == nil {
+		m.members = group.Members
+	}
+}
+
+// Init initializes the Docker screen
+func (m DockerModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the Docker screen
+func (m DockerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	if m.mode == "users" {
+		return m.updateForm(msg)
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if key.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+	switch m.mode {
+	case "actions":
+		return m.updateActions(key)
+	case "confirm":
+		var result ConfirmResult
+		m.confirm, result = m.confirm.Update(key)
+		switch result {
+		case ConfirmAccepted:
+			m.mode = "actions"
+			return m, m.run(m.confirm.Action)
+		case ConfirmCancelled:
+			m.mode = "actions"
+		}
+		return m, nil
+	}
+	return m.updateList(key)
+}
+
+// updateList handles keys on the project and container list
+func (m DockerModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: ConfigMenuScreen}
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.items)-1 {
+			m.cursor++
+		}
+	case "r":
+		if m.installed {
+			m.err = nil
+			m.success = ""
+			m.reload()
+		}
+	case "u":
+		if m.installed {
+			m.err = nil
+			m.success = ""
+			m.form = m.buildUsersForm()
+			m.mode = "users"
+			return m, m.form.Init()
+		}
+	case "enter", " ":
+		if len(m.items) > 0 {
+			m.actionCursor = 0
+			m.err = nil
+			m.success = ""
+			m.mode = "actions"
+		}
+	}
+	return m, nil
+}
+
+// updateActions handles the per-item action menu
+func (m DockerModel) updateActions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.mode = "list"
+	case "up", "k":
+		if m.actionCursor > 0 {
+			m.actionCursor--
+		}
+	case "down", "j":
+		if m.actionCursor < len(dockerActions)-1 {
+			m.actionCursor++
+		}
+	case "enter", " ":
+		return m.executeAction()
+	}
+	return m, nil
+}
+
+// executeAction runs the selected action on the selected item
+func (m DockerModel) executeAction() (tea.Model, tea.Cmd) {
+	item := m.items[m.cursor]
+	switch action := dockerActions[m.actionCursor].action; action {
+	case "back":
+		m.mode = "list"
+
+	case "stop":
+		// Sidecars such as Redis or Meilisearch often back a live site
+		kind := "container"
+		if item.project != nil {
+			kind = "every container of project"
+		}
+		m.confirm = NewConfirmation(action, "Confirm",
+			fmt.Sprintf("Stop %s %s?\nSites depending on it may stop working.", kind, item.name()), ConfirmWarning)
+		m.mode = "confirm"
+
+	case "logs":
+		key := "container"
+		if item.project != nil {
+			key = "compose"
+		}
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: LogViewerScreen, Data: map[string]interface{}{key: item.name()}}
+		}
+
+	default:
+		return m, m.run(action)
+	}
+	return m, nil
+}
+
+// run starts a docker action for the selected item on the execution screen
+func (m DockerModel) run(action string) tea.Cmd {
+	item := m.items[m.cursor]
+	command, description := system.DockerCommand(action, item.name(), item.project != nil)
+	return func() tea.Msg {
+		return ExecutionStartMsg{Command: command, Description: description}
+	}
+}
+
+// buildUsersForm picks login users to add to the docker group
+func (m *DockerModel) buildUsersForm() *huh.Form {
+	isMember := make(map[string]bool, len(m.members))
+	for _, member := range m.members {
+		isMember[member] = true
+	}
+	var options []huh.Option[string]
+	users, _ := system.NewUserManager().GetAllUsers()
+	for _, user := range users {
+		if user.UID >= 1000 && !isMember[user.Username] {
+			options = append(options, huh.NewOption(user.Username, user.Username))
+		}
+	}
+
+	var selected []string
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Key("users").
+				Title("Add Users to the docker Group").
+				Description("Space to toggle. Members can run containers as root; takes effect at next login.").
+				Options(options...).
+				Height(10).
+				Value(&selected),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// updateForm handles the docker group form
+func (m DockerModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = "list"
+			m.form = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	selected, _ := m.form.Get("users").([]string)
+	m.form = nil
+	m.mode = "list"
+	um := system.NewUserManager()
+	for _, user := range selected {
+		if err := um.AddUserToGroup(user, system.DockerGroup); err != nil {
+			m.err = fmt.Errorf("%s: %w", user, err)
+			m.reload()
+			return m, nil
+		}
+	}
+	if len(selected) > 0 {
+		m.success = fmt.Sprintf("%s Added %s to the docker group; they must log in again", m.theme.Symbols.CheckMark, strings.Join(selected, ", "))
+	}
+	m.reload()
+	return m, nil
+}
+
+// stateBadge renders a container state or project status
+func (m DockerModel) stateBadge(state string) string {
+	switch {
+	case state == "running":
+		return m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " running")
+	case strings.HasPrefix(state, "running") && !strings.Contains(state, "exited"):
+		return m.theme.SuccessStyle.Render(state)
+	case state == "restarting", state == "dead", strings.Contains(state, "restarting"):
+		return m.theme.ErrorStyle.Render(state)
+	case strings.Contains(state, "running"):
+		return m.theme.WarningStyle.Render(state)
+	}
+	return m.theme.DescriptionStyle.Render(state)
+}
+
+// View renders the Docker screen
+func (m DockerModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	var sections []string
+	var help string
+
+	switch {
+	case !m.installed:
+		sections = append(sections,
+			m.theme.Title.Render("Docker"),
+			"",
+			m.theme.WarningStyle.Render("Docker is not installed. Install it from Setup → Docker."),
+		)
+		help = "Esc: Back"
+
+	case m.mode == "users" && m.form != nil:
+		sections = append(sections, m.theme.Title.Render("docker Group"), "", m.form.View())
+		help = "Enter: Save " + m.theme.Symbols.Bullet + " Esc: Cancel"
+
+	case m.mode == "actions":
+		item := m.items[m.cursor]
+		detail := ""
+		if item.project != nil {
+			detail = m.stateBadge(item.project.Status) + "  " + m.theme.DescriptionStyle.Render(item.project.ConfigFiles)
+		} else {
+			detail = m.stateBadge(item.container.State) + "  " + m.theme.DescriptionStyle.Render(item.container.Image+"  "+item.container.Ports)
+		}
+		sections = append(sections, m.theme.Title.Render(item.name()), detail, "")
+		for i, a := range dockerActions {
+			if i == m.actionCursor {
+				sections = append(sections, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+m.theme.SelectedItem.Render(a.label))
+			} else {
+				sections = append(sections, "  "+m.theme.MenuItem.Render(a.label))
+			}
+		}
+		help = m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Select " + m.theme.Symbols.Bullet + " Esc: Back"
+
+	default:
+		members := "none"
+		if len(m.members) > 0 {
+			members = strings.Join(m.members, ", ")
+		}
+		sections = append(sections,
+			m.theme.Title.Render("Docker"),
+			m.theme.Label.Render("docker group: ")+m.theme.InfoStyle.Render(members),
+			"",
+		)
+		if len(m.items) == 0 && m.err == nil {
+			sections = append(sections, m.theme.DescriptionStyle.Render("No containers on this server."))
+		}
+		heading := ""
+		for i, item := range m.items {
+			// Projects first, then containers grouped by project
+			group := "Compose Projects"
+			if item.container != nil {
+				group = "Containers"
+			}
+			if group != heading {
+				if heading != "" {
+					sections = append(sections, "")
+				}
+				sections = append(sections, m.theme.Subtitle.Render(group))
+				heading = group
+			}
+
+			var name, state, detail string
+			if item.project != nil {
+				name, state, detail = item.project.Name, item.project.Status, ""
+			} else {
+				name, state, detail = item.container.Name, item.container.State, item.container.Image
+				if item.container.Project != "" {
+					detail = item.container.Project + " " + m.theme.Symbols.Bullet + " " + detail
+				}
+			}
+			label := fmt.Sprintf("%-28s", name)
+			if i == m.cursor {
+				label = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ") + m.theme.SelectedItem.Render(label)
+			} else {
+				label = "  " + m.theme.MenuItem.Render(label)
+			}
+			sections = append(sections, label+" "+m.stateBadge(state)+"  "+m.theme.DescriptionStyle.Render(detail))
+		}
+		help = m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet +
+			" Enter: Actions " + m.theme.Symbols.Bullet + " u: Add docker Users " + m.theme.Symbols.Bullet +
+			" r: Refresh " + m.theme.Symbols.Bullet + " Esc: Back"
+	}
+
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -60,8 +60,11 @@ func NewEditorSelectionModelForFile(filePath, description string, returnScreen S
 	}
 }
 
-// Init initializes the editor selection screen
+// Init opens the editor from the settings right away when one is set
 func (m EditorSelectionModel) Init() tea.Cmd {
+	if AppSettings.Editor != "" {
+		return m.open(AppSettings.Editor)
+	}
 	return nil
 }
 
@@ -104,30 +107,10 @@ func (m EditorSelectionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // executeSelection executes the selected editor
 func (m EditorSelectionModel) executeSelection() (EditorSelectionModel, tea.Cmd) {
 	switch m.cursor {
-	case 0: // nano
-		return m, tea.ExecProcess(exec.Command("nano", m.filePath), func(err error) tea.Msg {
-			if err != nil {
-				return EditorCompleteMsg{
-					Error: fmt.Sprintf("Failed to run nano: %v", err),
-				}
-			}
-			return EditorCompleteMsg{
-				Success: "Config file edited with nano",
-			}
-		})
-
-	case 1: // vi
-		return m, tea.ExecProcess(exec.Command("vi", m.filePath), func(err error) tea.Msg {
-			if err != nil {
-				return EditorCompleteMsg{
-					Error: fmt.Sprintf("Failed to run vi: %v", err),
-				}
-			}
-			return EditorCompleteMsg{
-				Success: "Config file edited with vi",
-			}
-		})
-
+	case 0:
+		return m, m.open("nano")
+	case 1:
+		return m, m.open("vi")
 	case 2: // Cancel
 		return m, func() tea.Msg {
 			return BackMsg{}
@@ -137,6 +120,20 @@ func (m EditorSelectionModel) executeSelection() (EditorSelectionModel, tea.Cmd)
 	return m, nil
 }
 
+// open edits the file with the editor
+func (m EditorSelectionModel) open(editor string) tea.Cmd {
+	return tea.ExecProcess(exec.Command(editor, m.filePath), func(err error) tea.Msg {
+		if err != nil {
+			return EditorCompleteMsg{
+				Error: fmt.Sprintf("Failed to run %s: %v", editor, err),
+			}
+		}
+		return EditorCompleteMsg{
+			Success: "Config file edited with " + editor,
+		}
+	})
+}
+
 // EditorCompleteMsg is sent when editor finishes
 type EditorCompleteMsg struct {
 	Success string
@@ -0,0 +1,107 @@
+package screens
+
+import (
+	"os/user"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// ElevationResultMsg reports the outcome of asking for the sudo password
+type ElevationResultMsg struct {
+	Cancelled bool
+	Err       error
+}
+
+// ElevationModel asks for the sudo password before a task that needs root
+// runs. Like the lock screen it is owned by the root model and drawn over
+// the current screen.
+type ElevationModel struct {
+	theme       *theme.Theme
+	username    string
+	description string
+	input       string
+	checking    bool
+	err         error
+}
+
+// NewElevationModel creates the password prompt for a task
+func NewElevationModel(description string) ElevationModel {
+	m := ElevationModel{
+		theme:       theme.DefaultTheme(),
+		description: description,
+	}
+	if u, err := user.Current(); err == nil {
+		m.username = u.Username
+	}
+	return m
+}
+
+// Update handles key presses and authentication results
+func (m ElevationModel) Update(msg tea.Msg) (ElevationModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ElevationResultMsg:
+		m.checking = false
+		m.err = msg.Err
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.checking {
+			return m, nil
+		}
+		switch msg.Type {
+		case tea.KeyBackspace:
+			if len(m.input) > 0 {
+				runes := []rune(m.input)
+				m.input = string(runes[:len(runes)-1])
+			}
+		case tea.KeySpace:
+			m.input += " "
+		case tea.KeyRunes:
+			m.input += string(msg.Runes)
+		case tea.KeyEsc:
+			return m, func() tea.Msg { return ElevationResultMsg{Cancelled: true} }
+		case tea.KeyEnter:
+			password := m.input
+			m.input = ""
+			m.err = nil
+			m.checking = true
+			return m, func() tea.Msg {
+				return ElevationResultMsg{Err: system.AuthenticateSudo(password)}
+			}
+		}
+	}
+	return m, nil
+}
+
+// View renders the password prompt
+func (m ElevationModel) View(width, height int) string {
+	if width == 0 {
+		return "Loading..."
+	}
+
+	sections := []string{
+		m.theme.Title.Render(m.theme.Symbols.Warning + " Administrator access needed"),
+		"",
+		m.theme.DescriptionStyle.Render(m.description + " needs root privileges."),
+		m.theme.DescriptionStyle.Render("Ravact runs it with sudo and will not ask again while sudo remembers the password."),
+		"",
+		m.theme.Label.Render("[sudo] password for "+m.username+": ") + m.theme.SelectedItem.Render(strings.Repeat("*", len([]rune(m.input)))+"_"),
+	}
+	if m.checking {
+		sections = append(sections, "", m.theme.InfoStyle.Render("Checking..."))
+	}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections = append(sections, "", m.theme.Help.Render("Enter: Continue"+bullet+"Esc: Cancel"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -0,0 +1,307 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// EnvEditorModel represents the .env editor screen
+type EnvEditorModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+	cursor int
+
+	path  string
+	env   *system.EnvFile
+	order []int // Entry indices grouped by key prefix
+
+	reveal         bool // Show secret values unmasked
+	editing        bool
+	editBuffer     string
+	dirty          bool
+	confirmDiscard bool
+
+	err     error
+	success string
+}
+
+// NewEnvEditorModel creates a new .env editor for the given file.
+// An empty path means the .env in the current working directory.
+func NewEnvEditorModel(path string) EnvEditorModel {
+	if path == "" {
+		cwd, _ := os.Getwd()
+		path = filepath.Join(cwd, ".env")
+	}
+
+	m := EnvEditorModel{
+		theme: theme.DefaultTheme(),
+		path:  path,
+	}
+
+	env, err := system.ParseEnvFile(path)
+	if err != nil {
+		m.err = err
+		return m
+	}
+	m.env = env
+	m.order = groupEnvEntries(env)
+	return m
+}
+
+// groupEnvEntries orders entries by key prefix, keeping file order within a group
+func groupEnvEntries(env *system.EnvFile) []int {
+	order := make([]int, len(env.Entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return system.EnvKeyPrefix(env.Entries[order[a]].Key) < system.EnvKeyPrefix(env.Entries[order[b]].Key)
+	})
+	return order
+}
+
+// Init initializes the .env editor screen
+func (m EnvEditorModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the .env editor
+func (m EnvEditorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.editing {
+			return m.updateEditing(msg)
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+
+		case "esc", "backspace":
+			if m.dirty && !m.confirmDiscard {
+				m.confirmDiscard = true
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: SiteCommandsScreen}
+			}
+
+		case "up", "k":
+			m.confirmDiscard = false
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case "down", "j":
+			m.confirmDiscard = false
+			if m.cursor < len(m.order)-1 {
+				m.cursor++
+			}
+
+		case "enter", "e":
+			if len(m.order) > 0 {
+				entry := m.env.Entries[m.order[m.cursor]]
+				m.editing = true
+				m.editBuffer = entry.Value
+				m.success = ""
+				m.err = nil
+			}
+
+		case "r":
+			m.reveal = !m.reveal
+
+		case "s":
+			if m.env == nil {
+				return m, nil
+			}
+			backup, err := m.env.Save()
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.dirty = false
+			m.confirmDiscard = false
+			m.err = nil
+			if backup != "" {
+				m.success = fmt.Sprintf("%s Saved %s (backup: %s)", m.theme.Symbols.CheckMark, m.path, filepath.Base(backup))
+			} else {
+				m.success = fmt.Sprintf("%s Saved %s", m.theme.Symbols.CheckMark, m.path)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// updateEditing handles key input while a value is being edited
+func (m EnvEditorModel) updateEditing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.editing = false
+		m.editBuffer = ""
+	case tea.KeyEnter:
+		entry := m.env.Entries[m.order[m.cursor]]
+		if entry.Value != m.editBuffer {
+			m.env.Set(entry.Key, m.editBuffer)
+			m.dirty = true
+		}
+		m.editing = false
+		m.editBuffer = ""
+	case tea.KeyBackspace:
+		if len(m.editBuffer) > 0 {
+			runes := []rune(m.editBuffer)
+			m.editBuffer = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.editBuffer += " "
+	case tea.KeyRunes:
+		m.editBuffer += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// displayValue returns the value as shown in the list, masking secrets
+func (m EnvEditorModel) displayValue(entry system.EnvEntry) string {
+	if entry.Value == "" {
+		return m.theme.DescriptionStyle.Render("(empty)")
+	}
+	if system.IsSecretEnvKey(entry.Key) && !m.reveal {
+		return m.theme.DescriptionStyle.Render(strings.Repeat("*", 8))
+	}
+	return entry.Value
+}
+
+// View renders the .env editor
+func (m EnvEditorModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	header := m.theme.Title.Render("Environment Editor")
+	pathLine := m.theme.DescriptionStyle.Render(m.path)
+
+	if m.env == nil {
+		content := lipgloss.JoinVertical(lipgloss.Left,
+			header,
+			pathLine,
+			"",
+			m.theme.ErrorStyle.Render(fmt.Sprintf("%s %v", m.theme.Symbols.CrossMark, m.err)),
+			"",
+			m.theme.DescriptionStyle.Render("Create one with Laravel App > Create .env from .env.example"),
+			"",
+			m.theme.Help.Render("Esc: Back "+m.theme.Symbols.Bullet+" q: Quit"),
+		)
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(content))
+	}
+
+	// Validation warnings
+	var warnings []string
+	for _, problem := range m.env.Validate() {
+		warnings = append(warnings, m.theme.WarningStyle.Render(m.theme.Symbols.Warning+" "+problem))
+	}
+	duplicates := make(map[string]bool)
+	for _, key := range m.env.Duplicates() {
+		duplicates[key] = true
+	}
+
+	// Build grouped list lines, remembering where the cursor lands
+	var lines []string
+	cursorLine := 0
+	lastPrefix := ""
+	for i, idx := range m.order {
+		entry := m.env.Entries[idx]
+		prefix := system.EnvKeyPrefix(entry.Key)
+		if i == 0 || prefix != lastPrefix {
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, m.theme.CategoryStyle.Render(prefix))
+			lastPrefix = prefix
+		}
+
+		value := m.displayValue(entry)
+		if i == m.cursor && m.editing {
+			value = m.theme.SelectedItem.Render(m.editBuffer + "_")
+		}
+
+		marker := ""
+		if duplicates[entry.Key] {
+			marker = " " + m.theme.WarningStyle.Render(m.theme.Symbols.Warning)
+		}
+
+		if i == m.cursor {
+			cursorLine = len(lines)
+			lines = append(lines, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+m.theme.Label.Render(entry.Key)+" = "+value+marker)
+		} else {
+			lines = append(lines, "  "+m.theme.MenuItem.Render(entry.Key)+" = "+value+marker)
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, m.theme.DescriptionStyle.Render("No variables defined"))
+	}
+
+	// Keep the cursor visible
+	visible := m.height - 16 - len(warnings)
+	if visible < 5 {
+		visible = 5
+	}
+	start := 0
+	if cursorLine >= visible {
+		start = cursorLine - visible + 1
+	}
+	end := start + visible
+	if end > len(lines) {
+		end = len(lines)
+	}
+	list := lipgloss.JoinVertical(lipgloss.Left, lines[start:end]...)
+
+	sections := []string{header, pathLine, ""}
+	if len(warnings) > 0 {
+		sections = append(sections, lipgloss.JoinVertical(lipgloss.Left, warnings...), "")
+	}
+	sections = append(sections, list, "")
+
+	if m.success != "" {
+		sections = append(sections, m.theme.SuccessStyle.Render(m.success))
+	}
+	if m.err != nil {
+		sections = append(sections, m.theme.ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+	}
+	if m.confirmDiscard {
+		sections = append(sections, m.theme.WarningStyle.Render(m.theme.Symbols.Warning+" Unsaved changes. Press s to save or Esc again to discard."))
+	} else if m.dirty {
+		sections = append(sections, m.theme.InfoStyle.Render("Modified - press s to save"))
+	}
+
+	var help string
+	if m.editing {
+		help = m.theme.Help.Render("Enter: Apply " + m.theme.Symbols.Bullet + " Esc: Cancel edit")
+	} else {
+		revealLabel := "r: Reveal secrets"
+		if m.reveal {
+			revealLabel = "r: Mask secrets"
+		}
+		help = m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Edit " + m.theme.Symbols.Bullet + " " + revealLabel + " " + m.theme.Symbols.Bullet + " s: Save " + m.theme.Symbols.Bullet + " Esc: Back")
+	}
+	sections = append(sections, "", help)
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
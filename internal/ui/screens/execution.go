@@ -6,20 +6,52 @@ import (
 	"context"
 	"embed"
 	"fmt"
+	"os"
 	"os/exec"
-	"runtime"
+	"path"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/config"
+	"github.com/iperamuna/ravact/internal/debuglog"
+	"github.com/iperamuna/ravact/internal/notify"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/system/pkgmanager"
 	"github.com/iperamuna/ravact/internal/ui/theme"
 )
 
 // EmbeddedFS will be set by main package
 var EmbeddedFS embed.FS
 
+// AppSettings are the application settings, set by the main package
+var AppSettings config.Settings
+
+// readSetupScript reads a bundled setup script, preferring a script of the
+// same name in the settings' scripts directory
+func readSetupScript(scriptPath string) ([]byte, error) {
+	if dir := AppSettings.ScriptsDir; dir != "" {
+		if content, err := os.ReadFile(filepath.Join(dir, path.Base(scriptPath))); err == nil {
+			return content, nil
+		}
+	}
+	return EmbeddedFS.ReadFile(scriptPath)
+}
+
+// setupScriptContent reads a setup script with the distribution helpers
+// (pkg_install, svc_name, ...) and verified_download prepended
+func setupScriptContent(scriptPath string) ([]byte, error) {
+	content, err := readSetupScript(scriptPath)
+	if err != nil {
+		return nil, err
+	}
+	distro, _ := pkgmanager.Detect()
+	return append([]byte(distro.ScriptPreamble()+system.DownloadHelpers), content...), nil
+}
+
 // ExecutionState represents the state of execution
 type ExecutionState int
 
@@ -49,6 +81,17 @@ type ExecutionModel struct {
 	copied       bool
 	copiedTimer  int
 	showCommand  bool
+	notice       string // Result of the completion notification
+	unprivileged bool   // Run as the current user instead of as root
+	removes      string // Setup script whose record is dropped on success
+
+	// Privileged tasks on Linux hosts run detached on the host, so they
+	// survive ravact exiting or the ssh connection dropping
+	task        *system.Task
+	taskOffset  int64  // How much of the task's output has been read
+	taskPartial string // Output after the last complete line
+	confirmStop bool   // x was pressed once; pressing it again stops the task
+	stopping    bool
 }
 
 // ExecutionOutputMsg is sent when new output is received
@@ -77,6 +120,119 @@ func NewExecutionModel(command, description string, returnScreen ScreenType) Exe
 	}
 }
 
+// AttachExecutionModel follows a detached task started earlier, e.g.
+// before ravact exited or its connection dropped
+func AttachExecutionModel(task system.Task, returnScreen ScreenType) ExecutionModel {
+	m := NewExecutionModel(task.Command, task.Description, returnScreen)
+	m.task = &task
+	m.startTime = task.Started
+	return m
+}
+
+// WithoutPrivileges runs the command as the current user rather than
+// through sudo or polkit
+func (m ExecutionModel) WithoutPrivileges() ExecutionModel {
+	m.unprivileged = true
+	return m
+}
+
+// Removing marks the command as uninstalling a setup script's software, so
+// its provisioning record is dropped once the command succeeds
+func (m ExecutionModel) Removing(script string) ExecutionModel {
+	m.removes = script
+	return m
+}
+
+// commandContext prepares the task's command, as root unless the task
+// runs without privileges
+func (m ExecutionModel) commandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	if m.unprivileged {
+		return system.CommandContext(ctx, name, args...)
+	}
+	return system.PrivilegedCommandContext(ctx, name, args...)
+}
+
+// configSnapshotMsg reports the result of recording the configuration history
+type configSnapshotMsg struct {
+	err error
+}
+
+// snapshotConfig records any configuration the task changed in the history.
+// Failed tasks are recorded too since they may have changed files before failing.
+func snapshotConfig(description string, success bool) tea.Cmd {
+	if !success {
+		description += " (failed)"
+	}
+	return func() tea.Msg {
+		_, err := system.SnapshotConfig(description)
+		return configSnapshotMsg{err: err}
+	}
+}
+
+// provisionRecordedMsg reports the result of recording a setup script run
+type provisionRecordedMsg struct {
+	err error
+}
+
+// recordProvisioned remembers an embedded setup script that ran to
+// completion, so the setup menu can skip it while nothing has changed
+func recordProvisioned(command string) tea.Cmd {
+	scriptPath, envPrefix := extractScriptPath(command)
+	if scriptPath == "" {
+		return nil
+	}
+	content, err := readSetupScript(scriptPath)
+	if err != nil {
+		return nil
+	}
+	rec := system.ProvisionRecord{
+		Script:      strings.TrimSuffix(path.Base(scriptPath), ".sh"),
+		ScriptHash:  system.ScriptHash(content),
+		Environment: envPrefix,
+	}
+	return func() tea.Msg {
+		return provisionRecordedMsg{err: system.RecordProvisioned(rec)}
+	}
+}
+
+// forgetProvisioned stops tracking a setup script whose software was
+// removed through ravact, since that is not drift
+func forgetProvisioned(script string) tea.Cmd {
+	return func() tea.Msg {
+		return provisionRecordedMsg{err: system.ForgetProvisioned(script)}
+	}
+}
+
+// notifyResultMsg reports the delivery of the completion notification
+type notifyResultMsg struct {
+	sent int
+	errs []error
+}
+
+// notifyCompletion sends the configured notifications for a finished task,
+// skipping short tasks so routine actions do not page anyone
+func notifyCompletion(description string, success bool, duration time.Duration, output []string) tea.Cmd {
+	cfg, err := notify.Load()
+	if err != nil {
+		return func() tea.Msg { return notifyResultMsg{errs: []error{err}} }
+	}
+	host, _ := os.Hostname()
+	if t := system.CurrentTransport(); t.IsRemote() {
+		host = t.Name()
+	}
+	ev := notify.Event{Title: description, Host: host, Success: success, Duration: duration}
+	if !success {
+		ev.Output = notify.Tail(output)
+	}
+	if !cfg.ShouldNotify(ev) {
+		return nil
+	}
+	return func() tea.Msg {
+		errs := cfg.Notify(ev)
+		return notifyResultMsg{sent: len(cfg.Channels) - len(errs), errs: errs}
+	}
+}
+
 // spinnerTick returns a command that sends a tick message for spinner animation
 func spinnerTick() tea.Cmd {
 	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
@@ -84,11 +240,60 @@ func spinnerTick() tea.Cmd {
 	})
 }
 
+// spinnerFrame returns the loading spinner frame for the time since started
+func spinnerFrame(t *theme.Theme, started time.Time) string {
+	frames := t.Symbols.Spinner
+	return frames[int(time.Since(started).Milliseconds()/100)%len(frames)]
+}
+
+// taskStartedMsg reports a task started detached on the host
+type taskStartedMsg struct {
+	task system.Task
+}
+
+// taskProgressMsg carries a detached task's new output and status
+type taskProgressMsg struct {
+	output []byte
+	status system.TaskStatus
+	err    error
+}
+
+// taskStoppedMsg reports the result of stopping a detached task
+type taskStoppedMsg struct {
+	err error
+}
+
+// taskPollInterval is how often a detached task's output is read
+const taskPollInterval = 500 * time.Millisecond
+
+// pollTask reads a detached task's status and the output written since
+// offset after delay. The status is read first so a finished task's output
+// is complete.
+func pollTask(id string, offset int64, delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		status, err := system.TaskStatusOf(id)
+		if err != nil {
+			return taskProgressMsg{err: err}
+		}
+		output, err := system.TaskOutput(id, offset)
+		return taskProgressMsg{output: output, status: status, err: err}
+	})
+}
+
 // Init initializes the execution screen
 func (m ExecutionModel) Init() tea.Cmd {
+	if m.task != nil {
+		return tea.Batch(pollTask(m.task.ID, 0, 0), spinnerTick())
+	}
 	return tea.Batch(m.executeCommand, spinnerTick())
 }
 
+// detached reports whether the task runs detached on the host rather than
+// as a child of ravact
+func (m ExecutionModel) detached() bool {
+	return !m.unprivileged && system.HostOS() == "linux"
+}
+
 // extractScriptPath extracts the embedded script path from a command
 // Returns the script path and any environment variable prefix, or empty strings if not an embedded script
 func extractScriptPath(command string) (scriptPath string, envPrefix string) {
@@ -117,6 +322,17 @@ func (m ExecutionModel) executeCommand() tea.Msg {
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
+	debuglog.Debug("task started", "task", m.description, "privileged", !m.unprivileged)
+
+	// Tasks run as a single privileged command so a non-root user is asked
+	// for their password once, before the task starts
+	if !m.unprivileged && system.DetectPrivilegeMethod() == system.PrivilegeUnavailable {
+		return ExecutionCompleteMsg{
+			Success: false,
+			Output:  system.ErrPrivilegeUnavailable.Error(),
+			Error:   system.ErrPrivilegeUnavailable,
+		}
+	}
 
 	// Check if this is a script path (embedded)
 	var cmd *exec.Cmd
@@ -124,8 +340,8 @@ func (m ExecutionModel) executeCommand() tea.Msg {
 
 	if scriptPath != "" {
 		// Check OS compatibility for setup scripts
-		if runtime.GOOS != "linux" {
-			errorMsg := fmt.Sprintf("⚠ Setup scripts are designed for Linux only.\n\nCurrent OS: %s\n\n", runtime.GOOS)
+		if hostOS := system.HostOS(); hostOS != "linux" {
+			errorMsg := fmt.Sprintf("⚠ Setup scripts are designed for Linux only.\n\nCurrent OS: %s\n\n", hostOS)
 			errorMsg += "These scripts use Linux-specific commands:\n"
 			errorMsg += "  • apt-get / yum (package managers)\n"
 			errorMsg += "  • systemctl (service management)\n"
@@ -139,12 +355,12 @@ func (m ExecutionModel) executeCommand() tea.Msg {
 			return ExecutionCompleteMsg{
 				Success: false,
 				Output:  errorMsg,
-				Error:   fmt.Errorf("setup scripts require Linux (current OS: %s)", runtime.GOOS),
+				Error:   fmt.Errorf("setup scripts require Linux (current OS: %s)", hostOS),
 			}
 		}
 
 		// Execute embedded script by reading content and piping to bash
-		scriptContent, err := EmbeddedFS.ReadFile(scriptPath)
+		scriptContent, err := setupScriptContent(scriptPath)
 		if err != nil {
 			return ExecutionCompleteMsg{
 				Success: false,
@@ -153,13 +369,19 @@ func (m ExecutionModel) executeCommand() tea.Msg {
 			}
 		}
 
+		if m.detached() {
+			return m.startTask(scriptContent, strings.Fields(envPrefix))
+		}
+
 		// Run bash with script piped to stdin
 		// If there's an env prefix, prepend it to set environment variables
-		cmd = exec.CommandContext(ctx, "bash", "-s")
+		// Environment variables are passed through env(1) so they also reach remote hosts
 		if envPrefix != "" {
 			// Parse environment variables from prefix (e.g., "VAR1=val1 VAR2=val2")
 			envVars := strings.Fields(envPrefix)
-			cmd.Env = append(cmd.Environ(), envVars...)
+			cmd = m.commandContext(ctx, "env", append(envVars, "bash", "-s")...)
+		} else {
+			cmd = m.commandContext(ctx, "bash", "-s")
 		}
 		cmd.Stdin = bytes.NewReader(scriptContent)
 	} else {
@@ -171,7 +393,10 @@ func (m ExecutionModel) executeCommand() tea.Msg {
 				Error:   fmt.Errorf("empty command"),
 			}
 		}
-		cmd = exec.CommandContext(ctx, "bash", "-c", m.command)
+		if m.detached() {
+			return m.startTask([]byte(m.command), nil)
+		}
+		cmd = m.commandContext(ctx, "bash", "-c", m.command)
 	}
 
 	// Get stdout and stderr pipes
@@ -242,6 +467,127 @@ func (m ExecutionModel) executeCommand() tea.Msg {
 	}
 }
 
+// startTask starts the task detached on the host
+func (m ExecutionModel) startTask(script []byte, env []string) tea.Msg {
+	task, err := system.StartTask(m.description, m.command, script, env)
+	if err != nil {
+		return ExecutionCompleteMsg{
+			Success: false,
+			Output:  fmt.Sprintf("Failed to start task: %v", err),
+			Error:   err,
+		}
+	}
+	debuglog.Debug("task detached", "task", m.description, "id", task.ID, "unit", task.Unit)
+	return taskStartedMsg{task: task}
+}
+
+// appendOutput adds lines to the output, keeping the last maxLines
+func (m *ExecutionModel) appendOutput(lines []string) {
+	m.output = append(m.output, lines...)
+
+	// Trim to max lines
+	if len(m.output) > m.maxLines {
+		m.output = m.output[len(m.output)-m.maxLines:]
+	}
+
+	// Auto-scroll to bottom when output is added
+	if m.autoScroll {
+		m.scrollOffset = len(m.output) - (m.height - 10)
+		if m.scrollOffset < 0 {
+			m.scrollOffset = 0
+		}
+	}
+}
+
+// complete records the end of the task and runs what follows it: the
+// configuration snapshot, notifications, and the provisioning record
+func (m ExecutionModel) complete(success bool, exitCode int, err error) (ExecutionModel, tea.Cmd) {
+	debuglog.Debug("task finished", "task", m.description, "success", success,
+		"duration", m.endTime.Sub(m.startTime).Round(time.Millisecond).String(), "error", err)
+	if success {
+		m.state = ExecutionSuccess
+	} else {
+		m.state = ExecutionFailed
+	}
+	m.exitCode = exitCode
+	// The task may have installed, started, or stopped services
+	system.InvalidateServiceStatus()
+
+	cmds := []tea.Cmd{
+		snapshotConfig(m.description, success),
+		notifyCompletion(m.description, success, m.endTime.Sub(m.startTime), m.output),
+	}
+	if success && m.removes != "" {
+		cmds = append(cmds, forgetProvisioned(m.removes))
+	} else if success {
+		cmds = append(cmds, recordProvisioned(m.command))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// taskProgress adds a detached task's new output and completes the task
+// once it has stopped
+func (m ExecutionModel) taskProgress(msg taskProgressMsg) (ExecutionModel, tea.Cmd) {
+	if msg.err != nil {
+		// The connection may be down; keep trying, the task runs on
+		m.notice = m.theme.WarningStyle.Render(m.theme.Symbols.Warning + " " + msg.err.Error() + " (retrying)")
+		return m, pollTask(m.task.ID, m.taskOffset, 5*taskPollInterval)
+	}
+	if !m.confirmStop && !m.stopping {
+		m.notice = ""
+	}
+	m.taskOffset += int64(len(msg.output))
+	lines := strings.Split(m.taskPartial+string(msg.output), "\n")
+	m.taskPartial = lines[len(lines)-1]
+	m.appendOutput(lines[:len(lines)-1])
+
+	status := msg.status
+	if !status.Done() {
+		return m, pollTask(m.task.ID, m.taskOffset, taskPollInterval)
+	}
+
+	if m.taskPartial != "" {
+		m.appendOutput([]string{m.taskPartial})
+		m.taskPartial = ""
+	}
+	m.endTime = time.Now()
+	if !status.Finished.IsZero() {
+		m.endTime = status.Finished
+	}
+	var err error
+	switch status.State {
+	case system.TaskFailed:
+		err = fmt.Errorf("exit status %d", status.ExitCode)
+		m.appendOutput([]string{"", fmt.Sprintf("Command failed with error: %v", err)})
+	case system.TaskLost:
+		err = fmt.Errorf("task stopped without an exit code")
+		if !m.stopping {
+			m.appendOutput([]string{"", "The task stopped without recording an exit code, e.g. because the server restarted."})
+		}
+	}
+
+	// A task re-attached to after ravact recorded its result is only shown
+	if status.Handled {
+		m.state = ExecutionSuccess
+		if err != nil {
+			m.state = ExecutionFailed
+		}
+		m.exitCode = status.ExitCode
+		return m, nil
+	}
+	m, cmd := m.complete(err == nil, status.ExitCode, err)
+	if m.stopping {
+		m.state = ExecutionCancelled
+	}
+	id := m.task.ID
+	return m, tea.Batch(cmd, func() tea.Msg {
+		if err := system.MarkTaskHandled(id); err != nil {
+			debuglog.Debug("marking task handled failed", "id", id, "error", err)
+		}
+		return nil
+	})
+}
+
 // Update handles messages for execution
 func (m ExecutionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -259,56 +605,79 @@ func (m ExecutionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ExecutionCompleteMsg:
 		m.endTime = time.Now()
-		if msg.Success {
-			m.state = ExecutionSuccess
-		} else {
-			m.state = ExecutionFailed
+		m.appendOutput(strings.Split(msg.Output, "\n"))
+		exitCode := 0
+		if msg.Error != nil {
+			exitCode = 1
 		}
+		return m.complete(msg.Success, exitCode, msg.Error)
 
-		// Add output lines
-		lines := strings.Split(msg.Output, "\n")
-		for _, line := range lines {
-			m.output = append(m.output, line)
-		}
+	case taskStartedMsg:
+		m.task = &msg.task
+		return m, pollTask(msg.task.ID, 0, 0)
 
-		// Trim to max lines
-		if len(m.output) > m.maxLines {
-			m.output = m.output[len(m.output)-m.maxLines:]
-		}
+	case taskProgressMsg:
+		return m.taskProgress(msg)
 
-		// Auto-scroll to bottom when output is added
-		if m.autoScroll {
-			m.scrollOffset = len(m.output) - (m.height - 10)
-			if m.scrollOffset < 0 {
-				m.scrollOffset = 0
-			}
+	case taskStoppedMsg:
+		if msg.err != nil {
+			m.stopping = false
+			m.notice = m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark + " Stopping the task failed: " + msg.err.Error())
 		}
+		return m, nil
 
-		if msg.Error != nil {
-			m.exitCode = 1
-		} else {
-			m.exitCode = 0
+	case provisionRecordedMsg:
+		if msg.err != nil {
+			m.notice = m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark + " " + msg.err.Error())
 		}
+		return m, nil
 
+	case notifyResultMsg:
+		if len(msg.errs) > 0 {
+			m.notice = m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark + " Notification failed: " + msg.errs[0].Error())
+		} else if msg.sent > 0 {
+			m.notice = m.theme.SuccessStyle.Render(fmt.Sprintf("%s Notification sent to %d channel(s)", m.theme.Symbols.CheckMark, msg.sent))
+		}
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.confirmStop && msg.String() != "x" {
+			m.confirmStop = false
+			m.notice = ""
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
-			if m.state == ExecutionRunning {
+			// A detached task keeps running; it is listed under Background Tasks
+			if m.state == ExecutionRunning && m.task == nil {
 				m.state = ExecutionCancelled
 				return m, tea.Quit
 			}
 			return m, tea.Quit
 
 		case "esc", "enter", " ":
-			// Only allow exit if execution is complete
-			if m.state != ExecutionRunning {
+			// Only allow exit if execution is complete, or leave a detached
+			// task running in the background
+			if m.state != ExecutionRunning || (m.task != nil && msg.String() == "esc") {
 				return m, func() tea.Msg {
 					return NavigateMsg{Screen: m.returnScreen}
 				}
 			}
 
+		case "x":
+			if m.state != ExecutionRunning || m.task == nil || m.stopping {
+				break
+			}
+			if !m.confirmStop {
+				m.confirmStop = true
+				m.notice = m.theme.WarningStyle.Render(m.theme.Symbols.Warning + " Stopping may leave the server half configured. Press x again to stop the task.")
+				break
+			}
+			m.confirmStop = false
+			m.stopping = true
+			m.notice = m.theme.InfoStyle.Render("Stopping the task...")
+			task := *m.task
+			return m, func() tea.Msg { return taskStoppedMsg{err: system.StopTask(task)} }
+
 		case "c":
 			// Copy output to clipboard
 			if len(m.output) > 0 {
@@ -478,7 +847,9 @@ func (m ExecutionModel) View() string {
 
 	// Help text
 	var help string
-	if m.state == ExecutionRunning {
+	if m.state == ExecutionRunning && m.task != nil {
+		help = m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Scroll • s: Toggle Command • x: Stop • Esc: Leave Running in Background")
+	} else if m.state == ExecutionRunning {
 		help = m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Scroll • s: Toggle Command • Ctrl+C: Cancel • Please wait...")
 	} else {
 		help = m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Scroll • s: Toggle Command • c: Copy • Enter/Esc: Continue • q: Quit")
@@ -512,6 +883,9 @@ func (m ExecutionModel) View() string {
 	if exitCodeDisplay != "" {
 		sections = append(sections, exitCodeDisplay)
 	}
+	if m.notice != "" {
+		sections = append(sections, m.notice)
+	}
 	if copiedMsg != "" {
 		sections = append(sections, copiedMsg)
 	}
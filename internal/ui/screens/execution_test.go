@@ -0,0 +1,30 @@
+package screens
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iperamuna/ravact/internal/config"
+)
+
+func TestReadSetupScriptOverride(t *testing.T) {
+	orig := AppSettings
+	defer func() { AppSettings = orig }()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "redis.sh"), []byte("echo custom\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	AppSettings = config.Settings{ScriptsDir: dir}
+
+	content, err := readSetupScript("assets/scripts/redis.sh")
+	if err != nil || string(content) != "echo custom\n" {
+		t.Errorf("expected the scripts directory to win, got %q %v", content, err)
+	}
+
+	// Scripts missing from the directory come from the bundle
+	if _, err := readSetupScript("assets/scripts/missing.sh"); err == nil {
+		t.Error("expected an error for a script that is neither overridden nor bundled")
+	}
+}
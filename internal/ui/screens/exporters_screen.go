@@ -0,0 +1,207 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// exportersStateMsg carries the exporters' settings and which units run
+type exportersStateMsg struct {
+	settings   system.ExporterSettings
+	configured bool
+	running    map[string]bool
+}
+
+// ExportersModel installs Prometheus exporters and limits scraping to one
+// source through the firewall
+type ExportersModel struct {
+	theme      *theme.Theme
+	width      int
+	height     int
+	settings   system.ExporterSettings
+	configured bool
+	running    map[string]bool
+	loading    bool
+	form       *huh.Form
+	err        error
+}
+
+// NewExportersModel creates the exporters screen
+func NewExportersModel() ExportersModel {
+	return ExportersModel{theme: theme.DefaultTheme(), loading: true}
+}
+
+// Init reads the last settings and the exporters' states
+func (m ExportersModel) Init() tea.Cmd {
+	return func() tea.Msg {
+		settings, configured := system.ReadExporterSettings()
+		running := make(map[string]bool)
+		for _, e := range system.Exporters {
+			running[e.ID] = system.ExporterRunning(e)
+		}
+		return exportersStateMsg{settings: settings, configured: configured, running: running}
+	}
+}
+
+// buildForm picks the exporters and the scrape source, starting from the
+// last run's settings
+func (m ExportersModel) buildForm() *huh.Form {
+	selected := append([]string(nil), m.settings.Exporters...)
+	source := m.settings.ScrapeSource
+	var options []huh.Option[string]
+	for _, e := range system.Exporters {
+		options = append(options, huh.NewOption(fmt.Sprintf("%s (:%d) - %s", e.Name, e.Port, e.Description), e.ID))
+	}
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Key("exporters").
+				Title("Exporters").
+				Description("Space toggles; exporters left out are stopped and disabled").
+				Options(options...).
+				Validate(func(ids []string) error {
+					if len(ids) == 0 {
+						return fmt.Errorf("choose at least one exporter")
+					}
+					return nil
+				}).
+				Value(&selected),
+
+			huh.NewInput().
+				Key("source").
+				Title("Scrape Source").
+				Description("The Prometheus server's IP or network; 127.0.0.1 keeps the exporters local").
+				Validate(func(s string) error {
+					return system.ExporterSettings{Exporters: []string{"node"}, ScrapeSource: strings.TrimSpace(s)}.Validate()
+				}).
+				Value(&source),
+		).Title("Prometheus Exporters"),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// Update handles messages for the exporters screen
+func (m ExportersModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case exportersStateMsg:
+		m.loading = false
+		m.settings, m.configured, m.running = msg.settings, msg.configured, msg.running
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.form != nil {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				m.form = nil
+				return m, nil
+			}
+			break
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: SetupMenuScreen}
+			}
+		case "enter", "e":
+			if !m.loading {
+				m.err = nil
+				m.form = m.buildForm()
+				return m, m.form.Init()
+			}
+		case "r":
+			m.loading = true
+			return m, m.Init()
+		}
+		return m, nil
+	}
+
+	if m.form == nil {
+		return m, nil
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State == huh.StateCompleted {
+		settings := system.ExporterSettings{
+			ScrapeSource: strings.TrimSpace(m.form.GetString("source")),
+		}
+		if ids, ok := m.form.Get("exporters").([]string); ok {
+			settings.Exporters = ids
+		}
+		m.form = nil
+		if err := settings.Validate(); err != nil {
+			m.err = err
+			return m, nil
+		}
+		description := fmt.Sprintf("Setting up Prometheus exporters (scraped from %s)", settings.ScrapeSource)
+		return m, func() tea.Msg {
+			return ExecutionStartMsg{Command: settings.Command(), Description: description}
+		}
+	}
+	return m, cmd
+}
+
+// View renders the exporters screen
+func (m ExportersModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections := []string{m.theme.Title.Render("Prometheus Exporters"), ""}
+
+	switch {
+	case m.form != nil:
+		sections = append(sections, m.form.View(), "",
+			m.theme.Help.Render("Tab/Shift+Tab: Navigate"+bullet+"Space: Toggle"+bullet+"Enter: Next/Install"+bullet+"Esc: Cancel"))
+
+	case m.loading:
+		sections = append(sections, m.theme.DescriptionStyle.Render("Checking exporters..."))
+
+	default:
+		sections = append(sections, m.theme.Label.Render(fmt.Sprintf("  %-28s %-6s %s", "Exporter", "Port", "Status")))
+		for _, e := range system.Exporters {
+			status := m.theme.DescriptionStyle.Render("not running")
+			if m.running[e.ID] {
+				status = m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " running")
+			}
+			sections = append(sections, fmt.Sprintf("  %-28s %-6d %s", e.Name, e.Port, status))
+		}
+		sections = append(sections, "")
+		switch {
+		case !m.configured:
+			sections = append(sections, m.theme.DescriptionStyle.Render("Not set up yet. Exporters install from their GitHub releases as systemd services."))
+		case m.settings.LocalOnly():
+			sections = append(sections, m.theme.InfoStyle.Render("Listening on 127.0.0.1 only: scrape through an SSH tunnel or a local Prometheus."))
+		default:
+			sections = append(sections, m.theme.InfoStyle.Render("Firewall allows scraping only from "+m.settings.ScrapeSource+"."))
+		}
+		if m.err != nil {
+			sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+		}
+		sections = append(sections, "",
+			m.theme.Help.Render("Enter/e: Configure"+bullet+"r: Refresh"+bullet+"Esc: Back"+bullet+"q: Quit"))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
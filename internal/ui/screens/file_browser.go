@@ -117,7 +117,7 @@ func NewFileBrowserModel() FileBrowserModel {
 		selectedItems:   make(map[string]bool),
 		history:         []string{startPath},
 		historyIndex:    0,
-		showHidden:      false,
+		showHidden:      AppSettings.ShowHiddenFiles,
 		sortBy:          "name",
 		maxVisibleItems: 20,
 	}
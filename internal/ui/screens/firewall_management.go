@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/theme"
@@ -26,6 +27,16 @@ type FirewallManagementModel struct {
 	inputField      string
 	inputValue      string
 	inputPrompt     string
+
+	// Rule editor and profiles
+	mode          string // "", "rules", "add_rule", "profiles", "preview", "confirm"
+	ruleCursor    int
+	ruleForm      *huh.Form
+	sshPort       string
+	profiles      []system.FirewallProfile
+	profileCursor int
+	plan          system.FirewallPlan
+	confirm       Confirmation
 }
 
 // NewFirewallManagementModel creates a new firewall management model
@@ -36,6 +47,8 @@ func NewFirewallManagementModel() FirewallManagementModel {
 
 	actions := []string{
 		"View Current Rules",
+		"Add Rule",
+		"Apply Profile",
 		"Allow Port",
 		"Deny Port",
 		"Delete Rule",
@@ -45,6 +58,8 @@ func NewFirewallManagementModel() FirewallManagementModel {
 		"← Back to Configurations",
 	}
 
+	sshPort := system.SSHPort()
+
 	return FirewallManagementModel{
 		theme:           theme.DefaultTheme(),
 		firewallManager: firewallManager,
@@ -52,6 +67,8 @@ func NewFirewallManagementModel() FirewallManagementModel {
 		actions:         actions,
 		rules:           rules,
 		status:          status,
+		sshPort:         sshPort,
+		profiles:        system.FirewallProfiles(sshPort),
 	}
 }
 
@@ -67,8 +84,25 @@ func (m FirewallManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, nil
+	}
 
+	if m.mode == "add_rule" {
+		return m.updateRuleForm(msg)
+	}
+
+	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		switch m.mode {
+		case "rules":
+			return m.updateRules(msg)
+		case "profiles":
+			return m.updateProfiles(msg)
+		case "preview":
+			return m.updatePreview(msg)
+		case "confirm":
+			return m.updateRuleConfirm(msg)
+		}
+
 		// Handle input mode
 		if m.inputMode {
 			switch msg.String() {
@@ -189,13 +223,16 @@ func (m FirewallManagementModel) executeAction() (FirewallManagementModel, tea.C
 
 	switch actionName {
 	case "View Current Rules":
-		// Refresh rules
-		rules, err := m.firewallManager.GetRules()
-		if err != nil {
-			m.err = err
-		} else {
-			m.rules = rules
-			m.success = fmt.Sprintf("✓ Found %d rules", len(rules))
+		m.openRules()
+
+	case "Add Rule":
+		model, cmd := m.openRuleForm()
+		return model.(FirewallManagementModel), cmd
+
+	case "Apply Profile":
+		if m.requireUFW() {
+			m.profileCursor = 0
+			m.mode = "profiles"
 		}
 
 	case "Allow Port":
@@ -254,6 +291,9 @@ func (m FirewallManagementModel) View() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
+	if m.mode != "" {
+		return m.rulesEditorView()
+	}
 
 	// Header
 	firewallType := string(m.firewallManager.GetFirewallType())
@@ -285,8 +325,8 @@ func (m FirewallManagementModel) View() string {
 		}
 		for i := 0; i < maxRules; i++ {
 			rule := m.rules[i]
-			ruleText := fmt.Sprintf("  • %s/%s %s from %s", rule.Port, rule.Protocol, strings.ToUpper(rule.Action), rule.From)
-			if rule.Action == "allow" {
+			ruleText := "  • " + system.SpecFromRule(rule).String()
+			if rule.Action == "allow" || rule.Action == "limit" {
 				rulesInfo = append(rulesInfo, m.theme.SuccessStyle.Render(ruleText))
 			} else {
 				rulesInfo = append(rulesInfo, m.theme.ErrorStyle.Render(ruleText))
@@ -0,0 +1,361 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+)
+
+// requireUFW reports an error when the rule editor is not available
+func (m *FirewallManagementModel) requireUFW() bool {
+	if m.firewallManager.GetFirewallType() != system.FirewallUFW {
+		m.err = fmt.Errorf("the rule editor and profiles require UFW")
+		return false
+	}
+	return true
+}
+
+// openRules switches to the numbered rule list
+func (m *FirewallManagementModel) openRules() {
+	rules, err := m.firewallManager.GetRules()
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.rules = rules
+	if m.ruleCursor >= len(m.rules) {
+		m.ruleCursor = 0
+	}
+	m.mode = "rules"
+}
+
+// updateRules handles keys on the numbered rule list
+func (m FirewallManagementModel) updateRules(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.mode = ""
+	case "up", "k":
+		if m.ruleCursor > 0 {
+			m.ruleCursor--
+		}
+	case "down", "j":
+		if m.ruleCursor < len(m.rules)-1 {
+			m.ruleCursor++
+		}
+	case "a":
+		return m.openRuleForm()
+	case "d", "delete":
+		if len(m.rules) == 0 || !m.requireUFW() {
+			return m, nil
+		}
+		rule := m.rules[m.ruleCursor]
+		spec := system.SpecFromRule(rule)
+		message := fmt.Sprintf("Delete rule [%d] %s?", rule.Number, spec.String())
+		severity := ConfirmNormal
+		if rule.Port == m.sshPort {
+			message += "\n\nThis rule allows SSH. Removing it may lock you out of this server."
+			severity = ConfirmWarning
+		}
+		m.confirm = NewConfirmation("delete_rule", "Delete Firewall Rule", message, severity)
+		m.mode = "confirm"
+	}
+	return m, nil
+}
+
+// openRuleForm shows the add rule form
+func (m FirewallManagementModel) openRuleForm() (tea.Model, tea.Cmd) {
+	if !m.requireUFW() {
+		return m, nil
+	}
+	m.ruleForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("action").
+				Title("Action").
+				Options(
+					huh.NewOption("Allow", "allow"),
+					huh.NewOption("Deny (drop silently)", "deny"),
+					huh.NewOption("Reject (refuse with an error)", "reject"),
+					huh.NewOption("Limit (allow, rate-limit repeated connections)", "limit"),
+				),
+			huh.NewInput().
+				Key("port").
+				Title("Port").
+				Description("A port or a range such as 8000:8100").
+				Placeholder("8080").
+				Validate(func(s string) error {
+					return system.UFWRuleSpec{Action: "allow", Port: strings.TrimSpace(s), Protocol: "tcp"}.Validate()
+				}),
+			huh.NewSelect[string]().
+				Key("protocol").
+				Title("Protocol").
+				Options(
+					huh.NewOption("TCP", "tcp"),
+					huh.NewOption("UDP", "udp"),
+					huh.NewOption("Both", "any"),
+				),
+			huh.NewInput().
+				Key("from").
+				Title("Source").
+				Description("IP address or CIDR allowed to connect (empty for anywhere)").
+				Placeholder("203.0.113.0/24").
+				Validate(func(s string) error {
+					return system.UFWRuleSpec{Action: "allow", Port: "80", From: strings.TrimSpace(s)}.Validate()
+				}),
+			huh.NewInput().
+				Key("comment").
+				Title("Comment").
+				Placeholder("Office VPN"),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+	m.mode = "add_rule"
+	return m, m.ruleForm.Init()
+}
+
+// updateRuleForm passes messages to the add rule form
+func (m FirewallManagementModel) updateRuleForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.openRules()
+			return m, nil
+		}
+	}
+
+	form, cmd := m.ruleForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.ruleForm = f
+	}
+
+	switch m.ruleForm.State {
+	case huh.StateCompleted:
+		from := strings.TrimSpace(m.ruleForm.GetString("from"))
+		if from == "" {
+			from = "any"
+		}
+		spec := system.UFWRuleSpec{
+			Action:   m.ruleForm.GetString("action"),
+			Port:     strings.TrimSpace(m.ruleForm.GetString("port")),
+			Protocol: m.ruleForm.GetString("protocol"),
+			From:     from,
+			Comment:  strings.TrimSpace(m.ruleForm.GetString("comment")),
+		}
+		m.err = nil
+		m.success = ""
+		if err := m.firewallManager.AddRule(spec); err != nil {
+			m.err = err
+		} else {
+			m.success = fmt.Sprintf("%s Added %s", m.theme.Symbols.CheckMark, spec.String())
+		}
+		m.openRules()
+		return m, nil
+	case huh.StateAborted:
+		m.openRules()
+		return m, nil
+	}
+	return m, cmd
+}
+
+// updateProfiles handles the profile picker
+func (m FirewallManagementModel) updateProfiles(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.mode = ""
+	case "up", "k":
+		if m.profileCursor > 0 {
+			m.profileCursor--
+		}
+	case "down", "j":
+		if m.profileCursor < len(m.profiles)-1 {
+			m.profileCursor++
+		}
+	case "enter", " ":
+		rules, err := m.firewallManager.GetRules()
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.rules = rules
+		m.plan = system.PlanProfile(rules, m.profiles[m.profileCursor], m.sshPort)
+		m.mode = "preview"
+	}
+	return m, nil
+}
+
+// updatePreview handles the dry-run preview of a profile
+func (m FirewallManagementModel) updatePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.mode = "profiles"
+	case "enter", "y":
+		m.confirm = NewConfirmation("apply_profile", "Apply Firewall Profile",
+			fmt.Sprintf("Apply the %s profile?\n%d rule(s) will be added and %d removed, and the firewall enabled.",
+				m.plan.Profile.Name, len(m.plan.Add), len(m.plan.Remove)), ConfirmWarning)
+		m.mode = "confirm"
+	}
+	return m, nil
+}
+
+// updateRuleConfirm handles rule deletion and profile confirmations
+func (m FirewallManagementModel) updateRuleConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var result ConfirmResult
+	m.confirm, result = m.confirm.Update(msg)
+
+	switch result {
+	case ConfirmAccepted:
+		switch m.confirm.Action {
+		case "delete_rule":
+			rule := m.rules[m.ruleCursor]
+			m.err = nil
+			m.success = ""
+			if err := m.firewallManager.DeleteRuleNumber(rule.Number); err != nil {
+				m.err = err
+			} else {
+				m.success = fmt.Sprintf("%s Deleted rule [%d]", m.theme.Symbols.CheckMark, rule.Number)
+			}
+			m.openRules()
+			return m, nil
+
+		case "apply_profile":
+			m.mode = ""
+			plan := m.plan
+			return m, func() tea.Msg {
+				return ExecutionStartMsg{
+					Command:     plan.Script(),
+					Description: "Applying firewall profile: " + plan.Profile.Name,
+				}
+			}
+		}
+
+	case ConfirmCancelled:
+		if m.confirm.Action == "apply_profile" {
+			m.mode = "preview"
+		} else {
+			m.mode = "rules"
+		}
+	}
+	return m, nil
+}
+
+// ruleLine renders a numbered rule
+func (m FirewallManagementModel) ruleLine(rule system.FirewallRule) string {
+	text := system.SpecFromRule(rule).String()
+	if rule.V6 {
+		text += " (v6)"
+	}
+	if rule.Direction == "OUT" {
+		text += " [out]"
+	}
+	line := fmt.Sprintf("[%2d] %s", rule.Number, text)
+	if rule.Action == "allow" || rule.Action == "limit" {
+		return m.theme.SuccessStyle.Render(line)
+	}
+	return m.theme.ErrorStyle.Render(line)
+}
+
+// rulesEditorView renders the rule list, add form, profile picker and preview
+func (m FirewallManagementModel) rulesEditorView() string {
+	if m.mode == "confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	var sections []string
+	var help string
+
+	switch m.mode {
+	case "rules":
+		sections = append(sections, m.theme.Title.Render("Firewall Rules"), "")
+		if len(m.rules) == 0 {
+			sections = append(sections, m.theme.DescriptionStyle.Render("No rules configured. Press a to add one."))
+		}
+		height := m.height - 14
+		if height < 5 {
+			height = 5
+		}
+		start := 0
+		if m.ruleCursor >= height {
+			start = m.ruleCursor - height + 1
+		}
+		for i := start; i < len(m.rules) && i < start+height; i++ {
+			prefix := "  "
+			if i == m.ruleCursor {
+				prefix = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+			}
+			sections = append(sections, prefix+m.ruleLine(m.rules[i]))
+		}
+		help = m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate" + bullet + "a: Add rule" + bullet + "d: Delete rule" + bullet + "Esc: Back"
+
+	case "add_rule":
+		sections = append(sections, m.theme.Title.Render("Add Firewall Rule"), "", m.ruleForm.View())
+		help = "Tab: Next field" + bullet + "Enter: Submit" + bullet + "Esc: Cancel"
+
+	case "profiles":
+		sections = append(sections,
+			m.theme.Title.Render("Firewall Profiles"),
+			m.theme.DescriptionStyle.Render(fmt.Sprintf("SSH (port %s) stays reachable with every profile.", m.sshPort)),
+			"",
+		)
+		for i, p := range m.profiles {
+			if i == m.profileCursor {
+				sections = append(sections, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+m.theme.SelectedItem.Render(p.Name))
+			} else {
+				sections = append(sections, "  "+m.theme.MenuItem.Render(p.Name))
+			}
+			sections = append(sections, "    "+m.theme.DescriptionStyle.Render(p.Description))
+		}
+		help = m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate" + bullet + "Enter: Preview" + bullet + "Esc: Back"
+
+	case "preview":
+		sections = append(sections,
+			m.theme.Title.Render("Dry Run: "+m.plan.Profile.Name),
+			m.theme.DescriptionStyle.Render("Nothing has been changed yet. Resulting incoming rules:"),
+			"",
+		)
+		added := map[string]bool{}
+		for _, spec := range m.plan.Add {
+			added[spec.String()] = true
+		}
+		for _, spec := range m.plan.Result() {
+			if added[spec.String()] {
+				sections = append(sections, m.theme.SuccessStyle.Render("  + "+spec.String()))
+			} else {
+				sections = append(sections, m.theme.MenuItem.Render("    "+spec.String()))
+			}
+		}
+		for _, spec := range m.plan.Remove {
+			sections = append(sections, m.theme.ErrorStyle.Render("  - "+spec.String()))
+		}
+		sections = append(sections, "", m.theme.Subtitle.Render("Commands:"))
+		for _, command := range m.plan.Commands() {
+			sections = append(sections, m.theme.DescriptionStyle.Render("  "+command))
+		}
+		help = "Enter: Apply" + bullet + "Esc: Back"
+	}
+
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
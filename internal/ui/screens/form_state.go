@@ -0,0 +1,151 @@
+package screens
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/huh"
+	"github.com/iperamuna/ravact/internal/debuglog"
+)
+
+// FormState holds the in-progress values of a form, keyed by huh field key
+type FormState map[string]string
+
+// FormStateDir is where form states are persisted between sessions.
+// When empty, form states are only kept in memory for the current session.
+var FormStateDir string
+
+var (
+	formStatesMu sync.Mutex
+	formStates   = map[string]FormState{}
+)
+
+// SaveFormState stores the in-progress values for a form
+func SaveFormState(key string, state FormState) {
+	if len(state) == 0 {
+		return
+	}
+
+	formStatesMu.Lock()
+	formStates[key] = state
+	formStatesMu.Unlock()
+
+	if FormStateDir == "" {
+		return
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(FormStateDir, 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(formStatePath(key), data, 0600)
+}
+
+// LoadFormState returns the saved values for a form, checking the current
+// session first and then the on-disk copy
+func LoadFormState(key string) (FormState, bool) {
+	formStatesMu.Lock()
+	state, ok := formStates[key]
+	formStatesMu.Unlock()
+	if ok {
+		return state, true
+	}
+
+	if FormStateDir == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(formStatePath(key))
+	if err != nil {
+		return nil, false
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		debuglog.ParseFailure("saved form", err, "form", key)
+		return nil, false
+	}
+	if len(state) == 0 {
+		return nil, false
+	}
+	return state, true
+}
+
+// ClearFormState discards any saved values for a form
+func ClearFormState(key string) {
+	formStatesMu.Lock()
+	delete(formStates, key)
+	formStatesMu.Unlock()
+
+	if FormStateDir != "" {
+		_ = os.Remove(formStatePath(key))
+	}
+}
+
+// formStatePath returns the on-disk location for a form state key
+func formStatePath(key string) string {
+	name := strings.NewReplacer("/", "_", ":", "_", " ", "_").Replace(key)
+	return filepath.Join(FormStateDir, name+".json")
+}
+
+// CaptureFormState collects the values entered so far in a huh form.
+// Fields the user has moved past are recorded in the form results;
+// the focused field is read directly since it has not been committed yet.
+func CaptureFormState(form *huh.Form, keys []string) FormState {
+	state := FormState{}
+	if form == nil {
+		return state
+	}
+
+	for _, key := range keys {
+		if v := form.Get(key); v != nil {
+			state[key] = fmt.Sprint(v)
+		} else {
+			debuglog.Debug("form field has no value", "field", key)
+		}
+	}
+
+	if field := form.GetFocusedField(); field != nil && field.GetKey() != "" {
+		if v := field.GetValue(); v != nil {
+			state[field.GetKey()] = fmt.Sprint(v)
+		}
+	}
+
+	return state
+}
+
+// Apply copies saved values into the bound form fields.
+// Bindings map field keys to *string or *bool targets.
+func (s FormState) Apply(bindings map[string]interface{}) {
+	for key, target := range bindings {
+		value, ok := s[key]
+		if !ok {
+			continue
+		}
+		switch p := target.(type) {
+		case *string:
+			*p = value
+		case *bool:
+			*p = value == "true"
+		default:
+			debuglog.Debug("form value not bound", "field", key, "type", fmt.Sprintf("%T", target))
+		}
+	}
+	for key := range s {
+		if _, ok := bindings[key]; !ok {
+			debuglog.Debug("saved form value has no field", "field", key)
+		}
+	}
+}
+
+// formBindingKeys returns the field keys of a bindings map
+func formBindingKeys(bindings map[string]interface{}) []string {
+	keys := make([]string, 0, len(bindings))
+	for key := range bindings {
+		keys = append(keys, key)
+	}
+	return keys
+}
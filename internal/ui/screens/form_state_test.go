@@ -0,0 +1,59 @@
+package screens
+
+import (
+	"testing"
+)
+
+func TestFormStateSaveLoadClear(t *testing.T) {
+	oldDir := FormStateDir
+	FormStateDir = t.TempDir()
+	defer func() { FormStateDir = oldDir }()
+
+	SaveFormState("test:form", FormState{"siteName": "demo", "ssl": "letsencrypt"})
+
+	state, ok := LoadFormState("test:form")
+	if !ok {
+		t.Fatal("expected saved form state to be found")
+	}
+	if state["siteName"] != "demo" {
+		t.Errorf("expected siteName 'demo', got '%s'", state["siteName"])
+	}
+
+	// Drop the in-memory copy to make sure the on-disk copy is used
+	formStatesMu.Lock()
+	delete(formStates, "test:form")
+	formStatesMu.Unlock()
+
+	state, ok = LoadFormState("test:form")
+	if !ok || state["ssl"] != "letsencrypt" {
+		t.Errorf("expected form state to be restored from disk, got %v", state)
+	}
+
+	ClearFormState("test:form")
+	if _, ok := LoadFormState("test:form"); ok {
+		t.Error("expected form state to be cleared")
+	}
+}
+
+func TestFormStateApply(t *testing.T) {
+	var name string
+	var enabled bool
+	untouched := "default"
+
+	state := FormState{"name": "mysite", "enabled": "true"}
+	state.Apply(map[string]interface{}{
+		"name":      &name,
+		"enabled":   &enabled,
+		"untouched": &untouched,
+	})
+
+	if name != "mysite" {
+		t.Errorf("expected name 'mysite', got '%s'", name)
+	}
+	if !enabled {
+		t.Error("expected enabled to be true")
+	}
+	if untouched != "default" {
+		t.Errorf("expected untouched field to keep its value, got '%s'", untouched)
+	}
+}
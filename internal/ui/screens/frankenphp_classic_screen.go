@@ -23,7 +23,7 @@ type FrankenPHPClassicModel struct {
 	width         int
 	height        int
 	cursor        int
-	mode          string // "install_options", "site_setup", "confirm", "review_files", "custom_url_input", "composer_setup"
+	mode          string // "install_options", "resume_prompt", "site_setup", "confirm", "review_files", "custom_url_input", "composer_setup"
 	binaryPath    string
 	binaryVersion string
 	binaryFound   bool
@@ -48,6 +48,8 @@ type FrankenPHPClassicModel struct {
 	formNumThreads  string
 	formMaxThreads  string
 	formMaxWaitTime string
+	formRuntime     string // "classic" or "worker" (Laravel Octane)
+	formNumWorkers  string
 
 	// PHP INI fields
 	formPHPMemoryLimit              string
@@ -73,6 +75,12 @@ type FrankenPHPClassicModel struct {
 	generatedFiles []GeneratedFile
 	fileCursor     int
 
+	// Resume previous input (saved when the form is left with Esc)
+	resumeState FormState
+
+	// Wizard progress
+	steps StepIndicator
+
 	// UI state
 	detector *system.Detector
 	err      error
@@ -84,6 +92,27 @@ type GeneratedFile struct {
 	Path    string
 	Content string
 	Name    string
+	// Stub and Params render Content again for drift detection; empty for
+	// files that are rewritten after deploy
+	Stub   string
+	Params map[string]string
+}
+
+// recordGeneratedFiles records how a deploy's files were rendered so drift
+// detection can regenerate them and spot manual edits
+func recordGeneratedFiles(owner string, files []GeneratedFile) {
+	for _, f := range files {
+		if f.Stub == "" {
+			continue
+		}
+		_ = system.RecordGeneratedConfig(system.GeneratedConfig{
+			Path:      f.Path,
+			Owner:     owner,
+			Generator: system.GeneratorStub,
+			Stub:      f.Stub,
+			Params:    f.Params,
+		}, f.Content)
+	}
 }
 
 // ComposerSetupOption represents a composer setup option
@@ -109,7 +138,7 @@ func NewFrankenPHPClassicModel() FrankenPHPClassicModel {
 func NewFrankenPHPClassicModelWithSite(site system.NginxSite) FrankenPHPClassicModel {
 	m := NewFrankenPHPClassicModelWithDir(site.RootDir)
 	m.formSiteKey = site.Name
-	m.formDomains = site.Domain
+	m.formDomains = strings.Join(siteDomains(site), " ")
 	m.formSiteRoot = site.RootDir
 	// We skip the installation step if binary is already found
 	if m.binaryPath != "" {
@@ -199,11 +228,12 @@ func NewFrankenPHPClassicModelWithDir(currentDir string) FrankenPHPClassicModel
 		formDocroot:     "", // Default empty
 		formConnType:    "socket",
 		formUser:        "www-data",
-		formGroup:       "www-data",
+		formGroup:       system.WebGroup,
 		formPort:        "8000",
 		formNumThreads:  strconv.Itoa(runtime.NumCPU() * 2),
 		formMaxThreads:  "auto",
 		formMaxWaitTime: "15",
+		formRuntime:     frankenphpClassic,
 
 		// PHP INI defaults
 		formPHPMemoryLimit:              "256M",
@@ -223,22 +253,91 @@ func NewFrankenPHPClassicModelWithDir(currentDir string) FrankenPHPClassicModel
 		detector:                        system.NewDetector(),
 	}
 
+	// Wizard steps (installation is only a step when the binary is missing)
+	var steps []WizardStep
+	if !found {
+		steps = append(steps, WizardStep{ID: "install", Label: "Install"})
+	}
+	steps = append(steps,
+		WizardStep{ID: "site_setup", Label: "Site Setup"},
+		WizardStep{ID: "confirm", Label: "Confirm"},
+		WizardStep{ID: "review", Label: "Review"},
+		WizardStep{ID: "deploy", Label: "Deploy"},
+		WizardStep{ID: "composer", Label: "Composer"},
+	)
+	m.steps = NewStepIndicator(steps...)
+	m.steps.SetCurrent(frankenphpStepForMode(m.mode))
+
 	// Default docroot to 'public' if it exists
 	publicPath := filepath.Join(m.formSiteRoot, "public")
 	if _, err := exec.Command("test", "-d", publicPath).Output(); err == nil {
 		m.formDocroot = "public"
 	}
 
+	// Offer to resume input left unfinished for this directory
+	if state, ok := LoadFormState(m.formStateKey()); ok && m.mode == "site_setup" {
+		m.resumeState = state
+		m.mode = "resume_prompt"
+	}
+
 	// Build the huh form for site setup
 	m.form = m.buildSiteSetupForm()
 
 	return m
 }
 
+// formStateKey identifies this directory's site setup form in the form state store
+func (m FrankenPHPClassicModel) formStateKey() string {
+	return "frankenphp_classic:" + m.currentDir
+}
+
+// formBindings maps site setup form keys to the model fields they populate
+func (m *FrankenPHPClassicModel) formBindings() map[string]interface{} {
+	return map[string]interface{}{
+		"siteRoot":        &m.formSiteRoot,
+		"siteKey":         &m.formSiteKey,
+		"docroot":         &m.formDocroot,
+		"domains":         &m.formDomains,
+		"connType":        &m.formConnType,
+		"port":            &m.formPort,
+		"user":            &m.formUser,
+		"group":           &m.formGroup,
+		"numThreads":      &m.formNumThreads,
+		"maxThreads":      &m.formMaxThreads,
+		"maxWaitTime":     &m.formMaxWaitTime,
+		"runtime":         &m.formRuntime,
+		"numWorkers":      &m.formNumWorkers,
+		"memoryLimit":     &m.formPHPMemoryLimit,
+		"maxExecTime":     &m.formPHPMaxExecutionTime,
+		"maxUploadSize":   &m.formPHPMaxUploadSize,
+		"opcacheEnable":   &m.formPHPOpcacheEnable,
+		"opcacheCli":      &m.formPHPOpcacheEnableCli,
+		"opcacheMemory":   &m.formPHPOpcacheMemoryConsumption,
+		"opcacheStrings":  &m.formPHPOpcacheInternedStrings,
+		"opcacheMaxFiles": &m.formPHPOpcacheMaxFiles,
+		"opcacheValidate": &m.formPHPOpcacheValidate,
+		"opcacheFreq":     &m.formPHPOpcacheRevalidateFreq,
+		"jit":             &m.formPHPOpcacheJit,
+		"jitBuffer":       &m.formPHPOpcacheJitBufferSize,
+		"realpathSize":    &m.formPHPRealpathCacheSize,
+		"realpathTtl":     &m.formPHPRealpathCacheTtl,
+	}
+}
+
 // buildSiteSetupForm creates the huh form for site configuration
 func (m FrankenPHPClassicModel) buildSiteSetupForm() *huh.Form {
 	return huh.NewForm(
 		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("runtime").
+				Title("Runtime Mode").
+				Description("Worker mode keeps Laravel booted between requests via Octane").
+				Options(
+					huh.NewOption("Classic (any PHP app)", frankenphpClassic),
+					huh.NewOption("Worker (Laravel Octane)", frankenphpWorker),
+				).
+				Value(&m.formRuntime),
+
 			huh.NewInput().
 				Key("siteRoot").
 				Title("Site Root").
@@ -281,8 +380,15 @@ func (m FrankenPHPClassicModel) buildSiteSetupForm() *huh.Form {
 			huh.NewInput().
 				Key("domains").
 				Title("Domain Names").
-				Description("Space-separated domain names for Nginx proxy").
-				Placeholder("mysite.com www.mysite.com").
+				Description("Space-separated domain names for Nginx proxy; www is added to apex domains").
+				Placeholder("mysite.com api.mysite.com").
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return nil // Filled from the site key
+					}
+					_, err := system.ParseDomains(s)
+					return err
+				}).
 				Value(&m.formDomains),
 
 			huh.NewSelect[string]().
@@ -379,6 +485,13 @@ func (m FrankenPHPClassicModel) buildSiteSetupForm() *huh.Form {
 					return nil
 				}).
 				Value(&m.formMaxWaitTime),
+
+			huh.NewInput().
+				Key("numWorkers").
+				Title("Number of Workers").
+				Description("Worker mode only: Octane workers kept booted, lower than Number of Threads (empty: 2 × CPUs)").
+				Validate(validateNumWorkers).
+				Value(&m.formNumWorkers),
 		).Title("Performance Tuning"),
 
 		huh.NewGroup(
@@ -559,8 +672,71 @@ func (m FrankenPHPClassicModel) Init() tea.Cmd {
 	return nil
 }
 
+// frankenphpStepForMode maps a screen mode to its wizard step
+func frankenphpStepForMode(mode string) string {
+	switch mode {
+	case "install_options", "custom_url_input":
+		return "install"
+	case "resume_prompt", "site_setup":
+		return "site_setup"
+	case "confirm":
+		return "confirm"
+	case "review_files", "view_file":
+		return "review"
+	case "confirm_deploy":
+		return "deploy"
+	case "composer_setup":
+		return "composer"
+	}
+	return ""
+}
+
+// jumpToStep returns to a completed wizard step
+func (m FrankenPHPClassicModel) jumpToStep(step string) (FrankenPHPClassicModel, tea.Cmd) {
+	m.err = nil
+	switch step {
+	case "install":
+		m.mode = "install_options"
+		m.cursor = 0
+	case "site_setup":
+		m.mode = "site_setup"
+		m.form = m.buildSiteSetupForm()
+		return m, m.form.Init()
+	case "confirm":
+		m.mode = "confirm"
+		m.cursor = 0
+	case "review":
+		m.mode = "review_files"
+	case "deploy":
+		m.mode = "confirm_deploy"
+	}
+	return m, nil
+}
+
 // Update handles messages for FrankenPHP Classic Mode
 func (m FrankenPHPClassicModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Number keys jump back to completed steps, except where they are typed input
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.message == "" {
+		switch m.mode {
+		case "confirm", "review_files", "view_file", "confirm_deploy", "composer_setup":
+			if step, ok := m.steps.JumpTarget(keyMsg.String()); ok {
+				m, cmd := m.jumpToStep(step)
+				m.steps.SetCurrent(frankenphpStepForMode(m.mode))
+				return m, cmd
+			}
+		}
+	}
+
+	model, cmd := m.update(msg)
+	if updated, ok := model.(FrankenPHPClassicModel); ok {
+		updated.steps.SetCurrent(frankenphpStepForMode(updated.mode))
+		return updated, cmd
+	}
+	return model, cmd
+}
+
+// update handles messages for the current mode
+func (m FrankenPHPClassicModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -587,6 +763,24 @@ func (m FrankenPHPClassicModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Handle resume prompt mode
+		if m.mode == "resume_prompt" {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "y", "enter":
+				m.resumeState.Apply(m.formBindings())
+			case "n", "esc":
+				ClearFormState(m.formStateKey())
+			default:
+				return m, nil
+			}
+			m.resumeState = nil
+			m.mode = "site_setup"
+			m.form = m.buildSiteSetupForm()
+			return m, m.form.Init()
+		}
+
 		// Handle custom URL input mode
 		if m.mode == "custom_url_input" {
 			switch msg.String() {
@@ -778,6 +972,7 @@ func (m FrankenPHPClassicModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			case "esc":
 				if m.form.State == huh.StateNormal {
+					SaveFormState(m.formStateKey(), CaptureFormState(m.form, formBindingKeys(m.formBindings())))
 					return m, func() tea.Msg {
 						return NavigateMsg{Screen: SiteCommandsScreen}
 					}
@@ -830,6 +1025,10 @@ func (m FrankenPHPClassicModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if v := m.form.GetString("group"); v != "" {
 				m.formGroup = v
 			}
+			if v := m.form.GetString("runtime"); v != "" {
+				m.formRuntime = v
+			}
+			m.formNumWorkers = m.form.GetString("numWorkers")
 			// Auto-fill empty fields
 			m = m.autoFillFields()
 			// Go to confirmation
@@ -860,6 +1059,11 @@ func (m FrankenPHPClassicModel) autoFillFields() FrankenPHPClassicModel {
 		m.formDomains = m.formSiteKey + ".test"
 	}
 
+	// Normalize domains to their ASCII form with apex and www both present
+	if domains, err := system.ParseDomains(m.formDomains); err == nil {
+		m.formDomains = strings.Join(system.ExpandDomains(domains), " ")
+	}
+
 	// Default connection type
 	if m.formConnType == "" {
 		m.formConnType = "socket"
@@ -875,11 +1079,31 @@ func (m FrankenPHPClassicModel) autoFillFields() FrankenPHPClassicModel {
 		m.formUser = "www-data"
 	}
 	if m.formGroup == "" {
-		m.formGroup = "www-data"
+		m.formGroup = system.WebGroup
 	}
 	return m
 }
 
+// isWorker reports whether the site runs in worker mode
+func (m FrankenPHPClassicModel) isWorker() bool {
+	return m.formRuntime == frankenphpWorker
+}
+
+// title names the flow after the selected runtime mode
+func (m FrankenPHPClassicModel) title() string {
+	if m.isWorker() {
+		return "FrankenPHP Worker Mode (Laravel Octane)"
+	}
+	return "FrankenPHP Classic Mode"
+}
+
+// WithWorkerMode preselects worker mode (Laravel Octane) in the setup form
+func (m FrankenPHPClassicModel) WithWorkerMode() FrankenPHPClassicModel {
+	m.formRuntime = frankenphpWorker
+	m.form = m.buildSiteSetupForm()
+	return m
+}
+
 // getFullDocroot returns the full document root path
 func (m FrankenPHPClassicModel) getFullDocroot() string {
 	if m.formDocroot == "" {
@@ -924,8 +1148,7 @@ func (m FrankenPHPClassicModel) executeInstallOption() (tea.Model, tea.Cmd) {
 	option := m.installOptions[m.cursor]
 	switch option.ID {
 	case "download_official":
-		downloadCmd := `#!/bin/bash
-set -e
+		downloadCmd := "#!/bin/bash\nset -e\n" + system.DownloadHelpers + `
 echo "=== FrankenPHP Download ==="
 echo ""
 
@@ -952,9 +1175,9 @@ URL="https://github.com/dunglas/frankenphp/releases/latest/download/frankenphp-l
 echo "Download URL: $URL"
 echo ""
 
-# Download with progress
+# Download and check against the release's published SHA-256
 echo "Downloading FrankenPHP binary..."
-curl --fail --location --progress-bar --output /tmp/frankenphp "$URL"
+verified_download "$URL" /tmp/frankenphp dunglas/frankenphp
 
 # Check if download was successful
 if [ ! -f /tmp/frankenphp ] || [ ! -s /tmp/frankenphp ]; then
@@ -1030,18 +1253,21 @@ Press any key to continue...`
 
 // executeCustomURLDownload downloads FrankenPHP from a custom URL
 func (m FrankenPHPClassicModel) executeCustomURLDownload() (FrankenPHPClassicModel, tea.Cmd) {
-	url := strings.TrimSpace(m.customURL)
+	// An optional SHA-256 may follow the URL for mirrors without a .sha256 file
+	url, checksum := strings.TrimSpace(m.customURL), ""
+	if fields := strings.Fields(url); len(fields) == 2 {
+		url, checksum = fields[0], fields[1]
+	}
 
-	downloadCmd := fmt.Sprintf(`#!/bin/bash
-set -e
+	downloadCmd := "#!/bin/bash\nset -e\n" + system.DownloadHelpers + fmt.Sprintf(`
 echo "=== FrankenPHP Download from Custom URL ==="
 echo ""
 echo "URL: %s"
 echo ""
 
-# Download with progress
+# Download and check against the given or published SHA-256
 echo "Downloading FrankenPHP binary..."
-curl --fail --location --progress-bar --output /tmp/frankenphp "%s"
+verified_download %s /tmp/frankenphp %s %s
 
 # Check if download was successful
 if [ ! -f /tmp/frankenphp ] || [ ! -s /tmp/frankenphp ]; then
@@ -1080,7 +1306,7 @@ echo ""
 echo "Location: /usr/local/bin/frankenphp"
 echo ""
 frankenphp version || echo "Note: Run 'frankenphp version' to verify"
-`, url, url)
+`, url, system.ShellQuote(url), system.ShellQuote(system.GitHubReleaseRepo(url)), system.ShellQuote(checksum))
 
 	return m, func() tea.Msg {
 		return ExecutionStartMsg{
@@ -1150,6 +1376,11 @@ func (m FrankenPHPClassicModel) buildCreateSiteCommand() string {
 		script.WriteString("\nEOF\n")
 	}
 
+	// Octane must be installed before the worker starts
+	if m.isWorker() {
+		script.WriteString(octaneInstallScript(siteRoot, m.getFullDocroot(), systemUser))
+	}
+
 	// Fix permissions and enable services
 	script.WriteString("\n# Fix permissions and enable services\n")
 	caddyfilePath := fmt.Sprintf("/etc/frankenphp/%s/Caddyfile", siteKey)
@@ -1312,7 +1543,13 @@ echo " FrankenPHP site is ready."
 	// Combine site creation and composer setup
 	fullCmd := siteCmd + composerCmd
 
+	// The form has been deployed, so there is nothing left to resume
+	ClearFormState(m.formStateKey())
+
+	files := m.generatedFiles
+	owner := "frankenphp-" + m.formSiteKey
 	return m, func() tea.Msg {
+		recordGeneratedFiles(owner, files)
 		return ExecutionStartMsg{
 			Command:     fullCmd,
 			Description: "Setting up FrankenPHP Site and Composer integration",
@@ -1326,7 +1563,7 @@ func (m FrankenPHPClassicModel) generateConfigFiles() FrankenPHPClassicModel {
 
 	id := m.formSiteKey
 
-	// 1. Caddyfile
+	// 1. Caddyfile (not recorded for drift: frankenphp fmt rewrites it)
 	caddyTemplate := m.generateCaddyfileContent()
 	m.generatedFiles = append(m.generatedFiles, GeneratedFile{
 		Name:    "Caddyfile",
@@ -1340,6 +1577,8 @@ func (m FrankenPHPClassicModel) generateConfigFiles() FrankenPHPClassicModel {
 		Name:    "Systemd Service",
 		Path:    fmt.Sprintf("/etc/systemd/system/frankenphp-%s.service", id),
 		Content: serviceTemplate,
+		Stub:    "service",
+		Params:  m.serviceFileParams(),
 	})
 
 	// 3. fpcli Wrapper
@@ -1348,6 +1587,8 @@ func (m FrankenPHPClassicModel) generateConfigFiles() FrankenPHPClassicModel {
 		Name:    "fpcli Wrapper",
 		Path:    "/usr/local/bin/fpcli",
 		Content: fpcliTemplate,
+		Stub:    "fpcli",
+		Params:  m.fpcliParams(),
 	})
 	return m
 }
@@ -1427,7 +1668,7 @@ func (m FrankenPHPClassicModel) generateCaddyfileContent() string {
 
 	requestBody := fmt.Sprintf("request_body {\n\t\tmax_size %sMB\n\t}", uploadMax)
 
-	content, err := stubs.LoadAndReplace("caddyfile", map[string]string{
+	replacements := map[string]string{
 		"SITE_KEY":       id,
 		"NUM_THREADS":    numThreads,
 		"MAX_THREADS":    maxThreads,
@@ -1437,7 +1678,11 @@ func (m FrankenPHPClassicModel) generateCaddyfileContent() string {
 		"REQUEST_BODY":   requestBody,
 		"DOCROOT":        docroot,
 		"PHP_DIRECTIVES": strings.TrimSpace(phpDirectives.String()),
-	})
+	}
+	if m.isWorker() {
+		addWorkerReplacements(replacements, m.formSiteRoot, m.formNumWorkers)
+	}
+	content, err := stubs.LoadAndReplace(frankenphpCaddyStub(m.isWorker()), replacements)
 	if err != nil {
 		return fmt.Sprintf("Error loading caddyfile stub: %v", err)
 	}
@@ -1446,6 +1691,16 @@ func (m FrankenPHPClassicModel) generateCaddyfileContent() string {
 }
 
 func (m FrankenPHPClassicModel) generateServiceFileContent() string {
+	content, err := stubs.LoadAndReplace("service", m.serviceFileParams())
+	if err != nil {
+		return fmt.Sprintf("Error loading service stub: %v", err)
+	}
+
+	return content
+}
+
+// serviceFileParams returns the replacements of the systemd service stub
+func (m FrankenPHPClassicModel) serviceFileParams() map[string]string {
 	id := m.formSiteKey
 	siteRoot := m.formSiteRoot
 	user := m.formUser
@@ -1463,9 +1718,12 @@ func (m FrankenPHPClassicModel) generateServiceFileContent() string {
 	}
 
 	caddyfile := fmt.Sprintf("/etc/frankenphp/%s/Caddyfile", id)
+	mode, execReload := frankenphpServiceMode(id, binary, caddyfile, m.isWorker())
 
-	content, err := stubs.LoadAndReplace("service", map[string]string{
+	return map[string]string{
 		"ID":                id,
+		"MODE":              mode,
+		"EXEC_RELOAD":       execReload,
 		"USER":              user,
 		"GROUP":             group,
 		"WORKING_DIRECTORY": siteRoot,
@@ -1474,24 +1732,12 @@ func (m FrankenPHPClassicModel) generateServiceFileContent() string {
 		"BINARY":            binary,
 		"CADDYFILE":         caddyfile,
 		"POST_START":        postStart,
-	})
-	if err != nil {
-		return fmt.Sprintf("Error loading service stub: %v", err)
 	}
-
-	return content
 }
 
 // generateFpcliContent generates the fpcli CLI wrapper script
 func (m FrankenPHPClassicModel) generateFpcliContent() string {
-	binary := m.binaryPath
-	if binary == "" {
-		binary = "/usr/local/bin/frankenphp"
-	}
-
-	content, err := stubs.LoadAndReplace("fpcli", map[string]string{
-		"BINARY": binary,
-	})
+	content, err := stubs.LoadAndReplace("fpcli", m.fpcliParams())
 	if err != nil {
 		return fmt.Sprintf("Error loading fpcli stub: %v", err)
 	}
@@ -1499,6 +1745,15 @@ func (m FrankenPHPClassicModel) generateFpcliContent() string {
 	return content
 }
 
+// fpcliParams returns the replacements of the fpcli stub
+func (m FrankenPHPClassicModel) fpcliParams() map[string]string {
+	binary := m.binaryPath
+	if binary == "" {
+		binary = "/usr/local/bin/frankenphp"
+	}
+	return map[string]string{"BINARY": binary}
+}
+
 // View renders the FrankenPHP Classic Mode screen
 func (m FrankenPHPClassicModel) View() string {
 	if m.width == 0 {
@@ -1506,6 +1761,8 @@ func (m FrankenPHPClassicModel) View() string {
 	}
 
 	switch m.mode {
+	case "resume_prompt":
+		return m.viewResumePrompt()
 	case "install_options":
 		return m.viewInstallOptions()
 	case "custom_url_input":
@@ -1527,18 +1784,45 @@ func (m FrankenPHPClassicModel) View() string {
 	return "Unknown mode"
 }
 
+// viewResumePrompt asks whether to restore site setup input from a previous visit
+func (m FrankenPHPClassicModel) viewResumePrompt() string {
+	header := lipgloss.JoinVertical(lipgloss.Left, m.theme.Title.Render(m.title()+" - Site Setup"), m.steps.View(m.theme))
+
+	var summary []string
+	for _, key := range []string{"siteRoot", "siteKey", "docroot", "domains", "connType", "port", "user", "group"} {
+		if v := m.resumeState[key]; v != "" {
+			summary = append(summary, m.theme.Label.Render(key+": ")+m.theme.InfoStyle.Render(v))
+		}
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		"",
+		m.theme.Subtitle.Render("Resume previous input?"),
+		m.theme.DescriptionStyle.Render("You left this form before finishing it. Restore what you entered?"),
+		"",
+		lipgloss.JoinVertical(lipgloss.Left, summary...),
+		"",
+		m.theme.Help.Render("y/Enter: Resume "+m.theme.Symbols.Bullet+" n/Esc: Start fresh"),
+	)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
 // viewCustomURLInput renders the custom URL input view
 func (m FrankenPHPClassicModel) viewCustomURLInput() string {
-	header := m.theme.Title.Render("FrankenPHP - Download from Custom URL")
+	header := lipgloss.JoinVertical(lipgloss.Left, m.theme.Title.Render("FrankenPHP - Download from Custom URL"), m.steps.View(m.theme))
 
 	instructions := lipgloss.JoinVertical(lipgloss.Left,
 		m.theme.DescriptionStyle.Render("Enter the direct download URL for the FrankenPHP binary."),
 		"",
 		m.theme.DescriptionStyle.Render("Examples:"),
 		m.theme.InfoStyle.Render("  • https://github.com/dunglas/frankenphp/releases/download/v1.0.0/frankenphp-linux-x86_64"),
-		m.theme.InfoStyle.Render("  • https://your-server.com/frankenphp"),
+		m.theme.InfoStyle.Render("  • https://your-server.com/frankenphp <sha256>"),
 		"",
 		m.theme.WarningStyle.Render("Note: URL must point directly to the binary file."),
+		m.theme.DescriptionStyle.Render("The download must match a SHA-256 from <url>.sha256, the GitHub release,"),
+		m.theme.DescriptionStyle.Render("or one typed after the URL."),
 	)
 
 	// Input field
@@ -1557,14 +1841,14 @@ func (m FrankenPHPClassicModel) viewCustomURLInput() string {
 func (m FrankenPHPClassicModel) viewInstallOptions() string {
 	// Handle message display (e.g., manual installation instructions)
 	if m.message != "" {
-		header := m.theme.Title.Render("FrankenPHP Classic Mode")
+		header := m.theme.Title.Render(m.title())
 		messageBox := m.theme.InfoStyle.Render(m.message)
 		content := lipgloss.JoinVertical(lipgloss.Left, header, "", messageBox)
 		bordered := m.theme.RenderBox(content)
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
 	}
 
-	header := m.theme.Title.Render("FrankenPHP Classic Mode")
+	header := lipgloss.JoinVertical(lipgloss.Left, m.theme.Title.Render(m.title()), m.steps.View(m.theme))
 
 	// Warning that binary not found
 	warning := lipgloss.JoinVertical(lipgloss.Left,
@@ -1624,7 +1908,7 @@ func (m FrankenPHPClassicModel) viewInstallOptions() string {
 func (m FrankenPHPClassicModel) viewSiteSetup() string {
 	// Handle message display
 	if m.message != "" {
-		header := m.theme.Title.Render("FrankenPHP Classic Mode")
+		header := m.theme.Title.Render(m.title())
 		messageBox := m.theme.InfoStyle.Render(m.message)
 		help := m.theme.Help.Render("Press any key to continue...")
 		content := lipgloss.JoinVertical(lipgloss.Left, header, "", messageBox, "", help)
@@ -1632,7 +1916,7 @@ func (m FrankenPHPClassicModel) viewSiteSetup() string {
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
 	}
 
-	header := m.theme.Title.Render("FrankenPHP Classic Mode - Site Setup")
+	header := lipgloss.JoinVertical(lipgloss.Left, m.theme.Title.Render(m.title()+" - Site Setup"), m.steps.View(m.theme))
 
 	// Binary info
 	binaryInfo := lipgloss.JoinVertical(lipgloss.Left,
@@ -1672,7 +1956,7 @@ func (m FrankenPHPClassicModel) viewSiteSetup() string {
 
 // viewConfirm renders the confirmation view
 func (m FrankenPHPClassicModel) viewConfirm() string {
-	header := m.theme.Title.Render("Confirm Site Creation")
+	header := lipgloss.JoinVertical(lipgloss.Left, m.theme.Title.Render("Confirm Site Creation"), m.steps.View(m.theme))
 
 	// Show summary of form values
 	var summary []string
@@ -1701,6 +1985,15 @@ func (m FrankenPHPClassicModel) viewConfirm() string {
 	if m.formGroup != "" {
 		summary = append(summary, m.theme.Label.Render("Run as Group: ")+m.theme.InfoStyle.Render(m.formGroup))
 	}
+	if m.isWorker() {
+		workers := m.formNumWorkers
+		if workers == "" {
+			workers = "default"
+		}
+		summary = append(summary, m.theme.Label.Render("Runtime: ")+m.theme.InfoStyle.Render("Worker mode (Laravel Octane), "+workers+" workers"))
+	} else {
+		summary = append(summary, m.theme.Label.Render("Runtime: ")+m.theme.InfoStyle.Render("Classic mode"))
+	}
 
 	// Performance Tuning
 	summary = append(summary, "")
@@ -1734,6 +2027,10 @@ func (m FrankenPHPClassicModel) viewConfirm() string {
 	summary = append(summary, m.theme.DescriptionStyle.Render(fmt.Sprintf("  • %s", m.theme.Label.Render("Custom app-php.ini: "))+fmt.Sprintf("/etc/frankenphp/%s/app-php.ini", siteKey)))
 	summary = append(summary, m.theme.DescriptionStyle.Render(fmt.Sprintf("  • %s", m.theme.Label.Render("CLI wrapper script: "))+"/usr/local/bin/fpcli"))
 
+	if m.isWorker() {
+		summary = append(summary, m.theme.DescriptionStyle.Render(fmt.Sprintf("  • %s", m.theme.Label.Render("Laravel Octane: "))+"composer require laravel/octane if missing, then octane:install"))
+		summary = append(summary, m.theme.DescriptionStyle.Render(fmt.Sprintf("  • %s", m.theme.Label.Render("Graceful reload: "))+fmt.Sprintf("systemctl reload frankenphp-%s restarts workers", siteKey)))
+	}
 	if m.formConnType == "socket" {
 		summary = append(summary, m.theme.DescriptionStyle.Render(fmt.Sprintf("  • %s /run/frankenphp/%s.sock", m.theme.Label.Render("Unix Socket:"), siteKey)))
 	} else {
@@ -1767,7 +2064,7 @@ func (m FrankenPHPClassicModel) viewConfirm() string {
 
 	optionsSection := lipgloss.JoinVertical(lipgloss.Left, options...)
 
-	help := m.theme.Help.Render("↑/↓: Navigate • Enter: Select • Esc: Back")
+	help := m.theme.Help.Render("↑/↓: Navigate • Enter: Select • Esc: Back" + m.steps.JumpHelp())
 
 	content := lipgloss.JoinVertical(lipgloss.Left, header, "", summarySection, optionsSection, "", help)
 	bordered := m.theme.RenderBox(content)
@@ -1776,7 +2073,7 @@ func (m FrankenPHPClassicModel) viewConfirm() string {
 
 // viewComposerSetup renders the composer setup options view
 func (m FrankenPHPClassicModel) viewComposerSetup() string {
-	header := m.theme.Title.Render("Composer Setup with FrankenPHP")
+	header := lipgloss.JoinVertical(lipgloss.Left, m.theme.Title.Render("Composer Setup with FrankenPHP"), m.steps.View(m.theme))
 
 	description := lipgloss.JoinVertical(lipgloss.Left,
 		m.theme.DescriptionStyle.Render("Configure how Composer should use FrankenPHP's PHP."),
@@ -1842,7 +2139,7 @@ func (m FrankenPHPClassicModel) viewComposerSetup() string {
 		}
 	}
 
-	help := m.theme.Help.Render("↑/↓: Navigate • Enter: Select • Esc: Back")
+	help := m.theme.Help.Render("↑/↓: Navigate • Enter: Select • Esc: Back" + m.steps.JumpHelp())
 
 	sections := []string{header, "", description, menu}
 	if infoSection != "" {
@@ -1857,7 +2154,7 @@ func (m FrankenPHPClassicModel) viewComposerSetup() string {
 
 // viewReviewFiles renders the file review view
 func (m FrankenPHPClassicModel) viewReviewFiles() string {
-	header := m.theme.Title.Render("Review Configuration Files")
+	header := lipgloss.JoinVertical(lipgloss.Left, m.theme.Title.Render("Review Configuration Files"), m.steps.View(m.theme))
 
 	description := m.theme.DescriptionStyle.Render("Review and optionally edit the files that will be created.")
 
@@ -1890,7 +2187,7 @@ func (m FrankenPHPClassicModel) viewReviewFiles() string {
 		m.theme.DescriptionStyle.Render(fmt.Sprintf("  %s: Proceed to Deployment", m.theme.KeyStyle.Render("d"))),
 	)
 
-	help := m.theme.Help.Render("↑/↓: Navigate • Enter: View • e: Edit • d: Deploy • Esc: Back")
+	help := m.theme.Help.Render("↑/↓: Navigate • Enter: View • e: Edit • d: Deploy • Esc: Back" + m.steps.JumpHelp())
 
 	content := lipgloss.JoinVertical(lipgloss.Left, header, "", description, "", menu, statusInfo, "", help)
 	bordered := m.theme.RenderBox(content)
@@ -1910,7 +2207,7 @@ func (m FrankenPHPClassicModel) viewFileContent() string {
 	// Wrap content in a style
 	content := m.theme.MenuItem.Render(file.Content)
 
-	help := m.theme.Help.Render("Esc/Enter/v: Back to List • d: Proceed to Deployment • q: Quit")
+	help := m.theme.Help.Render("Esc/Enter/v: Back to List • d: Proceed to Deployment • q: Quit" + m.steps.JumpHelp())
 
 	sections := []string{
 		header,
@@ -1929,7 +2226,7 @@ func (m FrankenPHPClassicModel) viewFileContent() string {
 
 // viewConfirmDeploy renders the final deployment confirmation
 func (m FrankenPHPClassicModel) viewConfirmDeploy() string {
-	header := m.theme.Title.Render("Final Deployment Confirmation")
+	header := lipgloss.JoinVertical(lipgloss.Left, m.theme.Title.Render("Final Deployment Confirmation"), m.steps.View(m.theme))
 
 	message := lipgloss.JoinVertical(lipgloss.Left,
 		m.theme.Subtitle.Render("Are you sure you want to deploy the site now?"),
@@ -1952,7 +2249,7 @@ func (m FrankenPHPClassicModel) viewConfirmDeploy() string {
 		m.theme.DescriptionStyle.Render("  Esc/n: No, back to review"),
 	)
 
-	help := m.theme.Help.Render("Enter: Confirm Deployment • Esc: Cancel")
+	help := m.theme.Help.Render("Enter: Confirm Deployment • Esc: Cancel" + m.steps.JumpHelp())
 
 	content := lipgloss.JoinVertical(lipgloss.Left, header, "", message, choices, "", help)
 	bordered := m.theme.RenderBox(content)
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -48,6 +50,11 @@ const (
 	FPServicesStateMetricsInput
 )
 
+// fpServicesLoadedMsg carries the discovered FrankenPHP services
+type fpServicesLoadedMsg struct {
+	services []FrankenPHPService
+}
+
 // EditableFile represents a file that can be edited
 type EditableFile struct {
 	Name string
@@ -98,6 +105,8 @@ type FrankenPHPServicesModel struct {
 	editNumThreads  string
 	editMaxThreads  string
 	editMaxWaitTime string
+	editWorker      bool   // Worker mode (Laravel Octane) Caddyfile
+	editNumWorkers  string // Empty for FrankenPHP's default
 
 	// Deployment data
 	generatedFiles []GeneratedFile
@@ -105,17 +114,23 @@ type FrankenPHPServicesModel struct {
 	fullCommand    string
 
 	// Confirm action
-	confirmAction string
-	confirmMsg    string
+	confirm Confirmation
 
 	// Filtering
-	filterDir string
+	filterDir   string
+	allServices []FrankenPHPService
+	tags        TagFilter
 
 	// Messages
 	detector *system.Detector
 	err      error
 	message  string
 
+	// Services load in the background; statuses come from the cache
+	loading     bool
+	loaded      bool
+	loadStarted time.Time
+
 	// Nginx View
 	nginxForm   *huh.Form
 	viewContent string
@@ -166,28 +181,10 @@ func NewFrankenPHPServicesModelWithFilter(filterDir string) FrankenPHPServicesMo
 			"Delete Service",
 			"← Back to List",
 		},
-		filterDir: filterDir,
-	}
-
-	// Load services
-	m.services = m.loadFrankenPHPServices()
-
-	// Apply filter if provided
-	if filterDir != "" {
-		var filtered []FrankenPHPService
-		normalizedFilter := strings.TrimSuffix(filterDir, "/")
-		for _, s := range m.services {
-			normalizedSiteRoot := strings.TrimSuffix(s.SiteRoot, "/")
-			if normalizedSiteRoot == normalizedFilter {
-				filtered = append(filtered, s)
-			}
-		}
-		m.services = filtered
-
-		// If exactly one service found for this dir, auto-select it
-		if len(m.services) == 1 {
-			m.state = FPServicesStateActions
-		}
+		filterDir:   filterDir,
+		tags:        NewTagFilter(system.TagKindService),
+		loading:     true,
+		loadStarted: time.Now(),
 	}
 
 	return m
@@ -222,19 +219,6 @@ func (m *FrankenPHPServicesModel) loadFrankenPHPServices() []FrankenPHPService {
 			SiteKey:     siteKey,
 		}
 
-		// Get service status
-		statusCmd := exec.Command("systemctl", "is-active", service.Name)
-		statusOutput, _ := statusCmd.Output()
-		service.Status = strings.TrimSpace(string(statusOutput))
-		if service.Status == "" {
-			service.Status = "unknown"
-		}
-
-		// Check if enabled
-		enabledCmd := exec.Command("systemctl", "is-enabled", service.Name)
-		enabledOutput, _ := enabledCmd.Output()
-		service.Enabled = strings.TrimSpace(string(enabledOutput)) == "enabled"
-
 		// Parse service file for details
 		config := m.parseServiceFileDetailed(line)
 		service.SiteRoot = config.SiteRoot
@@ -246,6 +230,19 @@ func (m *FrankenPHPServicesModel) loadFrankenPHPServices() []FrankenPHPService {
 		services = append(services, service)
 	}
 
+	// Ask systemctl about every service side by side
+	var wg sync.WaitGroup
+	for i := range services {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			state := system.CachedUnitState(services[i].Name)
+			services[i].Status = state.Active
+			services[i].Enabled = state.Enabled
+		}()
+	}
+	wg.Wait()
+
 	return services
 }
 
@@ -357,9 +354,26 @@ func (m *FrankenPHPServicesModel) parseServiceFileDetailed(path string) ServiceC
 	return config
 }
 
-// Init initializes the screen
+// Init loads the services in the background
 func (m FrankenPHPServicesModel) Init() tea.Cmd {
-	return nil
+	return tea.Batch(func() tea.Msg {
+		return fpServicesLoadedMsg{services: m.loadFrankenPHPServices()}
+	}, spinnerTick())
+}
+
+// refresh forgets the services' cached states and loads them again
+func (m FrankenPHPServicesModel) refresh() (FrankenPHPServicesModel, tea.Cmd) {
+	if m.loading {
+		return m, nil
+	}
+	names := make([]string, len(m.allServices))
+	for i, s := range m.allServices {
+		names[i] = s.Name
+	}
+	system.InvalidateServiceStatus(names...)
+	m.loading = true
+	m.loadStarted = time.Now()
+	return m, m.Init()
 }
 
 // Update handles messages
@@ -370,6 +384,25 @@ func (m FrankenPHPServicesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case fpServicesLoadedMsg:
+		m.loading = false
+		m.allServices = msg.services
+		m.applyTagFilter()
+		if m.loaded {
+			m.message = "Services refreshed"
+		} else if m.filterDir != "" && len(m.services) == 1 && m.state == FPServicesStateList {
+			// If exactly one service found for this dir, auto-select it
+			m.state = FPServicesStateActions
+		}
+		m.loaded = true
+		return m, nil
+
+	case SpinnerTickMsg:
+		if m.loading {
+			return m, spinnerTick()
+		}
+		return m, nil
+
 	case tea.KeyMsg:
 		// Clear messages on any key (except in specific states)
 		if m.state != FPServicesStateEdit && m.state != FPServicesStateReview && (m.message != "" || m.err != nil) {
@@ -452,6 +485,15 @@ func (m FrankenPHPServicesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // updateList handles list view navigation
 func (m FrankenPHPServicesModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.tags.Editing() {
+		var done bool
+		m.tags, done = m.tags.Update(msg)
+		if done {
+			m.applyTagFilter()
+		}
+		return m, nil
+	}
+
 	switch {
 	case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+c", "q"))):
 		return m, tea.Quit
@@ -492,13 +534,45 @@ func (m FrankenPHPServicesModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			}
 		}
 	case key.Matches(msg, key.NewBinding(key.WithKeys("r"))):
-		// Refresh services list
-		m.services = m.loadFrankenPHPServices()
-		m.message = "Services refreshed"
+		// Refresh services list, skipping the cached statuses
+		return m.refresh()
+	case key.Matches(msg, key.NewBinding(key.WithKeys("T"))):
+		if len(m.services) > 0 {
+			m.tags = m.tags.StartEdit(m.services[m.cursor].Name)
+		}
+	case key.Matches(msg, key.NewBinding(key.WithKeys("f"))):
+		m.tags = m.tags.Cycle()
+		m.cursor = 0
+		m.applyTagFilter()
+	case key.Matches(msg, key.NewBinding(key.WithKeys("R"))):
+		// Restart every service with the active tag
+		if m.tags.Active != "" && len(m.services) > 0 {
+			m.confirm = NewConfirmation("restart_tagged", "Restart Tagged Services",
+				fmt.Sprintf("Restart all %d service(s) tagged #%s?", len(m.services), m.tags.Active), ConfirmWarning)
+			m.state = FPServicesStateConfirmAction
+		}
 	}
 	return m, nil
 }
 
+// applyTagFilter limits the visible services to the active tag
+func (m *FrankenPHPServicesModel) applyTagFilter() {
+	services := m.allServices
+	if m.filterDir != "" {
+		services = nil
+		normalizedFilter := strings.TrimSuffix(m.filterDir, "/")
+		for _, s := range m.allServices {
+			if strings.TrimSuffix(s.SiteRoot, "/") == normalizedFilter {
+				services = append(services, s)
+			}
+		}
+	}
+	m.services = filterByTag(m.tags, services, func(s FrankenPHPService) string { return s.Name })
+	if m.cursor >= len(m.services) {
+		m.cursor = 0
+	}
+}
+
 // updateActions handles action menu navigation
 func (m FrankenPHPServicesModel) updateActions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
@@ -544,13 +618,19 @@ func (m FrankenPHPServicesModel) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 
 // updateConfirm handles confirmation dialog
 func (m FrankenPHPServicesModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c", "q":
+	if msg.String() == "ctrl+c" {
 		return m, tea.Quit
-	case "esc", "n", "N":
+	}
+
+	var result ConfirmResult
+	m.confirm, result = m.confirm.Update(msg)
+	switch result {
+	case ConfirmCancelled:
 		m.state = FPServicesStateActions
-		return m, nil
-	case "y", "Y", "enter":
+		if m.confirm.Action == "restart_tagged" {
+			m.state = FPServicesStateList
+		}
+	case ConfirmAccepted:
 		return m.doConfirmedAction()
 	}
 	return m, nil
@@ -567,56 +647,32 @@ func (m FrankenPHPServicesModel) executeAction() (tea.Model, tea.Cmd) {
 
 	switch action {
 	case "Start Service":
-		return m, func() tea.Msg {
-			return ExecutionStartMsg{
-				Command:     fmt.Sprintf("sudo systemctl start %s && sudo systemctl status %s --no-pager -l", service.Name, service.Name),
-				Description: fmt.Sprintf("Starting %s", service.Name),
-			}
-		}
+		return m, runSystemctl("start", service.Name)
 
 	case "Stop Service":
-		m.confirmAction = "stop"
-		m.confirmMsg = fmt.Sprintf("Stop service %s?", service.Name)
+		m.confirm = NewConfirmation("stop", "Stop Service", fmt.Sprintf("Stop service %s?", service.Name), ConfirmNormal)
 		m.state = FPServicesStateConfirmAction
 		return m, nil
 
 	case "Restart Service":
-		return m, func() tea.Msg {
-			return ExecutionStartMsg{
-				Command:     fmt.Sprintf("sudo systemctl restart %s && sudo systemctl status %s --no-pager -l", service.Name, service.Name),
-				Description: fmt.Sprintf("Restarting %s", service.Name),
-			}
-		}
+		return m, runSystemctl("restart", service.Name)
 
 	case "Enable (start on boot)":
-		return m, func() tea.Msg {
-			return ExecutionStartMsg{
-				Command:     fmt.Sprintf("sudo systemctl enable %s && echo '✓ Service enabled'", service.Name),
-				Description: fmt.Sprintf("Enabling %s", service.Name),
-			}
-		}
+		return m, runSystemctl("enable", service.Name)
 
 	case "Disable (don't start on boot)":
-		return m, func() tea.Msg {
-			return ExecutionStartMsg{
-				Command:     fmt.Sprintf("sudo systemctl disable %s && echo '✓ Service disabled'", service.Name),
-				Description: fmt.Sprintf("Disabling %s", service.Name),
-			}
-		}
+		return m, runSystemctl("disable", service.Name)
 
 	case "View Status":
-		return m, func() tea.Msg {
-			return ExecutionStartMsg{
-				Command:     fmt.Sprintf("sudo systemctl status %s --no-pager -l", service.Name),
-				Description: fmt.Sprintf("Status of %s", service.Name),
-			}
-		}
+		return m, runSystemctl("status", service.Name)
 
 	case "View Logs":
 		return m, func() tea.Msg {
-			return ExecutionStartMsg{
-				Command:     fmt.Sprintf("sudo journalctl -u %s -n 100 --no-pager", service.Name),
-				Description: fmt.Sprintf("Logs for %s", service.Name),
+			return NavigateMsg{
+				Screen: LogViewerScreen,
+				Data: map[string]interface{}{
+					"unit": service.Name,
+				},
 			}
 		}
 
@@ -643,8 +699,7 @@ func (m FrankenPHPServicesModel) executeAction() (tea.Model, tea.Cmd) {
 		return m, m.metricsForm.Init()
 
 	case "Disable Caddy Metrics":
-		m.confirmAction = "disable_metrics"
-		m.confirmMsg = "Remove Caddy Metrics configuration? This will restart the service."
+		m.confirm = NewConfirmation("disable_metrics", "Disable Caddy Metrics", "Remove Caddy Metrics configuration? This will restart the service.", ConfirmWarning)
 		m.state = FPServicesStateConfirmAction
 		return m, nil
 
@@ -670,8 +725,13 @@ func (m FrankenPHPServicesModel) executeAction() (tea.Model, tea.Cmd) {
 		return m, m.nginxForm.Init()
 
 	case "Delete Service":
-		m.confirmAction = "delete"
-		m.confirmMsg = fmt.Sprintf("Delete service %s? This will stop the service and remove configuration files.", service.Name)
+		phrase := service.SiteKey
+		if phrase == "" {
+			phrase = service.Name
+		}
+		m.confirm = NewDangerConfirmation("delete", "Delete Service",
+			fmt.Sprintf("Delete service %s? This will stop the service and remove configuration files.", service.Name),
+			phrase)
 		m.state = FPServicesStateConfirmAction
 		return m, nil
 
@@ -691,16 +751,25 @@ func (m FrankenPHPServicesModel) doConfirmedAction() (tea.Model, tea.Cmd) {
 
 	service := m.services[m.cursor]
 
-	switch m.confirmAction {
-	case "stop":
+	switch m.confirm.Action {
+	case "restart_tagged":
 		m.state = FPServicesStateList
+		var names []string
+		for _, s := range m.services {
+			names = append(names, s.Name)
+		}
+		units := strings.Join(names, " ")
 		return m, func() tea.Msg {
 			return ExecutionStartMsg{
-				Command:     fmt.Sprintf("sudo systemctl stop %s && echo '✓ Service stopped'", service.Name),
-				Description: fmt.Sprintf("Stopping %s", service.Name),
+				Command:     fmt.Sprintf("sudo systemctl restart %s && systemctl is-active %s", units, units),
+				Description: fmt.Sprintf("Restarting services tagged #%s", m.tags.Active),
 			}
 		}
 
+	case "stop":
+		m.state = FPServicesStateList
+		return m, runSystemctl("stop", service.Name)
+
 	case "delete":
 		m.state = FPServicesStateList
 		return m, func() tea.Msg {
@@ -717,6 +786,7 @@ func (m FrankenPHPServicesModel) doConfirmedAction() (tea.Model, tea.Cmd) {
 			cmds = append(cmds, fmt.Sprintf("sudo rm -f /run/frankenphp/%s.sock", service.SiteKey))
 
 			fullCmd := strings.Join(cmds, " && ") + " && echo '✓ Service deleted'"
+			_ = system.ForgetGeneratedConfig(service.ServiceFile)
 
 			return ExecutionStartMsg{
 				Command:     fullCmd,
@@ -931,6 +1001,8 @@ func (m *FrankenPHPServicesModel) loadCaddyfileForEdit(path string) {
 	m.editNumThreads = "8"
 	m.editMaxThreads = "auto"
 	m.editMaxWaitTime = "15"
+	m.editWorker = false
+	m.editNumWorkers = ""
 
 	// Defaults for PHP
 	m.editPHPMemoryLimit = "256M"
@@ -958,7 +1030,11 @@ func (m *FrankenPHPServicesModel) loadCaddyfileForEdit(path string) {
 	lines := strings.Split(content, "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "num_threads") {
+		if line == "worker {" || strings.HasPrefix(line, "worker ") {
+			m.editWorker = true
+		} else if strings.HasPrefix(line, "num ") {
+			m.editNumWorkers = strings.TrimSpace(strings.TrimPrefix(line, "num "))
+		} else if strings.HasPrefix(line, "num_threads") {
 			m.editNumThreads = strings.TrimSpace(strings.TrimPrefix(line, "num_threads"))
 		} else if strings.HasPrefix(line, "max_threads") {
 			m.editMaxThreads = strings.TrimSpace(strings.TrimPrefix(line, "max_threads"))
@@ -1157,6 +1233,13 @@ func (m *FrankenPHPServicesModel) buildEditForm() *huh.Form {
 					return nil
 				}).
 				Value(&m.editMaxWaitTime),
+
+			huh.NewInput().
+				Key("numWorkers").
+				Title("Number of Workers").
+				Description("Worker mode only: Octane workers kept booted (empty: 2 × CPUs)").
+				Validate(validateNumWorkers).
+				Value(&m.editNumWorkers),
 		).Title("Performance Tuning"),
 
 		huh.NewGroup(
@@ -1266,6 +1349,9 @@ func (m FrankenPHPServicesModel) saveServiceConfig() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.editWorker {
+		m.editNumWorkers = m.editForm.GetString("numWorkers")
+	}
 	m.state = FPServicesStateReview
 	m.fileCursor = 0
 	return m.generateConfigFiles(), nil
@@ -1277,7 +1363,7 @@ func (m FrankenPHPServicesModel) generateConfigFiles() FrankenPHPServicesModel {
 	service := m.services[m.cursor]
 	id := service.SiteKey
 
-	// 1. Caddyfile
+	// 1. Caddyfile (not recorded for drift: frankenphp fmt rewrites it)
 	caddyTemplate := m.generateCaddyfileContent()
 	m.generatedFiles = append(m.generatedFiles, GeneratedFile{
 		Name:    "Caddyfile",
@@ -1291,6 +1377,8 @@ func (m FrankenPHPServicesModel) generateConfigFiles() FrankenPHPServicesModel {
 		Name:    "Systemd Service",
 		Path:    fmt.Sprintf("/etc/systemd/system/frankenphp-%s.service", id),
 		Content: serviceTemplate,
+		Stub:    "service",
+		Params:  m.serviceFileParams(),
 	})
 
 	// 3. fpcli Wrapper
@@ -1299,6 +1387,8 @@ func (m FrankenPHPServicesModel) generateConfigFiles() FrankenPHPServicesModel {
 		Name:    "fpcli Wrapper",
 		Path:    "/usr/local/bin/fpcli",
 		Content: fpcliTemplate,
+		Stub:    "fpcli",
+		Params:  m.fpcliParams(),
 	})
 
 	return m
@@ -1375,7 +1465,7 @@ func (m FrankenPHPServicesModel) generateCaddyfileContent() string {
 
 	requestBody := fmt.Sprintf("request_body {\n\t\tmax_size %sMB\n\t}", uploadMax)
 
-	content, _ := stubs.LoadAndReplace("caddyfile", map[string]string{
+	replacements := map[string]string{
 		"SITE_KEY":       id,
 		"NUM_THREADS":    m.editNumThreads,
 		"MAX_THREADS":    m.editMaxThreads,
@@ -1385,12 +1475,24 @@ func (m FrankenPHPServicesModel) generateCaddyfileContent() string {
 		"REQUEST_BODY":   requestBody,
 		"DOCROOT":        docroot,
 		"PHP_DIRECTIVES": strings.TrimSpace(phpDirectives.String()),
-	})
+	}
+	// Keep worker-mode sites in worker mode
+	if m.editWorker {
+		addWorkerReplacements(replacements, m.editSiteRoot, m.editNumWorkers)
+	}
+	content, _ := stubs.LoadAndReplace(frankenphpCaddyStub(m.editWorker), replacements)
 
 	return content
 }
 
 func (m FrankenPHPServicesModel) generateServiceFileContent() string {
+	content, _ := stubs.LoadAndReplace("service", m.serviceFileParams())
+
+	return content
+}
+
+// serviceFileParams returns the replacements of the systemd service stub
+func (m FrankenPHPServicesModel) serviceFileParams() map[string]string {
 	id := m.services[m.cursor].SiteKey
 	siteRoot := m.editSiteRoot
 	user := m.editUser
@@ -1408,9 +1510,12 @@ func (m FrankenPHPServicesModel) generateServiceFileContent() string {
 	}
 
 	caddyfile := fmt.Sprintf("/etc/frankenphp/%s/Caddyfile", id)
+	mode, execReload := frankenphpServiceMode(id, binary, caddyfile, m.editWorker)
 
-	content, _ := stubs.LoadAndReplace("service", map[string]string{
+	return map[string]string{
 		"ID":                id,
+		"MODE":              mode,
+		"EXEC_RELOAD":       execReload,
 		"USER":              user,
 		"GROUP":             group,
 		"WORKING_DIRECTORY": siteRoot,
@@ -1419,9 +1524,7 @@ func (m FrankenPHPServicesModel) generateServiceFileContent() string {
 		"BINARY":            binary,
 		"CADDYFILE":         caddyfile,
 		"POST_START":        postStart,
-	})
-
-	return content
+	}
 }
 
 func (m FrankenPHPServicesModel) getFullDocroot() string {
@@ -1542,6 +1645,7 @@ func (m FrankenPHPServicesModel) updateConfirmDeploy(msg tea.KeyMsg) (tea.Model,
 	case "y", "Y", "enter":
 		m.state = FPServicesStateExecuting
 		return m, func() tea.Msg {
+			recordGeneratedFiles(m.services[m.cursor].Name, m.generatedFiles)
 			return ExecutionStartMsg{
 				Command:     m.buildDeployCommand(),
 				Description: "Deploying FrankenPHP Configuration Changes",
@@ -1569,7 +1673,7 @@ func (m FrankenPHPServicesModel) View() string {
 	case FPServicesStateReview:
 		return m.viewReview()
 	case FPServicesStateConfirmAction:
-		return m.viewConfirm()
+		return m.confirm.View(m.theme, m.width, m.height)
 	case FPServicesStateConfirmDeploy:
 		return m.viewConfirmDeploy()
 	case FPServicesStateExecuting:
@@ -1639,6 +1743,22 @@ func (m FrankenPHPServicesModel) getStatusStyle(status string) string {
 func (m FrankenPHPServicesModel) viewList() string {
 	header := m.theme.Title.Render("FrankenPHP Services")
 
+	if m.loading && len(m.services) == 0 {
+		content := lipgloss.JoinVertical(lipgloss.Left, header, "",
+			m.theme.InfoStyle.Render(spinnerFrame(m.theme, m.loadStarted)+" Checking FrankenPHP services..."), "",
+			m.theme.Help.Render("Esc: Back"))
+		bordered := m.theme.RenderBox(content)
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+	}
+
+	if len(m.services) == 0 && m.tags.Active != "" {
+		content := lipgloss.JoinVertical(lipgloss.Left, header, "",
+			m.theme.WarningStyle.Render("No services tagged #"+m.tags.Active+"."), "",
+			m.theme.Help.Render("f: Change filter • Esc: Back"))
+		bordered := m.theme.RenderBox(content)
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+	}
+
 	if len(m.services) == 0 {
 		noServices := lipgloss.JoinVertical(lipgloss.Left,
 			"",
@@ -1686,6 +1806,9 @@ func (m FrankenPHPServicesModel) viewList() string {
 			userStr = m.theme.DescriptionStyle.Render(fmt.Sprintf(" (%s)", svc.User))
 		}
 		name := fmt.Sprintf("%s %s%s%s", statusIndicator, svc.Name, enabledStr, userStr)
+		if badges := m.tags.Badges(m.theme, svc.Name); badges != "" {
+			name += " " + badges
+		}
 
 		var renderedItem string
 		if i == m.cursor {
@@ -1729,13 +1852,22 @@ func (m FrankenPHPServicesModel) viewList() string {
 	if m.message != "" {
 		messageSection = m.theme.SuccessStyle.Render(m.message)
 	}
+	if m.loading {
+		messageSection = m.theme.InfoStyle.Render(spinnerFrame(m.theme, m.loadStarted) + " Refreshing statuses...")
+	}
 	if m.err != nil {
 		messageSection = m.theme.ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err))
 	}
 
-	help := m.theme.Help.Render("↑/↓: Navigate • Enter: Actions • r: Refresh • Esc: Back")
+	help := m.theme.Help.Render("↑/↓: Navigate • Enter: Actions • r: Refresh" + m.tags.Help(m.theme) + " • Esc: Back")
+	if m.tags.Active != "" {
+		help += "\n" + m.theme.Help.Render("R: Restart all services tagged #"+m.tags.Active)
+	}
 
 	sections := []string{header, menu, legend}
+	if tagLine := m.tags.View(m.theme); tagLine != "" {
+		sections = append(sections, "", tagLine)
+	}
 	if messageSection != "" {
 		sections = append(sections, "", messageSection)
 	}
@@ -1747,16 +1879,18 @@ func (m FrankenPHPServicesModel) viewList() string {
 }
 
 func (m FrankenPHPServicesModel) generateFpcliContent() string {
+	content, _ := stubs.LoadAndReplace("fpcli", m.fpcliParams())
+
+	return content
+}
+
+// fpcliParams returns the replacements of the fpcli stub
+func (m FrankenPHPServicesModel) fpcliParams() map[string]string {
 	binary := m.editBinary
 	if binary == "" {
 		binary = "/usr/local/bin/frankenphp"
 	}
-
-	content, _ := stubs.LoadAndReplace("fpcli", map[string]string{
-		"BINARY": binary,
-	})
-
-	return content
+	return map[string]string{"BINARY": binary}
 }
 
 // viewActions renders the actions menu
@@ -1837,25 +1971,6 @@ func (m FrankenPHPServicesModel) viewEdit() string {
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
 }
 
-// viewConfirm renders the confirmation dialog
-func (m FrankenPHPServicesModel) viewConfirm() string {
-	header := m.theme.Title.Render("Confirm Action")
-
-	message := m.theme.WarningStyle.Render(m.confirmMsg)
-
-	options := lipgloss.JoinVertical(lipgloss.Left,
-		"",
-		m.theme.MenuItem.Render("  Press 'y' to confirm"),
-		m.theme.MenuItem.Render("  Press 'n' or Esc to cancel"),
-	)
-
-	help := m.theme.Help.Render("y: Yes • n/Esc: No")
-
-	content := lipgloss.JoinVertical(lipgloss.Left, header, "", message, options, "", help)
-	bordered := m.theme.RenderBox(content)
-	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
-}
-
 // buildNginxSelectForm builds the Nginx Selection Form
 func (m *FrankenPHPServicesModel) buildNginxSelectForm() *huh.Form {
 	service := m.services[m.cursor]
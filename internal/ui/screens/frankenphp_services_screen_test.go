@@ -101,3 +101,38 @@ func TestGenerateCaddyfileContent(t *testing.T) {
 		t.Error("expected port :8000 in generated Caddyfile")
 	}
 }
+
+func TestGenerateCaddyfileContentWorkerMode(t *testing.T) {
+	model := FrankenPHPServicesModel{
+		editNumThreads:  "4",
+		editMaxThreads:  "8",
+		editMaxWaitTime: "15",
+		editPort:        "8000",
+		editSiteRoot:    "/var/www/app",
+		editDocroot:     "/var/www/app/public",
+		editWorker:      true,
+		editNumWorkers:  "6",
+	}
+	model.services = []FrankenPHPService{{SiteKey: "app"}}
+
+	content := model.generateCaddyfileContent()
+
+	for _, want := range []string{
+		"file /var/www/app/public/frankenphp-worker.php",
+		"num 6",
+		"env APP_BASE_PATH /var/www/app",
+		"unix//run/frankenphp/app-admin.sock",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in worker Caddyfile:\n%s", want, content)
+		}
+	}
+
+	mode, reload := frankenphpServiceMode("app", "/usr/local/bin/frankenphp", "/etc/frankenphp/app/Caddyfile", true)
+	if mode != frankenphpWorker || !strings.Contains(reload, "--address unix//run/frankenphp/app-admin.sock") {
+		t.Errorf("unexpected worker service mode %q, reload %q", mode, reload)
+	}
+	if mode, reload := frankenphpServiceMode("app", "", "", false); mode != frankenphpClassic || reload != "" {
+		t.Errorf("classic mode should not reload workers, got %q, %q", mode, reload)
+	}
+}
@@ -0,0 +1,88 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FrankenPHP runtime modes
+const (
+	frankenphpClassic = "classic"
+	frankenphpWorker  = "worker"
+)
+
+// frankenphpCaddyStub returns the Caddyfile stub for a runtime mode
+func frankenphpCaddyStub(worker bool) string {
+	if worker {
+		return "caddyfile-worker"
+	}
+	return "caddyfile"
+}
+
+// frankenphpAdminSocket is the admin API socket worker-mode sites listen on,
+// which `frankenphp reload` uses to restart workers without dropping
+// requests
+func frankenphpAdminSocket(siteKey string) string {
+	return fmt.Sprintf("/run/frankenphp/%s-admin.sock", siteKey)
+}
+
+// addWorkerReplacements adds the worker placeholders to Caddyfile stub
+// replacements. An empty worker count leaves FrankenPHP's default (twice
+// the CPU count).
+func addWorkerReplacements(replacements map[string]string, siteRoot, numWorkers string) {
+	replacements["APP_BASE_PATH"] = siteRoot
+	replacements["NUM_WORKERS"] = ""
+	if numWorkers = strings.TrimSpace(numWorkers); numWorkers != "" {
+		replacements["NUM_WORKERS"] = "num " + numWorkers
+	}
+}
+
+// frankenphpServiceMode returns the service stub's mode label and, for
+// worker mode, the ExecReload line that restarts workers gracefully
+func frankenphpServiceMode(siteKey, binary, caddyfile string, worker bool) (mode, execReload string) {
+	if !worker {
+		return frankenphpClassic, ""
+	}
+	return frankenphpWorker, fmt.Sprintf("ExecReload=%s reload --config %s --address unix/%s --force\n",
+		binary, caddyfile, frankenphpAdminSocket(siteKey))
+}
+
+// validateNumWorkers accepts an empty count (FrankenPHP's default) or a
+// positive number
+func validateNumWorkers(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	if n, err := strconv.Atoi(s); err != nil || n < 1 {
+		return fmt.Errorf("must be a positive number, or empty for the default")
+	}
+	return nil
+}
+
+// octaneInstallScript installs laravel/octane and publishes its FrankenPHP
+// worker script when either is missing. Composer and artisan run as the
+// project owner so vendor/ keeps its ownership.
+func octaneInstallScript(siteRoot, docroot, owner string) string {
+	var b strings.Builder
+	b.WriteString("\necho \"\"\necho \"Checking Laravel Octane...\"\n")
+	fmt.Fprintf(&b, "cd %q\n", siteRoot)
+	b.WriteString("if [ ! -f artisan ]; then\n    echo \"✗ Worker mode needs a Laravel project: no artisan in $(pwd)\"\n    exit 1\nfi\n")
+	b.WriteString("chmod +x /usr/local/bin/fpcli 2>/dev/null || true\n")
+	b.WriteString("if command -v composer >/dev/null 2>&1; then\n    COMPOSER=\"composer\"\n")
+	b.WriteString("elif [ -f /usr/local/bin/composer.phar ]; then\n    COMPOSER=\"/usr/local/bin/fpcli /usr/local/bin/composer.phar\"\n")
+	b.WriteString("else\n    COMPOSER=\"\"\nfi\n")
+	b.WriteString("if ! grep -q '\"laravel/octane\"' composer.json; then\n")
+	b.WriteString("    if [ -z \"$COMPOSER\" ]; then\n        echo \"✗ Composer is required to install laravel/octane\"\n        exit 1\n    fi\n")
+	b.WriteString("    echo \"  Installing laravel/octane...\"\n")
+	fmt.Fprintf(&b, "    sudo -u %s $COMPOSER require laravel/octane --no-interaction\n", owner)
+	b.WriteString("else\n    echo \"  ✓ laravel/octane is installed\"\nfi\n")
+	fmt.Fprintf(&b, "if [ ! -f %q ]; then\n", docroot+"/frankenphp-worker.php")
+	b.WriteString("    echo \"  Publishing the FrankenPHP worker script...\"\n")
+	fmt.Fprintf(&b, "    sudo -u %s /usr/local/bin/fpcli artisan octane:install --server=frankenphp --no-interaction\n", owner)
+	b.WriteString("fi\n")
+	fmt.Fprintf(&b, "if [ ! -f %q ]; then\n", docroot+"/frankenphp-worker.php")
+	fmt.Fprintf(&b, "    echo \"✗ %s/frankenphp-worker.php is missing; run php artisan octane:install --server=frankenphp\"\n    exit 1\nfi\n", docroot)
+	b.WriteString("echo \"  ✓ Octane worker script ready\"\n")
+	return b.String()
+}
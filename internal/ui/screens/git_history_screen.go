@@ -0,0 +1,341 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// gitHistoryLimit is the number of log lines loaded
+const gitHistoryLimit = 300
+
+// GitHistoryModel is a read-only browser for a repository's log, commits,
+// and how the local branch compares with its upstream
+type GitHistoryModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+	dir    string
+
+	mode    string // "log", "detail", "compare"
+	all     bool   // Log all branches instead of HEAD
+	entries []system.GitLogEntry
+	cursor  int
+
+	compare       *system.GitComparison
+	compareList   []system.GitLogEntry // Ahead then behind
+	compareCursor int
+	compareStat   []string
+
+	detailFrom string // Mode to return to from the detail view
+	detailHash string
+	detail     []string
+	scroll     int
+
+	err error
+}
+
+// NewGitHistoryModel creates a history browser for the current directory
+func NewGitHistoryModel() GitHistoryModel {
+	dir, _ := os.Getwd()
+	m := GitHistoryModel{
+		theme: theme.DefaultTheme(),
+		dir:   dir,
+		mode:  "log",
+	}
+	m.loadLog()
+	return m
+}
+
+// loadLog reads the log and puts the cursor on the first commit
+func (m *GitHistoryModel) loadLog() {
+	rev := "HEAD"
+	if m.all {
+		rev = ""
+	}
+	m.entries, m.err = system.GitLog(m.dir, rev, gitHistoryLimit)
+	m.cursor = 0
+	m.cursor = m.nextCommit(0, 1)
+}
+
+// nextCommit returns the first line from i in direction step that is a
+// commit rather than a graph continuation, or the current cursor if none
+func (m GitHistoryModel) nextCommit(i, step int) int {
+	for ; i >= 0 && i < len(m.entries); i += step {
+		if m.entries[i].Hash != "" {
+			return i
+		}
+	}
+	return m.cursor
+}
+
+// Init initializes the history screen
+func (m GitHistoryModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the history screen
+func (m GitHistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case "detail":
+			return m.updateDetail(msg)
+		case "compare":
+			return m.updateCompare(msg)
+		}
+		return m.updateLog(msg)
+	}
+
+	return m, nil
+}
+
+// updateLog handles keys on the commit log
+func (m GitHistoryModel) updateLog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: GitManagementScreen}
+		}
+	case "up", "k":
+		m.cursor = m.nextCommit(m.cursor-1, -1)
+	case "down", "j":
+		m.cursor = m.nextCommit(m.cursor+1, 1)
+	case "pgup":
+		m.cursor = m.nextCommit(max(m.cursor-m.visibleLines(), 0), 1)
+	case "pgdown":
+		m.cursor = m.nextCommit(min(m.cursor+m.visibleLines(), len(m.entries)-1), -1)
+	case "a":
+		m.all = !m.all
+		m.loadLog()
+	case "c":
+		m.compare, m.err = system.GitCompareUpstream(m.dir)
+		if m.err != nil {
+			return m, nil
+		}
+		m.compareList = append(append([]system.GitLogEntry{}, m.compare.Ahead...), m.compare.Behind...)
+		m.compareCursor = 0
+		m.compareStat = nil
+		if len(m.compare.Behind) > 0 {
+			if stat, err := system.GitDiffStat(m.dir, "HEAD", "@{upstream}"); err == nil {
+				m.compareStat = strings.Split(strings.TrimRight(stat, "\n"), "\n")
+			}
+		}
+		m.mode = "compare"
+	case "enter", " ":
+		if m.cursor < len(m.entries) && m.entries[m.cursor].Hash != "" {
+			return m.openDetail(m.entries[m.cursor].Hash)
+		}
+	}
+	return m, nil
+}
+
+// updateCompare handles keys on the local/upstream comparison
+func (m GitHistoryModel) updateCompare(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "backspace":
+		m.mode = "log"
+	case "up", "k":
+		if m.compareCursor > 0 {
+			m.compareCursor--
+		}
+	case "down", "j":
+		if m.compareCursor < len(m.compareList)-1 {
+			m.compareCursor++
+		}
+	case "enter", " ":
+		if len(m.compareList) > 0 {
+			return m.openDetail(m.compareList[m.compareCursor].Hash)
+		}
+	}
+	return m, nil
+}
+
+// updateDetail handles scrolling a commit
+func (m GitHistoryModel) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxScroll := max(len(m.detail)-m.visibleLines(), 0)
+	switch msg.String() {
+	case "esc", "backspace":
+		m.mode = m.detailFrom
+	case "up", "k":
+		if m.scroll > 0 {
+			m.scroll--
+		}
+	case "down", "j":
+		if m.scroll < maxScroll {
+			m.scroll++
+		}
+	case "pgup":
+		m.scroll = max(m.scroll-m.visibleLines(), 0)
+	case "pgdown", " ":
+		m.scroll = min(m.scroll+m.visibleLines(), maxScroll)
+	case "home", "g":
+		m.scroll = 0
+	case "end", "G":
+		m.scroll = maxScroll
+	}
+	return m, nil
+}
+
+// openDetail shows a commit's stats and patch
+func (m GitHistoryModel) openDetail(hash string) (tea.Model, tea.Cmd) {
+	detail, err := system.GitCommitDetail(m.dir, hash)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.err = nil
+	m.detailFrom = m.mode
+	m.detailHash = hash
+	m.detail = strings.Split(strings.TrimRight(detail, "\n"), "\n")
+	m.scroll = 0
+	m.mode = "detail"
+	return m, nil
+}
+
+// visibleLines is the number of list or diff lines that fit on screen
+func (m GitHistoryModel) visibleLines() int {
+	if m.height < 20 {
+		return 8
+	}
+	return m.height - 14
+}
+
+// window returns the slice of n items around cursor that fits on screen
+func (m GitHistoryModel) window(n, cursor, height int) (int, int) {
+	start := 0
+	if cursor >= height {
+		start = cursor - height + 1
+	}
+	end := start + height
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+// renderEntry renders one log line with its graph, hash, refs, and subject
+func (m GitHistoryModel) renderEntry(e system.GitLogEntry, selected bool) string {
+	prefix := "  "
+	if selected {
+		prefix = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+	}
+	if e.Hash == "" {
+		return prefix + m.theme.DescriptionStyle.Render(e.Graph)
+	}
+	line := ""
+	if e.Graph != "" {
+		line = m.theme.DescriptionStyle.Render(e.Graph) + " "
+	}
+	line += m.theme.WarningStyle.Render(e.ShortHash()) + " "
+	if e.Refs != "" {
+		line += m.theme.SuccessStyle.Render("("+e.Refs+")") + " "
+	}
+	subject := e.Subject
+	if limit := m.theme.AppWidth - 40; limit > 20 && len([]rune(subject)) > limit {
+		subject = string([]rune(subject)[:limit-1]) + "…"
+	}
+	if selected {
+		line += m.theme.SelectedItem.Render(subject)
+	} else {
+		line += m.theme.MenuItem.Render(subject)
+	}
+	return prefix + line + m.theme.DescriptionStyle.Render(fmt.Sprintf("  %s, %s", e.Author, e.Date.Local().Format("2006-01-02")))
+}
+
+// View renders the history screen
+func (m GitHistoryModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	var sections []string
+	var help string
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	arrows := m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown
+
+	switch m.mode {
+	case "detail":
+		sections = append(sections,
+			m.theme.Title.Render("Commit "+system.GitLogEntry{Hash: m.detailHash}.ShortHash()),
+			"",
+		)
+		end := min(m.scroll+m.visibleLines(), len(m.detail))
+		for _, line := range m.detail[m.scroll:end] {
+			sections = append(sections, renderDiffLine(m.theme, line))
+		}
+		sections = append(sections, "", m.theme.DescriptionStyle.Render(fmt.Sprintf("Lines %d-%d of %d", m.scroll+1, end, len(m.detail))))
+		help = arrows + ": Scroll" + bullet + "PgUp/PgDn: Page" + bullet + "g/G: Top/Bottom" + bullet + "Esc: Back"
+
+	case "compare":
+		sections = append(sections,
+			m.theme.Title.Render("Compare with "+m.compare.Upstream),
+			m.theme.DescriptionStyle.Render(m.compare.Summary()+" (as of the last fetch)"),
+			"",
+		)
+		height := m.visibleLines() - len(m.compareStat)
+		start, end := m.window(len(m.compareList), m.compareCursor, max(height, 4))
+		for i := start; i < end; i++ {
+			if i == 0 && len(m.compare.Ahead) > 0 {
+				sections = append(sections, m.theme.Label.Render("Local commits not on "+m.compare.Upstream+":"))
+			}
+			if i == len(m.compare.Ahead) {
+				sections = append(sections, m.theme.Label.Render("Commits a pull would bring in:"))
+			}
+			sections = append(sections, m.renderEntry(m.compareList[i], i == m.compareCursor))
+		}
+		if len(m.compareStat) > 0 {
+			sections = append(sections, "")
+			for _, line := range m.compareStat {
+				sections = append(sections, m.theme.DescriptionStyle.Render(line))
+			}
+		}
+		help = arrows + ": Navigate" + bullet + "Enter: View commit" + bullet + "Esc: Back"
+
+	default:
+		scope := "Current branch"
+		if m.all {
+			scope = "All branches"
+		}
+		sections = append(sections,
+			m.theme.Title.Render("Git History"),
+			m.theme.DescriptionStyle.Render(scope+" in "+m.dir),
+			"",
+		)
+		if len(m.entries) == 0 && m.err == nil {
+			sections = append(sections, m.theme.DescriptionStyle.Render("No commits yet."))
+		}
+		start, end := m.window(len(m.entries), m.cursor, m.visibleLines())
+		for i := start; i < end; i++ {
+			sections = append(sections, m.renderEntry(m.entries[i], i == m.cursor))
+		}
+		toggle := "a: All branches"
+		if m.all {
+			toggle = "a: Current branch"
+		}
+		help = arrows + ": Navigate" + bullet + "Enter: View commit" + bullet + "c: Compare with origin" + bullet + toggle + bullet + "Esc: Back"
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/theme"
+	"github.com/iperamuna/ravact/internal/vault"
 )
 
 // GitState represents the current state of the git management screen
@@ -26,6 +27,9 @@ const (
 	GitStateConfirmRemote
 	GitStateGitOpForm
 	GitStateSetSystemUserForm
+	GitStateCloneRefForm
+	GitStateTokenForm
+	GitStateBuildStepForm
 )
 
 // GitInfo holds information about the current git repository
@@ -41,7 +45,16 @@ type GitInfo struct {
 	HasChanges       bool
 	Ahead            int
 	Behind           int
-	SystemUser       string // meta.systemuser config value
+	SystemUser       string           // meta.systemuser config value
+	DeployRef        string           // meta.deployref config value, e.g. refs/tags/v1.2.0
+	Build            system.BuildStep // meta.buildcommand and meta.precompress config values
+}
+
+// gitRefsMsg carries the branches and tags found by git ls-remote
+type gitRefsMsg struct {
+	refs []system.GitRef
+	head string
+	err  error
 }
 
 // GitAction represents a git action menu item
@@ -81,11 +94,25 @@ type GitManagementModel struct {
 	cloneUser string
 	cloneURL  string
 
+	// Ref, depth, and submodule choices for the clone
+	cloneRefForm    *huh.Form
+	cloneRefs       []system.GitRef
+	cloneHead       string // Default branch reported by the remote
+	cloneRefsErr    error
+	cloneRefLoading bool
+	cloneOpts       system.GitCloneOptions
+
 	// Form for git operations (pull, fetch, status, etc.)
 	gitOpForm   *huh.Form
 	gitOpUser   string
 	gitOpAction string
 
+	// Form for an HTTPS access token
+	tokenForm *huh.Form
+
+	// Form for the build step run after pulls
+	buildForm *huh.Form
+
 	// Form for setting system user
 	systemUserForm *huh.Form
 	systemUser     string
@@ -112,6 +139,7 @@ func NewGitManagementModel() GitManagementModel {
 
 	actions = append(actions, []GitAction{
 		{ID: "test_connection", Name: "Test Git Connection", Description: "Test SSH connection to GitHub/GitLab"},
+		{ID: "https_token", Name: "HTTPS Access Token", Description: "Store a personal access token for HTTPS remotes"},
 		{ID: "clone_repo", Name: "Clone Git Repo", Description: "Clone a repository into this directory"},
 		{ID: "add_remote", Name: "Add/Setup Git Remote", Description: "Add a new git remote URL"},
 		{ID: "change_remote", Name: "Change Remote URL", Description: "Update the remote URL"},
@@ -119,6 +147,8 @@ func NewGitManagementModel() GitManagementModel {
 		{ID: "git_pull", Name: "Git Pull", Description: "Pull latest changes from remote"},
 		{ID: "git_fetch", Name: "Git Fetch", Description: "Fetch changes from remote without merging"},
 		{ID: "git_status", Name: "Git Status", Description: "Show detailed git status"},
+		{ID: "git_history", Name: "Git History", Description: "Browse the log, commit diffs, and changes not yet pulled"},
+		{ID: "build_step", Name: "Build Step", Description: "Run a build (npm run build) and pre-compress assets after each pull"},
 		{ID: "set_system_user", Name: "Set System User", Description: "Set the user for git operations in this repo"},
 		{ID: "back", Name: "← Back to Site Commands", Description: "Return to site commands menu"},
 	}...)
@@ -228,6 +258,22 @@ func getGitInfo() GitInfo {
 		info.SystemUser = strings.TrimSpace(string(output))
 	}
 
+	// Get meta.deployref config, the branch or tag pulls follow
+	cmd = exec.Command("git", "config", "--get", "meta.deployref")
+	if output, err := cmd.Output(); err == nil {
+		info.DeployRef = strings.TrimSpace(string(output))
+	}
+
+	// Get the build step that runs after each pull
+	cmd = exec.Command("git", "config", "--get", "meta.buildcommand")
+	if output, err := cmd.Output(); err == nil {
+		info.Build.Command = strings.TrimSpace(string(output))
+	}
+	cmd = exec.Command("git", "config", "--get", "meta.precompress")
+	if output, err := cmd.Output(); err == nil {
+		info.Build.Precompress = strings.TrimSpace(string(output))
+	}
+
 	return info
 }
 
@@ -243,6 +289,15 @@ func (m GitManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, nil
+
+	case gitRefsMsg:
+		if m.state != GitStateCloneRefForm {
+			return m, nil
+		}
+		m.cloneRefLoading = false
+		m.cloneRefs, m.cloneHead, m.cloneRefsErr = msg.refs, msg.head, msg.err
+		m.cloneRefForm = m.buildCloneRefForm()
+		return m, m.cloneRefForm.Init()
 	}
 
 	// Handle different states
@@ -263,6 +318,12 @@ func (m GitManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateGitOpForm(msg)
 	case GitStateSetSystemUserForm:
 		return m.updateSetSystemUserForm(msg)
+	case GitStateCloneRefForm:
+		return m.updateCloneRefForm(msg)
+	case GitStateTokenForm:
+		return m.updateTokenForm(msg)
+	case GitStateBuildStepForm:
+		return m.updateBuildStepForm(msg)
 	}
 
 	return m, nil
@@ -344,9 +405,15 @@ func (m GitManagementModel) updateCloneForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.cloneUser = m.cloneForm.GetString("cloneUser")
 			m.cloneURL = m.cloneForm.GetString("cloneURL")
 
-			// Move to confirmation state
-			m.state = GitStateConfirmClone
-			return m, nil
+			// Look up the branches and tags to clone from
+			m.state = GitStateCloneRefForm
+			m.cloneRefLoading = true
+			m.cloneRefForm = nil
+			user, url := m.cloneUser, m.cloneURL
+			return m, func() tea.Msg {
+				refs, head, err := system.ListRemoteRefs(user, url)
+				return gitRefsMsg{refs: refs, head: head, err: err}
+			}
 		}
 
 		// Handle escape to cancel
@@ -367,6 +434,336 @@ func (m GitManagementModel) updateCloneForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateCloneRefForm handles the branch/tag, depth, and submodule form
+func (m GitManagementModel) updateCloneRefForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.state = GitStateMenu
+			m.cloneForm = nil
+			m.cloneRefForm = nil
+			return m, nil
+		}
+	}
+	if m.cloneRefForm == nil {
+		return m, nil
+	}
+
+	form, cmd := m.cloneRefForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.cloneRefForm = f
+	}
+	if m.cloneRefForm.State == huh.StateCompleted {
+		depth, _ := system.ParseCloneDepth(m.cloneRefForm.GetString("cloneDepth"))
+		m.cloneOpts = system.GitCloneOptions{Depth: depth, Submodules: m.cloneRefForm.GetBool("cloneSubmodules")}
+		if ref := m.cloneRefForm.GetString("cloneRef"); ref != "" {
+			m.cloneOpts.Ref = system.ParseDeployRef(ref)
+		}
+		m.state = GitStateConfirmClone
+		return m, nil
+	}
+	return m, cmd
+}
+
+// buildCloneRefForm creates the form choosing what to clone, listing the
+// remote's branches then tags
+func (m *GitManagementModel) buildCloneRefForm() *huh.Form {
+	defaultLabel := "Default branch"
+	if m.cloneHead != "" {
+		defaultLabel += " (" + m.cloneHead + ")"
+	}
+	refOptions := []huh.Option[string]{huh.NewOption(defaultLabel, "")}
+	for _, ref := range m.cloneRefs {
+		kind := "branch"
+		if ref.Tag {
+			kind = "tag"
+		}
+		refOptions = append(refOptions, huh.NewOption(fmt.Sprintf("%-6s %s", kind, ref.Name), ref.FullName()))
+	}
+
+	refDescription := "Later pulls follow this branch or tag"
+	if m.cloneRefsErr != nil {
+		refDescription = "Could not list branches and tags; the default branch will be cloned"
+	}
+
+	ref := ""
+	depth := ""
+	submodules := false
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("cloneRef").
+				Title("Branch or Tag").
+				Description(refDescription).
+				Options(refOptions...).
+				Height(12).
+				Value(&ref),
+
+			huh.NewInput().
+				Key("cloneDepth").
+				Title("Clone Depth").
+				Description("Number of commits to fetch for a shallow clone; empty for full history").
+				Placeholder("e.g. 1").
+				Validate(func(s string) error {
+					_, err := system.ParseCloneDepth(s)
+					return err
+				}).
+				Value(&depth),
+
+			huh.NewConfirm().
+				Key("cloneSubmodules").
+				Title("Clone Submodules?").
+				Description("Initialize and clone submodules recursively").
+				Affirmative("Yes").
+				Negative("No").
+				Value(&submodules),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// updateTokenForm handles the HTTPS access token form state
+func (m GitManagementModel) updateTokenForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.state = GitStateMenu
+			m.tokenForm = nil
+			return m, nil
+		}
+	}
+	if m.tokenForm == nil {
+		return m, nil
+	}
+
+	form, cmd := m.tokenForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.tokenForm = f
+	}
+	if m.tokenForm.State == huh.StateCompleted {
+		return m.saveToken()
+	}
+	return m, cmd
+}
+
+// buildTokenForm creates the form storing a personal access token for an
+// HTTPS git host
+func (m *GitManagementModel) buildTokenForm() *huh.Form {
+	var userOptions []huh.Option[string]
+	for _, user := range m.availableUsers {
+		userOptions = append(userOptions, huh.NewOption(user, user))
+	}
+
+	user := m.gitInfo.SystemUser
+	if user == "" && len(m.availableUsers) > 0 {
+		user = m.availableUsers[0]
+	}
+	host := "github.com"
+	if remoteHost, ok := system.HTTPSRemoteHost(m.gitInfo.RemoteURL); ok {
+		host = remoteHost
+	}
+	username := ""
+	token := ""
+	storage := "store"
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("tokenUser").
+				Title("Select User").
+				Description("The user that clones and pulls with this token").
+				Options(userOptions...).
+				Value(&user),
+
+			huh.NewInput().
+				Key("tokenHost").
+				Title("Git Host").
+				Placeholder("github.com").
+				Validate(func(s string) error {
+					return system.GitCredential{Host: strings.TrimSpace(s), Username: "-", Token: "-"}.Validate()
+				}).
+				Value(&host),
+
+			huh.NewInput().
+				Key("tokenUsername").
+				Title("Username").
+				Description("Leave empty for x-access-token (GitHub) or oauth2 (GitLab)").
+				Value(&username),
+
+			huh.NewInput().
+				Key("token").
+				Title("Personal Access Token").
+				Description("Needs read access to the repository contents").
+				EchoMode(huh.EchoModePassword).
+				Validate(func(s string) error {
+					return system.GitCredential{Host: "-", Username: "-", Token: strings.TrimSpace(s)}.Validate()
+				}).
+				Value(&token),
+
+			huh.NewSelect[string]().
+				Key("tokenStorage").
+				Title("Store Token In").
+				Options(
+					huh.NewOption("Git credential store on the server (needed for webhook deploys)", "store"),
+					huh.NewOption("Secrets vault only (used while the vault is unlocked)", "vault"),
+				).
+				Value(&storage),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// saveToken stores the token from the form in the user's git credential
+// store or the secrets vault
+func (m GitManagementModel) saveToken() (tea.Model, tea.Cmd) {
+	m.state = GitStateMenu
+	user := m.tokenForm.GetString("tokenUser")
+	c := system.GitCredential{
+		Host:     strings.TrimSpace(m.tokenForm.GetString("tokenHost")),
+		Username: strings.TrimSpace(m.tokenForm.GetString("tokenUsername")),
+		Token:    strings.TrimSpace(m.tokenForm.GetString("token")),
+	}
+	storage := m.tokenForm.GetString("tokenStorage")
+	m.tokenForm = nil
+	if c.Username == "" {
+		c.Username = system.DefaultGitTokenUser(c.Host)
+	}
+
+	if storage == "vault" {
+		if vault.Session() == nil {
+			m.err = fmt.Errorf("unlock the secrets vault first, or store the token on the server")
+			return m, nil
+		}
+		if err := c.Validate(); err != nil {
+			m.err = err
+			return m, nil
+		}
+		if _, err := vault.Record(system.GitTokenService, c.Host, c.Token, c.Username); err != nil {
+			m.err = fmt.Errorf("failed to save token to the secrets vault: %w", err)
+			return m, nil
+		}
+		m.success = fmt.Sprintf("✓ Token for %s saved to the secrets vault; clones and pulls use it while the vault is unlocked", c.Host)
+		return m, nil
+	}
+
+	if err := m.userManager.SaveGitCredential(user, c); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.success = fmt.Sprintf("✓ Token for %s stored in %s's git credential store", c.Host, user)
+	return m, nil
+}
+
+// vaultGitCredential returns shell lines that authenticate an HTTPS remote
+// with a token from the unlocked secrets vault. It returns nothing for SSH
+// remotes, a locked vault, or a host the user's credential store covers.
+func (m GitManagementModel) vaultGitCredential(user, remote string) (string, error) {
+	host, ok := system.HTTPSRemoteHost(remote)
+	v := vault.Session()
+	if !ok || v == nil {
+		return "", nil
+	}
+	for _, stored := range m.userManager.GitCredentialHosts(user) {
+		if stored == host {
+			return "", nil
+		}
+	}
+	for _, s := range v.Secrets {
+		if s.Service == system.GitTokenService && s.Account == host {
+			c := system.GitCredential{Host: host, Username: s.Note, Token: s.Value}
+			if c.Username == "" {
+				c.Username = system.DefaultGitTokenUser(host)
+			}
+			return m.userManager.WriteTempGitCredential(user, c)
+		}
+	}
+	return "", nil
+}
+
+// updateBuildStepForm handles the build step form state
+func (m GitManagementModel) updateBuildStepForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.state = GitStateMenu
+			m.buildForm = nil
+			return m, nil
+		}
+	}
+	if m.buildForm == nil {
+		return m, nil
+	}
+
+	form, cmd := m.buildForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.buildForm = f
+	}
+	if m.buildForm.State == huh.StateCompleted {
+		return m.saveBuildStep()
+	}
+	return m, cmd
+}
+
+// buildBuildStepForm creates the form for the command and pre-compressed
+// output directory of the build step
+func (m *GitManagementModel) buildBuildStepForm() *huh.Form {
+	command := m.gitInfo.Build.Command
+	precompress := m.gitInfo.Build.Precompress
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("buildCommand").
+				Title("Build Command").
+				Description("Leave empty to only pull").
+				Placeholder("npm ci && npm run build").
+				Value(&command),
+
+			huh.NewInput().
+				Key("precompress").
+				Title("Pre-compress Directory").
+				Description("Build output whose assets get .gz and .br copies for gzip_static; empty to skip").
+				Placeholder("dist").
+				Validate(func(s string) error {
+					return system.BuildStep{Precompress: s}.Validate()
+				}).
+				Value(&precompress),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// saveBuildStep records the build step in the repository's git config
+func (m GitManagementModel) saveBuildStep() (tea.Model, tea.Cmd) {
+	m.state = GitStateMenu
+	step := system.BuildStep{
+		Command:     strings.TrimSpace(m.buildForm.GetString("buildCommand")),
+		Precompress: strings.TrimSpace(m.buildForm.GetString("precompress")),
+	}
+	m.buildForm = nil
+
+	if err := step.Save(m.currentDir); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.gitInfo = getGitInfo()
+	if step.Command == "" && step.Precompress == "" {
+		m.success = "✓ Build step removed"
+	} else {
+		m.success = "✓ Build step saved; it runs after each pull"
+	}
+	return m, nil
+}
+
 // updateConfirmClone handles the clone confirmation state
 func (m GitManagementModel) updateConfirmClone(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -377,6 +774,7 @@ func (m GitManagementModel) updateConfirmClone(msg tea.Msg) (tea.Model, tea.Cmd)
 		case "esc", "backspace", "n", "N":
 			m.state = GitStateMenu
 			m.cloneForm = nil
+			m.cloneRefForm = nil
 			return m, nil
 		case "y", "Y", "enter":
 			// Check folder permissions and change if needed, then clone
@@ -569,8 +967,16 @@ func (m GitManagementModel) executeGitOp() (tea.Model, tea.Cmd) {
 
 	switch m.gitOpAction {
 	case "git_pull":
-		gitCmd = "git pull"
+		// Follow the branch or tag chosen when the repository was cloned,
+		// then build
+		gitCmd = system.GitDeployCommand(m.gitInfo.DeployRef)
+		if build := m.gitInfo.Build.Script(); build != "" {
+			gitCmd += " && " + build
+		}
 		description = "Pulling latest changes"
+		if m.gitInfo.DeployRef != "" {
+			description = "Updating to " + system.ParseDeployRef(m.gitInfo.DeployRef).Name
+		}
 	case "git_fetch":
 		gitCmd = "git fetch --all"
 		description = "Fetching from all remotes"
@@ -618,6 +1024,14 @@ EOF
 		return m, nil
 	}
 
+	credentials, err := m.vaultGitCredential(m.gitOpUser, m.gitInfo.RemoteURL)
+	if err != nil {
+		m.state = GitStateMenu
+		m.err = err
+		m.gitOpForm = nil
+		return m, nil
+	}
+
 	// For pull, fetch, status - build script with ssh-agent
 	script := fmt.Sprintf(`
 echo "Running git %s as user %s..."
@@ -625,7 +1039,7 @@ echo ""
 
 sudo -i -u %s bash << 'EOF'
 cd "%s"
-
+%s
 # Start ssh-agent
 eval $(ssh-agent -s) > /dev/null 2>&1
 
@@ -660,7 +1074,7 @@ ssh-agent -k > /dev/null 2>&1 || true
 
 exit $EXIT_CODE
 EOF
-`, m.gitOpAction, m.gitOpUser, m.gitOpUser, m.currentDir, gitCmd, gitCmd)
+`, m.gitOpAction, m.gitOpUser, m.gitOpUser, m.currentDir, credentials, gitCmd, gitCmd)
 
 	m.state = GitStateMenu
 	m.gitOpForm = nil
@@ -923,6 +1337,27 @@ func (m GitManagementModel) executeClone() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// The heredoc runs in the user's login shell, so the clone command is
+	// written into it rather than read from the variables below
+	cloneCmd := "git clone --progress"
+	for _, arg := range m.cloneOpts.CloneArgs() {
+		cloneCmd += " " + system.ShellQuote(arg)
+	}
+	cloneCmd += " -- " + system.ShellQuote(m.cloneURL) + " ."
+	credentials, err := m.vaultGitCredential(m.cloneUser, m.cloneURL)
+	if err != nil {
+		m.state = GitStateMenu
+		m.err = err
+		m.cloneForm = nil
+		return m, nil
+	}
+	refName := "default branch"
+	deployRef := ""
+	if m.cloneOpts.Ref.Name != "" {
+		refName = m.cloneOpts.Ref.Name
+		deployRef = m.cloneOpts.Ref.FullName()
+	}
+
 	// Build a script that starts ssh-agent, adds the keys, and clones the repo
 	// After cloning, set proper permissions for web server access
 	// Run the clone as the specified user
@@ -934,12 +1369,13 @@ echo ""
 echo "  Repository:  %s"
 echo "  Directory:   %s"
 echo "  User:        %s"
+echo "  Ref:         %s"
 echo ""
 echo "══════════════════════════════════════════════════════════"
 
 TARGET_DIR="%s"
 CLONE_USER="%s"
-CLONE_URL="%s"
+DEPLOY_REF=%s
 
 # Ensure target directory exists
 if [ ! -d "$TARGET_DIR" ]; then
@@ -952,8 +1388,8 @@ echo ""
 echo "  [1/4] Setting up SSH authentication..."
 
 sudo -i -u "$CLONE_USER" bash << 'EOF'
-cd "$TARGET_DIR"
-
+cd %s
+%s
 # Start ssh-agent
 eval $(ssh-agent -s) > /dev/null 2>&1
 
@@ -978,7 +1414,7 @@ echo ""
 echo "  [2/4] Cloning repository..."
 echo ""
 
-git clone --progress "$CLONE_URL" . 2>&1
+%s 2>&1
 CLONE_EXIT=$?
 
 ssh-agent -k > /dev/null 2>&1 || true
@@ -993,7 +1429,7 @@ if [ $CLONE_EXIT -eq 0 ]; then
     echo ""
     echo "  [3/4] Setting ownership..."
     
-    WEB_GROUP="www-data"
+    WEB_GROUP=%s
     if getent group "$WEB_GROUP" > /dev/null 2>&1; then
         chown -R "$CLONE_USER:$WEB_GROUP" "$TARGET_DIR"
         echo "        ✓ Ownership set to $CLONE_USER:$WEB_GROUP"
@@ -1030,6 +1466,10 @@ if [ $CLONE_EXIT -eq 0 ]; then
     cd "$TARGET_DIR"
     git config meta.systemuser "$CLONE_USER"
     echo "        ✓ System user set to '$CLONE_USER'"
+    if [ -n "$DEPLOY_REF" ]; then
+        git config meta.deployref "$DEPLOY_REF"
+        echo "        ✓ Pulls will follow $DEPLOY_REF"
+    fi
     
     echo ""
     echo "══════════════════════════════════════════════════════════"
@@ -1044,7 +1484,8 @@ else
     echo ""
     exit $CLONE_EXIT
 fi
-`, m.cloneURL, m.currentDir, m.cloneUser, m.currentDir, m.cloneUser, m.cloneURL)
+`, m.cloneURL, m.currentDir, m.cloneUser, refName, m.currentDir, m.cloneUser, system.ShellQuote(deployRef),
+		system.ShellQuote(m.currentDir), credentials, cloneCmd, system.ShellQuote(system.WebGroup))
 
 	m.state = GitStateMenu
 	m.cloneForm = nil
@@ -1219,6 +1660,15 @@ func (m GitManagementModel) executeAction() (tea.Model, tea.Cmd) {
 		m.testForm = m.buildTestConnectionForm()
 		return m, m.testForm.Init()
 
+	case "https_token":
+		if len(m.availableUsers) == 0 {
+			m.err = fmt.Errorf("no users available")
+			return m, nil
+		}
+		m.state = GitStateTokenForm
+		m.tokenForm = m.buildTokenForm()
+		return m, m.tokenForm.Init()
+
 	case "clone_repo":
 		// Check if already a git repo
 		if m.gitInfo.IsRepo {
@@ -1325,6 +1775,24 @@ func (m GitManagementModel) executeAction() (tea.Model, tea.Cmd) {
 		m.systemUserForm = m.buildSetSystemUserForm()
 		return m, m.systemUserForm.Init()
 
+	case "git_history":
+		if !m.gitInfo.IsRepo {
+			m.err = fmt.Errorf("not a git repository")
+			return m, nil
+		}
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: GitHistoryScreen}
+		}
+
+	case "build_step":
+		if !m.gitInfo.IsRepo {
+			m.err = fmt.Errorf("not a git repository")
+			return m, nil
+		}
+		m.state = GitStateBuildStepForm
+		m.buildForm = m.buildBuildStepForm()
+		return m, m.buildForm.Init()
+
 	case "set_system_user":
 		if !m.gitInfo.IsRepo {
 			m.err = fmt.Errorf("not a git repository")
@@ -1369,6 +1837,12 @@ func (m GitManagementModel) View() string {
 		return m.renderGitOpForm()
 	case GitStateSetSystemUserForm:
 		return m.renderSetSystemUserForm()
+	case GitStateCloneRefForm:
+		return m.renderCloneRefForm()
+	case GitStateTokenForm:
+		return m.renderTokenForm()
+	case GitStateBuildStepForm:
+		return m.renderBuildStepForm()
 	default:
 		return m.renderMenu()
 	}
@@ -1443,6 +1917,24 @@ func (m GitManagementModel) renderMenu() string {
 		} else {
 			infoLines = append(infoLines, sysUserLabel+m.theme.WarningStyle.Render("Not set (will prompt on git operations)"))
 		}
+
+		// Deploy ref
+		if m.gitInfo.DeployRef != "" {
+			ref := system.ParseDeployRef(m.gitInfo.DeployRef)
+			refValue := ref.Name
+			if ref.Tag {
+				refValue = "tag " + refValue
+			}
+			infoLines = append(infoLines, m.theme.Label.Render("Deploys: ")+m.theme.InfoStyle.Render(refValue))
+		}
+
+		// Build step
+		if m.gitInfo.Build.Command != "" {
+			infoLines = append(infoLines, m.theme.Label.Render("Build: ")+m.theme.InfoStyle.Render(m.gitInfo.Build.Command))
+		}
+		if m.gitInfo.Build.Precompress != "" {
+			infoLines = append(infoLines, m.theme.Label.Render("Pre-compress: ")+m.theme.InfoStyle.Render(m.gitInfo.Build.Precompress))
+		}
 	}
 
 	infoSection := lipgloss.JoinVertical(lipgloss.Left, infoLines...)
@@ -1604,6 +2096,60 @@ func (m GitManagementModel) renderCloneForm() string {
 	)
 }
 
+// renderCloneRefForm renders the branch/tag selection form
+func (m GitManagementModel) renderCloneRefForm() string {
+	header := m.theme.Title.Render("Clone Git Repository")
+	repoInfo := m.theme.Label.Render("Repository: ") + m.theme.InfoStyle.Render(m.cloneURL)
+
+	var body string
+	switch {
+	case m.cloneRefLoading:
+		body = m.theme.InfoStyle.Render("Listing branches and tags with git ls-remote...")
+	case m.cloneRefForm != nil:
+		body = m.cloneRefForm.View()
+		if m.cloneRefsErr != nil {
+			body = lipgloss.JoinVertical(lipgloss.Left, m.theme.WarningStyle.Render("⚠ "+m.cloneRefsErr.Error()), "", body)
+		}
+	}
+
+	help := m.theme.Help.Render("Tab: Next • Enter: Submit • Esc: Cancel")
+	content := lipgloss.JoinVertical(lipgloss.Left, header, "", repoInfo, "", body, "", help)
+	paddedContent := lipgloss.NewStyle().Padding(1, 4).Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(paddedContent))
+}
+
+// renderTokenForm renders the HTTPS access token form
+func (m GitManagementModel) renderTokenForm() string {
+	header := m.theme.Title.Render("HTTPS Access Token")
+	description := m.theme.DescriptionStyle.Render("Lets clones and pulls of https:// remotes authenticate where outbound SSH is blocked.\nThe token is never shown in commands or output.")
+
+	formView := ""
+	if m.tokenForm != nil {
+		formView = m.tokenForm.View()
+	}
+
+	help := m.theme.Help.Render("Tab: Next • Enter: Submit • Esc: Cancel")
+	content := lipgloss.JoinVertical(lipgloss.Left, header, "", description, "", formView, "", help)
+	paddedContent := lipgloss.NewStyle().Padding(1, 4).Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(paddedContent))
+}
+
+// renderBuildStepForm renders the build step form
+func (m GitManagementModel) renderBuildStepForm() string {
+	header := m.theme.Title.Render("Build Step")
+	description := m.theme.DescriptionStyle.Render("Runs after Git Pull and webhook deploys, as the pulling user, in the repository.\nSites with a deploy hook run that hook instead.")
+
+	formView := ""
+	if m.buildForm != nil {
+		formView = m.buildForm.View()
+	}
+
+	help := m.theme.Help.Render("Tab: Next • Enter: Submit • Esc: Cancel")
+	content := lipgloss.JoinVertical(lipgloss.Left, header, "", description, "", formView, "", help)
+	paddedContent := lipgloss.NewStyle().Padding(1, 4).Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(paddedContent))
+}
+
 // renderConfirmClone renders the clone confirmation screen
 func (m GitManagementModel) renderConfirmClone() string {
 	header := m.theme.Title.Render("Confirm Git Clone")
@@ -1612,7 +2158,7 @@ func (m GitManagementModel) renderConfirmClone() string {
 	statCmd := exec.Command("stat", "-c", "%U:%G", m.currentDir)
 	statOutput, _ := statCmd.Output()
 	currentOwner := strings.TrimSpace(string(statOutput))
-	expectedOwner := fmt.Sprintf("%s:www-data", m.cloneUser)
+	expectedOwner := fmt.Sprintf("%s:%s", m.cloneUser, system.WebGroup)
 	needsOwnershipChange := currentOwner != expectedOwner && currentOwner != ""
 
 	// Summary
@@ -1620,6 +2166,22 @@ func (m GitManagementModel) renderConfirmClone() string {
 	summaryLines = append(summaryLines, m.theme.Label.Render("Directory:   ")+m.theme.InfoStyle.Render(m.currentDir))
 	summaryLines = append(summaryLines, m.theme.Label.Render("User:        ")+m.theme.InfoStyle.Render(m.cloneUser))
 	summaryLines = append(summaryLines, m.theme.Label.Render("Repository:  ")+m.theme.SuccessStyle.Render(m.cloneURL))
+	refValue := "default branch"
+	if m.cloneOpts.Ref.Name != "" {
+		refValue = m.cloneOpts.Ref.Name
+		if m.cloneOpts.Ref.Tag {
+			refValue = "tag " + refValue
+		}
+	}
+	summaryLines = append(summaryLines, m.theme.Label.Render("Ref:         ")+m.theme.InfoStyle.Render(refValue))
+	depthValue := "full history"
+	if m.cloneOpts.Depth > 0 {
+		depthValue = fmt.Sprintf("shallow, %d commit(s)", m.cloneOpts.Depth)
+	}
+	summaryLines = append(summaryLines, m.theme.Label.Render("Depth:       ")+m.theme.InfoStyle.Render(depthValue))
+	if m.cloneOpts.Submodules {
+		summaryLines = append(summaryLines, m.theme.Label.Render("Submodules:  ")+m.theme.InfoStyle.Render("cloned recursively"))
+	}
 
 	if needsOwnershipChange {
 		summaryLines = append(summaryLines, "")
@@ -1630,7 +2192,7 @@ func (m GitManagementModel) renderConfirmClone() string {
 
 	var warning string
 	if needsOwnershipChange {
-		warning = m.theme.WarningStyle.Render("\n⚠ Directory ownership will be changed to " + m.cloneUser + ":www-data\n  for web server compatibility.")
+		warning = m.theme.WarningStyle.Render("\n⚠ Directory ownership will be changed to " + expectedOwner + "\n  for web server compatibility.")
 	} else {
 		warning = m.theme.WarningStyle.Render("\n⚠ This will clone the repository contents into the current directory.")
 	}
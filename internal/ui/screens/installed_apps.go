@@ -4,6 +4,8 @@ import (
 	"embed"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -19,16 +21,23 @@ type InstalledApp struct {
 	Status models.ServiceStatus
 }
 
+// installedAppsMsg carries the detected apps of the installed apps screen
+type installedAppsMsg struct {
+	apps []InstalledApp
+}
+
 // InstalledAppsModel represents the installed applications screen
 type InstalledAppsModel struct {
 	theme           *theme.Theme
 	width           int
 	height          int
 	cursor          int
+	scripts         []models.SetupScript
 	installedApps   []InstalledApp
 	detector        *system.Detector
 	executor        *setup.Executor
 	loading         bool
+	loadStarted     time.Time
 }
 
 // NewInstalledAppsModel creates a new installed apps model
@@ -89,10 +98,26 @@ func NewInstalledAppsModel(scriptsDir string) InstalledAppsModel {
 			scripts[i].Name = "Redis Cache"
 			scripts[i].Description = "In-memory data structure store and cache"
 			scripts[i].ServiceID = "redis-server"
+		case "pgbouncer":
+			scripts[i].Name = "pgBouncer"
+			scripts[i].Description = "Connection pooler for PostgreSQL"
+			scripts[i].ServiceID = "pgbouncer"
 		case "dragonfly":
 			scripts[i].Name = "Dragonfly"
 			scripts[i].Description = "Modern Redis/Memcached replacement (faster, less memory)"
 			scripts[i].ServiceID = "dragonfly"
+		case "docker":
+			scripts[i].Name = "Docker"
+			scripts[i].Description = "Docker Engine and Compose for sidecar containers"
+			scripts[i].ServiceID = "docker"
+		case "meilisearch":
+			scripts[i].Name = "Meilisearch"
+			scripts[i].Description = "Search engine for Laravel Scout"
+			scripts[i].ServiceID = "meilisearch"
+		case "netdata":
+			scripts[i].Name = "Netdata"
+			scripts[i].Description = "Real-time server graphs without a Prometheus stack"
+			scripts[i].ServiceID = "netdata"
 		case "php":
 			scripts[i].Name = "PHP"
 			scripts[i].Description = "PHP versions and extensions management"
@@ -113,36 +138,69 @@ func NewInstalledAppsModel(scriptsDir string) InstalledAppsModel {
 			scripts[i].Name = "Firewall (UFW/firewalld)"
 			scripts[i].Description = "Configure firewall with common rules"
 			scripts[i].ServiceID = "ufw"
+		case "exporters":
+			scripts[i].Name = "Prometheus Exporters"
+			scripts[i].Description = "node, nginx, MySQL, and PHP-FPM exporters scraped from one source"
+			scripts[i].ServiceID = "node_exporter"
 		}
 	}
 
-	// Filter only installed apps
-	var installedApps []InstalledApp
-	for _, script := range scripts {
-		if script.ServiceID != "" {
-			status, _ := detector.GetServiceStatus(script.ServiceID)
-			// Only include if installed (not StatusNotInstalled or StatusUnknown)
-			if status != models.StatusNotInstalled && status != models.StatusUnknown {
-				installedApps = append(installedApps, InstalledApp{
-					Script: script,
-					Status: status,
-				})
+	return InstalledAppsModel{
+		theme:       theme.DefaultTheme(),
+		cursor:      0,
+		scripts:     scripts,
+		detector:    detector,
+		executor:    executor,
+		loading:     true,
+		loadStarted: time.Now(),
+	}
+}
+
+// Init detects the installed apps in the background; statuses seen within
+// system.StatusTTL come from the cache
+func (m InstalledAppsModel) Init() tea.Cmd {
+	return tea.Batch(loadInstalledApps(m.scripts, m.detector), spinnerTick())
+}
+
+// loadInstalledApps checks every script's service side by side
+func loadInstalledApps(scripts []models.SetupScript, detector *system.Detector) tea.Cmd {
+	return func() tea.Msg {
+		statuses := make([]models.ServiceStatus, len(scripts))
+		var wg sync.WaitGroup
+		for i, script := range scripts {
+			if script.ServiceID == "" {
+				continue
 			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				statuses[i] = detector.CachedServiceStatus(script.ServiceID)
+			}()
 		}
-	}
+		wg.Wait()
 
-	return InstalledAppsModel{
-		theme:         theme.DefaultTheme(),
-		cursor:        0,
-		installedApps: installedApps,
-		detector:      detector,
-		executor:      executor,
+		// Only include if installed (not StatusNotInstalled or StatusUnknown)
+		var apps []InstalledApp
+		for i, script := range scripts {
+			if script.ServiceID == "" || statuses[i] == "" {
+				continue
+			}
+			if statuses[i] != models.StatusNotInstalled && statuses[i] != models.StatusUnknown {
+				apps = append(apps, InstalledApp{Script: script, Status: statuses[i]})
+			}
+		}
+		return installedAppsMsg{apps: apps}
 	}
 }
 
-// Init initializes the installed apps screen
-func (m InstalledAppsModel) Init() tea.Cmd {
-	return nil
+// startLoading marks the apps as loading and detects them again
+func (m InstalledAppsModel) startLoading() (InstalledAppsModel, tea.Cmd) {
+	if m.loading {
+		return m, nil
+	}
+	m.loading = true
+	m.loadStarted = time.Now()
+	return m, m.Init()
 }
 
 // Update handles messages for the installed apps screen
@@ -153,6 +211,20 @@ func (m InstalledAppsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case installedAppsMsg:
+		m.loading = false
+		m.installedApps = msg.apps
+		if m.cursor >= len(m.installedApps) {
+			m.cursor = 0
+		}
+		return m, nil
+
+	case SpinnerTickMsg:
+		if m.loading {
+			return m, spinnerTick()
+		}
+		return m, nil
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q":
@@ -189,14 +261,9 @@ func (m InstalledAppsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "r":
-			// Refresh status
-			if len(m.installedApps) > 0 {
-				selectedApp := &m.installedApps[m.cursor]
-				if selectedApp.Script.ServiceID != "" {
-					status, _ := m.detector.GetServiceStatus(selectedApp.Script.ServiceID)
-					selectedApp.Status = status
-				}
-			}
+			// Refresh every status, skipping the cache
+			system.InvalidateServiceStatus()
+			return m.startLoading()
 		}
 	}
 
@@ -221,9 +288,15 @@ func (m InstalledAppsModel) View() string {
 	// Summary
 	summary := m.theme.InfoStyle.Render(fmt.Sprintf("Found %d installed applications", len(m.installedApps)))
 
+	if m.loading {
+		summary = m.theme.InfoStyle.Render(spinnerFrame(m.theme, m.loadStarted) + " Checking installed applications...")
+	}
+
 	// App items
 	var appItems []string
-	if len(m.installedApps) == 0 {
+	if m.loading && len(m.installedApps) == 0 {
+		appItems = append(appItems, m.theme.DescriptionStyle.Render("Asking systemctl about each service"))
+	} else if len(m.installedApps) == 0 {
 		noApps := m.theme.WarningStyle.Render("No applications installed yet")
 		appItems = append(appItems, noApps)
 		appItems = append(appItems, "")
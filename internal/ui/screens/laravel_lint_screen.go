@@ -0,0 +1,259 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// LaravelLintModel checks a Laravel app for production readiness
+type LaravelLintModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+	cursor int
+
+	projectPath string
+	systemUser  string
+	env         *system.EnvFile
+	checks      []system.LaravelCheck
+
+	confirm    Confirmation
+	confirming bool
+	fixing     []system.LaravelCheck
+
+	err     error
+	success string
+}
+
+// NewLaravelLintModel runs the production checks for the Laravel app in
+// the current directory
+func NewLaravelLintModel() LaravelLintModel {
+	cwd, _ := os.Getwd()
+	m := LaravelLintModel{
+		theme:       theme.DefaultTheme(),
+		projectPath: cwd,
+		systemUser:  getGitSystemUser(),
+	}
+	m.lint()
+	return m
+}
+
+// lint reads the .env and caches and runs the checks
+func (m *LaravelLintModel) lint() {
+	if !isLaravelProject(m.projectPath) {
+		m.err = fmt.Errorf("not a Laravel project: %s", m.projectPath)
+		return
+	}
+	env, err := system.ParseEnvFile(filepath.Join(m.projectPath, ".env"))
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.env = env
+	m.checks = system.LintLaravel(env, system.DetectLaravelCaches(m.projectPath))
+}
+
+// Init initializes the production checks screen
+func (m LaravelLintModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the production checks screen
+func (m LaravelLintModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.confirming {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.confirm, result = m.confirm.Update(msg)
+			switch result {
+			case ConfirmAccepted:
+				m.confirming = false
+				return m.applyFixes()
+			case ConfirmCancelled:
+				m.confirming = false
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: SiteCommandsScreen}
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.checks)-1 {
+				m.cursor++
+			}
+		case "r":
+			m.err = nil
+			m.success = ""
+			m.lint()
+		case "f", "enter":
+			if m.cursor < len(m.checks) && m.checks[m.cursor].Fixable() {
+				m.askFix([]system.LaravelCheck{m.checks[m.cursor]})
+			}
+		case "F":
+			var fixable []system.LaravelCheck
+			for _, c := range m.checks {
+				if c.Fixable() {
+					fixable = append(fixable, c)
+				}
+			}
+			if len(fixable) > 0 {
+				m.askFix(fixable)
+			}
+		}
+	}
+	return m, nil
+}
+
+// askFix lists the changes that fix the checks and asks for confirmation
+func (m *LaravelLintModel) askFix(checks []system.LaravelCheck) {
+	var lines []string
+	for _, c := range checks {
+		if c.EnvKey != "" {
+			lines = append(lines, fmt.Sprintf("Set %s=%s in .env", c.EnvKey, c.EnvValue))
+		}
+	}
+	if script := system.LaravelFixScript(checks); script != "" {
+		lines = append(lines, strings.Split(script, " && ")...)
+	}
+	title := "Fix " + checks[0].Title
+	if len(checks) > 1 {
+		title = fmt.Sprintf("Fix %d Checks", len(checks))
+	}
+	m.fixing = checks
+	m.confirm = NewConfirmation("fix", title,
+		strings.Join(lines, "\n")+"\n\n.env is backed up before it is changed.", ConfirmNormal)
+	m.confirming = true
+}
+
+// applyFixes writes the .env changes and runs the artisan commands as the
+// site's system user
+func (m LaravelLintModel) applyFixes() (tea.Model, tea.Cmd) {
+	m.err = nil
+	m.success = ""
+
+	changed := false
+	for _, c := range m.fixing {
+		if c.EnvKey != "" {
+			m.env.Set(c.EnvKey, c.EnvValue)
+			changed = true
+		}
+	}
+	if changed {
+		backup, err := m.env.Save()
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.success = fmt.Sprintf("%s Updated .env (backup: %s)", m.theme.Symbols.CheckMark, filepath.Base(backup))
+	}
+
+	script := system.LaravelFixScript(m.fixing)
+	if script == "" {
+		m.lint()
+		return m, nil
+	}
+	if m.systemUser != "" {
+		script = fmt.Sprintf("sudo -i -u %s bash << 'EOF'\ncd %s\n%s\nEOF\n", m.systemUser, system.ShellQuote(m.projectPath), script)
+	} else {
+		script = fmt.Sprintf("cd %s && %s", system.ShellQuote(m.projectPath), script)
+	}
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{Command: script, Description: "Fixing production checks"}
+	}
+}
+
+// View renders the production checks screen
+func (m LaravelLintModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Production Readiness"),
+		m.theme.DescriptionStyle.Render(m.projectPath),
+		"",
+	}
+
+	failed, fixable := 0, 0
+	for i, c := range m.checks {
+		icon := m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark)
+		if !c.Passed {
+			failed++
+			if c.Severity == "error" {
+				icon = m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark)
+			} else {
+				icon = m.theme.WarningStyle.Render(m.theme.Symbols.Warning)
+			}
+		}
+		style := m.theme.MenuItem
+		cursor := "  "
+		if i == m.cursor {
+			style = m.theme.SelectedItem
+			cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+		}
+		line := cursor + icon + " " + style.Render(c.Title)
+		if c.Fixable() {
+			fixable++
+			line += m.theme.DescriptionStyle.Render("  [fixable]")
+		}
+		sections = append(sections, line)
+		if !c.Passed && i == m.cursor {
+			sections = append(sections, "    "+m.theme.DescriptionStyle.Render(c.Detail))
+		}
+	}
+
+	if len(m.checks) > 0 {
+		sections = append(sections, "")
+		if failed == 0 {
+			sections = append(sections, m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark+" Ready for production"))
+		} else {
+			sections = append(sections, m.theme.WarningStyle.Render(fmt.Sprintf("%d of %d checks need attention", failed, len(m.checks))))
+		}
+	}
+
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Select" + bullet + "f: Fix"
+	if fixable > 1 {
+		help += bullet + "F: Fix all"
+	}
+	help += bullet + "r: Re-check" + bullet + "Esc: Back"
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -44,6 +44,14 @@ type LaravelPermissionsModel struct {
 	availableUsers []string
 	selectingUser  bool
 
+	// POSIX ACL support on the project's filesystem
+	aclSupported bool
+	aclReason    string // Why ACLs cannot be used
+	viewingACL   bool
+	aclDirs      []string
+	aclEntries   map[string][]system.ACLEntry
+	aclErrs      map[string]error
+
 	// Storage Link state
 	storageLinkState string // "", "prompt"
 	pendingAction    LaravelPermAction
@@ -95,6 +103,9 @@ func NewLaravelPermissionsModel() LaravelPermissionsModel {
 		systemUser:     systemUser,
 		availableUsers: availableUsers,
 	}
+	if isLaravel {
+		m.aclSupported, m.aclReason = system.ACLSupport(filepath.Join(cwd, "storage"))
+	}
 
 	// Actions will be built in View or Update once user is confirmed
 	m.actions = m.buildActions()
@@ -128,6 +139,12 @@ func (m *LaravelPermissionsModel) buildActions() []LaravelPermAction {
 			Description: "Set storage & bootstrap/cache writable by web server",
 			Command:     fmt.Sprintf("sudo chgrp -R %s storage bootstrap/cache && sudo chmod -R ug+rwx storage bootstrap/cache", webUser),
 		},
+		{
+			ID:          "storage_acl",
+			Name:        "Make Storage Writable (ACL)",
+			Description: "Grant the web and owner users rwX with setfacl default ACLs, keeping file ownership",
+			Command:     "sudo bash -c " + system.ShellQuote(system.WritableACLCommand([]string{webUser, m.systemUser}, "storage", "bootstrap/cache")),
+		},
 		{
 			ID:          "full_reset",
 			Name:        "Full Permission Reset",
@@ -178,6 +195,11 @@ sudo chmod -R ug+rwx storage bootstrap/cache`, ownerUser, webUser, webUser, owne
 			Description: "Display permissions for key directories",
 			Command:     "echo '=== Storage ===' && ls -la storage/ && echo '' && echo '=== Bootstrap/Cache ===' && ls -la bootstrap/cache/ && echo '' && echo '=== .env ===' && ls -la .env 2>/dev/null || echo '.env not found'",
 		},
+		{
+			ID:          "show_acl",
+			Name:        "Show ACL Entries",
+			Description: "Display the POSIX ACLs on storage and bootstrap/cache",
+		},
 		{
 			ID:          "create_env",
 			Name:        "Create .env from .env.example",
@@ -294,6 +316,16 @@ func (m LaravelPermissionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.viewingACL {
+			switch msg.String() {
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			case "esc", "backspace", "enter":
+				m.viewingACL = false
+			}
+			return m, nil
+		}
+
 		// Handle scheduler form
 		if m.isScheduler && m.schedulerForm != nil {
 			form, cmd := m.schedulerForm.Update(msg)
@@ -546,8 +578,20 @@ func (m LaravelPermissionsModel) executeAction() (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// ACLs need setfacl and a filesystem that supports them
+	if action.ID == "storage_acl" && !m.aclSupported {
+		m.err = fmt.Errorf("ACLs are not available here: %s", m.aclReason)
+		return m, nil
+	}
+
+	if action.ID == "show_acl" {
+		m.loadACLs()
+		m.viewingACL = true
+		return m, nil
+	}
+
 	// Check for storage link on permission/reset actions
-	if (action.ID == "standard" || action.ID == "full_reset" || action.ID == "storage_writable") && !isStorageLinked(m.projectPath) {
+	if (action.ID == "standard" || action.ID == "full_reset" || action.ID == "storage_writable" || action.ID == "storage_acl") && !isStorageLinked(m.projectPath) {
 		m.storageLinkState = "prompt"
 		m.pendingAction = action
 		return m, nil
@@ -620,6 +664,10 @@ func (m LaravelPermissionsModel) View() string {
 		return m.viewSchedulerForm()
 	}
 
+	if m.viewingACL {
+		return m.viewACLs()
+	}
+
 	// Handle env selection states
 	if m.envState == "select_env" {
 		return m.viewEnvSelection()
@@ -656,6 +704,13 @@ func (m LaravelPermissionsModel) View() string {
 		infoLines = append(infoLines, m.theme.Label.Render("Owner User: ")+m.theme.WarningStyle.Render("$USER")+" (set via Git → Set System User)")
 	}
 	infoLines = append(infoLines, m.theme.Label.Render("Path: ")+m.theme.DescriptionStyle.Render(m.projectPath))
+	if m.isLaravel {
+		if m.aclSupported {
+			infoLines = append(infoLines, m.theme.Label.Render("ACLs: ")+m.theme.SuccessStyle.Render("supported"))
+		} else {
+			infoLines = append(infoLines, m.theme.Label.Render("ACLs: ")+m.theme.WarningStyle.Render("unavailable")+" ("+m.aclReason+")")
+		}
+	}
 
 	infoSection := lipgloss.JoinVertical(lipgloss.Left, infoLines...)
 
@@ -921,3 +976,48 @@ EOF
 		}
 	}
 }
+
+// loadACLs reads the ACL entries of the directories Laravel writes to
+func (m *LaravelPermissionsModel) loadACLs() {
+	m.aclDirs = []string{"storage", "bootstrap/cache"}
+	m.aclEntries = map[string][]system.ACLEntry{}
+	m.aclErrs = map[string]error{}
+	for _, dir := range m.aclDirs {
+		entries, err := system.GetACL(filepath.Join(m.projectPath, dir))
+		m.aclEntries[dir] = entries
+		m.aclErrs[dir] = err
+	}
+}
+
+// viewACLs renders the ACL entries of storage and bootstrap/cache, with the
+// entries added by setfacl highlighted
+func (m LaravelPermissionsModel) viewACLs() string {
+	sections := []string{m.theme.Title.Render("ACL Entries"), ""}
+	if !m.aclSupported {
+		sections = append(sections, m.theme.WarningStyle.Render("⚠ ACLs are not available: "+m.aclReason), "")
+	}
+	for _, dir := range m.aclDirs {
+		sections = append(sections, m.theme.Subtitle.Render(dir+"/"))
+		if err := m.aclErrs[dir]; err != nil {
+			sections = append(sections, m.theme.ErrorStyle.Render("  "+err.Error()), "")
+			continue
+		}
+		extended := false
+		for _, e := range m.aclEntries[dir] {
+			if e.Extended() {
+				extended = true
+				sections = append(sections, m.theme.InfoStyle.Render("  "+e.String()))
+			} else {
+				sections = append(sections, m.theme.DescriptionStyle.Render("  "+e.String()))
+			}
+		}
+		if !extended {
+			sections = append(sections, m.theme.DescriptionStyle.Render("  No ACL entries beyond the permission bits"))
+		}
+		sections = append(sections, "")
+	}
+	sections = append(sections, m.theme.Help.Render("Enter/Esc: Back • q: Quit"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(content))
+}
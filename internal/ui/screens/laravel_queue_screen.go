@@ -11,6 +11,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/theme"
 )
 
@@ -50,6 +51,7 @@ const (
 	QueueStateList LaravelQueueState = iota
 	QueueStateForm
 	QueueStateActions
+	QueueStateConfirm
 )
 
 // LaravelQueueModel manages Laravel queue services
@@ -72,6 +74,7 @@ type LaravelQueueModel struct {
 	// Actions
 	actions      []string
 	actionCursor int
+	confirm      Confirmation
 
 	// Messages
 	message string
@@ -207,6 +210,8 @@ func (m LaravelQueueModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateList(msg)
 		case QueueStateActions:
 			return m.updateActions(msg)
+		case QueueStateConfirm:
+			return m.updateConfirm(msg)
 		}
 	}
 
@@ -281,11 +286,35 @@ func (m LaravelQueueModel) updateActions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
 		action := m.actions[m.actionCursor]
 		svc := m.services[m.cursor]
+		if action == "Delete Service" {
+			m.confirm = NewDangerConfirmation(action, "Delete Queue Service",
+				fmt.Sprintf("Delete %s? All worker instances will be stopped and the unit file removed.", svc.Label),
+				strings.TrimSuffix(svc.Label, "@"))
+			m.state = QueueStateConfirm
+			return m, nil
+		}
 		return m.executeAction(action, svc)
 	}
 	return m, nil
 }
 
+func (m LaravelQueueModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	var result ConfirmResult
+	m.confirm, result = m.confirm.Update(msg)
+	switch result {
+	case ConfirmCancelled:
+		m.state = QueueStateActions
+	case ConfirmAccepted:
+		m.state = QueueStateActions
+		return m.executeAction(m.confirm.Action, m.services[m.cursor])
+	}
+	return m, nil
+}
+
 func (m *LaravelQueueModel) startAdd() {
 	m.isEditing = false
 
@@ -307,7 +336,7 @@ func (m *LaravelQueueModel) startAdd() {
 	}
 	if m.editService.User == "" {
 		m.editService.User = "www-data"
-		m.editService.Group = "www-data"
+		m.editService.Group = system.WebGroup
 	}
 
 	m.buildForm()
@@ -531,10 +560,10 @@ done
 		return m, func() tea.Msg { return ExecutionStartMsg{Command: cmd, Description: "Status " + serviceBase + "*"} }
 
 	case "View Logs":
-		// Logs for unit pattern
-		// journalctl -u "name@*"
-		cmd := fmt.Sprintf("journalctl -u '%s*' -f -n 20", serviceBase)
-		return m, func() tea.Msg { return ExecutionStartMsg{Command: cmd, Description: "Logs " + serviceBase + "*"} }
+		// journalctl -u accepts a pattern matching every instance
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: LogViewerScreen, Data: map[string]interface{}{"unit": serviceBase + "*"}}
+		}
 
 	case "Delete Service":
 		return m, func() tea.Msg {
@@ -604,7 +633,7 @@ func (m LaravelQueueModel) executeLegacyAction(action string, svc LaravelQueueSe
 		}
 	case "View Logs":
 		return m, func() tea.Msg {
-			return ExecutionStartMsg{Command: "journalctl -u " + svcName + " -f -n 50", Description: "Logs " + svcName}
+			return NavigateMsg{Screen: LogViewerScreen, Data: map[string]interface{}{"unit": svcName}}
 		}
 	case "Delete Service":
 		return m, func() tea.Msg {
@@ -628,6 +657,8 @@ func (m LaravelQueueModel) View() string {
 		return m.viewForm()
 	case QueueStateActions:
 		return m.viewActions()
+	case QueueStateConfirm:
+		return m.confirm.View(m.theme, m.width, m.height)
 	}
 	return ""
 }
@@ -0,0 +1,123 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/settings"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// UnlockResultMsg reports the outcome of an unlock attempt
+type UnlockResultMsg struct {
+	Err error
+}
+
+// LockModel is the screen shown over ravact after the idle timeout. It is
+// owned by the root model rather than navigated to, so the screen below
+// keeps its state.
+type LockModel struct {
+	theme    *theme.Theme
+	prefs    settings.Preferences
+	username string
+	input    string
+	checking bool
+	err      error
+}
+
+// NewLockModel creates the lock screen for the current preferences
+func NewLockModel(prefs settings.Preferences) LockModel {
+	username := os.Getenv("SUDO_USER")
+	if username == "" {
+		if u, err := user.Current(); err == nil {
+			username = u.Username
+		}
+	}
+	return LockModel{
+		theme:    theme.DefaultTheme(),
+		prefs:    prefs,
+		username: username,
+	}
+}
+
+// Update handles key presses and unlock results
+func (m LockModel) Update(msg tea.Msg) (LockModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case UnlockResultMsg:
+		m.checking = false
+		m.err = msg.Err
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.checking {
+			return m, nil
+		}
+		switch msg.Type {
+		case tea.KeyBackspace:
+			if len(m.input) > 0 {
+				runes := []rune(m.input)
+				m.input = string(runes[:len(runes)-1])
+			}
+		case tea.KeySpace:
+			m.input += " "
+		case tea.KeyRunes:
+			m.input += string(msg.Runes)
+		case tea.KeyEsc:
+			m.input = ""
+		case tea.KeyEnter:
+			input, prefs := m.input, m.prefs
+			m.input = ""
+			m.err = nil
+			m.checking = true
+			return m, func() tea.Msg {
+				if prefs.HasPIN() {
+					if !prefs.CheckPIN(input) {
+						return UnlockResultMsg{Err: fmt.Errorf("incorrect PIN")}
+					}
+					return UnlockResultMsg{}
+				}
+				return UnlockResultMsg{Err: system.VerifyLocalPassword(input)}
+			}
+		}
+	}
+	return m, nil
+}
+
+// View renders the lock screen
+func (m LockModel) View(width, height int) string {
+	if width == 0 {
+		return "Loading..."
+	}
+
+	prompt := "PIN: "
+	if !m.prefs.HasPIN() {
+		prompt = "Password for " + m.username + ": "
+	}
+
+	sections := []string{
+		m.theme.Title.Render(m.theme.Symbols.Warning + " Ravact is locked"),
+		"",
+		m.theme.DescriptionStyle.Render(fmt.Sprintf("Locked after %d minute(s) without input.", m.prefs.IdleLockMinutes)),
+		m.theme.DescriptionStyle.Render("Tasks started before the lock keep running; new ones wait until unlock."),
+		"",
+		m.theme.Label.Render(prompt) + m.theme.SelectedItem.Render(strings.Repeat("*", len([]rune(m.input)))+"_"),
+	}
+	if m.checking {
+		sections = append(sections, "", m.theme.InfoStyle.Render("Checking..."))
+	}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections = append(sections, "", m.theme.Help.Render("Enter: Unlock"+bullet+"Esc: Clear"+bullet+"Ctrl+C: Quit"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, bordered)
+}
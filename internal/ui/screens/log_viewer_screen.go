@@ -0,0 +1,565 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// logViewerMaxLines is the number of lines kept in memory per tail
+const logViewerMaxLines = 5000
+
+// logViewerGeneration tags tail output so lines from a stopped tail are dropped
+var logViewerGeneration int
+
+// logLinesMsg carries a batch of lines read from the tail
+type logLinesMsg struct {
+	generation int
+	lines      []string
+	closed     bool
+}
+
+// LogViewerModel tails nginx, PHP-FPM, Laravel and journal logs
+type LogViewerModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	mode    string // "pick", "unit", "tail"
+	sources []system.LogSource
+	cursor  int
+	unit    string
+
+	// Set when opened for a single source; Esc goes back to the caller
+	direct bool
+
+	source     system.LogSource
+	tail       *system.LogTail
+	generation int
+	lines      []string
+	scroll     int // Visible lines scrolled up from the newest
+	paused     bool
+	ended      bool
+	minLevel   system.LogLevel
+	searching  bool
+	query      string
+
+	err error
+}
+
+// NewLogViewerModel creates a log viewer that starts with the source picker
+func NewLogViewerModel() LogViewerModel {
+	return LogViewerModel{
+		theme:   theme.DefaultTheme(),
+		mode:    "pick",
+		sources: system.DiscoverLogSources(),
+	}
+}
+
+// NewLogViewerModelForSource creates a log viewer that tails one source
+func NewLogViewerModelForSource(source system.LogSource) LogViewerModel {
+	m := LogViewerModel{
+		theme:  theme.DefaultTheme(),
+		direct: true,
+	}
+	m.startTail(source)
+	return m
+}
+
+// Init waits for output when a source was given
+func (m LogViewerModel) Init() tea.Cmd {
+	if m.tail == nil {
+		return nil
+	}
+	return waitForLogLines(m.tail, m.generation)
+}
+
+// startTail stops any running tail and follows source
+func (m *LogViewerModel) startTail(source system.LogSource) tea.Cmd {
+	m.stopTail()
+	logViewerGeneration++
+	m.generation = logViewerGeneration
+	m.source = source
+	m.lines = nil
+	m.scroll = 0
+	m.paused = false
+	m.ended = false
+	m.err = nil
+	m.mode = "tail"
+
+	tail, err := system.StartLogTail(source, system.LogBacklog)
+	if err != nil {
+		m.err = err
+		m.ended = true
+		return nil
+	}
+	m.tail = tail
+	return waitForLogLines(tail, m.generation)
+}
+
+// stopTail terminates the running tail, if any
+func (m *LogViewerModel) stopTail() {
+	if m.tail != nil {
+		m.tail.Stop()
+		m.tail = nil
+	}
+}
+
+// waitForLogLines blocks for the next line and returns it with whatever
+// else is already buffered
+func waitForLogLines(tail *system.LogTail, generation int) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-tail.Lines
+		if !ok {
+			return logLinesMsg{generation: generation, closed: true}
+		}
+		lines := []string{line}
+		for len(lines) < 500 {
+			select {
+			case line, ok := <-tail.Lines:
+				if !ok {
+					return logLinesMsg{generation: generation, lines: lines, closed: true}
+				}
+				lines = append(lines, line)
+			default:
+				return logLinesMsg{generation: generation, lines: lines}
+			}
+		}
+		return logLinesMsg{generation: generation, lines: lines}
+	}
+}
+
+// Update handles messages for the log viewer
+func (m LogViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case logLinesMsg:
+		if msg.generation != m.generation {
+			return m, nil
+		}
+		m.appendLines(msg.lines)
+		if msg.closed {
+			m.ended = true
+			m.tail = nil
+			return m, nil
+		}
+		return m, waitForLogLines(m.tail, m.generation)
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.stopTail()
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case "pick":
+			return m.updatePicker(msg)
+		case "unit":
+			return m.updateUnitInput(msg)
+		}
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+		return m.updateTail(msg)
+	}
+
+	return m, nil
+}
+
+// appendLines adds new output, keeping the view still while paused or scrolled
+func (m *LogViewerModel) appendLines(lines []string) {
+	if m.paused || m.scroll > 0 {
+		for _, line := range lines {
+			if m.visible(line) {
+				m.scroll++
+			}
+		}
+	}
+	m.lines = append(m.lines, lines...)
+	if over := len(m.lines) - logViewerMaxLines; over > 0 {
+		m.lines = append([]string(nil), m.lines[over:]...)
+	}
+	if max := len(m.filtered()) - 1; m.scroll > max && max >= 0 {
+		m.scroll = max
+	}
+}
+
+// visible reports whether a line passes the level filter
+func (m LogViewerModel) visible(line string) bool {
+	return m.minLevel == system.LogLevelNone || system.DetectLogLevel(line) >= m.minLevel
+}
+
+// filtered returns the lines that pass the level filter
+func (m LogViewerModel) filtered() []string {
+	if m.minLevel == system.LogLevelNone {
+		return m.lines
+	}
+	var lines []string
+	for _, line := range m.lines {
+		if m.visible(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// updatePicker handles source selection
+func (m LogViewerModel) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: MainMenuScreen}
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.sources)-1 {
+			m.cursor++
+		}
+	case "u":
+		m.unit = ""
+		m.err = nil
+		m.mode = "unit"
+	case "r":
+		m.sources = system.DiscoverLogSources()
+		if m.cursor >= len(m.sources) {
+			m.cursor = 0
+		}
+	case "enter", " ":
+		if m.cursor < len(m.sources) {
+			return m, m.startTail(m.sources[m.cursor])
+		}
+	}
+	return m, nil
+}
+
+// updateUnitInput handles typing a systemd unit name
+func (m LogViewerModel) updateUnitInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = "pick"
+	case tea.KeyBackspace:
+		if len(m.unit) > 0 {
+			m.unit = m.unit[:len(m.unit)-1]
+		}
+	case tea.KeyRunes:
+		m.unit += string(msg.Runes)
+	case tea.KeyEnter:
+		unit := strings.TrimSpace(m.unit)
+		if unit == "" {
+			m.err = fmt.Errorf("enter a unit name, e.g. nginx or php8.3-fpm")
+			return m, nil
+		}
+		return m, m.startTail(system.JournalSource(unit))
+	}
+	return m, nil
+}
+
+// updateSearch handles typing a search term
+func (m LogViewerModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.searching = false
+		m.query = ""
+	case tea.KeyEnter:
+		m.searching = false
+		m.jumpToMatch(true)
+	case tea.KeyBackspace:
+		if len(m.query) > 0 {
+			runes := []rune(m.query)
+			m.query = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.query += " "
+	case tea.KeyRunes:
+		m.query += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// updateTail handles keys while following a log
+func (m LogViewerModel) updateTail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxScroll := len(m.filtered()) - m.viewHeight()
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+
+	switch msg.String() {
+	case "q":
+		m.stopTail()
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.stopTail()
+		if m.direct {
+			return m, func() tea.Msg { return BackMsg{} }
+		}
+		m.mode = "pick"
+		m.generation = 0
+		m.query = ""
+		m.minLevel = system.LogLevelNone
+	case "p", " ":
+		m.paused = !m.paused
+		if !m.paused {
+			m.scroll = 0
+		}
+	case "up", "k":
+		if m.scroll < maxScroll {
+			m.scroll++
+		}
+	case "down", "j":
+		if m.scroll > 0 {
+			m.scroll--
+		}
+	case "pgup":
+		m.scroll += m.viewHeight()
+		if m.scroll > maxScroll {
+			m.scroll = maxScroll
+		}
+	case "pgdown":
+		m.scroll -= m.viewHeight()
+		if m.scroll < 0 {
+			m.scroll = 0
+		}
+	case "G", "end":
+		m.scroll = 0
+		m.paused = false
+	case "/":
+		m.searching = true
+		m.query = ""
+	case "n":
+		m.jumpToMatch(true)
+	case "N":
+		m.jumpToMatch(false)
+	case "l":
+		// Cycle all → warnings and errors → errors only
+		switch m.minLevel {
+		case system.LogLevelNone:
+			m.minLevel = system.LogLevelWarning
+		case system.LogLevelWarning:
+			m.minLevel = system.LogLevelError
+		default:
+			m.minLevel = system.LogLevelNone
+		}
+		m.scroll = 0
+	case "r":
+		if m.ended {
+			return m, m.startTail(m.source)
+		}
+	}
+	return m, nil
+}
+
+// jumpToMatch scrolls to the next older (or newer) line containing the query
+func (m *LogViewerModel) jumpToMatch(older bool) {
+	if m.query == "" {
+		return
+	}
+	lines := m.filtered()
+	query := strings.ToLower(m.query)
+	bottom := len(lines) - 1 - m.scroll
+	if older {
+		for i := bottom - 1; i >= 0; i-- {
+			if strings.Contains(strings.ToLower(lines[i]), query) {
+				m.scroll = len(lines) - 1 - i
+				m.paused = true
+				return
+			}
+		}
+		return
+	}
+	for i := bottom + 1; i < len(lines); i++ {
+		if strings.Contains(strings.ToLower(lines[i]), query) {
+			m.scroll = len(lines) - 1 - i
+			return
+		}
+	}
+}
+
+// viewHeight is the number of log lines shown at once
+func (m LogViewerModel) viewHeight() int {
+	h := m.height - 14
+	if h < 5 {
+		h = 5
+	}
+	return h
+}
+
+// levelStyle returns the style used for a line's severity
+func (m LogViewerModel) levelStyle(level system.LogLevel) lipgloss.Style {
+	switch level {
+	case system.LogLevelError:
+		return m.theme.ErrorStyle
+	case system.LogLevelWarning:
+		return m.theme.WarningStyle
+	case system.LogLevelInfo:
+		return m.theme.InfoStyle
+	case system.LogLevelDebug:
+		return m.theme.DescriptionStyle
+	}
+	return m.theme.Value
+}
+
+// renderLine truncates a line, colours it by level and highlights the query
+func (m LogViewerModel) renderLine(line string) string {
+	style := m.levelStyle(system.DetectLogLevel(line))
+	runes := []rune(strings.ReplaceAll(line, "\t", "    "))
+	if len(runes) > m.theme.AppWidth {
+		runes = append(runes[:m.theme.AppWidth-1], '…')
+	}
+	line = string(runes)
+
+	lower := strings.ToLower(line)
+	if m.query == "" || len(lower) != len(line) {
+		return style.Render(line)
+	}
+	query := strings.ToLower(m.query)
+	highlight := style.Reverse(true)
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, query)
+		if i < 0 {
+			b.WriteString(style.Render(line))
+			break
+		}
+		b.WriteString(style.Render(line[:i]))
+		b.WriteString(highlight.Render(line[i : i+len(query)]))
+		line, lower = line[i+len(query):], lower[i+len(query):]
+	}
+	return b.String()
+}
+
+// View renders the log viewer
+func (m LogViewerModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	var sections []string
+	switch m.mode {
+	case "pick", "unit":
+		sections = m.pickerView()
+	default:
+		sections = m.tailView()
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// pickerView renders the list of log sources
+func (m LogViewerModel) pickerView() []string {
+	sections := []string{m.theme.Title.Render("Log Viewer"), ""}
+
+	if m.mode == "unit" {
+		sections = append(sections,
+			m.theme.DescriptionStyle.Render("Follow the journal of any systemd unit."), "",
+			m.theme.Label.Render("Unit: ")+m.theme.SelectedItem.Render(m.unit+"_"),
+		)
+		if m.err != nil {
+			sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+		}
+		return append(sections, "", m.theme.Help.Render("Enter: Follow "+m.theme.Symbols.Bullet+" Esc: Cancel"))
+	}
+
+	if len(m.sources) == 0 {
+		sections = append(sections, m.theme.DescriptionStyle.Render("No logs found. Press u to follow a systemd unit."))
+	}
+
+	// Keep the cursor in view on long lists
+	height := m.viewHeight()
+	start := 0
+	if m.cursor >= height {
+		start = m.cursor - height + 1
+	}
+	group := ""
+	for i := start; i < len(m.sources) && i < start+height; i++ {
+		source := m.sources[i]
+		if source.Group != group {
+			group = source.Group
+			sections = append(sections, m.theme.CategoryStyle.Render(group))
+		}
+		if i == m.cursor {
+			sections = append(sections, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+m.theme.SelectedItem.Render(source.Name))
+		} else {
+			sections = append(sections, "  "+m.theme.MenuItem.Render(source.Name))
+		}
+	}
+
+	help := m.theme.Help.Render(
+		m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet +
+			" Enter: Follow " + m.theme.Symbols.Bullet + " u: Systemd unit " + m.theme.Symbols.Bullet +
+			" r: Refresh " + m.theme.Symbols.Bullet + " Esc: Back")
+	return append(sections, "", help)
+}
+
+// tailView renders the followed log
+func (m LogViewerModel) tailView() []string {
+	status := m.theme.SuccessStyle.Render(m.theme.Symbols.Bullet + " Following")
+	switch {
+	case m.ended:
+		status = m.theme.DescriptionStyle.Render("Stopped")
+	case m.paused || m.scroll > 0:
+		status = m.theme.WarningStyle.Render(fmt.Sprintf("Paused (%d newer lines)", m.scroll))
+	}
+	filter := "all levels"
+	switch m.minLevel {
+	case system.LogLevelWarning:
+		filter = "warnings and errors"
+	case system.LogLevelError:
+		filter = "errors only"
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Log Viewer: " + m.source.Name),
+		status + m.theme.DescriptionStyle.Render("  "+m.theme.Symbols.Bullet+" Showing "+filter),
+		"",
+	}
+
+	lines := m.filtered()
+	end := len(lines) - m.scroll
+	start := end - m.viewHeight()
+	if start < 0 {
+		start = 0
+	}
+	if len(lines) == 0 && !m.ended {
+		sections = append(sections, m.theme.DescriptionStyle.Render("Waiting for log output..."))
+	}
+	for _, line := range lines[start:end] {
+		sections = append(sections, m.renderLine(line))
+	}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+
+	sections = append(sections, "")
+	if m.searching {
+		sections = append(sections, m.theme.Label.Render("Search: ")+m.theme.SelectedItem.Render(m.query+"_"),
+			m.theme.Help.Render("Enter: Find "+m.theme.Symbols.Bullet+" Esc: Clear"))
+		return sections
+	}
+
+	help := m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Scroll " + m.theme.Symbols.Bullet +
+		" p: Pause " + m.theme.Symbols.Bullet + " /: Search " + m.theme.Symbols.Bullet
+	if m.query != "" {
+		help += " n/N: Older/newer match " + m.theme.Symbols.Bullet
+	}
+	help += " l: Level " + m.theme.Symbols.Bullet
+	if m.ended {
+		help += " r: Restart " + m.theme.Symbols.Bullet
+	}
+	help += " Esc: Back"
+	return append(sections, m.theme.Help.Render(help))
+}
@@ -0,0 +1,394 @@
+package screens
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// logrotateTargetsMsg carries the discovered log rotation targets
+type logrotateTargetsMsg struct {
+	targets []system.LogrotateTarget
+}
+
+// LogrotateModel creates and edits logrotate configs for site, Laravel,
+// and supervisor program logs
+type LogrotateModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	targets []system.LogrotateTarget
+	loading bool
+	waiting bool // A simulation or install started from the screen is running
+	cursor  int
+
+	mode    string // "", "form", "preview", or "confirm"
+	form    *huh.Form
+	pending system.LogrotateSettings
+	content string
+	scroll  int
+	confirm Confirmation
+
+	err error
+}
+
+// NewLogrotateModel creates the log rotation screen
+func NewLogrotateModel() LogrotateModel {
+	return LogrotateModel{
+		theme:   theme.DefaultTheme(),
+		loading: true,
+	}
+}
+
+// Init discovers the logs and their rotation
+func (m LogrotateModel) Init() tea.Cmd {
+	return func() tea.Msg {
+		return logrotateTargetsMsg{targets: system.DiscoverLogrotateTargets()}
+	}
+}
+
+// Waiting reports whether a script started from the screen is running.
+// The model is kept across it so a simulation returns to the preview.
+func (m LogrotateModel) Waiting() bool {
+	return m.waiting
+}
+
+// Update handles messages for the log rotation screen
+func (m LogrotateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case logrotateTargetsMsg:
+		m.loading = false
+		m.waiting = false
+		m.targets = msg.targets
+		if m.cursor >= len(m.targets) {
+			m.cursor = 0
+		}
+		return m, nil
+	}
+
+	if m.mode == "form" {
+		return m.updateForm(msg)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if keyMsg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch m.mode {
+	case "confirm":
+		return m.updateConfirm(keyMsg)
+	case "preview":
+		return m.updatePreview(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: MainMenuScreen}
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.targets)-1 {
+			m.cursor++
+		}
+	case "e", "enter":
+		if m.loading || len(m.targets) == 0 {
+			break
+		}
+		t := m.targets[m.cursor]
+		if len(t.Uncovered()) == 0 {
+			m.err = fmt.Errorf("%s is already rotated by %s", t.Title, t.CoveredBy[t.Paths[0]])
+			break
+		}
+		return m.openForm()
+	case "x":
+		if m.loading || len(m.targets) == 0 || !m.targets[m.cursor].Installed {
+			break
+		}
+		t := m.targets[m.cursor]
+		m.confirm = NewConfirmation("remove", "Remove Log Rotation",
+			fmt.Sprintf("Remove %s?\n\nThe logs of %s will no longer be rotated.", t.ConfigPath(), t.Title), ConfirmWarning)
+		m.mode = "confirm"
+	case "r":
+		if !m.loading {
+			m.loading = true
+			m.err = nil
+			return m, m.Init()
+		}
+	}
+	return m, nil
+}
+
+// openForm shows the settings form for the selected target
+func (m LogrotateModel) openForm() (tea.Model, tea.Cmd) {
+	s := m.targets[m.cursor].Settings
+	period := s.Period
+	rotate := strconv.Itoa(s.Rotate)
+	compress := s.Compress
+
+	var periods []huh.Option[string]
+	for _, p := range system.LogrotatePeriods {
+		periods = append(periods, huh.NewOption(p, p))
+	}
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("period").
+				Title("Rotate").
+				Options(periods...).
+				Value(&period),
+			huh.NewInput().
+				Key("rotate").
+				Title("Rotated logs to keep").
+				Value(&rotate).
+				Validate(func(v string) error {
+					n, err := strconv.Atoi(strings.TrimSpace(v))
+					if err != nil {
+						return fmt.Errorf("enter a number")
+					}
+					return system.LogrotateSettings{Period: "daily", Rotate: n}.Validate()
+				}),
+			huh.NewConfirm().
+				Key("compress").
+				Title("Compress rotated logs?").
+				Description("gzip, skipping the newest rotation so writers can finish with it").
+				Value(&compress),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+	m.mode = "form"
+	m.err = nil
+	return m, m.form.Init()
+}
+
+// updateForm passes messages to the settings form
+func (m LogrotateModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = ""
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	switch m.form.State {
+	case huh.StateCompleted:
+		rotate, _ := strconv.Atoi(strings.TrimSpace(m.form.GetString("rotate")))
+		m.pending = system.LogrotateSettings{
+			Period:   m.form.GetString("period"),
+			Rotate:   rotate,
+			Compress: m.form.GetBool("compress"),
+		}
+		if err := m.pending.Validate(); err != nil {
+			m.err = err
+			m.mode = ""
+			return m, nil
+		}
+		m.content = system.LogrotateConfig(m.targets[m.cursor], m.pending)
+		m.scroll = 0
+		m.mode = "preview"
+		return m, nil
+	case huh.StateAborted:
+		m.mode = ""
+		return m, nil
+	}
+	return m, cmd
+}
+
+// updatePreview handles keys while previewing the generated config
+func (m LogrotateModel) updatePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = ""
+	case "down", "j":
+		if m.scroll < len(m.previewLines())-1 {
+			m.scroll++
+		}
+	case "up", "k":
+		if m.scroll > 0 {
+			m.scroll--
+		}
+	case "s":
+		m.waiting = true
+		script := system.LogrotateSimulateScript(m.content)
+		description := "Simulating log rotation for " + m.targets[m.cursor].Title
+		return m, func() tea.Msg {
+			return ExecutionStartMsg{Command: "sudo bash -c " + system.ShellQuote(script), Description: description}
+		}
+	case "enter", "w":
+		t := m.targets[m.cursor]
+		m.confirm = NewConfirmation("install", "Install Log Rotation",
+			fmt.Sprintf("Write %s?\n\nlogrotate picks it up on its next daily run.", t.ConfigPath()), ConfirmNormal)
+		m.mode = "confirm"
+	}
+	return m, nil
+}
+
+// updateConfirm handles the install and remove confirmations
+func (m LogrotateModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var result ConfirmResult
+	m.confirm, result = m.confirm.Update(msg)
+	switch result {
+	case ConfirmAccepted:
+		t := m.targets[m.cursor]
+		script := system.LogrotateRemoveScript(t)
+		description := "Removing log rotation for " + t.Title
+		if m.confirm.Action == "install" {
+			script = system.LogrotateInstallScript(t, m.content)
+			description = "Installing log rotation for " + t.Title
+		}
+		m.mode = ""
+		m.waiting = true
+		m.loading = true
+		return m, func() tea.Msg {
+			return ExecutionStartMsg{Command: "sudo bash -c " + system.ShellQuote(script), Description: description}
+		}
+	case ConfirmCancelled:
+		if m.confirm.Action == "install" {
+			m.mode = "preview"
+		} else {
+			m.mode = ""
+		}
+	}
+	return m, nil
+}
+
+// previewLines returns the lines of the generated config
+func (m LogrotateModel) previewLines() []string {
+	return strings.Split(strings.TrimRight(m.content, "\n"), "\n")
+}
+
+// View renders the log rotation screen
+func (m LogrotateModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Log Rotation"),
+		m.theme.DescriptionStyle.Render(system.LogrotateDir),
+		"",
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := "e: Configure" + bullet + "x: Remove" + bullet + "r: Refresh" + bullet + "Esc: Back"
+	switch {
+	case m.mode == "form":
+		sections = append(sections, m.theme.Label.Render(m.targets[m.cursor].Title), "", m.form.View())
+		help = "Enter: Next" + bullet + "Esc: Cancel"
+	case m.mode == "preview":
+		sections = append(sections, m.renderPreview()...)
+		help = "↑/↓: Scroll" + bullet + "s: Simulate (logrotate -d)" + bullet + "Enter: Install" + bullet + "Esc: Cancel"
+	case m.loading:
+		sections = append(sections, m.theme.InfoStyle.Render("Finding site, Laravel, and supervisor logs..."))
+	case len(m.targets) == 0:
+		sections = append(sections, m.theme.DescriptionStyle.Render("No site, Laravel, or supervisor program logs found"))
+	default:
+		sections = append(sections, m.renderTargets()...)
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// renderTargets lists the targets and details the selected one
+func (m LogrotateModel) renderTargets() []string {
+	var lines []string
+	for i, t := range m.targets {
+		cursor := "  "
+		style := m.theme.MenuItem
+		if i == m.cursor {
+			cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+			style = m.theme.SelectedItem
+		}
+		var status string
+		switch {
+		case t.Installed:
+			status = m.theme.SuccessStyle.Render(t.Settings.Describe())
+		case len(t.Uncovered()) == 0:
+			status = m.theme.DescriptionStyle.Render("rotated by " + t.CoveredBy[t.Paths[0]])
+		default:
+			status = m.theme.WarningStyle.Render("not rotated")
+		}
+		lines = append(lines, cursor+style.Render(fmt.Sprintf("%-40s", truncateRunes(t.Title, 40)))+"  "+status)
+	}
+
+	t := m.targets[m.cursor]
+	lines = append(lines, "")
+	pathWidth := max(m.width-30, 20)
+	for _, path := range t.Paths {
+		line := "  " + truncateRunes(path, pathWidth)
+		if name, ok := t.CoveredBy[path]; ok {
+			line += m.theme.DescriptionStyle.Render("  (rotated by " + name + ")")
+		}
+		lines = append(lines, m.theme.Value.Render(line))
+	}
+	if t.Note != "" {
+		lines = append(lines, m.theme.DescriptionStyle.Render("  "+t.Note))
+	}
+	return lines
+}
+
+// renderPreview shows the generated config and where it will be written
+func (m LogrotateModel) renderPreview() []string {
+	t := m.targets[m.cursor]
+	sections := []string{m.theme.DescriptionStyle.Render(t.ConfigPath()), ""}
+
+	height := max(m.height-16, 5)
+	lines := m.previewLines()
+	end := min(m.scroll+height, len(lines))
+	for _, line := range lines[m.scroll:end] {
+		sections = append(sections, m.theme.Value.Render(line))
+	}
+	if len(lines) > height {
+		sections = append(sections, m.theme.DescriptionStyle.Render(fmt.Sprintf("%d-%d of %d", m.scroll+1, end, len(lines))))
+	}
+
+	var covered []string
+	for path, name := range t.CoveredBy {
+		covered = append(covered, path+" ("+name+")")
+	}
+	sort.Strings(covered)
+	if len(covered) > 0 {
+		sections = append(sections, "", m.theme.DescriptionStyle.Render("Left to their existing configs: "+strings.Join(covered, ", ")))
+	}
+	return sections
+}
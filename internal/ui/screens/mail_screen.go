@@ -0,0 +1,379 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/system/pkgmanager"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+	"github.com/iperamuna/ravact/internal/vault"
+)
+
+// MailModel configures outbound mail through an SMTP relay, with Postfix
+// or msmtp as the system sendmail
+type MailModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	relay      system.MailRelay
+	configured bool // A backend is installed and has a relay
+	actions    []string
+	cursor     int
+
+	form *huh.Form
+	mode string // "menu", "relay", "test"
+
+	vaultStatus string
+	err         error
+	success     string
+}
+
+// NewMailModel creates the outbound mail screen
+func NewMailModel() MailModel {
+	m := MailModel{
+		theme: theme.DefaultTheme(),
+		actions: []string{
+			"Configure SMTP Relay",
+			"Send Test Email",
+			"View Mail Log",
+			"← Back to Configurations",
+		},
+		mode: "menu",
+	}
+	m.load()
+	return m
+}
+
+// load reads the installed backend's relay
+func (m *MailModel) load() {
+	relay, err := system.ReadMailRelay()
+	m.configured = err == nil && relay.Host != ""
+	if err != nil {
+		relay = system.MailRelay{Backend: system.MailBackendPostfix}
+	}
+	if relay.Port == 0 || !m.configured {
+		relay.Port = 587
+		relay.TLS = "starttls"
+	}
+	m.relay = relay
+}
+
+func (m MailModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m MailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	if m.mode != "menu" {
+		return m.updateForm(msg)
+	}
+
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: ConfigMenuScreen}
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.actions)-1 {
+				m.cursor++
+			}
+		case "enter", " ":
+			return m.executeAction()
+		}
+	}
+	return m, nil
+}
+
+func (m MailModel) executeAction() (MailModel, tea.Cmd) {
+	m.err = nil
+	m.success = ""
+	m.vaultStatus = ""
+
+	switch m.actions[m.cursor] {
+	case "Configure SMTP Relay":
+		m.form = m.buildRelayForm()
+		m.mode = "relay"
+		return m, m.form.Init()
+
+	case "Send Test Email":
+		if !m.configured {
+			m.err = fmt.Errorf("configure an SMTP relay first")
+			return m, nil
+		}
+		m.form = m.buildTestForm()
+		m.mode = "test"
+		return m, m.form.Init()
+
+	case "View Mail Log":
+		path := system.MailLogPath()
+		if path == "" && m.relay.Backend == system.MailBackendPostfix {
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: LogViewerScreen, Data: map[string]interface{}{"unit": "postfix@-"}}
+			}
+		}
+		if path == "" {
+			m.err = fmt.Errorf("no syslog mail log; msmtp logs to the journal (journalctl -t msmtp)")
+			return m, nil
+		}
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: LogViewerScreen, Data: map[string]interface{}{"path": path, "group": "mail"}}
+		}
+
+	case "← Back to Configurations":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: ConfigMenuScreen}
+		}
+	}
+	return m, nil
+}
+
+// buildRelayForm creates the relay form from the current settings
+func (m *MailModel) buildRelayForm() *huh.Form {
+	backend := m.relay.Backend
+	host := m.relay.Host
+	port := strconv.Itoa(m.relay.Port)
+	tls := m.relay.TLS
+	username := m.relay.Username
+	var password string
+	from := m.relay.From
+
+	passwordHelp := "SMTP password or API key"
+	if m.relay.Password != "" {
+		passwordHelp = "Leave empty to keep the current password"
+	}
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("backend").
+				Title("Mail Backend").
+				Options(
+					huh.NewOption("Postfix satellite relay (queues and retries)", system.MailBackendPostfix),
+					huh.NewOption("msmtp (minimal, sends directly)", system.MailBackendMsmtp),
+				).
+				Value(&backend),
+
+			huh.NewInput().
+				Key("host").
+				Title("SMTP Host").
+				Placeholder("smtp.mailgun.org").
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("host is required")
+					}
+					return nil
+				}).
+				Value(&host),
+
+			huh.NewInput().
+				Key("port").
+				Title("Port").
+				Description("587 for STARTTLS, 465 for TLS").
+				Validate(func(s string) error {
+					if n, err := strconv.Atoi(strings.TrimSpace(s)); err != nil || n < 1 || n > 65535 {
+						return fmt.Errorf("enter a port between 1 and 65535")
+					}
+					return nil
+				}).
+				Value(&port),
+
+			huh.NewSelect[string]().
+				Key("tls").
+				Title("Encryption").
+				Options(
+					huh.NewOption("STARTTLS", "starttls"),
+					huh.NewOption("TLS (SMTPS)", "tls"),
+					huh.NewOption("None", "none"),
+				).
+				Value(&tls),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Key("username").
+				Title("Username").
+				Description("Leave empty for relays that allow this server's IP").
+				Value(&username),
+
+			huh.NewInput().
+				Key("password").
+				Title("Password").
+				Description(passwordHelp).
+				EchoMode(huh.EchoModePassword).
+				Value(&password),
+
+			huh.NewInput().
+				Key("from").
+				Title("From Address").
+				Description("Verified sender used for system and cron mail").
+				Placeholder("no-reply@example.com").
+				Value(&from),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// buildTestForm asks for the test email recipient
+func (m *MailModel) buildTestForm() *huh.Form {
+	var to string
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("to").
+				Title("Send Test Email To").
+				Placeholder("you@example.com").
+				Value(&to),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// updateForm handles the relay and test email forms
+func (m MailModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = "menu"
+			m.form = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	mode := m.mode
+	m.mode = "menu"
+	if mode == "test" {
+		to := strings.TrimSpace(m.form.GetString("to"))
+		m.form = nil
+		if err := system.SendTestMail(m.relay.From, to); err != nil {
+			m.err = err
+		} else if m.relay.Backend == system.MailBackendPostfix {
+			m.success = "✓ Test email queued for " + to + "; check the mail log if it does not arrive"
+		} else {
+			m.success = "✓ Test email accepted by the relay for " + to
+		}
+		return m, nil
+	}
+	return m.applyRelay()
+}
+
+// applyRelay writes the credentials and runs the install and setup script
+func (m MailModel) applyRelay() (tea.Model, tea.Cmd) {
+	relay := system.MailRelay{
+		Backend:  m.form.GetString("backend"),
+		Host:     strings.TrimSpace(m.form.GetString("host")),
+		TLS:      m.form.GetString("tls"),
+		Username: strings.TrimSpace(m.form.GetString("username")),
+		Password: m.form.GetString("password"),
+		From:     strings.TrimSpace(m.form.GetString("from")),
+	}
+	relay.Port, _ = strconv.Atoi(strings.TrimSpace(m.form.GetString("port")))
+	if relay.Password == "" && relay.Username != "" {
+		relay.Password = m.relay.Password
+	}
+	m.form = nil
+
+	if err := system.WriteMailCredentials(relay); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if relay.Username != "" {
+		m.vaultStatus = vault.RecordMessage("SMTP Relay", relay.Username, relay.Password, fmt.Sprintf("%s:%d", relay.Host, relay.Port))
+	}
+
+	distro, err := pkgmanager.Detect()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	script := relay.MailSetupScript(distro.InstallCommand(system.MailBackendPackages(relay.Backend)...))
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{
+			Command:     script,
+			Description: fmt.Sprintf("Configuring %s to relay through %s:%d", relay.Backend, relay.Host, relay.Port),
+		}
+	}
+}
+
+func (m MailModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	header := m.theme.Title.Render("Outbound Mail")
+
+	var info []string
+	if m.configured {
+		auth := "none"
+		if m.relay.Username != "" {
+			auth = m.relay.Username
+		}
+		from := m.relay.From
+		if from == "" {
+			from = "not rewritten"
+		}
+		info = append(info,
+			m.theme.Label.Render("Backend: ")+m.theme.InfoStyle.Render(m.relay.Backend),
+			m.theme.Label.Render("Relay:   ")+m.theme.InfoStyle.Render(fmt.Sprintf("%s:%d (%s)", m.relay.Host, m.relay.Port, m.relay.TLS)),
+			m.theme.Label.Render("Login:   ")+m.theme.InfoStyle.Render(auth),
+			m.theme.Label.Render("From:    ")+m.theme.InfoStyle.Render(from),
+		)
+	} else {
+		info = append(info, m.theme.WarningStyle.Render("No SMTP relay configured; mail from this server (password resets, cron) is not delivered"))
+	}
+	sections := []string{header, "", lipgloss.JoinVertical(lipgloss.Left, info...), ""}
+
+	if m.mode != "menu" && m.form != nil {
+		sections = append(sections, m.form.View(), "", m.theme.Help.Render("Tab: Next • Enter: Save • Esc: Cancel"))
+	} else {
+		for i, action := range m.actions {
+			if i == m.cursor {
+				sections = append(sections, m.theme.SelectedItem.Render(m.theme.KeyStyle.Render("▶ ")+action))
+			} else {
+				sections = append(sections, m.theme.MenuItem.Render("  "+action))
+			}
+		}
+		if m.success != "" {
+			sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+		}
+		if m.vaultStatus != "" {
+			sections = append(sections, m.theme.DescriptionStyle.Render(m.vaultStatus))
+		}
+		if m.err != nil {
+			sections = append(sections, "", m.theme.ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		}
+		sections = append(sections, "", m.theme.Help.Render("↑/↓: Navigate • Enter: Select • Esc: Back • q: Quit"))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
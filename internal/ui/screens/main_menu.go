@@ -36,6 +36,19 @@ type MainMenuModel struct {
 	systemInfo *models.SystemInfo
 	detector   *system.Detector
 	version    string
+
+	runningTasks int                   // Tasks still running detached on the host
+	xdebug       []system.XdebugStatus // PHP versions running with Xdebug on
+}
+
+// runningTasksMsg carries how many tasks run detached on the host
+type runningTasksMsg struct {
+	count int
+}
+
+// xdebugActiveMsg carries the PHP versions running with Xdebug on
+type xdebugActiveMsg struct {
+	statuses []system.XdebugStatus
 }
 
 // NewMainMenuModel creates a new main menu model
@@ -62,6 +75,18 @@ func NewMainMenuModel(version string) MainMenuModel {
 					Screen:      InstalledAppsScreen,
 					Category:    "Package Management",
 				},
+				{
+					Title:       "System Updates",
+					Description: "Review and apply pending OS package upgrades",
+					Screen:      UpdatesScreen,
+					Category:    "Package Management",
+				},
+				{
+					Title:       "Automatic Updates",
+					Description: "Configure unattended security updates and reboots",
+					Screen:      AutoUpdatesScreen,
+					Category:    "Package Management",
+				},
 			},
 		},
 		{
@@ -98,6 +123,36 @@ func NewMainMenuModel(version string) MainMenuModel {
 			Name: "System Administration",
 			Icon: t.Symbols.Info,
 			Items: []MenuItem{
+				{
+					Title:       "Server Dashboard",
+					Description: "Live CPU, memory, disk, load, and service health",
+					Screen:      DashboardScreen,
+					Category:    "System Administration",
+				},
+				{
+					Title:       "Systemd Services",
+					Description: "Start, stop, enable, and edit nginx, PHP-FPM, database, and worker units",
+					Screen:      SystemdServicesScreen,
+					Category:    "System Administration",
+				},
+				{
+					Title:       "Log Viewer",
+					Description: "Follow nginx, PHP-FPM, Laravel, and systemd logs live",
+					Screen:      LogViewerScreen,
+					Category:    "System Administration",
+				},
+				{
+					Title:       "Disk Usage",
+					Description: "Find space hogs and run safe cleanups",
+					Screen:      DiskUsageScreen,
+					Category:    "System Administration",
+				},
+				{
+					Title:       "Log Rotation",
+					Description: "Rotate site, Laravel, and supervisor logs with logrotate",
+					Screen:      LogrotateScreen,
+					Category:    "System Administration",
+				},
 				{
 					Title:       "User Management",
 					Description: "Manage users, groups, and sudo privileges",
@@ -110,6 +165,54 @@ func NewMainMenuModel(version string) MainMenuModel {
 					Screen:      QuickCommandsScreen,
 					Category:    "System Administration",
 				},
+				{
+					Title:       "Background Tasks",
+					Description: "Follow installs that keep running after ravact exits or the connection drops",
+					Screen:      TasksScreen,
+					Category:    "System Administration",
+				},
+				{
+					Title:       "Configuration History",
+					Description: "Browse, diff, and restore configuration recorded after each task",
+					Screen:      ConfigHistoryScreen,
+					Category:    "System Administration",
+				},
+				{
+					Title:       "Detect Drift",
+					Description: "Compare generated site and service configs with the files on disk",
+					Screen:      ConfigDriftScreen,
+					Category:    "System Administration",
+				},
+				{
+					Title:       "Secrets Vault",
+					Description: "Encrypted store of passwords set by ravact",
+					Screen:      SecretsVaultScreen,
+					Category:    "System Administration",
+				},
+				{
+					Title:       "Servers",
+					Description: "Switch between this machine and remote servers over SSH",
+					Screen:      ServersScreen,
+					Category:    "System Administration",
+				},
+				{
+					Title:       "Settings",
+					Description: "Idle screen lock and other ravact preferences",
+					Screen:      SettingsScreen,
+					Category:    "System Administration",
+				},
+				{
+					Title:       "Naming Policy",
+					Description: "Required patterns for site keys, databases, and users, with an audit",
+					Screen:      NamingPolicyScreen,
+					Category:    "System Administration",
+				},
+				{
+					Title:       "Export / Import Settings",
+					Description: "Encrypted archive of ravact settings and site metadata",
+					Screen:      SettingsTransferScreen,
+					Category:    "System Administration",
+				},
 			},
 		},
 		{
@@ -122,6 +225,12 @@ func NewMainMenuModel(version string) MainMenuModel {
 					Screen:      FileBrowserScreen,
 					Category:    "Tools",
 				},
+				{
+					Title:       "Marketplace",
+					Description: "Signed community blueprints and scripts, vendored for review",
+					Screen:      MarketplaceScreen,
+					Category:    "Tools",
+				},
 			},
 		},
 	}
@@ -143,9 +252,34 @@ func NewMainMenuModel(version string) MainMenuModel {
 	}
 }
 
-// Init initializes the main menu
+// RefreshSystemInfo reloads host details, e.g. after switching servers
+func (m *MainMenuModel) RefreshSystemInfo() {
+	m.systemInfo, _ = m.detector.GetSystemInfo()
+}
+
+// Init counts the background tasks still running and checks for Xdebug
+// left on. Tasks are only read when that needs no password prompt.
 func (m MainMenuModel) Init() tea.Cmd {
-	return nil
+	xdebug := func() tea.Msg {
+		return xdebugActiveMsg{statuses: system.ActiveXdebug()}
+	}
+	tasks := func() tea.Msg {
+		if system.HostOS() != "linux" {
+			return runningTasksMsg{}
+		}
+		switch system.DetectPrivilegeMethod() {
+		case system.PrivilegeRoot:
+		case system.PrivilegeSudo:
+			if system.PrivilegePromptNeeded() {
+				return runningTasksMsg{}
+			}
+		default:
+			return runningTasksMsg{}
+		}
+		tasks, _ := system.RunningTasks()
+		return runningTasksMsg{count: len(tasks)}
+	}
+	return tea.Batch(tasks, xdebug)
 }
 
 // Update handles messages for the main menu
@@ -156,6 +290,14 @@ func (m MainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case runningTasksMsg:
+		m.runningTasks = msg.count
+		return m, nil
+
+	case xdebugActiveMsg:
+		m.xdebug = msg.statuses
+		return m, nil
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q":
@@ -200,6 +342,11 @@ func (m MainMenuModel) View() string {
 	if m.systemInfo != nil {
 		infoLines := []string{}
 
+		// Active remote server
+		if t := system.CurrentTransport(); t.IsRemote() {
+			infoLines = append(infoLines, m.theme.WarningStyle.Render(fmt.Sprintf("%s Managing remote server: %s", m.theme.Symbols.Info, t.Name())))
+		}
+
 		// Hostname with IP Address
 		if m.systemInfo.Hostname != "" {
 			ipAddr := system.GetPrimaryIP()
@@ -237,6 +384,27 @@ func (m MainMenuModel) View() string {
 		if !m.systemInfo.IsRoot {
 			infoLines = append(infoLines, "")
 			infoLines = append(infoLines, m.theme.WarningStyle.Render(m.theme.Symbols.Warning+" Not running as root"))
+			switch m.systemInfo.Privilege {
+			case "sudo":
+				infoLines = append(infoLines, m.theme.DescriptionStyle.Render("Changes ask for your sudo password"))
+			case "polkit":
+				infoLines = append(infoLines, m.theme.DescriptionStyle.Render("Changes ask through polkit"))
+			default:
+				infoLines = append(infoLines, m.theme.DescriptionStyle.Render("Browsing only: sudo is not available"))
+			}
+		}
+
+		if m.runningTasks > 0 {
+			infoLines = append(infoLines, "")
+			infoLines = append(infoLines, m.theme.WarningStyle.Render(fmt.Sprintf("%s %d background task(s) still running", m.theme.Symbols.Warning, m.runningTasks)))
+			infoLines = append(infoLines, m.theme.DescriptionStyle.Render("Open Background Tasks to follow them"))
+		}
+		if len(m.xdebug) > 0 {
+			infoLines = append(infoLines, "")
+			for _, st := range m.xdebug {
+				infoLines = append(infoLines, m.theme.WarningStyle.Render(fmt.Sprintf("%s Xdebug is on for PHP %s (%s)", m.theme.Symbols.Warning, st.Version, st.Label())))
+			}
+			infoLines = append(infoLines, m.theme.DescriptionStyle.Render("Turn it off under PHP-FPM Management "+m.theme.Symbols.ArrowRight+" Xdebug before production traffic"))
 		}
 
 		sysInfo = m.theme.InfoStyle.Render(lipgloss.JoinVertical(lipgloss.Left, infoLines...))
@@ -278,7 +446,7 @@ func (m MainMenuModel) View() string {
 	menu := lipgloss.JoinVertical(lipgloss.Left, menuItems...)
 
 	// Help
-	help := m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Select " + m.theme.Symbols.Bullet + " q: Quit")
+	help := m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Select " + m.theme.Symbols.Bullet + " Ctrl+P: Go to " + m.theme.Symbols.Bullet + " q: Quit")
 
 	// Combine all sections
 	content := lipgloss.JoinVertical(
@@ -303,3 +471,8 @@ func (m MainMenuModel) View() string {
 		bordered,
 	)
 }
+
+// Items returns the menu items in display order
+func (m MainMenuModel) Items() []MenuItem {
+	return m.flatItems
+}
@@ -0,0 +1,309 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/marketplace"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// marketplaceIndexMsg carries the verified index
+type marketplaceIndexMsg struct {
+	index *marketplace.Index
+	err   error
+}
+
+// marketplaceInstallMsg reports where an entry was vendored
+type marketplaceInstallMsg struct {
+	name string
+	dir  string
+	err  error
+}
+
+// MarketplaceModel browses the signed community index and vendors entries
+// for review
+type MarketplaceModel struct {
+	theme     *theme.Theme
+	width     int
+	height    int
+	config    marketplace.Config
+	entries   []marketplace.Entry
+	installed map[string]marketplace.Installed
+	cursor    int
+	viewing   bool // Showing the selected entry's details
+	loading   bool
+	busy      bool // Installing
+	editing   bool
+	form      *huh.Form
+	err       error
+	success   string
+}
+
+// NewMarketplaceModel creates the marketplace screen
+func NewMarketplaceModel() MarketplaceModel {
+	m := MarketplaceModel{theme: theme.DefaultTheme(), installed: marketplace.ListInstalled()}
+	m.config, m.err = marketplace.LoadConfig()
+	m.loading = m.err == nil && m.config.Enabled()
+	return m
+}
+
+// fetchIndex downloads and verifies the index in the background
+func fetchIndex(config marketplace.Config) tea.Cmd {
+	return func() tea.Msg {
+		index, err := config.Fetch()
+		return marketplaceIndexMsg{index: index, err: err}
+	}
+}
+
+// Init fetches the index when one is configured
+func (m MarketplaceModel) Init() tea.Cmd {
+	if !m.loading {
+		return nil
+	}
+	return fetchIndex(m.config)
+}
+
+func (m *MarketplaceModel) buildForm() *huh.Form {
+	url, key := m.config.URL, m.config.PublicKey
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("url").
+				Title("Index URL").
+				Description("HTTPS URL of the JSON index; its signature is read from <url>.sig").
+				Placeholder("https://example.com/ravact/index.json").
+				Value(&url),
+			huh.NewInput().
+				Key("public_key").
+				Title("Public Key").
+				Description("Base64 ed25519 key the index must be signed with (empty URL and key disable the marketplace)").
+				Value(&key),
+		).Title("Marketplace Index"),
+	).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// Update handles messages for the marketplace screen
+func (m MarketplaceModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case marketplaceIndexMsg:
+		m.loading = false
+		m.err = msg.err
+		m.entries = nil
+		if msg.index != nil {
+			m.entries = msg.index.Entries
+		}
+		if m.cursor >= len(m.entries) {
+			m.cursor = 0
+		}
+		return m, nil
+
+	case marketplaceInstallMsg:
+		m.busy = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.installed = marketplace.ListInstalled()
+		m.success = fmt.Sprintf("%s %s vendored to %s; review the files before using them", m.theme.Symbols.CheckMark, msg.name, msg.dir)
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.editing {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				m.editing = false
+				return m, nil
+			}
+			break
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			if m.viewing {
+				m.viewing = false
+				m.err, m.success = nil, ""
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: MainMenuScreen}
+			}
+		case "up", "k":
+			if !m.viewing && m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if !m.viewing && m.cursor < len(m.entries)-1 {
+				m.cursor++
+			}
+		case "enter", " ":
+			if !m.viewing && len(m.entries) > 0 {
+				m.viewing = true
+				m.err, m.success = nil, ""
+			}
+		case "i":
+			if m.viewing && !m.busy {
+				entry, config := m.entries[m.cursor], m.config
+				m.busy = true
+				m.err, m.success = nil, ""
+				return m, func() tea.Msg {
+					dir, err := config.Install(entry)
+					return marketplaceInstallMsg{name: entry.Name, dir: dir, err: err}
+				}
+			}
+		case "r":
+			if !m.loading && m.config.Enabled() {
+				m.loading = true
+				m.err, m.success = nil, ""
+				return m, fetchIndex(m.config)
+			}
+		case "e":
+			if !m.viewing {
+				m.err, m.success = nil, ""
+				m.form = m.buildForm()
+				m.editing = true
+				return m, m.form.Init()
+			}
+		}
+		return m, nil
+	}
+
+	if !m.editing {
+		return m, nil
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State == huh.StateCompleted {
+		return m.save()
+	}
+	return m, cmd
+}
+
+// save stores the index settings and fetches the index again
+func (m MarketplaceModel) save() (MarketplaceModel, tea.Cmd) {
+	m.editing = false
+	config := marketplace.Config{
+		URL:       strings.TrimSpace(m.form.GetString("url")),
+		PublicKey: strings.TrimSpace(m.form.GetString("public_key")),
+	}
+	if err := config.Save(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.config = config
+	m.entries = nil
+	m.err = nil
+	m.success = m.theme.Symbols.CheckMark + " Marketplace settings saved to " + marketplace.ConfigPath
+	if !config.Enabled() {
+		return m, nil
+	}
+	m.loading = true
+	return m, fetchIndex(config)
+}
+
+// View renders the marketplace screen
+func (m MarketplaceModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections := []string{m.theme.Title.Render("Marketplace"), ""}
+	help := "↑/↓: Navigate" + bullet + "Enter: Details" + bullet + "r: Refresh" + bullet + "e: Index settings" + bullet + "Esc: Back"
+
+	switch {
+	case m.editing:
+		sections = append(sections, m.form.View())
+		help = "Tab/Shift+Tab: Navigate" + bullet + "Enter: Next/Save" + bullet + "Esc: Cancel"
+
+	case !m.config.Enabled():
+		sections = append(sections,
+			m.theme.DescriptionStyle.Render("No marketplace index is configured."),
+			m.theme.DescriptionStyle.Render("Set an HTTPS index URL and the ed25519 key it is signed with to browse"),
+			m.theme.DescriptionStyle.Render("community blueprints and scripts."))
+		help = "e: Index settings" + bullet + "Esc: Back" + bullet + "q: Quit"
+
+	case m.loading:
+		sections = append(sections, m.theme.DescriptionStyle.Render("Fetching and verifying "+m.config.URL+"..."))
+
+	case m.viewing:
+		sections = append(sections, m.entryView(m.entries[m.cursor])...)
+		help = "i: Install for review" + bullet + "Esc: Back" + bullet + "q: Quit"
+
+	default:
+		sections = append(sections, m.theme.DescriptionStyle.Render(m.config.URL), "")
+		if len(m.entries) == 0 && m.err == nil {
+			sections = append(sections, m.theme.DescriptionStyle.Render("The index has no entries."))
+		}
+		for i, e := range m.entries {
+			cursor := "  "
+			style := m.theme.MenuItem
+			if i == m.cursor {
+				cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+				style = m.theme.SelectedItem
+			}
+			line := fmt.Sprintf("%-28s %-10s %s", e.Title, e.Kind, e.Version)
+			if inst, ok := m.installed[e.Name]; ok {
+				line += m.theme.DescriptionStyle.Render("  installed " + inst.Version)
+			}
+			sections = append(sections, style.Render(cursor+line))
+		}
+	}
+
+	if m.busy {
+		sections = append(sections, "", m.theme.DescriptionStyle.Render("Downloading and checking files..."))
+	}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// entryView lists an entry's details and the files install would write
+func (m MarketplaceModel) entryView(e marketplace.Entry) []string {
+	lines := []string{
+		m.theme.Subtitle.Render(e.Title),
+		m.theme.Label.Render("Kind:    ") + m.theme.MenuItem.Render(e.Kind),
+		m.theme.Label.Render("Version: ") + m.theme.MenuItem.Render(e.Version),
+		m.theme.Label.Render("Author:  ") + m.theme.MenuItem.Render(e.Author),
+	}
+	if len(e.Tags) > 0 {
+		lines = append(lines, m.theme.Label.Render("Tags:    ")+m.theme.MenuItem.Render(strings.Join(e.Tags, ", ")))
+	}
+	if e.Description != "" {
+		lines = append(lines, "", m.theme.DescriptionStyle.Render(e.Description))
+	}
+	lines = append(lines, "", m.theme.Label.Render("Files (into "+marketplace.Dir(e.Name)+"):"))
+	for _, f := range e.Files {
+		lines = append(lines, m.theme.MenuItem.Render(" "+m.theme.Symbols.Bullet+" "+f.Path))
+	}
+	if inst, ok := m.installed[e.Name]; ok {
+		lines = append(lines, "", m.theme.WarningStyle.Render(fmt.Sprintf("Version %s is installed; installing again replaces its files.", inst.Version)))
+	}
+	lines = append(lines, "", m.theme.DescriptionStyle.Render("Files are checked against the signed index and are never run by ravact."))
+	return lines
+}
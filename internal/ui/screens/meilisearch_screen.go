@@ -0,0 +1,372 @@
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+	"github.com/iperamuna/ravact/internal/vault"
+)
+
+// MeilisearchModel manages a Meilisearch server for Laravel Scout: its
+// listen address, master key, and the keys apps put in .env
+type MeilisearchModel struct {
+	theme   *theme.Theme
+	width   int
+	height  int
+	manager *system.MeilisearchManager
+
+	config   system.MeilisearchConfig
+	adminKey string // Default Admin API Key, when Meilisearch answered
+	status   string
+	reveal   bool // Show keys unmasked
+	actions  []string
+	cursor   int
+
+	form    *huh.Form
+	confirm Confirmation
+	mode    string // "menu", "env", "addr", "confirm"
+
+	vaultStatus string
+	err         error
+	success     string
+}
+
+// NewMeilisearchModel creates the Meilisearch screen
+func NewMeilisearchModel() MeilisearchModel {
+	m := MeilisearchModel{
+		theme:   theme.DefaultTheme(),
+		manager: system.NewMeilisearchManager(),
+		actions: []string{
+			"Write Scout Settings to .env",
+			"Listen Address",
+			"Rotate Master Key",
+			"Restart Meilisearch",
+			"View Logs",
+			"← Back to Configurations",
+		},
+		mode: "menu",
+	}
+	m.load()
+	return m
+}
+
+// load reads the config, service state, and admin API key
+func (m *MeilisearchModel) load() {
+	m.status = m.manager.Status()
+	config, err := m.manager.GetConfig()
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.config = config
+	m.adminKey = ""
+	if m.status == "active" && config.MasterKey != "" {
+		m.adminKey, _ = m.manager.AdminAPIKey(config)
+	}
+}
+
+// appKey returns the key apps should use: the admin API key, or the master
+// key when the API could not be reached
+func (m MeilisearchModel) appKey() string {
+	if m.adminKey != "" {
+		return m.adminKey
+	}
+	return m.config.MasterKey
+}
+
+func (m MeilisearchModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m MeilisearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	switch m.mode {
+	case "env", "addr":
+		return m.updateForm(msg)
+	case "confirm":
+		if key, ok := msg.(tea.KeyMsg); ok {
+			var result ConfirmResult
+			m.confirm, result = m.confirm.Update(key)
+			switch result {
+			case ConfirmAccepted:
+				m.mode = "menu"
+				return m.rotateKey()
+			case ConfirmCancelled:
+				m.mode = "menu"
+			}
+		}
+		return m, nil
+	}
+
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: ConfigMenuScreen}
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.actions)-1 {
+				m.cursor++
+			}
+		case "v":
+			m.reveal = !m.reveal
+		case "enter", " ":
+			return m.executeAction()
+		}
+	}
+	return m, nil
+}
+
+func (m MeilisearchModel) executeAction() (MeilisearchModel, tea.Cmd) {
+	m.err = nil
+	m.success = ""
+	m.vaultStatus = ""
+
+	switch m.actions[m.cursor] {
+	case "Write Scout Settings to .env":
+		if m.appKey() == "" {
+			m.err = fmt.Errorf("no master key in %s", system.MeilisearchConfigPath)
+			return m, nil
+		}
+		m.form = m.buildEnvForm()
+		m.mode = "env"
+		return m, m.form.Init()
+
+	case "Listen Address":
+		m.form = m.buildAddrForm()
+		m.mode = "addr"
+		return m, m.form.Init()
+
+	case "Rotate Master Key":
+		m.confirm = NewConfirmation("rotate", "Rotate Master Key",
+			"Generate a new master key and restart Meilisearch?\nEvery API key changes; update MEILISEARCH_KEY in each app afterwards.", ConfirmWarning)
+		m.mode = "confirm"
+
+	case "Restart Meilisearch":
+		if err := m.manager.Restart(); err != nil {
+			m.err = err
+		} else {
+			m.success = "✓ Meilisearch restarted"
+		}
+		m.load()
+
+	case "View Logs":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: LogViewerScreen,
+				Data:   map[string]interface{}{"unit": system.MeilisearchService},
+			}
+		}
+
+	case "← Back to Configurations":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: ConfigMenuScreen}
+		}
+	}
+	return m, nil
+}
+
+// rotateKey replaces the master key, restarts, and records the new key
+func (m MeilisearchModel) rotateKey() (tea.Model, tea.Cmd) {
+	key, err := m.manager.RotateMasterKey()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.vaultStatus = vault.RecordMessage("Meilisearch", "master_key", key, m.config.URL())
+	if err := m.manager.Restart(); err != nil {
+		m.err = err
+		m.load()
+		return m, nil
+	}
+	m.success = "✓ Master key rotated; rewrite MEILISEARCH_KEY in your apps"
+	m.load()
+	return m, nil
+}
+
+// buildEnvForm asks for the Laravel project whose .env gets the Scout settings
+func (m *MeilisearchModel) buildEnvForm() *huh.Form {
+	var project string
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("project").
+				Title("Laravel Project Path").
+				Description("SCOUT_DRIVER, MEILISEARCH_HOST, and MEILISEARCH_KEY are set in its .env").
+				Placeholder("/var/www/example.com").
+				Validate(func(s string) error {
+					if _, err := system.Stat(filepath.Join(strings.TrimSpace(s), ".env")); err != nil {
+						return fmt.Errorf("no .env in %s", s)
+					}
+					return nil
+				}).
+				Value(&project),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// buildAddrForm asks for the address the API listens on
+func (m *MeilisearchModel) buildAddrForm() *huh.Form {
+	addr := m.config.HTTPAddr
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("addr").
+				Title("Listen Address").
+				Description("Keep 127.0.0.1 unless other servers search this one; then firewall the port").
+				Validate(system.ValidateMeilisearchAddr).
+				Value(&addr),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// updateForm handles the .env and listen address forms
+func (m MeilisearchModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = "menu"
+			m.form = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	mode := m.mode
+	m.mode = "menu"
+	if mode == "env" {
+		path := filepath.Join(strings.TrimSpace(m.form.GetString("project")), ".env")
+		m.form = nil
+		m.writeEnv(path)
+		return m, nil
+	}
+
+	addr := strings.TrimSpace(m.form.GetString("addr"))
+	m.form = nil
+	if err := m.manager.SetListenAddress(addr); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := m.manager.Restart(); err != nil {
+		m.err = err
+	} else {
+		m.success = "✓ Meilisearch now listens on " + addr
+	}
+	m.load()
+	return m, nil
+}
+
+// writeEnv sets the Scout settings in a project's .env
+func (m *MeilisearchModel) writeEnv(path string) {
+	env, err := system.ParseEnvFile(path)
+	if err != nil {
+		m.err = err
+		return
+	}
+	for _, kv := range m.config.ScoutEnv(m.appKey()) {
+		env.Set(kv[0], kv[1])
+	}
+	backup, err := env.Save()
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.success = "✓ Scout settings written to " + path
+	if backup != "" {
+		m.success += " (backup: " + filepath.Base(backup) + ")"
+	}
+	if m.adminKey == "" {
+		m.success += "; Meilisearch was unreachable, so the master key was used"
+	}
+}
+
+// mask hides a key unless keys are revealed
+func (m MeilisearchModel) mask(key string) string {
+	if key == "" {
+		return "not available"
+	}
+	if m.reveal || len(key) <= 8 {
+		return key
+	}
+	return key[:4] + strings.Repeat("•", 12) + key[len(key)-4:]
+}
+
+func (m MeilisearchModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	header := m.theme.Title.Render("Meilisearch")
+
+	statusStyle := m.theme.ErrorStyle
+	if m.status == "active" {
+		statusStyle = m.theme.SuccessStyle
+	}
+	info := []string{
+		m.theme.Label.Render("Status:     ") + statusStyle.Render(m.status),
+		m.theme.Label.Render("Listen:     ") + m.theme.InfoStyle.Render(m.config.HTTPAddr),
+		m.theme.Label.Render("Env:        ") + m.theme.InfoStyle.Render(m.config.Env),
+		m.theme.Label.Render("Master Key: ") + m.theme.InfoStyle.Render(m.mask(m.config.MasterKey)),
+		m.theme.Label.Render("Admin Key:  ") + m.theme.InfoStyle.Render(m.mask(m.adminKey)),
+	}
+	sections := []string{header, "", lipgloss.JoinVertical(lipgloss.Left, info...), ""}
+
+	if m.mode != "menu" && m.form != nil {
+		sections = append(sections, m.form.View(), "", m.theme.Help.Render("Enter: Save • Esc: Cancel"))
+	} else {
+		for i, action := range m.actions {
+			if i == m.cursor {
+				sections = append(sections, m.theme.SelectedItem.Render(m.theme.KeyStyle.Render("▶ ")+action))
+			} else {
+				sections = append(sections, m.theme.MenuItem.Render("  "+action))
+			}
+		}
+		if m.success != "" {
+			sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+		}
+		if m.vaultStatus != "" {
+			sections = append(sections, m.theme.DescriptionStyle.Render(m.vaultStatus))
+		}
+		if m.err != nil {
+			sections = append(sections, "", m.theme.ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		}
+		sections = append(sections, "", m.theme.Help.Render("↑/↓: Navigate • Enter: Select • v: Show/Hide Keys • Esc: Back • q: Quit"))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
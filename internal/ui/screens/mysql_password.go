@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/theme"
+	"github.com/iperamuna/ravact/internal/vault"
 )
 
 // MySQLPasswordModel represents the MySQL password change screen
@@ -34,6 +35,7 @@ func NewMySQLPasswordModel(manager *system.MySQLManager) MySQLPasswordModel {
 	m.form = huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
+				Key("password").
 				Title("New Root Password").
 				Description("Enter a strong password for the MySQL root user").
 				Placeholder("Enter password...").
@@ -88,6 +90,7 @@ func (m MySQLPasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Check if form is completed
 	if m.form.State == huh.StateCompleted {
+		m.password = m.form.GetString("password")
 		err := m.manager.ChangeRootPassword(m.password)
 		if err != nil {
 			m.err = err
@@ -95,6 +98,7 @@ func (m MySQLPasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.form = huh.NewForm(
 				huh.NewGroup(
 					huh.NewInput().
+						Key("password").
 						Title("New Root Password").
 						Description("Enter a strong password for the MySQL root user").
 						Placeholder("Enter password...").
@@ -116,12 +120,13 @@ func (m MySQLPasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		// Success - navigate back
+		// Success - record the password and navigate back
+		vaultStatus := vault.RecordMessage("MySQL", "root", m.password, "")
 		return m, func() tea.Msg {
 			return NavigateMsg{
 				Screen: MySQLManagementScreen,
 				Data: map[string]interface{}{
-					"success": "Root password changed successfully",
+					"success": "Root password changed successfully (" + vaultStatus + ")",
 				},
 			}
 		}
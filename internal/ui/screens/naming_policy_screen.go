@@ -0,0 +1,234 @@
+package screens
+
+import (
+	"fmt"
+	"regexp"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// namingAuditMsg carries the existing resources that break the policy
+type namingAuditMsg struct {
+	violations []system.NamingViolation
+}
+
+// NamingPolicyModel edits the naming policy and audits existing resources
+// against it
+type NamingPolicyModel struct {
+	theme      *theme.Theme
+	width      int
+	height     int
+	policy     system.NamingPolicy
+	violations []system.NamingViolation
+	auditing   bool
+	editing    bool
+	form       *huh.Form
+	err        error
+	success    string
+}
+
+// NewNamingPolicyModel creates the naming policy screen
+func NewNamingPolicyModel() NamingPolicyModel {
+	m := NamingPolicyModel{theme: theme.DefaultTheme()}
+	m.policy, m.err = system.LoadNamingPolicy()
+	m.auditing = true
+	return m
+}
+
+// auditNames checks existing resources in the background; it lists
+// databases and users, which can take a moment
+func auditNames(policy system.NamingPolicy) tea.Cmd {
+	return func() tea.Msg {
+		return namingAuditMsg{violations: policy.AuditNames()}
+	}
+}
+
+func (m *NamingPolicyModel) buildForm() *huh.Form {
+	validPattern := func(s string) error {
+		if _, err := regexp.Compile(s); err != nil {
+			return fmt.Errorf("invalid regular expression: %v", err)
+		}
+		return nil
+	}
+	rule := func(kind, title string, r system.NamingRule) []huh.Field {
+		pattern, hint := r.Pattern, r.Hint
+		return []huh.Field{
+			huh.NewInput().
+				Key(kind + "_pattern").
+				Title(title + " Pattern").
+				Description("Regular expression names must match (empty allows any name)").
+				Placeholder(`^[a-z]+-(prod|staging)$`).
+				Validate(validPattern).
+				Value(&pattern),
+			huh.NewInput().
+				Key(kind + "_hint").
+				Title(title + " Hint").
+				Description("Shown when a name is rejected").
+				Placeholder("use <client>-<env>, such as acme-prod").
+				Value(&hint),
+		}
+	}
+
+	return huh.NewForm(
+		huh.NewGroup(rule(system.NamingSite, "Site Key", m.policy.Sites)...),
+		huh.NewGroup(rule(system.NamingDatabase, "Database Name", m.policy.Databases)...),
+		huh.NewGroup(rule(system.NamingUser, "Unix User", m.policy.Users)...),
+	).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// Init starts the audit
+func (m NamingPolicyModel) Init() tea.Cmd {
+	return auditNames(m.policy)
+}
+
+// Update handles messages for the naming policy screen
+func (m NamingPolicyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case namingAuditMsg:
+		m.auditing = false
+		m.violations = msg.violations
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.editing {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				m.editing = false
+				return m, nil
+			}
+			break
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: MainMenuScreen}
+			}
+		case "e":
+			m.err, m.success = nil, ""
+			m.form = m.buildForm()
+			m.editing = true
+			return m, m.form.Init()
+		case "r":
+			if !m.auditing {
+				m.auditing = true
+				return m, auditNames(m.policy)
+			}
+		}
+		return m, nil
+	}
+
+	if !m.editing {
+		return m, nil
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State == huh.StateCompleted {
+		return m.save()
+	}
+	return m, cmd
+}
+
+// save stores the submitted policy and audits again
+func (m NamingPolicyModel) save() (NamingPolicyModel, tea.Cmd) {
+	m.editing = false
+	rule := func(kind string) system.NamingRule {
+		return system.NamingRule{Pattern: m.form.GetString(kind + "_pattern"), Hint: m.form.GetString(kind + "_hint")}
+	}
+	policy := system.NamingPolicy{
+		Sites:     rule(system.NamingSite),
+		Databases: rule(system.NamingDatabase),
+		Users:     rule(system.NamingUser),
+	}
+	if err := policy.Save(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.policy = policy
+	m.err = nil
+	m.success = m.theme.Symbols.CheckMark + " Naming policy saved to " + system.NamingPolicyPath
+	m.auditing = true
+	return m, auditNames(policy)
+}
+
+// View renders the naming policy screen
+func (m NamingPolicyModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections := []string{m.theme.Title.Render("Naming Policy"), ""}
+
+	if m.editing {
+		sections = append(sections, m.form.View(), "",
+			m.theme.Help.Render("Tab/Shift+Tab: Navigate"+bullet+"Enter: Next/Save"+bullet+"Esc: Cancel"))
+		return m.render(sections)
+	}
+
+	for _, r := range []struct {
+		label string
+		rule  system.NamingRule
+	}{
+		{"Site keys:  ", m.policy.Sites},
+		{"Databases:  ", m.policy.Databases},
+		{"Unix users: ", m.policy.Users},
+	} {
+		value := m.theme.DescriptionStyle.Render("any name")
+		if r.rule.Pattern != "" {
+			value = m.theme.MenuItem.Render(r.rule.Pattern)
+			if r.rule.Hint != "" {
+				value += m.theme.DescriptionStyle.Render("  " + r.rule.Hint)
+			}
+		}
+		sections = append(sections, m.theme.Label.Render(r.label)+value)
+	}
+
+	sections = append(sections, "", m.theme.Subtitle.Render("Audit"))
+	switch {
+	case m.auditing:
+		sections = append(sections, m.theme.DescriptionStyle.Render("Checking existing sites, databases, and users..."))
+	case len(m.violations) == 0:
+		sections = append(sections, m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark+" Every existing resource matches the policy"))
+	default:
+		sections = append(sections, m.theme.WarningStyle.Render(fmt.Sprintf("%d existing resource(s) break the policy:", len(m.violations))))
+		for _, v := range m.violations {
+			sections = append(sections, m.theme.MenuItem.Render(fmt.Sprintf("  %-9s %s", v.Kind, v.Name)))
+		}
+		sections = append(sections, m.theme.DescriptionStyle.Render("Existing resources are not renamed; new ones must match."))
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+
+	sections = append(sections, "", m.theme.Help.Render("e: Edit policy"+bullet+"r: Audit again"+bullet+"Esc: Back"+bullet+"q: Quit"))
+	return m.render(sections)
+}
+
+func (m NamingPolicyModel) render(sections []string) string {
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -1,5 +1,7 @@
 package screens
 
+import "github.com/iperamuna/ravact/internal/models"
+
 // ScreenType represents different screens in the application
 type ScreenType int
 
@@ -50,6 +52,59 @@ const (
 	SSHKeyManagementScreen
 	TextDisplayScreen
 	LaravelQueueScreen
+	EnvEditorScreen
+	SecretsVaultScreen
+	ServersScreen
+	SiteNotesScreen
+	DashboardScreen
+	SettingsTransferScreen
+	LogViewerScreen
+	SystemdServicesScreen
+	ConfigHistoryScreen
+	SiteStackScreen
+	SSHDHardeningScreen
+	AutoUpdatesScreen
+	LaravelLintScreen
+	UpdatesScreen
+	SiteHardeningScreen
+	DBAccessScreen
+	SiteProtocolsScreen
+	SettingsScreen
+	NamingPolicyScreen
+	PHPIniScreen
+	MarketplaceScreen
+	NodeManagementScreen
+	NodeAppsScreen
+	WordPressSiteScreen
+	ComposerAuditScreen
+	ArtisanScreen
+	SiteBackupScreen
+	BackupTargetsScreen
+	ConfigDriftScreen
+	SudoManagementScreen
+	UserImportScreen
+	OffboardUserScreen
+	SFTPUserScreen
+	GitHistoryScreen
+	SupervisorProgramLogsScreen
+	SupervisorGroupsScreen
+	PgBouncerScreen
+	MeilisearchScreen
+	MailScreen
+	DockerScreen
+	SiteAccessScreen
+	SiteHeadersScreen
+	SiteRateLimitsScreen
+	SiteAnalyticsScreen
+	DiskUsageScreen
+	LogrotateScreen
+	SwapScreen
+	SetupBatchScreen
+	TasksScreen
+	XdebugScreen
+	ProfilerScreen
+	ExportersScreen
+	NetdataScreen
 )
 
 // NavigateMsg is sent when navigating between screens
@@ -68,6 +123,15 @@ type QuitMsg struct{}
 type ExecutionStartMsg struct {
 	Command     string
 	Description string
+	// Unprivileged runs the command as the current user, so read-only
+	// commands work without asking for a sudo password
+	Unprivileged bool
+	// Scripts installs several setup scripts as a batch on the batch
+	// screen instead of running Command
+	Scripts []models.SetupScript
+	// Removes names a setup script whose software Command uninstalls; its
+	// provisioning record is dropped once Command succeeds
+	Removes string
 }
 
 // ExecutionCompleteMsg is sent when execution completes
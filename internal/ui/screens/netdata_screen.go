@@ -0,0 +1,423 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// netdataStatusMsg carries Netdata's state and the server's address
+type netdataStatusMsg struct {
+	status system.NetdataStatus
+	hostIP string
+}
+
+// NetdataModel binds the Netdata dashboard to localhost or puts it behind
+// an nginx reverse proxy with basic auth
+type NetdataModel struct {
+	theme          *theme.Theme
+	width          int
+	height         int
+	netdataManager *system.NetdataManager
+	nginxManager   *system.NginxManager
+	webUser        string
+
+	status  system.NetdataStatus
+	hostIP  string
+	loading bool
+
+	// "" for the status, "proxy_form", "password_form", or "review"
+	mode   string
+	form   *huh.Form
+	review ConfigReview
+
+	// The proxy form's answers, kept until the review is accepted
+	domains    []string
+	user       string
+	password   string
+	useCertbot bool
+
+	err     error
+	success string
+}
+
+// NewNetdataModel creates the Netdata screen
+func NewNetdataModel() NetdataModel {
+	return NetdataModel{
+		theme:          theme.DefaultTheme(),
+		netdataManager: system.NewNetdataManager(),
+		nginxManager:   system.NewNginxManager(),
+		webUser:        detectWebUser(),
+		loading:        true,
+	}
+}
+
+// Init reads Netdata's state
+func (m NetdataModel) Init() tea.Cmd {
+	dm, nm := m.netdataManager, m.nginxManager
+	return func() tea.Msg {
+		return netdataStatusMsg{status: dm.Status(nm), hostIP: system.GetPrimaryIP()}
+	}
+}
+
+// Update handles messages for the Netdata screen
+func (m NetdataModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case netdataStatusMsg:
+		m.loading = false
+		m.status, m.hostIP = msg.status, msg.hostIP
+		return m, nil
+	}
+
+	switch m.mode {
+	case "proxy_form", "password_form":
+		return m.updateForm(msg)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.mode == "review" {
+		if keyMsg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		var result ConfirmResult
+		m.review, result = m.review.Update(keyMsg, m.height)
+		switch result {
+		case ConfirmAccepted:
+			m.mode = ""
+			return m.applyProxy()
+		case ConfirmCancelled:
+			m.mode = ""
+		}
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: ConfigMenuScreen}
+		}
+	case "r":
+		m.err, m.success = nil, ""
+		m.loading = true
+		return m, m.Init()
+	case "b":
+		if m.loading || !m.status.Installed {
+			break
+		}
+		m.err, m.success = nil, ""
+		bind := "127.0.0.1"
+		if system.NetdataLocalBind(m.status.Bind) {
+			if m.status.Proxy != nil {
+				m.err = fmt.Errorf("the reverse proxy needs the dashboard on localhost; remove the %s site first", system.NetdataSiteName)
+				break
+			}
+			bind = "*"
+		}
+		if err := m.netdataManager.SetBind(bind); err != nil {
+			m.err = err
+			break
+		}
+		m.status.Bind = bind
+		m.success = fmt.Sprintf("%s Dashboard now listens on %s", m.theme.Symbols.CheckMark, bind)
+		if bind == "*" {
+			m.success += "; anyone who reaches port 19999 can see it"
+		}
+	case "p":
+		if m.loading || !m.status.Installed {
+			break
+		}
+		m.err, m.success = nil, ""
+		if m.status.Proxy != nil {
+			return m.openPasswordForm()
+		}
+		return m.openProxyForm()
+	}
+	return m, nil
+}
+
+// credentialFields asks for the basic auth user and password
+func credentialFields(user, password *string) []huh.Field {
+	return []huh.Field{
+		huh.NewInput().
+			Key("user").
+			Title("Username").
+			Validate(func(s string) error {
+				s = strings.TrimSpace(s)
+				if s == "" || strings.ContainsAny(s, ": ") {
+					return fmt.Errorf("enter a username without spaces or colons")
+				}
+				return nil
+			}).
+			Value(user),
+		huh.NewInput().
+			Key("password").
+			Title("Password").
+			Description("At least 8 characters; nginx asks for it before showing the dashboard").
+			EchoMode(huh.EchoModePassword).
+			Validate(func(s string) error {
+				if len(s) < 8 {
+					return fmt.Errorf("use at least 8 characters")
+				}
+				return nil
+			}).
+			Value(password),
+	}
+}
+
+// openProxyForm asks for the proxy's domains and first user
+func (m NetdataModel) openProxyForm() (tea.Model, tea.Cmd) {
+	domains := ""
+	user := "admin"
+	password := ""
+	letsEncrypt := false
+
+	fields := append([]huh.Field{
+		huh.NewInput().
+			Key("domains").
+			Title("Domains").
+			Description("Space or comma separated, e.g. stats.example.com").
+			Validate(func(s string) error {
+				_, err := system.ParseDomains(s)
+				return err
+			}).
+			Value(&domains),
+	}, credentialFields(&user, &password)...)
+	fields = append(fields,
+		huh.NewConfirm().
+			Key("letsEncrypt").
+			Title("Obtain a Let's Encrypt certificate?").
+			Description("The domains must already point to this server").
+			Value(&letsEncrypt),
+	)
+
+	m.form = huh.NewForm(
+		huh.NewGroup(fields...).Title("Netdata Reverse Proxy"),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+	m.mode = "proxy_form"
+	return m, m.form.Init()
+}
+
+// openPasswordForm adds a proxy user or changes one's password
+func (m NetdataModel) openPasswordForm() (tea.Model, tea.Cmd) {
+	user := "admin"
+	password := ""
+	m.form = huh.NewForm(
+		huh.NewGroup(credentialFields(&user, &password)...).
+			Title("Netdata Proxy User").
+			Description("An existing user gets the new password"),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+	m.mode = "password_form"
+	return m, m.form.Init()
+}
+
+// updateForm passes messages to the proxy or password form
+func (m NetdataModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = ""
+			m.form = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	switch m.form.State {
+	case huh.StateCompleted:
+		mode := m.mode
+		m.mode = ""
+		m.user = strings.TrimSpace(m.form.GetString("user"))
+		m.password = m.form.GetString("password")
+		if mode == "password_form" {
+			m.form = nil
+			m.savePassword()
+			return m, nil
+		}
+		return m.planProxy()
+	case huh.StateAborted:
+		m.mode = ""
+		m.form = nil
+	}
+	return m, cmd
+}
+
+// savePassword writes the user to the proxy's htpasswd file
+func (m *NetdataModel) savePassword() {
+	if err := m.writeHtpasswd(); err != nil {
+		m.err = err
+		return
+	}
+	m.success = fmt.Sprintf("%s Saved %s; nginx reads the file on each request", m.theme.Symbols.CheckMark, m.user)
+}
+
+// writeHtpasswd hashes the form's user into the proxy's htpasswd file
+func (m NetdataModel) writeHtpasswd() error {
+	htpasswd, err := system.LoadHtpasswd(m.nginxManager.HtpasswdPath(system.NetdataSiteName))
+	if err != nil {
+		return err
+	}
+	if err := htpasswd.Set(m.user, m.password); err != nil {
+		return err
+	}
+	return htpasswd.Save(m.webUser)
+}
+
+// planProxy builds the proxy site from the form and shows it for review
+func (m NetdataModel) planProxy() (tea.Model, tea.Cmd) {
+	domains, err := system.ParseDomains(m.form.GetString("domains"))
+	m.useCertbot = m.form.GetBool("letsEncrypt")
+	m.form = nil
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.domains = system.ExpandDomains(domains)
+	change, err := system.PlanNetdataProxy(m.nginxManager, m.domains, m.useCertbot, m.useCertbot)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.review = NewConfigReview("create_site", m.nginxManager, change)
+	m.mode = "review"
+	return m, nil
+}
+
+// applyProxy keeps the dashboard on localhost, writes the first user and
+// the reviewed site, and reloads nginx
+func (m NetdataModel) applyProxy() (NetdataModel, tea.Cmd) {
+	if !system.NetdataLocalBind(m.status.Bind) {
+		if err := m.netdataManager.SetBind("127.0.0.1"); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.status.Bind = "127.0.0.1"
+	}
+	if err := m.writeHtpasswd(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := m.nginxManager.ApplyChange(m.review.Change); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := m.nginxManager.EnableSite(system.NetdataSiteName); err != nil {
+		m.err = fmt.Errorf("proxy created but failed to enable: %w", err)
+		return m, nil
+	}
+	if err := m.nginxManager.TestConfig(); err != nil {
+		m.err = fmt.Errorf("proxy created but config test failed: %w", err)
+		return m, nil
+	}
+	if err := m.nginxManager.ReloadNginx(); err != nil {
+		m.err = fmt.Errorf("proxy created but reload failed: %w", err)
+		return m, nil
+	}
+	if m.useCertbot {
+		if err := m.nginxManager.ObtainSSLCertificate(m.domains); err != nil {
+			m.err = fmt.Errorf("proxy created but certbot failed: %w", err)
+			return m, nil
+		}
+	}
+	m.success = fmt.Sprintf("%s Dashboard proxied behind basic auth as %s", m.theme.Symbols.CheckMark, m.user)
+	m.loading = true
+	return m, m.Init()
+}
+
+// View renders the Netdata screen
+func (m NetdataModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "review" {
+		return m.review.View(m.theme, m.width, m.height)
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections := []string{m.theme.Title.Render("Netdata"), ""}
+	help := ""
+
+	switch {
+	case m.form != nil:
+		sections = append(sections, m.form.View())
+		help = "Tab: Next" + bullet + "Enter: Submit" + bullet + "Esc: Cancel"
+
+	case m.loading:
+		sections = append(sections, m.theme.DescriptionStyle.Render("Checking Netdata..."))
+		help = "Esc: Back" + bullet + "q: Quit"
+
+	case !m.status.Installed:
+		sections = append(sections, m.theme.DescriptionStyle.Render("Netdata is not installed. Install it from Setup → Netdata."))
+		help = "r: Refresh" + bullet + "Esc: Back" + bullet + "q: Quit"
+
+	default:
+		service := m.theme.ErrorStyle.Render(m.status.Service)
+		if m.status.Service == "active" {
+			service = m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " running")
+		}
+		bind := m.status.Bind
+		if system.NetdataLocalBind(bind) {
+			bind += " (localhost only)"
+		} else {
+			bind = m.theme.WarningStyle.Render(bind + " (reachable from the network)")
+		}
+		proxy := "none"
+		if m.status.Proxy != nil {
+			proxy = strings.Join(m.status.Proxy.Domains, ", ") + " (basic auth)"
+		}
+		sections = append(sections,
+			m.theme.Label.Render("Service:   ")+service,
+			m.theme.Label.Render("Listening: ")+bind,
+			m.theme.Label.Render("Proxy:     ")+proxy,
+			m.theme.Label.Render("Dashboard: ")+m.theme.InfoStyle.Render(m.status.DashboardURL(m.hostIP)),
+		)
+		if m.status.Proxy == nil && system.NetdataLocalBind(m.status.Bind) {
+			sections = append(sections, "", m.theme.DescriptionStyle.Render(
+				fmt.Sprintf("Open an SSH tunnel first: ssh -L %d:127.0.0.1:%d user@%s", system.NetdataPort, system.NetdataPort, m.hostIP)))
+		}
+		toggle := "b: Listen on all interfaces"
+		if !system.NetdataLocalBind(m.status.Bind) {
+			toggle = "b: Bind to localhost"
+		}
+		proxyKey := "p: Add reverse proxy"
+		if m.status.Proxy != nil {
+			proxyKey = "p: Set proxy password"
+		}
+		help = toggle + bullet + proxyKey + bullet + "r: Refresh" + bullet + "Esc: Back"
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -26,11 +26,18 @@ type NginxConfigModel struct {
 	height       int
 	nginxManager *system.NginxManager
 	sites        []system.NginxSite
+	allSites     []system.NginxSite
+	tags         TagFilter
+	health       map[string]system.SiteHealthResult // Last health check per site
 	cursor       int
 	viewMode     NginxViewMode
 	scrollOffset int
 	maxVisible   int
 	err          error
+	success      string
+
+	confirm    Confirmation
+	confirming bool
 }
 
 // NewNginxConfigModel creates a new Nginx config model
@@ -43,11 +50,15 @@ func NewNginxConfigModel() NginxConfigModel {
 	}
 	
 	sites, _ := nginxManager.GetAllSites()
+	health, _ := system.LoadSiteHealthResults()
 
 	return NginxConfigModel{
 		theme:        theme.DefaultTheme(),
 		nginxManager: nginxManager,
 		sites:        sites,
+		allSites:     sites,
+		tags:         NewTagFilter(system.TagKindSite),
+		health:       health,
 		cursor:       0,
 		viewMode:     SitesListView,
 		scrollOffset: 0,
@@ -55,6 +66,60 @@ func NewNginxConfigModel() NginxConfigModel {
 	}
 }
 
+// reloadSites reloads all sites and applies the tag filter
+func (m *NginxConfigModel) reloadSites() {
+	m.allSites, _ = m.nginxManager.GetAllSites()
+	m.health, _ = system.LoadSiteHealthResults()
+	m.applyTagFilter()
+}
+
+// applyTagFilter limits the visible sites to the active tag
+func (m *NginxConfigModel) applyTagFilter() {
+	m.sites = filterByTag(m.tags, m.allSites, func(s system.NginxSite) string { return s.Name })
+	if m.cursor >= len(m.sites) {
+		m.cursor = 0
+		m.scrollOffset = 0
+	}
+}
+
+// setTaggedSitesEnabled enables or disables every site matching the tag filter
+func (m NginxConfigModel) setTaggedSitesEnabled(enable bool) NginxConfigModel {
+	var changed []string
+	for _, site := range m.sites {
+		if site.IsEnabled == enable {
+			continue
+		}
+		var err error
+		if enable {
+			err = m.nginxManager.EnableSite(site.Name)
+		} else {
+			err = m.nginxManager.DisableSite(site.Name)
+		}
+		if err != nil {
+			m.err = fmt.Errorf("%s: %w", site.Name, err)
+			break
+		}
+		changed = append(changed, site.Name)
+	}
+
+	if len(changed) > 0 {
+		if err := m.nginxManager.TestConfig(); err != nil {
+			m.err = fmt.Errorf("config test failed after updating %s: %w", strings.Join(changed, ", "), err)
+		} else if err := m.nginxManager.ReloadNginx(); err != nil {
+			m.err = err
+		}
+	}
+	if m.err == nil {
+		verb := "disabled"
+		if enable {
+			verb = "enabled"
+		}
+		m.success = fmt.Sprintf("%s %d site(s) tagged #%s %s", m.theme.Symbols.CheckMark, len(changed), m.tags.Active, verb)
+	}
+	m.reloadSites()
+	return m
+}
+
 // Init initializes the Nginx config screen
 func (m NginxConfigModel) Init() tea.Cmd {
 	return nil
@@ -69,6 +134,33 @@ func (m NginxConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.confirming {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.confirm, result = m.confirm.Update(msg)
+			switch result {
+			case ConfirmAccepted:
+				m.confirming = false
+				return m.setTaggedSitesEnabled(m.confirm.Action == "enable_tagged"), nil
+			case ConfirmCancelled:
+				m.confirming = false
+			}
+			return m, nil
+		}
+
+		if m.tags.Editing() {
+			var done bool
+			m.tags, done = m.tags.Update(msg)
+			if done {
+				m.applyTagFilter()
+			}
+			return m, nil
+		}
+
+		m.success = ""
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -78,6 +170,33 @@ func (m NginxConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return NavigateMsg{Screen: MainMenuScreen}
 			}
 
+		case "T":
+			// Edit tags of the selected site
+			if m.viewMode == SitesListView && len(m.sites) > 0 {
+				m.tags = m.tags.StartEdit(m.sites[m.cursor].Name)
+			}
+
+		case "f":
+			// Filter by the next tag
+			if m.viewMode == SitesListView {
+				m.tags = m.tags.Cycle()
+				m.cursor = 0
+				m.scrollOffset = 0
+				m.applyTagFilter()
+			}
+
+		case "E", "D":
+			// Enable or disable every site with the active tag
+			if m.viewMode == SitesListView && m.tags.Active != "" && len(m.sites) > 0 {
+				action, verb := "enable_tagged", "Enable"
+				if msg.String() == "D" {
+					action, verb = "disable_tagged", "Disable"
+				}
+				m.confirm = NewConfirmation(action, verb+" Tagged Sites",
+					fmt.Sprintf("%s all %d site(s) tagged #%s and reload Nginx?", verb, len(m.sites), m.tags.Active), ConfirmWarning)
+				m.confirming = true
+			}
+
 		case "tab":
 			// Switch between sites and global config
 			if m.viewMode == SitesListView {
@@ -105,9 +224,9 @@ func (m NginxConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "r":
 			// Refresh sites list
-			m.sites, _ = m.nginxManager.GetAllSites()
 			m.cursor = 0
 			m.scrollOffset = 0
+			m.reloadSites()
 
 		case "a":
 			// Add new site
@@ -122,6 +241,14 @@ func (m NginxConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+		case "w":
+			// Provision a WordPress site
+			if m.viewMode == SitesListView {
+				return m, func() tea.Msg {
+					return NavigateMsg{Screen: WordPressSiteScreen}
+				}
+			}
+
 		case "e":
 			// Enable/Disable site
 			if m.viewMode == SitesListView && len(m.sites) > 0 {
@@ -137,7 +264,7 @@ func (m NginxConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// Test config
 					if testErr := m.nginxManager.TestConfig(); testErr == nil {
 						m.nginxManager.ReloadNginx()
-						m.sites, _ = m.nginxManager.GetAllSites()
+						m.reloadSites()
 					}
 				}
 			}
@@ -176,6 +303,10 @@ func (m NginxConfigModel) View() string {
 		return "Loading..."
 	}
 
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
 	// Header
 	// Header with host info
 	hostInfo := system.GetHostInfo()
@@ -215,7 +346,10 @@ func (m NginxConfigModel) View() string {
 	// Help text
 	help := ""
 	if m.viewMode == SitesListView {
-		help = m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Edit " + m.theme.Symbols.Bullet + " a: Add " + m.theme.Symbols.Bullet + " e: Enable/Disable " + m.theme.Symbols.Bullet + " t: Test " + m.theme.Symbols.Bullet + " r: Refresh " + m.theme.Symbols.Bullet + " Esc: Back")
+		help = m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Edit " + m.theme.Symbols.Bullet + " a: Add " + m.theme.Symbols.Bullet + " w: New WordPress " + m.theme.Symbols.Bullet + " e: Enable/Disable " + m.theme.Symbols.Bullet + " t: Test " + m.theme.Symbols.Bullet + " r: Refresh" + m.tags.Help(m.theme) + " " + m.theme.Symbols.Bullet + " Esc: Back")
+		if m.tags.Active != "" {
+			help += "\n" + m.theme.Help.Render("E/D: Enable/Disable all sites tagged #"+m.tags.Active)
+		}
 	} else {
 		help = m.theme.Help.Render("Tab: Switch to Sites " + m.theme.Symbols.Bullet + " Esc: Back " + m.theme.Symbols.Bullet + " q: Quit")
 	}
@@ -232,6 +366,14 @@ func (m NginxConfigModel) View() string {
 	if errorMsg != "" {
 		fullContent = lipgloss.JoinVertical(lipgloss.Left, fullContent, errorMsg, "")
 	}
+	if m.success != "" {
+		fullContent = lipgloss.JoinVertical(lipgloss.Left, fullContent, m.theme.SuccessStyle.Render(m.success), "")
+	}
+	if m.viewMode == SitesListView {
+		if tagLine := m.tags.View(m.theme); tagLine != "" {
+			fullContent = lipgloss.JoinVertical(lipgloss.Left, fullContent, tagLine, "")
+		}
+	}
 
 	fullContent = lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -256,6 +398,9 @@ func (m NginxConfigModel) View() string {
 // renderSitesView renders the sites list
 func (m NginxConfigModel) renderSitesView() string {
 	if len(m.sites) == 0 {
+		if m.tags.Active != "" {
+			return m.theme.WarningStyle.Render("No sites tagged #" + m.tags.Active + ". Press 'f' to change the filter.")
+		}
 		return m.theme.WarningStyle.Render("No sites configured. Press 'a' to add a new site.")
 	}
 
@@ -280,6 +425,7 @@ func (m NginxConfigModel) renderSitesView() string {
 		headerStyle.Render("Domain"),
 		headerStyle.Render("Status"),
 		headerStyle.Render("SSL"),
+		headerStyle.Render("Health"),
 		headerStyle.Render("Root Directory"),
 	}
 	headerRow := lipgloss.JoinHorizontal(
@@ -288,13 +434,14 @@ func (m NginxConfigModel) renderSitesView() string {
 		lipgloss.NewStyle().Width(25).Render(headers[1]),
 		lipgloss.NewStyle().Width(12).Render(headers[2]),
 		lipgloss.NewStyle().Width(8).Render(headers[3]),
-		lipgloss.NewStyle().Width(35).Render(headers[4]),
+		lipgloss.NewStyle().Width(10).Render(headers[4]),
+		lipgloss.NewStyle().Width(35).Render(headers[5]),
 	)
 
 	// Table rows (with pagination)
 	var rows []string
 	rows = append(rows, headerRow)
-	rows = append(rows, strings.Repeat("─", 105))
+	rows = append(rows, strings.Repeat("─", 115))
 
 	// Calculate visible range
 	startIdx := m.scrollOffset
@@ -351,6 +498,17 @@ func (m NginxConfigModel) renderSitesView() string {
 		}
 		sslCol := lipgloss.NewStyle().Width(8).Render(sslBadge)
 
+		// Last health check
+		healthBadge := m.theme.DescriptionStyle.Render("-")
+		if result, ok := m.health[site.Name]; ok {
+			if result.OK {
+				healthBadge = m.theme.SuccessStyle.Render(fmt.Sprintf("%s %d", m.theme.Symbols.CheckMark, result.Status))
+			} else {
+				healthBadge = m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark + " Down")
+			}
+		}
+		healthCol := lipgloss.NewStyle().Width(10).Render(healthBadge)
+
 		// Root directory
 		rootDir := site.RootDir
 		if rootDir == "" {
@@ -368,8 +526,12 @@ func (m NginxConfigModel) renderSitesView() string {
 			domainCol,
 			statusCol,
 			sslCol,
+			healthCol,
 			rootCol,
 		)
+		if badges := m.tags.Badges(m.theme, site.Name); badges != "" {
+			row = lipgloss.JoinHorizontal(lipgloss.Left, row, " ", badges)
+		}
 
 		if i == m.cursor {
 			row = m.theme.SelectedItem.Render(row)
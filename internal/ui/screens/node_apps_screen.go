@@ -0,0 +1,540 @@
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// Ways a Node app can be run
+const (
+	nodeRunnerSystemd = "systemd"
+	nodeRunnerPM2     = "pm2"
+)
+
+// nodeAppRow is one line of the list: a systemd app or a PM2 process
+type nodeAppRow struct {
+	app  *system.NodeApp
+	proc *system.PM2Process
+	user string // Owner of the PM2 list the process is in
+}
+
+// NodeAppsModel runs Node apps as services, either as systemd units ravact
+// generates or in a user's PM2 process list
+type NodeAppsModel struct {
+	theme        *theme.Theme
+	width        int
+	height       int
+	site         system.NginxSite // Set when opened for a site
+	projectDir   string
+	owner        string // Owner of the project directory
+	manager      system.NodeManager
+	rows         []nodeAppRow
+	pm2Users     []string // Users whose PM2 list is shown
+	pm2Missing   []string // Of pm2Users, those without pm2 installed
+	cursor       int
+	actions      []string
+	actionCursor int
+	inActions    bool
+	form         *huh.Form
+	formKind     string // "app" or "pm2"
+	editing      *system.NodeApp
+	confirm      Confirmation
+	confirming   bool
+	err          error
+}
+
+// NewNodeAppsModel creates the Node apps screen
+func NewNodeAppsModel() NodeAppsModel {
+	m := NodeAppsModel{theme: theme.DefaultTheme()}
+	m.load()
+	return m
+}
+
+// WithSite limits the screen to a site's apps and its owner's PM2 list
+func (m NodeAppsModel) WithSite(site system.NginxSite) NodeAppsModel {
+	m.site = site
+	m.projectDir = system.SiteProjectDir(site.RootDir)
+	m.owner = system.PathOwner(m.projectDir)
+	m.load()
+	return m
+}
+
+// load refreshes the systemd apps and PM2 processes
+func (m *NodeAppsModel) load() {
+	m.manager = system.DetectNodeManager()
+	m.rows = nil
+	m.err = nil
+
+	apps, err := system.ListNodeApps()
+	if err != nil {
+		m.err = err
+	}
+	for i := range apps {
+		if m.projectDir != "" && !strings.HasPrefix(apps[i].Dir+"/", m.projectDir+"/") {
+			continue
+		}
+		m.rows = append(m.rows, nodeAppRow{app: &apps[i]})
+	}
+
+	m.pm2Users = m.findPM2Users()
+	m.pm2Missing = nil
+	for _, user := range m.pm2Users {
+		if !system.PM2Installed(user) {
+			m.pm2Missing = append(m.pm2Missing, user)
+			continue
+		}
+		processes, err := system.PM2Processes(user)
+		if err != nil {
+			m.err = err
+			continue
+		}
+		for i := range processes {
+			if m.projectDir != "" && !strings.HasPrefix(processes[i].Dir+"/", m.projectDir+"/") {
+				continue
+			}
+			m.rows = append(m.rows, nodeAppRow{proc: &processes[i], user: user})
+		}
+	}
+	if m.cursor >= len(m.rows) {
+		m.cursor = 0
+	}
+}
+
+// findPM2Users returns the site owner, or every user with a `pm2 startup`
+// unit when no site is selected
+func (m NodeAppsModel) findPM2Users() []string {
+	if m.site.Name != "" {
+		if m.owner == "" || m.owner == "root" {
+			return nil
+		}
+		return []string{m.owner}
+	}
+	entries, err := system.ReadDir(system.SystemdLocalUnitDir)
+	if err != nil {
+		return nil
+	}
+	var users []string
+	for _, e := range entries {
+		if user, ok := strings.CutPrefix(e.Name(), "pm2-"); ok && strings.HasSuffix(user, ".service") {
+			users = append(users, strings.TrimSuffix(user, ".service"))
+		}
+	}
+	return users
+}
+
+// Init initializes the Node apps screen
+func (m NodeAppsModel) Init() tea.Cmd {
+	return nil
+}
+
+// buildAppForm asks for a new app, or the settings of app when editing
+func (m *NodeAppsModel) buildAppForm(app *system.NodeApp) *huh.Form {
+	name, dir, user, command, port, nodeEnv := "", m.projectDir, m.owner, "npm start", "3000", "production"
+	if m.site.Name != "" {
+		name = strings.ToLower(strings.NewReplacer(".", "-", "_", "-").Replace(m.site.Name))
+	}
+	if app != nil {
+		name, dir, user, command, port, nodeEnv = app.Name, app.Dir, app.User, app.Command, app.Port, app.NodeEnv
+	}
+	runner := nodeRunnerSystemd
+
+	fields := []huh.Field{
+		huh.NewInput().Key("name").Title("Name").
+			Description("Lowercase letters, digits, and dashes; the unit is node-<name>.service").
+			Value(&name),
+		huh.NewInput().Key("dir").Title("Directory").Value(&dir),
+		huh.NewInput().Key("user").Title("Run As User").Value(&user),
+		huh.NewInput().Key("command").Title("Command").
+			Description("Such as npm start or node server.js").
+			Value(&command),
+		huh.NewInput().Key("port").Title("Port").
+			Description("Exported as PORT; leave empty if the app does not listen").
+			Value(&port),
+		huh.NewInput().Key("node_env").Title("NODE_ENV").Value(&nodeEnv),
+	}
+	if app == nil {
+		fields = append(fields, huh.NewSelect[string]().Key("runner").Title("Run With").
+			Options(
+				huh.NewOption("systemd unit generated by ravact", nodeRunnerSystemd),
+				huh.NewOption("PM2 (the user's process list)", nodeRunnerPM2),
+			).
+			Value(&runner))
+	}
+
+	title := "Add Node App"
+	if app != nil {
+		title = "Edit " + app.UnitName()
+	}
+	return huh.NewForm(huh.NewGroup(fields...).Title(title)).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// buildPM2Form asks which user to set PM2 up for
+func (m *NodeAppsModel) buildPM2Form() *huh.Form {
+	user := m.owner
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("user").
+				Title("User").
+				Description("PM2 is installed globally and its saved processes start on boot for this user").
+				Value(&user),
+		).Title("Set Up PM2"),
+	).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// run hands a privileged script to the execution screen
+func (m NodeAppsModel) run(script, description string, err error) (NodeAppsModel, tea.Cmd) {
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{
+			Command:     "sudo bash -c " + system.ShellQuote(script),
+			Description: description,
+		}
+	}
+}
+
+// Update handles messages for the Node apps screen
+func (m NodeAppsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.form != nil {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				m.form = nil
+				return m, nil
+			}
+			break
+		}
+		if m.confirming {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.confirm, result = m.confirm.Update(msg)
+			switch result {
+			case ConfirmAccepted:
+				m.confirming = false
+				return m.remove(m.rows[m.cursor])
+			case ConfirmCancelled:
+				m.confirming = false
+			}
+			return m, nil
+		}
+		if m.inActions {
+			return m.updateActions(msg)
+		}
+		return m.updateList(msg)
+	}
+
+	if m.form == nil {
+		return m, nil
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State == huh.StateCompleted {
+		return m.submit()
+	}
+	return m, cmd
+}
+
+func (m NodeAppsModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		if m.site.Name != "" {
+			return m, func() tea.Msg {
+				return NavigateMsg{
+					Screen: ConfigEditorScreen,
+					Data: map[string]interface{}{
+						"action": "edit_nginx_site",
+						"site":   m.site,
+					},
+				}
+			}
+		}
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: ConfigMenuScreen}
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+	case "enter", " ":
+		if len(m.rows) > 0 {
+			m.err = nil
+			m.inActions = true
+			m.actionCursor = 0
+			if m.rows[m.cursor].app != nil {
+				m.actions = []string{"Restart", "Start", "Stop", "Enable", "Disable", "View Logs", "Edit", "Remove", "← Back"}
+			} else {
+				m.actions = []string{"Restart", "Start", "Stop", "View Logs", "Remove", "← Back"}
+			}
+		}
+	case "a":
+		m.err = nil
+		m.editing = nil
+		m.formKind = "app"
+		m.form = m.buildAppForm(nil)
+		return m, m.form.Init()
+	case "p":
+		m.err = nil
+		m.formKind = "pm2"
+		m.form = m.buildPM2Form()
+		return m, m.form.Init()
+	case "r":
+		m.load()
+	}
+	return m, nil
+}
+
+func (m NodeAppsModel) updateActions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "backspace", "q":
+		m.inActions = false
+	case "up", "k":
+		if m.actionCursor > 0 {
+			m.actionCursor--
+		}
+	case "down", "j":
+		if m.actionCursor < len(m.actions)-1 {
+			m.actionCursor++
+		}
+	case "enter", " ":
+		return m.runAction(m.actions[m.actionCursor], m.rows[m.cursor])
+	}
+	return m, nil
+}
+
+// runAction performs an action on the selected app or process
+func (m NodeAppsModel) runAction(action string, row nodeAppRow) (tea.Model, tea.Cmd) {
+	switch action {
+	case "← Back":
+		m.inActions = false
+		return m, nil
+	case "Remove":
+		name := row.label()
+		m.confirm = NewDangerConfirmation(action, "Remove Node App",
+			fmt.Sprintf("Stop %s and remove it? The app's files are not touched.", name), name)
+		m.confirming = true
+		return m, nil
+	}
+
+	if row.app != nil {
+		unit := row.app.UnitName()
+		switch action {
+		case "View Logs":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: LogViewerScreen, Data: map[string]interface{}{"unit": unit}}
+			}
+		case "Edit":
+			m.inActions = false
+			m.editing = row.app
+			m.formKind = "app"
+			m.form = m.buildAppForm(row.app)
+			return m, m.form.Init()
+		}
+		command, description := system.SystemctlCommand(strings.ToLower(action), unit)
+		return m, func() tea.Msg {
+			return ExecutionStartMsg{Command: command, Description: description}
+		}
+	}
+
+	verb := strings.ToLower(action)
+	description := fmt.Sprintf("pm2 %s %s (%s)", verb, row.proc.Name, row.user)
+	if action == "View Logs" {
+		verb = "logs"
+		description = fmt.Sprintf("PM2 logs of %s (%s)", row.proc.Name, row.user)
+	}
+	command := system.PM2Command(row.user, verb, row.proc.Name)
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{Command: command, Description: description}
+	}
+}
+
+// remove deletes a systemd app or a PM2 process
+func (m NodeAppsModel) remove(row nodeAppRow) (tea.Model, tea.Cmd) {
+	m.inActions = false
+	if row.app != nil {
+		return m.run(system.NodeAppRemoveScript(*row.app), "Remove "+row.app.UnitName(), nil)
+	}
+	command := system.PM2Command(row.user, "delete", row.proc.Name)
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{Command: command, Description: fmt.Sprintf("pm2 delete %s (%s)", row.proc.Name, row.user)}
+	}
+}
+
+// submit runs the completed form
+func (m NodeAppsModel) submit() (tea.Model, tea.Cmd) {
+	form := m.form
+	m.form = nil
+	if m.formKind == "pm2" {
+		user := strings.TrimSpace(form.GetString("user"))
+		script, err := system.PM2SetupScript(user, m.manager)
+		return m.run(script, "Set up PM2 for "+user, err)
+	}
+
+	app := system.NodeApp{
+		Name:    strings.TrimSpace(form.GetString("name")),
+		Dir:     filepath.Clean(strings.TrimSpace(form.GetString("dir"))),
+		User:    strings.TrimSpace(form.GetString("user")),
+		Command: strings.TrimSpace(form.GetString("command")),
+		Port:    strings.TrimSpace(form.GetString("port")),
+		NodeEnv: strings.TrimSpace(form.GetString("node_env")),
+	}
+	if m.editing == nil && form.GetString("runner") == nodeRunnerPM2 {
+		command, err := system.PM2StartScript(app)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		return m, func() tea.Msg {
+			return ExecutionStartMsg{Command: command, Description: fmt.Sprintf("pm2 start %s (%s)", app.Name, app.User)}
+		}
+	}
+
+	script, err := system.NodeAppInstallScript(app, m.manager)
+	if err == nil && m.editing != nil && m.editing.Name != app.Name {
+		// Renamed: the old unit goes away
+		script += system.NodeAppRemoveScript(*m.editing)
+	}
+	return m.run(script, "Run "+app.UnitName(), err)
+}
+
+// label names a row for messages and confirmations
+func (r nodeAppRow) label() string {
+	if r.app != nil {
+		return r.app.UnitName()
+	}
+	return r.proc.Name
+}
+
+// View renders the Node apps screen
+func (m NodeAppsModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	title := "Node Apps"
+	if m.site.Name != "" {
+		title = "Node Apps: " + m.site.Name
+	}
+	sections := []string{m.theme.Title.Render(title), ""}
+	var help string
+
+	switch {
+	case m.form != nil:
+		sections = append(sections, m.form.View())
+		help = "Tab/Shift+Tab: Navigate" + bullet + "Enter: Next/Save" + bullet + "Esc: Cancel"
+
+	case m.inActions:
+		row := m.rows[m.cursor]
+		sections = append(sections, m.theme.Subtitle.Render(row.label()), "")
+		for i, action := range m.actions {
+			cursor := "  "
+			style := m.theme.MenuItem
+			if i == m.actionCursor {
+				cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+				style = m.theme.SelectedItem
+			}
+			sections = append(sections, style.Render(cursor+action))
+		}
+		help = "↑/↓: Navigate" + bullet + "Enter: Run" + bullet + "Esc: Back"
+
+	default:
+		if m.projectDir != "" {
+			sections = append(sections, m.theme.DescriptionStyle.Render("Project: "+m.projectDir), "")
+		}
+		if len(m.rows) == 0 {
+			sections = append(sections, m.theme.DescriptionStyle.Render("No Node apps are running as services. Press a to add one."))
+		}
+		for i, row := range m.rows {
+			cursor := "  "
+			style := m.theme.MenuItem
+			if i == m.cursor {
+				cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+				style = m.theme.SelectedItem
+			}
+			sections = append(sections, style.Render(cursor+m.rowLine(row)))
+		}
+		for _, user := range m.pm2Missing {
+			sections = append(sections, "", m.theme.WarningStyle.Render("PM2 is not installed for "+user+". Press p to set it up."))
+		}
+		help = "↑/↓: Navigate" + bullet + "Enter: Actions" + bullet + "a: Add app" + bullet + "p: Set up PM2" + bullet + "r: Refresh" + bullet + "Esc: Back"
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// rowLine renders a list entry with its state
+func (m NodeAppsModel) rowLine(row nodeAppRow) string {
+	if row.app != nil {
+		a := row.app
+		status := m.nodeStatus(a.Active, a.Active == "active")
+		detail := a.Command
+		if a.Port != "" {
+			detail += " :" + a.Port
+		}
+		if !a.Enabled {
+			detail += ", not started on boot"
+		}
+		return fmt.Sprintf("%s %-28s %-8s %s", status, a.UnitName(), "systemd", m.theme.DescriptionStyle.Render(detail))
+	}
+	p := row.proc
+	status := m.nodeStatus(p.Status, p.Status == "online")
+	detail := fmt.Sprintf("%s, %d MB, %.0f%% CPU, %d restarts", row.user, p.Memory/(1024*1024), p.CPU, p.Restarts)
+	return fmt.Sprintf("%s %-28s %-8s %s", status, p.Name, "pm2", m.theme.DescriptionStyle.Render(detail))
+}
+
+// nodeStatus renders a service state as a coloured dot
+func (m NodeAppsModel) nodeStatus(state string, up bool) string {
+	switch {
+	case up:
+		return m.theme.SuccessStyle.Render("●")
+	case state == "failed" || state == "errored":
+		return m.theme.ErrorStyle.Render("✗")
+	}
+	return m.theme.WarningStyle.Render("○")
+}
@@ -0,0 +1,333 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// NodeManagementModel installs a system-wide Node version manager and the
+// Node versions sites use. Opened for a site, it pins the site's version in
+// .nvmrc.
+type NodeManagementModel struct {
+	theme      *theme.Theme
+	width      int
+	height     int
+	manager    system.NodeManager
+	versions   []string
+	defaultVer string
+	systemNode string // Node installed by the OS package manager
+	cursor     int
+	site       system.NginxSite // Set when pinning a site's version
+	siteNvmrc  string
+	removing   bool // Confirming removal of the selected version
+	form       *huh.Form
+	err        error
+	success    string
+}
+
+// nodeManagerChoices are offered when no manager is installed
+var nodeManagerChoices = []struct{ kind, title, description string }{
+	{system.NodeManagerFnm, "fnm", "Fast Node Manager: a single binary, reads .nvmrc and .node-version"},
+	{system.NodeManagerNvm, "nvm", "Node Version Manager: the shell script most guides assume"},
+}
+
+// NewNodeManagementModel creates the Node versions screen
+func NewNodeManagementModel() NodeManagementModel {
+	m := NodeManagementModel{theme: theme.DefaultTheme()}
+	m.load()
+	return m
+}
+
+// WithSite opens the screen to pin a site's Node version
+func (m NodeManagementModel) WithSite(site system.NginxSite) NodeManagementModel {
+	m.site = site
+	m.siteNvmrc = system.ReadNvmrc(site.RootDir)
+	return m
+}
+
+// load refreshes the manager and its versions
+func (m *NodeManagementModel) load() {
+	m.manager = system.DetectNodeManager()
+	m.versions, m.err = m.manager.Versions()
+	m.defaultVer = m.manager.Default()
+	m.systemNode = ""
+	if out, err := system.Command("/usr/bin/node", "--version").Output(); err == nil {
+		m.systemNode = strings.TrimSpace(string(out))
+	}
+	if m.cursor >= len(m.versions) {
+		m.cursor = 0
+	}
+}
+
+// Init initializes the Node versions screen
+func (m NodeManagementModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *NodeManagementModel) buildInstallForm() *huh.Form {
+	version := "lts/*"
+	if m.siteNvmrc != "" {
+		version = m.siteNvmrc
+	}
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("version").
+				Title("Node Version").
+				Description("A major version, an exact version, or an alias (20, v20.11.1, lts/iron, lts/*)").
+				Validate(system.ValidateNodeVersion).
+				Value(&version),
+		).Title("Install Node.js"),
+	).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// run hands a privileged script to the execution screen
+func (m NodeManagementModel) run(script, description string, err error) (NodeManagementModel, tea.Cmd) {
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{
+			Command:     "sudo bash -c " + system.ShellQuote(script),
+			Description: description,
+		}
+	}
+}
+
+// Update handles messages for the Node versions screen
+func (m NodeManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.form != nil {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				m.form = nil
+				return m, nil
+			}
+			break
+		}
+
+		if m.removing {
+			m.removing = false
+			if msg.String() == "y" {
+				version := m.versions[m.cursor]
+				script, err := m.manager.UninstallVersionScript(version)
+				return m.run(script, "Remove Node.js "+version, err)
+			}
+			return m, nil
+		}
+
+		count := len(m.versions)
+		if !m.manager.Installed() {
+			count = len(nodeManagerChoices)
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			if m.site.Name != "" {
+				return m, func() tea.Msg {
+					return NavigateMsg{
+						Screen: ConfigEditorScreen,
+						Data: map[string]interface{}{
+							"action": "edit_nginx_site",
+							"site":   m.site,
+						},
+					}
+				}
+			}
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: ConfigMenuScreen}
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < count-1 {
+				m.cursor++
+			}
+		case "enter", " ":
+			m.err, m.success = nil, ""
+			if !m.manager.Installed() {
+				choice := nodeManagerChoices[m.cursor]
+				script, err := system.NodeManagerInstallScript(choice.kind)
+				return m.run(script, "Install "+choice.title+" system-wide", err)
+			}
+			if m.site.Name != "" && count > 0 {
+				return m.pin(m.versions[m.cursor])
+			}
+		case "i":
+			if m.manager.Installed() {
+				m.err, m.success = nil, ""
+				m.form = m.buildInstallForm()
+				return m, m.form.Init()
+			}
+		case "s":
+			if m.manager.Installed() && count > 0 {
+				version := m.versions[m.cursor]
+				script, err := m.manager.SetDefaultScript(version)
+				return m.run(script, "Make Node.js "+version+" the default", err)
+			}
+		case "d":
+			if m.manager.Installed() && count > 0 {
+				m.err, m.success = nil, ""
+				m.removing = true
+			}
+		}
+		return m, nil
+	}
+
+	if m.form == nil {
+		return m, nil
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State == huh.StateCompleted {
+		version := strings.TrimSpace(m.form.GetString("version"))
+		m.form = nil
+		script, err := m.manager.InstallVersionScript(version)
+		return m.run(script, "Install Node.js "+version, err)
+	}
+	return m, cmd
+}
+
+// pin writes a site's .nvmrc, using the major version so patch releases
+// installed later are picked up
+func (m NodeManagementModel) pin(version string) (NodeManagementModel, tea.Cmd) {
+	major := strings.SplitN(version, ".", 2)[0]
+	if err := system.WriteNvmrc(m.site.RootDir, major); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.siteNvmrc = major
+	m.success = fmt.Sprintf("%s %s now uses Node %s (.nvmrc)", m.theme.Symbols.CheckMark, m.site.Name, major)
+	return m, nil
+}
+
+// View renders the Node versions screen
+func (m NodeManagementModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	title := "Node.js Versions"
+	if m.site.Name != "" {
+		title = "Node.js Version: " + m.site.Name
+	}
+	sections := []string{m.theme.Title.Render(title), ""}
+	var help string
+
+	switch {
+	case m.form != nil:
+		sections = append(sections, m.form.View())
+		help = "Enter: Install" + bullet + "Esc: Cancel"
+
+	case !m.manager.Installed():
+		sections = append(sections,
+			m.theme.DescriptionStyle.Render("No system-wide Node version manager is installed."),
+			m.theme.DescriptionStyle.Render("Install one to keep several Node versions side by side for every user."),
+			"", m.theme.Subtitle.Render("Install a version manager"), "")
+		for i, c := range nodeManagerChoices {
+			cursor := "  "
+			style := m.theme.MenuItem
+			if i == m.cursor {
+				cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+				style = m.theme.SelectedItem
+			}
+			sections = append(sections, style.Render(cursor+c.title))
+			if i == m.cursor {
+				sections = append(sections, "    "+m.theme.DescriptionStyle.Render(c.description))
+			}
+		}
+		help = "↑/↓: Navigate" + bullet + "Enter: Install" + bullet + "Esc: Back"
+
+	default:
+		sections = append(sections, m.theme.Label.Render("Manager: ")+m.theme.MenuItem.Render(m.manager.Kind+" in "+m.manager.Dir))
+		if m.systemNode != "" {
+			sections = append(sections, m.theme.Label.Render("System:  ")+m.theme.MenuItem.Render("node "+m.systemNode+" (OS package)"))
+		}
+		if m.site.Name != "" {
+			pinned := "none"
+			if m.siteNvmrc != "" {
+				pinned = m.siteNvmrc
+			}
+			sections = append(sections, m.theme.Label.Render(".nvmrc:  ")+m.theme.MenuItem.Render(pinned+" in "+m.site.RootDir))
+		}
+		sections = append(sections, "", m.theme.Subtitle.Render("Installed versions"), "")
+		if len(m.versions) == 0 {
+			sections = append(sections, m.theme.DescriptionStyle.Render("No Node versions installed yet. Press i to install one."))
+		}
+		for i, v := range m.versions {
+			cursor := "  "
+			style := m.theme.MenuItem
+			if i == m.cursor {
+				cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+				style = m.theme.SelectedItem
+			}
+			var marks []string
+			if nodeVersionMatches(v, m.defaultVer) {
+				marks = append(marks, "default")
+			}
+			if m.site.Name != "" && nodeVersionMatches(v, m.siteNvmrc) {
+				marks = append(marks, ".nvmrc")
+			}
+			line := cursor + v
+			if len(marks) > 0 {
+				line += "  " + m.theme.DescriptionStyle.Render("("+strings.Join(marks, ", ")+")")
+			}
+			sections = append(sections, style.Render(line))
+		}
+		if m.removing {
+			sections = append(sections, "", m.theme.WarningStyle.Render("Remove Node.js "+m.versions[m.cursor]+"? (y/n)"))
+		}
+		help = "i: Install" + bullet + "s: Set default" + bullet + "d: Remove" + bullet + "Esc: Back"
+		if m.site.Name != "" {
+			help = "Enter: Pin in .nvmrc" + bullet + help
+		}
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// nodeVersionMatches reports whether an installed version satisfies a
+// pinned one such as 20, v20.11, or v20.11.1
+func nodeVersionMatches(installed, pinned string) bool {
+	pinned = strings.TrimPrefix(strings.TrimSpace(pinned), "v")
+	if pinned == "" {
+		return false
+	}
+	installed = strings.TrimPrefix(installed, "v")
+	return installed == pinned || strings.HasPrefix(installed, pinned+".")
+}
@@ -29,7 +29,9 @@ type NodeVersionModel struct {
 	commandType    string // "npm_install" or "npm_build"
 	currentVersion string
 	nvmInstalled   bool
-	systemUser     string // from git config meta.systemuser
+	manager        system.NodeManager // System-wide fnm or nvm, preferred over ~/.nvm
+	nvmrc          string             // Version pinned in the project's .nvmrc
+	systemUser     string             // from git config meta.systemuser
 	availableUsers []string
 	selectingUser  bool
 }
@@ -47,7 +49,15 @@ func NewNodeVersionModel(commandType string) NodeVersionModel {
 
 	// Detect current Node version
 	currentVersion := detectNodeVersion()
-	nvmInstalled := isNvmInstalled()
+	manager := system.DetectNodeManager()
+	nvmInstalled := manager.Installed() || isNvmInstalled()
+
+	// Offer the project's pinned version first
+	cwd, _ := os.Getwd()
+	nvmrc := system.ReadNvmrc(cwd)
+	if nvmrc != "" {
+		versions = append([]NodeVersion{{Version: "nvmrc", Label: "Use .nvmrc (" + nvmrc + ")", Description: "The version this project pins in .nvmrc"}}, versions...)
+	}
 
 	// Get system user from git config
 	systemUser := getGitSystemUser()
@@ -69,6 +79,8 @@ func NewNodeVersionModel(commandType string) NodeVersionModel {
 		commandType:    commandType,
 		currentVersion: currentVersion,
 		nvmInstalled:   nvmInstalled,
+		manager:        manager,
+		nvmrc:          nvmrc,
 		systemUser:     systemUser,
 		availableUsers: availableUsers,
 	}
@@ -174,9 +186,18 @@ func (m NodeVersionModel) executeCommand() (NodeVersionModel, tea.Cmd) {
 		baseCmd = npmCmd
 		description = fmt.Sprintf("Running %s (Node %s)", npmCmd, m.currentVersion)
 	} else if m.nvmInstalled {
-		// Use nvm to switch version
-		baseCmd = fmt.Sprintf("source $HOME/.nvm/nvm.sh && nvm use %s && %s", selectedVersion.Version, npmCmd)
-		description = fmt.Sprintf("Running %s with Node.js %s", npmCmd, selectedVersion.Version)
+		// .nvmrc is read by "use" without a version
+		version, label := selectedVersion.Version, selectedVersion.Version
+		if version == "nvmrc" {
+			version, label = "", m.nvmrc
+		}
+		if m.manager.Installed() {
+			baseCmd = m.manager.UseCommand(version) + " && " + npmCmd
+		} else {
+			// Use the user's own nvm
+			baseCmd = strings.TrimSpace(fmt.Sprintf("source $HOME/.nvm/nvm.sh && nvm use %s", version)) + " && " + npmCmd
+		}
+		description = fmt.Sprintf("Running %s with Node.js %s", npmCmd, label)
 	} else {
 		// No nvm, but user selected a specific version - warn them
 		baseCmd = fmt.Sprintf("echo 'Node.js %s selected but nvm is not installed.' && echo 'Install nvm first: curl -o- https://raw.githubusercontent.com/nvm-sh/nvm/v0.39.0/install.sh | bash' && echo '' && echo 'Running with current version instead...' && %s", selectedVersion.Version, npmCmd)
@@ -227,7 +248,11 @@ func (m NodeVersionModel) View() string {
 	statusLines = append(statusLines, m.theme.Label.Render("Current Node.js: ")+m.theme.InfoStyle.Render(m.currentVersion))
 
 	if m.nvmInstalled {
-		statusLines = append(statusLines, m.theme.SuccessStyle.Render("✓ nvm detected - version switching available"))
+		manager := "nvm"
+		if m.manager.Installed() {
+			manager = m.manager.Kind + " (system-wide)"
+		}
+		statusLines = append(statusLines, m.theme.SuccessStyle.Render("✓ "+manager+" detected - version switching available"))
 	} else {
 		statusLines = append(statusLines, m.theme.WarningStyle.Render("⚠ nvm not installed - using current version only"))
 	}
@@ -0,0 +1,277 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// userReferencesMsg carries what still refers to the user being offboarded
+type userReferencesMsg struct {
+	refs []system.UserReference
+}
+
+// offboardDoneMsg carries the outcome of an offboarding
+type offboardDoneMsg struct {
+	steps []system.OffboardStep
+	err   error
+}
+
+// OffboardUserModel deactivates a user who is leaving and lists what still
+// runs as them
+type OffboardUserModel struct {
+	theme       *theme.Theme
+	width       int
+	height      int
+	user        system.User
+	userManager *system.UserManager
+
+	mode    string // "form", "confirm", "running", "done"
+	form    *huh.Form
+	opts    system.OffboardOptions
+	refs    []system.UserReference
+	loading bool
+	steps   []system.OffboardStep
+
+	confirm Confirmation
+	err     error
+}
+
+// NewOffboardUserModel creates the offboarding screen for a user
+func NewOffboardUserModel(user system.User) OffboardUserModel {
+	m := OffboardUserModel{
+		theme:       theme.DefaultTheme(),
+		user:        user,
+		userManager: system.NewUserManager(),
+		loading:     true,
+	}
+	m.startForm()
+	return m
+}
+
+// startForm asks what to do with the home directory
+func (m *OffboardUserModel) startForm() {
+	m.mode = "form"
+	user := m.user
+	home := system.OffboardHomeKeep
+	reassignTo := ""
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("home").
+				Title("Home Directory").
+				Description(m.user.HomeDir).
+				Options(
+					huh.NewOption("Keep it as it is", system.OffboardHomeKeep),
+					huh.NewOption("Archive to "+system.OffboardArchiveDir+" and remove it", system.OffboardHomeArchive),
+					huh.NewOption("Reassign it to another user", system.OffboardHomeReassign),
+				).
+				Validate(func(h string) error {
+					if h == system.OffboardHomeKeep {
+						return nil
+					}
+					return system.OffboardableHome(user)
+				}).
+				Value(&home),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Key("reassignTo").
+				Title("New Owner").
+				Description("The user who takes over the home directory").
+				Validate(func(s string) error {
+					return system.OffboardOptions{Home: system.OffboardHomeReassign, ReassignTo: strings.TrimSpace(s)}.Validate(user)
+				}).
+				Value(&reassignTo),
+		).WithHideFunc(func() bool { return home != system.OffboardHomeReassign }),
+	).WithTheme(m.theme.HuhTheme).WithShowHelp(true).WithShowErrors(true)
+}
+
+// Init starts looking for references to the user
+func (m OffboardUserModel) Init() tea.Cmd {
+	username := m.user.Username
+	return tea.Batch(m.form.Init(), func() tea.Msg {
+		return userReferencesMsg{refs: system.FindUserReferences(username)}
+	})
+}
+
+// Update handles messages for the offboarding screen
+func (m OffboardUserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case userReferencesMsg:
+		m.loading = false
+		m.refs = msg.refs
+		return m, nil
+
+	case offboardDoneMsg:
+		m.mode = "done"
+		m.steps = msg.steps
+		m.err = msg.err
+		return m, nil
+	}
+
+	keyMsg, isKey := msg.(tea.KeyMsg)
+	if isKey && keyMsg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch m.mode {
+	case "form":
+		if isKey && keyMsg.String() == "esc" {
+			return m, m.back()
+		}
+		form, cmd := m.form.Update(msg)
+		if f, ok := form.(*huh.Form); ok {
+			m.form = f
+		}
+		if m.form.State == huh.StateCompleted {
+			m.opts = system.OffboardOptions{Home: m.form.GetString("home")}
+			if m.opts.Home == system.OffboardHomeReassign {
+				m.opts.ReassignTo = strings.TrimSpace(m.form.GetString("reassignTo"))
+			}
+			m.confirm = NewDangerConfirmation("offboard", "Offboard User", m.confirmMessage(), m.user.Username)
+			m.mode = "confirm"
+			return m, nil
+		}
+		return m, cmd
+
+	case "confirm":
+		if !isKey {
+			return m, nil
+		}
+		var result ConfirmResult
+		m.confirm, result = m.confirm.Update(keyMsg)
+		switch result {
+		case ConfirmAccepted:
+			m.mode = "running"
+			um, username, opts := m.userManager, m.user.Username, m.opts
+			return m, func() tea.Msg {
+				steps, err := um.OffboardUser(username, opts)
+				return offboardDoneMsg{steps: steps, err: err}
+			}
+		case ConfirmCancelled:
+			m.startForm()
+			return m, m.form.Init()
+		}
+
+	case "done":
+		if isKey {
+			switch keyMsg.String() {
+			case "q":
+				return m, tea.Quit
+			case "esc", "backspace", "enter":
+				return m, m.back()
+			}
+		}
+	}
+	return m, nil
+}
+
+// back returns to the user's details
+func (m OffboardUserModel) back() tea.Cmd {
+	user := m.user
+	if refreshed, err := m.userManager.GetUser(user.Username); err == nil {
+		user = *refreshed
+	}
+	return func() tea.Msg {
+		return NavigateMsg{Screen: UserDetailsScreen, Data: map[string]interface{}{"user": user}}
+	}
+}
+
+// confirmMessage describes the offboarding about to run
+func (m OffboardUserModel) confirmMessage() string {
+	lines := []string{
+		fmt.Sprintf("Offboard %s? This will:", m.user.Username),
+		"  - lock the account and expire its password",
+		"  - remove " + m.user.HomeDir + "/.ssh/authorized_keys",
+	}
+	switch m.opts.Home {
+	case system.OffboardHomeArchive:
+		lines = append(lines, "  - archive and remove "+m.user.HomeDir)
+	case system.OffboardHomeReassign:
+		lines = append(lines, "  - give "+m.user.HomeDir+" to "+m.opts.ReassignTo)
+	}
+	if len(m.refs) > 0 {
+		lines = append(lines, "", fmt.Sprintf("%d program(s), cron job(s), or repositories still run as this user.", len(m.refs)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderReferences lists what still refers to the user
+func (m OffboardUserModel) renderReferences() []string {
+	if m.loading {
+		return []string{m.theme.InfoStyle.Render("Looking for programs, cron jobs, and repositories running as " + m.user.Username + "...")}
+	}
+	if len(m.refs) == 0 {
+		return []string{m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " No supervisor programs, cron jobs, or git meta.systemuser settings refer to " + m.user.Username)}
+	}
+	lines := []string{m.theme.WarningStyle.Render(fmt.Sprintf("%s Still running as %s; reassign or remove these:", m.theme.Symbols.Warning, m.user.Username))}
+	for _, r := range m.refs {
+		lines = append(lines, m.theme.MenuItem.Render(fmt.Sprintf("  %-10s %s", r.Kind, r.Where)),
+			m.theme.DescriptionStyle.Render("             "+truncateRunes(r.Detail, 70)))
+	}
+	return lines
+}
+
+// View renders the offboarding screen
+func (m OffboardUserModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections := []string{
+		m.theme.Title.Render("Offboard User: " + m.user.Username),
+		m.theme.DescriptionStyle.Render("Locks the account, expires its password, and removes its SSH keys"),
+		"",
+	}
+	var help string
+
+	switch m.mode {
+	case "form":
+		sections = append(sections, m.form.View(), "")
+		sections = append(sections, m.renderReferences()...)
+		help = "Enter: Continue" + bullet + "Esc: Back"
+
+	case "running":
+		sections = append(sections, m.theme.InfoStyle.Render("Offboarding "+m.user.Username+"..."))
+
+	case "done":
+		for _, s := range m.steps {
+			line := s.Name
+			if s.Detail != "" {
+				line += ": " + s.Detail
+			}
+			if s.Err != nil {
+				sections = append(sections, m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+line+" ("+s.Err.Error()+")"))
+			} else {
+				sections = append(sections, m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark+" "+line))
+			}
+		}
+		if m.err != nil {
+			sections = append(sections, m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+		}
+		sections = append(sections, "")
+		sections = append(sections, m.renderReferences()...)
+		help = "Enter/Esc: Back to user"
+	}
+
+	if help != "" {
+		sections = append(sections, "", m.theme.Help.Render(help))
+	}
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(content))
+}
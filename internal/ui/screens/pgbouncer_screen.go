@@ -0,0 +1,324 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// PgBouncerModel configures pgBouncer pooling in front of PostgreSQL
+type PgBouncerModel struct {
+	theme    *theme.Theme
+	width    int
+	height   int
+	manager  *system.PgBouncerManager
+	postgres *system.PostgreSQLManager
+
+	config    system.PgBouncerConfig
+	generated bool // pgbouncer.ini was written by ravact
+	status    string
+	actions   []string
+	cursor    int
+
+	form *huh.Form
+	mode string // "menu", "form"
+
+	err     error
+	success string
+}
+
+// NewPgBouncerModel creates the pgBouncer screen
+func NewPgBouncerModel() PgBouncerModel {
+	m := PgBouncerModel{
+		theme:    theme.DefaultTheme(),
+		manager:  system.NewPgBouncerManager(),
+		postgres: system.NewPostgreSQLManager(),
+		actions: []string{
+			"Configure Pooling",
+			"Sync Users from PostgreSQL",
+			"Restart pgBouncer",
+			"View Logs",
+			"← Back to Configurations",
+		},
+		mode: "menu",
+	}
+	m.load()
+	return m
+}
+
+// load reads the current config and service state
+func (m *PgBouncerModel) load() {
+	m.status = m.manager.Status()
+	config, err := m.manager.GetConfig()
+	if err != nil {
+		m.config = system.DefaultPgBouncerConfig()
+		m.generated = false
+		return
+	}
+	m.config = config
+	m.generated = true
+}
+
+func (m PgBouncerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m PgBouncerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	if m.mode == "form" {
+		return m.updateForm(msg)
+	}
+
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: ConfigMenuScreen}
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.actions)-1 {
+				m.cursor++
+			}
+		case "enter", " ":
+			return m.executeAction()
+		}
+	}
+	return m, nil
+}
+
+func (m PgBouncerModel) executeAction() (PgBouncerModel, tea.Cmd) {
+	m.err = nil
+	m.success = ""
+
+	switch m.actions[m.cursor] {
+	case "Configure Pooling":
+		m.form = m.buildForm()
+		m.mode = "form"
+		return m, m.form.Init()
+
+	case "Sync Users from PostgreSQL":
+		count, err := m.syncUsers()
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		if err := m.manager.Reload(); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.success = fmt.Sprintf("✓ Synced %d PostgreSQL users to userlist.txt", count)
+
+	case "Restart pgBouncer":
+		if err := m.manager.Restart(); err != nil {
+			m.err = err
+		} else {
+			m.success = "✓ pgBouncer restarted"
+		}
+		m.load()
+
+	case "View Logs":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: LogViewerScreen,
+				Data:   map[string]interface{}{"unit": system.PgBouncerService},
+			}
+		}
+
+	case "← Back to Configurations":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: ConfigMenuScreen}
+		}
+	}
+	return m, nil
+}
+
+// syncUsers writes userlist.txt from PostgreSQL's login roles and returns
+// how many were written
+func (m *PgBouncerModel) syncUsers() (int, error) {
+	roles, err := m.postgres.LoginRoles()
+	if err != nil {
+		return 0, err
+	}
+	if err := m.manager.SyncUsers(roles); err != nil {
+		return 0, err
+	}
+	return len(roles), nil
+}
+
+// buildForm creates the pooling form from the current config
+func (m *PgBouncerModel) buildForm() *huh.Form {
+	poolMode := m.config.PoolMode
+	listenPort := strconv.Itoa(m.config.ListenPort)
+	maxClientConn := strconv.Itoa(m.config.MaxClientConn)
+	poolSize := strconv.Itoa(m.config.DefaultPoolSize)
+
+	positive := func(s string) error {
+		if n, err := strconv.Atoi(strings.TrimSpace(s)); err != nil || n < 1 {
+			return fmt.Errorf("enter a number of 1 or more")
+		}
+		return nil
+	}
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("poolMode").
+				Title("Pool Mode").
+				Description("Transaction suits Laravel at scale; on pgBouncer before 1.21 it needs PDO emulated prepares").
+				Options(
+					huh.NewOption("Transaction (server connection per transaction)", "transaction"),
+					huh.NewOption("Session (server connection per client, most compatible)", "session"),
+					huh.NewOption("Statement (no multi-statement transactions)", "statement"),
+				).
+				Value(&poolMode),
+
+			huh.NewInput().
+				Key("listenPort").
+				Title("Listen Port").
+				Description("Point DB_PORT at this port").
+				Validate(positive).
+				Value(&listenPort),
+
+			huh.NewInput().
+				Key("maxClientConn").
+				Title("Max Client Connections").
+				Description("Connections from the app that pgBouncer accepts").
+				Validate(positive).
+				Value(&maxClientConn),
+
+			huh.NewInput().
+				Key("poolSize").
+				Title("Default Pool Size").
+				Description("PostgreSQL connections per database and user").
+				Validate(positive).
+				Value(&poolSize),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// updateForm handles the pooling form and applies it on completion
+func (m PgBouncerModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = "menu"
+			m.form = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	config := m.config
+	config.PoolMode = m.form.GetString("poolMode")
+	config.ListenPort, _ = strconv.Atoi(strings.TrimSpace(m.form.GetString("listenPort")))
+	config.MaxClientConn, _ = strconv.Atoi(strings.TrimSpace(m.form.GetString("maxClientConn")))
+	config.DefaultPoolSize, _ = strconv.Atoi(strings.TrimSpace(m.form.GetString("poolSize")))
+	m.mode = "menu"
+	m.form = nil
+
+	if pg, err := m.postgres.GetConfig(); err == nil {
+		config.ServerPort = pg.Port
+	}
+	roles, err := m.postgres.LoginRoles()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	config.AuthType = system.PgBouncerAuthType(roles)
+
+	if err := m.manager.SaveConfig(config); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := m.manager.SyncUsers(roles); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := m.manager.Restart(); err != nil {
+		m.err = err
+		m.load()
+		return m, nil
+	}
+	m.success = fmt.Sprintf("✓ pgBouncer pooling %s connections on port %d for %d users", config.PoolMode, config.ListenPort, len(roles))
+	m.load()
+	return m, nil
+}
+
+func (m PgBouncerModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	header := m.theme.Title.Render("pgBouncer Connection Pooling")
+
+	statusStyle := m.theme.ErrorStyle
+	if m.status == "active" {
+		statusStyle = m.theme.SuccessStyle
+	}
+	info := []string{
+		m.theme.Label.Render("Status:    ") + statusStyle.Render(m.status),
+	}
+	if m.generated {
+		info = append(info,
+			m.theme.Label.Render("Listen:    ")+m.theme.InfoStyle.Render(fmt.Sprintf("%s:%d", m.config.ListenAddr, m.config.ListenPort)),
+			m.theme.Label.Render("Server:    ")+m.theme.InfoStyle.Render(fmt.Sprintf("%s:%d", m.config.ServerHost, m.config.ServerPort)),
+			m.theme.Label.Render("Pool Mode: ")+m.theme.InfoStyle.Render(m.config.PoolMode),
+			m.theme.Label.Render("Pool Size: ")+m.theme.InfoStyle.Render(fmt.Sprintf("%d per database/user, %d clients max", m.config.DefaultPoolSize, m.config.MaxClientConn)),
+			m.theme.Label.Render("Auth:      ")+m.theme.InfoStyle.Render(m.config.AuthType),
+		)
+	} else {
+		info = append(info, m.theme.WarningStyle.Render("No pgbouncer.ini found; Configure Pooling generates it"))
+	}
+	sections := []string{header, "", lipgloss.JoinVertical(lipgloss.Left, info...), ""}
+
+	if m.mode == "form" && m.form != nil {
+		sections = append(sections, m.form.View(), "", m.theme.Help.Render("Tab: Next • Enter: Save • Esc: Cancel"))
+	} else {
+		for i, action := range m.actions {
+			if i == m.cursor {
+				sections = append(sections, m.theme.SelectedItem.Render(m.theme.KeyStyle.Render("▶ ")+action))
+			} else {
+				sections = append(sections, m.theme.MenuItem.Render("  "+action))
+			}
+		}
+		if m.success != "" {
+			sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+		}
+		if m.err != nil {
+			sections = append(sections, "", m.theme.ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		}
+		sections = append(sections, "", m.theme.Help.Render("↑/↓: Navigate • Enter: Select • Esc: Back • q: Quit"))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
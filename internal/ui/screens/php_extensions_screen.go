@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/theme"
 )
 
@@ -31,6 +32,7 @@ type PHPExtensionsModel struct {
 	selectedVersion   string
 	versionCursor     int
 	mode              string // "version_select", "extensions", "confirm"
+	source            string // "apt" for distribution packages, "pecl" to build from PECL
 	err               error
 	success           string
 	scrollOffset      int
@@ -83,35 +85,51 @@ var availablePHPExtensions = []PHPExtension{
 	{Name: "sybase", Description: "Sybase database support"},
 }
 
+// peclPHPExtensions lists the extensions that can be built with PECL
+func peclPHPExtensions() []PHPExtension {
+	extensions := make([]PHPExtension, len(system.PECLExtensions))
+	for i, ext := range system.PECLExtensions {
+		extensions[i] = PHPExtension{Name: ext.Name, Description: ext.Description}
+	}
+	return extensions
+}
+
 // NewPHPExtensionsModel creates a new PHP extensions model
 func NewPHPExtensionsModel() PHPExtensionsModel {
 	installedVersions := detectInstalledPHPVersions()
 
-	// Copy extensions list
-	extensions := make([]PHPExtension, len(availablePHPExtensions))
-	copy(extensions, availablePHPExtensions)
-
-	// Initialize filtered indices to show all
-	filteredIndices := make([]int, len(extensions))
-	for i := range extensions {
-		filteredIndices[i] = i
-	}
-
 	selectedVersion := ""
 	if len(installedVersions) > 0 {
 		selectedVersion = installedVersions[0]
 	}
 
-	return PHPExtensionsModel{
+	m := PHPExtensionsModel{
 		theme:             theme.DefaultTheme(),
 		cursor:            0,
-		extensions:        extensions,
-		filteredIndices:   filteredIndices,
 		installedVersions: installedVersions,
 		selectedVersion:   selectedVersion,
 		mode:              "version_select",
 		maxVisible:        15,
 	}
+	m.setSource("apt")
+	return m
+}
+
+// setSource switches between distribution packages and PECL builds,
+// clearing the selection and search
+func (m *PHPExtensionsModel) setSource(source string) {
+	m.source = source
+	if source == "pecl" {
+		m.extensions = peclPHPExtensions()
+	} else {
+		// Copy extensions list
+		m.extensions = make([]PHPExtension, len(availablePHPExtensions))
+		copy(m.extensions, availablePHPExtensions)
+	}
+	m.searchQuery = ""
+	m.filterExtensions()
+	m.cursor = 0
+	m.scrollOffset = 0
 }
 
 // Init initializes the PHP extensions screen
@@ -184,6 +202,18 @@ func (m PHPExtensionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case "p":
+			// Switch between distribution packages and PECL builds
+			if m.mode == "extensions" {
+				m.err = nil
+				if m.source == "pecl" {
+					m.setSource("apt")
+				} else {
+					m.setSource("pecl")
+				}
+			}
+			return m, nil
+
 		case "up", "k":
 			if m.cursor > 0 {
 				m.cursor--
@@ -275,6 +305,20 @@ func (m PHPExtensionsModel) executeAction() (PHPExtensionsModel, tea.Cmd) {
 		if m.cursor == 0 {
 			// Yes - install
 			selected := m.getSelectedExtensions()
+			if m.source == "pecl" {
+				script, err := system.PECLInstallScript(m.selectedVersion, selected)
+				if err != nil {
+					m.err = err
+					m.mode = "extensions"
+					return m, nil
+				}
+				return m, func() tea.Msg {
+					return ExecutionStartMsg{
+						Command:     script,
+						Description: fmt.Sprintf("Building %d PECL extensions for PHP %s", len(selected), m.selectedVersion),
+					}
+				}
+			}
 			cmd := m.buildInstallCommand(selected)
 			return m, func() tea.Msg {
 				return ExecutionStartMsg{
@@ -403,6 +447,10 @@ func (m PHPExtensionsModel) viewVersionSelect() string {
 // viewExtensions renders the extensions selection view
 func (m PHPExtensionsModel) viewExtensions() string {
 	header := m.theme.Title.Render(fmt.Sprintf("PHP %s Extensions", m.selectedVersion))
+	sourceInfo := m.theme.DescriptionStyle.Render("Source: distribution packages (press p to build from PECL)")
+	if m.source == "pecl" {
+		sourceInfo = m.theme.InfoStyle.Render("Source: PECL, compiled on this server (press p for distribution packages)")
+	}
 
 	// Search bar
 	searchBar := ""
@@ -485,9 +533,9 @@ func (m PHPExtensionsModel) viewExtensions() string {
 	}
 
 	// Help
-	help := m.theme.Help.Render("↑/↓: Navigate • Space: Toggle • /: Search • Enter: Install • Esc: Back")
+	help := m.theme.Help.Render("↑/↓: Navigate • Space: Toggle • /: Search • p: Packages/PECL • Enter: Install • Esc: Back")
 
-	sections := []string{header, "", searchBar, selectedInfo, menu}
+	sections := []string{header, sourceInfo, "", searchBar, selectedInfo, menu}
 	if installHint != "" {
 		sections = append(sections, "", installHint)
 	}
@@ -508,12 +556,28 @@ func (m PHPExtensionsModel) viewConfirm() string {
 	selected := m.getSelectedExtensions()
 	extList := strings.Join(selected, ", ")
 
-	info := lipgloss.JoinVertical(lipgloss.Left,
+	infoLines := []string{
 		m.theme.Label.Render(fmt.Sprintf("PHP Version: %s", m.selectedVersion)),
 		"",
 		m.theme.Label.Render("Extensions to install:"),
 		m.theme.InfoStyle.Render(extList),
-	)
+	}
+	if m.source == "pecl" {
+		var exts []system.PECLExtension
+		for _, name := range selected {
+			if ext, ok := system.FindPECLExtension(name); ok {
+				exts = append(exts, ext)
+			}
+		}
+		infoLines = append(infoLines,
+			"",
+			m.theme.Label.Render("Build dependencies:"),
+			m.theme.DescriptionStyle.Render(strings.Join(system.PECLBuildDeps(m.selectedVersion, exts), " ")),
+			"",
+			m.theme.DescriptionStyle.Render(fmt.Sprintf("Each extension is compiled, enabled in /etc/php/%s/mods-available, and PHP-FPM is restarted.", m.selectedVersion)),
+		)
+	}
+	info := lipgloss.JoinVertical(lipgloss.Left, infoLines...)
 
 	var items []string
 	items = append(items, "")
@@ -0,0 +1,264 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// PHPIniModel tunes php.ini settings for an installed PHP version through a
+// conf.d override file
+type PHPIniModel struct {
+	theme    *theme.Theme
+	width    int
+	height   int
+	versions []string
+	cursor   int
+	version  string // Selected version; empty while choosing
+	fpm      map[string]string
+	cli      map[string]string
+	form     *huh.Form
+	err      error
+}
+
+// NewPHPIniModel creates the php.ini tuning screen
+func NewPHPIniModel() PHPIniModel {
+	m := PHPIniModel{theme: theme.DefaultTheme(), versions: system.PHPFPMVersions()}
+	switch len(m.versions) {
+	case 0:
+		m.err = fmt.Errorf("no PHP-FPM installation found in %s", system.PHPConfigDir)
+	case 1:
+		m.version = m.versions[0]
+		m.load()
+	}
+	return m
+}
+
+// Init initializes the php.ini screen
+func (m PHPIniModel) Init() tea.Cmd {
+	return nil
+}
+
+// load reads the FPM and CLI settings of the selected version
+func (m *PHPIniModel) load() {
+	m.err = nil
+	var err error
+	if m.fpm, err = system.ReadPHPIni(m.version, "fpm"); err != nil {
+		m.err = err
+	}
+	// Not every install has the CLI SAPI
+	m.cli, _ = system.ReadPHPIni(m.version, "cli")
+}
+
+func (m *PHPIniModel) buildForm() *huh.Form {
+	var groups []*huh.Group
+	var fields []huh.Field
+	for i, knob := range system.PHPIniKnobs {
+		value := m.fpm[knob.Key]
+		if knob.Kind == "bool" {
+			if value == "" {
+				value = knob.Default
+			}
+			fields = append(fields, huh.NewSelect[string]().
+				Key(knob.Key).
+				Title(knob.Title).
+				Description(knob.Key).
+				Options(huh.NewOption("On", "1"), huh.NewOption("Off", "0")).
+				Value(&value))
+		} else {
+			fields = append(fields, huh.NewInput().
+				Key(knob.Key).
+				Title(knob.Title).
+				Description(knob.Key+" (empty keeps php.ini's value)").
+				Placeholder(knob.Default).
+				Validate(knob.Validate).
+				Value(&value))
+		}
+
+		// One group per section of knobs
+		last := i == len(system.PHPIniKnobs)-1
+		if last || system.PHPIniKnobs[i+1].Group != knob.Group {
+			if last {
+				applyCLI := m.cli != nil
+				fields = append(fields, huh.NewConfirm().
+					Key("apply_cli").
+					Title("Apply to CLI Too").
+					Description("Also write the overrides for the php command, artisan, and cron").
+					Value(&applyCLI))
+			}
+			groups = append(groups, huh.NewGroup(fields...).Title(knob.Group))
+			fields = nil
+		}
+	}
+
+	return huh.NewForm(groups...).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// Update handles messages for the php.ini screen
+func (m PHPIniModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.form != nil {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				m.form = nil
+				return m, nil
+			}
+			break
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			if m.version != "" && len(m.versions) > 1 {
+				m.version = ""
+				m.err = nil
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: PHPFPMManagementScreen}
+			}
+		case "up", "k":
+			if m.version == "" && m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.version == "" && m.cursor < len(m.versions)-1 {
+				m.cursor++
+			}
+		case "enter", " ":
+			if m.version == "" && len(m.versions) > 0 {
+				m.version = m.versions[m.cursor]
+				m.load()
+			}
+		case "e":
+			if m.version != "" && m.fpm != nil {
+				m.form = m.buildForm()
+				return m, m.form.Init()
+			}
+		}
+		return m, nil
+	}
+
+	if m.form == nil {
+		return m, nil
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State == huh.StateCompleted {
+		return m.apply()
+	}
+	return m, cmd
+}
+
+// apply writes the overrides and restarts PHP-FPM
+func (m PHPIniModel) apply() (PHPIniModel, tea.Cmd) {
+	values := map[string]string{}
+	for _, knob := range system.PHPIniKnobs {
+		values[knob.Key] = m.form.GetString(knob.Key)
+	}
+	script := system.PHPIniScript(m.version, values, m.form.GetBool("apply_cli"))
+	m.form = nil
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{
+			Command:     "sudo bash -c " + system.ShellQuote(script),
+			Description: fmt.Sprintf("Tune php.ini for PHP %s", m.version),
+		}
+	}
+}
+
+// View renders the php.ini screen
+func (m PHPIniModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections := []string{m.theme.Title.Render("php.ini Tuning"), ""}
+
+	switch {
+	case m.form != nil:
+		sections = append(sections,
+			m.theme.DescriptionStyle.Render("PHP "+m.version+" "+bullet+" written to "+system.PHPIniOverridePath(m.version, "fpm")),
+			"", m.form.View(), "",
+			m.theme.Help.Render("Tab/Shift+Tab: Navigate"+bullet+"Enter: Next/Apply"+bullet+"Esc: Cancel"))
+
+	case m.version == "":
+		sections = append(sections, m.theme.Subtitle.Render("Select a PHP version"), "")
+		for i, v := range m.versions {
+			cursor := "  "
+			style := m.theme.MenuItem
+			if i == m.cursor {
+				cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+				style = m.theme.SelectedItem
+			}
+			sections = append(sections, style.Render(cursor+"PHP "+v))
+		}
+		if m.err != nil {
+			sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+		}
+		sections = append(sections, "", m.theme.Help.Render("↑/↓: Navigate"+bullet+"Enter: Select"+bullet+"Esc: Back"))
+
+	default:
+		sections = append(sections, m.theme.Subtitle.Render("PHP "+m.version), "",
+			m.theme.Label.Render(fmt.Sprintf("%-34s %-14s %s", "Setting", "FPM", "CLI")))
+		for _, knob := range system.PHPIniKnobs {
+			sections = append(sections, m.theme.MenuItem.Render(fmt.Sprintf("%-34s %-14s %s",
+				knob.Key, phpIniValue(m.fpm, knob), phpIniValue(m.cli, knob))))
+		}
+		if m.err != nil {
+			sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+		}
+		sections = append(sections, "",
+			m.theme.DescriptionStyle.Render("* PHP's default; php.ini does not set it"),
+			m.theme.DescriptionStyle.Render("Changes go to "+system.PHPIniOverrideFile+" in conf.d; php.ini itself is left untouched."),
+			"", m.theme.Help.Render("e: Edit"+bullet+"Esc: Back"+bullet+"q: Quit"))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// phpIniValue formats a setting for display, marking PHP's defaults
+func phpIniValue(values map[string]string, knob system.PHPIniKnob) string {
+	if values == nil {
+		return "-"
+	}
+	value, ok := values[knob.Key]
+	if !ok {
+		if knob.Default == "" {
+			return "(unset)"
+		}
+		value = knob.Default + "*"
+	}
+	if knob.Kind == "bool" {
+		label := "Off"
+		if strings.TrimSuffix(value, "*") == "1" {
+			label = "On"
+		}
+		if strings.HasSuffix(value, "*") {
+			label += "*"
+		}
+		return label
+	}
+	return value
+}
@@ -2,8 +2,12 @@ package screens
 
 import (
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/theme"
@@ -20,6 +24,20 @@ type PHPFPMManagementModel struct {
 	actions []string
 	err     error
 	success string
+
+	// Pool management
+	mode         string // "menu", "pools", "create", "form", "review", "confirm"
+	allPools     []system.PHPFPMPool
+	poolCursor   int
+	form         *huh.Form
+	pool         system.PHPFPMPool // Pool being created or edited
+	creating     bool
+	site         string // Site to point at a new pool
+	nginxManager *system.NginxManager
+	review       ConfigReview
+	confirm      Confirmation
+
+	xdebug []system.XdebugStatus // Versions running with Xdebug on
 }
 
 // NewPHPFPMManagementModel creates a new PHP-FPM management model
@@ -29,6 +47,9 @@ func NewPHPFPMManagementModel() PHPFPMManagementModel {
 	pools, _ := manager.ListPools()
 	
 	actions := []string{
+		"Manage Pools",
+		"Tune php.ini",
+		"Xdebug",
 		"List All Pools",
 		"Restart PHP-FPM Service",
 		"Reload PHP-FPM Service",
@@ -42,6 +63,8 @@ func NewPHPFPMManagementModel() PHPFPMManagementModel {
 		pools:   pools,
 		cursor:  0,
 		actions: actions,
+		mode:    "menu",
+		xdebug:  system.ActiveXdebug(),
 	}
 }
 
@@ -57,8 +80,26 @@ func (m PHPFPMManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case "pools":
+			return m.updatePools(msg)
+		case "create", "form":
+			if msg.String() == "esc" {
+				m.mode = "pools"
+				return m, nil
+			}
+			return m.updateForm(msg)
+		case "review":
+			return m.updateReview(msg)
+		case "confirm":
+			return m.updateConfirm(msg)
+		}
+
 		switch msg.String() {
-		case "ctrl+c", "q":
+		case "q":
 			return m, tea.Quit
 		case "esc", "backspace":
 			return m, func() tea.Msg {
@@ -76,6 +117,10 @@ func (m PHPFPMManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.executeAction()
 		}
 	}
+
+	if m.mode == "create" || m.mode == "form" {
+		return m.updateForm(msg)
+	}
 	return m, nil
 }
 
@@ -84,6 +129,24 @@ func (m PHPFPMManagementModel) executeAction() (PHPFPMManagementModel, tea.Cmd)
 	m.success = ""
 	
 	switch m.actions[m.cursor] {
+	case "Manage Pools":
+		m.allPools = system.ListAllPools()
+		m.poolCursor = 0
+		m.mode = "pools"
+		if len(system.PHPFPMVersions()) == 0 {
+			m.err = fmt.Errorf("no PHP-FPM installation found")
+		}
+
+	case "Tune php.ini":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: PHPIniScreen}
+		}
+
+	case "Xdebug":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: XdebugScreen}
+		}
+
 	case "List All Pools":
 		pools, err := m.manager.ListPools()
 		if err != nil {
@@ -135,6 +198,14 @@ func (m PHPFPMManagementModel) View() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
+	switch m.mode {
+	case "pools", "create", "form":
+		return m.viewPools()
+	case "review":
+		return m.review.View(m.theme, m.width, m.height)
+	case "confirm":
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
 
 	header := m.theme.Title.Render("🐘 PHP-FPM Pool Management")
 
@@ -148,6 +219,10 @@ func (m PHPFPMManagementModel) View() string {
 		poolInfo = append(poolInfo, m.theme.WarningStyle.Render("  No pools configured"))
 	}
 	
+	for _, st := range m.xdebug {
+		poolInfo = append(poolInfo, m.theme.WarningStyle.Render(fmt.Sprintf("%s Xdebug is on for PHP %s (%s)", m.theme.Symbols.Warning, st.Version, st.Label())))
+	}
+
 	poolInfoSection := lipgloss.JoinVertical(lipgloss.Left, poolInfo...)
 
 	var actionItems []string
@@ -209,3 +284,387 @@ func (m PHPFPMManagementModel) View() string {
 		bordered,
 	)
 }
+
+// updatePools handles keys on the pool list
+func (m PHPFPMManagementModel) updatePools(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.mode = "menu"
+		m.err = nil
+		m.success = ""
+		m.pools, _ = m.manager.ListPools()
+	case "up", "k":
+		if m.poolCursor > 0 {
+			m.poolCursor--
+		}
+	case "down", "j":
+		if m.poolCursor < len(m.allPools)-1 {
+			m.poolCursor++
+		}
+	case "n":
+		versions := system.PHPFPMVersions()
+		if len(versions) == 0 {
+			m.err = fmt.Errorf("no PHP-FPM installation found")
+			return m, nil
+		}
+		m.err = nil
+		m.success = ""
+		m.creating = true
+		m.mode = "create"
+		m.form = m.buildCreateForm(versions)
+		return m, m.form.Init()
+	case "enter", "e":
+		if len(m.allPools) == 0 {
+			return m, nil
+		}
+		m.err = nil
+		m.success = ""
+		m.creating = false
+		m.site = ""
+		m.pool = m.allPools[m.poolCursor]
+		m.mode = "form"
+		m.form = m.buildPoolForm()
+		return m, m.form.Init()
+	case "d", "delete":
+		if len(m.allPools) == 0 {
+			return m, nil
+		}
+		pool := m.allPools[m.poolCursor]
+		m.confirm = NewDangerConfirmation("delete_pool", "Delete Pool",
+			fmt.Sprintf("Delete the %s pool from PHP %s? Sites using %s will stop working.", pool.Name, pool.PHPVersion, pool.Listen),
+			pool.Name)
+		m.mode = "confirm"
+	}
+	return m, nil
+}
+
+// phpSites returns the nginx sites that run PHP
+func (m *PHPFPMManagementModel) phpSites() []system.NginxSite {
+	if m.nginxManager == nil {
+		m.nginxManager = system.NewNginxManager()
+	}
+	sites, _ := m.nginxManager.GetAllSites()
+	var php []system.NginxSite
+	for _, site := range sites {
+		if site.HasPHP {
+			php = append(php, site)
+		}
+	}
+	return php
+}
+
+// buildCreateForm asks which version and site a new pool is for
+func (m *PHPFPMManagementModel) buildCreateForm(versions []string) *huh.Form {
+	siteOptions := []huh.Option[string]{huh.NewOption("None", "")}
+	for _, site := range m.phpSites() {
+		siteOptions = append(siteOptions, huh.NewOption(site.Name, site.Name))
+	}
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("version").
+				Title("PHP Version").
+				Options(huh.NewOptions(versions...)...),
+			huh.NewSelect[string]().
+				Key("site").
+				Title("Site").
+				Description("The site is switched to the new pool's socket after review").
+				Options(siteOptions...),
+			huh.NewInput().
+				Key("name").
+				Title("Pool Name").
+				Description("Leave blank to name the pool after the site"),
+			huh.NewInput().
+				Key("user").
+				Title("Run As User").
+				Description("Each site's pool should run as its own user").
+				Placeholder("www-data").
+				Validate(func(s string) error {
+					if s != "" && !validPoolUser(s) {
+						return fmt.Errorf("invalid user name")
+					}
+					return nil
+				}),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// validPoolUser reports whether s looks like a system user name
+func validPoolUser(s string) bool {
+	return (system.PHPFPMPool{Name: "x", User: s, PM: "static", PMMaxChildren: 1}).Validate() == nil
+}
+
+// positiveInt validates a whole number of at least min
+func positiveInt(min int) func(string) error {
+	return func(s string) error {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n < min {
+			return fmt.Errorf("enter a whole number of at least %d", min)
+		}
+		return nil
+	}
+}
+
+// buildPoolForm edits the settings of m.pool
+func (m *PHPFPMManagementModel) buildPoolForm() *huh.Form {
+	pool := m.pool
+	user, group := pool.User, pool.Group
+	pm := pool.PM
+	maxChildren := strconv.Itoa(pool.PMMaxChildren)
+	start := strconv.Itoa(pool.PMStartServers)
+	minSpare := strconv.Itoa(pool.PMMinSpareServers)
+	maxSpare := strconv.Itoa(pool.PMMaxSpareServers)
+	maxRequests := strconv.Itoa(pool.PMMaxRequests)
+	slowlog, timeout := pool.Slowlog, pool.RequestSlowlogTimeout
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Key("user").Title("User").Value(&user).
+				Validate(func(s string) error {
+					if !validPoolUser(s) {
+						return fmt.Errorf("invalid user name")
+					}
+					return nil
+				}),
+			huh.NewInput().Key("group").Title("Group").Value(&group).
+				Validate(func(s string) error {
+					if !validPoolUser(s) {
+						return fmt.Errorf("invalid group name")
+					}
+					return nil
+				}),
+			huh.NewSelect[string]().
+				Key("pm").
+				Title("Process Manager").
+				Options(
+					huh.NewOption("ondemand - start workers per request (low traffic)", "ondemand"),
+					huh.NewOption("dynamic - keep spare workers ready", "dynamic"),
+					huh.NewOption("static - fixed number of workers", "static"),
+				).
+				Value(&pm),
+			huh.NewInput().Key("max_children").Title("pm.max_children").
+				Description("Upper limit of workers; each uses roughly the PHP memory_limit").
+				Value(&maxChildren).Validate(positiveInt(1)),
+		),
+		huh.NewGroup(
+			huh.NewInput().Key("start_servers").Title("pm.start_servers").
+				Value(&start).Validate(positiveInt(1)),
+			huh.NewInput().Key("min_spare").Title("pm.min_spare_servers").
+				Value(&minSpare).Validate(positiveInt(1)),
+			huh.NewInput().Key("max_spare").Title("pm.max_spare_servers").
+				Value(&maxSpare).Validate(positiveInt(1)),
+		).WithHideFunc(func() bool { return pm != "dynamic" }),
+		huh.NewGroup(
+			huh.NewInput().Key("max_requests").Title("pm.max_requests").
+				Description("Recycle a worker after this many requests (0 = never)").
+				Value(&maxRequests).Validate(positiveInt(0)),
+			huh.NewInput().Key("slowlog").Title("Slow Log").
+				Description("Leave blank to disable").
+				Value(&slowlog),
+			huh.NewInput().Key("timeout").Title("Slow Request Timeout").
+				Description("Log the stack of requests slower than this, e.g. 5s").
+				Value(&timeout),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// updateForm forwards messages to the active form and handles submission
+func (m PHPFPMManagementModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	if m.mode == "create" {
+		version := m.form.GetString("version")
+		m.site = m.form.GetString("site")
+		name := strings.TrimSpace(m.form.GetString("name"))
+		if name == "" {
+			name = m.site
+		}
+		if name == "" {
+			m.err = fmt.Errorf("enter a pool name or choose a site")
+			m.mode = "pools"
+			return m, nil
+		}
+		m.pool = system.SitePool(name, version, strings.TrimSpace(m.form.GetString("user")))
+		m.mode = "form"
+		m.form = m.buildPoolForm()
+		return m, m.form.Init()
+	}
+
+	pool := m.pool
+	// Fields in a hidden group keep the pool's current value
+	atoi := func(key string, current int) int {
+		if n, err := strconv.Atoi(strings.TrimSpace(m.form.GetString(key))); err == nil {
+			return n
+		}
+		return current
+	}
+	pool.User = strings.TrimSpace(m.form.GetString("user"))
+	pool.Group = strings.TrimSpace(m.form.GetString("group"))
+	pool.PM = m.form.GetString("pm")
+	pool.PMMaxChildren = atoi("max_children", pool.PMMaxChildren)
+	pool.PMStartServers = atoi("start_servers", pool.PMStartServers)
+	pool.PMMinSpareServers = atoi("min_spare", pool.PMMinSpareServers)
+	pool.PMMaxSpareServers = atoi("max_spare", pool.PMMaxSpareServers)
+	pool.PMMaxRequests = atoi("max_requests", pool.PMMaxRequests)
+	pool.Slowlog = strings.TrimSpace(m.form.GetString("slowlog"))
+	pool.RequestSlowlogTimeout = strings.TrimSpace(m.form.GetString("timeout"))
+	m.pool = pool
+
+	if err := pool.Validate(); err != nil {
+		m.err = err
+		m.form = m.buildPoolForm()
+		return m, m.form.Init()
+	}
+	return m.savePool()
+}
+
+// savePool writes the pool, reloads its PHP version, and offers to switch
+// the chosen site to the new socket
+func (m PHPFPMManagementModel) savePool() (tea.Model, tea.Cmd) {
+	manager := system.NewPHPFPMManager(m.pool.PHPVersion)
+	if err := manager.SavePool(&m.pool, m.creating); err != nil {
+		m.err = err
+		m.mode = "pools"
+		return m, nil
+	}
+	m.err = nil
+	m.success = fmt.Sprintf("%s Pool %s saved and php%s-fpm reloaded", m.theme.Symbols.CheckMark, m.pool.Name, m.pool.PHPVersion)
+	m.allPools = system.ListAllPools()
+	m.mode = "pools"
+
+	if m.creating && m.site != "" {
+		if m.nginxManager == nil {
+			m.nginxManager = system.NewNginxManager()
+		}
+		change, err := m.nginxManager.PlanSitePHPSocket(m.site, m.pool.Listen)
+		if err != nil {
+			m.err = fmt.Errorf("pool created, but %s was not switched: %w", m.site, err)
+			return m, nil
+		}
+		m.review = NewConfigReview("pool_socket", m.nginxManager, change)
+		m.mode = "review"
+	}
+	return m, nil
+}
+
+// updateReview handles the review of the site's new socket
+func (m PHPFPMManagementModel) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var result ConfirmResult
+	m.review, result = m.review.Update(msg, m.height)
+	switch result {
+	case ConfirmAccepted:
+		m.mode = "pools"
+		if err := m.nginxManager.ApplyChange(m.review.Change); err != nil {
+			m.err = err
+		} else if err := m.nginxManager.TestConfig(); err != nil {
+			m.err = fmt.Errorf("config written but test failed: %w", err)
+		} else if err := m.nginxManager.ReloadNginx(); err != nil {
+			m.err = fmt.Errorf("config written but reload failed: %w", err)
+		} else {
+			m.success = fmt.Sprintf("%s %s now uses %s", m.theme.Symbols.CheckMark, m.site, m.pool.Listen)
+		}
+	case ConfirmCancelled:
+		m.mode = "pools"
+		m.success += " (" + m.site + " unchanged)"
+	}
+	return m, nil
+}
+
+// updateConfirm handles the pool delete confirmation
+func (m PHPFPMManagementModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var result ConfirmResult
+	m.confirm, result = m.confirm.Update(msg)
+	switch result {
+	case ConfirmAccepted:
+		m.mode = "pools"
+		pool := m.allPools[m.poolCursor]
+		manager := system.NewPHPFPMManager(pool.PHPVersion)
+		if err := manager.DeletePool(strings.TrimSuffix(filepath.Base(pool.ConfigPath), ".conf")); err != nil {
+			m.err = err
+			return m, nil
+		}
+		if err := manager.ReloadService(); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.err = nil
+		m.success = fmt.Sprintf("%s Pool %s deleted and php%s-fpm reloaded", m.theme.Symbols.CheckMark, pool.Name, pool.PHPVersion)
+		m.allPools = system.ListAllPools()
+		if m.poolCursor >= len(m.allPools) && m.poolCursor > 0 {
+			m.poolCursor--
+		}
+	case ConfirmCancelled:
+		m.mode = "pools"
+	}
+	return m, nil
+}
+
+// viewPools renders the pool list and forms
+func (m PHPFPMManagementModel) viewPools() string {
+	sections := []string{m.theme.Title.Render("PHP-FPM Pools"), ""}
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	var help string
+
+	switch m.mode {
+	case "create":
+		sections = append(sections, m.theme.Subtitle.Render("New Pool"), m.form.View())
+		help = "Tab/Shift+Tab: Navigate" + bullet + "Enter: Next" + bullet + "Esc: Cancel"
+	case "form":
+		title := "Edit " + m.pool.Name
+		if m.creating {
+			title = "New Pool " + m.pool.Name
+		}
+		sections = append(sections,
+			m.theme.Subtitle.Render(title),
+			m.theme.DescriptionStyle.Render("PHP "+m.pool.PHPVersion+bullet+m.pool.Listen),
+			m.form.View())
+		help = "Tab/Shift+Tab: Navigate" + bullet + "Enter: Next / Save" + bullet + "Esc: Cancel"
+	default:
+		if len(m.allPools) == 0 {
+			sections = append(sections, m.theme.DescriptionStyle.Render("No pools found. Press n to create one."))
+		}
+		for i, pool := range m.allPools {
+			line := fmt.Sprintf("PHP %-5s %-20s %-9s max %-3d %s", pool.PHPVersion, pool.Name, pool.PM, pool.PMMaxChildren, pool.User)
+			if i == m.poolCursor {
+				sections = append(sections, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+m.theme.SelectedItem.Render(line))
+			} else {
+				sections = append(sections, "  "+m.theme.MenuItem.Render(line))
+			}
+		}
+		if len(m.allPools) > 0 {
+			pool := m.allPools[m.poolCursor]
+			detail := pool.Listen
+			if pool.Slowlog != "" {
+				detail += bullet + "slow log " + pool.Slowlog
+			}
+			sections = append(sections, "", m.theme.DescriptionStyle.Render(detail))
+		}
+		help = m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate" + bullet + "Enter: Edit" + bullet +
+			"n: New pool" + bullet + "d: Delete" + bullet + "Esc: Back"
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -24,6 +24,13 @@ type PostgreSQLManagementModel struct {
 	success     string
 	copied      bool
 	copiedTimer int
+
+	// Drop database flow
+	databases  []string
+	dbCursor   int
+	pickingDB  bool
+	confirm    Confirmation
+	confirming bool
 }
 
 // NewPostgreSQLManagementModel creates a new PostgreSQL management model
@@ -38,6 +45,8 @@ func NewPostgreSQLManagementModel() PostgreSQLManagementModel {
 		"Restart PostgreSQL Service",
 		"View Service Status",
 		"List Databases",
+		"Drop Database",
+		"Remote Access",
 		"← Back to Configurations",
 	}
 	
@@ -62,6 +71,10 @@ func (m PostgreSQLManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.confirming || m.pickingDB {
+			return m.updateDropDatabase(msg)
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -176,6 +189,26 @@ func (m PostgreSQLManagementModel) executeAction() (PostgreSQLManagementModel, t
 			}
 		}
 
+	case "Remote Access":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: DBAccessScreen,
+				Data:   map[string]interface{}{"engine": "postgresql"},
+			}
+		}
+
+	case "Drop Database":
+		databases, err := m.manager.ListDatabases()
+		if err != nil {
+			m.err = err
+		} else if len(databases) == 0 {
+			m.success = "No user databases found"
+		} else {
+			m.databases = databases
+			m.dbCursor = 0
+			m.pickingDB = true
+		}
+
 	case "← Back to Configurations":
 		return m, func() tea.Msg {
 			return NavigateMsg{Screen: ConfigMenuScreen}
@@ -185,11 +218,60 @@ func (m PostgreSQLManagementModel) executeAction() (PostgreSQLManagementModel, t
 	return m, nil
 }
 
+// updateDropDatabase handles database selection and the drop confirmation
+func (m PostgreSQLManagementModel) updateDropDatabase(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	if m.confirming {
+		var result ConfirmResult
+		m.confirm, result = m.confirm.Update(msg)
+		switch result {
+		case ConfirmCancelled:
+			m.confirming = false
+		case ConfirmAccepted:
+			m.confirming = false
+			m.pickingDB = false
+			if err := m.manager.DropDatabase(m.confirm.Action); err != nil {
+				m.err = err
+			} else {
+				m.success = fmt.Sprintf("✓ Database '%s' dropped", m.confirm.Action)
+			}
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "backspace", "q":
+		m.pickingDB = false
+	case "up", "k":
+		if m.dbCursor > 0 {
+			m.dbCursor--
+		}
+	case "down", "j":
+		if m.dbCursor < len(m.databases)-1 {
+			m.dbCursor++
+		}
+	case "enter", " ":
+		name := m.databases[m.dbCursor]
+		m.confirm = NewDangerConfirmation(name, "Drop PostgreSQL Database",
+			fmt.Sprintf("Drop database '%s'? All of its tables and data will be permanently deleted.", name),
+			name)
+		m.confirming = true
+	}
+	return m, nil
+}
+
 func (m PostgreSQLManagementModel) View() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
 
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
 	header := m.theme.Title.Render("PostgreSQL Management")
 
 	var configInfo []string
@@ -222,6 +304,19 @@ func (m PostgreSQLManagementModel) View() string {
 
 	actionsMenu := lipgloss.JoinVertical(lipgloss.Left, actionItems...)
 
+	// Database picker replaces the actions menu while choosing what to drop
+	if m.pickingDB {
+		dbItems := []string{m.theme.Label.Render("Select database to drop:")}
+		for i, db := range m.databases {
+			if i == m.dbCursor {
+				dbItems = append(dbItems, m.theme.SelectedItem.Render(m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+db))
+			} else {
+				dbItems = append(dbItems, m.theme.MenuItem.Render("  "+db))
+			}
+		}
+		actionsMenu = lipgloss.JoinVertical(lipgloss.Left, dbItems...)
+	}
+
 	var messages []string
 	if m.success != "" {
 		messages = append(messages, m.theme.SuccessStyle.Render(m.success))
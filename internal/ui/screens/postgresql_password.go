@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/theme"
+	"github.com/iperamuna/ravact/internal/vault"
 )
 
 // PostgreSQLPasswordModel represents the PostgreSQL password change screen
@@ -39,6 +40,7 @@ func (m *PostgreSQLPasswordModel) buildForm() *huh.Form {
 	return huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
+				Key("password").
 				Title("New Postgres User Password").
 				Description("Enter a strong password for the postgres user").
 				Placeholder("Enter password...").
@@ -91,6 +93,7 @@ func (m PostgreSQLPasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Check if form is completed
 	if m.form.State == huh.StateCompleted {
+		m.password = m.form.GetString("password")
 		err := m.manager.ChangeRootPassword(m.password)
 		if err != nil {
 			m.err = err
@@ -98,12 +101,13 @@ func (m PostgreSQLPasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		// Success
+		// Success - record the password and navigate back
+		vaultStatus := vault.RecordMessage("PostgreSQL", "postgres", m.password, "")
 		return m, func() tea.Msg {
 			return NavigateMsg{
 				Screen: PostgreSQLManagementScreen,
 				Data: map[string]interface{}{
-					"success": "Postgres user password changed successfully",
+					"success": "Postgres user password changed successfully (" + vaultStatus + ")",
 				},
 			}
 		}
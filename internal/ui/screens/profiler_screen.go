@@ -0,0 +1,204 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// ProfilerModel sets up a profiling agent for a PHP version: Blackfire with
+// the account's server credentials, or SPX built from source with its web UI
+// behind a key
+type ProfilerModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+	form   *huh.Form
+	err    error
+}
+
+// NewProfilerModel creates the profiling agent screen
+func NewProfilerModel() ProfilerModel {
+	m := ProfilerModel{theme: theme.DefaultTheme()}
+	versions := system.PHPFPMVersions()
+	if len(versions) == 0 {
+		m.err = fmt.Errorf("no PHP-FPM installation found in %s", system.PHPConfigDir)
+		return m
+	}
+	m.form = m.buildForm(versions)
+	return m
+}
+
+// buildForm asks for the agent and PHP version, then only the chosen
+// agent's settings
+func (m ProfilerModel) buildForm(versions []string) *huh.Form {
+	agent := system.ProfilerBlackfire
+	version := versions[len(versions)-1]
+	var options []huh.Option[string]
+	for _, v := range versions {
+		options = append(options, huh.NewOption("PHP "+v, v))
+	}
+	key, _ := system.GeneratePassword(24)
+	allowed := "127.0.0.1"
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("agent").
+				Title("Profiler").
+				Options(
+					huh.NewOption("Blackfire (hosted, needs a Blackfire account)", system.ProfilerBlackfire),
+					huh.NewOption("SPX (self-hosted web UI, built from source)", system.ProfilerSPX),
+				).
+				Value(&agent),
+
+			huh.NewSelect[string]().
+				Key("version").
+				Title("PHP Version").
+				Options(options...).
+				Value(&version),
+		).Title("Profiling Agent"),
+		huh.NewGroup(
+			huh.NewInput().
+				Key("server_id").
+				Title("Server ID").
+				Description("From Blackfire: Account, Credentials, Server Credentials").
+				Validate(func(s string) error {
+					return system.BlackfireConfig{ServerID: strings.TrimSpace(s), ServerToken: strings.Repeat("0", 16)}.Validate()
+				}),
+
+			huh.NewInput().
+				Key("server_token").
+				Title("Server Token").
+				EchoMode(huh.EchoModePassword).
+				Validate(func(s string) error {
+					return system.BlackfireConfig{ServerID: "00000000", ServerToken: strings.TrimSpace(s)}.Validate()
+				}),
+		).Title("Blackfire Credentials").WithHideFunc(func() bool {
+			return agent != system.ProfilerBlackfire
+		}),
+		huh.NewGroup(
+			huh.NewInput().
+				Key("spx_key").
+				Title("UI Key").
+				Description("Open the UI with ?SPX_KEY=<key>&SPX_UI_URI=/").
+				Validate(func(s string) error {
+					return system.SPXConfig{Key: strings.TrimSpace(s), AllowedIPs: "127.0.0.1"}.Validate()
+				}).
+				Value(&key),
+
+			huh.NewInput().
+				Key("spx_ips").
+				Title("Allowed IPs").
+				Description("Comma-separated client addresses that may use the UI").
+				Validate(func(s string) error {
+					return system.SPXConfig{Key: "00000000", AllowedIPs: strings.ReplaceAll(s, " ", "")}.Validate()
+				}).
+				Value(&allowed),
+		).Title("SPX Access").WithHideFunc(func() bool {
+			return agent != system.ProfilerSPX
+		}),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// Init initializes the profiling agent screen
+func (m ProfilerModel) Init() tea.Cmd {
+	if m.form == nil {
+		return nil
+	}
+	return m.form.Init()
+}
+
+// setup builds the chosen agent's script and starts it
+func (m ProfilerModel) setup() (ProfilerModel, tea.Cmd) {
+	version := m.form.GetString("version")
+	var script, description string
+	var err error
+	switch m.form.GetString("agent") {
+	case system.ProfilerSPX:
+		script, err = system.SPXSetupScript(version, system.SPXConfig{
+			Key:        strings.TrimSpace(m.form.GetString("spx_key")),
+			AllowedIPs: strings.ReplaceAll(m.form.GetString("spx_ips"), " ", ""),
+		})
+		description = "Set up SPX for PHP " + version
+	default:
+		script, err = system.BlackfireSetupScript(version, system.BlackfireConfig{
+			ServerID:    strings.TrimSpace(m.form.GetString("server_id")),
+			ServerToken: strings.TrimSpace(m.form.GetString("server_token")),
+		})
+		description = "Set up Blackfire for PHP " + version
+	}
+	if err != nil {
+		m.err = err
+		m.form = nil
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{Command: script, Description: description}
+	}
+}
+
+// Update handles messages for the profiling agent screen
+func (m ProfilerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: DeveloperToolkitScreen}
+			}
+		}
+		if m.form == nil {
+			return m, nil
+		}
+	}
+
+	if m.form == nil {
+		return m, nil
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State == huh.StateCompleted {
+		return m.setup()
+	}
+	return m, cmd
+}
+
+// View renders the profiling agent screen
+func (m ProfilerModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections := []string{m.theme.Title.Render("Set Up Profiling Agent"), ""}
+	if m.form != nil {
+		sections = append(sections, m.form.View(), "",
+			m.theme.DescriptionStyle.Render("The extension is enabled for the chosen version's FPM and CLI, then checked."),
+			"", m.theme.Help.Render("Tab/Shift+Tab: Navigate"+bullet+"Enter: Next/Install"+bullet+"Esc: Back"))
+	}
+	if m.err != nil {
+		sections = append(sections, m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()),
+			"", m.theme.Help.Render("Esc: Back"))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -5,7 +5,9 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/config"
 	"github.com/iperamuna/ravact/internal/models"
 	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/theme"
@@ -18,6 +20,17 @@ type QuickCommandsModel struct {
 	height   int
 	cursor   int
 	commands []models.QuickCommand
+
+	// Commands the user added, listed after the built-in ones
+	builtin int
+	custom  []config.QuickCommand
+	err     error
+	success string
+
+	form       *huh.Form
+	editing    int // Index in custom of the command being edited; -1 when adding
+	confirm    Confirmation
+	confirming bool
 }
 
 // NewQuickCommandsModel creates a new quick commands model
@@ -116,13 +129,62 @@ func NewQuickCommandsModel() QuickCommandsModel {
 		},
 	}
 
-	return QuickCommandsModel{
+	m := QuickCommandsModel{
 		theme:    theme.DefaultTheme(),
 		cursor:   0,
 		commands: commands,
+		builtin:  len(commands),
+	}
+	custom, err := config.LoadQuickCommands()
+	m.err = err
+	return m.withCustom(custom)
+}
+
+// withCustom replaces the user's commands in the list
+func (m QuickCommandsModel) withCustom(custom []config.QuickCommand) QuickCommandsModel {
+	m.custom = custom
+	m.commands = m.commands[:m.builtin:m.builtin]
+	for _, c := range custom {
+		m.commands = append(m.commands, userQuickCommand(c))
+	}
+	if m.cursor >= len(m.commands) {
+		m.cursor = max(len(m.commands)-1, 0)
+	}
+	return m
+}
+
+// userQuickCommand turns a command from commands.yaml into a list entry.
+// Switching to another user needs root, so they all run privileged.
+func userQuickCommand(c config.QuickCommand) models.QuickCommand {
+	description := c.Description
+	if description == "" {
+		description, _, _ = strings.Cut(strings.TrimSpace(c.Script), "\n")
+	}
+	if c.RunAs != "" {
+		description += " (as " + c.RunAs + ")"
+	}
+	return models.QuickCommand{
+		ID:          "user:" + c.Name,
+		Name:        c.Name,
+		Description: description,
+		Command:     quickCommandScript(c),
+		RequireRoot: true,
 	}
 }
 
+// quickCommandScript returns the bash command line that runs a user's
+// command in its working directory as its user
+func quickCommandScript(c config.QuickCommand) string {
+	script := c.Script
+	if c.WorkDir != "" {
+		script = "cd " + system.ShellQuote(c.WorkDir) + " || exit 1\n" + script
+	}
+	if c.RunAs == "" || c.RunAs == "root" {
+		return script
+	}
+	return "sudo -H -u " + system.ShellQuote(c.RunAs) + " bash -c " + system.ShellQuote(script)
+}
+
 // Init initializes the quick commands screen
 func (m QuickCommandsModel) Init() tea.Cmd {
 	return nil
@@ -135,12 +197,53 @@ func (m QuickCommandsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, nil
+	}
 
+	if m.form != nil {
+		if key, ok := msg.(tea.KeyMsg); ok && key.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m.updateForm(msg)
+	}
+
+	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.confirming {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.confirm, result = m.confirm.Update(msg)
+			switch result {
+			case ConfirmAccepted:
+				m.confirming = false
+				return m.remove()
+			case ConfirmCancelled:
+				m.confirming = false
+			}
+			return m, nil
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
 
+		case "a":
+			return m.openForm(-1)
+
+		case "e":
+			if m.cursor >= m.builtin {
+				return m.openForm(m.cursor - m.builtin)
+			}
+
+		case "d":
+			if m.cursor >= m.builtin {
+				c := m.custom[m.cursor-m.builtin]
+				m.confirm = NewConfirmation("remove_command", "Remove Command",
+					fmt.Sprintf("Remove the quick command %s?", c.Name), ConfirmWarning)
+				m.confirming = true
+			}
+
 		case "esc", "backspace":
 			return m, func() tea.Msg {
 				return NavigateMsg{Screen: MainMenuScreen}
@@ -166,8 +269,9 @@ func (m QuickCommandsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, func() tea.Msg {
 					return ExecutionStartMsg{
-						Command:     cmdStr,
-						Description: selectedCmd.Description,
+						Command:      cmdStr,
+						Description:  selectedCmd.Description,
+						Unprivileged: !selectedCmd.RequireRoot,
 					}
 				}
 			}
@@ -177,11 +281,143 @@ func (m QuickCommandsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// openForm edits one of the user's commands; index -1 adds one
+func (m QuickCommandsModel) openForm(index int) (QuickCommandsModel, tea.Cmd) {
+	var c config.QuickCommand
+	if index >= 0 {
+		c = m.custom[index]
+	}
+	m.err = nil
+	m.success = ""
+	m.editing = index
+	m.form = m.buildForm(c)
+	return m, m.form.Init()
+}
+
+// buildForm creates the form for a user's command
+func (m QuickCommandsModel) buildForm(c config.QuickCommand) *huh.Form {
+	name, description, script := c.Name, c.Description, c.Script
+	runAs, workDir := c.RunAs, c.WorkDir
+	editing := m.editing
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Key("name").Title("Name").
+				Validate(func(s string) error {
+					s = strings.TrimSpace(s)
+					if s == "" {
+						return fmt.Errorf("name is required")
+					}
+					for i, other := range m.custom {
+						if other.Name == s && i != editing {
+							return fmt.Errorf("a command named %s already exists", s)
+						}
+					}
+					return nil
+				}).
+				Value(&name),
+			huh.NewInput().Key("description").Title("Description").Value(&description),
+			huh.NewText().Key("script").Title("Script").
+				Description("Run with bash; may span several lines").
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("script is required")
+					}
+					return nil
+				}).
+				Value(&script),
+			huh.NewInput().Key("run_as").Title("Run As").
+				Description("User to run the script as. Leave blank for root.").
+				Validate(func(s string) error {
+					return config.QuickCommand{Name: "x", Script: "x", RunAs: strings.TrimSpace(s)}.Validate()
+				}).
+				Value(&runAs),
+			huh.NewInput().Key("working_dir").Title("Working Directory").
+				Description("Directory the script starts in. Leave blank for the user's home.").
+				Validate(func(s string) error {
+					return config.QuickCommand{Name: "x", Script: "x", WorkDir: strings.TrimSpace(s)}.Validate()
+				}).
+				Value(&workDir),
+		).Title("Quick Command"),
+	).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// updateForm handles the command form and saves the command
+func (m QuickCommandsModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "esc" {
+		m.form = nil
+		return m, nil
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	c := config.QuickCommand{
+		Name:        strings.TrimSpace(m.form.GetString("name")),
+		Description: strings.TrimSpace(m.form.GetString("description")),
+		Script:      strings.TrimSpace(m.form.GetString("script")),
+		RunAs:       strings.TrimSpace(m.form.GetString("run_as")),
+		WorkDir:     strings.TrimSpace(m.form.GetString("working_dir")),
+	}
+	m.form = nil
+
+	custom := append([]config.QuickCommand(nil), m.custom...)
+	index := m.editing
+	if index < 0 {
+		custom = append(custom, c)
+		index = len(custom) - 1
+	} else {
+		custom[index] = c
+	}
+	if err := config.SaveQuickCommands(custom); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m = m.withCustom(custom)
+	m.cursor = m.builtin + index
+	m.success = m.theme.Symbols.CheckMark + " Saved " + c.Name + " to " + config.CommandsPath()
+	return m, nil
+}
+
+// remove deletes the selected user command
+func (m QuickCommandsModel) remove() (QuickCommandsModel, tea.Cmd) {
+	index := m.cursor - m.builtin
+	name := m.custom[index].Name
+	custom := append(append([]config.QuickCommand(nil), m.custom[:index]...), m.custom[index+1:]...)
+	if err := config.SaveQuickCommands(custom); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m = m.withCustom(custom)
+	m.err = nil
+	m.success = m.theme.Symbols.CheckMark + " Removed " + name
+	return m, nil
+}
+
 // View renders the quick commands screen
 func (m QuickCommandsModel) View() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+	if m.form != nil {
+		content := lipgloss.JoinVertical(lipgloss.Left,
+			m.theme.Title.Render("Quick Command"),
+			m.theme.DescriptionStyle.Render("Saved in "+config.CommandsPath()),
+			"",
+			m.form.View(),
+		)
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(content))
+	}
 
 	// Header with host info
 	hostInfo := system.GetHostInfo()
@@ -211,14 +447,32 @@ func (m QuickCommandsModel) View() string {
 	categoryHeader = m.theme.CategoryStyle.Render(m.theme.Symbols.ArrowDown + " System Commands")
 	menuItems = append(menuItems, categoryHeader, "")
 
-	for i := 6; i < len(m.commands); i++ {
+	for i := 6; i < m.builtin; i++ {
+		menuItems = append(menuItems, m.renderCommand(i, m.commands[i]))
+	}
+
+	menuItems = append(menuItems, "")
+
+	// Add category: the user's own commands
+	categoryHeader = m.theme.CategoryStyle.Render(m.theme.Symbols.ArrowDown + " My Commands")
+	menuItems = append(menuItems, categoryHeader, "")
+
+	if len(m.custom) == 0 {
+		menuItems = append(menuItems, m.theme.DescriptionStyle.Render("  None yet. Press a to add one."), "")
+	}
+	for i := m.builtin; i < len(m.commands); i++ {
 		menuItems = append(menuItems, m.renderCommand(i, m.commands[i]))
 	}
 
 	menu := lipgloss.JoinVertical(lipgloss.Left, menuItems...)
 
 	// Help
-	help := m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Execute " + m.theme.Symbols.Bullet + " Esc: Back " + m.theme.Symbols.Bullet + " q: Quit")
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	helpText := m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate" + bullet + "Enter: Execute" + bullet + "a: Add"
+	if m.cursor >= m.builtin {
+		helpText += bullet + "e: Edit" + bullet + "d: Delete"
+	}
+	help := m.theme.Help.Render(helpText + bullet + "Esc: Back" + bullet + "q: Quit")
 
 	// Warning about root
 	var warning string
@@ -243,6 +497,12 @@ func (m QuickCommandsModel) View() string {
 	if warning != "" {
 		content = lipgloss.JoinVertical(lipgloss.Left, content, warning, "")
 	}
+	if m.err != nil {
+		content = lipgloss.JoinVertical(lipgloss.Left, content, m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()), "")
+	}
+	if m.success != "" {
+		content = lipgloss.JoinVertical(lipgloss.Left, content, m.theme.SuccessStyle.Render(m.success), "")
+	}
 
 	content = lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -0,0 +1,46 @@
+package screens
+
+import (
+	"testing"
+
+	"github.com/iperamuna/ravact/internal/config"
+)
+
+func TestQuickCommandScript(t *testing.T) {
+	cases := []struct {
+		command config.QuickCommand
+		want    string
+	}{
+		{config.QuickCommand{Script: "df -h"}, "df -h"},
+		{config.QuickCommand{Script: "ls", WorkDir: "/var/www"}, "cd /var/www || exit 1\nls"},
+		{
+			config.QuickCommand{Script: "php artisan about", RunAs: "deploy", WorkDir: "/var/www/app"},
+			"sudo -H -u deploy bash -c 'cd /var/www/app || exit 1\nphp artisan about'",
+		},
+	}
+	for _, c := range cases {
+		if got := quickCommandScript(c.command); got != c.want {
+			t.Errorf("quickCommandScript(%+v) = %q, want %q", c.command, got, c.want)
+		}
+	}
+}
+
+func TestQuickCommandsWithCustom(t *testing.T) {
+	m := NewQuickCommandsModel().withCustom(nil)
+	builtin := len(m.commands)
+
+	m = m.withCustom([]config.QuickCommand{{Name: "Deploy", Script: "./deploy.sh\necho done", RunAs: "deploy"}})
+	if len(m.commands) != builtin+1 {
+		t.Fatalf("expected the user's command after %d built-in ones, got %d", builtin, len(m.commands))
+	}
+	added := m.commands[builtin]
+	if added.Name != "Deploy" || added.Description != "./deploy.sh (as deploy)" || !added.RequireRoot {
+		t.Errorf("unexpected entry %+v", added)
+	}
+
+	m.cursor = builtin
+	m = m.withCustom(nil)
+	if len(m.commands) != builtin || m.cursor != builtin-1 {
+		t.Errorf("expected removing the command to move the cursor back, got %d commands, cursor %d", len(m.commands), m.cursor)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/theme"
+	"github.com/iperamuna/ravact/internal/vault"
 )
 
 // RedisPasswordModel represents the Redis password change screen
@@ -22,6 +23,7 @@ type RedisPasswordModel struct {
 	confirm      string
 	err          error
 	success      bool
+	vaultStatus  string
 }
 
 // NewRedisPasswordModel creates a new Redis password model
@@ -44,6 +46,7 @@ func (m *RedisPasswordModel) buildForm() *huh.Form {
 	return huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
+				Key("password").
 				Title("New Password").
 				Description("Password must be at least 8 characters").
 				Placeholder("Enter new password...").
@@ -60,6 +63,7 @@ func (m *RedisPasswordModel) buildForm() *huh.Form {
 				Value(&m.password),
 
 			huh.NewInput().
+				Key("confirm").
 				Title("Confirm Password").
 				Description("Re-enter the password to confirm").
 				Placeholder("Confirm password...").
@@ -129,6 +133,9 @@ func (m RedisPasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // changePassword changes the Redis password
 func (m RedisPasswordModel) changePassword() (RedisPasswordModel, tea.Cmd) {
+	m.password = m.form.GetString("password")
+	m.confirm = m.form.GetString("confirm")
+
 	// Validate passwords match
 	if m.password != m.confirm {
 		m.err = fmt.Errorf("passwords do not match")
@@ -153,6 +160,7 @@ func (m RedisPasswordModel) changePassword() (RedisPasswordModel, tea.Cmd) {
 
 	m.success = true
 	m.err = nil
+	m.vaultStatus = vault.RecordMessage("Redis", "requirepass", m.password, "")
 	return m, nil
 }
 
@@ -165,8 +173,9 @@ func (m RedisPasswordModel) View() string {
 	// If success, show message
 	if m.success {
 		msg := m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " Redis password changed successfully!")
+		status := m.theme.DescriptionStyle.Render(m.vaultStatus)
 		help := m.theme.Help.Render("Press any key to continue...")
-		content := lipgloss.JoinVertical(lipgloss.Center, "", msg, "", help)
+		content := lipgloss.JoinVertical(lipgloss.Center, "", msg, status, "", help)
 		bordered := m.theme.RenderBox(content)
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
 	}
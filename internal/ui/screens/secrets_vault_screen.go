@@ -0,0 +1,307 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+	"github.com/iperamuna/ravact/internal/vault"
+)
+
+// SecretsVaultModel represents the secrets vault screen
+type SecretsVaultModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+	cursor int
+
+	mode       string // "unlock", "create", "confirm_create", "list", "confirm_delete"
+	passphrase string
+	firstEntry string // Passphrase typed before confirmation when creating the vault
+	reveal     bool
+	confirm    Confirmation
+
+	err     error
+	message string
+}
+
+// NewSecretsVaultModel creates a new secrets vault model
+func NewSecretsVaultModel() SecretsVaultModel {
+	m := SecretsVaultModel{
+		theme: theme.DefaultTheme(),
+		mode:  "unlock",
+	}
+
+	switch {
+	case vault.Session() != nil:
+		m.mode = "list"
+	case !vault.Exists(vault.DefaultPath):
+		m.mode = "create"
+	}
+	return m
+}
+
+// Init initializes the secrets vault screen
+func (m SecretsVaultModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the secrets vault
+func (m SecretsVaultModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case "unlock", "create", "confirm_create":
+			return m.updatePassphrase(msg)
+		case "confirm_delete":
+			return m.updateConfirmDelete(msg)
+		}
+		return m.updateList(msg)
+	}
+
+	return m, nil
+}
+
+// updatePassphrase handles passphrase entry for unlocking or creating the vault
+func (m SecretsVaultModel) updatePassphrase(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: MainMenuScreen}
+		}
+	case tea.KeyBackspace:
+		if len(m.passphrase) > 0 {
+			runes := []rune(m.passphrase)
+			m.passphrase = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.passphrase += " "
+	case tea.KeyRunes:
+		m.passphrase += string(msg.Runes)
+	case tea.KeyEnter:
+		m.err = nil
+		switch m.mode {
+		case "create":
+			if len(m.passphrase) < 8 {
+				m.err = fmt.Errorf("passphrase must be at least 8 characters")
+				return m, nil
+			}
+			m.firstEntry = m.passphrase
+			m.passphrase = ""
+			m.mode = "confirm_create"
+			return m, nil
+		case "confirm_create":
+			if m.passphrase != m.firstEntry {
+				m.err = fmt.Errorf("passphrases do not match")
+				m.passphrase = ""
+				m.firstEntry = ""
+				m.mode = "create"
+				return m, nil
+			}
+		}
+
+		if err := vault.Unlock(m.passphrase); err != nil {
+			m.err = err
+			m.passphrase = ""
+			return m, nil
+		}
+		m.passphrase = ""
+		m.firstEntry = ""
+		m.mode = "list"
+		m.cursor = 0
+	}
+	return m, nil
+}
+
+// updateList handles navigation and actions on the secrets list
+func (m SecretsVaultModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := vault.Session()
+	if v == nil {
+		m.mode = "unlock"
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: MainMenuScreen}
+		}
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down", "j":
+		if m.cursor < len(v.Secrets)-1 {
+			m.cursor++
+		}
+
+	case "r":
+		m.reveal = !m.reveal
+
+	case "c", "enter":
+		if m.cursor < len(v.Secrets) {
+			s := v.Secrets[m.cursor]
+			if err := clipboard.WriteAll(s.Value); err != nil {
+				m.message = "(clipboard unavailable - press r to reveal instead)"
+			} else {
+				m.message = fmt.Sprintf("%s Copied %s / %s", m.theme.Symbols.Copy, s.Service, s.Account)
+			}
+		}
+
+	case "d":
+		if m.cursor < len(v.Secrets) {
+			s := v.Secrets[m.cursor]
+			m.confirm = NewConfirmation("delete", "Delete Secret",
+				fmt.Sprintf("Delete %s / %s from the vault?", s.Service, s.Account), ConfirmWarning)
+			m.mode = "confirm_delete"
+		}
+
+	case "L":
+		vault.Lock()
+		m.mode = "unlock"
+		m.reveal = false
+		m.message = ""
+	}
+	return m, nil
+}
+
+// updateConfirmDelete handles the delete confirmation prompt
+func (m SecretsVaultModel) updateConfirmDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	var result ConfirmResult
+	m.confirm, result = m.confirm.Update(msg)
+	switch result {
+	case ConfirmAccepted:
+		if v := vault.Session(); v != nil {
+			v.Delete(m.cursor)
+			if err := v.Save(); err != nil {
+				m.err = err
+			}
+			if m.cursor >= len(v.Secrets) && m.cursor > 0 {
+				m.cursor--
+			}
+		}
+		m.mode = "list"
+	case ConfirmCancelled:
+		m.mode = "list"
+	}
+	return m, nil
+}
+
+// View renders the secrets vault
+func (m SecretsVaultModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	if m.mode == "confirm_delete" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	header := m.theme.Title.Render("Secrets Vault")
+
+	var body string
+	switch m.mode {
+	case "unlock", "create", "confirm_create":
+		body = m.viewPassphrase()
+	default:
+		body = m.viewList()
+	}
+
+	sections := []string{header, "", body}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.message != "" {
+		sections = append(sections, "", m.theme.CopiedStyle.Render(m.message))
+	}
+
+	var help string
+	switch m.mode {
+	case "list":
+		help = m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " c: Copy " + m.theme.Symbols.Bullet + " r: Reveal " + m.theme.Symbols.Bullet + " d: Delete " + m.theme.Symbols.Bullet + " L: Lock " + m.theme.Symbols.Bullet + " Esc: Back")
+	default:
+		help = m.theme.Help.Render("Enter: Continue " + m.theme.Symbols.Bullet + " Esc: Back")
+	}
+	sections = append(sections, "", help)
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// viewPassphrase renders the passphrase prompt
+func (m SecretsVaultModel) viewPassphrase() string {
+	var prompt, description string
+	switch m.mode {
+	case "create":
+		prompt = "New vault passphrase: "
+		description = "Passwords set by ravact are encrypted with this passphrase in " + vault.DefaultPath + ".\nIt cannot be recovered if lost."
+	case "confirm_create":
+		prompt = "Confirm passphrase: "
+		description = "Enter the same passphrase again."
+	default:
+		prompt = "Passphrase: "
+		description = "Enter the vault passphrase to view stored credentials."
+	}
+
+	lines := []string{m.theme.DescriptionStyle.Render(description), ""}
+	if pending := vault.Pending(); pending > 0 {
+		lines = append(lines, m.theme.InfoStyle.Render(fmt.Sprintf("%s %d password(s) set this session will be saved on unlock", m.theme.Symbols.Info, pending)), "")
+	}
+	lines = append(lines, m.theme.Label.Render(prompt)+m.theme.SelectedItem.Render(strings.Repeat("*", len([]rune(m.passphrase)))+"_"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// viewList renders the stored secrets
+func (m SecretsVaultModel) viewList() string {
+	v := vault.Session()
+	if v == nil {
+		return ""
+	}
+	if len(v.Secrets) == 0 {
+		return m.theme.DescriptionStyle.Render("No credentials stored yet. Passwords set through ravact are added here automatically.")
+	}
+
+	var lines []string
+	for i, s := range v.Secrets {
+		value := strings.Repeat("*", 12)
+		if m.reveal && i == m.cursor {
+			value = s.Value
+		}
+
+		name := fmt.Sprintf("%-20s %-16s", s.Service, s.Account)
+		if i == m.cursor {
+			lines = append(lines, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+m.theme.SelectedItem.Render(name)+" "+value)
+		} else {
+			lines = append(lines, "  "+m.theme.MenuItem.Render(name)+" "+m.theme.DescriptionStyle.Render(value))
+		}
+
+		detail := "Updated " + s.UpdatedAt.Format("2006-01-02 15:04")
+		if s.Note != "" {
+			detail += " " + m.theme.Symbols.Bullet + " " + s.Note
+		}
+		lines = append(lines, "    "+m.theme.DescriptionStyle.Render(detail))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
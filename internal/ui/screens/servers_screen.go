@@ -0,0 +1,372 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// serverTestMsg reports the result of a connection test
+type serverTestMsg struct {
+	name   string
+	result string
+	err    error
+}
+
+// ServersModel represents the server inventory screen
+type ServersModel struct {
+	theme     *theme.Theme
+	width     int
+	height    int
+	cursor    int
+	inventory *system.ServerInventory
+	path      string
+
+	mode    string // "list", "add", "confirm_delete"
+	form    *huh.Form
+	confirm Confirmation
+	testing string
+
+	err     error
+	message string
+}
+
+// NewServersModel creates a new servers model
+func NewServersModel() ServersModel {
+	m := ServersModel{
+		theme: theme.DefaultTheme(),
+		path:  system.DefaultServerInventoryPath(),
+		mode:  "list",
+	}
+
+	inv, err := system.LoadServerInventory(m.path)
+	if err != nil {
+		m.err = err
+		inv = &system.ServerInventory{}
+	}
+	m.inventory = inv
+
+	// Start on the active server
+	if active := system.CurrentTransport(); active.IsRemote() {
+		for i, s := range inv.Servers {
+			if s.Name == active.Name() {
+				m.cursor = i + 1
+			}
+		}
+	}
+	return m
+}
+
+// Init initializes the servers screen
+func (m ServersModel) Init() tea.Cmd {
+	return nil
+}
+
+// buildForm creates the add server form
+func (m ServersModel) buildForm() *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("name").
+				Title("Name").
+				Description("Short name used to select this server").
+				Placeholder("web-1").
+				Validate(func(s string) error {
+					s = strings.TrimSpace(s)
+					if s == "" {
+						return fmt.Errorf("name cannot be empty")
+					}
+					if _, exists := m.inventory.Find(s); exists {
+						return fmt.Errorf("server %q already exists", s)
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Key("host").
+				Title("Host").
+				Description("Hostname or IP address").
+				Placeholder("203.0.113.10").
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("host cannot be empty")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Key("user").
+				Title("User").
+				Description("SSH user (leave empty to use ~/.ssh/config)").
+				Placeholder("root"),
+			huh.NewInput().
+				Key("port").
+				Title("Port").
+				Placeholder("22").
+				Validate(func(s string) error {
+					if s == "" {
+						return nil
+					}
+					if p, err := strconv.Atoi(s); err != nil || p < 1 || p > 65535 {
+						return fmt.Errorf("port must be between 1 and 65535")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Key("identity_file").
+				Title("Identity File").
+				Description("Private key to use (optional)").
+				Placeholder("~/.ssh/id_ed25519"),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// Update handles messages for the servers screen
+func (m ServersModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case serverTestMsg:
+		m.testing = ""
+		if msg.err != nil {
+			m.err = msg.err
+		} else {
+			m.message = fmt.Sprintf("%s %s: %s", m.theme.Symbols.CheckMark, msg.name, msg.result)
+		}
+		return m, nil
+	}
+
+	switch m.mode {
+	case "add":
+		return m.updateForm(msg)
+	case "confirm_delete":
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			return m.updateConfirmDelete(keyMsg)
+		}
+		return m, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		return m.updateList(keyMsg)
+	}
+	return m, nil
+}
+
+// updateList handles navigation and actions on the server list
+func (m ServersModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: MainMenuScreen}
+		}
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down", "j":
+		if m.cursor < len(m.inventory.Servers) {
+			m.cursor++
+		}
+
+	case "enter":
+		if m.cursor == 0 {
+			system.UseTransport(system.LocalTransport{})
+		} else {
+			system.UseTransport(system.NewSSHTransport(m.inventory.Servers[m.cursor-1]))
+		}
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: MainMenuScreen, Data: map[string]interface{}{"serverChanged": true}}
+		}
+
+	case "a":
+		m.err = nil
+		m.message = ""
+		m.form = m.buildForm()
+		m.mode = "add"
+		return m, m.form.Init()
+
+	case "t":
+		if m.cursor == 0 || m.testing != "" {
+			return m, nil
+		}
+		s := m.inventory.Servers[m.cursor-1]
+		m.err = nil
+		m.message = ""
+		m.testing = s.Name
+		return m, func() tea.Msg {
+			result, err := system.NewSSHTransport(s).TestConnection()
+			return serverTestMsg{name: s.Name, result: result, err: err}
+		}
+
+	case "d":
+		if m.cursor == 0 {
+			return m, nil
+		}
+		s := m.inventory.Servers[m.cursor-1]
+		m.confirm = NewConfirmation("delete", "Remove Server",
+			fmt.Sprintf("Remove %s (%s) from the server inventory?", s.Name, s.Address()), ConfirmWarning)
+		m.mode = "confirm_delete"
+	}
+	return m, nil
+}
+
+// updateForm passes messages to the add server form
+func (m ServersModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = "list"
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	switch m.form.State {
+	case huh.StateCompleted:
+		m.addServer()
+		m.mode = "list"
+		return m, nil
+	case huh.StateAborted:
+		m.mode = "list"
+		return m, nil
+	}
+	return m, cmd
+}
+
+// addServer saves the server entered in the form
+func (m *ServersModel) addServer() {
+	port, _ := strconv.Atoi(m.form.GetString("port"))
+	s := system.Server{
+		Name:         strings.TrimSpace(m.form.GetString("name")),
+		Host:         strings.TrimSpace(m.form.GetString("host")),
+		User:         strings.TrimSpace(m.form.GetString("user")),
+		Port:         port,
+		IdentityFile: strings.TrimSpace(m.form.GetString("identity_file")),
+	}
+
+	if err := m.inventory.Add(s); err != nil {
+		m.err = err
+		return
+	}
+	if err := m.inventory.Save(m.path); err != nil {
+		m.err = err
+		return
+	}
+	m.cursor = len(m.inventory.Servers)
+	m.message = fmt.Sprintf("%s Added %s - press t to test the connection", m.theme.Symbols.CheckMark, s.Name)
+}
+
+// updateConfirmDelete handles the remove confirmation prompt
+func (m ServersModel) updateConfirmDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	var result ConfirmResult
+	m.confirm, result = m.confirm.Update(msg)
+	switch result {
+	case ConfirmAccepted:
+		s := m.inventory.Servers[m.cursor-1]
+		m.inventory.Remove(s.Name)
+		if err := m.inventory.Save(m.path); err != nil {
+			m.err = err
+		}
+		// Fall back to this machine if the active server was removed
+		if active := system.CurrentTransport(); active.IsRemote() && active.Name() == s.Name {
+			system.UseTransport(system.LocalTransport{})
+		}
+		if m.cursor > len(m.inventory.Servers) {
+			m.cursor--
+		}
+		m.mode = "list"
+	case ConfirmCancelled:
+		m.mode = "list"
+	}
+	return m, nil
+}
+
+// View renders the servers screen
+func (m ServersModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	switch m.mode {
+	case "confirm_delete":
+		return m.confirm.View(m.theme, m.width, m.height)
+	case "add":
+		header := m.theme.Title.Render("Add Server")
+		help := m.theme.Help.Render("Tab: Next field " + m.theme.Symbols.Bullet + " Enter: Submit " + m.theme.Symbols.Bullet + " Esc: Cancel")
+		content := lipgloss.JoinVertical(lipgloss.Left, header, "", m.form.View(), "", help)
+		bordered := m.theme.RenderBox(content)
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+	}
+
+	header := m.theme.Title.Render("Servers")
+	description := m.theme.DescriptionStyle.Render("Select the host ravact manages. Remote servers are reached with your ssh client and keys.")
+
+	active := system.CurrentTransport()
+	var lines []string
+	addLine := func(i int, name, address string, isActive bool) {
+		marker := "  "
+		if isActive {
+			marker = m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " ")
+		}
+		text := fmt.Sprintf("%-20s %s", name, address)
+		if i == m.cursor {
+			lines = append(lines, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+marker+m.theme.SelectedItem.Render(text))
+		} else {
+			lines = append(lines, "  "+marker+m.theme.MenuItem.Render(text))
+		}
+	}
+
+	addLine(0, "localhost", "(this machine)", !active.IsRemote())
+	for i, s := range m.inventory.Servers {
+		address := s.Address()
+		if len(s.Tags) > 0 {
+			address += "  [" + strings.Join(s.Tags, ", ") + "]"
+		}
+		addLine(i+1, s.Name, address, active.IsRemote() && active.Name() == s.Name)
+	}
+
+	sections := []string{header, "", description, "", lipgloss.JoinVertical(lipgloss.Left, lines...)}
+	if len(m.inventory.Servers) == 0 {
+		sections = append(sections, "", m.theme.DescriptionStyle.Render("No servers yet. Press a to add one to "+m.path))
+	}
+	if m.testing != "" {
+		sections = append(sections, "", m.theme.InfoStyle.Render(m.theme.Symbols.Info+" Connecting to "+m.testing+"..."))
+	}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.message != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.message))
+	}
+
+	help := m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Select " + m.theme.Symbols.Bullet + " a: Add " + m.theme.Symbols.Bullet + " t: Test " + m.theme.Symbols.Bullet + " d: Remove " + m.theme.Symbols.Bullet + " Esc: Back")
+	sections = append(sections, "", help)
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -0,0 +1,398 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/config"
+	"github.com/iperamuna/ravact/internal/notify"
+	"github.com/iperamuna/ravact/internal/settings"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// PreferencesChangedMsg is sent after the preferences are saved so the
+// root model can apply them without a restart
+type PreferencesChangedMsg struct {
+	Preferences settings.Preferences
+	Settings    config.Settings
+}
+
+// SettingsModel edits the user's preferences
+type SettingsModel struct {
+	theme   *theme.Theme
+	width   int
+	height  int
+	prefs   settings.Preferences
+	app     config.Settings
+	notify  notify.Config
+	form    *huh.Form
+	err     error
+	success string
+}
+
+// NewSettingsModel creates the settings screen
+func NewSettingsModel() SettingsModel {
+	m := SettingsModel{theme: theme.DefaultTheme()}
+	m.prefs, m.err = settings.LoadPreferences()
+	if app, err := config.Load(); err != nil {
+		m.err = err
+	} else {
+		m.app = app
+	}
+	if cfg, err := notify.Load(); err != nil {
+		m.err = err
+	} else {
+		m.notify = cfg
+	}
+	m.form = m.buildForm()
+	return m
+}
+
+func (m *SettingsModel) buildForm() *huh.Form {
+	minutes := strconv.Itoa(m.prefs.IdleLockMinutes)
+	pinDescription := "Unlock with this PIN instead of your password. Leave blank for none."
+	if m.prefs.HasPIN() {
+		pinDescription = "A PIN is set. Leave blank to keep it."
+	}
+
+	fields := []huh.Field{
+		huh.NewInput().
+			Key("idle_minutes").
+			Title("Lock After Idle Minutes").
+			Description("Lock the screen after this many minutes without input (0 = never)").
+			Validate(func(s string) error {
+				n, err := strconv.Atoi(strings.TrimSpace(s))
+				if err != nil || n < 0 {
+					return fmt.Errorf("enter a number of minutes, or 0 to disable")
+				}
+				return nil
+			}).
+			Value(&minutes),
+		huh.NewInput().
+			Key("pin").
+			Title("Unlock PIN").
+			Description(pinDescription).
+			EchoMode(huh.EchoModePassword).
+			Validate(func(s string) error {
+				if s != "" && len(s) < 4 {
+					return fmt.Errorf("PIN must be at least 4 characters")
+				}
+				return nil
+			}),
+	}
+	if m.prefs.HasPIN() {
+		removePIN := false
+		fields = append(fields, huh.NewConfirm().
+			Key("remove_pin").
+			Title("Remove PIN").
+			Description("Unlock with your password instead").
+			Value(&removePIN))
+	}
+
+	return huh.NewForm(m.appGroup(), huh.NewGroup(fields...).Title("Screen Lock"), m.notifyGroup()).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// appGroup edits the application settings in config.yaml
+func (m *SettingsModel) appGroup() *huh.Group {
+	editor := m.app.Editor
+	themeName := m.app.Theme
+	showHidden := m.app.ShowHiddenFiles
+	webGroup := m.app.WebGroup
+	scriptsDir := m.app.ScriptsDir
+	confirmRun := m.app.ConfirmBeforeExecute
+
+	return huh.NewGroup(
+		huh.NewInput().
+			Key("editor").
+			Title("Default Editor").
+			Description("Open files with this editor without asking. Leave blank to choose each time.").
+			Validate(func(s string) error {
+				return config.Settings{Editor: strings.TrimSpace(s)}.Validate()
+			}).
+			Value(&editor),
+		huh.NewSelect[string]().
+			Key("theme").
+			Title("Theme").
+			Options(
+				huh.NewOption("Auto (detect from the terminal)", config.ThemeAuto),
+				huh.NewOption("ASCII (no Unicode symbols)", config.ThemeASCII),
+			).
+			Value(&themeName),
+		huh.NewConfirm().
+			Key("show_hidden").
+			Title("Show Hidden Files").
+			Description("List dotfiles in the file browser").
+			Value(&showHidden),
+		huh.NewInput().
+			Key("web_group").
+			Title("Web Server Group").
+			Description("Group that owns site files with the site user").
+			Validate(func(s string) error {
+				if strings.TrimSpace(s) == "" {
+					return fmt.Errorf("enter a group name")
+				}
+				return config.Settings{WebGroup: strings.TrimSpace(s)}.Validate()
+			}).
+			Value(&webGroup),
+		huh.NewInput().
+			Key("scripts_dir").
+			Title("Scripts Directory").
+			Description("Setup scripts here replace the bundled ones of the same name. Leave blank for none.").
+			Validate(func(s string) error {
+				return config.Settings{ScriptsDir: strings.TrimSpace(s)}.Validate()
+			}).
+			Value(&scriptsDir),
+		huh.NewConfirm().
+			Key("confirm_run").
+			Title("Confirm Before Running").
+			Description("Ask before any command or setup script runs").
+			Value(&confirmRun),
+	).Title("Application")
+}
+
+// appSettings builds the application settings from the submitted form
+func (m SettingsModel) appSettings() config.Settings {
+	return config.Settings{
+		Editor:               strings.TrimSpace(m.form.GetString("editor")),
+		Theme:                m.form.GetString("theme"),
+		ShowHiddenFiles:      m.form.GetBool("show_hidden"),
+		WebGroup:             strings.TrimSpace(m.form.GetString("web_group")),
+		ScriptsDir:           strings.TrimSpace(m.form.GetString("scripts_dir")),
+		ConfirmBeforeExecute: m.form.GetBool("confirm_run"),
+	}
+}
+
+// notifyGroup edits the channels that are told when long operations finish
+func (m *SettingsModel) notifyGroup() *huh.Group {
+	slack := m.notify.Channel(notify.ChannelSlack).WebhookURL
+	telegram := m.notify.Channel(notify.ChannelTelegram)
+	token, chatID := telegram.BotToken, telegram.ChatID
+	email := m.notify.Channel(notify.ChannelEmail).Email
+	minSeconds := strconv.Itoa(int(m.notify.MinDuration().Seconds()))
+	failuresOnly := m.notify.FailuresOnly
+
+	return huh.NewGroup(
+		huh.NewInput().
+			Key("slack_webhook").
+			Title("Slack Webhook URL").
+			Description("Incoming webhook to post to. Leave blank for none.").
+			Validate(func(s string) error {
+				if s != "" && !strings.HasPrefix(s, "https://") {
+					return fmt.Errorf("webhook URL must start with https://")
+				}
+				return nil
+			}).
+			Value(&slack),
+		huh.NewInput().
+			Key("telegram_token").
+			Title("Telegram Bot Token").
+			Description("From @BotFather. Leave blank for none.").
+			EchoMode(huh.EchoModePassword).
+			Value(&token),
+		huh.NewInput().
+			Key("telegram_chat").
+			Title("Telegram Chat ID").
+			Value(&chatID),
+		huh.NewInput().
+			Key("notify_email").
+			Title("Email").
+			Description("Sent through the local sendmail. Leave blank for none.").
+			Validate(func(s string) error {
+				if s != "" && !strings.Contains(s, "@") {
+					return fmt.Errorf("enter an email address")
+				}
+				return nil
+			}).
+			Value(&email),
+		huh.NewInput().
+			Key("notify_min_seconds").
+			Title("Minimum Duration (seconds)").
+			Description("Only notify about operations that ran at least this long").
+			Validate(func(s string) error {
+				if n, err := strconv.Atoi(strings.TrimSpace(s)); err != nil || n < 1 {
+					return fmt.Errorf("enter a number of seconds")
+				}
+				return nil
+			}).
+			Value(&minSeconds),
+		huh.NewConfirm().
+			Key("notify_failures_only").
+			Title("Only Notify About Failures").
+			Value(&failuresOnly),
+	).Title("Notifications").
+		Description("Send a message when installs, deploys, and other long operations finish")
+}
+
+// notifyConfig builds the notification config from the submitted form,
+// keeping any channels that are only configured in the file
+func (m SettingsModel) notifyConfig() (notify.Config, error) {
+	cfg := notify.Config{FailuresOnly: m.form.GetBool("notify_failures_only")}
+	cfg.MinSeconds, _ = strconv.Atoi(strings.TrimSpace(m.form.GetString("notify_min_seconds")))
+
+	if url := strings.TrimSpace(m.form.GetString("slack_webhook")); url != "" {
+		cfg.Channels = append(cfg.Channels, notify.Channel{Type: notify.ChannelSlack, WebhookURL: url})
+	}
+	token := strings.TrimSpace(m.form.GetString("telegram_token"))
+	chatID := strings.TrimSpace(m.form.GetString("telegram_chat"))
+	if token != "" || chatID != "" {
+		if token == "" || chatID == "" {
+			return cfg, fmt.Errorf("enter both a Telegram bot token and a chat ID")
+		}
+		cfg.Channels = append(cfg.Channels, notify.Channel{Type: notify.ChannelTelegram, BotToken: token, ChatID: chatID})
+	}
+	if email := strings.TrimSpace(m.form.GetString("notify_email")); email != "" {
+		cfg.Channels = append(cfg.Channels, notify.Channel{Type: notify.ChannelEmail, Email: email})
+	}
+
+	seen := map[string]bool{}
+	for _, ch := range m.notify.Channels {
+		switch ch.Type {
+		case notify.ChannelSlack, notify.ChannelTelegram, notify.ChannelEmail:
+			// The first of each is edited above; extra ones are kept as they are
+			if !seen[ch.Type] {
+				seen[ch.Type] = true
+				continue
+			}
+		}
+		cfg.Channels = append(cfg.Channels, ch)
+	}
+	return cfg, nil
+}
+
+// Init initializes the settings screen
+func (m SettingsModel) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+// Update handles messages for the settings screen
+func (m SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: MainMenuScreen}
+			}
+		}
+		if m.form.State == huh.StateCompleted {
+			m.success = ""
+			m.form = m.buildForm()
+			return m, m.form.Init()
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State == huh.StateCompleted {
+		return m.save()
+	}
+	return m, cmd
+}
+
+// save stores the submitted preferences
+func (m SettingsModel) save() (SettingsModel, tea.Cmd) {
+	prefs := m.prefs
+	prefs.IdleLockMinutes, _ = strconv.Atoi(strings.TrimSpace(m.form.GetString("idle_minutes")))
+	if pin := m.form.GetString("pin"); pin != "" {
+		if err := prefs.SetPIN(pin); err != nil {
+			m.err = err
+			return m, nil
+		}
+	} else if m.form.GetBool("remove_pin") {
+		_ = prefs.SetPIN("")
+	}
+
+	app := m.appSettings()
+	notifyCfg, err := m.notifyConfig()
+	if err == nil {
+		err = app.Save()
+	}
+	if err == nil {
+		err = notifyCfg.Save()
+	}
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	if err := prefs.Save(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.prefs = prefs
+	m.app = app
+	m.notify = notifyCfg
+	m.err = nil
+	m.success = m.theme.Symbols.CheckMark + " Settings saved to " + settings.PreferencesPath()
+	return m, func() tea.Msg {
+		return PreferencesChangedMsg{Preferences: prefs, Settings: app}
+	}
+}
+
+// View renders the settings screen
+func (m SettingsModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Settings"),
+		"",
+	}
+	if m.form.State == huh.StateCompleted {
+		editor := m.app.Editor
+		if editor == "" {
+			editor = "ask each time"
+		}
+		sections = append(sections, m.theme.DescriptionStyle.Render(fmt.Sprintf("Editor: %s, theme: %s, web group: %s", editor, m.app.Theme, m.app.WebGroup)))
+		if m.app.ConfirmBeforeExecute {
+			sections = append(sections, m.theme.DescriptionStyle.Render("Asks before running commands"))
+		}
+		if m.prefs.IdleLockMinutes > 0 {
+			sections = append(sections, m.theme.DescriptionStyle.Render(fmt.Sprintf("Locks after %d minute(s) idle", m.prefs.IdleLockMinutes)))
+		} else {
+			sections = append(sections, m.theme.DescriptionStyle.Render("Idle lock is off"))
+		}
+		if n := len(m.notify.Channels); n > 0 {
+			sections = append(sections, m.theme.DescriptionStyle.Render(fmt.Sprintf("Notifies %d channel(s) about operations over %s", n, m.notify.MinDuration())))
+		} else {
+			sections = append(sections, m.theme.DescriptionStyle.Render("Notifications are off"))
+		}
+	} else {
+		sections = append(sections, m.form.View())
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := "Tab/Shift+Tab: Navigate" + bullet + "Enter: Save" + bullet + "Esc: Back"
+	if m.form.State == huh.StateCompleted {
+		help = "Enter: Edit again" + bullet + "Esc: Back"
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -0,0 +1,303 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/settings"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// SettingsTransferModel exports and imports ravact's own settings
+type SettingsTransferModel struct {
+	theme   *theme.Theme
+	width   int
+	height  int
+	version string
+	cursor  int
+
+	mode       string // "menu", "export_path", "export_pass", "export_confirm", "import_path", "import_pass", "import_confirm", "done"
+	path       string
+	passphrase string
+	firstEntry string
+	archive    *settings.Archive
+	exported   bool
+	confirm    Confirmation
+
+	err    error
+	result []string
+}
+
+// NewSettingsTransferModel creates a new settings export/import model
+func NewSettingsTransferModel(version string) SettingsTransferModel {
+	return SettingsTransferModel{
+		theme:   theme.DefaultTheme(),
+		version: version,
+		mode:    "menu",
+	}
+}
+
+// Init initializes the settings transfer screen
+func (m SettingsTransferModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the settings transfer screen
+func (m SettingsTransferModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		switch m.mode {
+		case "menu":
+			return m.updateMenu(msg)
+		case "import_confirm":
+			var result ConfirmResult
+			m.confirm, result = m.confirm.Update(msg)
+			switch result {
+			case ConfirmAccepted:
+				m.result, m.err = m.archive.Apply()
+				m.mode = "done"
+			case ConfirmCancelled:
+				m.reset()
+			}
+			return m, nil
+		case "done":
+			if msg.String() == "enter" || msg.String() == "esc" {
+				m.reset()
+			}
+			return m, nil
+		}
+		return m.updateInput(msg)
+	}
+
+	return m, nil
+}
+
+// reset returns to the menu
+func (m *SettingsTransferModel) reset() {
+	m.mode = "menu"
+	m.path = ""
+	m.passphrase = ""
+	m.firstEntry = ""
+	m.archive = nil
+	m.exported = false
+	m.result = nil
+}
+
+// updateMenu handles the export/import choice
+func (m SettingsTransferModel) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: MainMenuScreen}
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < 1 {
+			m.cursor++
+		}
+	case "enter", " ":
+		m.err = nil
+		if m.cursor == 0 {
+			m.path = settings.DefaultExportPath()
+			m.mode = "export_path"
+		} else {
+			m.path = ""
+			m.mode = "import_path"
+		}
+	}
+	return m, nil
+}
+
+// updateInput handles path and passphrase entry
+func (m SettingsTransferModel) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	field := &m.path
+	if strings.HasSuffix(m.mode, "_pass") || m.mode == "export_confirm" {
+		field = &m.passphrase
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.reset()
+		return m, nil
+	case tea.KeyBackspace:
+		if len(*field) > 0 {
+			runes := []rune(*field)
+			*field = string(runes[:len(runes)-1])
+		}
+		return m, nil
+	case tea.KeySpace:
+		*field += " "
+		return m, nil
+	case tea.KeyRunes:
+		*field += string(msg.Runes)
+		return m, nil
+	case tea.KeyEnter:
+	default:
+		return m, nil
+	}
+
+	m.err = nil
+	switch m.mode {
+	case "export_path":
+		if strings.TrimSpace(m.path) == "" {
+			m.err = fmt.Errorf("enter a file to export to")
+			return m, nil
+		}
+		m.path = strings.TrimSpace(m.path)
+		m.mode = "export_pass"
+
+	case "export_pass":
+		if len(m.passphrase) < 8 {
+			m.err = fmt.Errorf("passphrase must be at least 8 characters")
+			return m, nil
+		}
+		m.firstEntry = m.passphrase
+		m.passphrase = ""
+		m.mode = "export_confirm"
+
+	case "export_confirm":
+		if m.passphrase != m.firstEntry {
+			m.err = fmt.Errorf("passphrases do not match")
+			m.passphrase = ""
+			m.firstEntry = ""
+			m.mode = "export_pass"
+			return m, nil
+		}
+		archive, err := settings.Export(m.path, m.passphrase, m.version)
+		m.passphrase = ""
+		m.firstEntry = ""
+		if err != nil {
+			m.err = err
+			m.mode = "export_pass"
+			return m, nil
+		}
+		m.archive = archive
+		m.exported = true
+		m.mode = "done"
+
+	case "import_path":
+		if strings.TrimSpace(m.path) == "" {
+			m.err = fmt.Errorf("enter the archive to import")
+			return m, nil
+		}
+		m.path = strings.TrimSpace(m.path)
+		m.mode = "import_pass"
+
+	case "import_pass":
+		archive, err := settings.Open(m.path, m.passphrase)
+		m.passphrase = ""
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.archive = archive
+		m.confirm = NewConfirmation("import", "Import Settings",
+			fmt.Sprintf("Import %d file(s) exported from %s on %s?\nExisting settings with the same names will be replaced.",
+				len(archive.Files), archive.Hostname, archive.CreatedAt.Local().Format("2006-01-02 15:04")), ConfirmWarning)
+		m.mode = "import_confirm"
+	}
+	return m, nil
+}
+
+// View renders the settings transfer screen
+func (m SettingsTransferModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	if m.mode == "import_confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	header := m.theme.Title.Render("Export / Import Settings")
+	var body []string
+	help := m.theme.Help.Render("Enter: Continue " + m.theme.Symbols.Bullet + " Esc: Cancel")
+
+	switch m.mode {
+	case "menu":
+		body = append(body,
+			m.theme.DescriptionStyle.Render("Move ravact's settings to a new server or restore them after a reinstall."),
+			m.theme.DescriptionStyle.Render("Includes "+settings.UserDir+" (servers, secrets vault, ...), "+settings.SystemDir+" (site notes, ...), and the metadata store (tags, ...)."),
+			"",
+		)
+		for i, item := range []string{"Export settings to an encrypted archive", "Import settings from an archive"} {
+			if i == m.cursor {
+				body = append(body, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+m.theme.SelectedItem.Render(item))
+			} else {
+				body = append(body, "  "+m.theme.MenuItem.Render(item))
+			}
+		}
+		help = m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Select " + m.theme.Symbols.Bullet + " Esc: Back")
+
+	case "export_path", "import_path":
+		prompt := "Export to: "
+		if m.mode == "import_path" {
+			prompt = "Archive to import: "
+		}
+		body = append(body, m.theme.Label.Render(prompt)+m.theme.SelectedItem.Render(m.path+"_"))
+
+	case "export_pass", "export_confirm", "import_pass":
+		prompt := "Passphrase: "
+		description := "The archive is encrypted with this passphrase (AES-256-GCM). It is needed to import it."
+		switch m.mode {
+		case "export_confirm":
+			prompt = "Confirm passphrase: "
+			description = "Enter the same passphrase again."
+		case "import_pass":
+			description = "Enter the passphrase used when " + m.path + " was exported."
+		}
+		body = append(body,
+			m.theme.DescriptionStyle.Render(description), "",
+			m.theme.Label.Render(prompt)+m.theme.SelectedItem.Render(strings.Repeat("*", len([]rune(m.passphrase)))+"_"),
+		)
+
+	case "done":
+		if m.err != nil {
+			body = append(body, m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" Import stopped: "+m.err.Error()))
+		}
+		if m.exported {
+			body = append(body,
+				m.theme.SuccessStyle.Render(fmt.Sprintf("%s Exported %d file(s) to %s", m.theme.Symbols.CheckMark, len(m.archive.Files), m.path)),
+				"",
+				m.theme.DescriptionStyle.Render(fmt.Sprintf("%d from %s, %d from %s, %d from %s", m.archive.Count(settings.ScopeUser), settings.UserDir, m.archive.Count(settings.ScopeSystem), settings.SystemDir, m.archive.Count(settings.ScopeState), system.StateDir)),
+			)
+		} else {
+			body = append(body, m.theme.SuccessStyle.Render(fmt.Sprintf("%s Imported %d file(s)", m.theme.Symbols.CheckMark, len(m.result))))
+			for i, path := range m.result {
+				if i == 10 {
+					body = append(body, m.theme.DescriptionStyle.Render(fmt.Sprintf("  ... and %d more", len(m.result)-10)))
+					break
+				}
+				body = append(body, m.theme.DescriptionStyle.Render("  "+path))
+			}
+		}
+		help = m.theme.Help.Render("Enter: Done")
+	}
+
+	sections := []string{header, "", lipgloss.JoinVertical(lipgloss.Left, body...)}
+	if m.err != nil && m.mode != "done" {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	sections = append(sections, "", help)
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
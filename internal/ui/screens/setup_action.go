@@ -6,6 +6,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/iperamuna/ravact/internal/models"
+	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/theme"
 )
 
@@ -23,13 +24,15 @@ type SetupActionModel struct {
 	width   int
 	height  int
 	cursor  int
-	script  models.SetupScript
-	status  models.ServiceStatus
-	actions []SetupAction
+	script    models.SetupScript
+	status    models.ServiceStatus
+	provision system.ProvisionState
+	actions   []SetupAction
+	notice    string
 }
 
 // NewSetupActionModel creates a new setup action model
-func NewSetupActionModel(script models.SetupScript, status models.ServiceStatus) SetupActionModel {
+func NewSetupActionModel(script models.SetupScript, status models.ServiceStatus, provision system.ProvisionState) SetupActionModel {
 	// Determine available actions based on status
 	actions := []SetupAction{}
 
@@ -208,12 +211,45 @@ func NewSetupActionModel(script models.SetupScript, status models.ServiceStatus)
 		}
 	}
 
+	// Swap is sized and tuned on its own screen whatever its status
+	if script.ID == "swap" {
+		actions = []SetupAction{
+			{
+				ID:          "configure",
+				Name:        "Configure Swap",
+				Description: "Create or resize the swap file and set swappiness and cache pressure",
+				Command:     "__swap_setup__",
+			},
+		}
+	}
+
+	// Exporters are picked, and their scrape source set, on their own screen
+	if script.ID == "exporters" {
+		actions = []SetupAction{
+			{
+				ID:          "configure",
+				Name:        "Configure Exporters",
+				Description: "Choose exporters and the Prometheus server allowed to scrape them",
+				Command:     "__exporters_setup__",
+			},
+		}
+	}
+
+	// Running the script again changes nothing when it is already applied
+	for i := range actions {
+		if actions[i].ID == "reinstall" && provision.Satisfied {
+			actions[i].Name = "Reinstall (force)"
+			actions[i].Description = "Already installed and up to date; run the setup script again anyway"
+		}
+	}
+
 	return SetupActionModel{
-		theme:   theme.DefaultTheme(),
-		cursor:  0,
-		script:  script,
-		status:  status,
-		actions: actions,
+		theme:     theme.DefaultTheme(),
+		cursor:    0,
+		script:    script,
+		status:    status,
+		provision: provision,
+		actions:   actions,
 	}
 }
 
@@ -231,6 +267,7 @@ func (m SetupActionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		m.notice = ""
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -268,12 +305,36 @@ func (m SetupActionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 				
-				return m, func() tea.Msg {
-					return ExecutionStartMsg{
-						Command:     selectedAction.Command,
-						Description: selectedAction.Description,
+				// Handle special navigation for swap setup
+				if selectedAction.Command == "__swap_setup__" {
+					return m, func() tea.Msg {
+						return NavigateMsg{Screen: SwapScreen}
+					}
+				}
+				
+				// Handle special navigation for exporters setup
+				if selectedAction.Command == "__exporters_setup__" {
+					return m, func() tea.Msg {
+						return NavigateMsg{Screen: ExportersScreen}
 					}
 				}
+				
+				// Installing is skipped while the recorded state is satisfied
+				if selectedAction.ID == "install" && m.provision.Satisfied {
+					m.notice = fmt.Sprintf("%s is already installed and up to date; nothing to do", m.script.Name)
+					return m, nil
+				}
+
+				start := ExecutionStartMsg{
+					Command:     selectedAction.Command,
+					Description: selectedAction.Description,
+				}
+				if selectedAction.ID == "remove" {
+					start.Removes = m.script.ID
+				}
+				return m, func() tea.Msg {
+					return start
+				}
 			}
 		}
 	}
@@ -315,6 +376,19 @@ func (m SetupActionModel) View() string {
 	}
 	statusInfo := statusColor.Render(fmt.Sprintf("Current Status: %s", statusText))
 
+	// Provisioning state recorded by ravact
+	if rec := m.provision.Record; rec != nil {
+		statusInfo = lipgloss.JoinVertical(lipgloss.Left, statusInfo,
+			m.theme.DescriptionStyle.Render("Applied by ravact on "+rec.AppliedAt.Format("2006-01-02 15:04")))
+	}
+	for _, drift := range m.provision.Drift {
+		statusInfo = lipgloss.JoinVertical(lipgloss.Left, statusInfo,
+			m.theme.WarningStyle.Render(m.theme.Symbols.Warning+" Drift: "+drift))
+	}
+	if m.notice != "" {
+		statusInfo = lipgloss.JoinVertical(lipgloss.Left, statusInfo, "", m.theme.InfoStyle.Render(m.notice))
+	}
+
 	// Description
 	desc := m.theme.DescriptionStyle.Render(m.script.Description)
 
@@ -0,0 +1,485 @@
+package screens
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/models"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// maxParallelSetup is how many setup scripts of a batch run at once
+const maxParallelSetup = 3
+
+// batchOutputLines is how much of each script's output is kept
+const batchOutputLines = 500
+
+// setupDependencies lists scripts that must succeed before another script
+// in the same batch starts. Scripts not listed are independent.
+var setupDependencies = map[string][]string{
+	"pgbouncer": {"postgresql"},
+	"certbot":   {"nginx"},
+}
+
+// setupBatchPreamble makes scripts running side by side queue for the
+// package manager's lock instead of failing on it
+const setupBatchPreamble = `# Scripts in a ravact batch share the package manager; queue for its lock
+if command -v flock >/dev/null 2>&1; then
+  for __ravact_pm in apt-get apt dpkg dnf yum; do
+    eval "$__ravact_pm() { flock /run/ravact-packages.lock $__ravact_pm \"\$@\"; }"
+  done
+fi
+`
+
+// batchJobState is the progress of one script in a batch
+type batchJobState int
+
+const (
+	batchWaiting batchJobState = iota
+	batchRunning
+	batchSucceeded
+	batchFailed
+	batchSkipped
+)
+
+// batchJob is one setup script in a batch
+type batchJob struct {
+	script  models.SetupScript
+	state   batchJobState
+	output  []string
+	err     error
+	started time.Time
+	ended   time.Time
+}
+
+// batchEvent is a line of output from a script, or its exit
+type batchEvent struct {
+	line string
+	done bool
+	err  error
+}
+
+// batchEventMsg delivers an event from the script at index
+type batchEventMsg struct {
+	index  int
+	event  batchEvent
+	events <-chan batchEvent
+}
+
+// batchTickMsg redraws spinners and elapsed times
+type batchTickMsg struct{}
+
+// SetupBatchModel installs several setup scripts at once, running
+// independent ones side by side and showing each in its own pane
+type SetupBatchModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	jobs     []batchJob
+	cursor   int
+	expanded bool // Show the selected script's output full screen
+	started  time.Time
+	ended    time.Time
+	ctx      context.Context
+	cancel   context.CancelFunc
+	notice   string
+}
+
+// NewSetupBatchModel creates a batch for the given scripts
+func NewSetupBatchModel(scripts []models.SetupScript) SetupBatchModel {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := SetupBatchModel{
+		theme:   theme.DefaultTheme(),
+		started: time.Now(),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	for _, script := range scripts {
+		m.jobs = append(m.jobs, batchJob{script: script})
+	}
+	return m
+}
+
+// Init starts the first scripts
+func (m SetupBatchModel) Init() tea.Cmd {
+	return tea.Batch(m.schedule(), batchTick())
+}
+
+func batchTick() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg { return batchTickMsg{} })
+}
+
+// schedule starts waiting scripts whose dependencies have succeeded
+func (m SetupBatchModel) schedule() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, i := range m.nextJobs() {
+		cmds = append(cmds, m.start(i))
+	}
+	return tea.Batch(cmds...)
+}
+
+// nextJobs returns the waiting scripts that can start now, keeping at most
+// maxParallelSetup running. Scripts whose dependencies failed are skipped.
+func (m SetupBatchModel) nextJobs() []int {
+	index := make(map[string]int, len(m.jobs))
+	running := 0
+	for i, job := range m.jobs {
+		index[job.script.ID] = i
+		if job.state == batchRunning {
+			running++
+		}
+	}
+
+	var next []int
+	for changed := true; changed; {
+		changed = false
+		for i := range m.jobs {
+			job := &m.jobs[i]
+			if job.state != batchWaiting || slices.Contains(next, i) {
+				continue
+			}
+			ready := true
+			for _, dep := range setupDependencies[job.script.ID] {
+				d, ok := index[dep]
+				if !ok {
+					continue
+				}
+				switch m.jobs[d].state {
+				case batchFailed, batchSkipped:
+					job.state = batchSkipped
+					job.err = fmt.Errorf("needs %s, which did not complete", m.jobs[d].script.Name)
+					changed = true
+				case batchSucceeded:
+				default:
+					ready = false
+				}
+			}
+			if job.state == batchWaiting && ready && running < maxParallelSetup {
+				next = append(next, i)
+				running++
+			}
+		}
+	}
+	return next
+}
+
+// start runs the script at index
+func (m SetupBatchModel) start(i int) tea.Cmd {
+	job := &m.jobs[i]
+	job.state = batchRunning
+	job.started = time.Now()
+	events := make(chan batchEvent, 64)
+	go runBatchScript(m.ctx, job.script, events)
+	return waitForBatchEvent(i, events)
+}
+
+// waitForBatchEvent delivers the next event from a running script
+func waitForBatchEvent(i int, events <-chan batchEvent) tea.Cmd {
+	return func() tea.Msg {
+		ev, ok := <-events
+		if !ok {
+			ev = batchEvent{done: true}
+		}
+		return batchEventMsg{index: i, event: ev, events: events}
+	}
+}
+
+// runBatchScript runs a setup script as root, sending its output line by
+// line and then its exit
+func runBatchScript(ctx context.Context, script models.SetupScript, events chan<- batchEvent) {
+	defer close(events)
+
+	if hostOS := system.HostOS(); hostOS != "linux" {
+		events <- batchEvent{done: true, err: fmt.Errorf("setup scripts require Linux (current OS: %s)", hostOS)}
+		return
+	}
+	if system.DetectPrivilegeMethod() == system.PrivilegeUnavailable {
+		events <- batchEvent{done: true, err: system.ErrPrivilegeUnavailable}
+		return
+	}
+	content, err := setupScriptContent("assets/scripts/" + script.ScriptPath)
+	if err != nil {
+		events <- batchEvent{done: true, err: fmt.Errorf("failed to read script: %w", err)}
+		return
+	}
+
+	cmd := system.PrivilegedCommandContext(ctx, "bash", "-s")
+	cmd.Stdin = bytes.NewReader(append([]byte(setupBatchPreamble), content...))
+	// Services started by a script may keep the output open
+	cmd.WaitDelay = 5 * time.Second
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		events <- batchEvent{done: true, err: err}
+		return
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+		pw.Close()
+	}()
+
+	scanner := bufio.NewScanner(pr)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		events <- batchEvent{line: scanner.Text()}
+	}
+	_, _ = io.Copy(io.Discard, pr)
+	events <- batchEvent{done: true, err: <-exited}
+}
+
+// running reports whether any script has not finished
+func (m SetupBatchModel) running() bool {
+	for _, job := range m.jobs {
+		if job.state == batchWaiting || job.state == batchRunning {
+			return true
+		}
+	}
+	return false
+}
+
+// counts returns how many scripts succeeded, failed, and were skipped
+func (m SetupBatchModel) counts() (succeeded, failed, skipped int) {
+	for _, job := range m.jobs {
+		switch job.state {
+		case batchSucceeded:
+			succeeded++
+		case batchFailed:
+			failed++
+		case batchSkipped:
+			skipped++
+		}
+	}
+	return succeeded, failed, skipped
+}
+
+// finish records the batch in the configuration history and sends the
+// completion notification
+func (m SetupBatchModel) finish() (SetupBatchModel, tea.Cmd) {
+	m.ended = time.Now()
+	m.cancel()
+
+	names := make([]string, len(m.jobs))
+	var failedOutput []string
+	for i, job := range m.jobs {
+		names[i] = job.script.Name
+		if job.state == batchFailed {
+			failedOutput = append(failedOutput, "== "+job.script.Name)
+			failedOutput = append(failedOutput, job.output...)
+		}
+	}
+	_, failed, skipped := m.counts()
+	success := failed == 0 && skipped == 0
+	description := "Installing " + strings.Join(names, ", ")
+	return m, tea.Batch(
+		snapshotConfig(description, success),
+		notifyCompletion(description, success, m.ended.Sub(m.started), failedOutput),
+	)
+}
+
+// Update handles messages for the batch screen
+func (m SetupBatchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case batchTickMsg:
+		if m.running() {
+			return m, batchTick()
+		}
+		return m, nil
+
+	case batchEventMsg:
+		job := &m.jobs[msg.index]
+		if !msg.event.done {
+			job.output = append(job.output, msg.event.line)
+			if len(job.output) > batchOutputLines {
+				job.output = job.output[len(job.output)-batchOutputLines:]
+			}
+			return m, waitForBatchEvent(msg.index, msg.events)
+		}
+
+		job.ended = time.Now()
+		job.err = msg.event.err
+		var cmds []tea.Cmd
+		if job.err == nil {
+			job.state = batchSucceeded
+			cmds = append(cmds, recordProvisioned("assets/scripts/"+job.script.ScriptPath))
+		} else {
+			job.state = batchFailed
+		}
+		cmds = append(cmds, m.schedule())
+		if !m.running() {
+			var cmd tea.Cmd
+			m, cmd = m.finish()
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+
+	case tea.KeyMsg:
+		m.notice = ""
+		switch msg.String() {
+		case "ctrl+c":
+			m.cancel()
+			return m, tea.Quit
+		case "esc", "backspace":
+			if m.expanded {
+				m.expanded = false
+				return m, nil
+			}
+			if m.running() {
+				m.notice = "Scripts are still running; press x to cancel them"
+				return m, nil
+			}
+			return m, func() tea.Msg { return NavigateMsg{Screen: SetupMenuScreen} }
+		case "x":
+			if m.running() {
+				m.cancel()
+				for i := range m.jobs {
+					if m.jobs[i].state == batchWaiting {
+						m.jobs[i].state = batchSkipped
+						m.jobs[i].err = fmt.Errorf("cancelled")
+					}
+				}
+				m.notice = "Cancelling..."
+			}
+		case "up", "k", "left", "h", "shift+tab":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j", "right", "l", "tab":
+			if m.cursor < len(m.jobs)-1 {
+				m.cursor++
+			}
+		case "enter":
+			m.expanded = !m.expanded
+		}
+	}
+	return m, nil
+}
+
+// stateLabel describes a script's progress
+func (m SetupBatchModel) stateLabel(job batchJob) string {
+	switch job.state {
+	case batchRunning:
+		spinner := m.theme.Symbols.Spinner
+		frame := spinner[int(time.Since(job.started).Milliseconds()/100)%len(spinner)]
+		return m.theme.InfoStyle.Render(fmt.Sprintf("%s %s", frame, time.Since(job.started).Round(time.Second)))
+	case batchSucceeded:
+		return m.theme.SuccessStyle.Render(fmt.Sprintf("%s %s", m.theme.Symbols.CheckMark, job.ended.Sub(job.started).Round(time.Second)))
+	case batchFailed:
+		return m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark + " Failed")
+	case batchSkipped:
+		return m.theme.WarningStyle.Render("Skipped")
+	}
+	return m.theme.DescriptionStyle.Render("Waiting")
+}
+
+// renderPane draws one script's status and the tail of its output
+func (m SetupBatchModel) renderPane(i, width, lines int) string {
+	job := m.jobs[i]
+	title := m.theme.Label.Render(truncateRunes(job.script.Name, max(width-16, 8))) + "  " + m.stateLabel(job)
+
+	body := job.output
+	if job.err != nil && job.state != batchRunning {
+		body = append(append([]string(nil), body...), job.err.Error())
+	}
+	if len(body) > lines {
+		body = body[len(body)-lines:]
+	}
+	rendered := make([]string, 0, lines+1)
+	rendered = append(rendered, title)
+	for _, line := range body {
+		rendered = append(rendered, m.theme.DescriptionStyle.Render(truncateRunes(strings.ReplaceAll(line, "\t", "    "), width-4)))
+	}
+	for len(rendered) < lines+1 {
+		rendered = append(rendered, "")
+	}
+
+	style := m.theme.BorderStyle.Padding(0, 1).Width(width - 2)
+	if i == m.cursor {
+		style = style.BorderForeground(m.theme.Primary)
+	}
+	return style.Render(strings.Join(rendered, "\n"))
+}
+
+// View renders the batch screen
+func (m SetupBatchModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	succeeded, failed, skipped := m.counts()
+	header := m.theme.Title.Render("Batch Install")
+	var progress string
+	if m.running() {
+		progress = m.theme.InfoStyle.Render(fmt.Sprintf("%d of %d finished", succeeded+failed+skipped, len(m.jobs)))
+	} else {
+		summary := fmt.Sprintf("%d succeeded, %d failed, %d skipped in %s", succeeded, failed, skipped, m.ended.Sub(m.started).Round(time.Second))
+		if failed+skipped == 0 {
+			progress = m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " " + summary)
+		} else {
+			progress = m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark + " " + summary)
+		}
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := "Tab/Arrows: Select" + bullet + "Enter: Expand"
+	if m.running() {
+		help += bullet + "x: Cancel"
+	} else {
+		help += bullet + "Esc: Back"
+	}
+
+	sections := []string{header, progress, ""}
+	available := m.height - 6
+	if m.notice != "" {
+		sections = append(sections, m.theme.WarningStyle.Render(m.notice), "")
+		available -= 2
+	}
+
+	if m.expanded && len(m.jobs) > 0 {
+		sections = append(sections, m.renderPane(m.cursor, m.width-2, max(available-3, 3)))
+	} else {
+		cols := 1
+		switch {
+		case m.width >= 150 && len(m.jobs) >= 3:
+			cols = 3
+		case m.width >= 90 && len(m.jobs) >= 2:
+			cols = 2
+		}
+		rows := (len(m.jobs) + cols - 1) / cols
+		paneWidth := (m.width - 2) / cols
+		// Each pane has a title and two border lines
+		lines := max(available/max(rows, 1)-3, 2)
+
+		var grid []string
+		for r := 0; r < rows; r++ {
+			var row []string
+			for c := 0; c < cols; c++ {
+				if i := r*cols + c; i < len(m.jobs) {
+					row = append(row, m.renderPane(i, paneWidth, lines))
+				}
+			}
+			grid = append(grid, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+		}
+		sections = append(sections, grid...)
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Top,
+		lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
@@ -0,0 +1,56 @@
+package screens
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/iperamuna/ravact/internal/models"
+)
+
+func batchOf(ids ...string) SetupBatchModel {
+	scripts := make([]models.SetupScript, len(ids))
+	for i, id := range ids {
+		scripts[i] = models.SetupScript{ID: id, Name: id, ScriptPath: id + ".sh"}
+	}
+	return NewSetupBatchModel(scripts)
+}
+
+func TestSetupBatchNextJobs(t *testing.T) {
+	m := batchOf("postgresql", "pgbouncer", "redis", "supervisor", "git")
+	if got := m.nextJobs(); !slices.Equal(got, []int{0, 2, 3}) {
+		t.Fatalf("expected independent scripts up to the limit to start, got %v", got)
+	}
+	for _, i := range []int{0, 2, 3} {
+		m.jobs[i].state = batchRunning
+	}
+	if got := m.nextJobs(); len(got) != 0 {
+		t.Errorf("expected nothing more to start while three run, got %v", got)
+	}
+
+	// pgbouncer waits for postgresql even when a slot is free
+	m.jobs[2].state = batchSucceeded
+	if got := m.nextJobs(); !slices.Equal(got, []int{4}) {
+		t.Errorf("expected only git to start, got %v", got)
+	}
+	m.jobs[0].state = batchSucceeded
+	if got := m.nextJobs(); !slices.Equal(got, []int{1, 4}) {
+		t.Errorf("expected pgbouncer to start once postgresql succeeded, got %v", got)
+	}
+}
+
+func TestSetupBatchSkipsFailedDependencies(t *testing.T) {
+	m := batchOf("postgresql", "pgbouncer")
+	m.jobs[0].state = batchFailed
+	if got := m.nextJobs(); len(got) != 0 {
+		t.Errorf("expected nothing to start, got %v", got)
+	}
+	if m.jobs[1].state != batchSkipped || m.jobs[1].err == nil {
+		t.Errorf("expected pgbouncer to be skipped with a reason, got %+v", m.jobs[1])
+	}
+	if m.running() {
+		t.Error("expected the batch to be finished")
+	}
+	if succeeded, failed, skipped := m.counts(); succeeded != 0 || failed != 1 || skipped != 1 {
+		t.Errorf("unexpected counts %d %d %d", succeeded, failed, skipped)
+	}
+}
@@ -21,11 +21,46 @@ type SetupMenuModel struct {
 	height          int
 	cursor          int
 	scripts         []models.SetupScript
+	scriptsDir      string
 	executor        *setup.Executor
 	detector        *system.Detector
 	serviceStatuses map[string]models.ServiceStatus
+	provision       map[string]system.ProvisionState
 	loading         bool
 	err             error
+	notice          string
+	selected        map[string]bool // Scripts picked with Space to install as a batch
+}
+
+// setupStatusMsg carries refreshed service statuses and provisioning state
+type setupStatusMsg struct {
+	statuses  map[string]models.ServiceStatus
+	provision map[string]system.ProvisionState
+}
+
+// setupScriptHash hashes an embedded setup script, or returns "" when it
+// cannot be read
+func setupScriptHash(scriptsDir, scriptPath string) string {
+	content, err := readSetupScript(scriptsDir + "/" + scriptPath)
+	if err != nil {
+		return ""
+	}
+	return system.ScriptHash(content)
+}
+
+// provisionStates compares each script's record with its service status,
+// for installs with the script's default options as the menu runs them
+func provisionStates(scriptsDir string, scripts []models.SetupScript, statuses map[string]models.ServiceStatus) map[string]system.ProvisionState {
+	records, _ := system.LoadProvisionRecords()
+	states := make(map[string]system.ProvisionState)
+	for _, script := range scripts {
+		var rec *system.ProvisionRecord
+		if r, ok := records[script.ID]; ok {
+			rec = &r
+		}
+		states[script.ID] = system.CheckProvisioned(rec, setupScriptHash(scriptsDir, script.ScriptPath), "", statuses[script.ID])
+	}
+	return states
 }
 
 // NewSetupMenuModel creates a new setup menu model
@@ -89,10 +124,26 @@ func NewSetupMenuModel(scriptsDir string) SetupMenuModel {
 			scripts[i].Name = "Redis Cache"
 			scripts[i].Description = "In-memory data structure store and cache"
 			scripts[i].ServiceID = "redis-server"
+		case "pgbouncer":
+			scripts[i].Name = "pgBouncer"
+			scripts[i].Description = "Connection pooler for PostgreSQL"
+			scripts[i].ServiceID = "pgbouncer"
 		case "dragonfly":
 			scripts[i].Name = "Dragonfly"
 			scripts[i].Description = "Modern Redis/Memcached replacement (faster, less memory)"
 			scripts[i].ServiceID = "dragonfly"
+		case "docker":
+			scripts[i].Name = "Docker"
+			scripts[i].Description = "Docker Engine and Compose for sidecar containers"
+			scripts[i].ServiceID = "docker"
+		case "meilisearch":
+			scripts[i].Name = "Meilisearch"
+			scripts[i].Description = "Search engine for Laravel Scout"
+			scripts[i].ServiceID = "meilisearch"
+		case "netdata":
+			scripts[i].Name = "Netdata"
+			scripts[i].Description = "Real-time server graphs without a Prometheus stack"
+			scripts[i].ServiceID = "netdata"
 		case "php":
 			scripts[i].Name = "PHP"
 			scripts[i].Description = "PHP versions and extensions management"
@@ -121,6 +172,14 @@ func NewSetupMenuModel(scriptsDir string) SetupMenuModel {
 			scripts[i].Name = "Firewall (UFW/firewalld)"
 			scripts[i].Description = "Configure firewall with common rules"
 			scripts[i].ServiceID = "ufw"
+		case "swap":
+			scripts[i].Name = "Swap File"
+			scripts[i].Description = "Create or resize a swap file and tune swappiness"
+			scripts[i].ServiceID = "swap"
+		case "exporters":
+			scripts[i].Name = "Prometheus Exporters"
+			scripts[i].Description = "node, nginx, MySQL, and PHP-FPM exporters scraped from one source"
+			scripts[i].ServiceID = "node_exporter"
 		}
 	}
 
@@ -137,16 +196,28 @@ func NewSetupMenuModel(scriptsDir string) SetupMenuModel {
 		theme:           theme.DefaultTheme(),
 		cursor:          0,
 		scripts:         scripts,
+		scriptsDir:      scriptsDir,
 		executor:        executor,
 		detector:        detector,
 		serviceStatuses: serviceStatuses,
+		provision:       provisionStates(scriptsDir, scripts, serviceStatuses),
 		err:             err,
+		selected:        make(map[string]bool),
 	}
 }
 
-// Init initializes the setup menu
+// Init refreshes the statuses, since returning from an install keeps the model
 func (m SetupMenuModel) Init() tea.Cmd {
-	return nil
+	scripts, scriptsDir, detector := m.scripts, m.scriptsDir, m.detector
+	return func() tea.Msg {
+		statuses := make(map[string]models.ServiceStatus)
+		for _, script := range scripts {
+			if script.ServiceID != "" {
+				statuses[script.ID], _ = detector.GetServiceStatus(script.ServiceID)
+			}
+		}
+		return setupStatusMsg{statuses: statuses, provision: provisionStates(scriptsDir, scripts, statuses)}
+	}
 }
 
 // Update handles messages for the setup menu
@@ -157,7 +228,13 @@ func (m SetupMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case setupStatusMsg:
+		m.serviceStatuses = msg.statuses
+		m.provision = msg.provision
+		return m, nil
+
 	case tea.KeyMsg:
+		m.notice = ""
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -177,7 +254,17 @@ func (m SetupMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.cursor++
 			}
 
-		case "enter", " ":
+		case " ":
+			if len(m.scripts) > 0 {
+				id := m.scripts[m.cursor].ID
+				if m.selected[id] {
+					delete(m.selected, id)
+				} else {
+					m.selected[id] = true
+				}
+			}
+
+		case "enter":
 			if len(m.scripts) > 0 {
 				// Check if running on macOS and warn user
 				if runtime.GOOS == "darwin" {
@@ -193,8 +280,9 @@ func (m SetupMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return NavigateMsg{
 						Screen: SetupActionScreen,
 						Data: map[string]interface{}{
-							"script": selectedScript,
-							"status": status,
+							"script":    selectedScript,
+							"status":    status,
+							"provision": m.provision[selectedScript.ID],
 						},
 					}
 				}
@@ -208,8 +296,16 @@ func (m SetupMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// Don't execute - user will see warning in menu
 					return m, nil
 				}
+				if len(m.selected) > 0 {
+					return m.installSelected()
+				}
 				
 				selectedScript := m.scripts[m.cursor]
+				// Skip scripts that already ran and have nothing to change
+				if m.provision[selectedScript.ID].Satisfied {
+					m.notice = fmt.Sprintf("%s is already installed and up to date; use Reinstall / Update from its actions to run it again", selectedScript.Name)
+					return m, nil
+				}
 				return m, func() tea.Msg {
 					return ExecutionStartMsg{
 						Command:     fmt.Sprintf("assets/scripts/%s", selectedScript.ScriptPath),
@@ -226,6 +322,9 @@ func (m SetupMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					status, _ := m.detector.GetServiceStatus(selectedScript.ServiceID)
 					m.serviceStatuses[selectedScript.ID] = status
 				}
+				for id, state := range provisionStates(m.scriptsDir, []models.SetupScript{selectedScript}, m.serviceStatuses) {
+					m.provision[id] = state
+				}
 			}
 		}
 	}
@@ -233,6 +332,42 @@ func (m SetupMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// installSelected installs the scripts picked with Space as a batch,
+// leaving out any that are already installed and up to date
+func (m SetupMenuModel) installSelected() (SetupMenuModel, tea.Cmd) {
+	var scripts []models.SetupScript
+	var current []string
+	for _, script := range m.scripts {
+		if !m.selected[script.ID] {
+			continue
+		}
+		if m.provision[script.ID].Satisfied {
+			current = append(current, script.Name)
+			continue
+		}
+		scripts = append(scripts, script)
+	}
+	if len(scripts) == 0 {
+		m.notice = "Everything selected is already installed and up to date"
+		return m, nil
+	}
+	if len(current) > 0 {
+		m.notice = "Leaving out " + strings.Join(current, ", ") + ", already up to date"
+	}
+
+	m.selected = make(map[string]bool)
+	names := make([]string, len(scripts))
+	for i, script := range scripts {
+		names[i] = script.Name
+	}
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{
+			Description: "Installing " + strings.Join(names, ", "),
+			Scripts:     scripts,
+		}
+	}
+}
+
 // View renders the setup menu
 func (m SetupMenuModel) View() string {
 	if m.width == 0 {
@@ -273,6 +408,10 @@ func (m SetupMenuModel) View() string {
 			if i == m.cursor {
 				cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
 			}
+			box := m.theme.Symbols.Box + " "
+			if m.selected[script.ID] {
+				box = m.theme.Symbols.BoxChecked + " "
+			}
 
 			// Get installation status
 			status := m.serviceStatuses[script.ID]
@@ -290,15 +429,26 @@ func (m SetupMenuModel) View() string {
 				statusBadge = m.theme.ErrorStyle.Render("[✗ Failed]")
 			}
 
-			title := script.Name
+			title := box + script.Name
 			if statusBadge != "" {
 				title = fmt.Sprintf("%s %s", title, statusBadge)
 			}
 
+			// Provisioning state: managed by ravact, or drifted from its record
+			state := m.provision[script.ID]
+			if len(state.Drift) > 0 {
+				title = fmt.Sprintf("%s %s", title, m.theme.WarningStyle.Render("[Drift]"))
+			} else if state.Satisfied {
+				title = fmt.Sprintf("%s %s", title, m.theme.SuccessStyle.Render("[Managed]"))
+			}
+
 			desc := ""
 			if script.Description != "" {
 				desc = m.theme.DescriptionStyle.Render(script.Description)
 			}
+			if len(state.Drift) > 0 {
+				desc = lipgloss.JoinVertical(lipgloss.Left, desc, "  "+m.theme.WarningStyle.Render(m.theme.Symbols.Warning+" "+strings.Join(state.Drift, "; ")))
+			}
 
 			var renderedItem string
 			if i == m.cursor {
@@ -318,10 +468,14 @@ func (m SetupMenuModel) View() string {
 	menu := lipgloss.JoinVertical(lipgloss.Left, menuItems...)
 
 	// Help
-	help := m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Actions " + m.theme.Symbols.Bullet + " i: Install " + m.theme.Symbols.Bullet + " r: Refresh " + m.theme.Symbols.Bullet + " Esc: Back " + m.theme.Symbols.Bullet + " q: Quit")
+	install := "i: Install"
+	if n := len(m.selected); n > 0 {
+		install = fmt.Sprintf("i: Install %d selected", n)
+	}
+	help := m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Actions " + m.theme.Symbols.Bullet + " Space: Select " + m.theme.Symbols.Bullet + " " + install + " " + m.theme.Symbols.Bullet + " r: Refresh " + m.theme.Symbols.Bullet + " Esc: Back " + m.theme.Symbols.Bullet + " q: Quit")
 
 	// Warning about root
-	warning := m.theme.WarningStyle.Render("Note: Installation requires root privileges")
+	warning := m.theme.WarningStyle.Render("Note: Installation requires root; ravact asks for sudo when needed")
 
 	// Combine all sections
 	content := lipgloss.JoinVertical(
@@ -338,6 +492,10 @@ func (m SetupMenuModel) View() string {
 		content = lipgloss.JoinVertical(lipgloss.Left, content, errorMsg, "")
 	}
 
+	if m.notice != "" {
+		content = lipgloss.JoinVertical(lipgloss.Left, content, m.theme.InfoStyle.Render(m.notice), "")
+	}
+
 	content = lipgloss.JoinVertical(
 		lipgloss.Left,
 		content,
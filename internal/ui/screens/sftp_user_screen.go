@@ -0,0 +1,289 @@
+package screens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// sftpUserDoneMsg carries the outcome of creating an SFTP-only user
+type sftpUserDoneMsg struct {
+	err error
+}
+
+// SFTPUserModel creates users limited to SFTP inside a chroot holding one
+// site directory, for handing access to external developers
+type SFTPUserModel struct {
+	theme       *theme.Theme
+	width       int
+	height      int
+	userManager *system.UserManager
+
+	mode string // "form", "confirm", "running", "done"
+	form *huh.Form
+	opts system.SFTPUserOptions
+
+	confirm Confirmation
+	err     error
+}
+
+// NewSFTPUserModel creates the SFTP-only user screen
+func NewSFTPUserModel() SFTPUserModel {
+	m := SFTPUserModel{
+		theme:       theme.DefaultTheme(),
+		userManager: system.NewUserManager(),
+	}
+	m.startForm()
+	return m
+}
+
+// siteDirSuggestions returns the nginx site roots, and their parents for
+// roots like app/public
+func siteDirSuggestions() []string {
+	dirs := map[string]bool{}
+	if sites, err := system.NewNginxManager().GetAllSites(); err == nil {
+		for _, s := range sites {
+			if s.RootDir != "" {
+				dirs[s.RootDir] = true
+				if strings.HasSuffix(s.RootDir, "/public") {
+					dirs[strings.TrimSuffix(s.RootDir, "/public")] = true
+				}
+			}
+		}
+	}
+	suggestions := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		suggestions = append(suggestions, dir)
+	}
+	sort.Strings(suggestions)
+	return suggestions
+}
+
+// startForm asks for the user, the site, and how they log in
+func (m *SFTPUserModel) startForm() {
+	m.mode = "form"
+	username, siteDir, publicKey, password := m.opts.Username, m.opts.SiteDir, m.opts.PublicKey, ""
+	access := m.opts.Access
+	if access == "" {
+		access = system.SFTPAccessWrite
+	}
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("username").
+				Title("Username").
+				Validate(func(s string) error {
+					return system.SFTPUserOptions{Username: strings.TrimSpace(s), SiteDir: "/srv", Access: system.SFTPAccessWrite, Password: "-"}.Validate()
+				}).
+				Value(&username),
+			huh.NewInput().
+				Key("siteDir").
+				Title("Site Directory").
+				Description("Bind-mounted into the user's chroot under "+system.SFTPChrootRoot).
+				Placeholder("/var/www/example.com").
+				Suggestions(siteDirSuggestions()).
+				Validate(func(s string) error {
+					if !strings.HasPrefix(strings.TrimSpace(s), "/") || strings.TrimSpace(s) == "/" {
+						return fmt.Errorf("enter an absolute path below /")
+					}
+					return nil
+				}).
+				Value(&siteDir),
+			huh.NewSelect[string]().
+				Key("access").
+				Title("Access").
+				Options(
+					huh.NewOption("Read and write (joins the site's own group, not a system group)", system.SFTPAccessWrite),
+					huh.NewOption("Read only", system.SFTPAccessReadOnly),
+				).
+				Value(&access),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Key("publicKey").
+				Title("Public Key").
+				Description("Optional; paste the developer's public key").
+				Placeholder("ssh-ed25519 AAAA... dev@example.com").
+				Validate(func(s string) error {
+					if s = strings.TrimSpace(s); s != "" {
+						if _, ok := system.ParseAuthorizedKey(s); !ok {
+							return fmt.Errorf("not a valid public key")
+						}
+					}
+					return nil
+				}).
+				Value(&publicKey),
+			huh.NewInput().
+				Key("password").
+				Title("Password").
+				Description("Optional when a public key is set").
+				EchoMode(huh.EchoModePassword).
+				Value(&password),
+		),
+	).WithTheme(m.theme.HuhTheme).WithShowHelp(true).WithShowErrors(true)
+}
+
+// Init initializes the SFTP user screen
+func (m SFTPUserModel) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+// Update handles messages for the SFTP user screen
+func (m SFTPUserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case sftpUserDoneMsg:
+		m.mode = "done"
+		m.err = msg.err
+		return m, nil
+	}
+
+	keyMsg, isKey := msg.(tea.KeyMsg)
+	if isKey && keyMsg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch m.mode {
+	case "form":
+		if isKey && keyMsg.String() == "esc" {
+			return m, m.back()
+		}
+		form, cmd := m.form.Update(msg)
+		if f, ok := form.(*huh.Form); ok {
+			m.form = f
+		}
+		if m.form.State == huh.StateCompleted {
+			m.opts = system.SFTPUserOptions{
+				Username:  strings.TrimSpace(m.form.GetString("username")),
+				SiteDir:   strings.TrimSpace(m.form.GetString("siteDir")),
+				Access:    m.form.GetString("access"),
+				PublicKey: strings.TrimSpace(m.form.GetString("publicKey")),
+				Password:  m.form.GetString("password"),
+			}
+			if err := m.opts.Validate(); err != nil {
+				m.err = err
+				m.startForm()
+				return m, m.form.Init()
+			}
+			m.err = nil
+			m.confirm = NewConfirmation("sftp", "Create SFTP User", m.confirmMessage(), ConfirmWarning)
+			m.mode = "confirm"
+			return m, nil
+		}
+		return m, cmd
+
+	case "confirm":
+		if !isKey {
+			return m, nil
+		}
+		var result ConfirmResult
+		m.confirm, result = m.confirm.Update(keyMsg)
+		switch result {
+		case ConfirmAccepted:
+			m.mode = "running"
+			um, opts := m.userManager, m.opts
+			return m, func() tea.Msg {
+				return sftpUserDoneMsg{err: um.CreateSFTPUser(opts)}
+			}
+		case ConfirmCancelled:
+			m.startForm()
+			return m, m.form.Init()
+		}
+
+	case "done":
+		if isKey {
+			switch keyMsg.String() {
+			case "q":
+				return m, tea.Quit
+			case "esc", "backspace", "enter":
+				return m, m.back()
+			}
+		}
+	}
+	return m, nil
+}
+
+// back returns to user management
+func (m SFTPUserModel) back() tea.Cmd {
+	return func() tea.Msg {
+		return NavigateMsg{Screen: UserManagementScreen}
+	}
+}
+
+// confirmMessage describes the changes about to be made
+func (m SFTPUserModel) confirmMessage() string {
+	mount := "bind mount (read and write)"
+	if m.opts.Access == system.SFTPAccessReadOnly {
+		mount = "read-only bind mount"
+	}
+	lines := []string{
+		fmt.Sprintf("Create %s, limited to SFTP? This will:", m.opts.Username),
+		"  - add the user to the " + system.SFTPGroup + " group with no shell",
+		"  - create the root-owned chroot " + m.opts.Chroot(),
+		"  - add a " + mount + " of " + m.opts.SiteDir + " to " + system.FstabPath,
+		"  - add a Match Group " + system.SFTPGroup + " block to sshd_config if missing",
+	}
+	if m.opts.Access == system.SFTPAccessWrite {
+		lines = append(lines, "  - give the site's group write access to "+m.opts.SiteDir)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// View renders the SFTP user screen
+func (m SFTPUserModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections := []string{
+		m.theme.Title.Render("Create SFTP-Only User"),
+		m.theme.DescriptionStyle.Render("Chrooted SFTP access to a single site directory, without a shell"),
+		"",
+	}
+	var help string
+
+	switch m.mode {
+	case "form":
+		sections = append(sections, m.form.View())
+		if m.err != nil {
+			sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+		}
+		help = "Enter: Continue" + bullet + "Esc: Back"
+
+	case "running":
+		sections = append(sections, m.theme.InfoStyle.Render("Creating "+m.opts.Username+"..."))
+
+	case "done":
+		if m.err != nil {
+			sections = append(sections, m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+		} else {
+			sections = append(sections,
+				m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark+" Created "+m.opts.Username),
+				"",
+				m.theme.MenuItem.Render("  sftp "+m.opts.Username+"@<server>"),
+				m.theme.DescriptionStyle.Render("  Files are under /"+strings.TrimPrefix(m.opts.MountPoint(), m.opts.Chroot()+"/")),
+			)
+		}
+		help = "Enter/Esc: Back to users"
+	}
+
+	if help != "" {
+		sections = append(sections, "", m.theme.Help.Render(help))
+	}
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(content))
+}
@@ -0,0 +1,493 @@
+package screens
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// SiteAccessModel protects a site or paths of it with basic auth and IP
+// allowlists
+type SiteAccessModel struct {
+	theme        *theme.Theme
+	width        int
+	height       int
+	nginxManager *system.NginxManager
+	site         system.NginxSite
+	webUser      string
+
+	current  []system.SiteAccessRule
+	pending  []system.SiteAccessRule
+	htpasswd *system.Htpasswd
+	cursor   int
+
+	// "" for the rule list, "rule_form", "users", "user_form", or "review"
+	mode       string
+	form       *huh.Form
+	editing    int // Index of the rule being edited, -1 when adding
+	userCursor int
+	review     ConfigReview
+
+	err     error
+	success string
+}
+
+// NewSiteAccessModel creates the access protection screen for a site
+func NewSiteAccessModel(site system.NginxSite) SiteAccessModel {
+	m := SiteAccessModel{
+		theme:        theme.DefaultTheme(),
+		nginxManager: system.NewNginxManager(),
+		site:         site,
+		webUser:      detectWebUser(),
+	}
+	m.load()
+	return m
+}
+
+// load reads the site's rules and users
+func (m *SiteAccessModel) load() {
+	path := m.nginxManager.HtpasswdPath(m.site.Name)
+	htpasswd, err := system.LoadHtpasswd(path)
+	if err != nil {
+		m.err = err
+		htpasswd = &system.Htpasswd{Path: path}
+	}
+	m.htpasswd = htpasswd
+
+	rules, err := m.nginxManager.SiteAccess(m.site.Name)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.current, m.pending = rules, slices.Clone(rules)
+}
+
+// Init initializes the access screen
+func (m SiteAccessModel) Init() tea.Cmd {
+	return nil
+}
+
+// sameAccessRule reports whether two rules protect alike
+func sameAccessRule(a, b system.SiteAccessRule) bool {
+	return a.Path == b.Path && a.BasicAuth == b.BasicAuth && a.SatisfyAny == b.SatisfyAny && slices.Equal(a.AllowIPs, b.AllowIPs)
+}
+
+// changed reports whether the pending rules differ from the site's
+func (m SiteAccessModel) changed() bool {
+	return !slices.EqualFunc(m.current, m.pending, sameAccessRule)
+}
+
+// Update handles messages for the access screen
+func (m SiteAccessModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	switch m.mode {
+	case "rule_form", "user_form":
+		return m.updateForm(msg)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch m.mode {
+	case "review":
+		if keyMsg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		var result ConfirmResult
+		m.review, result = m.review.Update(keyMsg, m.height)
+		switch result {
+		case ConfirmAccepted:
+			m.mode = ""
+			return m.apply()
+		case ConfirmCancelled:
+			m.mode = ""
+		}
+		return m, nil
+	case "users":
+		return m.updateUsers(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		site := m.site
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: ConfigEditorScreen,
+				Data: map[string]interface{}{
+					"action": "edit_nginx_site",
+					"site":   site,
+				},
+			}
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.pending)-1 {
+			m.cursor++
+		}
+	case "a":
+		return m.openRuleForm(-1)
+	case "e", "enter":
+		if len(m.pending) > 0 {
+			return m.openRuleForm(m.cursor)
+		}
+	case "d", "delete":
+		if len(m.pending) > 0 {
+			m.pending = slices.Delete(m.pending, m.cursor, m.cursor+1)
+			if m.cursor >= len(m.pending) && m.cursor > 0 {
+				m.cursor--
+			}
+			m.err, m.success = nil, ""
+		}
+	case "u":
+		m.mode = "users"
+		m.err, m.success = nil, ""
+	case "s":
+		m.err, m.success = nil, ""
+		if !m.changed() {
+			m.success = m.theme.Symbols.Info + " No changes"
+			break
+		}
+		needsUsers := slices.ContainsFunc(m.pending, func(r system.SiteAccessRule) bool { return r.BasicAuth })
+		if needsUsers && len(m.htpasswd.Users()) == 0 {
+			m.err = fmt.Errorf("add a basic auth user first (press u)")
+			break
+		}
+		change, err := m.nginxManager.PlanSiteAccess(m.site.Name, m.pending)
+		if err != nil {
+			m.err = err
+			break
+		}
+		m.review = NewConfigReview("access", m.nginxManager, change)
+		m.mode = "review"
+	}
+	return m, nil
+}
+
+// openRuleForm shows the form adding a rule, or editing rule index
+func (m SiteAccessModel) openRuleForm(index int) (tea.Model, tea.Cmd) {
+	rule := system.SiteAccessRule{Path: "/admin", BasicAuth: true}
+	if index >= 0 {
+		rule = m.pending[index]
+	}
+	path := rule.Path
+	basicAuth := rule.BasicAuth
+	allowIPs := strings.Join(rule.AllowIPs, ", ")
+	satisfy := "all"
+	if rule.SatisfyAny {
+		satisfy = "any"
+	}
+
+	m.editing = index
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("path").
+				Title("Path").
+				Description("/ protects the whole site; a prefix such as /admin protects that path").
+				Validate(func(s string) error {
+					s = strings.TrimSpace(s)
+					for i, r := range m.pending {
+						if r.Path == s && i != index {
+							return fmt.Errorf("%s already has a rule", s)
+						}
+					}
+					return system.SiteAccessRule{Path: s, BasicAuth: true}.Validate()
+				}).
+				Value(&path),
+
+			huh.NewConfirm().
+				Key("basicAuth").
+				Title("Require Password").
+				Description("HTTP basic auth with the site's users; use HTTPS so passwords are not sent in the clear").
+				Value(&basicAuth),
+
+			huh.NewInput().
+				Key("allowIPs").
+				Title("Allowed IPs").
+				Description("Addresses or CIDR ranges, comma separated; empty allows any address").
+				Placeholder("203.0.113.4, 198.51.100.0/24").
+				Validate(func(s string) error {
+					return system.SiteAccessRule{Path: "/", BasicAuth: true, AllowIPs: system.ParseAllowIPs(s)}.Validate()
+				}).
+				Value(&allowIPs),
+
+			huh.NewSelect[string]().
+				Key("satisfy").
+				Title("With Both").
+				Description("Only applies when both a password and allowed IPs are set").
+				Options(
+					huh.NewOption("Require an allowed IP and a password", "all"),
+					huh.NewOption("Allowed IPs skip the password", "any"),
+				).
+				Value(&satisfy),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+	m.mode = "rule_form"
+	return m, m.form.Init()
+}
+
+// openUserForm shows the form adding a user or changing a password
+func (m SiteAccessModel) openUserForm() (tea.Model, tea.Cmd) {
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("user").
+				Title("User").
+				Description("An existing user gets a new password").
+				Validate(func(s string) error {
+					if s = strings.TrimSpace(s); s == "" || strings.ContainsAny(s, ": \t") {
+						return fmt.Errorf("user name must not be empty or contain spaces or colons")
+					}
+					return nil
+				}),
+
+			huh.NewInput().
+				Key("password").
+				Title("Password").
+				EchoMode(huh.EchoModePassword).
+				Validate(func(s string) error {
+					if len(s) < 8 {
+						return fmt.Errorf("password must be at least 8 characters")
+					}
+					return nil
+				}),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+	m.mode = "user_form"
+	return m, m.form.Init()
+}
+
+// updateForm passes messages to the rule or user form
+func (m SiteAccessModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	back := ""
+	if m.mode == "user_form" {
+		back = "users"
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = back
+			m.form = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	switch m.form.State {
+	case huh.StateCompleted:
+		m.err, m.success = nil, ""
+		if m.mode == "rule_form" {
+			m.saveRule()
+		} else {
+			m.saveUser()
+		}
+		m.mode = back
+		m.form = nil
+		return m, nil
+	case huh.StateAborted:
+		m.mode = back
+		m.form = nil
+		return m, nil
+	}
+	return m, cmd
+}
+
+// saveRule stores the rule form in the pending rules
+func (m *SiteAccessModel) saveRule() {
+	rule := system.SiteAccessRule{
+		Path:       strings.TrimSpace(m.form.GetString("path")),
+		BasicAuth:  m.form.GetBool("basicAuth"),
+		AllowIPs:   system.ParseAllowIPs(m.form.GetString("allowIPs")),
+		SatisfyAny: m.form.GetString("satisfy") == "any",
+	}
+	// "IP or password" means nothing unless both are set
+	if !rule.BasicAuth || len(rule.AllowIPs) == 0 {
+		rule.SatisfyAny = false
+	}
+	if err := rule.Validate(); err != nil {
+		m.err = err
+		return
+	}
+	if m.editing >= 0 {
+		m.pending[m.editing] = rule
+	} else {
+		m.pending = append(m.pending, rule)
+		m.cursor = len(m.pending) - 1
+	}
+	m.success = m.theme.Symbols.Info + " Press s to review and apply"
+}
+
+// saveUser hashes and writes the user form to the site's htpasswd file
+func (m *SiteAccessModel) saveUser() {
+	user := strings.TrimSpace(m.form.GetString("user"))
+	if err := m.htpasswd.Set(user, m.form.GetString("password")); err != nil {
+		m.err = err
+		return
+	}
+	if err := m.htpasswd.Save(m.webUser); err != nil {
+		m.err = err
+		return
+	}
+	m.success = fmt.Sprintf("%s Saved %s; nginx reads the file on each request", m.theme.Symbols.CheckMark, user)
+}
+
+// updateUsers handles keys on the user list
+func (m SiteAccessModel) updateUsers(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	users := m.htpasswd.Users()
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.mode = ""
+	case "up", "k":
+		if m.userCursor > 0 {
+			m.userCursor--
+		}
+	case "down", "j":
+		if m.userCursor < len(users)-1 {
+			m.userCursor++
+		}
+	case "a", "enter":
+		m.err, m.success = nil, ""
+		return m.openUserForm()
+	case "d", "delete":
+		if len(users) == 0 {
+			break
+		}
+		m.err, m.success = nil, ""
+		user := users[m.userCursor]
+		m.htpasswd.Remove(user)
+		if err := m.htpasswd.Save(m.webUser); err != nil {
+			m.err = err
+			break
+		}
+		if m.userCursor >= len(users)-1 && m.userCursor > 0 {
+			m.userCursor--
+		}
+		m.success = fmt.Sprintf("%s Removed %s", m.theme.Symbols.CheckMark, user)
+	}
+	return m, nil
+}
+
+// apply writes the reviewed config and reloads nginx
+func (m SiteAccessModel) apply() (SiteAccessModel, tea.Cmd) {
+	if err := m.nginxManager.ApplyChange(m.review.Change); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := m.nginxManager.TestConfig(); err != nil {
+		m.err = fmt.Errorf("config written but test failed: %w", err)
+		return m, nil
+	}
+	if err := m.nginxManager.ReloadNginx(); err != nil {
+		m.err = fmt.Errorf("config written but reload failed: %w", err)
+		return m, nil
+	}
+	m.current = slices.Clone(m.pending)
+	m.success = m.theme.Symbols.CheckMark + " Access rules applied and nginx reloaded"
+	return m, nil
+}
+
+// View renders the access screen
+func (m SiteAccessModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "review" {
+		return m.review.View(m.theme, m.width, m.height)
+	}
+
+	title := "Access Protection"
+	if m.mode == "users" || m.mode == "user_form" {
+		title = "Basic Auth Users"
+	}
+	sections := []string{
+		m.theme.Title.Render(title),
+		m.theme.DescriptionStyle.Render(m.site.Name),
+		"",
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := ""
+	switch m.mode {
+	case "rule_form", "user_form":
+		sections = append(sections, m.form.View())
+		help = "Tab: Next" + bullet + "Enter: Submit" + bullet + "Esc: Cancel"
+
+	case "users":
+		users := m.htpasswd.Users()
+		if len(users) == 0 {
+			sections = append(sections, m.theme.DescriptionStyle.Render("No users yet"))
+		}
+		for i, user := range users {
+			cursor := "  "
+			style := m.theme.MenuItem
+			if i == m.userCursor {
+				cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+				style = m.theme.SelectedItem
+			}
+			sections = append(sections, cursor+style.Render(user))
+		}
+		sections = append(sections, "", m.theme.DescriptionStyle.Render(m.htpasswd.Path))
+		help = "a: Add or change password" + bullet + "d: Remove" + bullet + "Esc: Back"
+
+	default:
+		if len(m.pending) == 0 {
+			sections = append(sections, m.theme.DescriptionStyle.Render("The site is open to everyone"))
+		}
+		for i, rule := range m.pending {
+			line := fmt.Sprintf("%-20s %s", rule.Path, rule.Describe())
+			if i >= len(m.current) || !sameAccessRule(rule, m.current[i]) {
+				line += " *"
+			}
+			cursor := "  "
+			style := m.theme.MenuItem
+			if i == m.cursor {
+				cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+				style = m.theme.SelectedItem
+			}
+			sections = append(sections, cursor+style.Render(line))
+		}
+		sections = append(sections, "", m.theme.DescriptionStyle.Render(fmt.Sprintf("%d basic auth user(s)", len(m.htpasswd.Users()))))
+		help = "a: Add" + bullet + "Enter: Edit" + bullet + "d: Delete" + bullet + "u: Users" + bullet + "s: Review & apply" + bullet + "Esc: Back"
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -0,0 +1,333 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/system/pkgmanager"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// siteAnalyticsMsg carries a fresh GoAccess report
+type siteAnalyticsMsg struct {
+	installed bool
+	report    system.GoAccessReport
+	err       error
+}
+
+// SiteAnalyticsModel shows a GoAccess report of a site's access log and
+// publishes the HTML report behind basic auth
+type SiteAnalyticsModel struct {
+	theme        *theme.Theme
+	width        int
+	height       int
+	nginxManager *system.NginxManager
+	site         system.NginxSite
+	logPath      string
+
+	installed bool
+	loading   bool
+	failed    bool // GoAccess could not read the log
+	report    system.GoAccessReport
+	panel     int
+	offset    int
+
+	// "" for the report, or "review" while protecting the HTML report
+	mode   string
+	review ConfigReview
+
+	err error
+}
+
+// NewSiteAnalyticsModel creates the analytics screen for a site
+func NewSiteAnalyticsModel(site system.NginxSite) SiteAnalyticsModel {
+	return SiteAnalyticsModel{
+		theme:        theme.DefaultTheme(),
+		nginxManager: system.NewNginxManager(),
+		site:         site,
+		logPath:      system.SiteAccessLog(site),
+		loading:      true,
+	}
+}
+
+// Init runs GoAccess over the log. Returning from the install keeps the
+// model, so this also checks GoAccess again.
+func (m SiteAnalyticsModel) Init() tea.Cmd {
+	logPath := m.logPath
+	return func() tea.Msg {
+		if !system.GoAccessInstalled() {
+			return siteAnalyticsMsg{}
+		}
+		report, err := system.RunGoAccess(logPath)
+		return siteAnalyticsMsg{installed: true, report: report, err: err}
+	}
+}
+
+// Update handles messages for the analytics screen
+func (m SiteAnalyticsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case siteAnalyticsMsg:
+		m.loading = false
+		m.installed = msg.installed
+		m.report = msg.report
+		m.err = msg.err
+		m.failed = msg.err != nil
+		if m.panel >= len(m.report.Panels) {
+			m.panel, m.offset = 0, 0
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.mode == "review" {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.review, result = m.review.Update(msg, m.height)
+			switch result {
+			case ConfirmAccepted:
+				m.mode = ""
+				return m.protectAndPublish()
+			case ConfirmCancelled:
+				m.mode = ""
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			site := m.site
+			return m, func() tea.Msg {
+				return NavigateMsg{
+					Screen: ConfigEditorScreen,
+					Data: map[string]interface{}{
+						"action": "edit_nginx_site",
+						"site":   site,
+					},
+				}
+			}
+		case "i":
+			if !m.loading && !m.installed {
+				return m.install()
+			}
+		case "r":
+			if !m.loading {
+				m.loading = true
+				m.err = nil
+				return m, m.Init()
+			}
+		case "right", "l", "tab":
+			if len(m.report.Panels) > 0 {
+				m.panel = (m.panel + 1) % len(m.report.Panels)
+				m.offset = 0
+			}
+		case "left", "h", "shift+tab":
+			if len(m.report.Panels) > 0 {
+				m.panel = (m.panel + len(m.report.Panels) - 1) % len(m.report.Panels)
+				m.offset = 0
+			}
+		case "up", "k":
+			if m.offset > 0 {
+				m.offset--
+			}
+		case "down", "j":
+			if len(m.report.Panels) > 0 && m.offset < len(m.report.Panels[m.panel].Items)-m.visibleItems() {
+				m.offset++
+			}
+		case "p":
+			if !m.loading && m.installed {
+				m.err = nil
+				return m.publish()
+			}
+		}
+	}
+	return m, nil
+}
+
+// install installs GoAccess with the host's package manager
+func (m SiteAnalyticsModel) install() (tea.Model, tea.Cmd) {
+	distro, err := pkgmanager.Detect()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.loading = true
+	command := "sudo bash -c " + system.ShellQuote(distro.InstallCommand("goaccess"))
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{Command: command, Description: "Installing GoAccess"}
+	}
+}
+
+// publish writes the HTML report, first adding a basic auth rule for it
+// when no access rule covers the report's path
+func (m SiteAnalyticsModel) publish() (tea.Model, tea.Cmd) {
+	if m.site.RootDir == "" {
+		m.err = fmt.Errorf("%s has no document root to publish the report in", m.site.Name)
+		return m, nil
+	}
+	rules, err := m.nginxManager.SiteAccess(m.site.Name)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if system.AnalyticsProtected(rules) {
+		return m, m.generate()
+	}
+
+	htpasswd, err := system.LoadHtpasswd(m.nginxManager.HtpasswdPath(m.site.Name))
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if len(htpasswd.Users()) == 0 {
+		m.err = fmt.Errorf("the report is only published behind a password: add a user under Access Protection first")
+		return m, nil
+	}
+	rules = append(rules, system.SiteAccessRule{Path: system.AnalyticsPath, BasicAuth: true})
+	change, err := m.nginxManager.PlanSiteAccess(m.site.Name, rules)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.review = NewConfigReview("analytics", m.nginxManager, change)
+	m.mode = "review"
+	return m, nil
+}
+
+// protectAndPublish applies the reviewed access rule, then writes the report
+func (m SiteAnalyticsModel) protectAndPublish() (tea.Model, tea.Cmd) {
+	if err := m.nginxManager.ApplyChange(m.review.Change); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := m.nginxManager.TestConfig(); err != nil {
+		m.err = fmt.Errorf("config written but test failed: %w", err)
+		return m, nil
+	}
+	if err := m.nginxManager.ReloadNginx(); err != nil {
+		m.err = fmt.Errorf("config written but reload failed: %w", err)
+		return m, nil
+	}
+	return m, m.generate()
+}
+
+// generate runs GoAccess to write the HTML report
+func (m SiteAnalyticsModel) generate() tea.Cmd {
+	script := system.AnalyticsReportScript(m.site, m.logPath)
+	description := "Writing the analytics report for " + m.site.Name
+	return func() tea.Msg {
+		return ExecutionStartMsg{Command: "sudo bash -c " + system.ShellQuote(script), Description: description}
+	}
+}
+
+// visibleItems is the number of panel rows that fit the screen
+func (m SiteAnalyticsModel) visibleItems() int {
+	if n := m.height - 20; n > 5 {
+		return n
+	}
+	return 5
+}
+
+// View renders the analytics screen
+func (m SiteAnalyticsModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "review" {
+		return m.review.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Analytics"),
+		m.theme.DescriptionStyle.Render(m.site.Name + "  " + m.logPath),
+		"",
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := "r: Refresh" + bullet + "Esc: Back"
+	switch {
+	case m.loading:
+		sections = append(sections, m.theme.InfoStyle.Render("Analysing the access log..."))
+
+	case !m.installed:
+		sections = append(sections, m.theme.WarningStyle.Render(m.theme.Symbols.Warning+" GoAccess is not installed"))
+		help = "i: Install GoAccess" + bullet + help
+
+	case !m.failed:
+		r := m.report
+		sections = append(sections,
+			fmt.Sprintf("%s %d  %s %d  %s %d  %s %d  %s %s",
+				m.theme.Label.Render("Requests"), r.TotalRequests,
+				m.theme.Label.Render("Failed"), r.FailedRequests,
+				m.theme.Label.Render("Visitors"), r.UniqueVisitors,
+				m.theme.Label.Render("Not found"), m.notFound(),
+				m.theme.Label.Render("Bandwidth"), system.FormatBytes(uint64(r.Bandwidth))),
+			m.theme.DescriptionStyle.Render(r.StartDate+" - "+r.EndDate),
+			"",
+		)
+		sections = append(sections, m.renderPanel()...)
+		sections = append(sections, "", m.theme.DescriptionStyle.Render("HTML report: "+system.AnalyticsReportURL(m.site)))
+		help = "←/→: Panel" + bullet + "↑/↓: Scroll" + bullet + "p: Publish HTML report" + bullet + help
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// notFound returns the number of distinct URLs that answered 404
+func (m SiteAnalyticsModel) notFound() int {
+	for _, p := range m.report.Panels {
+		if p.Key == "not_found" {
+			return len(p.Items)
+		}
+	}
+	return 0
+}
+
+// renderPanel renders the panel tabs and the rows of the selected panel
+func (m SiteAnalyticsModel) renderPanel() []string {
+	if len(m.report.Panels) == 0 {
+		return []string{m.theme.DescriptionStyle.Render("No requests in the log")}
+	}
+	var tabs []string
+	for i, p := range m.report.Panels {
+		if i == m.panel {
+			tabs = append(tabs, m.theme.SelectedItem.Render("["+p.Title+"]"))
+		} else {
+			tabs = append(tabs, m.theme.MenuItem.Render(" "+p.Title+" "))
+		}
+	}
+	lines := []string{strings.Join(tabs, " "), ""}
+
+	panel := m.report.Panels[m.panel]
+	labelWidth := m.width - 48
+	if labelWidth < 20 {
+		labelWidth = 20
+	}
+	lines = append(lines, m.theme.Label.Render(fmt.Sprintf("%8s %8s %10s  %s", "Hits", "Visitors", "Bytes", "Item")))
+	end := min(m.offset+m.visibleItems(), len(panel.Items))
+	for _, item := range panel.Items[m.offset:end] {
+		lines = append(lines, fmt.Sprintf("%8d %8d %10s  %s", item.Hits, item.Visitors,
+			system.FormatBytes(uint64(item.Bytes)), truncateRunes(item.Label, labelWidth)))
+	}
+	if len(panel.Items) > end || m.offset > 0 {
+		lines = append(lines, m.theme.DescriptionStyle.Render(fmt.Sprintf("%d-%d of %d", m.offset+1, end, len(panel.Items))))
+	}
+	return lines
+}
@@ -0,0 +1,361 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// siteBackupsLoadedMsg carries the backup settings and a site's archives
+type siteBackupsLoadedMsg struct {
+	config  system.BackupConfig
+	backups []system.SiteBackup
+	err     error
+}
+
+// SiteBackupModel backs up a site to timestamped archives and restores them
+type SiteBackupModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	site    system.NginxSite
+	config  system.BackupConfig
+	backups []system.SiteBackup
+	cursor  int
+	loading bool
+	err     error
+	success string
+
+	form *huh.Form // Backup settings
+
+	confirm    Confirmation
+	confirming bool
+}
+
+// NewSiteBackupModel creates the backups screen for a site
+func NewSiteBackupModel(site system.NginxSite) SiteBackupModel {
+	return SiteBackupModel{
+		theme:   theme.DefaultTheme(),
+		site:    site,
+		loading: true,
+	}
+}
+
+// Init loads the archives. Returning from a backup or restore keeps the
+// model, so this also refreshes the list.
+func (m SiteBackupModel) Init() tea.Cmd {
+	name := m.site.Name
+	return func() tea.Msg {
+		config, err := system.LoadBackupConfig()
+		if err != nil {
+			return siteBackupsLoadedMsg{config: config, err: err}
+		}
+		backups, err := config.ListSiteBackups(name)
+		return siteBackupsLoadedMsg{config: config, backups: backups, err: err}
+	}
+}
+
+// Update handles messages for the backups screen
+func (m SiteBackupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case siteBackupsLoadedMsg:
+		m.loading = false
+		m.config = msg.config
+		m.backups = msg.backups
+		m.err = msg.err
+		if m.cursor >= len(m.backups) {
+			m.cursor = 0
+		}
+		return m, nil
+	}
+
+	if m.form != nil {
+		if key, ok := msg.(tea.KeyMsg); ok && key.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m.updateForm(msg)
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if m.confirming {
+		if key.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		var result ConfirmResult
+		m.confirm, result = m.confirm.Update(key)
+		switch result {
+		case ConfirmAccepted:
+			m.confirming = false
+			return m.restore()
+		case ConfirmCancelled:
+			m.confirming = false
+		}
+		return m, nil
+	}
+
+	switch key.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		site := m.site
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: ConfigEditorScreen,
+				Data: map[string]interface{}{
+					"action": "edit_nginx_site",
+					"site":   site,
+				},
+			}
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.backups)-1 {
+			m.cursor++
+		}
+	case "b":
+		if !m.loading {
+			return m.backup()
+		}
+	case "enter":
+		if !m.loading && len(m.backups) > 0 {
+			b := m.backups[m.cursor]
+			message := fmt.Sprintf("Restore %s from the backup of %s?\n\n"+
+				"Files in the archive overwrite the site's current files, .env, and nginx config. "+
+				"Files added since the backup and excluded directories (%s) are left as they are.",
+				m.site.Name, b.Created.Format("2006-01-02 15:04"), strings.Join(m.config.Excludes, ", "))
+			if b.Dump != "" {
+				message += "\n\nThe database is not restored; its dump is " + b.Dump + "."
+			}
+			m.confirm = NewDangerConfirmation("restore_backup", "Restore Site", message, m.site.Name)
+			m.confirming = true
+		}
+	case "s":
+		if !m.loading {
+			m.err = nil
+			m.success = ""
+			m.form = m.buildForm()
+			return m, m.form.Init()
+		}
+	case "o":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: BackupTargetsScreen, Data: map[string]interface{}{}}
+		}
+	case "r":
+		if !m.loading {
+			m.loading = true
+			return m, m.Init()
+		}
+	}
+	return m, nil
+}
+
+// backup archives the site now, pruning archives beyond the retention
+func (m SiteBackupModel) backup() (SiteBackupModel, tea.Cmd) {
+	script, archive, err := m.config.SiteBackupScript(m.site, m.backups, time.Now())
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.err = nil
+	m.success = ""
+	m.loading = true
+	description := "Backing up " + m.site.Name + " to " + archive
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{Command: "sudo bash -c " + system.ShellQuote(script), Description: description}
+	}
+}
+
+// restore extracts the selected archive over the site
+func (m SiteBackupModel) restore() (SiteBackupModel, tea.Cmd) {
+	b := m.backups[m.cursor]
+	script := system.SiteRestoreScript(m.site, b)
+	m.loading = true
+	description := "Restoring " + m.site.Name + " from " + b.Name()
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{Command: "sudo bash -c " + system.ShellQuote(script), Description: description}
+	}
+}
+
+// buildForm edits the backup root, retention, and exclusions, which apply
+// to every site
+func (m SiteBackupModel) buildForm() *huh.Form {
+	root := m.config.Root
+	keep := strconv.Itoa(m.config.Keep)
+	excludes := strings.Join(m.config.Excludes, ", ")
+	dumps := m.config.DatabaseDumps
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Key("root").Title("Backup Root").
+				Description("Archives are kept in <root>/<site>").
+				Validate(func(s string) error {
+					return system.BackupConfig{Root: strings.TrimSpace(s)}.Validate()
+				}).
+				Value(&root),
+			huh.NewInput().Key("keep").Title("Archives to Keep").
+				Description("Per site; older ones are pruned after each backup (0 = keep all)").
+				Validate(func(s string) error {
+					if n, err := strconv.Atoi(strings.TrimSpace(s)); err != nil || n < 0 {
+						return fmt.Errorf("enter a number, or 0 to keep all")
+					}
+					return nil
+				}).
+				Value(&keep),
+			huh.NewInput().Key("excludes").Title("Exclude").
+				Description("Comma-separated directories inside the project").
+				Validate(func(s string) error {
+					return system.BackupConfig{Root: system.DefaultBackupRoot, Excludes: splitList(s)}.Validate()
+				}).
+				Value(&excludes),
+			huh.NewConfirm().Key("dumps").Title("Dump Database").
+				Description("Also dump the MySQL or PostgreSQL database named in the site's .env").
+				Value(&dumps),
+		).Title("Backup Settings"),
+	).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// updateForm handles the settings form
+func (m SiteBackupModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "esc" {
+		m.form = nil
+		return m, nil
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	keep, _ := strconv.Atoi(strings.TrimSpace(m.form.GetString("keep")))
+	config := m.config
+	config.Root = strings.TrimSpace(m.form.GetString("root"))
+	config.Keep = keep
+	config.Excludes = splitList(m.form.GetString("excludes"))
+	config.DatabaseDumps = m.form.GetBool("dumps")
+	m.form = nil
+	if err := config.Save(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.success = m.theme.Symbols.CheckMark + " Backup settings saved to " + system.BackupConfigPath
+	m.loading = true
+	return m, m.Init()
+}
+
+// splitList splits a comma-separated list, dropping blanks
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// View renders the backups screen
+func (m SiteBackupModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Site Backups"),
+		m.theme.DescriptionStyle.Render(m.site.Name),
+		"",
+	}
+
+	if m.form != nil {
+		sections = append(sections, m.form.View())
+	} else {
+		keep := "all archives are kept"
+		if m.config.Keep > 0 {
+			keep = fmt.Sprintf("keeping %d per site", m.config.Keep)
+		}
+		if m.config.Root != "" {
+			sections = append(sections,
+				m.theme.Label.Render("Location: ")+m.theme.InfoStyle.Render(m.config.Root+"/"+m.site.Name),
+				m.theme.DescriptionStyle.Render(fmt.Sprintf("Excluding %s; %s", strings.Join(m.config.Excludes, ", "), keep)),
+			)
+			if engine, name := system.SiteDatabase(system.SiteProjectDir(m.site.RootDir)); engine != "" && m.config.DatabaseDumps {
+				sections = append(sections, m.theme.DescriptionStyle.Render(fmt.Sprintf("Dumping %s database %s", engine, name)))
+			}
+			if len(m.config.Targets) > 0 {
+				var names []string
+				for _, t := range m.config.Targets {
+					names = append(names, t.Name)
+				}
+				sections = append(sections, m.theme.Label.Render("Off-site: ")+m.theme.InfoStyle.Render(strings.Join(names, ", ")))
+			} else {
+				sections = append(sections, m.theme.DescriptionStyle.Render("Off-site: none (press o to add S3, SFTP, or rclone)"))
+			}
+			sections = append(sections, "")
+		}
+
+		switch {
+		case m.loading:
+			sections = append(sections, m.theme.InfoStyle.Render("Loading backups..."))
+		case len(m.backups) == 0:
+			sections = append(sections, m.theme.DescriptionStyle.Render("No backups yet. Press b to create one."))
+		default:
+			sections = append(sections, m.theme.Label.Render(fmt.Sprintf("  %-20s %10s  %-4s %s", "Created", "Size", "DB", "Archive")))
+			for i, b := range m.backups {
+				db := "-"
+				if b.Dump != "" {
+					db = "yes"
+				}
+				line := fmt.Sprintf("%-20s %10s  %-4s %s", b.Created.Format("2006-01-02 15:04:05"), formatSize(b.Size), db, b.Name())
+				if i == m.cursor {
+					sections = append(sections, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+m.theme.SelectedItem.Render(line))
+				} else {
+					sections = append(sections, "  "+m.theme.MenuItem.Render(line))
+				}
+			}
+		}
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := "b: Back up now" + bullet + "Enter: Restore" + bullet + "s: Settings" + bullet + "o: Off-site" + bullet + "r: Refresh" + bullet + "Esc: Back"
+	if m.form != nil {
+		help = "Tab/Shift+Tab: Navigate" + bullet + "Enter: Save" + bullet + "Esc: Cancel"
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/theme"
@@ -30,6 +33,22 @@ type SiteCommandsModel struct {
 	systemUser     string // from git config meta.systemuser
 	availableUsers []string
 	selectingUser  bool
+	form           *huh.Form // wp-cli search-replace or maintenance secret input
+	formAction     string    // Item ID the form belongs to
+	laravel        bool      // cwd is a Laravel app
+	maintenance    system.LaravelMaintenance
+	success        string
+	err            error
+}
+
+// wpCommandItems are offered when the current directory is a WordPress
+// install; each runs wp-cli as the configured system user
+var wpCommandItems = []SiteCommandItem{
+	{ID: "wp_core_update", Name: "WP: Core Update", Description: "Update WordPress core and its database", Screen: ExecutionScreen},
+	{ID: "wp_plugin_list", Name: "WP: Plugin List", Description: "List plugins with their status and available updates", Screen: ExecutionScreen},
+	{ID: "wp_plugin_update", Name: "WP: Plugin Update", Description: "Update every plugin", Screen: ExecutionScreen},
+	{ID: "wp_cache_flush", Name: "WP: Cache Flush", Description: "Flush the object cache", Screen: ExecutionScreen},
+	{ID: "wp_search_replace", Name: "WP: Search-Replace", Description: "Replace the old domain or URL in the database after a migration", Screen: ExecutionScreen},
 }
 
 // NewSiteCommandsModel creates a new site commands menu model
@@ -59,6 +78,24 @@ func NewSiteCommandsModel() SiteCommandsModel {
 			Description: "Laravel permissions, artisan commands, .env setup",
 			Screen:      LaravelPermissionsScreen, // Will navigate to Laravel menu
 		},
+		{
+			ID:          "env_editor",
+			Name:        "Environment (.env) Editor",
+			Description: "Edit .env values with validation and automatic backups",
+			Screen:      EnvEditorScreen,
+		},
+		{
+			ID:          "laravel_lint",
+			Name:        "Production Readiness",
+			Description: "Check .env and caches for production anti-patterns",
+			Screen:      LaravelLintScreen,
+		},
+		{
+			ID:          "laravel_log",
+			Name:        "Laravel Log",
+			Description: "Follow storage/logs/laravel.log live",
+			Screen:      LogViewerScreen,
+		},
 		{
 			ID:          "npm_install",
 			Name:        "NPM Install",
@@ -83,6 +120,36 @@ func NewSiteCommandsModel() SiteCommandsModel {
 			Description: "Run composer install using fpcli (FrankenPHP)",
 			Screen:      ExecutionScreen,
 		},
+		{
+			ID:          "artisan",
+			Name:        "Artisan Commands",
+			Description: "Search the project's artisan commands and run one with its arguments",
+			Screen:      ArtisanScreen,
+		},
+		{
+			ID:          "composer_audit",
+			Name:        "Composer Audit",
+			Description: "Check composer.lock for known vulnerabilities",
+			Screen:      ComposerAuditScreen,
+		},
+	}
+
+	cwd, _ := os.Getwd()
+	if _, err := system.Stat(filepath.Join(cwd, ".env")); err == nil {
+		items = append(items, SiteCommandItem{
+			ID:          "error_tracking",
+			Name:        "Error Tracking (Sentry)",
+			Description: "Set the Sentry DSN in .env, install the SDK, and send a test event",
+			Screen:      ExecutionScreen,
+		})
+	}
+	if system.IsWordPress(cwd) {
+		items = append(items, wpCommandItems...)
+	}
+	maintenance := system.LaravelMaintenanceState(cwd)
+	laravel := system.IsLaravelApp(cwd)
+	if laravel {
+		items = append(items, maintenanceItem(maintenance))
 	}
 
 	// Get available users for selection
@@ -99,11 +166,22 @@ func NewSiteCommandsModel() SiteCommandsModel {
 		theme:          theme.DefaultTheme(),
 		cursor:         0,
 		items:          items,
+		cwd:            cwd,
 		systemUser:     getGitSystemUser(),
 		availableUsers: availableUsers,
+		laravel:        laravel,
+		maintenance:    maintenance,
 	}
 }
 
+// maintenanceItem offers to bring a Laravel app down or back up
+func maintenanceItem(state system.LaravelMaintenance) SiteCommandItem {
+	if state.Down {
+		return SiteCommandItem{ID: "maintenance", Name: "Maintenance Mode: Bring Up (m)", Description: "The app is in maintenance mode; run php artisan up", Screen: ExecutionScreen}
+	}
+	return SiteCommandItem{ID: "maintenance", Name: "Maintenance Mode: Take Down (m)", Description: "The app is live; run php artisan down, optionally with a bypass secret", Screen: ExecutionScreen}
+}
+
 // Init initializes the site commands screen
 func (m SiteCommandsModel) Init() tea.Cmd {
 	return nil
@@ -118,6 +196,18 @@ func (m SiteCommandsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.form != nil {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				m.form = nil
+				return m, nil
+			}
+			break
+		}
+		m.err = nil
+		m.success = ""
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -137,6 +227,23 @@ func (m SiteCommandsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.cursor++
 			}
 
+		case "m":
+			// One-key maintenance toggle
+			if m.selectingUser {
+				return m, nil
+			}
+			for i, item := range m.items {
+				if item.ID == "maintenance" {
+					m.cursor = i
+					if m.systemUser == "" {
+						m.selectingUser = true
+						m.cursor = 0
+						return m, nil
+					}
+					return m.executeAction(item)
+				}
+			}
+
 		case "enter", " ":
 			if m.selectingUser {
 				m.systemUser = m.availableUsers[m.cursor]
@@ -155,8 +262,8 @@ func (m SiteCommandsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			selectedItem := m.items[m.cursor]
 
-			// If it's composer_install_fpcli and systemUser is missing, trigger selection
-			if selectedItem.ID == "composer_install_fpcli" && m.systemUser == "" {
+			// Commands that run as the site's user need one selected first
+			if (selectedItem.ID == "composer_install_fpcli" || selectedItem.ID == "artisan" || selectedItem.ID == "maintenance" || selectedItem.ID == "error_tracking" || strings.HasPrefix(selectedItem.ID, "wp_")) && m.systemUser == "" {
 				m.selectingUser = true
 				m.cursor = 0
 				return m, nil
@@ -164,11 +271,221 @@ func (m SiteCommandsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			return m.executeAction(selectedItem)
 		}
+		return m, nil
+	}
+
+	if m.form == nil {
+		return m, nil
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State == huh.StateCompleted && m.formAction == "error_tracking" {
+		return m.setupErrorTracking()
+	}
+	if m.form.State == huh.StateCompleted && m.formAction == "maintenance" {
+		secret := strings.TrimSpace(m.form.GetString("secret"))
+		m.form = nil
+		return m.runMaintenance(true, secret)
+	}
+	if m.form.State == huh.StateCompleted {
+		from := strings.TrimSpace(m.form.GetString("from"))
+		to := strings.TrimSpace(m.form.GetString("to"))
+		dryRun := m.form.GetBool("dryRun")
+		m.form = nil
+		commands := [][]string{system.WPSearchReplaceArgs(from, to, dryRun)}
+		description := fmt.Sprintf("wp search-replace %s → %s", from, to)
+		if dryRun {
+			description += " (dry run)"
+		} else {
+			commands = append(commands, []string{"cache", "flush"})
+		}
+		return m.runWP(description, commands...)
+	}
+	return m, cmd
+}
+
+// buildSearchReplaceForm asks for the old and new URL or domain
+func (m SiteCommandsModel) buildSearchReplaceForm() *huh.Form {
+	var from, to string
+	dryRun := true
+	notEmpty := func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("required")
+		}
+		return nil
+	}
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Key("from").Title("Search For").
+				Description("The old URL or domain, such as https://staging.example.com").
+				Validate(notEmpty).
+				Value(&from),
+			huh.NewInput().Key("to").Title("Replace With").
+				Description("The new URL or domain").
+				Validate(notEmpty).
+				Value(&to),
+			huh.NewConfirm().Key("dryRun").Title("Dry run first?").
+				Description("Report what would change without writing to the database").
+				Value(&dryRun),
+		).Title("WordPress Search-Replace"),
+	).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// buildMaintenanceForm asks for the secret that bypasses maintenance mode
+func (m SiteCommandsModel) buildMaintenanceForm() *huh.Form {
+	secret, _ := system.GeneratePassword(24)
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Key("secret").Title("Bypass Secret").
+				Description("Visiting /<secret> sets a cookie that skips maintenance mode; leave empty for none").
+				Validate(func(s string) error {
+					_, err := system.MaintenanceArgs(true, strings.TrimSpace(s))
+					return err
+				}).
+				Value(&secret),
+		).Title("Take the App Down"),
+	).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// buildErrorTrackingForm asks for the Sentry DSN and what to do after
+// writing it to .env
+func (m SiteCommandsModel) buildErrorTrackingForm() *huh.Form {
+	setup := system.SentrySetup{Laravel: m.laravel}
+	var dsn, environment string
+	if env, err := system.ParseEnvFile(filepath.Join(m.cwd, ".env")); err == nil {
+		dsn, _ = env.Get(setup.DSNKey())
+		if environment, _ = env.Get("SENTRY_ENVIRONMENT"); environment == "" {
+			environment, _ = env.Get("APP_ENV")
+		}
+	}
+	install := !setup.Installed(m.cwd)
+	test := true
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Key("dsn").Title("DSN").
+				Description("From Sentry: Project Settings, Client Keys (DSN); written as "+setup.DSNKey()).
+				Validate(func(s string) error {
+					return system.ValidateSentryDSN(strings.TrimSpace(s))
+				}).
+				Value(&dsn),
+			huh.NewInput().Key("environment").Title("Environment").
+				Description("Tags events, such as production or staging; leave empty to omit").
+				Validate(func(s string) error {
+					return system.SentrySetup{DSN: "https://k@sentry.io/1", Environment: strings.TrimSpace(s)}.Validate()
+				}).
+				Value(&environment),
+			huh.NewConfirm().Key("install").Title("Install "+setup.Package()+"?").
+				Description("Runs composer require as "+m.systemUser).
+				Value(&install),
+			huh.NewConfirm().Key("test").Title("Send a test event?").
+				Description("Confirms the DSN works; the event shows up in the project's Issues").
+				Value(&test),
+		).Title("Error Tracking"),
+	).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// setupErrorTracking writes the DSN to .env with a backup, then installs
+// the SDK and sends a test event as the system user if asked
+func (m SiteCommandsModel) setupErrorTracking() (SiteCommandsModel, tea.Cmd) {
+	setup := system.SentrySetup{
+		Laravel:     m.laravel,
+		DSN:         strings.TrimSpace(m.form.GetString("dsn")),
+		Environment: strings.TrimSpace(m.form.GetString("environment")),
+	}
+	install, test := m.form.GetBool("install"), m.form.GetBool("test")
+	m.form = nil
+	if err := setup.Validate(); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	path := filepath.Join(m.cwd, ".env")
+	env, err := system.ParseEnvFile(path)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	for _, kv := range setup.Env() {
+		env.Set(kv[0], kv[1])
+	}
+	backup, err := env.Save()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.success = m.theme.Symbols.CheckMark + " " + setup.DSNKey() + " written to .env"
+	if backup != "" {
+		m.success += " (backup: " + filepath.Base(backup) + ")"
+	}
+	if !install && !test {
+		return m, nil
+	}
+
+	script, err := setup.Script(m.cwd, m.systemUser, install, test)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	description := "Sending a Sentry test event"
+	if install {
+		description = "Installing " + setup.Package()
+		if test {
+			description += " and sending a test event"
+		}
+	}
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{Command: script, Description: description}
 	}
+}
 
+// runMaintenance runs php artisan down or up as the system user
+func (m SiteCommandsModel) runMaintenance(down bool, secret string) (SiteCommandsModel, tea.Cmd) {
+	args, err := system.MaintenanceArgs(down, secret)
+	if err == nil {
+		var script string
+		if script, err = system.ArtisanScript(m.cwd, m.systemUser, "php", args); err == nil {
+			description := "Bringing the app back up"
+			if down {
+				description = "Taking the app down for maintenance"
+				if secret != "" {
+					description += " (bypass at /" + secret + ")"
+				}
+			}
+			return m, func() tea.Msg {
+				return ExecutionStartMsg{Command: script, Description: description}
+			}
+		}
+	}
+	m.err = err
 	return m, nil
 }
 
+// runWP runs wp-cli commands in the current directory as the system user
+func (m SiteCommandsModel) runWP(description string, commands ...[]string) (SiteCommandsModel, tea.Cmd) {
+	script, err := system.WPCLIScript(m.cwd, m.systemUser, commands...)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{
+			Command:     "sudo bash -c " + system.ShellQuote(script),
+			Description: description + " as " + m.systemUser,
+		}
+	}
+}
+
 // executeAction handles the selected menu item
 func (m SiteCommandsModel) executeAction(item SiteCommandItem) (SiteCommandsModel, tea.Cmd) {
 	switch item.ID {
@@ -241,6 +558,26 @@ echo "Note: System PHP (if installed) is still available at /usr/bin/php"
 			return NavigateMsg{Screen: LaravelPermissionsScreen}
 		}
 
+	case "env_editor":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: EnvEditorScreen}
+		}
+
+	case "laravel_lint":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: LaravelLintScreen}
+		}
+
+	case "laravel_log":
+		cwd, _ := os.Getwd()
+		source := system.LaravelLogSource(cwd)
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: LogViewerScreen,
+				Data:   map[string]interface{}{"path": source.Path, "group": source.Group},
+			}
+		}
+
 	case "npm_install":
 		return m, func() tea.Msg {
 			return NavigateMsg{
@@ -265,6 +602,22 @@ echo "Note: System PHP (if installed) is still available at /usr/bin/php"
 			}
 		}
 
+	case "artisan":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: ArtisanScreen,
+				Data:   map[string]interface{}{"dir": m.cwd, "user": m.systemUser},
+			}
+		}
+
+	case "composer_audit":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: ComposerAuditScreen,
+				Data:   map[string]interface{}{"dir": m.cwd, "user": m.systemUser},
+			}
+		}
+
 	case "composer_install_fpcli":
 		// Run composer install using fpcli (FrankenPHP)
 		script := `
@@ -335,6 +688,35 @@ EOF
 			}
 		}
 
+	case "wp_core_update":
+		return m.runWP("wp core update", []string{"core", "update"}, []string{"core", "update-db"})
+
+	case "wp_plugin_list":
+		return m.runWP("wp plugin list", []string{"plugin", "list", "--fields=name,status,version,update_version"})
+
+	case "wp_plugin_update":
+		return m.runWP("wp plugin update --all", []string{"plugin", "update", "--all"})
+
+	case "wp_cache_flush":
+		return m.runWP("wp cache flush", []string{"cache", "flush"})
+
+	case "maintenance":
+		if m.maintenance.Down {
+			return m.runMaintenance(false, "")
+		}
+		m.form = m.buildMaintenanceForm()
+		m.formAction = item.ID
+		return m, m.form.Init()
+
+	case "wp_search_replace":
+		m.form = m.buildSearchReplaceForm()
+		m.formAction = item.ID
+		return m, m.form.Init()
+
+	case "error_tracking":
+		m.form = m.buildErrorTrackingForm()
+		m.formAction = item.ID
+		return m, m.form.Init()
 	}
 
 	return m, nil
@@ -350,6 +732,15 @@ func (m SiteCommandsModel) View() string {
 	if m.selectingUser {
 		return m.viewUserSelection()
 	}
+	if m.form != nil {
+		content := lipgloss.JoinVertical(lipgloss.Left,
+			m.theme.Title.Render("Site Commands"),
+			"",
+			m.form.View(),
+			"",
+			m.theme.Help.Render("Tab/Shift+Tab: Navigate "+m.theme.Symbols.Bullet+" Enter: Next/Run "+m.theme.Symbols.Bullet+" Esc: Cancel"))
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(content))
+	}
 
 	// Header
 	// Header with host info
@@ -386,9 +777,19 @@ func (m SiteCommandsModel) View() string {
 	}
 
 	menu := lipgloss.JoinVertical(lipgloss.Left, menuItems...)
+	if m.success != "" {
+		menu = lipgloss.JoinVertical(lipgloss.Left, menu, m.theme.SuccessStyle.Render(m.success))
+	}
+	if m.err != nil {
+		menu = lipgloss.JoinVertical(lipgloss.Left, menu, m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
 
 	// Help
-	help := m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Select " + m.theme.Symbols.Bullet + " Esc: Back " + m.theme.Symbols.Bullet + " q: Quit")
+	helpText := m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Select "
+	if m.laravel {
+		helpText += m.theme.Symbols.Bullet + " m: Maintenance "
+	}
+	help := m.theme.Help.Render(helpText + m.theme.Symbols.Bullet + " Esc: Back " + m.theme.Symbols.Bullet + " q: Quit")
 
 	// Combine all sections
 	content := lipgloss.JoinVertical(
@@ -419,7 +820,7 @@ func (m SiteCommandsModel) View() string {
 func (m SiteCommandsModel) viewUserSelection() string {
 	header := m.theme.Title.Render("Select System User")
 
-	description := m.theme.DescriptionStyle.Render("Select a user to run Composer commands as.")
+	description := m.theme.DescriptionStyle.Render("Select a user to run Composer and wp-cli commands as.")
 
 	var items []string
 	items = append(items, "")
@@ -2,8 +2,11 @@ package screens
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/theme"
@@ -34,6 +37,29 @@ type SiteDetailsModel struct {
 	actions      []string
 	err          error
 	success      string
+	notes        string
+
+	// Maintenance state of a Laravel app at the site's root
+	laravel     bool
+	maintenance system.LaravelMaintenance
+
+	// Health check and its last result
+	healthCheck  *system.SiteHealthCheck
+	healthResult *system.SiteHealthResult
+	healthForm   *huh.Form
+	checking     bool
+
+	confirm    Confirmation
+	confirming bool
+
+	review    ConfigReview
+	reviewing bool
+
+	// PHP version picker
+	phpVersions []string
+	phpCursor   int
+	phpSocket   string // Socket the site uses now
+	pickingPHP  bool
 }
 
 // NewSiteDetailsModel creates a new site details model
@@ -52,7 +78,7 @@ func NewSiteDetailsModel(site system.NginxSite) SiteDetailsModel {
 	}
 
 	if site.HasPHP {
-		actions = append(actions, "Convert to FrankenPHP Classic Mode")
+		actions = append(actions, "Switch PHP Version", "Convert to FrankenPHP Classic Mode", "Convert to FrankenPHP Worker Mode (Octane)")
 	}
 
 	actions = append(actions,
@@ -60,9 +86,34 @@ func NewSiteDetailsModel(site system.NginxSite) SiteDetailsModel {
 		"Reload Nginx",
 		"Delete Site",
 		"Open in Editor",
+		"Dependency Graph",
+		"Restart Stack",
+		"Drain Workers",
+		"Production Hardening",
+		"Protocols & Compression",
+		"Access Protection",
+		"Security Headers",
+		"Rate Limiting",
+		"Analytics",
+		"Notes & Runbooks",
+		"Node.js Version (.nvmrc)",
+		"Node Apps (systemd/PM2)",
+		"Check Health Now",
+		"Configure Health Check",
+		"Backups",
 		"← Back to Sites",
 	)
 
+	notes, _ := system.LoadSiteNotes(site.Name)
+	projectDir := system.SiteProjectDir(site.RootDir)
+	healthCheck, _ := system.LoadSiteHealthCheck(site.Name)
+	var healthResult *system.SiteHealthResult
+	if results, err := system.LoadSiteHealthResults(); err == nil {
+		if result, ok := results[site.Name]; ok {
+			healthResult = &result
+		}
+	}
+
 	return SiteDetailsModel{
 		theme:        theme.DefaultTheme(),
 		nginxManager: nginxManager,
@@ -71,7 +122,118 @@ func NewSiteDetailsModel(site system.NginxSite) SiteDetailsModel {
 		actions:      actions,
 		err:          nil,
 		success:      "",
+		notes:        notes,
+		laravel:      system.IsLaravelApp(projectDir),
+		maintenance:  system.LaravelMaintenanceState(projectDir),
+		healthCheck:  healthCheck,
+		healthResult: healthResult,
+	}
+}
+
+// siteHealthMsg carries the result of a health check
+type siteHealthMsg struct {
+	result system.SiteHealthResult
+	err    error
+}
+
+// checkHealth runs the site's health check in the background
+func (m SiteDetailsModel) checkHealth() tea.Cmd {
+	name := m.site.Name
+	return func() tea.Msg {
+		result, err := system.CheckSiteHealth(name)
+		return siteHealthMsg{result: result, err: err}
+	}
+}
+
+// buildHealthForm edits the health check, starting from the saved one or
+// the site's home page
+func (m SiteDetailsModel) buildHealthForm() *huh.Form {
+	check := system.DefaultSiteHealthCheck(m.site)
+	if m.healthCheck != nil {
+		check = *m.healthCheck
 	}
+	url, contains := check.URL, check.ExpectContains
+	status, timeout := "", ""
+	if check.ExpectStatus != 0 {
+		status = fmt.Sprint(check.ExpectStatus)
+	}
+	if check.TimeoutSeconds != 0 {
+		timeout = fmt.Sprint(check.TimeoutSeconds)
+	}
+	insecure := check.Insecure
+
+	number := func(field string) func(string) error {
+		return func(s string) error {
+			if s = strings.TrimSpace(s); s == "" {
+				return nil
+			}
+			if _, err := strconv.Atoi(s); err != nil {
+				return fmt.Errorf("%s must be a number", field)
+			}
+			return nil
+		}
+	}
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Key("url").Title("URL").
+				Description("Requested with GET; redirects are not followed").
+				Validate(func(s string) error {
+					return system.SiteHealthCheck{URL: strings.TrimSpace(s)}.Validate()
+				}).
+				Value(&url),
+			huh.NewInput().Key("status").Title("Expected Status").
+				Placeholder(fmt.Sprint(system.DefaultHealthCheckStatus)).
+				Validate(number("expected status")).
+				Value(&status),
+			huh.NewInput().Key("contains").Title("Expected Text").
+				Description("Optional; the response body must contain it").
+				Value(&contains),
+			huh.NewInput().Key("timeout").Title("Timeout (seconds)").
+				Placeholder(fmt.Sprint(system.DefaultHealthCheckTimeout)).
+				Validate(number("timeout")).
+				Value(&timeout),
+			huh.NewConfirm().Key("insecure").Title("Skip TLS verification?").
+				Description("Only for sites still waiting for a valid certificate").
+				Value(&insecure),
+		).Title("Health Check: " + m.site.Name),
+	).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// updateHealthForm handles the health check form
+func (m SiteDetailsModel) updateHealthForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "esc" {
+		m.healthForm = nil
+		return m, nil
+	}
+	form, cmd := m.healthForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.healthForm = f
+	}
+	if m.healthForm.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	status, _ := strconv.Atoi(strings.TrimSpace(m.healthForm.GetString("status")))
+	timeout, _ := strconv.Atoi(strings.TrimSpace(m.healthForm.GetString("timeout")))
+	check := system.SiteHealthCheck{
+		URL:            strings.TrimSpace(m.healthForm.GetString("url")),
+		ExpectStatus:   status,
+		ExpectContains: m.healthForm.GetString("contains"),
+		TimeoutSeconds: timeout,
+		Insecure:       m.healthForm.GetBool("insecure"),
+	}
+	m.healthForm = nil
+	if err := system.SaveSiteHealthCheck(m.site.Name, check); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.healthCheck = &check
+	m.success = m.theme.Symbols.CheckMark + " Health check saved; checking now..."
+	m.checking = true
+	return m, m.checkHealth()
 }
 
 // Init initializes the site details screen
@@ -87,7 +249,67 @@ func (m SiteDetailsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case siteHealthMsg:
+		m.checking = false
+		m.success = ""
+		if msg.err != nil && msg.result.CheckedAt.IsZero() {
+			m.err = msg.err
+			return m, nil
+		}
+		m.healthResult = &msg.result
+		if msg.result.OK {
+			m.success = m.theme.Symbols.CheckMark + " Site is healthy (" + msg.result.Summary() + ")"
+		} else {
+			m.err = fmt.Errorf("health check failed: %s", msg.result.Error)
+		}
+		return m, nil
+	}
+
+	if m.healthForm != nil {
+		if key, ok := msg.(tea.KeyMsg); ok && key.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m.updateHealthForm(msg)
+	}
+
+	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.reviewing {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.review, result = m.review.Update(msg, m.height)
+			switch result {
+			case ConfirmAccepted:
+				m.reviewing = false
+				return m.applyReviewed()
+			case ConfirmCancelled:
+				m.reviewing = false
+			}
+			return m, nil
+		}
+
+		if m.pickingPHP {
+			return m.updatePHPPicker(msg)
+		}
+
+		if m.confirming {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.confirm, result = m.confirm.Update(msg)
+			switch result {
+			case ConfirmCancelled:
+				m.confirming = false
+			case ConfirmAccepted:
+				m.confirming = false
+				return m.deleteSite()
+			}
+			return m, nil
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -115,6 +337,31 @@ func (m SiteDetailsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// applyReviewed writes a reviewed config change and reloads nginx
+func (m SiteDetailsModel) applyReviewed() (SiteDetailsModel, tea.Cmd) {
+	if m.review.Action == "php_version" {
+		return m.applyPHPSocket()
+	}
+	if err := m.nginxManager.ApplyChange(m.review.Change); err != nil {
+		m.err = fmt.Errorf("failed to remove SSL: %w", err)
+		return m, nil
+	}
+	if err := m.nginxManager.TestConfig(); err != nil {
+		m.err = fmt.Errorf("SSL removed but config test failed: %w", err)
+		return m, nil
+	}
+	if err := m.nginxManager.ReloadNginx(); err != nil {
+		m.err = fmt.Errorf("SSL removed but reload failed: %w", err)
+		return m, nil
+	}
+	m.success = "✓ SSL certificate removed, site now uses HTTP only"
+	m.site.HasSSL = false
+	// Return to nginx config to refresh
+	return m, func() tea.Msg {
+		return NavigateMsg{Screen: NginxConfigScreen}
+	}
+}
+
 // executeAction executes the selected action
 func (m SiteDetailsModel) executeAction() (SiteDetailsModel, tea.Cmd) {
 	m.err = nil
@@ -155,30 +402,38 @@ func (m SiteDetailsModel) executeAction() (SiteDetailsModel, tea.Cmd) {
 		}
 
 	case actionName == "Remove SSL Certificate":
-		// Remove SSL configuration
-		err := m.nginxManager.RemoveSSL(m.site.Name)
+		// Stage the config without SSL for review
+		change, err := m.nginxManager.PlanRemoveSSL(m.site.Name)
 		if err != nil {
 			m.err = fmt.Errorf("failed to remove SSL: %w", err)
 		} else {
-			// Test configuration
-			err = m.nginxManager.TestConfig()
-			if err != nil {
-				m.err = fmt.Errorf("SSL removed but config test failed: %w", err)
-			} else {
-				// Reload nginx
-				err = m.nginxManager.ReloadNginx()
-				if err != nil {
-					m.err = fmt.Errorf("SSL removed but reload failed: %w", err)
-				} else {
-					m.success = "✓ SSL certificate removed, site now uses HTTP only"
-					m.site.HasSSL = false
-					// Return to nginx config to refresh
-					return m, func() tea.Msg {
-						return NavigateMsg{Screen: NginxConfigScreen}
-					}
-				}
+			m.review = NewConfigReview("remove_ssl", m.nginxManager, change)
+			m.reviewing = true
+		}
+
+	case actionName == "Switch PHP Version":
+		m.phpVersions = system.PHPFPMVersions()
+		if len(m.phpVersions) == 0 {
+			m.err = fmt.Errorf("no PHP-FPM versions are installed")
+			break
+		}
+		socket, err := m.nginxManager.CurrentPHPSocket(m.site.Name)
+		if err != nil {
+			m.err = err
+			break
+		}
+		if socket == "" {
+			m.err = fmt.Errorf("%s does not pass requests to a PHP-FPM socket", m.site.Name)
+			break
+		}
+		m.phpSocket = socket
+		m.phpCursor = 0
+		for i, v := range m.phpVersions {
+			if v == system.SocketPHPVersion(socket) {
+				m.phpCursor = i
 			}
 		}
+		m.pickingPHP = true
 
 	case actionName == "Test Nginx Configuration":
 		err := m.nginxManager.TestConfig()
@@ -197,17 +452,10 @@ func (m SiteDetailsModel) executeAction() (SiteDetailsModel, tea.Cmd) {
 		}
 
 	case actionName == "Delete Site":
-		// Confirm deletion
-		err := m.nginxManager.DeleteSite(m.site.Name)
-		if err != nil {
-			m.err = err
-		} else {
-			m.success = fmt.Sprintf("✓ Site '%s' deleted", m.site.Name)
-			// Return to nginx config screen
-			return m, func() tea.Msg {
-				return NavigateMsg{Screen: NginxConfigScreen}
-			}
-		}
+		m.confirm = NewDangerConfirmation("delete_site", "Delete Site",
+			fmt.Sprintf("Delete site '%s' (%s)? Its Nginx configuration will be removed.", m.site.Name, m.site.Domain),
+			m.site.Name)
+		m.confirming = true
 
 	case actionName == "Convert to FrankenPHP Classic Mode":
 		// Navigate to FrankenPHP classic screen with site data
@@ -220,6 +468,17 @@ func (m SiteDetailsModel) executeAction() (SiteDetailsModel, tea.Cmd) {
 			}
 		}
 
+	case actionName == "Convert to FrankenPHP Worker Mode (Octane)":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: FrankenPHPClassicScreen,
+				Data: map[string]interface{}{
+					"site":   m.site,
+					"worker": true,
+				},
+			}
+		}
+
 	case actionName == "Open in Editor":
 		// Navigate to editor selection screen
 		return m, func() tea.Msg {
@@ -231,6 +490,153 @@ func (m SiteDetailsModel) executeAction() (SiteDetailsModel, tea.Cmd) {
 			}
 		}
 
+	case actionName == "Dependency Graph":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: SiteStackScreen,
+				Data: map[string]interface{}{
+					"site": m.site,
+				},
+			}
+		}
+
+	case actionName == "Restart Stack":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: SiteStackScreen,
+				Data: map[string]interface{}{
+					"site":    m.site,
+					"restart": true,
+				},
+			}
+		}
+
+	case actionName == "Drain Workers":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: SiteStackScreen,
+				Data: map[string]interface{}{
+					"site":  m.site,
+					"drain": true,
+				},
+			}
+		}
+
+	case actionName == "Production Hardening":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: SiteHardeningScreen,
+				Data: map[string]interface{}{
+					"site": m.site,
+				},
+			}
+		}
+
+	case actionName == "Protocols & Compression":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: SiteProtocolsScreen,
+				Data: map[string]interface{}{
+					"site": m.site,
+				},
+			}
+		}
+
+	case actionName == "Access Protection":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: SiteAccessScreen,
+				Data: map[string]interface{}{
+					"site": m.site,
+				},
+			}
+		}
+
+	case actionName == "Security Headers":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: SiteHeadersScreen,
+				Data: map[string]interface{}{
+					"site": m.site,
+				},
+			}
+		}
+
+	case actionName == "Rate Limiting":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: SiteRateLimitsScreen,
+				Data: map[string]interface{}{
+					"site": m.site,
+				},
+			}
+		}
+
+	case actionName == "Analytics":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: SiteAnalyticsScreen,
+				Data: map[string]interface{}{
+					"site": m.site,
+				},
+			}
+		}
+
+	case actionName == "Notes & Runbooks":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: SiteNotesScreen,
+				Data: map[string]interface{}{
+					"site": m.site,
+				},
+			}
+		}
+
+	case actionName == "Node.js Version (.nvmrc)":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: NodeManagementScreen,
+				Data: map[string]interface{}{
+					"site": m.site,
+				},
+			}
+		}
+
+	case actionName == "Node Apps (systemd/PM2)":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: NodeAppsScreen,
+				Data: map[string]interface{}{
+					"site": m.site,
+				},
+			}
+		}
+
+	case actionName == "Check Health Now":
+		if m.healthCheck == nil {
+			m.healthForm = m.buildHealthForm()
+			return m, m.healthForm.Init()
+		}
+		if !m.checking {
+			m.checking = true
+			m.success = "Checking " + m.healthCheck.URL + "..."
+			return m, m.checkHealth()
+		}
+
+	case actionName == "Configure Health Check":
+		m.healthForm = m.buildHealthForm()
+		return m, m.healthForm.Init()
+
+	case actionName == "Backups":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: SiteBackupScreen,
+				Data: map[string]interface{}{
+					"site": m.site,
+				},
+			}
+		}
+
 	case actionName == "← Back to Sites":
 		return m, func() tea.Msg {
 			return NavigateMsg{Screen: NginxConfigScreen}
@@ -240,18 +646,141 @@ func (m SiteDetailsModel) executeAction() (SiteDetailsModel, tea.Cmd) {
 	return m, nil
 }
 
+// updatePHPPicker handles keys while choosing a PHP version
+func (m SiteDetailsModel) updatePHPPicker(msg tea.KeyMsg) (SiteDetailsModel, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.pickingPHP = false
+	case "up", "k":
+		if m.phpCursor > 0 {
+			m.phpCursor--
+		}
+	case "down", "j":
+		if m.phpCursor < len(m.phpVersions)-1 {
+			m.phpCursor++
+		}
+	case "enter", " ":
+		m.pickingPHP = false
+		version := m.phpVersions[m.phpCursor]
+		socket := system.SitePHPSocket(m.site.Name, version)
+		if socket == m.phpSocket {
+			m.success = fmt.Sprintf("✓ %s already uses PHP %s", m.site.Name, version)
+			return m, nil
+		}
+		// Stage the new socket for review
+		change, err := m.nginxManager.PlanSitePHPSocket(m.site.Name, socket)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.review = NewConfigReview("php_version", m.nginxManager, change)
+		m.reviewing = true
+	}
+	return m, nil
+}
+
+// applyPHPSocket writes the reviewed PHP socket change and reloads nginx
+func (m SiteDetailsModel) applyPHPSocket() (SiteDetailsModel, tea.Cmd) {
+	version := m.phpVersions[m.phpCursor]
+	if err := m.nginxManager.ApplyChange(m.review.Change); err != nil {
+		m.err = fmt.Errorf("failed to switch PHP version: %w", err)
+		return m, nil
+	}
+	if err := m.nginxManager.TestConfig(); err != nil {
+		m.err = fmt.Errorf("PHP version switched but config test failed: %w", err)
+		return m, nil
+	}
+	if err := m.nginxManager.ReloadNginx(); err != nil {
+		m.err = fmt.Errorf("PHP version switched but reload failed: %w", err)
+		return m, nil
+	}
+	m.phpSocket = system.SitePHPSocket(m.site.Name, version)
+	m.success = fmt.Sprintf("✓ %s now uses PHP %s (%s)", m.site.Name, version, m.phpSocket)
+	return m, nil
+}
+
+// phpPickerView renders the PHP version choices
+func (m SiteDetailsModel) phpPickerView() string {
+	current := system.SocketPHPVersion(m.phpSocket)
+	sections := []string{
+		m.theme.Title.Render("Switch PHP Version: " + m.site.Name),
+		"",
+		m.theme.Label.Render("Current socket: ") + m.theme.DescriptionStyle.Render(m.phpSocket),
+		"",
+	}
+	for i, version := range m.phpVersions {
+		cursor := "  "
+		style := m.theme.MenuItem
+		if i == m.phpCursor {
+			cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+			style = m.theme.SelectedItem
+		}
+		line := "PHP " + version
+		if version == current {
+			line += " (current)"
+		}
+		sections = append(sections, style.Render(cursor+line))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections = append(sections, "",
+		m.theme.DescriptionStyle.Render("The site's own pool socket is used when one exists for the version."),
+		"",
+		m.theme.Help.Render("↑/↓: Navigate"+bullet+"Enter: Review change"+bullet+"Esc: Cancel"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// deleteSite removes the site once deletion has been confirmed
+func (m SiteDetailsModel) deleteSite() (SiteDetailsModel, tea.Cmd) {
+	err := m.nginxManager.DeleteSite(m.site.Name)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.success = fmt.Sprintf("✓ Site '%s' deleted", m.site.Name)
+	// Return to nginx config screen
+	return m, func() tea.Msg {
+		return NavigateMsg{Screen: NginxConfigScreen}
+	}
+}
+
 // View renders the site details screen
 func (m SiteDetailsModel) View() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
 
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+	if m.reviewing {
+		return m.review.View(m.theme, m.width, m.height)
+	}
+	if m.pickingPHP {
+		return m.phpPickerView()
+	}
+	if m.healthForm != nil {
+		content := lipgloss.JoinVertical(lipgloss.Left,
+			m.theme.Title.Render("Site Details: "+m.site.Name),
+			"",
+			m.healthForm.View(),
+			"",
+			m.theme.Help.Render("Tab/Shift+Tab: Navigate "+m.theme.Symbols.Bullet+" Enter: Next/Save "+m.theme.Symbols.Bullet+" Esc: Cancel"))
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(content))
+	}
+
 	// Header
 	header := m.theme.Title.Render(fmt.Sprintf("Site Details: %s", m.site.Name))
 
 	// Site information
 	var info []string
-	info = append(info, m.theme.Label.Render("Domain:      ")+m.theme.MenuItem.Render(m.site.Domain))
+	info = append(info, m.theme.Label.Render("Domain:      ")+m.theme.MenuItem.Render(strings.Join(siteDomains(m.site), ", ")))
 	info = append(info, m.theme.Label.Render("Root Dir:    ")+m.theme.MenuItem.Render(m.site.RootDir))
 	info = append(info, m.theme.Label.Render("Config Path: ")+m.theme.DescriptionStyle.Render(m.site.ConfigPath))
 
@@ -274,6 +803,37 @@ func (m SiteDetailsModel) View() string {
 	}
 	info = append(info, m.theme.Label.Render("SSL:         ")+m.theme.MenuItem.Render(sslText))
 
+	// Laravel maintenance mode
+	if m.laravel {
+		app := m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " Live")
+		if m.maintenance.Down {
+			app = m.theme.WarningStyle.Render(m.theme.Symbols.Warning + " In maintenance")
+			if m.maintenance.Secret != "" {
+				app += m.theme.DescriptionStyle.Render(" (bypass: /" + m.maintenance.Secret + ")")
+			}
+		}
+		info = append(info, m.theme.Label.Render("App:         ")+app)
+	}
+
+	// Health check
+	health := m.theme.DescriptionStyle.Render("Not configured")
+	if m.healthResult != nil {
+		checked := m.theme.DescriptionStyle.Render(" (checked " + m.healthResult.CheckedAt.Format("2006-01-02 15:04") + ")")
+		if m.healthResult.OK {
+			health = m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark+" Up, "+m.healthResult.Summary()) + checked
+		} else {
+			health = m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" Down: "+m.healthResult.Summary()) + checked
+		}
+	} else if m.healthCheck != nil {
+		health = m.theme.DescriptionStyle.Render("Not checked yet (" + m.healthCheck.URL + ")")
+	}
+	info = append(info, m.theme.Label.Render("Health:      ")+health)
+
+	// Notes preview
+	if preview := notesPreview(m.notes, 3); preview != "" {
+		info = append(info, "", m.theme.Label.Render("Notes:"), m.theme.DescriptionStyle.Render(preview))
+	}
+
 	siteInfo := lipgloss.JoinVertical(lipgloss.Left, info...)
 
 	// Actions menu
@@ -353,3 +913,19 @@ func (m SiteDetailsModel) View() string {
 		bordered,
 	)
 }
+
+// notesPreview returns the first non-empty lines of a site's notes
+func notesPreview(notes string, max int) string {
+	var lines []string
+	for _, line := range strings.Split(notes, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if len(lines) == max {
+			lines = append(lines, "  ...")
+			break
+		}
+		lines = append(lines, "  "+line)
+	}
+	return strings.Join(lines, "\n")
+}
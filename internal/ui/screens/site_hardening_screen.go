@@ -0,0 +1,199 @@
+package screens
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// siteHardeningInspectedMsg carries a fresh inspection of the site
+type siteHardeningInspectedMsg struct {
+	hardening system.SiteHardening
+}
+
+// SiteHardeningModel applies the production hardening one-shot to a site
+// and shows the checklist before and after
+type SiteHardeningModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	site       system.NginxSite
+	webUser    string
+	systemUser string
+
+	hardening system.SiteHardening
+	before    []system.HardeningCheck
+	after     []system.HardeningCheck
+	loading   bool
+	applied   bool // The script ran; the next inspection is the "after"
+
+	confirm    Confirmation
+	confirming bool
+}
+
+// NewSiteHardeningModel creates the hardening screen for a site
+func NewSiteHardeningModel(site system.NginxSite) SiteHardeningModel {
+	return SiteHardeningModel{
+		theme:      theme.DefaultTheme(),
+		site:       site,
+		webUser:    detectWebUser(),
+		systemUser: getGitSystemUser(),
+		loading:    true,
+	}
+}
+
+// Init inspects the site. Returning from the script keeps the model, so
+// this also produces the "after" checklist.
+func (m SiteHardeningModel) Init() tea.Cmd {
+	site, webUser, systemUser := m.site, m.webUser, m.systemUser
+	return func() tea.Msg {
+		return siteHardeningInspectedMsg{system.InspectSiteHardening(site, webUser, systemUser)}
+	}
+}
+
+// Update handles messages for the hardening screen
+func (m SiteHardeningModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case siteHardeningInspectedMsg:
+		m.loading = false
+		m.hardening = msg.hardening
+		if m.applied {
+			m.after = msg.hardening.Checks()
+		} else {
+			m.before = msg.hardening.Checks()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.confirming {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.confirm, result = m.confirm.Update(msg)
+			switch result {
+			case ConfirmAccepted:
+				m.confirming = false
+				m.applied = true
+				m.loading = true
+				script := m.hardening.Script()
+				description := "Hardening " + m.site.Name
+				return m, func() tea.Msg {
+					return ExecutionStartMsg{Command: "sudo bash -c " + system.ShellQuote(script), Description: description}
+				}
+			case ConfirmCancelled:
+				m.confirming = false
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			site := m.site
+			return m, func() tea.Msg {
+				return NavigateMsg{
+					Screen: ConfigEditorScreen,
+					Data: map[string]interface{}{
+						"action": "edit_nginx_site",
+						"site":   site,
+					},
+				}
+			}
+		case "r":
+			if !m.loading {
+				m.loading = true
+				return m, m.Init()
+			}
+		case "enter", "a":
+			if !m.loading && !m.applied {
+				m.confirm = NewConfirmation("harden", "Production Hardening",
+					fmt.Sprintf("Apply production hardening to %s?\n\n"+
+						"OPcache, Laravel caches, permissions, security headers, and a rate limit "+
+						"are applied. PHP-FPM and nginx configs are validated before reloading and "+
+						"rolled back if rejected.", m.site.Name), ConfirmWarning)
+				m.confirming = true
+			}
+		}
+	}
+	return m, nil
+}
+
+// View renders the hardening screen
+func (m SiteHardeningModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Production Hardening"),
+		m.theme.DescriptionStyle.Render(m.site.Name),
+		"",
+	}
+
+	switch {
+	case m.loading && len(m.before) == 0:
+		sections = append(sections, m.theme.InfoStyle.Render("Inspecting site..."))
+	case m.applied && m.after != nil:
+		sections = append(sections, m.theme.Label.Render(fmt.Sprintf("%-42s %-8s %s", "Check", "Before", "After")))
+		for i, after := range m.after {
+			status := m.renderStatus(m.before[i], false)
+			sections = append(sections, fmt.Sprintf("%-42s %s  %s", after.Title, status, m.renderStatus(after, true)))
+			if !after.Passed && !after.Skipped && after.Detail != "" {
+				sections = append(sections, m.theme.DescriptionStyle.Render("    "+after.Detail))
+			}
+		}
+	default:
+		for _, c := range m.before {
+			sections = append(sections, m.renderStatus(c, true)+" "+c.Title)
+			if c.Detail != "" {
+				sections = append(sections, m.theme.DescriptionStyle.Render("    "+c.Detail))
+			}
+		}
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := "r: Re-check" + bullet + "Esc: Back"
+	if !m.applied {
+		help = "Enter: Apply hardening" + bullet + help
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// renderStatus renders a check's status as a symbol, or as a word for the
+// Before column of the comparison
+func (m SiteHardeningModel) renderStatus(c system.HardeningCheck, symbolOnly bool) string {
+	var symbol, word string
+	var style lipgloss.Style
+	switch {
+	case c.Skipped:
+		symbol, word, style = "-", "n/a", m.theme.DescriptionStyle
+	case c.Passed:
+		symbol, word, style = m.theme.Symbols.CheckMark, "pass", m.theme.SuccessStyle
+	case c.Manual:
+		symbol, word, style = m.theme.Symbols.Warning, "manual", m.theme.WarningStyle
+	default:
+		symbol, word, style = m.theme.Symbols.CrossMark, "fail", m.theme.ErrorStyle
+	}
+	if symbolOnly {
+		return style.Render(symbol)
+	}
+	return style.Render(fmt.Sprintf("%-6s", word))
+}
@@ -0,0 +1,403 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// siteHeadersInspectedMsg carries a fresh read of the site's headers
+type siteHeadersInspectedMsg struct {
+	headers system.SiteHeaders
+}
+
+// SiteHeadersModel edits a site's security headers and CSP and grades
+// them the way Mozilla Observatory does
+type SiteHeadersModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	site    system.NginxSite
+	headers system.SiteHeaders
+	pending system.SecurityHeaders
+	loading bool
+
+	// "" for the score, "form", or "preview"
+	mode string
+	form *huh.Form
+	err  error
+}
+
+// NewSiteHeadersModel creates the security headers screen for a site
+func NewSiteHeadersModel(site system.NginxSite) SiteHeadersModel {
+	return SiteHeadersModel{
+		theme:   theme.DefaultTheme(),
+		site:    site,
+		loading: true,
+	}
+}
+
+// Init reads the headers the site sends. Returning from the script keeps
+// the model, so this also shows the new score.
+func (m SiteHeadersModel) Init() tea.Cmd {
+	site := m.site
+	return func() tea.Msg {
+		return siteHeadersInspectedMsg{system.InspectSiteHeaders(site)}
+	}
+}
+
+// Update handles messages for the security headers screen
+func (m SiteHeadersModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case siteHeadersInspectedMsg:
+		m.loading = false
+		m.headers = msg.headers
+		m.pending = msg.headers.Current()
+		m.mode = ""
+		return m, nil
+	}
+
+	if m.mode == "form" {
+		return m.updateForm(msg)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		if m.mode == "preview" {
+			m.mode = ""
+			return m, nil
+		}
+		site := m.site
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: ConfigEditorScreen,
+				Data: map[string]interface{}{
+					"action": "edit_nginx_site",
+					"site":   site,
+				},
+			}
+		}
+	case "r":
+		if !m.loading {
+			m.loading = true
+			m.err = nil
+			return m, m.Init()
+		}
+	case "e":
+		if !m.loading {
+			return m.openForm()
+		}
+	case "d":
+		if !m.loading && m.mode == "" {
+			m.pending = system.DefaultSecurityHeaders()
+			m.err = nil
+			m.mode = "preview"
+		}
+	case "enter":
+		if m.loading {
+			return m, nil
+		}
+		if m.mode == "" {
+			return m.openForm()
+		}
+		m.loading = true
+		script := m.headers.Script(m.pending)
+		description := "Security headers for " + m.site.Name
+		return m, func() tea.Msg {
+			return ExecutionStartMsg{Command: "sudo bash -c " + system.ShellQuote(script), Description: description}
+		}
+	}
+	return m, nil
+}
+
+// openForm shows the headers form filled with the pending headers
+func (m SiteHeadersModel) openForm() (tea.Model, tea.Cmd) {
+	h := m.pending
+	hstsMaxAge := strconv.Itoa(h.HSTSMaxAge)
+	hstsSubdomains := h.HSTSSubdomains
+	hstsPreload := h.HSTSPreload
+	frameOptions := h.FrameOptions
+	noSniff := h.NoSniff
+	referrerPolicy := h.ReferrerPolicy
+	permissionsPolicy := h.PermissionsPolicy
+	cspEnabled := h.CSP.Enabled
+	cspReportOnly := h.CSP.ReportOnly
+	defaultSrc, scriptSrc, styleSrc := h.CSP.DefaultSrc, h.CSP.ScriptSrc, h.CSP.StyleSrc
+	imgSrc, connectSrc, fontSrc := h.CSP.ImgSrc, h.CSP.ConnectSrc, h.CSP.FontSrc
+	frameAncestors := h.CSP.FrameAncestors
+	upgradeInsecure := h.CSP.UpgradeInsecure
+	extra := h.CSP.Extra
+
+	referrerOptions := []huh.Option[string]{huh.NewOption("Not sent", "")}
+	for _, policy := range system.ReferrerPolicies {
+		referrerOptions = append(referrerOptions, huh.NewOption(policy, policy))
+	}
+
+	source := func(key, title string, value *string) *huh.Input {
+		return huh.NewInput().Key(key).Title(title).Value(value)
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("hstsMaxAge").
+				Title("HSTS Max-Age").
+				Description("Seconds browsers only use HTTPS; 31536000 is a year, 0 leaves HSTS out").
+				Validate(func(s string) error {
+					if n, err := strconv.Atoi(strings.TrimSpace(s)); err != nil || n < 0 {
+						return fmt.Errorf("enter a number of seconds")
+					}
+					return nil
+				}).
+				Value(&hstsMaxAge),
+
+			huh.NewConfirm().
+				Key("hstsSubdomains").
+				Title("HSTS includeSubDomains").
+				Description("Every subdomain must serve HTTPS too").
+				Value(&hstsSubdomains),
+
+			huh.NewConfirm().
+				Key("hstsPreload").
+				Title("HSTS Preload").
+				Description("Allows submitting the domain to browser preload lists; hard to undo").
+				Value(&hstsPreload),
+
+			huh.NewSelect[string]().
+				Key("frameOptions").
+				Title("X-Frame-Options").
+				Options(
+					huh.NewOption("SAMEORIGIN - only this site may frame pages", "SAMEORIGIN"),
+					huh.NewOption("DENY - no site may frame pages", "DENY"),
+					huh.NewOption("Not sent", ""),
+				).
+				Value(&frameOptions),
+
+			huh.NewConfirm().
+				Key("noSniff").
+				Title("X-Content-Type-Options: nosniff").
+				Value(&noSniff),
+
+			huh.NewSelect[string]().
+				Key("referrerPolicy").
+				Title("Referrer-Policy").
+				Options(referrerOptions...).
+				Value(&referrerPolicy),
+
+			huh.NewInput().
+				Key("permissionsPolicy").
+				Title("Permissions-Policy").
+				Description("Browser features the site may use; empty leaves it out").
+				Value(&permissionsPolicy),
+
+			huh.NewConfirm().
+				Key("cspEnabled").
+				Title("Content-Security-Policy").
+				Description("Test a new policy in report-only mode first; a wrong policy breaks the site").
+				Value(&cspEnabled),
+		).Title("Headers"),
+
+		huh.NewGroup(
+			huh.NewConfirm().
+				Key("cspReportOnly").
+				Title("Report Only").
+				Description("Browsers report violations in the console without blocking").
+				Value(&cspReportOnly),
+			source("defaultSrc", "default-src", &defaultSrc),
+			source("scriptSrc", "script-src", &scriptSrc),
+			source("styleSrc", "style-src", &styleSrc),
+			source("imgSrc", "img-src", &imgSrc),
+			source("connectSrc", "connect-src", &connectSrc),
+			source("fontSrc", "font-src", &fontSrc),
+			source("frameAncestors", "frame-ancestors", &frameAncestors),
+			huh.NewConfirm().
+				Key("upgradeInsecure").
+				Title("upgrade-insecure-requests").
+				Value(&upgradeInsecure),
+			huh.NewInput().
+				Key("extra").
+				Title("Other Directives").
+				Description("Kept as written, separated by semicolons").
+				Value(&extra),
+		).Title("Content-Security-Policy").WithHideFunc(func() bool {
+			return !cspEnabled
+		}),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+	m.mode = "form"
+	m.err = nil
+	return m, m.form.Init()
+}
+
+// updateForm passes messages to the headers form and previews the result
+func (m SiteHeadersModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = ""
+			m.form = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	switch m.form.State {
+	case huh.StateCompleted:
+		h := m.formHeaders()
+		m.form = nil
+		if err := h.Validate(); err != nil {
+			m.err = err
+			m.mode = ""
+			return m, nil
+		}
+		m.pending = h
+		m.mode = "preview"
+		return m, nil
+	case huh.StateAborted:
+		m.mode = ""
+		m.form = nil
+		return m, nil
+	}
+	return m, cmd
+}
+
+// formHeaders reads the completed form
+func (m SiteHeadersModel) formHeaders() system.SecurityHeaders {
+	f := m.form
+	maxAge, _ := strconv.Atoi(strings.TrimSpace(f.GetString("hstsMaxAge")))
+	field := func(key string) string {
+		return strings.TrimSpace(f.GetString(key))
+	}
+	return system.SecurityHeaders{
+		HSTSMaxAge:        maxAge,
+		HSTSSubdomains:    f.GetBool("hstsSubdomains"),
+		HSTSPreload:       f.GetBool("hstsPreload"),
+		FrameOptions:      f.GetString("frameOptions"),
+		NoSniff:           f.GetBool("noSniff"),
+		ReferrerPolicy:    f.GetString("referrerPolicy"),
+		PermissionsPolicy: field("permissionsPolicy"),
+		CSP: system.ContentSecurityPolicy{
+			Enabled:         f.GetBool("cspEnabled"),
+			ReportOnly:      f.GetBool("cspReportOnly"),
+			DefaultSrc:      field("defaultSrc"),
+			ScriptSrc:       field("scriptSrc"),
+			StyleSrc:        field("styleSrc"),
+			ImgSrc:          field("imgSrc"),
+			ConnectSrc:      field("connectSrc"),
+			FontSrc:         field("fontSrc"),
+			FrameAncestors:  field("frameAncestors"),
+			UpgradeInsecure: f.GetBool("upgradeInsecure"),
+			Extra:           strings.Trim(field("extra"), "; "),
+		},
+	}
+}
+
+// View renders the security headers screen
+func (m SiteHeadersModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Security Headers"),
+		m.theme.DescriptionStyle.Render(m.site.Name),
+		"",
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := ""
+	switch {
+	case m.mode == "form":
+		sections = append(sections, m.form.View())
+		help = "Tab: Next" + bullet + "Enter: Submit" + bullet + "Esc: Cancel"
+
+	case m.loading:
+		sections = append(sections, m.theme.InfoStyle.Render("Reading headers..."))
+
+	case m.mode == "preview":
+		before, after := m.headers.Score(), m.headers.ScoreWith(m.pending)
+		sections = append(sections,
+			m.theme.Label.Render(system.SecurityHeadersPath(m.site.Name)),
+			m.theme.DescriptionStyle.Render(strings.TrimRight(m.pending.Include(), "\n")),
+			"",
+			fmt.Sprintf("Score: %s %s %s", m.renderGrade(before), m.theme.Symbols.Cursor, m.renderGrade(after)),
+		)
+		sections = append(sections, m.renderChecks(after)...)
+		if !m.headers.Managed {
+			sections = append(sections, "", m.theme.InfoStyle.Render(m.theme.Symbols.Info+" The site config gets an include for this file"))
+		}
+		help = "Enter: Apply" + bullet + "e: Edit" + bullet + "Esc: Back"
+
+	default:
+		score := m.headers.Score()
+		sections = append(sections, "Score: "+m.renderGrade(score), "")
+		sections = append(sections, m.renderChecks(score)...)
+		if m.headers.Managed {
+			sections = append(sections, "", m.theme.DescriptionStyle.Render("Managed in "+system.SecurityHeadersPath(m.site.Name)))
+		}
+		help = "Enter/e: Edit" + bullet + "d: Recommended headers" + bullet + "r: Re-check" + bullet + "Esc: Back"
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// renderGrade renders a grade and score, coloured by grade
+func (m SiteHeadersModel) renderGrade(s system.HeadersScore) string {
+	style := m.theme.ErrorStyle
+	switch s.Grade[0] {
+	case 'A':
+		style = m.theme.SuccessStyle
+	case 'B', 'C':
+		style = m.theme.WarningStyle
+	}
+	return style.Render(fmt.Sprintf("%s (%d/100)", s.Grade, s.Score))
+}
+
+// renderChecks renders the checks of a score with their modifiers
+func (m SiteHeadersModel) renderChecks(s system.HeadersScore) []string {
+	var lines []string
+	for _, c := range s.Checks {
+		symbol := m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark)
+		if c.Passed {
+			symbol = m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark)
+		}
+		modifier := ""
+		if c.Modifier != 0 {
+			modifier = fmt.Sprintf("%+d", c.Modifier)
+		}
+		lines = append(lines, fmt.Sprintf("%s %-36s %4s  %s", symbol, c.Title, modifier, m.theme.DescriptionStyle.Render(c.Detail)))
+	}
+	return lines
+}
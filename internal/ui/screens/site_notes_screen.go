@@ -0,0 +1,256 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// SiteNotesModel shows and edits the notes and runbooks kept for a site
+type SiteNotesModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+	site   system.NginxSite
+
+	notes       string
+	attachments []system.SiteAttachment
+	path        string
+
+	mode     string // "view", "edit", "attachment"
+	editor   TextEditor
+	scroll   int
+	viewing  string // Content of the attachment being viewed
+	viewName string
+
+	err     error
+	success string
+}
+
+// NewSiteNotesModel creates a new site notes model
+func NewSiteNotesModel(site system.NginxSite) SiteNotesModel {
+	m := SiteNotesModel{
+		theme: theme.DefaultTheme(),
+		site:  site,
+		mode:  "view",
+	}
+	m.path, m.err = system.SiteNotesPath(site.Name)
+	if m.err == nil {
+		m.notes, m.err = system.LoadSiteNotes(site.Name)
+	}
+	if m.err == nil {
+		m.attachments, m.err = system.ListSiteAttachments(site.Name)
+	}
+	return m
+}
+
+// Init initializes the site notes screen
+func (m SiteNotesModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the site notes screen
+func (m SiteNotesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case "edit":
+			return m.updateEdit(msg)
+		case "attachment":
+			return m.updateAttachment(msg)
+		}
+		return m.updateView(msg)
+	}
+
+	return m, nil
+}
+
+// updateView handles keys while reading the notes
+func (m SiteNotesModel) updateView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+
+	case "esc", "backspace":
+		return m, m.backToSite()
+
+	case "e", "enter":
+		m.err = nil
+		m.success = ""
+		m.editor = NewTextEditor(m.notes)
+		m.mode = "edit"
+
+	case "up", "k":
+		if m.scroll > 0 {
+			m.scroll--
+		}
+
+	case "down", "j":
+		if m.scroll < strings.Count(m.notes, "\n") {
+			m.scroll++
+		}
+
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		i := int(msg.String()[0] - '1')
+		if i < len(m.attachments) {
+			data, err := system.ReadFile(m.attachments[i].Path)
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.viewing = string(data)
+			m.viewName = m.attachments[i].Name
+			m.scroll = 0
+			m.mode = "attachment"
+		}
+	}
+	return m, nil
+}
+
+// updateEdit handles keys while editing the notes
+func (m SiteNotesModel) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.mode = "view"
+		return m, nil
+
+	case "ctrl+s":
+		notes := strings.TrimRight(m.editor.Value(), "\n ")
+		if err := system.SaveSiteNotes(m.site.Name, notes); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.notes = notes
+		m.err = nil
+		m.success = fmt.Sprintf("%s Notes saved to %s", m.theme.Symbols.CheckMark, m.path)
+		m.scroll = 0
+		m.mode = "view"
+		return m, nil
+	}
+
+	m.editor = m.editor.Update(msg)
+	return m, nil
+}
+
+// updateAttachment handles keys while viewing an attachment
+func (m SiteNotesModel) updateAttachment(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.mode = "view"
+		m.scroll = 0
+	case "up", "k":
+		if m.scroll > 0 {
+			m.scroll--
+		}
+	case "down", "j":
+		if m.scroll < strings.Count(m.viewing, "\n") {
+			m.scroll++
+		}
+	}
+	return m, nil
+}
+
+// backToSite returns to the site details screen
+func (m SiteNotesModel) backToSite() tea.Cmd {
+	return func() tea.Msg {
+		return NavigateMsg{
+			Screen: ConfigEditorScreen,
+			Data: map[string]interface{}{
+				"action": "edit_nginx_site",
+				"site":   m.site,
+			},
+		}
+	}
+}
+
+// visibleLines is how many lines of text fit in the box
+func (m SiteNotesModel) visibleLines() int {
+	if n := m.height - 16; n > 3 {
+		return n
+	}
+	return 3
+}
+
+// window returns the visible slice of text starting at the scroll offset
+func (m SiteNotesModel) window(text string) string {
+	lines := strings.Split(text, "\n")
+	start := m.scroll
+	if start >= len(lines) {
+		start = len(lines) - 1
+	}
+	end := start + m.visibleLines()
+	if end > len(lines) {
+		end = len(lines)
+	}
+	return strings.Join(lines[start:end], "\n")
+}
+
+// View renders the site notes screen
+func (m SiteNotesModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	var header, body, help string
+	switch m.mode {
+	case "edit":
+		header = m.theme.Title.Render(fmt.Sprintf("Edit Notes: %s", m.site.Name))
+		body = m.editor.View(m.theme, m.visibleLines())
+		help = m.theme.Help.Render("Ctrl+S: Save " + m.theme.Symbols.Bullet + " Esc: Cancel")
+
+	case "attachment":
+		header = m.theme.Title.Render(fmt.Sprintf("%s: %s", m.site.Name, m.viewName))
+		body = m.theme.MenuItem.Render(m.window(m.viewing))
+		help = m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Scroll " + m.theme.Symbols.Bullet + " Esc: Back to notes")
+
+	default:
+		header = m.theme.Title.Render(fmt.Sprintf("Site Notes: %s", m.site.Name))
+		if strings.TrimSpace(m.notes) == "" {
+			body = m.theme.DescriptionStyle.Render("No notes yet. Press e to record how this site is run:\ncache clears, deploy quirks, client contacts, ...")
+		} else {
+			body = m.theme.MenuItem.Render(m.window(m.notes))
+		}
+
+		if len(m.attachments) > 0 {
+			var items []string
+			for i, a := range m.attachments {
+				if i >= 9 {
+					break
+				}
+				items = append(items, m.theme.KeyStyle.Render(fmt.Sprintf("  %d ", i+1))+m.theme.MenuItem.Render(a.Name)+" "+m.theme.DescriptionStyle.Render(system.FormatBytes(uint64(a.Size))))
+			}
+			body = lipgloss.JoinVertical(lipgloss.Left, body, "", m.theme.Subtitle.Render("Runbooks & Attachments:"), lipgloss.JoinVertical(lipgloss.Left, items...))
+		}
+		help = m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Scroll " + m.theme.Symbols.Bullet + " e: Edit " + m.theme.Symbols.Bullet + " 1-9: Open attachment " + m.theme.Symbols.Bullet + " Esc: Back")
+	}
+
+	sections := []string{header, "", body}
+	if m.mode == "view" && m.path != "" {
+		sections = append(sections, "", m.theme.DescriptionStyle.Render("Stored in "+m.path+" "+m.theme.Symbols.Bullet+" add runbooks to the same directory"))
+	}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.success != "" && m.mode == "view" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	sections = append(sections, "", help)
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -0,0 +1,248 @@
+package screens
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// siteProtocolsLoadedMsg carries the nginx build and the site's settings
+type siteProtocolsLoadedMsg struct {
+	build     system.NginxBuild
+	protocols system.NginxProtocols
+	err       error
+}
+
+// protocolToggle is one row of the protocols screen
+type protocolToggle struct {
+	title     string
+	detail    string
+	value     func(*system.NginxProtocols) *bool
+	supported func(system.NginxBuild) bool
+}
+
+var protocolToggles = []protocolToggle{
+	{"HTTP/2", "Multiplexed requests over TLS",
+		func(p *system.NginxProtocols) *bool { return &p.HTTP2 },
+		func(b system.NginxBuild) bool { return b.HTTP2 }},
+	{"HTTP/3 (QUIC)", "UDP transport; needs port 443/udp open in the firewall",
+		func(p *system.NginxProtocols) *bool { return &p.HTTP3 },
+		func(b system.NginxBuild) bool { return b.HTTP3 }},
+	{"Gzip", "Compress text responses",
+		func(p *system.NginxProtocols) *bool { return &p.Gzip },
+		func(b system.NginxBuild) bool { return b.Gzip }},
+	{"Brotli", "Smaller than gzip for browsers that support it",
+		func(p *system.NginxProtocols) *bool { return &p.Brotli },
+		func(b system.NginxBuild) bool { return b.Brotli }},
+}
+
+// SiteProtocolsModel toggles HTTP/2, HTTP/3, gzip, and brotli for a site
+type SiteProtocolsModel struct {
+	theme        *theme.Theme
+	width        int
+	height       int
+	nginxManager *system.NginxManager
+	site         system.NginxSite
+
+	build     system.NginxBuild
+	current   system.NginxProtocols
+	pending   system.NginxProtocols
+	cursor    int
+	loading   bool
+	review    ConfigReview
+	reviewing bool
+	err       error
+	success   string
+}
+
+// NewSiteProtocolsModel creates the protocols screen for a site
+func NewSiteProtocolsModel(site system.NginxSite) SiteProtocolsModel {
+	return SiteProtocolsModel{
+		theme:        theme.DefaultTheme(),
+		nginxManager: system.NewNginxManager(),
+		site:         site,
+		loading:      true,
+	}
+}
+
+// Init detects the nginx build and reads the site's settings
+func (m SiteProtocolsModel) Init() tea.Cmd {
+	nm, name := m.nginxManager, m.site.Name
+	return func() tea.Msg {
+		build, err := nm.DetectNginxBuild()
+		if err != nil {
+			return siteProtocolsLoadedMsg{err: err}
+		}
+		protocols, err := nm.SiteProtocols(name)
+		return siteProtocolsLoadedMsg{build: build, protocols: protocols, err: err}
+	}
+}
+
+// Update handles messages for the protocols screen
+func (m SiteProtocolsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case siteProtocolsLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.build = msg.build
+		m.current, m.pending = msg.protocols, msg.protocols
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.reviewing {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.review, result = m.review.Update(msg, m.height)
+			switch result {
+			case ConfirmAccepted:
+				m.reviewing = false
+				return m.apply()
+			case ConfirmCancelled:
+				m.reviewing = false
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			site := m.site
+			return m, func() tea.Msg {
+				return NavigateMsg{
+					Screen: ConfigEditorScreen,
+					Data: map[string]interface{}{
+						"action": "edit_nginx_site",
+						"site":   site,
+					},
+				}
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(protocolToggles)-1 {
+				m.cursor++
+			}
+		case " ", "x":
+			if m.loading {
+				break
+			}
+			toggle := protocolToggles[m.cursor]
+			value := toggle.value(&m.pending)
+			// Unsupported features can still be turned off
+			if *value || toggle.supported(m.build) {
+				*value = !*value
+				m.err = nil
+				m.success = ""
+			}
+		case "enter", "s":
+			if m.loading {
+				break
+			}
+			if m.pending == m.current {
+				m.success = m.theme.Symbols.Info + " No changes"
+				break
+			}
+			change, err := m.nginxManager.PlanSiteProtocols(m.site.Name, m.pending, m.build)
+			if err != nil {
+				m.err = err
+				break
+			}
+			m.review = NewConfigReview("protocols", m.nginxManager, change)
+			m.reviewing = true
+		}
+	}
+	return m, nil
+}
+
+// apply writes the reviewed config and reloads nginx
+func (m SiteProtocolsModel) apply() (SiteProtocolsModel, tea.Cmd) {
+	if err := m.nginxManager.ApplyChange(m.review.Change); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := m.nginxManager.TestConfig(); err != nil {
+		m.err = fmt.Errorf("config written but test failed: %w", err)
+		return m, nil
+	}
+	if err := m.nginxManager.ReloadNginx(); err != nil {
+		m.err = fmt.Errorf("config written but reload failed: %w", err)
+		return m, nil
+	}
+	m.current = m.pending
+	m.success = m.theme.Symbols.CheckMark + " Settings applied and nginx reloaded"
+	return m, nil
+}
+
+// View renders the protocols screen
+func (m SiteProtocolsModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.reviewing {
+		return m.review.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Protocols & Compression"),
+		m.theme.DescriptionStyle.Render(m.site.Name),
+		"",
+	}
+
+	if m.loading {
+		sections = append(sections, m.theme.InfoStyle.Render("Checking nginx build..."))
+	} else {
+		if m.build.Version != "" {
+			sections = append(sections, m.theme.DescriptionStyle.Render("nginx "+m.build.Version), "")
+		}
+		for i, toggle := range protocolToggles {
+			box := "[ ]"
+			if *toggle.value(&m.pending) {
+				box = "[x]"
+			}
+			line := fmt.Sprintf("%s %s", box, toggle.title)
+			if *toggle.value(&m.pending) != *toggle.value(&m.current) {
+				line += " *"
+			}
+			cursor := "  "
+			style := m.theme.MenuItem
+			if i == m.cursor {
+				cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+				style = m.theme.SelectedItem
+			}
+			sections = append(sections, cursor+style.Render(line))
+
+			detail := toggle.detail
+			if !toggle.supported(m.build) {
+				detail = "Not supported by this nginx build"
+			}
+			sections = append(sections, m.theme.DescriptionStyle.Render("      "+detail))
+		}
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections = append(sections, "", m.theme.Help.Render("Space: Toggle"+bullet+"Enter: Review & apply"+bullet+"Esc: Back"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -0,0 +1,454 @@
+package screens
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// SiteRateLimitsModel limits request rates per path and blocks unwanted
+// user agents for a site
+type SiteRateLimitsModel struct {
+	theme        *theme.Theme
+	width        int
+	height       int
+	nginxManager *system.NginxManager
+	site         system.NginxSite
+
+	current system.SiteRateLimits
+	pending system.SiteRateLimits
+	agents  []string
+	cursor  int
+
+	// "" for the rule list, "rule_form", "agents_form", or "review"
+	mode    string
+	form    *huh.Form
+	editing int // Index of the rule being edited, -1 when adding
+	review  ConfigReview
+
+	err     error
+	success string
+}
+
+// NewSiteRateLimitsModel creates the rate limiting screen for a site
+func NewSiteRateLimitsModel(site system.NginxSite) SiteRateLimitsModel {
+	m := SiteRateLimitsModel{
+		theme:        theme.DefaultTheme(),
+		nginxManager: system.NewNginxManager(),
+		site:         site,
+	}
+	m.load()
+	return m
+}
+
+// load reads the site's limits and the shared blocklist
+func (m *SiteRateLimitsModel) load() {
+	agents, err := system.LoadBotBlocklist()
+	if err != nil {
+		m.err = err
+		agents = system.DefaultBlockedAgents
+	}
+	m.agents = agents
+
+	limits, err := m.nginxManager.SiteRateLimits(m.site.Name)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.current = limits
+	m.pending = system.SiteRateLimits{Rules: slices.Clone(limits.Rules), BlockBots: limits.BlockBots}
+}
+
+// Init initializes the rate limiting screen
+func (m SiteRateLimitsModel) Init() tea.Cmd {
+	return nil
+}
+
+// changed reports whether the pending limits differ from the site's
+func (m SiteRateLimitsModel) changed() bool {
+	return m.current.BlockBots != m.pending.BlockBots || !slices.Equal(m.current.Rules, m.pending.Rules)
+}
+
+// Update handles messages for the rate limiting screen
+func (m SiteRateLimitsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	switch m.mode {
+	case "rule_form", "agents_form":
+		return m.updateForm(msg)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.mode == "review" {
+		if keyMsg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		var result ConfirmResult
+		m.review, result = m.review.Update(keyMsg, m.height)
+		switch result {
+		case ConfirmAccepted:
+			m.mode = ""
+			return m.apply()
+		case ConfirmCancelled:
+			m.mode = ""
+		}
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		site := m.site
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: ConfigEditorScreen,
+				Data: map[string]interface{}{
+					"action": "edit_nginx_site",
+					"site":   site,
+				},
+			}
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.pending.Rules)-1 {
+			m.cursor++
+		}
+	case "a":
+		m.err, m.success = nil, ""
+		return m.openRuleForm(-1)
+	case "e", "enter":
+		if len(m.pending.Rules) > 0 {
+			m.err, m.success = nil, ""
+			return m.openRuleForm(m.cursor)
+		}
+	case "d", "delete":
+		if len(m.pending.Rules) > 0 {
+			m.pending.Rules = slices.Delete(m.pending.Rules, m.cursor, m.cursor+1)
+			if m.cursor >= len(m.pending.Rules) && m.cursor > 0 {
+				m.cursor--
+			}
+			m.err, m.success = nil, ""
+		}
+	case "b":
+		m.pending.BlockBots = !m.pending.BlockBots
+		m.err, m.success = nil, ""
+	case "u":
+		m.err, m.success = nil, ""
+		return m.openAgentsForm()
+	case "s":
+		m.err, m.success = nil, ""
+		if !m.changed() {
+			m.success = m.theme.Symbols.Info + " No changes"
+			break
+		}
+		// nginx -t needs the included blocklist to exist
+		if m.pending.BlockBots {
+			if err := system.SaveBotBlocklist(m.agents); err != nil {
+				m.err = err
+				break
+			}
+		}
+		change, err := m.nginxManager.PlanSiteRateLimits(m.site.Name, m.pending)
+		if err != nil {
+			m.err = err
+			break
+		}
+		m.review = NewConfigReview("rate limits", m.nginxManager, change)
+		m.mode = "review"
+	}
+	return m, nil
+}
+
+// openRuleForm shows the form adding a rule, or editing rule index
+func (m SiteRateLimitsModel) openRuleForm(index int) (tea.Model, tea.Cmd) {
+	rule := system.RateLimitRule{Path: "/login", Rate: 5, Per: "m", Burst: 5, NoDelay: true}
+	if index >= 0 {
+		rule = m.pending.Rules[index]
+	}
+	path := rule.Path
+	rate := strconv.Itoa(rule.Rate)
+	per := rule.Per
+	burst := strconv.Itoa(rule.Burst)
+	noDelay := rule.NoDelay
+
+	number := func(s string) error {
+		if _, err := strconv.Atoi(strings.TrimSpace(s)); err != nil {
+			return fmt.Errorf("enter a whole number")
+		}
+		return nil
+	}
+
+	m.editing = index
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("path").
+				Title("Path").
+				Description("/ limits the whole site; a prefix such as /login or /api limits those requests").
+				Validate(func(s string) error {
+					s = strings.TrimSpace(s)
+					for i, r := range m.pending.Rules {
+						if r.Path == s && i != index {
+							return fmt.Errorf("%s already has a limit", s)
+						}
+					}
+					return system.RateLimitRule{Path: s, Rate: 1, Per: "s"}.Validate()
+				}).
+				Value(&path),
+
+			huh.NewInput().
+				Key("rate").
+				Title("Requests").
+				Description("Allowed per client address").
+				Validate(number).
+				Value(&rate),
+
+			huh.NewSelect[string]().
+				Key("per").
+				Title("Per").
+				Options(
+					huh.NewOption("Second", "s"),
+					huh.NewOption("Minute", "m"),
+				).
+				Value(&per),
+
+			huh.NewInput().
+				Key("burst").
+				Title("Burst").
+				Description("Extra requests accepted over the rate before clients get 429").
+				Validate(number).
+				Value(&burst),
+
+			huh.NewConfirm().
+				Key("noDelay").
+				Title("No Delay").
+				Description("Serve the burst at once instead of slowing it down to the rate").
+				Value(&noDelay),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+	m.mode = "rule_form"
+	return m, m.form.Init()
+}
+
+// openAgentsForm shows the shared user-agent blocklist, one per line
+func (m SiteRateLimitsModel) openAgentsForm() (tea.Model, tea.Cmd) {
+	agents := strings.Join(m.agents, "\n")
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Key("agents").
+				Title("Blocked User Agents").
+				Description("One per line, matched anywhere in the User-Agent without regard to case. Shared by every site that blocks bots.").
+				Lines(12).
+				Validate(func(s string) error {
+					return system.ValidateBlockedAgents(splitAgents(s))
+				}).
+				Value(&agents),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+	m.mode = "agents_form"
+	return m, m.form.Init()
+}
+
+// splitAgents reads the blocklist text area
+func splitAgents(s string) []string {
+	var agents []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			agents = append(agents, line)
+		}
+	}
+	return agents
+}
+
+// updateForm passes messages to the rule or blocklist form
+func (m SiteRateLimitsModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = ""
+			m.form = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	switch m.form.State {
+	case huh.StateCompleted:
+		m.err, m.success = nil, ""
+		if m.mode == "rule_form" {
+			m.saveRule()
+		} else {
+			m.saveAgents()
+		}
+		m.mode = ""
+		m.form = nil
+		return m, nil
+	case huh.StateAborted:
+		m.mode = ""
+		m.form = nil
+		return m, nil
+	}
+	return m, cmd
+}
+
+// saveRule stores the rule form in the pending limits
+func (m *SiteRateLimitsModel) saveRule() {
+	rate, _ := strconv.Atoi(strings.TrimSpace(m.form.GetString("rate")))
+	burst, _ := strconv.Atoi(strings.TrimSpace(m.form.GetString("burst")))
+	rule := system.RateLimitRule{
+		Path:    strings.TrimSpace(m.form.GetString("path")),
+		Rate:    rate,
+		Per:     m.form.GetString("per"),
+		Burst:   burst,
+		NoDelay: m.form.GetBool("noDelay"),
+	}
+	if err := rule.Validate(); err != nil {
+		m.err = err
+		return
+	}
+	if m.editing >= 0 {
+		m.pending.Rules[m.editing] = rule
+	} else {
+		m.pending.Rules = append(m.pending.Rules, rule)
+		m.cursor = len(m.pending.Rules) - 1
+	}
+	m.success = m.theme.Symbols.Info + " Press s to review and apply"
+}
+
+// saveAgents writes the blocklist. Sites already including it pick the
+// change up on the next reload.
+func (m *SiteRateLimitsModel) saveAgents() {
+	agents := splitAgents(m.form.GetString("agents"))
+	if err := system.SaveBotBlocklist(agents); err != nil {
+		m.err = err
+		return
+	}
+	m.agents = agents
+	if !m.current.BlockBots {
+		m.success = fmt.Sprintf("%s Saved %d blocked agent(s)", m.theme.Symbols.CheckMark, len(agents))
+		return
+	}
+	if err := m.nginxManager.TestConfig(); err != nil {
+		m.err = fmt.Errorf("blocklist written but test failed: %w", err)
+		return
+	}
+	if err := m.nginxManager.ReloadNginx(); err != nil {
+		m.err = fmt.Errorf("blocklist written but reload failed: %w", err)
+		return
+	}
+	m.success = fmt.Sprintf("%s Saved %d blocked agent(s) and reloaded nginx", m.theme.Symbols.CheckMark, len(agents))
+}
+
+// apply writes the reviewed config and reloads nginx
+func (m SiteRateLimitsModel) apply() (SiteRateLimitsModel, tea.Cmd) {
+	if err := m.nginxManager.ApplyChange(m.review.Change); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := m.nginxManager.TestConfig(); err != nil {
+		m.err = fmt.Errorf("config written but test failed: %w", err)
+		return m, nil
+	}
+	if err := m.nginxManager.ReloadNginx(); err != nil {
+		m.err = fmt.Errorf("config written but reload failed: %w", err)
+		return m, nil
+	}
+	m.current = system.SiteRateLimits{Rules: slices.Clone(m.pending.Rules), BlockBots: m.pending.BlockBots}
+	m.success = m.theme.Symbols.CheckMark + " Rate limits applied and nginx reloaded"
+	return m, nil
+}
+
+// View renders the rate limiting screen
+func (m SiteRateLimitsModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "review" {
+		return m.review.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Rate Limiting"),
+		m.theme.DescriptionStyle.Render(m.site.Name),
+		"",
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := ""
+	switch m.mode {
+	case "rule_form", "agents_form":
+		sections = append(sections, m.form.View())
+		help = "Tab: Next" + bullet + "Enter: Submit" + bullet + "Esc: Cancel"
+
+	default:
+		if len(m.pending.Rules) == 0 {
+			sections = append(sections, m.theme.DescriptionStyle.Render("No request limits"))
+		}
+		for i, rule := range m.pending.Rules {
+			line := fmt.Sprintf("%-24s %s", rule.Path, rule.Describe())
+			if i >= len(m.current.Rules) || rule != m.current.Rules[i] {
+				line += " *"
+			}
+			cursor := "  "
+			style := m.theme.MenuItem
+			if i == m.cursor {
+				cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+				style = m.theme.SelectedItem
+			}
+			sections = append(sections, cursor+style.Render(line))
+		}
+
+		bots := m.theme.DescriptionStyle.Render("off")
+		if m.pending.BlockBots {
+			bots = m.theme.SuccessStyle.Render("on")
+		}
+		if m.pending.BlockBots != m.current.BlockBots {
+			bots += " *"
+		}
+		sections = append(sections, "",
+			m.theme.Label.Render("Block bots: ")+bots,
+			m.theme.DescriptionStyle.Render(fmt.Sprintf("%d agent(s) in %s", len(m.agents), system.BotBlocklistPath)))
+		help = "a: Add" + bullet + "Enter: Edit" + bullet + "d: Delete" + bullet + "b: Block bots" + bullet + "u: Blocked agents" + bullet + "s: Review & apply" + bullet + "Esc: Back"
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -0,0 +1,400 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// siteStackRefreshInterval is how often node status is refreshed
+const siteStackRefreshInterval = 3 * time.Second
+
+// siteStackGeneration tags ticks so a reopened graph does not pick up the
+// refresh loop of a previous one
+var siteStackGeneration int
+
+// siteStackTickMsg triggers a status refresh
+type siteStackTickMsg struct {
+	generation int
+}
+
+// siteStackDataMsg carries refreshed node status
+type siteStackDataMsg struct {
+	generation int
+	stack      system.SiteStack
+}
+
+// SiteStackModel renders a site's service dependency graph with live status
+type SiteStackModel struct {
+	theme      *theme.Theme
+	width      int
+	height     int
+	generation int
+
+	stack   system.SiteStack
+	cursor  int
+	loading bool
+	updated time.Time
+
+	confirm    Confirmation
+	confirming bool
+	message    string
+}
+
+// NewSiteStackModel creates a dependency graph for a site
+func NewSiteStackModel(site system.NginxSite) SiteStackModel {
+	siteStackGeneration++
+	return SiteStackModel{
+		theme:      theme.DefaultTheme(),
+		generation: siteStackGeneration,
+		stack:      system.DiscoverSiteStack(site),
+		loading:    true,
+	}
+}
+
+// NewSiteStackRestartModel opens the dependency graph with the full-stack
+// restart waiting for confirmation
+func NewSiteStackRestartModel(site system.NginxSite) SiteStackModel {
+	m := NewSiteStackModel(site)
+	m.askRestart()
+	return m
+}
+
+// askRestart asks for confirmation before restarting the stack
+func (m *SiteStackModel) askRestart() {
+	var lines []string
+	for i, step := range m.stack.RestartSteps() {
+		lines = append(lines, fmt.Sprintf("%d. %s", i+1, step.Title))
+	}
+	m.confirm = NewConfirmation("restart_stack", "Restart Stack",
+		fmt.Sprintf("Restart %s in dependency order?\n\n%s\n\nDatabases and caches keep running.",
+			m.stack.Site.Name, strings.Join(lines, "\n")), ConfirmWarning)
+	m.confirming = true
+}
+
+// NewSiteStackDrainModel opens the dependency graph with the worker drain
+// waiting for confirmation
+func NewSiteStackDrainModel(site system.NginxSite) SiteStackModel {
+	m := NewSiteStackModel(site)
+	m.askDrain()
+	return m
+}
+
+// askDrain asks for confirmation before draining the queue workers
+func (m *SiteStackModel) askDrain() {
+	if !m.stack.HasWorkers() {
+		m.message = "No queue workers found for " + m.stack.Site.Name
+		return
+	}
+	var names []string
+	for _, n := range m.stack.Layer(system.StackLayerWorkers) {
+		names = append(names, n.Name)
+	}
+	m.confirm = NewConfirmation("drain", "Drain Workers",
+		fmt.Sprintf("Stop the queue workers of %s after their current jobs?\n\n%s\n\nWorkers still running after %d seconds are killed. They stay stopped until started again with S or Restart Stack.",
+			m.stack.Site.Name, strings.Join(names, "\n"), int(system.DefaultDrainTimeout.Seconds())), ConfirmWarning)
+	m.confirming = true
+}
+
+// Init starts refreshing node status
+func (m SiteStackModel) Init() tea.Cmd {
+	return m.refresh()
+}
+
+// refresh collects node status in the background
+func (m SiteStackModel) refresh() tea.Cmd {
+	stack := m.stack
+	stack.Nodes = append([]system.StackNode(nil), m.stack.Nodes...)
+	generation := m.generation
+	return func() tea.Msg {
+		stack.RefreshStatus()
+		return siteStackDataMsg{generation: generation, stack: stack}
+	}
+}
+
+// tick schedules the next refresh
+func (m SiteStackModel) tick() tea.Cmd {
+	generation := m.generation
+	return tea.Tick(siteStackRefreshInterval, func(time.Time) tea.Msg {
+		return siteStackTickMsg{generation: generation}
+	})
+}
+
+// Update handles messages for the dependency graph
+func (m SiteStackModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case siteStackDataMsg:
+		if msg.generation != m.generation {
+			return m, nil
+		}
+		m.stack = msg.stack
+		m.loading = false
+		m.updated = time.Now()
+		return m, m.tick()
+
+	case siteStackTickMsg:
+		if msg.generation != m.generation || m.loading {
+			return m, nil
+		}
+		m.loading = true
+		return m, m.refresh()
+
+	case tea.KeyMsg:
+		if m.confirming {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.confirm, result = m.confirm.Update(msg)
+			switch result {
+			case ConfirmAccepted:
+				m.confirming = false
+				name := m.stack.Site.Name
+				var script, description string
+				switch m.confirm.Action {
+				case "restart_stack":
+					script, description = m.stack.RestartScript(), "Restarting stack: "+name
+				case "drain":
+					script, description = m.stack.DrainScript(system.DefaultDrainTimeout), "Draining workers: "+name
+				case "start_workers":
+					script, description = m.stack.StartWorkersScript(), "Starting workers: "+name
+				}
+				return m, func() tea.Msg {
+					return ExecutionStartMsg{Command: script, Description: description}
+				}
+			case ConfirmCancelled:
+				m.confirming = false
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "R":
+			m.askRestart()
+		case "D":
+			m.askDrain()
+		case "S":
+			if !m.stack.HasWorkers() {
+				m.message = "No queue workers found for " + m.stack.Site.Name
+				return m, nil
+			}
+			m.confirm = NewConfirmation("start_workers", "Start Workers",
+				"Start the queue workers of "+m.stack.Site.Name+"?", ConfirmNormal)
+			m.confirming = true
+		case "esc", "backspace":
+			// Stop the refresh loop
+			m.generation = -1
+			return m, m.backToSite()
+		case "up", "k", "left", "h":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j", "right", "l":
+			if m.cursor < len(m.stack.Nodes)-1 {
+				m.cursor++
+			}
+		case "r":
+			if !m.loading {
+				m.loading = true
+				return m, m.refresh()
+			}
+		case "enter", " ":
+			if len(m.stack.Nodes) == 0 {
+				return m, nil
+			}
+			node := m.stack.Nodes[m.cursor]
+			if node.Kind != "systemd" {
+				return m, nil
+			}
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: LogViewerScreen, Data: map[string]interface{}{"unit": node.Unit}}
+			}
+		}
+	}
+	return m, nil
+}
+
+// backToSite returns to the site's details
+func (m SiteStackModel) backToSite() tea.Cmd {
+	site := m.stack.Site
+	return func() tea.Msg {
+		return NavigateMsg{
+			Screen: ConfigEditorScreen,
+			Data: map[string]interface{}{
+				"action": "edit_nginx_site",
+				"site":   site,
+			},
+		}
+	}
+}
+
+// nodeStyle returns the colour for a node's status
+func (m SiteStackModel) nodeStyle(n system.StackNode) lipgloss.Style {
+	switch {
+	case n.Status == "remote":
+		return lipgloss.NewStyle().Foreground(m.theme.Info)
+	case n.Up():
+		return lipgloss.NewStyle().Foreground(m.theme.Success)
+	case n.Down():
+		return lipgloss.NewStyle().Foreground(m.theme.Error)
+	}
+	return lipgloss.NewStyle().Foreground(m.theme.Warning)
+}
+
+// renderNode draws a node as a box using the terminal's border symbols
+func (m SiteStackModel) renderNode(n system.StackNode, selected bool) string {
+	s := m.theme.Symbols
+	status := n.Status
+	if status == "" {
+		status = "checking"
+	}
+	title := n.Name
+	sub := status
+	if n.Detail != "" {
+		sub += " " + s.Bullet + " " + n.Detail
+	}
+	width := lipgloss.Width(title)
+	if w := lipgloss.Width(sub); w > width {
+		width = w
+	}
+	if width > 28 {
+		width = 28
+		sub = truncateRunes(sub, width)
+		title = truncateRunes(title, width)
+	}
+
+	style := m.nodeStyle(n)
+	border := style
+	if selected {
+		border = border.Bold(true).Reverse(true)
+	}
+	pad := func(text string) string {
+		return text + strings.Repeat(" ", width-lipgloss.Width(text))
+	}
+	top := border.Render(s.CornerTL + strings.Repeat(s.BorderH, width+2) + s.CornerTR)
+	bottom := border.Render(s.CornerBL + strings.Repeat(s.BorderH, width+2) + s.CornerBR)
+	side := style.Render(s.BorderV)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		top,
+		side+" "+m.theme.Value.Render(pad(title))+" "+side,
+		side+" "+style.Render(pad(sub))+" "+side,
+		bottom,
+	)
+}
+
+// truncateRunes shortens text to n runes with an ellipsis
+func truncateRunes(text string, n int) string {
+	r := []rune(text)
+	if len(r) <= n {
+		return text
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// diagram renders the layers top to bottom with connectors between them
+func (m SiteStackModel) diagram() []string {
+	const labelWidth = 9
+	broken, isBroken := m.stack.BrokenLayer()
+	connector := strings.Repeat(" ", labelWidth+3) + m.theme.DescriptionStyle.Render(m.theme.Symbols.BorderV)
+	arrow := strings.Repeat(" ", labelWidth+3) + m.theme.DescriptionStyle.Render(m.theme.Symbols.ArrowDown)
+
+	var lines []string
+	index := 0
+	for _, layer := range system.StackLayers {
+		nodes := m.stack.Layer(layer)
+		if len(nodes) == 0 {
+			continue
+		}
+		if len(lines) > 0 {
+			lines = append(lines, connector, arrow)
+		}
+
+		labelStyle := m.theme.Label
+		if isBroken && layer == broken {
+			labelStyle = m.theme.Label.Foreground(m.theme.Error)
+		}
+		label := labelStyle.Render(fmt.Sprintf("%-*s", labelWidth, layer.String()))
+
+		// Wrap wide layers such as many workers onto several rows
+		var row []string
+		rowWidth := 0
+		flush := func() {
+			if len(row) == 0 {
+				return
+			}
+			lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Center, label, lipgloss.JoinHorizontal(lipgloss.Top, row...)))
+			label = strings.Repeat(" ", lipgloss.Width(label))
+			row, rowWidth = nil, 0
+		}
+		for _, n := range nodes {
+			box := m.renderNode(n, index == m.cursor)
+			index++
+			w := lipgloss.Width(box) + 1
+			if rowWidth+w > m.theme.AppWidth-labelWidth {
+				flush()
+			}
+			row = append(row, box, " ")
+			rowWidth += w
+		}
+		flush()
+	}
+	return lines
+}
+
+// View renders the dependency graph
+func (m SiteStackModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Dependency Graph: " + m.stack.Site.Name),
+		m.theme.DescriptionStyle.Render(m.stack.Site.Domain + "  " + m.stack.ProjectDir),
+		"",
+	}
+	sections = append(sections, m.diagram()...)
+	sections = append(sections, "")
+
+	if layer, broken := m.stack.BrokenLayer(); broken {
+		var down []string
+		for _, n := range m.stack.Layer(layer) {
+			if n.Down() {
+				down = append(down, n.Name+" "+n.Status)
+			}
+		}
+		sections = append(sections, m.theme.ErrorStyle.Render(fmt.Sprintf("%s %s layer is down: %s",
+			m.theme.Symbols.CrossMark, layer, strings.Join(down, ", "))))
+	} else if !m.updated.IsZero() {
+		sections = append(sections, m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark+" All layers are up"))
+	}
+	if !m.updated.IsZero() {
+		sections = append(sections, m.theme.DescriptionStyle.Render("Updated "+m.updated.Format("15:04:05")))
+	}
+	if m.message != "" {
+		sections = append(sections, m.theme.WarningStyle.Render(m.theme.Symbols.Info+" "+m.message))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Select" + bullet + "Enter: Logs" + bullet +
+		"r: Refresh" + bullet + "R: Restart stack" + bullet + "D: Drain workers" + bullet + "S: Start workers" + bullet + "Esc: Back"
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -0,0 +1,281 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+)
+
+// sshKeyListActions is the number of action rows above the key list
+const sshKeyListActions = 2
+
+// remoteKeysMsg carries the keys fetched from a GitHub or GitLab account
+type remoteKeysMsg struct {
+	provider string
+	account  string
+	keys     []system.RemoteKey
+	err      error
+}
+
+// keyProviderName returns the display name of a key provider
+func keyProviderName(provider string) string {
+	switch provider {
+	case "github":
+		return "GitHub"
+	case "gitlab":
+		return "GitLab"
+	}
+	return provider
+}
+
+// startImport asks which account to import keys from
+func (m SSHKeyManagementModel) startImport() (tea.Model, tea.Cmd) {
+	m.state = SSHKeyStateImportForm
+	provider := "github"
+	account := ""
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("provider").
+				Title("Provider").
+				Options(
+					huh.NewOption("GitHub", "github"),
+					huh.NewOption("GitLab", "gitlab"),
+				).
+				Value(&provider),
+
+			huh.NewInput().
+				Key("account").
+				Title("Username").
+				Description("The account whose public keys to import").
+				Placeholder("octocat").
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("username cannot be empty")
+					}
+					return nil
+				}).
+				Value(&account),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+	return m, m.form.Init()
+}
+
+// updateImportForm handles the account form
+func (m SSHKeyManagementModel) updateImportForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.state = SSHKeyStateList
+			m.form = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State == huh.StateCompleted {
+		provider := m.form.GetString("provider")
+		account := strings.TrimSpace(m.form.GetString("account"))
+		m.form = nil
+		return m.fetchRemoteKeys(provider, account)
+	}
+	return m, cmd
+}
+
+// fetchRemoteKeys downloads an account's keys in the background
+func (m SSHKeyManagementModel) fetchRemoteKeys(provider, account string) (tea.Model, tea.Cmd) {
+	m.state = SSHKeyStateImportPick
+	m.importProvider = provider
+	m.importAccount = account
+	m.importLoading = true
+	m.remoteKeys = nil
+	m.importCursor = 0
+	return m, func() tea.Msg {
+		keys, err := system.FetchRemoteKeys(provider, account)
+		return remoteKeysMsg{provider: provider, account: account, keys: keys, err: err}
+	}
+}
+
+// handleRemoteKeys shows the fetched keys for picking. On a re-sync the
+// keys imported before stay selected and new ones start unselected.
+func (m SSHKeyManagementModel) handleRemoteKeys(msg remoteKeysMsg) (tea.Model, tea.Cmd) {
+	m.importLoading = false
+	if msg.err != nil {
+		m.state = SSHKeyStateList
+		m.err = msg.err
+		return m, nil
+	}
+
+	m.importPrevious = nil
+	for _, src := range m.sources {
+		if src.Provider == msg.provider && src.Account == msg.account {
+			m.importPrevious = src.Fingerprints
+		}
+	}
+	previous := map[string]bool{}
+	for _, fp := range m.importPrevious {
+		previous[fp] = true
+	}
+	m.remoteKeys = msg.keys
+	m.importSelected = make([]bool, len(msg.keys))
+	for i, k := range msg.keys {
+		m.importSelected[i] = m.importPrevious == nil || previous[k.Fingerprint]
+	}
+	return m, nil
+}
+
+// revokedKeys counts previously imported keys the account no longer publishes
+func (m SSHKeyManagementModel) revokedKeys() int {
+	published := map[string]bool{}
+	for _, k := range m.remoteKeys {
+		published[k.Fingerprint] = true
+	}
+	count := 0
+	for _, fp := range m.importPrevious {
+		if !published[fp] {
+			count++
+		}
+	}
+	return count
+}
+
+// updateImportPick handles choosing which fetched keys to authorize
+func (m SSHKeyManagementModel) updateImportPick(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+
+	case "esc", "backspace":
+		m.state = SSHKeyStateList
+		return m, nil
+	}
+	if m.importLoading {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if m.importCursor > 0 {
+			m.importCursor--
+		}
+
+	case "down", "j":
+		if m.importCursor < len(m.remoteKeys)-1 {
+			m.importCursor++
+		}
+
+	case " ", "x":
+		if len(m.importSelected) > 0 {
+			m.importSelected[m.importCursor] = !m.importSelected[m.importCursor]
+		}
+
+	case "a":
+		all := true
+		for _, selected := range m.importSelected {
+			all = all && selected
+		}
+		for i := range m.importSelected {
+			m.importSelected[i] = !all
+		}
+
+	case "enter":
+		var selected []system.RemoteKey
+		for i, k := range m.remoteKeys {
+			if m.importSelected[i] {
+				selected = append(selected, k)
+			}
+		}
+		if len(selected) == 0 && m.importPrevious == nil {
+			m.err = fmt.Errorf("select at least one key to import")
+			return m, nil
+		}
+		added, removed, err := m.userManager.ImportRemoteKeys(m.username, m.importProvider, m.importAccount, selected)
+		m.state = SSHKeyStateList
+		if err != nil {
+			m.err = fmt.Errorf("failed to import keys: %v", err)
+			return m, nil
+		}
+		m.message = fmt.Sprintf("%s %s %s: %d key(s) added, %d removed from authorized_keys",
+			m.theme.Symbols.CheckMark, keyProviderName(m.importProvider), m.importAccount, added, removed)
+		m.loadKeys()
+		m.cursor = 0
+	}
+	return m, nil
+}
+
+// renderImportForm renders the account form
+func (m SSHKeyManagementModel) renderImportForm() string {
+	header := m.theme.Title.Render("Import Keys from GitHub/GitLab")
+	desc := m.theme.DescriptionStyle.Render(fmt.Sprintf("Public keys are added to %s's authorized_keys", m.username))
+
+	formView := ""
+	if m.form != nil {
+		formView = m.form.View()
+	}
+	help := m.theme.Help.Render("Tab: Next Field • Enter: Submit • Esc: Cancel")
+
+	content := lipgloss.JoinVertical(lipgloss.Left, header, desc, "", formView, "", help)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// renderImportPick renders the fetched keys as a checklist
+func (m SSHKeyManagementModel) renderImportPick() string {
+	header := m.theme.Title.Render(fmt.Sprintf("Keys for %s %s", keyProviderName(m.importProvider), m.importAccount))
+
+	var items []string
+	if m.importLoading {
+		items = append(items, m.theme.InfoStyle.Render("Fetching public keys..."))
+	} else {
+		previous := map[string]bool{}
+		for _, fp := range m.importPrevious {
+			previous[fp] = true
+		}
+		for i, k := range m.remoteKeys {
+			cursor := "  "
+			if i == m.importCursor {
+				cursor = m.theme.KeyStyle.Render("▶ ")
+			}
+			check := "[ ]"
+			if m.importSelected[i] {
+				check = "[" + m.theme.Symbols.CheckMark + "]"
+			}
+			note := ""
+			if m.importPrevious != nil && !previous[k.Fingerprint] {
+				note = " (new)"
+			}
+			line := fmt.Sprintf("%s%s %s %s%s", cursor, check, strings.TrimPrefix(k.Type, "ssh-"), k.Fingerprint, note)
+			if k.Comment != "" {
+				line += "  " + k.Comment
+			}
+			if i == m.importCursor {
+				line = m.theme.SelectedItem.Render(line)
+			} else {
+				line = m.theme.MenuItem.Render(line)
+			}
+			items = append(items, line)
+		}
+		if revoked := m.revokedKeys(); revoked > 0 {
+			items = append(items, "", m.theme.WarningStyle.Render(fmt.Sprintf("%s %d previously imported key(s) are no longer published and will be removed",
+				m.theme.Symbols.Warning, revoked)))
+		}
+		items = append(items, "", m.theme.DescriptionStyle.Render("Unselected keys imported from this account before are removed from authorized_keys"))
+	}
+
+	help := m.theme.Help.Render("↑/↓: Navigate • Space: Toggle • a: All • Enter: Apply • Esc: Cancel")
+
+	content := lipgloss.JoinVertical(lipgloss.Left, header, "", lipgloss.JoinVertical(lipgloss.Left, items...), "", help)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
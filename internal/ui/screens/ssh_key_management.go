@@ -23,6 +23,8 @@ const (
 	SSHKeyStateConfirmDelete
 	SSHKeyStateCopyKey
 	SSHKeyStateExportOptions
+	SSHKeyStateImportForm
+	SSHKeyStateImportPick
 )
 
 // SSHKeyManagementModel represents the SSH key management screen
@@ -54,6 +56,16 @@ type SSHKeyManagementModel struct {
 
 	// Currently selected key for details
 	selectedKey *system.SSHKey
+
+	// Importing keys from GitHub/GitLab
+	sources        []system.KeySource
+	importProvider string
+	importAccount  string
+	importLoading  bool
+	remoteKeys     []system.RemoteKey
+	importSelected []bool
+	importPrevious []string // Fingerprints imported from the account before
+	importCursor   int
 }
 
 // NewSSHKeyManagementModel creates a new SSH key management model
@@ -85,6 +97,9 @@ func (m *SSHKeyManagementModel) loadKeys() {
 		return
 	}
 	m.keys = keys
+	if sources, err := system.LoadKeySources(m.username); err == nil {
+		m.sources = sources
+	}
 }
 
 // buildGenerateFormWithAccessors creates the key generation form with accessor functions
@@ -171,6 +186,10 @@ func (m SSHKeyManagementModel) Init() tea.Cmd {
 
 // Update handles messages for SSH key management
 func (m SSHKeyManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.state == SSHKeyStateImportForm && m.form != nil {
+		return m.updateImportForm(msg)
+	}
+
 	// Update form first if in generate state (before handling key messages)
 	if m.state == SSHKeyStateGenerateForm && m.form != nil {
 		form, cmd := m.form.Update(msg)
@@ -204,6 +223,9 @@ func (m SSHKeyManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case remoteKeysMsg:
+		return m.handleRemoteKeys(msg)
+
 	case tea.KeyMsg:
 		// Handle message clearing
 		if m.message != "" {
@@ -245,6 +267,8 @@ func (m SSHKeyManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateCopyKey(msg)
 		case SSHKeyStateExportOptions:
 			return m.updateExportOptions(msg)
+		case SSHKeyStateImportPick:
+			return m.updateImportPick(msg)
 		}
 	}
 
@@ -253,7 +277,7 @@ func (m SSHKeyManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // updateList handles key presses in the list view
 func (m SSHKeyManagementModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	totalItems := len(m.keys) + 1 // +1 for "Generate New Key" option
+	totalItems := sshKeyListActions + len(m.keys) + len(m.sources)
 
 	switch msg.String() {
 	case "ctrl+c", "q":
@@ -286,12 +310,18 @@ func (m SSHKeyManagementModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// Build form with fresh local variables that will be read on completion
 			m.form = m.buildGenerateFormWithAccessors()
 			return m, m.form.Init()
-		} else if m.cursor > 0 && m.cursor <= len(m.keys) {
+		} else if m.cursor == 1 {
+			return m.startImport()
+		} else if m.cursor < sshKeyListActions+len(m.keys) {
 			// View key details
-			key := m.keys[m.cursor-1]
+			key := m.keys[m.cursor-sshKeyListActions]
 			m.selectedKey = &key
 			m.state = SSHKeyStateKeyDetails
 			m.actionCursor = 0
+		} else if m.cursor < totalItems {
+			// Re-sync keys imported from an account
+			src := m.sources[m.cursor-sshKeyListActions-len(m.keys)]
+			return m.fetchRemoteKeys(src.Provider, src.Account)
 		}
 	}
 
@@ -846,6 +876,10 @@ func (m SSHKeyManagementModel) View() string {
 		return m.renderCopyKey()
 	case SSHKeyStateExportOptions:
 		return m.renderExportOptions()
+	case SSHKeyStateImportForm:
+		return m.renderImportForm()
+	case SSHKeyStateImportPick:
+		return m.renderImportPick()
 	}
 
 	return m.renderList()
@@ -909,6 +943,18 @@ func (m SSHKeyManagementModel) renderList() string {
 		generateItem = m.theme.MenuItem.Render(generateItem)
 	}
 	items = append(items, generateItem)
+
+	cursor = "  "
+	if m.cursor == 1 {
+		cursor = m.theme.KeyStyle.Render("▶ ")
+	}
+	importItem := fmt.Sprintf("%s+ Import Keys from GitHub/GitLab", cursor)
+	if m.cursor == 1 {
+		importItem = m.theme.SelectedItem.Render(importItem)
+	} else {
+		importItem = m.theme.MenuItem.Render(importItem)
+	}
+	items = append(items, importItem)
 	items = append(items, "")
 
 	// Key list header
@@ -918,7 +964,7 @@ func (m SSHKeyManagementModel) renderList() string {
 
 		for i, key := range m.keys {
 			cursor := "  "
-			if m.cursor == i+1 {
+			if m.cursor == i+sshKeyListActions {
 				cursor = m.theme.KeyStyle.Render("▶ ")
 			}
 
@@ -948,7 +994,7 @@ func (m SSHKeyManagementModel) renderList() string {
 				passphraseStatus,
 			)
 
-			if m.cursor == i+1 {
+			if m.cursor == i+sshKeyListActions {
 				keyLine = m.theme.SelectedItem.Render(keyLine)
 			} else {
 				keyLine = m.theme.MenuItem.Render(keyLine)
@@ -960,6 +1006,27 @@ func (m SSHKeyManagementModel) renderList() string {
 		items = append(items, m.theme.DescriptionStyle.Render("No SSH keys found for this user."))
 	}
 
+	if len(m.sources) > 0 {
+		items = append(items, "")
+		items = append(items, m.theme.Label.Render("Imported From (Enter to re-sync):"))
+		items = append(items, "")
+		for i, src := range m.sources {
+			index := sshKeyListActions + len(m.keys) + i
+			cursor := "  "
+			if m.cursor == index {
+				cursor = m.theme.KeyStyle.Render("▶ ")
+			}
+			line := fmt.Sprintf("%s%s %s (%d key(s), synced %s)", cursor, keyProviderName(src.Provider), src.Account,
+				len(src.Fingerprints), src.SyncedAt.Format("2006-01-02 15:04"))
+			if m.cursor == index {
+				line = m.theme.SelectedItem.Render(line)
+			} else {
+				line = m.theme.MenuItem.Render(line)
+			}
+			items = append(items, line)
+		}
+	}
+
 	// Legend
 	items = append(items, "")
 	items = append(items, m.theme.DescriptionStyle.Render("Legend: Login "+m.theme.Symbols.CheckMark+"=authorized_keys | Pass=passphrase required"))
@@ -0,0 +1,313 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// SSHDHardeningModel edits the hardening options in sshd_config
+type SSHDHardeningModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	mode      string // "view", "form", "confirm_save", "confirm_restart"
+	content   string
+	current   system.SSHDSettings
+	pending   system.SSHDSettings
+	overrides map[string]string
+	form      *huh.Form
+	confirm   Confirmation
+
+	needsRestart bool
+	err          error
+	success      string
+}
+
+// NewSSHDHardeningModel creates a new SSH server hardening model
+func NewSSHDHardeningModel() SSHDHardeningModel {
+	m := SSHDHardeningModel{
+		theme: theme.DefaultTheme(),
+		mode:  "view",
+	}
+	m.load()
+	return m
+}
+
+// load reads the current configuration
+func (m *SSHDHardeningModel) load() {
+	data, err := system.ReadFile(system.SSHDConfigPath)
+	if err != nil {
+		m.err = fmt.Errorf("failed to read %s: %w", system.SSHDConfigPath, err)
+		return
+	}
+	m.content = string(data)
+	m.current = system.ParseSSHDSettings(m.content)
+	m.overrides = system.SSHDDropInOverrides(m.content)
+}
+
+// Init initializes the hardening screen
+func (m SSHDHardeningModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the hardening screen
+func (m SSHDHardeningModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	if m.mode == "form" {
+		return m.updateForm(msg)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if keyMsg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch m.mode {
+	case "confirm_save", "confirm_restart":
+		return m.updateConfirm(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: ConfigMenuScreen}
+		}
+	case "e", "enter":
+		if m.content == "" {
+			return m, nil
+		}
+		return m.openForm()
+	case "r":
+		m.confirm = NewConfirmation("restart", "Restart SSH Server",
+			"Restart sshd to apply the configuration?\n\nExisting sessions stay connected. Keep this one open and test a new login before closing it.",
+			ConfirmWarning)
+		m.mode = "confirm_restart"
+	}
+	return m, nil
+}
+
+// openForm shows the settings form filled with the current values
+func (m SSHDHardeningModel) openForm() (tea.Model, tea.Cmd) {
+	s := m.current
+	if s.PermitRootLogin == "without-password" {
+		s.PermitRootLogin = "prohibit-password"
+	}
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("root").
+				Title("PermitRootLogin").
+				Options(
+					huh.NewOption("no (recommended)", "no"),
+					huh.NewOption("prohibit-password (keys only)", "prohibit-password"),
+					huh.NewOption("yes", "yes"),
+				).
+				Value(&s.PermitRootLogin),
+			huh.NewSelect[string]().
+				Key("password").
+				Title("PasswordAuthentication").
+				Options(
+					huh.NewOption("no (keys only, recommended)", "no"),
+					huh.NewOption("yes", "yes"),
+				).
+				Value(&s.PasswordAuthentication),
+			huh.NewInput().
+				Key("port").
+				Title("Port").
+				Value(&s.Port).
+				Validate(func(v string) error {
+					t := m.current
+					t.Port = strings.TrimSpace(v)
+					return t.Validate()
+				}),
+			huh.NewInput().
+				Key("allow").
+				Title("AllowUsers").
+				Description("Space-separated users allowed to log in (empty for everyone)").
+				Value(&s.AllowUsers),
+			huh.NewInput().
+				Key("tries").
+				Title("MaxAuthTries").
+				Value(&s.MaxAuthTries).
+				Validate(func(v string) error {
+					t := system.SSHDSettings{PermitRootLogin: "no", PasswordAuthentication: "no", Port: "22", MaxAuthTries: strings.TrimSpace(v)}
+					return t.Validate()
+				}),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+	m.mode = "form"
+	m.err = nil
+	m.success = ""
+	return m, m.form.Init()
+}
+
+// updateForm passes messages to the settings form
+func (m SSHDHardeningModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = "view"
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	switch m.form.State {
+	case huh.StateCompleted:
+		m.pending = system.SSHDSettings{
+			PermitRootLogin:        m.form.GetString("root"),
+			PasswordAuthentication: m.form.GetString("password"),
+			Port:                   strings.TrimSpace(m.form.GetString("port")),
+			AllowUsers:             strings.Join(strings.Fields(m.form.GetString("allow")), " "),
+			MaxAuthTries:           strings.TrimSpace(m.form.GetString("tries")),
+		}
+		if err := m.pending.Validate(); err != nil {
+			m.err = err
+			m.mode = "view"
+			return m, nil
+		}
+		if m.pending == m.current {
+			m.success = m.theme.Symbols.Info + " No changes"
+			m.mode = "view"
+			return m, nil
+		}
+
+		message := "Write these settings to " + system.SSHDConfigPath + "?\nThe file is validated with sshd -t and backed up first."
+		severity := ConfirmNormal
+		if warnings := m.pending.Warnings(m.current); len(warnings) > 0 {
+			message += "\n\n" + m.theme.Symbols.Warning + " " + strings.Join(warnings, "\n"+m.theme.Symbols.Warning+" ")
+			severity = ConfirmWarning
+		}
+		m.confirm = NewConfirmation("save", "Save SSH Settings", message, severity)
+		m.mode = "confirm_save"
+		return m, nil
+	case huh.StateAborted:
+		m.mode = "view"
+		return m, nil
+	}
+	return m, cmd
+}
+
+// updateConfirm handles the save and restart confirmations
+func (m SSHDHardeningModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var result ConfirmResult
+	m.confirm, result = m.confirm.Update(msg)
+	switch result {
+	case ConfirmAccepted:
+		m.mode = "view"
+		if m.confirm.Action == "restart" {
+			m.needsRestart = false
+			return m, runSystemctl("restart", system.SSHDServiceName())
+		}
+		backup, err := system.SaveSSHDConfig(system.ApplySSHDSettings(m.content, m.pending))
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.load()
+		m.err = nil
+		m.needsRestart = true
+		m.success = fmt.Sprintf("%s Saved (backup: %s). Press r to restart sshd.", m.theme.Symbols.CheckMark, backup)
+		return m, snapshotConfig("Update SSH server settings", true)
+	case ConfirmCancelled:
+		m.mode = "view"
+	}
+	return m, nil
+}
+
+// View renders the hardening screen
+func (m SSHDHardeningModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	switch m.mode {
+	case "confirm_save", "confirm_restart":
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{
+		m.theme.Title.Render("SSH Server Hardening"),
+		m.theme.DescriptionStyle.Render(system.SSHDConfigPath),
+		"",
+	}
+
+	if m.mode == "form" {
+		sections = append(sections, m.form.View())
+	} else if m.content != "" {
+		rows := []struct{ key, value, good string }{
+			{"PermitRootLogin", m.current.PermitRootLogin, "no"},
+			{"PasswordAuthentication", m.current.PasswordAuthentication, "no"},
+			{"Port", m.current.Port, ""},
+			{"AllowUsers", m.current.AllowUsers, ""},
+			{"MaxAuthTries", m.current.MaxAuthTries, ""},
+		}
+		for _, row := range rows {
+			value := row.value
+			if value == "" {
+				value = "(everyone)"
+			}
+			style := m.theme.MenuItem
+			if row.good != "" {
+				if value == row.good {
+					style = m.theme.SuccessStyle
+				} else {
+					style = m.theme.WarningStyle
+				}
+			}
+			line := m.theme.Label.Render(fmt.Sprintf("%-24s", row.key)) + style.Render(value)
+			if file, ok := m.overrides[row.key]; ok {
+				line += m.theme.WarningStyle.Render(m.theme.Symbols.Warning + " overridden by " + file)
+			}
+			sections = append(sections, line)
+		}
+		if len(m.overrides) > 0 {
+			sections = append(sections, "", m.theme.DescriptionStyle.Render("Files in sshd_config.d are read first; their values win over sshd_config."))
+		}
+	}
+
+	if m.needsRestart {
+		sections = append(sections, "", m.theme.WarningStyle.Render(m.theme.Symbols.Warning+" Changes take effect after sshd restarts"))
+	}
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := "e: Edit settings" + bullet + "r: Restart sshd" + bullet + "Esc: Back"
+	if m.mode == "form" {
+		help = "Enter: Next" + bullet + "Esc: Cancel"
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -33,6 +33,9 @@ type SSLManualModel struct {
 	chainPath    string
 	err          error
 	success      bool
+
+	review    ConfigReview
+	reviewing bool
 }
 
 // NewSSLManualModel creates a new manual SSL model
@@ -66,6 +69,22 @@ func (m SSLManualModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.reviewing {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.review, result = m.review.Update(msg, m.height)
+			switch result {
+			case ConfirmAccepted:
+				m.reviewing = false
+				return m.applyReviewed()
+			case ConfirmCancelled:
+				m.reviewing = false
+			}
+			return m, nil
+		}
+
 		// If showing success/error, any key returns
 		if m.success || m.err != nil {
 			if msg.String() == "enter" || msg.String() == " " || msg.String() == "esc" {
@@ -168,8 +187,20 @@ func (m SSLManualModel) applySSLCertificate() (SSLManualModel, tea.Cmd) {
 		}
 	}
 
-	// Apply SSL to nginx config
-	err := m.nginxManager.AddSSLManual(m.site.Name, m.certPath, m.keyPath, m.chainPath)
+	// Stage the SSL config for review
+	change, err := m.nginxManager.PlanAddSSLManual(m.site.Name, m.certPath, m.keyPath, m.chainPath)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.review = NewConfigReview("add_ssl", m.nginxManager, change)
+	m.reviewing = true
+	return m, nil
+}
+
+// applyReviewed writes the reviewed SSL config and reloads nginx
+func (m SSLManualModel) applyReviewed() (SSLManualModel, tea.Cmd) {
+	err := m.nginxManager.ApplyChange(m.review.Change)
 	if err != nil {
 		m.err = err
 		return m, nil
@@ -200,6 +231,10 @@ func (m SSLManualModel) View() string {
 		return "Loading..."
 	}
 
+	if m.reviewing {
+		return m.review.View(m.theme, m.width, m.height)
+	}
+
 	// If success or error, show message
 	if m.success {
 		msg := m.theme.SuccessStyle.Render("✓ SSL certificate applied successfully!")
@@ -2,6 +2,7 @@ package screens
 
 import (
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -26,6 +27,10 @@ func NewSSLOptionsModel(site system.NginxSite) SSLOptionsModel {
 		"Manual Certificate (Provide paths)",
 		"← Cancel",
 	}
+	// certbot --nginx uses the HTTP challenge, which cannot prove a wildcard
+	if system.HasWildcard(siteDomains(site)) {
+		options = options[1:]
+	}
 	
 	return SSLOptionsModel{
 		theme:   theme.DefaultTheme(),
@@ -91,7 +96,7 @@ func (m SSLOptionsModel) executeOption() (SSLOptionsModel, tea.Cmd) {
 		// Navigate to execution screen to run certbot
 		return m, func() tea.Msg {
 			return ExecutionStartMsg{
-				Command:     fmt.Sprintf("certbot --nginx -d %s", m.site.Domain),
+				Command:     certbotCommand(siteDomains(m.site)),
 				Description: fmt.Sprintf("Installing SSL certificate for %s", m.site.Domain),
 			}
 		}
@@ -132,7 +137,7 @@ func (m SSLOptionsModel) View() string {
 	header := m.theme.Title.Render("Add SSL Certificate")
 
 	// Site info
-	siteInfo := m.theme.DescriptionStyle.Render(fmt.Sprintf("Site: %s (%s)", m.site.Name, m.site.Domain))
+	siteInfo := m.theme.DescriptionStyle.Render(fmt.Sprintf("Site: %s (%s)", m.site.Name, strings.Join(siteDomains(m.site), ", ")))
 
 	// Instructions
 	instructions := lipgloss.JoinVertical(
@@ -143,6 +148,8 @@ func (m SSLOptionsModel) View() string {
 		m.theme.DescriptionStyle.Render("  • Requires domain to point to this server"),
 		m.theme.DescriptionStyle.Render("  • Ports 80 & 443 must be accessible"),
 		m.theme.DescriptionStyle.Render("  • Email required for renewal notifications"),
+		m.theme.DescriptionStyle.Render("  • One certificate covers every domain of the site"),
+		m.theme.DescriptionStyle.Render("  • Not available for wildcard domains (needs a DNS challenge)"),
 		"",
 		m.theme.DescriptionStyle.Render("Manual: Use your own certificate files"),
 		m.theme.DescriptionStyle.Render("  • Requires certificate and private key files"),
@@ -198,3 +205,24 @@ func (m SSLOptionsModel) View() string {
 		bordered,
 	)
 }
+
+// siteDomains returns every server_name of a site
+func siteDomains(site system.NginxSite) []string {
+	if len(site.Domains) > 0 {
+		return site.Domains
+	}
+	if site.Domain != "" {
+		return []string{site.Domain}
+	}
+	return nil
+}
+
+// certbotCommand returns an interactive certbot command issuing one
+// certificate for all domains, named after the first
+func certbotCommand(domains []string) string {
+	args := []string{"certbot", "--nginx", "--cert-name", system.ShellQuote(system.PrimaryDomain(domains))}
+	for _, d := range domains {
+		args = append(args, "-d", system.ShellQuote(d))
+	}
+	return strings.Join(args, " ")
+}
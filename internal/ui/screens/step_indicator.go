@@ -0,0 +1,96 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// WizardStep is a single step of a multi-step flow
+type WizardStep struct {
+	ID    string
+	Label string
+}
+
+// StepIndicator tracks progress through a multi-step flow and renders
+// a "Step 3 of 6" header. Completed steps (those before the current
+// one) can be revisited with the number keys.
+type StepIndicator struct {
+	Steps   []WizardStep
+	current int
+}
+
+// NewStepIndicator creates a step indicator starting at the first step
+func NewStepIndicator(steps ...WizardStep) StepIndicator {
+	return StepIndicator{Steps: steps}
+}
+
+// SetCurrent moves the indicator to the step with the given ID.
+// Unknown IDs are ignored.
+func (s *StepIndicator) SetCurrent(id string) {
+	for i, step := range s.Steps {
+		if step.ID == id {
+			s.current = i
+			return
+		}
+	}
+}
+
+// Current returns the ID of the current step
+func (s StepIndicator) Current() string {
+	if s.current < len(s.Steps) {
+		return s.Steps[s.current].ID
+	}
+	return ""
+}
+
+// JumpTarget returns the step ID a number key refers to, if that step
+// has already been completed
+func (s StepIndicator) JumpTarget(key string) (string, bool) {
+	if len(key) != 1 || key[0] < '1' || key[0] > '9' {
+		return "", false
+	}
+	idx := int(key[0] - '1')
+	if idx >= s.current {
+		return "", false
+	}
+	return s.Steps[idx].ID, true
+}
+
+// JumpHelp returns a help hint for revisiting completed steps
+func (s StepIndicator) JumpHelp() string {
+	switch s.current {
+	case 0:
+		return ""
+	case 1:
+		return " • 1: Previous step"
+	}
+	return fmt.Sprintf(" • 1-%d: Previous steps", s.current)
+}
+
+// View renders the step header and the step trail
+func (s StepIndicator) View(t *theme.Theme) string {
+	if len(s.Steps) == 0 {
+		return ""
+	}
+
+	title := t.Subtitle.Render(fmt.Sprintf("Step %d of %d: %s", s.current+1, len(s.Steps), s.Steps[s.current].Label))
+
+	var trail []string
+	for i, step := range s.Steps {
+		label := fmt.Sprintf("%d %s", i+1, step.Label)
+		switch {
+		case i == s.current:
+			trail = append(trail, t.SelectedItem.Render(t.Symbols.Cursor+" "+label))
+		case i < s.current:
+			trail = append(trail, t.SuccessStyle.Render(t.Symbols.CheckMark+" "+label))
+		default:
+			trail = append(trail, t.DescriptionStyle.Render(label))
+		}
+	}
+	separator := t.DescriptionStyle.Render(" " + t.Symbols.ArrowRight + " ")
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, strings.Join(trail, separator))
+}
@@ -0,0 +1,36 @@
+package screens
+
+import (
+	"testing"
+)
+
+func TestStepIndicatorJumpTarget(t *testing.T) {
+	steps := NewStepIndicator(
+		WizardStep{ID: "setup", Label: "Setup"},
+		WizardStep{ID: "confirm", Label: "Confirm"},
+		WizardStep{ID: "deploy", Label: "Deploy"},
+	)
+
+	if _, ok := steps.JumpTarget("1"); ok {
+		t.Error("expected no jump target on the first step")
+	}
+
+	steps.SetCurrent("deploy")
+	if steps.Current() != "deploy" {
+		t.Errorf("expected current step 'deploy', got '%s'", steps.Current())
+	}
+
+	if id, ok := steps.JumpTarget("2"); !ok || id != "confirm" {
+		t.Errorf("expected '2' to jump to 'confirm', got '%s' (%v)", id, ok)
+	}
+	for _, key := range []string{"3", "4", "0", "a", "12"} {
+		if _, ok := steps.JumpTarget(key); ok {
+			t.Errorf("expected key %q not to be a jump target", key)
+		}
+	}
+
+	steps.SetCurrent("unknown")
+	if steps.Current() != "deploy" {
+		t.Errorf("expected unknown step to be ignored, got '%s'", steps.Current())
+	}
+}
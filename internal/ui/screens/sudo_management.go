@@ -0,0 +1,331 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// Fixed rows above a user's passwordless commands
+const (
+	sudoRowGroup = iota
+	sudoRowNoPassword
+	sudoRowAddCommand
+	sudoFixedRows
+)
+
+// SudoManagementModel manages a user's sudo group membership and the
+// commands they may run as root without a password
+type SudoManagementModel struct {
+	theme       *theme.Theme
+	width       int
+	height      int
+	user        system.User
+	userManager *system.UserManager
+
+	noPassword bool     // NOPASSWD for all commands
+	commands   []string // Passwordless commands from the ravact fragment
+	cursor     int
+
+	form       *huh.Form
+	confirm    Confirmation
+	confirming bool
+	err        error
+	success    string
+}
+
+// NewSudoManagementModel creates the sudo management screen for a user
+func NewSudoManagementModel(user system.User) SudoManagementModel {
+	m := SudoManagementModel{
+		theme:       theme.DefaultTheme(),
+		user:        user,
+		userManager: system.NewUserManager(),
+	}
+	m.reload()
+	return m
+}
+
+// reload reads the user's sudo state again
+func (m *SudoManagementModel) reload() {
+	if user, err := m.userManager.GetUser(m.user.Username); err == nil {
+		m.user = *user
+	}
+	m.noPassword = m.userManager.HasSudoNoPassword(m.user.Username)
+	commands, err := m.userManager.GetSudoCommands(m.user.Username)
+	if err != nil {
+		m.err = err
+	}
+	m.commands = commands
+	if m.cursor >= sudoFixedRows+len(m.commands) {
+		m.cursor = sudoFixedRows + len(m.commands) - 1
+	}
+}
+
+// Init initializes the sudo management screen
+func (m SudoManagementModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the sudo management screen
+func (m SudoManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	if m.form != nil {
+		return m.updateForm(msg)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if keyMsg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+	if m.confirming {
+		var result ConfirmResult
+		m.confirm, result = m.confirm.Update(keyMsg)
+		switch result {
+		case ConfirmCancelled:
+			m.confirming = false
+		case ConfirmAccepted:
+			m.confirming = false
+			return m.applyConfirmed(m.confirm.Action)
+		}
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "q":
+		return m, tea.Quit
+
+	case "esc", "backspace":
+		user := m.user
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: UserDetailsScreen, Data: map[string]interface{}{"user": user}}
+		}
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down", "j":
+		if m.cursor < sudoFixedRows+len(m.commands)-1 {
+			m.cursor++
+		}
+
+	case "enter", " ":
+		m.err = nil
+		m.success = ""
+		return m.activate()
+
+	case "d", "delete":
+		if m.cursor >= sudoFixedRows {
+			m.err = nil
+			m.success = ""
+			return m.activate()
+		}
+	}
+	return m, nil
+}
+
+// activate acts on the selected row
+func (m SudoManagementModel) activate() (tea.Model, tea.Cmd) {
+	username := m.user.Username
+	switch {
+	case m.cursor == sudoRowGroup:
+		if m.user.HasSudo {
+			m.confirm = NewConfirmation("revoke-group", "Revoke Sudo",
+				fmt.Sprintf("Remove %s from the sudo group?\nPasswordless commands below keep working.", username), ConfirmWarning)
+			m.confirming = true
+			return m, nil
+		}
+		if err := m.userManager.GrantSudo(username); err != nil {
+			m.err = fmt.Errorf("failed to grant sudo: %v", err)
+			return m, nil
+		}
+		m.success = fmt.Sprintf("%s Added %s to the sudo group", m.theme.Symbols.CheckMark, username)
+
+	case m.cursor == sudoRowNoPassword:
+		if !m.noPassword {
+			m.confirm = NewDangerConfirmation("grant-nopasswd", "Passwordless Sudo",
+				fmt.Sprintf("Let %s run every command as root without a password?\nPrefer passwordless access to specific commands.", username), username)
+			m.confirming = true
+			return m, nil
+		}
+		if err := m.userManager.RevokeSudoNoPassword(username); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.success = fmt.Sprintf("%s %s needs a password for sudo again", m.theme.Symbols.CheckMark, username)
+
+	case m.cursor == sudoRowAddCommand:
+		command := ""
+		m.form = huh.NewForm(huh.NewGroup(
+			huh.NewInput().
+				Key("command").
+				Title("Command").
+				Description("Absolute path and arguments; without arguments any arguments are allowed").
+				Placeholder(system.SudoCommandSuggestions[0]).
+				Suggestions(system.SudoCommandSuggestions).
+				Validate(system.ValidateSudoCommand).
+				Value(&command),
+		)).WithTheme(m.theme.HuhTheme).WithShowHelp(true).WithShowErrors(true)
+		return m, m.form.Init()
+
+	default:
+		command := m.commands[m.cursor-sudoFixedRows]
+		m.confirm = NewConfirmation("remove-command", "Remove Command",
+			fmt.Sprintf("Stop letting %s run this without a password?\n\n%s", username, command), ConfirmWarning)
+		m.confirming = true
+		return m, nil
+	}
+	m.reload()
+	return m, nil
+}
+
+// applyConfirmed carries out a confirmed change
+func (m SudoManagementModel) applyConfirmed(action string) (tea.Model, tea.Cmd) {
+	username := m.user.Username
+	switch action {
+	case "revoke-group":
+		if err := m.userManager.RevokeSudo(username); err != nil {
+			m.err = fmt.Errorf("failed to revoke sudo: %v", err)
+			return m, nil
+		}
+		m.success = fmt.Sprintf("%s Removed %s from the sudo group", m.theme.Symbols.CheckMark, username)
+
+	case "grant-nopasswd":
+		if err := m.userManager.GrantSudoNoPassword(username); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.success = fmt.Sprintf("%s %s can run every command as root without a password", m.theme.Symbols.CheckMark, username)
+
+	case "remove-command":
+		index := m.cursor - sudoFixedRows
+		commands := append(append([]string{}, m.commands[:index]...), m.commands[index+1:]...)
+		if err := m.userManager.SetSudoCommands(username, commands); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.success = fmt.Sprintf("%s Removed %s", m.theme.Symbols.CheckMark, m.commands[index])
+	}
+	m.reload()
+	return m, nil
+}
+
+// updateForm handles the add command form
+func (m SudoManagementModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.form = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	command := strings.Join(strings.Fields(m.form.GetString("command")), " ")
+	m.form = nil
+	for _, c := range m.commands {
+		if c == command {
+			m.err = fmt.Errorf("%s is already allowed", command)
+			return m, nil
+		}
+	}
+	if err := m.userManager.SetSudoCommands(m.user.Username, append(m.commands, command)); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.success = fmt.Sprintf("%s %s may run %s without a password", m.theme.Symbols.CheckMark, m.user.Username, command)
+	m.reload()
+	m.cursor = sudoFixedRows + len(m.commands) - 1
+	return m, nil
+}
+
+// View renders the sudo management screen
+func (m SudoManagementModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+	if m.form != nil {
+		content := lipgloss.JoinVertical(lipgloss.Left,
+			m.theme.Title.Render("Add Passwordless Command: "+m.user.Username),
+			m.theme.DescriptionStyle.Render("Checked with visudo -c before it is installed in "+system.SudoCommandsPath(m.user.Username)),
+			"",
+			m.form.View(),
+			"",
+			m.theme.Help.Render("Tab: Complete • Enter: Save • Esc: Cancel"))
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(content))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	onOff := func(on bool, yes, no string) string {
+		if on {
+			return m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " " + yes)
+		}
+		return m.theme.DescriptionStyle.Render(m.theme.Symbols.CrossMark + " " + no)
+	}
+	rows := []string{
+		"Sudo group membership      " + onOff(m.user.HasSudo, "member", "not a member"),
+		"Passwordless sudo (all)    " + onOff(m.noPassword, "enabled", "disabled"),
+		"+ Add passwordless command",
+	}
+	for _, c := range m.commands {
+		rows = append(rows, "  "+c)
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Sudo Management: " + m.user.Username),
+		m.theme.DescriptionStyle.Render("Group membership and commands this user may run as root"),
+		"",
+	}
+	for i, row := range rows {
+		if i == sudoFixedRows {
+			sections = append(sections, "", m.theme.Label.Render("Passwordless commands:"))
+		}
+		if i == m.cursor {
+			sections = append(sections, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+m.theme.SelectedItem.Render(row))
+		} else {
+			sections = append(sections, "  "+m.theme.MenuItem.Render(row))
+		}
+	}
+	if len(m.commands) == 0 {
+		sections = append(sections, "", m.theme.DescriptionStyle.Render("No passwordless commands. Add e.g. "+system.SudoCommandSuggestions[0]))
+	}
+
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	help := m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate" + bullet + "Enter: Toggle/Add" + bullet +
+		"d: Remove command" + bullet + "Esc: Back"
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(content))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/huh"
@@ -22,6 +23,8 @@ type SupervisorAddProgramModel struct {
 	step        int // 0=form, 1=editing, 2=result
 	programName string
 	editor      string
+	runner      string // "supervisor" or "systemd"
+	instances   int    // Template unit instances when runner is systemd
 	form        *huh.Form
 	err         error
 	message     string
@@ -37,6 +40,12 @@ func NewSupervisorAddProgramModel(manager *system.SupervisorManager) SupervisorA
 		step:        0,
 		programName: "",
 		editor:      "nano",
+		runner:      "supervisor",
+		instances:   1,
+	}
+	// Servers without supervisord can still run workers under systemd
+	if !manager.IsInstalled() {
+		m.runner = "systemd"
 	}
 
 	m.form = m.buildForm()
@@ -44,11 +53,24 @@ func NewSupervisorAddProgramModel(manager *system.SupervisorManager) SupervisorA
 }
 
 func (m *SupervisorAddProgramModel) buildForm() *huh.Form {
+	name := m.programName
+	editor := m.editor
+	runner := m.runner
+	instances := strconv.Itoa(m.instances)
+
+	runners := []huh.Option[string]{
+		huh.NewOption("systemd template unit (name@.service, no supervisord)", "systemd"),
+	}
+	if m.manager.IsInstalled() {
+		runners = append([]huh.Option[string]{huh.NewOption("Supervisor program", "supervisor")}, runners...)
+	}
+
 	return huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
+				Key("programName").
 				Title("Program Name").
-				Description("Unique identifier for the supervisor program").
+				Description("Unique identifier for the program").
 				Placeholder("myprogram").
 				Validate(func(s string) error {
 					if s == "" {
@@ -57,19 +79,40 @@ func (m *SupervisorAddProgramModel) buildForm() *huh.Form {
 					if strings.Contains(s, " ") {
 						return fmt.Errorf("program name cannot contain spaces")
 					}
-					return nil
+					return system.ValidateWorkerName(s)
 				}).
-				Value(&m.programName),
+				Value(&name),
+
+			huh.NewSelect[string]().
+				Key("runner").
+				Title("Run With").
+				Description("systemd runs one unit instance per process, like numprocs").
+				Options(runners...).
+				Value(&runner),
 
 			huh.NewSelect[string]().
+				Key("editor").
 				Title("Editor").
 				Description("Choose editor to configure the program").
 				Options(
 					huh.NewOption("Nano (recommended for beginners)", "nano"),
 					huh.NewOption("Vi/Vim", "vi"),
 				).
-				Value(&m.editor),
+				Value(&editor),
 		),
+		huh.NewGroup(
+			huh.NewInput().
+				Key("instances").
+				Title("Instances").
+				Description("Processes to run, started as name@1 to name@N").
+				Validate(func(s string) error {
+					if n, err := strconv.Atoi(strings.TrimSpace(s)); err != nil || n < 1 {
+						return fmt.Errorf("enter a number of 1 or more")
+					}
+					return nil
+				}).
+				Value(&instances),
+		).WithHideFunc(func() bool { return runner != "systemd" }),
 	).WithTheme(m.theme.HuhTheme).
 		WithShowHelp(true).
 		WithShowErrors(true)
@@ -86,6 +129,12 @@ func (m SupervisorAddProgramModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case systemdUnitDraftMsg:
+		return m, m.editSystemdUnit(msg.path)
+
+	case systemdUnitEditedMsg:
+		return m, m.installSystemdWorker(msg.path, msg.err)
+
 	case ExecutionCompleteMsg:
 		// Handle result from editor
 		m.step = 2
@@ -135,6 +184,14 @@ func (m SupervisorAddProgramModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Check if form is completed
 		if m.form.State == huh.StateCompleted {
+			m.programName = m.form.GetString("programName")
+			m.editor = m.form.GetString("editor")
+			m.runner = m.form.GetString("runner")
+			if m.runner == "systemd" {
+				m.instances, _ = strconv.Atoi(strings.TrimSpace(m.form.GetString("instances")))
+				m.step = 1
+				return m, m.openSystemdEditor()
+			}
 			m.step = 1
 			return m, m.openEditor()
 		}
@@ -208,6 +265,106 @@ stdout_logfile_maxbytes=10MB
 	}
 }
 
+// systemdUnitDraftMsg carries the local copy of a new worker unit, ready
+// for the editor
+type systemdUnitDraftMsg struct {
+	path string
+}
+
+// systemdUnitEditedMsg is sent when the editor closes the worker unit draft
+type systemdUnitEditedMsg struct {
+	path string
+	err  error
+}
+
+// openSystemdEditor writes a worker template unit to a local draft for the
+// editor, unless the unit already exists on the server
+func (m SupervisorAddProgramModel) openSystemdEditor() tea.Cmd {
+	return func() tea.Msg {
+		unitPath := system.WorkerUnitPath(m.programName)
+		if _, err := system.Stat(unitPath); err == nil {
+			return ExecutionCompleteMsg{
+				Success: false,
+				Error:   fmt.Errorf("%s already exists; manage it from Systemd Services", unitPath),
+			}
+		}
+
+		// The editor runs in this terminal, so it edits a local copy that is
+		// installed on the server afterwards
+		draft, err := os.CreateTemp("", m.programName+"@*.service")
+		if err != nil {
+			return ExecutionCompleteMsg{Success: false, Error: err}
+		}
+		_, err = draft.WriteString(system.WorkerUnitTemplate(m.programName))
+		if closeErr := draft.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(draft.Name())
+			return ExecutionCompleteMsg{Success: false, Error: err}
+		}
+		return systemdUnitDraftMsg{path: draft.Name()}
+	}
+}
+
+// editSystemdUnit hands the terminal to the editor for the unit draft
+func (m SupervisorAddProgramModel) editSystemdUnit(draft string) tea.Cmd {
+	return tea.ExecProcess(exec.Command(m.editor, draft), func(err error) tea.Msg {
+		return systemdUnitEditedMsg{path: draft, err: err}
+	})
+}
+
+// installSystemdWorker writes the edited unit to the server and enables the
+// requested number of instances
+func (m SupervisorAddProgramModel) installSystemdWorker(draft string, editErr error) tea.Cmd {
+	return func() tea.Msg {
+		defer os.Remove(draft)
+		if editErr != nil {
+			return ExecutionCompleteMsg{
+				Success: false,
+				Error:   fmt.Errorf("editor failed: %w", editErr),
+			}
+		}
+		unit, err := os.ReadFile(draft)
+		if err != nil {
+			return ExecutionCompleteMsg{Success: false, Error: err}
+		}
+
+		unitPath := system.WorkerUnitPath(m.programName)
+		if err := system.WriteFile(unitPath, unit, 0644); err != nil {
+			return ExecutionCompleteMsg{
+				Success: false,
+				Error:   err,
+			}
+		}
+
+		output, err := system.Command("bash", "-c", system.WorkerInstancesScript(m.programName, m.instances)).CombinedOutput()
+		if err != nil {
+			// Leave nothing half-enabled behind
+			system.Command("bash", "-c", system.WorkerInstancesScript(m.programName, 0)).Run()
+			system.Remove(unitPath)
+			system.Command("systemctl", "daemon-reload").Run()
+			return ExecutionCompleteMsg{
+				Success: false,
+				Error:   fmt.Errorf("failed to start %s@.service: %s", m.programName, strings.TrimSpace(string(output))),
+			}
+		}
+
+		return ExecutionCompleteMsg{
+			Success: true,
+			Output:  fmt.Sprintf("Worker '%s' running as %s@1..%d under systemd", m.programName, m.programName, m.instances),
+		}
+	}
+}
+
+// title names the flow after the chosen runner
+func (m SupervisorAddProgramModel) title() string {
+	if m.runner == "systemd" {
+		return "Add systemd Worker"
+	}
+	return "Add Supervisor Program"
+}
+
 func (m SupervisorAddProgramModel) View() string {
 	if m.width == 0 {
 		return "Loading..."
@@ -231,7 +388,7 @@ func (m SupervisorAddProgramModel) View() string {
 		content = append(content, m.theme.Help.Render("Tab: Navigate "+m.theme.Symbols.Bullet+" Enter: Submit "+m.theme.Symbols.Bullet+" Esc: Cancel"))
 
 	case 1: // Editing in progress
-		header := m.theme.Title.Render("Add Supervisor Program - Editing")
+		header := m.theme.Title.Render(m.title() + " - Editing")
 		content = append(content, header)
 		content = append(content, "")
 		content = append(content, m.theme.InfoStyle.Render("Editor is open in the terminal..."))
@@ -240,7 +397,7 @@ func (m SupervisorAddProgramModel) View() string {
 		content = append(content, m.theme.DescriptionStyle.Render("Save and exit when done"))
 
 	case 2: // Result
-		header := m.theme.Title.Render("Add Supervisor Program - Result")
+		header := m.theme.Title.Render(m.title() + " - Result")
 		content = append(content, header)
 		content = append(content, "")
 
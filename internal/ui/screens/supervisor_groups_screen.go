@@ -0,0 +1,335 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// SupervisorGroupsModel defines supervisor groups and starts, stops, and
+// restarts them as a unit
+type SupervisorGroupsModel struct {
+	theme   *theme.Theme
+	width   int
+	height  int
+	manager *system.SupervisorManager
+
+	groups   []system.SupervisorGroup
+	programs []system.SupervisorProgram
+	cursor   int
+
+	mode    string // "list", "form", "confirm"
+	form    *huh.Form
+	editing string // Group being edited; empty for a new group
+	confirm Confirmation
+
+	err     error
+	success string
+}
+
+// NewSupervisorGroupsModel creates the supervisor groups screen
+func NewSupervisorGroupsModel(manager *system.SupervisorManager) SupervisorGroupsModel {
+	m := SupervisorGroupsModel{
+		theme:   theme.DefaultTheme(),
+		manager: manager,
+		mode:    "list",
+	}
+	m.load()
+	return m
+}
+
+// load reads the groups and programs
+func (m *SupervisorGroupsModel) load() {
+	var err error
+	m.groups, err = m.manager.GetGroups()
+	if err != nil {
+		m.err = err
+	}
+	m.programs, err = m.manager.GetAllPrograms()
+	if err != nil {
+		m.err = err
+	}
+	if m.cursor >= len(m.groups) {
+		m.cursor = max(len(m.groups)-1, 0)
+	}
+}
+
+func (m SupervisorGroupsModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m SupervisorGroupsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	switch m.mode {
+	case "form":
+		return m.updateForm(msg)
+	case "confirm":
+		if key, ok := msg.(tea.KeyMsg); ok {
+			var result ConfirmResult
+			m.confirm, result = m.confirm.Update(key)
+			switch result {
+			case ConfirmAccepted:
+				m.mode = "list"
+				return m.runConfirmed()
+			case ConfirmCancelled:
+				m.mode = "list"
+			}
+		}
+		return m, nil
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch key.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: SupervisorManagementScreen}
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.groups)-1 {
+			m.cursor++
+		}
+	case "n":
+		return m.openForm("")
+	}
+
+	if len(m.groups) == 0 {
+		return m, nil
+	}
+	group := m.groups[m.cursor]
+	switch key.String() {
+	case "enter", "e":
+		return m.openForm(group.Name)
+	case "r":
+		m.confirm = NewConfirmation("restart", "Restart Group",
+			fmt.Sprintf("Restart all %d programs in %s?\n%s", len(group.Programs), group.Name, strings.Join(group.Programs, ", ")), ConfirmNormal)
+		m.mode = "confirm"
+	case "s":
+		m.confirm = NewConfirmation("stop", "Stop Group",
+			fmt.Sprintf("Stop all %d programs in %s?\n%s", len(group.Programs), group.Name, strings.Join(group.Programs, ", ")), ConfirmWarning)
+		m.mode = "confirm"
+	case "t":
+		return m.groupAction("start", group.Name)
+	case "d":
+		m.confirm = NewConfirmation("delete", "Delete Group",
+			fmt.Sprintf("Delete group %s?\nIts programs keep running on their own; supervisor restarts them to leave the group.", group.Name), ConfirmWarning)
+		m.mode = "confirm"
+	}
+	return m, nil
+}
+
+// runConfirmed carries out the confirmed action on the selected group
+func (m SupervisorGroupsModel) runConfirmed() (tea.Model, tea.Cmd) {
+	name := m.groups[m.cursor].Name
+	if m.confirm.Action != "delete" {
+		return m.groupAction(m.confirm.Action, name)
+	}
+	m.err = nil
+	m.success = ""
+	if err := m.manager.DeleteGroup(name); err != nil {
+		m.err = err
+	} else {
+		m.success = "✓ Group " + name + " deleted"
+	}
+	m.load()
+	return m, nil
+}
+
+// groupAction starts, stops, or restarts every program in a group
+func (m SupervisorGroupsModel) groupAction(action, name string) (tea.Model, tea.Cmd) {
+	m.err = nil
+	m.success = ""
+	if err := m.manager.GroupAction(action, name); err != nil {
+		m.err = err
+	} else {
+		past := map[string]string{"start": "started", "stop": "stopped", "restart": "restarted"}[action]
+		m.success = fmt.Sprintf("✓ Group %s %s", name, past)
+	}
+	m.load()
+	return m, nil
+}
+
+// openForm shows the group form for a new or existing group
+func (m SupervisorGroupsModel) openForm(name string) (tea.Model, tea.Cmd) {
+	if len(m.programs) == 0 {
+		m.err = fmt.Errorf("no programs configured")
+		return m, nil
+	}
+	m.err = nil
+	m.success = ""
+	m.editing = name
+	m.form = m.buildForm()
+	m.mode = "form"
+	return m, m.form.Init()
+}
+
+// buildForm creates the form naming a group and choosing its programs
+func (m *SupervisorGroupsModel) buildForm() *huh.Form {
+	name := m.editing
+	var selected []string
+	for _, g := range m.groups {
+		if g.Name == m.editing {
+			selected = append(selected, g.Programs...)
+		}
+	}
+
+	var options []huh.Option[string]
+	for _, p := range m.programs {
+		label := p.Name
+		if p.Group != "" && p.Group != m.editing {
+			label += " (in " + p.Group + ")"
+		}
+		options = append(options, huh.NewOption(label, p.Name))
+	}
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("name").
+				Title("Group Name").
+				Description("e.g. the site name, so a deploy can restart all of its workers").
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("group name is required")
+					}
+					return nil
+				}).
+				Value(&name),
+
+			huh.NewMultiSelect[string]().
+				Key("programs").
+				Title("Programs").
+				Description("Space to toggle. A program moves out of any other group.").
+				Options(options...).
+				Height(10).
+				Value(&selected),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// updateForm handles the group form
+func (m SupervisorGroupsModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = "list"
+			m.form = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	name := strings.TrimSpace(m.form.GetString("name"))
+	programs, _ := m.form.Get("programs").([]string)
+	m.mode = "list"
+	m.form = nil
+	if err := m.manager.SaveGroup(name, programs); err != nil {
+		m.err = err
+		m.load()
+		return m, nil
+	}
+	// A rename leaves the old group behind; its programs are now in the new one
+	if m.editing != "" && m.editing != name {
+		if err := m.manager.DeleteGroup(m.editing); err != nil {
+			m.err = err
+		}
+	}
+	m.success = fmt.Sprintf("✓ Group %s saved with %d programs", name, len(programs))
+	m.load()
+	for i, g := range m.groups {
+		if g.Name == name {
+			m.cursor = i
+		}
+	}
+	return m, nil
+}
+
+func (m SupervisorGroupsModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	title := "Supervisor Groups"
+	if m.mode == "form" {
+		title = "New Group"
+		if m.editing != "" {
+			title = "Edit Group: " + m.editing
+		}
+	}
+	sections := []string{m.theme.Title.Render(title), ""}
+
+	if m.mode == "form" && m.form != nil {
+		sections = append(sections, m.form.View(), "", m.theme.Help.Render("Tab: Next • Space: Toggle • Enter: Save • Esc: Cancel"))
+	} else {
+		state := map[string]string{}
+		for _, p := range m.programs {
+			state[p.Name] = p.State
+		}
+		if len(m.groups) == 0 {
+			sections = append(sections, m.theme.WarningStyle.Render("No groups defined. Press n to group programs that restart together."))
+		}
+		for i, g := range m.groups {
+			var members []string
+			for _, p := range g.Programs {
+				style := m.theme.MenuItem
+				switch state[p] {
+				case "RUNNING":
+					style = m.theme.SuccessStyle
+				case "STOPPED", "FATAL", "EXITED", "BACKOFF":
+					style = m.theme.ErrorStyle
+				}
+				members = append(members, style.Render(p))
+			}
+			line := g.Name + " " + m.theme.DescriptionStyle.Render(fmt.Sprintf("(%d)", len(g.Programs)))
+			if i == m.cursor {
+				sections = append(sections, m.theme.SelectedItem.Render(m.theme.KeyStyle.Render("▶ ")+line))
+			} else {
+				sections = append(sections, m.theme.MenuItem.Render("  "+line))
+			}
+			sections = append(sections, "    "+strings.Join(members, m.theme.DescriptionStyle.Render(", ")))
+		}
+		if m.success != "" {
+			sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+		}
+		if m.err != nil {
+			sections = append(sections, "", m.theme.ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		}
+		sections = append(sections, "", m.theme.Help.Render("↑/↓: Navigate • n: New • Enter: Edit • r: Restart • s: Stop • t: Start • d: Delete • Esc: Back"))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
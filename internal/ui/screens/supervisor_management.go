@@ -16,10 +16,16 @@ type SupervisorManagementModel struct {
 	height   int
 	manager  *system.SupervisorManager
 	programs []system.SupervisorProgram
+	// installed is false on servers running workers under systemd only
+	installed bool
 	cursor   int
 	actions  []string
 	err      error
 	success  string
+
+	// Choosing a program for a per-program action
+	picking       bool
+	programCursor int
 }
 
 // NewSupervisorManagementModel creates a new Supervisor management model
@@ -30,11 +36,18 @@ func NewSupervisorManagementModel() SupervisorManagementModel {
 	actions := []string{
 		"List All Programs",
 		"Add New Program",
+		"Program Logs",
+		"Manage Groups",
 		"Configure XML-RPC",
 		"View XML-RPC Config",
 		"Restart Supervisor",
 		"← Back to Configurations",
 	}
+	// Without supervisord, new programs can only run as systemd workers
+	installed := manager.IsInstalled()
+	if !installed {
+		actions = []string{"Add New Program", "← Back to Configurations"}
+	}
 	
 	return SupervisorManagementModel{
 		theme:    theme.DefaultTheme(),
@@ -42,6 +55,7 @@ func NewSupervisorManagementModel() SupervisorManagementModel {
 		programs: programs,
 		cursor:   0,
 		actions:  actions,
+		installed: installed,
 	}
 }
 
@@ -57,6 +71,9 @@ func (m SupervisorManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.picking {
+			return m.updatePicking(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -79,6 +96,37 @@ func (m SupervisorManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updatePicking handles choosing a program from the list
+func (m SupervisorManagementModel) updatePicking(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.picking = false
+	case "up", "k":
+		if m.programCursor > 0 {
+			m.programCursor--
+		}
+	case "down", "j":
+		if m.programCursor < len(m.programs)-1 {
+			m.programCursor++
+		}
+	case "enter", " ":
+		m.picking = false
+		program := m.programs[m.programCursor].Name
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: SupervisorProgramLogsScreen,
+				Data: map[string]interface{}{
+					"manager": m.manager,
+					"program": program,
+				},
+			}
+		}
+	}
+	return m, nil
+}
+
 func (m SupervisorManagementModel) executeAction() (SupervisorManagementModel, tea.Cmd) {
 	m.err = nil
 	m.success = ""
@@ -103,6 +151,26 @@ func (m SupervisorManagementModel) executeAction() (SupervisorManagementModel, t
 			}
 		}
 
+	case "Program Logs":
+		if len(m.programs) == 0 {
+			m.err = fmt.Errorf("no programs configured")
+			return m, nil
+		}
+		m.picking = true
+		if m.programCursor >= len(m.programs) {
+			m.programCursor = 0
+		}
+
+	case "Manage Groups":
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: SupervisorGroupsScreen,
+				Data: map[string]interface{}{
+					"manager": m.manager,
+				},
+			}
+		}
+
 	case "Configure XML-RPC":
 		return m, func() tea.Msg {
 			return NavigateMsg{
@@ -146,16 +214,23 @@ func (m SupervisorManagementModel) View() string {
 	header := m.theme.Title.Render("⚙️  Supervisor Management")
 
 	var progInfo []string
+	if !m.installed {
+		progInfo = append(progInfo, m.theme.WarningStyle.Render("Supervisor is not installed; new programs run as systemd template units"))
+	}
 	progInfo = append(progInfo, m.theme.Label.Render(fmt.Sprintf("Total Programs: %d", len(m.programs))))
 	if len(m.programs) > 0 {
-		for _, prog := range m.programs {
+		for i, prog := range m.programs {
 			stateStyle := m.theme.MenuItem
 			if prog.State == "RUNNING" {
 				stateStyle = m.theme.SuccessStyle
 			} else if prog.State == "STOPPED" {
 				stateStyle = m.theme.ErrorStyle
 			}
-			progInfo = append(progInfo, m.theme.MenuItem.Render(fmt.Sprintf("  • %s ", prog.Name))+stateStyle.Render(fmt.Sprintf("[%s]", prog.State)))
+			if m.picking && i == m.programCursor {
+				progInfo = append(progInfo, m.theme.SelectedItem.Render(m.theme.KeyStyle.Render("▶ ")+prog.ProcessName()+" ")+stateStyle.Render(fmt.Sprintf("[%s]", prog.State)))
+				continue
+			}
+			progInfo = append(progInfo, m.theme.MenuItem.Render(fmt.Sprintf("  • %s ", prog.ProcessName()))+stateStyle.Render(fmt.Sprintf("[%s]", prog.State)))
 		}
 	} else {
 		progInfo = append(progInfo, m.theme.WarningStyle.Render("  No programs configured"))
@@ -193,6 +268,9 @@ func (m SupervisorManagementModel) View() string {
 	}
 
 	help := m.theme.Help.Render("↑/↓: Navigate • Enter: Execute • Esc: Back • q: Quit")
+	if m.picking {
+		help = m.theme.Help.Render("↑/↓: Choose program • Enter: Select • Esc: Cancel")
+	}
 
 	sections := []string{
 		header,
@@ -0,0 +1,262 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// SupervisorProgramLogsModel tails, rotates, and clears one supervisor
+// program's logs
+type SupervisorProgramLogsModel struct {
+	theme   *theme.Theme
+	width   int
+	height  int
+	manager *system.SupervisorManager
+	program string
+
+	logs    system.SupervisorLogConfig
+	actions []string
+	cursor  int
+
+	form    *huh.Form
+	confirm Confirmation
+	mode    string // "menu", "rotation", "confirm"
+
+	err     error
+	success string
+}
+
+// NewSupervisorProgramLogsModel creates the log actions for a program
+func NewSupervisorProgramLogsModel(manager *system.SupervisorManager, program string) SupervisorProgramLogsModel {
+	m := SupervisorProgramLogsModel{
+		theme:   theme.DefaultTheme(),
+		manager: manager,
+		program: program,
+		mode:    "menu",
+	}
+	m.load()
+	return m
+}
+
+// load reads the program's log settings and builds the action list
+func (m *SupervisorProgramLogsModel) load() {
+	m.logs, m.err = m.manager.ProgramLogConfig(m.program)
+	m.actions = []string{"Tail stdout Log"}
+	if !m.logs.RedirectStderr {
+		m.actions = append(m.actions, "Tail stderr Log")
+	}
+	m.actions = append(m.actions, "Log Rotation", "Clear Logs", "← Back to Supervisor")
+	if m.cursor >= len(m.actions) {
+		m.cursor = 0
+	}
+}
+
+func (m SupervisorProgramLogsModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m SupervisorProgramLogsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+
+	switch m.mode {
+	case "rotation":
+		return m.updateRotation(msg)
+	case "confirm":
+		if key, ok := msg.(tea.KeyMsg); ok {
+			var result ConfirmResult
+			m.confirm, result = m.confirm.Update(key)
+			switch result {
+			case ConfirmAccepted:
+				m.mode = "menu"
+				if err := m.manager.ClearProgramLogs(m.program); err != nil {
+					m.err = err
+				} else {
+					m.success = "✓ Logs cleared for " + m.program
+				}
+			case ConfirmCancelled:
+				m.mode = "menu"
+			}
+		}
+		return m, nil
+	}
+
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: SupervisorManagementScreen}
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.actions)-1 {
+				m.cursor++
+			}
+		case "enter", " ":
+			return m.executeAction()
+		}
+	}
+	return m, nil
+}
+
+func (m SupervisorProgramLogsModel) executeAction() (SupervisorProgramLogsModel, tea.Cmd) {
+	m.err = nil
+	m.success = ""
+
+	switch m.actions[m.cursor] {
+	case "Tail stdout Log", "Tail stderr Log":
+		stream := "stdout"
+		if m.actions[m.cursor] == "Tail stderr Log" {
+			stream = "stderr"
+		}
+		path, err := m.manager.ProgramLogFile(m.program, stream)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		return m, func() tea.Msg {
+			return NavigateMsg{
+				Screen: LogViewerScreen,
+				Data:   map[string]interface{}{"path": path, "group": "supervisor"},
+			}
+		}
+
+	case "Log Rotation":
+		m.form = m.buildRotationForm()
+		m.mode = "rotation"
+		return m, m.form.Init()
+
+	case "Clear Logs":
+		m.confirm = NewConfirmation("clear", "Clear Logs",
+			fmt.Sprintf("Empty the stdout and stderr logs of %s?\nRotated backups are kept.", m.program), ConfirmWarning)
+		m.mode = "confirm"
+
+	case "← Back to Supervisor":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: SupervisorManagementScreen}
+		}
+	}
+	return m, nil
+}
+
+// buildRotationForm creates the form for the log size and backup count
+func (m *SupervisorProgramLogsModel) buildRotationForm() *huh.Form {
+	maxBytes := m.logs.MaxBytes
+	backups := strconv.Itoa(m.logs.Backups)
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("maxBytes").
+				Title("Rotate At").
+				Description("Log size that triggers rotation (stdout_logfile_maxbytes); 0 never rotates").
+				Placeholder("10MB").
+				Validate(system.ValidateSupervisorBytes).
+				Value(&maxBytes),
+
+			huh.NewInput().
+				Key("backups").
+				Title("Backups").
+				Description("Rotated logs to keep (stdout_logfile_backups)").
+				Validate(func(s string) error {
+					if n, err := strconv.Atoi(strings.TrimSpace(s)); err != nil || n < 0 {
+						return fmt.Errorf("enter a number of 0 or more")
+					}
+					return nil
+				}).
+				Value(&backups),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// updateRotation handles the rotation form
+func (m SupervisorProgramLogsModel) updateRotation(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = "menu"
+			m.form = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State == huh.StateCompleted {
+		backups, _ := strconv.Atoi(strings.TrimSpace(m.form.GetString("backups")))
+		if err := m.manager.SetProgramLogRotation(m.program, m.form.GetString("maxBytes"), backups); err != nil {
+			m.err = err
+		} else {
+			m.success = "✓ Log rotation updated; supervisor restarted " + m.program + " to apply it"
+		}
+		m.mode = "menu"
+		m.form = nil
+		m.load()
+	}
+	return m, cmd
+}
+
+func (m SupervisorProgramLogsModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	header := m.theme.Title.Render("Supervisor Logs: " + m.program)
+
+	stderr := m.logs.StderrLogfile
+	if m.logs.RedirectStderr {
+		stderr = "redirected to stdout"
+	}
+	info := []string{
+		m.theme.Label.Render("stdout:   ") + m.theme.InfoStyle.Render(m.logs.StdoutLogfile),
+		m.theme.Label.Render("stderr:   ") + m.theme.InfoStyle.Render(stderr),
+		m.theme.Label.Render("Rotation: ") + m.theme.InfoStyle.Render(fmt.Sprintf("at %s, keep %d", m.logs.MaxBytes, m.logs.Backups)),
+	}
+	sections := []string{header, "", lipgloss.JoinVertical(lipgloss.Left, info...), ""}
+
+	if m.mode == "rotation" && m.form != nil {
+		sections = append(sections, m.form.View(), "", m.theme.Help.Render("Tab: Next • Enter: Save • Esc: Cancel"))
+	} else {
+		for i, action := range m.actions {
+			if i == m.cursor {
+				sections = append(sections, m.theme.SelectedItem.Render(m.theme.KeyStyle.Render("▶ ")+action))
+			} else {
+				sections = append(sections, m.theme.MenuItem.Render("  "+action))
+			}
+		}
+		if m.success != "" {
+			sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+		}
+		if m.err != nil {
+			sections = append(sections, "", m.theme.ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		}
+		sections = append(sections, "", m.theme.Help.Render("↑/↓: Navigate • Enter: Select • Esc: Back • q: Quit"))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
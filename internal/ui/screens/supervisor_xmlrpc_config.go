@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/iperamuna/ravact/internal/system"
 	"github.com/iperamuna/ravact/internal/ui/theme"
+	"github.com/iperamuna/ravact/internal/vault"
 )
 
 // SupervisorXMLRPCConfigModel represents the XML-RPC configuration screen
@@ -25,6 +26,8 @@ type SupervisorXMLRPCConfigModel struct {
 	password string
 	err      error
 	success  bool
+
+	vaultStatus string
 }
 
 // NewSupervisorXMLRPCConfigModel creates a new XML-RPC config model
@@ -169,6 +172,11 @@ func (m SupervisorXMLRPCConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m SupervisorXMLRPCConfigModel) saveConfig() (SupervisorXMLRPCConfigModel, tea.Cmd) {
+	m.ip = m.form.GetString("ip")
+	m.port = m.form.GetString("port")
+	m.username = m.form.GetString("username")
+	m.password = m.form.GetString("password")
+
 	// Apply defaults if empty
 	ip := m.ip
 	port := m.port
@@ -189,6 +197,9 @@ func (m SupervisorXMLRPCConfigModel) saveConfig() (SupervisorXMLRPCConfigModel,
 
 	m.success = true
 	m.err = nil
+	if m.password != "" {
+		m.vaultStatus = vault.RecordMessage("Supervisor XML-RPC", m.username, m.password, ip+":"+port)
+	}
 	return m, nil
 }
 
@@ -201,6 +212,9 @@ func (m SupervisorXMLRPCConfigModel) View() string {
 	if m.success {
 		msg := m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " XML-RPC configured successfully!")
 		note := m.theme.DescriptionStyle.Render("Supervisor will be restarted to apply changes.")
+		if m.vaultStatus != "" {
+			note = lipgloss.JoinVertical(lipgloss.Center, note, m.theme.DescriptionStyle.Render(m.vaultStatus))
+		}
 		help := m.theme.Help.Render("Press any key to continue...")
 		content := lipgloss.JoinVertical(lipgloss.Center, "", msg, "", note, "", help)
 		bordered := m.theme.RenderBox(content)
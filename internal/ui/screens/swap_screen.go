@@ -0,0 +1,299 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// swapStateMsg carries the host's swap state
+type swapStateMsg struct {
+	state system.SwapState
+	err   error
+}
+
+// SwapModel creates, resizes, and tunes the swap file
+type SwapModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	state   system.SwapState
+	loading bool
+
+	mode    string // "", "form", or "confirm"
+	form    *huh.Form
+	pending system.SwapSettings
+	confirm Confirmation
+
+	err error
+}
+
+// NewSwapModel creates the swap screen
+func NewSwapModel() SwapModel {
+	return SwapModel{
+		theme:   theme.DefaultTheme(),
+		loading: true,
+	}
+}
+
+// Init reads the swap state
+func (m SwapModel) Init() tea.Cmd {
+	return func() tea.Msg {
+		state, err := system.CurrentSwap()
+		return swapStateMsg{state: state, err: err}
+	}
+}
+
+// Update handles messages for the swap screen
+func (m SwapModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case swapStateMsg:
+		m.loading = false
+		m.state = msg.state
+		m.err = msg.err
+		return m, nil
+	}
+
+	if m.mode == "form" {
+		return m.updateForm(msg)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if keyMsg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	if m.mode == "confirm" {
+		var result ConfirmResult
+		m.confirm, result = m.confirm.Update(keyMsg)
+		switch result {
+		case ConfirmAccepted:
+			m.mode = ""
+			command, description := m.pending.Command(), "Configuring the swap file"
+			if m.confirm.Action == "remove" {
+				command, description = system.SwapRemoveScript(), "Removing the swap file"
+			}
+			return m, func() tea.Msg {
+				return ExecutionStartMsg{Command: command, Description: description}
+			}
+		case ConfirmCancelled:
+			m.mode = ""
+		}
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: SetupMenuScreen}
+		}
+	case "e", "enter":
+		if !m.loading {
+			return m.openForm()
+		}
+	case "x":
+		if m.loading || m.state.FileSize == 0 {
+			break
+		}
+		m.confirm = NewConfirmation("remove", "Remove Swap File",
+			fmt.Sprintf("Turn off and delete %s?\n\nSwapped pages move back into RAM first; this fails if they do not fit.", system.SwapFilePath),
+			ConfirmWarning)
+		m.mode = "confirm"
+	case "r":
+		if !m.loading {
+			m.loading = true
+			return m, m.Init()
+		}
+	}
+	return m, nil
+}
+
+// openForm shows the swap settings filled with the current values
+func (m SwapModel) openForm() (tea.Model, tea.Cmd) {
+	current := m.state.Settings()
+	size := system.FormatSwapSize(current.SizeMB)
+	swappiness := strconv.Itoa(current.Swappiness)
+	pressure := strconv.Itoa(current.CachePressure)
+	state := m.state
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("size").
+				Title("Swap file size").
+				Description(fmt.Sprintf("e.g. 2G or 512M; %s suggested for %s of RAM",
+					system.FormatSwapSize(system.RecommendedSwapMB(state.MemTotal)), system.FormatBytes(state.MemTotal))).
+				Value(&size).
+				Validate(func(v string) error {
+					mb, err := system.ParseSwapSize(v)
+					if err != nil {
+						return err
+					}
+					s := current
+					s.SizeMB = mb
+					return s.Validate(state)
+				}),
+			huh.NewInput().
+				Key("swappiness").
+				Title("Swappiness (vm.swappiness)").
+				Description("0-100; low values keep PHP-FPM in RAM and swap only under pressure").
+				Value(&swappiness).
+				Validate(func(v string) error {
+					n, err := strconv.Atoi(strings.TrimSpace(v))
+					if err != nil {
+						return fmt.Errorf("enter a number")
+					}
+					s := current
+					s.Swappiness = n
+					return s.Validate(state)
+				}),
+			huh.NewInput().
+				Key("pressure").
+				Title("Cache pressure (vm.vfs_cache_pressure)").
+				Description("Below 100 keeps directory and inode caches longer").
+				Value(&pressure).
+				Validate(func(v string) error {
+					n, err := strconv.Atoi(strings.TrimSpace(v))
+					if err != nil {
+						return fmt.Errorf("enter a number")
+					}
+					s := current
+					s.CachePressure = n
+					return s.Validate(state)
+				}),
+		),
+	).WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+	m.mode = "form"
+	m.err = nil
+	return m, m.form.Init()
+}
+
+// updateForm passes messages to the settings form
+func (m SwapModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.mode = ""
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	switch m.form.State {
+	case huh.StateCompleted:
+		size, _ := system.ParseSwapSize(m.form.GetString("size"))
+		swappiness, _ := strconv.Atoi(strings.TrimSpace(m.form.GetString("swappiness")))
+		pressure, _ := strconv.Atoi(strings.TrimSpace(m.form.GetString("pressure")))
+		m.pending = system.SwapSettings{SizeMB: size, Swappiness: swappiness, CachePressure: pressure}
+		if err := m.pending.Validate(m.state); err != nil {
+			m.err = err
+			m.mode = ""
+			return m, nil
+		}
+
+		message := fmt.Sprintf("Create a %s swap file at %s, add it to /etc/fstab, and write %s:\n\nvm.swappiness = %d\nvm.vfs_cache_pressure = %d",
+			system.FormatSwapSize(m.pending.SizeMB), system.SwapFilePath, system.SwapSysctlPath, m.pending.Swappiness, m.pending.CachePressure)
+		severity := ConfirmNormal
+		if current := m.state.Settings(); m.state.FileSize > 0 && current.SizeMB != m.pending.SizeMB {
+			message = fmt.Sprintf("Resize %s from %s to %s?\n\nThe file is turned off while it is rebuilt; swapped pages move back into RAM first.\n\nvm.swappiness = %d\nvm.vfs_cache_pressure = %d",
+				system.SwapFilePath, system.FormatSwapSize(current.SizeMB), system.FormatSwapSize(m.pending.SizeMB), m.pending.Swappiness, m.pending.CachePressure)
+			severity = ConfirmWarning
+		}
+		m.confirm = NewConfirmation("configure", "Configure Swap", message, severity)
+		m.mode = "confirm"
+		return m, nil
+	case huh.StateAborted:
+		m.mode = ""
+		return m, nil
+	}
+	return m, cmd
+}
+
+// View renders the swap screen
+func (m SwapModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Swap File"),
+		m.theme.DescriptionStyle.Render("Swap keeps small servers alive through memory spikes such as composer installs"),
+		"",
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := "e: Configure" + bullet + "r: Refresh" + bullet + "Esc: Back"
+	switch {
+	case m.mode == "form":
+		sections = append(sections, m.form.View())
+		help = "Enter: Next" + bullet + "Esc: Cancel"
+	case m.loading:
+		sections = append(sections, m.theme.InfoStyle.Render("Reading swap..."))
+	default:
+		sections = append(sections, m.renderState()...)
+		if m.state.FileSize > 0 {
+			help = "e: Configure" + bullet + "x: Remove swap file" + bullet + "r: Refresh" + bullet + "Esc: Back"
+		}
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// renderState shows memory, the active swap, and the tuning
+func (m SwapModel) renderState() []string {
+	row := func(label, value string) string {
+		return m.theme.Label.Render(fmt.Sprintf("%-16s", label)) + m.theme.MenuItem.Render(value)
+	}
+	s := m.state
+	lines := []string{row("Memory", system.FormatBytes(s.MemTotal))}
+	if len(s.Devices) == 0 {
+		lines = append(lines, m.theme.Label.Render(fmt.Sprintf("%-16s", "Swap"))+m.theme.WarningStyle.Render("none"))
+	}
+	for _, d := range s.Devices {
+		lines = append(lines, row("Swap", fmt.Sprintf("%s (%s) %s, %s used", d.Path, d.Type, system.FormatBytes(d.Size), system.FormatBytes(d.Used))))
+	}
+	tuning := ""
+	if !s.Tuned {
+		tuning = " (kernel default)"
+	}
+	lines = append(lines,
+		row("Swappiness", strconv.Itoa(s.Swappiness)+tuning),
+		row("Cache pressure", strconv.Itoa(s.CachePressure)+tuning),
+		row("Free on /", system.FormatBytes(s.DiskFree)),
+	)
+	return lines
+}
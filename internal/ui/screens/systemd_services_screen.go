@@ -0,0 +1,379 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// systemdActions are the per-unit actions, as shown in the action menu
+var systemdActions = []struct {
+	label  string
+	action string
+}{
+	{"Start", "start"},
+	{"Stop", "stop"},
+	{"Restart", "restart"},
+	{"Reload configuration", "reload"},
+	{"Enable (start on boot)", "enable"},
+	{"Disable (don't start on boot)", "disable"},
+	{"View Status", "status"},
+	{"View Logs", "logs"},
+	{"Edit Unit File", "edit"},
+	{"← Back to List", "back"},
+}
+
+// SystemdServicesModel manages the systemd units matching a set of patterns
+type SystemdServicesModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	mode     string // "list", "actions", "confirm", "edit", "patterns"
+	patterns []string
+	units    []system.SystemdUnit
+	cursor   int
+
+	actionCursor int
+	confirm      Confirmation
+
+	editor   TextEditor
+	editPath string
+
+	patternInput string
+
+	err     error
+	success string
+}
+
+// NewSystemdServicesModel creates a new systemd services model
+func NewSystemdServicesModel() SystemdServicesModel {
+	m := SystemdServicesModel{
+		theme: theme.DefaultTheme(),
+		mode:  "list",
+	}
+	m.patterns, m.err = system.LoadSystemdPatterns()
+	if m.err == nil {
+		m.reload()
+	}
+	return m
+}
+
+// reload lists the units matching the current patterns
+func (m *SystemdServicesModel) reload() {
+	units, err := system.ListSystemdUnits(m.patterns)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.units = units
+	if m.cursor >= len(m.units) {
+		m.cursor = 0
+	}
+}
+
+// Init initializes the systemd services screen
+func (m SystemdServicesModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the systemd services screen
+func (m SystemdServicesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case "actions":
+			return m.updateActions(msg)
+		case "confirm":
+			return m.updateConfirm(msg)
+		case "edit":
+			return m.updateEdit(msg)
+		case "patterns":
+			return m.updatePatterns(msg)
+		}
+		return m.updateList(msg)
+	}
+
+	return m, nil
+}
+
+// updateList handles keys on the unit list
+func (m SystemdServicesModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: MainMenuScreen}
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.units)-1 {
+			m.cursor++
+		}
+	case "r":
+		m.err = nil
+		m.success = ""
+		m.reload()
+	case "p":
+		m.patternInput = strings.Join(m.patterns, " ")
+		m.err = nil
+		m.mode = "patterns"
+	case "enter", " ":
+		if len(m.units) > 0 {
+			m.actionCursor = 0
+			m.success = ""
+			m.err = nil
+			m.mode = "actions"
+		}
+	}
+	return m, nil
+}
+
+// updateActions handles the per-unit action menu
+func (m SystemdServicesModel) updateActions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.mode = "list"
+	case "up", "k":
+		if m.actionCursor > 0 {
+			m.actionCursor--
+		}
+	case "down", "j":
+		if m.actionCursor < len(systemdActions)-1 {
+			m.actionCursor++
+		}
+	case "enter", " ":
+		return m.executeAction()
+	}
+	return m, nil
+}
+
+// executeAction runs the selected action on the selected unit
+func (m SystemdServicesModel) executeAction() (tea.Model, tea.Cmd) {
+	unit := m.units[m.cursor]
+	action := systemdActions[m.actionCursor].action
+
+	switch action {
+	case "back":
+		m.mode = "list"
+		return m, nil
+
+	case "stop", "disable":
+		// Stopping nginx or the database takes sites down
+		m.confirm = NewConfirmation(action, "Confirm",
+			fmt.Sprintf("%s %s?\nSites depending on it may become unavailable.", systemdActions[m.actionCursor].label, unit.Name), ConfirmWarning)
+		m.mode = "confirm"
+		return m, nil
+
+	case "logs":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: LogViewerScreen, Data: map[string]interface{}{"unit": unit.Name}}
+		}
+
+	case "edit":
+		content, path, err := system.ReadSystemdUnit(unit.Name)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.editor = NewTextEditor(content)
+		m.editPath = path
+		m.mode = "edit"
+		return m, nil
+	}
+
+	return m, runSystemctl(action, unit.Name)
+}
+
+// runSystemctl starts a systemctl action on the execution screen
+func runSystemctl(action, unit string) tea.Cmd {
+	command, description := system.SystemctlCommand(action, unit)
+	return func() tea.Msg {
+		return ExecutionStartMsg{Command: command, Description: description}
+	}
+}
+
+// updateConfirm handles the stop/disable confirmation
+func (m SystemdServicesModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var result ConfirmResult
+	m.confirm, result = m.confirm.Update(msg)
+	switch result {
+	case ConfirmAccepted:
+		m.mode = "actions"
+		return m, runSystemctl(m.confirm.Action, m.units[m.cursor].Name)
+	case ConfirmCancelled:
+		m.mode = "actions"
+	}
+	return m, nil
+}
+
+// updateEdit handles keys while editing a unit file
+func (m SystemdServicesModel) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = "actions"
+		return m, nil
+
+	case "ctrl+s":
+		content := strings.TrimRight(m.editor.Value(), "\n ") + "\n"
+		if err := system.SaveSystemdUnit(m.editPath, content); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.err = nil
+		m.success = fmt.Sprintf("%s Saved %s and reloaded systemd. Restart the unit to apply.", m.theme.Symbols.CheckMark, m.editPath)
+		m.mode = "actions"
+		return m, snapshotConfig("Edit "+m.units[m.cursor].Name, true)
+	}
+
+	m.editor = m.editor.Update(msg)
+	return m, nil
+}
+
+// updatePatterns handles editing the unit patterns
+func (m SystemdServicesModel) updatePatterns(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = "list"
+	case tea.KeyBackspace:
+		if len(m.patternInput) > 0 {
+			m.patternInput = m.patternInput[:len(m.patternInput)-1]
+		}
+	case tea.KeySpace:
+		m.patternInput += " "
+	case tea.KeyRunes:
+		m.patternInput += string(msg.Runes)
+	case tea.KeyEnter:
+		patterns := system.ParseSystemdPatterns(m.patternInput)
+		if len(patterns) == 0 {
+			patterns = system.DefaultSystemdPatterns
+		}
+		if err := system.SaveSystemdPatterns(patterns); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.patterns = patterns
+		m.err = nil
+		m.mode = "list"
+		m.reload()
+	}
+	return m, nil
+}
+
+// statusBadge renders a unit's active state
+func (m SystemdServicesModel) statusBadge(unit system.SystemdUnit) string {
+	switch unit.Active {
+	case "active":
+		return m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " " + unit.Sub)
+	case "failed":
+		return m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark + " failed")
+	case "activating", "deactivating", "reloading":
+		return m.theme.WarningStyle.Render(unit.Active)
+	}
+	return m.theme.DescriptionStyle.Render(unit.Active)
+}
+
+// View renders the systemd services screen
+func (m SystemdServicesModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	var sections []string
+	var help string
+
+	switch m.mode {
+	case "edit":
+		sections = append(sections,
+			m.theme.Title.Render("Edit Unit: "+m.units[m.cursor].Name),
+			m.theme.DescriptionStyle.Render("Saving to "+m.editPath),
+			"",
+			m.editor.View(m.theme, m.height-14),
+		)
+		help = "Ctrl+S: Save and daemon-reload " + m.theme.Symbols.Bullet + " Esc: Cancel"
+
+	case "patterns":
+		sections = append(sections,
+			m.theme.Title.Render("Unit Patterns"),
+			m.theme.DescriptionStyle.Render("Space-separated systemd unit patterns, e.g. nginx php*-fpm redis-server"),
+			"",
+			m.theme.Label.Render("Patterns: ")+m.theme.SelectedItem.Render(m.patternInput+"_"),
+		)
+		help = "Enter: Save " + m.theme.Symbols.Bullet + " Esc: Cancel"
+
+	case "actions":
+		unit := m.units[m.cursor]
+		sections = append(sections,
+			m.theme.Title.Render(unit.Name),
+			m.statusBadge(unit)+"  "+m.theme.DescriptionStyle.Render(unit.UnitState+"  "+unit.Description),
+			"",
+		)
+		for i, a := range systemdActions {
+			if i == m.actionCursor {
+				sections = append(sections, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+m.theme.SelectedItem.Render(a.label))
+			} else {
+				sections = append(sections, "  "+m.theme.MenuItem.Render(a.label))
+			}
+		}
+		help = m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Select " + m.theme.Symbols.Bullet + " Esc: Back"
+
+	default:
+		sections = append(sections,
+			m.theme.Title.Render("Systemd Services"),
+			m.theme.DescriptionStyle.Render("Matching: "+strings.Join(m.patterns, " ")),
+			"",
+		)
+		if len(m.units) == 0 && m.err == nil {
+			sections = append(sections, m.theme.DescriptionStyle.Render("No installed units match these patterns. Press p to change them."))
+		}
+		for i, unit := range m.units {
+			enabled := m.theme.DescriptionStyle.Render(fmt.Sprintf("%-9s", unit.UnitState))
+			if unit.Enabled() {
+				enabled = m.theme.InfoStyle.Render(fmt.Sprintf("%-9s", unit.UnitState))
+			}
+			name := fmt.Sprintf("%-32s", strings.TrimSuffix(unit.Name, ".service"))
+			if i == m.cursor {
+				name = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ") + m.theme.SelectedItem.Render(name)
+			} else {
+				name = "  " + m.theme.MenuItem.Render(name)
+			}
+			sections = append(sections, name+" "+enabled+" "+m.statusBadge(unit))
+		}
+		help = m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet +
+			" Enter: Actions " + m.theme.Symbols.Bullet + " p: Patterns " + m.theme.Symbols.Bullet +
+			" r: Refresh " + m.theme.Symbols.Bullet + " Esc: Back"
+	}
+
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.success))
+	}
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
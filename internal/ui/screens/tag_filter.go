@@ -0,0 +1,176 @@
+package screens
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// TagFilter adds tag editing and tag-based filtering to a list screen.
+// Screens keep one as a field: f cycles the active tag, T edits the tags of
+// the selected item, and bulk actions apply to the items matching Active.
+type TagFilter struct {
+	Kind   system.TagKind
+	Active string // Tag the list is filtered by; empty shows everything
+
+	store   *system.TagStore
+	editing string // Resource whose tags are being edited
+	input   string
+	err     error
+}
+
+// NewTagFilter loads the tag store for a kind of resource
+func NewTagFilter(kind system.TagKind) TagFilter {
+	f := TagFilter{Kind: kind}
+	f.store, f.err = system.LoadTagStore()
+	if f.store == nil {
+		f.store = &system.TagStore{Tags: map[system.TagKind]map[string][]string{}}
+	}
+	return f
+}
+
+// Tags returns the tags on a resource
+func (f TagFilter) Tags(name string) []string {
+	return f.store.Get(f.Kind, name)
+}
+
+// Matches reports whether a resource is shown under the active filter
+func (f TagFilter) Matches(name string) bool {
+	return f.Active == "" || f.store.Has(f.Kind, name, f.Active)
+}
+
+// Cycle moves the filter to the next tag, wrapping back to showing everything
+func (f TagFilter) Cycle() TagFilter {
+	all := f.store.All(f.Kind)
+	if len(all) == 0 {
+		f.Active = ""
+		return f
+	}
+	if f.Active == "" {
+		f.Active = all[0]
+		return f
+	}
+	for i, tag := range all {
+		if tag == f.Active {
+			if i+1 < len(all) {
+				f.Active = all[i+1]
+			} else {
+				f.Active = ""
+			}
+			return f
+		}
+	}
+	f.Active = ""
+	return f
+}
+
+// Editing reports whether tags are being entered
+func (f TagFilter) Editing() bool {
+	return f.editing != ""
+}
+
+// StartEdit begins editing the tags of a resource
+func (f TagFilter) StartEdit(name string) TagFilter {
+	f.editing = name
+	f.input = strings.Join(f.Tags(name), ", ")
+	f.err = nil
+	return f
+}
+
+// Update handles a key press while editing. It returns true once the edit
+// has been saved or cancelled.
+func (f TagFilter) Update(msg tea.KeyMsg) (TagFilter, bool) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		f.editing = ""
+		f.err = nil
+		return f, true
+	case tea.KeyEnter:
+		tags, err := system.ParseTags(f.input)
+		if err != nil {
+			f.err = err
+			return f, false
+		}
+		f.store.Set(f.Kind, f.editing, tags)
+		if err := f.store.Save(); err != nil {
+			f.err = err
+			return f, false
+		}
+		f.editing = ""
+		f.err = nil
+		// Drop the filter if no resource carries the tag any more
+		if f.Active != "" && !slices.Contains(f.store.All(f.Kind), f.Active) {
+			f.Active = ""
+		}
+		return f, true
+	case tea.KeyBackspace:
+		if len(f.input) > 0 {
+			runes := []rune(f.input)
+			f.input = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		f.input += " "
+	case tea.KeyRunes:
+		f.input += string(msg.Runes)
+	}
+	return f, false
+}
+
+// Badges renders the tags on a resource
+func (f TagFilter) Badges(t *theme.Theme, name string) string {
+	tags := f.Tags(name)
+	if len(tags) == 0 {
+		return ""
+	}
+	return t.InfoStyle.Render("#" + strings.Join(tags, " #"))
+}
+
+// View renders the tag input while editing, otherwise the active filter
+func (f TagFilter) View(t *theme.Theme) string {
+	if f.editing != "" {
+		lines := []string{
+			t.Label.Render("Tags for "+f.editing+": ") + t.SelectedItem.Render(f.input+"_"),
+			t.DescriptionStyle.Render("Comma separated, e.g. client-acme, production " + t.Symbols.Bullet + " Enter: Save " + t.Symbols.Bullet + " Esc: Cancel"),
+		}
+		if f.err != nil {
+			lines = append(lines, t.ErrorStyle.Render(t.Symbols.CrossMark+" "+f.err.Error()))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	var line string
+	if f.Active != "" {
+		line = t.Label.Render("Filter: ") + t.InfoStyle.Render("#"+f.Active)
+	} else if len(f.store.All(f.Kind)) > 0 {
+		line = t.DescriptionStyle.Render("Tags: " + strings.Join(f.store.All(f.Kind), ", "))
+	}
+	if f.err != nil {
+		if line != "" {
+			line += "\n"
+		}
+		line += t.ErrorStyle.Render(t.Symbols.CrossMark + " " + f.err.Error())
+	}
+	return line
+}
+
+// Help returns the key hints for tagging
+func (f TagFilter) Help(t *theme.Theme) string {
+	return " " + t.Symbols.Bullet + " T: Tags " + t.Symbols.Bullet + " f: Filter by tag"
+}
+
+// filterByTag returns the items whose name matches the active tag filter
+func filterByTag[T any](f TagFilter, items []T, name func(T) string) []T {
+	if f.Active == "" {
+		return items
+	}
+	var filtered []T
+	for _, item := range items {
+		if f.Matches(name(item)) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,227 @@
+package screens
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// AttachTaskMsg asks the root model to show a detached task's output
+type AttachTaskMsg struct {
+	Task system.Task
+}
+
+// tasksLoadedMsg carries the tasks on the managed host
+type tasksLoadedMsg struct {
+	tasks []system.TaskStatus
+	err   error
+}
+
+// taskActionMsg reports the result of stopping or removing a task
+type taskActionMsg struct {
+	done string
+	err  error
+}
+
+// TasksModel lists the tasks running detached on the managed host, so
+// they can be followed again after ravact exits or the connection drops
+type TasksModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	tasks   []system.TaskStatus
+	cursor  int
+	loading bool
+	err     error
+	success string
+
+	confirm    Confirmation
+	confirming bool
+}
+
+// NewTasksModel creates the background tasks screen
+func NewTasksModel() TasksModel {
+	return TasksModel{
+		theme:   theme.DefaultTheme(),
+		loading: true,
+	}
+}
+
+// loadTasks reads the tasks from the managed host
+func loadTasks() tea.Msg {
+	tasks, err := system.ListTasks()
+	return tasksLoadedMsg{tasks: tasks, err: err}
+}
+
+// Init loads the tasks
+func (m TasksModel) Init() tea.Cmd {
+	return loadTasks
+}
+
+// Update handles messages for the tasks screen
+func (m TasksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tasksLoadedMsg:
+		m.loading = false
+		m.tasks = msg.tasks
+		m.err = msg.err
+		if m.cursor >= len(m.tasks) {
+			m.cursor = 0
+		}
+		return m, nil
+
+	case taskActionMsg:
+		m.err = msg.err
+		if msg.err == nil {
+			m.success = msg.done
+		}
+		return m, loadTasks
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if m.confirming {
+		if key.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		var result ConfirmResult
+		m.confirm, result = m.confirm.Update(key)
+		switch result {
+		case ConfirmAccepted:
+			m.confirming = false
+			task := m.tasks[m.cursor].Task
+			return m, func() tea.Msg {
+				return taskActionMsg{done: "Stopped " + task.Description, err: system.StopTask(task)}
+			}
+		case ConfirmCancelled:
+			m.confirming = false
+		}
+		return m, nil
+	}
+
+	switch key.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: MainMenuScreen}
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.tasks)-1 {
+			m.cursor++
+		}
+	case "r":
+		m.loading = true
+		m.success = ""
+		return m, loadTasks
+	case "enter":
+		if !m.loading && len(m.tasks) > 0 {
+			task := m.tasks[m.cursor].Task
+			return m, func() tea.Msg { return AttachTaskMsg{Task: task} }
+		}
+	case "x":
+		if !m.loading && len(m.tasks) > 0 && m.tasks[m.cursor].State == system.TaskRunning {
+			t := m.tasks[m.cursor]
+			m.confirm = NewConfirmation("stop_task", "Stop Task",
+				fmt.Sprintf("Stop %s?\n\nStopping an install part way may leave the server half configured.", t.Description),
+				ConfirmWarning)
+			m.confirming = true
+		}
+	case "d":
+		if !m.loading && len(m.tasks) > 0 && m.tasks[m.cursor].Done() {
+			t := m.tasks[m.cursor]
+			return m, func() tea.Msg {
+				return taskActionMsg{done: "Removed " + t.Description, err: system.RemoveTask(t.ID)}
+			}
+		}
+	}
+	return m, nil
+}
+
+// taskStateStyle colours a task's state
+func (m TasksModel) taskStateStyle(s system.TaskStatus) lipgloss.Style {
+	switch s.State {
+	case system.TaskRunning:
+		return m.theme.InfoStyle
+	case system.TaskSucceeded:
+		return m.theme.SuccessStyle
+	case system.TaskFailed:
+		return m.theme.ErrorStyle
+	}
+	return m.theme.WarningStyle
+}
+
+// View renders the tasks screen
+func (m TasksModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{
+		m.theme.Title.Render("Background Tasks"),
+		m.theme.DescriptionStyle.Render("Tasks keep running on the server when ravact exits or the connection drops"),
+		"",
+	}
+
+	switch {
+	case m.loading:
+		sections = append(sections, m.theme.InfoStyle.Render("Loading tasks..."))
+	case len(m.tasks) == 0 && m.err == nil:
+		sections = append(sections, m.theme.DescriptionStyle.Render("No tasks yet. Installs and other changes are listed here while they run."))
+	default:
+		for i, t := range m.tasks {
+			state := t.State.String()
+			if t.State == system.TaskFailed {
+				state = fmt.Sprintf("Failed (%d)", t.ExitCode)
+			}
+			started := t.Started.Local().Format("Jan 02 15:04")
+			line := fmt.Sprintf("%-12s %-34s ", started, truncateRunes(t.Description, 34))
+			if t.State == system.TaskRunning {
+				line += fmt.Sprintf("%-14s", time.Since(t.Started).Round(time.Second))
+			} else {
+				line += fmt.Sprintf("%-14s", "")
+			}
+			stateText := m.taskStateStyle(t).Render(state)
+			if i == m.cursor {
+				sections = append(sections, m.theme.KeyStyle.Render(m.theme.Symbols.Cursor+" ")+m.theme.SelectedItem.Render(line)+stateText)
+			} else {
+				sections = append(sections, "  "+m.theme.MenuItem.Render(line)+stateText)
+			}
+		}
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if m.success != "" {
+		sections = append(sections, "", m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark+" "+m.success))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := "Enter: Show output" + bullet + "x: Stop" + bullet + "d: Remove finished" + bullet + "r: Refresh" + bullet + "Esc: Back"
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -0,0 +1,140 @@
+package screens
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// TextEditor is a minimal multi-line editor for short documents such as
+// site notes. Screens keep one as a field and forward key presses to Update.
+type TextEditor struct {
+	lines []string
+	row   int
+	col   int // Cursor position in runes
+	top   int // First visible line
+}
+
+// NewTextEditor creates an editor holding content with the cursor at the end
+func NewTextEditor(content string) TextEditor {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	e := TextEditor{lines: lines, row: len(lines) - 1}
+	e.col = len([]rune(lines[e.row]))
+	return e
+}
+
+// Value returns the edited text
+func (e TextEditor) Value() string {
+	return strings.Join(e.lines, "\n")
+}
+
+// Update handles a key press. Keys the editor does not use (Esc, Ctrl+S, ...)
+// are ignored so the owning screen can act on them.
+func (e TextEditor) Update(msg tea.KeyMsg) TextEditor {
+	line := []rune(e.lines[e.row])
+
+	switch msg.Type {
+	case tea.KeyRunes:
+		e.lines[e.row] = string(line[:e.col]) + string(msg.Runes) + string(line[e.col:])
+		e.col += len(msg.Runes)
+	case tea.KeySpace:
+		e.lines[e.row] = string(line[:e.col]) + " " + string(line[e.col:])
+		e.col++
+	case tea.KeyTab:
+		e.lines[e.row] = string(line[:e.col]) + "  " + string(line[e.col:])
+		e.col += 2
+	case tea.KeyEnter:
+		rest := string(line[e.col:])
+		e.lines[e.row] = string(line[:e.col])
+		e.lines = append(e.lines[:e.row+1], append([]string{rest}, e.lines[e.row+1:]...)...)
+		e.row++
+		e.col = 0
+	case tea.KeyBackspace:
+		switch {
+		case e.col > 0:
+			e.lines[e.row] = string(line[:e.col-1]) + string(line[e.col:])
+			e.col--
+		case e.row > 0:
+			prev := []rune(e.lines[e.row-1])
+			e.lines[e.row-1] = string(prev) + string(line)
+			e.lines = append(e.lines[:e.row], e.lines[e.row+1:]...)
+			e.row--
+			e.col = len(prev)
+		}
+	case tea.KeyDelete:
+		switch {
+		case e.col < len(line):
+			e.lines[e.row] = string(line[:e.col]) + string(line[e.col+1:])
+		case e.row < len(e.lines)-1:
+			e.lines[e.row] = string(line) + e.lines[e.row+1]
+			e.lines = append(e.lines[:e.row+1], e.lines[e.row+2:]...)
+		}
+	case tea.KeyLeft:
+		if e.col > 0 {
+			e.col--
+		} else if e.row > 0 {
+			e.row--
+			e.col = len([]rune(e.lines[e.row]))
+		}
+	case tea.KeyRight:
+		if e.col < len(line) {
+			e.col++
+		} else if e.row < len(e.lines)-1 {
+			e.row++
+			e.col = 0
+		}
+	case tea.KeyUp:
+		if e.row > 0 {
+			e.row--
+			e.clampCol()
+		}
+	case tea.KeyDown:
+		if e.row < len(e.lines)-1 {
+			e.row++
+			e.clampCol()
+		}
+	case tea.KeyHome, tea.KeyCtrlA:
+		e.col = 0
+	case tea.KeyEnd, tea.KeyCtrlE:
+		e.col = len(line)
+	}
+	return e
+}
+
+// clampCol keeps the cursor within the current line
+func (e *TextEditor) clampCol() {
+	if n := len([]rune(e.lines[e.row])); e.col > n {
+		e.col = n
+	}
+}
+
+// View renders up to height lines around the cursor
+func (e *TextEditor) View(t *theme.Theme, height int) string {
+	if height < 1 {
+		height = 1
+	}
+	if e.row < e.top {
+		e.top = e.row
+	}
+	if e.row >= e.top+height {
+		e.top = e.row - height + 1
+	}
+
+	var out []string
+	for i := e.top; i < len(e.lines) && i < e.top+height; i++ {
+		if i != e.row {
+			out = append(out, t.MenuItem.Render(e.lines[i]))
+			continue
+		}
+		line := []rune(e.lines[i])
+		cursor := " "
+		rest := ""
+		if e.col < len(line) {
+			cursor = string(line[e.col])
+			rest = string(line[e.col+1:])
+		}
+		out = append(out, t.MenuItem.Render(string(line[:e.col]))+t.SelectedItem.Reverse(true).Render(cursor)+t.MenuItem.Render(rest))
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,63 @@
+package screens
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func typeText(e TextEditor, s string) TextEditor {
+	for _, r := range s {
+		switch r {
+		case '\n':
+			e = e.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		case ' ':
+			e = e.Update(tea.KeyMsg{Type: tea.KeySpace})
+		default:
+			e = e.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	return e
+}
+
+func TestTextEditorTyping(t *testing.T) {
+	e := NewTextEditor("")
+	e = typeText(e, "# Notes\nclear cache")
+	if got := e.Value(); got != "# Notes\nclear cache" {
+		t.Fatalf("unexpected value %q", got)
+	}
+
+	// Backspace at the start of a line joins it with the previous one
+	e = e.Update(tea.KeyMsg{Type: tea.KeyHome})
+	e = e.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if got := e.Value(); got != "# Notesclear cache" {
+		t.Errorf("expected lines to be joined, got %q", got)
+	}
+
+	// Enter splits the line at the cursor
+	e = e.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := e.Value(); got != "# Notes\nclear cache" {
+		t.Errorf("expected line to be split, got %q", got)
+	}
+}
+
+func TestTextEditorCursorMovement(t *testing.T) {
+	e := NewTextEditor("first line\nab")
+
+	// Moving up keeps the column, moving down clamps it to the shorter line
+	e = e.Update(tea.KeyMsg{Type: tea.KeyUp})
+	e = e.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	e = e.Update(tea.KeyMsg{Type: tea.KeyDown})
+	e = typeText(e, "c")
+	if got := e.Value(); got != "first line\nabc" {
+		t.Errorf("unexpected value %q", got)
+	}
+
+	// Delete at the end of a line pulls up the next one
+	e = e.Update(tea.KeyMsg{Type: tea.KeyUp})
+	e = e.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	e = e.Update(tea.KeyMsg{Type: tea.KeyDelete})
+	if got := e.Value(); got != "first lineabc" {
+		t.Errorf("unexpected value after delete %q", got)
+	}
+}
@@ -0,0 +1,315 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// updatesLoadedMsg carries the pending package upgrades
+type updatesLoadedMsg struct {
+	updates []system.PackageUpdate
+	err     error
+}
+
+// UpdatesModel lists pending OS package upgrades and applies them
+type UpdatesModel struct {
+	theme  *theme.Theme
+	width  int
+	height int
+
+	updater  system.PackageUpdater
+	updates  []system.PackageUpdate
+	selected map[string]bool
+	loading  bool
+
+	cursor       int
+	scrollOffset int
+	maxVisible   int
+
+	confirm    Confirmation
+	confirming bool
+	packages   []string // Packages to upgrade; empty upgrades everything
+
+	err error
+}
+
+// NewUpdatesModel creates a new package updates model
+func NewUpdatesModel() UpdatesModel {
+	m := UpdatesModel{
+		theme:      theme.DefaultTheme(),
+		selected:   map[string]bool{},
+		maxVisible: 10,
+	}
+	updater, err := system.DetectPackageUpdater()
+	if err != nil {
+		m.err = err
+		return m
+	}
+	m.updater = updater
+	m.loading = true
+	return m
+}
+
+// Init loads the pending upgrades
+func (m UpdatesModel) Init() tea.Cmd {
+	if m.updater == "" {
+		return nil
+	}
+	return m.load(false)
+}
+
+// load lists the pending upgrades in the background, refreshing the
+// package lists first when asked
+func (m UpdatesModel) load(refresh bool) tea.Cmd {
+	updater := m.updater
+	return func() tea.Msg {
+		if refresh {
+			if err := updater.RefreshPackageLists(); err != nil {
+				return updatesLoadedMsg{err: err}
+			}
+		}
+		updates, err := updater.PendingUpdates()
+		return updatesLoadedMsg{updates: updates, err: err}
+	}
+}
+
+// Update handles messages for the updates screen
+func (m UpdatesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.maxVisible = m.height - 18
+		if m.maxVisible < 5 {
+			m.maxVisible = 5
+		}
+		return m, nil
+
+	case updatesLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.updates = msg.updates
+		m.selected = map[string]bool{}
+		m.cursor = 0
+		m.scrollOffset = 0
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.confirming {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.confirm, result = m.confirm.Update(msg)
+			switch result {
+			case ConfirmAccepted:
+				m.confirming = false
+				script := m.updater.UpgradeScript(m.packages)
+				description := "Upgrading all packages"
+				if len(m.packages) > 0 {
+					description = fmt.Sprintf("Upgrading %d packages", len(m.packages))
+				}
+				return m, func() tea.Msg {
+					return ExecutionStartMsg{Command: script, Description: description}
+				}
+			case ConfirmCancelled:
+				m.confirming = false
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: MainMenuScreen}
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+				if m.cursor < m.scrollOffset {
+					m.scrollOffset = m.cursor
+				}
+			}
+		case "down", "j":
+			if m.cursor < len(m.updates)-1 {
+				m.cursor++
+				if m.cursor >= m.scrollOffset+m.maxVisible {
+					m.scrollOffset = m.cursor - m.maxVisible + 1
+				}
+			}
+		case " ":
+			if len(m.updates) > 0 {
+				name := m.updates[m.cursor].Name
+				m.selected[name] = !m.selected[name]
+			}
+		case "a":
+			all := len(m.selectedPackages()) < len(m.updates)
+			for _, u := range m.updates {
+				m.selected[u.Name] = all
+			}
+		case "s":
+			m.selected = map[string]bool{}
+			for _, u := range m.updates {
+				if u.Security {
+					m.selected[u.Name] = true
+				}
+			}
+		case "r":
+			if !m.loading && m.updater != "" {
+				m.loading = true
+				m.err = nil
+				return m, m.load(true)
+			}
+		case "enter", "u":
+			if packages := m.selectedPackages(); len(packages) > 0 {
+				m.askUpgrade(packages)
+			}
+		case "U":
+			if len(m.updates) > 0 {
+				m.askUpgrade(nil)
+			}
+		}
+	}
+	return m, nil
+}
+
+// selectedPackages returns the selected package names in list order
+func (m UpdatesModel) selectedPackages() []string {
+	var packages []string
+	for _, u := range m.updates {
+		if m.selected[u.Name] {
+			packages = append(packages, u.Name)
+		}
+	}
+	return packages
+}
+
+// askUpgrade asks for confirmation before upgrading packages
+func (m *UpdatesModel) askUpgrade(packages []string) {
+	m.packages = packages
+	message := fmt.Sprintf("Upgrade all %d packages?", len(m.updates))
+	if len(packages) > 0 {
+		list := packages
+		if len(list) > 10 {
+			list = append(append([]string{}, list[:10]...), fmt.Sprintf("... and %d more", len(packages)-10))
+		}
+		message = fmt.Sprintf("Upgrade %d packages?\n\n%s", len(packages), strings.Join(list, "\n"))
+	}
+	m.confirm = NewConfirmation("upgrade", "Upgrade Packages",
+		message+"\n\nServices using upgraded libraries may need a restart.", ConfirmWarning)
+	m.confirming = true
+}
+
+// View renders the updates screen
+func (m UpdatesModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	sections := []string{m.theme.Title.Render("System Updates")}
+	if m.updater != "" {
+		sections = append(sections, m.theme.DescriptionStyle.Render(string(m.updater)))
+	}
+	sections = append(sections, "")
+
+	switch {
+	case m.loading:
+		sections = append(sections, m.theme.InfoStyle.Render("Checking for updates..."))
+	case m.err == nil && len(m.updates) == 0:
+		sections = append(sections, m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark+" All packages are up to date"))
+	case len(m.updates) > 0:
+		sections = append(sections, m.renderList()...)
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	help := "Space: Select" + bullet + "a: All" + bullet + "s: Security" + bullet +
+		"Enter: Upgrade selected" + bullet + "U: Upgrade all" + bullet + "r: Refresh" + bullet + "Esc: Back"
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
+
+// renderList renders the visible upgrades with a summary
+func (m UpdatesModel) renderList() []string {
+	security := 0
+	nameWidth := 0
+	for _, u := range m.updates {
+		if u.Security {
+			security++
+		}
+		if len(u.Name) > nameWidth {
+			nameWidth = len(u.Name)
+		}
+	}
+	if nameWidth > 32 {
+		nameWidth = 32
+	}
+
+	summary := fmt.Sprintf("%d updates available", len(m.updates))
+	lines := []string{m.theme.Label.Render(summary)}
+	if security > 0 {
+		lines[0] += m.theme.WarningStyle.Render(fmt.Sprintf("  %s %d security", m.theme.Symbols.Warning, security))
+	}
+	if n := len(m.selectedPackages()); n > 0 {
+		lines[0] += m.theme.DescriptionStyle.Render(fmt.Sprintf("  (%d selected)", n))
+	}
+	lines = append(lines, "")
+
+	end := m.scrollOffset + m.maxVisible
+	if end > len(m.updates) {
+		end = len(m.updates)
+	}
+	for i := m.scrollOffset; i < end; i++ {
+		u := m.updates[i]
+		cursor := "  "
+		if i == m.cursor {
+			cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+		}
+		checkbox := "[ ]"
+		if m.selected[u.Name] {
+			checkbox = "[" + m.theme.Symbols.CheckMark + "]"
+		}
+		name := u.Name
+		if len(name) > nameWidth {
+			name = name[:nameWidth-1] + "…"
+		}
+		version := u.Available
+		if u.Current != "" {
+			version = u.Current + " " + m.theme.Symbols.ArrowRight + " " + u.Available
+		}
+		label := fmt.Sprintf("%s %-*s  %s", checkbox, nameWidth, name, version)
+
+		style := m.theme.MenuItem
+		switch {
+		case i == m.cursor:
+			style = m.theme.SelectedItem
+		case u.Security:
+			style = m.theme.WarningStyle
+		}
+		line := cursor + style.Render(label)
+		if u.Security {
+			line += m.theme.WarningStyle.Render("  security")
+		}
+		lines = append(lines, line)
+	}
+	if len(m.updates) > m.maxVisible {
+		lines = append(lines, "", m.theme.DescriptionStyle.Render(fmt.Sprintf("Showing %d-%d of %d", m.scrollOffset+1, end, len(m.updates))))
+	}
+	return lines
+}
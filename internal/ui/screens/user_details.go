@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/huh"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/iperamuna/ravact/internal/system"
@@ -21,7 +22,11 @@ type UserDetailsModel struct {
 	actions     []string
 	err         error
 	message     string
-	confirmAction string // Action waiting for confirmation
+	confirm     Confirmation // Action waiting for confirmation
+	confirming  bool
+	umask       string    // Login umask set by ravact, "" if none
+	form        *huh.Form // Shell or umask form, nil when not editing
+	formAction  string
 }
 
 // NewUserDetailsModel creates a new user details model
@@ -37,6 +42,7 @@ func NewUserDetailsModel(user system.User) UserDetailsModel {
 		userManager: um,
 		cursor:      0,
 		actions:     actions,
+		umask:       um.GetUmask(user.Username),
 	}
 }
 
@@ -44,8 +50,9 @@ func NewUserDetailsModel(user system.User) UserDetailsModel {
 func buildUserActions(user system.User, um *system.UserManager) []string {
 	actions := []string{
 		"SSH Key Management",
-		"Toggle Sudo Access",
+		"Sudo Management",
 		"Change Shell",
+		"Set Umask",
 	}
 
 	// SSH Key Login toggle
@@ -62,6 +69,7 @@ func buildUserActions(user system.User, um *system.UserManager) []string {
 		actions = append(actions, "Disable SSH Password Login (Global)")
 	}
 
+	actions = append(actions, "Offboard User")
 	actions = append(actions, "Delete User")
 
 	return actions
@@ -74,15 +82,20 @@ func (m UserDetailsModel) Init() tea.Cmd {
 
 // Update handles messages for user details
 func (m UserDetailsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, nil
+	}
 
+	if m.form != nil {
+		return m.updateForm(msg)
+	}
+
+	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		// Handle confirmation dialogs
-		if m.confirmAction != "" {
+		if m.confirming {
 			return m.handleConfirmation(msg)
 		}
 
@@ -147,16 +160,18 @@ func (m UserDetailsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleConfirmation handles confirmation dialog responses
 func (m UserDetailsModel) handleConfirmation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c", "q":
+	if msg.String() == "ctrl+c" {
 		return m, tea.Quit
-	case "esc", "backspace", "n", "N":
-		m.confirmAction = ""
-		return m, nil
-	case "y", "Y":
-		action := m.confirmAction
-		m.confirmAction = ""
-		return m.confirmExecuteAction(action)
+	}
+
+	var result ConfirmResult
+	m.confirm, result = m.confirm.Update(msg)
+	switch result {
+	case ConfirmCancelled:
+		m.confirming = false
+	case ConfirmAccepted:
+		m.confirming = false
+		return m.confirmExecuteAction(m.confirm.Action)
 	}
 	return m, nil
 }
@@ -169,29 +184,47 @@ func (m UserDetailsModel) executeAction(action string) (tea.Model, tea.Cmd) {
 			return NavigateMsg{Screen: SSHKeyManagementScreen, Data: m.user.Username}
 		}
 
-	case "Toggle Sudo Access":
-		actionDesc := "grant"
-		if m.user.HasSudo {
-			actionDesc = "revoke"
-		}
-		err := m.userManager.ToggleSudo(m.user.Username)
-		if err != nil {
-			m.err = fmt.Errorf("failed to %s sudo: %v", actionDesc, err)
-		} else {
-			m.user.HasSudo = !m.user.HasSudo
-			if m.user.HasSudo {
-				m.message = fmt.Sprintf("✓ Granted sudo access to %s", m.user.Username)
-			} else {
-				m.message = fmt.Sprintf("✓ Revoked sudo access from %s", m.user.Username)
-			}
+	case "Sudo Management":
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: SudoManagementScreen, Data: m.user}
 		}
 
 	case "Change Shell":
-		m.message = "Feature coming soon: Shell selection menu"
+		shell := m.user.Shell
+		var options []huh.Option[string]
+		for _, s := range system.InstalledShells() {
+			options = append(options, huh.NewOption(s, s))
+		}
+		m.form = huh.NewForm(huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("value").
+				Title("Login Shell").
+				Description("Login shells installed on this server").
+				Options(options...).
+				Value(&shell),
+		)).WithTheme(m.theme.HuhTheme).WithShowHelp(true)
+		m.formAction = action
+		return m, m.form.Init()
+
+	case "Set Umask":
+		umask := m.umask
+		m.form = huh.NewForm(huh.NewGroup(
+			huh.NewInput().
+				Key("value").
+				Title("Umask").
+				Description("Set at login through "+system.UmaskProfilePath+" (empty to remove)").
+				Placeholder("022").
+				Validate(system.ValidateUmask).
+				Value(&umask),
+		)).WithTheme(m.theme.HuhTheme).WithShowHelp(true).WithShowErrors(true)
+		m.formAction = action
+		return m, m.form.Init()
 
 	case "Disable SSH Key Login":
-		m.confirmAction = action
-		m.message = fmt.Sprintf("⚠ Disable SSH key login for '%s'?\n\nThis will rename authorized_keys to authorized_keys.disabled.\nThe user will not be able to login using SSH keys.\n\nPress 'y' to confirm, 'n' or Esc to cancel", m.user.Username)
+		m.confirm = NewConfirmation(action, "Disable SSH Key Login",
+			fmt.Sprintf("Disable SSH key login for '%s'?\n\nThis will rename authorized_keys to authorized_keys.disabled.\nThe user will not be able to login using SSH keys.", m.user.Username),
+			ConfirmWarning)
+		m.confirming = true
 
 	case "Enable SSH Key Login":
 		err := m.userManager.EnableSSHKeyLogin(m.user.Username)
@@ -203,8 +236,10 @@ func (m UserDetailsModel) executeAction(action string) (tea.Model, tea.Cmd) {
 		}
 
 	case "Disable SSH Password Login (Global)":
-		m.confirmAction = action
-		m.message = "⚠ Disable SSH password login globally?\n\nThis will set 'PasswordAuthentication no' in /etc/ssh/sshd_config.\nAll users will be unable to login using passwords via SSH.\nMake sure you have SSH key access configured!\n\nPress 'y' to confirm, 'n' or Esc to cancel"
+		m.confirm = NewConfirmation(action, "Disable SSH Password Login",
+			"Disable SSH password login globally?\n\nThis will set 'PasswordAuthentication no' in /etc/ssh/sshd_config.\nAll users will be unable to login using passwords via SSH.\nMake sure you have SSH key access configured!",
+			ConfirmWarning)
+		m.confirming = true
 
 	case "Enable SSH Password Login (Global)":
 		err := m.userManager.EnablePasswordSSHLogin()
@@ -215,18 +250,72 @@ func (m UserDetailsModel) executeAction(action string) (tea.Model, tea.Cmd) {
 			m.actions = buildUserActions(m.user, m.userManager)
 		}
 
+	case "Offboard User":
+		if err := system.Offboardable(m.user); err != nil {
+			m.err = err
+			break
+		}
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: OffboardUserScreen, Data: m.user}
+		}
+
 	case "Delete User":
 		if m.user.Username == "root" {
 			m.err = fmt.Errorf("cannot delete root user")
 		} else {
-			m.confirmAction = action
-			m.message = fmt.Sprintf("⚠ Delete user '%s'?\n\nThis will remove the user account.\nPress 'y' to confirm, 'n' or Esc to cancel", m.user.Username)
+			m.confirm = NewDangerConfirmation(action, "Delete User",
+				fmt.Sprintf("Delete user '%s'?\n\nThis will remove the user account.", m.user.Username),
+				m.user.Username)
+			m.confirming = true
 		}
 	}
 
 	return m, nil
 }
 
+// updateForm passes messages to the shell or umask form and applies the
+// value when it is submitted
+func (m UserDetailsModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.form = nil
+			return m, nil
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	value := strings.TrimSpace(m.form.GetString("value"))
+	m.form = nil
+	switch m.formAction {
+	case "Change Shell":
+		if err := m.userManager.ChangeShell(m.user.Username, value); err != nil {
+			m.err = fmt.Errorf("failed to change shell: %v", err)
+		} else {
+			m.user.Shell = value
+			m.message = fmt.Sprintf("✓ Shell for %s set to %s", m.user.Username, value)
+		}
+	case "Set Umask":
+		if err := m.userManager.SetUmask(m.user.Username, value); err != nil {
+			m.err = fmt.Errorf("failed to set umask: %v", err)
+		} else if m.umask = value; value == "" {
+			m.message = fmt.Sprintf("✓ Umask for %s removed; the system default applies", m.user.Username)
+		} else {
+			m.message = fmt.Sprintf("✓ Umask for %s set to %s (applies from the next login)", m.user.Username, value)
+		}
+	}
+	return m, nil
+}
+
 // confirmExecuteAction executes an action after confirmation
 func (m UserDetailsModel) confirmExecuteAction(action string) (tea.Model, tea.Cmd) {
 	switch action {
@@ -269,6 +358,20 @@ func (m UserDetailsModel) View() string {
 		return "Loading..."
 	}
 
+	if m.confirming {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	if m.form != nil {
+		content := lipgloss.JoinVertical(lipgloss.Left,
+			m.theme.Title.Render(fmt.Sprintf("%s: %s", m.formAction, m.user.Username)),
+			"",
+			m.form.View(),
+			"",
+			m.theme.Help.Render("Enter: Save • Esc: Cancel"))
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(content))
+	}
+
 	// Show error if there's one
 	if m.err != nil {
 		errorMsg := m.theme.Title.Render("User Details") + "\n\n" +
@@ -320,6 +423,9 @@ func (m UserDetailsModel) View() string {
 		m.theme.Label.Render("Home:       ") + m.theme.MenuItem.Render(m.user.HomeDir),
 		m.theme.Label.Render("Shell:      ") + m.theme.MenuItem.Render(m.user.Shell),
 	}
+	if m.umask != "" {
+		infoLines = append(infoLines, m.theme.Label.Render("Umask:      ")+m.theme.MenuItem.Render(m.umask))
+	}
 
 	// Sudo status
 	sudoStatus := m.theme.ErrorStyle.Render("✗ No sudo access")
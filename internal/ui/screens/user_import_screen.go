@@ -0,0 +1,305 @@
+package screens
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// userImportDoneMsg carries the outcome of a bulk import
+type userImportDoneMsg struct {
+	results []system.UserImportResult
+}
+
+// UserImportModel creates users in bulk from a CSV or YAML file
+type UserImportModel struct {
+	theme       *theme.Theme
+	width       int
+	height      int
+	userManager *system.UserManager
+
+	mode    string // "path", "preview", "confirm", "running", "summary"
+	form    *huh.Form
+	file    string
+	entries []system.UserImportEntry
+	checks  []error // Why each entry cannot be imported, nil if it can
+	results []system.UserImportResult
+	scroll  int
+
+	confirm Confirmation
+	err     error
+}
+
+// NewUserImportModel creates the bulk user import screen
+func NewUserImportModel() UserImportModel {
+	m := UserImportModel{
+		theme:       theme.DefaultTheme(),
+		userManager: system.NewUserManager(),
+	}
+	m.startPath()
+	return m
+}
+
+// startPath asks for the file to import
+func (m *UserImportModel) startPath() {
+	m.mode = "path"
+	path := m.file
+	m.form = huh.NewForm(huh.NewGroup(
+		huh.NewInput().
+			Key("path").
+			Title("Import File").
+			Description("A .csv with a username,shell,groups,keys header, or a .yaml listing users:").
+			Placeholder("team.csv").
+			Validate(func(s string) error {
+				if strings.TrimSpace(s) == "" {
+					return fmt.Errorf("path cannot be empty")
+				}
+				return nil
+			}).
+			Value(&path),
+	)).WithTheme(m.theme.HuhTheme).WithShowHelp(true).WithShowErrors(true)
+}
+
+// Init initializes the import screen
+func (m UserImportModel) Init() tea.Cmd {
+	if m.form != nil {
+		return m.form.Init()
+	}
+	return nil
+}
+
+// load reads and checks the import file. The file is read on this machine,
+// even when managing a remote server.
+func (m UserImportModel) load(path string) (UserImportModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	entries, err := system.ParseUserImport(path, data)
+	if err != nil {
+		return m, err
+	}
+	m.file = path
+	m.entries = entries
+	m.checks = m.userManager.CheckUserImport(entries)
+	m.scroll = 0
+	m.mode = "preview"
+	return m, nil
+}
+
+// importable returns the entries that passed the checks
+func (m UserImportModel) importable() []system.UserImportEntry {
+	var entries []system.UserImportEntry
+	for i, e := range m.entries {
+		if m.checks[i] == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Update handles messages for the import screen
+func (m UserImportModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case userImportDoneMsg:
+		m.results = msg.results
+		m.scroll = 0
+		m.mode = "summary"
+		return m, nil
+	}
+
+	if m.mode == "path" && m.form != nil {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				return m, func() tea.Msg {
+					return NavigateMsg{Screen: UserManagementScreen}
+				}
+			}
+		}
+		form, cmd := m.form.Update(msg)
+		if f, ok := form.(*huh.Form); ok {
+			m.form = f
+		}
+		if m.form.State == huh.StateCompleted {
+			path := strings.TrimSpace(m.form.GetString("path"))
+			loaded, err := m.load(path)
+			if err != nil {
+				m.file = path
+				m.err = err
+				m.startPath()
+				return m, m.form.Init()
+			}
+			loaded.err = nil
+			loaded.form = nil
+			return loaded, nil
+		}
+		return m, cmd
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if keyMsg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch m.mode {
+	case "confirm":
+		var result ConfirmResult
+		m.confirm, result = m.confirm.Update(keyMsg)
+		switch result {
+		case ConfirmAccepted:
+			m.mode = "running"
+			entries := m.importable()
+			um := m.userManager
+			return m, func() tea.Msg {
+				return userImportDoneMsg{results: um.ImportUsers(entries)}
+			}
+		case ConfirmCancelled:
+			m.mode = "preview"
+		}
+		return m, nil
+
+	case "running":
+		return m, nil
+	}
+
+	rows := len(m.entries)
+	if m.mode == "summary" {
+		rows = len(m.results)
+	}
+	switch keyMsg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		if m.mode == "preview" {
+			m.startPath()
+			return m, m.form.Init()
+		}
+		return m, func() tea.Msg {
+			return NavigateMsg{Screen: UserManagementScreen}
+		}
+	case "up", "k":
+		if m.scroll > 0 {
+			m.scroll--
+		}
+	case "down", "j":
+		if m.scroll < rows-m.visibleRows() {
+			m.scroll++
+		}
+	case "enter":
+		if m.mode == "summary" {
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: UserManagementScreen}
+			}
+		}
+		count := len(m.importable())
+		if count == 0 {
+			m.err = fmt.Errorf("none of the users in %s can be imported", m.file)
+			return m, nil
+		}
+		m.confirm = NewConfirmation("import", "Import Users",
+			fmt.Sprintf("Create %d user(s) from %s?\nThey get no password; they log in with their SSH keys.", count, m.file), ConfirmWarning)
+		m.mode = "confirm"
+	}
+	return m, nil
+}
+
+// visibleRows is the number of users that fit on screen
+func (m UserImportModel) visibleRows() int {
+	if m.height < 20 {
+		return 6
+	}
+	return m.height - 14
+}
+
+// View renders the import screen
+func (m UserImportModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.mode == "confirm" {
+		return m.confirm.View(m.theme, m.width, m.height)
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections := []string{m.theme.Title.Render("Import Users"), ""}
+	var help string
+
+	switch m.mode {
+	case "path":
+		sections = append(sections, m.form.View())
+		help = "Enter: Preview" + bullet + "Esc: Back"
+
+	case "running":
+		sections = append(sections, m.theme.InfoStyle.Render(fmt.Sprintf("Creating %d user(s)...", len(m.importable()))))
+
+	case "preview":
+		ready := len(m.importable())
+		sections = append(sections, m.theme.DescriptionStyle.Render(fmt.Sprintf("%s: %d user(s), %d ready to import", m.file, len(m.entries), ready)), "")
+		end := min(m.scroll+m.visibleRows(), len(m.entries))
+		for i := m.scroll; i < end; i++ {
+			e := m.entries[i]
+			shell := e.Shell
+			if shell == "" {
+				shell = "default shell"
+			}
+			detail := fmt.Sprintf("%-16s %s, %d key(s)", e.Username, shell, len(e.Keys))
+			if len(e.Groups) > 0 {
+				detail += ", groups " + strings.Join(e.Groups, " ")
+			}
+			if m.checks[i] != nil {
+				sections = append(sections, m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+detail+" ("+m.checks[i].Error()+")"))
+			} else {
+				sections = append(sections, m.theme.MenuItem.Render(m.theme.Symbols.Bullet+" "+detail))
+			}
+		}
+		if ready < len(m.entries) {
+			sections = append(sections, "", m.theme.WarningStyle.Render(fmt.Sprintf("%s %d user(s) will be skipped", m.theme.Symbols.Warning, len(m.entries)-ready)))
+		}
+		help = m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Scroll" + bullet + "Enter: Import" + bullet + "Esc: Choose another file"
+
+	case "summary":
+		failed := 0
+		for _, r := range m.results {
+			if r.Err != nil {
+				failed++
+			}
+		}
+		sections = append(sections, m.theme.DescriptionStyle.Render(fmt.Sprintf("%d created, %d failed", len(m.results)-failed, failed)), "")
+		end := min(m.scroll+m.visibleRows(), len(m.results))
+		for _, r := range m.results[m.scroll:end] {
+			if r.Err != nil {
+				sections = append(sections, m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+r.Username+": "+r.Err.Error()))
+			} else {
+				sections = append(sections, m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark+" "+r.Username))
+			}
+		}
+		help = m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Scroll" + bullet + "Enter/Esc: Back to users"
+	}
+
+	if m.err != nil {
+		sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+	}
+	if help != "" {
+		sections = append(sections, "", m.theme.Help.Render(help))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.theme.RenderBox(content))
+}
@@ -25,6 +25,8 @@ type UserManagementModel struct {
 	height      int
 	userManager *system.UserManager
 	users       []system.User
+	allUsers    []system.User
+	tags        TagFilter
 	groups      []system.Group
 	cursor      int
 	viewMode    ViewMode
@@ -53,6 +55,16 @@ func NewUserManagementModel() UserManagementModel {
 		scrollOffset: 0,
 		maxVisible:   10, // Show max 10 items at once
 		loading:      true,
+		tags:         NewTagFilter(system.TagKindUser),
+	}
+}
+
+// applyTagFilter limits the visible users to the active tag
+func (m *UserManagementModel) applyTagFilter() {
+	m.users = filterByTag(m.tags, m.allUsers, func(u system.User) string { return u.Username })
+	if m.cursor >= len(m.users) {
+		m.cursor = 0
+		m.scrollOffset = 0
 	}
 }
 
@@ -85,7 +97,8 @@ func (m UserManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case UsersLoadedMsg:
 		m.loading = false
-		m.users = msg.users
+		m.allUsers = msg.users
+		m.applyTagFilter()
 		m.groups = msg.groups
 		m.err = msg.err
 		return m, nil
@@ -121,6 +134,15 @@ func (m UserManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		
+		if m.tags.Editing() {
+			var done bool
+			m.tags, done = m.tags.Update(msg)
+			if done {
+				m.applyTagFilter()
+			}
+			return m, nil
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -130,6 +152,21 @@ func (m UserManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return NavigateMsg{Screen: MainMenuScreen}
 			}
 
+		case "T":
+			// Edit tags of the selected user
+			if m.viewMode == UsersView && len(m.users) > 0 {
+				m.tags = m.tags.StartEdit(m.users[m.cursor].Username)
+			}
+
+		case "f":
+			// Filter users by the next tag
+			if m.viewMode == UsersView {
+				m.tags = m.tags.Cycle()
+				m.cursor = 0
+				m.scrollOffset = 0
+				m.applyTagFilter()
+			}
+
 		case "tab":
 			// Switch between users and groups view
 			if m.viewMode == UsersView {
@@ -188,6 +225,22 @@ func (m UserManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case "i":
+			// Import users in bulk from a CSV or YAML file
+			if m.viewMode == UsersView {
+				return m, func() tea.Msg {
+					return NavigateMsg{Screen: UserImportScreen}
+				}
+			}
+
+		case "s":
+			// Create a chrooted SFTP-only user for an external developer
+			if m.viewMode == UsersView {
+				return m, func() tea.Msg {
+					return NavigateMsg{Screen: SFTPUserScreen}
+				}
+			}
+
 		case "enter", " ":
 			// View/edit user or group details
 			if m.viewMode == UsersView && len(m.users) > 0 {
@@ -287,6 +340,9 @@ func (m UserManagementModel) View() string {
 	var content string
 	if m.viewMode == UsersView {
 		content = m.renderUsersView()
+		if tagLine := m.tags.View(m.theme); tagLine != "" {
+			content = lipgloss.JoinVertical(lipgloss.Left, tagLine, "", content)
+		}
 	} else {
 		content = m.renderGroupsView()
 	}
@@ -294,7 +350,7 @@ func (m UserManagementModel) View() string {
 	// Help text
 	help := ""
 	if m.viewMode == UsersView {
-		help = m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Details " + m.theme.Symbols.Bullet + " a: Add User " + m.theme.Symbols.Bullet + " r: Refresh " + m.theme.Symbols.Bullet + " Tab: Switch " + m.theme.Symbols.Bullet + " Esc: Back")
+		help = m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Details " + m.theme.Symbols.Bullet + " a: Add User " + m.theme.Symbols.Bullet + " i: Import " + m.theme.Symbols.Bullet + " s: SFTP User " + m.theme.Symbols.Bullet + " r: Refresh" + m.tags.Help(m.theme) + " " + m.theme.Symbols.Bullet + " Tab: Switch " + m.theme.Symbols.Bullet + " Esc: Back")
 	} else {
 		help = m.theme.Help.Render(m.theme.Symbols.ArrowUp + "/" + m.theme.Symbols.ArrowDown + ": Navigate " + m.theme.Symbols.Bullet + " Enter: Details " + m.theme.Symbols.Bullet + " a: Add Group " + m.theme.Symbols.Bullet + " r: Refresh " + m.theme.Symbols.Bullet + " Tab: Switch " + m.theme.Symbols.Bullet + " Esc: Back")
 	}
@@ -326,6 +382,9 @@ func (m UserManagementModel) View() string {
 // renderUsersView renders the users table
 func (m UserManagementModel) renderUsersView() string {
 	if len(m.users) == 0 {
+		if m.tags.Active != "" {
+			return m.theme.WarningStyle.Render("No users tagged #" + m.tags.Active + ". Press 'f' to change the filter.")
+		}
 		return m.theme.WarningStyle.Render("No users found")
 	}
 
@@ -427,6 +486,9 @@ func (m UserManagementModel) renderUsersView() string {
 			groups,
 			home,
 		)
+		if badges := m.tags.Badges(m.theme, user.Username); badges != "" {
+			row = lipgloss.JoinHorizontal(lipgloss.Left, row, " ", badges)
+		}
 
 		if i == m.cursor {
 			row = m.theme.SelectedItem.Render(row)
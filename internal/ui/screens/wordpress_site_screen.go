@@ -0,0 +1,332 @@
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+	"github.com/iperamuna/ravact/internal/vault"
+)
+
+// WordPressSiteModel provisions a WordPress site: the nginx vhost from the
+// wordpress stub, a MySQL database and user, WordPress core downloaded with
+// wp-cli, wp-config.php with fresh salts, and file permissions
+type WordPressSiteModel struct {
+	theme        *theme.Theme
+	width        int
+	height       int
+	nginxManager *system.NginxManager
+	mysql        *system.MySQLManager
+	form         *huh.Form
+
+	// Staged vhost awaiting review
+	review    ConfigReview
+	reviewing bool
+	siteName  string
+	domains   []string
+	ssl       bool
+	wp        system.WordPressSite
+
+	err error
+}
+
+// NewWordPressSiteModel creates the WordPress site wizard
+func NewWordPressSiteModel() WordPressSiteModel {
+	m := WordPressSiteModel{
+		theme:        theme.DefaultTheme(),
+		nginxManager: system.NewNginxManager(),
+		mysql:        system.NewMySQLManager(),
+	}
+	m.form = m.buildForm()
+	return m
+}
+
+// buildForm asks for the site, its administrator, and its database
+func (m *WordPressSiteModel) buildForm() *huh.Form {
+	naming, _ := system.LoadNamingPolicy()
+	siteName, domain, rootDir, owner := "", "", "/var/www/", system.WebGroup
+	title, adminUser, adminEmail, locale := "", "admin", "", "en_US"
+	dbName, dbUser, ssl := "", "", "none"
+
+	required := func(field string) func(string) error {
+		return func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return fmt.Errorf("%s is required", field)
+			}
+			return nil
+		}
+	}
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Key("siteName").Title("Site Name").
+				Description("Names the nginx config").
+				Validate(func(s string) error {
+					if s == "" || strings.Contains(s, " ") {
+						return fmt.Errorf("site name is required and cannot contain spaces")
+					}
+					return naming.Check(system.NamingSite, s)
+				}).
+				Value(&siteName),
+			huh.NewInput().Key("domain").Title("Domains").
+				Description("Comma-separated; www is added to apex domains").
+				Placeholder("blog.example.com").
+				Validate(func(s string) error {
+					_, err := system.ParseDomains(s)
+					return err
+				}).
+				Value(&domain),
+			huh.NewInput().Key("rootDir").Title("Install Directory").
+				Description("WordPress is installed here and served as the document root").
+				Validate(func(s string) error {
+					if !filepath.IsAbs(s) || filepath.Clean(s) == "/var/www" {
+						return fmt.Errorf("use a directory of its own, such as /var/www/blog")
+					}
+					return nil
+				}).
+				Value(&rootDir),
+			huh.NewInput().Key("owner").Title("File Owner").
+				Description("Owns the files; PHP-FPM ("+system.WebGroup+") can write only uploads and the cache").
+				Value(&owner),
+			huh.NewSelect[string]().Key("ssl").Title("SSL Certificate").
+				Options(
+					huh.NewOption("None (HTTP only)", "none"),
+					huh.NewOption("Let's Encrypt (Free SSL)", "letsencrypt"),
+				).
+				Value(&ssl),
+		).Title("Site"),
+		huh.NewGroup(
+			huh.NewInput().Key("title").Title("Site Title").Validate(required("site title")).Value(&title),
+			huh.NewInput().Key("adminUser").Title("Admin Username").Validate(required("admin username")).Value(&adminUser),
+			huh.NewInput().Key("adminEmail").Title("Admin Email").
+				Description("Also used to register the Let's Encrypt certificate").
+				Validate(func(s string) error {
+					if !strings.Contains(s, "@") {
+						return fmt.Errorf("enter a valid email address")
+					}
+					return nil
+				}).
+				Value(&adminEmail),
+			huh.NewInput().Key("locale").Title("Language").
+				Description("WordPress locale, such as en_US or de_DE").
+				Value(&locale),
+		).Title("WordPress"),
+		huh.NewGroup(
+			huh.NewInput().Key("dbName").Title("Database Name").
+				Validate(func(s string) error { return system.CheckName(system.NamingDatabase, s) }).
+				Value(&dbName),
+			huh.NewInput().Key("dbUser").Title("Database User").
+				Description("Created with a random password and access to this database only").
+				Validate(required("database user")).
+				Value(&dbUser),
+		).Title("Database"),
+	).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// Init initializes the WordPress site wizard
+func (m WordPressSiteModel) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+// Update handles messages for the WordPress site wizard
+func (m WordPressSiteModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.err != nil {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: NginxConfigScreen}
+			}
+		}
+		if m.reviewing {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			var result ConfirmResult
+			m.review, result = m.review.Update(msg, m.height)
+			switch result {
+			case ConfirmAccepted:
+				m.reviewing = false
+				return m.apply()
+			case ConfirmCancelled:
+				m.reviewing = false
+				m.form = m.buildForm()
+				return m, m.form.Init()
+			}
+			return m, nil
+		}
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: NginxConfigScreen}
+			}
+		}
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State == huh.StateCompleted {
+		return m.plan()
+	}
+	return m, cmd
+}
+
+// plan generates the passwords and stages the vhost for review
+func (m WordPressSiteModel) plan() (WordPressSiteModel, tea.Cmd) {
+	get := func(key string) string { return strings.TrimSpace(m.form.GetString(key)) }
+
+	if !m.mysql.IsInstalled() {
+		m.err = fmt.Errorf("MySQL is not installed; install it from Setup first")
+		return m, nil
+	}
+	domains, err := system.ParseDomains(get("domain"))
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.siteName = get("siteName")
+	m.domains = system.ExpandDomains(domains)
+	m.ssl = get("ssl") == "letsencrypt"
+
+	scheme := "http://"
+	if m.ssl {
+		scheme = "https://"
+	}
+	m.wp = system.WordPressSite{
+		Dir:        filepath.Clean(get("rootDir")),
+		Owner:      get("owner"),
+		URL:        scheme + system.PrimaryDomain(m.domains),
+		Title:      get("title"),
+		Locale:     get("locale"),
+		AdminUser:  get("adminUser"),
+		AdminEmail: get("adminEmail"),
+		DBName:     get("dbName"),
+		DBUser:     get("dbUser"),
+	}
+	if m.wp.AdminPassword, err = system.GeneratePassword(20); err == nil {
+		m.wp.DBPassword, err = system.GeneratePassword(24)
+	}
+	if err == nil {
+		err = m.wp.Validate()
+	}
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	change, err := m.nginxManager.PlanCreateSite(m.siteName, m.domains, m.wp.Dir, "wordpress", "", m.ssl, m.ssl)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.review = NewConfigReview("create_wordpress_site", m.nginxManager, change)
+	m.reviewing = true
+	return m, nil
+}
+
+// apply creates the database and vhost, then hands the WordPress install
+// to the execution screen
+func (m WordPressSiteModel) apply() (WordPressSiteModel, tea.Cmd) {
+	script, err := m.wp.ProvisionScript()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	// Keep the generated passwords before anything uses them
+	adminStored, err := vault.Record("WordPress "+system.PrimaryDomain(m.domains), m.wp.AdminUser, m.wp.AdminPassword, m.wp.URL+"/wp-admin")
+	if err != nil {
+		m.err = fmt.Errorf("could not save the admin password to the secrets vault: %w", err)
+		return m, nil
+	}
+	dbStored, err := vault.Record("MySQL", m.wp.DBUser, m.wp.DBPassword, "WordPress database "+m.wp.DBName)
+	if err != nil {
+		m.err = fmt.Errorf("could not save the database password to the secrets vault: %w", err)
+		return m, nil
+	}
+	passwords := "  Passwords: saved to the secrets vault"
+	if !adminStored || !dbStored {
+		passwords = "  Passwords: unlock the secrets vault to keep them"
+	}
+	script += "echo " + system.ShellQuote(passwords) + "\n"
+	if err := m.mysql.CreateDatabase(m.wp.DBName, m.wp.DBUser, m.wp.DBPassword); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := m.nginxManager.ApplyChange(m.review.Change); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := m.nginxManager.EnableSite(m.siteName); err != nil {
+		m.err = fmt.Errorf("site created but failed to enable: %w", err)
+		return m, nil
+	}
+	if err := m.nginxManager.TestConfig(); err != nil {
+		m.err = fmt.Errorf("site created but config test failed: %w", err)
+		return m, nil
+	}
+	if err := m.nginxManager.ReloadNginx(); err != nil {
+		m.err = fmt.Errorf("site created but reload failed: %w", err)
+		return m, nil
+	}
+	if m.ssl {
+		if err := m.nginxManager.ObtainSSLCertificate(m.domains); err != nil {
+			m.err = fmt.Errorf("site created but certbot failed: %w", err)
+			return m, nil
+		}
+	}
+
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{
+			Command:     "sudo bash -c " + system.ShellQuote(script),
+			Description: "Install WordPress for " + system.PrimaryDomain(m.domains),
+		}
+	}
+}
+
+// View renders the WordPress site wizard
+func (m WordPressSiteModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.reviewing {
+		return m.review.View(m.theme, m.width, m.height)
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections := []string{m.theme.Title.Render("New WordPress Site"), ""}
+	help := "Tab/Shift+Tab: Navigate" + bullet + "Enter: Next/Submit" + bullet + "Esc: Cancel"
+
+	if m.err != nil {
+		sections = append(sections, m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" Error: "+m.err.Error()))
+		help = "Press any key to continue..."
+	} else {
+		sections = append(sections,
+			m.form.View(),
+			"",
+			m.theme.DescriptionStyle.Render("The admin and database passwords are generated and shown once when the install finishes."))
+	}
+	sections = append(sections, "", m.theme.Help.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -0,0 +1,240 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iperamuna/ravact/internal/system"
+	"github.com/iperamuna/ravact/internal/ui/theme"
+)
+
+// XdebugModel installs and toggles Xdebug per PHP version, showing which
+// versions run with it so it is not left on in production
+type XdebugModel struct {
+	theme    *theme.Theme
+	width    int
+	height   int
+	statuses []system.XdebugStatus
+	cursor   int
+	form     *huh.Form
+	err      error
+}
+
+// NewXdebugModel creates the Xdebug screen
+func NewXdebugModel() XdebugModel {
+	m := XdebugModel{theme: theme.DefaultTheme()}
+	for _, version := range system.PHPFPMVersions() {
+		m.statuses = append(m.statuses, system.ReadXdebugStatus(version))
+	}
+	if len(m.statuses) == 0 {
+		m.err = fmt.Errorf("no PHP-FPM installation found in %s", system.PHPConfigDir)
+	}
+	return m
+}
+
+// Init initializes the Xdebug screen
+func (m XdebugModel) Init() tea.Cmd {
+	return nil
+}
+
+// toggle installs Xdebug in debug mode when it is missing, turns it off
+// when it is on, and otherwise turns it back on with the saved settings
+func (m XdebugModel) toggle() (XdebugModel, tea.Cmd) {
+	st := m.statuses[m.cursor]
+	settings := st.Settings
+	switch {
+	case st.Active():
+		settings.Mode = system.XdebugOff
+	case settings.Mode == system.XdebugOff || settings.Validate() != nil:
+		settings.Mode = system.XdebugDebug
+	}
+	return m.apply(st.Version, settings)
+}
+
+// apply runs the script that writes the settings and restarts PHP-FPM
+func (m XdebugModel) apply(version string, settings system.XdebugSettings) (XdebugModel, tea.Cmd) {
+	script, err := system.XdebugScript(version, settings)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	description := fmt.Sprintf("Turn Xdebug %s for PHP %s", settings.Mode, version)
+	if settings.Mode != system.XdebugOff {
+		description = fmt.Sprintf("Turn on Xdebug (%s) for PHP %s", settings.Mode, version)
+	}
+	return m, func() tea.Msg {
+		return ExecutionStartMsg{Command: script, Description: description}
+	}
+}
+
+// buildForm edits the settings of the selected version
+func (m XdebugModel) buildForm() *huh.Form {
+	s := m.statuses[m.cursor].Settings
+	mode := s.Mode
+	if mode != system.XdebugDebug && mode != system.XdebugProfile {
+		mode = system.XdebugOff
+	}
+	host, port, start := s.ClientHost, strconv.Itoa(s.ClientPort), s.StartWithRequest
+	if start != "yes" {
+		start = "trigger"
+	}
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().Key("mode").Title("Mode").
+				Options(
+					huh.NewOption("Off (unload the module)", system.XdebugOff),
+					huh.NewOption("Debug (step debugging)", system.XdebugDebug),
+					huh.NewOption("Profile (cachegrind files in "+system.XdebugProfileDir+")", system.XdebugProfile),
+				).
+				Value(&mode),
+			huh.NewInput().Key("client_host").Title("Client Host").
+				Description("Where the IDE listens; 127.0.0.1 with an SSH tunnel (ssh -R 9003:localhost:9003)").
+				Validate(func(s string) error {
+					return system.XdebugSettings{Mode: system.XdebugOff, ClientHost: strings.TrimSpace(s), ClientPort: 9003, StartWithRequest: "trigger"}.Validate()
+				}).
+				Value(&host),
+			huh.NewInput().Key("client_port").Title("Client Port").
+				Validate(func(s string) error {
+					if n, err := strconv.Atoi(strings.TrimSpace(s)); err != nil || n < 1 || n > 65535 {
+						return fmt.Errorf("port must be between 1 and 65535")
+					}
+					return nil
+				}).
+				Value(&port),
+			huh.NewSelect[string]().Key("start_with_request").Title("Start With Request").
+				Options(
+					huh.NewOption("On trigger (XDEBUG_TRIGGER cookie or parameter)", "trigger"),
+					huh.NewOption("Every request", "yes"),
+				).
+				Value(&start),
+		).Title("Xdebug for PHP " + m.statuses[m.cursor].Version),
+	).
+		WithTheme(m.theme.HuhTheme).
+		WithShowHelp(true).
+		WithShowErrors(true)
+}
+
+// Update handles messages for the Xdebug screen
+func (m XdebugModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.form != nil {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				m.form = nil
+				return m, nil
+			}
+			break
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			return m, func() tea.Msg {
+				return NavigateMsg{Screen: PHPFPMManagementScreen}
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.statuses)-1 {
+				m.cursor++
+			}
+		case "x", "enter", " ":
+			if len(m.statuses) > 0 {
+				m.err = nil
+				return m.toggle()
+			}
+		case "e":
+			if len(m.statuses) > 0 {
+				m.err = nil
+				m.form = m.buildForm()
+				return m, m.form.Init()
+			}
+		}
+		return m, nil
+	}
+
+	if m.form == nil {
+		return m, nil
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	if m.form.State == huh.StateCompleted {
+		port, _ := strconv.Atoi(strings.TrimSpace(m.form.GetString("client_port")))
+		settings := system.XdebugSettings{
+			Mode:             m.form.GetString("mode"),
+			ClientHost:       strings.TrimSpace(m.form.GetString("client_host")),
+			ClientPort:       port,
+			StartWithRequest: m.form.GetString("start_with_request"),
+		}
+		m.form = nil
+		return m.apply(m.statuses[m.cursor].Version, settings)
+	}
+	return m, cmd
+}
+
+// View renders the Xdebug screen
+func (m XdebugModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	bullet := " " + m.theme.Symbols.Bullet + " "
+	sections := []string{m.theme.Title.Render("Xdebug"), ""}
+
+	if m.form != nil {
+		sections = append(sections, m.form.View(), "",
+			m.theme.Help.Render("Tab/Shift+Tab: Navigate"+bullet+"Enter: Next/Apply"+bullet+"Esc: Cancel"))
+	} else {
+		sections = append(sections, m.theme.Label.Render(fmt.Sprintf("  %-10s %-16s %s", "PHP", "Xdebug", "Client")))
+		anyActive := false
+		for i, st := range m.statuses {
+			cursor := "  "
+			style := m.theme.MenuItem
+			if i == m.cursor {
+				cursor = m.theme.KeyStyle.Render(m.theme.Symbols.Cursor + " ")
+				style = m.theme.SelectedItem
+			}
+			state := m.theme.DescriptionStyle.Render(st.Label())
+			client := ""
+			if st.Active() {
+				anyActive = true
+				state = m.theme.WarningStyle.Render(m.theme.Symbols.Warning + " " + st.Label())
+				client = fmt.Sprintf("%s:%d (%s)", st.Settings.ClientHost, st.Settings.ClientPort, st.Settings.StartWithRequest)
+			} else if st.Installed {
+				state = m.theme.SuccessStyle.Render(m.theme.Symbols.CheckMark + " " + st.Label())
+			}
+			sections = append(sections, cursor+style.Render(fmt.Sprintf("%-10s ", st.Version))+lipgloss.NewStyle().Width(17).Render(state)+m.theme.DescriptionStyle.Render(client))
+		}
+		if anyActive {
+			sections = append(sections, "",
+				m.theme.WarningStyle.Render("Xdebug slows every request and can expose a debugger: turn it off before production traffic."))
+		}
+		if m.err != nil {
+			sections = append(sections, "", m.theme.ErrorStyle.Render(m.theme.Symbols.CrossMark+" "+m.err.Error()))
+		}
+		sections = append(sections, "",
+			m.theme.DescriptionStyle.Render("Settings go to "+system.XdebugSettingsFile+" in the FPM and CLI conf.d."),
+			"", m.theme.Help.Render("x/Enter: Install/Toggle"+bullet+"e: Configure"+bullet+"Esc: Back"+bullet+"q: Quit"))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	bordered := m.theme.RenderBox(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, bordered)
+}
@@ -3,8 +3,14 @@ package theme
 import (
 	"os"
 	"strings"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
+// ForceASCII renders with plain ASCII symbols and borders whatever the
+// terminal reports; set by the --ascii flag
+var ForceASCII bool
+
 // TerminalCapabilities holds information about terminal capabilities
 type TerminalCapabilities struct {
 	TrueColor    bool
@@ -93,10 +99,35 @@ func DetectTerminalCapabilities() TerminalCapabilities {
 		caps.Unicode = false
 		caps.Color256 = false
 	}
+
+	// Serial consoles and minimal SSH clients often run a non-UTF-8 locale
+	if ForceASCII || !localeIsUTF8() {
+		caps.Unicode = false
+	}
 	
 	return caps
 }
 
+// ASCII reports whether only plain ASCII can be drawn
+func (c TerminalCapabilities) ASCII() bool {
+	return !c.Unicode || c.IsBasicTerm
+}
+
+// localeIsUTF8 checks the locale the way setlocale does: LC_ALL wins over
+// LC_CTYPE, which wins over LANG. An unset locale is not treated as ASCII
+// because many SSH sessions do not forward one to UTF-8 terminals.
+func localeIsUTF8() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		value = strings.ToLower(value)
+		return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+	}
+	return true
+}
+
 // Symbols provides terminal-safe symbols based on capabilities
 type Symbols struct {
 	Cursor       string
@@ -124,7 +155,7 @@ type Symbols struct {
 
 // GetSymbols returns appropriate symbols based on terminal capabilities
 func GetSymbols(caps TerminalCapabilities) Symbols {
-	if caps.Unicode && !caps.IsBasicTerm {
+	if !caps.ASCII() {
 		return Symbols{
 			Cursor:      "▶",
 			CursorEmpty: " ",
@@ -175,3 +206,44 @@ func GetSymbols(caps TerminalCapabilities) Symbols {
 		Copy:        "[C]",
 	}
 }
+
+// asciiGlyphs replace the glyphs screens draw directly. Each replacement is
+// as wide as the glyph so layouts measured before the swap stay aligned.
+var asciiGlyphs = map[rune]string{
+	'─': "-", '━': "-", '═': "=", '│': "|", '┃': "|", '║': "|",
+	'╭': "+", '╮': "+", '╰': "+", '╯': "+", '┌': "+", '┐': "+", '└': "+", '┘': "+",
+	'├': "+", '┤': "+", '┬': "+", '┴': "+", '┼': "+",
+	'╔': "+", '╗': "+", '╚': "+", '╝': "+", '╠': "+", '╣': "+", '╦': "+", '╩': "+", '╬': "+",
+	'•': "*", '●': "*", '○': "o", '✓': "+", '✔': "+", '✗': "x", '✘': "x", '×': "x",
+	'⚠': "!", 'ℹ': "i", '↑': "^", '↓': "v", '←': "<", '→': ">", '▶': ">", '▸': ">",
+	'…': ".", '█': "#", '▓': "#", '▒': ":", '░': ".", '☐': "o", '☑': "x",
+}
+
+// ToASCII replaces every non-ASCII character in rendered output with ASCII
+// of the same width: known glyphs get a lookalike, decorative emoji become
+// spaces, and anything else becomes question marks. Zero-width characters
+// such as emoji variation selectors are dropped.
+func ToASCII(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if r < 0x80 {
+			b.WriteRune(r)
+			continue
+		}
+		if glyph, ok := asciiGlyphs[r]; ok {
+			b.WriteString(glyph)
+			continue
+		}
+		width := lipgloss.Width(string(r))
+		switch {
+		case r >= 0x2800 && r <= 0x28ff: // Braille spinner frames
+			b.WriteString("-")
+		case r >= 0x1f000 || (r >= 0x2300 && r <= 0x23ff) || (r >= 0x2600 && r <= 0x27bf):
+			b.WriteString(strings.Repeat(" ", width))
+		default:
+			b.WriteString(strings.Repeat("?", width))
+		}
+	}
+	return b.String()
+}
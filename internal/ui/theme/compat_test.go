@@ -2,7 +2,10 @@ package theme
 
 import (
 	"os"
+	"strings"
 	"testing"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestDetectTerminalCapabilities_TrueColor(t *testing.T) {
@@ -328,3 +331,72 @@ func TestSymbolsAllFieldsPopulated(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectTerminalCapabilities_Locale(t *testing.T) {
+	for _, name := range []string{"TERM", "LC_ALL", "LC_CTYPE", "LANG"} {
+		orig := os.Getenv(name)
+		defer os.Setenv(name, orig)
+		os.Setenv(name, "")
+	}
+	os.Setenv("TERM", "xterm-256color")
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		unicode bool
+	}{
+		{"unset locale", map[string]string{}, true},
+		{"UTF-8 LANG", map[string]string{"LANG": "en_US.UTF-8"}, true},
+		{"C.utf8 LANG", map[string]string{"LANG": "C.utf8"}, true},
+		{"POSIX LANG", map[string]string{"LANG": "POSIX"}, false},
+		{"Latin-1 LC_CTYPE over UTF-8 LANG", map[string]string{"LC_CTYPE": "de_DE.ISO-8859-1", "LANG": "de_DE.UTF-8"}, false},
+		{"C LC_ALL over UTF-8 LC_CTYPE", map[string]string{"LC_ALL": "C", "LC_CTYPE": "en_US.UTF-8"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+				os.Setenv(name, tt.env[name])
+			}
+			if caps := DetectTerminalCapabilities(); caps.Unicode != tt.unicode {
+				t.Errorf("expected Unicode=%v, got %v", tt.unicode, caps.Unicode)
+			}
+		})
+	}
+}
+
+func TestForceASCII(t *testing.T) {
+	origTerm := os.Getenv("TERM")
+	defer func() {
+		os.Setenv("TERM", origTerm)
+		ForceASCII = false
+	}()
+	os.Setenv("TERM", "xterm-256color")
+
+	ForceASCII = true
+	caps := DetectTerminalCapabilities()
+	if !caps.ASCII() {
+		t.Fatal("expected --ascii to force ASCII rendering")
+	}
+	if symbols := GetSymbols(caps); symbols.Cursor != ">" {
+		t.Errorf("expected ASCII symbols, got cursor %q", symbols.Cursor)
+	}
+}
+
+func TestToASCII(t *testing.T) {
+	input := "╭──╮\n│ ✓ Done • ⚠ 🐘 café ⠋ │\n╰──╯"
+	got := ToASCII(input)
+	for _, r := range got {
+		if r > 0x7f {
+			t.Fatalf("expected only ASCII, got %q", got)
+		}
+	}
+	if !strings.Contains(got, "+--+") || !strings.Contains(got, "+ Done * !") || !strings.Contains(got, "caf?") {
+		t.Errorf("unexpected transliteration %q", got)
+	}
+	inLines, outLines := strings.Split(input, "\n"), strings.Split(got, "\n")
+	for i := range inLines {
+		if lipgloss.Width(inLines[i]) != lipgloss.Width(outLines[i]) {
+			t.Errorf("line %d changed width: %q -> %q", i, inLines[i], outLines[i])
+		}
+	}
+}
@@ -172,7 +172,7 @@ func DefaultTheme() *Theme {
 	// Use appropriate border style based on terminal capabilities
 	// Use fixed width for consistency across the app
 	borderWidth := t.AppWidth + 6 // Account for borders (2) and padding (4)
-	if caps.Unicode && !caps.IsBasicTerm {
+	if !caps.ASCII() {
 		t.BorderStyle = lipgloss.NewStyle().
 			BorderStyle(lipgloss.RoundedBorder()).
 			BorderForeground(t.BorderColor).
@@ -181,7 +181,7 @@ func DefaultTheme() *Theme {
 	} else {
 		// ASCII-safe border for basic terminals
 		t.BorderStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.NormalBorder()).
+			BorderStyle(lipgloss.ASCIIBorder()).
 			BorderForeground(t.BorderColor).
 			Padding(1, 2).
 			Width(borderWidth)
@@ -247,6 +247,12 @@ func (t *Theme) RenderBox(content string) string {
 func createHuhTheme(t *Theme) *huh.Theme {
 	theme := huh.ThemeBase()
 
+	// Glyphs that basic terminals cannot draw
+	check, focusBorder := "✓", lipgloss.ThickBorder()
+	if t.Caps.ASCII() {
+		check, focusBorder = "x", lipgloss.ASCIIBorder()
+	}
+
 	// Form styles
 	theme.Form.Base = lipgloss.NewStyle().Padding(1, 0)
 
@@ -271,7 +277,7 @@ func createHuhTheme(t *Theme) *huh.Theme {
 	theme.Blurred.Option = lipgloss.NewStyle().Foreground(t.Text)
 	theme.Blurred.MultiSelectSelector = lipgloss.NewStyle().Foreground(t.Subtle).SetString("> ")
 	theme.Blurred.SelectedOption = lipgloss.NewStyle().Foreground(t.Success)
-	theme.Blurred.SelectedPrefix = lipgloss.NewStyle().Foreground(t.Success).SetString("[✓] ")
+	theme.Blurred.SelectedPrefix = lipgloss.NewStyle().Foreground(t.Success).SetString("[" + check + "] ")
 	theme.Blurred.UnselectedOption = lipgloss.NewStyle().Foreground(t.Text)
 	theme.Blurred.UnselectedPrefix = lipgloss.NewStyle().Foreground(t.Subtle).SetString("[ ] ")
 	theme.Blurred.FocusedButton = lipgloss.NewStyle().
@@ -293,7 +299,7 @@ func createHuhTheme(t *Theme) *huh.Theme {
 	// Focused field styles
 	theme.Focused.Base = lipgloss.NewStyle().
 		PaddingLeft(1).
-		BorderStyle(lipgloss.ThickBorder()).
+		BorderStyle(focusBorder).
 		BorderLeft(true).
 		BorderForeground(t.Primary)
 	theme.Focused.Title = lipgloss.NewStyle().Foreground(t.Primary).Bold(true)
@@ -301,12 +307,12 @@ func createHuhTheme(t *Theme) *huh.Theme {
 	theme.Focused.ErrorIndicator = lipgloss.NewStyle().Foreground(t.Error).SetString(" *")
 	theme.Focused.ErrorMessage = lipgloss.NewStyle().Foreground(t.Error)
 	theme.Focused.SelectSelector = lipgloss.NewStyle().Foreground(t.Primary).SetString("> ")
-	theme.Focused.NextIndicator = lipgloss.NewStyle().Foreground(t.Primary).SetString("↓ ")
-	theme.Focused.PrevIndicator = lipgloss.NewStyle().Foreground(t.Primary).SetString("↑ ")
+	theme.Focused.NextIndicator = lipgloss.NewStyle().Foreground(t.Primary).SetString(t.Symbols.ArrowDown + " ")
+	theme.Focused.PrevIndicator = lipgloss.NewStyle().Foreground(t.Primary).SetString(t.Symbols.ArrowUp + " ")
 	theme.Focused.Option = lipgloss.NewStyle().Foreground(t.Text)
 	theme.Focused.MultiSelectSelector = lipgloss.NewStyle().Foreground(t.Primary).SetString("> ")
 	theme.Focused.SelectedOption = lipgloss.NewStyle().Foreground(t.Success).Bold(true)
-	theme.Focused.SelectedPrefix = lipgloss.NewStyle().Foreground(t.Success).SetString("[✓] ")
+	theme.Focused.SelectedPrefix = lipgloss.NewStyle().Foreground(t.Success).SetString("[" + check + "] ")
 	theme.Focused.UnselectedOption = lipgloss.NewStyle().Foreground(t.Text)
 	theme.Focused.UnselectedPrefix = lipgloss.NewStyle().Foreground(t.Subtle).SetString("[ ] ")
 	theme.Focused.FocusedButton = lipgloss.NewStyle().
@@ -0,0 +1,308 @@
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	fileVersion = 1
+	kdfIter     = 600000
+	keyLength   = 32
+	saltLength  = 16
+)
+
+// ErrWrongPassphrase is returned when the vault cannot be decrypted
+var ErrWrongPassphrase = errors.New("incorrect vault passphrase")
+
+// DefaultPath is the vault file used by the session helpers
+var DefaultPath string
+
+// Secret is a credential recorded by ravact
+type Secret struct {
+	Service   string    `json:"service"`
+	Account   string    `json:"account"`
+	Value     string    `json:"value"`
+	Note      string    `json:"note,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Vault is a passphrase-encrypted store of secrets
+type Vault struct {
+	path    string
+	key     []byte
+	salt    []byte
+	iter    int // PBKDF2 iterations the key was derived with
+	Secrets []Secret
+}
+
+// vaultFile is the on-disk layout of the vault
+type vaultFile struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Exists reports whether a vault file has been created at path
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Open decrypts the vault at path, creating an empty one if it does not exist yet
+func Open(path, passphrase string) (*Vault, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase cannot be empty")
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		salt := make([]byte, saltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+		key, err := deriveKey(passphrase, salt, kdfIter)
+		if err != nil {
+			return nil, err
+		}
+		return &Vault{path: path, key: key, salt: salt, iter: kdfIter}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault: %w", err)
+	}
+
+	var file vaultFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse vault: %w", err)
+	}
+	if file.Version != fileVersion {
+		return nil, fmt.Errorf("unsupported vault version %d", file.Version)
+	}
+
+	key, err := deriveKey(passphrase, file.Salt, file.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	v := &Vault{path: path, key: key, salt: file.Salt, iter: file.Iterations}
+	if err := json.Unmarshal(plaintext, &v.Secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse vault contents: %w", err)
+	}
+	return v, nil
+}
+
+// Put stores a secret, replacing any existing one for the same service and account
+func (v *Vault) Put(s Secret) {
+	if s.UpdatedAt.IsZero() {
+		s.UpdatedAt = time.Now()
+	}
+	for i, existing := range v.Secrets {
+		if existing.Service == s.Service && existing.Account == s.Account {
+			v.Secrets[i] = s
+			return
+		}
+	}
+	v.Secrets = append(v.Secrets, s)
+	sort.SliceStable(v.Secrets, func(i, j int) bool {
+		if v.Secrets[i].Service != v.Secrets[j].Service {
+			return v.Secrets[i].Service < v.Secrets[j].Service
+		}
+		return v.Secrets[i].Account < v.Secrets[j].Account
+	})
+}
+
+// Delete removes the secret at index i
+func (v *Vault) Delete(i int) {
+	if i >= 0 && i < len(v.Secrets) {
+		v.Secrets = append(v.Secrets[:i], v.Secrets[i+1:]...)
+	}
+}
+
+// Save encrypts the vault and writes it to disk
+func (v *Vault) Save() error {
+	plaintext, err := json.Marshal(v.Secrets)
+	if err != nil {
+		return fmt.Errorf("failed to encode secrets: %w", err)
+	}
+
+	gcm, err := newGCM(v.key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	data, err := json.MarshalIndent(vaultFile{
+		Version:    fileVersion,
+		Iterations: v.iter,
+		Salt:       v.salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode vault: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(v.path), 0700); err != nil {
+		return fmt.Errorf("failed to create vault directory: %w", err)
+	}
+	return writeFileAtomic(v.path, data)
+}
+
+// writeFileAtomic replaces path with data through a synced temporary file
+// in the same directory, so a crash or a full disk never leaves a
+// truncated vault behind
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write vault: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write vault: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write vault: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write vault: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write vault: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace vault: %w", err)
+	}
+	return nil
+}
+
+// deriveKey derives the encryption key from a passphrase
+func deriveKey(passphrase string, salt []byte, iter int) ([]byte, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iter, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	return key, nil
+}
+
+// newGCM creates the AES-GCM cipher for a key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Session state: the vault unlocked for this run, plus secrets recorded
+// while it was locked that are written once it is unlocked.
+var (
+	sessionMu sync.Mutex
+	session   *Vault
+	pending   []Secret
+)
+
+// Unlock opens the default vault for the rest of the session and stores
+// any secrets recorded while it was locked
+func Unlock(passphrase string) error {
+	v, err := Open(DefaultPath, passphrase)
+	if err != nil {
+		return err
+	}
+
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+
+	for _, s := range pending {
+		v.Put(s)
+	}
+	if len(pending) > 0 || !Exists(DefaultPath) {
+		if err := v.Save(); err != nil {
+			return err
+		}
+	}
+	pending = nil
+	session = v
+	return nil
+}
+
+// Lock forgets the unlocked vault
+func Lock() {
+	sessionMu.Lock()
+	session = nil
+	sessionMu.Unlock()
+}
+
+// Session returns the unlocked vault, or nil when locked
+func Session() *Vault {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	return session
+}
+
+// Pending returns how many secrets are waiting for the vault to be unlocked
+func Pending() int {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	return len(pending)
+}
+
+// Record stores a credential ravact has just set. When the vault is
+// locked the secret is held in memory until Unlock is called.
+// It returns true if the secret was written to disk.
+func Record(service, account, value, note string) (bool, error) {
+	s := Secret{Service: service, Account: account, Value: value, Note: note, UpdatedAt: time.Now()}
+
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+
+	if session == nil {
+		pending = append(pending, s)
+		return false, nil
+	}
+	session.Put(s)
+	if err := session.Save(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RecordMessage records a credential and returns a short status line for the UI
+func RecordMessage(service, account, value, note string) string {
+	stored, err := Record(service, account, value, note)
+	switch {
+	case err != nil:
+		return fmt.Sprintf("Could not save to secrets vault: %v", err)
+	case stored:
+		return "Saved to secrets vault"
+	default:
+		return "Unlock the secrets vault to keep this password"
+	}
+}
@@ -0,0 +1,109 @@
+package vault
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVaultSaveAndOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault.json")
+
+	v, err := Open(path, "correct horse")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	v.Put(Secret{Service: "MySQL", Account: "root", Value: "first"})
+	v.Put(Secret{Service: "MySQL", Account: "root", Value: "second"})
+	if err := v.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "second") {
+		t.Error("expected secret value not to be stored in plain text")
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected the vault to be readable by its owner only, got %v (%v)", info, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("expected only the vault in its directory, got %d entries", len(entries))
+	}
+
+	reopened, err := Open(path, "correct horse")
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	if len(reopened.Secrets) != 1 || reopened.Secrets[0].Value != "second" {
+		t.Errorf("expected one updated secret, got %+v", reopened.Secrets)
+	}
+
+	if _, err := Open(path, "wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("expected ErrWrongPassphrase, got %v", err)
+	}
+}
+
+func TestVaultKeepsIterations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault.json")
+
+	// A vault written with another iteration count than new vaults get
+	salt := []byte("0123456789abcdef")
+	key, err := deriveKey("correct horse", salt, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&Vault{path: path, key: key, salt: salt, iter: 1000}).Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	v, err := Open(path, "correct horse")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	v.Put(Secret{Service: "Redis", Account: "requirepass", Value: "s3cret"})
+	if err := v.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	reopened, err := Open(path, "correct horse")
+	if err != nil {
+		t.Fatalf("expected the re-saved vault to unlock: %v", err)
+	}
+	if len(reopened.Secrets) != 1 || reopened.Secrets[0].Value != "s3cret" {
+		t.Errorf("unexpected secrets %+v", reopened.Secrets)
+	}
+}
+
+func TestRecordWhileLocked(t *testing.T) {
+	oldPath := DefaultPath
+	DefaultPath = filepath.Join(t.TempDir(), "vault.json")
+	defer func() {
+		DefaultPath = oldPath
+		Lock()
+	}()
+
+	Lock()
+	stored, err := Record("Redis", "requirepass", "s3cret", "")
+	if err != nil || stored {
+		t.Fatalf("expected secret to be held while locked, got stored=%v err=%v", stored, err)
+	}
+	if Pending() != 1 {
+		t.Fatalf("expected 1 pending secret, got %d", Pending())
+	}
+
+	if err := Unlock("passphrase"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if Pending() != 0 {
+		t.Errorf("expected pending secrets to be flushed, got %d", Pending())
+	}
+
+	v, err := Open(DefaultPath, "passphrase")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if len(v.Secrets) != 1 || v.Secrets[0].Service != "Redis" {
+		t.Errorf("expected Redis secret in vault, got %+v", v.Secrets)
+	}
+}